@@ -0,0 +1,104 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+const announcementTemplate = `{{ with .Announcement -}}
+{{ .Message }}
+(since {{ .Since | toRFC3339 }})
+{{ else -}}
+there is no announcement
+{{ end -}}
+`
+
+// announcementCmd represents the announcement command
+var announcementCmd = &cobra.Command{
+	Use:   "announcement",
+	Short: "Shows and sets the announcement werft shows its users, e.g. upcoming cluster maintenance",
+	Long: `Shows and sets the announcement werft shows its users, e.g. upcoming cluster maintenance.
+Jobs started while there is an announcement show it in their logs. Setting the announcement requires
+one of the admin tokens configured for werft.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetAnnouncement(context.Background(), &v1.GetAnnouncementRequest{})
+		if err != nil {
+			return err
+		}
+		return prettyPrint(resp, announcementTemplate)
+	},
+}
+
+// announcementSetCmd represents the announcement set command
+var announcementSetCmd = &cobra.Command{
+	Use:   "set <message>",
+	Short: "Sets the announcement, replacing the current one",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAnnouncement(cmd, args[0])
+	},
+}
+
+// announcementClearCmd represents the announcement clear command
+var announcementClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clears the announcement",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAnnouncement(cmd, "")
+	},
+}
+
+func setAnnouncement(cmd *cobra.Command, msg string) error {
+	token, _ := cmd.Flags().GetString("token")
+
+	conn := dial()
+	defer conn.Close()
+	client := v1.NewWerftServiceClient(conn)
+
+	resp, err := client.SetAnnouncement(context.Background(), &v1.SetAnnouncementRequest{Message: msg, Token: token})
+	if err != nil {
+		return err
+	}
+	return prettyPrint(resp, announcementTemplate)
+}
+
+func init() {
+	rootCmd.AddCommand(announcementCmd)
+	announcementCmd.AddCommand(announcementSetCmd)
+	announcementCmd.AddCommand(announcementClearCmd)
+
+	for _, c := range []*cobra.Command{announcementSetCmd, announcementClearCmd} {
+		c.Flags().String("token", os.Getenv("WERFT_ADMIN_TOKEN"), "admin token (defaults to WERFT_ADMIN_TOKEN env var)")
+	}
+	announcementCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	announcementCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
@@ -0,0 +1,96 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// deadLetterCmd represents the dead-letter command
+var deadLetterCmd = &cobra.Command{
+	Use:   "dead-letter",
+	Short: "Inspects and replays webhook events which failed processing",
+	Long: `Inspects and replays webhook events which failed processing. This requires one of the admin tokens
+configured for werft.`,
+	Args: cobra.ExactArgs(1),
+}
+
+// deadLetterListCmd represents the dead-letter list command
+var deadLetterListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists webhook events which failed processing",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.ListDeadLetters(context.Background(), &v1.ListDeadLettersRequest{Token: token})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `ID	EVENT	RECEIVED	ATTEMPTS	ERROR
+{{- range .Result }}
+{{ .Id }}	{{ .EventType }}	{{ .Received | toRFC3339 }}	{{ .Attempts }}	{{ .Error -}}
+{{ end }}
+`)
+	},
+}
+
+// deadLetterReplayCmd represents the dead-letter replay command
+var deadLetterReplayCmd = &cobra.Command{
+	Use:   "replay <id>",
+	Short: "Processes a failed webhook event again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		_, err := client.ReplayDeadLetter(context.Background(), &v1.ReplayDeadLetterRequest{Id: args[0], Token: token})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("replayed %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deadLetterCmd)
+	deadLetterCmd.AddCommand(deadLetterListCmd)
+	deadLetterCmd.AddCommand(deadLetterReplayCmd)
+
+	deadLetterCmd.PersistentFlags().String("token", os.Getenv("WERFT_ADMIN_TOKEN"), "admin token (defaults to WERFT_ADMIN_TOKEN env var)")
+	deadLetterCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	deadLetterCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
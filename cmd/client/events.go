@@ -0,0 +1,80 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"io"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// eventsCmd represents the events command
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Streams server events",
+	Long: `Streams all server events, i.e. job updates, job events, queued jobs, central config changes,
+maintenance mode changes, announcements and webhook events which went to the dead letter queue.
+
+The filter is an expression in a subset of CEL, e.g.
+  type == "job" && job.repo.owner == "32leaves" && job.phase in ["running", "done"]
+  type == "job_event" && event.type == "queued"
+  job.name.startsWith("werft-") && job.label["tier"] == "ui"
+Expressions support ==, !=, in [...], startsWith, endsWith, contains, !, && and || and refer to
+type, message, job.name, job.phase, job.owner, job.trigger, job.success, job.repo.{owner,repo,host,ref,rev},
+job.annotation.<name>, job.label.<name>, event.type, event.phase and attr.<name>.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, _ := cmd.Flags().GetString("filter")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		evts, err := client.StreamEvents(context.Background(), &v1.StreamEventsRequest{Filter: filter})
+		if err != nil {
+			return err
+		}
+		for {
+			evt, err := evts.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			err = prettyPrint(evt, `{{ .Time | toRFC3339 }}	{{ .Type }}	{{ or .JobName "-" }}	{{ if .Job }}{{ .Job.Phase }} {{ end }}{{ if .JobEvent }}{{ .JobEvent.Type }} {{ end }}{{ .Message }}
+`)
+			if err != nil {
+				return err
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(eventsCmd)
+	eventsCmd.Flags().String("filter", "", "only stream events which match this expression")
+	eventsCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	eventsCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
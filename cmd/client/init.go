@@ -21,16 +21,169 @@ package cmd
 // THE SOFTWARE.
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 )
 
-// initCmd represents the job command
+// initCmd represents the init command
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initializes configuration for werft",
-	Args:  cobra.ExactArgs(1),
+	Long: `Initializes the werft configuration of the repository in the current working directory.
+Detects the language of the repository and whether it has a Dockerfile, and writes a starter .werft/config.yaml
+with a job which builds and tests the repository, plus a job which builds the container image if there is a Dockerfile.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		st := detectStack(wd)
+		cfg := "defaultJob: \".werft/build.yaml\"\n"
+		files := []initFile{
+			{".werft/build.yaml", buildJobYAML(st)},
+		}
+		withImage := fileExists(wd, "Dockerfile")
+		if withImage {
+			cfg += "jobs:\n- path: \".werft/image.yaml\"\n  triggers: [\"push\"]\n"
+			files = append(files, initFile{".werft/image.yaml", imageJobYAML})
+		}
+		files = append([]initFile{{".werft/config.yaml", cfg}}, files...)
+
+		if !force {
+			for _, f := range files {
+				if fileExists(wd, f.Path) {
+					return xerrors.Errorf("%s exists already - use --force to overwrite it", f.Path)
+				}
+			}
+		}
+		err = os.MkdirAll(filepath.Join(wd, ".werft"), 0755)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			err = ioutil.WriteFile(filepath.Join(wd, filepath.FromSlash(f.Path)), []byte(f.Content), 0644)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("wrote %s\n", f.Path)
+		}
+
+		fmt.Printf(`
+Detected a %s repository. Next steps:
+  1. Check the jobs in .werft/ - they're a starting point and likely need adjusting.
+  2. Try them using: werft run local -j .werft/build.yaml
+  3. Commit and push .werft/ - werft runs the build job on every push once the GitHub app is installed on this repository.
+`, st.Name)
+		if withImage {
+			fmt.Println("  4. Set the registry the image job pushes to in .werft/image.yaml.")
+		}
+		return nil
+	},
+}
+
+// initFile is a file werft init writes
+type initFile struct {
+	Path    string
+	Content string
+}
+
+// stack describes how to build repositories of a language
+type stack struct {
+	Name     string
+	Markers  []string
+	Image    string
+	Commands []string
+}
+
+// stacks are the languages werft init detects, most specific first
+var stacks = []stack{
+	{Name: "Go", Markers: []string{"go.mod"}, Image: "golang:1.14", Commands: []string{"go build ./...", "go test ./..."}},
+	{Name: "Node.js (yarn)", Markers: []string{"package.json", "yarn.lock"}, Image: "node:12", Commands: []string{"yarn install --frozen-lockfile", "yarn test"}},
+	{Name: "Node.js", Markers: []string{"package.json"}, Image: "node:12", Commands: []string{"npm ci", "npm test"}},
+	{Name: "Java (Maven)", Markers: []string{"pom.xml"}, Image: "maven:3-jdk-11", Commands: []string{"mvn -B verify"}},
+	{Name: "Java (Gradle)", Markers: []string{"build.gradle"}, Image: "gradle:6-jdk11", Commands: []string{"gradle build --no-daemon"}},
+	{Name: "Java (Gradle)", Markers: []string{"build.gradle.kts"}, Image: "gradle:6-jdk11", Commands: []string{"gradle build --no-daemon"}},
+	{Name: "Rust", Markers: []string{"Cargo.toml"}, Image: "rust:1", Commands: []string{"cargo build", "cargo test"}},
+	{Name: "Python", Markers: []string{"requirements.txt"}, Image: "python:3.8", Commands: []string{"pip install -r requirements.txt", "python -m unittest discover"}},
+	{Name: "Python", Markers: []string{"setup.py"}, Image: "python:3.8", Commands: []string{"pip install .", "python -m unittest discover"}},
 }
 
+// genericStack is used if we cannot detect the language of a repository
+var genericStack = stack{Name: "generic", Image: "alpine:latest", Commands: []string{"echo \"add your build commands to .werft/build.yaml\""}}
+
+// detectStack determines the language of the repository in dir by the files it contains
+func detectStack(dir string) stack {
+	for _, s := range stacks {
+		found := true
+		for _, m := range s.Markers {
+			if !fileExists(dir, m) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return s
+		}
+	}
+	return genericStack
+}
+
+func fileExists(dir, fn string) bool {
+	_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(fn)))
+	return err == nil
+}
+
+// buildJobYAML produces a job which builds and tests a repository
+func buildJobYAML(st stack) string {
+	var cmds strings.Builder
+	for _, c := range st.Commands {
+		fmt.Fprintf(&cmds, "      echo \"[build|PHASE] %s\"\n      %s\n", strings.ReplaceAll(c, "\"", "'"), c)
+	}
+
+	return `pod:
+  containers:
+  - name: build
+    image: ` + st.Image + `
+    workingDir: /workspace
+    imagePullPolicy: IfNotPresent
+    command:
+    - sh
+    - -c
+    - |
+      set -e
+` + cmds.String()
+}
+
+// imageJobYAML builds the container image of a repository using kaniko, which does not need a Docker daemon
+const imageJobYAML = `pod:
+  containers:
+  - name: image
+    image: gcr.io/kaniko-project/executor:debug
+    workingDir: /workspace
+    imagePullPolicy: IfNotPresent
+    command:
+    - /busybox/sh
+    - -c
+    - |
+      set -e
+      echo "[image|PHASE] building the container image"
+      # To push the image, replace --no-push with --destination=<registry>/<image>:{{ .Name }} --digest-file=/tmp/digest
+      # and report the image as result: echo "[image|RESULT] <registry>/<image>:{{ .Name }}@$(cat /tmp/digest)"
+      /kaniko/executor --context=/workspace --dockerfile=/workspace/Dockerfile --no-push
+`
+
 func init() {
 	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().Bool("force", false, "overwrite existing werft configuration")
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestDetectStack(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Files    []string
+		Expected string
+	}{
+		{Name: "empty", Expected: "generic"},
+		{Name: "go", Files: []string{"go.mod", "package.json"}, Expected: "Go"},
+		{Name: "yarn", Files: []string{"package.json", "yarn.lock"}, Expected: "Node.js (yarn)"},
+		{Name: "npm", Files: []string{"package.json"}, Expected: "Node.js"},
+		{Name: "yarn lock only", Files: []string{"yarn.lock"}, Expected: "generic"},
+		{Name: "maven", Files: []string{"pom.xml"}, Expected: "Java (Maven)"},
+		{Name: "gradle kotlin", Files: []string{"build.gradle.kts"}, Expected: "Java (Gradle)"},
+		{Name: "rust", Files: []string{"Cargo.toml"}, Expected: "Rust"},
+		{Name: "python", Files: []string{"setup.py"}, Expected: "Python"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "werft-init")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			for _, f := range test.Files {
+				err = ioutil.WriteFile(filepath.Join(dir, f), nil, 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if act := detectStack(dir).Name; act != test.Expected {
+				t.Errorf("expected %s, got %s", test.Expected, act)
+			}
+		})
+	}
+}
+
+func TestBuildJobYAML(t *testing.T) {
+	for _, st := range append(stacks, genericStack) {
+		t.Run(st.Name, func(t *testing.T) {
+			var job struct {
+				Pod struct {
+					Containers []struct {
+						Image   string   `yaml:"image"`
+						Command []string `yaml:"command"`
+					} `yaml:"containers"`
+				} `yaml:"pod"`
+			}
+			err := yaml.Unmarshal([]byte(buildJobYAML(st)), &job)
+			if err != nil {
+				t.Fatalf("build job is not valid YAML: %v", err)
+			}
+			if len(job.Pod.Containers) != 1 {
+				t.Fatalf("expected one container, got %d", len(job.Pod.Containers))
+			}
+			c := job.Pod.Containers[0]
+			if c.Image != st.Image {
+				t.Errorf("expected image %s, got %s", st.Image, c.Image)
+			}
+			if len(c.Command) != 3 {
+				t.Fatalf("expected sh -c <script>, got %v", c.Command)
+			}
+			for _, cmd := range st.Commands {
+				if !strings.Contains(c.Command[2], "\n"+cmd+"\n") {
+					t.Errorf("expected script to run %q, got %q", cmd, c.Command[2])
+				}
+			}
+		})
+	}
+
+	if err := yaml.Unmarshal([]byte(imageJobYAML), &struct{}{}); err != nil {
+		t.Errorf("image job is not valid YAML: %v", err)
+	}
+}
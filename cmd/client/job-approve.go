@@ -0,0 +1,64 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobApproveCmd represents the approve command
+var jobApproveCmd = &cobra.Command{
+	Use:   "approve <name>",
+	Short: "Approves a job which waits for approval",
+	Long: `Approves a job whose job spec requires approval, so that it can start. Approving a matrix job approves all of
+its jobs. Approving requires write permission on the job's repository on GitHub, and the job spec can limit who
+can approve its jobs. Approvals are recorded in the job's events.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+		comment, _ := cmd.Flags().GetString("comment")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.ApproveJob(context.Background(), &v1.ApproveJobRequest{Name: args[0], GithubToken: token, Comment: comment})
+		if err != nil {
+			return err
+		}
+		for _, n := range resp.Jobs {
+			fmt.Printf("approved %s\n", n)
+		}
+		return nil
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobApproveCmd)
+
+	jobApproveCmd.Flags().String("token", os.Getenv("GITHUB_TOKEN"), "GitHub token identifying you (defaults to GITHUB_TOKEN env var)")
+	jobApproveCmd.Flags().StringP("comment", "m", "", "comment recorded along with the approval, e.g. a change ticket")
+}
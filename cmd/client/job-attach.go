@@ -0,0 +1,168 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// jobAttachCmd represents the attach command
+var jobAttachCmd = &cobra.Command{
+	Use:   "attach <name> [-- command...]",
+	Short: "Runs an interactive command (a shell by default) in a running job",
+	Long: `Runs an interactive command (a shell by default) in a running job, e.g. to debug a failing build.
+The job's repository must allow attaching, and you need sufficient permissions on the repository on GitHub.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream, err := client.AttachJob(ctx)
+		if err != nil {
+			return err
+		}
+
+		var (
+			stdinFd = int(os.Stdin.Fd())
+			tty     = terminal.IsTerminal(stdinFd)
+		)
+		start := &v1.AttachJobStart{
+			Name:    args[0],
+			Command: args[1:],
+			Tty:     tty,
+		}
+		start.Container, _ = cmd.Flags().GetString("container")
+		start.GithubToken, _ = cmd.Flags().GetString("token")
+		if tty {
+			start.Size = terminalSize(stdinFd)
+		}
+		err = stream.Send(&v1.AttachJobRequest{Content: &v1.AttachJobRequest_Start{Start: start}})
+		if err != nil {
+			return err
+		}
+
+		restoreTerminal := func() {}
+		if tty {
+			state, err := terminal.MakeRaw(stdinFd)
+			if err != nil {
+				return err
+			}
+			restoreTerminal = func() { terminal.Restore(stdinFd, state) }
+			defer restoreTerminal()
+
+			go watchTerminalSize(ctx, stream, stdinFd, start.Size)
+		}
+		go forwardStdin(stream)
+
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			switch c := resp.Content.(type) {
+			case *v1.AttachJobResponse_Stdout:
+				os.Stdout.Write(c.Stdout)
+			case *v1.AttachJobResponse_Stderr:
+				os.Stderr.Write(c.Stderr)
+			case *v1.AttachJobResponse_ExitCode:
+				if c.ExitCode != 0 {
+					// os.Exit skips our deferred cleanup
+					restoreTerminal()
+					cancel()
+					conn.Close()
+					os.Exit(int(c.ExitCode))
+				}
+				return nil
+			}
+		}
+	},
+}
+
+// forwardStdin sends our standard input to the attached command
+func forwardStdin(stream v1.WerftService_AttachJobClient) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			serr := stream.Send(&v1.AttachJobRequest{Content: &v1.AttachJobRequest_Stdin{Stdin: buf[:n]}})
+			if serr != nil {
+				return
+			}
+		}
+		if err != nil {
+			stream.CloseSend()
+			return
+		}
+	}
+}
+
+// watchTerminalSize sends changes of our terminal's size to the attached command until ctx is done
+func watchTerminalSize(ctx context.Context, stream v1.WerftService_AttachJobClient, fd int, last *v1.TerminalSize) {
+	// polling the size rather than listening for SIGWINCH keeps this working on all platforms
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		sz := terminalSize(fd)
+		if sz == nil || (last != nil && sz.Width == last.Width && sz.Height == last.Height) {
+			continue
+		}
+		err := stream.Send(&v1.AttachJobRequest{Content: &v1.AttachJobRequest_Resize{Resize: sz}})
+		if err != nil {
+			return
+		}
+		last = sz
+	}
+}
+
+// terminalSize returns the size of the terminal fd refers to, or nil if we cannot determine it
+func terminalSize(fd int) *v1.TerminalSize {
+	w, h, err := terminal.GetSize(fd)
+	if err != nil {
+		return nil
+	}
+	return &v1.TerminalSize{Width: uint32(w), Height: uint32(h)}
+}
+
+func init() {
+	jobCmd.AddCommand(jobAttachCmd)
+
+	jobAttachCmd.Flags().StringP("container", "c", "", "container to run the command in (defaults to the first container of the job)")
+	jobAttachCmd.Flags().String("token", os.Getenv("GITHUB_TOKEN"), "GitHub token identifying you (defaults to GITHUB_TOKEN env var)")
+}
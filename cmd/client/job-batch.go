@@ -0,0 +1,108 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+const batchJobsTpl = `NAME	OUTCOME	NEW JOB	MESSAGE
+{{- range .Results }}
+{{ .Name }}	{{ .Outcome }}	{{ if .NewJob }}{{ .NewJob }}{{ else }}-{{ end }}	{{ .Message -}}
+{{ end }}
+{{ .Succeeded }} succeeded, {{ .Failed }} failed, {{ .Skipped }} skipped{{ if .Truncated }} - more jobs matched than the limit allowed for{{ end }}
+`
+
+// jobCancelCmd represents the cancel command
+var jobCancelCmd = &cobra.Command{
+	Use:   "cancel <expression> ...",
+	Short: "Stops all waiting or running jobs matching a filter",
+	Long: `Stops all waiting or running jobs matching a filter, e.g. everything queued for a deleted branch:
+  werft job cancel repo.repo==werft repo.ref==refs/heads/old-feature
+
+Filter expressions work like those of werft job list. Cancelling jobs requires write permission on their repositories
+on GitHub. Use --dry-run to see which jobs would be cancelled.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBatchJobs(cmd, args, v1.WerftServiceClient.CancelJobs)
+	},
+}
+
+// jobRetryCmd represents the retry command
+var jobRetryCmd = &cobra.Command{
+	Use:   "retry <expression> ...",
+	Short: "Starts all finished jobs matching a filter again",
+	Long: `Starts all finished jobs matching a filter again, e.g. all jobs which failed during an infrastructure outage:
+  werft job retry success==false "created|=2020-04-01T10"
+
+Filter expressions work like those of werft job list. Retrying jobs requires write permission on their repositories
+on GitHub. Use --dry-run to see which jobs would be retried.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBatchJobs(cmd, args, v1.WerftServiceClient.RetryJobs)
+	},
+}
+
+func runBatchJobs(cmd *cobra.Command, args []string, op func(v1.WerftServiceClient, context.Context, *v1.BatchJobsRequest, ...grpc.CallOption) (*v1.BatchJobsResponse, error)) error {
+	filterterms, err := filterexpr.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	token, _ := cmd.Flags().GetString("token")
+	selector, _ := cmd.Flags().GetString("selector")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	limit, _ := cmd.Flags().GetUint("limit")
+	req := &v1.BatchJobsRequest{
+		Filter:        []*v1.FilterExpression{{Terms: filterterms}},
+		LabelSelector: selector,
+		GithubToken:   token,
+		DryRun:        dryRun,
+		Limit:         int32(limit),
+	}
+
+	conn := dial()
+	defer conn.Close()
+	client := v1.NewWerftServiceClient(conn)
+
+	resp, err := op(client, context.Background(), req)
+	if err != nil {
+		return err
+	}
+	return prettyPrint(resp, batchJobsTpl)
+}
+
+func init() {
+	for _, c := range []*cobra.Command{jobCancelCmd, jobRetryCmd} {
+		jobCmd.AddCommand(c)
+
+		c.Flags().String("token", os.Getenv("GITHUB_TOKEN"), "GitHub token identifying you (defaults to GITHUB_TOKEN env var)")
+		c.Flags().StringP("selector", "s", "", "label selector to filter jobs by, e.g. team=platform,stage!=deploy")
+		c.Flags().Bool("dry-run", false, "lists the jobs the operation would apply to without applying it")
+		c.Flags().Uint("limit", 100, "apply the operation to this many jobs at most (oldest first)")
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobBranchesCmd represents the branches command
+var jobBranchesCmd = &cobra.Command{
+	Use:   "branches [<owner>/<repo>]",
+	Short: "Shows the latest job of each branch of a repository",
+	Long: `Shows the branches of a repository which had jobs recently, with their latest job, whether their latest
+finished job succeeded and when a job of theirs last succeeded. Branches are sorted by their latest job, most recent first.
+Without a repository, the repository of the current working directory is used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var repo *v1.Repository
+		if len(args) == 0 {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			md, err := getLocalJobContext(wd, v1.JobTrigger_TRIGGER_MANUAL)
+			if err != nil {
+				return xerrors.Errorf("cannot get local job context: %w", err)
+			}
+			repo = &v1.Repository{Host: md.Repository.Host, Owner: md.Repository.Owner, Repo: md.Repository.Repo}
+		} else {
+			var err error
+			repo, err = reporef.Parse(args[0])
+			if err != nil {
+				return err
+			}
+		}
+		limit, _ := cmd.Flags().GetUint("limit")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetBranches(context.Background(), &v1.GetBranchesRequest{Repository: repo, Limit: int32(limit)})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `BRANCH	LATEST	PHASE	HEALTHY	LAST SUCCESS	JOBS
+{{- range .Branches }}
+{{ .Ref }}	{{ .Latest.Name }}	{{ .Latest.Phase }}	{{ if .LatestFinished }}{{ .LatestFinished.Conditions.Success }}{{ else }}unknown{{ end }}	{{ if .LastSuccess }}{{ .LastSuccess | toRFC3339 }}{{ else }}never{{ end }}	{{ .Jobs -}}
+{{ end }}
+`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobBranchesCmd)
+
+	jobBranchesCmd.Flags().Uint("limit", 1000, "number of most recent jobs of the repository to consider")
+}
@@ -0,0 +1,103 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobCostsCmd represents the costs command
+var jobCostsCmd = &cobra.Command{
+	Use:   "costs [<owner>/<repo>]",
+	Short: "Sums up the estimated cost of jobs per repository, team and/or month",
+	Long: `Sums up the estimated cost of jobs, based on the resources their pods request, the time they ran for and the prices
+configured for werft. Jobs are attributed to teams by their team label.
+
+For example:
+  werft job costs --by team,month --from 2020-01-01`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			req v1.GetJobCostsRequest
+			err error
+		)
+		if len(args) > 0 {
+			req.Repository, err = reporef.Parse(args[0])
+			if err != nil {
+				return err
+			}
+		}
+		by, _ := cmd.Flags().GetStringSlice("by")
+		for _, b := range by {
+			switch strings.TrimSpace(b) {
+			case "repo":
+				req.GroupBy = append(req.GroupBy, v1.CostGrouping_COST_BY_REPOSITORY)
+			case "team":
+				req.GroupBy = append(req.GroupBy, v1.CostGrouping_COST_BY_TEAM)
+			case "month":
+				req.GroupBy = append(req.GroupBy, v1.CostGrouping_COST_BY_MONTH)
+			default:
+				return xerrors.Errorf("cannot group by %s: must be repo, team or month", b)
+			}
+		}
+		from, _ := cmd.Flags().GetString("from")
+		req.From, err = parseExportTime(from)
+		if err != nil {
+			return xerrors.Errorf("invalid --from: %w", err)
+		}
+		to, _ := cmd.Flags().GetString("to")
+		req.To, err = parseExportTime(to)
+		if err != nil {
+			return xerrors.Errorf("invalid --to: %w", err)
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetJobCosts(context.Background(), &req)
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `REPO	TEAM	MONTH	JOBS	POD TIME	COST
+{{- $currency := .Currency }}
+{{- range .Groups }}
+{{ or .Repository "-" }}	{{ or .Team "-" }}	{{ or .Month "-" }}	{{ .Jobs }}	{{ printf "%.0f" .Seconds }}s	{{ printf "%.2f" .Amount }} {{ $currency }}
+{{- end }}
+Total:	{{ printf "%.2f" .Total }} {{ .Currency }}{{ if .Truncated }} (only the most recent jobs were considered){{ end }}
+`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobCostsCmd)
+
+	jobCostsCmd.Flags().StringSlice("by", []string{"repo"}, "what to sum up costs by: repo, team and/or month")
+	jobCostsCmd.Flags().String("from", "", "only consider jobs created at or after this time (RFC3339 or date)")
+	jobCostsCmd.Flags().String("to", "", "only consider jobs created before this time (RFC3339 or date)")
+}
@@ -0,0 +1,89 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobDeleteCmd represents the delete command
+var jobDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Hides a finished job from job listings",
+	Long: `Hides a finished job, including the jobs of its matrix, from job listings. Werft keeps all data of the job,
+and it remains available by name. Deleting a job requires write permission on the job's repository on GitHub.
+
+To remove a job and all of its data, use werft job purge.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		_, err := client.DeleteJob(context.Background(), &v1.DeleteJobRequest{Name: args[0], GithubToken: token})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("deleted %s\n", args[0])
+		return nil
+	},
+}
+
+// jobPurgeCmd represents the purge command
+var jobPurgeCmd = &cobra.Command{
+	Use:   "purge <name>",
+	Short: "Removes a finished job and all of its data",
+	Long: `Removes a finished job, including the jobs of its matrix, and all of their data, i.e. their records, logs,
+job specs, events, provenance and image builds. This cannot be undone. Purging requires one of the admin tokens
+configured for werft.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.PurgeJob(context.Background(), &v1.PurgeJobRequest{Name: args[0], Token: token})
+		if err != nil {
+			return err
+		}
+		for _, n := range resp.Jobs {
+			fmt.Printf("purged %s\n", n)
+		}
+		return nil
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobDeleteCmd)
+	jobCmd.AddCommand(jobPurgeCmd)
+
+	jobDeleteCmd.Flags().String("token", os.Getenv("GITHUB_TOKEN"), "GitHub token identifying you (defaults to GITHUB_TOKEN env var)")
+	jobPurgeCmd.Flags().String("token", os.Getenv("WERFT_ADMIN_TOKEN"), "admin token (defaults to WERFT_ADMIN_TOKEN env var)")
+}
@@ -0,0 +1,54 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobDiffCmd represents the diff command
+var jobDiffCmd = &cobra.Command{
+	Use:   "diff <from> <to>",
+	Short: "Shows what changed between the specs of two jobs",
+	Long: `Shows what changed between the resolved job specs (i.e. the pod templates) two jobs ran with, e.g. a
+successful and a failed run. Jobs with the same spec hash (see "werft job get") ran with the same spec.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.DiffJobSpecs(context.Background(), &v1.DiffJobSpecsRequest{From: args[0], To: args[1]})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `{{- if .Diff }}{{ .Diff }}{{ else }}Both jobs ran with the same spec ({{ .FromHash }})
+{{ end }}`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobDiffCmd)
+}
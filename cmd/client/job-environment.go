@@ -0,0 +1,84 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobEnvironmentCmd represents the environment command
+var jobEnvironmentCmd = &cobra.Command{
+	Use:   "environment <name> [<compare-to>]",
+	Short: "Shows the environment a job ran in or what changed between the environments of two jobs",
+	Long: `Shows the environment a job ran in, i.e. the digests its images resolved to, the kernel and container runtime
+of its node and the environment variables of its containers. Given a second job, shows what changed between the
+environments of both jobs, e.g. to find out whether the environment changed when a build regressed.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx := context.Background()
+		if len(args) == 2 {
+			resp, err := client.DiffJobEnvironments(ctx, &v1.DiffJobEnvironmentsRequest{From: args[0], To: args[1]})
+			if err != nil {
+				return err
+			}
+			return prettyPrint(resp, `{{- if .Changes }}FIELD	FROM	TO
+{{ range .Changes }}{{ .Field }}	{{ .From }}	{{ .To }}
+{{ end }}{{ else }}Both jobs ran in the same environment
+{{ end }}`)
+		}
+
+		resp, err := client.GetJob(ctx, &v1.GetJobRequest{Name: args[0]})
+		if err != nil {
+			return err
+		}
+		return prettyPrint(resp.Result.Environment, `{{- if . -}}
+Node:              {{ .Node }}
+Kernel:            {{ .KernelVersion }}
+OS:                {{ .OsImage }}
+Container runtime: {{ .ContainerRuntime }}
+Kubelet:           {{ .KubeletVersion }}
+{{ range .Containers }}
+Container {{ .Name }}
+  Image:    {{ .Image }}
+  Image ID: {{ .ImageId }}
+{{- if .Env }}
+  Env:
+{{- range $k, $v := .Env }}
+    {{ $k }}={{ $v }}
+{{- end }}
+{{- end }}
+{{ end }}
+{{- else -}}
+The job has no recorded environment
+{{ end }}`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobEnvironmentCmd)
+}
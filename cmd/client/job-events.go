@@ -0,0 +1,56 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobEventsCmd represents the events command
+var jobEventsCmd = &cobra.Command{
+	Use:   "events <name>",
+	Short: "Prints the timeline of a job",
+	Long: `Prints the timeline of a job, i.e. when it was created, queued, its pod scheduled, which phases it went through,
+whether someone asked to stop it, when its pod was deleted and whether its containers stopped or were killed then.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetJobEvents(context.Background(), &v1.GetJobEventsRequest{Name: args[0]})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `TIME	EVENT	PHASE	MESSAGE
+{{- range .Events }}
+{{ .Time | toRFC3339 }}	{{ .Type }}	{{ if .Phase }}{{ .Phase }}{{ else }}-{{ end }}	{{ .Message -}}
+{{ end }}
+`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobEventsCmd)
+}
@@ -0,0 +1,125 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobExportCmd represents the export command
+var jobExportCmd = &cobra.Command{
+	Use:   "export [<key><op><value> ...]",
+	Short: "Exports job records as JSON lines or CSV, e.g. to build reports",
+	Long: `Exports the records of all jobs matching the search expressions (see werft job list) as JSON lines or CSV.
+All search expressions must match. Exporting requires one of the export tokens configured for werft.
+
+For example:
+  werft job export --format csv --from 2020-01-01 repo.repo==werft > jobs.csv`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		terms, err := filterexpr.Parse(args)
+		if err != nil {
+			return err
+		}
+		req := v1.ExportJobsRequest{}
+		for _, t := range terms {
+			req.Filter = append(req.Filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{t}})
+		}
+		req.Token, _ = cmd.Flags().GetString("token")
+
+		switch format, _ := cmd.Flags().GetString("format"); format {
+		case "jsonl":
+			req.Format = v1.ExportFormat_EXPORT_JSONL
+		case "csv":
+			req.Format = v1.ExportFormat_EXPORT_CSV
+		default:
+			return xerrors.Errorf("unknown format %s: must be jsonl or csv", format)
+		}
+
+		from, _ := cmd.Flags().GetString("from")
+		req.From, err = parseExportTime(from)
+		if err != nil {
+			return xerrors.Errorf("invalid --from: %w", err)
+		}
+		to, _ := cmd.Flags().GetString("to")
+		req.To, err = parseExportTime(to)
+		if err != nil {
+			return xerrors.Errorf("invalid --to: %w", err)
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stream, err := client.ExportJobs(ctx, &req)
+		if err != nil {
+			return err
+		}
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			_, err = os.Stdout.Write(resp.Data)
+			if err != nil {
+				return err
+			}
+		}
+	},
+}
+
+// parseExportTime parses an RFC3339 time or a date, e.g. 2020-01-31
+func parseExportTime(s string) (*timestamp.Timestamp, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t, err = time.Parse("2006-01-02", s)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("%s is neither an RFC3339 time nor a date", s)
+	}
+	return ptypes.TimestampProto(t)
+}
+
+func init() {
+	jobCmd.AddCommand(jobExportCmd)
+
+	jobExportCmd.Flags().String("format", "jsonl", "export format: jsonl or csv")
+	jobExportCmd.Flags().String("from", "", "only export jobs created at or after this time (RFC3339 or date)")
+	jobExportCmd.Flags().String("to", "", "only export jobs created before or at this time (RFC3339 or date)")
+	jobExportCmd.Flags().String("token", os.Getenv("WERFT_EXPORT_TOKEN"), "export token (defaults to WERFT_EXPORT_TOKEN env var)")
+}
@@ -0,0 +1,84 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobFlakyCmd represents the flaky command
+var jobFlakyCmd = &cobra.Command{
+	Use:   "flaky [<owner>/<repo>(:ref)]",
+	Short: "Finds jobs which alternate between success and failure",
+	Long: `Finds jobs which alternate between success and failure, most flaky first.
+The score of a job is the share of consecutive runs whose outcome differs, from 0 (never) to 1 (every time).
+Revisions on which a job both failed and succeeded are a strong hint for flakiness.
+Without a repository, the repository of the current working directory is analysed.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var repo *v1.Repository
+		if len(args) == 0 {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			md, err := getLocalJobContext(wd, v1.JobTrigger_TRIGGER_MANUAL)
+			if err != nil {
+				return xerrors.Errorf("cannot get local job context: %w", err)
+			}
+			repo = &v1.Repository{Host: md.Repository.Host, Owner: md.Repository.Owner, Repo: md.Repository.Repo}
+		} else {
+			var err error
+			repo, err = reporef.Parse(args[0])
+			if err != nil {
+				return err
+			}
+		}
+		limit, _ := cmd.Flags().GetUint("limit")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetFlakyJobs(context.Background(), &v1.GetFlakyJobsRequest{Repository: repo, Limit: int32(limit)})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `NAME	SCORE	FLIPS	RUNS	MUTED	LAST FAILURE	FLAKY REVISIONS
+{{- range .Result }}
+{{ .Name }}	{{ printf "%.2f" .Score }}	{{ .Flips }}	{{ .Runs }}	{{ .Muted }}	{{ .LastFailure }}	{{ len .FlakyRevisions -}}
+{{ end }}
+`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobFlakyCmd)
+
+	jobFlakyCmd.Flags().Uint("limit", 500, "number of most recent jobs to analyse")
+}
@@ -22,6 +22,7 @@ package cmd
 
 import (
 	"context"
+	"os"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/spf13/cobra"
@@ -31,17 +32,81 @@ import (
 var jobGetTpl = `Name:	{{ .Name }}
 Phase:	{{ .Phase }}
 Success:	{{ .Conditions.Success }}
+{{- if .Conditions.Skipped }}
+Skipped:	{{ .Details }}
+{{- end }}
+{{- if .Conditions.QuotaExceeded }}
+Quota Exceeded:	{{ .Details }}
+{{- end }}
+{{- if gt .Conditions.Attempt 1 }}
+Attempt:	{{ .Conditions.Attempt }}
+{{- end }}
+{{- if .Conditions.InfrastructureFailure }}
+Infrastructure Failure:	true
+{{- end }}
+{{- if .Conditions.Muted }}
+Muted:	known-flaky job, failure muted and job run again
+{{- end }}
 Metadata:
   Owner:	{{ .Metadata.Owner }}
   Trigger:	{{ .Metadata.Trigger }}
   Started:	{{ .Metadata.Created | toRFC3339 }}
   Finished:	{{ .Metadata.Finished | toRFC3339 }}
+{{- if .Metadata.SpecHash }}
+  Spec Hash:	{{ .Metadata.SpecHash }}
+{{- end }}
+{{- with .Estimate }}
+  Estimated:	{{ .Completion | toRFC3339 }} (median {{ .P50Seconds }}s, p95 {{ .P95Seconds }}s of {{ .Samples }} runs)
+{{- end }}
+{{- if .Metadata.Parent }}
+  Matrix Job:	{{ .Metadata.Parent }}
+{{- end }}
+{{- if .Metadata.Children }}
+Matrix:
+{{- range .Metadata.Children }}
+  {{ . }}
+{{- end }}
+{{- end }}
 Repository:
   Host:	{{ .Metadata.Repository.Host }}
   Owner:	{{ .Metadata.Repository.Owner }}
   Repo:	{{ .Metadata.Repository.Repo }}
   Ref:	{{ .Metadata.Repository.Ref }}
   Revision:	{{ .Metadata.Repository.Revision }}
+{{- with .Metadata.JobSpecSource }}
+Job Spec:	{{ .Ref }} ({{ .Revision }})
+{{- end }}
+{{- with .Metadata.CommitRange }}
+Commits:	{{ .Before }}..{{ .After }}{{ if .Forced }} (forced){{ end }}{{ if .Truncated }} (truncated){{ end }}
+{{- range .Commits }}
+  {{ .Sha }}	{{ .AuthorName }}	{{ firstLine .Message }}
+{{- end }}
+{{- end }}
+{{- if .Metadata.Labels }}
+Labels:
+{{- range $k, $v := .Metadata.Labels }}
+  {{ $k }}:	{{ $v }}
+{{- end }}
+{{- end }}
+{{- with .ResourceUsage }}
+Resource Usage:
+  CPU:	{{ .CpuMillis }}m (peak {{ .PeakCpuMillis }}m)
+  Memory:	{{ toBytes .MemoryBytes }} (peak {{ toBytes .PeakMemoryBytes }})
+  Sampled:	{{ .Sampled | toRFC3339 }}
+{{- end }}
+{{- with .Cost }}
+Cost:	{{ printf "%.2f" .Amount }} {{ .Currency }} ({{ .CpuMillis }}m CPU, {{ toBytes .MemoryBytes }} memory{{ if .Gpus }}, {{ .Gpus }} GPUs{{ end }} for {{ printf "%.0f" .Seconds }}s)
+{{- end }}
+{{- with .LogSummary }}{{ if .FirstError }}
+First Error:	{{ .FirstErrorSlice }}
+{{ .FirstError | indent }}
+{{- end }}{{ end }}
+{{- if .Notes }}
+Notes:
+{{- range .Notes }}
+  {{ .Created | toRFC3339 }}	{{ .Author }}	{{ .Text }}
+{{- end }}
+{{- end }}
 {{- if .Results }}
 Results:
 {{- range .Results }}
@@ -51,6 +116,22 @@ Results:
 {{- end }}
 `
 
+var jobPodTpl = `{{- with .Pod }}
+Pod:
+  Name:	{{ .Name }}
+  Namespace:	{{ .Namespace }}
+  Node:	{{ if .Node }}{{ .Node }}{{ else }}not scheduled{{ end }}
+Debug with:
+  kubectl -n {{ .Namespace }} describe pod {{ .Name }}
+{{- $pod := . }}
+{{- range .Containers }}
+  kubectl -n {{ $pod.Namespace }} logs {{ $pod.Name }} -c {{ . }}
+{{- end }}
+{{ else }}
+Pod:	none - the job has no pod (anymore)
+{{ end -}}
+`
+
 // jobGetCmd represents the list command
 var jobGetCmd = &cobra.Command{
 	Use:   "get [name]",
@@ -78,17 +159,26 @@ var jobGetCmd = &cobra.Command{
 			name = args[0]
 		}
 
+		includePod, _ := cmd.Flags().GetBool("pod")
+		token, _ := cmd.Flags().GetString("token")
 		resp, err := client.GetJob(ctx, &v1.GetJobRequest{
-			Name: name,
+			Name:        name,
+			IncludePod:  includePod,
+			GithubToken: token,
 		})
 		if err != nil {
 			return err
 		}
 
+		if includePod {
+			return prettyPrint(resp, "{{ with .Result }}"+jobGetTpl+"{{ end }}"+jobPodTpl)
+		}
 		return prettyPrint(resp.Result, jobGetTpl)
 	},
 }
 
 func init() {
 	jobCmd.AddCommand(jobGetCmd)
+	jobGetCmd.Flags().Bool("pod", false, "show the pod the job runs in and how to debug it using kubectl")
+	jobGetCmd.Flags().String("token", os.Getenv("GITHUB_TOKEN"), "GitHub token identifying you, needed to see the pod (defaults to GITHUB_TOKEN env var)")
 }
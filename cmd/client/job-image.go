@@ -0,0 +1,57 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobImageCmd represents the image command
+var jobImageCmd = &cobra.Command{
+	Use:   "image <sha256:digest>",
+	Short: "Finds the jobs which built a container image",
+	Long: `Finds the jobs which built a container image, identified by its digest.
+Jobs report the images they built as results of type image, e.g. using
+werft log result image eu.gcr.io/werft/werft:master@sha256:4a1c...`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.FindImageBuilds(context.Background(), &v1.FindImageBuildsRequest{Digest: args[0]})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `JOB	IMAGE	REPO	REVISION	BUILT
+{{- range .Builds }}
+{{ .Job }}	{{ .Image }}	{{ with .Repository }}{{ .Host }}/{{ .Owner }}/{{ .Repo }}	{{ .Revision }}{{ else }}-	-{{ end }}	{{ .Built | toRFC3339 -}}
+{{ end }}
+`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobImageCmd)
+}
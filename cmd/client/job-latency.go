@@ -0,0 +1,80 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/spf13/cobra"
+)
+
+// jobLatencyCmd represents the latency command
+var jobLatencyCmd = &cobra.Command{
+	Use:   "latency [<owner>/<repo>]",
+	Short: "Shows how long jobs take from the webhook which started them until their pod runs",
+	Long: `Shows how long recent jobs took from the webhook which started them until their pod ran, summarised per repository.
+Jobs which were not started by a webhook count from their creation, scheduled jobs and retries from their start time.
+Without a repository, the most recent jobs of all repositories are considered.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var repo *v1.Repository
+		if len(args) > 0 {
+			var err error
+			repo, err = reporef.Parse(args[0])
+			if err != nil {
+				return err
+			}
+		}
+		limit, _ := cmd.Flags().GetUint("limit")
+		jobs, _ := cmd.Flags().GetBool("jobs")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetStartLatency(context.Background(), &v1.GetStartLatencyRequest{Repository: repo, Limit: int32(limit)})
+		if err != nil {
+			return err
+		}
+
+		if jobs {
+			return prettyPrint(resp, `NAME	REPO	LATENCY
+{{- range .Jobs }}
+{{ .Name }}	{{ .Repository.Owner }}/{{ .Repository.Repo }}	{{ printf "%.1f" .LatencySeconds }}s
+{{- end }}
+`)
+		}
+		return prettyPrint(resp, `REPO	SAMPLES	P50	P90	P99	MAX
+{{- range .Repositories }}
+{{ .Repository.Owner }}/{{ .Repository.Repo }}	{{ .Samples }}	{{ printf "%.1f" .P50Seconds }}s	{{ printf "%.1f" .P90Seconds }}s	{{ printf "%.1f" .P99Seconds }}s	{{ printf "%.1f" .MaxSeconds }}s
+{{- end }}
+`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobLatencyCmd)
+
+	jobLatencyCmd.Flags().Uint("limit", 100, "number of most recent jobs to consider")
+	jobLatencyCmd.Flags().Bool("jobs", false, "lists the latency of each job instead of the summary")
+}
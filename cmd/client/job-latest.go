@@ -0,0 +1,97 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobLatestCmd represents the latest command
+var jobLatestCmd = &cobra.Command{
+	Use:   "latest [<owner>/<repo>:<ref>]",
+	Short: "Retrieves the most recent job of a ref",
+	Long: `Retrieves the most recent job of a ref, e.g. to find out if the main branch is green before deploying.
+Refs can be branch names, e.g. werft job latest 32leaves/werft:master --job-spec build --done --success.
+Without a repository, the repository and ref of the current working directory are used.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var repo *v1.Repository
+		if len(args) == 0 {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			md, err := getLocalJobContext(wd, v1.JobTrigger_TRIGGER_MANUAL)
+			if err != nil {
+				return xerrors.Errorf("cannot get local job context: %w", err)
+			}
+			repo = &v1.Repository{Host: md.Repository.Host, Owner: md.Repository.Owner, Repo: md.Repository.Repo, Ref: md.Repository.Ref}
+		} else {
+			var err error
+			repo, err = reporef.Parse(args[0])
+			if err != nil {
+				return err
+			}
+			if repo.Ref == "" {
+				return xerrors.Errorf("missing ref - use <owner>/<repo>:<ref>")
+			}
+		}
+		jobSpec, _ := cmd.Flags().GetString("job-spec")
+		done, _ := cmd.Flags().GetBool("done")
+		success, _ := cmd.Flags().GetBool("success")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetLatestJob(context.Background(), &v1.GetLatestJobRequest{
+			Repository: repo,
+			JobSpec:    jobSpec,
+			Done:       done || success,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = prettyPrint(resp.Result, jobGetTpl)
+		if err != nil {
+			return err
+		}
+		if success && !resp.Result.Conditions.GetSuccess() {
+			return xerrors.Errorf("latest job %s did not succeed", resp.Result.Name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobLatestCmd)
+
+	jobLatestCmd.Flags().String("job-spec", "", "only consider jobs of this job file, e.g. build for .werft/build.yaml")
+	jobLatestCmd.Flags().Bool("done", false, "only consider jobs which have finished")
+	jobLatestCmd.Flags().Bool("success", false, "fail unless the latest finished job succeeded (implies --done)")
+}
@@ -26,6 +26,7 @@ import (
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/prettyprint"
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
 )
@@ -46,6 +47,7 @@ Available keys are:
   repo.ref    source reference, i.e. branch name
   success     one of true, false
   created     time the job started as RFC3339 date
+  label.<key> value of a job label
 
 Available operators are:
   ==          checks for equality
@@ -60,6 +62,9 @@ For example:
   owner!==webui              finds all jobs NOT owned by webui
   repo.repo|=werft           finds all jobs on repositories whose names begin with werft
   phase==done success==true  finds all successfully finished jobs
+
+Jobs can also be selected by their labels using Kubernetes-style label selectors, e.g.
+  --selector "team=platform,stage in (build,test)"
 		`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filterterms, err := filterexpr.Parse(args)
@@ -88,11 +93,21 @@ For example:
 
 		limit, _ := cmd.Flags().GetUint("limit")
 		offset, _ := cmd.Flags().GetUint("offset")
+		selector, _ := cmd.Flags().GetString("selector")
+		project, _ := cmd.Flags().GetString("project")
+		includeDeleted, _ := cmd.Flags().GetBool("include-deleted")
 		req := v1.ListJobsRequest{
-			Filter: filter,
-			Order:  order,
-			Limit:  int32(limit),
-			Start:  int32(offset),
+			Filter:         filter,
+			Order:          order,
+			Limit:          int32(limit),
+			Start:          int32(offset),
+			LabelSelector:  selector,
+			Project:        project,
+			IncludeDeleted: includeDeleted,
+		}
+		if outputFormat == string(prettyprint.TemplateFormat) && outputTemplate == "" {
+			// the default template only shows what's part of the summary
+			req.View = v1.JobView_JOB_VIEW_SUMMARY
 		}
 
 		conn := dial()
@@ -105,9 +120,10 @@ For example:
 			return err
 		}
 
-		return prettyPrint(resp, `NAME	OWNER	REPO	PHASE	SUCCESS
+		return prettyPrint(resp, `NAME	OWNER	REPO	PHASE	SUCCESS	NOTE
 {{- range .Result }}
-{{ .Name }}	{{ .Metadata.Owner }}	{{ .Metadata.Repository.Owner }}/{{ .Metadata.Repository.Repo }}	{{ .Phase }}	{{ .Conditions.Success -}}
+{{ $note := "" }}{{ range .Notes }}{{ $note = .Text }}{{ end -}}
+{{ .Name }}	{{ .Metadata.Owner }}	{{ .Metadata.Repository.Owner }}/{{ .Metadata.Repository.Repo }}	{{ .Phase }}	{{ .Conditions.Success }}	{{ firstLine $note -}}
 {{ end }}
 `)
 	},
@@ -136,4 +152,7 @@ func init() {
 	jobListCmd.Flags().Uint("offset", 0, "return results starting later than zero")
 	jobListCmd.Flags().StringArray("order", []string{"name:desc"}, "order the result list by fields")
 	jobListCmd.Flags().BoolP("local", "l", false, "finds jobs matching the local Git context")
+	jobListCmd.Flags().StringP("selector", "s", "", "label selector to filter jobs by, e.g. team=platform,stage!=deploy")
+	jobListCmd.Flags().StringP("project", "p", "", "lists the jobs of a project only")
+	jobListCmd.Flags().Bool("include-deleted", false, "lists deleted jobs too")
 }
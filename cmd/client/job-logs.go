@@ -21,13 +21,19 @@ package cmd
 // THE SOFTWARE.
 
 import (
+	"compress/gzip"
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // jobLogsCmd represents the list command
@@ -59,25 +65,151 @@ var jobLogsCmd = &cobra.Command{
 			name = args[0]
 		}
 
+		if download, _ := cmd.Flags().GetBool("download"); download {
+			slice, _ := cmd.Flags().GetString("slice")
+			fn, _ := cmd.Flags().GetString("file")
+			compress, _ := cmd.Flags().GetBool("gzip")
+			offset, _ := cmd.Flags().GetInt64("byte-offset")
+			limit, _ := cmd.Flags().GetInt64("byte-limit")
+			return downloadJobLogs(client, &v1.ListenRequest{Name: name, Slice: slice, ByteOffset: offset, ByteLimit: limit}, fn, compress)
+		}
+
 		return followJob(client, name, "")
 	},
 }
 
+// downloadJobLogs writes the stored log of a job (or a single slice or byte range thereof) to a file or stdout
+func downloadJobLogs(client v1.WerftServiceClient, req *v1.ListenRequest, fn string, compress bool) (err error) {
+	slice := req.Slice
+	req.Logs = v1.ListenRequestLogs_LOGS_UNSLICED
+	if slice != "" {
+		req.Logs = v1.ListenRequestLogs_LOGS_RAW
+	}
+	logs, err := client.Listen(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if fn != "" {
+		f, err := os.Create(fn)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			cerr := f.Close()
+			if err == nil {
+				err = cerr
+			}
+		}()
+		out = f
+	}
+	if compress {
+		gw := gzip.NewWriter(out)
+		defer func() {
+			cerr := gw.Close()
+			if err == nil {
+				err = cerr
+			}
+		}()
+		out = gw
+	}
+
+	for {
+		msg, err := logs.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		data := msg.GetSlice()
+		if data == nil {
+			continue
+		}
+		if slice == "" {
+			_, err = io.WriteString(out, data.Payload)
+		} else if data.Name == slice && data.Type == v1.LogSliceType_SLICE_CONTENT {
+			_, err = fmt.Fprintln(out, data.Payload)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// followJob prints the log output of a job until it's done and exits with the job's result. If the connection to the
+// werft server breaks, e.g. because the server restarts, we reconnect and resume where we left off.
 func followJob(client v1.WerftServiceClient, name, prefix string) error {
-	ctx := context.Background()
+	var (
+		offset    int64
+		backoff   = reconnectBackoffMin
+		lostSince time.Time
+	)
+	for {
+		received, err := listenToJob(client, name, prefix, offset)
+		offset += received
+		if received > 0 {
+			lostSince = time.Time{}
+			backoff = reconnectBackoffMin
+		}
+		if !isReconnectable(err) || reconnectTimeout <= 0 {
+			return err
+		}
+		if lostSince.IsZero() {
+			lostSince = time.Now()
+		} else if time.Since(lostSince) > reconnectTimeout {
+			return xerrors.Errorf("cannot reconnect to werft: %w", err)
+		}
+
+		log.WithError(err).WithField("offset", offset).Warnf("lost connection to werft - reconnecting in %s", backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > reconnectBackoffMax {
+			backoff = reconnectBackoffMax
+		}
+	}
+}
+
+const (
+	reconnectBackoffMin = 1 * time.Second
+	reconnectBackoffMax = 30 * time.Second
+)
+
+// isReconnectable returns true if err indicates a broken connection rather than a problem with the request
+func isReconnectable(err error) bool {
+	if err == nil || err == io.EOF {
+		return false
+	}
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// listenToJob prints the log output of a job, skipping the first offset log slices.
+// It returns the number of log slices it received.
+func listenToJob(client v1.WerftServiceClient, name, prefix string, offset int64) (received int64, err error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	logs, err := client.Listen(ctx, &v1.ListenRequest{
 		Name:    name,
 		Logs:    v1.ListenRequestLogs_LOGS_RAW,
 		Updates: true,
+		Offset:  offset,
 	})
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	for {
 		msg, err := logs.Recv()
 		if err != nil {
-			return err
+			return received, err
 		}
 
 		if update := msg.GetUpdate(); update != nil {
@@ -92,6 +224,7 @@ func followJob(client v1.WerftServiceClient, name, prefix string) error {
 			}
 		}
 		if data := msg.GetSlice(); data != nil {
+			received++
 			if prefix == "" {
 				pringLogSlice(data)
 			} else {
@@ -140,4 +273,11 @@ func printLogSliceWithPrefix(prefix string, slice *v1.LogSliceEvent) {
 
 func init() {
 	jobCmd.AddCommand(jobLogsCmd)
+
+	jobLogsCmd.Flags().Bool("download", false, "downloads the complete stored log instead of following the job")
+	jobLogsCmd.Flags().String("slice", "", "only download the content of this log slice (requires --download)")
+	jobLogsCmd.Flags().StringP("file", "f", "", "write the downloaded log to this file instead of stdout (requires --download)")
+	jobLogsCmd.Flags().Bool("gzip", false, "gzip compress the downloaded log (requires --download)")
+	jobLogsCmd.Flags().Int64("byte-offset", 0, "start downloading the log at this byte (requires --download)")
+	jobLogsCmd.Flags().Int64("byte-limit", 0, "download at most this many bytes of the log (requires --download)")
 }
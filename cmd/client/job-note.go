@@ -0,0 +1,65 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobNoteCmd represents the note command
+var jobNoteCmd = &cobra.Command{
+	Use:   "note <name> <text>",
+	Short: "Adds a note to a job",
+	Long: `Adds a note to a job, e.g. "failure caused by flaky registry, see incident-123", so that the job history
+carries the context of what happened. Notes are shown by werft job get and werft job list.
+Adding notes requires write permission on the job's repository on GitHub.`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		_, err := client.AddJobNote(context.Background(), &v1.AddJobNoteRequest{
+			Name:        args[0],
+			GithubToken: token,
+			Text:        strings.Join(args[1:], " "),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("added note to %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobNoteCmd)
+
+	jobNoteCmd.Flags().String("token", os.Getenv("GITHUB_TOKEN"), "GitHub token identifying you (defaults to GITHUB_TOKEN env var)")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/provenance"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobProvenanceCmd represents the provenance command
+var jobProvenanceCmd = &cobra.Command{
+	Use:   "provenance <name>",
+	Short: "Prints the signed provenance of a job",
+	Long: `Prints the signed provenance of a finished job, a DSSE envelope holding an in-toto statement with SLSA provenance.
+The provenance names the revision the job ran on, its spec hash, start and end time, and its results as subjects.
+Given the public key of werft, the signature is verified and the statement is printed instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var key []byte
+		if fn, _ := cmd.Flags().GetString("verify"); fn != "" {
+			var err error
+			key, err = ioutil.ReadFile(fn)
+			if err != nil {
+				return err
+			}
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetJobProvenance(context.Background(), &v1.GetJobProvenanceRequest{Name: args[0]})
+		if err != nil {
+			return err
+		}
+
+		var env provenance.Envelope
+		err = json.Unmarshal(resp.Envelope, &env)
+		if err != nil {
+			return xerrors.Errorf("cannot unmarshal provenance: %w", err)
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if key == nil {
+			return enc.Encode(env)
+		}
+
+		pub, err := provenance.ParsePublicKey(key)
+		if err != nil {
+			return err
+		}
+		stmt, err := provenance.Verify(&env, pub)
+		if err != nil {
+			return err
+		}
+		return enc.Encode(stmt)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobProvenanceCmd)
+
+	jobProvenanceCmd.Flags().String("verify", "", "path to the PEM encoded public key of werft to verify the provenance with")
+}
@@ -0,0 +1,55 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobQueueCmd represents the queue command
+var jobQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Lists the jobs which wait to run, and why they are waiting",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetQueueStatus(context.Background(), &v1.GetQueueStatusRequest{})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `POS	NAME	REPO	REASON	SINCE	DETAILS
+{{- range .Jobs }}
+{{ .Position }}	{{ .Job.Name }}	{{ .Job.Metadata.Repository.Owner }}/{{ .Job.Metadata.Repository.Repo }}	{{ .Reason }}	{{ .Since | toRFC3339 }}	{{ .Details -}}
+{{ end }}
+`)
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobQueueCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// jobSBOMCmd represents the sbom command
+var jobSBOMCmd = &cobra.Command{
+	Use:   "sbom <name>",
+	Short: "Prints the software bills of materials of the images a job built",
+	Long: `Prints the software bills of materials (SBOM) werft generated for the images a job reported as results.
+The SBOMs are printed to stdout, the image and format of each to stderr. Use --image to print the SBOM of a single image,
+or --output-dir to write one file per image instead.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		image, _ := cmd.Flags().GetString("image")
+		dir, _ := cmd.Flags().GetString("output-dir")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetJobSBOM(context.Background(), &v1.GetJobSBOMRequest{Name: args[0], Image: image})
+		if err != nil {
+			return err
+		}
+
+		for _, sbom := range resp.Sboms {
+			if dir != "" {
+				fn := filepath.Join(dir, sbomFilename(sbom))
+				err = ioutil.WriteFile(fn, sbom.Content, 0644)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "%s (%s): %s\n", sbom.Image, sbom.Format, fn)
+				continue
+			}
+
+			fmt.Fprintf(os.Stderr, "%s (%s)\n", sbom.Image, sbom.Format)
+			os.Stdout.Write(sbom.Content)
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+// sbomFilename produces a file name for an SBOM from its image, e.g. eu.gcr.io_werft_werft_master.spdx-json.json
+func sbomFilename(sbom *v1.JobSBOM) string {
+	name := sbom.Image
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.NewReplacer("/", "_", ":", "_").Replace(name)
+	return fmt.Sprintf("%s.%s.json", name, sbom.Format)
+}
+
+func init() {
+	jobCmd.AddCommand(jobSBOMCmd)
+
+	jobSBOMCmd.Flags().String("image", "", "print the SBOM of this image only (name or name@digest)")
+	jobSBOMCmd.Flags().String("output-dir", "", "write one file per image to this directory instead of printing the SBOMs")
+}
@@ -0,0 +1,96 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"io"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// jobSpecsCmd represents the specs command
+var jobSpecsCmd = &cobra.Command{
+	Use:   "specs [<owner>/<repo>(:ref|@revision)]",
+	Short: "Lists the job specs of a repository",
+	Long: `Lists the job specs of a repository at a ref or revision, including the annotations they take.
+Without a ref, the default branch of the repository is used.
+Without a repository, the repository of the current working directory is used at its checked out revision.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var repo *v1.Repository
+		if len(args) == 0 {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			md, err := getLocalJobContext(wd, v1.JobTrigger_TRIGGER_MANUAL)
+			if err != nil {
+				return xerrors.Errorf("cannot get local job context: %w", err)
+			}
+			repo = md.Repository
+		} else {
+			var err error
+			repo, err = reporef.Parse(args[0])
+			if err != nil {
+				return err
+			}
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftUIClient(conn)
+
+		specs, err := client.ListJobSpecs(context.Background(), &v1.ListJobSpecsRequest{Repository: repo})
+		if err != nil {
+			return err
+		}
+		for {
+			spec, err := specs.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			err = prettyPrint(spec, `{{ .Name }}	{{ .Path }}
+{{- if .Description }}
+  {{ .Description }}
+{{- end }}
+{{- range .Arguments }}
+  {{ .Name }}{{ if .Required }} (required){{ end }}	{{ .Description }}
+{{- end }}
+`)
+			if err != nil {
+				return err
+			}
+		}
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobSpecsCmd)
+}
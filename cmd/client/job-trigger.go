@@ -0,0 +1,74 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+)
+
+// jobTriggerCmd represents the trigger command
+var jobTriggerCmd = &cobra.Command{
+	Use:   "trigger <name>",
+	Short: "Prints the payload of the webhook which started a job",
+	Long: `Prints the payload of the webhook which started a job, e.g. the GitHub push event, to find out why werft ran it.
+The payload is printed to stdout, where it came from to stderr. JSON payloads are indented.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetTriggerPayload(context.Background(), &v1.GetTriggerPayloadRequest{Name: args[0]})
+		if err != nil {
+			return err
+		}
+
+		trigger := resp.Trigger
+		fmt.Fprintf(os.Stderr, "Source:\t\t%s\nEvent:\t\t%s\n", trigger.Source, trigger.EventType)
+		if trigger.Delivery != "" {
+			fmt.Fprintf(os.Stderr, "Delivery:\t%s\n", trigger.Delivery)
+		}
+		if received, err := ptypes.Timestamp(trigger.Received); err == nil {
+			fmt.Fprintf(os.Stderr, "Received:\t%s\n", received.Format(time.RFC3339))
+		}
+
+		payload := trigger.Payload
+		var buf bytes.Buffer
+		if json.Indent(&buf, payload, "", "  ") == nil {
+			payload = append(buf.Bytes(), '\n')
+		}
+		_, err = os.Stdout.Write(payload)
+		return err
+	},
+}
+
+func init() {
+	jobCmd.AddCommand(jobTriggerCmd)
+}
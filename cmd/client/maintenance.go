@@ -0,0 +1,112 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+const maintenanceTemplate = `{{ if .Mode.Enabled -}}
+job processing is paused since {{ .Mode.Since | toRFC3339 }}{{ if .Mode.Reason }}: {{ .Mode.Reason }}{{ end }}
+{{ else -}}
+job processing is running
+{{ end -}}
+`
+
+// maintenanceCmd represents the maintenance command
+var maintenanceCmd = &cobra.Command{
+	Use:   "maintenance",
+	Short: "Pauses and resumes job processing, e.g. during cluster maintenance",
+	Long: `Pauses and resumes job processing, e.g. during cluster maintenance.
+While job processing is paused, werft still accepts webhooks and starts jobs, but holds them back
+until job processing resumes. Jobs which already run are not affected. Pausing and resuming job processing
+requires one of the admin tokens configured for werft.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetMaintenanceMode(context.Background(), &v1.GetMaintenanceModeRequest{})
+		if err != nil {
+			return err
+		}
+		return prettyPrint(resp, maintenanceTemplate)
+	},
+}
+
+// maintenancePauseCmd represents the maintenance pause command
+var maintenancePauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Stops werft from starting new jobs until job processing resumes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reason, _ := cmd.Flags().GetString("reason")
+		token, _ := cmd.Flags().GetString("token")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.SetMaintenanceMode(context.Background(), &v1.SetMaintenanceModeRequest{Enabled: true, Reason: reason, Token: token})
+		if err != nil {
+			return err
+		}
+		return prettyPrint(resp, maintenanceTemplate)
+	},
+}
+
+// maintenanceResumeCmd represents the maintenance resume command
+var maintenanceResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resumes job processing and starts all jobs which were held back",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.SetMaintenanceMode(context.Background(), &v1.SetMaintenanceModeRequest{Enabled: false, Token: token})
+		if err != nil {
+			return err
+		}
+		return prettyPrint(resp, maintenanceTemplate)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(maintenanceCmd)
+	maintenanceCmd.AddCommand(maintenancePauseCmd)
+	maintenanceCmd.AddCommand(maintenanceResumeCmd)
+
+	maintenancePauseCmd.Flags().String("reason", "", "tells users why job processing is paused")
+	for _, c := range []*cobra.Command{maintenancePauseCmd, maintenanceResumeCmd} {
+		c.Flags().String("token", os.Getenv("WERFT_ADMIN_TOKEN"), "admin token (defaults to WERFT_ADMIN_TOKEN env var)")
+	}
+	maintenanceCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	maintenanceCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
@@ -0,0 +1,94 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// projectCmd represents the project command
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Lists projects and shows the health of their repositories",
+	Args:  cobra.ExactArgs(1),
+}
+
+// projectListCmd represents the project list command
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the projects configured on the server",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.ListProjects(context.Background(), &v1.ListProjectsRequest{})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `NAME	REPOS	DESCRIPTION
+{{- range .Projects }}
+{{ .Name }}	{{ range $i, $r := .Repos }}{{ if $i }},{{ end }}{{ $r }}{{ end }}	{{ .Description -}}
+{{ end }}
+`)
+	},
+}
+
+// projectHealthCmd represents the project health command
+var projectHealthCmd = &cobra.Command{
+	Use:   "health <name>",
+	Short: "Shows the latest job of each repository of a project",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ref, _ := cmd.Flags().GetString("ref")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetProjectHealth(context.Background(), &v1.GetProjectHealthRequest{Name: args[0], Ref: ref})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `REPO	LATEST	PHASE	HEALTHY
+{{- range .Repositories }}
+{{ .Repository.Owner }}/{{ .Repository.Repo }}	{{ .Latest.Name }}	{{ .Latest.Phase }}	{{ if .LatestFinished }}{{ .LatestFinished.Conditions.Success }}{{ else }}unknown{{ end -}}
+{{ end }}
+`)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectHealthCmd)
+
+	projectHealthCmd.Flags().String("ref", "", "only considers jobs of this ref, e.g. refs/heads/master")
+
+	projectCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	projectCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
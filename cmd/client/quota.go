@@ -0,0 +1,63 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// quotaCmd represents the quota command
+var quotaCmd = &cobra.Command{
+	Use:   "quota [name]",
+	Short: "Shows the current usage of the quotas configured on the server",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var name string
+		if len(args) > 0 {
+			name = args[0]
+		}
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetQuotaUsage(context.Background(), &v1.GetQuotaUsageRequest{Name: name})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `NAME	CONCURRENT JOBS	JOB MINUTES	PERIOD
+{{- range .Quotas }}
+{{ .Name }}	{{ .ConcurrentJobs }}{{ if .MaxConcurrentJobs }}/{{ .MaxConcurrentJobs }}{{ end }}	{{ printf "%.0f" .JobMinutes }}{{ if .MaxJobMinutes }}/{{ .MaxJobMinutes }}{{ end }}	{{ .PeriodSeconds }}s
+{{- end }}
+`)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(quotaCmd)
+
+	quotaCmd.Flags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	quotaCmd.Flags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
@@ -0,0 +1,104 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// requiredChecksCmd represents the required-checks command
+var requiredChecksCmd = &cobra.Command{
+	Use:   "required-checks",
+	Short: "Manages the required status checks of protected branches",
+	Args:  cobra.NoArgs,
+}
+
+// requiredChecksSyncCmd represents the required-checks sync command
+var requiredChecksSyncCmd = &cobra.Command{
+	Use:   "sync [<owner>/<repo>]",
+	Short: "Makes the required status checks of a protected branch match the jobs werft reports",
+	Long: `Makes the werft status checks among the required status checks of a protected branch match the jobs
+the repository's werft config starts. Status checks of other CI systems are left alone.
+Without a repository, the repository of the current working directory is used. Syncing requires
+one of the admin tokens configured for werft.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var repo *v1.Repository
+		if len(args) == 0 {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			md, err := getLocalJobContext(wd, v1.JobTrigger_TRIGGER_MANUAL)
+			if err != nil {
+				return xerrors.Errorf("cannot get local job context: %w", err)
+			}
+			repo = &v1.Repository{Host: md.Repository.Host, Owner: md.Repository.Owner, Repo: md.Repository.Repo}
+		} else {
+			var err error
+			repo, err = reporef.Parse(args[0])
+			if err != nil {
+				return err
+			}
+		}
+		branch, _ := cmd.Flags().GetString("branch")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		token, _ := cmd.Flags().GetString("token")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.SyncRequiredChecks(context.Background(), &v1.SyncRequiredChecksRequest{
+			Repository: repo,
+			Branch:     branch,
+			DryRun:     dryRun,
+			Token:      token,
+		})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `{{- range .Added }}+ {{ . }}
+{{ end -}}
+{{- range .Removed }}- {{ . }}
+{{ end -}}
+required: {{ range $i, $c := .Contexts }}{{ if $i }}, {{ end }}{{ $c }}{{ end }}
+`)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(requiredChecksCmd)
+	requiredChecksCmd.AddCommand(requiredChecksSyncCmd)
+
+	requiredChecksSyncCmd.Flags().String("branch", "", "protected branch (defaults to the default branch of the repository)")
+	requiredChecksSyncCmd.Flags().Bool("dry-run", false, "show the changes without making them")
+	requiredChecksSyncCmd.Flags().String("token", os.Getenv("WERFT_ADMIN_TOKEN"), "admin token (defaults to WERFT_ADMIN_TOKEN env var)")
+	requiredChecksCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	requiredChecksCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
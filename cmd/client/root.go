@@ -23,15 +23,20 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 var (
-	verbose bool
-	host    string
+	verbose          bool
+	host             string
+	keepaliveTime    time.Duration
+	keepaliveTimeout time.Duration
+	reconnectTimeout time.Duration
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -67,10 +72,26 @@ func init() {
 
 	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "en/disable verbose logging")
 	rootCmd.PersistentFlags().StringVar(&host, "host", werftHost, "werft host to talk to (defaults to WERFT_HOST env var)")
+	rootCmd.PersistentFlags().DurationVar(&keepaliveTime, "keepalive", 30*time.Second, "ping the werft server after this time without activity to keep the connection alive (0 disables keepalive)")
+	rootCmd.PersistentFlags().DurationVar(&keepaliveTimeout, "keepalive-timeout", 10*time.Second, "consider the connection broken if a keepalive ping isn't answered within this time")
+	rootCmd.PersistentFlags().DurationVar(&reconnectTimeout, "reconnect-timeout", 5*time.Minute, "keep trying to reconnect for this long when following logs and the connection breaks (0 disables reconnects)")
 }
 
 func dial() *grpc.ClientConn {
-	conn, err := grpc.Dial(host, grpc.WithInsecure())
+	opts := []grpc.DialOption{
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(versionUnaryInterceptor),
+		grpc.WithStreamInterceptor(versionStreamInterceptor),
+	}
+	if keepaliveTime > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                keepaliveTime,
+			Timeout:             keepaliveTimeout,
+			PermitWithoutStream: true,
+		}))
+	}
+
+	conn, err := grpc.Dial(host, opts...)
 	if err != nil {
 		log.WithError(err).Fatal("cannot connect to werft server")
 	}
@@ -78,7 +78,7 @@ var runGithubCmd = &cobra.Command{
 		if !ok {
 			var vs []string
 			for k := range v1.JobTrigger_value {
-				vs = append(vs, strings.ToLower(strings.TrimPrefix("TRIGGER_", k)))
+				vs = append(vs, strings.ToLower(strings.TrimPrefix(k, "TRIGGER_")))
 			}
 
 			return xerrors.Errorf("Invalid value for --trigger. Valid choices are %s", strings.Join(vs, "\n"))
@@ -92,6 +92,7 @@ var runGithubCmd = &cobra.Command{
 		}
 
 		req.JobPath, _ = cmd.Flags().GetString("remote-job-path")
+		req.JobSpecRef, _ = cmd.Flags().GetString("job-spec-ref")
 		if fn, _ := flags.GetString("job-file"); fn != "" {
 			fc, err := ioutil.ReadFile(fn)
 			if err != nil {
@@ -215,5 +216,6 @@ func init() {
 
 	runGithubCmd.Flags().String("token", "", "Token to use for authorization against GitHub")
 	runGithubCmd.Flags().String("remote-job-path", "", "start the job at that path in the repo (defaults to the default job of the repo)")
+	runGithubCmd.Flags().String("job-spec-ref", "", "read the job spec and werft config from this ref or revision, but check out the source of the job's ref (e.g. --job-spec-ref main)")
 	runGithubCmd.Flags().StringArrayP("sideload", "s", []string{}, "sideload files overwriting/adding to the Git working copy")
 }
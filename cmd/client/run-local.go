@@ -27,9 +27,11 @@ import (
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/paulbellamy/ratecounter"
 	log "github.com/sirupsen/logrus"
@@ -53,7 +55,7 @@ var runLocalCmd = &cobra.Command{
 		if !ok {
 			var vs []string
 			for k := range v1.JobTrigger_value {
-				vs = append(vs, strings.ToLower(strings.TrimPrefix("TRIGGER_", k)))
+				vs = append(vs, strings.ToLower(strings.TrimPrefix(k, "TRIGGER_")))
 			}
 
 			return xerrors.Errorf("Invalid value for --trigger. Valid choices are %s", strings.Join(vs, "\n"))
@@ -82,6 +84,10 @@ var runLocalCmd = &cobra.Command{
 		if err != nil {
 			return xerrors.Errorf("cannot read job file: %w", err)
 		}
+		jobYAML, err = resolveLocalFragments(workingdir, jobPath, jobYAML)
+		if err != nil {
+			return err
+		}
 
 		conn := dial()
 		defer conn.Close()
@@ -198,3 +204,23 @@ func init() {
 	runLocalCmd.Flags().String("cwd", wd, "working directory")
 	runLocalCmd.Flags().StringP("job-file", "j", "", "start a particular job (defaults to the default job of the repo)")
 }
+
+// resolveLocalFragments pulls the fragments a job file extends or includes from the working copy into the job spec.
+// Fragments of other repositories are only available to jobs werft starts from GitHub.
+func resolveLocalFragments(workingdir, jobPath string, jobYAML []byte) ([]byte, error) {
+	if abs, err := filepath.Abs(workingdir); err == nil {
+		workingdir = abs
+	}
+	if abs, err := filepath.Abs(jobPath); err == nil {
+		if rel, err := filepath.Rel(workingdir, abs); err == nil {
+			jobPath = filepath.ToSlash(rel)
+		}
+	}
+
+	return repoconfig.ResolveFragments(context.Background(), jobPath, jobYAML, func(ctx context.Context, ref repoconfig.FragmentRef) ([]byte, error) {
+		if ref.Repo != "" {
+			return nil, xerrors.Errorf("fragments of other repositories are not supported for local jobs")
+		}
+		return ioutil.ReadFile(filepath.Join(workingdir, filepath.FromSlash(ref.Path)))
+	})
+}
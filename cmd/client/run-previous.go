@@ -41,6 +41,10 @@ var runPreviousJobCmd = &cobra.Command{
 		if len(annotations) > 0 {
 			return fmt.Errorf("--annotation is not supported when replaying a previous job")
 		}
+		secrets, _ := flags.GetStringToString("secret-annotation")
+		if len(secrets) > 0 {
+			return fmt.Errorf("--secret-annotation is not supported when replaying a previous job")
+		}
 
 		conn := dial()
 		defer conn.Close()
@@ -112,7 +112,7 @@ func configureRepoFromOrigin(repo *v1.Repository, origin string) error {
 	return nil
 }
 
-// adds the annotations from --annotation to the metadata
+// adds the annotations from --annotation and --secret-annotation to the metadata
 func addUserAnnotations(md *v1.JobMetadata) {
 	annotations, _ := runCmd.PersistentFlags().GetStringToString("annotations")
 	for k, v := range annotations {
@@ -121,6 +121,14 @@ func addUserAnnotations(md *v1.JobMetadata) {
 			Value: v,
 		})
 	}
+	secrets, _ := runCmd.PersistentFlags().GetStringToString("secret-annotation")
+	for k, v := range secrets {
+		md.Annotations = append(md.Annotations, &v1.Annotation{
+			Key:    k,
+			Value:  v,
+			Secret: true,
+		})
+	}
 }
 
 func getWaitUntil() (*time.Time, error) {
@@ -148,9 +156,10 @@ func init() {
 
 	runCmd.PersistentFlags().StringP("job-file", "j", "", "location of the job file (defaults to the default job in the werft config)")
 	runCmd.PersistentFlags().String("config-file", "$CWD/.werft/config.yaml", "location of the werft config file")
-	runCmd.PersistentFlags().String("trigger", "manual", "job trigger. One of push, manual")
+	runCmd.PersistentFlags().String("trigger", "manual", "job trigger. One of manual, push, deleted, tag, pull_request, merge_group, scheduled")
 	runCmd.PersistentFlags().BoolP("follow", "f", false, "follow the log output once the job is running")
 	runCmd.PersistentFlags().StringToStringP("annotations", "a", map[string]string{}, "adds an annotation to the job")
+	runCmd.PersistentFlags().StringToString("secret-annotation", map[string]string{}, "hands a secret to the job as environment variable - werft does not store its value")
 	runCmd.PersistentFlags().String("follow-with-prefix", "", "prints the log output with a prefix and disbales colors - useful for starting jobs from within jobs")
 	runCmd.PersistentFlags().String("wait-until", "", "delays the execution of the job by/until some time - use a valid duration (e.g. 5h) or RFC3339 timestamp")
 }
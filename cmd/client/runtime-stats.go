@@ -0,0 +1,83 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// runtimeStatsCmd represents the runtime-stats command
+var runtimeStatsCmd = &cobra.Command{
+	Use:   "runtime-stats",
+	Short: "Shows the internal state of the werft server",
+	Long: `Shows the goroutines, memory and the size of the internal queues and buffers of the werft server,
+e.g. to diagnose hangs and memory growth. With --goroutines, the stack traces of all goroutines are
+printed instead. Requires one of the admin tokens configured for werft.
+
+CPU and heap profiles are served at /debug/pprof/ on the web port to the same admin tokens, e.g.
+  curl -H "Authorization: Bearer $WERFT_ADMIN_TOKEN" https://werft.example.com/debug/pprof/heap > heap.pprof
+  go tool pprof heap.pprof`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, _ := cmd.Flags().GetString("token")
+		goroutines, _ := cmd.Flags().GetBool("goroutines")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.GetRuntimeStats(context.Background(), &v1.GetRuntimeStatsRequest{
+			Token:         token,
+			GoroutineDump: goroutines,
+		})
+		if err != nil {
+			return err
+		}
+		if goroutines {
+			fmt.Print(resp.GoroutineDump)
+			return nil
+		}
+
+		return prettyPrint(resp, `started:	{{ if .Started }}{{ .Started | toRFC3339 }}{{ else }}unknown{{ end }}
+go version:	{{ .GoVersion }}
+goroutines:	{{ .Goroutines }}
+heap:	{{ .HeapAllocBytes }} bytes in {{ .HeapObjects }} objects
+sys:	{{ .SysBytes }} bytes
+gc:	{{ .GcCycles }} cycles, {{ .GcPauseTotalNs }}ns paused
+{{- range $k, $v := .Buffers }}
+{{ $k }}:	{{ $v }}
+{{- end }}
+`)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(runtimeStatsCmd)
+	runtimeStatsCmd.Flags().Bool("goroutines", false, "print the stack traces of all goroutines")
+	runtimeStatsCmd.Flags().String("token", os.Getenv("WERFT_ADMIN_TOKEN"), "admin token (defaults to WERFT_ADMIN_TOKEN env var)")
+	runtimeStatsCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	runtimeStatsCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/spf13/cobra"
+)
+
+// schedulesCmd represents the schedules command
+var schedulesCmd = &cobra.Command{
+	Use:   "schedules",
+	Short: "Lists the schedules registered with werft and when they run next",
+	Long: `Lists the schedules registered by schedulers such as the cron plugin, and when they run next.
+Next runs are computed in the time zone of each schedule and printed in UTC.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runs, _ := cmd.Flags().GetInt32("runs")
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		resp, err := client.ListSchedules(context.Background(), &v1.ListSchedulesRequest{Runs: runs})
+		if err != nil {
+			return err
+		}
+
+		return prettyPrint(resp, `SCHEDULER	NAME	SPEC	TIMEZONE	REPO	NEXT RUNS
+{{- range .Schedules }}
+{{ .Scheduler }}	{{ .Name }}	{{ .Spec }}	{{ .Timezone }}	{{ if .Repository }}{{ .Repository.Owner }}/{{ .Repository.Repo }}{{ else }}-{{ end }}	{{ range $i, $r := .NextRuns }}{{ if $i }}, {{ end }}{{ $r | toRFC3339 }}{{ end -}}
+{{ end }}
+`)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schedulesCmd)
+	schedulesCmd.Flags().Int32("runs", 5, "number of next runs to list per schedule (at most 100)")
+	schedulesCmd.PersistentFlags().StringVarP(&outputFormat, "output-format", "o", "template", "selects the output format: string, json, yaml, template")
+	schedulesCmd.PersistentFlags().StringVar(&outputTemplate, "output-template", "", "template to use in combination with --output-format template")
+}
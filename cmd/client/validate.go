@@ -0,0 +1,102 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate <job.yaml>...",
+	Short: "Validates job files",
+	Long: `Validates job files the same way werft does when it starts a job, i.e. renders them as template and checks the
+result against the job spec schema. Templates are rendered using the metadata of the local Git working copy.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		wd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		md, err := getLocalJobContext(wd, v1.JobTrigger_TRIGGER_MANUAL)
+		if err != nil {
+			log.WithError(err).Debug("cannot get local job context - rendering templates without it")
+			md = &v1.JobMetadata{Owner: "local", Repository: &v1.Repository{Host: "local", Owner: "local", Repo: filepath.Base(wd)}}
+		}
+		annotations, _ := cmd.Flags().GetStringToString("annotations")
+		for k, v := range annotations {
+			md.Annotations = append(md.Annotations, &v1.Annotation{Key: k, Value: v})
+		}
+
+		var invalid bool
+		for _, fn := range args {
+			content, err := ioutil.ReadFile(fn)
+			if err != nil {
+				return err
+			}
+			content, err = resolveLocalFragments(wd, fn, content)
+			if err != nil {
+				fmt.Printf("%s: %v\n", fn, err)
+				invalid = true
+				continue
+			}
+
+			err = werft.ValidateJobSpec("validate", md, content)
+			var verrs repoconfig.ValidationErrors
+			if xerrors.As(err, &verrs) {
+				for _, verr := range verrs {
+					if verr.Field == "" {
+						fmt.Printf("%s:%d: %s\n", fn, verr.Line, verr.Message)
+					} else {
+						fmt.Printf("%s:%d: %s: %s\n", fn, verr.Line, verr.Field, verr.Message)
+					}
+				}
+				invalid = true
+				continue
+			}
+			if err != nil {
+				fmt.Printf("%s: %v\n", fn, err)
+				invalid = true
+				continue
+			}
+			fmt.Printf("%s is valid\n", fn)
+		}
+		if invalid {
+			os.Exit(1)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().StringToStringP("annotations", "a", map[string]string{}, "annotations available to the job template")
+}
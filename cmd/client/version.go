@@ -19,22 +19,140 @@ package cmd
 // THE SOFTWARE.
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"sync"
 
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 var version = "unknown"
 
+// releaseDownloadURL is where the CLI builds of a release are published, see the release package in BUILD.yaml
+const releaseDownloadURL = "https://github.com/32leaves/werft/releases/download/%s/werft-client-%s-%s.tar.gz"
+
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Prints the version of this binary",
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(version)
+	Long: `Prints the version of this binary.
+With --check, the version is compared with the werft server's. If they differ, the command prints where to
+download the client matching the server, and fails if the client cannot talk to the server at all.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		check, _ := cmd.Flags().GetBool("check")
+		if !check {
+			fmt.Println(version)
+			return nil
+		}
+
+		// we report the skew ourselves
+		versionSkewWarning.Do(func() {})
+
+		conn := dial()
+		defer conn.Close()
+		client := v1.NewWerftServiceClient(conn)
+
+		srv, err := client.GetVersion(context.Background(), &v1.GetVersionRequest{})
+		if status.Code(err) == codes.Unimplemented {
+			return xerrors.Errorf("the werft server predates version checks - use a client of the same release as the server")
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("client:\t%s (API %s)\nserver:\t%s (API %s)\n", version, v1.APIVersion, srv.Version, srv.ApiVersion)
+
+		if srv.Version == version && srv.ApiVersion == v1.APIVersion {
+			fmt.Println("\nthis client matches the server")
+			return nil
+		}
+		if url := clientDownloadURL(srv.Version); url != "" {
+			fmt.Printf("\ndownload the client matching the server from %s\n", url)
+		} else {
+			fmt.Println("\nno client build matches the server - build the client from the server's commit " + srv.Commit)
+		}
+		if srv.ApiVersion != v1.APIVersion {
+			return xerrors.Errorf("this client speaks API %s, but the server speaks API %s", v1.APIVersion, srv.ApiVersion)
+		}
+		return nil
 	},
 }
 
+// clientDownloadURL returns the URL of the CLI build of a release for this platform, or an empty string if
+// there is none
+func clientDownloadURL(release string) string {
+	if release == "" || release == "unknown" {
+		return ""
+	}
+	if runtime.GOARCH != "amd64" || (runtime.GOOS != "linux" && runtime.GOOS != "darwin") {
+		return ""
+	}
+	return fmt.Sprintf(releaseDownloadURL, release, runtime.GOOS, runtime.GOARCH)
+}
+
+// versionSkewWarning makes sure we warn about running another version than the server only once
+var versionSkewWarning sync.Once
+
+// withVersion tells the server which version we are, so that it can reject us if we speak another API version
+func withVersion(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, v1.APIVersionMetadataKey, v1.APIVersion, v1.VersionMetadataKey, version)
+}
+
+// warnOnVersionSkew warns if the server runs another release than this client
+func warnOnVersionSkew(header metadata.MD) {
+	vs := header.Get(v1.VersionMetadataKey)
+	if len(vs) == 0 || vs[0] == version || vs[0] == "unknown" || version == "unknown" {
+		return
+	}
+	versionSkewWarning.Do(func() {
+		log.Warnf("this client (%s) differs from the werft server (%s) - run werft version --check to find a matching client", version, vs[0])
+	})
+}
+
+func versionUnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	var header metadata.MD
+	err := invoker(withVersion(ctx), method, req, reply, cc, append(opts, grpc.Header(&header))...)
+	warnOnVersionSkew(header)
+	return err
+}
+
+func versionStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s, err := streamer(withVersion(ctx), desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &versionCheckingStream{ClientStream: s}, nil
+}
+
+// versionCheckingStream checks the version of the server once the server sent its header
+type versionCheckingStream struct {
+	grpc.ClientStream
+
+	once sync.Once
+}
+
+// RecvMsg receives a message from the stream
+func (s *versionCheckingStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	s.once.Do(func() {
+		header, herr := s.Header()
+		if herr == nil {
+			warnOnVersionSkew(header)
+		}
+	})
+	return err
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
+
+	versionCmd.Flags().Bool("check", false, "compare the version with the werft server's")
 }
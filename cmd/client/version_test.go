@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestClientDownloadURL(t *testing.T) {
+	supported := runtime.GOARCH == "amd64" && (runtime.GOOS == "linux" || runtime.GOOS == "darwin")
+	tests := []struct {
+		Release  string
+		Expected string
+	}{
+		{Release: ""},
+		{Release: "unknown"},
+		{Release: "v0.3.0", Expected: "https://github.com/32leaves/werft/releases/download/v0.3.0/werft-client-" + runtime.GOOS + "-amd64.tar.gz"},
+	}
+	for _, test := range tests {
+		t.Run(test.Release, func(t *testing.T) {
+			expected := test.Expected
+			if !supported {
+				expected = ""
+			}
+			if act := clientDownloadURL(test.Release); act != expected {
+				t.Errorf("expected %q, got %q", expected, act)
+			}
+		})
+	}
+}
+
+func TestWithVersion(t *testing.T) {
+	md, ok := metadata.FromOutgoingContext(withVersion(context.Background()))
+	if !ok {
+		t.Fatal("expected outgoing metadata")
+	}
+	if act := strings.Join(md.Get(v1.APIVersionMetadataKey), ","); act != v1.APIVersion {
+		t.Errorf("expected API version %s, got %s", v1.APIVersion, act)
+	}
+	if act := strings.Join(md.Get(v1.VersionMetadataKey), ","); act != version {
+		t.Errorf("expected version %s, got %s", version, act)
+	}
+}
@@ -0,0 +1,165 @@
+package cmd
+
+// Copyright © 2019 Christian Weichel
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// agentCmd represents the agent command
+var agentCmd = &cobra.Command{
+	Use:   "agent <werft-host:grpc-port>",
+	Short: "Runs the jobs of a werft server which uses the agent executor, e.g. in a cluster the server cannot reach",
+	Long: `Runs the jobs of a werft server which uses the agent executor, e.g. in a cluster the server cannot reach.
+The agent connects to the server - it needs no inbound connectivity - and runs the jobs the server sends it,
+either as pods in a Kubernetes namespace or on this host using docker. It relays their logs and status to the
+server and reconnects whenever the connection breaks. Jobs which run when the connection breaks are lost.
+
+The agent token is read from the WERFT_AGENT_TOKEN env var unless --token-file is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			name, _       = cmd.Flags().GetString("name")
+			tokenFile, _  = cmd.Flags().GetString("token-file")
+			runtime, _    = cmd.Flags().GetString("runtime")
+			namespace, _  = cmd.Flags().GetString("namespace")
+			kubeconfig, _ = cmd.Flags().GetString("kubeconfig")
+			useTLS, _     = cmd.Flags().GetBool("tls")
+			hostPaths, _  = cmd.Flags().GetStringSlice("allow-host-path")
+			privileged, _ = cmd.Flags().GetBool("allow-privileged")
+		)
+		if name == "" {
+			name, _ = os.Hostname()
+		}
+		token := os.Getenv("WERFT_AGENT_TOKEN")
+		if tokenFile != "" {
+			fc, err := ioutil.ReadFile(tokenFile)
+			if err != nil {
+				return xerrors.Errorf("cannot read agent token: %w", err)
+			}
+			token = strings.TrimSpace(string(fc))
+		}
+		if token == "" {
+			return xerrors.Errorf("agent token is required")
+		}
+
+		var agent *executor.Agent
+		switch runtime {
+		case executor.BackendKubernetes:
+			var (
+				kubeCfg *rest.Config
+				err     error
+			)
+			if kubeconfig != "" {
+				kubeCfg, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+			} else {
+				kubeCfg, err = rest.InClusterConfig()
+			}
+			if err != nil {
+				return xerrors.Errorf("cannot get kubeconfig: %w", err)
+			}
+			clientset, err := kubernetes.NewForConfig(kubeCfg)
+			if err != nil {
+				return err
+			}
+			agent = executor.NewAgent(name, token, clientset, namespace)
+		case executor.BackendDocker:
+			agent = executor.NewDockerAgent(name, token, &executor.DockerConfig{
+				AllowedHostPaths: hostPaths,
+				AllowPrivileged:  privileged,
+			})
+		default:
+			return xerrors.Errorf("unknown runtime %s: must be %s or %s", runtime, executor.BackendKubernetes, executor.BackendDocker)
+		}
+
+		creds := grpc.WithInsecure()
+		if useTLS {
+			creds = grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{}))
+		}
+		conn, err := grpc.Dial(args[0], creds,
+			grpc.WithStreamInterceptor(agentVersionStreamInterceptor),
+			grpc.WithKeepaliveParams(keepalive.ClientParameters{
+				Time:                30 * time.Second,
+				Timeout:             10 * time.Second,
+				PermitWithoutStream: true,
+			}),
+		)
+		if err != nil {
+			return xerrors.Errorf("cannot connect to werft: %w", err)
+		}
+		defer conn.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			log.Info("Received SIGINT - shutting down")
+			cancel()
+		}()
+
+		log.WithField("name", name).WithField("runtime", runtime).WithField("werft", args[0]).Info("agent is up and running. Stop with SIGINT or CTRL+C")
+		err = agent.Serve(ctx, v1.NewAgentServiceClient(conn))
+		if err == context.Canceled {
+			return nil
+		}
+		return err
+	},
+}
+
+// agentVersionStreamInterceptor tells the werft server which API version the agent speaks
+func agentVersionStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, v1.APIVersionMetadataKey, v1.APIVersion, v1.VersionMetadataKey, version)
+	return streamer(ctx, desc, cc, method, opts...)
+}
+
+func init() {
+	rootCmd.AddCommand(agentCmd)
+
+	agentCmd.Flags().String("name", "", "name of the agent, e.g. the cluster it runs in (defaults to the hostname)")
+	agentCmd.Flags().String("token-file", "", "file containing the agent token")
+	agentCmd.Flags().String("runtime", executor.BackendKubernetes, "where jobs run: kubernetes or docker")
+	agentCmd.Flags().String("namespace", "default", "Kubernetes namespace jobs run in - the agent deletes all job pods it finds there when it connects")
+	agentCmd.Flags().String("kubeconfig", "", "kubeconfig to use instead of the in-cluster config")
+	agentCmd.Flags().Bool("tls", false, "connect to werft using TLS")
+	agentCmd.Flags().StringSlice("allow-host-path", nil, "host path jobs may mount when using the docker runtime (may be given more than once)")
+	agentCmd.Flags().Bool("allow-privileged", false, "let jobs run privileged containers when using the docker runtime")
+}
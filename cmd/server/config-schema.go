@@ -0,0 +1,76 @@
+package cmd
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/32leaves/werft/pkg/configschema"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// configSchema is the JSON schema of the werft config file
+func configSchema() *configschema.Schema {
+	return configschema.Generate(Config{})
+}
+
+// validateConfig checks a config file against the config schema and logs every problem it finds
+func validateConfig(fn string, fc []byte) error {
+	errs, err := configschema.Validate(configSchema(), fc)
+	if err != nil {
+		return xerrors.Errorf("invalid config file %s: %w", fn, err)
+	}
+	for _, e := range errs {
+		log.WithField("path", e.Path).WithField("line", e.Line).Error(e.Message)
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return xerrors.Errorf("invalid config file %s: %w", fn, errs[0])
+	default:
+		return xerrors.Errorf("invalid config file %s: %w (and %d more problems)", fn, errs[0], len(errs)-1)
+	}
+}
+
+// handleConfigSchema serves the config schema, e.g. for deployment tooling to validate werft config before applying it
+func handleConfigSchema(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	json.NewEncoder(w).Encode(configSchema())
+}
+
+// configSchemaCmd represents the config-schema command
+var configSchemaCmd = &cobra.Command{
+	Use:   "config-schema",
+	Short: "Prints the JSON schema of the config file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(configSchema())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configSchemaCmd)
+}
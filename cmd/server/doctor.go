@@ -0,0 +1,351 @@
+package cmd
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// doctorStatus is the outcome of a doctor check
+type doctorStatus string
+
+const (
+	doctorPass doctorStatus = "PASS"
+	// doctorWarn marks problems which only affect some features, or checks which could not be made
+	doctorWarn doctorStatus = "WARN"
+	doctorFail doctorStatus = "FAIL"
+)
+
+// doctorResult is the outcome of a single check
+type doctorResult struct {
+	Status  doctorStatus
+	Check   string
+	Details string
+}
+
+// doctorPermission is a permission werft needs in its namespace
+type doctorPermission struct {
+	Group       string
+	Resource    string
+	Subresource string
+	Verbs       []string
+	// Feature names what doesn't work without the permission. Permissions without feature are required.
+	Feature string
+}
+
+// doctorPermissions are the permissions werft uses in its namespace
+var doctorPermissions = []doctorPermission{
+	{Resource: "pods", Verbs: []string{"create", "delete", "get", "list", "update", "watch"}},
+	{Resource: "pods", Subresource: "exec", Verbs: []string{"create"}},
+	{Resource: "secrets", Verbs: []string{"get"}},
+	{Resource: "events", Verbs: []string{"list"}, Feature: "failure diagnostics"},
+	{Resource: "resourcequotas", Verbs: []string{"list"}, Feature: "capacity checks"},
+	// werft keeps the maintenance mode in a config map
+	{Resource: "configmaps", Verbs: []string{"get", "create", "update", "delete"}},
+	{Group: "networking.k8s.io", Resource: "networkpolicies", Verbs: []string{"create", "delete", "get", "update"}, Feature: "egress rules"},
+	{Group: "metrics.k8s.io", Resource: "pods", Verbs: []string{"list"}, Feature: "resource usage of jobs"},
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor <config.yaml>",
+	Short: "Checks that the cluster and network are ready for werft",
+	Long: `Checks that the cluster and network are ready for werft: the permissions werft needs in its namespace,
+resource quotas and their defaults, the volume of the checkout cache, and access to GitHub.
+Permissions are checked for whoever runs this command, hence run it in the werft pod to check werft's service account.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var cfg Config
+		err := readConfig(args[0], &cfg)
+		if err != nil {
+			return err
+		}
+		namespace := cfg.Executor.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+
+		var res []doctorResult
+		kubeConfig, err := getKubeConfig(cfg, true)
+		if err == nil {
+			var client *kubernetes.Clientset
+			client, err = kubernetes.NewForConfig(kubeConfig)
+			if err == nil {
+				res = append(res, checkPermissions(client, namespace)...)
+				res = append(res, checkResourceQuotas(client, namespace)...)
+				res = append(res, checkCheckoutCache(client, namespace, cfg)...)
+			}
+		}
+		if err != nil {
+			res = append(res, doctorResult{doctorFail, "kubernetes", fmt.Sprintf("cannot connect: %v", err)})
+		}
+		res = append(res, checkGitHub(cfg)...)
+
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "STATUS\tCHECK\tDETAILS")
+		var failed int
+		for _, r := range res {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Status, r.Check, r.Details)
+			if r.Status == doctorFail {
+				failed++
+			}
+		}
+		tw.Flush()
+
+		if failed > 0 {
+			return xerrors.Errorf("%d of %d checks failed", failed, len(res))
+		}
+		return nil
+	},
+}
+
+// checkPermissions asks Kubernetes if we have the permissions werft needs in the namespace
+func checkPermissions(client kubernetes.Interface, namespace string) []doctorResult {
+	var res []doctorResult
+	for _, p := range doctorPermissions {
+		resource := p.Resource
+		if p.Subresource != "" {
+			resource += "/" + p.Subresource
+		}
+		if p.Group != "" {
+			resource += "." + p.Group
+		}
+		check := fmt.Sprintf("rbac: %s", resource)
+
+		var missing []string
+		var err error
+		for _, verb := range p.Verbs {
+			var review *authv1.SelfSubjectAccessReview
+			review, err = client.AuthorizationV1().SelfSubjectAccessReviews().Create(&authv1.SelfSubjectAccessReview{
+				Spec: authv1.SelfSubjectAccessReviewSpec{
+					ResourceAttributes: &authv1.ResourceAttributes{
+						Namespace:   namespace,
+						Verb:        verb,
+						Group:       p.Group,
+						Resource:    p.Resource,
+						Subresource: p.Subresource,
+					},
+				},
+			})
+			if err != nil {
+				break
+			}
+			if !review.Status.Allowed {
+				missing = append(missing, verb)
+			}
+		}
+
+		switch {
+		case err != nil:
+			res = append(res, doctorResult{doctorWarn, check, fmt.Sprintf("cannot check permissions: %v", err)})
+		case len(missing) == 0:
+			res = append(res, doctorResult{doctorPass, check, strings.Join(p.Verbs, ", ")})
+		case p.Feature != "":
+			res = append(res, doctorResult{doctorWarn, check, fmt.Sprintf("missing %s - %s won't work", strings.Join(missing, ", "), p.Feature)})
+		default:
+			res = append(res, doctorResult{doctorFail, check, fmt.Sprintf("missing %s", strings.Join(missing, ", "))})
+		}
+	}
+	return res
+}
+
+// checkResourceQuotas makes sure that job pods which don't specify resources aren't rejected by resource quotas.
+// Kubernetes rejects such pods if a quota constrains a resource no limit range has a default for.
+func checkResourceQuotas(client kubernetes.Interface, namespace string) []doctorResult {
+	quotas, err := client.CoreV1().ResourceQuotas(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return []doctorResult{{doctorWarn, "resource quotas", fmt.Sprintf("cannot list resource quotas: %v", err)}}
+	}
+	if len(quotas.Items) == 0 {
+		return []doctorResult{{doctorPass, "resource quotas", "no resource quotas - jobs are not limited"}}
+	}
+
+	limitRanges, err := client.CoreV1().LimitRanges(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return []doctorResult{{doctorWarn, "resource quotas", fmt.Sprintf("cannot list limit ranges: %v", err)}}
+	}
+	defaults := make(map[corev1.ResourceName]bool)
+	for _, lr := range limitRanges.Items {
+		for _, l := range lr.Spec.Limits {
+			if l.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			for r := range l.DefaultRequest {
+				defaults[corev1.ResourceName("requests."+r)] = true
+			}
+			for r := range l.Default {
+				defaults[corev1.ResourceName("limits."+r)] = true
+				// limits default the requests, too
+				defaults[corev1.ResourceName("requests."+r)] = true
+			}
+		}
+	}
+
+	var res []doctorResult
+	for _, q := range quotas.Items {
+		check := fmt.Sprintf("resource quota %s", q.Name)
+
+		var undefaulted []string
+		for r := range q.Spec.Hard {
+			name := r
+			switch r {
+			case corev1.ResourceCPU, corev1.ResourceMemory:
+				// cpu and memory constrain the requests
+				name = corev1.ResourceName("requests." + r)
+			}
+			if !strings.HasPrefix(string(name), "requests.") && !strings.HasPrefix(string(name), "limits.") {
+				continue
+			}
+			if !defaults[name] {
+				undefaulted = append(undefaulted, string(name))
+			}
+		}
+		sort.Strings(undefaulted)
+
+		if len(undefaulted) > 0 {
+			res = append(res, doctorResult{doctorFail, check, fmt.Sprintf("no limit range sets defaults for %s - job pods without resources will be rejected", strings.Join(undefaulted, ", "))})
+			continue
+		}
+		res = append(res, doctorResult{doctorPass, check, "limit ranges set defaults for all constrained resources"})
+	}
+	return res
+}
+
+// checkCheckoutCache makes sure that the volume claim of the checkout cache exists and can be bound
+func checkCheckoutCache(client kubernetes.Interface, namespace string, cfg Config) []doctorResult {
+	const check = "checkout cache"
+
+	cc := cfg.Werft.CheckoutCache
+	if cc == nil || cc.ClaimName == "" {
+		return []doctorResult{{doctorPass, check, "no checkout cache configured"}}
+	}
+
+	pvc, err := client.CoreV1().PersistentVolumeClaims(namespace).Get(cc.ClaimName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return []doctorResult{{doctorFail, check, fmt.Sprintf("persistent volume claim %s does not exist", cc.ClaimName)}}
+	}
+	if err != nil {
+		return []doctorResult{{doctorWarn, check, fmt.Sprintf("cannot get persistent volume claim %s: %v", cc.ClaimName, err)}}
+	}
+
+	var res []doctorResult
+	if pvc.Status.Phase != corev1.ClaimBound {
+		scName := pvc.Spec.StorageClassName
+		if scName == nil {
+			res = append(res, checkDefaultStorageClass(client, cc.ClaimName))
+		} else if *scName != "" {
+			_, err := client.StorageV1().StorageClasses().Get(*scName, metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				res = append(res, doctorResult{doctorFail, check, fmt.Sprintf("storage class %s of %s does not exist", *scName, cc.ClaimName)})
+			} else if err != nil {
+				res = append(res, doctorResult{doctorWarn, check, fmt.Sprintf("cannot get storage class %s: %v", *scName, err)})
+			}
+		}
+		if len(res) == 0 {
+			res = append(res, doctorResult{doctorWarn, check, fmt.Sprintf("%s is %s and not bound yet", cc.ClaimName, pvc.Status.Phase)})
+		}
+	}
+
+	var rwx bool
+	for _, m := range pvc.Spec.AccessModes {
+		if m == corev1.ReadWriteMany {
+			rwx = true
+		}
+	}
+	if !rwx {
+		res = append(res, doctorResult{doctorWarn, check, fmt.Sprintf("%s is not ReadWriteMany - jobs on different nodes cannot share the cache", cc.ClaimName)})
+	}
+
+	if len(res) == 0 {
+		res = append(res, doctorResult{doctorPass, check, fmt.Sprintf("%s is bound", cc.ClaimName)})
+	}
+	return res
+}
+
+// checkDefaultStorageClass makes sure that there is a default storage class for claims which don't name one
+func checkDefaultStorageClass(client kubernetes.Interface, claim string) doctorResult {
+	const check = "checkout cache"
+
+	classes, err := client.StorageV1().StorageClasses().List(metav1.ListOptions{})
+	if err != nil {
+		return doctorResult{doctorWarn, check, fmt.Sprintf("cannot list storage classes: %v", err)}
+	}
+	for _, sc := range classes.Items {
+		if sc.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			return doctorResult{doctorWarn, check, fmt.Sprintf("%s is not bound yet, it will use the default storage class %s", claim, sc.Name)}
+		}
+	}
+	return doctorResult{doctorFail, check, fmt.Sprintf("%s names no storage class and there is no default storage class", claim)}
+}
+
+// checkGitHub makes sure that we can reach GitHub, through the proxy if there is one, and authenticate as GitHub app
+func checkGitHub(cfg Config) []doctorResult {
+	transport, err := cfg.Proxy.Transport()
+	if err != nil {
+		return []doctorResult{{doctorFail, "github: network", err.Error()}}
+	}
+	client := &http.Client{Transport: transport, Timeout: 10 * time.Second}
+
+	var res []doctorResult
+	resp, err := client.Get("https://api.github.com")
+	if err != nil {
+		return []doctorResult{{doctorFail, "github: network", fmt.Sprintf("cannot reach api.github.com: %v", err)}}
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		res = append(res, doctorResult{doctorWarn, "github: network", fmt.Sprintf("api.github.com responded with %s", resp.Status)})
+	} else {
+		res = append(res, doctorResult{doctorPass, "github: network", "api.github.com is reachable"})
+	}
+
+	if cfg.GitHub.PrivateKeyPath == "" {
+		return append(res, doctorResult{doctorWarn, "github: app", "no GitHub app configured - only public repositories are accessible"})
+	}
+	tr, err := ghinstallation.NewKeyFromFile(transport, cfg.GitHub.AppID, cfg.GitHub.InstallationID, cfg.GitHub.PrivateKeyPath)
+	if err != nil {
+		return append(res, doctorResult{doctorFail, "github: app", err.Error()})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = tr.Token(ctx)
+	if err != nil {
+		return append(res, doctorResult{doctorFail, "github: app", fmt.Sprintf("cannot authenticate as installation %d of app %d: %v", cfg.GitHub.InstallationID, cfg.GitHub.AppID, err)})
+	}
+	return append(res, doctorResult{doctorPass, "github: app", fmt.Sprintf("authenticated as installation %d of app %d", cfg.GitHub.InstallationID, cfg.GitHub.AppID)})
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/werft"
+	authv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func formatDoctorResults(res []doctorResult) string {
+	var lines []string
+	for _, r := range res {
+		lines = append(lines, fmt.Sprintf("%s %s: %s", r.Status, r.Check, r.Details))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestCheckPermissions(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Denied   map[string]bool
+		Error    bool
+		Expected map[string]string
+	}{
+		{
+			Name: "all allowed",
+			Expected: map[string]string{
+				"rbac: pods":      "PASS rbac: pods: create, delete, get, list, update, watch",
+				"rbac: pods/exec": "PASS rbac: pods/exec: create",
+			},
+		},
+		{
+			Name:   "required permission missing",
+			Denied: map[string]bool{"pods/delete": true, "pods/watch": true},
+			Expected: map[string]string{
+				"rbac: pods": "FAIL rbac: pods: missing delete, watch",
+			},
+		},
+		{
+			Name:   "optional permission missing",
+			Denied: map[string]bool{"resourcequotas/list": true},
+			Expected: map[string]string{
+				"rbac: resourcequotas": "WARN rbac: resourcequotas: missing list - capacity checks won't work",
+				"rbac: pods":           "PASS rbac: pods: create, delete, get, list, update, watch",
+			},
+		},
+		{
+			Name:  "cannot check",
+			Error: true,
+			Expected: map[string]string{
+				"rbac: secrets": "WARN rbac: secrets: cannot check permissions: forbidden",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			client.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+				if test.Error {
+					return true, &authv1.SelfSubjectAccessReview{}, fmt.Errorf("forbidden")
+				}
+				review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+				attrs := review.Spec.ResourceAttributes
+				if attrs.Namespace != "werft" {
+					t.Errorf("expected permissions to be checked in the werft namespace, got %q", attrs.Namespace)
+				}
+				review.Status.Allowed = !test.Denied[attrs.Resource+"/"+attrs.Verb]
+				return true, review, nil
+			})
+
+			res := checkPermissions(client, "werft")
+			if len(res) != len(doctorPermissions) {
+				t.Errorf("expected one result per permission, got %s", formatDoctorResults(res))
+			}
+			for _, r := range res {
+				exp, ok := test.Expected[r.Check]
+				if !ok {
+					continue
+				}
+				if act := formatDoctorResults([]doctorResult{r}); act != exp {
+					t.Errorf("expected %q, got %q", exp, act)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckResourceQuotas(t *testing.T) {
+	quota := func(resources ...corev1.ResourceName) *corev1.ResourceQuota {
+		q := &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "jobs", Namespace: "werft"},
+			Spec:       corev1.ResourceQuotaSpec{Hard: make(corev1.ResourceList)},
+		}
+		for _, r := range resources {
+			q.Spec.Hard[r] = resource.MustParse("1")
+		}
+		return q
+	}
+	limitRange := func(tpe corev1.LimitType, defaults, defaultRequests corev1.ResourceList) *corev1.LimitRange {
+		return &corev1.LimitRange{
+			ObjectMeta: metav1.ObjectMeta{Name: "defaults", Namespace: "werft"},
+			Spec: corev1.LimitRangeSpec{Limits: []corev1.LimitRangeItem{{
+				Type:           tpe,
+				Default:        defaults,
+				DefaultRequest: defaultRequests,
+			}}},
+		}
+	}
+	cpu := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+	mem := corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+
+	tests := []struct {
+		Name     string
+		Objects  []runtime.Object
+		Expected string
+	}{
+		{Name: "no quotas", Expected: "PASS resource quotas: no resource quotas - jobs are not limited"},
+		{
+			Name:     "object count quotas only",
+			Objects:  []runtime.Object{quota(corev1.ResourcePods)},
+			Expected: "PASS resource quota jobs: limit ranges set defaults for all constrained resources",
+		},
+		{
+			Name:     "no defaults",
+			Objects:  []runtime.Object{quota(corev1.ResourceCPU, corev1.ResourceLimitsMemory)},
+			Expected: "FAIL resource quota jobs: no limit range sets defaults for limits.memory, requests.cpu - job pods without resources will be rejected",
+		},
+		{
+			Name:     "default requests",
+			Objects:  []runtime.Object{quota(corev1.ResourceCPU), limitRange(corev1.LimitTypeContainer, nil, cpu)},
+			Expected: "PASS resource quota jobs: limit ranges set defaults for all constrained resources",
+		},
+		{
+			Name:     "default limits default the requests",
+			Objects:  []runtime.Object{quota(corev1.ResourceRequestsMemory, corev1.ResourceLimitsMemory), limitRange(corev1.LimitTypeContainer, mem, nil)},
+			Expected: "PASS resource quota jobs: limit ranges set defaults for all constrained resources",
+		},
+		{
+			Name:     "default requests don't default limits",
+			Objects:  []runtime.Object{quota(corev1.ResourceLimitsCPU), limitRange(corev1.LimitTypeContainer, nil, cpu)},
+			Expected: "FAIL resource quota jobs: no limit range sets defaults for limits.cpu - job pods without resources will be rejected",
+		},
+		{
+			Name:     "pod limits don't count",
+			Objects:  []runtime.Object{quota(corev1.ResourceCPU), limitRange(corev1.LimitTypePod, cpu, cpu)},
+			Expected: "FAIL resource quota jobs: no limit range sets defaults for requests.cpu - job pods without resources will be rejected",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.Objects...)
+			if act := formatDoctorResults(checkResourceQuotas(client, "werft")); act != test.Expected {
+				t.Errorf("expected %q, got %q", test.Expected, act)
+			}
+		})
+	}
+}
+
+func TestCheckCheckoutCache(t *testing.T) {
+	claim := func(phase corev1.PersistentVolumeClaimPhase, storageClass *string, modes ...corev1.PersistentVolumeAccessMode) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "werft"},
+			Spec:       corev1.PersistentVolumeClaimSpec{StorageClassName: storageClass, AccessModes: modes},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	storageClass := func(name string, isDefault bool) *storagev1.StorageClass {
+		sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if isDefault {
+			sc.Annotations = map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}
+		}
+		return sc
+	}
+	nfs := "nfs"
+	none := ""
+
+	tests := []struct {
+		Name     string
+		Claim    string
+		Objects  []runtime.Object
+		Expected string
+	}{
+		{Name: "no cache", Expected: "PASS checkout cache: no checkout cache configured"},
+		{Name: "missing claim", Claim: "cache", Expected: "FAIL checkout cache: persistent volume claim cache does not exist"},
+		{
+			Name:     "bound",
+			Claim:    "cache",
+			Objects:  []runtime.Object{claim(corev1.ClaimBound, &nfs, corev1.ReadWriteMany)},
+			Expected: "PASS checkout cache: cache is bound",
+		},
+		{
+			Name:     "not ReadWriteMany",
+			Claim:    "cache",
+			Objects:  []runtime.Object{claim(corev1.ClaimBound, &nfs, corev1.ReadWriteOnce)},
+			Expected: "WARN checkout cache: cache is not ReadWriteMany - jobs on different nodes cannot share the cache",
+		},
+		{
+			Name:     "missing storage class",
+			Claim:    "cache",
+			Objects:  []runtime.Object{claim(corev1.ClaimPending, &nfs, corev1.ReadWriteMany)},
+			Expected: "FAIL checkout cache: storage class nfs of cache does not exist",
+		},
+		{
+			Name:     "pending",
+			Claim:    "cache",
+			Objects:  []runtime.Object{claim(corev1.ClaimPending, &nfs, corev1.ReadWriteMany), storageClass("nfs", false)},
+			Expected: "WARN checkout cache: cache is Pending and not bound yet",
+		},
+		{
+			Name:     "statically provisioned",
+			Claim:    "cache",
+			Objects:  []runtime.Object{claim(corev1.ClaimPending, &none, corev1.ReadWriteMany)},
+			Expected: "WARN checkout cache: cache is Pending and not bound yet",
+		},
+		{
+			Name:     "default storage class",
+			Claim:    "cache",
+			Objects:  []runtime.Object{claim(corev1.ClaimPending, nil, corev1.ReadWriteMany), storageClass("standard", false), storageClass("nfs", true)},
+			Expected: "WARN checkout cache: cache is not bound yet, it will use the default storage class nfs",
+		},
+		{
+			Name:     "no default storage class",
+			Claim:    "cache",
+			Objects:  []runtime.Object{claim(corev1.ClaimPending, nil, corev1.ReadWriteMany), storageClass("standard", false)},
+			Expected: "FAIL checkout cache: cache names no storage class and there is no default storage class",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var cfg Config
+			if test.Claim != "" {
+				cfg.Werft.CheckoutCache = &werft.CheckoutCacheConfig{ClaimName: test.Claim}
+			}
+			client := fake.NewSimpleClientset(test.Objects...)
+			if act := formatDoctorResults(checkCheckoutCache(client, "werft", cfg)); act != test.Expected {
+				t.Errorf("expected %q, got %q", test.Expected, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package cmd
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// loadgenJobYAML is the job every synthetic job runs. The fake executor doesn't run its containers.
+const loadgenJobYAML = `pod:
+  containers:
+  - name: build
+    image: alpine:latest
+    command: ["true"]
+`
+
+// loadgenCmd represents the loadgen command
+var loadgenCmd = &cobra.Command{
+	Use:   "loadgen [config.yaml]",
+	Short: "Puts werft under load using synthetic jobs",
+	Long: `Starts werft with the fake executor and puts it under load using synthetic jobs, e.g. to validate
+performance changes before they are rolled out. Jobs start at the given rate and log synthetic output while
+API clients list jobs and follow their logs. Once all jobs are done, loadgen reports their outcome and the
+latencies it measured.
+
+Without a config file all jobs and logs are kept in memory. With a config file loadgen uses the storage it
+configures - make sure it's not the one of a production installation. The executor it configures is replaced
+by the fake one either way.`,
+	Hidden: true,
+	Args:   cobra.RangeArgs(0, 1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		err := configureLogging(cmd)
+		if err != nil {
+			return err
+		}
+
+		var opts loadgenOptions
+		opts.Rate, _ = cmd.Flags().GetFloat64("rate")
+		opts.Duration, _ = cmd.Flags().GetDuration("duration")
+		opts.Readers, _ = cmd.Flags().GetInt("readers")
+		opts.ReadInterval, _ = cmd.Flags().GetDuration("read-interval")
+		opts.Follow, _ = cmd.Flags().GetFloat64("follow")
+		opts.DrainTimeout, _ = cmd.Flags().GetDuration("drain-timeout")
+		if opts.Rate <= 0 {
+			return xerrors.Errorf("--rate must be positive")
+		}
+		if opts.Follow < 0 || opts.Follow > 1 {
+			return xerrors.Errorf("--follow must be between 0 and 1")
+		}
+
+		cfg := demoConfig()
+		if len(args) > 0 {
+			err = readConfig(args[0], &cfg)
+			if err != nil {
+				return err
+			}
+		} else {
+			cfg.Storage.InMemory = true
+		}
+		jobDuration, _ := cmd.Flags().GetDuration("job-duration")
+		logLines, _ := cmd.Flags().GetInt("log-lines")
+		lineLength, _ := cmd.Flags().GetInt("line-length")
+		failureRate, _ := cmd.Flags().GetFloat64("failure-rate")
+		cfg.Executor.Backend = executor.BackendFake
+		cfg.Executor.Fake = &executor.FakeConfig{
+			Duration:    &executor.Duration{Duration: jobDuration},
+			LogLines:    logLines,
+			LineLength:  lineLength,
+			FailureRate: failureRate,
+		}
+
+		transport, err := cfg.Proxy.Transport()
+		if err != nil {
+			return err
+		}
+		stores, err := setupStorage(cfg, transport)
+		if err != nil {
+			return err
+		}
+		defer stores.Close()
+		exec, err := executor.NewFakeExecutor(cfg.Executor)
+		if err != nil {
+			return err
+		}
+
+		srv, err := werft.New(
+			werft.WithConfig(cfg.Werft),
+			werft.WithExecutor(exec),
+			werft.WithLogStore(stores.Logs),
+			werft.WithJobStore(stores.Jobs),
+			werft.WithNumberGroup(stores.Groups),
+			werft.WithArchive(stores.Archive),
+			werft.WithDeadLetters(stores.DeadLetters),
+			werft.WithImages(stores.Images),
+			werft.WithGitHub(werft.GitHubSetup{Client: github.NewClient(nil)}),
+			werft.WithVersion(getVersionInfo().proto()),
+		)
+		if err != nil {
+			return err
+		}
+		err = srv.Start(context.Background())
+		if err != nil {
+			return err
+		}
+		defer srv.Stop(context.Background())
+
+		// clients use the API like they would use a werft server
+		lis, err := net.Listen("tcp", "localhost:0")
+		if err != nil {
+			return err
+		}
+		grpcServer := grpc.NewServer()
+		v1.RegisterWerftServiceServer(grpcServer, srv)
+		go grpcServer.Serve(lis)
+		defer grpcServer.Stop()
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		res := runLoad(srv, exec, v1.NewWerftServiceClient(conn), opts)
+		res.Print(os.Stdout)
+		return nil
+	},
+}
+
+// loadgenOptions configures the load loadgen puts werft under
+type loadgenOptions struct {
+	Rate         float64
+	Duration     time.Duration
+	Readers      int
+	ReadInterval time.Duration
+	Follow       float64
+	DrainTimeout time.Duration
+}
+
+// loadgenResult is what loadgen observed
+type loadgenResult struct {
+	Jobs      []string
+	Outcomes  map[string]int
+	Errors    map[string]int
+	LogBytes  int64
+	Latencies map[string]*latencies
+	Took      time.Duration
+}
+
+// Print writes the result as table
+func (r *loadgenResult) Print(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "jobs started\t%d in %s\n", len(r.Jobs), r.Took.Round(time.Millisecond))
+	outcomes := make([]string, 0, len(r.Outcomes))
+	for o := range r.Outcomes {
+		outcomes = append(outcomes, o)
+	}
+	sort.Strings(outcomes)
+	for _, o := range outcomes {
+		fmt.Fprintf(w, "jobs %s\t%d\n", o, r.Outcomes[o])
+	}
+	fmt.Fprintf(w, "log bytes followed\t%d\n", r.LogBytes)
+	for _, op := range []string{"start", "list", "get", "listen"} {
+		if n := r.Errors[op]; n > 0 {
+			fmt.Fprintf(w, "%s errors\t%d\n", op, n)
+		}
+	}
+
+	fmt.Fprintln(w, "\nOPERATION\tCOUNT\tP50\tP95\tP99\tMAX")
+	for _, op := range []string{"start", "list", "get", "listen first byte"} {
+		l, ok := r.Latencies[op]
+		if !ok || len(l.vals) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", op, len(l.vals), l.Percentile(50), l.Percentile(95), l.Percentile(99), l.Percentile(100))
+	}
+}
+
+// latencies collects the durations of an operation
+type latencies struct {
+	vals []time.Duration
+	mu   sync.Mutex
+}
+
+func (l *latencies) Add(d time.Duration) {
+	l.mu.Lock()
+	l.vals = append(l.vals, d)
+	l.mu.Unlock()
+}
+
+// Percentile returns the p-th percentile of the durations
+func (l *latencies) Percentile(p int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.vals) == 0 {
+		return 0
+	}
+	sort.Slice(l.vals, func(i, j int) bool { return l.vals[i] < l.vals[j] })
+	idx := (len(l.vals)*p+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return l.vals[idx].Round(time.Microsecond)
+}
+
+// loadgenContent provides no content: the fake executor doesn't run the checkout
+type loadgenContent struct{}
+
+// InitContainer returns a container which does nothing
+func (loadgenContent) InitContainer() (*corev1.Container, error) {
+	return &corev1.Container{
+		Image:   "alpine:latest",
+		Command: []string{"true"},
+	}, nil
+}
+
+// Serve does nothing
+func (loadgenContent) Serve(jobName string) error {
+	return nil
+}
+
+// runLoad starts jobs at the configured rate while readers use the API, and waits for the jobs to finish
+func runLoad(srv *werft.Service, exec executor.Backend, client v1.WerftServiceClient, opts loadgenOptions) *loadgenResult {
+	var (
+		res = &loadgenResult{
+			Outcomes: make(map[string]int),
+			Errors:   make(map[string]int),
+			Latencies: map[string]*latencies{
+				"start":             {},
+				"list":              {},
+				"get":               {},
+				"listen first byte": {},
+			},
+		}
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		done    = make(chan struct{})
+		started = time.Now()
+	)
+	countError := func(op string, err error) {
+		log.WithError(err).WithField("operation", op).Debug("loadgen operation failed")
+		mu.Lock()
+		res.Errors[op]++
+		mu.Unlock()
+	}
+
+	for i := 0; i < opts.Readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t := time.NewTicker(opts.ReadInterval)
+			defer t.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-t.C:
+				}
+
+				start := time.Now()
+				_, err := client.ListJobs(context.Background(), &v1.ListJobsRequest{Limit: 50, View: v1.JobView_JOB_VIEW_SUMMARY})
+				if err != nil {
+					countError("list", err)
+					continue
+				}
+				res.Latencies["list"].Add(time.Since(start))
+			}
+		}()
+	}
+
+	follow := func(name string) {
+		defer wg.Done()
+		start := time.Now()
+		lst, err := client.Listen(context.Background(), &v1.ListenRequest{Name: name, Logs: v1.ListenRequestLogs_LOGS_RAW})
+		if err != nil {
+			countError("listen", err)
+			return
+		}
+		var first bool
+		for {
+			msg, err := lst.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				countError("listen", err)
+				return
+			}
+			if slice := msg.GetSlice(); slice != nil {
+				if !first {
+					first = true
+					res.Latencies["listen first byte"].Add(time.Since(start))
+				}
+				mu.Lock()
+				res.LogBytes += int64(len(slice.Payload))
+				mu.Unlock()
+			}
+		}
+	}
+
+	md := v1.JobMetadata{
+		Owner: "loadgen",
+		Repository: &v1.Repository{
+			Host:  "loadgen",
+			Owner: "werft",
+			Repo:  "loadgen",
+			Ref:   "refs/heads/main",
+		},
+		Trigger: v1.JobTrigger_TRIGGER_MANUAL,
+	}
+	t := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+	for time.Since(started) < opts.Duration {
+		<-t.C
+
+		nr, err := srv.Groups.Next("loadgen")
+		if err != nil {
+			countError("start", err)
+			continue
+		}
+		name := fmt.Sprintf("loadgen.%d", nr)
+		start := time.Now()
+		status, err := srv.RunJob(context.Background(), name, md, loadgenContent{}, []byte(loadgenJobYAML), false, time.Time{})
+		if err != nil {
+			countError("start", err)
+			continue
+		}
+		res.Latencies["start"].Add(time.Since(start))
+		res.Jobs = append(res.Jobs, status.Name)
+
+		if rand.Float64() < opts.Follow {
+			wg.Add(1)
+			go follow(status.Name)
+		}
+	}
+	t.Stop()
+
+	// wait for the jobs to finish
+	drained := time.Now().Add(opts.DrainTimeout)
+	for time.Now().Before(drained) {
+		known, err := exec.GetKnownJobs()
+		if err == nil && len(known) == 0 {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	close(done)
+	wg.Wait()
+	res.Took = time.Since(started)
+
+	for _, name := range res.Jobs {
+		start := time.Now()
+		resp, err := client.GetJob(context.Background(), &v1.GetJobRequest{Name: name})
+		if err != nil {
+			countError("get", err)
+			continue
+		}
+		res.Latencies["get"].Add(time.Since(start))
+
+		outcome := "running"
+		if job := resp.Result; job.Phase == v1.JobPhase_PHASE_DONE {
+			outcome = "failed"
+			if job.Conditions != nil && job.Conditions.Success {
+				outcome = "succeeded"
+			}
+		}
+		res.Outcomes[outcome]++
+	}
+	return res
+}
+
+func init() {
+	rootCmd.AddCommand(loadgenCmd)
+
+	loadgenCmd.Flags().Float64("rate", 1, "jobs started per second")
+	loadgenCmd.Flags().Duration("duration", time.Minute, "how long to start jobs for")
+	loadgenCmd.Flags().Duration("job-duration", 10*time.Second, "how long every job runs")
+	loadgenCmd.Flags().Int("log-lines", 100, "number of lines every job logs")
+	loadgenCmd.Flags().Int("line-length", 80, "length of the lines jobs log")
+	loadgenCmd.Flags().Float64("failure-rate", 0, "share of jobs which fail, between 0 and 1")
+	loadgenCmd.Flags().Int("readers", 2, "number of API clients which list jobs concurrently")
+	loadgenCmd.Flags().Duration("read-interval", time.Second, "how often every reader lists jobs")
+	loadgenCmd.Flags().Float64("follow", 0.1, "share of jobs whose logs a client follows, between 0 and 1")
+	loadgenCmd.Flags().Duration("drain-timeout", 5*time.Minute, "how long to wait for jobs to finish once no more jobs start")
+	loadgenCmd.Flags().Bool("verbose", false, "enable verbose debug output (same as --log-level debug)")
+	loadgenCmd.Flags().String("log-level", "warn", "log level: one of panic, fatal, error, warn, info, debug, trace")
+	loadgenCmd.Flags().String("log-format", "text", "log output format: one of text, json")
+}
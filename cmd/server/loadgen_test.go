@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLatenciesPercentile(t *testing.T) {
+	var l latencies
+	if act := l.Percentile(50); act != 0 {
+		t.Errorf("expected 0 without values, got %v", act)
+	}
+	for i := 100; i > 0; i-- {
+		l.Add(time.Duration(i) * time.Millisecond)
+	}
+
+	tests := []struct {
+		P        int
+		Expected time.Duration
+	}{
+		{P: 0, Expected: time.Millisecond},
+		{P: 1, Expected: time.Millisecond},
+		{P: 50, Expected: 50 * time.Millisecond},
+		{P: 95, Expected: 95 * time.Millisecond},
+		{P: 99, Expected: 99 * time.Millisecond},
+		{P: 100, Expected: 100 * time.Millisecond},
+	}
+	for _, test := range tests {
+		if act := l.Percentile(test.P); act != test.Expected {
+			t.Errorf("p%d: expected %v, got %v", test.P, test.Expected, act)
+		}
+	}
+
+	var single latencies
+	single.Add(1500 * time.Nanosecond)
+	if act := single.Percentile(99); act != 2*time.Microsecond {
+		t.Errorf("expected a single value rounded to microseconds, got %v", act)
+	}
+}
+
+func TestLoadgenResultPrint(t *testing.T) {
+	start := &latencies{}
+	start.Add(10 * time.Millisecond)
+	res := &loadgenResult{
+		Jobs:      []string{"a", "b", "c"},
+		Outcomes:  map[string]int{"succeeded": 2, "failed": 1},
+		Errors:    map[string]int{"list": 4},
+		LogBytes:  1024,
+		Latencies: map[string]*latencies{"start": start, "get": {}},
+		Took:      1500 * time.Millisecond,
+	}
+
+	var buf bytes.Buffer
+	res.Print(&buf)
+	out := buf.String()
+	for _, exp := range []string{"jobs started  3 in 1.5s", "jobs failed   1", "jobs succeeded  2", "log bytes followed  1024", "list errors  4", "start   1      10ms"} {
+		if !strings.Contains(strings.Join(strings.Fields(out), " "), strings.Join(strings.Fields(exp), " ")) {
+			t.Errorf("expected %q in\n%s", exp, out)
+		}
+	}
+	if strings.Contains(out, "\nget") {
+		t.Errorf("expected operations without latencies to be left out:\n%s", out)
+	}
+	if strings.Index(out, "jobs failed") > strings.Index(out, "jobs succeeded") {
+		t.Errorf("expected outcomes to be sorted:\n%s", out)
+	}
+}
@@ -23,6 +23,7 @@ package cmd
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -32,14 +33,21 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/executor"
-	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/32leaves/werft/pkg/logforward"
 	plugin "github.com/32leaves/werft/pkg/plugin/host"
+	"github.com/32leaves/werft/pkg/proxy"
 	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/store/objectstore"
 	"github.com/32leaves/werft/pkg/store/postgres"
+	"github.com/32leaves/werft/pkg/tracing"
+	"github.com/32leaves/werft/pkg/websecurity"
 	"github.com/32leaves/werft/pkg/werft"
 	rice "github.com/GeertJohan/go.rice"
 	"github.com/bradleyfalzon/ghinstallation"
@@ -49,144 +57,200 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
 	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// serviceStopTimeout is the time werft has to store pending job updates when shutting down
+const serviceStopTimeout = 30 * time.Second
+
 // runCmd represents the run command
 var runCmd = &cobra.Command{
-	Use:   "run <config.json>",
+	Use:   "run [config.yaml]",
 	Short: "Starts the werft server",
-	Args:  cobra.ExactArgs(1),
+	Args:  cobra.RangeArgs(0, 1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if v, _ := cmd.Flags().GetBool("verbose"); v {
-			log.SetLevel(log.DebugLevel)
-		}
-
-		fc, err := ioutil.ReadFile(args[0])
+		err := configureLogging(cmd)
 		if err != nil {
 			return err
 		}
 
-		var cfg Config
-		err = yaml.Unmarshal(fc, &cfg)
-		if err != nil {
-			return err
+		demo, _ := cmd.Flags().GetBool("demo")
+		if len(args) == 0 && !demo {
+			return xerrors.Errorf("config file is required unless --demo is set")
 		}
 
-		log.Info("connecting to database")
-		db, err := sql.Open("postgres", cfg.Storage.JobStore)
-		if err != nil {
-			return err
+		var cfg Config
+		if demo {
+			cfg = demoConfig()
 		}
-		maxConns := 10
-		maxIdleConns := 2
-		if cfg.Storage.JobStoreMaxConnections > 0 {
-			maxConns = cfg.Storage.JobStoreMaxConnections
+		if len(args) > 0 {
+			err = readConfig(args[0], &cfg)
+			if err != nil {
+				return err
+			}
 		}
-		if cfg.Storage.JobStoreMaxIdleConnections > 0 {
-			maxIdleConns = cfg.Storage.JobStoreMaxIdleConnections
+		if demo {
+			cfg.Storage.InMemory = true
 		}
-		log.WithField("maxOpenConns", maxConns).WithField("maxIdleConns", maxIdleConns).Debug("setting max open connections on job store DB")
-		db.SetMaxOpenConns(maxConns)
-		db.SetMaxIdleConns(maxIdleConns)
-		err = db.Ping()
+
+		// werft's outbound HTTP traffic, e.g. to GitHub or log sinks, goes through the configured proxy
+		proxyTransport, err := cfg.Proxy.Transport()
 		if err != nil {
 			return err
 		}
 
-		log.Info("making sure database schema is up to date")
-		err = postgres.Migrate(db)
+		err = cfg.WebSecurity.Validate()
 		if err != nil {
 			return err
 		}
-		jobStore, err := postgres.NewJobStore(db)
+
+		shutdownTracing, err := tracing.Init(cfg.Tracing)
 		if err != nil {
 			return err
 		}
-		nrGroups, err := postgres.NewNumberGroup(db)
+		defer shutdownTracing(context.Background())
+
+		stores, err := setupStorage(cfg, proxyTransport)
 		if err != nil {
 			return err
 		}
+		defer stores.Close()
 
-		var kubeConfig *rest.Config
-		if cfg.Kubeconfig == "" {
-			kubeConfig, err = rest.InClusterConfig()
-			if err != nil {
-				return err
-			}
+		var (
+			ghClient *github.Client
+			ghAuth   werft.GitCredentialHelper
+		)
+		if cfg.GitHub.PrivateKeyPath == "" && demo {
+			// without a GitHub app we can only access public repositories
+			log.Warn("no GitHub app configured - only public repositories will be accessible")
+			ghClient = github.NewClient(&http.Client{Transport: proxyTransport})
 		} else {
-			kubeConfig, err = clientcmd.BuildConfigFromFlags("", cfg.Kubeconfig)
+			ghtr, err := ghinstallation.NewKeyFromFile(proxyTransport, cfg.GitHub.AppID, cfg.GitHub.InstallationID, cfg.GitHub.PrivateKeyPath)
 			if err != nil {
 				return err
 			}
+			ghClient = github.NewClient(&http.Client{Transport: ghtr})
+			ghAuth = func(ctx context.Context) (user string, pass string, err error) {
+				tkn, err := ghtr.Token(ctx)
+				if err != nil {
+					return
+				}
+				user = "x-access-token"
+				pass = tkn
+				return
+			}
 		}
 
-		ghtr, err := ghinstallation.NewKeyFromFile(http.DefaultTransport, cfg.GitHub.AppID, cfg.GitHub.InstallationID, cfg.GitHub.PrivateKeyPath)
+		uiservice, err := werft.NewUIService(ghClient, cfg.Service.JobSpecRepos)
 		if err != nil {
 			return err
 		}
-		ghClient := github.NewClient(&http.Client{Transport: ghtr})
-
-		execCfg := cfg.Executor
-		if execCfg.Namespace == "" {
-			execCfg.Namespace = "default"
+		var sessions *websecurity.Sessions
+		if cfg.WebSecurity.Sessions != nil {
+			sessions, err = websecurity.NewSessions(*cfg.WebSecurity.Sessions)
+			if err != nil {
+				return err
+			}
+			uiservice.Sessions = sessions
 		}
 
-		logStore, err := store.NewFileLogStore(cfg.Storage.LogStore)
+		exec, informerStats, err := newExecutor(cfg, demo)
 		if err != nil {
 			return err
 		}
 
-		uiservice, err := werft.NewUIService(ghClient, cfg.Service.JobSpecRepos)
+		logForwarder, err := logforward.New(cfg.LogForwarding, proxyTransport)
 		if err != nil {
-			return err
+			return xerrors.Errorf("cannot set up log forwarding: %w", err)
+		}
+		if logForwarder != nil {
+			defer logForwarder.Close()
 		}
 
-		log.Info("connecting to kubernetes")
-		exec, err := executor.NewExecutor(execCfg, kubeConfig)
+		jobResources, err := newJobResources(cfg, demo)
 		if err != nil {
-			return err
+			return xerrors.Errorf("cannot set up job resources: %w", err)
 		}
-		exec.Run()
-		service := &werft.Service{
-			Logs:     logStore,
-			Jobs:     jobStore,
-			Groups:   nrGroups,
-			Executor: exec,
-			Cutter:   logcutter.DefaultCutter,
-			GitHub: werft.GitHubSetup{
-				WebhookSecret: []byte(cfg.GitHub.WebhookSecret),
-				Client:        ghClient,
-				Auth: func(ctx context.Context) (user string, pass string, err error) {
-					tkn, err := ghtr.Token(ctx)
-					if err != nil {
-						return
-					}
-					user = "x-access-token"
-					pass = tkn
-					return
-				},
-			},
-			Config: cfg.Werft,
+
+		webhookSecrets := make([][]byte, len(cfg.GitHub.WebhookSecrets))
+		for i, s := range cfg.GitHub.WebhookSecrets {
+			webhookSecrets[i] = []byte(s)
+		}
+		webhookPath := cfg.GitHub.WebhookPath
+		if webhookPath == "" {
+			webhookPath = "/github/app"
 		}
+		webhookGuard, err := werft.NewWebhookGuard(cfg.Werft.WebhookSources, cfg.Werft.MaxWebhookPayloadSize, ghClient)
+		if err != nil {
+			return err
+		}
+		defer webhookGuard.Close()
+
 		if val, _ := cmd.Flags().GetString("debug-webui-proxy"); val != "" {
 			cfg.Werft.DebugProxy = val
 		}
-		err = service.Start()
+		service, err := werft.New(
+			werft.WithConfig(cfg.Werft),
+			werft.WithExecutor(exec),
+			werft.WithLogStore(stores.Logs),
+			werft.WithJobStore(stores.Jobs),
+			werft.WithNumberGroup(stores.Groups),
+			werft.WithArchive(stores.Archive),
+			werft.WithDeadLetters(stores.DeadLetters),
+			werft.WithImages(stores.Images),
+			werft.WithGitHub(werft.GitHubSetup{
+				WebhookSecret:  []byte(cfg.GitHub.WebhookSecret),
+				WebhookSecrets: webhookSecrets,
+				Client:         ghClient,
+				Auth:           ghAuth,
+			}),
+			werft.WithLogForwarder(logForwarder),
+			werft.WithJobResources(jobResources),
+			werft.WithVersion(getVersionInfo().proto()),
+			werft.WithTransport(proxyTransport),
+		)
+		if err != nil {
+			log.WithError(err).Fatal("cannot create service")
+		}
+		err = service.Start(context.Background())
 		if err != nil {
 			log.WithError(err).Fatal("cannot start service")
 		}
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), serviceStopTimeout)
+			defer cancel()
+			err := service.Stop(ctx)
+			if err != nil {
+				log.WithError(err).Warn("cannot stop service")
+			}
+		}()
 
-		grpcServer := grpc.NewServer()
+		grpcServer := grpc.NewServer(
+			// allow clients to keep long-running log listeners alive using keepalive pings
+			grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+				MinTime:             10 * time.Second,
+				PermitWithoutStream: true,
+			}),
+			grpc.UnaryInterceptor(apiVersionUnaryInterceptor),
+			grpc.StreamInterceptor(apiVersionStreamInterceptor),
+		)
 		v1.RegisterWerftServiceServer(grpcServer, service)
 		v1.RegisterWerftUIServer(grpcServer, uiservice)
+		if agents, ok := exec.(*executor.AgentExecutor); ok {
+			v1.RegisterAgentServiceServer(grpcServer, agents)
+		}
+		reflection.Register(grpcServer)
 		go startGRPC(grpcServer, fmt.Sprintf(":%d", cfg.Service.GRPCPort))
-		go startWeb(service, grpcServer, fmt.Sprintf(":%d", cfg.Service.WebPort), cfg.Werft.DebugProxy)
+		go startWeb(service, grpcServer, fmt.Sprintf(":%d", cfg.Service.WebPort), webhookPath, webhookGuard, cfg.WebSecurity, sessions, cfg.Werft.DebugProxy)
 		if cfg.Service.PromPort != 0 {
-			go startPrometheus(fmt.Sprintf(":%d", cfg.Service.PromPort), db.Stats)
+			go startPrometheus(fmt.Sprintf(":%d", cfg.Service.PromPort), stores.DBStats, informerStats, append(service.Metrics(), stores.Metrics()...)...)
 		}
 		if cfg.Service.PprofPort != 0 {
 			go startPProf(fmt.Sprintf(":%d", cfg.Service.PprofPort))
@@ -202,6 +266,13 @@ var runCmd = &cobra.Command{
 			}
 		}()
 		defer plugins.Stop()
+		for _, p := range plugins.LogParsers {
+			service.AddLogParser(p)
+		}
+		for _, h := range plugins.Hooks {
+			service.AddHook(h)
+			exec.AddPodHook(h)
+		}
 
 		sigChan := make(chan os.Signal, 1)
 		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -213,8 +284,347 @@ var runCmd = &cobra.Command{
 	},
 }
 
+// storage bundles the stores werft persists its state in
+type storage struct {
+	Logs        store.Logs
+	Jobs        store.Jobs
+	Groups      store.NumberGroup
+	Archive     store.JobArchive
+	DeadLetters store.DeadLetters
+	Images      store.Images
+	DB          *sql.DB
+
+	// WAL keeps job updates while the job store is unavailable. It's nil unless configured.
+	WAL *store.WALJobStore
+
+	snapshotPath string
+	stop         chan struct{}
+}
+
+// DBStats returns the job store database stats, or zero stats if the stores live in memory
+func (s *storage) DBStats() sql.DBStats {
+	if s.DB == nil {
+		return sql.DBStats{}
+	}
+	return s.DB.Stats()
+}
+
+// Close stops the periodic snapshots and writes a final one if the stores live in memory, and closes the write-ahead log
+func (s *storage) Close() {
+	if s.stop != nil {
+		close(s.stop)
+	}
+	s.saveSnapshot()
+	if s.WAL != nil {
+		err := s.WAL.Close()
+		if err != nil {
+			log.WithError(err).Warn("cannot close write-ahead log")
+		}
+	}
+}
+
+// Metrics returns the Prometheus collectors of the stores
+func (s *storage) Metrics() []prometheus.Collector {
+	if s.WAL == nil {
+		return nil
+	}
+	return []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "job_store_wal_entries_total",
+			Help: "Job updates in the write-ahead log which wait for the job store to be available again.",
+		}, func() float64 { return float64(s.WAL.Buffered()) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "job_store_wal_dead_letters_total",
+			Help: "Job updates which could not be replayed from the write-ahead log and were moved to its dead letter file.",
+		}, func() float64 { return float64(s.WAL.DeadLettered()) }),
+	}
+}
+
+func (s *storage) saveSnapshot() {
+	if s.snapshotPath == "" {
+		return
+	}
+
+	err := store.SaveSnapshotFile(s.snapshotPath, s.Jobs, s.Groups, s.Logs)
+	if err != nil {
+		log.WithError(err).WithField("path", s.snapshotPath).Warn("cannot save snapshot")
+		return
+	}
+	log.WithField("path", s.snapshotPath).Debug("saved snapshot")
+}
+
+// setupStorage creates the job, log and number group stores as configured. Object stores are reached using transport.
+func setupStorage(cfg Config, transport http.RoundTripper) (*storage, error) {
+	if cfg.Storage.InMemory {
+		return setupInMemoryStorage(cfg)
+	}
+
+	queryTimeout := postgres.DefaultQueryTimeout
+	if cfg.Storage.JobStoreQueryTimeout != nil {
+		queryTimeout = cfg.Storage.JobStoreQueryTimeout.Duration
+	}
+
+	log.Info("connecting to database")
+	db, err := openJobStoreDB(cfg.Storage, cfg.Storage.JobStore, queryTimeout)
+	if err != nil {
+		return nil, err
+	}
+	var readDB *sql.DB
+	if cfg.Storage.JobStoreReadReplica != "" {
+		log.Info("connecting to database read replica")
+		readDB, err = openJobStoreDB(cfg.Storage, cfg.Storage.JobStoreReadReplica, queryTimeout)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot connect to read replica: %w", err)
+		}
+	}
+
+	log.Info("making sure database schema is up to date")
+	err = postgres.Migrate(db)
+	if err != nil {
+		return nil, err
+	}
+	jobStore, err := postgres.NewJobStore(db)
+	if err != nil {
+		return nil, err
+	}
+	jobStore.QueryTimeout = queryTimeout
+	jobStore.ReadDB = readDB
+	var (
+		jobs store.Jobs = jobStore
+		wal  *store.WALJobStore
+	)
+	if c := cfg.Storage.JobStoreWAL; c != nil {
+		if c.Path == "" {
+			return nil, xerrors.Errorf("storage.jobsWriteAheadLog.path is required")
+		}
+		var retry time.Duration
+		if c.RetryInterval != nil {
+			retry = c.RetryInterval.Duration
+		}
+		wal, err = store.NewWALJobStore(jobStore, c.Path, retry)
+		if err != nil {
+			return nil, err
+		}
+		if c.MaxSize > 0 {
+			wal.MaxSize = c.MaxSize
+		}
+		jobs = wal
+	}
+	nrGroups, err := postgres.NewNumberGroup(db)
+	if err != nil {
+		return nil, err
+	}
+	nrGroups.QueryTimeout = queryTimeout
+	deadLetters, err := postgres.NewDeadLetters(db)
+	if err != nil {
+		return nil, err
+	}
+	deadLetters.QueryTimeout = queryTimeout
+	images, err := postgres.NewImages(db)
+	if err != nil {
+		return nil, err
+	}
+	images.QueryTimeout = queryTimeout
+
+	encKey, err := cfg.Storage.logEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Storage.Objects != nil {
+		logStore, jobArchive, err := setupObjectStorage(cfg.Storage, encKey, transport)
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			Logs:        logStore,
+			Jobs:        jobs,
+			Groups:      nrGroups,
+			Archive:     jobArchive,
+			DeadLetters: deadLetters,
+			Images:      images,
+			DB:          db,
+		}, nil
+	}
+
+	var logStoreOpts []store.FileLogStoreOption
+	if encKey != nil {
+		log.Info("encrypting logs at rest")
+		logStoreOpts = append(logStoreOpts, store.WithEncryptionKey(encKey))
+	}
+	if cfg.Storage.LogSyncInterval != nil {
+		logStoreOpts = append(logStoreOpts, store.WithSyncInterval(cfg.Storage.LogSyncInterval.Duration))
+	}
+	logStore, err := store.NewFileLogStore(cfg.Storage.LogStore, logStoreOpts...)
+	if err != nil {
+		return nil, err
+	}
+	archivePath := cfg.Storage.JobArchive
+	if archivePath == "" {
+		archivePath = filepath.Join(cfg.Storage.LogStore, "archive")
+	}
+	jobArchive, err := store.NewFileJobArchive(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storage{
+		Logs:        logStore,
+		Jobs:        jobs,
+		Groups:      nrGroups,
+		Archive:     jobArchive,
+		DeadLetters: deadLetters,
+		Images:      images,
+		DB:          db,
+	}, nil
+}
+
+// setupObjectStorage creates the log store and job archive which keep their content in an object store. Logs are
+// spooled to the logs path while they're written.
+func setupObjectStorage(storage StorageConfig, encKey []byte, transport http.RoundTripper) (store.Logs, store.JobArchive, error) {
+	cfg := *storage.Objects
+	objects, err := objectstore.New(cfg, &http.Client{Transport: transport})
+	if err != nil {
+		return nil, nil, xerrors.Errorf("cannot set up object store: %w", err)
+	}
+	if encKey != nil {
+		log.Info("encrypting logs and archived jobs at rest")
+		objects, err = store.EncryptObjects(objects, encKey)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var spoolOpts []store.FileLogStoreOption
+	if encKey != nil {
+		spoolOpts = append(spoolOpts, store.WithEncryptionKey(encKey))
+	}
+	spool, err := store.NewFileLogStore(filepath.Join(storage.LogStore, "spool"), spoolOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	logs, err := store.NewObjectLogStore(objects, "logs/", spool)
+	if err != nil {
+		return nil, nil, err
+	}
+	log.WithField("type", cfg.Type).Info("keeping logs and archived jobs in an object store")
+	return logs, store.NewObjectJobArchive(objects, "archive/"), nil
+}
+
+// openJobStoreDB connects to a job store database with the configured connection pool
+func openJobStoreDB(cfg StorageConfig, connStr string, timeout time.Duration) (*sql.DB, error) {
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	maxConns := 10
+	maxIdleConns := 2
+	if cfg.JobStoreMaxConnections > 0 {
+		maxConns = cfg.JobStoreMaxConnections
+	}
+	if cfg.JobStoreMaxIdleConnections > 0 {
+		maxIdleConns = cfg.JobStoreMaxIdleConnections
+	}
+	log.WithField("maxOpenConns", maxConns).WithField("maxIdleConns", maxIdleConns).Debug("setting max open connections on job store DB")
+	db.SetMaxOpenConns(maxConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	if cfg.JobStoreConnMaxLifetime != nil {
+		db.SetConnMaxLifetime(cfg.JobStoreConnMaxLifetime.Duration)
+	}
+	if cfg.JobStoreConnMaxIdleTime != nil {
+		db.SetConnMaxIdleTime(cfg.JobStoreConnMaxIdleTime.Duration)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err = db.PingContext(ctx)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// setupInMemoryStorage creates stores which keep everything in memory, optionally snapshotting to disk
+func setupInMemoryStorage(cfg Config) (*storage, error) {
+	log.Warn("using in-memory storage - jobs and logs will be lost when werft stops unless a snapshot path is configured")
+
+	res := &storage{
+		Logs:         store.NewInMemoryLogStore(),
+		Jobs:         store.NewInMemoryJobStore(),
+		Groups:       store.NewInMemoryNumberGroup(),
+		DeadLetters:  store.NewInMemoryDeadLetters(),
+		Images:       store.NewInMemoryImages(),
+		snapshotPath: cfg.Storage.SnapshotPath,
+	}
+	if res.snapshotPath == "" {
+		return res, nil
+	}
+
+	log.WithField("path", res.snapshotPath).Info("restoring snapshot")
+	err := store.LoadSnapshotFile(res.snapshotPath, res.Jobs, res.Groups, res.Logs)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot restore snapshot: %w", err)
+	}
+
+	interval := 5 * time.Minute
+	if cfg.Storage.SnapshotInterval != nil {
+		interval = cfg.Storage.SnapshotInterval.Duration
+	}
+	res.stop = make(chan struct{})
+	go func() {
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				res.saveSnapshot()
+			case <-res.stop:
+				return
+			}
+		}
+	}()
+
+	return res, nil
+}
+
+// demoConfig returns the configuration used by --demo unless overridden by a config file
+func demoConfig() Config {
+	var cfg Config
+	cfg.Service.WebPort = 8080
+	cfg.Service.GRPCPort = 7777
+	cfg.Executor.JobPrepTimeout = &executor.Duration{Duration: 10 * time.Minute}
+	cfg.Executor.JobTotalTimeout = &executor.Duration{Duration: 60 * time.Minute}
+	return cfg
+}
+
+// configureLogging sets up logrus according to the --log-level, --log-format and --verbose flags
+func configureLogging(cmd *cobra.Command) error {
+	lvl, _ := cmd.Flags().GetString("log-level")
+	level, err := log.ParseLevel(lvl)
+	if err != nil {
+		return xerrors.Errorf("invalid --log-level: %w", err)
+	}
+	if v, _ := cmd.Flags().GetBool("verbose"); v {
+		level = log.DebugLevel
+	}
+	log.SetLevel(level)
+
+	format, _ := cmd.Flags().GetString("log-format")
+	switch format {
+	case "text":
+		log.SetFormatter(&log.TextFormatter{})
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return xerrors.Errorf("invalid --log-format %s: must be one of text, json", format)
+	}
+
+	return nil
+}
+
 // startWeb starts the werft web UI service
-func startWeb(srv *werft.Service, grpcServer *grpc.Server, addr string, debugProxy string) {
+func startWeb(srv *werft.Service, grpcServer *grpc.Server, addr string, webhookPath string, webhookGuard *werft.WebhookGuard, sec websecurity.Config, sessions *websecurity.Sessions, debugProxy string) {
 	var webuiServer http.Handler
 	if debugProxy != "" {
 		tgt, err := url.Parse(debugProxy)
@@ -242,19 +652,26 @@ func startWeb(srv *werft.Service, grpcServer *grpc.Server, addr string, debugPro
 		})
 	}
 
-	grpcWebServer := grpcweb.WrapServer(grpcServer)
+	grpcWebServer := grpcweb.WrapServer(grpcServer, grpcweb.WithOriginFunc(sec.OriginAllowed))
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("/github/app", srv.HandleGithubWebhook)
-	mux.Handle("/", hstsHandler(
-		grpcTrafficSplitter(
-			webuiServer,
-			grpcWebServer,
-		),
+	if sessions != nil {
+		mux.Handle("/auth/", sessions.Handler())
+	}
+	mux.Handle(webhookPath, webhookGuard.Handler(http.HandlerFunc(srv.HandleGithubWebhook)))
+	mux.Handle("/api/version", sec.CORS(http.HandlerFunc(handleVersion)))
+	mux.Handle("/api/config-schema", sec.CORS(http.HandlerFunc(handleConfigSchema)))
+	mux.Handle("/logs/", sec.CORS(http.HandlerFunc(srv.HandleLogDownload)))
+	mux.Handle("/export/jobs", sec.CORS(http.HandlerFunc(srv.HandleJobExport)))
+	mux.HandleFunc("/artifacts/", srv.HandleArtifactWebhook)
+	mux.HandleFunc("/debug/", srv.HandleDebug)
+	mux.Handle("/", grpcTrafficSplitter(
+		webuiServer,
+		grpcWebServer,
 	))
 
 	log.WithField("addr", addr).Info("serving werft web service")
-	err := http.ListenAndServe(addr, mux)
+	err := http.ListenAndServe(addr, sec.Handler(mux))
 	if err != nil {
 		log.WithField("addr", addr).WithError(err).Warn("cannot serve web service")
 	}
@@ -274,8 +691,85 @@ func startGRPC(srv *grpc.Server, addr string) {
 	}
 }
 
-// startPrometheus starts a Prometheus metrics server on addr.
-func startPrometheus(addr string, dbstats func() sql.DBStats) {
+// newExecutor creates the executor backend the config asks for. It returns the stats of the job pod cache as well,
+// which are zero unless jobs run in Kubernetes.
+func newExecutor(cfg Config, demo bool) (exec executor.Backend, informerStats func() executor.InformerStats, err error) {
+	execCfg := cfg.Executor
+	switch execCfg.Backend {
+	case executor.BackendDocker:
+		log.Info("running jobs using docker")
+		exec, err = executor.NewDockerExecutor(execCfg)
+		return exec, func() executor.InformerStats { return executor.InformerStats{} }, err
+	case executor.BackendNomad:
+		log.Info("running jobs using Nomad")
+		exec, err = executor.NewNomadExecutor(execCfg)
+		return exec, func() executor.InformerStats { return executor.InformerStats{} }, err
+	case executor.BackendAgent:
+		log.Info("running jobs using agents")
+		exec, err = executor.NewAgentExecutor(execCfg)
+		return exec, func() executor.InformerStats { return executor.InformerStats{} }, err
+	case executor.BackendFake:
+		log.Warn("pretending to run jobs using the fake executor - jobs do not run")
+		exec, err = executor.NewFakeExecutor(execCfg)
+		return exec, func() executor.InformerStats { return executor.InformerStats{} }, err
+	case "", executor.BackendKubernetes:
+	default:
+		return nil, nil, xerrors.Errorf("unknown executor backend %s: must be %s, %s, %s, %s or %s", execCfg.Backend, executor.BackendKubernetes, executor.BackendDocker, executor.BackendNomad, executor.BackendAgent, executor.BackendFake)
+	}
+
+	if execCfg.Namespace == "" {
+		execCfg.Namespace = "default"
+	}
+	kubeConfig, err := getKubeConfig(cfg, demo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Info("connecting to kubernetes")
+	kexec, err := executor.NewExecutor(execCfg, kubeConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pullSecrets := execCfg.ImagePullSecrets
+	for _, rc := range cfg.Werft.Repositories {
+		pullSecrets = append(pullSecrets, rc.ImagePullSecrets...)
+	}
+	err = kexec.ValidateImagePullSecrets(pullSecrets...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kexec, kexec.InformerStats, nil
+}
+
+// newJobResources connects to the WerftJob custom resources jobs are mirrored as. It returns nil if jobs aren't mirrored.
+func newJobResources(cfg Config, demo bool) (dynamic.ResourceInterface, error) {
+	jrCfg := cfg.Werft.JobResources
+	if jrCfg == nil {
+		return nil, nil
+	}
+
+	namespace := jrCfg.Namespace
+	if namespace == "" {
+		namespace = cfg.Executor.Namespace
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+	kubeConfig, err := getKubeConfig(cfg, demo)
+	if err != nil {
+		return nil, err
+	}
+	client, err := dynamic.NewForConfig(kubeConfig)
+	if err != nil {
+		return nil, err
+	}
+	log.WithField("namespace", namespace).Info("mirroring jobs as WerftJob resources")
+	return client.Resource(werft.JobResourceGVR).Namespace(namespace), nil
+}
+
+// startPrometheus starts a Prometheus metrics server on addr. Additional collectors, e.g. those of the werft service, are served as well.
+func startPrometheus(addr string, dbstats func() sql.DBStats, informerStats func() executor.InformerStats, collectors ...prometheus.Collector) {
 	reg := prometheus.NewRegistry()
 	reg.MustRegister(
 		prometheus.NewGoCollector(),
@@ -296,7 +790,29 @@ func startPrometheus(addr string, dbstats func() sql.DBStats) {
 			Name: "job_store_db_waiting_queries_total",
 			Help: "Number of waiting new DB connections of the job store.",
 		}, func() float64 { return float64(dbstats().WaitCount) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "executor_informer_synced",
+			Help: "1 once the executor's job pod cache is synced, 0 before.",
+		}, func() float64 {
+			if informerStats().Synced {
+				return 1
+			}
+			return 0
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "executor_informer_cached_pods_total",
+			Help: "Job pods in the executor's cache.",
+		}, func() float64 { return float64(informerStats().CachedPods) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "executor_workqueue_depth_total",
+			Help: "Job pods waiting to be processed by the executor.",
+		}, func() float64 { return float64(informerStats().QueueDepth) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "executor_pod_events_total",
+			Help: "Job pod events the executor has seen, including resyncs.",
+		}, func() float64 { return float64(informerStats().Events) }),
 	)
+	reg.MustRegister(collectors...)
 
 	handler := http.NewServeMux()
 	handler.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
@@ -324,14 +840,6 @@ func startPProf(addr string) {
 	}
 }
 
-// hstsHandler wraps an http.HandlerFunc sfuch that it sets the HSTS header.
-func hstsHandler(fn http.HandlerFunc) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains; preload")
-		fn(w, r)
-	})
-}
-
 func grpcTrafficSplitter(fallback http.Handler, wrappedGrpc *grpcweb.WrappedGrpcServer) http.HandlerFunc {
 	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 		if wrappedGrpc.IsGrpcWebRequest(req) || wrappedGrpc.IsAcceptableGrpcCorsRequest(req) {
@@ -368,7 +876,38 @@ func init() {
 	rootCmd.AddCommand(runCmd)
 
 	runCmd.Flags().String("debug-webui-proxy", "", "proxies the web UI to this address")
-	runCmd.Flags().Bool("verbose", false, "enable verbose debug output")
+	runCmd.Flags().Bool("verbose", false, "enable verbose debug output (same as --log-level debug)")
+	runCmd.Flags().String("log-level", "info", "log level: one of panic, fatal, error, warn, info, debug, trace")
+	runCmd.Flags().String("log-format", "text", "log output format: one of text, json")
+	runCmd.Flags().Bool("demo", false, "run without Postgres or persistent volumes - all jobs and logs are kept in memory and the config file becomes optional")
+}
+
+// readConfig reads and validates a config file
+func readConfig(fn string, cfg *Config) error {
+	fc, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return err
+	}
+	err = validateConfig(fn, fc)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(fc, cfg)
+}
+
+// getKubeConfig connects to the configured cluster or the cluster werft runs in. Outside of a cluster,
+// the local kubeconfig is used if fallback is true.
+func getKubeConfig(cfg Config, fallback bool) (*rest.Config, error) {
+	if cfg.Kubeconfig != "" {
+		return clientcmd.BuildConfigFromFlags("", cfg.Kubeconfig)
+	}
+
+	res, err := rest.InClusterConfig()
+	if err == rest.ErrNotInCluster && fallback {
+		log.WithField("kubeconfig", clientcmd.RecommendedHomeFile).Info("not running in a cluster - using local kubeconfig")
+		return clientcmd.BuildConfigFromFlags("", clientcmd.RecommendedHomeFile)
+	}
+	return res, err
 }
 
 // Config configures the werft server
@@ -381,19 +920,105 @@ type Config struct {
 		PprofPort    int      `yaml:"pprofPort,omitempty"`
 		JobSpecRepos []string `yaml:"jobSpecRepos"`
 	}
-	Storage struct {
-		LogStore                   string `yaml:"logsPath"`
-		JobStore                   string `yaml:"jobsConnectionString"`
-		JobStoreMaxConnections     int    `yaml:"jobsMaxConnections"`
-		JobStoreMaxIdleConnections int    `yaml:"jobsMaxIdleConnections"`
-	} `yaml:"storage"`
+	Storage    StorageConfig   `yaml:"storage"`
 	Executor   executor.Config `yaml:"executor"`
 	Kubeconfig string          `yaml:"kubeconfig,omitempty"`
 	GitHub     struct {
-		WebhookSecret  string `yaml:"webhookSecret"`
+		WebhookSecret string `yaml:"webhookSecret"`
+		// WebhookSecrets are accepted in addition to WebhookSecret so that the secret can be rotated
+		WebhookSecrets []string `yaml:"webhookSecrets,omitempty"`
+		// WebhookPath is the path GitHub delivers webhook events to. Defaults to /github/app.
+		WebhookPath    string `yaml:"webhookPath,omitempty"`
 		PrivateKeyPath string `yaml:"privateKeyPath"`
 		InstallationID int64  `yaml:"installationID,omitempty"`
 		AppID          int64  `yaml:"appID"`
 	} `yaml:"github"`
 	Plugins plugin.Config
+	Tracing tracing.Config `yaml:"tracing,omitempty"`
+
+	// LogForwarding mirrors job logs to external log sinks
+	LogForwarding logforward.Config `yaml:"logForwarding,omitempty"`
+
+	// Proxy configures the proxy outbound HTTP traffic goes through
+	Proxy proxy.Config `yaml:"proxy,omitempty"`
+
+	// WebSecurity configures the security headers of the web server and which other sites may call the API
+	WebSecurity websecurity.Config `yaml:"webSecurity,omitempty"`
+}
+
+// StorageConfig configures where werft keeps its jobs and logs
+type StorageConfig struct {
+	LogStore                   string `yaml:"logsPath"`
+	JobStore                   string `yaml:"jobsConnectionString"`
+	JobStoreMaxConnections     int    `yaml:"jobsMaxConnections"`
+	JobStoreMaxIdleConnections int    `yaml:"jobsMaxIdleConnections"`
+	JobArchive                 string `yaml:"archivePath,omitempty"`
+
+	// JobStoreReadReplica is the connection string of a read-only replica of the job store. If set, job listings
+	// and searches are served by the replica so that they don't contend with job updates on the primary.
+	JobStoreReadReplica string `yaml:"jobsReadConnectionString,omitempty"`
+
+	// JobStoreConnMaxLifetime closes connections to the job store once they've been open for that long, e.g. so that
+	// connections are spread across database replicas again after a failover. By default connections are kept open.
+	JobStoreConnMaxLifetime *executor.Duration `yaml:"jobsConnMaxLifetime,omitempty"`
+	// JobStoreConnMaxIdleTime closes idle connections to the job store once they've been idle for that long
+	JobStoreConnMaxIdleTime *executor.Duration `yaml:"jobsConnMaxIdleTime,omitempty"`
+	// JobStoreQueryTimeout is the time a job store query may take. Defaults to 30 seconds.
+	JobStoreQueryTimeout *executor.Duration `yaml:"jobsQueryTimeout,omitempty"`
+
+	// InMemory keeps all jobs and logs in memory. Meant for demos and local testing only.
+	InMemory bool `yaml:"inMemory,omitempty"`
+	// SnapshotPath is the file the in-memory stores are periodically saved to and restored from
+	SnapshotPath     string             `yaml:"snapshotPath,omitempty"`
+	SnapshotInterval *executor.Duration `yaml:"snapshotInterval,omitempty"`
+
+	// LogEncryptionKey is a base64 encoded AES key (16, 24 or 32 bytes) used to encrypt logs at rest.
+	LogEncryptionKey string `yaml:"logEncryptionKey,omitempty"`
+	// LogEncryptionKeyPath points to a file containing the base64 encoded log encryption key, e.g. mounted from a Kubernetes secret.
+	LogEncryptionKeyPath string `yaml:"logEncryptionKeyPath,omitempty"`
+
+	// LogSyncInterval makes werft flush logs to disk (fsync) at most once per interval while they're written, and once
+	// they're complete. 0s flushes logs after every write. If this is not set, the operating system decides when logs
+	// reach the disk.
+	LogSyncInterval *executor.Duration `yaml:"logSyncInterval,omitempty"`
+
+	// Objects keeps logs and archived jobs in an object store, e.g. an S3 bucket, instead of logsPath and archivePath.
+	// This way werft doesn't need a persistent volume for them.
+	Objects *objectstore.Config `yaml:"objects,omitempty"`
+
+	// JobStoreWAL keeps job updates in a write-ahead log on disk while the job store is unavailable, e.g. during a
+	// brief database outage, and replays them once it's back. Without it such updates are lost.
+	JobStoreWAL *WALConfig `yaml:"jobsWriteAheadLog,omitempty"`
+}
+
+// WALConfig configures the write-ahead log of the job store
+type WALConfig struct {
+	// Path is the file the log is kept in. It must be on a persistent volume for updates to survive restarts.
+	Path string `yaml:"path"`
+	// MaxSize is the size in bytes the log may grow to. Once it's full, job updates fail. Defaults to 64 MiB.
+	MaxSize int64 `yaml:"maxSize,omitempty"`
+	// RetryInterval is how often werft tries to replay the log while the job store is unavailable. Defaults to 5s.
+	RetryInterval *executor.Duration `yaml:"retryInterval,omitempty"`
+}
+
+// logEncryptionKey returns the configured log encryption key or nil if logs are not to be encrypted
+func (c StorageConfig) logEncryptionKey() ([]byte, error) {
+	encoded := c.LogEncryptionKey
+	if c.LogEncryptionKeyPath != "" {
+		fc, err := ioutil.ReadFile(c.LogEncryptionKeyPath)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read log encryption key: %w", err)
+		}
+		encoded = string(fc)
+	}
+	encoded = strings.TrimSpace(encoded)
+	if encoded == "" {
+		return nil, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, xerrors.Errorf("log encryption key must be base64 encoded: %w", err)
+	}
+	return key, nil
 }
@@ -0,0 +1,147 @@
+package cmd
+
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+)
+
+// stateCmd represents the state command
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Exports and imports the jobs werft stores, e.g. to migrate to another database or cluster",
+	Long: `Exports and imports the jobs werft stores, e.g. to migrate to another database or cluster.
+State archives contain the job records, job specs, events, provenance and image builds of all jobs, and optionally
+their logs. Archived jobs are not exported. Use - as archive to write to stdout or read from stdin.`,
+	Args: cobra.NoArgs,
+}
+
+// stateExportCmd represents the state export command
+var stateExportCmd = &cobra.Command{
+	Use:   "export <config.yaml> <archive.tar.gz>",
+	Short: "Exports the jobs stored by the werft instance a config file configures",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		withLogs, _ := cmd.Flags().GetBool("logs")
+		withImages, _ := cmd.Flags().GetBool("images")
+
+		stores, err := openStateStores(args[0])
+		if err != nil {
+			return err
+		}
+		defer stores.Close()
+
+		var out io.Writer = os.Stdout
+		if args[1] != "-" {
+			f, err := os.Create(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+
+		st := store.State{Jobs: stores.Jobs, Groups: stores.Groups}
+		if withLogs {
+			st.Logs = stores.Logs
+		}
+		if withImages {
+			st.Images = stores.Images
+		}
+		n, err := store.ExportState(context.Background(), out, st)
+		if err != nil {
+			return err
+		}
+		log.WithField("jobs", n).Info("state exported")
+		return nil
+	},
+}
+
+// stateImportCmd represents the state import command
+var stateImportCmd = &cobra.Command{
+	Use:   "import <config.yaml> <archive.tar.gz>",
+	Short: "Imports a state archive into the werft instance a config file configures",
+	Long: `Imports a state archive into the werft instance a config file configures.
+Jobs which exist already are replaced, logs which exist already are kept. Job numbering continues after the imported jobs.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stores, err := openStateStores(args[0])
+		if err != nil {
+			return err
+		}
+		defer stores.Close()
+
+		var in io.Reader = os.Stdin
+		if args[1] != "-" {
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			in = f
+		}
+
+		n, err := store.ImportState(context.Background(), in, store.State{
+			Jobs:   stores.Jobs,
+			Groups: stores.Groups,
+			Logs:   stores.Logs,
+			Images: stores.Images,
+		})
+		if err != nil {
+			return err
+		}
+		log.WithField("jobs", n).Info("state imported")
+		return nil
+	},
+}
+
+// openStateStores sets up the stores a config file configures, as werft would when it starts
+func openStateStores(fn string) (*storage, error) {
+	var cfg Config
+	err := readConfig(fn, &cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Storage.InMemory && cfg.Storage.SnapshotPath == "" {
+		return nil, xerrors.Errorf("in-memory storage without storage.snapshotPath cannot be exported or imported")
+	}
+	// the write-ahead log belongs to the running werft instance
+	cfg.Storage.JobStoreWAL = nil
+	transport, err := cfg.Proxy.Transport()
+	if err != nil {
+		return nil, err
+	}
+	return setupStorage(cfg, transport)
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+
+	stateExportCmd.Flags().Bool("logs", false, "export the logs of the jobs as well")
+	stateExportCmd.Flags().Bool("images", true, "export the image builds of the jobs")
+}
@@ -19,19 +19,137 @@ package cmd
 // THE SOFTWARE.
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
 
+	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
-var version = "unknown"
+// version, commit and buildDate are set at build time using -ldflags "-X ...".
+// If commit or buildDate are not set, we fall back to the VCS information Go embeds into the binary.
+var (
+	version   = "unknown"
+	commit    = ""
+	buildDate = ""
+)
+
+// versionInfo describes this build of werft
+type versionInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit"`
+	BuildDate  string `json:"buildDate"`
+	APIVersion string `json:"apiVersion"`
+	GoVersion  string `json:"goVersion"`
+}
+
+func getVersionInfo() versionInfo {
+	res := versionInfo{
+		Version:    version,
+		Commit:     commit,
+		BuildDate:  buildDate,
+		APIVersion: v1.APIVersion,
+		GoVersion:  runtime.Version(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && res.Commit == "":
+				res.Commit = s.Value
+			case s.Key == "vcs.time" && res.BuildDate == "":
+				res.BuildDate = s.Value
+			}
+		}
+	}
+	if res.Commit == "" {
+		res.Commit = "unknown"
+	}
+	if res.BuildDate == "" {
+		res.BuildDate = "unknown"
+	}
+	return res
+}
+
+// proto converts the version info to its API representation
+func (vi versionInfo) proto() *v1.GetVersionResponse {
+	return &v1.GetVersionResponse{
+		Version:    vi.Version,
+		Commit:     vi.Commit,
+		BuildDate:  vi.BuildDate,
+		ApiVersion: vi.APIVersion,
+	}
+}
+
+// getVersionMethod is answered regardless of the API version clients speak, so that they can find a matching client
+const getVersionMethod = "/v1.WerftService/GetVersion"
+
+// checkAPIVersion rejects requests of clients which speak another API version than we do and tells clients
+// which version we run. Clients which don't send their API version (e.g. the web UI or older CLIs) are accepted.
+func checkAPIVersion(ctx context.Context, method string, setHeader func(metadata.MD) error) error {
+	err := setHeader(metadata.Pairs(v1.APIVersionMetadataKey, v1.APIVersion, v1.VersionMetadataKey, version))
+	if err != nil {
+		return err
+	}
+	if method == getVersionMethod {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	vs := md.Get(v1.APIVersionMetadataKey)
+	if len(vs) == 0 || vs[0] == v1.APIVersion {
+		return nil
+	}
+
+	var client string
+	if cv := md.Get(v1.VersionMetadataKey); len(cv) > 0 {
+		client = " " + cv[0]
+	}
+	return status.Errorf(codes.FailedPrecondition, "client%s speaks API %s, but this werft server (%s) speaks API %s - run werft version --check to find a matching client", client, vs[0], version, v1.APIVersion)
+}
+
+// apiVersionUnaryInterceptor checks the API version of unary calls
+func apiVersionUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	err := checkAPIVersion(ctx, info.FullMethod, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) })
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// apiVersionStreamInterceptor checks the API version of streaming calls
+func apiVersionStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	err := checkAPIVersion(ss.Context(), info.FullMethod, ss.SetHeader)
+	if err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// handleVersion serves the version info as JSON, e.g. to debug mixed-version clients
+func handleVersion(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(getVersionInfo())
+}
 
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Prints the version of this binary",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(version)
+		vi := getVersionInfo()
+		fmt.Println(vi.Version)
+		fmt.Printf("commit:\t\t%s\nbuild date:\t%s\napi version:\t%s\ngo version:\t%s\n", vi.Commit, vi.BuildDate, vi.APIVersion, vi.GoVersion)
 	},
 }
 
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCheckAPIVersion(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Method   string
+		Metadata metadata.MD
+		Code     codes.Code
+	}{
+		{Name: "no metadata", Method: "/v1.WerftService/ListJobs"},
+		{Name: "no API version", Method: "/v1.WerftService/ListJobs", Metadata: metadata.Pairs("foo", "bar")},
+		{Name: "same API version", Method: "/v1.WerftService/ListJobs", Metadata: metadata.Pairs(v1.APIVersionMetadataKey, v1.APIVersion)},
+		{Name: "other API version", Method: "/v1.WerftService/ListJobs", Metadata: metadata.Pairs(v1.APIVersionMetadataKey, "v0", v1.VersionMetadataKey, "v0.0.1"), Code: codes.FailedPrecondition},
+		{Name: "other API version asking for the version", Method: getVersionMethod, Metadata: metadata.Pairs(v1.APIVersionMetadataKey, "v0")},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.Metadata != nil {
+				ctx = metadata.NewIncomingContext(ctx, test.Metadata)
+			}
+			var header metadata.MD
+			err := checkAPIVersion(ctx, test.Method, func(md metadata.MD) error {
+				header = md
+				return nil
+			})
+			if act := status.Code(err); act != test.Code {
+				t.Errorf("expected code %v, got %v", test.Code, err)
+			}
+			if act := header.Get(v1.APIVersionMetadataKey); len(act) != 1 || act[0] != v1.APIVersion {
+				t.Errorf("expected the server to tell its API version, got %v", header)
+			}
+			if act := header.Get(v1.VersionMetadataKey); len(act) != 1 || act[0] != version {
+				t.Errorf("expected the server to tell its version, got %v", header)
+			}
+		})
+	}
+}
@@ -9,28 +9,38 @@ require (
 	github.com/bradleyfalzon/ghinstallation v1.0.0
 	github.com/buildkite/terminal-to-html v3.2.0+incompatible
 	github.com/elazarl/goproxy v0.0.0-20191011121108-aa519ddbe484 // indirect
+	github.com/evanphx/json-patch v0.0.0-20190203023257-5858425f7550
 	github.com/gogo/protobuf v1.2.1
 	github.com/golang-migrate/migrate/v4 v4.7.1
-	github.com/golang/protobuf v1.3.2
+	github.com/golang/protobuf v1.5.2
 	github.com/google/go-github v17.0.0+incompatible
 	github.com/gorilla/websocket v1.4.1 // indirect
 	github.com/huandu/xstrings v1.2.1 // indirect
 	github.com/improbable-eng/grpc-web v0.11.0
+	github.com/json-iterator/go v1.1.8
 	github.com/lib/pq v1.2.0
 	github.com/mwitkow/go-conntrack v0.0.0-20190716064945-2f068394615f // indirect
 	github.com/olebedev/emitter v0.0.0-20190110104742-e8d1457e6aee
 	github.com/paulbellamy/ratecounter v0.2.0
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/prometheus/client_golang v1.3.0
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/rs/cors v1.7.0 // indirect
 	github.com/segmentio/textio v1.2.0
 	github.com/sirupsen/logrus v1.4.2
 	github.com/spf13/cobra v0.0.5
 	github.com/technosophos/moniker v0.0.0-20180509230615-a5dbd03a2245
-	golang.org/x/oauth2 v0.0.0-20191122200657-5d9234df094c
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
+	golang.org/x/net v0.0.0-20200822124328-c89045814202
+	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	golang.org/x/tools v0.0.0-20191219041853-979b82bfef62
-	golang.org/x/xerrors v0.0.0-20191011141410-1b5146add898
-	google.golang.org/grpc v1.25.1
-	gopkg.in/yaml.v3 v3.0.0-20191120175047-4206685974f2
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1
+	google.golang.org/grpc v1.40.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 	k8s.io/api v0.0.0-20190620084959-7cf5895f2711
 	k8s.io/apimachinery v0.0.0-20190612205821-1799e75a0719
 	k8s.io/client-go v0.0.0-20190620085101-78d2af792bab
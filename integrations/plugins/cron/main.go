@@ -4,19 +4,28 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	plugin "github.com/32leaves/werft/pkg/plugin/client"
 	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/32leaves/werft/pkg/schedule"
 	cron "github.com/robfig/cron/v3"
 	log "github.com/sirupsen/logrus"
 )
 
 // Config configures this plugin
 type Config struct {
+	// Timezone is the time zone of tasks which don't name one, e.g. Europe/Berlin. Defaults to the time zone of the host.
+	Timezone string `yaml:"timezone,omitempty"`
+
 	Tasks []struct {
+		// Name identifies the task in werft's list of schedules. Defaults to the task's index.
+		Name        string            `yaml:"name,omitempty"`
 		Spec        string            `yaml:"spec"`
+		Timezone    string            `yaml:"timezone,omitempty"`
 		Repo        string            `yaml:"repo"`
 		JobPath     string            `yaml:"jobPath,omitempty"`
 		Trigger     string            `yaml:"trigger,omitempty"`
@@ -38,14 +47,35 @@ func (*cronPlugin) Run(ctx context.Context, config interface{}, srv v1.WerftServ
 		return fmt.Errorf("config has wrong type %s", reflect.TypeOf(config))
 	}
 
-	c := cron.New()
+	defaultTimezone := cfg.Timezone
+	if defaultTimezone == "" {
+		defaultTimezone = time.Local.String()
+	}
+
+	var (
+		c         = cron.New()
+		schedules []*v1.Schedule
+	)
 	for idx, task := range cfg.Tasks {
+		task := task
 		repo, err := reporef.Parse(task.Repo)
 		if err != nil {
 			return err
 		}
+		name := task.Name
+		if name == "" {
+			name = strconv.Itoa(idx)
+		}
+		timezone := task.Timezone
+		if timezone == "" {
+			timezone = defaultTimezone
+		}
+		sched, err := schedule.Parse(task.Spec, timezone)
+		if err != nil {
+			return err
+		}
 
-		var trigger v1.JobTrigger
+		trigger := v1.JobTrigger_TRIGGER_SCHEDULED
 		if trg, ok := v1.JobTrigger_value[fmt.Sprintf("TRIGGER_%s", strings.ToUpper(task.Trigger))]; ok {
 			trigger = v1.JobTrigger(trg)
 		} else if task.Trigger != "" {
@@ -60,7 +90,7 @@ func (*cronPlugin) Run(ctx context.Context, config interface{}, srv v1.WerftServ
 			})
 		}
 
-		_, err = c.AddFunc(task.Spec, func() {
+		c.Schedule(sched, cron.FuncJob(func() {
 			_, err := srv.StartGitHubJob(ctx, &v1.StartGitHubJobRequest{
 				Metadata: &v1.JobMetadata{
 					Owner:       "cron",
@@ -71,14 +101,23 @@ func (*cronPlugin) Run(ctx context.Context, config interface{}, srv v1.WerftServ
 				JobPath: task.JobPath,
 			})
 			if err != nil {
-				log.WithError(err).WithField("idx", idx).WithField("spec", task.Spec).Error("cannot start job")
+				log.WithError(err).WithField("name", name).WithField("spec", task.Spec).Error("cannot start job")
 			}
+		}))
+		schedules = append(schedules, &v1.Schedule{
+			Name:       name,
+			Spec:       task.Spec,
+			Timezone:   timezone,
+			Repository: repo,
+			JobPath:    task.JobPath,
 		})
-		if err != nil {
-			return err
-		}
 
-		log.WithField("spec", task.Spec).Info("scheduled job")
+		log.WithField("spec", task.Spec).WithField("timezone", timezone).Info("scheduled job")
+	}
+
+	_, err := srv.RegisterSchedules(ctx, &v1.RegisterSchedulesRequest{Scheduler: "cron", Schedules: schedules})
+	if err != nil {
+		log.WithError(err).Warn("cannot register schedules with werft - they won't show in werft's list of schedules")
 	}
 	c.Run()
 
@@ -4,12 +4,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"html/template"
-	"io"
-	"net/http"
 	"reflect"
 	"sync"
-	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/filterexpr"
@@ -24,6 +20,10 @@ type Config struct {
 		Filter      []string `yaml:"filter"`
 		Template    string   `yaml:"template"`
 		ContentType string   `yaml:"contentType"`
+
+		// Type is the chat service the webhook belongs to: slack, mattermost, discord or teams. For those services
+		// the template produces the message text. Without a type the template produces the request body.
+		Type string `yaml:"type"`
 	} `yaml:"notifications"`
 }
 
@@ -48,13 +48,20 @@ func (*webhookPlugin) Run(ctx context.Context, config interface{}, srv v1.WerftS
 			log.WithError(err).Errorf("cannot parse filter for notification %d", idx)
 		}
 
-		tpl, err := template.New("tpl").Parse(nf.Template)
+		tpl, err := newTemplate(nf.Type, nf.Template)
 		if err != nil {
 			log.WithError(err).Errorf("cannot parse template for notification %d", idx)
+			continue
+		}
+
+		snd, err := newSender(nf.Type, nf.ContentType)
+		if err != nil {
+			log.WithError(err).Errorf("cannot set up notification %d", idx)
+			continue
 		}
 
 		wg.Add(1)
-		go func(idx int, url string, contentType string, tpl *template.Template) {
+		go func(idx int, url string, tpl renderer, snd sender) {
 			defer wg.Done()
 
 			sub, err := srv.Subscribe(ctx, &v1.SubscribeRequest{
@@ -80,40 +87,15 @@ func (*webhookPlugin) Run(ctx context.Context, config interface{}, srv v1.WerftS
 					continue
 				}
 
-				err = sendNotification(url, contentType, buf)
+				err = snd.Send(url, resp.Result, buf.Bytes())
 				if err != nil {
 					log.WithError(err).Warnf("send error with notification %d", idx)
 					continue
 				}
 			}
-		}(idx, nf.WebhookURL, nf.ContentType, tpl)
+		}(idx, nf.WebhookURL, tpl, snd)
 	}
 
 	wg.Wait()
 	return nil
 }
-
-// sendNotification will post text to a URL
-func sendNotification(webhookURL string, contentType string, body io.Reader) error {
-	log.WithField("url", webhookURL).Info("sending message")
-
-	req, err := http.NewRequest(http.MethodPost, webhookURL, body)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Add("Content-Type", contentType)
-
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	buf := new(bytes.Buffer)
-	buf.ReadFrom(resp.Body)
-	if buf.String() != "ok" {
-		return fmt.Errorf("non-ok response")
-	}
-	return nil
-}
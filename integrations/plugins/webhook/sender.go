@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultMessageTemplate is used by chat services if a notification has no template
+const defaultMessageTemplate = `{{ .Name }} {{ if .Conditions.Success }}succeeded{{ else }}failed{{ end }}` +
+	`{{ with .Metadata.Repository }} ({{ .Owner }}/{{ .Repo }}@{{ .Ref }}){{ end }}`
+
+// sender delivers notifications to a webhook
+type sender interface {
+	// Send posts a rendered notification about a job to a webhook
+	Send(webhookURL string, job *v1.JobStatus, msg []byte) error
+}
+
+// renderer is the part of html/template and text/template we need
+type renderer interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+// newSender produces the sender of a notification type. The empty type posts the rendered template as is.
+func newSender(tpe, contentType string) (sender, error) {
+	switch strings.ToLower(tpe) {
+	case "":
+		return &rawSender{ContentType: contentType}, nil
+	case "slack", "mattermost":
+		return &chatSender{Payload: func(job *v1.JobStatus, text string) interface{} {
+			return map[string]string{"text": text}
+		}}, nil
+	case "discord":
+		return &chatSender{Payload: func(job *v1.JobStatus, text string) interface{} {
+			return map[string]string{"content": text}
+		}}, nil
+	case "teams":
+		return &chatSender{Payload: teamsPayload}, nil
+	default:
+		return nil, fmt.Errorf("unknown notification type %q: must be one of slack, mattermost, discord or teams", tpe)
+	}
+}
+
+// newTemplate parses the template of a notification. Templates of chat services produce the message text,
+// which the sender escapes itself, and default to a short success/failure message.
+func newTemplate(tpe, tpl string) (renderer, error) {
+	if tpe == "" {
+		return htmltemplate.New("tpl").Parse(tpl)
+	}
+	if tpl == "" {
+		tpl = defaultMessageTemplate
+	}
+	return texttemplate.New("tpl").Parse(tpl)
+}
+
+// rawSender posts the rendered template as is, e.g. a JSON document built by the template
+type rawSender struct {
+	ContentType string
+}
+
+func (s *rawSender) Send(webhookURL string, job *v1.JobStatus, msg []byte) error {
+	body, err := post(webhookURL, s.ContentType, msg)
+	if err != nil {
+		return err
+	}
+	if string(body) != "ok" {
+		return fmt.Errorf("non-ok response")
+	}
+	return nil
+}
+
+// chatSender posts the rendered template as message to the incoming webhook of a chat service
+type chatSender struct {
+	Payload func(job *v1.JobStatus, text string) interface{}
+}
+
+func (s *chatSender) Send(webhookURL string, job *v1.JobStatus, msg []byte) error {
+	payload, err := json.Marshal(s.Payload(job, string(msg)))
+	if err != nil {
+		return err
+	}
+	_, err = post(webhookURL, "application/json", payload)
+	return err
+}
+
+// teamsPayload produces a Microsoft Teams message card which is colored by the outcome of the job
+func teamsPayload(job *v1.JobStatus, text string) interface{} {
+	color := "D9534F"
+	if job.GetConditions().GetSuccess() {
+		color = "5CB85C"
+	}
+	return map[string]string{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    job.GetName(),
+		"themeColor": color,
+		"text":       text,
+	}
+}
+
+// post sends a message to a URL and returns the response body. Responses other than 2xx are errors.
+func post(webhookURL, contentType string, msg []byte) ([]byte, error) {
+	log.WithField("url", webhookURL).Info("sending message")
+
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", contentType)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("webhook responded with %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+func TestSenders(t *testing.T) {
+	job := &v1.JobStatus{
+		Name: "werft-build.1",
+		Metadata: &v1.JobMetadata{
+			Repository: &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "master"},
+		},
+		Conditions: &v1.JobConditions{Success: true},
+	}
+	failed := &v1.JobStatus{Name: "werft-build.2", Metadata: &v1.JobMetadata{}, Conditions: &v1.JobConditions{}}
+
+	tests := []struct {
+		Name        string
+		Type        string
+		Template    string
+		Job         *v1.JobStatus
+		Response    string
+		Status      int
+		ContentType string
+		Expected    map[string]string
+		Raw         string
+		Error       string
+	}{
+		{
+			Name:     "slack",
+			Type:     "slack",
+			Job:      job,
+			Expected: map[string]string{"text": "werft-build.1 succeeded (32leaves/werft@master)"},
+		},
+		{
+			Name:     "mattermost with template",
+			Type:     "Mattermost",
+			Template: `{{ .Name }} <done> "quoted"`,
+			Job:      job,
+			Expected: map[string]string{"text": `werft-build.1 <done> "quoted"`},
+		},
+		{
+			Name:     "discord",
+			Type:     "discord",
+			Job:      failed,
+			Expected: map[string]string{"content": "werft-build.2 failed"},
+		},
+		{
+			Name: "teams success",
+			Type: "teams",
+			Job:  job,
+			Expected: map[string]string{
+				"@type":      "MessageCard",
+				"@context":   "https://schema.org/extensions",
+				"summary":    "werft-build.1",
+				"themeColor": "5CB85C",
+				"text":       "werft-build.1 succeeded (32leaves/werft@master)",
+			},
+		},
+		{
+			Name: "teams failure",
+			Type: "teams",
+			Job:  failed,
+			Expected: map[string]string{
+				"@type":      "MessageCard",
+				"@context":   "https://schema.org/extensions",
+				"summary":    "werft-build.2",
+				"themeColor": "D9534F",
+				"text":       "werft-build.2 failed",
+			},
+		},
+		{
+			Name:        "raw",
+			Template:    `{"job": "{{ .Name }}"}`,
+			ContentType: "text/plain",
+			Job:         job,
+			Response:    "ok",
+			Raw:         `{"job": "werft-build.1"}`,
+		},
+		{
+			Name:     "raw non-ok response",
+			Template: "{{ .Name }}",
+			Job:      job,
+			Response: "nope",
+			Raw:      "werft-build.1",
+			Error:    "non-ok response",
+		},
+		{
+			Name:     "chat service error",
+			Type:     "slack",
+			Job:      job,
+			Status:   http.StatusForbidden,
+			Response: "invalid_token\n",
+			Expected: map[string]string{"text": "werft-build.1 succeeded (32leaves/werft@master)"},
+			Error:    "webhook responded with 403 Forbidden: invalid_token",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var (
+				body        []byte
+				contentType string
+			)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = ioutil.ReadAll(r.Body)
+				contentType = r.Header.Get("Content-Type")
+				if test.Status != 0 {
+					w.WriteHeader(test.Status)
+				}
+				w.Write([]byte(test.Response))
+			}))
+			defer srv.Close()
+
+			snd, err := newSender(test.Type, test.ContentType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tpl, err := newTemplate(test.Type, test.Template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var msg bytes.Buffer
+			err = tpl.Execute(&msg, test.Job)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = snd.Send(srv.URL, test.Job, msg.Bytes())
+			if test.Error == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if test.Error != "" && (err == nil || !strings.Contains(err.Error(), test.Error)) {
+				t.Errorf("expected error %q, got %v", test.Error, err)
+			}
+
+			if test.Raw != "" {
+				if string(body) != test.Raw {
+					t.Errorf("expected body %q, got %q", test.Raw, string(body))
+				}
+				if contentType != test.ContentType {
+					t.Errorf("expected content type %q, got %q", test.ContentType, contentType)
+				}
+				return
+			}
+			if contentType != "application/json" {
+				t.Errorf("expected JSON, got %q", contentType)
+			}
+			var act map[string]string
+			err = json.Unmarshal(body, &act)
+			if err != nil {
+				t.Fatalf("invalid payload %q: %v", string(body), err)
+			}
+			if len(act) != len(test.Expected) {
+				t.Errorf("expected %v, got %v", test.Expected, act)
+			}
+			for k, v := range test.Expected {
+				if act[k] != v {
+					t.Errorf("expected %s to be %q, got %q", k, v, act[k])
+				}
+			}
+		})
+	}
+}
+
+func TestNewSenderUnknownType(t *testing.T) {
+	_, err := newSender("irc", "")
+	if err == nil || !strings.Contains(err.Error(), "unknown notification type") {
+		t.Errorf("expected unknown notification type, got %v", err)
+	}
+}
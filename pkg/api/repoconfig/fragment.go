@@ -0,0 +1,336 @@
+package repoconfig
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// MaxFragments is the number of fragments a job spec can pull in, including the fragments of fragments
+const MaxFragments = 32
+
+// FragmentRef points to a job spec fragment which a job spec extends or includes
+type FragmentRef struct {
+	// Host, Owner and Repo identify the repository of the fragment. They're empty for fragments in the
+	// repository of the job spec which refers to them.
+	Host  string
+	Owner string
+	Repo  string
+
+	// Path is the path of the fragment relative to the root of its repository
+	Path string
+
+	// Rev is the branch, tag or commit of the fragment's repository. Empty means the default branch.
+	Rev string
+}
+
+// ParseFragmentRef parses a fragment reference. Fragments in the same repository are referred to by their path,
+// e.g. .werft/base.yaml. Fragments in other repositories are referred to as [host/]owner/repo:path[@rev],
+// e.g. 32leaves/ci-config:jobs/base.yaml@main.
+func ParseFragmentRef(ref string) (FragmentRef, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return FragmentRef{}, xerrors.Errorf("fragment reference is empty")
+	}
+	if strings.Contains(ref, "{{") {
+		return FragmentRef{}, xerrors.Errorf("fragment reference %s must not be a template", ref)
+	}
+
+	segs := strings.SplitN(ref, ":", 2)
+	if len(segs) == 1 {
+		return FragmentRef{Path: strings.TrimPrefix(ref, "/")}, nil
+	}
+
+	var res FragmentRef
+	repo := strings.Split(segs[0], "/")
+	switch len(repo) {
+	case 2:
+		res.Owner, res.Repo = repo[0], repo[1]
+	case 3:
+		res.Host, res.Owner, res.Repo = repo[0], repo[1], repo[2]
+	default:
+		return FragmentRef{}, xerrors.Errorf("invalid fragment reference %s: repository must be [host/]owner/repo", ref)
+	}
+	res.Path = segs[1]
+	if idx := strings.LastIndex(res.Path, "@"); idx >= 0 {
+		res.Path, res.Rev = res.Path[:idx], res.Path[idx+1:]
+	}
+	res.Path = strings.TrimPrefix(res.Path, "/")
+	if res.Owner == "" || res.Repo == "" || res.Path == "" {
+		return FragmentRef{}, xerrors.Errorf("invalid fragment reference %s: must be [host/]owner/repo:path[@rev]", ref)
+	}
+	return res, nil
+}
+
+// String produces the reference ParseFragmentRef parses
+func (r FragmentRef) String() string {
+	if r.Repo == "" {
+		return r.Path
+	}
+
+	res := fmt.Sprintf("%s/%s:%s", r.Owner, r.Repo, r.Path)
+	if r.Host != "" {
+		res = r.Host + "/" + res
+	}
+	if r.Rev != "" {
+		res += "@" + r.Rev
+	}
+	return res
+}
+
+// FragmentRefs returns the fragments a job spec extends and includes, the one it extends first.
+// Job specs are templates which aren't necessarily valid YAML before they're rendered, hence we look
+// for the top-level extends and include fields line by line. Their values must not be templates.
+func FragmentRefs(jobYAML []byte) ([]FragmentRef, error) {
+	var (
+		extends  []string
+		includes []string
+		lines    = strings.Split(string(jobYAML), "\n")
+	)
+	for i := 0; i < len(lines); i++ {
+		line := stripComment(lines[i])
+		switch {
+		case strings.HasPrefix(line, "extends:"):
+			if len(extends) > 0 {
+				return nil, xerrors.Errorf("line %d: a job spec can extend only one fragment", i+1)
+			}
+			val := unquote(strings.TrimSpace(strings.TrimPrefix(line, "extends:")))
+			if val == "" {
+				return nil, xerrors.Errorf("line %d: extends must name a fragment", i+1)
+			}
+			extends = append(extends, val)
+		case strings.HasPrefix(line, "include:"):
+			val := strings.TrimSpace(strings.TrimPrefix(line, "include:"))
+			if strings.HasPrefix(val, "[") && strings.HasSuffix(val, "]") {
+				for _, v := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(val, "["), "]"), ",") {
+					if v = unquote(strings.TrimSpace(v)); v != "" {
+						includes = append(includes, v)
+					}
+				}
+				continue
+			}
+			if val != "" {
+				includes = append(includes, unquote(val))
+				continue
+			}
+
+			// block sequences can be indented or start at the beginning of the line
+			for ; i+1 < len(lines); i++ {
+				next := stripComment(lines[i+1])
+				item := strings.TrimSpace(next)
+				if item == "" {
+					continue
+				}
+				if !strings.HasPrefix(item, "-") || !(strings.HasPrefix(next, " ") || strings.HasPrefix(next, "-")) {
+					break
+				}
+				includes = append(includes, unquote(strings.TrimSpace(strings.TrimPrefix(item, "-"))))
+			}
+		}
+	}
+
+	var res []FragmentRef
+	for _, r := range append(extends, includes...) {
+		ref, err := ParseFragmentRef(r)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, ref)
+	}
+	return res, nil
+}
+
+func stripComment(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if strings.HasPrefix(line, "#") {
+		return ""
+	}
+	if idx := strings.Index(line, " #"); idx >= 0 {
+		return strings.TrimRight(line[:idx], " ")
+	}
+	return line
+}
+
+func unquote(val string) string {
+	if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+		return val[1 : len(val)-1]
+	}
+	return val
+}
+
+// FragmentFetcher downloads a job spec fragment
+type FragmentFetcher func(ctx context.Context, ref FragmentRef) ([]byte, error)
+
+// ResolveFragments fetches all fragments a job spec extends or includes, and the fragments those refer to. It returns
+// a job spec which consists of one YAML document per fragment, in the order they are merged in, followed by the job
+// spec itself. Job specs which don't refer to any fragments are returned as they are. Relative references in fragments
+// of another repository refer to that repository. The path of the job spec is used to detect cycles and can be empty.
+func ResolveFragments(ctx context.Context, path string, jobYAML []byte, fetch FragmentFetcher) ([]byte, error) {
+	var stack []string
+	if path != "" {
+		stack = append(stack, path)
+	}
+	var count int
+	docs, err := resolveFragments(ctx, FragmentRef{}, jobYAML, fetch, stack, &count)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 1 {
+		return jobYAML, nil
+	}
+	return JoinDocuments(docs), nil
+}
+
+func resolveFragments(ctx context.Context, origin FragmentRef, content []byte, fetch FragmentFetcher, stack []string, count *int) ([][]byte, error) {
+	refs, err := FragmentRefs(content)
+	if err != nil {
+		return nil, err
+	}
+
+	var res [][]byte
+	for _, ref := range refs {
+		if ref.Repo == "" {
+			ref.Host, ref.Owner, ref.Repo, ref.Rev = origin.Host, origin.Owner, origin.Repo, origin.Rev
+		}
+		key := ref.String()
+		for _, s := range stack {
+			if s == key {
+				return nil, xerrors.Errorf("job spec fragments form a cycle: %s -> %s", strings.Join(stack, " -> "), key)
+			}
+		}
+		*count++
+		if *count > MaxFragments {
+			return nil, xerrors.Errorf("job spec refers to more than %d fragments", MaxFragments)
+		}
+
+		fc, err := fetch(ctx, ref)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot fetch job spec fragment %s: %w", key, err)
+		}
+		docs, err := resolveFragments(ctx, ref, fc, fetch, append(stack[:len(stack):len(stack)], key), count)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, docs...)
+	}
+	return append(res, content), nil
+}
+
+// SplitDocuments splits a YAML stream into its documents. Empty documents are skipped.
+func SplitDocuments(content []byte) [][]byte {
+	var (
+		res [][]byte
+		doc bytes.Buffer
+	)
+	flush := func() {
+		if len(bytes.TrimSpace(doc.Bytes())) > 0 {
+			res = append(res, append([]byte(nil), doc.Bytes()...))
+		}
+		doc.Reset()
+	}
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		if strings.TrimRight(line, " \r\n") == "---" {
+			flush()
+			continue
+		}
+		doc.WriteString(line)
+	}
+	flush()
+	return res
+}
+
+// JoinDocuments joins YAML documents into a single YAML stream
+func JoinDocuments(docs [][]byte) []byte {
+	var res bytes.Buffer
+	for i, d := range docs {
+		if i > 0 {
+			res.WriteString("---\n")
+		}
+		res.Write(d)
+		if !bytes.HasSuffix(d, []byte("\n")) {
+			res.WriteString("\n")
+		}
+	}
+	return res.Bytes()
+}
+
+// MergeDocuments merges rendered job spec documents into one, later documents overriding earlier ones.
+// Maps are merged field by field. Lists of named items, e.g. containers or env vars, are merged by name.
+// All other lists are replaced. The extends and include fields are dropped from the result.
+func MergeDocuments(docs [][]byte) ([]byte, error) {
+	var res interface{}
+	for i, d := range docs {
+		var doc interface{}
+		err := yaml.Unmarshal(d, &doc)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid job spec fragment %d: %w", i, err)
+		}
+		if doc == nil {
+			continue
+		}
+		if _, ok := doc.(map[string]interface{}); !ok {
+			return nil, xerrors.Errorf("invalid job spec fragment %d: must be a map", i)
+		}
+		res = mergeValues(res, doc)
+	}
+	if m, ok := res.(map[string]interface{}); ok {
+		delete(m, "extends")
+		delete(m, "include")
+	}
+	return yaml.Marshal(res)
+}
+
+func mergeValues(dst, src interface{}) interface{} {
+	switch s := src.(type) {
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			return s
+		}
+		for k, v := range s {
+			d[k] = mergeValues(d[k], v)
+		}
+		return d
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok || !namedItems(d) || !namedItems(s) {
+			return s
+		}
+		for _, item := range s {
+			name := item.(map[string]interface{})["name"]
+			var found bool
+			for i, existing := range d {
+				if existing.(map[string]interface{})["name"] == name {
+					d[i] = mergeValues(existing, item)
+					found = true
+					break
+				}
+			}
+			if !found {
+				d = append(d, item)
+			}
+		}
+		return d
+	default:
+		return src
+	}
+}
+
+// namedItems returns true if all items of a list are maps with a name
+func namedItems(l []interface{}) bool {
+	for _, item := range l {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		switch m["name"].(type) {
+		case nil, map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,275 @@
+package repoconfig_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseFragmentRef(t *testing.T) {
+	tests := []struct {
+		Ref         string
+		Expectation repoconfig.FragmentRef
+		Error       bool
+	}{
+		{".werft/base.yaml", repoconfig.FragmentRef{Path: ".werft/base.yaml"}, false},
+		{"32leaves/ci-config:jobs/base.yaml", repoconfig.FragmentRef{Owner: "32leaves", Repo: "ci-config", Path: "jobs/base.yaml"}, false},
+		{"github.com/32leaves/ci-config:/jobs/base.yaml@v1", repoconfig.FragmentRef{Host: "github.com", Owner: "32leaves", Repo: "ci-config", Path: "jobs/base.yaml", Rev: "v1"}, false},
+		{"ci-config:jobs/base.yaml", repoconfig.FragmentRef{}, true},
+		{"32leaves/ci-config:", repoconfig.FragmentRef{}, true},
+		{"{{ .Annotations.base }}", repoconfig.FragmentRef{}, true},
+		{"", repoconfig.FragmentRef{}, true},
+	}
+	for _, test := range tests {
+		act, err := repoconfig.ParseFragmentRef(test.Ref)
+		if (err != nil) != test.Error {
+			t.Errorf("%s: unexpected error: %v", test.Ref, err)
+			continue
+		}
+		if act != test.Expectation {
+			t.Errorf("%s: expected %+v, got %+v", test.Ref, test.Expectation, act)
+		}
+		if err == nil && strings.TrimPrefix(strings.Replace(test.Ref, ":/", ":", 1), "/") != act.String() {
+			t.Errorf("%s: does not round-trip: %s", test.Ref, act.String())
+		}
+	}
+}
+
+func TestFragmentRefs(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Spec        string
+		Expectation []string
+		Error       bool
+	}{
+		{"none", "pod:\n  containers: []\n", nil, false},
+		{"extends", "extends: \"base.yaml\" # the base\npod: {}\n", []string{"base.yaml"}, false},
+		{"flow list", "extends: base.yaml\ninclude: [a.yaml, 'o/r:b.yaml@main']\n", []string{"base.yaml", "a.yaml", "o/r:b.yaml@main"}, false},
+		{"block list", "include:\n  - a.yaml\n\n  # comment\n  - b.yaml\npod:\n  containers:\n  - name: foo\n", []string{"a.yaml", "b.yaml"}, false},
+		{"unindented block list", "include:\n- a.yaml\n- b.yaml\npod: {}\n", []string{"a.yaml", "b.yaml"}, false},
+		{"extends is last", "include: [a.yaml]\nextends: base.yaml\n", []string{"base.yaml", "a.yaml"}, false},
+		{"nested fields", "pod:\n  extends: foo\n  include: [bar]\n", nil, false},
+		{"template", "pod:\n  containers:\n  {{- range .Annotations }}\n  {{- end }}\n", nil, false},
+		{"extends twice", "extends: a.yaml\nextends: b.yaml\n", nil, true},
+		{"templated ref", "extends: \"{{ .Annotations.base }}\"\n", nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			refs, err := repoconfig.FragmentRefs([]byte(test.Spec))
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var act []string
+			for _, r := range refs {
+				act = append(act, r.String())
+			}
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestResolveFragments(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Files       map[string]string
+		Spec        string
+		Expectation []string
+		Error       string
+	}{
+		{
+			Name:        "no fragments",
+			Spec:        "pod: {}\n",
+			Expectation: []string{"pod: {}\n"},
+		},
+		{
+			Name: "extends and includes",
+			Files: map[string]string{
+				"base.yaml":                      "include: [o/r:shared.yaml@v1]\nbase: true\n",
+				"o/r:shared.yaml@v1":             "include: [more.yaml]\nshared: true\n",
+				"o/r:more.yaml@v1":               "more: true\n",
+				"extra.yaml":                     "extra: true\n",
+				"github.com/o/r:unused.yaml@foo": "unused: true\n",
+			},
+			Spec: "extends: base.yaml\ninclude: [extra.yaml]\npod: {}\n",
+			Expectation: []string{
+				"more: true\n",
+				"include: [more.yaml]\nshared: true\n",
+				"include: [o/r:shared.yaml@v1]\nbase: true\n",
+				"extra: true\n",
+				"extends: base.yaml\ninclude: [extra.yaml]\npod: {}\n",
+			},
+		},
+		{
+			Name: "cycle",
+			Files: map[string]string{
+				"a.yaml": "extends: b.yaml\n",
+				"b.yaml": "extends: a.yaml\n",
+			},
+			Spec:  "extends: a.yaml\n",
+			Error: "job spec fragments form a cycle: .werft/job.yaml -> a.yaml -> b.yaml -> a.yaml",
+		},
+		{
+			Name:  "cycle to the job spec",
+			Files: map[string]string{"a.yaml": "extends: .werft/job.yaml\n"},
+			Spec:  "extends: a.yaml\n",
+			Error: "job spec fragments form a cycle: .werft/job.yaml -> a.yaml -> .werft/job.yaml",
+		},
+		{
+			Name:  "missing fragment",
+			Spec:  "extends: a.yaml\n",
+			Error: "cannot fetch job spec fragment a.yaml: not found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			fetch := func(ctx context.Context, ref repoconfig.FragmentRef) ([]byte, error) {
+				c, ok := test.Files[ref.String()]
+				if !ok {
+					return nil, fmt.Errorf("not found")
+				}
+				return []byte(c), nil
+			}
+			act, err := repoconfig.ResolveFragments(context.Background(), ".werft/job.yaml", []byte(test.Spec), fetch)
+			if test.Error != "" {
+				if err == nil || err.Error() != test.Error {
+					t.Fatalf("expected error %q, got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var docs []string
+			for _, d := range repoconfig.SplitDocuments(act) {
+				docs = append(docs, string(d))
+			}
+			if !reflect.DeepEqual(docs, test.Expectation) {
+				t.Errorf("expected %q, got %q", test.Expectation, docs)
+			}
+		})
+	}
+}
+
+func TestResolveFragmentsLimit(t *testing.T) {
+	fetch := func(ctx context.Context, ref repoconfig.FragmentRef) ([]byte, error) {
+		var n int
+		fmt.Sscanf(ref.Path, "%d.yaml", &n)
+		return []byte(fmt.Sprintf("extends: %d.yaml\n", n+1)), nil
+	}
+	_, err := repoconfig.ResolveFragments(context.Background(), "", []byte("extends: 0.yaml\n"), fetch)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestMergeDocuments(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Docs        []string
+		Expectation string
+	}{
+		{
+			Name: "maps and named lists",
+			Docs: []string{
+				`
+labels:
+  team: infra
+pod:
+  serviceAccount: builder
+  volumes:
+  - name: cache
+    emptyDir: {}
+  containers:
+  - name: build
+    image: golang:1.13
+    env:
+    - name: GOPROXY
+      value: https://proxy.golang.org
+    command: ["sh", "-c", "make"]
+`,
+				`
+extends: base.yaml
+include: [other.yaml]
+labels:
+  stage: test
+pod:
+  containers:
+  - name: build
+    env:
+    - name: CGO_ENABLED
+      value: "0"
+    command: ["go", "test"]
+  - name: sidecar
+    image: redis
+`,
+			},
+			Expectation: `
+labels:
+  team: infra
+  stage: test
+pod:
+  serviceAccount: builder
+  volumes:
+  - name: cache
+    emptyDir: {}
+  containers:
+  - name: build
+    image: golang:1.13
+    env:
+    - name: GOPROXY
+      value: https://proxy.golang.org
+    - name: CGO_ENABLED
+      value: "0"
+    command: ["go", "test"]
+  - name: sidecar
+    image: redis
+`,
+		},
+		{
+			Name:        "empty documents",
+			Docs:        []string{"", "pod: {}", "# nothing\n"},
+			Expectation: "pod: {}",
+		},
+		{
+			Name:        "replaced values",
+			Docs:        []string{"a: [1, 2]\nb: {c: d}\n", "a: [3]\nb: e\n"},
+			Expectation: "a: [3]\nb: e\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var docs [][]byte
+			for _, d := range test.Docs {
+				docs = append(docs, []byte(d))
+			}
+			res, err := repoconfig.MergeDocuments(docs)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var act, exp interface{}
+			if err := yaml.Unmarshal(res, &act); err != nil {
+				t.Fatal(err)
+			}
+			if err := yaml.Unmarshal([]byte(test.Expectation), &exp); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(act, exp) {
+				t.Errorf("expected %v, got %v", exp, act)
+			}
+		})
+	}
+
+	_, err := repoconfig.MergeDocuments([][]byte{[]byte("- not a map")})
+	if err == nil {
+		t.Error("expected an error for documents which aren't maps")
+	}
+}
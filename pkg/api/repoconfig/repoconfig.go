@@ -1,8 +1,16 @@
 package repoconfig
 
 import (
+	"fmt"
+	"hash/fnv"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
 	werftv1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/filterexpr"
+	"golang.org/x/xerrors"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -10,19 +18,73 @@ import (
 type C struct {
 	DefaultJob string          `yaml:"defaultJob"`
 	Rules      []*JobStartRule `yaml:"rules"`
+
+	// Jobs lists job files which are started whenever their rule matches, in addition to the job chosen by
+	// DefaultJob and Rules. Unlike Rules, all matching jobs are started, e.g. build and e2e tests on push.
+	Jobs []*JobStartRule `yaml:"jobs,omitempty" json:",omitempty"`
+
+	// PullRequests starts jobs when pull requests are opened or updated, in addition to the jobs started by pushing
+	// to their branch. Use the trigger (e.g. trigger==pull_request) in rules to tell them apart.
+	PullRequests bool `yaml:"pullRequests,omitempty"`
+
+	// MergeGroups starts jobs when a GitHub merge queue needs checks for a merge group. Pushes to the merge queue's
+	// branches no longer start jobs then. Use the trigger (e.g. trigger==merge_group) in rules to tell them apart.
+	MergeGroups bool `yaml:"mergeGroups,omitempty" json:",omitempty"`
+
+	// LabelJobs maps pull request labels to the job files which are started whenever the label is added to a pull
+	// request, e.g. needs-e2e: [.werft/e2e.yaml]. Adding the label again re-runs the jobs.
+	LabelJobs map[string][]string `yaml:"labelJobs,omitempty" json:",omitempty"`
+
+	// StatusChecks configures the GitHub status checks the repository's jobs report
+	StatusChecks *StatusChecks `yaml:"statusChecks,omitempty" json:",omitempty"`
+
+	// Annotations are added to all jobs of the repository which don't have an annotation of the same name already
+	Annotations map[string]string `yaml:"annotations,omitempty" json:",omitempty"`
+
+	// ResultChannels are the channels results of the repository's jobs are published to if a job does not name any,
+	// e.g. slack
+	ResultChannels []string `yaml:"resultChannels,omitempty" json:",omitempty"`
+}
+
+// StatusChecks configures the GitHub status checks the jobs of a repository report
+type StatusChecks struct {
+	// PerJob reports the status of each job file under its own context, e.g. continuous-integration/werft/build
+	// for .werft/build.yaml, rather than the status of all jobs under continuous-integration/werft.
+	PerJob bool `yaml:"perJob,omitempty"`
+
+	// SyncRequired lists protected branches whose required status checks werft keeps in sync with the jobs the repo
+	// config starts whenever someone pushes to them, e.g. master. Status checks of other CI systems are left alone.
+	SyncRequired []string `yaml:"syncRequired,omitempty"`
+
+	// NotRequired lists job files which never become required status checks, e.g. .werft/nightly.yaml.
+	// Jobs started by pull request labels are never required.
+	NotRequired []string `yaml:"notRequired,omitempty"`
 }
 
-// JobStartRule determines if a job will be started
+// JobStartRule determines if a job will be started. All conditions of a rule have to match.
 type JobStartRule struct {
 	Path string                      `yaml:"path"`
 	Expr []*werftv1.FilterExpression `yaml:"matchesAll"`
+
+	// Triggers limits the rule to jobs started by one of these triggers, e.g. push or pull_request
+	Triggers []string `yaml:"triggers,omitempty" json:",omitempty"`
+
+	// Refs limits the rule to refs matching one of these patterns, e.g. refs/heads/release/*
+	Refs []string `yaml:"refs,omitempty" json:",omitempty"`
+
+	// ChangedPaths limits the rule to changes of files matching one of these patterns, e.g. go.mod or pkg/**.
+	// Patterns ending in /** match all files below a directory.
+	ChangedPaths []string `yaml:"changedPaths,omitempty" json:",omitempty"`
 }
 
 // UnmarshalYAML unmarshals the filter expressions
 func (r *JobStartRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var rawJobStartRule struct {
-		Path string           `yaml:"path"`
-		Expr []JobStartRuleOr `yaml:"matchesAll"`
+		Path         string           `yaml:"path"`
+		Expr         []JobStartRuleOr `yaml:"matchesAll"`
+		Triggers     []string         `yaml:"triggers"`
+		Refs         []string         `yaml:"refs"`
+		ChangedPaths []string         `yaml:"changedPaths"`
 	}
 	err := unmarshal(&rawJobStartRule)
 	if err != nil {
@@ -38,6 +100,21 @@ func (r *JobStartRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
 		r.Expr = append(r.Expr, &werftv1.FilterExpression{Terms: terms})
 	}
 
+	for _, t := range rawJobStartRule.Triggers {
+		trigger, ok := werftv1.JobTrigger_value["TRIGGER_"+strings.ToUpper(t)]
+		if !ok || trigger == int32(werftv1.JobTrigger_TRIGGER_UNKNOWN) {
+			return xerrors.Errorf("unknown trigger %s", t)
+		}
+	}
+	for _, p := range append(rawJobStartRule.Refs, rawJobStartRule.ChangedPaths...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return xerrors.Errorf("invalid pattern %s: %w", p, err)
+		}
+	}
+	r.Triggers = rawJobStartRule.Triggers
+	r.Refs = rawJobStartRule.Refs
+	r.ChangedPaths = rawJobStartRule.ChangedPaths
+
 	return nil
 }
 
@@ -46,11 +123,84 @@ type JobStartRuleOr struct {
 	Or []string `yaml:"or"`
 }
 
+// Matches returns true if a job with the metadata and changed files matches the rule.
+// If changed is nil, i.e. we don't know which files changed, conditions on the changed files are met.
+func (r *JobStartRule) Matches(md *werftv1.JobMetadata, changed []string) bool {
+	if !filterexpr.MatchesFilter(&werftv1.JobStatus{Metadata: md}, r.Expr) {
+		return false
+	}
+
+	if len(r.Triggers) > 0 {
+		var found bool
+		for _, t := range r.Triggers {
+			if t == TriggerName(md.Trigger) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.Refs) > 0 {
+		var found bool
+		for _, p := range r.Refs {
+			if m, _ := path.Match(p, md.GetRepository().GetRef()); m {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.ChangedPaths) > 0 && changed != nil {
+		var found bool
+		for _, p := range r.ChangedPaths {
+			for _, f := range changed {
+				if pathMatches(p, f) {
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// pathMatches returns true if a file matches a changed paths pattern
+func pathMatches(pattern, file string) bool {
+	if strings.HasSuffix(pattern, "/**") {
+		dir := strings.TrimSuffix(pattern, "/**")
+		for p := path.Dir(file); p != "." && p != "/"; p = path.Dir(p) {
+			if m, _ := path.Match(dir, p); m {
+				return true
+			}
+		}
+		return false
+	}
+
+	m, _ := path.Match(pattern, file)
+	return m
+}
+
 // TemplatePath returns the path to the job template in the repo
 func (rc *C) TemplatePath(md *werftv1.JobMetadata) string {
-	js := &werftv1.JobStatus{Metadata: md}
+	return rc.templatePath(md, nil)
+}
+
+func (rc *C) templatePath(md *werftv1.JobMetadata, changed []string) string {
 	for _, rule := range rc.Rules {
-		if filterexpr.MatchesFilter(js, rule.Expr) {
+		if rule.Matches(md, changed) {
 			return rule.Path
 		}
 	}
@@ -58,9 +208,136 @@ func (rc *C) TemplatePath(md *werftv1.JobMetadata) string {
 	return rc.DefaultJob
 }
 
+// TemplatePaths returns the paths to all job templates in the repo which are to be started, i.e. the one chosen by
+// the rules and all matching jobs. If changed is nil, conditions on the changed files are met.
+func (rc *C) TemplatePaths(md *werftv1.JobMetadata, changed []string) []string {
+	var res []string
+	if p := rc.templatePath(md, changed); p != "" {
+		res = append(res, p)
+	}
+	for _, job := range rc.Jobs {
+		if job.Path == "" || !job.Matches(md, changed) {
+			continue
+		}
+
+		var dup bool
+		for _, p := range res {
+			if p == job.Path {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			res = append(res, job.Path)
+		}
+	}
+	return res
+}
+
+// NeedsChangedPaths returns true if any rule depends on the files a change touched
+func (rc *C) NeedsChangedPaths() bool {
+	for _, rules := range [][]*JobStartRule{rc.Rules, rc.Jobs} {
+		for _, r := range rules {
+			if len(r.ChangedPaths) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// JobPaths returns the paths of all job files the repo config refers to, in the order they appear
+func (rc *C) JobPaths() []string {
+	var (
+		res  []string
+		seen = make(map[string]struct{})
+	)
+	add := func(p string) {
+		if _, ok := seen[p]; ok || p == "" {
+			return
+		}
+		seen[p] = struct{}{}
+		res = append(res, p)
+	}
+
+	add(rc.DefaultJob)
+	for _, r := range rc.Rules {
+		add(r.Path)
+	}
+	for _, j := range rc.Jobs {
+		add(j.Path)
+	}
+	labels := make([]string, 0, len(rc.LabelJobs))
+	for l := range rc.LabelJobs {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	for _, l := range labels {
+		for _, p := range rc.LabelJobs[l] {
+			add(p)
+		}
+	}
+	return res
+}
+
+// LabelJobPaths returns the paths of the job files which are started when a label is added to a pull request
+func (rc *C) LabelJobPaths(label string) []string {
+	var res []string
+	for _, p := range rc.LabelJobs[label] {
+		if p != "" {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// RequiredJobPaths returns the paths of the job files whose status checks are to be required, in the order they appear
+func (rc *C) RequiredJobPaths() []string {
+	skip := make(map[string]struct{})
+	if rc.StatusChecks != nil {
+		for _, p := range rc.StatusChecks.NotRequired {
+			skip[p] = struct{}{}
+		}
+	}
+	for _, ps := range rc.LabelJobs {
+		for _, p := range ps {
+			skip[p] = struct{}{}
+		}
+	}
+
+	var res []string
+	for _, p := range rc.JobPaths() {
+		if _, ok := skip[p]; ok {
+			continue
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+// ApplyAnnotations adds the default annotations of the repo config to a job, unless the job has them already
+func (rc *C) ApplyAnnotations(md *werftv1.JobMetadata) {
+	keys := make([]string, 0, len(rc.Annotations))
+	for k := range rc.Annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	present := make(map[string]struct{}, len(md.Annotations))
+	for _, a := range md.Annotations {
+		present[a.Key] = struct{}{}
+	}
+	for _, k := range keys {
+		if _, ok := present[k]; ok {
+			continue
+		}
+		md.Annotations = append(md.Annotations, &werftv1.Annotation{Key: k, Value: rc.Annotations[k]})
+	}
+}
+
 // ShouldRun determines based on the repo config if the job should run
 func (rc *C) ShouldRun(md *werftv1.JobMetadata) bool {
-	return rc.TemplatePath(md) != ""
+	return len(rc.TemplatePaths(md, nil)) > 0
 }
 
 // JobSpec is the format of the files we expect to find when starting jobs
@@ -68,15 +345,68 @@ type JobSpec struct {
 	// Desc describes the purpose of this job spec.
 	Desc string `yaml:"description,omitempty"`
 
+	// Extends names a job spec fragment this job spec is based on, e.g. .werft/base.yaml or a fragment in another
+	// repository like 32leaves/ci-config:jobs/base.yaml@main. This job spec overrides the fields of the fragment.
+	Extends string `yaml:"extends,omitempty"`
+
+	// Include names job spec fragments which are merged into this job spec, after the one it extends.
+	// Later fragments override earlier ones, and this job spec overrides them all.
+	Include []string `yaml:"include,omitempty"`
+
 	// Pod is the actual job spec to start. Prior to deploying this to Kubernetes, we'll run this
 	// as a Go template.
 	Pod *corev1.PodSpec `yaml:"pod"`
 
+	// Triggers replace the pod spec for jobs started by a particular trigger, e.g. to deploy on push but only test
+	// pull requests. Triggers are named like in filter expressions, e.g. push, tag or pull_request.
+	Triggers map[string]*TriggerSpec `yaml:"triggers,omitempty"`
+
+	// When runs containers of the pod only if a condition holds for the job, keyed by container name. Conditions are
+	// expressions over the job's fields (see filterexpr.Expression), e.g. annotation.publish == "true" or
+	// trigger in ["push", "tag"], so that one job spec can validate pull requests and publish releases.
+	// Containers without a condition always run.
+	When map[string]string `yaml:"when,omitempty"`
+
 	// Mutex makes job execution exclusive, with new ones canceling the currently running one.
 	// For example: job A is running at the moment, and job B is about to start. If A and B share the
 	// same mutex, B will cancel A.
 	Mutex string `yaml:"mutex,omitempty"`
 
+	// Labels are attached to all jobs started from this spec and can be used to query job history,
+	// e.g. by team or pipeline stage.
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// Matrix expands this job into one job per combination of the values listed here, e.g. go versions and platforms.
+	// The values of a combination are available to the job as annotations.
+	Matrix map[string][]string `yaml:"matrix,omitempty"`
+
+	// Retry configures if and how failed jobs are started again. Without a retry policy failed jobs are not retried.
+	Retry *RetryPolicy `yaml:"retry,omitempty"`
+
+	// Sampling runs this job for only a share of the commits it's started for, e.g. to run expensive end-to-end
+	// tests on some pull request pushes but always on master. Without a sampling policy the job always runs.
+	Sampling *SamplingPolicy `yaml:"sampling,omitempty"`
+
+	// ServiceAccount requests one of the service accounts the werft operator made available to jobs, e.g. deployer.
+	// Whether a job gets the service account depends on the policy the operator configured for it.
+	ServiceAccount string `yaml:"serviceAccount,omitempty"`
+
+	// SecurityProfile requests one of the security profiles the werft operator configured, e.g. image-builder, in place
+	// of the default profile. Whether a job gets the profile depends on the policy the operator configured for it.
+	SecurityProfile string `yaml:"securityProfile,omitempty"`
+
+	// ExecutionWindow requests one of the execution windows the werft operator configured, e.g. nightly. Jobs which
+	// are started outside their window wait until it opens.
+	ExecutionWindow string `yaml:"executionWindow,omitempty"`
+
+	// Credentials requests short-lived cloud credentials the werft operator made available to jobs, e.g. aws-deploy.
+	// Whether a job gets the credentials depends on the policy the operator configured for them.
+	Credentials []string `yaml:"credentials,omitempty"`
+
+	// Downstream are jobs in other repositories which are started when this job succeeds, e.g. to rebuild the
+	// services which depend on a library. The annotations of this job are passed on to them.
+	Downstream []DownstreamJob `yaml:"downstream,omitempty"`
+
 	// Args describe annotations which this job expects. This list is only used on the UI when manually
 	// starting the job.
 	// This is list is neither exhaustive (i.e. jobs can use annotations not listed here), nor binding
@@ -85,6 +415,283 @@ type JobSpec struct {
 	Args []ArgSpec `yaml:"args,omitempty"`
 }
 
+// DownstreamJob is a job in another repository which is started when a job succeeds
+type DownstreamJob struct {
+	// Repo is the repository of the downstream job as host/owner/repo or owner/repo (on github.com)
+	Repo string `yaml:"repo"`
+
+	// Ref is the ref the downstream job is started on. Defaults to refs/heads/master.
+	Ref string `yaml:"ref,omitempty"`
+
+	// Path is the job file. Defaults to the job the downstream repository's werft config chooses.
+	Path string `yaml:"path,omitempty"`
+
+	// OnRefs limits starting the downstream job to successful jobs on matching refs, e.g. refs/heads/master or
+	// refs/tags/*. Defaults to the default branch of the upstream repository.
+	OnRefs []string `yaml:"onRefs,omitempty"`
+
+	// Annotations are passed to the downstream job in addition to those of the upstream job
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// Applies returns true if the downstream job is to be started for a job on ref. defaultRef is the ref of the
+// upstream repository's default branch, e.g. refs/heads/main.
+func (d DownstreamJob) Applies(ref, defaultRef string) bool {
+	if len(d.OnRefs) == 0 {
+		return ref == defaultRef
+	}
+	for _, p := range d.OnRefs {
+		if ok, _ := path.Match(p, ref); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TriggerSpec configures jobs started by a particular trigger
+type TriggerSpec struct {
+	// Pod replaces the pod spec of the job spec
+	Pod *corev1.PodSpec `yaml:"pod"`
+}
+
+// TriggerName returns the name of a trigger as used in job specs and filter expressions, e.g. pull_request
+func TriggerName(trigger werftv1.JobTrigger) string {
+	return strings.ToLower(strings.TrimPrefix(trigger.String(), "TRIGGER_"))
+}
+
+// PodFor returns the pod spec for jobs started by trigger, which is the job spec's pod
+// unless the trigger has a pod spec of its own.
+func (js *JobSpec) PodFor(trigger werftv1.JobTrigger) *corev1.PodSpec {
+	if ts, ok := js.Triggers[TriggerName(trigger)]; ok && ts != nil && ts.Pod != nil {
+		return ts.Pod
+	}
+	return js.Pod
+}
+
+// ApplyConditions removes the containers whose condition does not hold for a job from its pod spec, and returns the
+// names of the containers it removed
+func (js *JobSpec) ApplyConditions(pod *corev1.PodSpec, md *werftv1.JobMetadata) (skipped []string, err error) {
+	if len(js.When) == 0 {
+		return nil, nil
+	}
+
+	fields := filterexpr.JobFields(&werftv1.JobStatus{Metadata: md})
+	keep := func(cs []corev1.Container) ([]corev1.Container, error) {
+		var res []corev1.Container
+		for _, c := range cs {
+			cond, ok := js.When[c.Name]
+			if !ok {
+				res = append(res, c)
+				continue
+			}
+			expr, err := filterexpr.ParseExpression(cond)
+			if err != nil {
+				return nil, xerrors.Errorf("invalid condition of container %s: %w", c.Name, err)
+			}
+			if expr.Matches(fields) {
+				res = append(res, c)
+			} else {
+				skipped = append(skipped, c.Name)
+			}
+		}
+		return res, nil
+	}
+
+	pod.InitContainers, err = keep(pod.InitContainers)
+	if err != nil {
+		return nil, err
+	}
+	pod.Containers, err = keep(pod.Containers)
+	if err != nil {
+		return nil, err
+	}
+	return skipped, nil
+}
+
+// MatrixCombinations returns all combinations of the matrix values, ordered by the matrix keys.
+// If the job has no matrix, there are no combinations.
+func (js *JobSpec) MatrixCombinations() []map[string]string {
+	if len(js.Matrix) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(js.Matrix))
+	for k := range js.Matrix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	res := []map[string]string{map[string]string{}}
+	for _, k := range keys {
+		var next []map[string]string
+		for _, c := range res {
+			for _, v := range js.Matrix[k] {
+				nc := make(map[string]string, len(c)+1)
+				for ck, cv := range c {
+					nc[ck] = cv
+				}
+				nc[k] = v
+				next = append(next, nc)
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+// RetryCondition names a kind of failure upon which a job is retried
+type RetryCondition string
+
+const (
+	// RetryOnInfrastructure retries jobs which failed because of their pod, e.g. because it was evicted,
+	// OOMKilled or its image could not be pulled.
+	RetryOnInfrastructure RetryCondition = "infrastructure"
+
+	// RetryOnFailure retries all failed jobs, including those whose tests failed.
+	RetryOnFailure RetryCondition = "failure"
+)
+
+const (
+	// DefaultRetryBackoff is the delay before the first retry if a retry policy does not specify one
+	DefaultRetryBackoff = 30 * time.Second
+
+	// maxRetryBackoff caps the exponential backoff between retries
+	maxRetryBackoff = 1 * time.Hour
+)
+
+// RetryPolicy determines if and how failed jobs are retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a job is run at most, including the first attempt.
+	MaxAttempts int `yaml:"maxAttempts"`
+
+	// Backoff is the delay before the first retry (e.g. "1m"). The delay doubles with every subsequent retry.
+	Backoff string `yaml:"backoff,omitempty"`
+
+	// RetryOn lists the failures which cause a retry. Defaults to infrastructure failures only.
+	RetryOn []RetryCondition `yaml:"retryOn,omitempty"`
+}
+
+// ShouldRetry determines if a job which failed in its n-th attempt should be retried
+func (p *RetryPolicy) ShouldRetry(attempt int, infrastructureFailure bool) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	conditions := p.RetryOn
+	if len(conditions) == 0 {
+		conditions = []RetryCondition{RetryOnInfrastructure}
+	}
+	for _, c := range conditions {
+		switch c {
+		case RetryOnFailure:
+			return true
+		case RetryOnInfrastructure:
+			if infrastructureFailure {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Delay computes how long to wait before starting the attempt following the n-th one
+func (p *RetryPolicy) Delay(attempt int) (time.Duration, error) {
+	backoff := DefaultRetryBackoff
+	if p != nil && p.Backoff != "" {
+		var err error
+		backoff, err = time.ParseDuration(p.Backoff)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for i := 1; i < attempt && backoff < maxRetryBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryBackoff {
+		backoff = maxRetryBackoff
+	}
+	return backoff, nil
+}
+
+// SamplingPolicy determines for which commits a job runs
+type SamplingPolicy struct {
+	// Rate is the percentage of commits the job runs for, from 0 to 100.
+	Rate int `yaml:"rate"`
+
+	// Always lists filter terms (e.g. repo.ref==refs/heads/master) which make the job run regardless of the rate
+	// if any of them matches.
+	Always []string `yaml:"always,omitempty"`
+}
+
+// Sample decides if a job runs. The decision depends on the revision only, so that a job either always or never
+// runs for a particular commit, no matter how often it's started. Jobs started manually always run.
+func (p *SamplingPolicy) Sample(md *werftv1.JobMetadata) (run bool, reason string, err error) {
+	if p == nil || p.Rate >= 100 || md.Trigger == werftv1.JobTrigger_TRIGGER_MANUAL || md.Repository == nil || md.Repository.Revision == "" {
+		return true, "", nil
+	}
+
+	if len(p.Always) > 0 {
+		terms, err := filterexpr.Parse(p.Always)
+		if err != nil {
+			return false, "", xerrors.Errorf("invalid sampling condition: %w", err)
+		}
+		if filterexpr.MatchesFilter(&werftv1.JobStatus{Metadata: md}, []*werftv1.FilterExpression{{Terms: terms}}) {
+			return true, "", nil
+		}
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(md.Repository.Revision))
+	if int(h.Sum32()%100) < p.Rate {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("sampled out: job runs for %d%% of commits", p.Rate), nil
+}
+
+// ApprovalPolicy determines which jobs wait for approval and who can approve them. The werft operator sets the
+// policy in the server config, not the job spec: whoever can push a branch can change the job spec.
+type ApprovalPolicy struct {
+	// Approvers are the GitHub users who can approve jobs. Defaults to everyone with write permission
+	// on the repository.
+	Approvers []string `yaml:"approvers,omitempty"`
+
+	// Triggers limits the approval to jobs started by these triggers, e.g. tag. Defaults to all triggers.
+	Triggers []string `yaml:"triggers,omitempty"`
+}
+
+// Required returns true if a job needs approval before it runs
+func (p *ApprovalPolicy) Required(md *werftv1.JobMetadata) bool {
+	if p == nil {
+		return false
+	}
+	if len(p.Triggers) == 0 {
+		return true
+	}
+	trigger := TriggerName(md.Trigger)
+	for _, t := range p.Triggers {
+		if t == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+// MayApprove returns true if the policy lets a GitHub user approve jobs. Users still need write permission
+// on the repository of the job.
+func (p *ApprovalPolicy) MayApprove(user string) bool {
+	if p == nil || len(p.Approvers) == 0 {
+		return true
+	}
+	for _, a := range p.Approvers {
+		// GitHub logins are case-insensitive
+		if strings.EqualFold(a, user) {
+			return true
+		}
+	}
+	return false
+}
+
 // ArgSpec specifies an argument/annotation for a job.
 type ArgSpec struct {
 	Name string `yaml:"name"`
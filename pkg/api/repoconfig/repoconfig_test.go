@@ -2,11 +2,14 @@ package repoconfig_test
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func TestUnmarshalC(t *testing.T) {
@@ -14,7 +17,7 @@ func TestUnmarshalC(t *testing.T) {
 		Source      string
 		Expectation string
 	}{
-		{`defaultJob: "foo.yaml"`, `{"DefaultJob":"foo.yaml","Rules":null}`},
+		{`defaultJob: "foo.yaml"`, `{"DefaultJob":"foo.yaml","Rules":null,"PullRequests":false}`},
 		{
 			`rules:
 - path: ""
@@ -23,7 +26,7 @@ func TestUnmarshalC(t *testing.T) {
 - path: ""
   matchesAll:
   - or: ["repo.ref !~= refs/branches/"]`,
-			`{"DefaultJob":"","Rules":[{"Path":"","Expr":[{"terms":[{"field":"repo.ref","value":"refs/tags/","operation":3}]}]},{"Path":"","Expr":[{"terms":[{"field":"repo.ref","value":"refs/branches/","operation":3,"negate":true}]}]}]}`,
+			`{"DefaultJob":"","Rules":[{"Path":"","Expr":[{"terms":[{"field":"repo.ref","value":"refs/tags/","operation":3}]}]},{"Path":"","Expr":[{"terms":[{"field":"repo.ref","value":"refs/branches/","operation":3,"negate":true}]}]}],"PullRequests":false}`,
 		},
 		{
 			`rules:
@@ -33,7 +36,7 @@ func TestUnmarshalC(t *testing.T) {
     - "repo.ref ~= refs/branches/"
   - or:
     - "name !~= 0"
-`, `{"DefaultJob":"","Rules":[{"Path":"foo.yaml","Expr":[{"terms":[{"field":"repo.ref","value":"refs/branches/","operation":3}]},{"terms":[{"field":"name","value":"0","operation":3,"negate":true}]}]}]}`,
+`, `{"DefaultJob":"","Rules":[{"Path":"foo.yaml","Expr":[{"terms":[{"field":"repo.ref","value":"refs/branches/","operation":3}]},{"terms":[{"field":"name","value":"0","operation":3,"negate":true}]}]}],"PullRequests":false}`,
 		},
 	}
 
@@ -101,6 +104,24 @@ func TestTemplatePath(t *testing.T) {
 			},
 			"bar",
 		},
+		{
+			repoconfig.C{
+				DefaultJob: "foo",
+				Rules: []*repoconfig.JobStartRule{
+					&repoconfig.JobStartRule{
+						Path: "bar",
+						Expr: []*v1.FilterExpression{
+							&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "trigger", Value: "pull_request", Operation: v1.FilterOp_OP_EQUALS}}},
+						},
+					},
+				},
+			},
+			v1.JobMetadata{
+				Repository: &v1.Repository{},
+				Trigger:    v1.JobTrigger_TRIGGER_PULL_REQUEST,
+			},
+			"bar",
+		},
 	}
 
 	for idx, test := range tests {
@@ -110,3 +131,350 @@ func TestTemplatePath(t *testing.T) {
 		}
 	}
 }
+
+func TestTemplatePaths(t *testing.T) {
+	var c repoconfig.C
+	err := yaml.Unmarshal([]byte(`defaultJob: ".werft/build.yaml"
+rules:
+- path: ""
+  triggers: ["deleted"]
+jobs:
+- path: ".werft/e2e.yaml"
+  triggers: ["push", "pull_request"]
+  changedPaths: ["pkg/**", "go.mod"]
+- path: ".werft/release.yaml"
+  triggers: ["tag"]
+  refs: ["refs/tags/v*"]
+- path: ".werft/build.yaml"
+  triggers: ["tag"]
+`), &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name        string
+		Trigger     v1.JobTrigger
+		Ref         string
+		Changed     []string
+		Expectation []string
+	}{
+		{"unknown changes", v1.JobTrigger_TRIGGER_PUSH, "refs/heads/master", nil, []string{".werft/build.yaml", ".werft/e2e.yaml"}},
+		{"matching changes", v1.JobTrigger_TRIGGER_PULL_REQUEST, "refs/heads/foo", []string{"README.md", "pkg/store/job.go"}, []string{".werft/build.yaml", ".werft/e2e.yaml"}},
+		{"other changes", v1.JobTrigger_TRIGGER_PUSH, "refs/heads/master", []string{"README.md", "pkg.go"}, []string{".werft/build.yaml"}},
+		{"release tag", v1.JobTrigger_TRIGGER_TAG, "refs/tags/v1.0.0", nil, []string{".werft/build.yaml", ".werft/release.yaml"}},
+		{"other tag", v1.JobTrigger_TRIGGER_TAG, "refs/tags/nightly", nil, []string{".werft/build.yaml"}},
+		{"deleted", v1.JobTrigger_TRIGGER_DELETED, "refs/heads/foo", nil, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := c.TemplatePaths(&v1.JobMetadata{Trigger: test.Trigger, Repository: &v1.Repository{Ref: test.Ref}}, test.Changed)
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJobStartRuleErrors(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Source string
+	}{
+		{"unknown trigger", `jobs: [{path: "foo.yaml", triggers: ["commit"]}]`},
+		{"invalid ref pattern", `jobs: [{path: "foo.yaml", refs: ["refs/heads/["]}]`},
+		{"invalid path pattern", `rules: [{path: "foo.yaml", changedPaths: ["pkg/["]}]`},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var c repoconfig.C
+			err := yaml.Unmarshal([]byte(test.Source), &c)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestJobPaths(t *testing.T) {
+	var c repoconfig.C
+	err := yaml.Unmarshal([]byte(`defaultJob: "ci/build.yaml"
+rules:
+- path: ""
+  triggers: ["deleted"]
+- path: "ci/deploy.yaml"
+  triggers: ["tag"]
+jobs:
+- path: "ci/build.yaml"
+  triggers: ["pull_request"]
+- path: ".werft/e2e.yaml"
+labelJobs:
+  needs-perf: [".werft/perf.yaml", ".werft/e2e.yaml"]
+  needs-docs: [".werft/docs.yaml"]
+`), &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{"ci/build.yaml", "ci/deploy.yaml", ".werft/e2e.yaml", ".werft/docs.yaml", ".werft/perf.yaml"}
+	if act := c.JobPaths(); !reflect.DeepEqual(act, exp) {
+		t.Errorf("expected %v, got %v", exp, act)
+	}
+}
+
+func TestApplyAnnotations(t *testing.T) {
+	c := repoconfig.C{Annotations: map[string]string{"team": "platform", "goVersion": "1.13"}}
+	md := v1.JobMetadata{Annotations: []*v1.Annotation{{Key: "goVersion", Value: "1.14"}}}
+	c.ApplyAnnotations(&md)
+
+	act := make(map[string]string)
+	for _, a := range md.Annotations {
+		act[a.Key] = a.Value
+	}
+	exp := map[string]string{"team": "platform", "goVersion": "1.14"}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("expected %v, got %v", exp, act)
+	}
+	if len(md.Annotations) != 2 {
+		t.Errorf("expected 2 annotations, got %d", len(md.Annotations))
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Policy   *repoconfig.RetryPolicy
+		Attempt  int
+		Infra    bool
+		Retry    bool
+		Delay    time.Duration
+		DelayErr bool
+	}{
+		{Name: "no policy", Policy: nil, Attempt: 1, Infra: true, Retry: false, Delay: repoconfig.DefaultRetryBackoff},
+		{Name: "infra failure", Policy: &repoconfig.RetryPolicy{MaxAttempts: 3}, Attempt: 1, Infra: true, Retry: true, Delay: repoconfig.DefaultRetryBackoff},
+		{Name: "test failure", Policy: &repoconfig.RetryPolicy{MaxAttempts: 3}, Attempt: 1, Infra: false, Retry: false, Delay: repoconfig.DefaultRetryBackoff},
+		{Name: "attempts exhausted", Policy: &repoconfig.RetryPolicy{MaxAttempts: 3}, Attempt: 3, Infra: true, Retry: false, Delay: 4 * repoconfig.DefaultRetryBackoff},
+		{Name: "retry on failure", Policy: &repoconfig.RetryPolicy{MaxAttempts: 2, Backoff: "10s", RetryOn: []repoconfig.RetryCondition{repoconfig.RetryOnFailure}}, Attempt: 1, Infra: false, Retry: true, Delay: 10 * time.Second},
+		{Name: "backoff doubles", Policy: &repoconfig.RetryPolicy{MaxAttempts: 5, Backoff: "10s"}, Attempt: 3, Infra: true, Retry: true, Delay: 40 * time.Second},
+		{Name: "backoff is capped", Policy: &repoconfig.RetryPolicy{MaxAttempts: 50, Backoff: "10m"}, Attempt: 20, Infra: true, Retry: true, Delay: 1 * time.Hour},
+		{Name: "invalid backoff", Policy: &repoconfig.RetryPolicy{MaxAttempts: 2, Backoff: "soon"}, Attempt: 1, Infra: true, Retry: true, DelayErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			retry := test.Policy.ShouldRetry(test.Attempt, test.Infra)
+			if retry != test.Retry {
+				t.Errorf("expected retry to be %v, was %v", test.Retry, retry)
+			}
+
+			delay, err := test.Policy.Delay(test.Attempt)
+			if test.DelayErr {
+				if err == nil {
+					t.Errorf("expected an error for backoff %q", test.Policy.Backoff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if delay != test.Delay {
+				t.Errorf("expected delay of %v, was %v", test.Delay, delay)
+			}
+		})
+	}
+}
+
+func TestMatrixCombinations(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Matrix      map[string][]string
+		Expectation []map[string]string
+	}{
+		{"no matrix", nil, nil},
+		{"single key", map[string][]string{"go": {"1.13", "1.14"}}, []map[string]string{{"go": "1.13"}, {"go": "1.14"}}},
+		{
+			"two keys",
+			map[string][]string{"platform": {"linux", "darwin"}, "go": {"1.13", "1.14"}},
+			[]map[string]string{
+				{"go": "1.13", "platform": "linux"},
+				{"go": "1.13", "platform": "darwin"},
+				{"go": "1.14", "platform": "linux"},
+				{"go": "1.14", "platform": "darwin"},
+			},
+		},
+		{"empty values", map[string][]string{"go": {"1.13"}, "platform": {}}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			spec := repoconfig.JobSpec{Matrix: test.Matrix}
+			act := spec.MatrixCombinations()
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestPodFor(t *testing.T) {
+	var (
+		pod   = &corev1.PodSpec{ServiceAccountName: "default"}
+		prPod = &corev1.PodSpec{ServiceAccountName: "pull-request"}
+	)
+	tests := []struct {
+		Name        string
+		Triggers    map[string]*repoconfig.TriggerSpec
+		Trigger     v1.JobTrigger
+		Expectation *corev1.PodSpec
+	}{
+		{"no triggers", nil, v1.JobTrigger_TRIGGER_PULL_REQUEST, pod},
+		{"matching trigger", map[string]*repoconfig.TriggerSpec{"pull_request": {Pod: prPod}}, v1.JobTrigger_TRIGGER_PULL_REQUEST, prPod},
+		{"other trigger", map[string]*repoconfig.TriggerSpec{"pull_request": {Pod: prPod}}, v1.JobTrigger_TRIGGER_PUSH, pod},
+		{"trigger without pod", map[string]*repoconfig.TriggerSpec{"pull_request": {}}, v1.JobTrigger_TRIGGER_PULL_REQUEST, pod},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			spec := repoconfig.JobSpec{Pod: pod, Triggers: test.Triggers}
+			act := spec.PodFor(test.Trigger)
+			if act != test.Expectation {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestApplyConditions(t *testing.T) {
+	when := map[string]string{
+		"publish": `annotation.publish == "true"`,
+		"deploy":  `trigger == "push" && repo.ref == "refs/heads/master"`,
+		"lint":    `trigger == "pull_request"`,
+	}
+	tests := []struct {
+		Name       string
+		Metadata   v1.JobMetadata
+		Containers []string
+		Skipped    []string
+	}{
+		{"pull request", v1.JobMetadata{Trigger: v1.JobTrigger_TRIGGER_PULL_REQUEST}, []string{"lint", "build"}, []string{"publish", "deploy"}},
+		{"release", v1.JobMetadata{
+			Trigger:     v1.JobTrigger_TRIGGER_PUSH,
+			Repository:  &v1.Repository{Ref: "refs/heads/master"},
+			Annotations: []*v1.Annotation{{Key: "publish", Value: "true"}},
+		}, []string{"build", "publish", "deploy"}, []string{"lint"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			spec := repoconfig.JobSpec{When: when}
+			pod := &corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "lint"}},
+				Containers:     []corev1.Container{{Name: "build"}, {Name: "publish"}, {Name: "deploy"}},
+			}
+			skipped, err := spec.ApplyConditions(pod, &test.Metadata)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var containers []string
+			for _, c := range append(pod.InitContainers, pod.Containers...) {
+				containers = append(containers, c.Name)
+			}
+			if !reflect.DeepEqual(containers, test.Containers) {
+				t.Errorf("expected containers %v, actual %v", test.Containers, containers)
+			}
+			if !reflect.DeepEqual(skipped, test.Skipped) {
+				t.Errorf("expected skipped %v, actual %v", test.Skipped, skipped)
+			}
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	md := func(trigger v1.JobTrigger, ref, rev string) *v1.JobMetadata {
+		return &v1.JobMetadata{Trigger: trigger, Repository: &v1.Repository{Ref: ref, Revision: rev}}
+	}
+	tests := []struct {
+		Name        string
+		Policy      *repoconfig.SamplingPolicy
+		Metadata    *v1.JobMetadata
+		Expectation bool
+	}{
+		{"no policy", nil, md(v1.JobTrigger_TRIGGER_PUSH, "refs/heads/foo", "def456"), true},
+		{"sampled in", &repoconfig.SamplingPolicy{Rate: 50}, md(v1.JobTrigger_TRIGGER_PUSH, "refs/heads/foo", "abc123"), true},
+		{"sampled out", &repoconfig.SamplingPolicy{Rate: 50}, md(v1.JobTrigger_TRIGGER_PUSH, "refs/heads/foo", "def456"), false},
+		{"never", &repoconfig.SamplingPolicy{Rate: 0}, md(v1.JobTrigger_TRIGGER_PUSH, "refs/heads/foo", "deadbeef"), false},
+		{"always", &repoconfig.SamplingPolicy{Rate: 100}, md(v1.JobTrigger_TRIGGER_PUSH, "refs/heads/foo", "def456"), true},
+		{"manual", &repoconfig.SamplingPolicy{Rate: 0}, md(v1.JobTrigger_TRIGGER_MANUAL, "refs/heads/foo", "def456"), true},
+		{"always on master", &repoconfig.SamplingPolicy{Rate: 0, Always: []string{"repo.ref==refs/heads/master"}}, md(v1.JobTrigger_TRIGGER_PUSH, "refs/heads/master", "def456"), true},
+		{"always on other branch", &repoconfig.SamplingPolicy{Rate: 0, Always: []string{"repo.ref==refs/heads/master", "trigger==tag"}}, md(v1.JobTrigger_TRIGGER_PUSH, "refs/heads/foo", "def456"), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, reason, err := test.Policy.Sample(test.Metadata)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act != test.Expectation {
+				t.Errorf("expected %v, actual %v (%s)", test.Expectation, act, reason)
+			}
+			if !act && reason == "" {
+				t.Errorf("skipped job has no reason")
+			}
+		})
+	}
+}
+
+func TestApprovalPolicy(t *testing.T) {
+	tests := []struct {
+		Name       string
+		Policy     *repoconfig.ApprovalPolicy
+		Trigger    v1.JobTrigger
+		User       string
+		Required   bool
+		MayApprove bool
+	}{
+		{"no policy", nil, v1.JobTrigger_TRIGGER_TAG, "bob", false, true},
+		{"all triggers", &repoconfig.ApprovalPolicy{}, v1.JobTrigger_TRIGGER_PUSH, "bob", true, true},
+		{"matching trigger", &repoconfig.ApprovalPolicy{Triggers: []string{"tag"}}, v1.JobTrigger_TRIGGER_TAG, "bob", true, true},
+		{"other trigger", &repoconfig.ApprovalPolicy{Triggers: []string{"tag"}}, v1.JobTrigger_TRIGGER_PULL_REQUEST, "bob", false, true},
+		{"approver", &repoconfig.ApprovalPolicy{Approvers: []string{"alice", "Bob"}}, v1.JobTrigger_TRIGGER_MANUAL, "bob", true, true},
+		{"not an approver", &repoconfig.ApprovalPolicy{Approvers: []string{"alice"}}, v1.JobTrigger_TRIGGER_MANUAL, "bob", true, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if act := test.Policy.Required(&v1.JobMetadata{Trigger: test.Trigger}); act != test.Required {
+				t.Errorf("expected required to be %v, actual %v", test.Required, act)
+			}
+			if act := test.Policy.MayApprove(test.User); act != test.MayApprove {
+				t.Errorf("expected %s to be allowed to approve: %v, actual %v", test.User, test.MayApprove, act)
+			}
+		})
+	}
+}
+
+func TestRequiredJobPaths(t *testing.T) {
+	var c repoconfig.C
+	err := yaml.Unmarshal([]byte(`defaultJob: "ci/build.yaml"
+jobs:
+- path: ".werft/e2e.yaml"
+- path: ".werft/nightly.yaml"
+  triggers: ["scheduled"]
+labelJobs:
+  needs-perf: [".werft/perf.yaml"]
+statusChecks:
+  perJob: true
+  syncRequired: ["master"]
+  notRequired: [".werft/nightly.yaml"]
+`), &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{"ci/build.yaml", ".werft/e2e.yaml"}
+	if act := c.RequiredJobPaths(); !reflect.DeepEqual(act, exp) {
+		t.Errorf("expected %v, got %v", exp, act)
+	}
+}
@@ -0,0 +1,283 @@
+package repoconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	werftv1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError describes a problem with a particular field of a job spec
+type ValidationError struct {
+	// Line is the line in the job spec the problem was found at
+	Line int
+	// Field is the path of the offending field, e.g. pod.containers[0].image
+	Field string
+	// Message describes the problem
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Field, e.Message)
+}
+
+// ValidationErrors lists all problems found in a job spec
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return "invalid job spec: " + strings.Join(msgs, "; ")
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	jobSpecType         = reflect.TypeOf(JobSpec{})
+)
+
+// ValidateJobSpec checks a job spec YAML against the schema of JobSpec, including the pod spec.
+// Unlike decoding the job spec, this rejects unknown fields and values of the wrong type, and reports
+// the line of each problem. If the job spec is a template, validate the rendered result.
+// The error returned is either a YAML syntax error or ValidationErrors.
+func ValidateJobSpec(content []byte) error {
+	var doc yaml.Node
+	err := yaml.Unmarshal(content, &doc)
+	if err != nil {
+		return xerrors.Errorf("invalid job spec: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return ValidationErrors{{Line: 1, Message: "job spec is empty"}}
+	}
+
+	root := doc.Content[0]
+	var errs ValidationErrors
+	validateNode(root, jobSpecType, "", &errs)
+	if root.Kind == yaml.MappingNode {
+		var hasPod bool
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			switch root.Content[i].Value {
+			case "pod":
+				hasPod = true
+			case "triggers":
+				validateTriggers(root.Content[i+1], &errs)
+			case "sampling":
+				validateSampling(root.Content[i+1], &errs)
+			case "when":
+				validateWhen(root.Content[i+1], &errs)
+			}
+		}
+		if !hasPod {
+			errs = append(errs, ValidationError{Line: root.Line, Field: "pod", Message: "is required"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateNode checks that a YAML node can be decoded into a value of type t, adding all problems to errs
+func validateNode(n *yaml.Node, t reflect.Type, path string, errs *ValidationErrors) {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Tag == "!!null" {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		// types like resource quantities bring their own format, which Kubernetes checks for us
+		return
+	}
+
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, ValidationError{Line: n.Line, Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode {
+			fail("expected an object")
+			return
+		}
+		fields := make(map[string]reflect.StructField)
+		collectFields(t, fields)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			field, ok := fields[key.Value]
+			if !ok {
+				*errs = append(*errs, ValidationError{Line: key.Line, Field: joinPath(path, key.Value), Message: "unknown field"})
+				continue
+			}
+			validateNode(val, field.Type, joinPath(path, key.Value), errs)
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			fail("expected an object")
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			validateNode(n.Content[i+1], t.Elem(), joinPath(path, n.Content[i].Value), errs)
+		}
+	case reflect.Slice, reflect.Array:
+		if n.Kind != yaml.SequenceNode {
+			fail("expected a list")
+			return
+		}
+		for i, c := range n.Content {
+			validateNode(c, t.Elem(), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.String:
+		// numbers and booleans are fine, the decoder turns them into strings
+		if n.Kind != yaml.ScalarNode {
+			fail("expected a string")
+		}
+	case reflect.Bool:
+		if n.Kind != yaml.ScalarNode || !(n.Tag == "!!bool" || (n.Style == 0 && isYAML11Bool(n.Value))) {
+			fail("expected true or false, got %s", describeNode(n))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!int" {
+			fail("expected an integer, got %s", describeNode(n))
+		}
+	case reflect.Float32, reflect.Float64:
+		if n.Kind != yaml.ScalarNode || (n.Tag != "!!int" && n.Tag != "!!float") {
+			fail("expected a number, got %s", describeNode(n))
+		}
+	}
+}
+
+// validateTriggers checks that all triggers of a job spec exist
+func validateTriggers(n *yaml.Node, errs *ValidationErrors) {
+	if n.Kind != yaml.MappingNode {
+		// validateNode complains about this already
+		return
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		key := n.Content[i]
+		trigger, ok := werftv1.JobTrigger_value["TRIGGER_"+strings.ToUpper(key.Value)]
+		if !ok || trigger == int32(werftv1.JobTrigger_TRIGGER_UNKNOWN) {
+			*errs = append(*errs, ValidationError{Line: key.Line, Field: joinPath("triggers", key.Value), Message: "unknown trigger"})
+		}
+	}
+}
+
+// validateSampling checks that the sampling rate is a percentage and all sampling conditions are valid filter terms
+func validateSampling(n *yaml.Node, errs *ValidationErrors) {
+	if n.Kind != yaml.MappingNode {
+		// validateNode complains about this already
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		switch key.Value {
+		case "rate":
+			var rate int
+			if val.Decode(&rate) == nil && (rate < 0 || rate > 100) {
+				*errs = append(*errs, ValidationError{Line: val.Line, Field: "sampling.rate", Message: "must be between 0 and 100"})
+			}
+		case "always":
+			if val.Kind != yaml.SequenceNode {
+				continue
+			}
+			for j, c := range val.Content {
+				if _, err := filterexpr.Parse([]string{c.Value}); err != nil {
+					*errs = append(*errs, ValidationError{Line: c.Line, Field: fmt.Sprintf("sampling.always[%d]", j), Message: err.Error()})
+				}
+			}
+		}
+	}
+}
+
+// validateWhen checks that all container conditions are valid expressions
+func validateWhen(n *yaml.Node, errs *ValidationErrors) {
+	if n.Kind != yaml.MappingNode {
+		// validateNode complains about this already
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, val := n.Content[i], n.Content[i+1]
+		if val.Kind != yaml.ScalarNode {
+			continue
+		}
+		if _, err := filterexpr.ParseExpression(val.Value); err != nil {
+			*errs = append(*errs, ValidationError{Line: val.Line, Field: joinPath("when", key.Value), Message: err.Error()})
+		}
+	}
+}
+
+// collectFields lists the fields of a struct by the name they have in a job spec, including those of inlined structs.
+// Kubernetes types are named by their JSON tags, our own types by their YAML tags.
+func collectFields(t reflect.Type, fields map[string]reflect.StructField) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			continue
+		}
+
+		tag, ok := f.Tag.Lookup("json")
+		if !ok {
+			tag = f.Tag.Get("yaml")
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" || strings.Contains(tag, ",inline") {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectFields(ft, fields)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f
+	}
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// isYAML11Bool returns true if the decoder would read an unquoted value as boolean.
+// The Kubernetes decoder follows YAML 1.1, which has many more ways of spelling booleans than YAML 1.2.
+func isYAML11Bool(val string) bool {
+	switch strings.ToLower(val) {
+	case "y", "yes", "n", "no", "on", "off", "true", "false":
+		return true
+	default:
+		return false
+	}
+}
+
+func describeNode(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "a list"
+	default:
+		return fmt.Sprintf("%q", n.Value)
+	}
+}
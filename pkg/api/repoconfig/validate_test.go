@@ -0,0 +1,127 @@
+package repoconfig_test
+
+import (
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+)
+
+func TestValidateJobSpec(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Spec        string
+		Expectation string
+	}{
+		{"valid", `
+description: builds things
+mutex: build
+labels:
+  team: platform
+retry:
+  maxAttempts: 3
+pod:
+  hostNetwork: yes
+  containers:
+  - name: build
+    image: alpine:latest
+    tty: true
+    env:
+    - name: FOO
+      value: 1
+    resources:
+      limits:
+        cpu: 500m
+        memory: 1Gi
+    ports:
+    - containerPort: 8080
+`, ""},
+		{"empty", ``, "invalid job spec: line 1: job spec is empty"},
+		{"no pod", `mutex: build`, "invalid job spec: line 1: pod: is required"},
+		{"syntax error", "pod:\n  containers:\n- name: foo\n  bar", "invalid job spec: yaml: line 2: did not find expected key"},
+		{"unknown field", `
+pod:
+  containers:
+  - name: build
+    imag: alpine:latest
+`, "invalid job spec: line 5: pod.containers[0].imag: unknown field"},
+		{"unknown top-level field", `
+pods:
+  containers: []
+pod: {}
+`, "invalid job spec: line 2: pods: unknown field"},
+		{"wrong types", `
+retry:
+  maxAttempts: three
+pod:
+  containers:
+    name: build
+  hostNetwork: "true"
+`, `invalid job spec: line 3: retry.maxAttempts: expected an integer, got "three"; line 6: pod.containers: expected a list; line 7: pod.hostNetwork: expected true or false, got "true"`},
+		{"triggers", `
+pod:
+  containers: []
+triggers:
+  pull_request:
+    pod:
+      containers:
+      - name: test
+  tag:
+    pod:
+      containers: []
+`, ""},
+		{"unknown trigger", `
+pod:
+  containers: []
+triggers:
+  pullrequest:
+    pod:
+      containers: []
+`, "invalid job spec: line 5: triggers.pullrequest: unknown trigger"},
+		{"sampling", `
+pod:
+  containers: []
+sampling:
+  rate: 20
+  always:
+  - repo.ref==refs/heads/master
+`, ""},
+		{"invalid sampling", `
+pod:
+  containers: []
+sampling:
+  rate: 120
+  always:
+  - master
+`, "invalid job spec: line 5: sampling.rate: must be between 0 and 100; line 7: sampling.always[0]: missing operator"},
+		{"when", `
+pod:
+  containers: []
+when:
+  publish: annotation.publish == "true" && trigger in ["push", "tag"]
+`, ""},
+		{"invalid when", `
+pod:
+  containers: []
+when:
+  publish: annotation.publish ==
+`, "invalid job spec: line 5: when.publish: expected field or value at position 21, found end of expression"},
+		{"approval moved to the server config", `
+pod:
+  containers: []
+approval:
+  approvers: [alice]
+`, "invalid job spec: line 4: approval: unknown field"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := repoconfig.ValidateJobSpec([]byte(test.Spec))
+			var act string
+			if err != nil {
+				act = err.Error()
+			}
+			if act != test.Expectation {
+				t.Errorf("unexpected error: expected %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -1,4 +1,11 @@
 #!/bin/sh
 
 go get github.com/golang/protobuf/protoc-gen-go
-protoc -I. --go_out=plugins=grpc:. *.proto
\ No newline at end of file
+protoc -I. --go_out=plugins=grpc:. *.proto
+
+# TypeScript client - used by the web UI, but just as usable from any other Node/browser code
+cd ../../webui && yarn protoc && cd -
+
+# Python client - requires grpcio-tools (pip install grpcio-tools)
+mkdir -p ../python/werft && touch ../python/werft/__init__.py
+python3 -m grpc_tools.protoc -I. --python_out=../python/werft --grpc_python_out=../python/werft *.proto
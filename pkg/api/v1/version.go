@@ -0,0 +1,14 @@
+package v1
+
+// APIVersion is the version of the werft API defined in this package.
+// Clients can compare it with the version reported by a server to detect incompatibilities.
+const APIVersion = "v1"
+
+const (
+	// APIVersionMetadataKey is the gRPC metadata key clients and servers send their APIVersion as.
+	// Servers reject requests of clients which speak another API version.
+	APIVersionMetadataKey = "werft-api-version"
+
+	// VersionMetadataKey is the gRPC metadata key clients and servers send their release version as, e.g. v0.3.0
+	VersionMetadataKey = "werft-version"
+)
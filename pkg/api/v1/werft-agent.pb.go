@@ -0,0 +1,914 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: werft-agent.proto
+
+package v1
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type AgentMessage struct {
+	// id is the ID of the command the message answers, zero for the hello
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Content:
+	//	*AgentMessage_Hello
+	//	*AgentMessage_ContainerStarted
+	//	*AgentMessage_ContainerStopped
+	//	*AgentMessage_InitDone
+	//	*AgentMessage_Logs
+	//	*AgentMessage_Done
+	Content              isAgentMessage_Content `protobuf_oneof:"content"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *AgentMessage) Reset()         { *m = AgentMessage{} }
+func (m *AgentMessage) String() string { return proto.CompactTextString(m) }
+func (*AgentMessage) ProtoMessage()    {}
+func (*AgentMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{0}
+}
+
+func (m *AgentMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentMessage.Unmarshal(m, b)
+}
+func (m *AgentMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentMessage.Marshal(b, m, deterministic)
+}
+func (m *AgentMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentMessage.Merge(m, src)
+}
+func (m *AgentMessage) XXX_Size() int {
+	return xxx_messageInfo_AgentMessage.Size(m)
+}
+func (m *AgentMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentMessage proto.InternalMessageInfo
+
+func (m *AgentMessage) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type isAgentMessage_Content interface {
+	isAgentMessage_Content()
+}
+
+type AgentMessage_Hello struct {
+	Hello *AgentHello `protobuf:"bytes,2,opt,name=hello,proto3,oneof"`
+}
+
+type AgentMessage_ContainerStarted struct {
+	ContainerStarted *AgentContainerStarted `protobuf:"bytes,3,opt,name=container_started,json=containerStarted,proto3,oneof"`
+}
+
+type AgentMessage_ContainerStopped struct {
+	ContainerStopped *AgentContainerStopped `protobuf:"bytes,4,opt,name=container_stopped,json=containerStopped,proto3,oneof"`
+}
+
+type AgentMessage_InitDone struct {
+	InitDone *AgentInitDone `protobuf:"bytes,5,opt,name=init_done,json=initDone,proto3,oneof"`
+}
+
+type AgentMessage_Logs struct {
+	Logs []byte `protobuf:"bytes,6,opt,name=logs,proto3,oneof"`
+}
+
+type AgentMessage_Done struct {
+	Done *AgentDone `protobuf:"bytes,7,opt,name=done,proto3,oneof"`
+}
+
+func (*AgentMessage_Hello) isAgentMessage_Content() {}
+
+func (*AgentMessage_ContainerStarted) isAgentMessage_Content() {}
+
+func (*AgentMessage_ContainerStopped) isAgentMessage_Content() {}
+
+func (*AgentMessage_InitDone) isAgentMessage_Content() {}
+
+func (*AgentMessage_Logs) isAgentMessage_Content() {}
+
+func (*AgentMessage_Done) isAgentMessage_Content() {}
+
+func (m *AgentMessage) GetContent() isAgentMessage_Content {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *AgentMessage) GetHello() *AgentHello {
+	if x, ok := m.GetContent().(*AgentMessage_Hello); ok {
+		return x.Hello
+	}
+	return nil
+}
+
+func (m *AgentMessage) GetContainerStarted() *AgentContainerStarted {
+	if x, ok := m.GetContent().(*AgentMessage_ContainerStarted); ok {
+		return x.ContainerStarted
+	}
+	return nil
+}
+
+func (m *AgentMessage) GetContainerStopped() *AgentContainerStopped {
+	if x, ok := m.GetContent().(*AgentMessage_ContainerStopped); ok {
+		return x.ContainerStopped
+	}
+	return nil
+}
+
+func (m *AgentMessage) GetInitDone() *AgentInitDone {
+	if x, ok := m.GetContent().(*AgentMessage_InitDone); ok {
+		return x.InitDone
+	}
+	return nil
+}
+
+func (m *AgentMessage) GetLogs() []byte {
+	if x, ok := m.GetContent().(*AgentMessage_Logs); ok {
+		return x.Logs
+	}
+	return nil
+}
+
+func (m *AgentMessage) GetDone() *AgentDone {
+	if x, ok := m.GetContent().(*AgentMessage_Done); ok {
+		return x.Done
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*AgentMessage) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*AgentMessage_Hello)(nil),
+		(*AgentMessage_ContainerStarted)(nil),
+		(*AgentMessage_ContainerStopped)(nil),
+		(*AgentMessage_InitDone)(nil),
+		(*AgentMessage_Logs)(nil),
+		(*AgentMessage_Done)(nil),
+	}
+}
+
+type AgentHello struct {
+	// name identifies the agent, e.g. by the cluster it runs in
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// token authenticates the agent
+	Token                string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentHello) Reset()         { *m = AgentHello{} }
+func (m *AgentHello) String() string { return proto.CompactTextString(m) }
+func (*AgentHello) ProtoMessage()    {}
+func (*AgentHello) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{1}
+}
+
+func (m *AgentHello) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentHello.Unmarshal(m, b)
+}
+func (m *AgentHello) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentHello.Marshal(b, m, deterministic)
+}
+func (m *AgentHello) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentHello.Merge(m, src)
+}
+func (m *AgentHello) XXX_Size() int {
+	return xxx_messageInfo_AgentHello.Size(m)
+}
+func (m *AgentHello) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentHello.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentHello proto.InternalMessageInfo
+
+func (m *AgentHello) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AgentHello) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type AgentContainerStarted struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// id identifies the container for the agent
+	Id                   string   `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Init                 bool     `protobuf:"varint,3,opt,name=init,proto3" json:"init,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentContainerStarted) Reset()         { *m = AgentContainerStarted{} }
+func (m *AgentContainerStarted) String() string { return proto.CompactTextString(m) }
+func (*AgentContainerStarted) ProtoMessage()    {}
+func (*AgentContainerStarted) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{2}
+}
+
+func (m *AgentContainerStarted) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentContainerStarted.Unmarshal(m, b)
+}
+func (m *AgentContainerStarted) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentContainerStarted.Marshal(b, m, deterministic)
+}
+func (m *AgentContainerStarted) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentContainerStarted.Merge(m, src)
+}
+func (m *AgentContainerStarted) XXX_Size() int {
+	return xxx_messageInfo_AgentContainerStarted.Size(m)
+}
+func (m *AgentContainerStarted) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentContainerStarted.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentContainerStarted proto.InternalMessageInfo
+
+func (m *AgentContainerStarted) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AgentContainerStarted) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AgentContainerStarted) GetInit() bool {
+	if m != nil {
+		return m.Init
+	}
+	return false
+}
+
+type AgentContainerStopped struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// state is the terminated state of the container as Kubernetes ContainerStateTerminated JSON
+	State                []byte   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentContainerStopped) Reset()         { *m = AgentContainerStopped{} }
+func (m *AgentContainerStopped) String() string { return proto.CompactTextString(m) }
+func (*AgentContainerStopped) ProtoMessage()    {}
+func (*AgentContainerStopped) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{3}
+}
+
+func (m *AgentContainerStopped) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentContainerStopped.Unmarshal(m, b)
+}
+func (m *AgentContainerStopped) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentContainerStopped.Marshal(b, m, deterministic)
+}
+func (m *AgentContainerStopped) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentContainerStopped.Merge(m, src)
+}
+func (m *AgentContainerStopped) XXX_Size() int {
+	return xxx_messageInfo_AgentContainerStopped.Size(m)
+}
+func (m *AgentContainerStopped) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentContainerStopped.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentContainerStopped proto.InternalMessageInfo
+
+func (m *AgentContainerStopped) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AgentContainerStopped) GetState() []byte {
+	if m != nil {
+		return m.State
+	}
+	return nil
+}
+
+type AgentInitDone struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentInitDone) Reset()         { *m = AgentInitDone{} }
+func (m *AgentInitDone) String() string { return proto.CompactTextString(m) }
+func (*AgentInitDone) ProtoMessage()    {}
+func (*AgentInitDone) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{4}
+}
+
+func (m *AgentInitDone) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentInitDone.Unmarshal(m, b)
+}
+func (m *AgentInitDone) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentInitDone.Marshal(b, m, deterministic)
+}
+func (m *AgentInitDone) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentInitDone.Merge(m, src)
+}
+func (m *AgentInitDone) XXX_Size() int {
+	return xxx_messageInfo_AgentInitDone.Size(m)
+}
+func (m *AgentInitDone) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentInitDone.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentInitDone proto.InternalMessageInfo
+
+type AgentDone struct {
+	// error is empty if the command succeeded
+	Error                string   `protobuf:"bytes,1,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentDone) Reset()         { *m = AgentDone{} }
+func (m *AgentDone) String() string { return proto.CompactTextString(m) }
+func (*AgentDone) ProtoMessage()    {}
+func (*AgentDone) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{5}
+}
+
+func (m *AgentDone) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentDone.Unmarshal(m, b)
+}
+func (m *AgentDone) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentDone.Marshal(b, m, deterministic)
+}
+func (m *AgentDone) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentDone.Merge(m, src)
+}
+func (m *AgentDone) XXX_Size() int {
+	return xxx_messageInfo_AgentDone.Size(m)
+}
+func (m *AgentDone) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentDone.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentDone proto.InternalMessageInfo
+
+func (m *AgentDone) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type AgentCommand struct {
+	// id identifies the command, so that the agent's answers can refer to it
+	Id uint64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Types that are valid to be assigned to Content:
+	//	*AgentCommand_RunPod
+	//	*AgentCommand_StopPod
+	//	*AgentCommand_RemovePod
+	//	*AgentCommand_Logs
+	//	*AgentCommand_Cancel
+	Content              isAgentCommand_Content `protobuf_oneof:"content"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *AgentCommand) Reset()         { *m = AgentCommand{} }
+func (m *AgentCommand) String() string { return proto.CompactTextString(m) }
+func (*AgentCommand) ProtoMessage()    {}
+func (*AgentCommand) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{6}
+}
+
+func (m *AgentCommand) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentCommand.Unmarshal(m, b)
+}
+func (m *AgentCommand) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentCommand.Marshal(b, m, deterministic)
+}
+func (m *AgentCommand) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentCommand.Merge(m, src)
+}
+func (m *AgentCommand) XXX_Size() int {
+	return xxx_messageInfo_AgentCommand.Size(m)
+}
+func (m *AgentCommand) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentCommand.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentCommand proto.InternalMessageInfo
+
+func (m *AgentCommand) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type isAgentCommand_Content interface {
+	isAgentCommand_Content()
+}
+
+type AgentCommand_RunPod struct {
+	RunPod *AgentRunPod `protobuf:"bytes,2,opt,name=run_pod,json=runPod,proto3,oneof"`
+}
+
+type AgentCommand_StopPod struct {
+	StopPod *AgentStopPod `protobuf:"bytes,3,opt,name=stop_pod,json=stopPod,proto3,oneof"`
+}
+
+type AgentCommand_RemovePod struct {
+	RemovePod *AgentRemovePod `protobuf:"bytes,4,opt,name=remove_pod,json=removePod,proto3,oneof"`
+}
+
+type AgentCommand_Logs struct {
+	Logs *AgentLogs `protobuf:"bytes,5,opt,name=logs,proto3,oneof"`
+}
+
+type AgentCommand_Cancel struct {
+	Cancel uint64 `protobuf:"varint,6,opt,name=cancel,proto3,oneof"`
+}
+
+func (*AgentCommand_RunPod) isAgentCommand_Content() {}
+
+func (*AgentCommand_StopPod) isAgentCommand_Content() {}
+
+func (*AgentCommand_RemovePod) isAgentCommand_Content() {}
+
+func (*AgentCommand_Logs) isAgentCommand_Content() {}
+
+func (*AgentCommand_Cancel) isAgentCommand_Content() {}
+
+func (m *AgentCommand) GetContent() isAgentCommand_Content {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *AgentCommand) GetRunPod() *AgentRunPod {
+	if x, ok := m.GetContent().(*AgentCommand_RunPod); ok {
+		return x.RunPod
+	}
+	return nil
+}
+
+func (m *AgentCommand) GetStopPod() *AgentStopPod {
+	if x, ok := m.GetContent().(*AgentCommand_StopPod); ok {
+		return x.StopPod
+	}
+	return nil
+}
+
+func (m *AgentCommand) GetRemovePod() *AgentRemovePod {
+	if x, ok := m.GetContent().(*AgentCommand_RemovePod); ok {
+		return x.RemovePod
+	}
+	return nil
+}
+
+func (m *AgentCommand) GetLogs() *AgentLogs {
+	if x, ok := m.GetContent().(*AgentCommand_Logs); ok {
+		return x.Logs
+	}
+	return nil
+}
+
+func (m *AgentCommand) GetCancel() uint64 {
+	if x, ok := m.GetContent().(*AgentCommand_Cancel); ok {
+		return x.Cancel
+	}
+	return 0
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*AgentCommand) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*AgentCommand_RunPod)(nil),
+		(*AgentCommand_StopPod)(nil),
+		(*AgentCommand_RemovePod)(nil),
+		(*AgentCommand_Logs)(nil),
+		(*AgentCommand_Cancel)(nil),
+	}
+}
+
+type AgentRunPod struct {
+	// pod is the Kubernetes pod of the job as JSON
+	Pod                  []byte   `protobuf:"bytes,1,opt,name=pod,proto3" json:"pod,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentRunPod) Reset()         { *m = AgentRunPod{} }
+func (m *AgentRunPod) String() string { return proto.CompactTextString(m) }
+func (*AgentRunPod) ProtoMessage()    {}
+func (*AgentRunPod) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{7}
+}
+
+func (m *AgentRunPod) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentRunPod.Unmarshal(m, b)
+}
+func (m *AgentRunPod) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentRunPod.Marshal(b, m, deterministic)
+}
+func (m *AgentRunPod) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentRunPod.Merge(m, src)
+}
+func (m *AgentRunPod) XXX_Size() int {
+	return xxx_messageInfo_AgentRunPod.Size(m)
+}
+func (m *AgentRunPod) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentRunPod.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentRunPod proto.InternalMessageInfo
+
+func (m *AgentRunPod) GetPod() []byte {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type AgentStopPod struct {
+	// pod is the Kubernetes pod of the job as JSON
+	Pod []byte `protobuf:"bytes,1,opt,name=pod,proto3" json:"pod,omitempty"`
+	// containers maps the names of the running containers to their IDs
+	Containers           map[string]string `protobuf:"bytes,2,rep,name=containers,proto3" json:"containers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	GracePeriodSeconds   int64             `protobuf:"varint,3,opt,name=grace_period_seconds,json=gracePeriodSeconds,proto3" json:"grace_period_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AgentStopPod) Reset()         { *m = AgentStopPod{} }
+func (m *AgentStopPod) String() string { return proto.CompactTextString(m) }
+func (*AgentStopPod) ProtoMessage()    {}
+func (*AgentStopPod) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{8}
+}
+
+func (m *AgentStopPod) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentStopPod.Unmarshal(m, b)
+}
+func (m *AgentStopPod) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentStopPod.Marshal(b, m, deterministic)
+}
+func (m *AgentStopPod) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentStopPod.Merge(m, src)
+}
+func (m *AgentStopPod) XXX_Size() int {
+	return xxx_messageInfo_AgentStopPod.Size(m)
+}
+func (m *AgentStopPod) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentStopPod.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentStopPod proto.InternalMessageInfo
+
+func (m *AgentStopPod) GetPod() []byte {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+func (m *AgentStopPod) GetContainers() map[string]string {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+func (m *AgentStopPod) GetGracePeriodSeconds() int64 {
+	if m != nil {
+		return m.GracePeriodSeconds
+	}
+	return 0
+}
+
+type AgentRemovePod struct {
+	// pod is the Kubernetes pod of the job as JSON
+	Pod []byte `protobuf:"bytes,1,opt,name=pod,proto3" json:"pod,omitempty"`
+	// containers maps the names of the containers which started to their IDs
+	Containers           map[string]string `protobuf:"bytes,2,rep,name=containers,proto3" json:"containers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AgentRemovePod) Reset()         { *m = AgentRemovePod{} }
+func (m *AgentRemovePod) String() string { return proto.CompactTextString(m) }
+func (*AgentRemovePod) ProtoMessage()    {}
+func (*AgentRemovePod) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{9}
+}
+
+func (m *AgentRemovePod) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentRemovePod.Unmarshal(m, b)
+}
+func (m *AgentRemovePod) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentRemovePod.Marshal(b, m, deterministic)
+}
+func (m *AgentRemovePod) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentRemovePod.Merge(m, src)
+}
+func (m *AgentRemovePod) XXX_Size() int {
+	return xxx_messageInfo_AgentRemovePod.Size(m)
+}
+func (m *AgentRemovePod) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentRemovePod.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentRemovePod proto.InternalMessageInfo
+
+func (m *AgentRemovePod) GetPod() []byte {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+func (m *AgentRemovePod) GetContainers() map[string]string {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+type AgentLogs struct {
+	ContainerId string `protobuf:"bytes,1,opt,name=container_id,json=containerId,proto3" json:"container_id,omitempty"`
+	// follow streams the logs until the container stops
+	Follow bool `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"`
+	// limit is the maximum number of bytes to send unless follow is set
+	Limit                int64    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AgentLogs) Reset()         { *m = AgentLogs{} }
+func (m *AgentLogs) String() string { return proto.CompactTextString(m) }
+func (*AgentLogs) ProtoMessage()    {}
+func (*AgentLogs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c688b27b4ee70914, []int{10}
+}
+
+func (m *AgentLogs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AgentLogs.Unmarshal(m, b)
+}
+func (m *AgentLogs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AgentLogs.Marshal(b, m, deterministic)
+}
+func (m *AgentLogs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AgentLogs.Merge(m, src)
+}
+func (m *AgentLogs) XXX_Size() int {
+	return xxx_messageInfo_AgentLogs.Size(m)
+}
+func (m *AgentLogs) XXX_DiscardUnknown() {
+	xxx_messageInfo_AgentLogs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AgentLogs proto.InternalMessageInfo
+
+func (m *AgentLogs) GetContainerId() string {
+	if m != nil {
+		return m.ContainerId
+	}
+	return ""
+}
+
+func (m *AgentLogs) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
+func (m *AgentLogs) GetLimit() int64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*AgentMessage)(nil), "v1.AgentMessage")
+	proto.RegisterType((*AgentHello)(nil), "v1.AgentHello")
+	proto.RegisterType((*AgentContainerStarted)(nil), "v1.AgentContainerStarted")
+	proto.RegisterType((*AgentContainerStopped)(nil), "v1.AgentContainerStopped")
+	proto.RegisterType((*AgentInitDone)(nil), "v1.AgentInitDone")
+	proto.RegisterType((*AgentDone)(nil), "v1.AgentDone")
+	proto.RegisterType((*AgentCommand)(nil), "v1.AgentCommand")
+	proto.RegisterType((*AgentRunPod)(nil), "v1.AgentRunPod")
+	proto.RegisterType((*AgentStopPod)(nil), "v1.AgentStopPod")
+	proto.RegisterMapType((map[string]string)(nil), "v1.AgentStopPod.ContainersEntry")
+	proto.RegisterType((*AgentRemovePod)(nil), "v1.AgentRemovePod")
+	proto.RegisterMapType((map[string]string)(nil), "v1.AgentRemovePod.ContainersEntry")
+	proto.RegisterType((*AgentLogs)(nil), "v1.AgentLogs")
+}
+
+func init() { proto.RegisterFile("werft-agent.proto", fileDescriptor_c688b27b4ee70914) }
+
+var fileDescriptor_c688b27b4ee70914 = []byte{
+	// 643 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdd, 0x6a, 0xdb, 0x4c,
+	0x10, 0xb5, 0x64, 0xf9, 0x47, 0x63, 0x27, 0x71, 0x96, 0x7c, 0x1f, 0x6a, 0x6e, 0xea, 0xa8, 0x50,
+	0x4c, 0x21, 0x26, 0x3f, 0x50, 0x4a, 0xa1, 0xd0, 0xc4, 0x2d, 0x38, 0xd0, 0xd2, 0xb0, 0xb9, 0x2d,
+	0x18, 0x55, 0x9a, 0xb8, 0x22, 0xf2, 0xae, 0x59, 0xad, 0x1d, 0xf2, 0x40, 0x7d, 0xa1, 0xf6, 0x65,
+	0x7a, 0x59, 0x76, 0x56, 0x96, 0x1d, 0xdb, 0xe9, 0x4d, 0xef, 0x66, 0x76, 0x67, 0x8e, 0x66, 0xce,
+	0x39, 0x5a, 0xd8, 0xbf, 0x47, 0x75, 0xab, 0x8f, 0xa3, 0x31, 0x0a, 0xdd, 0x9f, 0x2a, 0xa9, 0x25,
+	0x73, 0xe7, 0xa7, 0xe1, 0x4f, 0x17, 0xda, 0x17, 0xe6, 0xec, 0x33, 0xe6, 0x79, 0x34, 0x46, 0xb6,
+	0x0b, 0x6e, 0x9a, 0x04, 0x4e, 0xd7, 0xe9, 0x79, 0xdc, 0x4d, 0x13, 0xf6, 0x12, 0x6a, 0xdf, 0x31,
+	0xcb, 0x64, 0xe0, 0x76, 0x9d, 0x5e, 0xeb, 0x6c, 0xb7, 0x3f, 0x3f, 0xed, 0x53, 0xc3, 0xd0, 0x9c,
+	0x0e, 0x2b, 0xdc, 0x5e, 0xb3, 0x21, 0xec, 0xc7, 0x52, 0xe8, 0x28, 0x15, 0xa8, 0x46, 0xb9, 0x8e,
+	0x94, 0xc6, 0x24, 0xa8, 0x52, 0xcf, 0xb3, 0xb2, 0x67, 0xb0, 0xa8, 0xb8, 0xb1, 0x05, 0xc3, 0x0a,
+	0xef, 0xc4, 0x6b, 0x67, 0xeb, 0x48, 0x72, 0x3a, 0xc5, 0x24, 0xf0, 0x9e, 0x46, 0xa2, 0x82, 0x35,
+	0x24, 0x3a, 0x63, 0x27, 0xe0, 0xa7, 0x22, 0xd5, 0xa3, 0x44, 0x0a, 0x0c, 0x6a, 0x84, 0xb0, 0x5f,
+	0x22, 0x5c, 0x89, 0x54, 0x7f, 0x90, 0x02, 0x87, 0x15, 0xde, 0x4c, 0x8b, 0x98, 0x1d, 0x80, 0x97,
+	0xc9, 0x71, 0x1e, 0xd4, 0xbb, 0x4e, 0xaf, 0x3d, 0xac, 0x70, 0xca, 0xd8, 0x0b, 0xf0, 0x08, 0xa2,
+	0x41, 0x10, 0x3b, 0x25, 0x44, 0xd1, 0x4e, 0x97, 0x97, 0x3e, 0x34, 0xcc, 0x00, 0x28, 0x74, 0xf8,
+	0x1a, 0x60, 0x49, 0x11, 0x63, 0xe0, 0x89, 0x68, 0x82, 0xc4, 0xa9, 0xcf, 0x29, 0x66, 0x07, 0x50,
+	0xd3, 0xf2, 0x0e, 0x05, 0xb1, 0xea, 0x73, 0x9b, 0x84, 0x5f, 0xe0, 0xbf, 0xad, 0x34, 0x6d, 0x85,
+	0xb0, 0x42, 0xd9, 0x7e, 0x23, 0x14, 0x03, 0xcf, 0xac, 0x41, 0x9c, 0x37, 0x39, 0xc5, 0xe1, 0xc5,
+	0x26, 0xa0, 0x65, 0xe6, 0x89, 0x99, 0x72, 0x1d, 0x69, 0x24, 0xcc, 0x36, 0xb7, 0x49, 0xb8, 0x07,
+	0x3b, 0x8f, 0xe8, 0x0a, 0x8f, 0xc0, 0x2f, 0x97, 0x37, 0x3d, 0xa8, 0x94, 0x54, 0x05, 0x90, 0x4d,
+	0xc2, 0xdf, 0x4e, 0x61, 0xaa, 0x81, 0x9c, 0x4c, 0x22, 0x91, 0x6c, 0x98, 0xea, 0x15, 0x34, 0xd4,
+	0x4c, 0x8c, 0xa6, 0x32, 0x29, 0x6c, 0xb5, 0x57, 0x72, 0xca, 0x67, 0xe2, 0x5a, 0x1a, 0x39, 0xeb,
+	0x8a, 0x22, 0x76, 0x0c, 0x4d, 0x63, 0x02, 0x2a, 0xb6, 0x7e, 0xea, 0x94, 0xc5, 0x66, 0x1d, 0x5b,
+	0xdd, 0xc8, 0x6d, 0xc8, 0xce, 0x01, 0x14, 0x4e, 0xe4, 0x1c, 0xa9, 0xc1, 0xda, 0x86, 0x2d, 0xd1,
+	0xe9, 0xca, 0xb6, 0xf8, 0x6a, 0x91, 0x18, 0x81, 0x49, 0xf6, 0xda, 0x9a, 0xc0, 0x9f, 0xe4, 0x38,
+	0x2f, 0x5d, 0x10, 0x40, 0x3d, 0x8e, 0x44, 0x8c, 0x19, 0xb9, 0xc3, 0x33, 0x23, 0xda, 0x7c, 0x55,
+	0xfa, 0xe7, 0xd0, 0x5a, 0x59, 0x83, 0x75, 0xa0, 0x3a, 0x95, 0x76, 0xf3, 0x36, 0x37, 0x61, 0xf8,
+	0x6b, 0xc1, 0x4d, 0x31, 0xfb, 0x66, 0x09, 0x7b, 0x0f, 0x50, 0x5a, 0x39, 0x0f, 0xdc, 0x6e, 0xb5,
+	0xd7, 0x3a, 0xeb, 0xae, 0xef, 0xdc, 0x2f, 0x35, 0xcd, 0x3f, 0x0a, 0xad, 0x1e, 0xf8, 0x4a, 0x0f,
+	0x3b, 0x81, 0x83, 0xb1, 0x8a, 0x62, 0x1c, 0x4d, 0x51, 0xa5, 0x32, 0x19, 0xe5, 0x18, 0x4b, 0x91,
+	0xe4, 0xc4, 0x5f, 0x95, 0x33, 0xba, 0xbb, 0xa6, 0xab, 0x1b, 0x7b, 0x73, 0xf8, 0x0e, 0xf6, 0xd6,
+	0x00, 0xcd, 0x60, 0x77, 0xf8, 0x50, 0x28, 0x6b, 0x42, 0xa3, 0xf6, 0x3c, 0xca, 0x66, 0xb8, 0x70,
+	0x2d, 0x25, 0x6f, 0xdd, 0x37, 0x4e, 0xf8, 0xc3, 0x81, 0xdd, 0xc7, 0x04, 0x6f, 0xd9, 0xeb, 0x72,
+	0xcb, 0x5e, 0xe1, 0xa6, 0x34, 0x7f, 0xdb, 0xec, 0x5f, 0xe7, 0xfc, 0x0a, 0x7e, 0x29, 0x2c, 0x3b,
+	0x82, 0xf6, 0xf2, 0xa1, 0x29, 0xfc, 0xe9, 0xf3, 0x56, 0x79, 0x76, 0x95, 0xb0, 0xff, 0xa1, 0x7e,
+	0x2b, 0xb3, 0x4c, 0xde, 0x13, 0x54, 0x93, 0x17, 0x99, 0xf9, 0x42, 0x96, 0x4e, 0x8a, 0xbf, 0xad,
+	0xca, 0x6d, 0x72, 0x36, 0x58, 0x48, 0x8b, 0x6a, 0x9e, 0xc6, 0xc8, 0xce, 0xa1, 0x31, 0x90, 0x42,
+	0x60, 0xac, 0xd9, 0xd2, 0xb3, 0xc5, 0x43, 0x7b, 0xd8, 0x59, 0x79, 0xcb, 0xe8, 0x2f, 0x09, 0x2b,
+	0x3d, 0xe7, 0xc4, 0xf9, 0x56, 0xa7, 0xc7, 0xf9, 0xfc, 0xcf, 0x00, 0x56, 0x05, 0x7c, 0xb1, 0xb1,
+	0x05, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AgentServiceClient is the client API for AgentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AgentServiceClient interface {
+	// Connect is called by agents. The agent introduces itself with its first message, then runs the commands the
+	// server sends and reports back until either side closes the stream.
+	Connect(ctx context.Context, opts ...grpc.CallOption) (AgentService_ConnectClient, error)
+}
+
+type agentServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAgentServiceClient(cc *grpc.ClientConn) AgentServiceClient {
+	return &agentServiceClient{cc}
+}
+
+func (c *agentServiceClient) Connect(ctx context.Context, opts ...grpc.CallOption) (AgentService_ConnectClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AgentService_serviceDesc.Streams[0], "/v1.AgentService/Connect", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &agentServiceConnectClient{stream}
+	return x, nil
+}
+
+type AgentService_ConnectClient interface {
+	Send(*AgentMessage) error
+	Recv() (*AgentCommand, error)
+	grpc.ClientStream
+}
+
+type agentServiceConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *agentServiceConnectClient) Send(m *AgentMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *agentServiceConnectClient) Recv() (*AgentCommand, error) {
+	m := new(AgentCommand)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AgentServiceServer is the server API for AgentService service.
+type AgentServiceServer interface {
+	// Connect is called by agents. The agent introduces itself with its first message, then runs the commands the
+	// server sends and reports back until either side closes the stream.
+	Connect(AgentService_ConnectServer) error
+}
+
+// UnimplementedAgentServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedAgentServiceServer struct {
+}
+
+func (*UnimplementedAgentServiceServer) Connect(srv AgentService_ConnectServer) error {
+	return status.Errorf(codes.Unimplemented, "method Connect not implemented")
+}
+
+func RegisterAgentServiceServer(s *grpc.Server, srv AgentServiceServer) {
+	s.RegisterService(&_AgentService_serviceDesc, srv)
+}
+
+func _AgentService_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AgentServiceServer).Connect(&agentServiceConnectServer{stream})
+}
+
+type AgentService_ConnectServer interface {
+	Send(*AgentCommand) error
+	Recv() (*AgentMessage, error)
+	grpc.ServerStream
+}
+
+type agentServiceConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *agentServiceConnectServer) Send(m *AgentCommand) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *agentServiceConnectServer) Recv() (*AgentMessage, error) {
+	m := new(AgentMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _AgentService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.AgentService",
+	HandlerType: (*AgentServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _AgentService_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "werft-agent.proto",
+}
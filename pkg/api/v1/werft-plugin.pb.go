@@ -0,0 +1,1140 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: werft-plugin.proto
+
+package v1
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Hook int32
+
+const (
+	Hook_HOOK_UNKNOWN         Hook = 0
+	Hook_HOOK_FILTER_WEBHOOK  Hook = 1
+	Hook_HOOK_MUTATE_JOB      Hook = 2
+	Hook_HOOK_JOB_DONE        Hook = 3
+	Hook_HOOK_PRE_CREATE_POD  Hook = 4
+	Hook_HOOK_POST_CREATE_POD Hook = 5
+	Hook_HOOK_PRE_DELETE_POD  Hook = 6
+)
+
+var Hook_name = map[int32]string{
+	0: "HOOK_UNKNOWN",
+	1: "HOOK_FILTER_WEBHOOK",
+	2: "HOOK_MUTATE_JOB",
+	3: "HOOK_JOB_DONE",
+	4: "HOOK_PRE_CREATE_POD",
+	5: "HOOK_POST_CREATE_POD",
+	6: "HOOK_PRE_DELETE_POD",
+}
+
+var Hook_value = map[string]int32{
+	"HOOK_UNKNOWN":         0,
+	"HOOK_FILTER_WEBHOOK":  1,
+	"HOOK_MUTATE_JOB":      2,
+	"HOOK_JOB_DONE":        3,
+	"HOOK_PRE_CREATE_POD":  4,
+	"HOOK_POST_CREATE_POD": 5,
+	"HOOK_PRE_DELETE_POD":  6,
+}
+
+func (x Hook) String() string {
+	return proto.EnumName(Hook_name, int32(x))
+}
+
+func (Hook) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{0}
+}
+
+type ParseLogRequest struct {
+	// job is the name of the job which produced the line
+	Job string `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	// slice is the name of the log slice the line belongs to
+	Slice                string   `protobuf:"bytes,2,opt,name=slice,proto3" json:"slice,omitempty"`
+	Line                 string   `protobuf:"bytes,3,opt,name=line,proto3" json:"line,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ParseLogRequest) Reset()         { *m = ParseLogRequest{} }
+func (m *ParseLogRequest) String() string { return proto.CompactTextString(m) }
+func (*ParseLogRequest) ProtoMessage()    {}
+func (*ParseLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{0}
+}
+
+func (m *ParseLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParseLogRequest.Unmarshal(m, b)
+}
+func (m *ParseLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParseLogRequest.Marshal(b, m, deterministic)
+}
+func (m *ParseLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParseLogRequest.Merge(m, src)
+}
+func (m *ParseLogRequest) XXX_Size() int {
+	return xxx_messageInfo_ParseLogRequest.Size(m)
+}
+func (m *ParseLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParseLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParseLogRequest proto.InternalMessageInfo
+
+func (m *ParseLogRequest) GetJob() string {
+	if m != nil {
+		return m.Job
+	}
+	return ""
+}
+
+func (m *ParseLogRequest) GetSlice() string {
+	if m != nil {
+		return m.Slice
+	}
+	return ""
+}
+
+func (m *ParseLogRequest) GetLine() string {
+	if m != nil {
+		return m.Line
+	}
+	return ""
+}
+
+type ParseLogResponse struct {
+	Result               *JobResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ParseLogResponse) Reset()         { *m = ParseLogResponse{} }
+func (m *ParseLogResponse) String() string { return proto.CompactTextString(m) }
+func (*ParseLogResponse) ProtoMessage()    {}
+func (*ParseLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{1}
+}
+
+func (m *ParseLogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ParseLogResponse.Unmarshal(m, b)
+}
+func (m *ParseLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ParseLogResponse.Marshal(b, m, deterministic)
+}
+func (m *ParseLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ParseLogResponse.Merge(m, src)
+}
+func (m *ParseLogResponse) XXX_Size() int {
+	return xxx_messageInfo_ParseLogResponse.Size(m)
+}
+func (m *ParseLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ParseLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ParseLogResponse proto.InternalMessageInfo
+
+func (m *ParseLogResponse) GetResult() *JobResult {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type HookInfoRequest struct {
+	// api_version is the version of the hook API werft speaks
+	ApiVersion           uint32   `protobuf:"varint,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HookInfoRequest) Reset()         { *m = HookInfoRequest{} }
+func (m *HookInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*HookInfoRequest) ProtoMessage()    {}
+func (*HookInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{2}
+}
+
+func (m *HookInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HookInfoRequest.Unmarshal(m, b)
+}
+func (m *HookInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HookInfoRequest.Marshal(b, m, deterministic)
+}
+func (m *HookInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HookInfoRequest.Merge(m, src)
+}
+func (m *HookInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_HookInfoRequest.Size(m)
+}
+func (m *HookInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HookInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HookInfoRequest proto.InternalMessageInfo
+
+func (m *HookInfoRequest) GetApiVersion() uint32 {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return 0
+}
+
+type HookInfoResponse struct {
+	// api_version is the version of the hook API the plugin speaks. Werft refuses to start plugins which speak a different version.
+	ApiVersion uint32 `protobuf:"varint,1,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	// hooks lists the hooks the plugin implements
+	Hooks                []Hook   `protobuf:"varint,2,rep,packed,name=hooks,proto3,enum=v1.Hook" json:"hooks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HookInfoResponse) Reset()         { *m = HookInfoResponse{} }
+func (m *HookInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*HookInfoResponse) ProtoMessage()    {}
+func (*HookInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{3}
+}
+
+func (m *HookInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HookInfoResponse.Unmarshal(m, b)
+}
+func (m *HookInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HookInfoResponse.Marshal(b, m, deterministic)
+}
+func (m *HookInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HookInfoResponse.Merge(m, src)
+}
+func (m *HookInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_HookInfoResponse.Size(m)
+}
+func (m *HookInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HookInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HookInfoResponse proto.InternalMessageInfo
+
+func (m *HookInfoResponse) GetApiVersion() uint32 {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return 0
+}
+
+func (m *HookInfoResponse) GetHooks() []Hook {
+	if m != nil {
+		return m.Hooks
+	}
+	return nil
+}
+
+type FilterWebhookRequest struct {
+	// event_type is the type of the event, e.g. the X-GitHub-Event header
+	EventType            string   `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FilterWebhookRequest) Reset()         { *m = FilterWebhookRequest{} }
+func (m *FilterWebhookRequest) String() string { return proto.CompactTextString(m) }
+func (*FilterWebhookRequest) ProtoMessage()    {}
+func (*FilterWebhookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{4}
+}
+
+func (m *FilterWebhookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilterWebhookRequest.Unmarshal(m, b)
+}
+func (m *FilterWebhookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilterWebhookRequest.Marshal(b, m, deterministic)
+}
+func (m *FilterWebhookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterWebhookRequest.Merge(m, src)
+}
+func (m *FilterWebhookRequest) XXX_Size() int {
+	return xxx_messageInfo_FilterWebhookRequest.Size(m)
+}
+func (m *FilterWebhookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterWebhookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterWebhookRequest proto.InternalMessageInfo
+
+func (m *FilterWebhookRequest) GetEventType() string {
+	if m != nil {
+		return m.EventType
+	}
+	return ""
+}
+
+func (m *FilterWebhookRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type FilterWebhookResponse struct {
+	// drop makes werft ignore the event
+	Drop bool `protobuf:"varint,1,opt,name=drop,proto3" json:"drop,omitempty"`
+	// reason explains why the event is dropped
+	Reason               string   `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FilterWebhookResponse) Reset()         { *m = FilterWebhookResponse{} }
+func (m *FilterWebhookResponse) String() string { return proto.CompactTextString(m) }
+func (*FilterWebhookResponse) ProtoMessage()    {}
+func (*FilterWebhookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{5}
+}
+
+func (m *FilterWebhookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FilterWebhookResponse.Unmarshal(m, b)
+}
+func (m *FilterWebhookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FilterWebhookResponse.Marshal(b, m, deterministic)
+}
+func (m *FilterWebhookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FilterWebhookResponse.Merge(m, src)
+}
+func (m *FilterWebhookResponse) XXX_Size() int {
+	return xxx_messageInfo_FilterWebhookResponse.Size(m)
+}
+func (m *FilterWebhookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FilterWebhookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FilterWebhookResponse proto.InternalMessageInfo
+
+func (m *FilterWebhookResponse) GetDrop() bool {
+	if m != nil {
+		return m.Drop
+	}
+	return false
+}
+
+func (m *FilterWebhookResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type MutateJobRequest struct {
+	Name     string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata *JobMetadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// pod_spec is the Kubernetes pod spec of the job as JSON, before werft adds its checkout and policies
+	PodSpec              []byte   `protobuf:"bytes,3,opt,name=pod_spec,json=podSpec,proto3" json:"pod_spec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MutateJobRequest) Reset()         { *m = MutateJobRequest{} }
+func (m *MutateJobRequest) String() string { return proto.CompactTextString(m) }
+func (*MutateJobRequest) ProtoMessage()    {}
+func (*MutateJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{6}
+}
+
+func (m *MutateJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MutateJobRequest.Unmarshal(m, b)
+}
+func (m *MutateJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MutateJobRequest.Marshal(b, m, deterministic)
+}
+func (m *MutateJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MutateJobRequest.Merge(m, src)
+}
+func (m *MutateJobRequest) XXX_Size() int {
+	return xxx_messageInfo_MutateJobRequest.Size(m)
+}
+func (m *MutateJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_MutateJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MutateJobRequest proto.InternalMessageInfo
+
+func (m *MutateJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *MutateJobRequest) GetMetadata() *JobMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *MutateJobRequest) GetPodSpec() []byte {
+	if m != nil {
+		return m.PodSpec
+	}
+	return nil
+}
+
+type MutateJobResponse struct {
+	// pod_spec replaces the pod spec of the job if it's not empty
+	PodSpec []byte `protobuf:"bytes,1,opt,name=pod_spec,json=podSpec,proto3" json:"pod_spec,omitempty"`
+	// annotations are added to the job, replacing existing annotations with the same key
+	Annotations []*Annotation `protobuf:"bytes,2,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	// reject keeps the job from starting if it's not empty, and explains why
+	Reject               string   `protobuf:"bytes,3,opt,name=reject,proto3" json:"reject,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MutateJobResponse) Reset()         { *m = MutateJobResponse{} }
+func (m *MutateJobResponse) String() string { return proto.CompactTextString(m) }
+func (*MutateJobResponse) ProtoMessage()    {}
+func (*MutateJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{7}
+}
+
+func (m *MutateJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MutateJobResponse.Unmarshal(m, b)
+}
+func (m *MutateJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MutateJobResponse.Marshal(b, m, deterministic)
+}
+func (m *MutateJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MutateJobResponse.Merge(m, src)
+}
+func (m *MutateJobResponse) XXX_Size() int {
+	return xxx_messageInfo_MutateJobResponse.Size(m)
+}
+func (m *MutateJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_MutateJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MutateJobResponse proto.InternalMessageInfo
+
+func (m *MutateJobResponse) GetPodSpec() []byte {
+	if m != nil {
+		return m.PodSpec
+	}
+	return nil
+}
+
+func (m *MutateJobResponse) GetAnnotations() []*Annotation {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *MutateJobResponse) GetReject() string {
+	if m != nil {
+		return m.Reject
+	}
+	return ""
+}
+
+type JobDoneRequest struct {
+	Job                  *JobStatus `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *JobDoneRequest) Reset()         { *m = JobDoneRequest{} }
+func (m *JobDoneRequest) String() string { return proto.CompactTextString(m) }
+func (*JobDoneRequest) ProtoMessage()    {}
+func (*JobDoneRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{8}
+}
+
+func (m *JobDoneRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobDoneRequest.Unmarshal(m, b)
+}
+func (m *JobDoneRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobDoneRequest.Marshal(b, m, deterministic)
+}
+func (m *JobDoneRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobDoneRequest.Merge(m, src)
+}
+func (m *JobDoneRequest) XXX_Size() int {
+	return xxx_messageInfo_JobDoneRequest.Size(m)
+}
+func (m *JobDoneRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobDoneRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobDoneRequest proto.InternalMessageInfo
+
+func (m *JobDoneRequest) GetJob() *JobStatus {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+type JobDoneResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobDoneResponse) Reset()         { *m = JobDoneResponse{} }
+func (m *JobDoneResponse) String() string { return proto.CompactTextString(m) }
+func (*JobDoneResponse) ProtoMessage()    {}
+func (*JobDoneResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{9}
+}
+
+func (m *JobDoneResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobDoneResponse.Unmarshal(m, b)
+}
+func (m *JobDoneResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobDoneResponse.Marshal(b, m, deterministic)
+}
+func (m *JobDoneResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobDoneResponse.Merge(m, src)
+}
+func (m *JobDoneResponse) XXX_Size() int {
+	return xxx_messageInfo_JobDoneResponse.Size(m)
+}
+func (m *JobDoneResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobDoneResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobDoneResponse proto.InternalMessageInfo
+
+type PodHookRequest struct {
+	// pod is the Kubernetes pod of the job as JSON
+	Pod                  []byte   `protobuf:"bytes,1,opt,name=pod,proto3" json:"pod,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PodHookRequest) Reset()         { *m = PodHookRequest{} }
+func (m *PodHookRequest) String() string { return proto.CompactTextString(m) }
+func (*PodHookRequest) ProtoMessage()    {}
+func (*PodHookRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{10}
+}
+
+func (m *PodHookRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodHookRequest.Unmarshal(m, b)
+}
+func (m *PodHookRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodHookRequest.Marshal(b, m, deterministic)
+}
+func (m *PodHookRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodHookRequest.Merge(m, src)
+}
+func (m *PodHookRequest) XXX_Size() int {
+	return xxx_messageInfo_PodHookRequest.Size(m)
+}
+func (m *PodHookRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodHookRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PodHookRequest proto.InternalMessageInfo
+
+func (m *PodHookRequest) GetPod() []byte {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+type PreCreatePodResponse struct {
+	// pod replaces the pod of the job if it's not empty
+	Pod []byte `protobuf:"bytes,1,opt,name=pod,proto3" json:"pod,omitempty"`
+	// reject keeps the job from starting if it's not empty, and explains why
+	Reject               string   `protobuf:"bytes,2,opt,name=reject,proto3" json:"reject,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PreCreatePodResponse) Reset()         { *m = PreCreatePodResponse{} }
+func (m *PreCreatePodResponse) String() string { return proto.CompactTextString(m) }
+func (*PreCreatePodResponse) ProtoMessage()    {}
+func (*PreCreatePodResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{11}
+}
+
+func (m *PreCreatePodResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreCreatePodResponse.Unmarshal(m, b)
+}
+func (m *PreCreatePodResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreCreatePodResponse.Marshal(b, m, deterministic)
+}
+func (m *PreCreatePodResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreCreatePodResponse.Merge(m, src)
+}
+func (m *PreCreatePodResponse) XXX_Size() int {
+	return xxx_messageInfo_PreCreatePodResponse.Size(m)
+}
+func (m *PreCreatePodResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreCreatePodResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreCreatePodResponse proto.InternalMessageInfo
+
+func (m *PreCreatePodResponse) GetPod() []byte {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
+func (m *PreCreatePodResponse) GetReject() string {
+	if m != nil {
+		return m.Reject
+	}
+	return ""
+}
+
+type PodHookResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PodHookResponse) Reset()         { *m = PodHookResponse{} }
+func (m *PodHookResponse) String() string { return proto.CompactTextString(m) }
+func (*PodHookResponse) ProtoMessage()    {}
+func (*PodHookResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9a931a6e0aa932ef, []int{12}
+}
+
+func (m *PodHookResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PodHookResponse.Unmarshal(m, b)
+}
+func (m *PodHookResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PodHookResponse.Marshal(b, m, deterministic)
+}
+func (m *PodHookResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PodHookResponse.Merge(m, src)
+}
+func (m *PodHookResponse) XXX_Size() int {
+	return xxx_messageInfo_PodHookResponse.Size(m)
+}
+func (m *PodHookResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PodHookResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PodHookResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterEnum("v1.Hook", Hook_name, Hook_value)
+	proto.RegisterType((*ParseLogRequest)(nil), "v1.ParseLogRequest")
+	proto.RegisterType((*ParseLogResponse)(nil), "v1.ParseLogResponse")
+	proto.RegisterType((*HookInfoRequest)(nil), "v1.HookInfoRequest")
+	proto.RegisterType((*HookInfoResponse)(nil), "v1.HookInfoResponse")
+	proto.RegisterType((*FilterWebhookRequest)(nil), "v1.FilterWebhookRequest")
+	proto.RegisterType((*FilterWebhookResponse)(nil), "v1.FilterWebhookResponse")
+	proto.RegisterType((*MutateJobRequest)(nil), "v1.MutateJobRequest")
+	proto.RegisterType((*MutateJobResponse)(nil), "v1.MutateJobResponse")
+	proto.RegisterType((*JobDoneRequest)(nil), "v1.JobDoneRequest")
+	proto.RegisterType((*JobDoneResponse)(nil), "v1.JobDoneResponse")
+	proto.RegisterType((*PodHookRequest)(nil), "v1.PodHookRequest")
+	proto.RegisterType((*PreCreatePodResponse)(nil), "v1.PreCreatePodResponse")
+	proto.RegisterType((*PodHookResponse)(nil), "v1.PodHookResponse")
+}
+
+func init() { proto.RegisterFile("werft-plugin.proto", fileDescriptor_9a931a6e0aa932ef) }
+
+var fileDescriptor_9a931a6e0aa932ef = []byte{
+	// 730 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x5d, 0x4f, 0xdb, 0x4a,
+	0x10, 0x25, 0x5f, 0x10, 0x26, 0x24, 0x71, 0x36, 0xe1, 0x5e, 0x13, 0xe9, 0xde, 0x22, 0x4b, 0x95,
+	0x50, 0xab, 0x22, 0x08, 0x7d, 0x28, 0x7d, 0xa8, 0x0a, 0xc4, 0x08, 0x42, 0x12, 0x5b, 0x4e, 0x28,
+	0x8f, 0xd6, 0x26, 0x5e, 0xa8, 0xc1, 0x78, 0x5d, 0x7b, 0x93, 0x96, 0x1f, 0xd4, 0x3f, 0xd0, 0x5f,
+	0x58, 0xed, 0x7a, 0xe3, 0xd8, 0x29, 0x6a, 0xd5, 0xb7, 0xdd, 0x33, 0x33, 0x67, 0xce, 0x4e, 0xce,
+	0x38, 0x80, 0xbe, 0x92, 0xf0, 0x96, 0xbd, 0x09, 0xbc, 0xd9, 0x9d, 0xeb, 0xef, 0x07, 0x21, 0x65,
+	0x14, 0xe5, 0xe7, 0x87, 0xed, 0x8a, 0xc0, 0x63, 0x40, 0x1b, 0x40, 0xdd, 0xc4, 0x61, 0x44, 0xfa,
+	0xf4, 0xce, 0x22, 0x5f, 0x66, 0x24, 0x62, 0x48, 0x81, 0xc2, 0x3d, 0x9d, 0xa8, 0xb9, 0xdd, 0xdc,
+	0xde, 0xa6, 0xc5, 0x8f, 0xa8, 0x05, 0xa5, 0xc8, 0x73, 0xa7, 0x44, 0xcd, 0x0b, 0x2c, 0xbe, 0x20,
+	0x04, 0x45, 0xcf, 0xf5, 0x89, 0x5a, 0x10, 0xa0, 0x38, 0x6b, 0xc7, 0xa0, 0x2c, 0xe9, 0xa2, 0x80,
+	0xfa, 0x11, 0x41, 0x2f, 0x61, 0x3d, 0x24, 0xd1, 0xcc, 0x63, 0x82, 0xb2, 0xd2, 0xa9, 0xee, 0xcf,
+	0x0f, 0xf7, 0x7b, 0x74, 0x62, 0x09, 0xd0, 0x92, 0x41, 0xad, 0x03, 0xf5, 0x0b, 0x4a, 0x1f, 0x2e,
+	0xfd, 0x5b, 0xba, 0x50, 0xf2, 0x02, 0x2a, 0x38, 0x70, 0xed, 0x39, 0x09, 0x23, 0x97, 0xfa, 0xa2,
+	0xbc, 0x6a, 0x01, 0x0e, 0xdc, 0x4f, 0x31, 0xa2, 0x8d, 0x40, 0x59, 0xd6, 0xc8, 0x76, 0x7f, 0x2a,
+	0x42, 0xff, 0x43, 0xe9, 0x33, 0xa5, 0x0f, 0x91, 0x9a, 0xdf, 0x2d, 0xec, 0xd5, 0x3a, 0x65, 0x2e,
+	0x87, 0xb3, 0x58, 0x31, 0xac, 0x19, 0xd0, 0x3a, 0x77, 0x3d, 0x46, 0xc2, 0x1b, 0x32, 0xe1, 0xc8,
+	0x42, 0xcd, 0x7f, 0x00, 0x64, 0x4e, 0x7c, 0x66, 0xb3, 0xa7, 0x80, 0xc8, 0xf1, 0x6c, 0x0a, 0x64,
+	0xfc, 0x14, 0x10, 0xa4, 0xc2, 0x46, 0x80, 0x9f, 0x3c, 0x8a, 0x1d, 0x31, 0xa6, 0x2d, 0x6b, 0x71,
+	0xd5, 0xce, 0x60, 0x7b, 0x85, 0x50, 0x4a, 0x45, 0x50, 0x74, 0x42, 0x1a, 0x08, 0xae, 0xb2, 0x25,
+	0xce, 0xe8, 0x1f, 0x3e, 0x2d, 0x1c, 0x51, 0x5f, 0x0e, 0x5b, 0xde, 0x34, 0x1f, 0x94, 0xc1, 0x8c,
+	0x61, 0x46, 0xc4, 0xe4, 0x62, 0x45, 0x08, 0x8a, 0x3e, 0x7e, 0x5c, 0x68, 0x11, 0x67, 0xf4, 0x1a,
+	0xca, 0x8f, 0x84, 0x61, 0x07, 0x33, 0x2c, 0x18, 0x2a, 0x9d, 0xba, 0x9c, 0xf7, 0x40, 0xc2, 0x56,
+	0x92, 0x80, 0x76, 0xa0, 0x1c, 0x50, 0xc7, 0x8e, 0x02, 0x32, 0x55, 0x0b, 0x52, 0x34, 0x75, 0x46,
+	0x01, 0x99, 0x6a, 0xdf, 0xa0, 0x91, 0xea, 0x27, 0x05, 0xa7, 0xf3, 0x73, 0x99, 0x7c, 0x74, 0x00,
+	0x15, 0xec, 0xfb, 0x94, 0x61, 0xe6, 0x52, 0x3f, 0x9e, 0x6d, 0xa5, 0x53, 0xe3, 0xad, 0x4f, 0x12,
+	0xd8, 0x4a, 0xa7, 0xc4, 0x2f, 0xbd, 0x27, 0x53, 0x26, 0x1d, 0x24, 0x6f, 0xda, 0x21, 0xd4, 0x7a,
+	0x74, 0xd2, 0xa5, 0x3e, 0x59, 0xfa, 0x20, 0x71, 0xe4, 0xd2, 0x3e, 0x23, 0x86, 0xd9, 0x2c, 0x12,
+	0x06, 0xd5, 0x1a, 0x50, 0x4f, 0x4a, 0x62, 0xa9, 0x9a, 0x06, 0x35, 0x93, 0x3a, 0x17, 0xa9, 0xdf,
+	0x4f, 0x81, 0x42, 0x40, 0x1d, 0xa9, 0x9b, 0x1f, 0xb5, 0x8f, 0xd0, 0x32, 0x43, 0x72, 0x16, 0x12,
+	0xcc, 0x88, 0x49, 0x9d, 0xe4, 0x99, 0xbf, 0x64, 0xa6, 0xb4, 0xe6, 0x33, 0x5a, 0x1b, 0x50, 0x4f,
+	0xba, 0xc4, 0xc5, 0xaf, 0xbe, 0xe7, 0xa0, 0xc8, 0x01, 0xa4, 0xc0, 0xd6, 0x85, 0x61, 0x5c, 0xd9,
+	0xd7, 0xc3, 0xab, 0xa1, 0x71, 0x33, 0x54, 0xd6, 0xd0, 0xbf, 0xd0, 0x14, 0xc8, 0xf9, 0x65, 0x7f,
+	0xac, 0x5b, 0xf6, 0x8d, 0x7e, 0xca, 0xaf, 0x4a, 0x0e, 0x35, 0xa1, 0x2e, 0x02, 0x83, 0xeb, 0xf1,
+	0xc9, 0x58, 0xb7, 0x7b, 0xc6, 0xa9, 0x92, 0x47, 0x0d, 0xa8, 0x0a, 0xb0, 0x67, 0x9c, 0xda, 0x5d,
+	0x63, 0xa8, 0x2b, 0x85, 0x84, 0xc0, 0xb4, 0x74, 0xfb, 0xcc, 0xd2, 0x79, 0xae, 0x69, 0x74, 0x95,
+	0x22, 0x52, 0xa1, 0x15, 0x07, 0x8c, 0xd1, 0x38, 0x1d, 0x29, 0x65, 0x4a, 0xba, 0x7a, 0x5f, 0x97,
+	0x81, 0xf5, 0xce, 0x15, 0xd4, 0xfb, 0xf4, 0x4e, 0x6c, 0x6b, 0x68, 0x8a, 0x6f, 0x04, 0x7a, 0x07,
+	0x25, 0x71, 0x47, 0x4d, 0x3e, 0xe3, 0x95, 0xef, 0x42, 0xbb, 0x95, 0x05, 0xe5, 0x9c, 0xd7, 0xf6,
+	0x72, 0x07, 0xb9, 0xce, 0x8f, 0x02, 0x00, 0x7f, 0xb4, 0x24, 0x3a, 0x82, 0x22, 0xdf, 0xc9, 0x98,
+	0x67, 0x65, 0xab, 0xdb, 0xad, 0x2c, 0xb8, 0xe0, 0x41, 0xe7, 0x50, 0xcd, 0xac, 0x09, 0x52, 0x79,
+	0xe2, 0x73, 0xab, 0xd8, 0xde, 0x79, 0x26, 0x92, 0xf0, 0xbc, 0x87, 0xcd, 0xc4, 0xb9, 0x48, 0x34,
+	0x5b, 0x5d, 0x9c, 0xf6, 0xf6, 0x0a, 0x9a, 0xd4, 0xbe, 0x85, 0x0d, 0x69, 0x24, 0x84, 0xa4, 0xcf,
+	0x52, 0x46, 0x6c, 0x37, 0x33, 0x58, 0x52, 0xf5, 0x01, 0xb6, 0xd2, 0x3e, 0x8a, 0x4b, 0xb3, 0xee,
+	0x6b, 0x8b, 0xc7, 0x3c, 0xe7, 0x36, 0xa1, 0xb8, 0x6a, 0xd2, 0x88, 0xfd, 0x9e, 0xa0, 0x99, 0xc1,
+	0x92, 0xda, 0x63, 0xd1, 0xbb, 0x4b, 0x3c, 0xf2, 0xb7, 0xa5, 0x93, 0x75, 0xf1, 0x17, 0x70, 0xf4,
+	0x73, 0x00, 0xe2, 0xce, 0x22, 0x3f, 0x29, 0x06, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// LogParserPluginClient is the client API for LogParserPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LogParserPluginClient interface {
+	// Parse receives the log of a single job line by line, and sends back the results found in it.
+	// The plugin may send results until the log is complete and werft closes the request stream.
+	Parse(ctx context.Context, opts ...grpc.CallOption) (LogParserPlugin_ParseClient, error)
+}
+
+type logParserPluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLogParserPluginClient(cc *grpc.ClientConn) LogParserPluginClient {
+	return &logParserPluginClient{cc}
+}
+
+func (c *logParserPluginClient) Parse(ctx context.Context, opts ...grpc.CallOption) (LogParserPlugin_ParseClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogParserPlugin_serviceDesc.Streams[0], "/v1.LogParserPlugin/Parse", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logParserPluginParseClient{stream}
+	return x, nil
+}
+
+type LogParserPlugin_ParseClient interface {
+	Send(*ParseLogRequest) error
+	Recv() (*ParseLogResponse, error)
+	grpc.ClientStream
+}
+
+type logParserPluginParseClient struct {
+	grpc.ClientStream
+}
+
+func (x *logParserPluginParseClient) Send(m *ParseLogRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *logParserPluginParseClient) Recv() (*ParseLogResponse, error) {
+	m := new(ParseLogResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LogParserPluginServer is the server API for LogParserPlugin service.
+type LogParserPluginServer interface {
+	// Parse receives the log of a single job line by line, and sends back the results found in it.
+	// The plugin may send results until the log is complete and werft closes the request stream.
+	Parse(LogParserPlugin_ParseServer) error
+}
+
+// UnimplementedLogParserPluginServer can be embedded to have forward compatible implementations.
+type UnimplementedLogParserPluginServer struct {
+}
+
+func (*UnimplementedLogParserPluginServer) Parse(srv LogParserPlugin_ParseServer) error {
+	return status.Errorf(codes.Unimplemented, "method Parse not implemented")
+}
+
+func RegisterLogParserPluginServer(s *grpc.Server, srv LogParserPluginServer) {
+	s.RegisterService(&_LogParserPlugin_serviceDesc, srv)
+}
+
+func _LogParserPlugin_Parse_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LogParserPluginServer).Parse(&logParserPluginParseServer{stream})
+}
+
+type LogParserPlugin_ParseServer interface {
+	Send(*ParseLogResponse) error
+	Recv() (*ParseLogRequest, error)
+	grpc.ServerStream
+}
+
+type logParserPluginParseServer struct {
+	grpc.ServerStream
+}
+
+func (x *logParserPluginParseServer) Send(m *ParseLogResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *logParserPluginParseServer) Recv() (*ParseLogRequest, error) {
+	m := new(ParseLogRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _LogParserPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.LogParserPlugin",
+	HandlerType: (*LogParserPluginServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Parse",
+			Handler:       _LogParserPlugin_Parse_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "werft-plugin.proto",
+}
+
+// HookPluginClient is the client API for HookPlugin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type HookPluginClient interface {
+	// Info returns the version of the hook API the plugin speaks and the hooks it implements
+	Info(ctx context.Context, in *HookInfoRequest, opts ...grpc.CallOption) (*HookInfoResponse, error)
+	// FilterWebhook decides if werft handles a webhook event, e.g. to ignore pushes by bots
+	FilterWebhook(ctx context.Context, in *FilterWebhookRequest, opts ...grpc.CallOption) (*FilterWebhookResponse, error)
+	// MutateJob can change a job before it starts, e.g. to add sidecars or annotations, or reject it altogether
+	MutateJob(ctx context.Context, in *MutateJobRequest, opts ...grpc.CallOption) (*MutateJobResponse, error)
+	// JobDone is called once a job is done, e.g. to notify other systems
+	JobDone(ctx context.Context, in *JobDoneRequest, opts ...grpc.CallOption) (*JobDoneResponse, error)
+	// PreCreatePod can change the pod of a job right before it's created, e.g. to adjust it to the environment, or reject it.
+	// Unlike MutateJob it sees the complete pod, including everything werft adds.
+	PreCreatePod(ctx context.Context, in *PodHookRequest, opts ...grpc.CallOption) (*PreCreatePodResponse, error)
+	// PostCreatePod is called once the pod of a job was created, e.g. to register the DNS name of a preview environment
+	PostCreatePod(ctx context.Context, in *PodHookRequest, opts ...grpc.CallOption) (*PodHookResponse, error)
+	// PreDeletePod is called before the pod of a job is deleted, e.g. to clean up what PostCreatePod set up
+	PreDeletePod(ctx context.Context, in *PodHookRequest, opts ...grpc.CallOption) (*PodHookResponse, error)
+}
+
+type hookPluginClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewHookPluginClient(cc *grpc.ClientConn) HookPluginClient {
+	return &hookPluginClient{cc}
+}
+
+func (c *hookPluginClient) Info(ctx context.Context, in *HookInfoRequest, opts ...grpc.CallOption) (*HookInfoResponse, error) {
+	out := new(HookInfoResponse)
+	err := c.cc.Invoke(ctx, "/v1.HookPlugin/Info", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hookPluginClient) FilterWebhook(ctx context.Context, in *FilterWebhookRequest, opts ...grpc.CallOption) (*FilterWebhookResponse, error) {
+	out := new(FilterWebhookResponse)
+	err := c.cc.Invoke(ctx, "/v1.HookPlugin/FilterWebhook", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hookPluginClient) MutateJob(ctx context.Context, in *MutateJobRequest, opts ...grpc.CallOption) (*MutateJobResponse, error) {
+	out := new(MutateJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.HookPlugin/MutateJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hookPluginClient) JobDone(ctx context.Context, in *JobDoneRequest, opts ...grpc.CallOption) (*JobDoneResponse, error) {
+	out := new(JobDoneResponse)
+	err := c.cc.Invoke(ctx, "/v1.HookPlugin/JobDone", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hookPluginClient) PreCreatePod(ctx context.Context, in *PodHookRequest, opts ...grpc.CallOption) (*PreCreatePodResponse, error) {
+	out := new(PreCreatePodResponse)
+	err := c.cc.Invoke(ctx, "/v1.HookPlugin/PreCreatePod", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hookPluginClient) PostCreatePod(ctx context.Context, in *PodHookRequest, opts ...grpc.CallOption) (*PodHookResponse, error) {
+	out := new(PodHookResponse)
+	err := c.cc.Invoke(ctx, "/v1.HookPlugin/PostCreatePod", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hookPluginClient) PreDeletePod(ctx context.Context, in *PodHookRequest, opts ...grpc.CallOption) (*PodHookResponse, error) {
+	out := new(PodHookResponse)
+	err := c.cc.Invoke(ctx, "/v1.HookPlugin/PreDeletePod", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HookPluginServer is the server API for HookPlugin service.
+type HookPluginServer interface {
+	// Info returns the version of the hook API the plugin speaks and the hooks it implements
+	Info(context.Context, *HookInfoRequest) (*HookInfoResponse, error)
+	// FilterWebhook decides if werft handles a webhook event, e.g. to ignore pushes by bots
+	FilterWebhook(context.Context, *FilterWebhookRequest) (*FilterWebhookResponse, error)
+	// MutateJob can change a job before it starts, e.g. to add sidecars or annotations, or reject it altogether
+	MutateJob(context.Context, *MutateJobRequest) (*MutateJobResponse, error)
+	// JobDone is called once a job is done, e.g. to notify other systems
+	JobDone(context.Context, *JobDoneRequest) (*JobDoneResponse, error)
+	// PreCreatePod can change the pod of a job right before it's created, e.g. to adjust it to the environment, or reject it.
+	// Unlike MutateJob it sees the complete pod, including everything werft adds.
+	PreCreatePod(context.Context, *PodHookRequest) (*PreCreatePodResponse, error)
+	// PostCreatePod is called once the pod of a job was created, e.g. to register the DNS name of a preview environment
+	PostCreatePod(context.Context, *PodHookRequest) (*PodHookResponse, error)
+	// PreDeletePod is called before the pod of a job is deleted, e.g. to clean up what PostCreatePod set up
+	PreDeletePod(context.Context, *PodHookRequest) (*PodHookResponse, error)
+}
+
+// UnimplementedHookPluginServer can be embedded to have forward compatible implementations.
+type UnimplementedHookPluginServer struct {
+}
+
+func (*UnimplementedHookPluginServer) Info(ctx context.Context, req *HookInfoRequest) (*HookInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Info not implemented")
+}
+func (*UnimplementedHookPluginServer) FilterWebhook(ctx context.Context, req *FilterWebhookRequest) (*FilterWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FilterWebhook not implemented")
+}
+func (*UnimplementedHookPluginServer) MutateJob(ctx context.Context, req *MutateJobRequest) (*MutateJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MutateJob not implemented")
+}
+func (*UnimplementedHookPluginServer) JobDone(ctx context.Context, req *JobDoneRequest) (*JobDoneResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method JobDone not implemented")
+}
+func (*UnimplementedHookPluginServer) PreCreatePod(ctx context.Context, req *PodHookRequest) (*PreCreatePodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreCreatePod not implemented")
+}
+func (*UnimplementedHookPluginServer) PostCreatePod(ctx context.Context, req *PodHookRequest) (*PodHookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostCreatePod not implemented")
+}
+func (*UnimplementedHookPluginServer) PreDeletePod(ctx context.Context, req *PodHookRequest) (*PodHookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreDeletePod not implemented")
+}
+
+func RegisterHookPluginServer(s *grpc.Server, srv HookPluginServer) {
+	s.RegisterService(&_HookPlugin_serviceDesc, srv)
+}
+
+func _HookPlugin_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HookInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HookPluginServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.HookPlugin/Info",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HookPluginServer).Info(ctx, req.(*HookInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HookPlugin_FilterWebhook_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FilterWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HookPluginServer).FilterWebhook(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.HookPlugin/FilterWebhook",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HookPluginServer).FilterWebhook(ctx, req.(*FilterWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HookPlugin_MutateJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MutateJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HookPluginServer).MutateJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.HookPlugin/MutateJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HookPluginServer).MutateJob(ctx, req.(*MutateJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HookPlugin_JobDone_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobDoneRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HookPluginServer).JobDone(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.HookPlugin/JobDone",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HookPluginServer).JobDone(ctx, req.(*JobDoneRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HookPlugin_PreCreatePod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PodHookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HookPluginServer).PreCreatePod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.HookPlugin/PreCreatePod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HookPluginServer).PreCreatePod(ctx, req.(*PodHookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HookPlugin_PostCreatePod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PodHookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HookPluginServer).PostCreatePod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.HookPlugin/PostCreatePod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HookPluginServer).PostCreatePod(ctx, req.(*PodHookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HookPlugin_PreDeletePod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PodHookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HookPluginServer).PreDeletePod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.HookPlugin/PreDeletePod",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HookPluginServer).PreDeletePod(ctx, req.(*PodHookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _HookPlugin_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v1.HookPlugin",
+	HandlerType: (*HookPluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Info",
+			Handler:    _HookPlugin_Info_Handler,
+		},
+		{
+			MethodName: "FilterWebhook",
+			Handler:    _HookPlugin_FilterWebhook_Handler,
+		},
+		{
+			MethodName: "MutateJob",
+			Handler:    _HookPlugin_MutateJob_Handler,
+		},
+		{
+			MethodName: "JobDone",
+			Handler:    _HookPlugin_JobDone_Handler,
+		},
+		{
+			MethodName: "PreCreatePod",
+			Handler:    _HookPlugin_PreCreatePod_Handler,
+		},
+		{
+			MethodName: "PostCreatePod",
+			Handler:    _HookPlugin_PostCreatePod_Handler,
+		},
+		{
+			MethodName: "PreDeletePod",
+			Handler:    _HookPlugin_PreDeletePod_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "werft-plugin.proto",
+}
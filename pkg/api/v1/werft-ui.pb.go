@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -25,9 +26,12 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type ListJobSpecsRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// repository lists the job specs of a single repository at its ref or revision, fetched when asked for.
+	// If this is not set, the job specs of the repositories configured for the UI are listed.
+	Repository           *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *ListJobSpecsRequest) Reset()         { *m = ListJobSpecsRequest{} }
@@ -55,6 +59,13 @@ func (m *ListJobSpecsRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ListJobSpecsRequest proto.InternalMessageInfo
 
+func (m *ListJobSpecsRequest) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
 type ListJobSpecsResponse struct {
 	Repo                 *Repository          `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
 	Name                 string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -182,33 +193,132 @@ func (m *DesiredAnnotation) GetDescription() string {
 	return ""
 }
 
+type GetIdentityRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetIdentityRequest) Reset()         { *m = GetIdentityRequest{} }
+func (m *GetIdentityRequest) String() string { return proto.CompactTextString(m) }
+func (*GetIdentityRequest) ProtoMessage()    {}
+func (*GetIdentityRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d41ca2a021dc92d, []int{3}
+}
+
+func (m *GetIdentityRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetIdentityRequest.Unmarshal(m, b)
+}
+func (m *GetIdentityRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetIdentityRequest.Marshal(b, m, deterministic)
+}
+func (m *GetIdentityRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetIdentityRequest.Merge(m, src)
+}
+func (m *GetIdentityRequest) XXX_Size() int {
+	return xxx_messageInfo_GetIdentityRequest.Size(m)
+}
+func (m *GetIdentityRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetIdentityRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetIdentityRequest proto.InternalMessageInfo
+
+type GetIdentityResponse struct {
+	// user is the user the authenticating proxy in front of werft logged in
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// permissions the user has, e.g. "admin"
+	Permissions []string `protobuf:"bytes,2,rep,name=permissions,proto3" json:"permissions,omitempty"`
+	// expires is the time the session token expires and must be refreshed at /auth/refresh
+	Expires              *timestamp.Timestamp `protobuf:"bytes,3,opt,name=expires,proto3" json:"expires,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *GetIdentityResponse) Reset()         { *m = GetIdentityResponse{} }
+func (m *GetIdentityResponse) String() string { return proto.CompactTextString(m) }
+func (*GetIdentityResponse) ProtoMessage()    {}
+func (*GetIdentityResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d41ca2a021dc92d, []int{4}
+}
+
+func (m *GetIdentityResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetIdentityResponse.Unmarshal(m, b)
+}
+func (m *GetIdentityResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetIdentityResponse.Marshal(b, m, deterministic)
+}
+func (m *GetIdentityResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetIdentityResponse.Merge(m, src)
+}
+func (m *GetIdentityResponse) XXX_Size() int {
+	return xxx_messageInfo_GetIdentityResponse.Size(m)
+}
+func (m *GetIdentityResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetIdentityResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetIdentityResponse proto.InternalMessageInfo
+
+func (m *GetIdentityResponse) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *GetIdentityResponse) GetPermissions() []string {
+	if m != nil {
+		return m.Permissions
+	}
+	return nil
+}
+
+func (m *GetIdentityResponse) GetExpires() *timestamp.Timestamp {
+	if m != nil {
+		return m.Expires
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ListJobSpecsRequest)(nil), "v1.ListJobSpecsRequest")
 	proto.RegisterType((*ListJobSpecsResponse)(nil), "v1.ListJobSpecsResponse")
 	proto.RegisterType((*DesiredAnnotation)(nil), "v1.DesiredAnnotation")
+	proto.RegisterType((*GetIdentityRequest)(nil), "v1.GetIdentityRequest")
+	proto.RegisterType((*GetIdentityResponse)(nil), "v1.GetIdentityResponse")
 }
 
 func init() { proto.RegisterFile("werft-ui.proto", fileDescriptor_8d41ca2a021dc92d) }
 
 var fileDescriptor_8d41ca2a021dc92d = []byte{
-	// 268 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0x41, 0x4b, 0xc3, 0x30,
-	0x18, 0x86, 0xcd, 0x5a, 0x75, 0xfb, 0x2a, 0x03, 0xa3, 0xc3, 0xd0, 0x53, 0xc9, 0xa9, 0x17, 0x8b,
-	0xeb, 0x7e, 0x81, 0xa0, 0x07, 0xc5, 0x83, 0x44, 0xc4, 0x73, 0xb7, 0x7d, 0x6a, 0x0e, 0x4b, 0xb2,
-	0x24, 0x9d, 0xf8, 0xd3, 0xfc, 0x77, 0x92, 0x14, 0x66, 0xb5, 0xde, 0xbe, 0x3e, 0xef, 0x0b, 0x7d,
-	0x78, 0x03, 0xd3, 0x0f, 0xb4, 0xaf, 0xfe, 0xb2, 0x95, 0x95, 0xb1, 0xda, 0x6b, 0x3a, 0xda, 0xcd,
-	0xf3, 0x2c, 0xb2, 0x0e, 0xf0, 0x19, 0x9c, 0x3d, 0x48, 0xe7, 0xef, 0xf5, 0xf2, 0xc9, 0xe0, 0xca,
-	0x09, 0xdc, 0xb6, 0xe8, 0x3c, 0xff, 0x22, 0x70, 0xfe, 0x9b, 0x3b, 0xa3, 0x95, 0x43, 0xca, 0x21,
-	0xb5, 0x68, 0x34, 0x23, 0x05, 0x29, 0xb3, 0x7a, 0x5a, 0xed, 0xe6, 0x95, 0x40, 0xa3, 0x9d, 0xf4,
-	0xda, 0x7e, 0x8a, 0x98, 0x51, 0x0a, 0xa9, 0x6a, 0x36, 0xc8, 0x46, 0x05, 0x29, 0x27, 0x22, 0xde,
-	0x81, 0x99, 0xc6, 0xbf, 0xb3, 0xa4, 0x63, 0xe1, 0xa6, 0x05, 0x64, 0x6b, 0x74, 0x2b, 0x2b, 0x8d,
-	0x97, 0x5a, 0xb1, 0x34, 0x46, 0x7d, 0x44, 0x17, 0x30, 0x69, 0xec, 0x5b, 0xbb, 0x41, 0xe5, 0x1d,
-	0x3b, 0x2c, 0x92, 0x32, 0xab, 0x67, 0xe1, 0x97, 0x37, 0xe8, 0xa4, 0xc5, 0xf5, 0xb5, 0x52, 0xda,
-	0x37, 0xa1, 0x29, 0x7e, 0x7a, 0x1c, 0xe1, 0x74, 0x90, 0xef, 0x9d, 0x48, 0xcf, 0x29, 0x87, 0xb1,
-	0xc5, 0x6d, 0x1b, 0x9a, 0xd1, 0x75, 0x2c, 0xf6, 0xdf, 0x7f, 0xdd, 0x92, 0x81, 0x5b, 0xfd, 0x08,
-	0xc7, 0x2f, 0x61, 0xc8, 0xe7, 0x3b, 0x7a, 0x0b, 0x27, 0xfd, 0xb1, 0xe8, 0x45, 0x70, 0xfc, 0x67,
-	0xd6, 0x9c, 0x0d, 0x83, 0x6e, 0x57, 0x7e, 0x70, 0x45, 0x96, 0x47, 0xf1, 0x49, 0x16, 0xdf, 0x01,
-	0x00, 0x00, 0xff, 0xff, 0x14, 0xb8, 0xdd, 0x0d, 0xb5, 0x01, 0x00, 0x00,
+	// 388 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x92, 0xc1, 0xce, 0xd2, 0x40,
+	0x10, 0xc7, 0x5d, 0x5a, 0x05, 0xa6, 0x86, 0xc4, 0x05, 0xa5, 0xe9, 0xc5, 0xa6, 0x27, 0x2e, 0x16,
+	0x29, 0x3e, 0x80, 0x26, 0x12, 0x83, 0xf1, 0x54, 0x35, 0x9e, 0x0b, 0x0c, 0xb8, 0x89, 0xdd, 0x5d,
+	0x76, 0xb7, 0x28, 0x47, 0x5f, 0xc1, 0xb7, 0xf1, 0xed, 0xcc, 0x6e, 0x29, 0x96, 0xaf, 0xdf, 0x77,
+	0x9b, 0xfe, 0xe6, 0x9f, 0x99, 0xff, 0x4e, 0xff, 0x30, 0xfa, 0x89, 0x6a, 0x6f, 0x5e, 0x55, 0x2c,
+	0x95, 0x4a, 0x18, 0x41, 0x7b, 0xa7, 0x45, 0x14, 0x38, 0x56, 0x83, 0xe8, 0xe5, 0x41, 0x88, 0xc3,
+	0x0f, 0x9c, 0xbb, 0xaf, 0x4d, 0xb5, 0x9f, 0x1b, 0x56, 0xa2, 0x36, 0x45, 0x29, 0x6b, 0x41, 0xb2,
+	0x82, 0xf1, 0x27, 0xa6, 0xcd, 0x47, 0xb1, 0xf9, 0x2c, 0x71, 0xab, 0x73, 0x3c, 0x56, 0xa8, 0x0d,
+	0x4d, 0x01, 0x14, 0x4a, 0xa1, 0x99, 0x11, 0xea, 0x1c, 0x92, 0x98, 0xcc, 0x82, 0x6c, 0x94, 0x9e,
+	0x16, 0x69, 0x7e, 0xa5, 0x79, 0x4b, 0x91, 0xfc, 0x25, 0x30, 0xb9, 0x9d, 0xa3, 0xa5, 0xe0, 0x1a,
+	0x69, 0x02, 0xbe, 0x95, 0x3d, 0x30, 0xc2, 0xf5, 0x28, 0x05, 0x9f, 0x17, 0x25, 0x86, 0xbd, 0x98,
+	0xcc, 0x86, 0xb9, 0xab, 0x2d, 0x93, 0x85, 0xf9, 0x1e, 0x7a, 0x35, 0xb3, 0x35, 0x8d, 0x21, 0xd8,
+	0xa1, 0xde, 0x2a, 0x26, 0x0d, 0x13, 0x3c, 0xf4, 0x5d, 0xab, 0x8d, 0xe8, 0x12, 0x86, 0x85, 0x3a,
+	0x54, 0x25, 0x72, 0xa3, 0xc3, 0xc7, 0xb1, 0x37, 0x0b, 0xb2, 0xe7, 0x76, 0xe5, 0x7b, 0xd4, 0x4c,
+	0xe1, 0xee, 0x1d, 0xe7, 0xc2, 0x14, 0x56, 0x99, 0xff, 0xd7, 0x25, 0x08, 0xcf, 0x3a, 0xfd, 0xab,
+	0x27, 0xd2, 0xf2, 0x14, 0xc1, 0x40, 0xe1, 0xb1, 0xb2, 0x4a, 0xe7, 0x75, 0x90, 0x5f, 0xbf, 0xef,
+	0x7a, 0xf3, 0x3a, 0xde, 0x92, 0x09, 0xd0, 0x0f, 0x68, 0xd6, 0x3b, 0xe4, 0x86, 0x99, 0xf3, 0xe5,
+	0xd0, 0xc9, 0x6f, 0x02, 0xe3, 0x1b, 0x7c, 0xb9, 0x1b, 0x05, 0xbf, 0xd2, 0xa8, 0x9a, 0xfd, 0xb6,
+	0xb6, 0x3b, 0x24, 0xaa, 0x92, 0x69, 0xcd, 0x04, 0xd7, 0x61, 0x2f, 0xf6, 0xec, 0x8e, 0x16, 0xa2,
+	0x6f, 0xa0, 0x8f, 0xbf, 0x24, 0x53, 0xa8, 0x9d, 0x83, 0x20, 0x8b, 0xd2, 0x3a, 0x00, 0x69, 0x13,
+	0x80, 0xf4, 0x4b, 0x13, 0x80, 0xbc, 0x91, 0x66, 0x7f, 0x08, 0xf4, 0xbf, 0xd9, 0xd0, 0x7c, 0x5d,
+	0xd3, 0x15, 0x3c, 0x6d, 0xff, 0x47, 0x3a, 0xb5, 0xe7, 0xbb, 0x27, 0x21, 0x51, 0xd8, 0x6d, 0xd4,
+	0xd6, 0x93, 0x47, 0xaf, 0x09, 0x7d, 0x0b, 0x41, 0xeb, 0x55, 0xf4, 0x85, 0x15, 0x77, 0x5f, 0x1f,
+	0x4d, 0x3b, 0xbc, 0x99, 0xb1, 0x79, 0xe2, 0x1c, 0x2f, 0xff, 0x0d, 0x00, 0x4c, 0x28, 0xfe, 0x69,
+	0xe3, 0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -223,8 +333,11 @@ const _ = grpc.SupportPackageIsVersion4
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type WerftUIClient interface {
-	// ListJobSpecs returns a list of jobs that can be started through the UI.
+	// ListJobSpecs returns a list of jobs that can be started through the UI or CLI.
 	ListJobSpecs(ctx context.Context, in *ListJobSpecsRequest, opts ...grpc.CallOption) (WerftUI_ListJobSpecsClient, error)
+	// GetIdentity returns the user of the current session and their permissions, so that the UI can offer only
+	// what the user may do. Sessions are started by logging in at /auth/login.
+	GetIdentity(ctx context.Context, in *GetIdentityRequest, opts ...grpc.CallOption) (*GetIdentityResponse, error)
 }
 
 type werftUIClient struct {
@@ -267,10 +380,22 @@ func (x *werftUIListJobSpecsClient) Recv() (*ListJobSpecsResponse, error) {
 	return m, nil
 }
 
+func (c *werftUIClient) GetIdentity(ctx context.Context, in *GetIdentityRequest, opts ...grpc.CallOption) (*GetIdentityResponse, error) {
+	out := new(GetIdentityResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftUI/GetIdentity", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WerftUIServer is the server API for WerftUI service.
 type WerftUIServer interface {
-	// ListJobSpecs returns a list of jobs that can be started through the UI.
+	// ListJobSpecs returns a list of jobs that can be started through the UI or CLI.
 	ListJobSpecs(*ListJobSpecsRequest, WerftUI_ListJobSpecsServer) error
+	// GetIdentity returns the user of the current session and their permissions, so that the UI can offer only
+	// what the user may do. Sessions are started by logging in at /auth/login.
+	GetIdentity(context.Context, *GetIdentityRequest) (*GetIdentityResponse, error)
 }
 
 // UnimplementedWerftUIServer can be embedded to have forward compatible implementations.
@@ -280,6 +405,9 @@ type UnimplementedWerftUIServer struct {
 func (*UnimplementedWerftUIServer) ListJobSpecs(req *ListJobSpecsRequest, srv WerftUI_ListJobSpecsServer) error {
 	return status.Errorf(codes.Unimplemented, "method ListJobSpecs not implemented")
 }
+func (*UnimplementedWerftUIServer) GetIdentity(ctx context.Context, req *GetIdentityRequest) (*GetIdentityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetIdentity not implemented")
+}
 
 func RegisterWerftUIServer(s *grpc.Server, srv WerftUIServer) {
 	s.RegisterService(&_WerftUI_serviceDesc, srv)
@@ -306,10 +434,33 @@ func (x *werftUIListJobSpecsServer) Send(m *ListJobSpecsResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _WerftUI_GetIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetIdentityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftUIServer).GetIdentity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftUI/GetIdentity",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftUIServer).GetIdentity(ctx, req.(*GetIdentityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _WerftUI_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v1.WerftUI",
 	HandlerType: (*WerftUIServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetIdentity",
+			Handler:    _WerftUI_GetIdentity_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "ListJobSpecs",
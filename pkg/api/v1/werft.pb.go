@@ -25,6 +25,33 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type JobView int32
+
+const (
+	// JOB_VIEW_FULL returns all information about a job
+	JobView_JOB_VIEW_FULL JobView = 0
+	// JOB_VIEW_SUMMARY returns a job's name, owner, phase, success, trigger, repository, created/finished times and notes
+	JobView_JOB_VIEW_SUMMARY JobView = 1
+)
+
+var JobView_name = map[int32]string{
+	0: "JOB_VIEW_FULL",
+	1: "JOB_VIEW_SUMMARY",
+}
+
+var JobView_value = map[string]int32{
+	"JOB_VIEW_FULL":    0,
+	"JOB_VIEW_SUMMARY": 1,
+}
+
+func (x JobView) String() string {
+	return proto.EnumName(JobView_name, int32(x))
+}
+
+func (JobView) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{0}
+}
+
 type FilterOp int32
 
 const (
@@ -56,7 +83,7 @@ func (x FilterOp) String() string {
 }
 
 func (FilterOp) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{0}
+	return fileDescriptor_9fe744feedd6d332, []int{1}
 }
 
 type ListenRequestLogs int32
@@ -87,7 +114,7 @@ func (x ListenRequestLogs) String() string {
 }
 
 func (ListenRequestLogs) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{1}
+	return fileDescriptor_9fe744feedd6d332, []int{2}
 }
 
 type JobTrigger int32
@@ -97,6 +124,18 @@ const (
 	JobTrigger_TRIGGER_MANUAL  JobTrigger = 1
 	JobTrigger_TRIGGER_PUSH    JobTrigger = 2
 	JobTrigger_TRIGGER_DELETED JobTrigger = 3
+	// Tag means the job was started by pushing a tag
+	JobTrigger_TRIGGER_TAG JobTrigger = 4
+	// PullRequest means the job was started because a pull request was opened or updated
+	JobTrigger_TRIGGER_PULL_REQUEST JobTrigger = 5
+	// Scheduled means the job was started on a schedule, e.g. by the cron plugin
+	JobTrigger_TRIGGER_SCHEDULED JobTrigger = 6
+	// Artifact means the job was started because a new version of an artifact was published, e.g. a base image
+	JobTrigger_TRIGGER_ARTIFACT JobTrigger = 7
+	// Upstream means the job was started as downstream job of a successful job in another repository
+	JobTrigger_TRIGGER_UPSTREAM JobTrigger = 8
+	// MergeGroup means the job was started because a merge queue needs checks for a merge group
+	JobTrigger_TRIGGER_MERGE_GROUP JobTrigger = 9
 )
 
 var JobTrigger_name = map[int32]string{
@@ -104,13 +143,25 @@ var JobTrigger_name = map[int32]string{
 	1: "TRIGGER_MANUAL",
 	2: "TRIGGER_PUSH",
 	3: "TRIGGER_DELETED",
+	4: "TRIGGER_TAG",
+	5: "TRIGGER_PULL_REQUEST",
+	6: "TRIGGER_SCHEDULED",
+	7: "TRIGGER_ARTIFACT",
+	8: "TRIGGER_UPSTREAM",
+	9: "TRIGGER_MERGE_GROUP",
 }
 
 var JobTrigger_value = map[string]int32{
-	"TRIGGER_UNKNOWN": 0,
-	"TRIGGER_MANUAL":  1,
-	"TRIGGER_PUSH":    2,
-	"TRIGGER_DELETED": 3,
+	"TRIGGER_UNKNOWN":      0,
+	"TRIGGER_MANUAL":       1,
+	"TRIGGER_PUSH":         2,
+	"TRIGGER_DELETED":      3,
+	"TRIGGER_TAG":          4,
+	"TRIGGER_PULL_REQUEST": 5,
+	"TRIGGER_SCHEDULED":    6,
+	"TRIGGER_ARTIFACT":     7,
+	"TRIGGER_UPSTREAM":     8,
+	"TRIGGER_MERGE_GROUP":  9,
 }
 
 func (x JobTrigger) String() string {
@@ -118,7 +169,7 @@ func (x JobTrigger) String() string {
 }
 
 func (JobTrigger) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{2}
+	return fileDescriptor_9fe744feedd6d332, []int{3}
 }
 
 type JobPhase int32
@@ -166,7 +217,70 @@ func (x JobPhase) String() string {
 }
 
 func (JobPhase) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{3}
+	return fileDescriptor_9fe744feedd6d332, []int{4}
+}
+
+type JobEventType int32
+
+const (
+	JobEventType_EVENT_UNKNOWN JobEventType = 0
+	// Created means werft accepted the job and is about to start it
+	JobEventType_EVENT_CREATED JobEventType = 1
+	// Queued means the job waits to run, e.g. for its start time or for other jobs of the repository to finish
+	JobEventType_EVENT_QUEUED JobEventType = 2
+	// PodScheduled means Kubernetes assigned the pod of the job to a node
+	JobEventType_EVENT_POD_SCHEDULED JobEventType = 3
+	// PhaseChanged means the job entered the phase of the event
+	JobEventType_EVENT_PHASE_CHANGED JobEventType = 4
+	// CancelRequested means someone asked to stop the job
+	JobEventType_EVENT_CANCEL_REQUESTED JobEventType = 5
+	// PodDeleted means the pod of the job is being deleted, i.e. the job entered cleanup
+	JobEventType_EVENT_POD_DELETED JobEventType = 6
+	// WebhookReceived means werft received the webhook which started the job. It precedes the creation of the job.
+	JobEventType_EVENT_WEBHOOK_RECEIVED JobEventType = 7
+	// Deleted means someone deleted the job, which hides it from job listings. The message names who deleted it.
+	JobEventType_EVENT_DELETED JobEventType = 8
+	// Approved means someone approved a job which waited for approval. The message names who approved it.
+	JobEventType_EVENT_APPROVED JobEventType = 9
+	// ContainersStopped means the containers of a job stopped after werft deleted its pod, e.g. because the job was
+	// cancelled or timed out. The message says whether they stopped within the grace period after SIGTERM or were killed.
+	JobEventType_EVENT_CONTAINERS_STOPPED JobEventType = 10
+)
+
+var JobEventType_name = map[int32]string{
+	0:  "EVENT_UNKNOWN",
+	1:  "EVENT_CREATED",
+	2:  "EVENT_QUEUED",
+	3:  "EVENT_POD_SCHEDULED",
+	4:  "EVENT_PHASE_CHANGED",
+	5:  "EVENT_CANCEL_REQUESTED",
+	6:  "EVENT_POD_DELETED",
+	7:  "EVENT_WEBHOOK_RECEIVED",
+	8:  "EVENT_DELETED",
+	9:  "EVENT_APPROVED",
+	10: "EVENT_CONTAINERS_STOPPED",
+}
+
+var JobEventType_value = map[string]int32{
+	"EVENT_UNKNOWN":            0,
+	"EVENT_CREATED":            1,
+	"EVENT_QUEUED":             2,
+	"EVENT_POD_SCHEDULED":      3,
+	"EVENT_PHASE_CHANGED":      4,
+	"EVENT_CANCEL_REQUESTED":   5,
+	"EVENT_POD_DELETED":        6,
+	"EVENT_WEBHOOK_RECEIVED":   7,
+	"EVENT_DELETED":            8,
+	"EVENT_APPROVED":           9,
+	"EVENT_CONTAINERS_STOPPED": 10,
+}
+
+func (x JobEventType) String() string {
+	return proto.EnumName(JobEventType_name, int32(x))
+}
+
+func (JobEventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{5}
 }
 
 type LogSliceType int32
@@ -206,7 +320,158 @@ func (x LogSliceType) String() string {
 }
 
 func (LogSliceType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{4}
+	return fileDescriptor_9fe744feedd6d332, []int{6}
+}
+
+type WaitReason int32
+
+const (
+	WaitReason_WAIT_UNKNOWN WaitReason = 0
+	// the job waits for the time it was scheduled at
+	WaitReason_WAIT_SCHEDULED WaitReason = 1
+	// the job failed and waits before it's retried
+	WaitReason_WAIT_RETRY_BACKOFF WaitReason = 2
+	// the job's pod waits for Kubernetes to schedule it, e.g. because the cluster lacks resources
+	WaitReason_WAIT_POD_PENDING WaitReason = 3
+	// werft is in maintenance mode and holds the job until job processing resumes
+	WaitReason_WAIT_MAINTENANCE WaitReason = 4
+	// the job was started outside of its execution window and waits for the window to open
+	WaitReason_WAIT_EXECUTION_WINDOW WaitReason = 5
+	// the job waits for the cluster to have capacity for its pod, e.g. room in the resource quotas of the namespace
+	WaitReason_WAIT_CAPACITY WaitReason = 6
+	// the job's spec requires someone to approve the job before it runs
+	WaitReason_WAIT_APPROVAL WaitReason = 7
+	// the repository runs as many jobs as it may run at the same time
+	WaitReason_WAIT_CONCURRENCY_LIMIT WaitReason = 8
+	// the repository started as many jobs as it may start within an hour
+	WaitReason_WAIT_RATE_LIMIT WaitReason = 9
+	// the job waits for other jobs it depends on to finish
+	WaitReason_WAIT_DEPENDENCY WaitReason = 10
+)
+
+var WaitReason_name = map[int32]string{
+	0:  "WAIT_UNKNOWN",
+	1:  "WAIT_SCHEDULED",
+	2:  "WAIT_RETRY_BACKOFF",
+	3:  "WAIT_POD_PENDING",
+	4:  "WAIT_MAINTENANCE",
+	5:  "WAIT_EXECUTION_WINDOW",
+	6:  "WAIT_CAPACITY",
+	7:  "WAIT_APPROVAL",
+	8:  "WAIT_CONCURRENCY_LIMIT",
+	9:  "WAIT_RATE_LIMIT",
+	10: "WAIT_DEPENDENCY",
+}
+
+var WaitReason_value = map[string]int32{
+	"WAIT_UNKNOWN":           0,
+	"WAIT_SCHEDULED":         1,
+	"WAIT_RETRY_BACKOFF":     2,
+	"WAIT_POD_PENDING":       3,
+	"WAIT_MAINTENANCE":       4,
+	"WAIT_EXECUTION_WINDOW":  5,
+	"WAIT_CAPACITY":          6,
+	"WAIT_APPROVAL":          7,
+	"WAIT_CONCURRENCY_LIMIT": 8,
+	"WAIT_RATE_LIMIT":        9,
+	"WAIT_DEPENDENCY":        10,
+}
+
+func (x WaitReason) String() string {
+	return proto.EnumName(WaitReason_name, int32(x))
+}
+
+func (WaitReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{7}
+}
+
+type ExportFormat int32
+
+const (
+	// JSON lines, one job status per line
+	ExportFormat_EXPORT_JSONL ExportFormat = 0
+	// CSV with a header row
+	ExportFormat_EXPORT_CSV ExportFormat = 1
+)
+
+var ExportFormat_name = map[int32]string{
+	0: "EXPORT_JSONL",
+	1: "EXPORT_CSV",
+}
+
+var ExportFormat_value = map[string]int32{
+	"EXPORT_JSONL": 0,
+	"EXPORT_CSV":   1,
+}
+
+func (x ExportFormat) String() string {
+	return proto.EnumName(ExportFormat_name, int32(x))
+}
+
+func (ExportFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{8}
+}
+
+type CostGrouping int32
+
+const (
+	CostGrouping_COST_BY_REPOSITORY CostGrouping = 0
+	// team groups jobs by their team label
+	CostGrouping_COST_BY_TEAM CostGrouping = 1
+	// month groups jobs by the month (UTC) they were created in
+	CostGrouping_COST_BY_MONTH CostGrouping = 2
+)
+
+var CostGrouping_name = map[int32]string{
+	0: "COST_BY_REPOSITORY",
+	1: "COST_BY_TEAM",
+	2: "COST_BY_MONTH",
+}
+
+var CostGrouping_value = map[string]int32{
+	"COST_BY_REPOSITORY": 0,
+	"COST_BY_TEAM":       1,
+	"COST_BY_MONTH":      2,
+}
+
+func (x CostGrouping) String() string {
+	return proto.EnumName(CostGrouping_name, int32(x))
+}
+
+func (CostGrouping) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{9}
+}
+
+type BatchJobOutcome int32
+
+const (
+	BatchJobOutcome_BATCH_SUCCEEDED BatchJobOutcome = 0
+	BatchJobOutcome_BATCH_FAILED    BatchJobOutcome = 1
+	BatchJobOutcome_BATCH_SKIPPED   BatchJobOutcome = 2
+	// DryRun means the operation would have applied to the job
+	BatchJobOutcome_BATCH_DRY_RUN BatchJobOutcome = 3
+)
+
+var BatchJobOutcome_name = map[int32]string{
+	0: "BATCH_SUCCEEDED",
+	1: "BATCH_FAILED",
+	2: "BATCH_SKIPPED",
+	3: "BATCH_DRY_RUN",
+}
+
+var BatchJobOutcome_value = map[string]int32{
+	"BATCH_SUCCEEDED": 0,
+	"BATCH_FAILED":    1,
+	"BATCH_SKIPPED":   2,
+	"BATCH_DRY_RUN":   3,
+}
+
+func (x BatchJobOutcome) String() string {
+	return proto.EnumName(BatchJobOutcome_name, int32(x))
+}
+
+func (BatchJobOutcome) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{10}
 }
 
 type StartLocalJobRequest struct {
@@ -374,15 +639,18 @@ func (m *StartJobResponse) GetStatus() *JobStatus {
 }
 
 type StartGitHubJobRequest struct {
-	Metadata             *JobMetadata         `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	JobPath              string               `protobuf:"bytes,2,opt,name=job_path,json=jobPath,proto3" json:"job_path,omitempty"`
-	JobYaml              []byte               `protobuf:"bytes,3,opt,name=job_yaml,json=jobYaml,proto3" json:"job_yaml,omitempty"`
-	GithubToken          string               `protobuf:"bytes,4,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
-	Sideload             []byte               `protobuf:"bytes,5,opt,name=sideload,proto3" json:"sideload,omitempty"`
-	WaitUntil            *timestamp.Timestamp `protobuf:"bytes,6,opt,name=wait_until,json=waitUntil,proto3" json:"wait_until,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Metadata    *JobMetadata         `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	JobPath     string               `protobuf:"bytes,2,opt,name=job_path,json=jobPath,proto3" json:"job_path,omitempty"`
+	JobYaml     []byte               `protobuf:"bytes,3,opt,name=job_yaml,json=jobYaml,proto3" json:"job_yaml,omitempty"`
+	GithubToken string               `protobuf:"bytes,4,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	Sideload    []byte               `protobuf:"bytes,5,opt,name=sideload,proto3" json:"sideload,omitempty"`
+	WaitUntil   *timestamp.Timestamp `protobuf:"bytes,6,opt,name=wait_until,json=waitUntil,proto3" json:"wait_until,omitempty"`
+	// job_spec_ref is the ref or revision the job spec and repository config are read from, if not the one of the job.
+	// The job still checks out the source of its own revision.
+	JobSpecRef           string   `protobuf:"bytes,7,opt,name=job_spec_ref,json=jobSpecRef,proto3" json:"job_spec_ref,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *StartGitHubJobRequest) Reset()         { *m = StartGitHubJobRequest{} }
@@ -452,6 +720,13 @@ func (m *StartGitHubJobRequest) GetWaitUntil() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *StartGitHubJobRequest) GetJobSpecRef() string {
+	if m != nil {
+		return m.JobSpecRef
+	}
+	return ""
+}
+
 type StartFromPreviousJobRequest struct {
 	PreviousJob          string               `protobuf:"bytes,1,opt,name=previous_job,json=previousJob,proto3" json:"previous_job,omitempty"`
 	GithubToken          string               `protobuf:"bytes,2,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
@@ -508,13 +783,22 @@ func (m *StartFromPreviousJobRequest) GetWaitUntil() *timestamp.Timestamp {
 }
 
 type ListJobsRequest struct {
-	Filter               []*FilterExpression `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty"`
-	Order                []*OrderExpression  `protobuf:"bytes,2,rep,name=order,proto3" json:"order,omitempty"`
-	Start                int32               `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
-	Limit                int32               `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Filter []*FilterExpression `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty"`
+	Order  []*OrderExpression  `protobuf:"bytes,2,rep,name=order,proto3" json:"order,omitempty"`
+	Start  int32               `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	Limit  int32               `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// label_selector is a Kubernetes-style label selector, e.g. "team=platform,stage in (build, test)"
+	LabelSelector string `protobuf:"bytes,5,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	// view determines how much of each job is returned. Lists which only show a job's name, phase, ref and time
+	// should use the summary view to keep responses small.
+	View JobView `protobuf:"varint,6,opt,name=view,proto3,enum=v1.JobView" json:"view,omitempty"`
+	// project limits the list to the jobs of a project, i.e. jobs labelled project=<name>
+	Project string `protobuf:"bytes,7,opt,name=project,proto3" json:"project,omitempty"`
+	// include_deleted lists deleted jobs too
+	IncludeDeleted       bool     `protobuf:"varint,8,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ListJobsRequest) Reset()         { *m = ListJobsRequest{} }
@@ -570,6 +854,34 @@ func (m *ListJobsRequest) GetLimit() int32 {
 	return 0
 }
 
+func (m *ListJobsRequest) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+func (m *ListJobsRequest) GetView() JobView {
+	if m != nil {
+		return m.View
+	}
+	return JobView_JOB_VIEW_FULL
+}
+
+func (m *ListJobsRequest) GetProject() string {
+	if m != nil {
+		return m.Project
+	}
+	return ""
+}
+
+func (m *ListJobsRequest) GetIncludeDeleted() bool {
+	if m != nil {
+		return m.IncludeDeleted
+	}
+	return false
+}
+
 type FilterExpression struct {
 	Terms                []*FilterTerm `protobuf:"bytes,1,rep,name=terms,proto3" json:"terms,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
@@ -845,7 +1157,11 @@ func (m *SubscribeResponse) GetResult() *JobStatus {
 }
 
 type GetJobRequest struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// github_token identifies the user asking for the job's pod
+	GithubToken string `protobuf:"bytes,2,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	// include_pod adds the pod the job runs in to the response. Requires a GitHub token.
+	IncludePod           bool     `protobuf:"varint,3,opt,name=include_pod,json=includePod,proto3" json:"include_pod,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -883,11 +1199,27 @@ func (m *GetJobRequest) GetName() string {
 	return ""
 }
 
+func (m *GetJobRequest) GetGithubToken() string {
+	if m != nil {
+		return m.GithubToken
+	}
+	return ""
+}
+
+func (m *GetJobRequest) GetIncludePod() bool {
+	if m != nil {
+		return m.IncludePod
+	}
+	return false
+}
+
 type GetJobResponse struct {
-	Result               *JobStatus `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
-	XXX_unrecognized     []byte     `json:"-"`
-	XXX_sizecache        int32      `json:"-"`
+	Result *JobStatus `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	// pod is the pod the job runs in, if it was requested and still exists
+	Pod                  *JobPod  `protobuf:"bytes,2,opt,name=pod,proto3" json:"pod,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GetJobResponse) Reset()         { *m = GetJobResponse{} }
@@ -922,13 +1254,30 @@ func (m *GetJobResponse) GetResult() *JobStatus {
 	return nil
 }
 
+func (m *GetJobResponse) GetPod() *JobPod {
+	if m != nil {
+		return m.Pod
+	}
+	return nil
+}
+
 type ListenRequest struct {
-	Name                 string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Updates              bool              `protobuf:"varint,2,opt,name=updates,proto3" json:"updates,omitempty"`
-	Logs                 ListenRequestLogs `protobuf:"varint,3,opt,name=logs,proto3,enum=v1.ListenRequestLogs" json:"logs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Name    string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Updates bool              `protobuf:"varint,2,opt,name=updates,proto3" json:"updates,omitempty"`
+	Logs    ListenRequestLogs `protobuf:"varint,3,opt,name=logs,proto3,enum=v1.ListenRequestLogs" json:"logs,omitempty"`
+	// offset is the number of log slices to skip. Clients which lost their connection can resume listening
+	// by passing the number of slices they have already received.
+	Offset int64 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	// slice limits the log to the events of a single slice or phase, e.g. the one a user expanded
+	Slice string `protobuf:"bytes,5,opt,name=slice,proto3" json:"slice,omitempty"`
+	// byte_offset is the position in the log listening starts at. It should point to the start of a line, e.g. be
+	// the total length of the payloads a client received listening with LOGS_UNSLICED.
+	ByteOffset int64 `protobuf:"varint,6,opt,name=byte_offset,json=byteOffset,proto3" json:"byte_offset,omitempty"`
+	// byte_limit is the number of bytes of the log to read starting at byte_offset. Zero means no limit.
+	ByteLimit            int64    `protobuf:"varint,7,opt,name=byte_limit,json=byteLimit,proto3" json:"byte_limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ListenRequest) Reset()         { *m = ListenRequest{} }
@@ -977,6 +1326,34 @@ func (m *ListenRequest) GetLogs() ListenRequestLogs {
 	return ListenRequestLogs_LOGS_DISABLED
 }
 
+func (m *ListenRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ListenRequest) GetSlice() string {
+	if m != nil {
+		return m.Slice
+	}
+	return ""
+}
+
+func (m *ListenRequest) GetByteOffset() int64 {
+	if m != nil {
+		return m.ByteOffset
+	}
+	return 0
+}
+
+func (m *ListenRequest) GetByteLimit() int64 {
+	if m != nil {
+		return m.ByteLimit
+	}
+	return 0
+}
+
 type ListenResponse struct {
 	// Types that are valid to be assigned to Content:
 	//	*ListenResponse_Update
@@ -1058,12 +1435,29 @@ func (*ListenResponse) XXX_OneofWrappers() []interface{} {
 }
 
 type JobStatus struct {
-	Name                 string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Metadata             *JobMetadata   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	Phase                JobPhase       `protobuf:"varint,3,opt,name=phase,proto3,enum=v1.JobPhase" json:"phase,omitempty"`
-	Conditions           *JobConditions `protobuf:"bytes,4,opt,name=conditions,proto3" json:"conditions,omitempty"`
-	Details              string         `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
-	Results              []*JobResult   `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	Name       string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Metadata   *JobMetadata   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Phase      JobPhase       `protobuf:"varint,3,opt,name=phase,proto3,enum=v1.JobPhase" json:"phase,omitempty"`
+	Conditions *JobConditions `protobuf:"bytes,4,opt,name=conditions,proto3" json:"conditions,omitempty"`
+	Details    string         `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
+	Results    []*JobResult   `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`
+	// resource_usage is the CPU and memory usage of the job's pod as reported by the Kubernetes metrics API.
+	// It is only available if the cluster runs a metrics server.
+	ResourceUsage *ResourceUsage `protobuf:"bytes,7,opt,name=resource_usage,json=resourceUsage,proto3" json:"resource_usage,omitempty"`
+	// estimate is the expected duration of a running job based on the recent successful runs of jobs with the same name,
+	// e.g. werft-build-master for werft-build-master.12. It is only available once werft has seen a few such runs.
+	Estimate *DurationEstimate `protobuf:"bytes,8,opt,name=estimate,proto3" json:"estimate,omitempty"`
+	// notes are what users noted about the job, oldest first
+	Notes []*JobNote `protobuf:"bytes,9,rep,name=notes,proto3" json:"notes,omitempty"`
+	// cost is the estimated cost of the job's pod, based on the resources it requests and the time it ran for.
+	// It is only available if werft is configured with resource prices.
+	Cost *JobCost `protobuf:"bytes,10,opt,name=cost,proto3" json:"cost,omitempty"`
+	// environment is what the job ran with, e.g. the digests its images resolved to. It's recorded once the job's
+	// pod runs.
+	Environment *JobEnvironment `protobuf:"bytes,11,opt,name=environment,proto3" json:"environment,omitempty"`
+	// log_summary counts the error, warning and info lines of each log slice and points to the first error,
+	// i.e. the probable cause of a failure
+	LogSummary           *JobLogSummary `protobuf:"bytes,12,opt,name=log_summary,json=logSummary,proto3" json:"log_summary,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
 	XXX_unrecognized     []byte         `json:"-"`
 	XXX_sizecache        int32          `json:"-"`
@@ -1136,1005 +1530,8685 @@ func (m *JobStatus) GetResults() []*JobResult {
 	return nil
 }
 
-type JobMetadata struct {
-	Owner                string               `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
-	Repository           *Repository          `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
-	Trigger              JobTrigger           `protobuf:"varint,3,opt,name=trigger,proto3,enum=v1.JobTrigger" json:"trigger,omitempty"`
-	Created              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
-	Finished             *timestamp.Timestamp `protobuf:"bytes,5,opt,name=finished,proto3" json:"finished,omitempty"`
-	Annotations          []*Annotation        `protobuf:"bytes,6,rep,name=annotations,proto3" json:"annotations,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
-}
-
-func (m *JobMetadata) Reset()         { *m = JobMetadata{} }
-func (m *JobMetadata) String() string { return proto.CompactTextString(m) }
-func (*JobMetadata) ProtoMessage()    {}
-func (*JobMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{16}
-}
-
-func (m *JobMetadata) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_JobMetadata.Unmarshal(m, b)
-}
-func (m *JobMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_JobMetadata.Marshal(b, m, deterministic)
-}
-func (m *JobMetadata) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_JobMetadata.Merge(m, src)
-}
-func (m *JobMetadata) XXX_Size() int {
-	return xxx_messageInfo_JobMetadata.Size(m)
-}
-func (m *JobMetadata) XXX_DiscardUnknown() {
-	xxx_messageInfo_JobMetadata.DiscardUnknown(m)
-}
-
-var xxx_messageInfo_JobMetadata proto.InternalMessageInfo
-
-func (m *JobMetadata) GetOwner() string {
+func (m *JobStatus) GetResourceUsage() *ResourceUsage {
 	if m != nil {
-		return m.Owner
+		return m.ResourceUsage
 	}
-	return ""
+	return nil
 }
 
-func (m *JobMetadata) GetRepository() *Repository {
+func (m *JobStatus) GetEstimate() *DurationEstimate {
 	if m != nil {
-		return m.Repository
+		return m.Estimate
 	}
 	return nil
 }
 
-func (m *JobMetadata) GetTrigger() JobTrigger {
+func (m *JobStatus) GetNotes() []*JobNote {
 	if m != nil {
-		return m.Trigger
+		return m.Notes
 	}
-	return JobTrigger_TRIGGER_UNKNOWN
+	return nil
 }
 
-func (m *JobMetadata) GetCreated() *timestamp.Timestamp {
+func (m *JobStatus) GetCost() *JobCost {
 	if m != nil {
-		return m.Created
+		return m.Cost
 	}
 	return nil
 }
 
-func (m *JobMetadata) GetFinished() *timestamp.Timestamp {
+func (m *JobStatus) GetEnvironment() *JobEnvironment {
 	if m != nil {
-		return m.Finished
+		return m.Environment
 	}
 	return nil
 }
 
-func (m *JobMetadata) GetAnnotations() []*Annotation {
+func (m *JobStatus) GetLogSummary() *JobLogSummary {
 	if m != nil {
-		return m.Annotations
+		return m.LogSummary
 	}
 	return nil
 }
 
-type Repository struct {
-	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
-	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
-	Repo                 string   `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
-	Ref                  string   `protobuf:"bytes,4,opt,name=ref,proto3" json:"ref,omitempty"`
-	Revision             string   `protobuf:"bytes,5,opt,name=revision,proto3" json:"revision,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type JobNote struct {
+	// author is the GitHub user who added the note
+	Author               string               `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Created              *timestamp.Timestamp `protobuf:"bytes,2,opt,name=created,proto3" json:"created,omitempty"`
+	Text                 string               `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *Repository) Reset()         { *m = Repository{} }
-func (m *Repository) String() string { return proto.CompactTextString(m) }
-func (*Repository) ProtoMessage()    {}
-func (*Repository) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{17}
+func (m *JobNote) Reset()         { *m = JobNote{} }
+func (m *JobNote) String() string { return proto.CompactTextString(m) }
+func (*JobNote) ProtoMessage()    {}
+func (*JobNote) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{16}
 }
 
-func (m *Repository) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Repository.Unmarshal(m, b)
+func (m *JobNote) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobNote.Unmarshal(m, b)
 }
-func (m *Repository) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Repository.Marshal(b, m, deterministic)
+func (m *JobNote) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobNote.Marshal(b, m, deterministic)
 }
-func (m *Repository) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Repository.Merge(m, src)
+func (m *JobNote) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobNote.Merge(m, src)
 }
-func (m *Repository) XXX_Size() int {
-	return xxx_messageInfo_Repository.Size(m)
+func (m *JobNote) XXX_Size() int {
+	return xxx_messageInfo_JobNote.Size(m)
 }
-func (m *Repository) XXX_DiscardUnknown() {
-	xxx_messageInfo_Repository.DiscardUnknown(m)
+func (m *JobNote) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobNote.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_Repository proto.InternalMessageInfo
+var xxx_messageInfo_JobNote proto.InternalMessageInfo
 
-func (m *Repository) GetHost() string {
+func (m *JobNote) GetAuthor() string {
 	if m != nil {
-		return m.Host
+		return m.Author
 	}
 	return ""
 }
 
-func (m *Repository) GetOwner() string {
+func (m *JobNote) GetCreated() *timestamp.Timestamp {
 	if m != nil {
-		return m.Owner
+		return m.Created
 	}
-	return ""
+	return nil
 }
 
-func (m *Repository) GetRepo() string {
+func (m *JobNote) GetText() string {
 	if m != nil {
-		return m.Repo
+		return m.Text
 	}
 	return ""
 }
 
-func (m *Repository) GetRef() string {
-	if m != nil {
-		return m.Ref
-	}
-	return ""
+type DurationEstimate struct {
+	// completion is the time the job is expected to be done at
+	Completion *timestamp.Timestamp `protobuf:"bytes,1,opt,name=completion,proto3" json:"completion,omitempty"`
+	// p50_seconds is the median duration of recent successful runs
+	P50Seconds int64 `protobuf:"varint,2,opt,name=p50_seconds,json=p50Seconds,proto3" json:"p50_seconds,omitempty"`
+	// p95_seconds is the 95th percentile of the duration of recent successful runs
+	P95Seconds int64 `protobuf:"varint,3,opt,name=p95_seconds,json=p95Seconds,proto3" json:"p95_seconds,omitempty"`
+	// samples is the number of runs the estimate is based on
+	Samples              int32    `protobuf:"varint,4,opt,name=samples,proto3" json:"samples,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Repository) GetRevision() string {
-	if m != nil {
-		return m.Revision
-	}
-	return ""
-}
-
-type Annotation struct {
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value                string   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *DurationEstimate) Reset()         { *m = DurationEstimate{} }
+func (m *DurationEstimate) String() string { return proto.CompactTextString(m) }
+func (*DurationEstimate) ProtoMessage()    {}
+func (*DurationEstimate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{17}
 }
 
-func (m *Annotation) Reset()         { *m = Annotation{} }
-func (m *Annotation) String() string { return proto.CompactTextString(m) }
-func (*Annotation) ProtoMessage()    {}
-func (*Annotation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{18}
+func (m *DurationEstimate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DurationEstimate.Unmarshal(m, b)
 }
-
-func (m *Annotation) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_Annotation.Unmarshal(m, b)
+func (m *DurationEstimate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DurationEstimate.Marshal(b, m, deterministic)
 }
-func (m *Annotation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_Annotation.Marshal(b, m, deterministic)
+func (m *DurationEstimate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DurationEstimate.Merge(m, src)
 }
-func (m *Annotation) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_Annotation.Merge(m, src)
+func (m *DurationEstimate) XXX_Size() int {
+	return xxx_messageInfo_DurationEstimate.Size(m)
 }
-func (m *Annotation) XXX_Size() int {
-	return xxx_messageInfo_Annotation.Size(m)
+func (m *DurationEstimate) XXX_DiscardUnknown() {
+	xxx_messageInfo_DurationEstimate.DiscardUnknown(m)
 }
-func (m *Annotation) XXX_DiscardUnknown() {
-	xxx_messageInfo_Annotation.DiscardUnknown(m)
+
+var xxx_messageInfo_DurationEstimate proto.InternalMessageInfo
+
+func (m *DurationEstimate) GetCompletion() *timestamp.Timestamp {
+	if m != nil {
+		return m.Completion
+	}
+	return nil
 }
 
-var xxx_messageInfo_Annotation proto.InternalMessageInfo
+func (m *DurationEstimate) GetP50Seconds() int64 {
+	if m != nil {
+		return m.P50Seconds
+	}
+	return 0
+}
 
-func (m *Annotation) GetKey() string {
+func (m *DurationEstimate) GetP95Seconds() int64 {
 	if m != nil {
-		return m.Key
+		return m.P95Seconds
 	}
-	return ""
+	return 0
 }
 
-func (m *Annotation) GetValue() string {
+func (m *DurationEstimate) GetSamples() int32 {
 	if m != nil {
-		return m.Value
+		return m.Samples
 	}
-	return ""
+	return 0
 }
 
-type JobConditions struct {
-	Success              bool                 `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	FailureCount         int32                `protobuf:"varint,2,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
-	CanReplay            bool                 `protobuf:"varint,3,opt,name=can_replay,json=canReplay,proto3" json:"can_replay,omitempty"`
-	WaitUntil            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=wait_until,json=waitUntil,proto3" json:"wait_until,omitempty"`
-	DidExecute           bool                 `protobuf:"varint,5,opt,name=did_execute,json=didExecute,proto3" json:"did_execute,omitempty"`
+type ResourceUsage struct {
+	// cpu_millis is the CPU usage in millicores
+	CpuMillis int64 `protobuf:"varint,1,opt,name=cpu_millis,json=cpuMillis,proto3" json:"cpu_millis,omitempty"`
+	// memory_bytes is the working set memory usage in bytes
+	MemoryBytes          int64                `protobuf:"varint,2,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	PeakCpuMillis        int64                `protobuf:"varint,3,opt,name=peak_cpu_millis,json=peakCpuMillis,proto3" json:"peak_cpu_millis,omitempty"`
+	PeakMemoryBytes      int64                `protobuf:"varint,4,opt,name=peak_memory_bytes,json=peakMemoryBytes,proto3" json:"peak_memory_bytes,omitempty"`
+	Sampled              *timestamp.Timestamp `protobuf:"bytes,5,opt,name=sampled,proto3" json:"sampled,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
 	XXX_unrecognized     []byte               `json:"-"`
 	XXX_sizecache        int32                `json:"-"`
 }
 
-func (m *JobConditions) Reset()         { *m = JobConditions{} }
-func (m *JobConditions) String() string { return proto.CompactTextString(m) }
-func (*JobConditions) ProtoMessage()    {}
-func (*JobConditions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{19}
+func (m *ResourceUsage) Reset()         { *m = ResourceUsage{} }
+func (m *ResourceUsage) String() string { return proto.CompactTextString(m) }
+func (*ResourceUsage) ProtoMessage()    {}
+func (*ResourceUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{18}
 }
 
-func (m *JobConditions) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_JobConditions.Unmarshal(m, b)
+func (m *ResourceUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceUsage.Unmarshal(m, b)
 }
-func (m *JobConditions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_JobConditions.Marshal(b, m, deterministic)
+func (m *ResourceUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceUsage.Marshal(b, m, deterministic)
 }
-func (m *JobConditions) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_JobConditions.Merge(m, src)
+func (m *ResourceUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceUsage.Merge(m, src)
 }
-func (m *JobConditions) XXX_Size() int {
-	return xxx_messageInfo_JobConditions.Size(m)
+func (m *ResourceUsage) XXX_Size() int {
+	return xxx_messageInfo_ResourceUsage.Size(m)
 }
-func (m *JobConditions) XXX_DiscardUnknown() {
-	xxx_messageInfo_JobConditions.DiscardUnknown(m)
+func (m *ResourceUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceUsage.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_JobConditions proto.InternalMessageInfo
+var xxx_messageInfo_ResourceUsage proto.InternalMessageInfo
 
-func (m *JobConditions) GetSuccess() bool {
+func (m *ResourceUsage) GetCpuMillis() int64 {
 	if m != nil {
-		return m.Success
+		return m.CpuMillis
 	}
-	return false
+	return 0
 }
 
-func (m *JobConditions) GetFailureCount() int32 {
+func (m *ResourceUsage) GetMemoryBytes() int64 {
 	if m != nil {
-		return m.FailureCount
+		return m.MemoryBytes
 	}
 	return 0
 }
 
-func (m *JobConditions) GetCanReplay() bool {
+func (m *ResourceUsage) GetPeakCpuMillis() int64 {
 	if m != nil {
-		return m.CanReplay
+		return m.PeakCpuMillis
 	}
-	return false
+	return 0
 }
 
-func (m *JobConditions) GetWaitUntil() *timestamp.Timestamp {
+func (m *ResourceUsage) GetPeakMemoryBytes() int64 {
 	if m != nil {
-		return m.WaitUntil
+		return m.PeakMemoryBytes
 	}
-	return nil
+	return 0
 }
 
-func (m *JobConditions) GetDidExecute() bool {
+func (m *ResourceUsage) GetSampled() *timestamp.Timestamp {
 	if m != nil {
-		return m.DidExecute
+		return m.Sampled
 	}
-	return false
+	return nil
 }
 
-type JobResult struct {
-	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Payload              string   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
-	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Channels             []string `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+type JobMetadata struct {
+	Owner       string               `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`
+	Repository  *Repository          `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
+	Trigger     JobTrigger           `protobuf:"varint,3,opt,name=trigger,proto3,enum=v1.JobTrigger" json:"trigger,omitempty"`
+	Created     *timestamp.Timestamp `protobuf:"bytes,4,opt,name=created,proto3" json:"created,omitempty"`
+	Finished    *timestamp.Timestamp `protobuf:"bytes,5,opt,name=finished,proto3" json:"finished,omitempty"`
+	Annotations []*Annotation        `protobuf:"bytes,6,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	Labels      map[string]string    `protobuf:"bytes,7,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// parent names the matrix job this job was expanded from
+	Parent string `protobuf:"bytes,8,opt,name=parent,proto3" json:"parent,omitempty"`
+	// children names the jobs a matrix job was expanded into
+	Children []string `protobuf:"bytes,9,rep,name=children,proto3" json:"children,omitempty"`
+	// spec_hash identifies the resolved job spec and pod template the job ran with.
+	// Jobs with the same hash ran with the same spec.
+	SpecHash string `protobuf:"bytes,10,opt,name=spec_hash,json=specHash,proto3" json:"spec_hash,omitempty"`
+	// commit_range describes the commits a push brought to the job's ref. Only jobs started by a push have one.
+	CommitRange *CommitRange `protobuf:"bytes,11,opt,name=commit_range,json=commitRange,proto3" json:"commit_range,omitempty"`
+	// merge_group is the merge group of jobs started by a merge queue. The job's revision is the head of the merge group.
+	MergeGroup *MergeGroup `protobuf:"bytes,12,opt,name=merge_group,json=mergeGroup,proto3" json:"merge_group,omitempty"`
+	// job_spec_source is where the job spec came from if that's not the job's revision, e.g. the default branch
+	// when testing a pull request with the pipeline of the default branch.
+	JobSpecSource *Repository `protobuf:"bytes,13,opt,name=job_spec_source,json=jobSpecSource,proto3" json:"job_spec_source,omitempty"`
+	// source_verified is set by werft only: it's true if werft made sure the job's ref pointed to its revision when
+	// the job started, e.g. because a GitHub webhook said so, and the job spec came from the repository.
+	SourceVerified       bool     `protobuf:"varint,14,opt,name=source_verified,json=sourceVerified,proto3" json:"source_verified,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *JobResult) Reset()         { *m = JobResult{} }
-func (m *JobResult) String() string { return proto.CompactTextString(m) }
-func (*JobResult) ProtoMessage()    {}
-func (*JobResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{20}
+func (m *JobMetadata) Reset()         { *m = JobMetadata{} }
+func (m *JobMetadata) String() string { return proto.CompactTextString(m) }
+func (*JobMetadata) ProtoMessage()    {}
+func (*JobMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{19}
 }
 
-func (m *JobResult) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_JobResult.Unmarshal(m, b)
+func (m *JobMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobMetadata.Unmarshal(m, b)
 }
-func (m *JobResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_JobResult.Marshal(b, m, deterministic)
+func (m *JobMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobMetadata.Marshal(b, m, deterministic)
 }
-func (m *JobResult) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_JobResult.Merge(m, src)
+func (m *JobMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobMetadata.Merge(m, src)
 }
-func (m *JobResult) XXX_Size() int {
-	return xxx_messageInfo_JobResult.Size(m)
+func (m *JobMetadata) XXX_Size() int {
+	return xxx_messageInfo_JobMetadata.Size(m)
 }
-func (m *JobResult) XXX_DiscardUnknown() {
-	xxx_messageInfo_JobResult.DiscardUnknown(m)
+func (m *JobMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobMetadata.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_JobResult proto.InternalMessageInfo
+var xxx_messageInfo_JobMetadata proto.InternalMessageInfo
 
-func (m *JobResult) GetType() string {
+func (m *JobMetadata) GetOwner() string {
 	if m != nil {
-		return m.Type
+		return m.Owner
 	}
 	return ""
 }
 
-func (m *JobResult) GetPayload() string {
+func (m *JobMetadata) GetRepository() *Repository {
 	if m != nil {
-		return m.Payload
+		return m.Repository
 	}
-	return ""
+	return nil
 }
 
-func (m *JobResult) GetDescription() string {
+func (m *JobMetadata) GetTrigger() JobTrigger {
 	if m != nil {
-		return m.Description
+		return m.Trigger
 	}
-	return ""
+	return JobTrigger_TRIGGER_UNKNOWN
 }
 
-func (m *JobResult) GetChannels() []string {
+func (m *JobMetadata) GetCreated() *timestamp.Timestamp {
 	if m != nil {
-		return m.Channels
+		return m.Created
 	}
 	return nil
 }
 
-type LogSliceEvent struct {
-	Name                 string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Type                 LogSliceType `protobuf:"varint,2,opt,name=type,proto3,enum=v1.LogSliceType" json:"type,omitempty"`
-	Payload              string       `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+func (m *JobMetadata) GetFinished() *timestamp.Timestamp {
+	if m != nil {
+		return m.Finished
+	}
+	return nil
 }
 
-func (m *LogSliceEvent) Reset()         { *m = LogSliceEvent{} }
-func (m *LogSliceEvent) String() string { return proto.CompactTextString(m) }
-func (*LogSliceEvent) ProtoMessage()    {}
-func (*LogSliceEvent) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{21}
+func (m *JobMetadata) GetAnnotations() []*Annotation {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
 }
 
-func (m *LogSliceEvent) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_LogSliceEvent.Unmarshal(m, b)
-}
-func (m *LogSliceEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_LogSliceEvent.Marshal(b, m, deterministic)
+func (m *JobMetadata) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
 }
-func (m *LogSliceEvent) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LogSliceEvent.Merge(m, src)
+
+func (m *JobMetadata) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
 }
-func (m *LogSliceEvent) XXX_Size() int {
-	return xxx_messageInfo_LogSliceEvent.Size(m)
+
+func (m *JobMetadata) GetChildren() []string {
+	if m != nil {
+		return m.Children
+	}
+	return nil
 }
-func (m *LogSliceEvent) XXX_DiscardUnknown() {
-	xxx_messageInfo_LogSliceEvent.DiscardUnknown(m)
+
+func (m *JobMetadata) GetSpecHash() string {
+	if m != nil {
+		return m.SpecHash
+	}
+	return ""
 }
 
-var xxx_messageInfo_LogSliceEvent proto.InternalMessageInfo
+func (m *JobMetadata) GetCommitRange() *CommitRange {
+	if m != nil {
+		return m.CommitRange
+	}
+	return nil
+}
 
-func (m *LogSliceEvent) GetName() string {
+func (m *JobMetadata) GetMergeGroup() *MergeGroup {
 	if m != nil {
-		return m.Name
+		return m.MergeGroup
 	}
-	return ""
+	return nil
 }
 
-func (m *LogSliceEvent) GetType() LogSliceType {
+func (m *JobMetadata) GetJobSpecSource() *Repository {
 	if m != nil {
-		return m.Type
+		return m.JobSpecSource
 	}
-	return LogSliceType_SLICE_ABANDONED
+	return nil
 }
 
-func (m *LogSliceEvent) GetPayload() string {
+func (m *JobMetadata) GetSourceVerified() bool {
 	if m != nil {
-		return m.Payload
+		return m.SourceVerified
 	}
-	return ""
+	return false
 }
 
-type StopJobRequest struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+type CommitRange struct {
+	// before is the revision the ref pointed to before the push. It's all zeros if the push created the ref.
+	Before string `protobuf:"bytes,1,opt,name=before,proto3" json:"before,omitempty"`
+	// after is the revision the ref points to after the push, i.e. the revision of the job
+	After string `protobuf:"bytes,2,opt,name=after,proto3" json:"after,omitempty"`
+	// compare_url shows the changes between before and after on GitHub
+	CompareUrl string `protobuf:"bytes,3,opt,name=compare_url,json=compareUrl,proto3" json:"compare_url,omitempty"`
+	// forced is true if the push rewrote the history of the ref
+	Forced bool `protobuf:"varint,4,opt,name=forced,proto3" json:"forced,omitempty"`
+	// commits lists the commits the push brought to the ref, oldest first
+	Commits []*Commit `protobuf:"bytes,5,rep,name=commits,proto3" json:"commits,omitempty"`
+	// truncated is true if the push brought more commits or changed more files than werft records
+	Truncated            bool     `protobuf:"varint,6,opt,name=truncated,proto3" json:"truncated,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *StopJobRequest) Reset()         { *m = StopJobRequest{} }
-func (m *StopJobRequest) String() string { return proto.CompactTextString(m) }
-func (*StopJobRequest) ProtoMessage()    {}
-func (*StopJobRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{22}
+func (m *CommitRange) Reset()         { *m = CommitRange{} }
+func (m *CommitRange) String() string { return proto.CompactTextString(m) }
+func (*CommitRange) ProtoMessage()    {}
+func (*CommitRange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{20}
 }
 
-func (m *StopJobRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StopJobRequest.Unmarshal(m, b)
+func (m *CommitRange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitRange.Unmarshal(m, b)
 }
-func (m *StopJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StopJobRequest.Marshal(b, m, deterministic)
+func (m *CommitRange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitRange.Marshal(b, m, deterministic)
 }
-func (m *StopJobRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StopJobRequest.Merge(m, src)
+func (m *CommitRange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitRange.Merge(m, src)
 }
-func (m *StopJobRequest) XXX_Size() int {
-	return xxx_messageInfo_StopJobRequest.Size(m)
+func (m *CommitRange) XXX_Size() int {
+	return xxx_messageInfo_CommitRange.Size(m)
 }
-func (m *StopJobRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_StopJobRequest.DiscardUnknown(m)
+func (m *CommitRange) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitRange.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_StopJobRequest proto.InternalMessageInfo
+var xxx_messageInfo_CommitRange proto.InternalMessageInfo
 
-func (m *StopJobRequest) GetName() string {
+func (m *CommitRange) GetBefore() string {
 	if m != nil {
-		return m.Name
+		return m.Before
 	}
 	return ""
 }
 
-type StopJobResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *CommitRange) GetAfter() string {
+	if m != nil {
+		return m.After
+	}
+	return ""
 }
 
-func (m *StopJobResponse) Reset()         { *m = StopJobResponse{} }
-func (m *StopJobResponse) String() string { return proto.CompactTextString(m) }
-func (*StopJobResponse) ProtoMessage()    {}
-func (*StopJobResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9fe744feedd6d332, []int{23}
+func (m *CommitRange) GetCompareUrl() string {
+	if m != nil {
+		return m.CompareUrl
+	}
+	return ""
 }
 
-func (m *StopJobResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_StopJobResponse.Unmarshal(m, b)
-}
-func (m *StopJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_StopJobResponse.Marshal(b, m, deterministic)
+func (m *CommitRange) GetForced() bool {
+	if m != nil {
+		return m.Forced
+	}
+	return false
 }
-func (m *StopJobResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_StopJobResponse.Merge(m, src)
+
+func (m *CommitRange) GetCommits() []*Commit {
+	if m != nil {
+		return m.Commits
+	}
+	return nil
+}
+
+func (m *CommitRange) GetTruncated() bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return false
+}
+
+type MergeGroup struct {
+	// head_sha is the commit the merge queue wants checked, i.e. the base with the queued pull requests merged
+	HeadSha string `protobuf:"bytes,1,opt,name=head_sha,json=headSha,proto3" json:"head_sha,omitempty"`
+	// head_ref is the temporary branch of the merge group, e.g. refs/heads/gh-readonly-queue/main/pr-42-...
+	HeadRef string `protobuf:"bytes,2,opt,name=head_ref,json=headRef,proto3" json:"head_ref,omitempty"`
+	// base_sha is the commit of the base branch the merge group builds upon
+	BaseSha string `protobuf:"bytes,3,opt,name=base_sha,json=baseSha,proto3" json:"base_sha,omitempty"`
+	// base_ref is the branch the merge group is merged into, e.g. refs/heads/main
+	BaseRef              string   `protobuf:"bytes,4,opt,name=base_ref,json=baseRef,proto3" json:"base_ref,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MergeGroup) Reset()         { *m = MergeGroup{} }
+func (m *MergeGroup) String() string { return proto.CompactTextString(m) }
+func (*MergeGroup) ProtoMessage()    {}
+func (*MergeGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{21}
+}
+
+func (m *MergeGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MergeGroup.Unmarshal(m, b)
+}
+func (m *MergeGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MergeGroup.Marshal(b, m, deterministic)
+}
+func (m *MergeGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MergeGroup.Merge(m, src)
+}
+func (m *MergeGroup) XXX_Size() int {
+	return xxx_messageInfo_MergeGroup.Size(m)
+}
+func (m *MergeGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_MergeGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MergeGroup proto.InternalMessageInfo
+
+func (m *MergeGroup) GetHeadSha() string {
+	if m != nil {
+		return m.HeadSha
+	}
+	return ""
+}
+
+func (m *MergeGroup) GetHeadRef() string {
+	if m != nil {
+		return m.HeadRef
+	}
+	return ""
+}
+
+func (m *MergeGroup) GetBaseSha() string {
+	if m != nil {
+		return m.BaseSha
+	}
+	return ""
+}
+
+func (m *MergeGroup) GetBaseRef() string {
+	if m != nil {
+		return m.BaseRef
+	}
+	return ""
+}
+
+type Commit struct {
+	Sha                  string               `protobuf:"bytes,1,opt,name=sha,proto3" json:"sha,omitempty"`
+	Message              string               `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	AuthorName           string               `protobuf:"bytes,3,opt,name=author_name,json=authorName,proto3" json:"author_name,omitempty"`
+	AuthorEmail          string               `protobuf:"bytes,4,opt,name=author_email,json=authorEmail,proto3" json:"author_email,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Url                  string               `protobuf:"bytes,6,opt,name=url,proto3" json:"url,omitempty"`
+	Added                []string             `protobuf:"bytes,7,rep,name=added,proto3" json:"added,omitempty"`
+	Removed              []string             `protobuf:"bytes,8,rep,name=removed,proto3" json:"removed,omitempty"`
+	Modified             []string             `protobuf:"bytes,9,rep,name=modified,proto3" json:"modified,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Commit) Reset()         { *m = Commit{} }
+func (m *Commit) String() string { return proto.CompactTextString(m) }
+func (*Commit) ProtoMessage()    {}
+func (*Commit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{22}
+}
+
+func (m *Commit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Commit.Unmarshal(m, b)
+}
+func (m *Commit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Commit.Marshal(b, m, deterministic)
+}
+func (m *Commit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Commit.Merge(m, src)
+}
+func (m *Commit) XXX_Size() int {
+	return xxx_messageInfo_Commit.Size(m)
+}
+func (m *Commit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Commit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Commit proto.InternalMessageInfo
+
+func (m *Commit) GetSha() string {
+	if m != nil {
+		return m.Sha
+	}
+	return ""
+}
+
+func (m *Commit) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Commit) GetAuthorName() string {
+	if m != nil {
+		return m.AuthorName
+	}
+	return ""
+}
+
+func (m *Commit) GetAuthorEmail() string {
+	if m != nil {
+		return m.AuthorEmail
+	}
+	return ""
+}
+
+func (m *Commit) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *Commit) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *Commit) GetAdded() []string {
+	if m != nil {
+		return m.Added
+	}
+	return nil
+}
+
+func (m *Commit) GetRemoved() []string {
+	if m != nil {
+		return m.Removed
+	}
+	return nil
+}
+
+func (m *Commit) GetModified() []string {
+	if m != nil {
+		return m.Modified
+	}
+	return nil
+}
+
+type Repository struct {
+	Host                 string   `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	Owner                string   `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Repo                 string   `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
+	Ref                  string   `protobuf:"bytes,4,opt,name=ref,proto3" json:"ref,omitempty"`
+	Revision             string   `protobuf:"bytes,5,opt,name=revision,proto3" json:"revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Repository) Reset()         { *m = Repository{} }
+func (m *Repository) String() string { return proto.CompactTextString(m) }
+func (*Repository) ProtoMessage()    {}
+func (*Repository) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{23}
+}
+
+func (m *Repository) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Repository.Unmarshal(m, b)
+}
+func (m *Repository) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Repository.Marshal(b, m, deterministic)
+}
+func (m *Repository) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Repository.Merge(m, src)
+}
+func (m *Repository) XXX_Size() int {
+	return xxx_messageInfo_Repository.Size(m)
+}
+func (m *Repository) XXX_DiscardUnknown() {
+	xxx_messageInfo_Repository.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Repository proto.InternalMessageInfo
+
+func (m *Repository) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *Repository) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *Repository) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *Repository) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *Repository) GetRevision() string {
+	if m != nil {
+		return m.Revision
+	}
+	return ""
+}
+
+type Annotation struct {
+	Key   string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// secret annotations are handed to the job's pod as an environment variable named after their key. Werft never stores
+	// their value and returns them redacted.
+	Secret               bool     `protobuf:"varint,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Annotation) Reset()         { *m = Annotation{} }
+func (m *Annotation) String() string { return proto.CompactTextString(m) }
+func (*Annotation) ProtoMessage()    {}
+func (*Annotation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{24}
+}
+
+func (m *Annotation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Annotation.Unmarshal(m, b)
+}
+func (m *Annotation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Annotation.Marshal(b, m, deterministic)
+}
+func (m *Annotation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Annotation.Merge(m, src)
+}
+func (m *Annotation) XXX_Size() int {
+	return xxx_messageInfo_Annotation.Size(m)
+}
+func (m *Annotation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Annotation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Annotation proto.InternalMessageInfo
+
+func (m *Annotation) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Annotation) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+func (m *Annotation) GetSecret() bool {
+	if m != nil {
+		return m.Secret
+	}
+	return false
+}
+
+type JobEvent struct {
+	Type JobEventType         `protobuf:"varint,1,opt,name=type,proto3,enum=v1.JobEventType" json:"type,omitempty"`
+	Time *timestamp.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// phase is the phase the job entered, for events which change the phase of the job
+	Phase JobPhase `protobuf:"varint,3,opt,name=phase,proto3,enum=v1.JobPhase" json:"phase,omitempty"`
+	// message describes the event, e.g. why a job was stopped
+	Message              string   `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobEvent) Reset()         { *m = JobEvent{} }
+func (m *JobEvent) String() string { return proto.CompactTextString(m) }
+func (*JobEvent) ProtoMessage()    {}
+func (*JobEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{25}
+}
+
+func (m *JobEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobEvent.Unmarshal(m, b)
+}
+func (m *JobEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobEvent.Marshal(b, m, deterministic)
+}
+func (m *JobEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobEvent.Merge(m, src)
+}
+func (m *JobEvent) XXX_Size() int {
+	return xxx_messageInfo_JobEvent.Size(m)
+}
+func (m *JobEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobEvent proto.InternalMessageInfo
+
+func (m *JobEvent) GetType() JobEventType {
+	if m != nil {
+		return m.Type
+	}
+	return JobEventType_EVENT_UNKNOWN
+}
+
+func (m *JobEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *JobEvent) GetPhase() JobPhase {
+	if m != nil {
+		return m.Phase
+	}
+	return JobPhase_PHASE_UNKNOWN
+}
+
+func (m *JobEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type JobConditions struct {
+	Success      bool                 `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	FailureCount int32                `protobuf:"varint,2,opt,name=failure_count,json=failureCount,proto3" json:"failure_count,omitempty"`
+	CanReplay    bool                 `protobuf:"varint,3,opt,name=can_replay,json=canReplay,proto3" json:"can_replay,omitempty"`
+	WaitUntil    *timestamp.Timestamp `protobuf:"bytes,4,opt,name=wait_until,json=waitUntil,proto3" json:"wait_until,omitempty"`
+	DidExecute   bool                 `protobuf:"varint,5,opt,name=did_execute,json=didExecute,proto3" json:"did_execute,omitempty"`
+	// infrastructure_failure is true if the job failed because of its pod (e.g. evicted, OOMKilled, image pull errors)
+	// rather than the job itself.
+	InfrastructureFailure bool `protobuf:"varint,6,opt,name=infrastructure_failure,json=infrastructureFailure,proto3" json:"infrastructure_failure,omitempty"`
+	// attempt counts how often this job has been tried, starting at 1. Retries of a job carry a higher attempt count.
+	Attempt int32 `protobuf:"varint,7,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// skipped is true if the job did not run because its sampling policy left it out. The job details tell why.
+	Skipped bool `protobuf:"varint,8,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	// quota_exceeded is true if the job did not run because it would have exceeded a quota. The job details tell which.
+	QuotaExceeded bool `protobuf:"varint,9,opt,name=quota_exceeded,json=quotaExceeded,proto3" json:"quota_exceeded,omitempty"`
+	// muted is true if the job is known to be flaky and its failure was muted because the job is rerun.
+	// The job only counts as failed if the rerun fails, too.
+	Muted                bool     `protobuf:"varint,10,opt,name=muted,proto3" json:"muted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobConditions) Reset()         { *m = JobConditions{} }
+func (m *JobConditions) String() string { return proto.CompactTextString(m) }
+func (*JobConditions) ProtoMessage()    {}
+func (*JobConditions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{26}
+}
+
+func (m *JobConditions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobConditions.Unmarshal(m, b)
+}
+func (m *JobConditions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobConditions.Marshal(b, m, deterministic)
+}
+func (m *JobConditions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobConditions.Merge(m, src)
+}
+func (m *JobConditions) XXX_Size() int {
+	return xxx_messageInfo_JobConditions.Size(m)
+}
+func (m *JobConditions) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobConditions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobConditions proto.InternalMessageInfo
+
+func (m *JobConditions) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *JobConditions) GetFailureCount() int32 {
+	if m != nil {
+		return m.FailureCount
+	}
+	return 0
+}
+
+func (m *JobConditions) GetCanReplay() bool {
+	if m != nil {
+		return m.CanReplay
+	}
+	return false
+}
+
+func (m *JobConditions) GetWaitUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.WaitUntil
+	}
+	return nil
+}
+
+func (m *JobConditions) GetDidExecute() bool {
+	if m != nil {
+		return m.DidExecute
+	}
+	return false
+}
+
+func (m *JobConditions) GetInfrastructureFailure() bool {
+	if m != nil {
+		return m.InfrastructureFailure
+	}
+	return false
+}
+
+func (m *JobConditions) GetAttempt() int32 {
+	if m != nil {
+		return m.Attempt
+	}
+	return 0
+}
+
+func (m *JobConditions) GetSkipped() bool {
+	if m != nil {
+		return m.Skipped
+	}
+	return false
+}
+
+func (m *JobConditions) GetQuotaExceeded() bool {
+	if m != nil {
+		return m.QuotaExceeded
+	}
+	return false
+}
+
+func (m *JobConditions) GetMuted() bool {
+	if m != nil {
+		return m.Muted
+	}
+	return false
+}
+
+type JobResult struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Payload              string   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Description          string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Channels             []string `protobuf:"bytes,4,rep,name=channels,proto3" json:"channels,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobResult) Reset()         { *m = JobResult{} }
+func (m *JobResult) String() string { return proto.CompactTextString(m) }
+func (*JobResult) ProtoMessage()    {}
+func (*JobResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{27}
+}
+
+func (m *JobResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobResult.Unmarshal(m, b)
+}
+func (m *JobResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobResult.Marshal(b, m, deterministic)
+}
+func (m *JobResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobResult.Merge(m, src)
+}
+func (m *JobResult) XXX_Size() int {
+	return xxx_messageInfo_JobResult.Size(m)
+}
+func (m *JobResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobResult proto.InternalMessageInfo
+
+func (m *JobResult) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *JobResult) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+func (m *JobResult) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *JobResult) GetChannels() []string {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type LogSliceEvent struct {
+	Name                 string       `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type                 LogSliceType `protobuf:"varint,2,opt,name=type,proto3,enum=v1.LogSliceType" json:"type,omitempty"`
+	Payload              string       `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *LogSliceEvent) Reset()         { *m = LogSliceEvent{} }
+func (m *LogSliceEvent) String() string { return proto.CompactTextString(m) }
+func (*LogSliceEvent) ProtoMessage()    {}
+func (*LogSliceEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{28}
+}
+
+func (m *LogSliceEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSliceEvent.Unmarshal(m, b)
+}
+func (m *LogSliceEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSliceEvent.Marshal(b, m, deterministic)
+}
+func (m *LogSliceEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSliceEvent.Merge(m, src)
+}
+func (m *LogSliceEvent) XXX_Size() int {
+	return xxx_messageInfo_LogSliceEvent.Size(m)
+}
+func (m *LogSliceEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSliceEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSliceEvent proto.InternalMessageInfo
+
+func (m *LogSliceEvent) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LogSliceEvent) GetType() LogSliceType {
+	if m != nil {
+		return m.Type
+	}
+	return LogSliceType_SLICE_ABANDONED
+}
+
+func (m *LogSliceEvent) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+type StopJobRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StopJobRequest) Reset()         { *m = StopJobRequest{} }
+func (m *StopJobRequest) String() string { return proto.CompactTextString(m) }
+func (*StopJobRequest) ProtoMessage()    {}
+func (*StopJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{29}
+}
+
+func (m *StopJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StopJobRequest.Unmarshal(m, b)
+}
+func (m *StopJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StopJobRequest.Marshal(b, m, deterministic)
+}
+func (m *StopJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StopJobRequest.Merge(m, src)
+}
+func (m *StopJobRequest) XXX_Size() int {
+	return xxx_messageInfo_StopJobRequest.Size(m)
+}
+func (m *StopJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StopJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StopJobRequest proto.InternalMessageInfo
+
+func (m *StopJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type StopJobResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StopJobResponse) Reset()         { *m = StopJobResponse{} }
+func (m *StopJobResponse) String() string { return proto.CompactTextString(m) }
+func (*StopJobResponse) ProtoMessage()    {}
+func (*StopJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{30}
+}
+
+func (m *StopJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StopJobResponse.Unmarshal(m, b)
+}
+func (m *StopJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StopJobResponse.Marshal(b, m, deterministic)
+}
+func (m *StopJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StopJobResponse.Merge(m, src)
 }
 func (m *StopJobResponse) XXX_Size() int {
 	return xxx_messageInfo_StopJobResponse.Size(m)
 }
-func (m *StopJobResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_StopJobResponse.DiscardUnknown(m)
+func (m *StopJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StopJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StopJobResponse proto.InternalMessageInfo
+
+type AttachJobRequest struct {
+	// Types that are valid to be assigned to Content:
+	//	*AttachJobRequest_Start
+	//	*AttachJobRequest_Stdin
+	//	*AttachJobRequest_Resize
+	Content              isAttachJobRequest_Content `protobuf_oneof:"content"`
+	XXX_NoUnkeyedLiteral struct{}                   `json:"-"`
+	XXX_unrecognized     []byte                     `json:"-"`
+	XXX_sizecache        int32                      `json:"-"`
+}
+
+func (m *AttachJobRequest) Reset()         { *m = AttachJobRequest{} }
+func (m *AttachJobRequest) String() string { return proto.CompactTextString(m) }
+func (*AttachJobRequest) ProtoMessage()    {}
+func (*AttachJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{31}
+}
+
+func (m *AttachJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttachJobRequest.Unmarshal(m, b)
+}
+func (m *AttachJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttachJobRequest.Marshal(b, m, deterministic)
+}
+func (m *AttachJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttachJobRequest.Merge(m, src)
+}
+func (m *AttachJobRequest) XXX_Size() int {
+	return xxx_messageInfo_AttachJobRequest.Size(m)
+}
+func (m *AttachJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttachJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttachJobRequest proto.InternalMessageInfo
+
+type isAttachJobRequest_Content interface {
+	isAttachJobRequest_Content()
+}
+
+type AttachJobRequest_Start struct {
+	Start *AttachJobStart `protobuf:"bytes,1,opt,name=start,proto3,oneof"`
+}
+
+type AttachJobRequest_Stdin struct {
+	Stdin []byte `protobuf:"bytes,2,opt,name=stdin,proto3,oneof"`
+}
+
+type AttachJobRequest_Resize struct {
+	Resize *TerminalSize `protobuf:"bytes,3,opt,name=resize,proto3,oneof"`
+}
+
+func (*AttachJobRequest_Start) isAttachJobRequest_Content() {}
+
+func (*AttachJobRequest_Stdin) isAttachJobRequest_Content() {}
+
+func (*AttachJobRequest_Resize) isAttachJobRequest_Content() {}
+
+func (m *AttachJobRequest) GetContent() isAttachJobRequest_Content {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *AttachJobRequest) GetStart() *AttachJobStart {
+	if x, ok := m.GetContent().(*AttachJobRequest_Start); ok {
+		return x.Start
+	}
+	return nil
+}
+
+func (m *AttachJobRequest) GetStdin() []byte {
+	if x, ok := m.GetContent().(*AttachJobRequest_Stdin); ok {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (m *AttachJobRequest) GetResize() *TerminalSize {
+	if x, ok := m.GetContent().(*AttachJobRequest_Resize); ok {
+		return x.Resize
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*AttachJobRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*AttachJobRequest_Start)(nil),
+		(*AttachJobRequest_Stdin)(nil),
+		(*AttachJobRequest_Resize)(nil),
+	}
+}
+
+type AttachJobStart struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// container to run the command in. Defaults to the first container of the job.
+	Container string `protobuf:"bytes,2,opt,name=container,proto3" json:"container,omitempty"`
+	// command to run. Defaults to sh.
+	Command []string      `protobuf:"bytes,3,rep,name=command,proto3" json:"command,omitempty"`
+	Tty     bool          `protobuf:"varint,4,opt,name=tty,proto3" json:"tty,omitempty"`
+	Size    *TerminalSize `protobuf:"bytes,5,opt,name=size,proto3" json:"size,omitempty"`
+	// github_token identifies the user who attaches. The user needs sufficient permissions on the job's repository.
+	GithubToken          string   `protobuf:"bytes,6,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AttachJobStart) Reset()         { *m = AttachJobStart{} }
+func (m *AttachJobStart) String() string { return proto.CompactTextString(m) }
+func (*AttachJobStart) ProtoMessage()    {}
+func (*AttachJobStart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{32}
+}
+
+func (m *AttachJobStart) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttachJobStart.Unmarshal(m, b)
+}
+func (m *AttachJobStart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttachJobStart.Marshal(b, m, deterministic)
+}
+func (m *AttachJobStart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttachJobStart.Merge(m, src)
+}
+func (m *AttachJobStart) XXX_Size() int {
+	return xxx_messageInfo_AttachJobStart.Size(m)
+}
+func (m *AttachJobStart) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttachJobStart.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttachJobStart proto.InternalMessageInfo
+
+func (m *AttachJobStart) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AttachJobStart) GetContainer() string {
+	if m != nil {
+		return m.Container
+	}
+	return ""
+}
+
+func (m *AttachJobStart) GetCommand() []string {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *AttachJobStart) GetTty() bool {
+	if m != nil {
+		return m.Tty
+	}
+	return false
+}
+
+func (m *AttachJobStart) GetSize() *TerminalSize {
+	if m != nil {
+		return m.Size
+	}
+	return nil
+}
+
+func (m *AttachJobStart) GetGithubToken() string {
+	if m != nil {
+		return m.GithubToken
+	}
+	return ""
+}
+
+type TerminalSize struct {
+	Width                uint32   `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height               uint32   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TerminalSize) Reset()         { *m = TerminalSize{} }
+func (m *TerminalSize) String() string { return proto.CompactTextString(m) }
+func (*TerminalSize) ProtoMessage()    {}
+func (*TerminalSize) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{33}
+}
+
+func (m *TerminalSize) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TerminalSize.Unmarshal(m, b)
+}
+func (m *TerminalSize) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TerminalSize.Marshal(b, m, deterministic)
+}
+func (m *TerminalSize) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TerminalSize.Merge(m, src)
+}
+func (m *TerminalSize) XXX_Size() int {
+	return xxx_messageInfo_TerminalSize.Size(m)
+}
+func (m *TerminalSize) XXX_DiscardUnknown() {
+	xxx_messageInfo_TerminalSize.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TerminalSize proto.InternalMessageInfo
+
+func (m *TerminalSize) GetWidth() uint32 {
+	if m != nil {
+		return m.Width
+	}
+	return 0
+}
+
+func (m *TerminalSize) GetHeight() uint32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type AttachJobResponse struct {
+	// Types that are valid to be assigned to Content:
+	//	*AttachJobResponse_Stdout
+	//	*AttachJobResponse_Stderr
+	//	*AttachJobResponse_ExitCode
+	Content              isAttachJobResponse_Content `protobuf_oneof:"content"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *AttachJobResponse) Reset()         { *m = AttachJobResponse{} }
+func (m *AttachJobResponse) String() string { return proto.CompactTextString(m) }
+func (*AttachJobResponse) ProtoMessage()    {}
+func (*AttachJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{34}
+}
+
+func (m *AttachJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttachJobResponse.Unmarshal(m, b)
+}
+func (m *AttachJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttachJobResponse.Marshal(b, m, deterministic)
+}
+func (m *AttachJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttachJobResponse.Merge(m, src)
+}
+func (m *AttachJobResponse) XXX_Size() int {
+	return xxx_messageInfo_AttachJobResponse.Size(m)
+}
+func (m *AttachJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttachJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttachJobResponse proto.InternalMessageInfo
+
+type isAttachJobResponse_Content interface {
+	isAttachJobResponse_Content()
+}
+
+type AttachJobResponse_Stdout struct {
+	Stdout []byte `protobuf:"bytes,1,opt,name=stdout,proto3,oneof"`
+}
+
+type AttachJobResponse_Stderr struct {
+	Stderr []byte `protobuf:"bytes,2,opt,name=stderr,proto3,oneof"`
+}
+
+type AttachJobResponse_ExitCode struct {
+	ExitCode int32 `protobuf:"varint,3,opt,name=exit_code,json=exitCode,proto3,oneof"`
+}
+
+func (*AttachJobResponse_Stdout) isAttachJobResponse_Content() {}
+
+func (*AttachJobResponse_Stderr) isAttachJobResponse_Content() {}
+
+func (*AttachJobResponse_ExitCode) isAttachJobResponse_Content() {}
+
+func (m *AttachJobResponse) GetContent() isAttachJobResponse_Content {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+func (m *AttachJobResponse) GetStdout() []byte {
+	if x, ok := m.GetContent().(*AttachJobResponse_Stdout); ok {
+		return x.Stdout
+	}
+	return nil
+}
+
+func (m *AttachJobResponse) GetStderr() []byte {
+	if x, ok := m.GetContent().(*AttachJobResponse_Stderr); ok {
+		return x.Stderr
+	}
+	return nil
+}
+
+func (m *AttachJobResponse) GetExitCode() int32 {
+	if x, ok := m.GetContent().(*AttachJobResponse_ExitCode); ok {
+		return x.ExitCode
+	}
+	return 0
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*AttachJobResponse) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*AttachJobResponse_Stdout)(nil),
+		(*AttachJobResponse_Stderr)(nil),
+		(*AttachJobResponse_ExitCode)(nil),
+	}
+}
+
+type DeadLetter struct {
+	Id        string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	EventType string               `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Payload   []byte               `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	Error     string               `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	Received  *timestamp.Timestamp `protobuf:"bytes,5,opt,name=received,proto3" json:"received,omitempty"`
+	Attempts  int32                `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// failed_jobs are the paths of the jobs the event failed to start. A replay starts only those.
+	// If it's empty, the whole event failed.
+	FailedJobs           []string `protobuf:"bytes,7,rep,name=failed_jobs,json=failedJobs,proto3" json:"failed_jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeadLetter) Reset()         { *m = DeadLetter{} }
+func (m *DeadLetter) String() string { return proto.CompactTextString(m) }
+func (*DeadLetter) ProtoMessage()    {}
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{35}
+}
+
+func (m *DeadLetter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeadLetter.Unmarshal(m, b)
+}
+func (m *DeadLetter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeadLetter.Marshal(b, m, deterministic)
+}
+func (m *DeadLetter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeadLetter.Merge(m, src)
+}
+func (m *DeadLetter) XXX_Size() int {
+	return xxx_messageInfo_DeadLetter.Size(m)
+}
+func (m *DeadLetter) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeadLetter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeadLetter proto.InternalMessageInfo
+
+func (m *DeadLetter) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *DeadLetter) GetEventType() string {
+	if m != nil {
+		return m.EventType
+	}
+	return ""
+}
+
+func (m *DeadLetter) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *DeadLetter) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *DeadLetter) GetReceived() *timestamp.Timestamp {
+	if m != nil {
+		return m.Received
+	}
+	return nil
+}
+
+func (m *DeadLetter) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *DeadLetter) GetFailedJobs() []string {
+	if m != nil {
+		return m.FailedJobs
+	}
+	return nil
+}
+
+type ListDeadLettersRequest struct {
+	// token is one of the admin tokens configured for werft
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListDeadLettersRequest) Reset()         { *m = ListDeadLettersRequest{} }
+func (m *ListDeadLettersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListDeadLettersRequest) ProtoMessage()    {}
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{36}
+}
+
+func (m *ListDeadLettersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeadLettersRequest.Unmarshal(m, b)
+}
+func (m *ListDeadLettersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeadLettersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListDeadLettersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeadLettersRequest.Merge(m, src)
+}
+func (m *ListDeadLettersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListDeadLettersRequest.Size(m)
+}
+func (m *ListDeadLettersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeadLettersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeadLettersRequest proto.InternalMessageInfo
+
+func (m *ListDeadLettersRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ListDeadLettersResponse struct {
+	Result               []*DeadLetter `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *ListDeadLettersResponse) Reset()         { *m = ListDeadLettersResponse{} }
+func (m *ListDeadLettersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListDeadLettersResponse) ProtoMessage()    {}
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{37}
+}
+
+func (m *ListDeadLettersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListDeadLettersResponse.Unmarshal(m, b)
+}
+func (m *ListDeadLettersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListDeadLettersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListDeadLettersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListDeadLettersResponse.Merge(m, src)
+}
+func (m *ListDeadLettersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListDeadLettersResponse.Size(m)
+}
+func (m *ListDeadLettersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListDeadLettersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListDeadLettersResponse proto.InternalMessageInfo
+
+func (m *ListDeadLettersResponse) GetResult() []*DeadLetter {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type ReplayDeadLetterRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// token is one of the admin tokens configured for werft
+	Token                string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplayDeadLetterRequest) Reset()         { *m = ReplayDeadLetterRequest{} }
+func (m *ReplayDeadLetterRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplayDeadLetterRequest) ProtoMessage()    {}
+func (*ReplayDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{38}
+}
+
+func (m *ReplayDeadLetterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplayDeadLetterRequest.Unmarshal(m, b)
+}
+func (m *ReplayDeadLetterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplayDeadLetterRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplayDeadLetterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplayDeadLetterRequest.Merge(m, src)
+}
+func (m *ReplayDeadLetterRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplayDeadLetterRequest.Size(m)
+}
+func (m *ReplayDeadLetterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplayDeadLetterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplayDeadLetterRequest proto.InternalMessageInfo
+
+func (m *ReplayDeadLetterRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReplayDeadLetterRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ReplayDeadLetterResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplayDeadLetterResponse) Reset()         { *m = ReplayDeadLetterResponse{} }
+func (m *ReplayDeadLetterResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplayDeadLetterResponse) ProtoMessage()    {}
+func (*ReplayDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{39}
+}
+
+func (m *ReplayDeadLetterResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplayDeadLetterResponse.Unmarshal(m, b)
+}
+func (m *ReplayDeadLetterResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplayDeadLetterResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplayDeadLetterResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplayDeadLetterResponse.Merge(m, src)
+}
+func (m *ReplayDeadLetterResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplayDeadLetterResponse.Size(m)
+}
+func (m *ReplayDeadLetterResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplayDeadLetterResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplayDeadLetterResponse proto.InternalMessageInfo
+
+type GetQueueStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetQueueStatusRequest) Reset()         { *m = GetQueueStatusRequest{} }
+func (m *GetQueueStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetQueueStatusRequest) ProtoMessage()    {}
+func (*GetQueueStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{40}
+}
+
+func (m *GetQueueStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQueueStatusRequest.Unmarshal(m, b)
+}
+func (m *GetQueueStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQueueStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetQueueStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQueueStatusRequest.Merge(m, src)
+}
+func (m *GetQueueStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetQueueStatusRequest.Size(m)
+}
+func (m *GetQueueStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQueueStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQueueStatusRequest proto.InternalMessageInfo
+
+type GetQueueStatusResponse struct {
+	Jobs                 []*QueuedJob `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *GetQueueStatusResponse) Reset()         { *m = GetQueueStatusResponse{} }
+func (m *GetQueueStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetQueueStatusResponse) ProtoMessage()    {}
+func (*GetQueueStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{41}
+}
+
+func (m *GetQueueStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQueueStatusResponse.Unmarshal(m, b)
+}
+func (m *GetQueueStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQueueStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *GetQueueStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQueueStatusResponse.Merge(m, src)
+}
+func (m *GetQueueStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetQueueStatusResponse.Size(m)
+}
+func (m *GetQueueStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQueueStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQueueStatusResponse proto.InternalMessageInfo
+
+func (m *GetQueueStatusResponse) GetJobs() []*QueuedJob {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+type QueuedJob struct {
+	Job *JobStatus `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	// position is the place of the job in the queue, starting at 1
+	Position int32      `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	Reason   WaitReason `protobuf:"varint,3,opt,name=reason,proto3,enum=v1.WaitReason" json:"reason,omitempty"`
+	// details describes the reason in a human readable way
+	Details string `protobuf:"bytes,4,opt,name=details,proto3" json:"details,omitempty"`
+	// since is the time the job started waiting
+	Since *timestamp.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	// until is the time the job is expected to start at, if known
+	Until                *timestamp.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *QueuedJob) Reset()         { *m = QueuedJob{} }
+func (m *QueuedJob) String() string { return proto.CompactTextString(m) }
+func (*QueuedJob) ProtoMessage()    {}
+func (*QueuedJob) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{42}
+}
+
+func (m *QueuedJob) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueuedJob.Unmarshal(m, b)
+}
+func (m *QueuedJob) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueuedJob.Marshal(b, m, deterministic)
+}
+func (m *QueuedJob) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueuedJob.Merge(m, src)
+}
+func (m *QueuedJob) XXX_Size() int {
+	return xxx_messageInfo_QueuedJob.Size(m)
+}
+func (m *QueuedJob) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueuedJob.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueuedJob proto.InternalMessageInfo
+
+func (m *QueuedJob) GetJob() *JobStatus {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+func (m *QueuedJob) GetPosition() int32 {
+	if m != nil {
+		return m.Position
+	}
+	return 0
+}
+
+func (m *QueuedJob) GetReason() WaitReason {
+	if m != nil {
+		return m.Reason
+	}
+	return WaitReason_WAIT_UNKNOWN
+}
+
+func (m *QueuedJob) GetDetails() string {
+	if m != nil {
+		return m.Details
+	}
+	return ""
+}
+
+func (m *QueuedJob) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *QueuedJob) GetUntil() *timestamp.Timestamp {
+	if m != nil {
+		return m.Until
+	}
+	return nil
+}
+
+type SetMaintenanceModeRequest struct {
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// reason tells users why job processing is paused
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// token is one of the admin tokens configured for werft
+	Token                string   `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetMaintenanceModeRequest) Reset()         { *m = SetMaintenanceModeRequest{} }
+func (m *SetMaintenanceModeRequest) String() string { return proto.CompactTextString(m) }
+func (*SetMaintenanceModeRequest) ProtoMessage()    {}
+func (*SetMaintenanceModeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{43}
+}
+
+func (m *SetMaintenanceModeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMaintenanceModeRequest.Unmarshal(m, b)
+}
+func (m *SetMaintenanceModeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMaintenanceModeRequest.Marshal(b, m, deterministic)
+}
+func (m *SetMaintenanceModeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMaintenanceModeRequest.Merge(m, src)
+}
+func (m *SetMaintenanceModeRequest) XXX_Size() int {
+	return xxx_messageInfo_SetMaintenanceModeRequest.Size(m)
+}
+func (m *SetMaintenanceModeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMaintenanceModeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMaintenanceModeRequest proto.InternalMessageInfo
+
+func (m *SetMaintenanceModeRequest) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *SetMaintenanceModeRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *SetMaintenanceModeRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type SetMaintenanceModeResponse struct {
+	Mode                 *MaintenanceMode `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SetMaintenanceModeResponse) Reset()         { *m = SetMaintenanceModeResponse{} }
+func (m *SetMaintenanceModeResponse) String() string { return proto.CompactTextString(m) }
+func (*SetMaintenanceModeResponse) ProtoMessage()    {}
+func (*SetMaintenanceModeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{44}
+}
+
+func (m *SetMaintenanceModeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetMaintenanceModeResponse.Unmarshal(m, b)
+}
+func (m *SetMaintenanceModeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetMaintenanceModeResponse.Marshal(b, m, deterministic)
+}
+func (m *SetMaintenanceModeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetMaintenanceModeResponse.Merge(m, src)
+}
+func (m *SetMaintenanceModeResponse) XXX_Size() int {
+	return xxx_messageInfo_SetMaintenanceModeResponse.Size(m)
+}
+func (m *SetMaintenanceModeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetMaintenanceModeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetMaintenanceModeResponse proto.InternalMessageInfo
+
+func (m *SetMaintenanceModeResponse) GetMode() *MaintenanceMode {
+	if m != nil {
+		return m.Mode
+	}
+	return nil
+}
+
+type GetMaintenanceModeRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMaintenanceModeRequest) Reset()         { *m = GetMaintenanceModeRequest{} }
+func (m *GetMaintenanceModeRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMaintenanceModeRequest) ProtoMessage()    {}
+func (*GetMaintenanceModeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{45}
+}
+
+func (m *GetMaintenanceModeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMaintenanceModeRequest.Unmarshal(m, b)
+}
+func (m *GetMaintenanceModeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMaintenanceModeRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMaintenanceModeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMaintenanceModeRequest.Merge(m, src)
+}
+func (m *GetMaintenanceModeRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMaintenanceModeRequest.Size(m)
+}
+func (m *GetMaintenanceModeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMaintenanceModeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMaintenanceModeRequest proto.InternalMessageInfo
+
+type GetMaintenanceModeResponse struct {
+	Mode                 *MaintenanceMode `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetMaintenanceModeResponse) Reset()         { *m = GetMaintenanceModeResponse{} }
+func (m *GetMaintenanceModeResponse) String() string { return proto.CompactTextString(m) }
+func (*GetMaintenanceModeResponse) ProtoMessage()    {}
+func (*GetMaintenanceModeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{46}
+}
+
+func (m *GetMaintenanceModeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMaintenanceModeResponse.Unmarshal(m, b)
+}
+func (m *GetMaintenanceModeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMaintenanceModeResponse.Marshal(b, m, deterministic)
+}
+func (m *GetMaintenanceModeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMaintenanceModeResponse.Merge(m, src)
+}
+func (m *GetMaintenanceModeResponse) XXX_Size() int {
+	return xxx_messageInfo_GetMaintenanceModeResponse.Size(m)
+}
+func (m *GetMaintenanceModeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMaintenanceModeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMaintenanceModeResponse proto.InternalMessageInfo
+
+func (m *GetMaintenanceModeResponse) GetMode() *MaintenanceMode {
+	if m != nil {
+		return m.Mode
+	}
+	return nil
+}
+
+type MaintenanceMode struct {
+	Enabled              bool                 `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	Reason               string               `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Since                *timestamp.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *MaintenanceMode) Reset()         { *m = MaintenanceMode{} }
+func (m *MaintenanceMode) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceMode) ProtoMessage()    {}
+func (*MaintenanceMode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{47}
+}
+
+func (m *MaintenanceMode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceMode.Unmarshal(m, b)
+}
+func (m *MaintenanceMode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceMode.Marshal(b, m, deterministic)
+}
+func (m *MaintenanceMode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceMode.Merge(m, src)
+}
+func (m *MaintenanceMode) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceMode.Size(m)
+}
+func (m *MaintenanceMode) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceMode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceMode proto.InternalMessageInfo
+
+func (m *MaintenanceMode) GetEnabled() bool {
+	if m != nil {
+		return m.Enabled
+	}
+	return false
+}
+
+func (m *MaintenanceMode) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *MaintenanceMode) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+type GetFlakyJobsRequest struct {
+	// repository selects the jobs to analyse by owner and repo, and optionally by host and ref
+	Repository *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// limit is the number of most recent jobs to analyse. Defaults to 500.
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetFlakyJobsRequest) Reset()         { *m = GetFlakyJobsRequest{} }
+func (m *GetFlakyJobsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetFlakyJobsRequest) ProtoMessage()    {}
+func (*GetFlakyJobsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{48}
+}
+
+func (m *GetFlakyJobsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFlakyJobsRequest.Unmarshal(m, b)
+}
+func (m *GetFlakyJobsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFlakyJobsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetFlakyJobsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFlakyJobsRequest.Merge(m, src)
+}
+func (m *GetFlakyJobsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetFlakyJobsRequest.Size(m)
+}
+func (m *GetFlakyJobsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFlakyJobsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFlakyJobsRequest proto.InternalMessageInfo
+
+func (m *GetFlakyJobsRequest) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *GetFlakyJobsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type GetFlakyJobsResponse struct {
+	Result               []*FlakyJob `protobuf:"bytes,1,rep,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GetFlakyJobsResponse) Reset()         { *m = GetFlakyJobsResponse{} }
+func (m *GetFlakyJobsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetFlakyJobsResponse) ProtoMessage()    {}
+func (*GetFlakyJobsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{49}
+}
+
+func (m *GetFlakyJobsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetFlakyJobsResponse.Unmarshal(m, b)
+}
+func (m *GetFlakyJobsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetFlakyJobsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetFlakyJobsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetFlakyJobsResponse.Merge(m, src)
+}
+func (m *GetFlakyJobsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetFlakyJobsResponse.Size(m)
+}
+func (m *GetFlakyJobsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetFlakyJobsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetFlakyJobsResponse proto.InternalMessageInfo
+
+func (m *GetFlakyJobsResponse) GetResult() []*FlakyJob {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type FlakyJob struct {
+	// name is the name of the job without its number, e.g. werft-build-master
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// score is the share of consecutive runs whose outcome differs, from 0 (never flips) to 1 (flips every time)
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// runs is the number of runs analysed
+	Runs int32 `protobuf:"varint,3,opt,name=runs,proto3" json:"runs,omitempty"`
+	// flips is the number of consecutive runs whose outcome differs
+	Flips int32 `protobuf:"varint,4,opt,name=flips,proto3" json:"flips,omitempty"`
+	// flaky_revisions lists the revisions which both failed and succeeded
+	FlakyRevisions []string `protobuf:"bytes,5,rep,name=flaky_revisions,json=flakyRevisions,proto3" json:"flaky_revisions,omitempty"`
+	// last_failure is the name of the most recent failed run
+	LastFailure string `protobuf:"bytes,6,opt,name=last_failure,json=lastFailure,proto3" json:"last_failure,omitempty"`
+	// muted is the number of failed runs which were muted and rerun because the job is known to be flaky
+	Muted                int32    `protobuf:"varint,7,opt,name=muted,proto3" json:"muted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FlakyJob) Reset()         { *m = FlakyJob{} }
+func (m *FlakyJob) String() string { return proto.CompactTextString(m) }
+func (*FlakyJob) ProtoMessage()    {}
+func (*FlakyJob) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{50}
+}
+
+func (m *FlakyJob) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FlakyJob.Unmarshal(m, b)
+}
+func (m *FlakyJob) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FlakyJob.Marshal(b, m, deterministic)
+}
+func (m *FlakyJob) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FlakyJob.Merge(m, src)
+}
+func (m *FlakyJob) XXX_Size() int {
+	return xxx_messageInfo_FlakyJob.Size(m)
+}
+func (m *FlakyJob) XXX_DiscardUnknown() {
+	xxx_messageInfo_FlakyJob.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FlakyJob proto.InternalMessageInfo
+
+func (m *FlakyJob) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FlakyJob) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *FlakyJob) GetRuns() int32 {
+	if m != nil {
+		return m.Runs
+	}
+	return 0
+}
+
+func (m *FlakyJob) GetFlips() int32 {
+	if m != nil {
+		return m.Flips
+	}
+	return 0
+}
+
+func (m *FlakyJob) GetFlakyRevisions() []string {
+	if m != nil {
+		return m.FlakyRevisions
+	}
+	return nil
+}
+
+func (m *FlakyJob) GetLastFailure() string {
+	if m != nil {
+		return m.LastFailure
+	}
+	return ""
+}
+
+func (m *FlakyJob) GetMuted() int32 {
+	if m != nil {
+		return m.Muted
+	}
+	return 0
+}
+
+type ExportJobsRequest struct {
+	// token authorizes the export and must be one of the export tokens configured for werft
+	Token  string              `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Filter []*FilterExpression `protobuf:"bytes,2,rep,name=filter,proto3" json:"filter,omitempty"`
+	// from and to limit the export to jobs created within this time range. Both are optional.
+	From                 *timestamp.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To                   *timestamp.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Format               ExportFormat         `protobuf:"varint,5,opt,name=format,proto3,enum=v1.ExportFormat" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ExportJobsRequest) Reset()         { *m = ExportJobsRequest{} }
+func (m *ExportJobsRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportJobsRequest) ProtoMessage()    {}
+func (*ExportJobsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{51}
+}
+
+func (m *ExportJobsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportJobsRequest.Unmarshal(m, b)
+}
+func (m *ExportJobsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportJobsRequest.Marshal(b, m, deterministic)
+}
+func (m *ExportJobsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportJobsRequest.Merge(m, src)
+}
+func (m *ExportJobsRequest) XXX_Size() int {
+	return xxx_messageInfo_ExportJobsRequest.Size(m)
+}
+func (m *ExportJobsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportJobsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportJobsRequest proto.InternalMessageInfo
+
+func (m *ExportJobsRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *ExportJobsRequest) GetFilter() []*FilterExpression {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *ExportJobsRequest) GetFrom() *timestamp.Timestamp {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *ExportJobsRequest) GetTo() *timestamp.Timestamp {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *ExportJobsRequest) GetFormat() ExportFormat {
+	if m != nil {
+		return m.Format
+	}
+	return ExportFormat_EXPORT_JSONL
+}
+
+type ExportJobsResponse struct {
+	// data is the next chunk of the export
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExportJobsResponse) Reset()         { *m = ExportJobsResponse{} }
+func (m *ExportJobsResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportJobsResponse) ProtoMessage()    {}
+func (*ExportJobsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{52}
+}
+
+func (m *ExportJobsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExportJobsResponse.Unmarshal(m, b)
+}
+func (m *ExportJobsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExportJobsResponse.Marshal(b, m, deterministic)
+}
+func (m *ExportJobsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExportJobsResponse.Merge(m, src)
+}
+func (m *ExportJobsResponse) XXX_Size() int {
+	return xxx_messageInfo_ExportJobsResponse.Size(m)
+}
+func (m *ExportJobsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExportJobsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExportJobsResponse proto.InternalMessageInfo
+
+func (m *ExportJobsResponse) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type DiffJobSpecsRequest struct {
+	// from and to name the jobs whose specs are compared
+	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffJobSpecsRequest) Reset()         { *m = DiffJobSpecsRequest{} }
+func (m *DiffJobSpecsRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffJobSpecsRequest) ProtoMessage()    {}
+func (*DiffJobSpecsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{53}
+}
+
+func (m *DiffJobSpecsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffJobSpecsRequest.Unmarshal(m, b)
+}
+func (m *DiffJobSpecsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffJobSpecsRequest.Marshal(b, m, deterministic)
+}
+func (m *DiffJobSpecsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffJobSpecsRequest.Merge(m, src)
+}
+func (m *DiffJobSpecsRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffJobSpecsRequest.Size(m)
+}
+func (m *DiffJobSpecsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffJobSpecsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffJobSpecsRequest proto.InternalMessageInfo
+
+func (m *DiffJobSpecsRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *DiffJobSpecsRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type DiffJobSpecsResponse struct {
+	FromHash string `protobuf:"bytes,1,opt,name=from_hash,json=fromHash,proto3" json:"from_hash,omitempty"`
+	ToHash   string `protobuf:"bytes,2,opt,name=to_hash,json=toHash,proto3" json:"to_hash,omitempty"`
+	// diff is a unified diff from the spec of the from job to the spec of the to job. It's empty if both specs are the same.
+	Diff                 string   `protobuf:"bytes,3,opt,name=diff,proto3" json:"diff,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffJobSpecsResponse) Reset()         { *m = DiffJobSpecsResponse{} }
+func (m *DiffJobSpecsResponse) String() string { return proto.CompactTextString(m) }
+func (*DiffJobSpecsResponse) ProtoMessage()    {}
+func (*DiffJobSpecsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{54}
+}
+
+func (m *DiffJobSpecsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffJobSpecsResponse.Unmarshal(m, b)
+}
+func (m *DiffJobSpecsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffJobSpecsResponse.Marshal(b, m, deterministic)
+}
+func (m *DiffJobSpecsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffJobSpecsResponse.Merge(m, src)
+}
+func (m *DiffJobSpecsResponse) XXX_Size() int {
+	return xxx_messageInfo_DiffJobSpecsResponse.Size(m)
+}
+func (m *DiffJobSpecsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffJobSpecsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffJobSpecsResponse proto.InternalMessageInfo
+
+func (m *DiffJobSpecsResponse) GetFromHash() string {
+	if m != nil {
+		return m.FromHash
+	}
+	return ""
+}
+
+func (m *DiffJobSpecsResponse) GetToHash() string {
+	if m != nil {
+		return m.ToHash
+	}
+	return ""
+}
+
+func (m *DiffJobSpecsResponse) GetDiff() string {
+	if m != nil {
+		return m.Diff
+	}
+	return ""
+}
+
+type GetJobProvenanceRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobProvenanceRequest) Reset()         { *m = GetJobProvenanceRequest{} }
+func (m *GetJobProvenanceRequest) String() string { return proto.CompactTextString(m) }
+func (*GetJobProvenanceRequest) ProtoMessage()    {}
+func (*GetJobProvenanceRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{55}
+}
+
+func (m *GetJobProvenanceRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobProvenanceRequest.Unmarshal(m, b)
+}
+func (m *GetJobProvenanceRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobProvenanceRequest.Marshal(b, m, deterministic)
+}
+func (m *GetJobProvenanceRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobProvenanceRequest.Merge(m, src)
+}
+func (m *GetJobProvenanceRequest) XXX_Size() int {
+	return xxx_messageInfo_GetJobProvenanceRequest.Size(m)
+}
+func (m *GetJobProvenanceRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobProvenanceRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobProvenanceRequest proto.InternalMessageInfo
+
+func (m *GetJobProvenanceRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetJobProvenanceResponse struct {
+	// envelope is a DSSE envelope (JSON) holding an in-toto statement with SLSA provenance, signed by werft
+	Envelope             []byte   `protobuf:"bytes,1,opt,name=envelope,proto3" json:"envelope,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobProvenanceResponse) Reset()         { *m = GetJobProvenanceResponse{} }
+func (m *GetJobProvenanceResponse) String() string { return proto.CompactTextString(m) }
+func (*GetJobProvenanceResponse) ProtoMessage()    {}
+func (*GetJobProvenanceResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{56}
+}
+
+func (m *GetJobProvenanceResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobProvenanceResponse.Unmarshal(m, b)
+}
+func (m *GetJobProvenanceResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobProvenanceResponse.Marshal(b, m, deterministic)
+}
+func (m *GetJobProvenanceResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobProvenanceResponse.Merge(m, src)
+}
+func (m *GetJobProvenanceResponse) XXX_Size() int {
+	return xxx_messageInfo_GetJobProvenanceResponse.Size(m)
+}
+func (m *GetJobProvenanceResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobProvenanceResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobProvenanceResponse proto.InternalMessageInfo
+
+func (m *GetJobProvenanceResponse) GetEnvelope() []byte {
+	if m != nil {
+		return m.Envelope
+	}
+	return nil
+}
+
+type ImageBuild struct {
+	// digest identifies the image, e.g. sha256:4a1c...
+	Digest string `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
+	// image is the name the job pushed the image as, e.g. eu.gcr.io/werft/werft:master
+	Image string `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	// job names the job which built the image
+	Job string `protobuf:"bytes,3,opt,name=job,proto3" json:"job,omitempty"`
+	// repository is the repository and revision the job built
+	Repository           *Repository          `protobuf:"bytes,4,opt,name=repository,proto3" json:"repository,omitempty"`
+	Built                *timestamp.Timestamp `protobuf:"bytes,5,opt,name=built,proto3" json:"built,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ImageBuild) Reset()         { *m = ImageBuild{} }
+func (m *ImageBuild) String() string { return proto.CompactTextString(m) }
+func (*ImageBuild) ProtoMessage()    {}
+func (*ImageBuild) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{57}
+}
+
+func (m *ImageBuild) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImageBuild.Unmarshal(m, b)
+}
+func (m *ImageBuild) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImageBuild.Marshal(b, m, deterministic)
+}
+func (m *ImageBuild) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImageBuild.Merge(m, src)
+}
+func (m *ImageBuild) XXX_Size() int {
+	return xxx_messageInfo_ImageBuild.Size(m)
+}
+func (m *ImageBuild) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImageBuild.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImageBuild proto.InternalMessageInfo
+
+func (m *ImageBuild) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *ImageBuild) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *ImageBuild) GetJob() string {
+	if m != nil {
+		return m.Job
+	}
+	return ""
+}
+
+func (m *ImageBuild) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *ImageBuild) GetBuilt() *timestamp.Timestamp {
+	if m != nil {
+		return m.Built
+	}
+	return nil
+}
+
+type FindImageBuildsRequest struct {
+	Digest               string   `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindImageBuildsRequest) Reset()         { *m = FindImageBuildsRequest{} }
+func (m *FindImageBuildsRequest) String() string { return proto.CompactTextString(m) }
+func (*FindImageBuildsRequest) ProtoMessage()    {}
+func (*FindImageBuildsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{58}
+}
+
+func (m *FindImageBuildsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindImageBuildsRequest.Unmarshal(m, b)
+}
+func (m *FindImageBuildsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindImageBuildsRequest.Marshal(b, m, deterministic)
+}
+func (m *FindImageBuildsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindImageBuildsRequest.Merge(m, src)
+}
+func (m *FindImageBuildsRequest) XXX_Size() int {
+	return xxx_messageInfo_FindImageBuildsRequest.Size(m)
+}
+func (m *FindImageBuildsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindImageBuildsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindImageBuildsRequest proto.InternalMessageInfo
+
+func (m *FindImageBuildsRequest) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+type FindImageBuildsResponse struct {
+	Builds               []*ImageBuild `protobuf:"bytes,1,rep,name=builds,proto3" json:"builds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *FindImageBuildsResponse) Reset()         { *m = FindImageBuildsResponse{} }
+func (m *FindImageBuildsResponse) String() string { return proto.CompactTextString(m) }
+func (*FindImageBuildsResponse) ProtoMessage()    {}
+func (*FindImageBuildsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{59}
+}
+
+func (m *FindImageBuildsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindImageBuildsResponse.Unmarshal(m, b)
+}
+func (m *FindImageBuildsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindImageBuildsResponse.Marshal(b, m, deterministic)
+}
+func (m *FindImageBuildsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindImageBuildsResponse.Merge(m, src)
+}
+func (m *FindImageBuildsResponse) XXX_Size() int {
+	return xxx_messageInfo_FindImageBuildsResponse.Size(m)
+}
+func (m *FindImageBuildsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindImageBuildsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindImageBuildsResponse proto.InternalMessageInfo
+
+func (m *FindImageBuildsResponse) GetBuilds() []*ImageBuild {
+	if m != nil {
+		return m.Builds
+	}
+	return nil
+}
+
+type GetVersionRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetVersionRequest) Reset()         { *m = GetVersionRequest{} }
+func (m *GetVersionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetVersionRequest) ProtoMessage()    {}
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{60}
+}
+
+func (m *GetVersionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetVersionRequest.Unmarshal(m, b)
+}
+func (m *GetVersionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetVersionRequest.Marshal(b, m, deterministic)
+}
+func (m *GetVersionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetVersionRequest.Merge(m, src)
+}
+func (m *GetVersionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetVersionRequest.Size(m)
+}
+func (m *GetVersionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetVersionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetVersionRequest proto.InternalMessageInfo
+
+type GetVersionResponse struct {
+	// version is the release of werft the server runs, e.g. v0.3.0. It's "unknown" for development builds.
+	Version   string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Commit    string `protobuf:"bytes,2,opt,name=commit,proto3" json:"commit,omitempty"`
+	BuildDate string `protobuf:"bytes,3,opt,name=build_date,json=buildDate,proto3" json:"build_date,omitempty"`
+	// api_version is the version of the API the server speaks. Clients must speak the same version.
+	ApiVersion           string   `protobuf:"bytes,4,opt,name=api_version,json=apiVersion,proto3" json:"api_version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetVersionResponse) Reset()         { *m = GetVersionResponse{} }
+func (m *GetVersionResponse) String() string { return proto.CompactTextString(m) }
+func (*GetVersionResponse) ProtoMessage()    {}
+func (*GetVersionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{61}
+}
+
+func (m *GetVersionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetVersionResponse.Unmarshal(m, b)
+}
+func (m *GetVersionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetVersionResponse.Marshal(b, m, deterministic)
+}
+func (m *GetVersionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetVersionResponse.Merge(m, src)
+}
+func (m *GetVersionResponse) XXX_Size() int {
+	return xxx_messageInfo_GetVersionResponse.Size(m)
+}
+func (m *GetVersionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetVersionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetVersionResponse proto.InternalMessageInfo
+
+func (m *GetVersionResponse) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *GetVersionResponse) GetCommit() string {
+	if m != nil {
+		return m.Commit
+	}
+	return ""
+}
+
+func (m *GetVersionResponse) GetBuildDate() string {
+	if m != nil {
+		return m.BuildDate
+	}
+	return ""
+}
+
+func (m *GetVersionResponse) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+type GetJobEventsRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobEventsRequest) Reset()         { *m = GetJobEventsRequest{} }
+func (m *GetJobEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetJobEventsRequest) ProtoMessage()    {}
+func (*GetJobEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{62}
+}
+
+func (m *GetJobEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobEventsRequest.Unmarshal(m, b)
+}
+func (m *GetJobEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetJobEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobEventsRequest.Merge(m, src)
+}
+func (m *GetJobEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetJobEventsRequest.Size(m)
+}
+func (m *GetJobEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobEventsRequest proto.InternalMessageInfo
+
+func (m *GetJobEventsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetJobEventsResponse struct {
+	Events               []*JobEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GetJobEventsResponse) Reset()         { *m = GetJobEventsResponse{} }
+func (m *GetJobEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetJobEventsResponse) ProtoMessage()    {}
+func (*GetJobEventsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{63}
+}
+
+func (m *GetJobEventsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobEventsResponse.Unmarshal(m, b)
+}
+func (m *GetJobEventsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobEventsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetJobEventsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobEventsResponse.Merge(m, src)
+}
+func (m *GetJobEventsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetJobEventsResponse.Size(m)
+}
+func (m *GetJobEventsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobEventsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobEventsResponse proto.InternalMessageInfo
+
+func (m *GetJobEventsResponse) GetEvents() []*JobEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type Project struct {
+	Name        string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// repos are the patterns identifying the repositories of the project, e.g. github.com/32leaves/*
+	Repos                []string `protobuf:"bytes,3,rep,name=repos,proto3" json:"repos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Project) Reset()         { *m = Project{} }
+func (m *Project) String() string { return proto.CompactTextString(m) }
+func (*Project) ProtoMessage()    {}
+func (*Project) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{64}
+}
+
+func (m *Project) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Project.Unmarshal(m, b)
+}
+func (m *Project) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Project.Marshal(b, m, deterministic)
+}
+func (m *Project) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Project.Merge(m, src)
+}
+func (m *Project) XXX_Size() int {
+	return xxx_messageInfo_Project.Size(m)
+}
+func (m *Project) XXX_DiscardUnknown() {
+	xxx_messageInfo_Project.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Project proto.InternalMessageInfo
+
+func (m *Project) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Project) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Project) GetRepos() []string {
+	if m != nil {
+		return m.Repos
+	}
+	return nil
+}
+
+type ListProjectsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListProjectsRequest) Reset()         { *m = ListProjectsRequest{} }
+func (m *ListProjectsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListProjectsRequest) ProtoMessage()    {}
+func (*ListProjectsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{65}
+}
+
+func (m *ListProjectsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProjectsRequest.Unmarshal(m, b)
+}
+func (m *ListProjectsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProjectsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListProjectsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProjectsRequest.Merge(m, src)
+}
+func (m *ListProjectsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListProjectsRequest.Size(m)
+}
+func (m *ListProjectsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProjectsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProjectsRequest proto.InternalMessageInfo
+
+type ListProjectsResponse struct {
+	Projects             []*Project `protobuf:"bytes,1,rep,name=projects,proto3" json:"projects,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *ListProjectsResponse) Reset()         { *m = ListProjectsResponse{} }
+func (m *ListProjectsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListProjectsResponse) ProtoMessage()    {}
+func (*ListProjectsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{66}
+}
+
+func (m *ListProjectsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProjectsResponse.Unmarshal(m, b)
+}
+func (m *ListProjectsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProjectsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListProjectsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProjectsResponse.Merge(m, src)
+}
+func (m *ListProjectsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListProjectsResponse.Size(m)
+}
+func (m *ListProjectsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProjectsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProjectsResponse proto.InternalMessageInfo
+
+func (m *ListProjectsResponse) GetProjects() []*Project {
+	if m != nil {
+		return m.Projects
+	}
+	return nil
+}
+
+type GetProjectHealthRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// ref limits the health to jobs of a ref, e.g. refs/heads/master. If empty, jobs of all refs count.
+	Ref                  string   `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetProjectHealthRequest) Reset()         { *m = GetProjectHealthRequest{} }
+func (m *GetProjectHealthRequest) String() string { return proto.CompactTextString(m) }
+func (*GetProjectHealthRequest) ProtoMessage()    {}
+func (*GetProjectHealthRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{67}
+}
+
+func (m *GetProjectHealthRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProjectHealthRequest.Unmarshal(m, b)
+}
+func (m *GetProjectHealthRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProjectHealthRequest.Marshal(b, m, deterministic)
+}
+func (m *GetProjectHealthRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProjectHealthRequest.Merge(m, src)
+}
+func (m *GetProjectHealthRequest) XXX_Size() int {
+	return xxx_messageInfo_GetProjectHealthRequest.Size(m)
+}
+func (m *GetProjectHealthRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProjectHealthRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProjectHealthRequest proto.InternalMessageInfo
+
+func (m *GetProjectHealthRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetProjectHealthRequest) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+type RepositoryHealth struct {
+	Repository *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// latest is the most recent job of the repository, which might still be running
+	Latest *JobStatus `protobuf:"bytes,2,opt,name=latest,proto3" json:"latest,omitempty"`
+	// latest_finished is the most recent job of the repository which ran and is done. Its success is the health of the repository.
+	LatestFinished       *JobStatus `protobuf:"bytes,3,opt,name=latest_finished,json=latestFinished,proto3" json:"latest_finished,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *RepositoryHealth) Reset()         { *m = RepositoryHealth{} }
+func (m *RepositoryHealth) String() string { return proto.CompactTextString(m) }
+func (*RepositoryHealth) ProtoMessage()    {}
+func (*RepositoryHealth) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{68}
+}
+
+func (m *RepositoryHealth) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepositoryHealth.Unmarshal(m, b)
+}
+func (m *RepositoryHealth) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepositoryHealth.Marshal(b, m, deterministic)
+}
+func (m *RepositoryHealth) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepositoryHealth.Merge(m, src)
+}
+func (m *RepositoryHealth) XXX_Size() int {
+	return xxx_messageInfo_RepositoryHealth.Size(m)
+}
+func (m *RepositoryHealth) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepositoryHealth.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepositoryHealth proto.InternalMessageInfo
+
+func (m *RepositoryHealth) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *RepositoryHealth) GetLatest() *JobStatus {
+	if m != nil {
+		return m.Latest
+	}
+	return nil
+}
+
+func (m *RepositoryHealth) GetLatestFinished() *JobStatus {
+	if m != nil {
+		return m.LatestFinished
+	}
+	return nil
+}
+
+type GetProjectHealthResponse struct {
+	Repositories         []*RepositoryHealth `protobuf:"bytes,1,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *GetProjectHealthResponse) Reset()         { *m = GetProjectHealthResponse{} }
+func (m *GetProjectHealthResponse) String() string { return proto.CompactTextString(m) }
+func (*GetProjectHealthResponse) ProtoMessage()    {}
+func (*GetProjectHealthResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{69}
+}
+
+func (m *GetProjectHealthResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProjectHealthResponse.Unmarshal(m, b)
+}
+func (m *GetProjectHealthResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProjectHealthResponse.Marshal(b, m, deterministic)
+}
+func (m *GetProjectHealthResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProjectHealthResponse.Merge(m, src)
+}
+func (m *GetProjectHealthResponse) XXX_Size() int {
+	return xxx_messageInfo_GetProjectHealthResponse.Size(m)
+}
+func (m *GetProjectHealthResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProjectHealthResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProjectHealthResponse proto.InternalMessageInfo
+
+func (m *GetProjectHealthResponse) GetRepositories() []*RepositoryHealth {
+	if m != nil {
+		return m.Repositories
+	}
+	return nil
+}
+
+type GetQuotaUsageRequest struct {
+	// name limits the response to a single quota
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetQuotaUsageRequest) Reset()         { *m = GetQuotaUsageRequest{} }
+func (m *GetQuotaUsageRequest) String() string { return proto.CompactTextString(m) }
+func (*GetQuotaUsageRequest) ProtoMessage()    {}
+func (*GetQuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{70}
+}
+
+func (m *GetQuotaUsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQuotaUsageRequest.Unmarshal(m, b)
+}
+func (m *GetQuotaUsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQuotaUsageRequest.Marshal(b, m, deterministic)
+}
+func (m *GetQuotaUsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQuotaUsageRequest.Merge(m, src)
+}
+func (m *GetQuotaUsageRequest) XXX_Size() int {
+	return xxx_messageInfo_GetQuotaUsageRequest.Size(m)
+}
+func (m *GetQuotaUsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQuotaUsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQuotaUsageRequest proto.InternalMessageInfo
+
+func (m *GetQuotaUsageRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetQuotaUsageResponse struct {
+	Quotas               []*QuotaUsage `protobuf:"bytes,1,rep,name=quotas,proto3" json:"quotas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetQuotaUsageResponse) Reset()         { *m = GetQuotaUsageResponse{} }
+func (m *GetQuotaUsageResponse) String() string { return proto.CompactTextString(m) }
+func (*GetQuotaUsageResponse) ProtoMessage()    {}
+func (*GetQuotaUsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{71}
+}
+
+func (m *GetQuotaUsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQuotaUsageResponse.Unmarshal(m, b)
+}
+func (m *GetQuotaUsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQuotaUsageResponse.Marshal(b, m, deterministic)
+}
+func (m *GetQuotaUsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQuotaUsageResponse.Merge(m, src)
+}
+func (m *GetQuotaUsageResponse) XXX_Size() int {
+	return xxx_messageInfo_GetQuotaUsageResponse.Size(m)
+}
+func (m *GetQuotaUsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQuotaUsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQuotaUsageResponse proto.InternalMessageInfo
+
+func (m *GetQuotaUsageResponse) GetQuotas() []*QuotaUsage {
+	if m != nil {
+		return m.Quotas
+	}
+	return nil
+}
+
+type QuotaUsage struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// concurrent_jobs is the number of jobs counting towards the quota whose pods currently exist
+	ConcurrentJobs int32 `protobuf:"varint,2,opt,name=concurrent_jobs,json=concurrentJobs,proto3" json:"concurrent_jobs,omitempty"`
+	// max_concurrent_jobs is the limit of concurrent jobs, or 0 if there is none
+	MaxConcurrentJobs int32 `protobuf:"varint,3,opt,name=max_concurrent_jobs,json=maxConcurrentJobs,proto3" json:"max_concurrent_jobs,omitempty"`
+	// job_minutes is the time jobs counting towards the quota ran for within the current period
+	JobMinutes float64 `protobuf:"fixed64,4,opt,name=job_minutes,json=jobMinutes,proto3" json:"job_minutes,omitempty"`
+	// max_job_minutes is the limit of job minutes per period, or 0 if there is none
+	MaxJobMinutes int64 `protobuf:"varint,5,opt,name=max_job_minutes,json=maxJobMinutes,proto3" json:"max_job_minutes,omitempty"`
+	// period_seconds is the length of the period job minutes are counted over, which ends now
+	PeriodSeconds        int64    `protobuf:"varint,6,opt,name=period_seconds,json=periodSeconds,proto3" json:"period_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuotaUsage) Reset()         { *m = QuotaUsage{} }
+func (m *QuotaUsage) String() string { return proto.CompactTextString(m) }
+func (*QuotaUsage) ProtoMessage()    {}
+func (*QuotaUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{72}
+}
+
+func (m *QuotaUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaUsage.Unmarshal(m, b)
+}
+func (m *QuotaUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaUsage.Marshal(b, m, deterministic)
+}
+func (m *QuotaUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaUsage.Merge(m, src)
+}
+func (m *QuotaUsage) XXX_Size() int {
+	return xxx_messageInfo_QuotaUsage.Size(m)
+}
+func (m *QuotaUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaUsage proto.InternalMessageInfo
+
+func (m *QuotaUsage) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QuotaUsage) GetConcurrentJobs() int32 {
+	if m != nil {
+		return m.ConcurrentJobs
+	}
+	return 0
+}
+
+func (m *QuotaUsage) GetMaxConcurrentJobs() int32 {
+	if m != nil {
+		return m.MaxConcurrentJobs
+	}
+	return 0
+}
+
+func (m *QuotaUsage) GetJobMinutes() float64 {
+	if m != nil {
+		return m.JobMinutes
+	}
+	return 0
+}
+
+func (m *QuotaUsage) GetMaxJobMinutes() int64 {
+	if m != nil {
+		return m.MaxJobMinutes
+	}
+	return 0
+}
+
+func (m *QuotaUsage) GetPeriodSeconds() int64 {
+	if m != nil {
+		return m.PeriodSeconds
+	}
+	return 0
+}
+
+type GetStartLatencyRequest struct {
+	// repository limits the response to the jobs of a repository. Its ref and revision are ignored.
+	Repository *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// limit is the number of most recent jobs considered. Defaults to 100.
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStartLatencyRequest) Reset()         { *m = GetStartLatencyRequest{} }
+func (m *GetStartLatencyRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStartLatencyRequest) ProtoMessage()    {}
+func (*GetStartLatencyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{73}
+}
+
+func (m *GetStartLatencyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStartLatencyRequest.Unmarshal(m, b)
+}
+func (m *GetStartLatencyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStartLatencyRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStartLatencyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStartLatencyRequest.Merge(m, src)
+}
+func (m *GetStartLatencyRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStartLatencyRequest.Size(m)
+}
+func (m *GetStartLatencyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStartLatencyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStartLatencyRequest proto.InternalMessageInfo
+
+func (m *GetStartLatencyRequest) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *GetStartLatencyRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type GetStartLatencyResponse struct {
+	// repositories summarises the start latency of the jobs of each repository
+	Repositories []*RepositoryStartLatency `protobuf:"bytes,1,rep,name=repositories,proto3" json:"repositories,omitempty"`
+	// jobs lists the start latency of each job, most recent first
+	Jobs                 []*JobStartLatency `protobuf:"bytes,2,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *GetStartLatencyResponse) Reset()         { *m = GetStartLatencyResponse{} }
+func (m *GetStartLatencyResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStartLatencyResponse) ProtoMessage()    {}
+func (*GetStartLatencyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{74}
+}
+
+func (m *GetStartLatencyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStartLatencyResponse.Unmarshal(m, b)
+}
+func (m *GetStartLatencyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStartLatencyResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStartLatencyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStartLatencyResponse.Merge(m, src)
+}
+func (m *GetStartLatencyResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStartLatencyResponse.Size(m)
+}
+func (m *GetStartLatencyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStartLatencyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStartLatencyResponse proto.InternalMessageInfo
+
+func (m *GetStartLatencyResponse) GetRepositories() []*RepositoryStartLatency {
+	if m != nil {
+		return m.Repositories
+	}
+	return nil
+}
+
+func (m *GetStartLatencyResponse) GetJobs() []*JobStartLatency {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+type RepositoryStartLatency struct {
+	Repository           *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	Samples              int32       `protobuf:"varint,2,opt,name=samples,proto3" json:"samples,omitempty"`
+	P50Seconds           float64     `protobuf:"fixed64,3,opt,name=p50_seconds,json=p50Seconds,proto3" json:"p50_seconds,omitempty"`
+	P90Seconds           float64     `protobuf:"fixed64,4,opt,name=p90_seconds,json=p90Seconds,proto3" json:"p90_seconds,omitempty"`
+	P99Seconds           float64     `protobuf:"fixed64,5,opt,name=p99_seconds,json=p99Seconds,proto3" json:"p99_seconds,omitempty"`
+	MaxSeconds           float64     `protobuf:"fixed64,6,opt,name=max_seconds,json=maxSeconds,proto3" json:"max_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *RepositoryStartLatency) Reset()         { *m = RepositoryStartLatency{} }
+func (m *RepositoryStartLatency) String() string { return proto.CompactTextString(m) }
+func (*RepositoryStartLatency) ProtoMessage()    {}
+func (*RepositoryStartLatency) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{75}
+}
+
+func (m *RepositoryStartLatency) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RepositoryStartLatency.Unmarshal(m, b)
+}
+func (m *RepositoryStartLatency) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RepositoryStartLatency.Marshal(b, m, deterministic)
+}
+func (m *RepositoryStartLatency) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepositoryStartLatency.Merge(m, src)
+}
+func (m *RepositoryStartLatency) XXX_Size() int {
+	return xxx_messageInfo_RepositoryStartLatency.Size(m)
+}
+func (m *RepositoryStartLatency) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepositoryStartLatency.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepositoryStartLatency proto.InternalMessageInfo
+
+func (m *RepositoryStartLatency) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *RepositoryStartLatency) GetSamples() int32 {
+	if m != nil {
+		return m.Samples
+	}
+	return 0
+}
+
+func (m *RepositoryStartLatency) GetP50Seconds() float64 {
+	if m != nil {
+		return m.P50Seconds
+	}
+	return 0
+}
+
+func (m *RepositoryStartLatency) GetP90Seconds() float64 {
+	if m != nil {
+		return m.P90Seconds
+	}
+	return 0
+}
+
+func (m *RepositoryStartLatency) GetP99Seconds() float64 {
+	if m != nil {
+		return m.P99Seconds
+	}
+	return 0
+}
+
+func (m *RepositoryStartLatency) GetMaxSeconds() float64 {
+	if m != nil {
+		return m.MaxSeconds
+	}
+	return 0
+}
+
+type JobStartLatency struct {
+	Name       string      `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Repository *Repository `protobuf:"bytes,2,opt,name=repository,proto3" json:"repository,omitempty"`
+	// latency_seconds is the time from the webhook which started the job (or the job's creation if there was none,
+	// or its scheduled start time) until its pod ran
+	LatencySeconds       float64  `protobuf:"fixed64,3,opt,name=latency_seconds,json=latencySeconds,proto3" json:"latency_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobStartLatency) Reset()         { *m = JobStartLatency{} }
+func (m *JobStartLatency) String() string { return proto.CompactTextString(m) }
+func (*JobStartLatency) ProtoMessage()    {}
+func (*JobStartLatency) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{76}
+}
+
+func (m *JobStartLatency) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobStartLatency.Unmarshal(m, b)
+}
+func (m *JobStartLatency) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobStartLatency.Marshal(b, m, deterministic)
+}
+func (m *JobStartLatency) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobStartLatency.Merge(m, src)
+}
+func (m *JobStartLatency) XXX_Size() int {
+	return xxx_messageInfo_JobStartLatency.Size(m)
+}
+func (m *JobStartLatency) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobStartLatency.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobStartLatency proto.InternalMessageInfo
+
+func (m *JobStartLatency) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *JobStartLatency) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *JobStartLatency) GetLatencySeconds() float64 {
+	if m != nil {
+		return m.LatencySeconds
+	}
+	return 0
+}
+
+type DeleteJobRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// github_token identifies the user deleting the job
+	GithubToken          string   `protobuf:"bytes,2,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteJobRequest) Reset()         { *m = DeleteJobRequest{} }
+func (m *DeleteJobRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteJobRequest) ProtoMessage()    {}
+func (*DeleteJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{77}
+}
+
+func (m *DeleteJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteJobRequest.Unmarshal(m, b)
+}
+func (m *DeleteJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteJobRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteJobRequest.Merge(m, src)
+}
+func (m *DeleteJobRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteJobRequest.Size(m)
+}
+func (m *DeleteJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteJobRequest proto.InternalMessageInfo
+
+func (m *DeleteJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DeleteJobRequest) GetGithubToken() string {
+	if m != nil {
+		return m.GithubToken
+	}
+	return ""
+}
+
+type DeleteJobResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteJobResponse) Reset()         { *m = DeleteJobResponse{} }
+func (m *DeleteJobResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteJobResponse) ProtoMessage()    {}
+func (*DeleteJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{78}
+}
+
+func (m *DeleteJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteJobResponse.Unmarshal(m, b)
+}
+func (m *DeleteJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteJobResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteJobResponse.Merge(m, src)
+}
+func (m *DeleteJobResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteJobResponse.Size(m)
+}
+func (m *DeleteJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteJobResponse proto.InternalMessageInfo
+
+type PurgeJobRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// token authorizes purging the job and must be one of the admin tokens configured for werft
+	Token                string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeJobRequest) Reset()         { *m = PurgeJobRequest{} }
+func (m *PurgeJobRequest) String() string { return proto.CompactTextString(m) }
+func (*PurgeJobRequest) ProtoMessage()    {}
+func (*PurgeJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{79}
+}
+
+func (m *PurgeJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeJobRequest.Unmarshal(m, b)
+}
+func (m *PurgeJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeJobRequest.Marshal(b, m, deterministic)
+}
+func (m *PurgeJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeJobRequest.Merge(m, src)
+}
+func (m *PurgeJobRequest) XXX_Size() int {
+	return xxx_messageInfo_PurgeJobRequest.Size(m)
+}
+func (m *PurgeJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeJobRequest proto.InternalMessageInfo
+
+func (m *PurgeJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PurgeJobRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type PurgeJobResponse struct {
+	// jobs lists the purged jobs, i.e. the job and the jobs of its matrix
+	Jobs                 []string `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PurgeJobResponse) Reset()         { *m = PurgeJobResponse{} }
+func (m *PurgeJobResponse) String() string { return proto.CompactTextString(m) }
+func (*PurgeJobResponse) ProtoMessage()    {}
+func (*PurgeJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{80}
+}
+
+func (m *PurgeJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PurgeJobResponse.Unmarshal(m, b)
+}
+func (m *PurgeJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PurgeJobResponse.Marshal(b, m, deterministic)
+}
+func (m *PurgeJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PurgeJobResponse.Merge(m, src)
+}
+func (m *PurgeJobResponse) XXX_Size() int {
+	return xxx_messageInfo_PurgeJobResponse.Size(m)
+}
+func (m *PurgeJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PurgeJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PurgeJobResponse proto.InternalMessageInfo
+
+func (m *PurgeJobResponse) GetJobs() []string {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+type ApproveJobRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// github_token identifies the user approving the job
+	GithubToken string `protobuf:"bytes,2,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	// comment is recorded along with the approval, e.g. a change ticket
+	Comment              string   `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApproveJobRequest) Reset()         { *m = ApproveJobRequest{} }
+func (m *ApproveJobRequest) String() string { return proto.CompactTextString(m) }
+func (*ApproveJobRequest) ProtoMessage()    {}
+func (*ApproveJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{81}
+}
+
+func (m *ApproveJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveJobRequest.Unmarshal(m, b)
+}
+func (m *ApproveJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveJobRequest.Marshal(b, m, deterministic)
+}
+func (m *ApproveJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveJobRequest.Merge(m, src)
+}
+func (m *ApproveJobRequest) XXX_Size() int {
+	return xxx_messageInfo_ApproveJobRequest.Size(m)
+}
+func (m *ApproveJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveJobRequest proto.InternalMessageInfo
+
+func (m *ApproveJobRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ApproveJobRequest) GetGithubToken() string {
+	if m != nil {
+		return m.GithubToken
+	}
+	return ""
+}
+
+func (m *ApproveJobRequest) GetComment() string {
+	if m != nil {
+		return m.Comment
+	}
+	return ""
+}
+
+type ApproveJobResponse struct {
+	// jobs are the approved jobs, i.e. the job or all jobs of its matrix which waited for approval
+	Jobs                 []string `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApproveJobResponse) Reset()         { *m = ApproveJobResponse{} }
+func (m *ApproveJobResponse) String() string { return proto.CompactTextString(m) }
+func (*ApproveJobResponse) ProtoMessage()    {}
+func (*ApproveJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{82}
+}
+
+func (m *ApproveJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveJobResponse.Unmarshal(m, b)
+}
+func (m *ApproveJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveJobResponse.Marshal(b, m, deterministic)
+}
+func (m *ApproveJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveJobResponse.Merge(m, src)
+}
+func (m *ApproveJobResponse) XXX_Size() int {
+	return xxx_messageInfo_ApproveJobResponse.Size(m)
+}
+func (m *ApproveJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveJobResponse proto.InternalMessageInfo
+
+func (m *ApproveJobResponse) GetJobs() []string {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+type AddJobNoteRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// github_token identifies the user adding the note
+	GithubToken          string   `protobuf:"bytes,2,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	Text                 string   `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddJobNoteRequest) Reset()         { *m = AddJobNoteRequest{} }
+func (m *AddJobNoteRequest) String() string { return proto.CompactTextString(m) }
+func (*AddJobNoteRequest) ProtoMessage()    {}
+func (*AddJobNoteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{83}
+}
+
+func (m *AddJobNoteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddJobNoteRequest.Unmarshal(m, b)
+}
+func (m *AddJobNoteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddJobNoteRequest.Marshal(b, m, deterministic)
+}
+func (m *AddJobNoteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddJobNoteRequest.Merge(m, src)
+}
+func (m *AddJobNoteRequest) XXX_Size() int {
+	return xxx_messageInfo_AddJobNoteRequest.Size(m)
+}
+func (m *AddJobNoteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddJobNoteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddJobNoteRequest proto.InternalMessageInfo
+
+func (m *AddJobNoteRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AddJobNoteRequest) GetGithubToken() string {
+	if m != nil {
+		return m.GithubToken
+	}
+	return ""
+}
+
+func (m *AddJobNoteRequest) GetText() string {
+	if m != nil {
+		return m.Text
+	}
+	return ""
+}
+
+type AddJobNoteResponse struct {
+	Note                 *JobNote `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddJobNoteResponse) Reset()         { *m = AddJobNoteResponse{} }
+func (m *AddJobNoteResponse) String() string { return proto.CompactTextString(m) }
+func (*AddJobNoteResponse) ProtoMessage()    {}
+func (*AddJobNoteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{84}
+}
+
+func (m *AddJobNoteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddJobNoteResponse.Unmarshal(m, b)
+}
+func (m *AddJobNoteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddJobNoteResponse.Marshal(b, m, deterministic)
+}
+func (m *AddJobNoteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddJobNoteResponse.Merge(m, src)
+}
+func (m *AddJobNoteResponse) XXX_Size() int {
+	return xxx_messageInfo_AddJobNoteResponse.Size(m)
+}
+func (m *AddJobNoteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddJobNoteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddJobNoteResponse proto.InternalMessageInfo
+
+func (m *AddJobNoteResponse) GetNote() *JobNote {
+	if m != nil {
+		return m.Note
+	}
+	return nil
+}
+
+type SetAnnouncementRequest struct {
+	// message is the announcement - an empty message clears the announcement
+	Message string `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// token authorizes setting the announcement and must be one of the admin tokens configured for werft
+	Token                string   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetAnnouncementRequest) Reset()         { *m = SetAnnouncementRequest{} }
+func (m *SetAnnouncementRequest) String() string { return proto.CompactTextString(m) }
+func (*SetAnnouncementRequest) ProtoMessage()    {}
+func (*SetAnnouncementRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{85}
+}
+
+func (m *SetAnnouncementRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetAnnouncementRequest.Unmarshal(m, b)
+}
+func (m *SetAnnouncementRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetAnnouncementRequest.Marshal(b, m, deterministic)
+}
+func (m *SetAnnouncementRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetAnnouncementRequest.Merge(m, src)
+}
+func (m *SetAnnouncementRequest) XXX_Size() int {
+	return xxx_messageInfo_SetAnnouncementRequest.Size(m)
+}
+func (m *SetAnnouncementRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetAnnouncementRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetAnnouncementRequest proto.InternalMessageInfo
+
+func (m *SetAnnouncementRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *SetAnnouncementRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type SetAnnouncementResponse struct {
+	// announcement is the new announcement, which is unset if the announcement was cleared
+	Announcement         *Announcement `protobuf:"bytes,1,opt,name=announcement,proto3" json:"announcement,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *SetAnnouncementResponse) Reset()         { *m = SetAnnouncementResponse{} }
+func (m *SetAnnouncementResponse) String() string { return proto.CompactTextString(m) }
+func (*SetAnnouncementResponse) ProtoMessage()    {}
+func (*SetAnnouncementResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{86}
+}
+
+func (m *SetAnnouncementResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetAnnouncementResponse.Unmarshal(m, b)
+}
+func (m *SetAnnouncementResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetAnnouncementResponse.Marshal(b, m, deterministic)
+}
+func (m *SetAnnouncementResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetAnnouncementResponse.Merge(m, src)
+}
+func (m *SetAnnouncementResponse) XXX_Size() int {
+	return xxx_messageInfo_SetAnnouncementResponse.Size(m)
+}
+func (m *SetAnnouncementResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetAnnouncementResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetAnnouncementResponse proto.InternalMessageInfo
+
+func (m *SetAnnouncementResponse) GetAnnouncement() *Announcement {
+	if m != nil {
+		return m.Announcement
+	}
+	return nil
+}
+
+type GetAnnouncementRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAnnouncementRequest) Reset()         { *m = GetAnnouncementRequest{} }
+func (m *GetAnnouncementRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAnnouncementRequest) ProtoMessage()    {}
+func (*GetAnnouncementRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{87}
+}
+
+func (m *GetAnnouncementRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAnnouncementRequest.Unmarshal(m, b)
+}
+func (m *GetAnnouncementRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAnnouncementRequest.Marshal(b, m, deterministic)
+}
+func (m *GetAnnouncementRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAnnouncementRequest.Merge(m, src)
+}
+func (m *GetAnnouncementRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAnnouncementRequest.Size(m)
+}
+func (m *GetAnnouncementRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAnnouncementRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAnnouncementRequest proto.InternalMessageInfo
+
+type GetAnnouncementResponse struct {
+	// announcement is unset if there is no announcement
+	Announcement         *Announcement `protobuf:"bytes,1,opt,name=announcement,proto3" json:"announcement,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetAnnouncementResponse) Reset()         { *m = GetAnnouncementResponse{} }
+func (m *GetAnnouncementResponse) String() string { return proto.CompactTextString(m) }
+func (*GetAnnouncementResponse) ProtoMessage()    {}
+func (*GetAnnouncementResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{88}
+}
+
+func (m *GetAnnouncementResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAnnouncementResponse.Unmarshal(m, b)
+}
+func (m *GetAnnouncementResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAnnouncementResponse.Marshal(b, m, deterministic)
+}
+func (m *GetAnnouncementResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAnnouncementResponse.Merge(m, src)
+}
+func (m *GetAnnouncementResponse) XXX_Size() int {
+	return xxx_messageInfo_GetAnnouncementResponse.Size(m)
+}
+func (m *GetAnnouncementResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAnnouncementResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAnnouncementResponse proto.InternalMessageInfo
+
+func (m *GetAnnouncementResponse) GetAnnouncement() *Announcement {
+	if m != nil {
+		return m.Announcement
+	}
+	return nil
+}
+
+type Announcement struct {
+	Message              string               `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Since                *timestamp.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Announcement) Reset()         { *m = Announcement{} }
+func (m *Announcement) String() string { return proto.CompactTextString(m) }
+func (*Announcement) ProtoMessage()    {}
+func (*Announcement) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{89}
+}
+
+func (m *Announcement) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Announcement.Unmarshal(m, b)
+}
+func (m *Announcement) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Announcement.Marshal(b, m, deterministic)
+}
+func (m *Announcement) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Announcement.Merge(m, src)
+}
+func (m *Announcement) XXX_Size() int {
+	return xxx_messageInfo_Announcement.Size(m)
+}
+func (m *Announcement) XXX_DiscardUnknown() {
+	xxx_messageInfo_Announcement.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Announcement proto.InternalMessageInfo
+
+func (m *Announcement) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Announcement) GetSince() *timestamp.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+type GetLatestJobRequest struct {
+	// repository selects the jobs by owner, repo and ref, and optionally by host
+	Repository *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// job_spec limits the jobs to those of a job file, e.g. build for .werft/build.yaml
+	JobSpec string `protobuf:"bytes,2,opt,name=job_spec,json=jobSpec,proto3" json:"job_spec,omitempty"`
+	// done limits the jobs to those which have finished
+	Done                 bool     `protobuf:"varint,3,opt,name=done,proto3" json:"done,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLatestJobRequest) Reset()         { *m = GetLatestJobRequest{} }
+func (m *GetLatestJobRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLatestJobRequest) ProtoMessage()    {}
+func (*GetLatestJobRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{90}
+}
+
+func (m *GetLatestJobRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLatestJobRequest.Unmarshal(m, b)
+}
+func (m *GetLatestJobRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLatestJobRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLatestJobRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLatestJobRequest.Merge(m, src)
+}
+func (m *GetLatestJobRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLatestJobRequest.Size(m)
+}
+func (m *GetLatestJobRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLatestJobRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLatestJobRequest proto.InternalMessageInfo
+
+func (m *GetLatestJobRequest) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *GetLatestJobRequest) GetJobSpec() string {
+	if m != nil {
+		return m.JobSpec
+	}
+	return ""
+}
+
+func (m *GetLatestJobRequest) GetDone() bool {
+	if m != nil {
+		return m.Done
+	}
+	return false
+}
+
+type GetLatestJobResponse struct {
+	Result               *JobStatus `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetLatestJobResponse) Reset()         { *m = GetLatestJobResponse{} }
+func (m *GetLatestJobResponse) String() string { return proto.CompactTextString(m) }
+func (*GetLatestJobResponse) ProtoMessage()    {}
+func (*GetLatestJobResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{91}
+}
+
+func (m *GetLatestJobResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLatestJobResponse.Unmarshal(m, b)
+}
+func (m *GetLatestJobResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLatestJobResponse.Marshal(b, m, deterministic)
+}
+func (m *GetLatestJobResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLatestJobResponse.Merge(m, src)
+}
+func (m *GetLatestJobResponse) XXX_Size() int {
+	return xxx_messageInfo_GetLatestJobResponse.Size(m)
+}
+func (m *GetLatestJobResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLatestJobResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLatestJobResponse proto.InternalMessageInfo
+
+func (m *GetLatestJobResponse) GetResult() *JobStatus {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+type GetBranchesRequest struct {
+	// repository selects the jobs by owner and repo, and optionally by host. Its ref and revision are ignored.
+	Repository *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// limit is the number of most recent jobs of the repository considered. Branches without jobs amongst them
+	// are left out. Defaults to 1000.
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBranchesRequest) Reset()         { *m = GetBranchesRequest{} }
+func (m *GetBranchesRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBranchesRequest) ProtoMessage()    {}
+func (*GetBranchesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{92}
+}
+
+func (m *GetBranchesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBranchesRequest.Unmarshal(m, b)
+}
+func (m *GetBranchesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBranchesRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBranchesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBranchesRequest.Merge(m, src)
+}
+func (m *GetBranchesRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBranchesRequest.Size(m)
+}
+func (m *GetBranchesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBranchesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBranchesRequest proto.InternalMessageInfo
+
+func (m *GetBranchesRequest) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *GetBranchesRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type GetBranchesResponse struct {
+	// branches are sorted by their latest job, most recent first
+	Branches             []*Branch `protobuf:"bytes,1,rep,name=branches,proto3" json:"branches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetBranchesResponse) Reset()         { *m = GetBranchesResponse{} }
+func (m *GetBranchesResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBranchesResponse) ProtoMessage()    {}
+func (*GetBranchesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{93}
+}
+
+func (m *GetBranchesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBranchesResponse.Unmarshal(m, b)
+}
+func (m *GetBranchesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBranchesResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBranchesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBranchesResponse.Merge(m, src)
+}
+func (m *GetBranchesResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBranchesResponse.Size(m)
+}
+func (m *GetBranchesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBranchesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBranchesResponse proto.InternalMessageInfo
+
+func (m *GetBranchesResponse) GetBranches() []*Branch {
+	if m != nil {
+		return m.Branches
+	}
+	return nil
+}
+
+type Branch struct {
+	// ref is the full ref of the branch, e.g. refs/heads/master
+	Ref string `protobuf:"bytes,1,opt,name=ref,proto3" json:"ref,omitempty"`
+	// latest is the most recent job of the branch, which might still be running
+	Latest *JobStatus `protobuf:"bytes,2,opt,name=latest,proto3" json:"latest,omitempty"`
+	// latest_finished is the most recent job of the branch which ran and is done
+	LatestFinished *JobStatus `protobuf:"bytes,3,opt,name=latest_finished,json=latestFinished,proto3" json:"latest_finished,omitempty"`
+	// last_success is when the most recent successful job of the branch finished
+	LastSuccess *timestamp.Timestamp `protobuf:"bytes,4,opt,name=last_success,json=lastSuccess,proto3" json:"last_success,omitempty"`
+	// jobs is the number of jobs of the branch which were considered
+	Jobs                 int32    `protobuf:"varint,5,opt,name=jobs,proto3" json:"jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Branch) Reset()         { *m = Branch{} }
+func (m *Branch) String() string { return proto.CompactTextString(m) }
+func (*Branch) ProtoMessage()    {}
+func (*Branch) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{94}
+}
+
+func (m *Branch) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Branch.Unmarshal(m, b)
+}
+func (m *Branch) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Branch.Marshal(b, m, deterministic)
+}
+func (m *Branch) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Branch.Merge(m, src)
+}
+func (m *Branch) XXX_Size() int {
+	return xxx_messageInfo_Branch.Size(m)
+}
+func (m *Branch) XXX_DiscardUnknown() {
+	xxx_messageInfo_Branch.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Branch proto.InternalMessageInfo
+
+func (m *Branch) GetRef() string {
+	if m != nil {
+		return m.Ref
+	}
+	return ""
+}
+
+func (m *Branch) GetLatest() *JobStatus {
+	if m != nil {
+		return m.Latest
+	}
+	return nil
+}
+
+func (m *Branch) GetLatestFinished() *JobStatus {
+	if m != nil {
+		return m.LatestFinished
+	}
+	return nil
+}
+
+func (m *Branch) GetLastSuccess() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastSuccess
+	}
+	return nil
+}
+
+func (m *Branch) GetJobs() int32 {
+	if m != nil {
+		return m.Jobs
+	}
+	return 0
+}
+
+type JobPod struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Namespace            string   `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Node                 string   `protobuf:"bytes,3,opt,name=node,proto3" json:"node,omitempty"`
+	Containers           []string `protobuf:"bytes,4,rep,name=containers,proto3" json:"containers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobPod) Reset()         { *m = JobPod{} }
+func (m *JobPod) String() string { return proto.CompactTextString(m) }
+func (*JobPod) ProtoMessage()    {}
+func (*JobPod) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{95}
+}
+
+func (m *JobPod) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobPod.Unmarshal(m, b)
+}
+func (m *JobPod) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobPod.Marshal(b, m, deterministic)
+}
+func (m *JobPod) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobPod.Merge(m, src)
+}
+func (m *JobPod) XXX_Size() int {
+	return xxx_messageInfo_JobPod.Size(m)
+}
+func (m *JobPod) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobPod.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobPod proto.InternalMessageInfo
+
+func (m *JobPod) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *JobPod) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *JobPod) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *JobPod) GetContainers() []string {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+type JobCost struct {
+	// amount is the estimated cost in currency
+	Amount   float64 `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency string  `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	// cpu_millis, memory_bytes and gpus are the resources the job's pod requests
+	CpuMillis   int64 `protobuf:"varint,3,opt,name=cpu_millis,json=cpuMillis,proto3" json:"cpu_millis,omitempty"`
+	MemoryBytes int64 `protobuf:"varint,4,opt,name=memory_bytes,json=memoryBytes,proto3" json:"memory_bytes,omitempty"`
+	Gpus        int64 `protobuf:"varint,5,opt,name=gpus,proto3" json:"gpus,omitempty"`
+	// seconds is the time the job's pod ran for
+	Seconds              float64  `protobuf:"fixed64,6,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobCost) Reset()         { *m = JobCost{} }
+func (m *JobCost) String() string { return proto.CompactTextString(m) }
+func (*JobCost) ProtoMessage()    {}
+func (*JobCost) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{96}
+}
+
+func (m *JobCost) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobCost.Unmarshal(m, b)
+}
+func (m *JobCost) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobCost.Marshal(b, m, deterministic)
+}
+func (m *JobCost) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobCost.Merge(m, src)
+}
+func (m *JobCost) XXX_Size() int {
+	return xxx_messageInfo_JobCost.Size(m)
+}
+func (m *JobCost) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobCost.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobCost proto.InternalMessageInfo
+
+func (m *JobCost) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *JobCost) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *JobCost) GetCpuMillis() int64 {
+	if m != nil {
+		return m.CpuMillis
+	}
+	return 0
+}
+
+func (m *JobCost) GetMemoryBytes() int64 {
+	if m != nil {
+		return m.MemoryBytes
+	}
+	return 0
+}
+
+func (m *JobCost) GetGpus() int64 {
+	if m != nil {
+		return m.Gpus
+	}
+	return 0
+}
+
+func (m *JobCost) GetSeconds() float64 {
+	if m != nil {
+		return m.Seconds
+	}
+	return 0
+}
+
+type GetJobCostsRequest struct {
+	// group_by lists what costs are summed up by. No grouping sums up all jobs.
+	GroupBy []CostGrouping `protobuf:"varint,1,rep,packed,name=group_by,json=groupBy,proto3,enum=v1.CostGrouping" json:"group_by,omitempty"`
+	// from and to limit the jobs to those created in that time. Both are optional.
+	From *timestamp.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamp.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	// repository limits the jobs to those of a repository. Its ref and revision are ignored.
+	Repository           *Repository `protobuf:"bytes,4,opt,name=repository,proto3" json:"repository,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *GetJobCostsRequest) Reset()         { *m = GetJobCostsRequest{} }
+func (m *GetJobCostsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetJobCostsRequest) ProtoMessage()    {}
+func (*GetJobCostsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{97}
+}
+
+func (m *GetJobCostsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobCostsRequest.Unmarshal(m, b)
+}
+func (m *GetJobCostsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobCostsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetJobCostsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobCostsRequest.Merge(m, src)
+}
+func (m *GetJobCostsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetJobCostsRequest.Size(m)
+}
+func (m *GetJobCostsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobCostsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobCostsRequest proto.InternalMessageInfo
+
+func (m *GetJobCostsRequest) GetGroupBy() []CostGrouping {
+	if m != nil {
+		return m.GroupBy
+	}
+	return nil
+}
+
+func (m *GetJobCostsRequest) GetFrom() *timestamp.Timestamp {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *GetJobCostsRequest) GetTo() *timestamp.Timestamp {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *GetJobCostsRequest) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+type GetJobCostsResponse struct {
+	Currency string `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	// groups are ordered by their repository, team and month
+	Groups []*JobCostGroup `protobuf:"bytes,2,rep,name=groups,proto3" json:"groups,omitempty"`
+	Total  float64         `protobuf:"fixed64,3,opt,name=total,proto3" json:"total,omitempty"`
+	// truncated is true if there were more jobs than werft considers for a single request
+	Truncated            bool     `protobuf:"varint,4,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobCostsResponse) Reset()         { *m = GetJobCostsResponse{} }
+func (m *GetJobCostsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetJobCostsResponse) ProtoMessage()    {}
+func (*GetJobCostsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{98}
+}
+
+func (m *GetJobCostsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobCostsResponse.Unmarshal(m, b)
+}
+func (m *GetJobCostsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobCostsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetJobCostsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobCostsResponse.Merge(m, src)
+}
+func (m *GetJobCostsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetJobCostsResponse.Size(m)
+}
+func (m *GetJobCostsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobCostsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobCostsResponse proto.InternalMessageInfo
+
+func (m *GetJobCostsResponse) GetCurrency() string {
+	if m != nil {
+		return m.Currency
+	}
+	return ""
+}
+
+func (m *GetJobCostsResponse) GetGroups() []*JobCostGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+func (m *GetJobCostsResponse) GetTotal() float64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *GetJobCostsResponse) GetTruncated() bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return false
+}
+
+type JobCostGroup struct {
+	// repository, team and month are set if costs are grouped by them
+	Repository string  `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	Team       string  `protobuf:"bytes,2,opt,name=team,proto3" json:"team,omitempty"`
+	Month      string  `protobuf:"bytes,3,opt,name=month,proto3" json:"month,omitempty"`
+	Amount     float64 `protobuf:"fixed64,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Jobs       int32   `protobuf:"varint,5,opt,name=jobs,proto3" json:"jobs,omitempty"`
+	// seconds is the time the pods of the jobs ran for
+	Seconds              float64  `protobuf:"fixed64,6,opt,name=seconds,proto3" json:"seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobCostGroup) Reset()         { *m = JobCostGroup{} }
+func (m *JobCostGroup) String() string { return proto.CompactTextString(m) }
+func (*JobCostGroup) ProtoMessage()    {}
+func (*JobCostGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{99}
+}
+
+func (m *JobCostGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobCostGroup.Unmarshal(m, b)
+}
+func (m *JobCostGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobCostGroup.Marshal(b, m, deterministic)
+}
+func (m *JobCostGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobCostGroup.Merge(m, src)
+}
+func (m *JobCostGroup) XXX_Size() int {
+	return xxx_messageInfo_JobCostGroup.Size(m)
+}
+func (m *JobCostGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobCostGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobCostGroup proto.InternalMessageInfo
+
+func (m *JobCostGroup) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *JobCostGroup) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *JobCostGroup) GetMonth() string {
+	if m != nil {
+		return m.Month
+	}
+	return ""
+}
+
+func (m *JobCostGroup) GetAmount() float64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *JobCostGroup) GetJobs() int32 {
+	if m != nil {
+		return m.Jobs
+	}
+	return 0
+}
+
+func (m *JobCostGroup) GetSeconds() float64 {
+	if m != nil {
+		return m.Seconds
+	}
+	return 0
+}
+
+type TriggerPayload struct {
+	// source is what sent the payload, e.g. github
+	Source string `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
+	// event_type is the type of the event, e.g. the X-GitHub-Event header of a GitHub webhook
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// delivery identifies the delivery of the event, e.g. the X-GitHub-Delivery header of a GitHub webhook
+	Delivery             string               `protobuf:"bytes,3,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	Received             *timestamp.Timestamp `protobuf:"bytes,4,opt,name=received,proto3" json:"received,omitempty"`
+	Payload              []byte               `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *TriggerPayload) Reset()         { *m = TriggerPayload{} }
+func (m *TriggerPayload) String() string { return proto.CompactTextString(m) }
+func (*TriggerPayload) ProtoMessage()    {}
+func (*TriggerPayload) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{100}
+}
+
+func (m *TriggerPayload) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TriggerPayload.Unmarshal(m, b)
+}
+func (m *TriggerPayload) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TriggerPayload.Marshal(b, m, deterministic)
+}
+func (m *TriggerPayload) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TriggerPayload.Merge(m, src)
+}
+func (m *TriggerPayload) XXX_Size() int {
+	return xxx_messageInfo_TriggerPayload.Size(m)
+}
+func (m *TriggerPayload) XXX_DiscardUnknown() {
+	xxx_messageInfo_TriggerPayload.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TriggerPayload proto.InternalMessageInfo
+
+func (m *TriggerPayload) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+func (m *TriggerPayload) GetEventType() string {
+	if m != nil {
+		return m.EventType
+	}
+	return ""
+}
+
+func (m *TriggerPayload) GetDelivery() string {
+	if m != nil {
+		return m.Delivery
+	}
+	return ""
+}
+
+func (m *TriggerPayload) GetReceived() *timestamp.Timestamp {
+	if m != nil {
+		return m.Received
+	}
+	return nil
+}
+
+func (m *TriggerPayload) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type GetTriggerPayloadRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTriggerPayloadRequest) Reset()         { *m = GetTriggerPayloadRequest{} }
+func (m *GetTriggerPayloadRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTriggerPayloadRequest) ProtoMessage()    {}
+func (*GetTriggerPayloadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{101}
+}
+
+func (m *GetTriggerPayloadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTriggerPayloadRequest.Unmarshal(m, b)
+}
+func (m *GetTriggerPayloadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTriggerPayloadRequest.Marshal(b, m, deterministic)
+}
+func (m *GetTriggerPayloadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTriggerPayloadRequest.Merge(m, src)
+}
+func (m *GetTriggerPayloadRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTriggerPayloadRequest.Size(m)
+}
+func (m *GetTriggerPayloadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTriggerPayloadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTriggerPayloadRequest proto.InternalMessageInfo
+
+func (m *GetTriggerPayloadRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type GetTriggerPayloadResponse struct {
+	Trigger              *TriggerPayload `protobuf:"bytes,1,opt,name=trigger,proto3" json:"trigger,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetTriggerPayloadResponse) Reset()         { *m = GetTriggerPayloadResponse{} }
+func (m *GetTriggerPayloadResponse) String() string { return proto.CompactTextString(m) }
+func (*GetTriggerPayloadResponse) ProtoMessage()    {}
+func (*GetTriggerPayloadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{102}
+}
+
+func (m *GetTriggerPayloadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTriggerPayloadResponse.Unmarshal(m, b)
+}
+func (m *GetTriggerPayloadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTriggerPayloadResponse.Marshal(b, m, deterministic)
+}
+func (m *GetTriggerPayloadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTriggerPayloadResponse.Merge(m, src)
+}
+func (m *GetTriggerPayloadResponse) XXX_Size() int {
+	return xxx_messageInfo_GetTriggerPayloadResponse.Size(m)
+}
+func (m *GetTriggerPayloadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTriggerPayloadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTriggerPayloadResponse proto.InternalMessageInfo
+
+func (m *GetTriggerPayloadResponse) GetTrigger() *TriggerPayload {
+	if m != nil {
+		return m.Trigger
+	}
+	return nil
+}
+
+type BatchJobsRequest struct {
+	Filter []*FilterExpression `protobuf:"bytes,1,rep,name=filter,proto3" json:"filter,omitempty"`
+	// label_selector is a Kubernetes-style label selector, e.g. "team=platform,stage in (build, test)"
+	LabelSelector string `protobuf:"bytes,2,opt,name=label_selector,json=labelSelector,proto3" json:"label_selector,omitempty"`
+	GithubToken   string `protobuf:"bytes,3,opt,name=github_token,json=githubToken,proto3" json:"github_token,omitempty"`
+	// dry_run lists the jobs the operation would apply to, without applying it
+	DryRun bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// limit is the number of jobs the operation applies to at most. Defaults to 100.
+	Limit                int32    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchJobsRequest) Reset()         { *m = BatchJobsRequest{} }
+func (m *BatchJobsRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchJobsRequest) ProtoMessage()    {}
+func (*BatchJobsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{103}
+}
+
+func (m *BatchJobsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchJobsRequest.Unmarshal(m, b)
+}
+func (m *BatchJobsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchJobsRequest.Marshal(b, m, deterministic)
+}
+func (m *BatchJobsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchJobsRequest.Merge(m, src)
+}
+func (m *BatchJobsRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchJobsRequest.Size(m)
+}
+func (m *BatchJobsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchJobsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchJobsRequest proto.InternalMessageInfo
+
+func (m *BatchJobsRequest) GetFilter() []*FilterExpression {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *BatchJobsRequest) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+func (m *BatchJobsRequest) GetGithubToken() string {
+	if m != nil {
+		return m.GithubToken
+	}
+	return ""
+}
+
+func (m *BatchJobsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *BatchJobsRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type BatchJobsResponse struct {
+	Results []*BatchJobResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// succeeded counts the jobs the operation applied to, or would have applied to in a dry run
+	Succeeded int32 `protobuf:"varint,2,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	Failed    int32 `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	Skipped   int32 `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	// truncated is true if more jobs matched the filter than the limit allowed for
+	Truncated            bool     `protobuf:"varint,5,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchJobsResponse) Reset()         { *m = BatchJobsResponse{} }
+func (m *BatchJobsResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchJobsResponse) ProtoMessage()    {}
+func (*BatchJobsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{104}
+}
+
+func (m *BatchJobsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchJobsResponse.Unmarshal(m, b)
+}
+func (m *BatchJobsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchJobsResponse.Marshal(b, m, deterministic)
+}
+func (m *BatchJobsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchJobsResponse.Merge(m, src)
+}
+func (m *BatchJobsResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchJobsResponse.Size(m)
+}
+func (m *BatchJobsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchJobsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchJobsResponse proto.InternalMessageInfo
+
+func (m *BatchJobsResponse) GetResults() []*BatchJobResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+func (m *BatchJobsResponse) GetSucceeded() int32 {
+	if m != nil {
+		return m.Succeeded
+	}
+	return 0
+}
+
+func (m *BatchJobsResponse) GetFailed() int32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *BatchJobsResponse) GetSkipped() int32 {
+	if m != nil {
+		return m.Skipped
+	}
+	return 0
+}
+
+func (m *BatchJobsResponse) GetTruncated() bool {
+	if m != nil {
+		return m.Truncated
+	}
+	return false
+}
+
+type BatchJobResult struct {
+	Name    string          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Outcome BatchJobOutcome `protobuf:"varint,2,opt,name=outcome,proto3,enum=v1.BatchJobOutcome" json:"outcome,omitempty"`
+	// message says why the operation failed or skipped the job
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// new_job is the name of the job a retry started
+	NewJob               string   `protobuf:"bytes,4,opt,name=new_job,json=newJob,proto3" json:"new_job,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchJobResult) Reset()         { *m = BatchJobResult{} }
+func (m *BatchJobResult) String() string { return proto.CompactTextString(m) }
+func (*BatchJobResult) ProtoMessage()    {}
+func (*BatchJobResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{105}
+}
+
+func (m *BatchJobResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchJobResult.Unmarshal(m, b)
+}
+func (m *BatchJobResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchJobResult.Marshal(b, m, deterministic)
+}
+func (m *BatchJobResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchJobResult.Merge(m, src)
+}
+func (m *BatchJobResult) XXX_Size() int {
+	return xxx_messageInfo_BatchJobResult.Size(m)
+}
+func (m *BatchJobResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchJobResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchJobResult proto.InternalMessageInfo
+
+func (m *BatchJobResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BatchJobResult) GetOutcome() BatchJobOutcome {
+	if m != nil {
+		return m.Outcome
+	}
+	return BatchJobOutcome_BATCH_SUCCEEDED
+}
+
+func (m *BatchJobResult) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *BatchJobResult) GetNewJob() string {
+	if m != nil {
+		return m.NewJob
+	}
+	return ""
+}
+
+type GetJobSBOMRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// image restricts the response to the SBOM of an image, given as name or name@digest
+	Image                string   `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetJobSBOMRequest) Reset()         { *m = GetJobSBOMRequest{} }
+func (m *GetJobSBOMRequest) String() string { return proto.CompactTextString(m) }
+func (*GetJobSBOMRequest) ProtoMessage()    {}
+func (*GetJobSBOMRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{106}
+}
+
+func (m *GetJobSBOMRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobSBOMRequest.Unmarshal(m, b)
+}
+func (m *GetJobSBOMRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobSBOMRequest.Marshal(b, m, deterministic)
+}
+func (m *GetJobSBOMRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobSBOMRequest.Merge(m, src)
+}
+func (m *GetJobSBOMRequest) XXX_Size() int {
+	return xxx_messageInfo_GetJobSBOMRequest.Size(m)
+}
+func (m *GetJobSBOMRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobSBOMRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobSBOMRequest proto.InternalMessageInfo
+
+func (m *GetJobSBOMRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetJobSBOMRequest) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+type GetJobSBOMResponse struct {
+	Sboms                []*JobSBOM `protobuf:"bytes,1,rep,name=sboms,proto3" json:"sboms,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *GetJobSBOMResponse) Reset()         { *m = GetJobSBOMResponse{} }
+func (m *GetJobSBOMResponse) String() string { return proto.CompactTextString(m) }
+func (*GetJobSBOMResponse) ProtoMessage()    {}
+func (*GetJobSBOMResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{107}
+}
+
+func (m *GetJobSBOMResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetJobSBOMResponse.Unmarshal(m, b)
+}
+func (m *GetJobSBOMResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetJobSBOMResponse.Marshal(b, m, deterministic)
+}
+func (m *GetJobSBOMResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetJobSBOMResponse.Merge(m, src)
+}
+func (m *GetJobSBOMResponse) XXX_Size() int {
+	return xxx_messageInfo_GetJobSBOMResponse.Size(m)
+}
+func (m *GetJobSBOMResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetJobSBOMResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetJobSBOMResponse proto.InternalMessageInfo
+
+func (m *GetJobSBOMResponse) GetSboms() []*JobSBOM {
+	if m != nil {
+		return m.Sboms
+	}
+	return nil
+}
+
+type JobSBOM struct {
+	// image is the image the SBOM describes (name@digest)
+	Image string `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	// format is the format of the SBOM, e.g. spdx-json
+	Format               string   `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Content              []byte   `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobSBOM) Reset()         { *m = JobSBOM{} }
+func (m *JobSBOM) String() string { return proto.CompactTextString(m) }
+func (*JobSBOM) ProtoMessage()    {}
+func (*JobSBOM) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{108}
+}
+
+func (m *JobSBOM) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobSBOM.Unmarshal(m, b)
+}
+func (m *JobSBOM) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobSBOM.Marshal(b, m, deterministic)
+}
+func (m *JobSBOM) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobSBOM.Merge(m, src)
+}
+func (m *JobSBOM) XXX_Size() int {
+	return xxx_messageInfo_JobSBOM.Size(m)
+}
+func (m *JobSBOM) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobSBOM.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobSBOM proto.InternalMessageInfo
+
+func (m *JobSBOM) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *JobSBOM) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *JobSBOM) GetContent() []byte {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+type StreamEventsRequest struct {
+	// filter is an expression in a subset of CEL, e.g. type == "job" && job.repo.owner == "32leaves".
+	// The empty filter matches all events.
+	Filter               string   `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamEventsRequest) Reset()         { *m = StreamEventsRequest{} }
+func (m *StreamEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamEventsRequest) ProtoMessage()    {}
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{109}
+}
+
+func (m *StreamEventsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamEventsRequest.Unmarshal(m, b)
+}
+func (m *StreamEventsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamEventsRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamEventsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamEventsRequest.Merge(m, src)
+}
+func (m *StreamEventsRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamEventsRequest.Size(m)
+}
+func (m *StreamEventsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamEventsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamEventsRequest proto.InternalMessageInfo
+
+func (m *StreamEventsRequest) GetFilter() string {
+	if m != nil {
+		return m.Filter
+	}
+	return ""
+}
+
+type ServerEvent struct {
+	// type is one of job, job_event, queue, config, maintenance, announcement or dead_letter
+	Type string               `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Time *timestamp.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	// job is the job the event is about, for job and queue events
+	Job *JobStatus `protobuf:"bytes,3,opt,name=job,proto3" json:"job,omitempty"`
+	// job_name is the name of the job the event is about, for job, job_event and queue events
+	JobName string `protobuf:"bytes,4,opt,name=job_name,json=jobName,proto3" json:"job_name,omitempty"`
+	// job_event is the event recorded for a job, for job_event events
+	JobEvent *JobEvent `protobuf:"bytes,5,opt,name=job_event,json=jobEvent,proto3" json:"job_event,omitempty"`
+	// message describes the event
+	Message string `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"`
+	// attributes carry event specific details, e.g. the revision of the central config
+	Attributes           map[string]string `protobuf:"bytes,7,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ServerEvent) Reset()         { *m = ServerEvent{} }
+func (m *ServerEvent) String() string { return proto.CompactTextString(m) }
+func (*ServerEvent) ProtoMessage()    {}
+func (*ServerEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{110}
+}
+
+func (m *ServerEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServerEvent.Unmarshal(m, b)
+}
+func (m *ServerEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServerEvent.Marshal(b, m, deterministic)
+}
+func (m *ServerEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServerEvent.Merge(m, src)
+}
+func (m *ServerEvent) XXX_Size() int {
+	return xxx_messageInfo_ServerEvent.Size(m)
+}
+func (m *ServerEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServerEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServerEvent proto.InternalMessageInfo
+
+func (m *ServerEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ServerEvent) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *ServerEvent) GetJob() *JobStatus {
+	if m != nil {
+		return m.Job
+	}
+	return nil
+}
+
+func (m *ServerEvent) GetJobName() string {
+	if m != nil {
+		return m.JobName
+	}
+	return ""
+}
+
+func (m *ServerEvent) GetJobEvent() *JobEvent {
+	if m != nil {
+		return m.JobEvent
+	}
+	return nil
+}
+
+func (m *ServerEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ServerEvent) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+type SyncRequiredChecksRequest struct {
+	Repository *Repository `protobuf:"bytes,1,opt,name=repository,proto3" json:"repository,omitempty"`
+	// branch is the protected branch, e.g. master. Defaults to the default branch of the repository.
+	Branch string `protobuf:"bytes,2,opt,name=branch,proto3" json:"branch,omitempty"`
+	// dry_run computes the changes without making them
+	DryRun bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// token authorizes the sync and must be one of the admin tokens configured for werft
+	Token                string   `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncRequiredChecksRequest) Reset()         { *m = SyncRequiredChecksRequest{} }
+func (m *SyncRequiredChecksRequest) String() string { return proto.CompactTextString(m) }
+func (*SyncRequiredChecksRequest) ProtoMessage()    {}
+func (*SyncRequiredChecksRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{111}
+}
+
+func (m *SyncRequiredChecksRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncRequiredChecksRequest.Unmarshal(m, b)
+}
+func (m *SyncRequiredChecksRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncRequiredChecksRequest.Marshal(b, m, deterministic)
+}
+func (m *SyncRequiredChecksRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncRequiredChecksRequest.Merge(m, src)
+}
+func (m *SyncRequiredChecksRequest) XXX_Size() int {
+	return xxx_messageInfo_SyncRequiredChecksRequest.Size(m)
+}
+func (m *SyncRequiredChecksRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncRequiredChecksRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncRequiredChecksRequest proto.InternalMessageInfo
+
+func (m *SyncRequiredChecksRequest) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *SyncRequiredChecksRequest) GetBranch() string {
+	if m != nil {
+		return m.Branch
+	}
+	return ""
+}
+
+func (m *SyncRequiredChecksRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *SyncRequiredChecksRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type SyncRequiredChecksResponse struct {
+	// contexts are the required status checks of the branch after the sync
+	Contexts             []string `protobuf:"bytes,1,rep,name=contexts,proto3" json:"contexts,omitempty"`
+	Added                []string `protobuf:"bytes,2,rep,name=added,proto3" json:"added,omitempty"`
+	Removed              []string `protobuf:"bytes,3,rep,name=removed,proto3" json:"removed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncRequiredChecksResponse) Reset()         { *m = SyncRequiredChecksResponse{} }
+func (m *SyncRequiredChecksResponse) String() string { return proto.CompactTextString(m) }
+func (*SyncRequiredChecksResponse) ProtoMessage()    {}
+func (*SyncRequiredChecksResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{112}
+}
+
+func (m *SyncRequiredChecksResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncRequiredChecksResponse.Unmarshal(m, b)
+}
+func (m *SyncRequiredChecksResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncRequiredChecksResponse.Marshal(b, m, deterministic)
+}
+func (m *SyncRequiredChecksResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncRequiredChecksResponse.Merge(m, src)
+}
+func (m *SyncRequiredChecksResponse) XXX_Size() int {
+	return xxx_messageInfo_SyncRequiredChecksResponse.Size(m)
+}
+func (m *SyncRequiredChecksResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncRequiredChecksResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncRequiredChecksResponse proto.InternalMessageInfo
+
+func (m *SyncRequiredChecksResponse) GetContexts() []string {
+	if m != nil {
+		return m.Contexts
+	}
+	return nil
+}
+
+func (m *SyncRequiredChecksResponse) GetAdded() []string {
+	if m != nil {
+		return m.Added
+	}
+	return nil
+}
+
+func (m *SyncRequiredChecksResponse) GetRemoved() []string {
+	if m != nil {
+		return m.Removed
+	}
+	return nil
+}
+
+type Schedule struct {
+	// name identifies the schedule within its scheduler
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// spec is the cron spec, e.g. "0 3 * * *" or "@every 1h"
+	Spec string `protobuf:"bytes,2,opt,name=spec,proto3" json:"spec,omitempty"`
+	// timezone is the time zone the spec is evaluated in, e.g. Europe/Berlin. Defaults to the time zone werft is configured with.
+	Timezone   string      `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Repository *Repository `protobuf:"bytes,4,opt,name=repository,proto3" json:"repository,omitempty"`
+	JobPath    string      `protobuf:"bytes,5,opt,name=job_path,json=jobPath,proto3" json:"job_path,omitempty"`
+	// scheduler is the scheduler which registered the schedule, e.g. cron
+	Scheduler string `protobuf:"bytes,6,opt,name=scheduler,proto3" json:"scheduler,omitempty"`
+	// next_runs are the next times the schedule starts the job, in UTC. These are only set by ListSchedules.
+	NextRuns             []*timestamp.Timestamp `protobuf:"bytes,7,rep,name=next_runs,json=nextRuns,proto3" json:"next_runs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *Schedule) Reset()         { *m = Schedule{} }
+func (m *Schedule) String() string { return proto.CompactTextString(m) }
+func (*Schedule) ProtoMessage()    {}
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{113}
+}
+
+func (m *Schedule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schedule.Unmarshal(m, b)
+}
+func (m *Schedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schedule.Marshal(b, m, deterministic)
+}
+func (m *Schedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schedule.Merge(m, src)
+}
+func (m *Schedule) XXX_Size() int {
+	return xxx_messageInfo_Schedule.Size(m)
+}
+func (m *Schedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schedule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schedule proto.InternalMessageInfo
+
+func (m *Schedule) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Schedule) GetSpec() string {
+	if m != nil {
+		return m.Spec
+	}
+	return ""
+}
+
+func (m *Schedule) GetTimezone() string {
+	if m != nil {
+		return m.Timezone
+	}
+	return ""
+}
+
+func (m *Schedule) GetRepository() *Repository {
+	if m != nil {
+		return m.Repository
+	}
+	return nil
+}
+
+func (m *Schedule) GetJobPath() string {
+	if m != nil {
+		return m.JobPath
+	}
+	return ""
+}
+
+func (m *Schedule) GetScheduler() string {
+	if m != nil {
+		return m.Scheduler
+	}
+	return ""
+}
+
+func (m *Schedule) GetNextRuns() []*timestamp.Timestamp {
+	if m != nil {
+		return m.NextRuns
+	}
+	return nil
+}
+
+type RegisterSchedulesRequest struct {
+	// scheduler identifies the scheduler, e.g. cron
+	Scheduler            string      `protobuf:"bytes,1,opt,name=scheduler,proto3" json:"scheduler,omitempty"`
+	Schedules            []*Schedule `protobuf:"bytes,2,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *RegisterSchedulesRequest) Reset()         { *m = RegisterSchedulesRequest{} }
+func (m *RegisterSchedulesRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterSchedulesRequest) ProtoMessage()    {}
+func (*RegisterSchedulesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{114}
+}
+
+func (m *RegisterSchedulesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterSchedulesRequest.Unmarshal(m, b)
+}
+func (m *RegisterSchedulesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterSchedulesRequest.Marshal(b, m, deterministic)
+}
+func (m *RegisterSchedulesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterSchedulesRequest.Merge(m, src)
+}
+func (m *RegisterSchedulesRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterSchedulesRequest.Size(m)
+}
+func (m *RegisterSchedulesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterSchedulesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterSchedulesRequest proto.InternalMessageInfo
+
+func (m *RegisterSchedulesRequest) GetScheduler() string {
+	if m != nil {
+		return m.Scheduler
+	}
+	return ""
+}
+
+func (m *RegisterSchedulesRequest) GetSchedules() []*Schedule {
+	if m != nil {
+		return m.Schedules
+	}
+	return nil
+}
+
+type RegisterSchedulesResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterSchedulesResponse) Reset()         { *m = RegisterSchedulesResponse{} }
+func (m *RegisterSchedulesResponse) String() string { return proto.CompactTextString(m) }
+func (*RegisterSchedulesResponse) ProtoMessage()    {}
+func (*RegisterSchedulesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{115}
+}
+
+func (m *RegisterSchedulesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterSchedulesResponse.Unmarshal(m, b)
+}
+func (m *RegisterSchedulesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterSchedulesResponse.Marshal(b, m, deterministic)
+}
+func (m *RegisterSchedulesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterSchedulesResponse.Merge(m, src)
+}
+func (m *RegisterSchedulesResponse) XXX_Size() int {
+	return xxx_messageInfo_RegisterSchedulesResponse.Size(m)
+}
+func (m *RegisterSchedulesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterSchedulesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterSchedulesResponse proto.InternalMessageInfo
+
+type ListSchedulesRequest struct {
+	// runs is the number of next runs to list per schedule. Defaults to 5, and is at most 100.
+	Runs                 int32    `protobuf:"varint,1,opt,name=runs,proto3" json:"runs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListSchedulesRequest) Reset()         { *m = ListSchedulesRequest{} }
+func (m *ListSchedulesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListSchedulesRequest) ProtoMessage()    {}
+func (*ListSchedulesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{116}
+}
+
+func (m *ListSchedulesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchedulesRequest.Unmarshal(m, b)
+}
+func (m *ListSchedulesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchedulesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListSchedulesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchedulesRequest.Merge(m, src)
+}
+func (m *ListSchedulesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListSchedulesRequest.Size(m)
+}
+func (m *ListSchedulesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchedulesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchedulesRequest proto.InternalMessageInfo
+
+func (m *ListSchedulesRequest) GetRuns() int32 {
+	if m != nil {
+		return m.Runs
+	}
+	return 0
+}
+
+type ListSchedulesResponse struct {
+	Schedules            []*Schedule `protobuf:"bytes,1,rep,name=schedules,proto3" json:"schedules,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListSchedulesResponse) Reset()         { *m = ListSchedulesResponse{} }
+func (m *ListSchedulesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListSchedulesResponse) ProtoMessage()    {}
+func (*ListSchedulesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{117}
+}
+
+func (m *ListSchedulesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListSchedulesResponse.Unmarshal(m, b)
+}
+func (m *ListSchedulesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListSchedulesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListSchedulesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListSchedulesResponse.Merge(m, src)
+}
+func (m *ListSchedulesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListSchedulesResponse.Size(m)
+}
+func (m *ListSchedulesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListSchedulesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListSchedulesResponse proto.InternalMessageInfo
+
+func (m *ListSchedulesResponse) GetSchedules() []*Schedule {
+	if m != nil {
+		return m.Schedules
+	}
+	return nil
+}
+
+type GetRuntimeStatsRequest struct {
+	// token is one of the admin tokens configured for werft
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// goroutine_dump includes the stack traces of all goroutines
+	GoroutineDump        bool     `protobuf:"varint,2,opt,name=goroutine_dump,json=goroutineDump,proto3" json:"goroutine_dump,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRuntimeStatsRequest) Reset()         { *m = GetRuntimeStatsRequest{} }
+func (m *GetRuntimeStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRuntimeStatsRequest) ProtoMessage()    {}
+func (*GetRuntimeStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{118}
+}
+
+func (m *GetRuntimeStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRuntimeStatsRequest.Unmarshal(m, b)
+}
+func (m *GetRuntimeStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRuntimeStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRuntimeStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRuntimeStatsRequest.Merge(m, src)
+}
+func (m *GetRuntimeStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRuntimeStatsRequest.Size(m)
+}
+func (m *GetRuntimeStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRuntimeStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRuntimeStatsRequest proto.InternalMessageInfo
+
+func (m *GetRuntimeStatsRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *GetRuntimeStatsRequest) GetGoroutineDump() bool {
+	if m != nil {
+		return m.GoroutineDump
+	}
+	return false
+}
+
+type GetRuntimeStatsResponse struct {
+	// started is when the werft server started
+	Started        *timestamp.Timestamp `protobuf:"bytes,1,opt,name=started,proto3" json:"started,omitempty"`
+	GoVersion      string               `protobuf:"bytes,2,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	Goroutines     int32                `protobuf:"varint,3,opt,name=goroutines,proto3" json:"goroutines,omitempty"`
+	HeapAllocBytes uint64               `protobuf:"varint,4,opt,name=heap_alloc_bytes,json=heapAllocBytes,proto3" json:"heap_alloc_bytes,omitempty"`
+	HeapObjects    uint64               `protobuf:"varint,5,opt,name=heap_objects,json=heapObjects,proto3" json:"heap_objects,omitempty"`
+	// sys_bytes is the memory werft obtained from the operating system
+	SysBytes       uint64 `protobuf:"varint,6,opt,name=sys_bytes,json=sysBytes,proto3" json:"sys_bytes,omitempty"`
+	GcCycles       uint32 `protobuf:"varint,7,opt,name=gc_cycles,json=gcCycles,proto3" json:"gc_cycles,omitempty"`
+	GcPauseTotalNs uint64 `protobuf:"varint,8,opt,name=gc_pause_total_ns,json=gcPauseTotalNs,proto3" json:"gc_pause_total_ns,omitempty"`
+	// buffers are the sizes of werft's internal queues and buffers by name, e.g. queued_jobs or job_batch_pending
+	Buffers map[string]int64 `protobuf:"bytes,9,rep,name=buffers,proto3" json:"buffers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// goroutine_dump are the stack traces of all goroutines if the request asked for them
+	GoroutineDump        string   `protobuf:"bytes,10,opt,name=goroutine_dump,json=goroutineDump,proto3" json:"goroutine_dump,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetRuntimeStatsResponse) Reset()         { *m = GetRuntimeStatsResponse{} }
+func (m *GetRuntimeStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRuntimeStatsResponse) ProtoMessage()    {}
+func (*GetRuntimeStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{119}
+}
+
+func (m *GetRuntimeStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRuntimeStatsResponse.Unmarshal(m, b)
+}
+func (m *GetRuntimeStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRuntimeStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRuntimeStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRuntimeStatsResponse.Merge(m, src)
+}
+func (m *GetRuntimeStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRuntimeStatsResponse.Size(m)
+}
+func (m *GetRuntimeStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRuntimeStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRuntimeStatsResponse proto.InternalMessageInfo
+
+func (m *GetRuntimeStatsResponse) GetStarted() *timestamp.Timestamp {
+	if m != nil {
+		return m.Started
+	}
+	return nil
+}
+
+func (m *GetRuntimeStatsResponse) GetGoVersion() string {
+	if m != nil {
+		return m.GoVersion
+	}
+	return ""
+}
+
+func (m *GetRuntimeStatsResponse) GetGoroutines() int32 {
+	if m != nil {
+		return m.Goroutines
+	}
+	return 0
+}
+
+func (m *GetRuntimeStatsResponse) GetHeapAllocBytes() uint64 {
+	if m != nil {
+		return m.HeapAllocBytes
+	}
+	return 0
+}
+
+func (m *GetRuntimeStatsResponse) GetHeapObjects() uint64 {
+	if m != nil {
+		return m.HeapObjects
+	}
+	return 0
+}
+
+func (m *GetRuntimeStatsResponse) GetSysBytes() uint64 {
+	if m != nil {
+		return m.SysBytes
+	}
+	return 0
+}
+
+func (m *GetRuntimeStatsResponse) GetGcCycles() uint32 {
+	if m != nil {
+		return m.GcCycles
+	}
+	return 0
+}
+
+func (m *GetRuntimeStatsResponse) GetGcPauseTotalNs() uint64 {
+	if m != nil {
+		return m.GcPauseTotalNs
+	}
+	return 0
+}
+
+func (m *GetRuntimeStatsResponse) GetBuffers() map[string]int64 {
+	if m != nil {
+		return m.Buffers
+	}
+	return nil
+}
+
+func (m *GetRuntimeStatsResponse) GetGoroutineDump() string {
+	if m != nil {
+		return m.GoroutineDump
+	}
+	return ""
+}
+
+type JobEnvironment struct {
+	// node is the name of the node the job's pod ran on
+	Node                 string                  `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	KernelVersion        string                  `protobuf:"bytes,2,opt,name=kernel_version,json=kernelVersion,proto3" json:"kernel_version,omitempty"`
+	OsImage              string                  `protobuf:"bytes,3,opt,name=os_image,json=osImage,proto3" json:"os_image,omitempty"`
+	ContainerRuntime     string                  `protobuf:"bytes,4,opt,name=container_runtime,json=containerRuntime,proto3" json:"container_runtime,omitempty"`
+	KubeletVersion       string                  `protobuf:"bytes,5,opt,name=kubelet_version,json=kubeletVersion,proto3" json:"kubelet_version,omitempty"`
+	Containers           []*ContainerEnvironment `protobuf:"bytes,6,rep,name=containers,proto3" json:"containers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *JobEnvironment) Reset()         { *m = JobEnvironment{} }
+func (m *JobEnvironment) String() string { return proto.CompactTextString(m) }
+func (*JobEnvironment) ProtoMessage()    {}
+func (*JobEnvironment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{120}
+}
+
+func (m *JobEnvironment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobEnvironment.Unmarshal(m, b)
+}
+func (m *JobEnvironment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobEnvironment.Marshal(b, m, deterministic)
+}
+func (m *JobEnvironment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobEnvironment.Merge(m, src)
+}
+func (m *JobEnvironment) XXX_Size() int {
+	return xxx_messageInfo_JobEnvironment.Size(m)
+}
+func (m *JobEnvironment) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobEnvironment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobEnvironment proto.InternalMessageInfo
+
+func (m *JobEnvironment) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *JobEnvironment) GetKernelVersion() string {
+	if m != nil {
+		return m.KernelVersion
+	}
+	return ""
+}
+
+func (m *JobEnvironment) GetOsImage() string {
+	if m != nil {
+		return m.OsImage
+	}
+	return ""
+}
+
+func (m *JobEnvironment) GetContainerRuntime() string {
+	if m != nil {
+		return m.ContainerRuntime
+	}
+	return ""
+}
+
+func (m *JobEnvironment) GetKubeletVersion() string {
+	if m != nil {
+		return m.KubeletVersion
+	}
+	return ""
+}
+
+func (m *JobEnvironment) GetContainers() []*ContainerEnvironment {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+type ContainerEnvironment struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// image is the image as the job spec names it, e.g. golang:1.14
+	Image string `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	// image_id identifies the image the container ran, including its digest. It's empty until the container started.
+	ImageId string `protobuf:"bytes,3,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	// env are the environment variables of the container with a literal value, including those werft sets.
+	// The values of variables whose name contains "secret" are redacted.
+	Env                  map[string]string `protobuf:"bytes,4,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *ContainerEnvironment) Reset()         { *m = ContainerEnvironment{} }
+func (m *ContainerEnvironment) String() string { return proto.CompactTextString(m) }
+func (*ContainerEnvironment) ProtoMessage()    {}
+func (*ContainerEnvironment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{121}
+}
+
+func (m *ContainerEnvironment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ContainerEnvironment.Unmarshal(m, b)
+}
+func (m *ContainerEnvironment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ContainerEnvironment.Marshal(b, m, deterministic)
+}
+func (m *ContainerEnvironment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ContainerEnvironment.Merge(m, src)
+}
+func (m *ContainerEnvironment) XXX_Size() int {
+	return xxx_messageInfo_ContainerEnvironment.Size(m)
+}
+func (m *ContainerEnvironment) XXX_DiscardUnknown() {
+	xxx_messageInfo_ContainerEnvironment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ContainerEnvironment proto.InternalMessageInfo
+
+func (m *ContainerEnvironment) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ContainerEnvironment) GetImage() string {
+	if m != nil {
+		return m.Image
+	}
+	return ""
+}
+
+func (m *ContainerEnvironment) GetImageId() string {
+	if m != nil {
+		return m.ImageId
+	}
+	return ""
+}
+
+func (m *ContainerEnvironment) GetEnv() map[string]string {
+	if m != nil {
+		return m.Env
+	}
+	return nil
+}
+
+type DiffJobEnvironmentsRequest struct {
+	From                 string   `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiffJobEnvironmentsRequest) Reset()         { *m = DiffJobEnvironmentsRequest{} }
+func (m *DiffJobEnvironmentsRequest) String() string { return proto.CompactTextString(m) }
+func (*DiffJobEnvironmentsRequest) ProtoMessage()    {}
+func (*DiffJobEnvironmentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{122}
+}
+
+func (m *DiffJobEnvironmentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffJobEnvironmentsRequest.Unmarshal(m, b)
+}
+func (m *DiffJobEnvironmentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffJobEnvironmentsRequest.Marshal(b, m, deterministic)
+}
+func (m *DiffJobEnvironmentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffJobEnvironmentsRequest.Merge(m, src)
+}
+func (m *DiffJobEnvironmentsRequest) XXX_Size() int {
+	return xxx_messageInfo_DiffJobEnvironmentsRequest.Size(m)
+}
+func (m *DiffJobEnvironmentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffJobEnvironmentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffJobEnvironmentsRequest proto.InternalMessageInfo
+
+func (m *DiffJobEnvironmentsRequest) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *DiffJobEnvironmentsRequest) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type DiffJobEnvironmentsResponse struct {
+	From *JobEnvironment `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *JobEnvironment `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// changes lists what's different between the two environments. It's empty if they're the same.
+	Changes              []*JobEnvironmentChange `protobuf:"bytes,3,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *DiffJobEnvironmentsResponse) Reset()         { *m = DiffJobEnvironmentsResponse{} }
+func (m *DiffJobEnvironmentsResponse) String() string { return proto.CompactTextString(m) }
+func (*DiffJobEnvironmentsResponse) ProtoMessage()    {}
+func (*DiffJobEnvironmentsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{123}
+}
+
+func (m *DiffJobEnvironmentsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiffJobEnvironmentsResponse.Unmarshal(m, b)
+}
+func (m *DiffJobEnvironmentsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiffJobEnvironmentsResponse.Marshal(b, m, deterministic)
+}
+func (m *DiffJobEnvironmentsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiffJobEnvironmentsResponse.Merge(m, src)
+}
+func (m *DiffJobEnvironmentsResponse) XXX_Size() int {
+	return xxx_messageInfo_DiffJobEnvironmentsResponse.Size(m)
+}
+func (m *DiffJobEnvironmentsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiffJobEnvironmentsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiffJobEnvironmentsResponse proto.InternalMessageInfo
+
+func (m *DiffJobEnvironmentsResponse) GetFrom() *JobEnvironment {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *DiffJobEnvironmentsResponse) GetTo() *JobEnvironment {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *DiffJobEnvironmentsResponse) GetChanges() []*JobEnvironmentChange {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type JobEnvironmentChange struct {
+	// field names what changed, e.g. kernel_version, containers.build.image_id or containers.build.env.GOPROXY
+	Field                string   `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	From                 string   `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To                   string   `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobEnvironmentChange) Reset()         { *m = JobEnvironmentChange{} }
+func (m *JobEnvironmentChange) String() string { return proto.CompactTextString(m) }
+func (*JobEnvironmentChange) ProtoMessage()    {}
+func (*JobEnvironmentChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{124}
+}
+
+func (m *JobEnvironmentChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobEnvironmentChange.Unmarshal(m, b)
+}
+func (m *JobEnvironmentChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobEnvironmentChange.Marshal(b, m, deterministic)
+}
+func (m *JobEnvironmentChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobEnvironmentChange.Merge(m, src)
+}
+func (m *JobEnvironmentChange) XXX_Size() int {
+	return xxx_messageInfo_JobEnvironmentChange.Size(m)
+}
+func (m *JobEnvironmentChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobEnvironmentChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobEnvironmentChange proto.InternalMessageInfo
+
+func (m *JobEnvironmentChange) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+func (m *JobEnvironmentChange) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *JobEnvironmentChange) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+type JobLogSummary struct {
+	// slices are in the order they first logged
+	Slices []*LogSliceSummary `protobuf:"bytes,1,rep,name=slices,proto3" json:"slices,omitempty"`
+	// first_error is an excerpt of the log starting at the first line classified as error
+	FirstError string `protobuf:"bytes,2,opt,name=first_error,json=firstError,proto3" json:"first_error,omitempty"`
+	// first_error_slice names the slice the first error was logged in
+	FirstErrorSlice      string   `protobuf:"bytes,3,opt,name=first_error_slice,json=firstErrorSlice,proto3" json:"first_error_slice,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *JobLogSummary) Reset()         { *m = JobLogSummary{} }
+func (m *JobLogSummary) String() string { return proto.CompactTextString(m) }
+func (*JobLogSummary) ProtoMessage()    {}
+func (*JobLogSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{125}
+}
+
+func (m *JobLogSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_JobLogSummary.Unmarshal(m, b)
+}
+func (m *JobLogSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_JobLogSummary.Marshal(b, m, deterministic)
+}
+func (m *JobLogSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_JobLogSummary.Merge(m, src)
+}
+func (m *JobLogSummary) XXX_Size() int {
+	return xxx_messageInfo_JobLogSummary.Size(m)
+}
+func (m *JobLogSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_JobLogSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_JobLogSummary proto.InternalMessageInfo
+
+func (m *JobLogSummary) GetSlices() []*LogSliceSummary {
+	if m != nil {
+		return m.Slices
+	}
+	return nil
+}
+
+func (m *JobLogSummary) GetFirstError() string {
+	if m != nil {
+		return m.FirstError
+	}
+	return ""
+}
+
+func (m *JobLogSummary) GetFirstErrorSlice() string {
+	if m != nil {
+		return m.FirstErrorSlice
+	}
+	return ""
+}
+
+type LogSliceSummary struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Errors               int64    `protobuf:"varint,2,opt,name=errors,proto3" json:"errors,omitempty"`
+	Warnings             int64    `protobuf:"varint,3,opt,name=warnings,proto3" json:"warnings,omitempty"`
+	Info                 int64    `protobuf:"varint,4,opt,name=info,proto3" json:"info,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogSliceSummary) Reset()         { *m = LogSliceSummary{} }
+func (m *LogSliceSummary) String() string { return proto.CompactTextString(m) }
+func (*LogSliceSummary) ProtoMessage()    {}
+func (*LogSliceSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9fe744feedd6d332, []int{126}
+}
+
+func (m *LogSliceSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogSliceSummary.Unmarshal(m, b)
+}
+func (m *LogSliceSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogSliceSummary.Marshal(b, m, deterministic)
+}
+func (m *LogSliceSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogSliceSummary.Merge(m, src)
+}
+func (m *LogSliceSummary) XXX_Size() int {
+	return xxx_messageInfo_LogSliceSummary.Size(m)
+}
+func (m *LogSliceSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogSliceSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogSliceSummary proto.InternalMessageInfo
+
+func (m *LogSliceSummary) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LogSliceSummary) GetErrors() int64 {
+	if m != nil {
+		return m.Errors
+	}
+	return 0
+}
+
+func (m *LogSliceSummary) GetWarnings() int64 {
+	if m != nil {
+		return m.Warnings
+	}
+	return 0
+}
+
+func (m *LogSliceSummary) GetInfo() int64 {
+	if m != nil {
+		return m.Info
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("v1.JobView", JobView_name, JobView_value)
+	proto.RegisterEnum("v1.FilterOp", FilterOp_name, FilterOp_value)
+	proto.RegisterEnum("v1.ListenRequestLogs", ListenRequestLogs_name, ListenRequestLogs_value)
+	proto.RegisterEnum("v1.JobTrigger", JobTrigger_name, JobTrigger_value)
+	proto.RegisterEnum("v1.JobPhase", JobPhase_name, JobPhase_value)
+	proto.RegisterEnum("v1.JobEventType", JobEventType_name, JobEventType_value)
+	proto.RegisterEnum("v1.LogSliceType", LogSliceType_name, LogSliceType_value)
+	proto.RegisterEnum("v1.WaitReason", WaitReason_name, WaitReason_value)
+	proto.RegisterEnum("v1.ExportFormat", ExportFormat_name, ExportFormat_value)
+	proto.RegisterEnum("v1.CostGrouping", CostGrouping_name, CostGrouping_value)
+	proto.RegisterEnum("v1.BatchJobOutcome", BatchJobOutcome_name, BatchJobOutcome_value)
+	proto.RegisterType((*StartLocalJobRequest)(nil), "v1.StartLocalJobRequest")
+	proto.RegisterType((*StartJobResponse)(nil), "v1.StartJobResponse")
+	proto.RegisterType((*StartGitHubJobRequest)(nil), "v1.StartGitHubJobRequest")
+	proto.RegisterType((*StartFromPreviousJobRequest)(nil), "v1.StartFromPreviousJobRequest")
+	proto.RegisterType((*ListJobsRequest)(nil), "v1.ListJobsRequest")
+	proto.RegisterType((*FilterExpression)(nil), "v1.FilterExpression")
+	proto.RegisterType((*FilterTerm)(nil), "v1.FilterTerm")
+	proto.RegisterType((*OrderExpression)(nil), "v1.OrderExpression")
+	proto.RegisterType((*ListJobsResponse)(nil), "v1.ListJobsResponse")
+	proto.RegisterType((*SubscribeRequest)(nil), "v1.SubscribeRequest")
+	proto.RegisterType((*SubscribeResponse)(nil), "v1.SubscribeResponse")
+	proto.RegisterType((*GetJobRequest)(nil), "v1.GetJobRequest")
+	proto.RegisterType((*GetJobResponse)(nil), "v1.GetJobResponse")
+	proto.RegisterType((*ListenRequest)(nil), "v1.ListenRequest")
+	proto.RegisterType((*ListenResponse)(nil), "v1.ListenResponse")
+	proto.RegisterType((*JobStatus)(nil), "v1.JobStatus")
+	proto.RegisterType((*JobNote)(nil), "v1.JobNote")
+	proto.RegisterType((*DurationEstimate)(nil), "v1.DurationEstimate")
+	proto.RegisterType((*ResourceUsage)(nil), "v1.ResourceUsage")
+	proto.RegisterType((*JobMetadata)(nil), "v1.JobMetadata")
+	proto.RegisterMapType((map[string]string)(nil), "v1.JobMetadata.LabelsEntry")
+	proto.RegisterType((*CommitRange)(nil), "v1.CommitRange")
+	proto.RegisterType((*MergeGroup)(nil), "v1.MergeGroup")
+	proto.RegisterType((*Commit)(nil), "v1.Commit")
+	proto.RegisterType((*Repository)(nil), "v1.Repository")
+	proto.RegisterType((*Annotation)(nil), "v1.Annotation")
+	proto.RegisterType((*JobEvent)(nil), "v1.JobEvent")
+	proto.RegisterType((*JobConditions)(nil), "v1.JobConditions")
+	proto.RegisterType((*JobResult)(nil), "v1.JobResult")
+	proto.RegisterType((*LogSliceEvent)(nil), "v1.LogSliceEvent")
+	proto.RegisterType((*StopJobRequest)(nil), "v1.StopJobRequest")
+	proto.RegisterType((*StopJobResponse)(nil), "v1.StopJobResponse")
+	proto.RegisterType((*AttachJobRequest)(nil), "v1.AttachJobRequest")
+	proto.RegisterType((*AttachJobStart)(nil), "v1.AttachJobStart")
+	proto.RegisterType((*TerminalSize)(nil), "v1.TerminalSize")
+	proto.RegisterType((*AttachJobResponse)(nil), "v1.AttachJobResponse")
+	proto.RegisterType((*DeadLetter)(nil), "v1.DeadLetter")
+	proto.RegisterType((*ListDeadLettersRequest)(nil), "v1.ListDeadLettersRequest")
+	proto.RegisterType((*ListDeadLettersResponse)(nil), "v1.ListDeadLettersResponse")
+	proto.RegisterType((*ReplayDeadLetterRequest)(nil), "v1.ReplayDeadLetterRequest")
+	proto.RegisterType((*ReplayDeadLetterResponse)(nil), "v1.ReplayDeadLetterResponse")
+	proto.RegisterType((*GetQueueStatusRequest)(nil), "v1.GetQueueStatusRequest")
+	proto.RegisterType((*GetQueueStatusResponse)(nil), "v1.GetQueueStatusResponse")
+	proto.RegisterType((*QueuedJob)(nil), "v1.QueuedJob")
+	proto.RegisterType((*SetMaintenanceModeRequest)(nil), "v1.SetMaintenanceModeRequest")
+	proto.RegisterType((*SetMaintenanceModeResponse)(nil), "v1.SetMaintenanceModeResponse")
+	proto.RegisterType((*GetMaintenanceModeRequest)(nil), "v1.GetMaintenanceModeRequest")
+	proto.RegisterType((*GetMaintenanceModeResponse)(nil), "v1.GetMaintenanceModeResponse")
+	proto.RegisterType((*MaintenanceMode)(nil), "v1.MaintenanceMode")
+	proto.RegisterType((*GetFlakyJobsRequest)(nil), "v1.GetFlakyJobsRequest")
+	proto.RegisterType((*GetFlakyJobsResponse)(nil), "v1.GetFlakyJobsResponse")
+	proto.RegisterType((*FlakyJob)(nil), "v1.FlakyJob")
+	proto.RegisterType((*ExportJobsRequest)(nil), "v1.ExportJobsRequest")
+	proto.RegisterType((*ExportJobsResponse)(nil), "v1.ExportJobsResponse")
+	proto.RegisterType((*DiffJobSpecsRequest)(nil), "v1.DiffJobSpecsRequest")
+	proto.RegisterType((*DiffJobSpecsResponse)(nil), "v1.DiffJobSpecsResponse")
+	proto.RegisterType((*GetJobProvenanceRequest)(nil), "v1.GetJobProvenanceRequest")
+	proto.RegisterType((*GetJobProvenanceResponse)(nil), "v1.GetJobProvenanceResponse")
+	proto.RegisterType((*ImageBuild)(nil), "v1.ImageBuild")
+	proto.RegisterType((*FindImageBuildsRequest)(nil), "v1.FindImageBuildsRequest")
+	proto.RegisterType((*FindImageBuildsResponse)(nil), "v1.FindImageBuildsResponse")
+	proto.RegisterType((*GetVersionRequest)(nil), "v1.GetVersionRequest")
+	proto.RegisterType((*GetVersionResponse)(nil), "v1.GetVersionResponse")
+	proto.RegisterType((*GetJobEventsRequest)(nil), "v1.GetJobEventsRequest")
+	proto.RegisterType((*GetJobEventsResponse)(nil), "v1.GetJobEventsResponse")
+	proto.RegisterType((*Project)(nil), "v1.Project")
+	proto.RegisterType((*ListProjectsRequest)(nil), "v1.ListProjectsRequest")
+	proto.RegisterType((*ListProjectsResponse)(nil), "v1.ListProjectsResponse")
+	proto.RegisterType((*GetProjectHealthRequest)(nil), "v1.GetProjectHealthRequest")
+	proto.RegisterType((*RepositoryHealth)(nil), "v1.RepositoryHealth")
+	proto.RegisterType((*GetProjectHealthResponse)(nil), "v1.GetProjectHealthResponse")
+	proto.RegisterType((*GetQuotaUsageRequest)(nil), "v1.GetQuotaUsageRequest")
+	proto.RegisterType((*GetQuotaUsageResponse)(nil), "v1.GetQuotaUsageResponse")
+	proto.RegisterType((*QuotaUsage)(nil), "v1.QuotaUsage")
+	proto.RegisterType((*GetStartLatencyRequest)(nil), "v1.GetStartLatencyRequest")
+	proto.RegisterType((*GetStartLatencyResponse)(nil), "v1.GetStartLatencyResponse")
+	proto.RegisterType((*RepositoryStartLatency)(nil), "v1.RepositoryStartLatency")
+	proto.RegisterType((*JobStartLatency)(nil), "v1.JobStartLatency")
+	proto.RegisterType((*DeleteJobRequest)(nil), "v1.DeleteJobRequest")
+	proto.RegisterType((*DeleteJobResponse)(nil), "v1.DeleteJobResponse")
+	proto.RegisterType((*PurgeJobRequest)(nil), "v1.PurgeJobRequest")
+	proto.RegisterType((*PurgeJobResponse)(nil), "v1.PurgeJobResponse")
+	proto.RegisterType((*ApproveJobRequest)(nil), "v1.ApproveJobRequest")
+	proto.RegisterType((*ApproveJobResponse)(nil), "v1.ApproveJobResponse")
+	proto.RegisterType((*AddJobNoteRequest)(nil), "v1.AddJobNoteRequest")
+	proto.RegisterType((*AddJobNoteResponse)(nil), "v1.AddJobNoteResponse")
+	proto.RegisterType((*SetAnnouncementRequest)(nil), "v1.SetAnnouncementRequest")
+	proto.RegisterType((*SetAnnouncementResponse)(nil), "v1.SetAnnouncementResponse")
+	proto.RegisterType((*GetAnnouncementRequest)(nil), "v1.GetAnnouncementRequest")
+	proto.RegisterType((*GetAnnouncementResponse)(nil), "v1.GetAnnouncementResponse")
+	proto.RegisterType((*Announcement)(nil), "v1.Announcement")
+	proto.RegisterType((*GetLatestJobRequest)(nil), "v1.GetLatestJobRequest")
+	proto.RegisterType((*GetLatestJobResponse)(nil), "v1.GetLatestJobResponse")
+	proto.RegisterType((*GetBranchesRequest)(nil), "v1.GetBranchesRequest")
+	proto.RegisterType((*GetBranchesResponse)(nil), "v1.GetBranchesResponse")
+	proto.RegisterType((*Branch)(nil), "v1.Branch")
+	proto.RegisterType((*JobPod)(nil), "v1.JobPod")
+	proto.RegisterType((*JobCost)(nil), "v1.JobCost")
+	proto.RegisterType((*GetJobCostsRequest)(nil), "v1.GetJobCostsRequest")
+	proto.RegisterType((*GetJobCostsResponse)(nil), "v1.GetJobCostsResponse")
+	proto.RegisterType((*JobCostGroup)(nil), "v1.JobCostGroup")
+	proto.RegisterType((*TriggerPayload)(nil), "v1.TriggerPayload")
+	proto.RegisterType((*GetTriggerPayloadRequest)(nil), "v1.GetTriggerPayloadRequest")
+	proto.RegisterType((*GetTriggerPayloadResponse)(nil), "v1.GetTriggerPayloadResponse")
+	proto.RegisterType((*BatchJobsRequest)(nil), "v1.BatchJobsRequest")
+	proto.RegisterType((*BatchJobsResponse)(nil), "v1.BatchJobsResponse")
+	proto.RegisterType((*BatchJobResult)(nil), "v1.BatchJobResult")
+	proto.RegisterType((*GetJobSBOMRequest)(nil), "v1.GetJobSBOMRequest")
+	proto.RegisterType((*GetJobSBOMResponse)(nil), "v1.GetJobSBOMResponse")
+	proto.RegisterType((*JobSBOM)(nil), "v1.JobSBOM")
+	proto.RegisterType((*StreamEventsRequest)(nil), "v1.StreamEventsRequest")
+	proto.RegisterType((*ServerEvent)(nil), "v1.ServerEvent")
+	proto.RegisterMapType((map[string]string)(nil), "v1.ServerEvent.AttributesEntry")
+	proto.RegisterType((*SyncRequiredChecksRequest)(nil), "v1.SyncRequiredChecksRequest")
+	proto.RegisterType((*SyncRequiredChecksResponse)(nil), "v1.SyncRequiredChecksResponse")
+	proto.RegisterType((*Schedule)(nil), "v1.Schedule")
+	proto.RegisterType((*RegisterSchedulesRequest)(nil), "v1.RegisterSchedulesRequest")
+	proto.RegisterType((*RegisterSchedulesResponse)(nil), "v1.RegisterSchedulesResponse")
+	proto.RegisterType((*ListSchedulesRequest)(nil), "v1.ListSchedulesRequest")
+	proto.RegisterType((*ListSchedulesResponse)(nil), "v1.ListSchedulesResponse")
+	proto.RegisterType((*GetRuntimeStatsRequest)(nil), "v1.GetRuntimeStatsRequest")
+	proto.RegisterType((*GetRuntimeStatsResponse)(nil), "v1.GetRuntimeStatsResponse")
+	proto.RegisterMapType((map[string]int64)(nil), "v1.GetRuntimeStatsResponse.BuffersEntry")
+	proto.RegisterType((*JobEnvironment)(nil), "v1.JobEnvironment")
+	proto.RegisterType((*ContainerEnvironment)(nil), "v1.ContainerEnvironment")
+	proto.RegisterMapType((map[string]string)(nil), "v1.ContainerEnvironment.EnvEntry")
+	proto.RegisterType((*DiffJobEnvironmentsRequest)(nil), "v1.DiffJobEnvironmentsRequest")
+	proto.RegisterType((*DiffJobEnvironmentsResponse)(nil), "v1.DiffJobEnvironmentsResponse")
+	proto.RegisterType((*JobEnvironmentChange)(nil), "v1.JobEnvironmentChange")
+	proto.RegisterType((*JobLogSummary)(nil), "v1.JobLogSummary")
+	proto.RegisterType((*LogSliceSummary)(nil), "v1.LogSliceSummary")
+}
+
+func init() { proto.RegisterFile("werft.proto", fileDescriptor_9fe744feedd6d332) }
+
+var fileDescriptor_9fe744feedd6d332 = []byte{
+	// 6777 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x7c, 0x4b, 0x6c, 0x23, 0x49,
+	0x96, 0x58, 0x25, 0x3f, 0x22, 0xf9, 0x24, 0x51, 0x54, 0x96, 0x4a, 0xc5, 0x62, 0x55, 0x77, 0x55,
+	0xa7, 0xbb, 0xa7, 0xab, 0x35, 0xd3, 0x9a, 0xea, 0xea, 0xcf, 0x74, 0xf5, 0x4c, 0x4f, 0x0f, 0x45,
+	0xb1, 0x24, 0x55, 0x4b, 0xa2, 0x3a, 0x48, 0x55, 0x75, 0x8d, 0x81, 0x49, 0xa7, 0xc8, 0x10, 0x95,
+	0x5d, 0x64, 0x26, 0x27, 0x33, 0xa9, 0x2a, 0x0d, 0x0c, 0xc3, 0xf0, 0x61, 0x00, 0x1b, 0x36, 0xc6,
+	0xf0, 0xc1, 0x37, 0x0f, 0x30, 0x80, 0x4f, 0x06, 0xc6, 0x3e, 0x19, 0xb6, 0x6f, 0x3e, 0xfa, 0x60,
+	0x5f, 0x7c, 0xd8, 0xcb, 0x62, 0x81, 0x05, 0x16, 0xd8, 0xc1, 0x5e, 0x16, 0xbb, 0x97, 0xbd, 0x2d,
+	0xb0, 0x78, 0xf1, 0xcb, 0xc8, 0x64, 0xaa, 0x24, 0xf5, 0xf4, 0x9e, 0xc4, 0xf7, 0x89, 0x17, 0x11,
+	0x2f, 0x5e, 0x44, 0xbc, 0x78, 0xef, 0xa5, 0x60, 0xfe, 0x25, 0x0d, 0x8e, 0xa3, 0xf5, 0x49, 0xe0,
+	0x47, 0xbe, 0x99, 0x3b, 0xfd, 0xa0, 0x71, 0x77, 0xe8, 0xfb, 0xc3, 0x11, 0xfd, 0x21, 0xc3, 0x1c,
+	0x4d, 0x8f, 0x7f, 0x18, 0xb9, 0x63, 0x1a, 0x46, 0xce, 0x78, 0xc2, 0x99, 0xac, 0x3f, 0x18, 0xb0,
+	0xd2, 0x8d, 0x9c, 0x20, 0xda, 0xf5, 0xfb, 0xce, 0xe8, 0x89, 0x7f, 0x44, 0xe8, 0x2f, 0xa7, 0x34,
+	0x8c, 0xcc, 0xf7, 0xa1, 0x3c, 0xa6, 0x91, 0x33, 0x70, 0x22, 0xa7, 0x6e, 0xdc, 0x33, 0xee, 0xcf,
+	0x3f, 0x5c, 0x5a, 0x3f, 0xfd, 0x60, 0xfd, 0x89, 0x7f, 0xb4, 0x27, 0xd0, 0xdb, 0xd7, 0x88, 0x62,
+	0x31, 0xdf, 0x82, 0xf9, 0xbe, 0xef, 0x1d, 0xbb, 0x43, 0xfb, 0xcc, 0x19, 0x8f, 0xea, 0xb9, 0x7b,
+	0xc6, 0xfd, 0x85, 0xed, 0x6b, 0x04, 0x38, 0xf2, 0xb9, 0x33, 0x1e, 0x99, 0xb7, 0xa1, 0xfc, 0x8d,
+	0x7f, 0xc4, 0xe9, 0x79, 0x41, 0x2f, 0x7d, 0xe3, 0x1f, 0x31, 0xe2, 0x3b, 0xb0, 0xf8, 0xd2, 0x0f,
+	0x5e, 0x84, 0x13, 0xa7, 0x4f, 0xed, 0xc8, 0x09, 0xea, 0x05, 0xc1, 0xb1, 0xa0, 0xd0, 0x3d, 0x27,
+	0x30, 0xd7, 0xc1, 0x4c, 0xb0, 0xd9, 0x03, 0xdf, 0xa3, 0xf5, 0xe2, 0x3d, 0xe3, 0x7e, 0x79, 0xfb,
+	0x1a, 0xa9, 0xe9, 0xbc, 0x9b, 0xbe, 0x47, 0x37, 0x2a, 0x50, 0xea, 0xfb, 0x5e, 0x44, 0xbd, 0xc8,
+	0x7a, 0x04, 0x35, 0x36, 0x51, 0x36, 0xc7, 0x70, 0xe2, 0x7b, 0x21, 0x35, 0xdf, 0x81, 0xb9, 0x30,
+	0x72, 0xa2, 0x69, 0x28, 0xa6, 0xb8, 0x28, 0xa6, 0xd8, 0x65, 0x48, 0x22, 0x88, 0xd6, 0x7f, 0xcc,
+	0xc1, 0x0d, 0xd6, 0x76, 0xcb, 0x8d, 0xb6, 0xa7, 0x47, 0x9a, 0x96, 0xbe, 0x7f, 0xa1, 0x96, 0x34,
+	0x1d, 0xdd, 0xe2, 0x0a, 0x98, 0x38, 0xd1, 0x09, 0x53, 0x50, 0x85, 0x4d, 0xff, 0xc0, 0x89, 0x4e,
+	0xcc, 0x5b, 0x69, 0xdd, 0xc4, 0x9a, 0x79, 0x0b, 0x16, 0x86, 0x6e, 0x74, 0x32, 0x3d, 0xb2, 0x23,
+	0xff, 0x05, 0xf5, 0x98, 0x62, 0x2a, 0x64, 0x9e, 0xe3, 0x7a, 0x88, 0x32, 0x1b, 0x50, 0x0e, 0xdd,
+	0x01, 0x1d, 0xf9, 0xce, 0x80, 0xe9, 0x62, 0x81, 0x28, 0xd8, 0x7c, 0x04, 0xf0, 0xd2, 0x71, 0x23,
+	0x7b, 0xea, 0x45, 0xee, 0xa8, 0x3e, 0xc7, 0xc6, 0xd8, 0x58, 0xe7, 0x66, 0xb1, 0x2e, 0xcd, 0x62,
+	0xbd, 0x27, 0xcd, 0x82, 0x54, 0x90, 0xfb, 0x10, 0x99, 0xcd, 0x7b, 0xb0, 0x80, 0x83, 0x0a, 0x27,
+	0xb4, 0x6f, 0x07, 0xf4, 0xb8, 0x5e, 0x62, 0x3d, 0xc3, 0x37, 0xfe, 0x51, 0x77, 0x42, 0xfb, 0x84,
+	0x1e, 0x5b, 0xbf, 0x35, 0xe0, 0x36, 0x53, 0xcc, 0xe3, 0xc0, 0x1f, 0x1f, 0x04, 0xf4, 0xd4, 0xf5,
+	0xa7, 0xa1, 0xa6, 0x9e, 0xb7, 0x60, 0x61, 0x22, 0xb0, 0xf6, 0x37, 0xfe, 0x11, 0x53, 0x51, 0x85,
+	0xcc, 0x4f, 0x62, 0xce, 0x99, 0xe9, 0xe5, 0x66, 0xa7, 0x97, 0x9c, 0x42, 0xfe, 0x0a, 0x53, 0xb0,
+	0x7e, 0x97, 0x83, 0xa5, 0x5d, 0x37, 0xc4, 0x45, 0x0f, 0xe5, 0xa0, 0x7e, 0x00, 0x73, 0xc7, 0xee,
+	0x28, 0xa2, 0x41, 0xdd, 0xb8, 0x97, 0xbf, 0x3f, 0xff, 0x70, 0x05, 0x57, 0xec, 0x31, 0xc3, 0xb4,
+	0x5f, 0x4d, 0x02, 0x1a, 0x86, 0xae, 0xef, 0x11, 0xc1, 0x63, 0xbe, 0x07, 0x45, 0x3f, 0x18, 0xd0,
+	0xa0, 0x9e, 0x63, 0xcc, 0xd7, 0x91, 0xb9, 0x13, 0x0c, 0x12, 0xbc, 0x9c, 0xc3, 0x5c, 0x81, 0x62,
+	0x88, 0xca, 0x60, 0x43, 0x2c, 0x12, 0x0e, 0x20, 0x76, 0xe4, 0x8e, 0xdd, 0x88, 0x2d, 0x5c, 0x91,
+	0x70, 0xc0, 0x7c, 0x07, 0xaa, 0x23, 0xe7, 0x88, 0x8e, 0xec, 0x90, 0x8e, 0x68, 0x3f, 0xf2, 0x03,
+	0xb6, 0x70, 0x15, 0xb2, 0xc8, 0xb0, 0x5d, 0x81, 0x34, 0xef, 0x42, 0xe1, 0xd4, 0xa5, 0x2f, 0xd9,
+	0xba, 0x55, 0x1f, 0xce, 0x0b, 0xdb, 0x7a, 0xea, 0xd2, 0x97, 0x84, 0x11, 0xcc, 0x3a, 0x94, 0x26,
+	0x81, 0xff, 0x0d, 0xed, 0x47, 0x62, 0x79, 0x24, 0x68, 0xbe, 0x0b, 0x4b, 0xae, 0xd7, 0x1f, 0x4d,
+	0x07, 0xd4, 0x1e, 0xd0, 0x11, 0x8d, 0xe8, 0xa0, 0x5e, 0xc6, 0x7d, 0x42, 0xaa, 0x02, 0xbd, 0xc9,
+	0xb1, 0xd6, 0xa7, 0x50, 0x4b, 0xcf, 0xde, 0x7c, 0x1b, 0x8a, 0x11, 0x0d, 0xc6, 0xa1, 0x50, 0x51,
+	0x35, 0x56, 0x51, 0x8f, 0x06, 0x63, 0xc2, 0x89, 0xd6, 0x3f, 0x07, 0x88, 0x91, 0x38, 0xd1, 0x63,
+	0x97, 0x8e, 0x06, 0x62, 0x95, 0x39, 0x80, 0xd8, 0x53, 0x67, 0x34, 0xa5, 0x62, 0x61, 0x39, 0x60,
+	0xae, 0x41, 0xc5, 0x9f, 0xd0, 0xc0, 0x89, 0x5c, 0xdf, 0x63, 0xea, 0xaa, 0x3e, 0x5c, 0x88, 0xfb,
+	0xe8, 0x4c, 0x48, 0x4c, 0x36, 0x57, 0x61, 0xce, 0xa3, 0x43, 0x27, 0xa2, 0x4c, 0x83, 0x65, 0x22,
+	0x20, 0xab, 0x0d, 0x4b, 0xa9, 0x85, 0x38, 0x67, 0x08, 0x77, 0xa0, 0xe2, 0x84, 0x7d, 0xea, 0x0d,
+	0x5c, 0x6f, 0xc8, 0x86, 0x51, 0x26, 0x31, 0xc2, 0xea, 0x40, 0x2d, 0xb6, 0x10, 0x71, 0x2e, 0xac,
+	0x40, 0x31, 0xf2, 0x23, 0x67, 0xc4, 0xe4, 0x14, 0x09, 0x07, 0xf0, 0xb4, 0x08, 0x68, 0x38, 0x1d,
+	0x45, 0xc2, 0x16, 0xd2, 0xa7, 0x05, 0x27, 0x5a, 0x3f, 0x83, 0x5a, 0x77, 0x7a, 0x14, 0xf6, 0x03,
+	0xf7, 0x88, 0x7e, 0x2b, 0x9b, 0xb3, 0x3e, 0x83, 0x65, 0x4d, 0x42, 0x7c, 0x56, 0x89, 0xde, 0xb3,
+	0xcf, 0x2a, 0xd1, 0xfb, 0x10, 0x16, 0xb7, 0x68, 0xa4, 0xed, 0x41, 0x13, 0x0a, 0x9e, 0x33, 0xa6,
+	0x42, 0x25, 0xec, 0xf7, 0x65, 0x36, 0xdd, 0x5d, 0x98, 0x97, 0xe6, 0x33, 0xf1, 0x07, 0x6c, 0x8d,
+	0xca, 0x04, 0x04, 0xea, 0xc0, 0x1f, 0x58, 0x87, 0x50, 0x95, 0x1d, 0x5d, 0x69, 0x84, 0xe6, 0x1d,
+	0xc8, 0xa3, 0xc4, 0x1c, 0xe3, 0x01, 0xc1, 0x73, 0xe0, 0x0f, 0x08, 0xa2, 0xad, 0x3f, 0x31, 0x60,
+	0x11, 0xd7, 0x83, 0x7a, 0xaf, 0x9b, 0x40, 0x1d, 0x4a, 0xd3, 0xc9, 0xc0, 0x89, 0x68, 0x28, 0x16,
+	0x54, 0x82, 0xe6, 0x7b, 0x50, 0x18, 0xf9, 0xc3, 0x50, 0x18, 0xd5, 0x0d, 0x14, 0x9f, 0x10, 0xb7,
+	0xeb, 0x0f, 0x43, 0xc2, 0x58, 0xd0, 0xb0, 0xfc, 0xe3, 0xe3, 0x90, 0xf2, 0xad, 0x99, 0x27, 0x02,
+	0x62, 0xfb, 0x78, 0xe4, 0xf6, 0xa9, 0xd8, 0x92, 0x1c, 0x40, 0x85, 0x1c, 0x9d, 0x45, 0xd4, 0x16,
+	0x4d, 0xe6, 0x58, 0x13, 0x40, 0x54, 0x87, 0x37, 0x7b, 0x03, 0x18, 0x64, 0xf3, 0xdd, 0x5e, 0x62,
+	0xf4, 0x0a, 0x62, 0x76, 0x11, 0x61, 0xf9, 0x50, 0x95, 0x03, 0x11, 0xfa, 0x7a, 0x17, 0xe6, 0xf8,
+	0xa8, 0x33, 0xf5, 0xb5, 0x7d, 0x8d, 0x08, 0x32, 0x9e, 0x41, 0x7c, 0x40, 0x5c, 0x67, 0xcb, 0x6c,
+	0x52, 0xfe, 0xb0, 0x8b, 0xb8, 0xf6, 0x29, 0xf5, 0xa2, 0xed, 0x6b, 0x62, 0x94, 0xfa, 0x85, 0xf7,
+	0x2f, 0x0b, 0x50, 0x51, 0xd2, 0x32, 0xb5, 0xa8, 0xdf, 0x5e, 0xb9, 0x8b, 0x6e, 0x2f, 0x0b, 0x8a,
+	0x93, 0x13, 0x27, 0xa4, 0xfa, 0x76, 0xc5, 0x85, 0x43, 0x1c, 0xe1, 0x24, 0xf3, 0x03, 0xc0, 0x0b,
+	0x7f, 0xe0, 0xe2, 0xbe, 0x0d, 0xeb, 0x85, 0x78, 0xb4, 0x4f, 0xfc, 0xa3, 0x96, 0x22, 0x10, 0x8d,
+	0x09, 0x57, 0x72, 0x40, 0x23, 0xc7, 0x1d, 0x85, 0x42, 0xdd, 0x12, 0x34, 0xdf, 0x85, 0x12, 0xb7,
+	0x98, 0xb0, 0x3e, 0x97, 0xd8, 0x6f, 0x84, 0x61, 0x89, 0xa4, 0x9a, 0x9f, 0x42, 0x35, 0xa0, 0xa1,
+	0x3f, 0x0d, 0xfa, 0xd4, 0x9e, 0x86, 0xce, 0x90, 0xd6, 0x4b, 0x71, 0xcf, 0x44, 0x50, 0x0e, 0x91,
+	0x40, 0x16, 0x03, 0x1d, 0x34, 0x1f, 0x40, 0x99, 0x86, 0x91, 0x3b, 0xc6, 0x35, 0x28, 0xdf, 0x33,
+	0xe4, 0xc6, 0xdc, 0x9c, 0xf2, 0xa3, 0xa7, 0x2d, 0x68, 0x44, 0x71, 0x99, 0x6f, 0x41, 0xd1, 0xf3,
+	0xd1, 0xec, 0x2a, 0x6c, 0x48, 0xf2, 0x44, 0xde, 0xf7, 0x23, 0x4a, 0x38, 0x05, 0xcf, 0xec, 0xbe,
+	0x1f, 0x46, 0x75, 0xb8, 0x67, 0x68, 0x1c, 0x2d, 0x3f, 0x8c, 0x08, 0x23, 0x98, 0x1f, 0xc1, 0x3c,
+	0xf5, 0x4e, 0xdd, 0xc0, 0xf7, 0xc6, 0xd4, 0x8b, 0xea, 0xf3, 0x8c, 0xcf, 0x14, 0x7c, 0xed, 0x98,
+	0x42, 0x74, 0x36, 0xf3, 0x21, 0xcc, 0x8f, 0xfc, 0xa1, 0x1d, 0x4e, 0xc7, 0x63, 0x27, 0x38, 0xab,
+	0x2f, 0x24, 0x94, 0x8b, 0xd6, 0xc0, 0x09, 0x04, 0x46, 0xea, 0xb7, 0xf5, 0x02, 0x4a, 0x62, 0x70,
+	0x68, 0xec, 0xce, 0x34, 0x3a, 0xf1, 0x03, 0x61, 0x01, 0x02, 0x32, 0x3f, 0x82, 0x52, 0x3f, 0xa0,
+	0x0e, 0x5e, 0x0f, 0xb9, 0x0b, 0x6f, 0x56, 0xc9, 0x8a, 0xd6, 0x14, 0xd1, 0x57, 0xfc, 0xa6, 0xab,
+	0x10, 0xf6, 0xdb, 0xfa, 0x2f, 0x06, 0xd4, 0xd2, 0x9a, 0x33, 0x3f, 0x43, 0x8b, 0x18, 0x4f, 0x46,
+	0x14, 0xb1, 0x75, 0xe3, 0xc2, 0x1e, 0x34, 0x6e, 0xdc, 0x71, 0x93, 0x8f, 0x1f, 0xd8, 0x21, 0x45,
+	0x73, 0xe1, 0x1b, 0x3d, 0x4f, 0x60, 0xf2, 0xf1, 0x83, 0x2e, 0xc7, 0x30, 0x86, 0x47, 0x1f, 0x2b,
+	0x86, 0xbc, 0x60, 0x78, 0xf4, 0xb1, 0x64, 0xa8, 0x43, 0x29, 0x74, 0x50, 0x5e, 0x28, 0x6e, 0x5f,
+	0x09, 0x5a, 0x7f, 0x6a, 0xc0, 0x62, 0xc2, 0x34, 0x70, 0xfb, 0xf6, 0x27, 0x53, 0x7b, 0xec, 0x8e,
+	0x46, 0x2e, 0xf7, 0x07, 0xf3, 0xa4, 0xd2, 0x9f, 0x4c, 0xf7, 0x18, 0x02, 0x8f, 0xcc, 0x31, 0x1d,
+	0xfb, 0xc1, 0x99, 0x8d, 0x5b, 0x5a, 0x8e, 0x66, 0x9e, 0xe3, 0x36, 0x10, 0x65, 0x7e, 0x0f, 0x96,
+	0x26, 0xd4, 0x79, 0x61, 0x6b, 0x62, 0xf8, 0x90, 0x16, 0x11, 0xdd, 0x52, 0xa2, 0xd6, 0x60, 0x99,
+	0xf1, 0x25, 0xe4, 0xf1, 0x23, 0x88, 0x09, 0xd8, 0xd3, 0x64, 0x7e, 0x24, 0x67, 0xc0, 0x3d, 0xbb,
+	0x0b, 0x96, 0x47, 0xb0, 0x5a, 0xff, 0xb9, 0x08, 0xf3, 0xda, 0x2e, 0xc6, 0x13, 0xcd, 0x7f, 0xe9,
+	0x51, 0xb9, 0xf6, 0x1c, 0x30, 0xd7, 0x01, 0x02, 0x3a, 0xf1, 0x43, 0x37, 0xf2, 0x83, 0x33, 0xb1,
+	0xfa, 0x55, 0xbe, 0x67, 0x24, 0x96, 0x68, 0x1c, 0xe6, 0x7d, 0x28, 0x45, 0x81, 0x3b, 0x1c, 0xd2,
+	0x40, 0x9c, 0x01, 0x55, 0x61, 0x7d, 0x3d, 0x8e, 0x25, 0x92, 0xac, 0x1b, 0x55, 0xe1, 0xf2, 0x46,
+	0xf5, 0x09, 0x94, 0x8f, 0x5d, 0xcf, 0x0d, 0x4f, 0x2e, 0x35, 0x59, 0xc5, 0x6b, 0x3e, 0x80, 0x79,
+	0xc7, 0xf3, 0xfc, 0xc8, 0xe1, 0xc7, 0xce, 0x5c, 0xec, 0xb2, 0x34, 0x15, 0x9a, 0xe8, 0x2c, 0xe6,
+	0x87, 0x30, 0xc7, 0xfc, 0xac, 0xb0, 0x5e, 0x62, 0xcc, 0xb7, 0x53, 0xc7, 0xde, 0xfa, 0x2e, 0xa3,
+	0xb6, 0xbd, 0x28, 0x38, 0x23, 0x82, 0x15, 0x77, 0xd0, 0xc4, 0x09, 0x70, 0xc7, 0x96, 0xf9, 0x0e,
+	0xe2, 0x10, 0x7a, 0xdf, 0xfd, 0x13, 0x77, 0x34, 0x08, 0xa8, 0xc7, 0x4e, 0x85, 0x0a, 0x51, 0xb0,
+	0x79, 0x1b, 0x2a, 0xcc, 0x7d, 0x3e, 0x71, 0xc2, 0x13, 0x76, 0x20, 0x54, 0x48, 0x19, 0x11, 0xdb,
+	0x4e, 0x78, 0x62, 0x3e, 0x84, 0x85, 0xbe, 0x3f, 0x1e, 0xbb, 0x91, 0x1d, 0x38, 0xde, 0x90, 0xd6,
+	0xe7, 0xe3, 0x23, 0xb8, 0xc5, 0xf0, 0x04, 0xd1, 0x64, 0xbe, 0x1f, 0x03, 0xe6, 0x0f, 0x61, 0x7e,
+	0x4c, 0x83, 0x21, 0xb5, 0x87, 0x81, 0x3f, 0x9d, 0x88, 0x53, 0x80, 0xcd, 0x75, 0x0f, 0xd1, 0x5b,
+	0x88, 0x25, 0x30, 0x56, 0xbf, 0xcd, 0x4f, 0x60, 0x49, 0x39, 0xf1, 0xdc, 0xdc, 0xeb, 0x8b, 0x99,
+	0x2b, 0xbd, 0x28, 0xfc, 0xfa, 0x2e, 0x63, 0x42, 0xf7, 0x51, 0x1c, 0xa9, 0xa7, 0x34, 0x70, 0x8f,
+	0x5d, 0x3a, 0xa8, 0x57, 0xb9, 0xfb, 0xc8, 0xd1, 0x4f, 0x05, 0xb6, 0xf1, 0x08, 0xe6, 0x35, 0x6d,
+	0x99, 0x35, 0xc8, 0xbf, 0xa0, 0x67, 0xc2, 0xd0, 0xf0, 0x67, 0xb6, 0x07, 0xf8, 0x59, 0xee, 0x53,
+	0xc3, 0xfa, 0x9f, 0x06, 0xcc, 0x6b, 0x33, 0x45, 0x0d, 0x1f, 0xd1, 0x63, 0x3f, 0x90, 0xb7, 0x94,
+	0x80, 0x50, 0x82, 0x73, 0x1c, 0x31, 0x1f, 0x9c, 0x49, 0x60, 0x00, 0xee, 0x7e, 0x3c, 0x2c, 0x9c,
+	0x80, 0xda, 0xd3, 0x60, 0x24, 0x8e, 0x22, 0x10, 0xa8, 0xc3, 0x60, 0x84, 0xe2, 0x8e, 0xfd, 0xa0,
+	0x2f, 0x8c, 0xb0, 0x4c, 0x04, 0x64, 0xbe, 0x8d, 0x77, 0x24, 0xf6, 0x8a, 0x57, 0x4e, 0x5e, 0x3a,
+	0x21, 0x62, 0x20, 0x92, 0x84, 0x5e, 0x63, 0x14, 0x4c, 0xbd, 0x3e, 0xb3, 0xe2, 0x39, 0xee, 0x35,
+	0x2a, 0x84, 0xf5, 0x0a, 0x20, 0x56, 0x38, 0x3e, 0xdf, 0x4e, 0xa8, 0x33, 0xb0, 0xc3, 0x13, 0x47,
+	0x0c, 0xbd, 0x84, 0x70, 0xf7, 0xc4, 0x51, 0x24, 0x7c, 0x40, 0xe5, 0x62, 0x12, 0xa1, 0xc7, 0x48,
+	0x3a, 0x72, 0x42, 0xca, 0x5a, 0xf1, 0xd1, 0x97, 0x10, 0x16, 0xad, 0x18, 0x09, 0x5b, 0x15, 0x62,
+	0x12, 0xbe, 0xb9, 0xfe, 0x7d, 0x0e, 0xe6, 0xf8, 0x58, 0x51, 0xd7, 0x71, 0x8f, 0xf8, 0x13, 0x0f,
+	0xbc, 0x31, 0x0d, 0xd9, 0x1d, 0x28, 0x3a, 0x13, 0x20, 0x6a, 0x8b, 0x9f, 0xf8, 0x36, 0x73, 0x03,
+	0x84, 0xb6, 0x38, 0x6a, 0x5f, 0xf8, 0x84, 0x82, 0x81, 0x8e, 0x1d, 0x77, 0x24, 0xdf, 0x99, 0x1c,
+	0xd7, 0x46, 0x94, 0xf9, 0x29, 0x54, 0x54, 0xfc, 0xe0, 0x12, 0x3b, 0x34, 0x66, 0xc6, 0x91, 0xe2,
+	0x1a, 0xcd, 0xf1, 0x91, 0x4e, 0x83, 0x11, 0x5b, 0xd3, 0xc1, 0x80, 0x0e, 0xd8, 0x0e, 0xac, 0x10,
+	0x0e, 0xe0, 0xf8, 0x03, 0x3a, 0xf6, 0x4f, 0xd9, 0x63, 0x05, 0xf1, 0x12, 0xc4, 0x5d, 0x36, 0xf6,
+	0x07, 0xdc, 0x10, 0xc5, 0x2e, 0x93, 0x30, 0x2e, 0x46, 0x6c, 0xc8, 0x78, 0x37, 0x9d, 0xe0, 0xfd,
+	0x2b, 0x3c, 0x1d, 0xfc, 0x1d, 0x1f, 0x80, 0x39, 0xfd, 0x00, 0x34, 0xa1, 0x80, 0xc7, 0x9b, 0xbc,
+	0xc5, 0xf0, 0x37, 0x8e, 0x34, 0x56, 0x3a, 0xfe, 0xc4, 0x9e, 0xf1, 0xbd, 0x8a, 0x1e, 0xba, 0x70,
+	0x51, 0x14, 0x6c, 0xed, 0x02, 0xc4, 0x67, 0xcc, 0x65, 0x6d, 0x1f, 0x0d, 0x33, 0xa4, 0xfd, 0x80,
+	0x46, 0xc2, 0xad, 0x16, 0x10, 0x3e, 0xa7, 0xcb, 0xe8, 0x02, 0xa0, 0x4b, 0x67, 0xbe, 0x0d, 0x85,
+	0xe8, 0x6c, 0xc2, 0xb7, 0x42, 0xf5, 0x61, 0x4d, 0xba, 0x07, 0x48, 0xeb, 0x9d, 0x4d, 0x28, 0x61,
+	0x54, 0x73, 0x1d, 0x0a, 0xa8, 0xe5, 0x4b, 0xdc, 0xdd, 0x8c, 0xef, 0x52, 0x5e, 0x9c, 0x66, 0x44,
+	0x85, 0x84, 0x11, 0x59, 0x7f, 0x9b, 0x83, 0xc5, 0x84, 0x2b, 0x87, 0xbc, 0xe1, 0xb4, 0xdf, 0xa7,
+	0x21, 0xbf, 0x32, 0xcb, 0x44, 0x82, 0xe6, 0x3f, 0x81, 0xc5, 0x63, 0xc7, 0x1d, 0x4d, 0x03, 0x6a,
+	0xf7, 0xfd, 0xa9, 0x17, 0xb1, 0x21, 0x16, 0xc9, 0x82, 0x40, 0xb6, 0x10, 0xc7, 0x2e, 0x5d, 0xc7,
+	0xb3, 0x03, 0x3a, 0x19, 0x39, 0x67, 0x42, 0x1b, 0x95, 0xbe, 0xe3, 0x11, 0x86, 0x48, 0xbd, 0xfc,
+	0x0b, 0x57, 0x09, 0x5e, 0xdc, 0x85, 0xf9, 0x81, 0x3b, 0xb0, 0xe9, 0x2b, 0xda, 0x9f, 0x46, 0x22,
+	0x44, 0x44, 0x60, 0xe0, 0x0e, 0xda, 0x1c, 0x63, 0x7e, 0x0c, 0xab, 0xae, 0x77, 0x1c, 0x38, 0x61,
+	0x14, 0x4c, 0xfb, 0x11, 0x0e, 0x53, 0x8c, 0x4c, 0x6c, 0xf6, 0x1b, 0x49, 0xea, 0x63, 0x4e, 0xc4,
+	0x09, 0x3b, 0x51, 0x44, 0xc7, 0x13, 0xee, 0xe2, 0x17, 0x89, 0x04, 0x91, 0x12, 0xbe, 0x70, 0x27,
+	0x13, 0xf5, 0xd0, 0x96, 0x20, 0x3e, 0xf6, 0x7f, 0x39, 0xf5, 0x23, 0xc7, 0xa6, 0xaf, 0xfa, 0x94,
+	0x0e, 0x98, 0x05, 0x23, 0xc3, 0x22, 0xc3, 0xb6, 0x05, 0x12, 0x8d, 0x65, 0x3c, 0xc5, 0xd3, 0x06,
+	0x18, 0x95, 0x03, 0xd6, 0x4b, 0xa8, 0x28, 0x9f, 0xd7, 0x34, 0x35, 0xa3, 0xa8, 0x08, 0x13, 0xc0,
+	0x10, 0x80, 0x73, 0xc6, 0x82, 0x3f, 0x62, 0xcf, 0x0b, 0xd0, 0xbc, 0x07, 0xf3, 0x03, 0x8a, 0xcf,
+	0xc8, 0x89, 0x7a, 0x67, 0x57, 0x88, 0x8e, 0xe2, 0x77, 0x97, 0xe3, 0x79, 0x78, 0x15, 0x16, 0xe4,
+	0xdd, 0xc5, 0x61, 0xab, 0x0f, 0x8b, 0x89, 0x47, 0x46, 0xe6, 0x13, 0x42, 0x5a, 0x69, 0x2e, 0xb6,
+	0x52, 0xd9, 0x48, 0xb3, 0x52, 0x6d, 0x88, 0xf9, 0xc4, 0x10, 0xad, 0xb7, 0xa1, 0xda, 0x8d, 0xfc,
+	0xc9, 0xeb, 0xdf, 0xab, 0xd6, 0x32, 0x2c, 0x29, 0x2e, 0xfe, 0x78, 0xb2, 0xfe, 0x9d, 0x01, 0xb5,
+	0x66, 0x14, 0x39, 0xfd, 0x13, 0xad, 0xed, 0x9a, 0x8c, 0xc0, 0x18, 0xb1, 0x4f, 0xad, 0x98, 0x58,
+	0xa0, 0x8a, 0xbd, 0x94, 0xf0, 0x87, 0xb9, 0x8a, 0xbc, 0x03, 0xd7, 0x53, 0xb1, 0x4a, 0x0e, 0x9a,
+	0x6b, 0xec, 0x15, 0xeb, 0xfe, 0x8a, 0x8a, 0x48, 0x13, 0x9b, 0x13, 0x06, 0x38, 0x5c, 0xcf, 0x19,
+	0x75, 0xdd, 0x5f, 0x51, 0x7c, 0x98, 0x71, 0x0e, 0xfd, 0xb5, 0xf5, 0x3f, 0x0c, 0xa8, 0x26, 0xbb,
+	0xca, 0xd4, 0xd7, 0x1d, 0xa8, 0x60, 0x0b, 0xc7, 0x8d, 0x0f, 0xa3, 0x18, 0x81, 0x7a, 0xc2, 0xeb,
+	0xc7, 0xf1, 0x50, 0x4f, 0xec, 0xf8, 0x13, 0x20, 0x1e, 0x2d, 0x51, 0x74, 0x26, 0x2e, 0x32, 0xfc,
+	0x89, 0x9a, 0x67, 0xa3, 0x2c, 0x66, 0x8f, 0x92, 0x30, 0xea, 0xcc, 0x4b, 0x7f, 0x6e, 0xe6, 0xa5,
+	0x6f, 0xfd, 0x04, 0x16, 0xf4, 0x86, 0x68, 0x86, 0x2f, 0xdd, 0x41, 0x74, 0xc2, 0xc6, 0xbd, 0x48,
+	0x38, 0x80, 0x67, 0xd6, 0x09, 0x75, 0x87, 0x27, 0x7c, 0x1f, 0x2f, 0x12, 0x01, 0x59, 0xbf, 0x84,
+	0x65, 0x6d, 0x19, 0xc4, 0xcb, 0xb6, 0x8e, 0x71, 0xd5, 0x81, 0x3f, 0xe5, 0x0b, 0x81, 0xca, 0x15,
+	0xb0, 0xa0, 0xd0, 0x20, 0x50, 0x6a, 0x17, 0xb0, 0xf9, 0x06, 0x54, 0xe8, 0x2b, 0x37, 0xb2, 0xfb,
+	0xfe, 0x80, 0xab, 0xbe, 0x88, 0x01, 0x66, 0x44, 0xb5, 0xfc, 0x41, 0x42, 0xd5, 0x7f, 0x6e, 0x00,
+	0x6c, 0x52, 0x67, 0xb0, 0x4b, 0x23, 0xf4, 0x03, 0xaa, 0x90, 0x73, 0x65, 0xc4, 0x27, 0xe7, 0x0e,
+	0xf0, 0x4c, 0xa1, 0x68, 0xaf, 0xb6, 0x32, 0xcc, 0x0a, 0xa9, 0x50, 0x79, 0x6e, 0xa6, 0x6d, 0x71,
+	0x21, 0xde, 0x2e, 0x2b, 0x50, 0xa4, 0x41, 0xe0, 0x07, 0xe2, 0xd4, 0xe3, 0x00, 0x7a, 0xa5, 0x01,
+	0xed, 0x53, 0xf7, 0xf4, 0x72, 0x5e, 0xa9, 0xe4, 0xc5, 0xad, 0x25, 0x4e, 0x86, 0x90, 0x69, 0xbd,
+	0x48, 0x14, 0x8c, 0x87, 0x13, 0x1e, 0x36, 0x74, 0x80, 0x51, 0xd1, 0x50, 0x5c, 0x81, 0xc0, 0x51,
+	0x18, 0x88, 0xb2, 0xd6, 0x61, 0x15, 0x83, 0x05, 0xf1, 0x2c, 0x55, 0xf4, 0x92, 0x85, 0xa6, 0x70,
+	0x25, 0x85, 0x2b, 0xcf, 0x00, 0xab, 0x09, 0x37, 0x67, 0xf8, 0xc5, 0x5a, 0x7c, 0x4f, 0x8b, 0xca,
+	0x28, 0xc7, 0x38, 0x66, 0x54, 0x81, 0xa3, 0x2f, 0xe0, 0x26, 0x3f, 0x75, 0x35, 0x9a, 0xe8, 0x33,
+	0xad, 0x61, 0x35, 0x86, 0x9c, 0x3e, 0x86, 0x06, 0xd4, 0x67, 0x05, 0x88, 0xdd, 0x7a, 0x13, 0x6e,
+	0x6c, 0xd1, 0xe8, 0xab, 0x29, 0x9d, 0x52, 0x11, 0x0d, 0xe2, 0xa2, 0xad, 0x1f, 0xc3, 0x6a, 0x9a,
+	0x20, 0xc6, 0xfd, 0x16, 0x14, 0x98, 0x72, 0x8c, 0xf8, 0xed, 0xcf, 0xd8, 0x50, 0x41, 0x84, 0x91,
+	0xac, 0xbf, 0x36, 0xa0, 0xa2, 0x70, 0xe6, 0x5d, 0xc8, 0xcb, 0x18, 0xf3, 0x4c, 0xec, 0x09, 0x29,
+	0xb8, 0x22, 0xcc, 0x49, 0x70, 0x7d, 0x3e, 0xf2, 0x22, 0x51, 0x30, 0xd7, 0x92, 0x13, 0xaa, 0x68,
+	0x24, 0xd3, 0xd2, 0x33, 0xc7, 0x8d, 0x08, 0xc3, 0x12, 0x41, 0xd5, 0xc3, 0x15, 0x85, 0x64, 0xb8,
+	0xe2, 0x01, 0x14, 0x43, 0xd7, 0xeb, 0xd3, 0x4b, 0x18, 0x09, 0x67, 0xc4, 0x16, 0x97, 0x8d, 0xca,
+	0x73, 0x46, 0xab, 0x0f, 0xb7, 0xba, 0x34, 0xda, 0x73, 0x5c, 0xdc, 0x08, 0x8e, 0xd7, 0xa7, 0x7b,
+	0xfe, 0x40, 0xc5, 0x18, 0xeb, 0x50, 0xa2, 0x9e, 0x73, 0x84, 0x4f, 0x45, 0x71, 0x15, 0x0b, 0x10,
+	0xf7, 0xae, 0x98, 0x1c, 0x5f, 0x30, 0x39, 0x19, 0xb5, 0x8e, 0x79, 0x7d, 0x1d, 0xdb, 0xd0, 0xc8,
+	0xea, 0x44, 0x05, 0xad, 0x0a, 0x63, 0xdc, 0xa1, 0x5c, 0xcd, 0x2c, 0x1c, 0x9e, 0x66, 0x65, 0x0c,
+	0xd6, 0x6d, 0xb8, 0xb5, 0x75, 0xde, 0x58, 0xb1, 0x8f, 0xad, 0xef, 0xa0, 0x8f, 0x29, 0x2c, 0xa5,
+	0x08, 0xdf, 0x42, 0x0b, 0x6a, 0xe1, 0xf2, 0x97, 0x5c, 0x38, 0xeb, 0x9f, 0xc2, 0xf5, 0x2d, 0x1a,
+	0x3d, 0x1e, 0x39, 0x2f, 0xce, 0xf4, 0xc4, 0x42, 0xf2, 0x3d, 0x6d, 0x5c, 0xf8, 0x9e, 0x56, 0x99,
+	0x81, 0x9c, 0x96, 0x19, 0xb0, 0x7e, 0x02, 0x2b, 0x49, 0xe1, 0x42, 0x29, 0x6f, 0xa7, 0xf6, 0x31,
+	0x8f, 0x97, 0x0b, 0x36, 0xb5, 0x8b, 0xff, 0xb7, 0x01, 0x65, 0x89, 0xcc, 0xbc, 0x80, 0x30, 0xb8,
+	0xd9, 0xc7, 0x27, 0x16, 0x76, 0x6a, 0x10, 0x0e, 0x20, 0x67, 0x30, 0xf5, 0x42, 0x91, 0xb9, 0x60,
+	0xbf, 0x91, 0xf3, 0x78, 0xe4, 0x4e, 0x64, 0xe8, 0x84, 0x03, 0xf8, 0x2e, 0x3c, 0x46, 0xf9, 0xb6,
+	0xf4, 0x81, 0xf9, 0x23, 0xaa, 0x42, 0xaa, 0x0c, 0x4d, 0x24, 0x16, 0x6f, 0x9e, 0x91, 0x13, 0x46,
+	0x09, 0xaf, 0xaa, 0x42, 0xe6, 0x11, 0x27, 0x7d, 0x29, 0xe5, 0xf0, 0x70, 0x4f, 0x8a, 0x03, 0xd6,
+	0x9f, 0x19, 0xb0, 0xdc, 0x7e, 0x35, 0xf1, 0x83, 0x44, 0xd6, 0x26, 0xf3, 0xdc, 0xd3, 0xe2, 0xea,
+	0xb9, 0x4b, 0xe4, 0x72, 0xd6, 0xa1, 0x70, 0x1c, 0xf8, 0xe3, 0x4b, 0x2c, 0x34, 0xe3, 0x33, 0xd7,
+	0x20, 0x17, 0xf9, 0x97, 0x70, 0x3b, 0x73, 0x91, 0x6f, 0xde, 0x67, 0x8f, 0xcd, 0xb1, 0x13, 0xd5,
+	0x8b, 0xb1, 0x2b, 0xc4, 0xa7, 0xf1, 0x98, 0xe1, 0x89, 0xa0, 0x5b, 0xf7, 0xc1, 0xd4, 0xa7, 0x27,
+	0x96, 0xd7, 0x84, 0x82, 0xca, 0x22, 0x2e, 0x10, 0xf6, 0xdb, 0x7a, 0x04, 0xd7, 0x37, 0xdd, 0xe3,
+	0xe3, 0x27, 0xfc, 0x61, 0x1e, 0x6a, 0x1e, 0x12, 0x9b, 0x86, 0x58, 0x56, 0x36, 0xd4, 0x2a, 0x1b,
+	0x2a, 0x37, 0xec, 0x5c, 0xe4, 0x5b, 0xff, 0x0c, 0x56, 0x92, 0x4d, 0x45, 0x37, 0xb7, 0xa1, 0x82,
+	0xfc, 0x3c, 0x20, 0xc1, 0x05, 0x94, 0x11, 0xc1, 0x02, 0x12, 0x37, 0xa1, 0x14, 0xf9, 0x9c, 0x24,
+	0xb6, 0x48, 0xe4, 0x33, 0x02, 0x0e, 0xce, 0x3d, 0x3e, 0x96, 0x0f, 0x25, 0xfc, 0x6d, 0xbd, 0x0f,
+	0x37, 0x79, 0xfc, 0xff, 0x20, 0xf0, 0x4f, 0xf9, 0x06, 0x7c, 0x9d, 0x0b, 0xf7, 0x09, 0xd4, 0x67,
+	0xd9, 0xc5, 0xa0, 0x1a, 0x50, 0xa6, 0xde, 0x29, 0x1d, 0xf9, 0xc2, 0xb3, 0x5d, 0x20, 0x0a, 0xb6,
+	0xfe, 0xab, 0x01, 0xb0, 0x33, 0x76, 0x86, 0x74, 0x63, 0xea, 0x8e, 0xd8, 0x26, 0x1e, 0xb8, 0x43,
+	0xaa, 0x9e, 0x77, 0x02, 0x42, 0xf3, 0x70, 0xc7, 0xf1, 0xb3, 0x97, 0x03, 0x66, 0x8d, 0x5f, 0x09,
+	0x7c, 0xd8, 0xf8, 0x33, 0xb5, 0x47, 0x0b, 0x17, 0xee, 0xd1, 0x07, 0x50, 0x3c, 0x9a, 0xba, 0xa3,
+	0xe8, 0x32, 0xa7, 0x3a, 0x63, 0xb4, 0x1e, 0xc0, 0xea, 0x63, 0xd7, 0x1b, 0xc4, 0x63, 0x56, 0xeb,
+	0x76, 0xce, 0xd8, 0xf1, 0xf2, 0x9e, 0x69, 0x11, 0x5f, 0xde, 0x47, 0x0c, 0xa3, 0x5f, 0xde, 0x31,
+	0x23, 0x11, 0x54, 0xeb, 0x3a, 0x2c, 0x6f, 0xd1, 0xe8, 0x29, 0x0d, 0x98, 0xbd, 0x8b, 0x43, 0xf6,
+	0xd7, 0x06, 0x98, 0x3a, 0x56, 0x39, 0x67, 0xa5, 0x53, 0x8e, 0x92, 0xb1, 0x0a, 0x01, 0xe2, 0x00,
+	0x79, 0xf4, 0x43, 0x2e, 0x3f, 0x87, 0x58, 0x66, 0x03, 0xfb, 0xb1, 0x59, 0xb2, 0x82, 0x6b, 0xb3,
+	0xc2, 0x30, 0x9b, 0x4e, 0xc4, 0x43, 0x0b, 0x13, 0xd7, 0x96, 0x42, 0x0b, 0x22, 0xb4, 0x30, 0x71,
+	0x45, 0xcf, 0xd6, 0x7b, 0xec, 0xbc, 0x94, 0xaf, 0xd7, 0xf0, 0x75, 0x66, 0xc2, 0x4f, 0x3f, 0x8d,
+	0x35, 0x3e, 0xfd, 0x98, 0x0b, 0x17, 0xea, 0xa7, 0x9f, 0x64, 0x23, 0x82, 0x66, 0x1d, 0x42, 0xe9,
+	0x40, 0xa4, 0x3f, 0xb3, 0xce, 0xbe, 0xd4, 0x7b, 0x28, 0x37, 0xfb, 0x1e, 0x5a, 0x81, 0x22, 0x5b,
+	0x7c, 0xe1, 0x7e, 0x73, 0xc0, 0xba, 0x01, 0xd7, 0xd1, 0xbb, 0x12, 0xa2, 0x95, 0xef, 0xf2, 0x05,
+	0xac, 0x24, 0xd1, 0xea, 0xfa, 0x2a, 0x8b, 0x24, 0xac, 0x1c, 0x2d, 0x4b, 0x02, 0x08, 0x3e, 0xa2,
+	0x88, 0xe8, 0x72, 0x6d, 0x51, 0xd9, 0x7e, 0x9b, 0x3a, 0xa3, 0xe8, 0xe4, 0x75, 0x49, 0x2f, 0x11,
+	0x9a, 0xc8, 0xa9, 0xd0, 0x84, 0xf5, 0x3b, 0x03, 0x6a, 0xb1, 0xe1, 0x72, 0x09, 0x57, 0xbe, 0x86,
+	0xde, 0xc1, 0x60, 0x68, 0x84, 0x66, 0x99, 0xcb, 0x4c, 0xdb, 0x71, 0x22, 0x06, 0x12, 0xf9, 0x2f,
+	0x5b, 0x05, 0x69, 0xf3, 0x59, 0xfc, 0x55, 0xce, 0xf5, 0x58, 0x30, 0x59, 0x3d, 0xa8, 0xcf, 0x4e,
+	0x52, 0x68, 0xea, 0x53, 0x58, 0x50, 0x03, 0x71, 0x69, 0xa8, 0x27, 0x47, 0xd3, 0xd3, 0x22, 0x09,
+	0x4e, 0x6b, 0x8d, 0xd9, 0xc9, 0x57, 0xf8, 0x7e, 0xe6, 0x99, 0x9d, 0xd7, 0xd8, 0xd4, 0x17, 0x70,
+	0x23, 0xc5, 0x1b, 0xef, 0x2e, 0xf6, 0x02, 0x4f, 0xec, 0x2e, 0x8d, 0x4f, 0x50, 0xad, 0xbf, 0x32,
+	0x00, 0x62, 0x74, 0xe6, 0xda, 0xbc, 0x0b, 0x4b, 0x7d, 0xdf, 0xeb, 0x4f, 0x83, 0x00, 0x5f, 0x1e,
+	0xcc, 0x71, 0xe5, 0xb7, 0x7a, 0x35, 0x46, 0xe3, 0x79, 0x6f, 0xae, 0xc3, 0xf5, 0xb1, 0xf3, 0xca,
+	0x4e, 0x33, 0xf3, 0x8b, 0x77, 0x79, 0xec, 0xbc, 0x6a, 0x25, 0xf9, 0xef, 0xc2, 0x3c, 0xc6, 0x6f,
+	0xc7, 0xae, 0x37, 0x95, 0x69, 0x02, 0x83, 0xd5, 0x60, 0xec, 0x71, 0x0c, 0x66, 0x1d, 0x50, 0xa0,
+	0xce, 0x54, 0xe4, 0x59, 0x87, 0xb1, 0xf3, 0xea, 0x49, 0xcc, 0xf7, 0x0e, 0x54, 0x27, 0x34, 0x70,
+	0xfd, 0x81, 0xca, 0x97, 0xcc, 0xc9, 0xe4, 0x04, 0x62, 0x45, 0xca, 0xc4, 0xfa, 0x05, 0x73, 0xc8,
+	0x79, 0x49, 0x90, 0x13, 0x51, 0xaf, 0x7f, 0xf6, 0xdd, 0xba, 0x37, 0xff, 0xca, 0x80, 0x9b, 0x33,
+	0x1d, 0x88, 0xf5, 0xf8, 0x69, 0xa6, 0x39, 0x34, 0x92, 0x7d, 0x24, 0x5a, 0x26, 0xf8, 0xd1, 0x6f,
+	0x14, 0x9a, 0x57, 0xa5, 0x1a, 0xf2, 0x31, 0x2e, 0x1b, 0xf0, 0x87, 0xc3, 0x5f, 0x1a, 0xb0, 0x9a,
+	0x2d, 0xf1, 0xca, 0xb3, 0xd4, 0x52, 0x4c, 0xb9, 0x44, 0x8a, 0x29, 0x9d, 0xbe, 0xca, 0xf3, 0x95,
+	0x4b, 0xa7, 0xaf, 0x62, 0x06, 0xb1, 0xb4, 0x93, 0x47, 0x49, 0x86, 0x47, 0x8a, 0xa1, 0x28, 0x19,
+	0x1e, 0x69, 0x0c, 0xb8, 0xf6, 0xfa, 0x82, 0x1a, 0x04, 0xc6, 0xce, 0x2b, 0xb9, 0x9a, 0xff, 0x02,
+	0x96, 0x52, 0x1a, 0xc8, 0xb4, 0xde, 0xab, 0x66, 0x82, 0xde, 0xe5, 0x67, 0x81, 0xd7, 0x3f, 0x4b,
+	0x4d, 0xaf, 0x2a, 0xd0, 0xb2, 0xff, 0x1d, 0xa8, 0xf1, 0x32, 0x93, 0x3f, 0xba, 0x20, 0x01, 0xaf,
+	0x38, 0x4d, 0x94, 0x78, 0x57, 0xfe, 0x18, 0x96, 0x0e, 0xa6, 0xc1, 0xf0, 0x22, 0xf1, 0xd9, 0x0f,
+	0xd6, 0xef, 0x41, 0x2d, 0x6e, 0x1c, 0xbb, 0x61, 0xea, 0xd5, 0x59, 0x11, 0xd6, 0x32, 0x80, 0xe5,
+	0xe6, 0x64, 0x82, 0x6e, 0xcb, 0x1f, 0x3d, 0x0b, 0x19, 0xe1, 0xc1, 0x2c, 0x92, 0x88, 0x84, 0x09,
+	0x10, 0xdd, 0x42, 0xbd, 0x97, 0xd7, 0x8c, 0xe7, 0x17, 0xb0, 0xdc, 0x1c, 0x0c, 0x64, 0xd6, 0xf9,
+	0x8f, 0x1b, 0x4f, 0x56, 0x22, 0xf7, 0x63, 0x30, 0x75, 0xf9, 0x62, 0x24, 0x77, 0xa1, 0xe0, 0xf9,
+	0xaa, 0x56, 0x21, 0x91, 0xf8, 0x66, 0x04, 0x6b, 0x1b, 0x56, 0xbb, 0x34, 0xc2, 0x70, 0xf8, 0xd4,
+	0xeb, 0x53, 0x9c, 0x93, 0xf6, 0x32, 0x95, 0x01, 0x65, 0x23, 0x99, 0x95, 0xc8, 0x5e, 0x98, 0x0e,
+	0xdc, 0x9c, 0x91, 0x24, 0x46, 0xf1, 0x11, 0x2c, 0x38, 0x1a, 0x5e, 0x8c, 0xa6, 0x26, 0x93, 0x7d,
+	0x8a, 0x3f, 0xc1, 0x65, 0xd5, 0xd9, 0xa1, 0x96, 0x31, 0x34, 0xec, 0x6a, 0xeb, 0x3b, 0xed, 0xea,
+	0xe7, 0xb0, 0xa0, 0x53, 0x5f, 0x33, 0x77, 0xf5, 0xee, 0xcc, 0x5d, 0xf6, 0xdd, 0x19, 0x31, 0x3f,
+	0x6a, 0x97, 0xdd, 0xaf, 0x9a, 0x29, 0x5e, 0xf5, 0xc8, 0x12, 0xc5, 0x86, 0x98, 0x12, 0xd4, 0xea,
+	0x10, 0xf1, 0x9d, 0xc0, 0x1c, 0x7d, 0xdf, 0xa3, 0x22, 0x12, 0xcf, 0x7e, 0x5b, 0x9f, 0xc3, 0x4a,
+	0xb2, 0xd7, 0xab, 0x15, 0x24, 0xfd, 0x9c, 0x39, 0xa1, 0x1b, 0x81, 0xe3, 0xf5, 0x4f, 0xe8, 0x77,
+	0xfc, 0x56, 0xfe, 0x1c, 0xae, 0x27, 0x64, 0xab, 0x7b, 0xbd, 0x7c, 0x24, 0x70, 0x75, 0x23, 0xce,
+	0xf0, 0x71, 0x3e, 0xa2, 0x68, 0xd6, 0xff, 0x35, 0x60, 0x8e, 0x23, 0xa5, 0x6f, 0x65, 0xc4, 0x69,
+	0x9f, 0x7f, 0x5c, 0xb7, 0xc8, 0xfc, 0x5c, 0x3c, 0x8f, 0x65, 0xf6, 0xe4, 0xe2, 0x57, 0x26, 0x7b,
+	0x3a, 0x77, 0x39, 0xbb, 0x3a, 0x17, 0x8a, 0xfc, 0xc1, 0x8e, 0xbf, 0x2d, 0x0f, 0xe6, 0x78, 0x25,
+	0xd5, 0x79, 0x91, 0x67, 0xfc, 0xcb, 0xca, 0x63, 0x65, 0x54, 0x54, 0x21, 0x58, 0x0b, 0x19, 0x78,
+	0xc5, 0x16, 0x18, 0x4a, 0x79, 0x13, 0x40, 0x85, 0xa6, 0x65, 0x7a, 0x40, 0xc3, 0x58, 0xbf, 0x37,
+	0xa0, 0x24, 0x2a, 0x5b, 0x58, 0x79, 0xc9, 0x98, 0xa5, 0x79, 0x0c, 0x76, 0x11, 0x08, 0x88, 0x25,
+	0x18, 0x98, 0x37, 0xd3, 0x3f, 0x13, 0x9d, 0x2a, 0x38, 0x55, 0x71, 0x91, 0xbf, 0xa8, 0xe2, 0xa2,
+	0x30, 0x5b, 0x71, 0x61, 0x42, 0x61, 0x38, 0x99, 0x4a, 0x87, 0x87, 0xfd, 0x66, 0x17, 0x72, 0xe2,
+	0x3e, 0x94, 0xa0, 0xf5, 0xff, 0xf8, 0x7b, 0x48, 0x0c, 0x39, 0xd4, 0x6a, 0x78, 0x59, 0x32, 0xdd,
+	0x3e, 0x3a, 0x63, 0xd6, 0x22, 0xde, 0xee, 0xc8, 0xc3, 0xb2, 0xbb, 0xae, 0x37, 0x24, 0x25, 0xc6,
+	0xb1, 0x71, 0xa6, 0x42, 0x08, 0xb9, 0x2b, 0x85, 0x10, 0xf2, 0x97, 0x0a, 0x21, 0x5c, 0xf1, 0x6d,
+	0x6a, 0xfd, 0xc6, 0x90, 0xef, 0x2a, 0x31, 0x9f, 0xf8, 0x39, 0xad, 0x74, 0x6e, 0xa4, 0x74, 0x7e,
+	0x1f, 0xe6, 0xd8, 0x54, 0xa4, 0x93, 0x54, 0xd3, 0xca, 0x93, 0xd8, 0x6c, 0x89, 0xa0, 0xc7, 0x35,
+	0x90, 0xfc, 0x66, 0xe7, 0x40, 0x32, 0x2b, 0x5e, 0x48, 0x67, 0xc5, 0x7f, 0x6b, 0xc0, 0x82, 0x2e,
+	0x0c, 0x4d, 0x28, 0xb5, 0xcd, 0x2b, 0x89, 0x6d, 0xcd, 0xae, 0x1f, 0x67, 0x2c, 0x4c, 0x83, 0xfd,
+	0xc6, 0x8e, 0xc7, 0xbe, 0x17, 0x9d, 0xc8, 0xa8, 0x24, 0x03, 0x34, 0x03, 0x2b, 0x24, 0x0c, 0x2c,
+	0x63, 0x23, 0xbc, 0xc6, 0x04, 0xfe, 0xbb, 0x01, 0x55, 0x51, 0xad, 0x72, 0x20, 0xa2, 0xfe, 0x98,
+	0x8c, 0xe5, 0x75, 0x11, 0xe2, 0x55, 0xce, 0xa1, 0x8b, 0xd2, 0x08, 0x0d, 0x28, 0x0f, 0xe8, 0xc8,
+	0x3d, 0xa5, 0xc1, 0x99, 0x18, 0xa8, 0x82, 0x13, 0x29, 0x83, 0xc2, 0x15, 0x52, 0x06, 0x5a, 0x6a,
+	0xa2, 0x98, 0x48, 0x4d, 0x58, 0xeb, 0xec, 0x11, 0x95, 0x1c, 0xf9, 0xeb, 0x9e, 0x3c, 0x3b, 0x70,
+	0x2b, 0x83, 0x5f, 0xd8, 0xc7, 0x0f, 0xe2, 0x3a, 0x1e, 0x2d, 0x4f, 0x96, 0x62, 0x96, 0x2c, 0xd6,
+	0xff, 0x32, 0xa0, 0xb6, 0xe1, 0x44, 0x2c, 0xc1, 0xf3, 0x2d, 0x6b, 0xa8, 0x67, 0x8b, 0x9d, 0x73,
+	0x59, 0xc5, 0xce, 0x69, 0x77, 0x25, 0x3f, 0xeb, 0xae, 0xdc, 0x84, 0xd2, 0x20, 0x38, 0xb3, 0x83,
+	0xa9, 0x27, 0x6b, 0x3a, 0x06, 0xc1, 0x19, 0x99, 0x7a, 0xf1, 0xfd, 0x50, 0xd4, 0xef, 0x87, 0xff,
+	0x66, 0xc0, 0xb2, 0x36, 0xf6, 0x78, 0xfe, 0xb2, 0xb0, 0x90, 0x8f, 0x9e, 0xcd, 0x5f, 0xf2, 0xa5,
+	0xab, 0x0b, 0xef, 0x40, 0x85, 0x9d, 0xd1, 0x2c, 0x6f, 0xcb, 0x6f, 0x9f, 0x18, 0xc1, 0x6a, 0x4c,
+	0x58, 0xda, 0x46, 0xbc, 0xe0, 0x04, 0xa4, 0x27, 0x83, 0x65, 0xe5, 0x19, 0x07, 0x93, 0x3b, 0xa8,
+	0x98, 0xde, 0x41, 0xbf, 0x36, 0xa0, 0x9a, 0x1c, 0x49, 0xe6, 0x61, 0xfe, 0x3e, 0x94, 0xfc, 0x69,
+	0xd4, 0xf7, 0xc7, 0x32, 0xf3, 0x7a, 0x5d, 0x9f, 0x42, 0x87, 0x93, 0x88, 0xe4, 0xd1, 0x9d, 0x90,
+	0x7c, 0xd2, 0x09, 0xb9, 0x09, 0x25, 0x8f, 0xbe, 0x64, 0xc5, 0xf9, 0x3c, 0x6e, 0x33, 0xe7, 0xd1,
+	0x97, 0x4f, 0xfc, 0x23, 0xeb, 0x73, 0x16, 0x51, 0xc2, 0xfb, 0x6b, 0xa3, 0xb3, 0x77, 0x81, 0x6f,
+	0x3d, 0x1b, 0x79, 0xb3, 0x7e, 0x04, 0xa6, 0xde, 0x5c, 0xe5, 0x74, 0x8a, 0xe1, 0x91, 0x3f, 0x4e,
+	0x84, 0x45, 0x24, 0x0f, 0xa7, 0x58, 0x5f, 0x41, 0x49, 0x60, 0x62, 0xc9, 0x86, 0x26, 0xd9, 0x5c,
+	0x55, 0x81, 0x56, 0x11, 0xa4, 0xe2, 0x10, 0xf7, 0xac, 0x59, 0x82, 0x50, 0xe6, 0xf5, 0x04, 0x68,
+	0xbd, 0x0f, 0xd7, 0xbb, 0x51, 0x40, 0x9d, 0x71, 0x32, 0xfc, 0xb4, 0xaa, 0xd9, 0x30, 0x17, 0xc4,
+	0x20, 0xeb, 0xff, 0xe7, 0x60, 0xbe, 0x4b, 0x83, 0x53, 0x1a, 0xa8, 0xb4, 0xf7, 0x4c, 0xce, 0xfd,
+	0xaa, 0x65, 0x17, 0x77, 0xe3, 0x40, 0x64, 0x76, 0x6e, 0x4a, 0xf8, 0x64, 0x4c, 0xbb, 0x05, 0xe5,
+	0x93, 0xb1, 0xc2, 0x9c, 0xf7, 0xa0, 0x82, 0x24, 0x76, 0xf4, 0x88, 0x30, 0x64, 0x32, 0xfa, 0x55,
+	0xfe, 0x46, 0xfc, 0xd2, 0xd7, 0x79, 0x2e, 0xb9, 0xce, 0x5f, 0x00, 0x38, 0x51, 0x14, 0xb8, 0x47,
+	0x2c, 0x40, 0xc0, 0xab, 0xde, 0xee, 0xa2, 0x14, 0x6d, 0xa6, 0xeb, 0x4d, 0xc5, 0xc1, 0x2b, 0xdf,
+	0xb4, 0x26, 0x8d, 0xcf, 0x61, 0x29, 0x45, 0xbe, 0x52, 0xa9, 0xd7, 0x7f, 0x30, 0xe0, 0x56, 0xf7,
+	0xcc, 0xeb, 0xa3, 0xf2, 0xdd, 0x80, 0x0e, 0x5a, 0x27, 0xb4, 0xff, 0xe2, 0x5b, 0x7b, 0x83, 0x58,
+	0x28, 0xc6, 0xfc, 0x36, 0x69, 0x03, 0x1c, 0xd2, 0x8f, 0x87, 0x7c, 0xfa, 0x78, 0xd0, 0xbf, 0x9e,
+	0xe1, 0x80, 0x75, 0x02, 0x8d, 0xac, 0x31, 0x69, 0xd7, 0x28, 0x5a, 0xd0, 0xab, 0x48, 0x3e, 0xbf,
+	0x14, 0x1c, 0x57, 0x2f, 0xe5, 0xce, 0xa9, 0x5e, 0xca, 0x27, 0xaa, 0x97, 0xac, 0xbf, 0x31, 0xa0,
+	0xdc, 0xed, 0x9f, 0xd0, 0xc1, 0x74, 0x94, 0x1d, 0x3f, 0x32, 0xa1, 0xa0, 0xf9, 0xe3, 0xec, 0x37,
+	0x0e, 0x00, 0x8d, 0xe7, 0x57, 0xd2, 0x21, 0xaf, 0x10, 0x05, 0x5f, 0x39, 0x8e, 0xad, 0x7f, 0x7b,
+	0x54, 0x4c, 0x7e, 0x7b, 0x84, 0x07, 0x9c, 0x18, 0x5a, 0x20, 0xcc, 0x26, 0x46, 0x98, 0x3f, 0x82,
+	0x8a, 0x47, 0x5f, 0x45, 0x36, 0x4b, 0x0f, 0x95, 0xee, 0xe5, 0x2f, 0x30, 0xf7, 0x32, 0x32, 0x93,
+	0xa9, 0x87, 0xaf, 0xe6, 0x3a, 0xa1, 0x43, 0x37, 0x8c, 0x68, 0x20, 0x67, 0xae, 0xd6, 0x3b, 0xd1,
+	0xa5, 0x91, 0xee, 0x72, 0x2d, 0xa6, 0x4a, 0x37, 0x85, 0x19, 0xbc, 0x14, 0x13, 0xf3, 0x86, 0x98,
+	0x65, 0xcc, 0xe8, 0x45, 0x44, 0x07, 0xd6, 0x78, 0x80, 0x76, 0xa6, 0x7b, 0x99, 0xed, 0x32, 0xe2,
+	0x6c, 0x97, 0xd5, 0x82, 0x1b, 0x29, 0x5e, 0x61, 0x06, 0x89, 0xd1, 0x18, 0xaf, 0x1f, 0xcd, 0x21,
+	0x7b, 0x67, 0x12, 0x4c, 0xd6, 0x8e, 0x59, 0x3e, 0xfb, 0x82, 0xf4, 0xd5, 0x3b, 0x50, 0x1d, 0xfa,
+	0x81, 0x3f, 0x8d, 0x5c, 0x8f, 0xda, 0x83, 0xe9, 0x78, 0x22, 0xbe, 0x66, 0x58, 0x54, 0xd8, 0xcd,
+	0xe9, 0x78, 0x62, 0xfd, 0x21, 0x0f, 0x37, 0x67, 0xe4, 0xaa, 0x57, 0x6a, 0x89, 0xd5, 0xb3, 0x88,
+	0x7c, 0xe7, 0x45, 0x05, 0xc2, 0x9c, 0x15, 0x9d, 0x9b, 0xa1, 0xaf, 0x22, 0xf6, 0xc2, 0xb9, 0x19,
+	0xfa, 0x22, 0x60, 0x8f, 0x6e, 0x9b, 0x1a, 0x81, 0x8c, 0x4d, 0x6a, 0x18, 0xf3, 0x3e, 0xd4, 0x4e,
+	0xa8, 0x33, 0xb1, 0x9d, 0xd1, 0xc8, 0xef, 0x6b, 0xee, 0x79, 0x81, 0x54, 0x11, 0xdf, 0x44, 0x34,
+	0xf7, 0xd0, 0xdf, 0x82, 0x05, 0xc6, 0xe9, 0x1f, 0xf1, 0x78, 0x78, 0x91, 0x71, 0xcd, 0x23, 0xae,
+	0xc3, 0x51, 0xac, 0x46, 0xf6, 0x2c, 0x14, 0x52, 0xe6, 0x18, 0xbd, 0x1c, 0x9e, 0x85, 0xbc, 0xfd,
+	0x6d, 0xa8, 0x0c, 0xfb, 0x76, 0xff, 0xac, 0x3f, 0x62, 0xc7, 0x16, 0x56, 0x9e, 0x94, 0x87, 0xfd,
+	0x16, 0x83, 0xcd, 0xf7, 0x60, 0x79, 0xd8, 0xb7, 0x27, 0xce, 0x34, 0xa4, 0x36, 0x73, 0x4f, 0x6d,
+	0x2f, 0x64, 0xb5, 0x57, 0x05, 0x52, 0x1d, 0xf6, 0x0f, 0x10, 0xdf, 0x43, 0xf4, 0x7e, 0x68, 0x6e,
+	0x40, 0xe9, 0x68, 0x7a, 0x7c, 0x8c, 0x0f, 0x19, 0x5e, 0xb9, 0x7f, 0x1f, 0xd7, 0xf0, 0x1c, 0xa5,
+	0xae, 0x6f, 0x70, 0x56, 0x7e, 0x0a, 0xca, 0x86, 0x19, 0xab, 0xc5, 0x2b, 0x7a, 0x93, 0xab, 0xd5,
+	0xf8, 0x0c, 0x16, 0xf4, 0xf6, 0x17, 0x1d, 0x93, 0x79, 0xfd, 0x98, 0xfc, 0x3b, 0x03, 0xaa, 0xc9,
+	0x8f, 0x00, 0xd4, 0xcb, 0xcc, 0xd0, 0x5e, 0x66, 0xef, 0x40, 0xf5, 0x05, 0x0d, 0x3c, 0x3a, 0x4a,
+	0x2d, 0xe1, 0x22, 0xc7, 0xca, 0x65, 0xbc, 0x05, 0x65, 0x3f, 0xb4, 0xf9, 0x1d, 0x2a, 0xee, 0x7d,
+	0x3f, 0x64, 0xd9, 0x23, 0xf3, 0xfb, 0xb0, 0xac, 0x5e, 0x72, 0x76, 0xc0, 0x75, 0x20, 0x0e, 0xc7,
+	0x9a, 0x22, 0x08, 0xdd, 0x60, 0xb8, 0xef, 0xc5, 0xf4, 0x88, 0x8e, 0x68, 0xa4, 0xfa, 0xe3, 0x67,
+	0x48, 0x55, 0xa0, 0x65, 0x87, 0x9f, 0x26, 0x5e, 0x8c, 0xbc, 0x10, 0xbb, 0xce, 0x1f, 0x53, 0x02,
+	0xab, 0xcd, 0x2c, 0xf1, 0x96, 0xfc, 0x3f, 0x06, 0xac, 0x64, 0x31, 0x5d, 0xde, 0xe5, 0xc0, 0xd9,
+	0xb2, 0x1f, 0xb6, 0xab, 0xaa, 0xcc, 0x18, 0xbc, 0x33, 0x30, 0x3f, 0x84, 0x3c, 0xf5, 0x4e, 0xd9,
+	0x13, 0x76, 0xfe, 0xe1, 0x5b, 0xe7, 0x0d, 0x68, 0xbd, 0xed, 0x9d, 0xf2, 0x25, 0x47, 0xee, 0xc6,
+	0x27, 0x50, 0x96, 0x88, 0x2b, 0x5d, 0x75, 0x3f, 0x83, 0x86, 0x48, 0xbd, 0x6a, 0xb2, 0xaf, 0x94,
+	0xbc, 0xfd, 0x4f, 0x06, 0xdc, 0xce, 0x14, 0xa1, 0xe2, 0x1b, 0xb1, 0x8c, 0xec, 0x2f, 0x47, 0xb8,
+	0x5c, 0x4b, 0xc9, 0xcd, 0xe6, 0xc2, 0x47, 0xe7, 0x43, 0x28, 0x61, 0xc5, 0xdf, 0x90, 0xf2, 0x9c,
+	0x97, 0x58, 0xaf, 0x24, 0x63, 0x8b, 0x31, 0x10, 0xc9, 0x68, 0x1d, 0xc0, 0x4a, 0x16, 0xc3, 0x39,
+	0x9f, 0xdf, 0x99, 0xda, 0x93, 0x39, 0x39, 0xe3, 0xbc, 0x9a, 0xf1, 0xbf, 0x36, 0x58, 0x61, 0x69,
+	0xfc, 0x19, 0x8b, 0xf9, 0x7d, 0x98, 0x63, 0x5f, 0x34, 0xc9, 0x33, 0xf7, 0xba, 0x5e, 0x5a, 0x28,
+	0x98, 0x88, 0x60, 0x61, 0xf5, 0x54, 0x6e, 0x10, 0x46, 0x36, 0xaf, 0xdf, 0xe2, 0x3d, 0x01, 0x43,
+	0xb5, 0x11, 0x83, 0x9f, 0x5c, 0x68, 0x0c, 0x36, 0x6b, 0x26, 0xba, 0x5f, 0x8a, 0xd9, 0x98, 0x6c,
+	0x6b, 0x0c, 0x4b, 0xa9, 0x7e, 0x32, 0x8d, 0x70, 0x15, 0xe6, 0x98, 0x30, 0xf9, 0x29, 0x88, 0x80,
+	0xf0, 0xd6, 0x7e, 0xe9, 0x04, 0x9e, 0xeb, 0x0d, 0x65, 0x4c, 0x43, 0xc1, 0x28, 0xc7, 0xf5, 0x8e,
+	0x7d, 0x11, 0xca, 0x60, 0xbf, 0xd7, 0x1e, 0x42, 0x49, 0x7c, 0xd2, 0x69, 0x2e, 0xc3, 0xe2, 0x93,
+	0xce, 0x86, 0xfd, 0x74, 0xa7, 0xfd, 0xcc, 0x7e, 0x7c, 0xb8, 0xbb, 0x5b, 0xbb, 0x66, 0xae, 0x40,
+	0x4d, 0xa1, 0xba, 0x87, 0x7b, 0x7b, 0x4d, 0xf2, 0xbc, 0x66, 0xac, 0xd9, 0x50, 0x96, 0x5f, 0x4a,
+	0x9a, 0x8b, 0x50, 0xe9, 0x1c, 0xd8, 0xed, 0xaf, 0x0e, 0x9b, 0xbb, 0xdd, 0xda, 0x35, 0xd3, 0x84,
+	0x6a, 0xe7, 0xc0, 0xee, 0xf6, 0x9a, 0xa4, 0xd7, 0xb5, 0x9f, 0xed, 0xf4, 0xb6, 0x6b, 0x86, 0x59,
+	0x83, 0x05, 0x64, 0xd9, 0xdf, 0x14, 0x98, 0x9c, 0xb9, 0x04, 0xf3, 0x9d, 0x03, 0xbb, 0xd5, 0xd9,
+	0xef, 0x35, 0x77, 0xf6, 0xbb, 0xb5, 0xbc, 0x94, 0xf2, 0xf5, 0x4e, 0xb7, 0xd7, 0xad, 0x15, 0xd6,
+	0x9e, 0xc2, 0xf2, 0xcc, 0x57, 0x73, 0x38, 0xbc, 0xdd, 0xce, 0x56, 0xd7, 0xde, 0xdc, 0xe9, 0x36,
+	0x37, 0x76, 0xdb, 0x9b, 0xb5, 0x6b, 0x0a, 0x75, 0xb8, 0xdf, 0xdd, 0xdd, 0x69, 0xb5, 0x37, 0x6b,
+	0x86, 0xb9, 0x00, 0x65, 0x86, 0x22, 0xcd, 0x67, 0xb5, 0x1c, 0xca, 0x65, 0xd0, 0x76, 0x6f, 0x6f,
+	0xb7, 0x96, 0x5f, 0xfb, 0x0b, 0x03, 0x20, 0xfe, 0x60, 0xc4, 0xbc, 0x0e, 0x4b, 0x3d, 0xb2, 0xb3,
+	0xb5, 0xd5, 0x26, 0xf6, 0xe1, 0xfe, 0x97, 0xfb, 0x9d, 0x67, 0xfb, 0x7c, 0x06, 0x12, 0xb9, 0xd7,
+	0xdc, 0x3f, 0x6c, 0xee, 0xf2, 0x19, 0x48, 0xdc, 0xc1, 0x61, 0x17, 0x67, 0xa0, 0x35, 0xdd, 0x6c,
+	0xef, 0xb6, 0x7b, 0xed, 0xcd, 0x5a, 0x1e, 0xa7, 0x25, 0x91, 0xbd, 0xe6, 0x56, 0xad, 0x60, 0xd6,
+	0x61, 0x25, 0x6e, 0xb7, 0xbb, 0x6b, 0x93, 0xf6, 0x57, 0x87, 0xed, 0x6e, 0xaf, 0x56, 0x34, 0x6f,
+	0xc0, 0xb2, 0xa4, 0x74, 0x5b, 0xdb, 0xed, 0xcd, 0x43, 0x9c, 0xd0, 0x1c, 0xea, 0x5b, 0xa2, 0x9b,
+	0xa4, 0xb7, 0xf3, 0xb8, 0xd9, 0xea, 0xd5, 0x4a, 0x3a, 0xf6, 0xf0, 0xa0, 0xdb, 0x23, 0xed, 0xe6,
+	0x5e, 0xad, 0x6c, 0xde, 0x84, 0xeb, 0x6a, 0xa0, 0x6d, 0xb2, 0xd5, 0xb6, 0xb7, 0x48, 0xe7, 0xf0,
+	0xa0, 0x56, 0x59, 0xfb, 0x0d, 0xaf, 0xe3, 0x66, 0x45, 0xd5, 0xa8, 0xa2, 0x83, 0xed, 0x66, 0xb7,
+	0xad, 0xcd, 0xf0, 0x3a, 0x2c, 0x71, 0xd4, 0x01, 0x69, 0x1f, 0x34, 0xc9, 0xce, 0xfe, 0x56, 0xcd,
+	0xc0, 0x69, 0x73, 0x24, 0x5b, 0x3b, 0xc4, 0xe5, 0xe2, 0xb6, 0xe4, 0x70, 0x7f, 0x1f, 0x51, 0x79,
+	0xb3, 0x0a, 0xc0, 0x51, 0x9b, 0x9d, 0xfd, 0x76, 0xad, 0x10, 0xb3, 0xb4, 0x76, 0xdb, 0xcd, 0xfd,
+	0xc3, 0x83, 0x5a, 0x31, 0x46, 0x3d, 0x6b, 0xee, 0x30, 0x41, 0x73, 0x6b, 0xff, 0x36, 0xc7, 0x02,
+	0x33, 0xaa, 0x7a, 0x1c, 0x79, 0xda, 0x4f, 0xdb, 0xfb, 0x3d, 0x6d, 0x54, 0x0a, 0xd5, 0x22, 0xed,
+	0x66, 0x8f, 0xad, 0x65, 0x0d, 0x16, 0x38, 0xea, 0xab, 0xc3, 0xf6, 0x61, 0x7b, 0xb3, 0x96, 0xc3,
+	0x39, 0x73, 0xcc, 0x41, 0x67, 0x53, 0x53, 0x5c, 0x5e, 0x23, 0xf0, 0xd1, 0x6c, 0x37, 0xf7, 0xb7,
+	0xda, 0x9b, 0xb5, 0x82, 0xd9, 0x80, 0x55, 0x21, 0xb6, 0xb9, 0xdf, 0x6a, 0xab, 0x25, 0x68, 0x6f,
+	0xf2, 0x45, 0x88, 0xa5, 0xc9, 0x65, 0x9c, 0x8b, 0x9b, 0x3c, 0x6b, 0x6f, 0x6c, 0x77, 0x3a, 0x5f,
+	0xda, 0xa4, 0xdd, 0x6a, 0xef, 0x3c, 0x6d, 0x6f, 0xd6, 0x4a, 0xf1, 0x28, 0x25, 0x7b, 0x19, 0x35,
+	0xc7, 0x51, 0xcd, 0x83, 0x03, 0xd2, 0x41, 0xb6, 0x8a, 0x79, 0x07, 0xea, 0xa2, 0x57, 0x6e, 0xe3,
+	0x6d, 0xd2, 0xb5, 0xbb, 0xbd, 0xce, 0xc1, 0x41, 0x7b, 0xb3, 0x06, 0x6b, 0xff, 0xc6, 0x80, 0x05,
+	0xbd, 0x4c, 0x19, 0x57, 0x84, 0x19, 0xb0, 0xdd, 0xdc, 0x68, 0xee, 0xa3, 0x66, 0xd1, 0xb8, 0x97,
+	0x60, 0x9e, 0x23, 0xd9, 0x94, 0x6a, 0x46, 0x8c, 0x60, 0x4b, 0xc4, 0xd7, 0x87, 0x23, 0xb0, 0x97,
+	0xf6, 0x7e, 0x8f, 0xaf, 0x0f, 0x47, 0x89, 0xf5, 0x51, 0xf0, 0xe3, 0xe6, 0xce, 0x6e, 0xad, 0x88,
+	0x2a, 0xe5, 0x30, 0x69, 0x77, 0x0f, 0x77, 0x7b, 0xb5, 0xb9, 0xb5, 0xbf, 0x37, 0x00, 0xe2, 0x4a,
+	0x43, 0x64, 0xc0, 0x75, 0x4b, 0x6e, 0x08, 0x86, 0x89, 0xd5, 0x6d, 0x98, 0xab, 0x60, 0x32, 0x1c,
+	0x69, 0xf7, 0xc8, 0x73, 0x7b, 0xa3, 0xd9, 0xfa, 0xb2, 0xf3, 0xf8, 0x71, 0x2d, 0x87, 0x96, 0xca,
+	0xf0, 0xa8, 0xd0, 0x83, 0xf6, 0xfe, 0x26, 0x37, 0x1a, 0x89, 0xdd, 0x6b, 0xee, 0xe0, 0x38, 0x71,
+	0x21, 0x6a, 0x05, 0xf3, 0x16, 0xdc, 0x60, 0xd8, 0xf6, 0xd7, 0xed, 0xd6, 0x61, 0x6f, 0xa7, 0xb3,
+	0x6f, 0x3f, 0xdb, 0xd9, 0xdf, 0xec, 0x3c, 0xe3, 0x26, 0xc4, 0x48, 0xad, 0xe6, 0x41, 0xb3, 0xb5,
+	0xd3, 0x7b, 0x5e, 0x9b, 0x53, 0x28, 0xae, 0xe4, 0xe6, 0x6e, 0xad, 0x84, 0xeb, 0xc4, 0xb9, 0x3a,
+	0xfb, 0xad, 0x43, 0x42, 0xda, 0xfb, 0xad, 0xe7, 0xf6, 0xee, 0xce, 0xde, 0x4e, 0xaf, 0x56, 0x46,
+	0x8d, 0xf2, 0x01, 0x36, 0x7b, 0x6d, 0x81, 0xac, 0x28, 0xe4, 0x66, 0x1b, 0xc7, 0x86, 0xfc, 0x35,
+	0x58, 0x7b, 0x00, 0x0b, 0x7a, 0x9d, 0x14, 0x33, 0xba, 0xaf, 0x0f, 0x3a, 0xa4, 0x67, 0x3f, 0xe9,
+	0x76, 0xf6, 0xf1, 0x10, 0xac, 0x02, 0x08, 0x4c, 0xab, 0xfb, 0xb4, 0x66, 0xac, 0x7d, 0x09, 0x0b,
+	0x7a, 0x74, 0x16, 0x95, 0xd1, 0xea, 0x74, 0x7b, 0xf6, 0xc6, 0x73, 0x9b, 0xb4, 0x0f, 0x3a, 0xdd,
+	0x9d, 0x5e, 0x87, 0x3c, 0xaf, 0x5d, 0x43, 0x49, 0x12, 0xdf, 0xc3, 0x2d, 0x6b, 0xe0, 0x24, 0x24,
+	0x66, 0xaf, 0xb3, 0x8f, 0x47, 0xe1, 0xda, 0x2f, 0x60, 0x29, 0x15, 0x37, 0xc1, 0x61, 0x6e, 0x34,
+	0x7b, 0xad, 0x6d, 0xbb, 0x7b, 0xd8, 0x6a, 0xb5, 0xdb, 0x9b, 0xcc, 0x1a, 0x6a, 0xb0, 0xc0, 0x91,
+	0xb8, 0x90, 0x6c, 0x0d, 0x96, 0x61, 0x51, 0xb0, 0x7d, 0xb9, 0xc3, 0x0c, 0x2b, 0x17, 0xa3, 0x36,
+	0xc9, 0x73, 0xdc, 0xb4, 0xb5, 0xfc, 0xc3, 0xdf, 0xd7, 0x61, 0xe1, 0x19, 0x0d, 0x8e, 0x23, 0x7c,
+	0x69, 0xe3, 0x87, 0xc4, 0x2d, 0x58, 0x4c, 0xfc, 0xc7, 0x0d, 0x93, 0xdd, 0xb8, 0x59, 0xff, 0x84,
+	0xa3, 0xb1, 0xa2, 0x28, 0x7a, 0xd2, 0xf3, 0xda, 0x7d, 0xc3, 0x6c, 0x41, 0x35, 0xf9, 0x1f, 0x29,
+	0xcc, 0x5b, 0x8a, 0x37, 0xfd, 0x5f, 0x2a, 0xce, 0x13, 0x63, 0x76, 0x60, 0x25, 0xeb, 0xbf, 0x37,
+	0x98, 0x77, 0x15, 0x7f, 0xf6, 0xff, 0x75, 0x38, 0x57, 0xe0, 0x8f, 0xa0, 0x2c, 0xbf, 0xa5, 0x37,
+	0xaf, 0xcb, 0x4f, 0xaf, 0xb5, 0xb8, 0x61, 0x63, 0x25, 0x89, 0x54, 0x0d, 0x7f, 0x02, 0x15, 0xf5,
+	0xc5, 0xbb, 0xc9, 0xa5, 0xa7, 0x3e, 0xa1, 0x6f, 0xdc, 0x48, 0x61, 0x65, 0xdb, 0x07, 0x86, 0xf9,
+	0x01, 0xcc, 0xf1, 0x60, 0x93, 0xb9, 0x2c, 0xbc, 0x7a, 0x6d, 0xac, 0xa6, 0x8e, 0x52, 0x1d, 0x7e,
+	0x08, 0x73, 0xfc, 0x82, 0xe3, 0x4d, 0x12, 0x97, 0x5d, 0xc3, 0xd4, 0x51, 0x5a, 0x3f, 0x1f, 0x41,
+	0x49, 0x7c, 0x86, 0x60, 0x9a, 0x5c, 0x03, 0xfa, 0x97, 0x0b, 0x8d, 0xeb, 0x09, 0x9c, 0xea, 0xea,
+	0xa7, 0x50, 0x51, 0x15, 0xf2, 0x7c, 0x6e, 0xe9, 0xef, 0x16, 0x1a, 0x37, 0x52, 0xd8, 0x78, 0xa1,
+	0x1f, 0x18, 0xe6, 0x2e, 0xff, 0x17, 0x16, 0x5a, 0x6d, 0xb7, 0xd9, 0x90, 0x03, 0x9c, 0x2d, 0x10,
+	0x6f, 0xdc, 0xce, 0xa4, 0x69, 0x6b, 0x5e, 0x4b, 0x57, 0x69, 0x9b, 0xb7, 0x45, 0xe0, 0x20, 0xab,
+	0xf8, 0xbb, 0x71, 0x27, 0x9b, 0xa8, 0x04, 0xee, 0xb0, 0xff, 0x03, 0xa0, 0x55, 0x70, 0x73, 0x4b,
+	0xcc, 0x2c, 0xf7, 0x6e, 0x34, 0xb2, 0x48, 0x4a, 0xd4, 0x21, 0x98, 0xb3, 0x95, 0xc7, 0xe6, 0x1b,
+	0x4c, 0xad, 0xe7, 0x95, 0x12, 0x37, 0xde, 0x3c, 0x8f, 0xac, 0x8b, 0xdd, 0x3a, 0x47, 0xec, 0xd6,
+	0xeb, 0xc5, 0x6e, 0xbd, 0x4e, 0x6c, 0x0b, 0x16, 0xf4, 0x42, 0x5d, 0xf3, 0xa6, 0x68, 0x91, 0xae,
+	0x0b, 0x6e, 0xd4, 0x67, 0x09, 0x4a, 0xc8, 0x17, 0x00, 0x71, 0x31, 0xa8, 0x79, 0x23, 0x2e, 0x1a,
+	0xd5, 0x05, 0xac, 0xa6, 0xd1, 0x9a, 0x4d, 0xb6, 0x60, 0x41, 0x2f, 0xf4, 0xe4, 0xa3, 0xc8, 0xa8,
+	0x1a, 0x6d, 0xd4, 0x67, 0x09, 0xba, 0x51, 0xa4, 0x8b, 0x33, 0xb9, 0x51, 0x9c, 0x53, 0xe1, 0xd9,
+	0xb8, 0x93, 0x4d, 0x54, 0x02, 0x77, 0x61, 0x29, 0x55, 0xd2, 0xc8, 0x6d, 0x36, 0xbb, 0x32, 0xb2,
+	0x71, 0x3b, 0x93, 0xa6, 0xa4, 0x7d, 0x0e, 0x10, 0xd7, 0x31, 0x72, 0x25, 0xcd, 0x54, 0x3b, 0x36,
+	0x56, 0xd3, 0xe8, 0xd4, 0x42, 0xa9, 0x9a, 0x42, 0xb5, 0x50, 0xe9, 0x82, 0xc4, 0x46, 0x7d, 0x96,
+	0xa0, 0x0b, 0xd1, 0x8b, 0xfd, 0xb8, 0x90, 0x8c, 0xaa, 0xc0, 0x46, 0x7d, 0x96, 0x90, 0xd2, 0x73,
+	0xa2, 0x16, 0x4e, 0xe9, 0x39, 0xab, 0x0c, 0xb0, 0x71, 0x27, 0x9b, 0xa8, 0x04, 0x3e, 0x66, 0xff,
+	0xed, 0x43, 0xab, 0x4d, 0xab, 0xab, 0x0d, 0x96, 0xaa, 0x8c, 0x6b, 0xdc, 0xca, 0xa0, 0xe8, 0xeb,
+	0x95, 0x2a, 0xca, 0x32, 0xe5, 0x56, 0xcd, 0x28, 0x05, 0x6b, 0xdc, 0xce, 0xa4, 0x29, 0x69, 0x9f,
+	0x41, 0x45, 0x95, 0xea, 0xf0, 0x13, 0x2f, 0x5d, 0x04, 0xd4, 0xb8, 0x91, 0xc2, 0xea, 0x57, 0x88,
+	0x2c, 0xca, 0xe1, 0x57, 0x48, 0xaa, 0xbe, 0xa7, 0xb1, 0x92, 0x44, 0xea, 0x46, 0x12, 0xd7, 0xcf,
+	0x70, 0x23, 0x99, 0xa9, 0xda, 0x69, 0xac, 0xa6, 0xd1, 0x89, 0xe6, 0xaa, 0xe8, 0x45, 0x34, 0x4f,
+	0x17, 0xd9, 0x34, 0x56, 0xd3, 0x68, 0x5d, 0x81, 0xa9, 0x92, 0x15, 0xae, 0xc0, 0xec, 0x8a, 0x98,
+	0xc6, 0xed, 0x4c, 0x5a, 0x6a, 0x39, 0x66, 0xa5, 0x6d, 0xbd, 0x46, 0xda, 0xd6, 0xb9, 0xd2, 0xb8,
+	0xfd, 0xab, 0x02, 0x0e, 0x65, 0xff, 0xe9, 0x42, 0x92, 0x46, 0x7d, 0x96, 0xa0, 0x84, 0xfc, 0x0c,
+	0xe6, 0xb5, 0x52, 0x0b, 0x53, 0xee, 0xb6, 0x54, 0x5d, 0x47, 0xe3, 0xe6, 0x0c, 0x3e, 0x25, 0x41,
+	0x66, 0xab, 0x95, 0x84, 0x54, 0x3a, 0xbe, 0x71, 0x73, 0x06, 0xaf, 0x24, 0x10, 0x96, 0x93, 0x4a,
+	0xe5, 0x6f, 0xe5, 0x16, 0xc9, 0x4c, 0x8e, 0x36, 0xde, 0x38, 0x87, 0xaa, 0x64, 0xfe, 0x18, 0xa0,
+	0x85, 0x87, 0xd7, 0x88, 0x1d, 0xc0, 0x2b, 0x7a, 0x1a, 0x2d, 0x4c, 0x18, 0xeb, 0x4c, 0x1e, 0x91,
+	0x1b, 0x3a, 0xa1, 0x51, 0x70, 0xf6, 0x6d, 0xda, 0xf2, 0x43, 0x4d, 0xe6, 0xba, 0x6e, 0xc4, 0xb3,
+	0xd6, 0x12, 0x6e, 0x8d, 0xd5, 0x34, 0x5a, 0xf3, 0x98, 0x16, 0xf4, 0xa4, 0x16, 0x5f, 0xd4, 0x8c,
+	0x34, 0x57, 0x63, 0x29, 0x95, 0xe5, 0x61, 0xb7, 0x06, 0xde, 0xb4, 0x33, 0x99, 0x0f, 0x71, 0xd3,
+	0x9e, 0x97, 0xa5, 0x69, 0xbc, 0x79, 0x1e, 0x59, 0x5f, 0xa0, 0x99, 0x68, 0xbc, 0x29, 0x1c, 0x88,
+	0xec, 0x54, 0x40, 0xe3, 0x8d, 0x73, 0xa8, 0xfa, 0x11, 0x97, 0x08, 0xcc, 0x9b, 0xea, 0x80, 0x9d,
+	0x91, 0x75, 0x2b, 0x83, 0x92, 0xda, 0x53, 0x7a, 0xb8, 0x57, 0xed, 0xa9, 0x8c, 0x80, 0x7d, 0xe3,
+	0x76, 0x26, 0x4d, 0x49, 0xfb, 0x5a, 0x7d, 0x9a, 0xa1, 0x47, 0xe8, 0xcc, 0x37, 0xb5, 0x4b, 0x36,
+	0x23, 0xfa, 0xd7, 0xb8, 0x7b, 0x2e, 0x5d, 0x4a, 0x3e, 0x9a, 0x63, 0x71, 0xfb, 0x0f, 0xff, 0x61,
+	0x00, 0xeb, 0xf1, 0x41, 0x1e, 0xcc, 0x4f, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// WerftServiceClient is the client API for WerftService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type WerftServiceClient interface {
+	// StartLocalJob starts a job by uploading the workspace content directly. The incoming requests are expected in the following order:
+	//   1. metadata
+	//   2. all bytes constituting the werft/config.yaml
+	//   3. all bytes constituting the job YAML that will be executed (that the config.yaml points to)
+	//   4. all bytes constituting the gzipped workspace tar stream
+	//   5. the workspace tar stream done marker
+	StartLocalJob(ctx context.Context, opts ...grpc.CallOption) (WerftService_StartLocalJobClient, error)
+	// StartGitHubJob starts a job on a Git context, possibly with a custom job.
+	StartGitHubJob(ctx context.Context, in *StartGitHubJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error)
+	// StartFromPreviousJob starts a new job based on a previous one.
+	// If the previous job does not have the can-replay condition set this call will result in an error.
+	StartFromPreviousJob(ctx context.Context, in *StartFromPreviousJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error)
+	// Searches for jobs known to this instance
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	// Subscribe listens to new jobs/job updates
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WerftService_SubscribeClient, error)
+	// GetJob retrieves details of a single job
+	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error)
+	// Listen listens to job updates and log output of a running job
+	Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (WerftService_ListenClient, error)
+	// StopJob stops a currently running job
+	StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*StopJobResponse, error)
+	// AttachJob runs an interactive command (a shell by default) in the pod of a running job.
+	// The first request must start the session, all subsequent ones carry input or terminal size changes.
+	AttachJob(ctx context.Context, opts ...grpc.CallOption) (WerftService_AttachJobClient, error)
+	// ListDeadLetters lists webhook events which failed processing
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	// ReplayDeadLetter processes a failed webhook event again. If processing succeeds, the event is removed from the dead letter queue.
+	ReplayDeadLetter(ctx context.Context, in *ReplayDeadLetterRequest, opts ...grpc.CallOption) (*ReplayDeadLetterResponse, error)
+	// GetQueueStatus lists the jobs which wait to run, and why they are waiting
+	GetQueueStatus(ctx context.Context, in *GetQueueStatusRequest, opts ...grpc.CallOption) (*GetQueueStatusResponse, error)
+	// SetMaintenanceMode pauses or resumes job processing. While paused, werft accepts webhooks and jobs but
+	// does not start them until job processing resumes.
+	SetMaintenanceMode(ctx context.Context, in *SetMaintenanceModeRequest, opts ...grpc.CallOption) (*SetMaintenanceModeResponse, error)
+	// GetMaintenanceMode returns whether job processing is paused
+	GetMaintenanceMode(ctx context.Context, in *GetMaintenanceModeRequest, opts ...grpc.CallOption) (*GetMaintenanceModeResponse, error)
+	// GetFlakyJobs finds jobs of a repository which alternate between success and failure, most flaky first
+	GetFlakyJobs(ctx context.Context, in *GetFlakyJobsRequest, opts ...grpc.CallOption) (*GetFlakyJobsResponse, error)
+	// ExportJobs dumps the records of all jobs matching a filter and time range, e.g. to build reports.
+	// Exporting requires one of the export tokens configured for werft.
+	ExportJobs(ctx context.Context, in *ExportJobsRequest, opts ...grpc.CallOption) (WerftService_ExportJobsClient, error)
+	// DiffJobSpecs compares the resolved job specs two jobs ran with, e.g. to find out what changed between
+	// a successful and a failed run
+	DiffJobSpecs(ctx context.Context, in *DiffJobSpecsRequest, opts ...grpc.CallOption) (*DiffJobSpecsResponse, error)
+	// GetJobProvenance returns the signed provenance of a finished job, which attests how its results were built.
+	// Provenance is only recorded if werft has a signing key.
+	GetJobProvenance(ctx context.Context, in *GetJobProvenanceRequest, opts ...grpc.CallOption) (*GetJobProvenanceResponse, error)
+	// FindImageBuilds returns the jobs which built a container image, identified by its digest.
+	// Jobs report the images they built as results of type image.
+	FindImageBuilds(ctx context.Context, in *FindImageBuildsRequest, opts ...grpc.CallOption) (*FindImageBuildsResponse, error)
+	// GetVersion returns the version of the server, so that clients can check if they match it
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
+	// GetJobEvents returns the timeline of a job, i.e. what happened to the job when, oldest first
+	GetJobEvents(ctx context.Context, in *GetJobEventsRequest, opts ...grpc.CallOption) (*GetJobEventsResponse, error)
+	// ListProjects lists the projects configured for werft, i.e. groups of related repositories
+	ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error)
+	// GetProjectHealth returns the latest job of each repository of a project
+	GetProjectHealth(ctx context.Context, in *GetProjectHealthRequest, opts ...grpc.CallOption) (*GetProjectHealthResponse, error)
+	// GetQuotaUsage returns the current resource consumption of the configured quotas
+	GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error)
+	// GetStartLatency returns the time recent jobs took from the webhook which started them until their pod ran
+	GetStartLatency(ctx context.Context, in *GetStartLatencyRequest, opts ...grpc.CallOption) (*GetStartLatencyResponse, error)
+	// DeleteJob hides a finished job from job listings, but keeps all of its data. Deleting a job requires
+	// write permission on the job's repository on GitHub.
+	DeleteJob(ctx context.Context, in *DeleteJobRequest, opts ...grpc.CallOption) (*DeleteJobResponse, error)
+	// PurgeJob removes a finished job and all of its data, i.e. its record, logs, job spec, events, provenance
+	// and image builds. Purging requires one of the admin tokens configured for werft.
+	PurgeJob(ctx context.Context, in *PurgeJobRequest, opts ...grpc.CallOption) (*PurgeJobResponse, error)
+	// ApproveJob lets a job which waits for approval start. Who can approve a job depends on the approval policy
+	// of its job spec, but always requires write permission on the job's repository on GitHub.
+	ApproveJob(ctx context.Context, in *ApproveJobRequest, opts ...grpc.CallOption) (*ApproveJobResponse, error)
+	// AddJobNote attaches a note to a job, e.g. why it failed. Adding notes requires write permission on the
+	// job's repository on GitHub.
+	AddJobNote(ctx context.Context, in *AddJobNoteRequest, opts ...grpc.CallOption) (*AddJobNoteResponse, error)
+	// SetAnnouncement sets the announcement werft shows its users, e.g. upcoming cluster maintenance, or clears it.
+	// Jobs started while there is an announcement show it in their logs. Setting the announcement requires one of
+	// the admin tokens configured for werft.
+	SetAnnouncement(ctx context.Context, in *SetAnnouncementRequest, opts ...grpc.CallOption) (*SetAnnouncementResponse, error)
+	// GetAnnouncement returns the current announcement, if there is one
+	GetAnnouncement(ctx context.Context, in *GetAnnouncementRequest, opts ...grpc.CallOption) (*GetAnnouncementResponse, error)
+	// GetLatestJob returns the most recent job of a ref, e.g. to find out if the main branch is green
+	GetLatestJob(ctx context.Context, in *GetLatestJobRequest, opts ...grpc.CallOption) (*GetLatestJobResponse, error)
+	// GetBranches returns the branches of a repository which had jobs recently, with their latest jobs
+	GetBranches(ctx context.Context, in *GetBranchesRequest, opts ...grpc.CallOption) (*GetBranchesResponse, error)
+	// GetJobCosts sums up the estimated cost of jobs per repository, team and/or month, e.g. for chargeback
+	// reporting. Costs are only known if werft is configured with resource prices.
+	GetJobCosts(ctx context.Context, in *GetJobCostsRequest, opts ...grpc.CallOption) (*GetJobCostsResponse, error)
+	// GetTriggerPayload returns what triggered a job, e.g. the body of the GitHub webhook which started it.
+	// Werft keeps the payload as long as it keeps the job.
+	GetTriggerPayload(ctx context.Context, in *GetTriggerPayloadRequest, opts ...grpc.CallOption) (*GetTriggerPayloadResponse, error)
+	// CancelJobs stops all waiting or running jobs matching a filter. Cancelling jobs requires write permission on
+	// their repositories on GitHub.
+	CancelJobs(ctx context.Context, in *BatchJobsRequest, opts ...grpc.CallOption) (*BatchJobsResponse, error)
+	// RetryJobs starts all finished jobs matching a filter again, like StartFromPreviousJob does. Retrying jobs requires
+	// write permission on their repositories on GitHub.
+	RetryJobs(ctx context.Context, in *BatchJobsRequest, opts ...grpc.CallOption) (*BatchJobsResponse, error)
+	// GetJobSBOM returns the software bills of materials of the images a job built. SBOMs are only generated if
+	// werft is configured to do so.
+	GetJobSBOM(ctx context.Context, in *GetJobSBOMRequest, opts ...grpc.CallOption) (*GetJobSBOMResponse, error)
+	// StreamEvents streams all server events, e.g. job updates, queued jobs or central config changes, which match
+	// a filter expression.
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (WerftService_StreamEventsClient, error)
+	// SyncRequiredChecks makes the werft status checks among the required status checks of a protected branch match
+	// the jobs the repository's werft config starts. Status checks of other CI systems are left alone. Syncing
+	// requires one of the admin tokens configured for werft.
+	SyncRequiredChecks(ctx context.Context, in *SyncRequiredChecksRequest, opts ...grpc.CallOption) (*SyncRequiredChecksResponse, error)
+	// RegisterSchedules is called by schedulers, e.g. the cron plugin, to tell werft which jobs they start on a
+	// schedule. The schedules replace those the scheduler registered before. Werft keeps them in memory only.
+	RegisterSchedules(ctx context.Context, in *RegisterSchedulesRequest, opts ...grpc.CallOption) (*RegisterSchedulesResponse, error)
+	// ListSchedules returns the registered schedules and their next runs, e.g. to verify cron specs and time zones
+	ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesResponse, error)
+	// GetRuntimeStats returns the internal state of the werft server, e.g. its goroutines, memory and the size of
+	// its queues and buffers, to diagnose hangs and memory growth in production. It requires one of the admin tokens
+	// configured for werft.
+	GetRuntimeStats(ctx context.Context, in *GetRuntimeStatsRequest, opts ...grpc.CallOption) (*GetRuntimeStatsResponse, error)
+	// DiffJobEnvironments compares the environments two jobs ran in, i.e. the digests their images resolved to, their
+	// node and the environment variables of their containers, e.g. to find out whether the environment changed
+	// when a build regressed.
+	DiffJobEnvironments(ctx context.Context, in *DiffJobEnvironmentsRequest, opts ...grpc.CallOption) (*DiffJobEnvironmentsResponse, error)
+}
+
+type werftServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWerftServiceClient(cc *grpc.ClientConn) WerftServiceClient {
+	return &werftServiceClient{cc}
+}
+
+func (c *werftServiceClient) StartLocalJob(ctx context.Context, opts ...grpc.CallOption) (WerftService_StartLocalJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[0], "/v1.WerftService/StartLocalJob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &werftServiceStartLocalJobClient{stream}
+	return x, nil
+}
+
+type WerftService_StartLocalJobClient interface {
+	Send(*StartLocalJobRequest) error
+	CloseAndRecv() (*StartJobResponse, error)
+	grpc.ClientStream
+}
+
+type werftServiceStartLocalJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *werftServiceStartLocalJobClient) Send(m *StartLocalJobRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *werftServiceStartLocalJobClient) CloseAndRecv() (*StartJobResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(StartJobResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *werftServiceClient) StartGitHubJob(ctx context.Context, in *StartGitHubJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
+	out := new(StartJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/StartGitHubJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) StartFromPreviousJob(ctx context.Context, in *StartFromPreviousJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
+	out := new(StartJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/StartFromPreviousJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WerftService_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[1], "/v1.WerftService/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &werftServiceSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WerftService_SubscribeClient interface {
+	Recv() (*SubscribeResponse, error)
+	grpc.ClientStream
+}
+
+type werftServiceSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *werftServiceSubscribeClient) Recv() (*SubscribeResponse, error) {
+	m := new(SubscribeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *werftServiceClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error) {
+	out := new(GetJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (WerftService_ListenClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[2], "/v1.WerftService/Listen", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &werftServiceListenClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WerftService_ListenClient interface {
+	Recv() (*ListenResponse, error)
+	grpc.ClientStream
+}
+
+type werftServiceListenClient struct {
+	grpc.ClientStream
+}
+
+func (x *werftServiceListenClient) Recv() (*ListenResponse, error) {
+	m := new(ListenResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *werftServiceClient) StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*StopJobResponse, error) {
+	out := new(StopJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/StopJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) AttachJob(ctx context.Context, opts ...grpc.CallOption) (WerftService_AttachJobClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[3], "/v1.WerftService/AttachJob", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &werftServiceAttachJobClient{stream}
+	return x, nil
+}
+
+type WerftService_AttachJobClient interface {
+	Send(*AttachJobRequest) error
+	Recv() (*AttachJobResponse, error)
+	grpc.ClientStream
+}
+
+type werftServiceAttachJobClient struct {
+	grpc.ClientStream
+}
+
+func (x *werftServiceAttachJobClient) Send(m *AttachJobRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *werftServiceAttachJobClient) Recv() (*AttachJobResponse, error) {
+	m := new(AttachJobResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *werftServiceClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ReplayDeadLetter(ctx context.Context, in *ReplayDeadLetterRequest, opts ...grpc.CallOption) (*ReplayDeadLetterResponse, error) {
+	out := new(ReplayDeadLetterResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ReplayDeadLetter", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetQueueStatus(ctx context.Context, in *GetQueueStatusRequest, opts ...grpc.CallOption) (*GetQueueStatusResponse, error) {
+	out := new(GetQueueStatusResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetQueueStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) SetMaintenanceMode(ctx context.Context, in *SetMaintenanceModeRequest, opts ...grpc.CallOption) (*SetMaintenanceModeResponse, error) {
+	out := new(SetMaintenanceModeResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/SetMaintenanceMode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetMaintenanceMode(ctx context.Context, in *GetMaintenanceModeRequest, opts ...grpc.CallOption) (*GetMaintenanceModeResponse, error) {
+	out := new(GetMaintenanceModeResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetMaintenanceMode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetFlakyJobs(ctx context.Context, in *GetFlakyJobsRequest, opts ...grpc.CallOption) (*GetFlakyJobsResponse, error) {
+	out := new(GetFlakyJobsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetFlakyJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ExportJobs(ctx context.Context, in *ExportJobsRequest, opts ...grpc.CallOption) (WerftService_ExportJobsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[4], "/v1.WerftService/ExportJobs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &werftServiceExportJobsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WerftService_ExportJobsClient interface {
+	Recv() (*ExportJobsResponse, error)
+	grpc.ClientStream
+}
+
+type werftServiceExportJobsClient struct {
+	grpc.ClientStream
+}
+
+func (x *werftServiceExportJobsClient) Recv() (*ExportJobsResponse, error) {
+	m := new(ExportJobsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *werftServiceClient) DiffJobSpecs(ctx context.Context, in *DiffJobSpecsRequest, opts ...grpc.CallOption) (*DiffJobSpecsResponse, error) {
+	out := new(DiffJobSpecsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/DiffJobSpecs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetJobProvenance(ctx context.Context, in *GetJobProvenanceRequest, opts ...grpc.CallOption) (*GetJobProvenanceResponse, error) {
+	out := new(GetJobProvenanceResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJobProvenance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) FindImageBuilds(ctx context.Context, in *FindImageBuildsRequest, opts ...grpc.CallOption) (*FindImageBuildsResponse, error) {
+	out := new(FindImageBuildsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/FindImageBuilds", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error) {
+	out := new(GetVersionResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetVersion", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetJobEvents(ctx context.Context, in *GetJobEventsRequest, opts ...grpc.CallOption) (*GetJobEventsResponse, error) {
+	out := new(GetJobEventsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJobEvents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ListProjects(ctx context.Context, in *ListProjectsRequest, opts ...grpc.CallOption) (*ListProjectsResponse, error) {
+	out := new(ListProjectsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListProjects", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetProjectHealth(ctx context.Context, in *GetProjectHealthRequest, opts ...grpc.CallOption) (*GetProjectHealthResponse, error) {
+	out := new(GetProjectHealthResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetProjectHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error) {
+	out := new(GetQuotaUsageResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetQuotaUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetStartLatency(ctx context.Context, in *GetStartLatencyRequest, opts ...grpc.CallOption) (*GetStartLatencyResponse, error) {
+	out := new(GetStartLatencyResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetStartLatency", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) DeleteJob(ctx context.Context, in *DeleteJobRequest, opts ...grpc.CallOption) (*DeleteJobResponse, error) {
+	out := new(DeleteJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/DeleteJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) PurgeJob(ctx context.Context, in *PurgeJobRequest, opts ...grpc.CallOption) (*PurgeJobResponse, error) {
+	out := new(PurgeJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/PurgeJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ApproveJob(ctx context.Context, in *ApproveJobRequest, opts ...grpc.CallOption) (*ApproveJobResponse, error) {
+	out := new(ApproveJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ApproveJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) AddJobNote(ctx context.Context, in *AddJobNoteRequest, opts ...grpc.CallOption) (*AddJobNoteResponse, error) {
+	out := new(AddJobNoteResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/AddJobNote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) SetAnnouncement(ctx context.Context, in *SetAnnouncementRequest, opts ...grpc.CallOption) (*SetAnnouncementResponse, error) {
+	out := new(SetAnnouncementResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/SetAnnouncement", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetAnnouncement(ctx context.Context, in *GetAnnouncementRequest, opts ...grpc.CallOption) (*GetAnnouncementResponse, error) {
+	out := new(GetAnnouncementResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetAnnouncement", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetLatestJob(ctx context.Context, in *GetLatestJobRequest, opts ...grpc.CallOption) (*GetLatestJobResponse, error) {
+	out := new(GetLatestJobResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetLatestJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetBranches(ctx context.Context, in *GetBranchesRequest, opts ...grpc.CallOption) (*GetBranchesResponse, error) {
+	out := new(GetBranchesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetBranches", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetJobCosts(ctx context.Context, in *GetJobCostsRequest, opts ...grpc.CallOption) (*GetJobCostsResponse, error) {
+	out := new(GetJobCostsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJobCosts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetTriggerPayload(ctx context.Context, in *GetTriggerPayloadRequest, opts ...grpc.CallOption) (*GetTriggerPayloadResponse, error) {
+	out := new(GetTriggerPayloadResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetTriggerPayload", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) CancelJobs(ctx context.Context, in *BatchJobsRequest, opts ...grpc.CallOption) (*BatchJobsResponse, error) {
+	out := new(BatchJobsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/CancelJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) RetryJobs(ctx context.Context, in *BatchJobsRequest, opts ...grpc.CallOption) (*BatchJobsResponse, error) {
+	out := new(BatchJobsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/RetryJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetJobSBOM(ctx context.Context, in *GetJobSBOMRequest, opts ...grpc.CallOption) (*GetJobSBOMResponse, error) {
+	out := new(GetJobSBOMResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJobSBOM", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (WerftService_StreamEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[5], "/v1.WerftService/StreamEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &werftServiceStreamEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WerftService_StreamEventsClient interface {
+	Recv() (*ServerEvent, error)
+	grpc.ClientStream
+}
+
+type werftServiceStreamEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *werftServiceStreamEventsClient) Recv() (*ServerEvent, error) {
+	m := new(ServerEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *werftServiceClient) SyncRequiredChecks(ctx context.Context, in *SyncRequiredChecksRequest, opts ...grpc.CallOption) (*SyncRequiredChecksResponse, error) {
+	out := new(SyncRequiredChecksResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/SyncRequiredChecks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) RegisterSchedules(ctx context.Context, in *RegisterSchedulesRequest, opts ...grpc.CallOption) (*RegisterSchedulesResponse, error) {
+	out := new(RegisterSchedulesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/RegisterSchedules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) ListSchedules(ctx context.Context, in *ListSchedulesRequest, opts ...grpc.CallOption) (*ListSchedulesResponse, error) {
+	out := new(ListSchedulesResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/ListSchedules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) GetRuntimeStats(ctx context.Context, in *GetRuntimeStatsRequest, opts ...grpc.CallOption) (*GetRuntimeStatsResponse, error) {
+	out := new(GetRuntimeStatsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/GetRuntimeStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *werftServiceClient) DiffJobEnvironments(ctx context.Context, in *DiffJobEnvironmentsRequest, opts ...grpc.CallOption) (*DiffJobEnvironmentsResponse, error) {
+	out := new(DiffJobEnvironmentsResponse)
+	err := c.cc.Invoke(ctx, "/v1.WerftService/DiffJobEnvironments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WerftServiceServer is the server API for WerftService service.
+type WerftServiceServer interface {
+	// StartLocalJob starts a job by uploading the workspace content directly. The incoming requests are expected in the following order:
+	//   1. metadata
+	//   2. all bytes constituting the werft/config.yaml
+	//   3. all bytes constituting the job YAML that will be executed (that the config.yaml points to)
+	//   4. all bytes constituting the gzipped workspace tar stream
+	//   5. the workspace tar stream done marker
+	StartLocalJob(WerftService_StartLocalJobServer) error
+	// StartGitHubJob starts a job on a Git context, possibly with a custom job.
+	StartGitHubJob(context.Context, *StartGitHubJobRequest) (*StartJobResponse, error)
+	// StartFromPreviousJob starts a new job based on a previous one.
+	// If the previous job does not have the can-replay condition set this call will result in an error.
+	StartFromPreviousJob(context.Context, *StartFromPreviousJobRequest) (*StartJobResponse, error)
+	// Searches for jobs known to this instance
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	// Subscribe listens to new jobs/job updates
+	Subscribe(*SubscribeRequest, WerftService_SubscribeServer) error
+	// GetJob retrieves details of a single job
+	GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error)
+	// Listen listens to job updates and log output of a running job
+	Listen(*ListenRequest, WerftService_ListenServer) error
+	// StopJob stops a currently running job
+	StopJob(context.Context, *StopJobRequest) (*StopJobResponse, error)
+	// AttachJob runs an interactive command (a shell by default) in the pod of a running job.
+	// The first request must start the session, all subsequent ones carry input or terminal size changes.
+	AttachJob(WerftService_AttachJobServer) error
+	// ListDeadLetters lists webhook events which failed processing
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	// ReplayDeadLetter processes a failed webhook event again. If processing succeeds, the event is removed from the dead letter queue.
+	ReplayDeadLetter(context.Context, *ReplayDeadLetterRequest) (*ReplayDeadLetterResponse, error)
+	// GetQueueStatus lists the jobs which wait to run, and why they are waiting
+	GetQueueStatus(context.Context, *GetQueueStatusRequest) (*GetQueueStatusResponse, error)
+	// SetMaintenanceMode pauses or resumes job processing. While paused, werft accepts webhooks and jobs but
+	// does not start them until job processing resumes.
+	SetMaintenanceMode(context.Context, *SetMaintenanceModeRequest) (*SetMaintenanceModeResponse, error)
+	// GetMaintenanceMode returns whether job processing is paused
+	GetMaintenanceMode(context.Context, *GetMaintenanceModeRequest) (*GetMaintenanceModeResponse, error)
+	// GetFlakyJobs finds jobs of a repository which alternate between success and failure, most flaky first
+	GetFlakyJobs(context.Context, *GetFlakyJobsRequest) (*GetFlakyJobsResponse, error)
+	// ExportJobs dumps the records of all jobs matching a filter and time range, e.g. to build reports.
+	// Exporting requires one of the export tokens configured for werft.
+	ExportJobs(*ExportJobsRequest, WerftService_ExportJobsServer) error
+	// DiffJobSpecs compares the resolved job specs two jobs ran with, e.g. to find out what changed between
+	// a successful and a failed run
+	DiffJobSpecs(context.Context, *DiffJobSpecsRequest) (*DiffJobSpecsResponse, error)
+	// GetJobProvenance returns the signed provenance of a finished job, which attests how its results were built.
+	// Provenance is only recorded if werft has a signing key.
+	GetJobProvenance(context.Context, *GetJobProvenanceRequest) (*GetJobProvenanceResponse, error)
+	// FindImageBuilds returns the jobs which built a container image, identified by its digest.
+	// Jobs report the images they built as results of type image.
+	FindImageBuilds(context.Context, *FindImageBuildsRequest) (*FindImageBuildsResponse, error)
+	// GetVersion returns the version of the server, so that clients can check if they match it
+	GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error)
+	// GetJobEvents returns the timeline of a job, i.e. what happened to the job when, oldest first
+	GetJobEvents(context.Context, *GetJobEventsRequest) (*GetJobEventsResponse, error)
+	// ListProjects lists the projects configured for werft, i.e. groups of related repositories
+	ListProjects(context.Context, *ListProjectsRequest) (*ListProjectsResponse, error)
+	// GetProjectHealth returns the latest job of each repository of a project
+	GetProjectHealth(context.Context, *GetProjectHealthRequest) (*GetProjectHealthResponse, error)
+	// GetQuotaUsage returns the current resource consumption of the configured quotas
+	GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error)
+	// GetStartLatency returns the time recent jobs took from the webhook which started them until their pod ran
+	GetStartLatency(context.Context, *GetStartLatencyRequest) (*GetStartLatencyResponse, error)
+	// DeleteJob hides a finished job from job listings, but keeps all of its data. Deleting a job requires
+	// write permission on the job's repository on GitHub.
+	DeleteJob(context.Context, *DeleteJobRequest) (*DeleteJobResponse, error)
+	// PurgeJob removes a finished job and all of its data, i.e. its record, logs, job spec, events, provenance
+	// and image builds. Purging requires one of the admin tokens configured for werft.
+	PurgeJob(context.Context, *PurgeJobRequest) (*PurgeJobResponse, error)
+	// ApproveJob lets a job which waits for approval start. Who can approve a job depends on the approval policy
+	// of its job spec, but always requires write permission on the job's repository on GitHub.
+	ApproveJob(context.Context, *ApproveJobRequest) (*ApproveJobResponse, error)
+	// AddJobNote attaches a note to a job, e.g. why it failed. Adding notes requires write permission on the
+	// job's repository on GitHub.
+	AddJobNote(context.Context, *AddJobNoteRequest) (*AddJobNoteResponse, error)
+	// SetAnnouncement sets the announcement werft shows its users, e.g. upcoming cluster maintenance, or clears it.
+	// Jobs started while there is an announcement show it in their logs. Setting the announcement requires one of
+	// the admin tokens configured for werft.
+	SetAnnouncement(context.Context, *SetAnnouncementRequest) (*SetAnnouncementResponse, error)
+	// GetAnnouncement returns the current announcement, if there is one
+	GetAnnouncement(context.Context, *GetAnnouncementRequest) (*GetAnnouncementResponse, error)
+	// GetLatestJob returns the most recent job of a ref, e.g. to find out if the main branch is green
+	GetLatestJob(context.Context, *GetLatestJobRequest) (*GetLatestJobResponse, error)
+	// GetBranches returns the branches of a repository which had jobs recently, with their latest jobs
+	GetBranches(context.Context, *GetBranchesRequest) (*GetBranchesResponse, error)
+	// GetJobCosts sums up the estimated cost of jobs per repository, team and/or month, e.g. for chargeback
+	// reporting. Costs are only known if werft is configured with resource prices.
+	GetJobCosts(context.Context, *GetJobCostsRequest) (*GetJobCostsResponse, error)
+	// GetTriggerPayload returns what triggered a job, e.g. the body of the GitHub webhook which started it.
+	// Werft keeps the payload as long as it keeps the job.
+	GetTriggerPayload(context.Context, *GetTriggerPayloadRequest) (*GetTriggerPayloadResponse, error)
+	// CancelJobs stops all waiting or running jobs matching a filter. Cancelling jobs requires write permission on
+	// their repositories on GitHub.
+	CancelJobs(context.Context, *BatchJobsRequest) (*BatchJobsResponse, error)
+	// RetryJobs starts all finished jobs matching a filter again, like StartFromPreviousJob does. Retrying jobs requires
+	// write permission on their repositories on GitHub.
+	RetryJobs(context.Context, *BatchJobsRequest) (*BatchJobsResponse, error)
+	// GetJobSBOM returns the software bills of materials of the images a job built. SBOMs are only generated if
+	// werft is configured to do so.
+	GetJobSBOM(context.Context, *GetJobSBOMRequest) (*GetJobSBOMResponse, error)
+	// StreamEvents streams all server events, e.g. job updates, queued jobs or central config changes, which match
+	// a filter expression.
+	StreamEvents(*StreamEventsRequest, WerftService_StreamEventsServer) error
+	// SyncRequiredChecks makes the werft status checks among the required status checks of a protected branch match
+	// the jobs the repository's werft config starts. Status checks of other CI systems are left alone. Syncing
+	// requires one of the admin tokens configured for werft.
+	SyncRequiredChecks(context.Context, *SyncRequiredChecksRequest) (*SyncRequiredChecksResponse, error)
+	// RegisterSchedules is called by schedulers, e.g. the cron plugin, to tell werft which jobs they start on a
+	// schedule. The schedules replace those the scheduler registered before. Werft keeps them in memory only.
+	RegisterSchedules(context.Context, *RegisterSchedulesRequest) (*RegisterSchedulesResponse, error)
+	// ListSchedules returns the registered schedules and their next runs, e.g. to verify cron specs and time zones
+	ListSchedules(context.Context, *ListSchedulesRequest) (*ListSchedulesResponse, error)
+	// GetRuntimeStats returns the internal state of the werft server, e.g. its goroutines, memory and the size of
+	// its queues and buffers, to diagnose hangs and memory growth in production. It requires one of the admin tokens
+	// configured for werft.
+	GetRuntimeStats(context.Context, *GetRuntimeStatsRequest) (*GetRuntimeStatsResponse, error)
+	// DiffJobEnvironments compares the environments two jobs ran in, i.e. the digests their images resolved to, their
+	// node and the environment variables of their containers, e.g. to find out whether the environment changed
+	// when a build regressed.
+	DiffJobEnvironments(context.Context, *DiffJobEnvironmentsRequest) (*DiffJobEnvironmentsResponse, error)
+}
+
+// UnimplementedWerftServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedWerftServiceServer struct {
+}
+
+func (*UnimplementedWerftServiceServer) StartLocalJob(srv WerftService_StartLocalJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method StartLocalJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) StartGitHubJob(ctx context.Context, req *StartGitHubJobRequest) (*StartJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartGitHubJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) StartFromPreviousJob(ctx context.Context, req *StartFromPreviousJobRequest) (*StartJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartFromPreviousJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListJobs(ctx context.Context, req *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (*UnimplementedWerftServiceServer) Subscribe(req *SubscribeRequest, srv WerftService_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetJob(ctx context.Context, req *GetJobRequest) (*GetJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) Listen(req *ListenRequest, srv WerftService_ListenServer) error {
+	return status.Errorf(codes.Unimplemented, "method Listen not implemented")
+}
+func (*UnimplementedWerftServiceServer) StopJob(ctx context.Context, req *StopJobRequest) (*StopJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) AttachJob(srv WerftService_AttachJobServer) error {
+	return status.Errorf(codes.Unimplemented, "method AttachJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListDeadLetters(ctx context.Context, req *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (*UnimplementedWerftServiceServer) ReplayDeadLetter(ctx context.Context, req *ReplayDeadLetterRequest) (*ReplayDeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDeadLetter not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetQueueStatus(ctx context.Context, req *GetQueueStatusRequest) (*GetQueueStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueueStatus not implemented")
+}
+func (*UnimplementedWerftServiceServer) SetMaintenanceMode(ctx context.Context, req *SetMaintenanceModeRequest) (*SetMaintenanceModeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMaintenanceMode not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetMaintenanceMode(ctx context.Context, req *GetMaintenanceModeRequest) (*GetMaintenanceModeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMaintenanceMode not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetFlakyJobs(ctx context.Context, req *GetFlakyJobsRequest) (*GetFlakyJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFlakyJobs not implemented")
+}
+func (*UnimplementedWerftServiceServer) ExportJobs(req *ExportJobsRequest, srv WerftService_ExportJobsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ExportJobs not implemented")
+}
+func (*UnimplementedWerftServiceServer) DiffJobSpecs(ctx context.Context, req *DiffJobSpecsRequest) (*DiffJobSpecsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffJobSpecs not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetJobProvenance(ctx context.Context, req *GetJobProvenanceRequest) (*GetJobProvenanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobProvenance not implemented")
+}
+func (*UnimplementedWerftServiceServer) FindImageBuilds(ctx context.Context, req *FindImageBuildsRequest) (*FindImageBuildsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindImageBuilds not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetVersion(ctx context.Context, req *GetVersionRequest) (*GetVersionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetJobEvents(ctx context.Context, req *GetJobEventsRequest) (*GetJobEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobEvents not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListProjects(ctx context.Context, req *ListProjectsRequest) (*ListProjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProjects not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetProjectHealth(ctx context.Context, req *GetProjectHealthRequest) (*GetProjectHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProjectHealth not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetQuotaUsage(ctx context.Context, req *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuotaUsage not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetStartLatency(ctx context.Context, req *GetStartLatencyRequest) (*GetStartLatencyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStartLatency not implemented")
+}
+func (*UnimplementedWerftServiceServer) DeleteJob(ctx context.Context, req *DeleteJobRequest) (*DeleteJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) PurgeJob(ctx context.Context, req *PurgeJobRequest) (*PurgeJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) ApproveJob(ctx context.Context, req *ApproveJobRequest) (*ApproveJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) AddJobNote(ctx context.Context, req *AddJobNoteRequest) (*AddJobNoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddJobNote not implemented")
+}
+func (*UnimplementedWerftServiceServer) SetAnnouncement(ctx context.Context, req *SetAnnouncementRequest) (*SetAnnouncementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetAnnouncement not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetAnnouncement(ctx context.Context, req *GetAnnouncementRequest) (*GetAnnouncementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAnnouncement not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetLatestJob(ctx context.Context, req *GetLatestJobRequest) (*GetLatestJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestJob not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetBranches(ctx context.Context, req *GetBranchesRequest) (*GetBranchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBranches not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetJobCosts(ctx context.Context, req *GetJobCostsRequest) (*GetJobCostsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobCosts not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetTriggerPayload(ctx context.Context, req *GetTriggerPayloadRequest) (*GetTriggerPayloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTriggerPayload not implemented")
+}
+func (*UnimplementedWerftServiceServer) CancelJobs(ctx context.Context, req *BatchJobsRequest) (*BatchJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJobs not implemented")
+}
+func (*UnimplementedWerftServiceServer) RetryJobs(ctx context.Context, req *BatchJobsRequest) (*BatchJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryJobs not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetJobSBOM(ctx context.Context, req *GetJobSBOMRequest) (*GetJobSBOMResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJobSBOM not implemented")
+}
+func (*UnimplementedWerftServiceServer) StreamEvents(req *StreamEventsRequest, srv WerftService_StreamEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (*UnimplementedWerftServiceServer) SyncRequiredChecks(ctx context.Context, req *SyncRequiredChecksRequest) (*SyncRequiredChecksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncRequiredChecks not implemented")
+}
+func (*UnimplementedWerftServiceServer) RegisterSchedules(ctx context.Context, req *RegisterSchedulesRequest) (*RegisterSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterSchedules not implemented")
+}
+func (*UnimplementedWerftServiceServer) ListSchedules(ctx context.Context, req *ListSchedulesRequest) (*ListSchedulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSchedules not implemented")
+}
+func (*UnimplementedWerftServiceServer) GetRuntimeStats(ctx context.Context, req *GetRuntimeStatsRequest) (*GetRuntimeStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRuntimeStats not implemented")
+}
+func (*UnimplementedWerftServiceServer) DiffJobEnvironments(ctx context.Context, req *DiffJobEnvironmentsRequest) (*DiffJobEnvironmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffJobEnvironments not implemented")
+}
+
+func RegisterWerftServiceServer(s *grpc.Server, srv WerftServiceServer) {
+	s.RegisterService(&_WerftService_serviceDesc, srv)
+}
+
+func _WerftService_StartLocalJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WerftServiceServer).StartLocalJob(&werftServiceStartLocalJobServer{stream})
+}
+
+type WerftService_StartLocalJobServer interface {
+	SendAndClose(*StartJobResponse) error
+	Recv() (*StartLocalJobRequest, error)
+	grpc.ServerStream
+}
+
+type werftServiceStartLocalJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *werftServiceStartLocalJobServer) SendAndClose(m *StartJobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *werftServiceStartLocalJobServer) Recv() (*StartLocalJobRequest, error) {
+	m := new(StartLocalJobRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _WerftService_StartGitHubJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartGitHubJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).StartGitHubJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/StartGitHubJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).StartGitHubJob(ctx, req.(*StartGitHubJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_StartFromPreviousJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartFromPreviousJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).StartFromPreviousJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/StartFromPreviousJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).StartFromPreviousJob(ctx, req.(*StartFromPreviousJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-var xxx_messageInfo_StopJobResponse proto.InternalMessageInfo
+func _WerftService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-func init() {
-	proto.RegisterEnum("v1.FilterOp", FilterOp_name, FilterOp_value)
-	proto.RegisterEnum("v1.ListenRequestLogs", ListenRequestLogs_name, ListenRequestLogs_value)
-	proto.RegisterEnum("v1.JobTrigger", JobTrigger_name, JobTrigger_value)
-	proto.RegisterEnum("v1.JobPhase", JobPhase_name, JobPhase_value)
-	proto.RegisterEnum("v1.LogSliceType", LogSliceType_name, LogSliceType_value)
-	proto.RegisterType((*StartLocalJobRequest)(nil), "v1.StartLocalJobRequest")
-	proto.RegisterType((*StartJobResponse)(nil), "v1.StartJobResponse")
-	proto.RegisterType((*StartGitHubJobRequest)(nil), "v1.StartGitHubJobRequest")
-	proto.RegisterType((*StartFromPreviousJobRequest)(nil), "v1.StartFromPreviousJobRequest")
-	proto.RegisterType((*ListJobsRequest)(nil), "v1.ListJobsRequest")
-	proto.RegisterType((*FilterExpression)(nil), "v1.FilterExpression")
-	proto.RegisterType((*FilterTerm)(nil), "v1.FilterTerm")
-	proto.RegisterType((*OrderExpression)(nil), "v1.OrderExpression")
-	proto.RegisterType((*ListJobsResponse)(nil), "v1.ListJobsResponse")
-	proto.RegisterType((*SubscribeRequest)(nil), "v1.SubscribeRequest")
-	proto.RegisterType((*SubscribeResponse)(nil), "v1.SubscribeResponse")
-	proto.RegisterType((*GetJobRequest)(nil), "v1.GetJobRequest")
-	proto.RegisterType((*GetJobResponse)(nil), "v1.GetJobResponse")
-	proto.RegisterType((*ListenRequest)(nil), "v1.ListenRequest")
-	proto.RegisterType((*ListenResponse)(nil), "v1.ListenResponse")
-	proto.RegisterType((*JobStatus)(nil), "v1.JobStatus")
-	proto.RegisterType((*JobMetadata)(nil), "v1.JobMetadata")
-	proto.RegisterType((*Repository)(nil), "v1.Repository")
-	proto.RegisterType((*Annotation)(nil), "v1.Annotation")
-	proto.RegisterType((*JobConditions)(nil), "v1.JobConditions")
-	proto.RegisterType((*JobResult)(nil), "v1.JobResult")
-	proto.RegisterType((*LogSliceEvent)(nil), "v1.LogSliceEvent")
-	proto.RegisterType((*StopJobRequest)(nil), "v1.StopJobRequest")
-	proto.RegisterType((*StopJobResponse)(nil), "v1.StopJobResponse")
+func _WerftService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WerftServiceServer).Subscribe(m, &werftServiceSubscribeServer{stream})
 }
 
-func init() { proto.RegisterFile("werft.proto", fileDescriptor_9fe744feedd6d332) }
+type WerftService_SubscribeServer interface {
+	Send(*SubscribeResponse) error
+	grpc.ServerStream
+}
 
-var fileDescriptor_9fe744feedd6d332 = []byte{
-	// 1673 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0x5f, 0x6f, 0xdb, 0xc8,
-	0x11, 0x37, 0x25, 0x4b, 0x96, 0x46, 0x92, 0x4d, 0xaf, 0x9d, 0x42, 0xe7, 0x6b, 0x11, 0x87, 0x97,
-	0xc3, 0xf9, 0xdc, 0xd6, 0x77, 0xf1, 0x05, 0xbd, 0xa6, 0xe8, 0x43, 0x15, 0x9b, 0xb1, 0x94, 0x2a,
-	0x92, 0xba, 0x94, 0xea, 0x16, 0x28, 0x40, 0xac, 0xa8, 0xb5, 0xcc, 0x84, 0xe2, 0xb2, 0xe4, 0xca,
-	0x8e, 0x81, 0x7e, 0x82, 0xbe, 0xf4, 0xad, 0x6f, 0xed, 0xf7, 0xe9, 0x17, 0x69, 0xbf, 0x40, 0x9f,
-	0x8b, 0x62, 0xff, 0xf0, 0x8f, 0x14, 0x27, 0x41, 0xee, 0x8d, 0xf3, 0x9b, 0xd9, 0xd9, 0x99, 0xdf,
-	0xce, 0xce, 0x0e, 0xa1, 0x71, 0x4b, 0xe3, 0x2b, 0x7e, 0x12, 0xc5, 0x8c, 0x33, 0x54, 0xba, 0x79,
-	0x72, 0xf0, 0x70, 0xce, 0xd8, 0x3c, 0xa0, 0xdf, 0x48, 0x64, 0xba, 0xbc, 0xfa, 0x86, 0xfb, 0x0b,
-	0x9a, 0x70, 0xb2, 0x88, 0x94, 0x91, 0xf5, 0x1f, 0x03, 0xf6, 0x1d, 0x4e, 0x62, 0xde, 0x67, 0x1e,
-	0x09, 0x5e, 0xb2, 0x29, 0xa6, 0x7f, 0x5e, 0xd2, 0x84, 0xa3, 0x9f, 0x43, 0x6d, 0x41, 0x39, 0x99,
-	0x11, 0x4e, 0xda, 0xc6, 0xa1, 0x71, 0xd4, 0x38, 0xdd, 0x39, 0xb9, 0x79, 0x72, 0xf2, 0x92, 0x4d,
-	0x5f, 0x69, 0xb8, 0xbb, 0x81, 0x33, 0x13, 0xf4, 0x08, 0x1a, 0x1e, 0x0b, 0xaf, 0xfc, 0xb9, 0x7b,
-	0x47, 0x16, 0x41, 0xbb, 0x74, 0x68, 0x1c, 0x35, 0xbb, 0x1b, 0x18, 0x14, 0xf8, 0x47, 0xb2, 0x08,
-	0xd0, 0xe7, 0x50, 0x7b, 0xcd, 0xa6, 0x4a, 0x5f, 0xd6, 0xfa, 0xad, 0xd7, 0x6c, 0x2a, 0x95, 0x5f,
-	0x42, 0xeb, 0x96, 0xc5, 0x6f, 0x92, 0x88, 0x78, 0xd4, 0xe5, 0x24, 0x6e, 0x6f, 0x6a, 0x8b, 0x66,
-	0x06, 0x8f, 0x49, 0x8c, 0x4e, 0x00, 0xad, 0x98, 0xb9, 0x33, 0x16, 0xd2, 0x76, 0xe5, 0xd0, 0x38,
-	0xaa, 0x75, 0x37, 0xb0, 0x59, 0xb4, 0x3d, 0x67, 0x21, 0x7d, 0x5e, 0x87, 0x2d, 0x8f, 0x85, 0x9c,
-	0x86, 0xdc, 0x7a, 0x06, 0xa6, 0x4c, 0x54, 0xe6, 0x98, 0x44, 0x2c, 0x4c, 0x28, 0xfa, 0x12, 0xaa,
-	0x09, 0x27, 0x7c, 0x99, 0xe8, 0x14, 0x5b, 0x3a, 0x45, 0x47, 0x82, 0x58, 0x2b, 0xad, 0xff, 0x1a,
-	0xf0, 0x40, 0xae, 0xbd, 0xf0, 0x79, 0x77, 0x39, 0x2d, 0xb0, 0xf4, 0xd3, 0x8f, 0xb2, 0x54, 0xe0,
-	0xe8, 0x33, 0x45, 0x40, 0x44, 0xf8, 0xb5, 0x24, 0xa8, 0x2e, 0xd3, 0x1f, 0x11, 0x7e, 0x9d, 0xaa,
-	0x72, 0x6e, 0x72, 0x66, 0x1e, 0x41, 0x73, 0xee, 0xf3, 0xeb, 0xe5, 0xd4, 0xe5, 0xec, 0x0d, 0x0d,
-	0x25, 0x31, 0x75, 0xdc, 0x50, 0xd8, 0x58, 0x40, 0xe8, 0x00, 0x6a, 0x89, 0x3f, 0xa3, 0x01, 0x23,
-	0x33, 0xc9, 0x45, 0x13, 0x67, 0x32, 0x7a, 0x06, 0x70, 0x4b, 0x7c, 0xee, 0x2e, 0x43, 0xee, 0x07,
-	0xed, 0xaa, 0x8c, 0xf1, 0xe0, 0x44, 0x95, 0xc5, 0x49, 0x5a, 0x16, 0x27, 0xe3, 0xb4, 0x2c, 0x70,
-	0x5d, 0x58, 0x4f, 0x84, 0xb1, 0xf5, 0x4f, 0x03, 0x3e, 0x97, 0x69, 0xbf, 0x88, 0xd9, 0x62, 0x14,
-	0xd3, 0x1b, 0x9f, 0x2d, 0x93, 0x42, 0xf2, 0x8f, 0xa0, 0x19, 0x69, 0xd4, 0x7d, 0xcd, 0xa6, 0x92,
-	0x80, 0x3a, 0x6e, 0x44, 0xb9, 0xe5, 0x3b, 0xc1, 0x97, 0xde, 0x0d, 0x7e, 0x35, 0xc0, 0xf2, 0xa7,
-	0x04, 0xf8, 0x77, 0x03, 0x76, 0xfa, 0x7e, 0x22, 0x8e, 0x34, 0x49, 0x83, 0xfa, 0x19, 0x54, 0xaf,
-	0xfc, 0x80, 0xd3, 0xb8, 0x6d, 0x1c, 0x96, 0x8f, 0x1a, 0xa7, 0xfb, 0xe2, 0x3c, 0x5e, 0x48, 0xc4,
-	0x7e, 0x1b, 0xc5, 0x34, 0x49, 0x7c, 0x16, 0x62, 0x6d, 0x83, 0xbe, 0x86, 0x0a, 0x8b, 0x67, 0x34,
-	0x6e, 0x97, 0xa4, 0xf1, 0x9e, 0x30, 0x1e, 0x0a, 0xa0, 0x60, 0xab, 0x2c, 0xd0, 0x3e, 0x54, 0x12,
-	0x41, 0x86, 0x0c, 0xb1, 0x82, 0x95, 0x20, 0xd0, 0xc0, 0x5f, 0xf8, 0x5c, 0x1e, 0x4b, 0x05, 0x2b,
-	0xc1, 0xfa, 0x25, 0x98, 0xeb, 0x5b, 0xa2, 0xc7, 0x50, 0xe1, 0x34, 0x5e, 0x24, 0x3a, 0xae, 0xed,
-	0x3c, 0xae, 0x31, 0x8d, 0x17, 0x58, 0x29, 0xad, 0xbf, 0x00, 0xe4, 0xa0, 0xf0, 0x7e, 0xe5, 0xd3,
-	0x60, 0xa6, 0xa9, 0x55, 0x82, 0x40, 0x6f, 0x48, 0xb0, 0xa4, 0x9a, 0x4d, 0x25, 0xa0, 0x63, 0xa8,
-	0xb3, 0x88, 0xc6, 0x84, 0xfb, 0x2c, 0x94, 0x31, 0x6e, 0x9f, 0x36, 0xf3, 0x3d, 0x86, 0x11, 0xce,
-	0xd5, 0xe8, 0x47, 0x50, 0x0d, 0xe9, 0x9c, 0x70, 0x2a, 0xc3, 0xae, 0x61, 0x2d, 0x59, 0x36, 0xec,
-	0xac, 0x65, 0xff, 0x9e, 0x10, 0x7e, 0x0c, 0x75, 0x92, 0x78, 0x34, 0x9c, 0xf9, 0xe1, 0x5c, 0x86,
-	0x51, 0xc3, 0x39, 0x60, 0x0d, 0xc1, 0xcc, 0x8f, 0x45, 0x5f, 0xb5, 0x7d, 0xa8, 0x70, 0xc6, 0x49,
-	0x20, 0xfd, 0x54, 0xb0, 0x12, 0xc4, 0x05, 0x8c, 0x69, 0xb2, 0x0c, 0xb8, 0x3e, 0x80, 0xf5, 0x0b,
-	0xa8, 0x94, 0xd6, 0x6f, 0xc0, 0x74, 0x96, 0xd3, 0xc4, 0x8b, 0xfd, 0x29, 0xfd, 0x41, 0x07, 0x6d,
-	0xfd, 0x0a, 0x76, 0x0b, 0x1e, 0xf2, 0xeb, 0xaf, 0x77, 0xbf, 0xff, 0xfa, 0xeb, 0xdd, 0xbf, 0x80,
-	0xd6, 0x05, 0xe5, 0x85, 0xc2, 0x47, 0xb0, 0x19, 0x92, 0x05, 0xd5, 0x94, 0xc8, 0x6f, 0xeb, 0x7b,
-	0xd8, 0x4e, 0x8d, 0x3e, 0xcd, 0xfb, 0x35, 0xb4, 0x04, 0x59, 0x34, 0xfc, 0x80, 0x77, 0xd4, 0x86,
-	0xad, 0x65, 0x34, 0x23, 0x9c, 0x26, 0x9a, 0xed, 0x54, 0x44, 0x5f, 0xc3, 0x66, 0xc0, 0xe6, 0x89,
-	0x3e, 0xf1, 0x07, 0x62, 0x8f, 0x15, 0x77, 0x7d, 0x36, 0x4f, 0xb0, 0x34, 0xb1, 0x18, 0x6c, 0xa7,
-	0x2a, 0x1d, 0xe2, 0x57, 0x50, 0x55, 0x7e, 0xee, 0x0d, 0xb1, 0xbb, 0x81, 0xb5, 0x5a, 0xdc, 0x93,
-	0x24, 0xf0, 0x3d, 0x55, 0x72, 0x8d, 0xd3, 0x5d, 0xb9, 0x0d, 0x9b, 0x3b, 0x02, 0xb3, 0x6f, 0x68,
-	0xc8, 0xbb, 0x1b, 0x58, 0x59, 0x14, 0x5b, 0xee, 0xbf, 0x0d, 0xa8, 0x67, 0xde, 0xee, 0xcd, 0xab,
-	0xd8, 0x3f, 0x4b, 0x1f, 0xeb, 0x9f, 0x16, 0x54, 0xa2, 0x6b, 0x92, 0xd0, 0x62, 0x75, 0xbf, 0x64,
-	0xd3, 0x91, 0xc0, 0xb0, 0x52, 0xa1, 0x27, 0x20, 0x9e, 0x9c, 0x99, 0x2f, 0xca, 0x3c, 0x91, 0xd5,
-	0xad, 0xa3, 0x7d, 0xc9, 0xa6, 0x67, 0x99, 0x02, 0x17, 0x8c, 0x04, 0xb7, 0x33, 0xca, 0x89, 0x1f,
-	0x24, 0xb2, 0x79, 0xd6, 0x71, 0x2a, 0xa2, 0xaf, 0x60, 0x4b, 0x1d, 0x52, 0xd2, 0xae, 0xae, 0x94,
-	0x27, 0x96, 0x28, 0x4e, 0xb5, 0xd6, 0x3f, 0x4a, 0xd0, 0x28, 0xc4, 0x2c, 0x8a, 0x9d, 0xdd, 0x86,
-	0xb2, 0x34, 0xe5, 0xa5, 0x91, 0x02, 0x3a, 0x01, 0x88, 0x69, 0xc4, 0x12, 0x9f, 0xb3, 0xf8, 0x4e,
-	0xa7, 0x2b, 0xdb, 0x00, 0xce, 0x50, 0x5c, 0xb0, 0x40, 0x47, 0xb0, 0xc5, 0x63, 0x7f, 0x3e, 0xa7,
-	0xb1, 0xce, 0x78, 0x5b, 0x6f, 0x3f, 0x56, 0x28, 0x4e, 0xd5, 0xe8, 0x29, 0x6c, 0x79, 0x31, 0x25,
-	0x9c, 0xce, 0x74, 0xca, 0x1f, 0x6a, 0xa0, 0xa9, 0x29, 0xfa, 0x05, 0xd4, 0xae, 0xfc, 0xd0, 0x4f,
-	0xae, 0xa9, 0x7a, 0x36, 0x3e, 0xbc, 0x2c, 0xb3, 0x45, 0xdf, 0x42, 0x83, 0x84, 0x21, 0xe3, 0x44,
-	0x91, 0x5c, 0xcd, 0xfb, 0x59, 0x27, 0x83, 0x71, 0xd1, 0xc4, 0x7a, 0x0b, 0x90, 0xe7, 0x28, 0x0a,
-	0xe1, 0x9a, 0x25, 0x3c, 0x2d, 0x04, 0xf1, 0x9d, 0x33, 0x56, 0x2a, 0x32, 0x86, 0x60, 0x53, 0xf0,
-	0x21, 0xd3, 0xaf, 0x63, 0xf9, 0x8d, 0x4c, 0x28, 0xc7, 0xf4, 0x4a, 0x3f, 0x83, 0xe2, 0x53, 0x3c,
-	0x7f, 0xe2, 0xc9, 0x11, 0xf7, 0x5d, 0x9f, 0x60, 0x26, 0x5b, 0x4f, 0x01, 0xf2, 0xa0, 0xc4, 0xda,
-	0x37, 0xf4, 0x4e, 0x6f, 0x2c, 0x3e, 0xef, 0xef, 0xa5, 0xd6, 0xbf, 0x0c, 0x68, 0xad, 0x14, 0x8c,
-	0x28, 0x92, 0x64, 0xe9, 0x79, 0x34, 0x51, 0xa3, 0x42, 0x0d, 0xa7, 0x22, 0xfa, 0x02, 0x5a, 0x57,
-	0xc4, 0x0f, 0x96, 0x31, 0x75, 0x3d, 0xb6, 0x0c, 0xb9, 0xf4, 0x54, 0xc1, 0x4d, 0x0d, 0x9e, 0x09,
-	0x0c, 0xfd, 0x04, 0xc0, 0x23, 0xa1, 0x1b, 0xd3, 0x28, 0x20, 0x77, 0x32, 0x9d, 0x1a, 0xae, 0x7b,
-	0x24, 0xc4, 0x12, 0x58, 0x7b, 0x03, 0x37, 0x3f, 0xe1, 0x0d, 0x44, 0x0f, 0xa1, 0x31, 0xf3, 0x67,
-	0x2e, 0x7d, 0x4b, 0xbd, 0x25, 0xd7, 0xa3, 0x10, 0x86, 0x99, 0x3f, 0xb3, 0x15, 0x62, 0xdd, 0xca,
-	0x3b, 0xa8, 0x2a, 0x56, 0x10, 0xca, 0xef, 0xa2, 0xec, 0x0e, 0x8a, 0x6f, 0x91, 0x5a, 0x44, 0xee,
-	0xe4, 0xf0, 0xa0, 0xa7, 0x12, 0x2d, 0xa2, 0x43, 0x68, 0xcc, 0xa8, 0xe8, 0x99, 0x51, 0xf6, 0xa8,
-	0xd4, 0x71, 0x11, 0x12, 0xd4, 0x7b, 0xd7, 0x24, 0x0c, 0x69, 0x20, 0x2e, 0x5b, 0x59, 0x50, 0x9f,
-	0xca, 0x96, 0x07, 0xad, 0x95, 0x16, 0x71, 0x6f, 0x03, 0x78, 0xac, 0x03, 0x2a, 0xc9, 0x02, 0x37,
-	0x8b, 0x7d, 0x65, 0x7c, 0x17, 0xd1, 0x77, 0x43, 0x2c, 0xaf, 0x84, 0x68, 0x3d, 0x86, 0x6d, 0x87,
-	0xb3, 0xe8, 0x23, 0xcd, 0x79, 0x17, 0x76, 0x32, 0x2b, 0xd5, 0xfa, 0x8e, 0x5d, 0xa8, 0xa5, 0x2f,
-	0x23, 0x6a, 0x41, 0x7d, 0x38, 0x72, 0xed, 0xdf, 0x4d, 0x3a, 0x7d, 0xc7, 0xdc, 0x40, 0x08, 0xb6,
-	0x87, 0x23, 0xd7, 0x19, 0x77, 0xf0, 0xd8, 0x71, 0x2f, 0x7b, 0xe3, 0xae, 0x69, 0x20, 0x13, 0x9a,
-	0xc2, 0x64, 0x70, 0xae, 0x91, 0x12, 0xda, 0x81, 0xc6, 0x70, 0xe4, 0x9e, 0x0d, 0x07, 0xe3, 0x4e,
-	0x6f, 0xe0, 0x98, 0xe5, 0xd4, 0xcb, 0x1f, 0x7a, 0xce, 0xd8, 0x31, 0x37, 0x8f, 0x7f, 0x0f, 0xbb,
-	0xef, 0x34, 0x62, 0xb4, 0x0b, 0xad, 0xfe, 0xf0, 0xc2, 0x71, 0xcf, 0x7b, 0x4e, 0xe7, 0x79, 0xdf,
-	0x3e, 0x37, 0x37, 0x32, 0x68, 0x32, 0x70, 0xfa, 0xbd, 0x33, 0xfb, 0xdc, 0x34, 0x50, 0x13, 0x6a,
-	0x12, 0xc2, 0x9d, 0x4b, 0xb3, 0x24, 0xfc, 0x4a, 0xa9, 0x3b, 0x7e, 0xd5, 0x37, 0xcb, 0xc7, 0x7f,
-	0x02, 0xc8, 0x5b, 0x00, 0xda, 0x83, 0x9d, 0x31, 0xee, 0x5d, 0x5c, 0xd8, 0xd8, 0x9d, 0x0c, 0x7e,
-	0x3b, 0x18, 0x5e, 0x0e, 0x54, 0x02, 0x29, 0xf8, 0xaa, 0x33, 0x98, 0x74, 0xfa, 0x2a, 0x81, 0x14,
-	0x1b, 0x4d, 0x1c, 0x91, 0x40, 0x61, 0xe9, 0xb9, 0xdd, 0xb7, 0xc7, 0xf6, 0xb9, 0x59, 0x3e, 0xfe,
-	0x9b, 0x01, 0xb5, 0xb4, 0xa7, 0x8a, 0xd0, 0x46, 0xdd, 0x8e, 0x63, 0x17, 0x5c, 0xef, 0xc1, 0x8e,
-	0x82, 0x46, 0xd8, 0x1e, 0x75, 0x70, 0x6f, 0x70, 0x61, 0x1a, 0x62, 0x3f, 0x05, 0x4a, 0xce, 0x04,
-	0x56, 0xca, 0xd7, 0xe2, 0xc9, 0x60, 0x20, 0xa0, 0x32, 0xda, 0x06, 0x50, 0xd0, 0xf9, 0x70, 0x60,
-	0x9b, 0x9b, 0xb9, 0xc9, 0x59, 0xdf, 0xee, 0x0c, 0x26, 0x23, 0xb3, 0x92, 0x43, 0x97, 0x9d, 0x9e,
-	0x74, 0x54, 0x3d, 0xfe, 0xab, 0x01, 0xcd, 0x62, 0x49, 0x88, 0x10, 0x24, 0x53, 0x6e, 0xe7, 0x79,
-	0x67, 0x20, 0x5c, 0x09, 0x16, 0x77, 0xa0, 0xa1, 0x40, 0xb9, 0xdc, 0x34, 0x72, 0x40, 0xc6, 0xa4,
-	0x02, 0x52, 0x80, 0x38, 0x32, 0x7b, 0x30, 0x56, 0x01, 0x29, 0x48, 0x07, 0x94, 0xc9, 0x2f, 0x3a,
-	0xbd, 0xbe, 0x59, 0x11, 0x9c, 0x29, 0x19, 0xdb, 0xce, 0xa4, 0x3f, 0x36, 0xab, 0xa7, 0xff, 0x2b,
-	0x43, 0xf3, 0x52, 0xfc, 0x63, 0x39, 0x34, 0xbe, 0xf1, 0x3d, 0x8a, 0xce, 0xa0, 0xb5, 0xf2, 0xfb,
-	0x84, 0xda, 0xa2, 0x84, 0xef, 0xfb, 0xa3, 0x3a, 0xd8, 0xcf, 0x34, 0x85, 0x3a, 0xb4, 0x36, 0x8e,
-	0x0c, 0x74, 0x26, 0x8a, 0xb8, 0xf8, 0x7b, 0x81, 0x3e, 0xcb, 0x6c, 0xd7, 0x7f, 0x39, 0xde, 0xe7,
-	0x06, 0x0d, 0xf5, 0x8f, 0xdc, 0xda, 0xb0, 0x8e, 0x1e, 0x66, 0xf6, 0xf7, 0x8f, 0xf1, 0xef, 0x75,
-	0xf8, 0x3d, 0xd4, 0xd2, 0x29, 0x0e, 0xed, 0xa5, 0x73, 0x45, 0x61, 0xd4, 0x56, 0x0b, 0xd7, 0x07,
-	0x3d, 0x6b, 0x03, 0xfd, 0x1a, 0xea, 0xd9, 0xac, 0x85, 0x94, 0xf7, 0xb5, 0xe1, 0xed, 0xe0, 0xc1,
-	0x1a, 0x9a, 0xae, 0xfd, 0xd6, 0x40, 0x4f, 0xa0, 0xaa, 0x06, 0x29, 0x24, 0xdf, 0xed, 0x95, 0xc9,
-	0xeb, 0x00, 0x15, 0xa1, 0x6c, 0xc3, 0xef, 0xa0, 0xaa, 0xae, 0x9a, 0x5a, 0xb2, 0x72, 0xed, 0xd4,
-	0x92, 0xd5, 0xb9, 0x47, 0xee, 0xf3, 0x14, 0xb6, 0x74, 0x4f, 0x40, 0x48, 0x31, 0x50, 0x6c, 0x23,
-	0x07, 0x7b, 0x2b, 0x58, 0xba, 0x6e, 0x5a, 0x95, 0xdd, 0xf8, 0xbb, 0xff, 0x07, 0x00, 0x00, 0xff,
-	0xff, 0xe9, 0xfb, 0x60, 0x0c, 0x6a, 0x0f, 0x00, 0x00,
+type werftServiceSubscribeServer struct {
+	grpc.ServerStream
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (x *werftServiceSubscribeServer) Send(m *SubscribeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func _WerftService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
 
-// WerftServiceClient is the client API for WerftService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type WerftServiceClient interface {
-	// StartLocalJob starts a job by uploading the workspace content directly. The incoming requests are expected in the following order:
-	//   1. metadata
-	//   2. all bytes constituting the werft/config.yaml
-	//   3. all bytes constituting the job YAML that will be executed (that the config.yaml points to)
-	//   4. all bytes constituting the gzipped workspace tar stream
-	//   5. the workspace tar stream done marker
-	StartLocalJob(ctx context.Context, opts ...grpc.CallOption) (WerftService_StartLocalJobClient, error)
-	// StartGitHubJob starts a job on a Git context, possibly with a custom job.
-	StartGitHubJob(ctx context.Context, in *StartGitHubJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error)
-	// StartFromPreviousJob starts a new job based on a previous one.
-	// If the previous job does not have the can-replay condition set this call will result in an error.
-	StartFromPreviousJob(ctx context.Context, in *StartFromPreviousJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error)
-	// Searches for jobs known to this instance
-	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
-	// Subscribe listens to new jobs/job updates
-	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WerftService_SubscribeClient, error)
-	// GetJob retrieves details of a single job
-	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error)
-	// Listen listens to job updates and log output of a running job
-	Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (WerftService_ListenClient, error)
-	// StopJob stops a currently running job
-	StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*StopJobResponse, error)
+func _WerftService_Listen_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListenRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WerftServiceServer).Listen(m, &werftServiceListenServer{stream})
 }
 
-type werftServiceClient struct {
-	cc *grpc.ClientConn
+type WerftService_ListenServer interface {
+	Send(*ListenResponse) error
+	grpc.ServerStream
+}
+
+type werftServiceListenServer struct {
+	grpc.ServerStream
+}
+
+func (x *werftServiceListenServer) Send(m *ListenResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WerftService_StopJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).StopJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/StopJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).StopJob(ctx, req.(*StopJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_AttachJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(WerftServiceServer).AttachJob(&werftServiceAttachJobServer{stream})
+}
+
+type WerftService_AttachJobServer interface {
+	Send(*AttachJobResponse) error
+	Recv() (*AttachJobRequest, error)
+	grpc.ServerStream
+}
+
+type werftServiceAttachJobServer struct {
+	grpc.ServerStream
+}
+
+func (x *werftServiceAttachJobServer) Send(m *AttachJobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *werftServiceAttachJobServer) Recv() (*AttachJobRequest, error) {
+	m := new(AttachJobRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _WerftService_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ListDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_ReplayDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ReplayDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ReplayDeadLetter",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ReplayDeadLetter(ctx, req.(*ReplayDeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_GetQueueStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueueStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetQueueStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetQueueStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetQueueStatus(ctx, req.(*GetQueueStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_SetMaintenanceMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetMaintenanceModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).SetMaintenanceMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/SetMaintenanceMode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).SetMaintenanceMode(ctx, req.(*SetMaintenanceModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WerftService_GetMaintenanceMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMaintenanceModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetMaintenanceMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetMaintenanceMode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetMaintenanceMode(ctx, req.(*GetMaintenanceModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func NewWerftServiceClient(cc *grpc.ClientConn) WerftServiceClient {
-	return &werftServiceClient{cc}
+func _WerftService_GetFlakyJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFlakyJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetFlakyJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetFlakyJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetFlakyJobs(ctx, req.(*GetFlakyJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *werftServiceClient) StartLocalJob(ctx context.Context, opts ...grpc.CallOption) (WerftService_StartLocalJobClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[0], "/v1.WerftService/StartLocalJob", opts...)
-	if err != nil {
-		return nil, err
+func _WerftService_ExportJobs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportJobsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	x := &werftServiceStartLocalJobClient{stream}
-	return x, nil
+	return srv.(WerftServiceServer).ExportJobs(m, &werftServiceExportJobsServer{stream})
 }
 
-type WerftService_StartLocalJobClient interface {
-	Send(*StartLocalJobRequest) error
-	CloseAndRecv() (*StartJobResponse, error)
-	grpc.ClientStream
+type WerftService_ExportJobsServer interface {
+	Send(*ExportJobsResponse) error
+	grpc.ServerStream
 }
 
-type werftServiceStartLocalJobClient struct {
-	grpc.ClientStream
+type werftServiceExportJobsServer struct {
+	grpc.ServerStream
 }
 
-func (x *werftServiceStartLocalJobClient) Send(m *StartLocalJobRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (x *werftServiceExportJobsServer) Send(m *ExportJobsResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (x *werftServiceStartLocalJobClient) CloseAndRecv() (*StartJobResponse, error) {
-	if err := x.ClientStream.CloseSend(); err != nil {
+func _WerftService_DiffJobSpecs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffJobSpecsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	m := new(StartJobResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(WerftServiceServer).DiffJobSpecs(ctx, in)
 	}
-	return m, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/DiffJobSpecs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).DiffJobSpecs(ctx, req.(*DiffJobSpecsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *werftServiceClient) StartGitHubJob(ctx context.Context, in *StartGitHubJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
-	out := new(StartJobResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/StartGitHubJob", in, out, opts...)
-	if err != nil {
+func _WerftService_GetJobProvenance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobProvenanceRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetJobProvenance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetJobProvenance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetJobProvenance(ctx, req.(*GetJobProvenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *werftServiceClient) StartFromPreviousJob(ctx context.Context, in *StartFromPreviousJobRequest, opts ...grpc.CallOption) (*StartJobResponse, error) {
-	out := new(StartJobResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/StartFromPreviousJob", in, out, opts...)
-	if err != nil {
+func _WerftService_FindImageBuilds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindImageBuildsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WerftServiceServer).FindImageBuilds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/FindImageBuilds",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).FindImageBuilds(ctx, req.(*FindImageBuildsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *werftServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
-	out := new(ListJobsResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/ListJobs", in, out, opts...)
-	if err != nil {
+func _WerftService_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetVersion(ctx, req.(*GetVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *werftServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (WerftService_SubscribeClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[1], "/v1.WerftService/Subscribe", opts...)
-	if err != nil {
+func _WerftService_GetJobEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobEventsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &werftServiceSubscribeClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetJobEvents(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetJobEvents",
 	}
-	return x, nil
-}
-
-type WerftService_SubscribeClient interface {
-	Recv() (*SubscribeResponse, error)
-	grpc.ClientStream
-}
-
-type werftServiceSubscribeClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetJobEvents(ctx, req.(*GetJobEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *werftServiceSubscribeClient) Recv() (*SubscribeResponse, error) {
-	m := new(SubscribeResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _WerftService_ListProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProjectsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ListProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ListProjects",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ListProjects(ctx, req.(*ListProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *werftServiceClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error) {
-	out := new(GetJobResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/GetJob", in, out, opts...)
-	if err != nil {
+func _WerftService_GetProjectHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectHealthRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetProjectHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetProjectHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetProjectHealth(ctx, req.(*GetProjectHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *werftServiceClient) Listen(ctx context.Context, in *ListenRequest, opts ...grpc.CallOption) (WerftService_ListenClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_WerftService_serviceDesc.Streams[2], "/v1.WerftService/Listen", opts...)
-	if err != nil {
+func _WerftService_GetQuotaUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotaUsageRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &werftServiceListenClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetQuotaUsage(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetQuotaUsage",
 	}
-	return x, nil
-}
-
-type WerftService_ListenClient interface {
-	Recv() (*ListenResponse, error)
-	grpc.ClientStream
-}
-
-type werftServiceListenClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetQuotaUsage(ctx, req.(*GetQuotaUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *werftServiceListenClient) Recv() (*ListenResponse, error) {
-	m := new(ListenResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _WerftService_GetStartLatency_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStartLatencyRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetStartLatency(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetStartLatency",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetStartLatency(ctx, req.(*GetStartLatencyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *werftServiceClient) StopJob(ctx context.Context, in *StopJobRequest, opts ...grpc.CallOption) (*StopJobResponse, error) {
-	out := new(StopJobResponse)
-	err := c.cc.Invoke(ctx, "/v1.WerftService/StopJob", in, out, opts...)
-	if err != nil {
+func _WerftService_DeleteJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteJobRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// WerftServiceServer is the server API for WerftService service.
-type WerftServiceServer interface {
-	// StartLocalJob starts a job by uploading the workspace content directly. The incoming requests are expected in the following order:
-	//   1. metadata
-	//   2. all bytes constituting the werft/config.yaml
-	//   3. all bytes constituting the job YAML that will be executed (that the config.yaml points to)
-	//   4. all bytes constituting the gzipped workspace tar stream
-	//   5. the workspace tar stream done marker
-	StartLocalJob(WerftService_StartLocalJobServer) error
-	// StartGitHubJob starts a job on a Git context, possibly with a custom job.
-	StartGitHubJob(context.Context, *StartGitHubJobRequest) (*StartJobResponse, error)
-	// StartFromPreviousJob starts a new job based on a previous one.
-	// If the previous job does not have the can-replay condition set this call will result in an error.
-	StartFromPreviousJob(context.Context, *StartFromPreviousJobRequest) (*StartJobResponse, error)
-	// Searches for jobs known to this instance
-	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
-	// Subscribe listens to new jobs/job updates
-	Subscribe(*SubscribeRequest, WerftService_SubscribeServer) error
-	// GetJob retrieves details of a single job
-	GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error)
-	// Listen listens to job updates and log output of a running job
-	Listen(*ListenRequest, WerftService_ListenServer) error
-	// StopJob stops a currently running job
-	StopJob(context.Context, *StopJobRequest) (*StopJobResponse, error)
+	if interceptor == nil {
+		return srv.(WerftServiceServer).DeleteJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/DeleteJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).DeleteJob(ctx, req.(*DeleteJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// UnimplementedWerftServiceServer can be embedded to have forward compatible implementations.
-type UnimplementedWerftServiceServer struct {
+func _WerftService_PurgeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).PurgeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/PurgeJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).PurgeJob(ctx, req.(*PurgeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (*UnimplementedWerftServiceServer) StartLocalJob(srv WerftService_StartLocalJobServer) error {
-	return status.Errorf(codes.Unimplemented, "method StartLocalJob not implemented")
-}
-func (*UnimplementedWerftServiceServer) StartGitHubJob(ctx context.Context, req *StartGitHubJobRequest) (*StartJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StartGitHubJob not implemented")
-}
-func (*UnimplementedWerftServiceServer) StartFromPreviousJob(ctx context.Context, req *StartFromPreviousJobRequest) (*StartJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StartFromPreviousJob not implemented")
-}
-func (*UnimplementedWerftServiceServer) ListJobs(ctx context.Context, req *ListJobsRequest) (*ListJobsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
-}
-func (*UnimplementedWerftServiceServer) Subscribe(req *SubscribeRequest, srv WerftService_SubscribeServer) error {
-	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
-}
-func (*UnimplementedWerftServiceServer) GetJob(ctx context.Context, req *GetJobRequest) (*GetJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
-}
-func (*UnimplementedWerftServiceServer) Listen(req *ListenRequest, srv WerftService_ListenServer) error {
-	return status.Errorf(codes.Unimplemented, "method Listen not implemented")
+func _WerftService_ApproveJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ApproveJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ApproveJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ApproveJob(ctx, req.(*ApproveJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (*UnimplementedWerftServiceServer) StopJob(ctx context.Context, req *StopJobRequest) (*StopJobResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StopJob not implemented")
+
+func _WerftService_AddJobNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddJobNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).AddJobNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/AddJobNote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).AddJobNote(ctx, req.(*AddJobNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterWerftServiceServer(s *grpc.Server, srv WerftServiceServer) {
-	s.RegisterService(&_WerftService_serviceDesc, srv)
+func _WerftService_SetAnnouncement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAnnouncementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).SetAnnouncement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/SetAnnouncement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).SetAnnouncement(ctx, req.(*SetAnnouncementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_StartLocalJob_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(WerftServiceServer).StartLocalJob(&werftServiceStartLocalJobServer{stream})
+func _WerftService_GetAnnouncement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAnnouncementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetAnnouncement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetAnnouncement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetAnnouncement(ctx, req.(*GetAnnouncementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type WerftService_StartLocalJobServer interface {
-	SendAndClose(*StartJobResponse) error
-	Recv() (*StartLocalJobRequest, error)
-	grpc.ServerStream
+func _WerftService_GetLatestJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetLatestJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetLatestJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetLatestJob(ctx, req.(*GetLatestJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type werftServiceStartLocalJobServer struct {
-	grpc.ServerStream
+func _WerftService_GetBranches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBranchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetBranches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetBranches",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetBranches(ctx, req.(*GetBranchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *werftServiceStartLocalJobServer) SendAndClose(m *StartJobResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _WerftService_GetJobCosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobCostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetJobCosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetJobCosts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetJobCosts(ctx, req.(*GetJobCostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *werftServiceStartLocalJobServer) Recv() (*StartLocalJobRequest, error) {
-	m := new(StartLocalJobRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _WerftService_GetTriggerPayload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTriggerPayloadRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetTriggerPayload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetTriggerPayload",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetTriggerPayload(ctx, req.(*GetTriggerPayloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_StartGitHubJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StartGitHubJobRequest)
+func _WerftService_CancelJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchJobsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).StartGitHubJob(ctx, in)
+		return srv.(WerftServiceServer).CancelJobs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/StartGitHubJob",
+		FullMethod: "/v1.WerftService/CancelJobs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).StartGitHubJob(ctx, req.(*StartGitHubJobRequest))
+		return srv.(WerftServiceServer).CancelJobs(ctx, req.(*BatchJobsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_StartFromPreviousJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StartFromPreviousJobRequest)
+func _WerftService_RetryJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchJobsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).StartFromPreviousJob(ctx, in)
+		return srv.(WerftServiceServer).RetryJobs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/StartFromPreviousJob",
+		FullMethod: "/v1.WerftService/RetryJobs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).StartFromPreviousJob(ctx, req.(*StartFromPreviousJobRequest))
+		return srv.(WerftServiceServer).RetryJobs(ctx, req.(*BatchJobsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListJobsRequest)
+func _WerftService_GetJobSBOM_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobSBOMRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).ListJobs(ctx, in)
+		return srv.(WerftServiceServer).GetJobSBOM(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/ListJobs",
+		FullMethod: "/v1.WerftService/GetJobSBOM",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).ListJobs(ctx, req.(*ListJobsRequest))
+		return srv.(WerftServiceServer).GetJobSBOM(ctx, req.(*GetJobSBOMRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SubscribeRequest)
+func _WerftService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(WerftServiceServer).Subscribe(m, &werftServiceSubscribeServer{stream})
+	return srv.(WerftServiceServer).StreamEvents(m, &werftServiceStreamEventsServer{stream})
 }
 
-type WerftService_SubscribeServer interface {
-	Send(*SubscribeResponse) error
+type WerftService_StreamEventsServer interface {
+	Send(*ServerEvent) error
 	grpc.ServerStream
 }
 
-type werftServiceSubscribeServer struct {
+type werftServiceStreamEventsServer struct {
 	grpc.ServerStream
 }
 
-func (x *werftServiceSubscribeServer) Send(m *SubscribeResponse) error {
+func (x *werftServiceStreamEventsServer) Send(m *ServerEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _WerftService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetJobRequest)
+func _WerftService_SyncRequiredChecks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncRequiredChecksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).GetJob(ctx, in)
+		return srv.(WerftServiceServer).SyncRequiredChecks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/GetJob",
+		FullMethod: "/v1.WerftService/SyncRequiredChecks",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).GetJob(ctx, req.(*GetJobRequest))
+		return srv.(WerftServiceServer).SyncRequiredChecks(ctx, req.(*SyncRequiredChecksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_Listen_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ListenRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _WerftService_RegisterSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(WerftServiceServer).Listen(m, &werftServiceListenServer{stream})
-}
-
-type WerftService_ListenServer interface {
-	Send(*ListenResponse) error
-	grpc.ServerStream
+	if interceptor == nil {
+		return srv.(WerftServiceServer).RegisterSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/RegisterSchedules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).RegisterSchedules(ctx, req.(*RegisterSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type werftServiceListenServer struct {
-	grpc.ServerStream
+func _WerftService_ListSchedules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSchedulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).ListSchedules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/ListSchedules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).ListSchedules(ctx, req.(*ListSchedulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *werftServiceListenServer) Send(m *ListenResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _WerftService_GetRuntimeStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRuntimeStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WerftServiceServer).GetRuntimeStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v1.WerftService/GetRuntimeStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WerftServiceServer).GetRuntimeStats(ctx, req.(*GetRuntimeStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WerftService_StopJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopJobRequest)
+func _WerftService_DiffJobEnvironments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffJobEnvironmentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WerftServiceServer).StopJob(ctx, in)
+		return srv.(WerftServiceServer).DiffJobEnvironments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/v1.WerftService/StopJob",
+		FullMethod: "/v1.WerftService/DiffJobEnvironments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WerftServiceServer).StopJob(ctx, req.(*StopJobRequest))
+		return srv.(WerftServiceServer).DiffJobEnvironments(ctx, req.(*DiffJobEnvironmentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -2163,6 +10237,138 @@ var _WerftService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StopJob",
 			Handler:    _WerftService_StopJob_Handler,
 		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _WerftService_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "ReplayDeadLetter",
+			Handler:    _WerftService_ReplayDeadLetter_Handler,
+		},
+		{
+			MethodName: "GetQueueStatus",
+			Handler:    _WerftService_GetQueueStatus_Handler,
+		},
+		{
+			MethodName: "SetMaintenanceMode",
+			Handler:    _WerftService_SetMaintenanceMode_Handler,
+		},
+		{
+			MethodName: "GetMaintenanceMode",
+			Handler:    _WerftService_GetMaintenanceMode_Handler,
+		},
+		{
+			MethodName: "GetFlakyJobs",
+			Handler:    _WerftService_GetFlakyJobs_Handler,
+		},
+		{
+			MethodName: "DiffJobSpecs",
+			Handler:    _WerftService_DiffJobSpecs_Handler,
+		},
+		{
+			MethodName: "GetJobProvenance",
+			Handler:    _WerftService_GetJobProvenance_Handler,
+		},
+		{
+			MethodName: "FindImageBuilds",
+			Handler:    _WerftService_FindImageBuilds_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _WerftService_GetVersion_Handler,
+		},
+		{
+			MethodName: "GetJobEvents",
+			Handler:    _WerftService_GetJobEvents_Handler,
+		},
+		{
+			MethodName: "ListProjects",
+			Handler:    _WerftService_ListProjects_Handler,
+		},
+		{
+			MethodName: "GetProjectHealth",
+			Handler:    _WerftService_GetProjectHealth_Handler,
+		},
+		{
+			MethodName: "GetQuotaUsage",
+			Handler:    _WerftService_GetQuotaUsage_Handler,
+		},
+		{
+			MethodName: "GetStartLatency",
+			Handler:    _WerftService_GetStartLatency_Handler,
+		},
+		{
+			MethodName: "DeleteJob",
+			Handler:    _WerftService_DeleteJob_Handler,
+		},
+		{
+			MethodName: "PurgeJob",
+			Handler:    _WerftService_PurgeJob_Handler,
+		},
+		{
+			MethodName: "ApproveJob",
+			Handler:    _WerftService_ApproveJob_Handler,
+		},
+		{
+			MethodName: "AddJobNote",
+			Handler:    _WerftService_AddJobNote_Handler,
+		},
+		{
+			MethodName: "SetAnnouncement",
+			Handler:    _WerftService_SetAnnouncement_Handler,
+		},
+		{
+			MethodName: "GetAnnouncement",
+			Handler:    _WerftService_GetAnnouncement_Handler,
+		},
+		{
+			MethodName: "GetLatestJob",
+			Handler:    _WerftService_GetLatestJob_Handler,
+		},
+		{
+			MethodName: "GetBranches",
+			Handler:    _WerftService_GetBranches_Handler,
+		},
+		{
+			MethodName: "GetJobCosts",
+			Handler:    _WerftService_GetJobCosts_Handler,
+		},
+		{
+			MethodName: "GetTriggerPayload",
+			Handler:    _WerftService_GetTriggerPayload_Handler,
+		},
+		{
+			MethodName: "CancelJobs",
+			Handler:    _WerftService_CancelJobs_Handler,
+		},
+		{
+			MethodName: "RetryJobs",
+			Handler:    _WerftService_RetryJobs_Handler,
+		},
+		{
+			MethodName: "GetJobSBOM",
+			Handler:    _WerftService_GetJobSBOM_Handler,
+		},
+		{
+			MethodName: "SyncRequiredChecks",
+			Handler:    _WerftService_SyncRequiredChecks_Handler,
+		},
+		{
+			MethodName: "RegisterSchedules",
+			Handler:    _WerftService_RegisterSchedules_Handler,
+		},
+		{
+			MethodName: "ListSchedules",
+			Handler:    _WerftService_ListSchedules_Handler,
+		},
+		{
+			MethodName: "GetRuntimeStats",
+			Handler:    _WerftService_GetRuntimeStats_Handler,
+		},
+		{
+			MethodName: "DiffJobEnvironments",
+			Handler:    _WerftService_DiffJobEnvironments_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2180,6 +10386,22 @@ var _WerftService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _WerftService_Listen_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "AttachJob",
+			Handler:       _WerftService_AttachJob_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportJobs",
+			Handler:       _WerftService_ExportJobs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _WerftService_StreamEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "werft.proto",
 }
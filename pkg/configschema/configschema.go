@@ -0,0 +1,290 @@
+package configschema
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+// Draft is the JSON Schema version of the schemas we produce
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// FormatDuration marks strings which must be Go durations, e.g. 10m
+const FormatDuration = "duration"
+
+// Schema is the subset of JSON Schema we need to describe config files
+type Schema struct {
+	Schema     string             `json:"$schema,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+
+	// AdditionalProperties is either false, i.e. objects must not have other properties, or the schema of
+	// all other properties. If it's nil, objects can have any other property.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
+
+// Describer is implemented by config types which describe themselves, e.g. because they unmarshal in a special way
+type Describer interface {
+	ConfigSchema() *Schema
+}
+
+var (
+	describerType       = reflect.TypeOf((*Describer)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	nodeType            = reflect.TypeOf(yaml.Node{})
+	nodeUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	funcUnmarshalerType = reflect.TypeOf((*interface {
+		UnmarshalYAML(unmarshal func(interface{}) error) error
+	})(nil)).Elem()
+)
+
+// Generate produces the schema of the YAML documents cfg can be unmarshalled from.
+// Just like YAML unmarshalling, the schema follows the yaml tags of struct fields.
+func Generate(cfg interface{}) *Schema {
+	res := generate(reflect.TypeOf(cfg), make(map[reflect.Type]bool))
+	res.Schema = Draft
+	return res
+}
+
+func generate(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	pt := reflect.PtrTo(t)
+	switch {
+	case t.Implements(describerType):
+		return reflect.Zero(t).Interface().(Describer).ConfigSchema()
+	case pt.Implements(describerType):
+		return reflect.New(t).Interface().(Describer).ConfigSchema()
+	case t == nodeType || pt.Implements(nodeUnmarshalerType) || pt.Implements(funcUnmarshalerType):
+		// we cannot know what such types accept
+		return &Schema{}
+	case pt.Implements(textUnmarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if t == reflect.TypeOf(time.Duration(0)) {
+			return &Schema{Type: "string", Format: FormatDuration}
+		}
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"}
+		}
+		return &Schema{Type: "array", Items: generate(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: generate(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			// recursive types would produce an infinite schema
+			return &Schema{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		res := &Schema{Type: "object", Properties: make(map[string]*Schema), AdditionalProperties: false}
+		addFields(res, t, visiting)
+		return res
+	default:
+		// e.g. interface{}
+		return &Schema{}
+	}
+}
+
+// addFields adds the fields of a struct to an object schema the way YAML unmarshalling maps them
+func addFields(res *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" && !f.Anonymous {
+			// unexported
+			continue
+		}
+
+		tag := f.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		segs := strings.Split(tag, ",")
+		name := segs[0]
+		var inline bool
+		for _, s := range segs[1:] {
+			if s == "inline" {
+				inline = true
+			}
+		}
+
+		if inline {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Map {
+				res.AdditionalProperties = generate(ft.Elem(), visiting)
+				continue
+			}
+			addFields(res, ft, visiting)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		res.Properties[name] = generate(f.Type, visiting)
+	}
+}
+
+// Error is a violation of a schema
+type Error struct {
+	// Path points to the offending value, e.g. werft.repositories[2].timeout
+	Path    string
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (line %d): %s", e.Path, e.Line, e.Message)
+}
+
+// Validate checks a YAML document against a schema and returns all violations. Only YAML syntax errors produce an error.
+func Validate(s *Schema, doc []byte) ([]*Error, error) {
+	var root yaml.Node
+	err := yaml.Unmarshal(doc, &root)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse YAML: %w", err)
+	}
+	if len(root.Content) == 0 {
+		// empty document
+		return nil, nil
+	}
+
+	var res []*Error
+	validate(s, root.Content[0], ".", &res)
+	return res, nil
+}
+
+func validate(s *Schema, n *yaml.Node, path string, errs *[]*Error) {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		// unmarshalling leaves the zero value
+		return
+	}
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, &Error{Path: path, Line: n.Line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch s.Type {
+	case "object":
+		if n.Kind != yaml.MappingNode {
+			fail("expected an object, got %s", describe(n))
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			if key.Value == "<<" {
+				// merge keys are checked where the merged values are defined
+				continue
+			}
+
+			childPath := key.Value
+			if path != "." {
+				childPath = path + "." + key.Value
+			}
+			if ps, ok := s.Properties[key.Value]; ok {
+				validate(ps, val, childPath, errs)
+				continue
+			}
+			switch ap := s.AdditionalProperties.(type) {
+			case *Schema:
+				validate(ap, val, childPath, errs)
+			case bool:
+				if !ap {
+					*errs = append(*errs, &Error{Path: childPath, Line: key.Line, Message: fmt.Sprintf("unknown field, expected one of %s", strings.Join(propertyNames(s), ", "))})
+				}
+			}
+		}
+	case "array":
+		if n.Kind != yaml.SequenceNode {
+			fail("expected a list, got %s", describe(n))
+			return
+		}
+		if s.Items == nil {
+			return
+		}
+		for i, c := range n.Content {
+			validate(s.Items, c, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case "string":
+		// YAML unmarshalling accepts numbers and booleans for strings as well
+		if n.Kind != yaml.ScalarNode {
+			fail("expected a string, got %s", describe(n))
+			return
+		}
+		if s.Format == FormatDuration {
+			if _, err := time.ParseDuration(n.Value); err != nil {
+				fail("expected a duration like 10m or 1h30m, got %q", n.Value)
+			}
+		}
+	case "integer":
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!int" {
+			fail("expected an integer, got %s", describe(n))
+		}
+	case "number":
+		if n.Kind != yaml.ScalarNode || (n.Tag != "!!int" && n.Tag != "!!float") {
+			fail("expected a number, got %s", describe(n))
+		}
+	case "boolean":
+		if n.Kind != yaml.ScalarNode || n.Tag != "!!bool" {
+			fail("expected true or false, got %s", describe(n))
+		}
+	}
+}
+
+// describe names the kind of a YAML node for error messages
+func describe(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "an object"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	switch n.Tag {
+	case "!!int":
+		return fmt.Sprintf("the integer %s", n.Value)
+	case "!!float":
+		return fmt.Sprintf("the number %s", n.Value)
+	case "!!bool":
+		return n.Value
+	}
+	return fmt.Sprintf("%q", n.Value)
+}
+
+func propertyNames(s *Schema) []string {
+	res := make([]string, 0, len(s.Properties))
+	for k := range s.Properties {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,125 @@
+package configschema_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/configschema"
+)
+
+type duration struct{}
+
+func (duration) ConfigSchema() *configschema.Schema {
+	return &configschema.Schema{Type: "string", Format: configschema.FormatDuration}
+}
+
+type testConfig struct {
+	Name    string            `yaml:"name"`
+	Port    int               `yaml:"port,omitempty"`
+	Ratio   float64           `yaml:"ratio,omitempty"`
+	Enabled bool              `yaml:"enabled"`
+	Timeout *duration         `yaml:"timeout,omitempty"`
+	Labels  map[string]string `yaml:"labels,omitempty"`
+	Repos   []struct {
+		Repo string `yaml:"repo"`
+	} `yaml:"repos"`
+	Untagged string
+	Ignored  string      `yaml:"-"`
+	Any      interface{} `yaml:"any"`
+	Inlined  `yaml:",inline"`
+}
+
+type Inlined struct {
+	Extra string `yaml:"extra"`
+}
+
+func TestGenerate(t *testing.T) {
+	s := configschema.Generate(testConfig{})
+	if s.Schema != configschema.Draft {
+		t.Errorf("expected $schema to be %s, got %q", configschema.Draft, s.Schema)
+	}
+	if s.AdditionalProperties != false {
+		t.Errorf("expected structs to disallow additional properties")
+	}
+
+	tests := []struct {
+		Property    string
+		Expectation *configschema.Schema
+	}{
+		{"name", &configschema.Schema{Type: "string"}},
+		{"port", &configschema.Schema{Type: "integer"}},
+		{"ratio", &configschema.Schema{Type: "number"}},
+		{"enabled", &configschema.Schema{Type: "boolean"}},
+		{"timeout", &configschema.Schema{Type: "string", Format: configschema.FormatDuration}},
+		{"labels", &configschema.Schema{Type: "object", AdditionalProperties: &configschema.Schema{Type: "string"}}},
+		{"untagged", &configschema.Schema{Type: "string"}},
+		{"any", &configschema.Schema{}},
+		{"extra", &configschema.Schema{Type: "string"}},
+		{"repos", &configschema.Schema{Type: "array", Items: &configschema.Schema{
+			Type:                 "object",
+			Properties:           map[string]*configschema.Schema{"repo": {Type: "string"}},
+			AdditionalProperties: false,
+		}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Property, func(t *testing.T) {
+			if act := s.Properties[test.Property]; !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("unexpected schema: %+v, expected %+v", act, test.Expectation)
+			}
+		})
+	}
+	if _, ok := s.Properties["Ignored"]; ok {
+		t.Errorf("fields tagged with - must not be part of the schema")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := configschema.Generate(testConfig{})
+
+	tests := []struct {
+		Name        string
+		Doc         string
+		Expectation []configschema.Error
+	}{
+		{"valid", "name: foo\nport: 8080\nratio: 0.5\nenabled: true\ntimeout: 10m\nlabels:\n  a: b\nrepos:\n- repo: foo/bar\nany: [1, 2]\nextra: x\n", nil},
+		{"empty", "", nil},
+		{"null", "name:\nrepos:\n", nil},
+		{"numbers for strings", "name: 1234\n", nil},
+		{"unknown field", "name: foo\nnmae: bar\n", []configschema.Error{
+			{Path: "nmae", Line: 2, Message: "unknown field, expected one of any, enabled, extra, labels, name, port, ratio, repos, timeout, untagged"},
+		}},
+		{"wrong types", "port: \"8080\"\nenabled: yes please\nratio: much\n", []configschema.Error{
+			{Path: "port", Line: 1, Message: `expected an integer, got "8080"`},
+			{Path: "enabled", Line: 2, Message: `expected true or false, got "yes please"`},
+			{Path: "ratio", Line: 3, Message: `expected a number, got "much"`},
+		}},
+		{"invalid duration", "timeout: 10 minutes\n", []configschema.Error{
+			{Path: "timeout", Line: 1, Message: `expected a duration like 10m or 1h30m, got "10 minutes"`},
+		}},
+		{"nested", "repos:\n- repo: foo/bar\n- repo: [foo]\n", []configschema.Error{
+			{Path: "repos[1].repo", Line: 3, Message: "expected a string, got a list"},
+		}},
+		{"map values", "labels:\n  a:\n    b: c\n", []configschema.Error{
+			{Path: "labels.a", Line: 3, Message: "expected a string, got an object"},
+		}},
+		{"not an object", "- foo\n", []configschema.Error{
+			{Path: ".", Line: 1, Message: "expected an object, got a list"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			errs, err := configschema.Validate(s, []byte(test.Doc))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var act []configschema.Error
+			for _, e := range errs {
+				act = append(act, *e)
+			}
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("unexpected violations: %+v, expected %+v", act, test.Expectation)
+			}
+		})
+	}
+}
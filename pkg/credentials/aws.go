@@ -0,0 +1,59 @@
+package credentials
+
+import (
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultAWSAudience is the audience AWS STS expects in web identity tokens
+const defaultAWSAudience = "sts.amazonaws.com"
+
+// AWSConfig configures credentials for an AWS IAM role
+type AWSConfig struct {
+	// RoleARN is the IAM role jobs assume, e.g. arn:aws:iam::123456789012:role/deployer.
+	// The role's trust policy must allow the cluster's OIDC provider.
+	RoleARN string `yaml:"roleARN"`
+
+	// Audience of the projected token. Defaults to sts.amazonaws.com.
+	Audience string `yaml:"audience,omitempty"`
+
+	// Region sets AWS_REGION for the job, if not empty
+	Region string `yaml:"region,omitempty"`
+
+	// TokenExpirationSeconds is the lifetime of the projected token. Defaults to an hour.
+	TokenExpirationSeconds int64 `yaml:"tokenExpirationSeconds,omitempty"`
+}
+
+type awsProvider struct {
+	Name           string
+	serviceAccount string
+	Config         AWSConfig
+}
+
+func newAWS(name, serviceAccount string, cfg AWSConfig) (*awsProvider, error) {
+	if cfg.RoleARN == "" {
+		return nil, xerrors.Errorf("roleARN is required")
+	}
+	if cfg.Audience == "" {
+		cfg.Audience = defaultAWSAudience
+	}
+	return &awsProvider{Name: name, serviceAccount: serviceAccount, Config: cfg}, nil
+}
+
+func (p *awsProvider) ServiceAccount() string {
+	return p.serviceAccount
+}
+
+// Inject makes the AWS SDKs assume the role using the projected token
+func (p *awsProvider) Inject(pod *corev1.Pod) {
+	env := []corev1.EnvVar{
+		{Name: "AWS_ROLE_ARN", Value: p.Config.RoleARN},
+		{Name: "AWS_WEB_IDENTITY_TOKEN_FILE", Value: mountPath(p.Name) + "/token"},
+		{Name: "AWS_ROLE_SESSION_NAME", Value: pod.Name},
+	}
+	if p.Config.Region != "" {
+		env = append(env, corev1.EnvVar{Name: "AWS_REGION", Value: p.Config.Region})
+	}
+
+	inject(pod, p.Name, []corev1.VolumeProjection{tokenProjection(p.Config.Audience, p.Config.TokenExpirationSeconds)}, env)
+}
@@ -0,0 +1,135 @@
+// Package credentials provides short-lived cloud credentials to jobs. Rather than handing long-lived static keys
+// to jobs, providers project a Kubernetes service account token into the job's pod, which the cloud SDKs exchange
+// for short-lived credentials. This requires the cloud provider to trust the cluster's service account issuer.
+//
+// Each set of credentials has a dedicated service account which jobs run with when they request the credentials.
+// The cloud provider should only trust tokens of that service account, so that no other pod can obtain the credentials.
+package credentials
+
+import (
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// basePath is where credentials are mounted in job containers
+	basePath = "/var/run/secrets/werft"
+
+	// defaultTokenExpiration is the lifetime of projected service account tokens. Kubelet refreshes tokens before they expire.
+	defaultTokenExpiration = 3600
+)
+
+// Provider injects short-lived cloud credentials into the pod of a job
+type Provider interface {
+	// Inject adds the volumes and environment variables the job's containers need to obtain credentials
+	Inject(pod *corev1.Pod)
+
+	// ServiceAccount is the Kubernetes service account the job's pod must run with, so that the projected token identifies it
+	ServiceAccount() string
+}
+
+// Config configures a credential provider. Exactly one of AWS or GCP must be set.
+type Config struct {
+	// Name identifies the credentials, jobs request them using this name
+	Name string `yaml:"name"`
+
+	// ServiceAccount is the dedicated Kubernetes service account in the executor's namespace jobs which request these
+	// credentials run with. The cloud provider must trust only this service account's tokens.
+	ServiceAccount string `yaml:"serviceAccount"`
+
+	// AWS provides credentials for an IAM role using web identity federation (like IRSA does)
+	AWS *AWSConfig `yaml:"aws,omitempty"`
+
+	// GCP provides credentials using workload identity federation
+	GCP *GCPConfig `yaml:"gcp,omitempty"`
+
+	// Repositories limits the credentials to jobs of these repositories, given as host/owner/repo or owner/repo. Supports globs.
+	// If empty, jobs of all repositories can use these credentials.
+	Repositories []string `yaml:"repositories,omitempty"`
+
+	// Refs limits the credentials to jobs running on these refs, e.g. refs/heads/master. Supports globs.
+	// If empty, jobs on all refs can use these credentials.
+	Refs []string `yaml:"refs,omitempty"`
+}
+
+// New creates the provider configured by cfg
+func New(cfg Config) (Provider, error) {
+	if cfg.Name == "" {
+		return nil, xerrors.Errorf("credentials need a name")
+	}
+	if cfg.ServiceAccount == "" {
+		return nil, xerrors.Errorf("credentials %s need a dedicated serviceAccount", cfg.Name)
+	}
+	if (cfg.AWS == nil) == (cfg.GCP == nil) {
+		return nil, xerrors.Errorf("credentials %s must configure exactly one of aws or gcp", cfg.Name)
+	}
+
+	var (
+		p   Provider
+		err error
+	)
+	if cfg.AWS != nil {
+		p, err = newAWS(cfg.Name, cfg.ServiceAccount, *cfg.AWS)
+	} else {
+		p, err = newGCP(cfg.Name, cfg.ServiceAccount, *cfg.GCP)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("invalid credentials %s: %w", cfg.Name, err)
+	}
+	return p, nil
+}
+
+// IsTokenProjection returns true if the volume projects a service account token
+func IsTokenProjection(vol corev1.Volume) bool {
+	if vol.Projected == nil {
+		return false
+	}
+	for _, src := range vol.Projected.Sources {
+		if src.ServiceAccountToken != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// volumeName produces the name of the volume holding the credentials of a provider
+func volumeName(name string) string {
+	return "werft-credentials-" + name
+}
+
+// mountPath produces the path the credentials of a provider are mounted at
+func mountPath(name string) string {
+	return basePath + "/" + name
+}
+
+// tokenProjection projects a service account token for the audience into the credentials volume
+func tokenProjection(audience string, expiration int64) corev1.VolumeProjection {
+	if expiration == 0 {
+		expiration = defaultTokenExpiration
+	}
+	return corev1.VolumeProjection{
+		ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+			Audience:          audience,
+			ExpirationSeconds: &expiration,
+			Path:              "token",
+		},
+	}
+}
+
+// inject adds the credentials volume to the pod and mounts it, together with the environment, into all job containers
+func inject(pod *corev1.Pod, name string, sources []corev1.VolumeProjection, env []corev1.EnvVar) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: volumeName(name),
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{Sources: sources},
+		},
+	})
+	for i, c := range pod.Spec.Containers {
+		pod.Spec.Containers[i].VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
+			Name:      volumeName(name),
+			MountPath: mountPath(name),
+			ReadOnly:  true,
+		})
+		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, env...)
+	}
+}
@@ -0,0 +1,128 @@
+package credentials_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/credentials"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config credentials.Config
+		Error  bool
+	}{
+		{"aws", credentials.Config{Name: "deploy", ServiceAccount: "werft-deploy", AWS: &credentials.AWSConfig{RoleARN: "arn:aws:iam::123456789012:role/deployer"}}, false},
+		{"gcp", credentials.Config{Name: "deploy", ServiceAccount: "werft-deploy", GCP: &credentials.GCPConfig{Audience: "//iam.googleapis.com/foo"}}, false},
+		{"no name", credentials.Config{ServiceAccount: "werft-deploy", AWS: &credentials.AWSConfig{RoleARN: "arn"}}, true},
+		{"no service account", credentials.Config{Name: "deploy", AWS: &credentials.AWSConfig{RoleARN: "arn"}}, true},
+		{"no provider", credentials.Config{Name: "deploy"}, true},
+		{"both providers", credentials.Config{Name: "deploy", ServiceAccount: "werft-deploy", AWS: &credentials.AWSConfig{RoleARN: "arn"}, GCP: &credentials.GCPConfig{Audience: "aud"}}, true},
+		{"aws without role", credentials.Config{Name: "deploy", ServiceAccount: "werft-deploy", AWS: &credentials.AWSConfig{}}, true},
+		{"gcp without audience", credentials.Config{Name: "deploy", ServiceAccount: "werft-deploy", GCP: &credentials.GCPConfig{}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := credentials.New(test.Config)
+			if (err != nil) != test.Error {
+				t.Errorf("expected error: %v, got %v", test.Error, err)
+			}
+		})
+	}
+}
+
+func TestInject(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Config   credentials.Config
+		Env      map[string]string
+		Audience string
+	}{
+		{
+			Name:   "aws",
+			Config: credentials.Config{Name: "deploy", ServiceAccount: "werft-deploy", AWS: &credentials.AWSConfig{RoleARN: "arn:aws:iam::123456789012:role/deployer", Region: "eu-west-1"}},
+			Env: map[string]string{
+				"AWS_ROLE_ARN":                "arn:aws:iam::123456789012:role/deployer",
+				"AWS_WEB_IDENTITY_TOKEN_FILE": "/var/run/secrets/werft/deploy/token",
+				"AWS_ROLE_SESSION_NAME":       "foo.1",
+				"AWS_REGION":                  "eu-west-1",
+			},
+			Audience: "sts.amazonaws.com",
+		},
+		{
+			Name:   "gcp",
+			Config: credentials.Config{Name: "deploy", ServiceAccount: "werft-deploy", GCP: &credentials.GCPConfig{Audience: "//iam.googleapis.com/foo", ServiceAccount: "deployer@project.iam.gserviceaccount.com"}},
+			Env: map[string]string{
+				"GOOGLE_APPLICATION_CREDENTIALS":         "/var/run/secrets/werft/deploy/credentials.json",
+				"CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE": "/var/run/secrets/werft/deploy/credentials.json",
+			},
+			Audience: "//iam.googleapis.com/foo",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			p, err := credentials.New(test.Config)
+			if err != nil {
+				t.Fatalf("cannot create provider: %v", err)
+			}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo.1"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "build"}, {Name: "sidecar"}},
+				},
+			}
+			p.Inject(pod)
+
+			if sa := p.ServiceAccount(); sa != "werft-deploy" {
+				t.Errorf("expected the dedicated service account, got %s", sa)
+			}
+			if !credentials.IsTokenProjection(pod.Spec.Volumes[0]) {
+				t.Errorf("expected the credentials volume to project a token")
+			}
+
+			if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].Projected == nil {
+				t.Fatalf("expected a single projected volume, got %v", pod.Spec.Volumes)
+			}
+			var audience string
+			for _, src := range pod.Spec.Volumes[0].Projected.Sources {
+				if src.ServiceAccountToken != nil {
+					audience = src.ServiceAccountToken.Audience
+				}
+			}
+			if audience != test.Audience {
+				t.Errorf("expected token audience %s, got %s", test.Audience, audience)
+			}
+
+			for _, c := range pod.Spec.Containers {
+				if len(c.VolumeMounts) != 1 || c.VolumeMounts[0].MountPath != "/var/run/secrets/werft/deploy" {
+					t.Errorf("container %s does not mount the credentials: %v", c.Name, c.VolumeMounts)
+				}
+
+				env := make(map[string]string)
+				for _, e := range c.Env {
+					env[e.Name] = e.Value
+				}
+				for k, v := range test.Env {
+					if env[k] != v {
+						t.Errorf("container %s: expected %s=%s, got %s", c.Name, k, v, env[k])
+					}
+				}
+			}
+
+			if test.Config.GCP != nil {
+				var cc map[string]interface{}
+				err := json.Unmarshal([]byte(pod.Annotations["credentials.werft.sh/gcp-deploy"]), &cc)
+				if err != nil {
+					t.Fatalf("cannot unmarshal credential configuration: %v", err)
+				}
+				if cc["type"] != "external_account" || cc["audience"] != test.Audience || cc["service_account_impersonation_url"] == nil {
+					t.Errorf("unexpected credential configuration: %v", cc)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// annotationGCPCredentialsPrefix prefixes the pod annotation which holds the credential configuration file
+const annotationGCPCredentialsPrefix = "credentials.werft.sh/gcp-"
+
+// GCPConfig configures credentials using GCP workload identity federation
+type GCPConfig struct {
+	// Audience is the full resource name of the workload identity provider, e.g.
+	// //iam.googleapis.com/projects/123/locations/global/workloadIdentityPools/werft/providers/cluster
+	Audience string `yaml:"audience"`
+
+	// ServiceAccount is the GCP service account jobs impersonate, e.g. deployer@project.iam.gserviceaccount.com.
+	// If empty, jobs use the federated identity directly.
+	ServiceAccount string `yaml:"serviceAccount,omitempty"`
+
+	// TokenExpirationSeconds is the lifetime of the projected token. Defaults to an hour.
+	TokenExpirationSeconds int64 `yaml:"tokenExpirationSeconds,omitempty"`
+}
+
+type gcpProvider struct {
+	Name           string
+	serviceAccount string
+	Config         GCPConfig
+
+	credentialConfig string
+}
+
+func newGCP(name, serviceAccount string, cfg GCPConfig) (*gcpProvider, error) {
+	if cfg.Audience == "" {
+		return nil, xerrors.Errorf("audience is required")
+	}
+
+	// this is the credential configuration file gcloud iam workload-identity-pools create-cred-config produces
+	cc := map[string]interface{}{
+		"type":               "external_account",
+		"audience":           cfg.Audience,
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url":          "https://sts.googleapis.com/v1/token",
+		"credential_source": map[string]string{
+			"file": mountPath(name) + "/token",
+		},
+	}
+	if cfg.ServiceAccount != "" {
+		cc["service_account_impersonation_url"] = fmt.Sprintf("https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:generateAccessToken", cfg.ServiceAccount)
+	}
+	fc, err := json.Marshal(cc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gcpProvider{Name: name, serviceAccount: serviceAccount, Config: cfg, credentialConfig: string(fc)}, nil
+}
+
+func (p *gcpProvider) ServiceAccount() string {
+	return p.serviceAccount
+}
+
+// Inject makes the GCP SDKs and gcloud exchange the projected token for credentials
+func (p *gcpProvider) Inject(pod *corev1.Pod) {
+	// the credential configuration reaches the container through the downward API, so that we don't need a config map per job
+	annotation := annotationGCPCredentialsPrefix + p.Name
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[annotation] = p.credentialConfig
+
+	credentialsFile := mountPath(p.Name) + "/credentials.json"
+	inject(pod, p.Name, []corev1.VolumeProjection{
+		tokenProjection(p.Config.Audience, p.Config.TokenExpirationSeconds),
+		{
+			DownwardAPI: &corev1.DownwardAPIProjection{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path:     "credentials.json",
+						FieldRef: &corev1.ObjectFieldSelector{FieldPath: fmt.Sprintf("metadata.annotations['%s']", annotation)},
+					},
+				},
+			},
+		},
+	}, []corev1.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: credentialsFile},
+		{Name: "CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE", Value: credentialsFile},
+	})
+}
@@ -0,0 +1,475 @@
+package executor
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// agentWaitInterval is how often a job which waits for an agent to connect checks again
+const agentWaitInterval = time.Second
+
+// AgentConfig configures the agent executor
+type AgentConfig struct {
+	// Tokens authenticate agents: agents must present one of them when they connect
+	Tokens []string `yaml:"tokens"`
+}
+
+// NewAgentExecutor creates a new executor which runs jobs using the agents which connect to werft
+func NewAgentExecutor(config Config) (*AgentExecutor, error) {
+	err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+	if config.Agent == nil || len(config.Agent.Tokens) == 0 {
+		return nil, xerrors.Errorf("the agent executor needs at least one agent token")
+	}
+
+	rt := &agentRuntime{
+		Config: config.Agent,
+		agents: make(map[string]*agentConn),
+		jobs:   make(map[string]*agentConn),
+	}
+	return &AgentExecutor{newRuntimeExecutor(config, rt), rt}, nil
+}
+
+// AgentExecutor runs jobs using agents, which run in clusters werft cannot reach and connect to werft instead.
+// Every job runs on the connected agent with the fewest jobs. Jobs wait for an agent to connect, until their
+// preparation times out. Agents run jobs like the docker executor does - in Kubernetes or using docker - while
+// queueing, approvals, maintenance and timeouts are up to werft.
+//
+// AgentExecutor serves the agent API agents connect to. Jobs live in memory only: werft loses the jobs which run
+// when it stops or their agent disconnects.
+type AgentExecutor struct {
+	*runtimeExecutor
+
+	agents *agentRuntime
+}
+
+// Connect serves an agent until it disconnects
+func (a *AgentExecutor) Connect(stream v1.AgentService_ConnectServer) error {
+	return a.agents.serve(stream)
+}
+
+// Agents returns the names of the agents which are connected
+func (a *AgentExecutor) Agents() []string {
+	a.agents.mu.Lock()
+	defer a.agents.mu.Unlock()
+
+	res := make([]string, 0, len(a.agents.agents))
+	for name := range a.agents.agents {
+		res = append(res, name)
+	}
+	return res
+}
+
+// agentRuntime runs pods using the connected agents
+type agentRuntime struct {
+	Config *AgentConfig
+
+	mu     sync.Mutex
+	agents map[string]*agentConn
+	// jobs maps the names of the pods which run to the agent which runs them
+	jobs map[string]*agentConn
+
+	cmdID uint64
+}
+
+// agentConn is the connection to an agent
+type agentConn struct {
+	Name   string
+	stream v1.AgentService_ConnectServer
+
+	sendMu sync.Mutex
+	mu     sync.Mutex
+	calls  map[uint64]chan *v1.AgentMessage
+	jobs   int
+
+	// closed is closed once the agent disconnected
+	closed chan struct{}
+}
+
+// serve registers an agent and dispatches its messages until it disconnects
+func (rt *agentRuntime) serve(stream v1.AgentService_ConnectServer) error {
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	hello := msg.GetHello()
+	if hello == nil || hello.Name == "" {
+		return status.Error(codes.InvalidArgument, "agents must introduce themselves with their name first")
+	}
+	var authenticated bool
+	for _, token := range rt.Config.Tokens {
+		if subtle.ConstantTimeCompare([]byte(hello.Token), []byte(token)) == 1 {
+			authenticated = true
+		}
+	}
+	if !authenticated {
+		return status.Error(codes.PermissionDenied, "invalid agent token")
+	}
+
+	conn := &agentConn{
+		Name:   hello.Name,
+		stream: stream,
+		calls:  make(map[uint64]chan *v1.AgentMessage),
+		closed: make(chan struct{}),
+	}
+	rt.mu.Lock()
+	if _, exists := rt.agents[conn.Name]; exists {
+		rt.mu.Unlock()
+		return status.Errorf(codes.AlreadyExists, "agent %s is connected already", conn.Name)
+	}
+	rt.agents[conn.Name] = conn
+	rt.mu.Unlock()
+	log.WithField("agent", conn.Name).Info("agent connected")
+
+	defer func() {
+		rt.mu.Lock()
+		delete(rt.agents, conn.Name)
+		rt.mu.Unlock()
+		close(conn.closed)
+		log.WithField("agent", conn.Name).Info("agent disconnected")
+	}()
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		conn.mu.Lock()
+		c, ok := conn.calls[msg.Id]
+		conn.mu.Unlock()
+		if !ok {
+			// the command was cancelled in the meantime
+			continue
+		}
+		select {
+		case c <- msg:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// call sends a command to the agent and returns the channel its answers arrive on. Callers must end the call.
+func (rt *agentRuntime) call(conn *agentConn, cmd *v1.AgentCommand) (id uint64, answers <-chan *v1.AgentMessage, err error) {
+	cmd.Id = atomic.AddUint64(&rt.cmdID, 1)
+	c := make(chan *v1.AgentMessage, 64)
+	conn.mu.Lock()
+	conn.calls[cmd.Id] = c
+	conn.mu.Unlock()
+
+	conn.sendMu.Lock()
+	err = conn.stream.Send(cmd)
+	conn.sendMu.Unlock()
+	if err != nil {
+		conn.end(cmd.Id)
+		return 0, nil, xerrors.Errorf("cannot send command to agent %s: %w", conn.Name, err)
+	}
+	return cmd.Id, c, nil
+}
+
+// end forgets about a call
+func (conn *agentConn) end(id uint64) {
+	conn.mu.Lock()
+	delete(conn.calls, id)
+	conn.mu.Unlock()
+}
+
+// do sends a command to the agent and waits for it to be done
+func (rt *agentRuntime) do(conn *agentConn, cmd *v1.AgentCommand) error {
+	id, answers, err := rt.call(conn, cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.end(id)
+
+	for {
+		select {
+		case msg := <-answers:
+			if done := msg.GetDone(); done != nil {
+				if done.Error != "" {
+					return xerrors.Errorf("agent %s: %s", conn.Name, done.Error)
+				}
+				return nil
+			}
+		case <-conn.closed:
+			return xerrors.Errorf("agent %s disconnected", conn.Name)
+		}
+	}
+}
+
+// Name returns agent
+func (rt *agentRuntime) Name() string {
+	return "agent"
+}
+
+// CheckPod accepts all pods - the agent which runs a pod checks whether it can
+func (rt *agentRuntime) CheckPod(pod *corev1.Pod) error {
+	return nil
+}
+
+// RemoveAll does nothing: agents remove what their jobs left behind whenever they connect
+func (rt *agentRuntime) RemoveAll() error {
+	return nil
+}
+
+// pick returns the connected agent with the fewest jobs, or nil if no agent is connected
+func (rt *agentRuntime) pick() *agentConn {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	var res *agentConn
+	for _, conn := range rt.agents {
+		if res == nil || conn.jobs < res.jobs || (conn.jobs == res.jobs && conn.Name < res.Name) {
+			res = conn
+		}
+	}
+	if res != nil {
+		res.jobs++
+	}
+	return res
+}
+
+// agentOf returns the agent which runs a pod, or nil if no agent does
+func (rt *agentRuntime) agentOf(pod string) *agentConn {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	return rt.jobs[pod]
+}
+
+// RunPod waits for an agent to connect, has it run the pod and relays what it reports
+func (rt *agentRuntime) RunPod(pod *corev1.Pod, r podReporter) error {
+	var conn *agentConn
+	for i := 0; ; i++ {
+		if r.Stopped() {
+			return nil
+		}
+		conn = rt.pick()
+		if conn != nil {
+			break
+		}
+		if i == 0 {
+			log.WithField("name", pod.Name).Info("waiting for an agent to connect")
+		}
+		time.Sleep(agentWaitInterval)
+	}
+	rt.mu.Lock()
+	rt.jobs[pod.Name] = conn
+	rt.mu.Unlock()
+	log.WithField("name", pod.Name).WithField("agent", conn.Name).Debug("running job on agent")
+
+	spec, err := json.Marshal(pod)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal pod: %w", err)
+	}
+	id, answers, err := rt.call(conn, &v1.AgentCommand{Content: &v1.AgentCommand_RunPod{RunPod: &v1.AgentRunPod{Pod: spec}}})
+	if err != nil {
+		return err
+	}
+	defer conn.end(id)
+
+	for {
+		var msg *v1.AgentMessage
+		select {
+		case msg = <-answers:
+		case <-conn.closed:
+			return xerrors.Errorf("agent %s disconnected", conn.Name)
+		}
+
+		switch c := msg.Content.(type) {
+		case *v1.AgentMessage_ContainerStarted:
+			r.ContainerStarted(c.ContainerStarted.Name, agentContainerID(conn.Name, c.ContainerStarted.Id), c.ContainerStarted.Init)
+		case *v1.AgentMessage_ContainerStopped:
+			var state corev1.ContainerStateTerminated
+			err := json.Unmarshal(c.ContainerStopped.State, &state)
+			if err != nil {
+				log.WithError(err).WithField("name", pod.Name).WithField("agent", conn.Name).Warn("agent sent invalid container state")
+				state = corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Unknown"}
+			}
+			r.ContainerStopped(c.ContainerStopped.Name, state)
+		case *v1.AgentMessage_InitDone:
+			r.InitDone()
+		case *v1.AgentMessage_Done:
+			if c.Done.Error != "" {
+				return xerrors.Errorf("agent %s: %s", conn.Name, c.Done.Error)
+			}
+			return nil
+		}
+	}
+}
+
+// agentContainerID makes the ID of a container unique across agents
+func agentContainerID(agent, id string) string {
+	return agent + "/" + id
+}
+
+// splitAgentContainerID returns the agent and its ID of a container
+func splitAgentContainerID(id string) (agent, container string, err error) {
+	segs := strings.SplitN(id, "/", 2)
+	if len(segs) != 2 {
+		return "", "", xerrors.Errorf("invalid container ID %s", id)
+	}
+	return segs[0], segs[1], nil
+}
+
+// agentContainers translates container IDs to the IDs the agent knows
+func agentContainers(containers map[string]string) map[string]string {
+	res := make(map[string]string, len(containers))
+	for name, id := range containers {
+		if _, cid, err := splitAgentContainerID(id); err == nil {
+			res[name] = cid
+		}
+	}
+	return res
+}
+
+// StopPod has the agent which runs a pod stop it
+func (rt *agentRuntime) StopPod(pod *corev1.Pod, containers map[string]string, gracePeriod time.Duration) {
+	conn := rt.agentOf(pod.Name)
+	if conn == nil {
+		// the pod does not run (yet)
+		return
+	}
+	spec, err := json.Marshal(pod)
+	if err == nil {
+		err = rt.do(conn, &v1.AgentCommand{Content: &v1.AgentCommand_StopPod{StopPod: &v1.AgentStopPod{
+			Pod:                spec,
+			Containers:         agentContainers(containers),
+			GracePeriodSeconds: int64(gracePeriod.Seconds()),
+		}}})
+	}
+	if err != nil {
+		log.WithError(err).WithField("name", pod.Name).WithField("agent", conn.Name).Warn("cannot stop job")
+	}
+}
+
+// RemovePod has the agent which ran a pod remove it
+func (rt *agentRuntime) RemovePod(pod *corev1.Pod, containers map[string]string) error {
+	rt.mu.Lock()
+	conn := rt.jobs[pod.Name]
+	delete(rt.jobs, pod.Name)
+	if conn != nil {
+		conn.jobs--
+	}
+	rt.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+
+	spec, err := json.Marshal(pod)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal pod: %w", err)
+	}
+	return rt.do(conn, &v1.AgentCommand{Content: &v1.AgentCommand_RemovePod{RemovePod: &v1.AgentRemovePod{
+		Pod:        spec,
+		Containers: agentContainers(containers),
+	}}})
+}
+
+// conn returns the connection to the agent which runs a container
+func (rt *agentRuntime) conn(id string) (conn *agentConn, container string, err error) {
+	agent, container, err := splitAgentContainerID(id)
+	if err != nil {
+		return nil, "", err
+	}
+	rt.mu.Lock()
+	conn = rt.agents[agent]
+	rt.mu.Unlock()
+	if conn == nil {
+		return nil, "", xerrors.Errorf("agent %s is not connected", agent)
+	}
+	return conn, container, nil
+}
+
+// Logs streams the output of a container from the agent which runs it
+func (rt *agentRuntime) Logs(id string) (io.ReadCloser, error) {
+	conn, container, err := rt.conn(id)
+	if err != nil {
+		return nil, err
+	}
+	cid, answers, err := rt.call(conn, &v1.AgentCommand{Content: &v1.AgentCommand_Logs{Logs: &v1.AgentLogs{ContainerId: container, Follow: true}}})
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer conn.end(cid)
+		for {
+			select {
+			case msg := <-answers:
+				if done := msg.GetDone(); done != nil {
+					pw.Close()
+					return
+				}
+				_, err := pw.Write(msg.GetLogs())
+				if err != nil {
+					// the reader is gone - tell the agent to stop sending
+					conn.sendMu.Lock()
+					//nolint:errcheck
+					conn.stream.Send(&v1.AgentCommand{Content: &v1.AgentCommand_Cancel{Cancel: cid}})
+					conn.sendMu.Unlock()
+					return
+				}
+			case <-conn.closed:
+				pw.CloseWithError(xerrors.Errorf("agent %s disconnected", conn.Name))
+				return
+			}
+		}
+	}()
+	return pr, nil
+}
+
+// ContainerLogs returns the output of a container the agent ran
+func (rt *agentRuntime) ContainerLogs(id string, limit int64) ([]byte, error) {
+	conn, container, err := rt.conn(id)
+	if err != nil {
+		return nil, err
+	}
+	cid, answers, err := rt.call(conn, &v1.AgentCommand{Content: &v1.AgentCommand_Logs{Logs: &v1.AgentLogs{ContainerId: container, Limit: limit}}})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.end(cid)
+
+	var out []byte
+	for {
+		select {
+		case msg := <-answers:
+			if done := msg.GetDone(); done != nil {
+				if done.Error != "" {
+					return nil, xerrors.Errorf("agent %s: %s", conn.Name, done.Error)
+				}
+				if int64(len(out)) > limit {
+					out = out[:limit]
+				}
+				return out, nil
+			}
+			out = append(out, msg.GetLogs()...)
+		case <-conn.closed:
+			return nil, xerrors.Errorf("agent %s disconnected", conn.Name)
+		}
+	}
+}
+
+// Exec is not supported: agents don't relay interactive sessions
+func (rt *agentRuntime) Exec(id string, opts AttachOptions) error {
+	return xerrors.Errorf("the agent executor cannot attach to jobs")
+}
@@ -0,0 +1,426 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAgentContainerID(t *testing.T) {
+	agent, id, err := splitAgentContainerID(agentContainerID("eu-west", "abc/def"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agent != "eu-west" || id != "abc/def" {
+		t.Errorf("unexpected split: %s, %s", agent, id)
+	}
+	_, _, err = splitAgentContainerID("abc")
+	if err == nil {
+		t.Error("expected an error for an ID without agent")
+	}
+
+	containers := agentContainers(map[string]string{"build": "eu-west/abc", "broken": "abc"})
+	if !reflect.DeepEqual(containers, map[string]string{"build": "abc"}) {
+		t.Errorf("unexpected agent containers: %v", containers)
+	}
+}
+
+func TestAgentPick(t *testing.T) {
+	rt := &agentRuntime{agents: make(map[string]*agentConn), jobs: make(map[string]*agentConn)}
+	if conn := rt.pick(); conn != nil {
+		t.Fatalf("pick should return nil without agents, got %s", conn.Name)
+	}
+
+	rt.agents["b"] = &agentConn{Name: "b"}
+	rt.agents["a"] = &agentConn{Name: "a", jobs: 1}
+	var picked []string
+	for i := 0; i < 4; i++ {
+		picked = append(picked, rt.pick().Name)
+	}
+	expected := []string{"b", "a", "b", "a"}
+	if !reflect.DeepEqual(picked, expected) {
+		t.Errorf("unexpected picks: got %v, want %v", picked, expected)
+	}
+}
+
+// fakeAgentStream records the messages an agent sends to werft
+type fakeAgentStream struct {
+	grpc.ClientStream
+	sent []*v1.AgentMessage
+}
+
+func (s *fakeAgentStream) Send(msg *v1.AgentMessage) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func (s *fakeAgentStream) Recv() (*v1.AgentCommand, error) {
+	return nil, io.EOF
+}
+
+func TestAgentRejectsHostAccess(t *testing.T) {
+	tests := []struct {
+		Name string
+		Pod  *corev1.Pod
+	}{
+		{"host path", hostPathPod("", "/var/run/docker.sock", corev1.VolumeMount{Name: "host", MountPath: "/var/run/docker.sock"}, false)},
+		{"privileged", hostPathPod("", "", corev1.VolumeMount{Name: "ws", MountPath: "/workspace"}, true)},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			spec, err := json.Marshal(test.Pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			stream := &fakeAgentStream{}
+			s := &agentSession{
+				Agent:   NewDockerAgent("agent", "token", &DockerConfig{Command: "werft-test-no-such-cli"}),
+				stream:  stream,
+				stopped: make(map[string]bool),
+				logs:    make(map[uint64]io.Closer),
+			}
+			s.handle(&v1.AgentCommand{Id: 42, Content: &v1.AgentCommand_RunPod{RunPod: &v1.AgentRunPod{Pod: spec}}})
+
+			if len(stream.sent) != 1 {
+				t.Fatalf("expected the agent to send exactly one message, got %d", len(stream.sent))
+			}
+			msg := stream.sent[0]
+			if msg.Id != 42 || msg.GetDone() == nil || msg.GetDone().Error == "" {
+				t.Errorf("expected the agent to refuse the pod, got %v", msg)
+			}
+		})
+	}
+}
+
+// fakeAgentConn is werft's end of the connection of an agent the test plays
+type fakeAgentConn struct {
+	grpc.ServerStream
+	in  chan *v1.AgentMessage
+	out chan *v1.AgentCommand
+
+	disconnectOnce sync.Once
+}
+
+// newFakeAgentConn creates a connection whose agent introduces itself with hello
+func newFakeAgentConn(hello *v1.AgentMessage) *fakeAgentConn {
+	c := &fakeAgentConn{
+		in:  make(chan *v1.AgentMessage, 16),
+		out: make(chan *v1.AgentCommand, 16),
+	}
+	c.in <- hello
+	return c
+}
+
+func (c *fakeAgentConn) Send(cmd *v1.AgentCommand) error {
+	c.out <- cmd
+	return nil
+}
+
+func (c *fakeAgentConn) Recv() (*v1.AgentMessage, error) {
+	msg, ok := <-c.in
+	if !ok {
+		return nil, io.EOF
+	}
+	return msg, nil
+}
+
+func (c *fakeAgentConn) Context() context.Context {
+	return context.Background()
+}
+
+// disconnect ends the connection as the agent
+func (c *fakeAgentConn) disconnect() {
+	c.disconnectOnce.Do(func() { close(c.in) })
+}
+
+// next returns the next command werft sends to the agent
+func (c *fakeAgentConn) next(t *testing.T) *v1.AgentCommand {
+	select {
+	case cmd := <-c.out:
+		return cmd
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent received no command")
+		return nil
+	}
+}
+
+func agentHello(name, token string) *v1.AgentMessage {
+	return &v1.AgentMessage{Content: &v1.AgentMessage_Hello{Hello: &v1.AgentHello{Name: name, Token: token}}}
+}
+
+func agentDone(id uint64, err string) *v1.AgentMessage {
+	return &v1.AgentMessage{Id: id, Content: &v1.AgentMessage_Done{Done: &v1.AgentDone{Error: err}}}
+}
+
+func newTestAgentExecutor(t *testing.T) *AgentExecutor {
+	a, err := NewAgentExecutor(Config{
+		Namespace:       "werft",
+		JobPrepTimeout:  &Duration{Duration: time.Minute},
+		JobTotalTimeout: &Duration{Duration: time.Hour},
+		Agent:           &AgentConfig{Tokens: []string{"secret"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+// connectAgent serves conn in the background and waits until its agent is registered. The returned channel
+// receives the result of serving the agent once it disconnects.
+func connectAgent(t *testing.T, a *AgentExecutor, name string, conn *fakeAgentConn) <-chan error {
+	res := make(chan error, 1)
+	go func() { res <- a.Connect(conn) }()
+	waitForAgents(t, a, []string{name})
+	return res
+}
+
+func waitForAgents(t *testing.T, a *AgentExecutor, expected []string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		act := a.Agents()
+		if len(act) == 0 && len(expected) == 0 || reflect.DeepEqual(act, expected) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected agents %v, got %v", expected, act)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAgentRegistration(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Hello *v1.AgentMessage
+		Code  codes.Code
+	}{
+		{"no hello", &v1.AgentMessage{Content: &v1.AgentMessage_InitDone{InitDone: &v1.AgentInitDone{}}}, codes.InvalidArgument},
+		{"no name", agentHello("", "secret"), codes.InvalidArgument},
+		{"invalid token", agentHello("eu", "guess"), codes.PermissionDenied},
+		{"no token", agentHello("eu", ""), codes.PermissionDenied},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			a := newTestAgentExecutor(t)
+			err := a.Connect(newFakeAgentConn(test.Hello))
+			if status.Code(err) != test.Code {
+				t.Errorf("expected %v, got %v", test.Code, err)
+			}
+			if agents := a.Agents(); len(agents) != 0 {
+				t.Errorf("expected no agent to be registered, got %v", agents)
+			}
+		})
+	}
+
+	a := newTestAgentExecutor(t)
+	conn := newFakeAgentConn(agentHello("eu", "secret"))
+	served := connectAgent(t, a, "eu", conn)
+
+	err := a.Connect(newFakeAgentConn(agentHello("eu", "secret")))
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("expected a second agent with the same name to be refused, got %v", err)
+	}
+
+	conn.disconnect()
+	if err := <-served; err != nil {
+		t.Errorf("expected the agent to disconnect cleanly, got %v", err)
+	}
+	waitForAgents(t, a, nil)
+}
+
+func TestAgentRunPod(t *testing.T) {
+	a := newTestAgentExecutor(t)
+	conn := newFakeAgentConn(agentHello("eu", "secret"))
+	connectAgent(t, a, "eu", conn)
+	defer conn.disconnect()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-1"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "checkout", Image: "alpine/git"}},
+			Containers:     []corev1.Container{{Name: "build", Image: "golang"}},
+		},
+	}
+	var r testPodReporter
+	ran := make(chan error, 1)
+	go func() { ran <- a.agents.RunPod(pod, &r) }()
+
+	cmd := conn.next(t)
+	var dispatched corev1.Pod
+	if err := json.Unmarshal(cmd.GetRunPod().GetPod(), &dispatched); err != nil {
+		t.Fatalf("expected the agent to be told to run the pod, got %v: %v", cmd, err)
+	}
+	if dispatched.Name != "job-1" {
+		t.Errorf("expected pod job-1 to be dispatched, got %s", dispatched.Name)
+	}
+
+	completed, _ := json.Marshal(corev1.ContainerStateTerminated{Reason: "Completed"})
+	failed, _ := json.Marshal(corev1.ContainerStateTerminated{ExitCode: 2, Reason: "Error"})
+	for _, msg := range []*v1.AgentMessage{
+		{Content: &v1.AgentMessage_ContainerStarted{ContainerStarted: &v1.AgentContainerStarted{Name: "checkout", Id: "c1", Init: true}}},
+		{Content: &v1.AgentMessage_ContainerStopped{ContainerStopped: &v1.AgentContainerStopped{Name: "checkout", State: completed}}},
+		{Content: &v1.AgentMessage_InitDone{InitDone: &v1.AgentInitDone{}}},
+		{Content: &v1.AgentMessage_ContainerStarted{ContainerStarted: &v1.AgentContainerStarted{Name: "build", Id: "c2"}}},
+		{Content: &v1.AgentMessage_ContainerStopped{ContainerStopped: &v1.AgentContainerStopped{Name: "build", State: failed}}},
+		agentDone(0, ""),
+	} {
+		msg.Id = cmd.Id
+		conn.in <- msg
+	}
+	if err := <-ran; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"started checkout init=true",
+		"stopped checkout 0 Completed",
+		"init done",
+		"started build init=false",
+		"stopped build 2 Error",
+	}
+	if !reflect.DeepEqual(r.events, expected) {
+		t.Errorf("unexpected events:\n got %q\nwant %q", r.events, expected)
+	}
+	ids := r.containerIDs()
+	if exp := map[string]string{"checkout": "eu/c1", "build": "eu/c2"}; !reflect.DeepEqual(ids, exp) {
+		t.Errorf("unexpected container IDs: got %v, want %v", ids, exp)
+	}
+
+	// the agent sends logs in chunks until it's done
+	logs, err := a.agents.Logs(ids["build"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd = conn.next(t)
+	if l := cmd.GetLogs(); l == nil || l.ContainerId != "c2" || !l.Follow {
+		t.Fatalf("expected the agent to be asked for the logs of c2, got %v", cmd)
+	}
+	for _, chunk := range []string{"compiling\n", "done\n"} {
+		conn.in <- &v1.AgentMessage{Id: cmd.Id, Content: &v1.AgentMessage_Logs{Logs: []byte(chunk)}}
+	}
+	conn.in <- agentDone(cmd.Id, "")
+	out, err := ioutil.ReadAll(logs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "compiling\ndone\n" {
+		t.Errorf("unexpected logs: %q", string(out))
+	}
+
+	removed := make(chan error, 1)
+	go func() { removed <- a.agents.RemovePod(pod, ids) }()
+	cmd = conn.next(t)
+	if rp := cmd.GetRemovePod(); rp == nil || !reflect.DeepEqual(rp.Containers, map[string]string{"checkout": "c1", "build": "c2"}) {
+		t.Fatalf("expected the agent to be told to remove the containers, got %v", cmd)
+	}
+	conn.in <- agentDone(cmd.Id, "")
+	if err := <-removed; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := a.agents.agentOf("job-1"); c != nil {
+		t.Errorf("expected the job to be forgotten once removed, but it's still on agent %s", c.Name)
+	}
+}
+
+func TestAgentRunPodFailure(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-1"}}
+	tests := []struct {
+		Name  string
+		Agent func(conn *fakeAgentConn, cmd *v1.AgentCommand)
+		Error string
+	}{
+		{
+			Name:  "agent fails",
+			Agent: func(conn *fakeAgentConn, cmd *v1.AgentCommand) { conn.in <- agentDone(cmd.Id, "cannot pull image") },
+			Error: "agent eu: cannot pull image",
+		},
+		{
+			Name:  "agent disconnects",
+			Agent: func(conn *fakeAgentConn, cmd *v1.AgentCommand) { conn.disconnect() },
+			Error: "agent eu disconnected",
+		},
+		{
+			Name: "invalid container state",
+			Agent: func(conn *fakeAgentConn, cmd *v1.AgentCommand) {
+				conn.in <- &v1.AgentMessage{Id: cmd.Id, Content: &v1.AgentMessage_ContainerStopped{ContainerStopped: &v1.AgentContainerStopped{Name: "build", State: []byte("{")}}}
+				conn.in <- agentDone(cmd.Id, "")
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			a := newTestAgentExecutor(t)
+			conn := newFakeAgentConn(agentHello("eu", "secret"))
+			connectAgent(t, a, "eu", conn)
+			defer conn.disconnect()
+
+			var r testPodReporter
+			ran := make(chan error, 1)
+			go func() { ran <- a.agents.RunPod(pod, &r) }()
+			test.Agent(conn, conn.next(t))
+
+			err := <-ran
+			if test.Error == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if test.Error != "" && (err == nil || !strings.Contains(err.Error(), test.Error)) {
+				t.Errorf("expected error containing %q, got %v", test.Error, err)
+			}
+			if test.Error == "" && !reflect.DeepEqual(r.events, []string{"stopped build 1 Unknown"}) {
+				t.Errorf("expected a container with an invalid state to have failed, got %q", r.events)
+			}
+		})
+	}
+}
+
+func TestAgentReporter(t *testing.T) {
+	a := newTestAgentExecutor(t)
+	conn := newFakeAgentConn(agentHello("eu", "secret"))
+	connectAgent(t, a, "eu", conn)
+	defer conn.disconnect()
+
+	var r testPodReporter
+	ran := make(chan error, 1)
+	go func() { ran <- a.agents.RunPod(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-1"}}, &r) }()
+	cmd := conn.next(t)
+
+	// the agent reports what its runtime tells it, and werft relays that to the job
+	stream := &fakeAgentStream{}
+	s := &agentSession{stream: stream, stopped: map[string]bool{"job-2": true}}
+	ar := &agentReporter{s: s, id: cmd.Id, pod: "job-1"}
+	ar.ContainerStarted("build", "c1", false)
+	ar.InitDone()
+	ar.ContainerStopped("build", corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"})
+	if ar.Stopped() {
+		t.Error("job-1 was not stopped")
+	}
+	for _, msg := range stream.sent {
+		if msg.Id != cmd.Id {
+			t.Errorf("expected the agent to answer command %d, got %d", cmd.Id, msg.Id)
+		}
+		conn.in <- msg
+	}
+	conn.in <- agentDone(cmd.Id, "")
+	if err := <-ran; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"started build init=false", "init done", "stopped build 137 OOMKilled"}
+	if !reflect.DeepEqual(r.events, expected) {
+		t.Errorf("unexpected events:\n got %q\nwant %q", r.events, expected)
+	}
+}
@@ -0,0 +1,42 @@
+package executor
+
+import (
+	"golang.org/x/xerrors"
+)
+
+// approvalDetails tells users why a job which waits for approval does not start
+const approvalDetails = "waits for approval"
+
+// ErrNotWaitingForApproval is returned when approving a job which does not wait for approval
+var ErrNotWaitingForApproval = xerrors.New("job does not wait for approval")
+
+// WithApproval holds the job until it's approved using Approve. Once approved the job starts like any other,
+// i.e. it still waits for its start time, the end of the maintenance mode or capacity.
+func WithApproval() StartOpt {
+	return func(opts *startOptions) {
+		opts.Approval = true
+	}
+}
+
+// Approve lets a job which waits for approval start
+func (js *Executor) Approve(name string) error {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	wj, ok := js.waitingJobs[name]
+	if !ok || wj.Approve == nil {
+		return xerrors.Errorf("%s: %w", name, ErrNotWaitingForApproval)
+	}
+	wj.Approve()
+	wj.Approve = nil
+	return nil
+}
+
+// WaitsForApproval returns true if the job waits for approval
+func (js *Executor) WaitsForApproval(name string) bool {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+
+	wj, ok := js.waitingJobs[name]
+	return ok && wj.Approve != nil
+}
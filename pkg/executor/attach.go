@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"io"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// ErrNotRunning is returned by Attach if the job's pod isn't running
+var ErrNotRunning = xerrors.Errorf("job is not running")
+
+// AttachOptions configure an interactive command run in the pod of a job
+type AttachOptions struct {
+	// Container to run the command in. Defaults to the first running container of the job.
+	Container string
+	// Command to run. Defaults to sh.
+	Command []string
+
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// TTY allocates a terminal for the command, in which case Stderr is not used
+	TTY bool
+	// Resize receives terminal size changes if TTY is true
+	Resize remotecommand.TerminalSizeQueue
+}
+
+// Attach runs a command in the pod of a running job and waits for it to finish.
+// If the command exits with a non-zero exit code, the error is a k8s.io/client-go/util/exec.ExitError.
+func (js *Executor) Attach(name string, opts AttachOptions) error {
+	pod, err := js.getJobPod(name)
+	if xerrors.Is(err, errNotFound) {
+		return xerrors.Errorf("%w: %s", ErrNotRunning, name)
+	}
+	if err != nil {
+		return err
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return xerrors.Errorf("%w: %s", ErrNotRunning, name)
+	}
+
+	if opts.Container == "" {
+		// prefer a container that's still running, e.g. the debug container of a failed job
+		opts.Container = pod.Spec.Containers[0].Name
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Running != nil {
+				opts.Container = cs.Name
+				break
+			}
+		}
+	}
+	var found bool
+	for _, c := range pod.Spec.Containers {
+		if c.Name == opts.Container {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return xerrors.Errorf("job %s has no container %s", name, opts.Container)
+	}
+	if len(opts.Command) == 0 {
+		opts.Command = []string{"sh"}
+	}
+
+	req := js.Client.CoreV1().RESTClient().
+		Post().
+		Namespace(js.Config.Namespace).
+		Resource("pods").
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    opts.Stderr != nil && !opts.TTY,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	remoteExec, err := remotecommand.NewSPDYExecutor(js.KubeConfig, "POST", req.URL())
+	if err != nil {
+		return xerrors.Errorf("cannot attach to %s: %w", name, err)
+	}
+
+	streamOpts := remotecommand.StreamOptions{
+		Stdin:             opts.Stdin,
+		Stdout:            opts.Stdout,
+		Tty:               opts.TTY,
+		TerminalSizeQueue: opts.Resize,
+	}
+	if !opts.TTY {
+		streamOpts.Stderr = opts.Stderr
+	}
+	return remoteExec.Stream(streamOpts)
+}
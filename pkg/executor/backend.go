@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"io"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// BackendKubernetes runs every job in a pod in Kubernetes
+	BackendKubernetes = "kubernetes"
+
+	// BackendDocker runs the containers of every job's pod on the werft host using docker
+	BackendDocker = "docker"
+
+	// BackendNomad runs every job as batch job in a HashiCorp Nomad cluster
+	BackendNomad = "nomad"
+
+	// BackendAgent runs every job using one of the agents which connect to werft, e.g. from air-gapped clusters
+	BackendAgent = "agent"
+
+	// BackendFake pretends to run every job, e.g. to put werft under load
+	BackendFake = "fake"
+)
+
+// Backend starts and watches jobs. No matter where jobs run, backends describe them as pods, so that werft
+// treats all jobs alike.
+type Backend interface {
+	// Run starts the backend and returns immediately
+	Run()
+
+	// Close ends the background work of the backend, e.g. watching jobs, and waits for it. Jobs keep running.
+	Close()
+
+	// SetUpdateHandler sets the function which is called when the status of a job changes.
+	// Beware: the function can be called several times with the same status.
+	SetUpdateHandler(f func(pod *corev1.Pod, status *v1.JobStatus))
+
+	// SetClock sets the clock the backend tells the time of jobs with, e.g. when they were created or finished
+	SetClock(c Clock)
+
+	// SetIDs sets the ID generator the backend names jobs with which are started without a name
+	SetIDs(ids IDGenerator)
+
+	// AddPodHook adds a hook which is called for all job pods created or deleted from now on
+	AddPodHook(h PodHook)
+
+	// Start starts a new job
+	Start(podspec corev1.PodSpec, metadata v1.JobMetadata, options ...StartOpt) (*v1.JobStatus, error)
+
+	// Stop stops a job
+	Stop(name, reason string) error
+
+	// Logs provides the log output of a running job
+	Logs(name string) io.Reader
+
+	// ContainerLogs returns the complete output of a container of a pod, e.g. of a job which finished.
+	// Logs larger than limit bytes are truncated.
+	ContainerLogs(pod, container string, limit int64) ([]byte, error)
+
+	// GetKnownJobs returns a list of all jobs the backend knows about
+	GetKnownJobs() ([]v1.JobStatus, error)
+
+	// RegisterResult registers a result produced by a job
+	RegisterResult(jobname string, res *v1.JobResult) error
+
+	// JobPod returns the pod a job runs in, or nil if the job has no pod (anymore)
+	JobPod(name string) (*corev1.Pod, error)
+
+	// Diagnostics explains why the pod of a job failed to start or run
+	Diagnostics(pod *corev1.Pod) ([]string, error)
+
+	// ResourceUsage returns the resources a job used so far, or nil if that's unknown
+	ResourceUsage(name string) *v1.ResourceUsage
+
+	// Attach runs a command in the pod of a running job and waits for it to finish
+	Attach(name string, opts AttachOptions) error
+
+	// Approve lets a job which waits for approval start
+	Approve(name string) error
+
+	// WaitsForApproval returns true if the job waits for approval
+	WaitsForApproval(name string) bool
+
+	// Queue lists all jobs which wait to run, in the order we expect them to start
+	Queue() ([]*v1.QueuedJob, error)
+
+	// Pause puts the backend into maintenance mode: jobs started from now on wait until it resumes
+	Pause(reason string) error
+
+	// Resume ends the maintenance mode and starts all jobs which waited for it to end
+	Resume() error
+
+	// Maintenance returns whether the backend is paused
+	Maintenance() Maintenance
+}
+
+var (
+	_ Backend = &Executor{}
+	_ Backend = &DockerExecutor{}
+	_ Backend = &NomadExecutor{}
+	_ Backend = &AgentExecutor{}
+	_ Backend = &FakeExecutor{}
+
+	_ v1.AgentServiceServer = &AgentExecutor{}
+)
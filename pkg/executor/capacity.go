@@ -0,0 +1,132 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultCapacityCheckInterval is the time after which jobs waiting for capacity check again if the config doesn't say otherwise
+const defaultCapacityCheckInterval = 15 * time.Second
+
+// CapacityConfig makes jobs wait in the queue until there is capacity for their pods, instead of creating pods
+// which sit pending or are rejected by a resource quota
+type CapacityConfig struct {
+	// ResourceQuotas makes jobs wait until all resource quotas of the namespace have room for their pod
+	ResourceQuotas bool `yaml:"resourceQuotas,omitempty"`
+
+	// MaxUnschedulablePods makes jobs wait while at least this many job pods cannot be scheduled, e.g. because the
+	// nodes of the cluster lack resources. 0 disables this check.
+	MaxUnschedulablePods int `yaml:"maxUnschedulablePods,omitempty"`
+
+	// CheckInterval is the time after which jobs waiting for capacity check again. Defaults to 15 seconds.
+	CheckInterval *Duration `yaml:"checkInterval,omitempty"`
+}
+
+func (js *Executor) capacityCheckInterval() time.Duration {
+	if cfg := js.Config.Capacity; cfg != nil && cfg.CheckInterval != nil && cfg.CheckInterval.Duration > 0 {
+		return cfg.CheckInterval.Duration
+	}
+	return defaultCapacityCheckInterval
+}
+
+// lacksCapacity returns why there is no capacity for a job's pod, or an empty string if the pod can be created.
+// If we cannot tell (e.g. because listing the resource quotas fails) we assume there is capacity, so that jobs
+// don't get stuck in the queue.
+func (js *Executor) lacksCapacity(pod *corev1.Pod) string {
+	cfg := js.Config.Capacity
+	if cfg == nil {
+		return ""
+	}
+
+	if cfg.MaxUnschedulablePods > 0 {
+		pods, err := js.listPods(fmt.Sprintf("%s=true", LabelWerftMarker))
+		if err != nil {
+			log.WithError(err).WithField("name", pod.Name).Warn("cannot check for unschedulable job pods")
+		}
+		var unschedulable int
+		for _, p := range pods {
+			if p.Status.Phase != corev1.PodPending {
+				continue
+			}
+			for _, c := range p.Status.Conditions {
+				if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason == corev1.PodReasonUnschedulable {
+					unschedulable++
+					break
+				}
+			}
+		}
+		if unschedulable >= cfg.MaxUnschedulablePods {
+			return fmt.Sprintf("waiting for capacity: %d job pods cannot be scheduled", unschedulable)
+		}
+	}
+
+	if cfg.ResourceQuotas {
+		quotas, err := js.Client.CoreV1().ResourceQuotas(js.Config.Namespace).List(metav1.ListOptions{})
+		if err != nil {
+			log.WithError(err).WithField("name", pod.Name).Warn("cannot check resource quotas")
+			return ""
+		}
+		demand := podDemand(&pod.Spec)
+		for _, q := range quotas.Items {
+			for name, hard := range q.Status.Hard {
+				need, ok := demand[name]
+				if !ok {
+					continue
+				}
+				used := q.Status.Used[name].DeepCopy()
+				used.Add(need)
+				if used.Cmp(hard) > 0 {
+					used = q.Status.Used[name]
+					return fmt.Sprintf("waiting for capacity: resource quota %s has %s of %s %s in use, the job needs %s", q.Name, used.String(), hard.String(), name, need.String())
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// podDemand computes what a pod counts towards resource quotas. Like Kubernetes we take the larger of the sum
+// of all containers and the largest init container. Quota scopes are not considered.
+func podDemand(spec *corev1.PodSpec) corev1.ResourceList {
+	sum := func(ctrs []corev1.Container, max bool) corev1.ResourceList {
+		res := make(corev1.ResourceList)
+		add := func(name corev1.ResourceName, q resource.Quantity) {
+			cur, ok := res[name]
+			if !ok {
+				res[name] = q.DeepCopy()
+				return
+			}
+			if !max {
+				cur.Add(q)
+			} else if q.Cmp(cur) > 0 {
+				cur = q.DeepCopy()
+			}
+			res[name] = cur
+		}
+		for _, c := range ctrs {
+			for name, q := range c.Resources.Requests {
+				add(name, q)
+				add(corev1.ResourceName("requests."+string(name)), q)
+			}
+			for name, q := range c.Resources.Limits {
+				add(corev1.ResourceName("limits."+string(name)), q)
+			}
+		}
+		return res
+	}
+
+	res := sum(spec.Containers, false)
+	for name, q := range sum(spec.InitContainers, true) {
+		if cur, ok := res[name]; !ok || q.Cmp(cur) > 0 {
+			res[name] = q
+		}
+	}
+	res[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+	return res
+}
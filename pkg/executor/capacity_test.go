@@ -0,0 +1,152 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodDemand(t *testing.T) {
+	ctr := func(cpu, mem string) corev1.Container {
+		return corev1.Container{Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+			Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(mem)},
+		}}
+	}
+	tests := []struct {
+		Name     string
+		Spec     corev1.PodSpec
+		Expected map[corev1.ResourceName]string
+	}{
+		{
+			Name:     "no requests",
+			Expected: map[corev1.ResourceName]string{corev1.ResourcePods: "1"},
+		},
+		{
+			Name: "containers add up",
+			Spec: corev1.PodSpec{Containers: []corev1.Container{ctr("500m", "1Gi"), ctr("250m", "512Mi")}},
+			Expected: map[corev1.ResourceName]string{
+				corev1.ResourcePods:         "1",
+				corev1.ResourceCPU:          "750m",
+				corev1.ResourceRequestsCPU:  "750m",
+				corev1.ResourceLimitsMemory: "1536Mi",
+			},
+		},
+		{
+			Name: "largest init container counts if it exceeds the containers",
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{ctr("2", "256Mi"), ctr("1", "128Mi")},
+				Containers:     []corev1.Container{ctr("500m", "1Gi"), ctr("250m", "512Mi")},
+			},
+			Expected: map[corev1.ResourceName]string{
+				corev1.ResourcePods:         "1",
+				corev1.ResourceCPU:          "2",
+				corev1.ResourceRequestsCPU:  "2",
+				corev1.ResourceLimitsMemory: "1536Mi",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := podDemand(&test.Spec)
+			if len(act) != len(test.Expected) {
+				t.Errorf("expected %v, got %v", test.Expected, act)
+			}
+			for name, exp := range test.Expected {
+				q, ok := act[name]
+				if !ok || q.Cmp(resource.MustParse(exp)) != 0 {
+					t.Errorf("expected %s to be %s, got %s", name, exp, q.String())
+				}
+			}
+		})
+	}
+}
+
+func TestLacksCapacity(t *testing.T) {
+	const ns = "werft"
+	quota := func(hard, used string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: "jobs", Namespace: ns},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse(hard)},
+				Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse(used)},
+			},
+		}
+	}
+	jobPod := func(name string, unschedulable bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: map[string]string{LabelWerftMarker: "true"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		}
+		if unschedulable {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable}}
+		}
+		return pod
+	}
+	newPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "new"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+		}}},
+	}
+
+	tests := []struct {
+		Name     string
+		Config   *CapacityConfig
+		Objects  []runtime.Object
+		Expected string
+	}{
+		{Name: "disabled", Objects: []runtime.Object{quota("1", "1")}},
+		{Name: "quota has room", Config: &CapacityConfig{ResourceQuotas: true}, Objects: []runtime.Object{quota("2", "1")}},
+		{Name: "quota is full", Config: &CapacityConfig{ResourceQuotas: true}, Objects: []runtime.Object{quota("2", "1500m")}, Expected: "resource quota jobs has 1500m of 2 requests.cpu in use, the job needs 1"},
+		{Name: "quotas aren't checked", Config: &CapacityConfig{MaxUnschedulablePods: 2}, Objects: []runtime.Object{quota("1", "1")}},
+		{
+			Name:    "few unschedulable pods",
+			Config:  &CapacityConfig{MaxUnschedulablePods: 2},
+			Objects: []runtime.Object{jobPod("a", true), jobPod("b", false)},
+		},
+		{
+			Name:     "too many unschedulable pods",
+			Config:   &CapacityConfig{MaxUnschedulablePods: 2},
+			Objects:  []runtime.Object{jobPod("a", true), jobPod("b", true), jobPod("c", false)},
+			Expected: "2 job pods cannot be scheduled",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.Objects...)
+			js := &Executor{
+				Config: Config{Namespace: ns, Capacity: test.Config},
+				Client: client,
+				pods:   newPodInformer(client, ns, 0),
+			}
+			act := js.lacksCapacity(newPod)
+			if test.Expected == "" {
+				if act != "" {
+					t.Errorf("expected capacity, got %q", act)
+				}
+				return
+			}
+			if !strings.Contains(act, test.Expected) {
+				t.Errorf("expected %q, got %q", test.Expected, act)
+			}
+		})
+	}
+}
+
+func TestCapacityCheckInterval(t *testing.T) {
+	js := &Executor{}
+	if act := js.capacityCheckInterval(); act != defaultCapacityCheckInterval {
+		t.Errorf("expected default interval, got %v", act)
+	}
+	js.Config.Capacity = &CapacityConfig{CheckInterval: &Duration{Duration: time.Minute}}
+	if act := js.capacityCheckInterval(); act != time.Minute {
+		t.Errorf("expected configured interval, got %v", act)
+	}
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/technosophos/moniker"
+)
+
+// Clock tells the time. Tests replace the clock of an executor to control time.
+type Clock interface {
+	Now() time.Time
+}
+
+// jobClock is the clock an executor tells the time of jobs with, e.g. when they were created or finished
+type jobClock struct {
+	clock   Clock
+	clockMu sync.RWMutex
+}
+
+// SetClock sets the clock the executor tells the time of jobs with. Without a clock it uses the system clock.
+func (c *jobClock) SetClock(clock Clock) {
+	c.clockMu.Lock()
+	defer c.clockMu.Unlock()
+
+	c.clock = clock
+}
+
+// now returns the current time according to the executor's clock
+func (c *jobClock) now() time.Time {
+	c.clockMu.RLock()
+	defer c.clockMu.RUnlock()
+
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// IDGenerator produces unique identifiers, e.g. for the names of jobs started without a name. IDs must be valid in
+// Kubernetes label values, i.e. consist of alphanumeric characters and dashes.
+type IDGenerator interface {
+	NewID() string
+}
+
+// monikerIDs produces random, readable IDs like "gilded-turtle"
+type monikerIDs struct{}
+
+// NewID returns a random ID
+func (monikerIDs) NewID() string {
+	return moniker.New().NameSep("-")
+}
+
+// jobIDs is the ID generator an executor names jobs with
+type jobIDs struct {
+	ids   IDGenerator
+	idsMu sync.RWMutex
+}
+
+// SetIDs sets the ID generator the executor names jobs with. Without one it uses random, readable IDs.
+func (i *jobIDs) SetIDs(ids IDGenerator) {
+	i.idsMu.Lock()
+	defer i.idsMu.Unlock()
+
+	i.ids = ids
+}
+
+// newID returns a new ID according to the executor's ID generator
+func (i *jobIDs) newID() string {
+	i.idsMu.RLock()
+	defer i.idsMu.RUnlock()
+
+	if i.ids == nil {
+		return monikerIDs{}.NewID()
+	}
+	return i.ids.NewID()
+}
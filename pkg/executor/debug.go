@@ -0,0 +1,76 @@
+package executor
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AnnotationDebugKeepAlive is the time the pod of a failed job is kept around for debugging
+	AnnotationDebugKeepAlive = "werft.sh/debugKeepAlive"
+
+	// AnnotationDebugUntil is the time until which the pod of a failed job is kept around for debugging
+	AnnotationDebugUntil = "werft.sh/debugUntil"
+
+	// DebugContainerName is the name of the container which keeps the pod of a failed job alive.
+	// Its state does not count towards the state of the job.
+	DebugContainerName = "werft-debug"
+)
+
+// WithDebugKeepAlive keeps the pod of a job around for some time if the job fails, so that it can be debugged
+// using the attach feature or kubectl. To this end the pod gets a debug container which runs the image of the job's
+// first container with the same mounts and environment, and lives on after the job has failed.
+func WithDebugKeepAlive(keepAlive time.Duration) StartOpt {
+	return func(opts *startOptions) {
+		opts.Modifier = append(opts.Modifier, func(pod *corev1.Pod) {
+			if len(pod.Spec.Containers) == 0 {
+				return
+			}
+
+			pod.Annotations[AnnotationDebugKeepAlive] = keepAlive.String()
+
+			main := pod.Spec.Containers[0]
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+				Name:            DebugContainerName,
+				Image:           main.Image,
+				ImagePullPolicy: main.ImagePullPolicy,
+				Command:         []string{"sh", "-c", "trap 'exit 0' TERM; while true; do sleep 1; done"},
+				WorkingDir:      main.WorkingDir,
+				Env:             main.Env,
+				EnvFrom:         main.EnvFrom,
+				VolumeMounts:    main.VolumeMounts,
+			})
+		})
+	}
+}
+
+// keepForDebugging returns true if the pod of a failed job should not be deleted yet, because it's kept around for
+// debugging. The first time this is called for a pod, it starts the keep-alive period.
+func (js *Executor) keepForDebugging(obj *corev1.Pod) bool {
+	if obj.DeletionTimestamp != nil {
+		return false
+	}
+	if until, ok := obj.Annotations[AnnotationDebugUntil]; ok {
+		t, err := time.Parse(time.RFC3339, until)
+		return err == nil && js.now().Before(t)
+	}
+
+	keepAlive, err := time.ParseDuration(obj.Annotations[AnnotationDebugKeepAlive])
+	if err != nil || keepAlive <= 0 {
+		return false
+	}
+	err = js.addAnnotation(obj.Name, map[string]string{
+		AnnotationDebugUntil: js.now().Add(keepAlive).Format(time.RFC3339),
+	})
+	if err != nil {
+		log.WithError(err).WithField("name", obj.Name).Warn("cannot keep pod of failed job for debugging")
+		return false
+	}
+	log.WithField("name", obj.Name).WithField("keepAlive", keepAlive).Info("keeping pod of failed job for debugging")
+
+	// housekeeping would remove the pod eventually, but that may take a while
+	time.AfterFunc(keepAlive, func() { js.deleteJobPod(obj) })
+	return true
+}
@@ -0,0 +1,85 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// Diagnostics explains why the pod of a job failed to schedule, start or run. It reports the pod's failed
+// conditions, the state of its containers and all events Kubernetes recorded for the pod.
+func (js *Executor) Diagnostics(pod *corev1.Pod) ([]string, error) {
+	res := podDiagnostics(pod)
+
+	evts, err := js.Client.CoreV1().Events(js.Config.Namespace).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.name", pod.Name).String(),
+	})
+	if err != nil {
+		return res, xerrors.Errorf("cannot list events of %s: %w", pod.Name, err)
+	}
+
+	events := evts.Items
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(&events[j].LastTimestamp)
+	})
+	for _, evt := range events {
+		if evt.InvolvedObject.UID != "" && evt.InvolvedObject.UID != pod.UID {
+			// this event belongs to an earlier pod of the same name
+			continue
+		}
+
+		line := fmt.Sprintf("event %s %s: %s", evt.Type, evt.Reason, evt.Message)
+		if evt.Count > 1 {
+			line += fmt.Sprintf(" (x%d)", evt.Count)
+		}
+		res = append(res, line)
+	}
+
+	return res, nil
+}
+
+// podDiagnostics describes the state of a pod and its containers
+func podDiagnostics(pod *corev1.Pod) []string {
+	var res []string
+	if pod.Status.Reason != "" {
+		res = append(res, fmt.Sprintf("pod %s: %s", pod.Status.Reason, pod.Status.Message))
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Status == corev1.ConditionTrue || c.Reason == "" {
+			continue
+		}
+		res = append(res, fmt.Sprintf("pod condition %s is %s (%s): %s", c.Type, c.Status, c.Reason, c.Message))
+	}
+
+	for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if w := cs.State.Waiting; w != nil {
+			res = append(res, fmt.Sprintf("container %s is waiting (%s): %s", cs.Name, w.Reason, w.Message))
+		}
+		if t := cs.State.Terminated; t != nil {
+			res = append(res, describeTermination(cs.Name, "terminated", t))
+		}
+		if t := cs.LastTerminationState.Terminated; t != nil {
+			res = append(res, describeTermination(cs.Name, "previously terminated", t))
+		}
+		if cs.RestartCount > 0 {
+			res = append(res, fmt.Sprintf("container %s restarted %d times", cs.Name, cs.RestartCount))
+		}
+	}
+
+	return res
+}
+
+func describeTermination(container, state string, t *corev1.ContainerStateTerminated) string {
+	res := fmt.Sprintf("container %s %s with exit code %d", container, state, t.ExitCode)
+	if t.Reason != "" {
+		res += fmt.Sprintf(" (%s)", t.Reason)
+	}
+	if t.Message != "" {
+		res += ": " + t.Message
+	}
+	return res
+}
@@ -0,0 +1,458 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+// defaultDockerCommand is the container CLI the docker executor uses if the config doesn't say otherwise
+const defaultDockerCommand = "docker"
+
+// DockerConfig configures the docker executor
+type DockerConfig struct {
+	// Command is the container CLI. Defaults to docker. Any CLI compatible with docker works, e.g. nerdctl to run
+	// jobs using containerd.
+	Command string `yaml:"command,omitempty"`
+
+	// WorkDir holds the emptyDir volumes of jobs on the host. Defaults to werft-jobs in the temp directory.
+	WorkDir string `yaml:"workDir,omitempty"`
+
+	// Network is the network the containers of jobs join. Defaults to the default network of the container CLI.
+	Network string `yaml:"network,omitempty"`
+
+	// AllowedHostPaths are the host paths jobs may mount using hostPath volumes, including everything below them.
+	// By default jobs may mount none, except for the volumes werft adds itself, e.g. the workspace.
+	AllowedHostPaths []string `yaml:"allowedHostPaths,omitempty"`
+
+	// AllowPrivileged lets jobs run privileged containers, which have full access to the host
+	AllowPrivileged bool `yaml:"allowPrivileged,omitempty"`
+}
+
+func (c *DockerConfig) command() string {
+	if c == nil || c.Command == "" {
+		return defaultDockerCommand
+	}
+	return c.Command
+}
+
+func (c *DockerConfig) workDir() string {
+	if c == nil || c.WorkDir == "" {
+		return filepath.Join(os.TempDir(), "werft-jobs")
+	}
+	return c.WorkDir
+}
+
+func (c *DockerConfig) network() string {
+	if c == nil {
+		return ""
+	}
+	return c.Network
+}
+
+// checkHostAccess makes sure a pod only mounts allowed host paths and has privileged containers only if allowed
+func (c *DockerConfig) checkHostAccess(pod *corev1.Pod) error {
+	if c == nil {
+		return checkHostAccess(pod, nil, false, "docker")
+	}
+	return checkHostAccess(pod, c.AllowedHostPaths, c.AllowPrivileged, "docker")
+}
+
+// NewDockerExecutor creates a new executor which runs jobs on this host
+func NewDockerExecutor(config Config) (*DockerExecutor, error) {
+	err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+	_, err = exec.LookPath(config.Docker.command())
+	if err != nil {
+		return nil, xerrors.Errorf("cannot find container CLI: %w", err)
+	}
+
+	return &DockerExecutor{newRuntimeExecutor(config, &dockerRuntime{Config: config.Docker})}, nil
+}
+
+// DockerExecutor runs the containers of job pods on the werft host using the docker CLI. Init containers run one
+// after the other, followed by all other containers at once. Unlike pods in Kubernetes, containers which fail are
+// not restarted and only emptyDir and hostPath volumes are supported. Jobs may mount only the host paths the config
+// allows, and run privileged containers only if the config allows them.
+//
+// Jobs live in memory only: werft loses the jobs which run when it stops and removes their containers once it's back.
+type DockerExecutor struct {
+	*runtimeExecutor
+}
+
+// dockerRuntime runs containers using the docker CLI
+type dockerRuntime struct {
+	Config *DockerConfig
+}
+
+// Name returns docker
+func (d *dockerRuntime) Name() string {
+	return "docker"
+}
+
+// RemoveAll removes the containers of a previous werft instance
+func (d *dockerRuntime) RemoveAll() error {
+	out, err := d.docker("ps", "--all", "--quiet", "--filter", fmt.Sprintf("label=%s=true", LabelWerftMarker))
+	if err != nil {
+		return xerrors.Errorf("cannot list containers of previous jobs: %w", err)
+	}
+	for _, id := range strings.Fields(out) {
+		_, err := d.docker("rm", "--force", id)
+		if err != nil {
+			log.WithError(err).WithField("container", id).Warn("cannot remove container of previous job")
+		}
+	}
+	return nil
+}
+
+// docker runs the container CLI and returns its output
+func (d *dockerRuntime) docker(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(d.Config.command(), args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", xerrors.Errorf("%s %s: %s", d.Config.command(), args[0], msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// RunPod runs the init containers of a pod one after the other, followed by all other containers at once
+func (d *dockerRuntime) RunPod(pod *corev1.Pod, r podReporter) error {
+	// agents run the pods werft sends them without werft checking them first
+	err := d.CheckPod(pod)
+	if err != nil {
+		return err
+	}
+	vols, err := d.createVolumes(pod)
+	if err != nil {
+		return err
+	}
+	for _, c := range pod.Spec.InitContainers {
+		code, err := d.runContainer(pod, c, vols, true, r)
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return nil
+		}
+	}
+	r.InitDone()
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []string
+	)
+	for _, c := range pod.Spec.Containers {
+		wg.Add(1)
+		go func(c corev1.Container) {
+			defer wg.Done()
+
+			_, err := d.runContainer(pod, c, vols, false, r)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err.Error())
+				mu.Unlock()
+			}
+		}(c)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return xerrors.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// runContainer runs a container of a pod and waits for it to finish. It returns the container's exit code.
+func (d *dockerRuntime) runContainer(pod *corev1.Pod, c corev1.Container, vols map[string]string, init bool, r podReporter) (code int, err error) {
+	if r.Stopped() {
+		return 0, nil
+	}
+
+	args, err := dockerRunArgs(pod, &c, vols, d.Config.network())
+	if err != nil {
+		return 0, err
+	}
+	id, err := d.docker(args...)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot start container %s: %w", c.Name, err)
+	}
+	r.ContainerStarted(c.Name, id, init)
+
+	out, err := d.docker("wait", id)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot wait for container %s: %w", c.Name, err)
+	}
+	code, err = strconv.Atoi(out)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot parse exit code of container %s: %w", c.Name, err)
+	}
+
+	reason := "Completed"
+	if code != 0 {
+		reason = "Error"
+	}
+	if oom, _ := d.docker("inspect", "--format", "{{.State.OOMKilled}}", id); oom == "true" {
+		reason = "OOMKilled"
+	}
+	r.ContainerStopped(c.Name, corev1.ContainerStateTerminated{ExitCode: int32(code), Reason: reason})
+
+	return code, nil
+}
+
+// StopPod stops the running containers of a pod
+func (d *dockerRuntime) StopPod(pod *corev1.Pod, containers map[string]string, gracePeriod time.Duration) {
+	for _, id := range containers {
+		_, err := d.docker("stop", "--time", strconv.FormatInt(int64(gracePeriod.Seconds()), 10), id)
+		if err != nil {
+			log.WithError(err).WithField("name", pod.Name).WithField("container", id).Warn("cannot stop container of job")
+		}
+	}
+}
+
+// RemovePod removes the containers and emptyDir volumes of a pod
+func (d *dockerRuntime) RemovePod(pod *corev1.Pod, containers map[string]string) error {
+	for _, id := range containers {
+		_, err := d.docker("rm", "--force", id)
+		if err != nil {
+			log.WithError(err).WithField("name", pod.Name).WithField("container", id).Warn("cannot remove container of job")
+		}
+	}
+	err := os.RemoveAll(filepath.Join(d.Config.workDir(), pod.Name))
+	if err != nil {
+		return xerrors.Errorf("cannot remove volumes: %w", err)
+	}
+	return nil
+}
+
+// createVolumes makes the volumes of a pod available on the host. It returns the host path of every volume.
+func (d *dockerRuntime) createVolumes(pod *corev1.Pod) (map[string]string, error) {
+	res := make(map[string]string, len(pod.Spec.Volumes))
+	for _, v := range pod.Spec.Volumes {
+		var (
+			path   string
+			create bool
+		)
+		switch {
+		case v.EmptyDir != nil:
+			path, create = filepath.Join(d.Config.workDir(), pod.Name, v.Name), true
+		case v.HostPath != nil:
+			path = v.HostPath.Path
+			create = v.HostPath.Type != nil && *v.HostPath.Type == corev1.HostPathDirectoryOrCreate
+		default:
+			return nil, xerrors.Errorf("volume %s: only emptyDir and hostPath volumes are supported", v.Name)
+		}
+		if create {
+			err := os.MkdirAll(path, 0755)
+			if err != nil {
+				return nil, xerrors.Errorf("cannot create volume %s: %w", v.Name, err)
+			}
+		}
+		res[v.Name] = path
+	}
+	return res, nil
+}
+
+// CheckPod makes sure the docker executor can and may run a pod
+func (d *dockerRuntime) CheckPod(pod *corev1.Pod) error {
+	err := d.Config.checkHostAccess(pod)
+	if err != nil {
+		return err
+	}
+
+	spec := &pod.Spec
+	vols := make(map[string]struct{}, len(spec.Volumes))
+	for _, v := range spec.Volumes {
+		if v.EmptyDir == nil && v.HostPath == nil {
+			return xerrors.Errorf("volume %s: only emptyDir and hostPath volumes are supported by the docker executor", v.Name)
+		}
+		vols[v.Name] = struct{}{}
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		if len(c.EnvFrom) > 0 {
+			return xerrors.Errorf("container %s: envFrom is not supported by the docker executor", c.Name)
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom != nil {
+				return xerrors.Errorf("container %s: env %s: valueFrom is not supported by the docker executor", c.Name, e.Name)
+			}
+		}
+		for _, m := range c.VolumeMounts {
+			if _, ok := vols[m.Name]; !ok {
+				return xerrors.Errorf("container %s: unknown volume %s", c.Name, m.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// dockerContainerName is the name of the docker container of a job's container
+func dockerContainerName(job, container string) string {
+	return fmt.Sprintf("%s-%s", job, container)
+}
+
+// dockerRunArgs produces the arguments of docker run which start a container of a pod in the background
+func dockerRunArgs(pod *corev1.Pod, c *corev1.Container, vols map[string]string, network string) ([]string, error) {
+	args := []string{
+		"run", "--detach",
+		"--name", dockerContainerName(pod.Name, c.Name),
+		"--label", fmt.Sprintf("%s=true", LabelWerftMarker),
+		"--label", fmt.Sprintf("%s=%s", LabelJobName, pod.Name),
+	}
+	if network != "" {
+		args = append(args, "--network", network)
+	}
+
+	env := make(map[string]string, len(c.Env))
+	for _, e := range c.Env {
+		if e.ValueFrom != nil {
+			return nil, xerrors.Errorf("container %s: env %s: valueFrom is not supported", c.Name, e.Name)
+		}
+		val := expandVars(e.Value, env)
+		env[e.Name] = val
+		args = append(args, "--env", fmt.Sprintf("%s=%s", e.Name, val))
+	}
+	if c.WorkingDir != "" {
+		args = append(args, "--workdir", c.WorkingDir)
+	}
+	for _, m := range c.VolumeMounts {
+		path, ok := vols[m.Name]
+		if !ok {
+			return nil, xerrors.Errorf("container %s: unknown volume %s", c.Name, m.Name)
+		}
+		if m.SubPath != "" {
+			path = filepath.Join(path, m.SubPath)
+		}
+		spec := fmt.Sprintf("%s:%s", path, m.MountPath)
+		if m.ReadOnly {
+			spec += ":ro"
+		}
+		args = append(args, "--volume", spec)
+	}
+	if cpu := c.Resources.Limits.Cpu(); !cpu.IsZero() {
+		args = append(args, "--cpus", strconv.FormatFloat(float64(cpu.MilliValue())/1000, 'f', 3, 64))
+	}
+	if mem := c.Resources.Limits.Memory(); !mem.IsZero() {
+		args = append(args, "--memory", strconv.FormatInt(mem.Value(), 10))
+	}
+	if sc := c.SecurityContext; sc != nil {
+		if sc.Privileged != nil && *sc.Privileged {
+			args = append(args, "--privileged")
+		}
+		if sc.RunAsUser != nil {
+			args = append(args, "--user", strconv.FormatInt(*sc.RunAsUser, 10))
+		}
+	}
+
+	command := make([]string, len(c.Command))
+	for i, a := range c.Command {
+		command[i] = expandVars(a, env)
+	}
+	if len(command) > 0 {
+		args = append(args, "--entrypoint", command[0])
+	}
+	args = append(args, c.Image)
+	if len(command) > 1 {
+		args = append(args, command[1:]...)
+	}
+	for _, a := range c.Args {
+		args = append(args, expandVars(a, env))
+	}
+	return args, nil
+}
+
+// varRef matches the $(VAR) references Kubernetes expands in the command, args and env of containers
+var varRef = regexp.MustCompile(`\$\$|\$\(([A-Za-z_][A-Za-z0-9_]*)\)`)
+
+// expandVars expands $(VAR) references like Kubernetes does: references to unknown variables are left as they are
+// and $$ escapes a $.
+func expandVars(s string, env map[string]string) string {
+	return varRef.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$$" {
+			return "$"
+		}
+		if val, ok := env[ref[2:len(ref)-1]]; ok {
+			return val
+		}
+		return ref
+	})
+}
+
+// Logs streams the output of a container until it stops
+func (d *dockerRuntime) Logs(id string) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	cmd := exec.Command(d.Config.command(), "logs", "--follow", id)
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+	err := cmd.Start()
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		//nolint:errcheck
+		cmd.Wait()
+		pw.Close()
+	}()
+	return &dockerLogs{PipeReader: pr, cmd: cmd}, nil
+}
+
+// dockerLogs is the output of docker logs. Closing it stops docker logs.
+type dockerLogs struct {
+	*io.PipeReader
+	cmd *exec.Cmd
+}
+
+func (l *dockerLogs) Close() error {
+	//nolint:errcheck
+	l.cmd.Process.Kill()
+	return l.PipeReader.Close()
+}
+
+// ContainerLogs returns the output of a container
+func (d *dockerRuntime) ContainerLogs(id string, limit int64) ([]byte, error) {
+	cmd := exec.Command(d.Config.command(), "logs", id)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get logs of container %s: %s", id, strings.TrimSpace(string(out)))
+	}
+	if int64(len(out)) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// Exec runs a command in a container. The command has no terminal, even if opts.TTY is set.
+func (d *dockerRuntime) Exec(id string, opts AttachOptions) error {
+	cmd := exec.Command(d.Config.command(), append([]string{"exec", "--interactive", id}, opts.Command...)...)
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+	if opts.TTY {
+		cmd.Stderr = opts.Stdout
+	}
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return utilexec.CodeExitError{Err: err, Code: exitErr.ExitCode()}
+	}
+	return err
+}
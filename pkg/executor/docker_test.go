@@ -0,0 +1,187 @@
+package executor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDockerRunArgs(t *testing.T) {
+	var (
+		user       int64 = 1000
+		privileged       = true
+	)
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-1"}}
+	vols := map[string]string{"ws": "/tmp/werft-jobs/job-1/ws", "cache": "/cache"}
+
+	tests := []struct {
+		Name      string
+		Container corev1.Container
+		Network   string
+		Args      []string
+		Error     bool
+	}{
+		{
+			Name:      "minimal",
+			Container: corev1.Container{Name: "build", Image: "alpine"},
+			Args:      []string{"run", "--detach", "--name", "job-1-build", "--label", LabelWerftMarker + "=true", "--label", LabelJobName + "=job-1", "alpine"},
+		},
+		{
+			Name:      "network",
+			Container: corev1.Container{Name: "build", Image: "alpine"},
+			Network:   "ci",
+			Args:      []string{"run", "--detach", "--name", "job-1-build", "--label", LabelWerftMarker + "=true", "--label", LabelJobName + "=job-1", "--network", "ci", "alpine"},
+		},
+		{
+			Name: "env and expansion",
+			Container: corev1.Container{
+				Name:    "build",
+				Image:   "alpine",
+				Env:     []corev1.EnvVar{{Name: "A", Value: "a"}, {Name: "B", Value: "$(A)-b"}, {Name: "C", Value: "$(UNKNOWN)$$"}},
+				Command: []string{"sh", "-c"},
+				Args:    []string{"echo $(B)"},
+			},
+			Args: []string{"run", "--detach", "--name", "job-1-build", "--label", LabelWerftMarker + "=true", "--label", LabelJobName + "=job-1",
+				"--env", "A=a", "--env", "B=a-b", "--env", "C=$(UNKNOWN)$",
+				"--entrypoint", "sh", "alpine", "-c", "echo a-b",
+			},
+		},
+		{
+			Name: "volumes, resources and security context",
+			Container: corev1.Container{
+				Name:       "build",
+				Image:      "alpine",
+				WorkingDir: "/workspace",
+				VolumeMounts: []corev1.VolumeMount{
+					{Name: "ws", MountPath: "/workspace"},
+					{Name: "cache", MountPath: "/go", SubPath: "go", ReadOnly: true},
+				},
+				Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1500m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				}},
+				SecurityContext: &corev1.SecurityContext{RunAsUser: &user, Privileged: &privileged},
+			},
+			Args: []string{"run", "--detach", "--name", "job-1-build", "--label", LabelWerftMarker + "=true", "--label", LabelJobName + "=job-1",
+				"--workdir", "/workspace",
+				"--volume", "/tmp/werft-jobs/job-1/ws:/workspace",
+				"--volume", "/cache/go:/go:ro",
+				"--cpus", "1.500", "--memory", "1073741824",
+				"--privileged", "--user", "1000",
+				"alpine",
+			},
+		},
+		{
+			Name:      "unknown volume",
+			Container: corev1.Container{Name: "build", Image: "alpine", VolumeMounts: []corev1.VolumeMount{{Name: "missing", MountPath: "/m"}}},
+			Error:     true,
+		},
+		{
+			Name:      "valueFrom",
+			Container: corev1.Container{Name: "build", Image: "alpine", Env: []corev1.EnvVar{{Name: "A", ValueFrom: &corev1.EnvVarSource{}}}},
+			Error:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			args, err := dockerRunArgs(pod, &test.Container, vols, test.Network)
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(args, test.Args) {
+				t.Errorf("unexpected args:\n\tgot  %q\n\twant %q", args, test.Args)
+			}
+		})
+	}
+}
+
+func TestDockerCreateVolumes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "werft-docker-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	orCreate := corev1.HostPathDirectoryOrCreate
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-1"},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{
+			{Name: "ws", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			{Name: "cache", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Join(dir, "cache"), Type: &orCreate}}},
+			{Name: "sock", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: filepath.Join(dir, "sock")}}},
+		}},
+	}
+	rt := &dockerRuntime{Config: &DockerConfig{WorkDir: filepath.Join(dir, "work")}}
+	vols, err := rt.createVolumes(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"ws":    filepath.Join(dir, "work", "job-1", "ws"),
+		"cache": filepath.Join(dir, "cache"),
+		"sock":  filepath.Join(dir, "sock"),
+	}
+	if !reflect.DeepEqual(vols, expected) {
+		t.Errorf("unexpected volumes: got %v, want %v", vols, expected)
+	}
+	for _, p := range []string{expected["ws"], expected["cache"]} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("volume %s was not created: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(expected["sock"]); !os.IsNotExist(err) {
+		t.Errorf("hostPath volume %s without DirectoryOrCreate should not be created", expected["sock"])
+	}
+}
+
+func TestDockerCheckPod(t *testing.T) {
+	ws := corev1.VolumeMount{Name: "ws", MountPath: "/workspace"}
+	tests := []struct {
+		Name   string
+		Config *DockerConfig
+		Pod    *corev1.Pod
+		Valid  bool
+	}{
+		{"emptyDir", nil, hostPathPod("", "", ws, false), true},
+		{"docker socket", nil, hostPathPod("", "/var/run/docker.sock", ws, false), false},
+		{"docker socket allowed", &DockerConfig{AllowedHostPaths: []string{"/var/run/docker.sock"}}, hostPathPod("", "/var/run/docker.sock", ws, false), true},
+		{"workspace", nil, hostPathPod("/mnt/job", "/mnt/job", ws, false), true},
+		{"privileged", &DockerConfig{}, hostPathPod("", "", ws, true), false},
+		{"privileged allowed", &DockerConfig{AllowPrivileged: true}, hostPathPod("", "", ws, true), true},
+		{"unknown volume", nil, hostPathPod("", "", corev1.VolumeMount{Name: "missing", MountPath: "/m"}, false), false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			rt := &dockerRuntime{Config: test.Config}
+			err := rt.CheckPod(test.Pod)
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected the pod to be rejected")
+			}
+		})
+	}
+}
+
+func TestDockerRunPodRejectsHostAccess(t *testing.T) {
+	// agents call RunPod without anybody checking the pod first
+	rt := &dockerRuntime{Config: &DockerConfig{Command: "werft-test-no-such-cli"}}
+	err := rt.RunPod(hostPathPod("", "/", corev1.VolumeMount{Name: "host", MountPath: "/host"}, false), nil)
+	if err == nil {
+		t.Fatal("RunPod should reject pods which mount host paths which are not allowed")
+	}
+}
@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// WithEgress limits the network destinations a job can reach to those the rules allow, using a NetworkPolicy
+// which selects the job's pod. DNS is always allowed, so without rules the job can reach nothing else.
+// The network policy is deleted together with the pod.
+func WithEgress(rules []networkingv1.NetworkPolicyEgressRule) StartOpt {
+	return func(opts *startOptions) {
+		opts.LimitEgress = true
+		opts.Egress = append(opts.Egress, rules...)
+	}
+}
+
+// egressPolicy produces the network policy which limits the egress of a job's pod
+func egressPolicy(jobName string, rules []networkingv1.NetworkPolicyEgressRule) *networkingv1.NetworkPolicy {
+	var (
+		udp = corev1.ProtocolUDP
+		tcp = corev1.ProtocolTCP
+		dns = intstr.FromInt(53)
+	)
+	egress := append([]networkingv1.NetworkPolicyEgressRule{
+		{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &dns}, {Protocol: &tcp, Port: &dns}}},
+	}, rules...)
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: jobName,
+			Labels: map[string]string{
+				LabelWerftMarker: "true",
+				LabelJobName:     jobName,
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{LabelJobName: jobName}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			Egress:      egress,
+		},
+	}
+}
+
+// createEgressPolicy creates the network policy of a job before its pod, so that the pod never runs without it
+func (js *Executor) createEgressPolicy(jobName string, rules []networkingv1.NetworkPolicyEgressRule) error {
+	_, err := js.Client.NetworkingV1().NetworkPolicies(js.Config.Namespace).Create(egressPolicy(jobName, rules))
+	if err != nil {
+		return xerrors.Errorf("cannot create egress network policy: %w", err)
+	}
+	return nil
+}
+
+// ownEgressPolicy makes the job's pod the owner of its network policy, so that Kubernetes deletes the policy with the pod
+func (js *Executor) ownEgressPolicy(pod *corev1.Pod) error {
+	policies := js.Client.NetworkingV1().NetworkPolicies(js.Config.Namespace)
+	np, err := policies.Get(pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	np.OwnerReferences = append(np.OwnerReferences, metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "Pod",
+		Name:       pod.Name,
+		UID:        pod.UID,
+	})
+	_, err = policies.Update(np)
+	return err
+}
+
+// deleteEgressPolicy deletes the network policy of a job whose pod could not be created
+func (js *Executor) deleteEgressPolicy(jobName string) error {
+	return js.Client.NetworkingV1().NetworkPolicies(js.Config.Namespace).Delete(jobName, &metav1.DeleteOptions{})
+}
@@ -5,19 +5,22 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	werftv1 "github.com/32leaves/werft/pkg/api/v1"
-	"github.com/gogo/protobuf/jsonpb"
+	"github.com/32leaves/werft/pkg/configschema"
+	"github.com/32leaves/werft/pkg/credentials"
 	"github.com/golang/protobuf/ptypes"
 	log "github.com/sirupsen/logrus"
-	"github.com/technosophos/moniker"
 	"golang.org/x/xerrors"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -49,6 +52,16 @@ const (
 
 	// AnnotationWaitUntil stores the start time of waiting job
 	AnnotationWaitUntil = "werft.sh/waitUntil"
+
+	// AnnotationTimeout overrides the total timeout of a job
+	AnnotationTimeout = "werft.sh/timeout"
+
+	// AnnotationAttempt stores how often this job has been tried
+	AnnotationAttempt = "werft.sh/attempt"
+
+	// AnnotationSecretEnv lists the environment variables werft set from secrets, one per line. Their values
+	// must never show, e.g. in logs.
+	AnnotationSecretEnv = "werft.sh/secretEnv"
 )
 
 // Config configures the executor
@@ -57,6 +70,73 @@ type Config struct {
 	EventTraceLog   string    `yaml:"eventTraceLog,omitempty"`
 	JobPrepTimeout  *Duration `yaml:"preperationTimeout"`
 	JobTotalTimeout *Duration `yaml:"totalTimeout"`
+
+	// ImagePullSecrets name secrets in the namespace which are used to pull the images of all jobs
+	ImagePullSecrets []string `yaml:"imagePullSecrets,omitempty"`
+
+	// Env is set in all containers of all jobs, e.g. proxy settings or registry mirrors.
+	// Jobs override these variables by setting them in their containers.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// ResyncInterval is the time after which all job pods are processed again, even if they haven't changed.
+	// Defaults to five minutes.
+	ResyncInterval *Duration `yaml:"resyncInterval,omitempty"`
+
+	// Capacity makes jobs wait until there is capacity for their pods. If this is nil, pods are created right away.
+	Capacity *CapacityConfig `yaml:"capacity,omitempty"`
+
+	// TerminationGracePeriod is the time the containers of a cancelled or timed out job have between SIGTERM and SIGKILL,
+	// e.g. to upload partial test results. Jobs override it using terminationGracePeriodSeconds in their pod.
+	// Defaults to five seconds.
+	TerminationGracePeriod *Duration `yaml:"terminationGracePeriod,omitempty"`
+
+	// ImagePolicy restricts the images the containers of jobs can use. If this is nil, jobs can use all images.
+	ImagePolicy *ImagePolicyConfig `yaml:"imagePolicy,omitempty"`
+
+	// GarbageCollection removes resources jobs leave behind once their pod has been gone for a while, e.g. cache PVCs,
+	// network policies or secrets labelled with werft.sh/jobName. If this is nil, werft removes no such resources.
+	GarbageCollection *GarbageCollectionConfig `yaml:"garbageCollection,omitempty"`
+
+	// StandbyPools keep idle pods of the images of common jobs running, which pre-pull those images and reserve room
+	// for the jobs on the nodes that have them. Only the kubernetes backend supports standby pools.
+	StandbyPools []StandbyPoolConfig `yaml:"standbyPools,omitempty"`
+
+	// Backend selects where jobs run: kubernetes (the default) runs every job in a pod, docker runs the containers of
+	// a job's pod on the werft host, e.g. for single-machine installations without a Kubernetes cluster.
+	Backend string `yaml:"backend,omitempty"`
+
+	// Docker configures the docker backend
+	Docker *DockerConfig `yaml:"docker,omitempty"`
+
+	// Nomad configures the nomad backend
+	Nomad *NomadConfig `yaml:"nomad,omitempty"`
+
+	// Agent configures the agent backend
+	Agent *AgentConfig `yaml:"agent,omitempty"`
+
+	// Fake configures the fake backend
+	Fake *FakeConfig `yaml:"fake,omitempty"`
+}
+
+// validate checks the parts of the config all backends use
+func (c *Config) validate() error {
+	if c.JobPrepTimeout == nil {
+		return xerrors.Errorf("job preperation timeout is required")
+	}
+	if c.JobTotalTimeout == nil {
+		return xerrors.Errorf("total job timeout is required")
+	}
+	if c.JobTotalTimeout.Duration < c.JobPrepTimeout.Duration {
+		return xerrors.Errorf("total job timeout must be greater than the preparation timeout")
+	}
+
+	if c.TerminationGracePeriod != nil && c.TerminationGracePeriod.Duration < 0 {
+		return xerrors.Errorf("termination grace period must not be negative")
+	}
+	if c.ImagePolicy != nil {
+		return c.ImagePolicy.Validate()
+	}
+	return nil
 }
 
 // Duration is a JSON un-/marshallable type
@@ -64,6 +144,11 @@ type Duration struct {
 	time.Duration
 }
 
+// ConfigSchema describes durations in config files
+func (Duration) ConfigSchema() *configschema.Schema {
+	return &configschema.Schema{Type: "string", Format: configschema.FormatDuration}
+}
+
 // UnmarshalYAML parses a duration from its JSON representation
 func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	var v string
@@ -86,25 +171,40 @@ func NewExecutor(config Config, kubeConfig *rest.Config) (*Executor, error) {
 		return nil, err
 	}
 
-	if config.JobPrepTimeout == nil {
-		return nil, xerrors.Errorf("job preperation timeout is required")
+	err = config.validate()
+	if err != nil {
+		return nil, err
 	}
-	if config.JobTotalTimeout == nil {
-		return nil, xerrors.Errorf("total job timeout is required")
+	if gc := config.GarbageCollection; gc != nil && (gc.ttl() < 0 || gc.interval() <= 0) {
+		return nil, xerrors.Errorf("garbage collection ttl must not be negative and its interval must be positive")
 	}
-	if config.JobTotalTimeout.Duration < config.JobPrepTimeout.Duration {
-		return nil, xerrors.Errorf("total job timeout must be greater than the preparation timeout")
+	err = validateStandbyPools(config.StandbyPools)
+	if err != nil {
+		return nil, err
+	}
+
+	resync := defaultResyncInterval
+	if config.ResyncInterval != nil {
+		resync = config.ResyncInterval.Duration
 	}
 
-	return &Executor{
+	js := &Executor{
 		OnUpdate: func(pod *corev1.Pod, status *werftv1.JobStatus) {},
 
 		Config:     config,
 		Client:     kubeClient,
 		KubeConfig: kubeConfig,
 
-		waitingJobs: make(map[string]*waitingJob),
-	}, nil
+		pods:           newPodInformer(kubeClient, config.Namespace, resync),
+		waitingJobs:    make(map[string]*waitingJob),
+		usage:          make(map[string]*v1.ResourceUsage),
+		standbyClaimed: make(chan struct{}, 1),
+	}
+	err = js.loadMaintenance()
+	if err != nil {
+		return nil, err
+	}
+	return js, nil
 }
 
 // Executor starts and watches jobs running in Kubernetes
@@ -117,22 +217,48 @@ type Executor struct {
 	Config     Config
 	KubeConfig *rest.Config
 
+	pods *podInformer
+
 	waitingJobs map[string]*waitingJob
+	maintenance Maintenance
 	mu          sync.RWMutex
+
+	usage   map[string]*v1.ResourceUsage
+	usageMu sync.RWMutex
+
+	// standbyClaimed tells the standby pools that a job claimed one of their pods
+	standbyClaimed chan struct{}
+
+	podHooks
+	lifecycle
+	jobClock
+	jobIDs
+	gating
 }
 
 // waitingJob is a job which doesn't run yet, but waits until it can start (e.g. based on time)
 type waitingJob struct {
 	Cancel func(reason string)
 	Start  func()
-	Mutex  string
-	Status *v1.JobStatus
+	// Approve lets a job which waits for approval go on. It's nil if the job doesn't need approval (anymore).
+	Approve func()
+	Mutex   string
+	Status  *v1.JobStatus
+	Since   time.Time
+	Until   time.Time
+	Reason  v1.WaitReason
+
+	// Details tells why a job waits for capacity or is held by its gate
+	Details string
 }
 
 // Run starts the executor and returns immediately
 func (js *Executor) Run() {
-	go js.monitorJobs()
-	go js.doHousekeeping()
+	js.goBackground(js.monitorJobs)
+	js.goBackground(js.doHousekeeping)
+	js.goBackground(js.monitorResourceUsage)
+	js.goBackground(js.collectGarbage)
+	js.goBackground(js.maintainStandbyPools)
 }
 
 type startOptions struct {
@@ -142,6 +268,22 @@ type startOptions struct {
 	Mutex       string
 	CanReplay   bool
 	WaitUntil   time.Time
+	Timeout     time.Duration
+	Attempt     int
+	WaitReason  v1.WaitReason
+	Approval    bool
+	Gate        Gate
+
+	ImagePullSecrets []string
+	Env              map[string]string
+	SecretEnv        []string
+	LimitEgress      bool
+	Egress           []networkingv1.NetworkPolicyEgressRule
+
+	// TrustedContainers are exempt from the image policy
+	TrustedContainers []corev1.Container
+	// TrustedHostPaths are mounted by werft itself and exempt from the allowed host paths
+	TrustedHostPaths []string
 }
 
 // StartOpt configures a job at startup
@@ -173,6 +315,18 @@ func WithAnnotations(annotations map[string]string) StartOpt {
 	}
 }
 
+// WithPodAnnotations sets annotations on the job's pod as they are, e.g. to configure the seccomp profile of the pod.
+// Unlike WithAnnotation these annotations are not job annotations.
+func WithPodAnnotations(annotations map[string]string) StartOpt {
+	return func(opts *startOptions) {
+		opts.Modifier = append(opts.Modifier, func(pod *corev1.Pod) {
+			for k, v := range annotations {
+				pod.Annotations[k] = v
+			}
+		})
+	}
+}
+
 // WithName sets the name of the job
 func WithName(name string) StartOpt {
 	return func(opts *startOptions) {
@@ -201,13 +355,132 @@ func WithWaitUntil(t time.Time) StartOpt {
 	}
 }
 
-// Start starts a new job
-func (js *Executor) Start(podspec corev1.PodSpec, metadata werftv1.JobMetadata, options ...StartOpt) (status *v1.JobStatus, err error) {
-	opts := startOptions{
-		JobName: fmt.Sprintf("werft-%s", strings.ReplaceAll(moniker.New().Name(), " ", "-")),
+// WithTimeout overrides the total timeout configured for the executor for this job
+func WithTimeout(timeout time.Duration) StartOpt {
+	return func(opts *startOptions) {
+		opts.Timeout = timeout
+	}
+}
+
+// WithAttempt marks the job as the n-th attempt of running it, e.g. when retrying a failed job
+func WithAttempt(n int) StartOpt {
+	return func(opts *startOptions) {
+		opts.Attempt = n
+	}
+}
+
+// WithWaitReason tells why a job waits until the time set using WithWaitUntil
+func WithWaitReason(reason v1.WaitReason) StartOpt {
+	return func(opts *startOptions) {
+		opts.WaitReason = reason
 	}
+}
+
+// WithImagePullSecrets adds image pull secrets to the job in addition to the ones configured for the executor
+func WithImagePullSecrets(names ...string) StartOpt {
+	return func(opts *startOptions) {
+		opts.ImagePullSecrets = append(opts.ImagePullSecrets, names...)
+	}
+}
+
+// injectEnv sets environment variables in all containers of a pod unless they set the variable themselves.
+// The variables come first so that the container's own variables can refer to them.
+func injectEnv(spec *corev1.PodSpec, env map[string]string) {
+	if len(env) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(env))
+	for k := range env {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, cs := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range cs {
+			c := &cs[i]
+			defined := make(map[string]struct{}, len(c.Env))
+			for _, e := range c.Env {
+				defined[e.Name] = struct{}{}
+			}
+
+			var res []corev1.EnvVar
+			for _, n := range names {
+				if _, ok := defined[n]; ok {
+					continue
+				}
+				res = append(res, corev1.EnvVar{Name: n, Value: env[n]})
+			}
+			c.Env = append(res, c.Env...)
+		}
+	}
+}
+
+// WithEnv sets environment variables in all containers of the job in addition to those configured for the executor
+func WithEnv(env map[string]string) StartOpt {
+	return func(opts *startOptions) {
+		if opts.Env == nil {
+			opts.Env = make(map[string]string, len(env))
+		}
+		for k, v := range env {
+			opts.Env[k] = v
+		}
+	}
+}
+
+// WithSecretEnv sets environment variables whose values are secret in all containers of the job's pod.
+// The pod records their names, so that werft keeps masking them after a restart.
+func WithSecretEnv(env map[string]string) StartOpt {
+	return func(opts *startOptions) {
+		WithEnv(env)(opts)
+		for k := range env {
+			opts.SecretEnv = append(opts.SecretEnv, k)
+		}
+	}
+}
+
+// SecretEnv returns the names of the environment variables werft set from secrets in a job's pod
+func SecretEnv(pod *corev1.Pod) []string {
+	if pod == nil || pod.Annotations[AnnotationSecretEnv] == "" {
+		return nil
+	}
+	return strings.Split(pod.Annotations[AnnotationSecretEnv], "\n")
+}
+
+// WithCredentials injects short-lived cloud credentials into the job's pod
+func WithCredentials(providers ...credentials.Provider) StartOpt {
+	return func(opts *startOptions) {
+		for _, p := range providers {
+			opts.Modifier = append(opts.Modifier, p.Inject)
+		}
+	}
+}
+
+// ValidateImagePullSecrets makes sure all secrets exist in the namespace jobs run in
+func (js *Executor) ValidateImagePullSecrets(names ...string) error {
+	for _, name := range names {
+		_, err := js.Client.CoreV1().Secrets(js.Config.Namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return xerrors.Errorf("image pull secret %s is not available in namespace %s: %w", name, js.Config.Namespace, err)
+		}
+	}
+	return nil
+}
+
+// newJobPod produces the pod of a job, i.e. applies the start options and the executor config to its pod spec.
+// Executors describe all jobs as pods, no matter where the jobs run. Jobs started without a name are named using newID.
+func newJobPod(cfg *Config, podspec corev1.PodSpec, metadata werftv1.JobMetadata, created time.Time, newID func() string, options ...StartOpt) (*corev1.Pod, *startOptions, error) {
+	opts := &startOptions{}
 	for _, opt := range options {
-		opt(&opts)
+		opt(opts)
+	}
+	if opts.JobName == "" {
+		opts.JobName = fmt.Sprintf("werft-%s", newID())
+	}
+	// werft's own modifications (opts.Modifier) are not subject to the image policy, hence we check beforehand
+	err := cfg.ImagePolicy.check(&podspec, metadata.Trigger, opts.TrustedContainers)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	annotations := make(map[string]string)
@@ -220,19 +493,63 @@ func (js *Executor) Start(podspec corev1.PodSpec, metadata werftv1.JobMetadata,
 	if !opts.WaitUntil.IsZero() {
 		annotations[AnnotationWaitUntil] = opts.WaitUntil.Format(time.RFC3339)
 	}
+	if opts.Timeout > 0 {
+		annotations[AnnotationTimeout] = opts.Timeout.String()
+	}
+	if opts.Attempt > 1 {
+		annotations[AnnotationAttempt] = fmt.Sprintf("%d", opts.Attempt)
+	}
+	if len(opts.TrustedHostPaths) > 0 {
+		annotations[AnnotationTrustedHostPaths] = strings.Join(opts.TrustedHostPaths, "\n")
+	}
+	if len(opts.SecretEnv) > 0 {
+		sort.Strings(opts.SecretEnv)
+		annotations[AnnotationSecretEnv] = strings.Join(opts.SecretEnv, "\n")
+	}
 
-	metadata.Created = ptypes.TimestampNow()
-	mdjson, err := (&jsonpb.Marshaler{
-		EnumsAsInts: true,
-	}).MarshalToString(&metadata)
+	metadata.Created, err = ptypes.TimestampProto(created)
 	if err != nil {
-		return nil, xerrors.Errorf("cannot marshal metadata: %w", err)
+		return nil, nil, xerrors.Errorf("invalid creation time: %w", err)
+	}
+	mdjson, err := encodeMetadata(&metadata)
+	if err != nil {
+		return nil, nil, xerrors.Errorf("cannot marshal metadata: %w", err)
 	}
 	annotations[AnnotationMetadata] = mdjson
+	err = checkAnnotationsSize(annotations)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, name := range append(cfg.ImagePullSecrets, opts.ImagePullSecrets...) {
+		var exists bool
+		for _, ref := range podspec.ImagePullSecrets {
+			if ref.Name == name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			podspec.ImagePullSecrets = append(podspec.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+		}
+	}
+
+	env := make(map[string]string, len(cfg.Env)+len(opts.Env))
+	for k, v := range cfg.Env {
+		env[k] = v
+	}
+	for k, v := range opts.Env {
+		env[k] = v
+	}
+	injectEnv(&podspec, env)
 
 	if podspec.RestartPolicy != corev1.RestartPolicyNever && podspec.RestartPolicy != corev1.RestartPolicyOnFailure {
 		podspec.RestartPolicy = corev1.RestartPolicyOnFailure
 	}
+	if podspec.TerminationGracePeriodSeconds == nil {
+		gracePeriod := int64(cfg.terminationGracePeriod().Seconds())
+		podspec.TerminationGracePeriodSeconds = &gracePeriod
+	}
 
 	meta := metav1.ObjectMeta{
 		Name: opts.JobName,
@@ -249,11 +566,23 @@ func (js *Executor) Start(podspec corev1.PodSpec, metadata werftv1.JobMetadata,
 	for _, opt := range opts.Modifier {
 		opt(&poddesc)
 	}
-
-	mutexCancelationMsg := fmt.Sprintf("a newer job (%s) with the same mutex (%s) started", opts.JobName, opts.Mutex)
 	if opts.Mutex != "" {
 		poddesc.ObjectMeta.Labels[LabelMutex] = opts.Mutex
+	}
+
+	return &poddesc, opts, nil
+}
 
+// Start starts a new job
+func (js *Executor) Start(podspec corev1.PodSpec, metadata werftv1.JobMetadata, options ...StartOpt) (status *v1.JobStatus, err error) {
+	desc, opts, err := newJobPod(&js.Config, podspec, metadata, js.now(), js.newID, options...)
+	if err != nil {
+		return nil, err
+	}
+	poddesc := *desc
+
+	mutexCancelationMsg := fmt.Sprintf("a newer job (%s) with the same mutex (%s) started", opts.JobName, opts.Mutex)
+	if opts.Mutex != "" {
 		// enforce mutex by marking all other jobs with the same mutex as failed
 		pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", LabelMutex, opts.Mutex)})
 		if err != nil {
@@ -285,33 +614,107 @@ func (js *Executor) Start(podspec corev1.PodSpec, metadata werftv1.JobMetadata,
 			log.Debugf("scheduling job\n%s", dbg)
 		}
 
+		err := js.preCreatePod(&poddesc)
+		if err != nil {
+			return nil, err
+		}
+		js.claimStandbyPod(&poddesc)
+
+		if opts.LimitEgress {
+			err := js.createEgressPolicy(opts.JobName, opts.Egress)
+			if err != nil {
+				return nil, err
+			}
+		}
+
 		job, err := js.Client.CoreV1().Pods(js.Config.Namespace).Create(&poddesc)
 		if err != nil {
+			if opts.LimitEgress {
+				if derr := js.deleteEgressPolicy(opts.JobName); derr != nil {
+					log.WithError(derr).WithField("name", opts.JobName).Warn("cannot delete egress network policy")
+				}
+			}
 			return nil, err
 		}
+		if opts.LimitEgress {
+			err = js.ownEgressPolicy(job)
+			if err != nil {
+				log.WithError(err).WithField("name", opts.JobName).Warn("cannot make pod own its egress network policy - the policy will outlive the pod")
+			}
+		}
+		js.postCreatePod(job)
 
-		return getStatus(job)
+		return getStatus(job, js.now())
 	}
 
 	// Register the go routine to start the job when its time comes.
 	// Werft will tell us again about this job upon startup (pass set of waiting jobs into NewExecutor).
 	// When a waiting job is canceled manually or by a mutex it's deleted from the store.
 	log.WithField("wait-until", opts.WaitUntil).Debug("waiting until")
-	if !opts.WaitUntil.IsZero() && opts.WaitUntil.After(time.Now()) {
-		status, err := getStatus(&poddesc)
+	scheduled := !opts.WaitUntil.IsZero() && opts.WaitUntil.After(js.now())
+	maintenance := js.Maintenance()
+	var (
+		lacksCapacity string
+		gateReason    v1.WaitReason
+		gateDetails   string
+	)
+	if !scheduled && !maintenance.Enabled {
+		lacksCapacity = js.lacksCapacity(&poddesc)
+	}
+	if !opts.Approval && !scheduled && !maintenance.Enabled && lacksCapacity == "" {
+		gateReason, gateDetails = opts.Gate.check()
+	}
+	gated := gateReason != v1.WaitReason_WAIT_UNKNOWN
+	if opts.Approval || scheduled || maintenance.Enabled || lacksCapacity != "" || gated {
+		status, err := getStatus(&poddesc, js.now())
 		if err != nil {
 			return nil, err
 		}
 
 		// This job's time hasn't come yet - let's delay its execution until later.
-		startChan, cancelChan := make(chan struct{}), make(chan string)
-		js.mu.Lock()
-		js.waitingJobs[opts.JobName] = &waitingJob{
-			Cancel: func(reason string) { cancelChan <- reason },
-			Start:  func() { close(startChan) },
-			Mutex:  opts.Mutex,
-			Status: status,
+		reason := v1.WaitReason_WAIT_SCHEDULED
+		if opts.Attempt > 1 {
+			reason = v1.WaitReason_WAIT_RETRY_BACKOFF
+		}
+		if opts.WaitReason != v1.WaitReason_WAIT_UNKNOWN {
+			reason = opts.WaitReason
 		}
+		if maintenance.Enabled {
+			reason = v1.WaitReason_WAIT_MAINTENANCE
+			status.Details = maintenanceDetails(maintenance)
+		} else if lacksCapacity != "" {
+			reason = v1.WaitReason_WAIT_CAPACITY
+			status.Details = lacksCapacity
+		} else if gated {
+			reason = gateReason
+			status.Details = gateDetails
+		}
+		// jobs wait for their approval first - what else they wait for matters once they're approved
+		waitReason, waitDetails := reason, lacksCapacity
+		if gated {
+			waitDetails = gateDetails
+		}
+		if opts.Approval {
+			waitReason, waitDetails = v1.WaitReason_WAIT_APPROVAL, ""
+			status.Details = approvalDetails
+		}
+		// cancelChan is buffered so that those canceling the job while holding js.mu don't block on us
+		startChan, cancelChan, approveChan := make(chan struct{}), make(chan string, 1), make(chan struct{})
+		wj := &waitingJob{
+			Cancel:  func(reason string) { cancelChan <- reason },
+			Start:   func() { close(startChan) },
+			Mutex:   opts.Mutex,
+			Status:  status,
+			Since:   js.now(),
+			Until:   opts.WaitUntil,
+			Reason:  waitReason,
+			Details: waitDetails,
+		}
+		if opts.Approval {
+			wj.Approve = func() { close(approveChan) }
+		}
+		js.mu.Lock()
+		js.waitingJobs[opts.JobName] = wj
 		js.mu.Unlock()
 
 		run := func() {
@@ -319,22 +722,97 @@ func (js *Executor) Start(podspec corev1.PodSpec, metadata werftv1.JobMetadata,
 			delete(js.waitingJobs, opts.JobName)
 			js.mu.Unlock()
 
-			startJob()
+			_, err := startJob()
+			if err != nil {
+				log.WithError(err).WithField("name", opts.JobName).Error("cannot start waiting job")
+			}
+		}
+		cancel := func(reason string) {
+			log.WithField("name", opts.JobName).Debug("canceled this waiting job")
+			status.Phase = v1.JobPhase_PHASE_DONE
+			status.Conditions.Success = false
+			status.Details = reason
+			js.OnUpdate(&poddesc, status)
+		}
+		// hold keeps the job waiting, e.g. after the executor resumed but there is no capacity for the job yet.
+		// It returns false if the job was canceled in the meantime.
+		hold := func(reason v1.WaitReason, details string) bool {
+			js.mu.Lock()
+			select {
+			case r := <-cancelChan:
+				js.mu.Unlock()
+				cancel(r)
+				return false
+			default:
+			}
+			wj.Reason = reason
+			wj.Details = details
+			js.waitingJobs[opts.JobName] = wj
+			js.mu.Unlock()
+			return true
 		}
 
 		go func() {
-			d := opts.WaitUntil.Sub(time.Now())
-			select {
-			case <-time.After(d):
-				run()
-			case <-startChan:
+			if opts.Approval {
+				select {
+				case <-approveChan:
+				case reason := <-cancelChan:
+					cancel(reason)
+					return
+				}
+				if !hold(reason, "") {
+					return
+				}
+			}
+
+			var timeout <-chan time.Time
+			if scheduled {
+				timeout = time.After(opts.WaitUntil.Sub(js.now()))
+			} else if opts.Approval {
+				// approved jobs start right away unless werft is in maintenance mode or lacks capacity
+				timeout = time.After(0)
+			} else if reason == v1.WaitReason_WAIT_CAPACITY {
+				timeout = time.After(js.capacityCheckInterval())
+			} else if gated {
+				timeout = time.After(js.gateCheckInterval())
+			}
+			start := startChan
+			for {
+				select {
+				case <-timeout:
+				case <-start:
+					start = nil
+				case reason := <-cancelChan:
+					cancel(reason)
+					return
+				}
+
+				// in maintenance mode the job keeps waiting until the executor resumes
+				if js.Maintenance().Enabled {
+					if !hold(v1.WaitReason_WAIT_MAINTENANCE, "") {
+						return
+					}
+					timeout = nil
+					continue
+				}
+				// without capacity for its pod the job keeps waiting and checks again later
+				if details := js.lacksCapacity(&poddesc); details != "" {
+					if !hold(v1.WaitReason_WAIT_CAPACITY, details) {
+						return
+					}
+					timeout = time.After(js.capacityCheckInterval())
+					continue
+				}
+				// the job's gate might hold it, e.g. until other jobs are done
+				if reason, details := opts.Gate.check(); reason != v1.WaitReason_WAIT_UNKNOWN {
+					if !hold(reason, details) {
+						return
+					}
+					timeout = time.After(js.gateCheckInterval())
+					continue
+				}
 				run()
-			case reason := <-cancelChan:
-				log.WithField("name", opts.JobName).Debug("canceled this waiting job")
-				status.Phase = v1.JobPhase_PHASE_DONE
-				status.Conditions.Success = false
-				status.Details = reason
-				js.OnUpdate(&poddesc, status)
+				return
 			}
 		}()
 
@@ -351,45 +829,51 @@ func (js *Executor) Start(podspec corev1.PodSpec, metadata werftv1.JobMetadata,
 	return startJob()
 }
 
-func (js *Executor) monitorJobs() {
-	reconnectionTimeout := 100 * time.Millisecond
-	for {
-		incoming, err := js.Client.CoreV1().Pods(js.Config.Namespace).Watch(metav1.ListOptions{
-			LabelSelector: fmt.Sprintf("%s=true", LabelWerftMarker),
-		})
-		if err != nil {
-			log.WithError(err).Error("cannot watch jobs - retrying")
-			time.Sleep(reconnectionTimeout)
-			continue
-		}
-		log.Info("connected to Kubernetes master")
+func (js *Executor) monitorJobs(stop <-chan struct{}) {
+	js.pods.run(stop, js.handleJobEvent)
+}
 
-		for evt := range incoming.ResultChan() {
-			if evt.Object == nil {
-				break
-			}
-			obj, ok := evt.Object.(*corev1.Pod)
-			if !ok {
-				continue
-			}
+// InformerStats returns the state of the job pod cache
+func (js *Executor) InformerStats() InformerStats {
+	return js.pods.Stats()
+}
 
-			js.handleJobEvent(evt.Type, obj)
+// listPods lists the job pods matching selector. Once the job pod cache is synced, pods come from there instead of
+// the Kubernetes API.
+func (js *Executor) listPods(selector string) ([]corev1.Pod, error) {
+	if js.pods.Synced() {
+		sel, err := labels.Parse(selector)
+		if err != nil {
+			return nil, err
 		}
-		log.Warn("lost connection to Kubernetes master")
-
-		time.Sleep(reconnectionTimeout)
+		return js.pods.listPods(js.Config.Namespace, sel)
 	}
 
-	// TODO: handle graceful shutdown
+	return js.listPodsFromAPI(selector)
+}
+
+// listPodsFromAPI lists the job pods matching selector using the Kubernetes API
+func (js *Executor) listPodsFromAPI(selector string) ([]corev1.Pod, error) {
+	pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
 }
 
 func (js *Executor) handleJobEvent(evttpe watch.EventType, obj *corev1.Pod) {
-	status, err := getStatus(obj)
+	status, err := getStatus(obj, js.now())
 	js.writeEventTraceLog(status, obj)
 	if err != nil {
 		log.WithError(err).WithField("name", obj.Name).Error("cannot compute status")
 		return
 	}
+	status.ResourceUsage = js.ResourceUsage(status.Name)
+	if evttpe == watch.Deleted {
+		defer js.forgetResourceUsage(status.Name)
+	}
 
 	js.OnUpdate(obj, status)
 	err = js.actOnUpdate(status, obj)
@@ -401,17 +885,12 @@ func (js *Executor) handleJobEvent(evttpe watch.EventType, obj *corev1.Pod) {
 
 func (js *Executor) actOnUpdate(status *werftv1.JobStatus, obj *corev1.Pod) error {
 	if status.Phase == werftv1.JobPhase_PHASE_DONE {
-		gracePeriod := int64(5)
-		policy := metav1.DeletePropagationForeground
-
-		err := js.Client.CoreV1().Pods(js.Config.Namespace).Delete(obj.Name, &metav1.DeleteOptions{
-			GracePeriodSeconds: &gracePeriod,
-			PropagationPolicy:  &policy,
-		})
-		if err != nil {
-			log.WithError(err).WithField("name", obj.Name).Error("cannot delete job pod")
+		if !status.Conditions.Success && js.keepForDebugging(obj) {
+			return nil
 		}
 
+		js.deleteJobPod(obj)
+
 		// TODO: clean up workspace content
 
 		return nil
@@ -420,6 +899,26 @@ func (js *Executor) actOnUpdate(status *werftv1.JobStatus, obj *corev1.Pod) erro
 	return nil
 }
 
+// deleteJobPod deletes the pod of a job which is done
+func (js *Executor) deleteJobPod(pod *corev1.Pod) {
+	js.preDeletePod(pod)
+
+	// Kubernetes sends SIGTERM to the containers and SIGKILL once the grace period is over
+	gracePeriod := int64(js.Config.terminationGracePeriod().Seconds())
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		gracePeriod = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	policy := metav1.DeletePropagationForeground
+
+	err := js.Client.CoreV1().Pods(js.Config.Namespace).Delete(pod.Name, &metav1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriod,
+		PropagationPolicy:  &policy,
+	})
+	if err != nil {
+		log.WithError(err).WithField("name", pod.Name).Error("cannot delete job pod")
+	}
+}
+
 func (js *Executor) writeEventTraceLog(status *werftv1.JobStatus, obj *corev1.Pod) {
 	// make sure we recover from a panic in this function - not that we expect this to ever happen
 	//nolint:errcheck
@@ -450,7 +949,12 @@ func (js *Executor) writeEventTraceLog(status *werftv1.JobStatus, obj *corev1.Po
 	// If writing the event trace log fails that does nothing to harm the function of ws-manager.
 	// In fact we don't even want to react to it, hence the nolint.
 	//nolint:errcheck
-	json.NewEncoder(out).Encode(eventTraceEntry{Time: time.Now().Format(time.RFC3339), Status: status, Job: obj})
+	json.NewEncoder(out).Encode(eventTraceEntry{Time: js.now().Format(time.RFC3339), Status: status, Job: obj})
+}
+
+// SetUpdateHandler sets the function called when the status of a job changes, i.e. OnUpdate
+func (js *Executor) SetUpdateHandler(f func(pod *corev1.Pod, status *werftv1.JobStatus)) {
+	js.OnUpdate = f
 }
 
 // Logs provides the log output of a running job. If the job is unknown, nil is returned.
@@ -458,20 +962,41 @@ func (js *Executor) Logs(name string) io.Reader {
 	return listenToLogs(js.Client, name, js.Config.Namespace)
 }
 
-func (js *Executor) doHousekeeping() {
+// ContainerLogs returns the complete output of a container of a pod, e.g. of a job which finished.
+// Logs larger than limit bytes are truncated.
+func (js *Executor) ContainerLogs(pod, container string, limit int64) ([]byte, error) {
+	return js.Client.CoreV1().Pods(js.Config.Namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container:  container,
+		LimitBytes: &limit,
+	}).DoRaw()
+}
+
+func (js *Executor) doHousekeeping(stop <-chan struct{}) {
 	tick := time.NewTicker(js.Config.JobPrepTimeout.Duration / 2)
+	defer tick.Stop()
 	for {
 		// check our state and watch for non-existent jobs/events that we missed
-		pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{
-			LabelSelector: fmt.Sprintf("%s=true", LabelWerftMarker),
-		})
+		pods, err := js.listPods(fmt.Sprintf("%s=true", LabelWerftMarker))
 		if err != nil {
 			log.WithError(err).Warn("cannot perform housekeeping")
-			continue
+			select {
+			case <-tick.C:
+				continue
+			case <-stop:
+				return
+			}
 		}
 
-		for _, pod := range pods.Items {
-			status, err := getStatus(&pod)
+		for _, pod := range pods {
+			if until, ok := pod.Annotations[AnnotationDebugUntil]; ok {
+				// the pod of this failed job is kept for debugging - its time's up once the keep-alive period has passed
+				if t, err := time.Parse(time.RFC3339, until); err != nil || js.now().After(t) {
+					js.deleteJobPod(&pod)
+				}
+				continue
+			}
+
+			status, err := getStatus(&pod, js.now())
 			if err != nil {
 				log.WithError(err).WithField("name", pod.Name).Warn("cannot perform housekeeping")
 				continue
@@ -486,10 +1011,12 @@ func (js *Executor) doHousekeeping() {
 			var ttl time.Duration
 			if status.Phase == v1.JobPhase_PHASE_PREPARING {
 				ttl = js.Config.JobPrepTimeout.Duration
+			} else if timeout, err := time.ParseDuration(pod.Annotations[AnnotationTimeout]); err == nil {
+				ttl = timeout
 			} else {
 				ttl = js.Config.JobTotalTimeout.Duration
 			}
-			if time.Since(created) < ttl {
+			if js.now().Sub(created) < ttl {
 				continue
 			}
 
@@ -500,7 +1027,11 @@ func (js *Executor) doHousekeeping() {
 			})
 		}
 
-		<-tick.C
+		select {
+		case <-tick.C:
+		case <-stop:
+			return
+		}
 	}
 }
 
@@ -509,21 +1040,36 @@ var errNotFound = xerrors.Errorf("unknown job")
 
 // Finds the pod executing a job
 func (js *Executor) getJobPod(name string) (*corev1.Pod, error) {
-	pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%s=%s", LabelJobName, name),
-	})
+	selector := fmt.Sprintf("%s=%s", LabelJobName, name)
+	pods, err := js.listPods(selector)
+	if err == nil && len(pods) == 0 && js.pods.Synced() {
+		// the cache might not know about a pod we've just created yet
+		pods, err = js.listPodsFromAPI(selector)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if len(pods.Items) == 0 {
+	if len(pods) == 0 {
 		return nil, xerrors.Errorf("%w: %s", errNotFound, name)
 	}
-	if len(pods.Items) > 1 {
+	if len(pods) > 1 {
 		return nil, xerrors.Errorf("job %s has no unique execution", name)
 	}
 
-	return &pods.Items[0], nil
+	return &pods[0], nil
+}
+
+// JobPod returns the pod a job runs in, or nil if the job has no pod (anymore)
+func (js *Executor) JobPod(name string) (*corev1.Pod, error) {
+	pod, err := js.getJobPod(name)
+	if xerrors.Is(err, errNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pod, nil
 }
 
 // Stop stops a job
@@ -562,18 +1108,17 @@ func (js *Executor) GetKnownJobs() (jobs []v1.JobStatus, err error) {
 	}
 	js.mu.RUnlock()
 
-	pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%s=true", LabelWerftMarker),
-	})
+	pods, err := js.listPods(fmt.Sprintf("%s=true", LabelWerftMarker))
 	if err != nil {
 		return nil, err
 	}
-	for _, pod := range pods.Items {
+	for _, pod := range pods {
 		var status *v1.JobStatus
-		status, err = getStatus(&pod)
+		status, err = getStatus(&pod, js.now())
 		if err != nil {
 			return nil, err
 		}
+		status.ResourceUsage = js.ResourceUsage(status.Name)
 
 		jobs = append(jobs, *status)
 	}
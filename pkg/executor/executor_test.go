@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNewJobPodSecretEnv(t *testing.T) {
+	spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Image: "alpine"}}}
+	pod, _, err := newJobPod(&Config{}, spec, v1.JobMetadata{}, time.Now(), monikerIDs{}.NewID,
+		WithEnv(map[string]string{"PLAIN": "value"}),
+		WithSecretEnv(map[string]string{"NPM_AUTH": "s3cr3t", "API_KEY": "k3y"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := make(map[string]string)
+	for _, e := range pod.Spec.Containers[0].Env {
+		env[e.Name] = e.Value
+	}
+	if env["PLAIN"] != "value" || env["NPM_AUTH"] != "s3cr3t" || env["API_KEY"] != "k3y" {
+		t.Errorf("expected all env vars to be set, got %v", env)
+	}
+	if act, exp := SecretEnv(pod), []string{"API_KEY", "NPM_AUTH"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected secret env: got %v, want %v", act, exp)
+	}
+
+	pod, _, err = newJobPod(&Config{}, spec, v1.JobMetadata{}, time.Now(), monikerIDs{}.NewID, WithEnv(map[string]string{"PLAIN": "value"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := SecretEnv(pod); act != nil {
+		t.Errorf("expected no secret env, got %v", act)
+	}
+}
+
+func TestNewJobPodEgress(t *testing.T) {
+	spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Image: "alpine"}}}
+	_, opts, err := newJobPod(&Config{}, spec, v1.JobMetadata{}, time.Now(), monikerIDs{}.NewID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.LimitEgress {
+		t.Error("jobs without egress rules should not be limited")
+	}
+
+	_, opts, err = newJobPod(&Config{}, spec, v1.JobMetadata{}, time.Now(), monikerIDs{}.NewID, WithEgress(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.LimitEgress {
+		t.Error("jobs with an empty set of egress rules should still be limited to DNS")
+	}
+	if np := egressPolicy(opts.JobName, opts.Egress); len(np.Spec.Egress) != 1 {
+		t.Errorf("expected the network policy to allow DNS only, got %v", np.Spec.Egress)
+	}
+}
+
+func TestNewJobPodCreated(t *testing.T) {
+	created := time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+	spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Image: "alpine"}}}
+	pod, _, err := newJobPod(&Config{}, spec, v1.JobMetadata{}, created, monikerIDs{}.NewID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := getStatus(pod, created.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err := ptypes.Timestamp(status.Metadata.Created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !act.Equal(created) {
+		t.Errorf("expected the job to be created at %v, got %v", created, act)
+	}
+	if status.Metadata.Finished != nil {
+		t.Errorf("expected a job which isn't done to have no finish time, got %v", status.Metadata.Finished)
+	}
+}
+
+func TestNewJobPodName(t *testing.T) {
+	spec := corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Image: "alpine"}}}
+	newID := func() string { return "id-1" }
+
+	pod, _, err := newJobPod(&Config{}, spec, v1.JobMetadata{}, time.Now(), newID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pod.Name != "werft-id-1" {
+		t.Errorf("unnamed job: expected name werft-id-1, got %s", pod.Name)
+	}
+
+	pod, _, err = newJobPod(&Config{}, spec, v1.JobMetadata{}, time.Now(), newID, WithName("build-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pod.Name != "build-1" {
+		t.Errorf("named job: expected name build-1, got %s", pod.Name)
+	}
+}
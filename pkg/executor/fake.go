@@ -0,0 +1,333 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	defaultFakeJobDuration = 10 * time.Second
+	defaultFakeLogLines    = 100
+	defaultFakeLineLength  = 80
+)
+
+// FakeConfig configures the fake executor
+type FakeConfig struct {
+	// Duration is how long the containers of every job run. Defaults to ten seconds.
+	Duration *Duration `yaml:"duration,omitempty"`
+
+	// LogLines is the number of lines every container logs while it runs. Defaults to 100.
+	LogLines int `yaml:"logLines,omitempty"`
+
+	// LineLength is the length of the lines containers log. Defaults to 80.
+	LineLength int `yaml:"lineLength,omitempty"`
+
+	// FailureRate is the share of jobs which fail, between 0 and 1
+	FailureRate float64 `yaml:"failureRate,omitempty"`
+}
+
+func (c *FakeConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Duration != nil && c.Duration.Duration < 0 {
+		return xerrors.Errorf("fake: duration must not be negative")
+	}
+	if c.LogLines < 0 || c.LineLength < 0 {
+		return xerrors.Errorf("fake: logLines and lineLength must not be negative")
+	}
+	if c.FailureRate < 0 || c.FailureRate > 1 {
+		return xerrors.Errorf("fake: failureRate must be between 0 and 1")
+	}
+	return nil
+}
+
+func (c *FakeConfig) duration() time.Duration {
+	if c == nil || c.Duration == nil {
+		return defaultFakeJobDuration
+	}
+	return c.Duration.Duration
+}
+
+func (c *FakeConfig) logLines() int {
+	if c == nil || c.LogLines == 0 {
+		return defaultFakeLogLines
+	}
+	return c.LogLines
+}
+
+func (c *FakeConfig) lineLength() int {
+	if c == nil || c.LineLength == 0 {
+		return defaultFakeLineLength
+	}
+	return c.LineLength
+}
+
+func (c *FakeConfig) failureRate() float64 {
+	if c == nil {
+		return 0
+	}
+	return c.FailureRate
+}
+
+// NewFakeExecutor creates a new executor which pretends to run jobs
+func NewFakeExecutor(config Config) (*FakeExecutor, error) {
+	err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+	err = config.Fake.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &FakeExecutor{newRuntimeExecutor(config, &fakeRuntime{
+		Config:     config.Fake,
+		containers: make(map[string]*fakeContainer),
+	})}, nil
+}
+
+// FakeExecutor pretends to run jobs: the containers of job pods don't run anything but log synthetic output for a
+// while and then stop, failing at the configured rate. It exercises werft, e.g. its stores and log pipeline, under
+// load without the cost of running actual jobs.
+type FakeExecutor struct {
+	*runtimeExecutor
+}
+
+// fakeRuntime is the container runtime of the fake executor
+type fakeRuntime struct {
+	Config *FakeConfig
+
+	containers map[string]*fakeContainer
+	mu         sync.Mutex
+}
+
+// fakeContainer logs its lines evenly spread across its duration
+type fakeContainer struct {
+	Name     string
+	Started  time.Time
+	Duration time.Duration
+	Lines    int
+	Length   int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	finished time.Time
+}
+
+// due returns when the container logs line i
+func (c *fakeContainer) due(i int) time.Time {
+	return c.Started.Add(c.Duration * time.Duration(i) / time.Duration(c.Lines))
+}
+
+// line produces line i of the container's log
+func (c *fakeContainer) line(i int) []byte {
+	if i == 0 {
+		return []byte(fmt.Sprintf("[%s|PHASE] running synthetic %s container\n", c.Name, c.Name))
+	}
+	l := fmt.Sprintf("[%s] synthetic log line %d of %d ", c.Name, i, c.Lines-1)
+	if pad := c.Length - len(l); pad > 0 {
+		l += strings.Repeat("x", pad)
+	}
+	return []byte(l + "\n")
+}
+
+// logged returns the number of lines the container has logged so far
+func (c *fakeContainer) logged() int {
+	until := time.Now()
+	select {
+	case <-c.done:
+		until = c.finished
+	default:
+	}
+
+	var n int
+	for n < c.Lines && !c.due(n).After(until) {
+		n++
+	}
+	return n
+}
+
+// Name returns fake
+func (f *fakeRuntime) Name() string {
+	return "fake"
+}
+
+// CheckPod accepts all pods - they don't run anyways
+func (f *fakeRuntime) CheckPod(pod *corev1.Pod) error {
+	return nil
+}
+
+// RemoveAll forgets all containers
+func (f *fakeRuntime) RemoveAll() error {
+	f.mu.Lock()
+	f.containers = make(map[string]*fakeContainer)
+	f.mu.Unlock()
+	return nil
+}
+
+// RunPod pretends to run the init containers one after the other, followed by all other containers at once
+func (f *fakeRuntime) RunPod(pod *corev1.Pod, r podReporter) error {
+	for _, c := range pod.Spec.InitContainers {
+		if r.Stopped() {
+			return nil
+		}
+		if !f.runContainer(pod, c.Name, true, 0, 1, 0, r) {
+			return nil
+		}
+	}
+	if r.Stopped() {
+		return nil
+	}
+	r.InitDone()
+
+	var (
+		wg   sync.WaitGroup
+		fail = rand.Float64() < f.Config.failureRate()
+	)
+	for i, c := range pod.Spec.Containers {
+		var exitCode int32
+		if fail && i == len(pod.Spec.Containers)-1 {
+			exitCode = 1
+		}
+		wg.Add(1)
+		go func(name string, exitCode int32) {
+			defer wg.Done()
+			f.runContainer(pod, name, false, f.Config.duration(), f.Config.logLines(), exitCode, r)
+		}(c.Name, exitCode)
+	}
+	wg.Wait()
+	return nil
+}
+
+// runContainer pretends to run a container for d and returns true if it succeeded
+func (f *fakeRuntime) runContainer(pod *corev1.Pod, name string, init bool, d time.Duration, lines int, exitCode int32, r podReporter) bool {
+	id := pod.Name + "/" + name
+	c := &fakeContainer{
+		Name:     name,
+		Started:  time.Now(),
+		Duration: d,
+		Lines:    lines,
+		Length:   f.Config.lineLength(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	f.mu.Lock()
+	f.containers[id] = c
+	f.mu.Unlock()
+	r.ContainerStarted(name, id, init)
+
+	state := corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: "Completed"}
+	if exitCode != 0 {
+		state.Reason = "Error"
+	}
+	t := time.NewTimer(d)
+	select {
+	case <-t.C:
+	case <-c.stop:
+		t.Stop()
+		state = corev1.ContainerStateTerminated{ExitCode: 137, Reason: "Killed"}
+	}
+	c.finished = time.Now()
+	close(c.done)
+
+	r.ContainerStopped(name, state)
+	return state.ExitCode == 0
+}
+
+// StopPod stops the containers of the pod right away
+func (f *fakeRuntime) StopPod(pod *corev1.Pod, containers map[string]string, gracePeriod time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range containers {
+		if c, ok := f.containers[id]; ok {
+			c.stopOnce.Do(func() { close(c.stop) })
+		}
+	}
+}
+
+// RemovePod forgets the containers of the pod
+func (f *fakeRuntime) RemovePod(pod *corev1.Pod, containers map[string]string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range containers {
+		delete(f.containers, id)
+	}
+	return nil
+}
+
+func (f *fakeRuntime) container(id string) (*fakeContainer, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.containers[id]
+	if !ok {
+		return nil, xerrors.Errorf("%w: container %s", errNotFound, id)
+	}
+	return c, nil
+}
+
+// Logs streams the lines of a container as it logs them
+func (f *fakeRuntime) Logs(id string) (io.ReadCloser, error) {
+	c, err := f.container(id)
+	if err != nil {
+		return nil, err
+	}
+
+	r, w := io.Pipe()
+	go func() {
+		for i := 0; i < c.Lines; i++ {
+			if wait := time.Until(c.due(i)); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-c.done:
+				}
+			}
+			select {
+			case <-c.done:
+				if c.due(i).After(c.finished) {
+					// the container was stopped before it logged this line
+					w.Close()
+					return
+				}
+			default:
+			}
+			if _, err := w.Write(c.line(i)); err != nil {
+				return
+			}
+		}
+		w.Close()
+	}()
+	return r, nil
+}
+
+// ContainerLogs returns the lines a container logged so far
+func (f *fakeRuntime) ContainerLogs(id string, limit int64) ([]byte, error) {
+	c, err := f.container(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for i, n := 0, c.logged(); i < n && int64(buf.Len()) < limit; i++ {
+		buf.Write(c.line(i))
+	}
+	res := buf.Bytes()
+	if int64(len(res)) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+// Exec is not supported: fake containers don't run anything
+func (f *fakeRuntime) Exec(id string, opts AttachOptions) error {
+	return xerrors.Errorf("the fake executor cannot attach to containers")
+}
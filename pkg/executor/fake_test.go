@@ -0,0 +1,255 @@
+package executor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// testPodReporter records what a container runtime reports about a pod
+type testPodReporter struct {
+	mu      sync.Mutex
+	events  []string
+	ids     map[string]string
+	stopped bool
+}
+
+func (r *testPodReporter) ContainerStarted(name, id string, init bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ids == nil {
+		r.ids = make(map[string]string)
+	}
+	r.ids[name] = id
+	r.events = append(r.events, fmt.Sprintf("started %s init=%v", name, init))
+}
+
+func (r *testPodReporter) ContainerStopped(name string, state corev1.ContainerStateTerminated) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf("stopped %s %d %s", name, state.ExitCode, state.Reason))
+}
+
+func (r *testPodReporter) InitDone() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, "init done")
+}
+
+func (r *testPodReporter) Stopped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stopped
+}
+
+func (r *testPodReporter) containerIDs() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := make(map[string]string, len(r.ids))
+	for k, v := range r.ids {
+		res[k] = v
+	}
+	return res
+}
+
+func fakePod() *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "checkout"}},
+			Containers:     []corev1.Container{{Name: "build"}, {Name: "sidecar"}},
+		},
+	}
+}
+
+func TestFakeConfig(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config *FakeConfig
+		Error  string
+	}{
+		{Name: "nil"},
+		{Name: "valid", Config: &FakeConfig{Duration: &Duration{Duration: time.Minute}, LogLines: 10, LineLength: 20, FailureRate: 0.5}},
+		{Name: "negative duration", Config: &FakeConfig{Duration: &Duration{Duration: -time.Second}}, Error: "duration must not be negative"},
+		{Name: "negative lines", Config: &FakeConfig{LogLines: -1}, Error: "logLines and lineLength must not be negative"},
+		{Name: "failure rate too high", Config: &FakeConfig{FailureRate: 1.5}, Error: "failureRate must be between 0 and 1"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.validate()
+			if test.Error == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if test.Error != "" && (err == nil || !strings.Contains(err.Error(), test.Error)) {
+				t.Errorf("expected error %q, got %v", test.Error, err)
+			}
+		})
+	}
+
+	var cfg *FakeConfig
+	if cfg.duration() != defaultFakeJobDuration || cfg.logLines() != defaultFakeLogLines || cfg.lineLength() != defaultFakeLineLength || cfg.failureRate() != 0 {
+		t.Error("expected defaults without config")
+	}
+}
+
+func TestFakeContainerLines(t *testing.T) {
+	c := &fakeContainer{Name: "build", Lines: 3, Length: 40}
+	if act := string(c.line(0)); act != "[build|PHASE] running synthetic build container\n" {
+		t.Errorf("unexpected first line %q", act)
+	}
+	if act := string(c.line(1)); act != "[build] synthetic log line 1 of 2 xxxxxx\n" {
+		t.Errorf("unexpected padded line %q", act)
+	}
+
+	c.Length = 5
+	if act := string(c.line(2)); act != "[build] synthetic log line 2 of 2 \n" {
+		t.Errorf("expected long lines not to be cut, got %q", act)
+	}
+}
+
+func TestFakeRuntimeRunPod(t *testing.T) {
+	tests := []struct {
+		Name        string
+		FailureRate float64
+		Expected    []string
+	}{
+		{
+			Name: "success",
+			Expected: []string{
+				"started checkout init=true", "stopped checkout 0 Completed", "init done",
+				"started build init=false", "started sidecar init=false", "stopped build 0 Completed", "stopped sidecar 0 Completed",
+			},
+		},
+		{
+			Name:        "failure",
+			FailureRate: 1,
+			Expected: []string{
+				"started checkout init=true", "stopped checkout 0 Completed", "init done",
+				"started build init=false", "started sidecar init=false", "stopped build 0 Completed", "stopped sidecar 1 Error",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			rt := &fakeRuntime{
+				Config:     &FakeConfig{Duration: &Duration{Duration: 10 * time.Millisecond}, LogLines: 5, FailureRate: test.FailureRate},
+				containers: make(map[string]*fakeContainer),
+			}
+			var r testPodReporter
+			err := rt.RunPod(fakePod(), &r)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the containers run concurrently, hence we only compare the order of the init containers
+			act := append([]string(nil), r.events...)
+			if len(act) != len(test.Expected) {
+				t.Fatalf("expected %v, got %v", test.Expected, act)
+			}
+			if strings.Join(act[:3], ",") != strings.Join(test.Expected[:3], ",") {
+				t.Errorf("expected init containers to run first: %v, got %v", test.Expected[:3], act[:3])
+			}
+			for _, exp := range test.Expected[3:] {
+				var found bool
+				for _, a := range act[3:] {
+					found = found || a == exp
+				}
+				if !found {
+					t.Errorf("expected %q in %v", exp, act)
+				}
+			}
+
+			ids := r.containerIDs()
+			logs, err := rt.ContainerLogs(ids["build"], 1<<20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := strings.Count(string(logs), "\n"); n != 5 {
+				t.Errorf("expected 5 lines once the container is done, got %d", n)
+			}
+			logs, err = rt.ContainerLogs(ids["build"], 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(logs) != 10 {
+				t.Errorf("expected logs to be limited to 10 bytes, got %d", len(logs))
+			}
+
+			rd, err := rt.Logs(ids["sidecar"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			streamed, err := ioutil.ReadAll(rd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := strings.Count(string(streamed), "\n"); n != 5 {
+				t.Errorf("expected 5 streamed lines, got %d", n)
+			}
+
+			err = rt.RemovePod(fakePod(), ids)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := rt.ContainerLogs(ids["build"], 1<<20); err == nil {
+				t.Error("expected removed containers to be gone")
+			}
+		})
+	}
+}
+
+func TestFakeRuntimeStopPod(t *testing.T) {
+	rt := &fakeRuntime{
+		Config:     &FakeConfig{Duration: &Duration{Duration: time.Hour}, LogLines: 1000},
+		containers: make(map[string]*fakeContainer),
+	}
+	var r testPodReporter
+	done := make(chan error, 1)
+	go func() { done <- rt.RunPod(fakePod(), &r) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(r.containerIDs()) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("containers did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	ids := r.containerIDs()
+	rd, err := rt.Logs(ids["build"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rt.StopPod(fakePod(), ids, 0)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("pod did not stop")
+	}
+	for _, exp := range []string{"stopped build 137 Killed", "stopped sidecar 137 Killed"} {
+		var found bool
+		for _, a := range r.events {
+			found = found || a == exp
+		}
+		if !found {
+			t.Errorf("expected %q in %v", exp, r.events)
+		}
+	}
+
+	// the log stream ends once the container was stopped
+	streamed, err := ioutil.ReadAll(rd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(streamed), "\n"); n >= 1000 {
+		t.Errorf("expected the stopped container not to log all lines, got %d", n)
+	}
+}
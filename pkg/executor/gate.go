@@ -0,0 +1,45 @@
+package executor
+
+import (
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+// defaultGateCheckInterval is the time after which jobs held by their gate check again
+const defaultGateCheckInterval = 15 * time.Second
+
+// Gate holds a job in the queue until it can start. It returns why the job cannot start yet, e.g. WAIT_RATE_LIMIT and
+// details users can act on, or WAIT_UNKNOWN if the job can start. The executor asks the gate right before it starts
+// the job, hence a gate which lets a job through can reserve what the job needs, e.g. one of a limited number of slots.
+// Gates are asked only once nothing else holds the job, i.e. the job is approved, its time has come, the executor
+// isn't in maintenance mode and there is capacity for the job.
+type Gate func() (reason v1.WaitReason, details string)
+
+// WithGate holds the job in the queue until gate lets it through
+func WithGate(gate Gate) StartOpt {
+	return func(opts *startOptions) {
+		opts.Gate = gate
+	}
+}
+
+// gating tells how often jobs held by their gate check again
+type gating struct {
+	// gateInterval overrides defaultGateCheckInterval, e.g. in tests
+	gateInterval time.Duration
+}
+
+func (g *gating) gateCheckInterval() time.Duration {
+	if g.gateInterval > 0 {
+		return g.gateInterval
+	}
+	return defaultGateCheckInterval
+}
+
+// check asks the gate whether a job can start. Jobs without a gate can always start.
+func (g Gate) check() (reason v1.WaitReason, details string) {
+	if g == nil {
+		return v1.WaitReason_WAIT_UNKNOWN, ""
+	}
+	return g()
+}
@@ -0,0 +1,244 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// AnnotationOrphanedSince marks resources of a job whose pod is gone with the time garbage collection noticed
+	AnnotationOrphanedSince = "werft.sh/orphanedSince"
+
+	// defaultGarbageCollectionTTL is the time resources of a job are kept after its pod is gone if the config doesn't say otherwise
+	defaultGarbageCollectionTTL = time.Hour
+
+	// defaultGarbageCollectionInterval is the time between two garbage collection runs if the config doesn't say otherwise
+	defaultGarbageCollectionInterval = 10 * time.Minute
+)
+
+// GarbageCollectionConfig configures the removal of Kubernetes resources jobs leave behind
+type GarbageCollectionConfig struct {
+	// TTL is the time resources of a job are kept after its pod is gone. Defaults to an hour.
+	TTL *Duration `yaml:"ttl,omitempty"`
+
+	// Interval is the time between two garbage collection runs. Defaults to ten minutes.
+	Interval *Duration `yaml:"interval,omitempty"`
+}
+
+func (c *GarbageCollectionConfig) ttl() time.Duration {
+	if c.TTL == nil {
+		return defaultGarbageCollectionTTL
+	}
+	return c.TTL.Duration
+}
+
+func (c *GarbageCollectionConfig) interval() time.Duration {
+	if c.Interval == nil {
+		return defaultGarbageCollectionInterval
+	}
+	return c.Interval.Duration
+}
+
+// jobResource is a Kubernetes resource which belongs to a job, i.e. is labelled with the job's name
+type jobResource struct {
+	Kind        string
+	Name        string
+	Job         string
+	Annotations map[string]string
+}
+
+// jobResourceKind lists, annotates and deletes the resources of one kind which belong to jobs
+type jobResourceKind struct {
+	Kind   string
+	List   func(opts metav1.ListOptions) ([]jobResource, error)
+	Patch  func(name string, patch []byte) error
+	Delete func(name string) error
+}
+
+// jobResourceKinds are the kinds of resources garbage collection looks at. Jobs label the resources they create
+// with LabelJobName, like werft does for the network policies of jobs.
+func (js *Executor) jobResourceKinds() []jobResourceKind {
+	var (
+		ns     = js.Config.Namespace
+		core   = js.Client.CoreV1()
+		netw   = js.Client.NetworkingV1()
+		fromMD = func(kind string, md metav1.ObjectMeta) jobResource {
+			return jobResource{Kind: kind, Name: md.Name, Job: md.Labels[LabelJobName], Annotations: md.Annotations}
+		}
+	)
+	return []jobResourceKind{
+		{
+			Kind: "PersistentVolumeClaim",
+			List: func(opts metav1.ListOptions) (res []jobResource, err error) {
+				l, err := core.PersistentVolumeClaims(ns).List(opts)
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range l.Items {
+					res = append(res, fromMD("PersistentVolumeClaim", r.ObjectMeta))
+				}
+				return res, nil
+			},
+			Patch: func(name string, patch []byte) error {
+				_, err := core.PersistentVolumeClaims(ns).Patch(name, types.MergePatchType, patch)
+				return err
+			},
+			Delete: func(name string) error { return core.PersistentVolumeClaims(ns).Delete(name, &metav1.DeleteOptions{}) },
+		},
+		{
+			Kind: "NetworkPolicy",
+			List: func(opts metav1.ListOptions) (res []jobResource, err error) {
+				l, err := netw.NetworkPolicies(ns).List(opts)
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range l.Items {
+					res = append(res, fromMD("NetworkPolicy", r.ObjectMeta))
+				}
+				return res, nil
+			},
+			Patch: func(name string, patch []byte) error {
+				_, err := netw.NetworkPolicies(ns).Patch(name, types.MergePatchType, patch)
+				return err
+			},
+			Delete: func(name string) error { return netw.NetworkPolicies(ns).Delete(name, &metav1.DeleteOptions{}) },
+		},
+		{
+			Kind: "Secret",
+			List: func(opts metav1.ListOptions) (res []jobResource, err error) {
+				l, err := core.Secrets(ns).List(opts)
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range l.Items {
+					res = append(res, fromMD("Secret", r.ObjectMeta))
+				}
+				return res, nil
+			},
+			Patch: func(name string, patch []byte) error {
+				_, err := core.Secrets(ns).Patch(name, types.MergePatchType, patch)
+				return err
+			},
+			Delete: func(name string) error { return core.Secrets(ns).Delete(name, &metav1.DeleteOptions{}) },
+		},
+		{
+			Kind: "ConfigMap",
+			List: func(opts metav1.ListOptions) (res []jobResource, err error) {
+				l, err := core.ConfigMaps(ns).List(opts)
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range l.Items {
+					res = append(res, fromMD("ConfigMap", r.ObjectMeta))
+				}
+				return res, nil
+			},
+			Patch: func(name string, patch []byte) error {
+				_, err := core.ConfigMaps(ns).Patch(name, types.MergePatchType, patch)
+				return err
+			},
+			Delete: func(name string) error { return core.ConfigMaps(ns).Delete(name, &metav1.DeleteOptions{}) },
+		},
+	}
+}
+
+// collectGarbage periodically removes the resources of jobs whose pod has been gone for longer than the TTL
+func (js *Executor) collectGarbage(stop <-chan struct{}) {
+	cfg := js.Config.GarbageCollection
+	if cfg == nil {
+		return
+	}
+
+	tick := time.NewTicker(cfg.interval())
+	defer tick.Stop()
+	for {
+		err := js.collectGarbageOnce(cfg.ttl(), js.now())
+		if err != nil {
+			log.WithError(err).Warn("cannot collect garbage")
+		}
+
+		select {
+		case <-tick.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// collectGarbageOnce looks at all resources which belong to jobs. Resources of jobs without pod are marked as orphaned
+// first and removed once they were orphaned for longer than the TTL. Resources whose job has a pod again, e.g. because
+// it was just being created, lose their mark.
+func (js *Executor) collectGarbageOnce(ttl time.Duration, now time.Time) error {
+	pods, err := js.listPods(fmt.Sprintf("%s=true", LabelWerftMarker))
+	if err != nil {
+		return xerrors.Errorf("cannot list job pods: %w", err)
+	}
+	alive := make(map[string]struct{}, len(pods))
+	for _, pod := range pods {
+		alive[pod.Labels[LabelJobName]] = struct{}{}
+	}
+	js.mu.RLock()
+	for name := range js.waitingJobs {
+		alive[name] = struct{}{}
+	}
+	js.mu.RUnlock()
+
+	for _, kind := range js.jobResourceKinds() {
+		resources, err := kind.List(metav1.ListOptions{LabelSelector: LabelJobName})
+		if err != nil {
+			return xerrors.Errorf("cannot list %s resources of jobs: %w", kind.Kind, err)
+		}
+
+		for _, r := range resources {
+			if r.Job == "" {
+				continue
+			}
+			logger := log.WithField("kind", r.Kind).WithField("name", r.Name).WithField("job", r.Job)
+			since, orphaned := r.Annotations[AnnotationOrphanedSince]
+			_, hasPod := alive[r.Job]
+
+			var err error
+			switch {
+			case hasPod && orphaned:
+				err = kind.Patch(r.Name, orphanedSincePatch(nil))
+			case hasPod:
+				continue
+			case !orphaned:
+				err = kind.Patch(r.Name, orphanedSincePatch(&now))
+			default:
+				t, perr := time.Parse(time.RFC3339, since)
+				if perr == nil && now.Sub(t) < ttl {
+					continue
+				}
+				err = kind.Delete(r.Name)
+				if err == nil {
+					logger.Info("removed resource of finished job")
+				}
+			}
+			if err != nil {
+				logger.WithError(err).Warn("cannot collect garbage")
+			}
+		}
+	}
+	return nil
+}
+
+// orphanedSincePatch produces a merge patch which sets the orphaned annotation to t, or removes it if t is nil
+func orphanedSincePatch(t *time.Time) []byte {
+	var val interface{}
+	if t != nil {
+		val = t.UTC().Format(time.RFC3339)
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{AnnotationOrphanedSince: val},
+		},
+	})
+	return patch
+}
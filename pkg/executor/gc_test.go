@@ -0,0 +1,153 @@
+package executor
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestCollectGarbageOnce(t *testing.T) {
+	const (
+		ns  = "werft"
+		job = "foo.1"
+		ttl = time.Hour
+	)
+	var (
+		client = fake.NewSimpleClientset(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "foo-cfg", Namespace: ns, Labels: map[string]string{LabelJobName: job}}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "foo-secret", Namespace: ns, Labels: map[string]string{LabelJobName: job}}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: ns}},
+		)
+		js = &Executor{
+			Config:      Config{Namespace: ns},
+			Client:      client,
+			pods:        newPodInformer(client, ns, 0),
+			waitingJobs: make(map[string]*waitingJob),
+		}
+		pod = &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      job,
+			Namespace: ns,
+			Labels:    map[string]string{LabelWerftMarker: "true", LabelJobName: job},
+		}}
+		start = time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+	)
+	client.PrependReactor("patch", "*", mergePatchReactor(client.Tracker()))
+
+	type state int
+	const (
+		unmarked state = iota
+		marked
+		deleted
+	)
+	tests := []struct {
+		Name    string
+		Pod     bool
+		Waiting bool
+		Now     time.Time
+		Exp     state
+		ExpTime time.Time
+	}{
+		{Name: "job still running", Pod: true, Now: start, Exp: unmarked},
+		{Name: "pod gone", Now: start, Exp: marked, ExpTime: start},
+		{Name: "within ttl", Now: start.Add(ttl / 2), Exp: marked, ExpTime: start},
+		{Name: "pod back", Pod: true, Now: start.Add(ttl / 2), Exp: unmarked},
+		{Name: "pod gone again", Now: start.Add(ttl), Exp: marked, ExpTime: start.Add(ttl)},
+		{Name: "job waiting", Waiting: true, Now: start.Add(ttl + time.Minute), Exp: unmarked},
+		{Name: "marked again", Now: start.Add(2 * ttl), Exp: marked, ExpTime: start.Add(2 * ttl)},
+		{Name: "ttl expired", Now: start.Add(3 * ttl), Exp: deleted},
+	}
+	for _, test := range tests {
+		// the steps build on one another, hence they don't run as subtests
+		pods := client.CoreV1().Pods(ns)
+		if test.Pod {
+			if _, err := pods.Get(pod.Name, metav1.GetOptions{}); errors.IsNotFound(err) {
+				_, err = pods.Create(pod)
+				if err != nil {
+					t.Fatalf("%s: cannot create pod: %v", test.Name, err)
+				}
+			}
+		} else {
+			err := pods.Delete(pod.Name, &metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				t.Fatalf("%s: cannot delete pod: %v", test.Name, err)
+			}
+		}
+		delete(js.waitingJobs, job)
+		if test.Waiting {
+			js.waitingJobs[job] = &waitingJob{}
+		}
+
+		err := js.collectGarbageOnce(ttl, test.Now)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.Name, err)
+		}
+
+		cm, cmErr := client.CoreV1().ConfigMaps(ns).Get("foo-cfg", metav1.GetOptions{})
+		sec, secErr := client.CoreV1().Secrets(ns).Get("foo-secret", metav1.GetOptions{})
+		if test.Exp == deleted {
+			if !errors.IsNotFound(cmErr) || !errors.IsNotFound(secErr) {
+				t.Errorf("%s: expected resources to be deleted, got %v and %v", test.Name, cmErr, secErr)
+			}
+			continue
+		}
+		if cmErr != nil || secErr != nil {
+			t.Fatalf("%s: expected resources to exist, got %v and %v", test.Name, cmErr, secErr)
+		}
+		for _, md := range []metav1.ObjectMeta{cm.ObjectMeta, sec.ObjectMeta} {
+			since, ok := md.Annotations[AnnotationOrphanedSince]
+			if test.Exp == unmarked {
+				if ok {
+					t.Errorf("%s: expected %s to be unmarked, got %s", test.Name, md.Name, since)
+				}
+				continue
+			}
+			if exp := test.ExpTime.Format(time.RFC3339); since != exp {
+				t.Errorf("%s: expected %s to be orphaned since %s, got %q", test.Name, md.Name, exp, since)
+			}
+		}
+	}
+
+	if _, err := client.CoreV1().ConfigMaps(ns).Get("unrelated", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected resources of no job to be left alone, got %v", err)
+	}
+}
+
+// mergePatchReactor applies merge patches like the API server does. The fake clientset decodes the patched object into
+// the existing one, which keeps map entries the patch removes, e.g. annotations.
+func mergePatchReactor(tracker ktesting.ObjectTracker) ktesting.ReactionFunc {
+	return func(action ktesting.Action) (bool, runtime.Object, error) {
+		patch := action.(ktesting.PatchAction)
+		if patch.GetPatchType() != types.MergePatchType {
+			return false, nil, nil
+		}
+
+		obj, err := tracker.Get(patch.GetResource(), patch.GetNamespace(), patch.GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		old, err := json.Marshal(obj)
+		if err != nil {
+			return true, nil, err
+		}
+		modified, err := jsonpatch.MergePatch(old, patch.GetPatch())
+		if err != nil {
+			return true, nil, err
+		}
+		res := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(runtime.Object)
+		err = json.Unmarshal(modified, res)
+		if err != nil {
+			return true, nil, err
+		}
+		return true, res, tracker.Update(patch.GetResource(), res, patch.GetNamespace())
+	}
+}
@@ -0,0 +1,93 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podHookTimeout is the time a pod hook gets to respond
+const podHookTimeout = 10 * time.Second
+
+// PodHook takes part in the lifecycle of job pods, e.g. to adjust pods to the environment werft runs in or to register
+// the DNS name of a preview environment. Hooks which don't care about one of the calls return nil.
+type PodHook interface {
+	// Name identifies the hook in logs and errors
+	Name() string
+
+	// PreCreatePod can change the pod of a job before it's created. Returning an error keeps the job from starting.
+	PreCreatePod(ctx context.Context, pod *corev1.Pod) error
+
+	// PostCreatePod is called once the pod of a job was created
+	PostCreatePod(ctx context.Context, pod *corev1.Pod) error
+
+	// PreDeletePod is called before the pod of a job is deleted. Errors don't keep the pod from being deleted.
+	// Should deleting the pod take a while, this can be called more than once for the same pod.
+	PreDeletePod(ctx context.Context, pod *corev1.Pod) error
+}
+
+// podHooks are the pod hooks of an executor
+type podHooks struct {
+	hooks  []PodHook
+	hookMu sync.RWMutex
+}
+
+// AddPodHook adds a hook which is called for all job pods created or deleted from now on
+func (js *podHooks) AddPodHook(h PodHook) {
+	js.hookMu.Lock()
+	defer js.hookMu.Unlock()
+
+	js.hooks = append(js.hooks, h)
+}
+
+func (js *podHooks) getPodHooks() []PodHook {
+	js.hookMu.RLock()
+	defer js.hookMu.RUnlock()
+
+	return js.hooks
+}
+
+// preCreatePod passes a job's pod through all hooks, which may change it, before it's created
+func (js *podHooks) preCreatePod(pod *corev1.Pod) error {
+	for _, h := range js.getPodHooks() {
+		ctx, cancel := context.WithTimeout(context.Background(), podHookTimeout)
+		err := h.PreCreatePod(ctx, pod)
+		cancel()
+		if err != nil {
+			return xerrors.Errorf("hook %s rejected the pod: %w", h.Name(), err)
+		}
+	}
+	return nil
+}
+
+// postCreatePod tells all hooks that a job's pod was created. The hooks are called in the background.
+func (js *podHooks) postCreatePod(pod *corev1.Pod) {
+	for _, h := range js.getPodHooks() {
+		go func(h PodHook) {
+			ctx, cancel := context.WithTimeout(context.Background(), podHookTimeout)
+			defer cancel()
+
+			err := h.PostCreatePod(ctx, pod)
+			if err != nil {
+				log.WithError(err).WithField("name", pod.Name).WithField("hook", h.Name()).Warn("hook failed handling created pod")
+			}
+		}(h)
+	}
+}
+
+// preDeletePod tells all hooks that a job's pod is about to be deleted, e.g. so that they can clean up what they set
+// up for the pod. Unlike the other hooks, the pod is not deleted before all hooks returned.
+func (js *podHooks) preDeletePod(pod *corev1.Pod) {
+	for _, h := range js.getPodHooks() {
+		ctx, cancel := context.WithTimeout(context.Background(), podHookTimeout)
+		err := h.PreDeletePod(ctx, pod)
+		cancel()
+		if err != nil {
+			log.WithError(err).WithField("name", pod.Name).WithField("hook", h.Name()).Warn("hook failed handling pod deletion")
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type testPodHook struct {
+	name   string
+	reject bool
+	fail   bool
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (h *testPodHook) Name() string { return h.name }
+
+func (h *testPodHook) record(call string, pod *corev1.Pod) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = append(h.calls, call+":"+pod.Name)
+}
+
+func (h *testPodHook) getCalls() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return strings.Join(h.calls, ",")
+}
+
+func (h *testPodHook) PreCreatePod(ctx context.Context, pod *corev1.Pod) error {
+	h.record("pre-create", pod)
+	if h.reject {
+		return fmt.Errorf("not today")
+	}
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels["hooks"] += h.name
+	return nil
+}
+
+func (h *testPodHook) PostCreatePod(ctx context.Context, pod *corev1.Pod) error {
+	h.record("post-create", pod)
+	return nil
+}
+
+func (h *testPodHook) PreDeletePod(ctx context.Context, pod *corev1.Pod) error {
+	h.record("pre-delete", pod)
+	if h.fail {
+		return fmt.Errorf("cannot clean up")
+	}
+	return nil
+}
+
+func TestPreCreatePod(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Hooks  []*testPodHook
+		Labels string
+		Error  string
+		Calls  []string
+	}{
+		{Name: "no hooks"},
+		{
+			Name:   "hooks change the pod in order",
+			Hooks:  []*testPodHook{{name: "a"}, {name: "b"}},
+			Labels: "ab",
+			Calls:  []string{"pre-create:job", "pre-create:job"},
+		},
+		{
+			Name:  "rejection stops the remaining hooks",
+			Hooks: []*testPodHook{{name: "a", reject: true}, {name: "b"}},
+			Error: "hook a rejected the pod: not today",
+			Calls: []string{"pre-create:job", ""},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var hooks podHooks
+			for _, h := range test.Hooks {
+				hooks.AddPodHook(h)
+			}
+			pod := &corev1.Pod{}
+			pod.Name = "job"
+			err := hooks.preCreatePod(pod)
+			if test.Error == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if test.Error != "" && (err == nil || err.Error() != test.Error) {
+				t.Errorf("expected error %q, got %v", test.Error, err)
+			}
+			if act := pod.Labels["hooks"]; act != test.Labels {
+				t.Errorf("expected hooks %q to change the pod, got %q", test.Labels, act)
+			}
+			for i, h := range test.Hooks {
+				if act := h.getCalls(); act != test.Calls[i] {
+					t.Errorf("expected hook %s to be called %q, got %q", h.name, test.Calls[i], act)
+				}
+			}
+		})
+	}
+}
+
+func TestPreDeletePod(t *testing.T) {
+	a := &testPodHook{name: "a", fail: true}
+	b := &testPodHook{name: "b"}
+	var hooks podHooks
+	hooks.AddPodHook(a)
+	hooks.AddPodHook(b)
+
+	pod := &corev1.Pod{}
+	pod.Name = "job"
+	hooks.preDeletePod(pod)
+	if act := a.getCalls() + "|" + b.getCalls(); act != "pre-delete:job|pre-delete:job" {
+		t.Errorf("expected all hooks to be called despite failures, got %q", act)
+	}
+}
+
+func TestPostCreatePod(t *testing.T) {
+	a := &testPodHook{name: "a"}
+	b := &testPodHook{name: "b"}
+	var hooks podHooks
+	hooks.AddPodHook(a)
+	hooks.AddPodHook(b)
+
+	pod := &corev1.Pod{}
+	pod.Name = "job"
+	hooks.postCreatePod(pod)
+
+	// the hooks are called in the background
+	deadline := time.Now().Add(5 * time.Second)
+	for a.getCalls() == "" || b.getCalls() == "" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected all hooks to be called, got %q and %q", a.getCalls(), b.getCalls())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if act := a.getCalls() + "|" + b.getCalls(); act != "post-create:job|post-create:job" {
+		t.Errorf("unexpected calls %q", act)
+	}
+}
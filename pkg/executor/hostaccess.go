@@ -0,0 +1,77 @@
+package executor
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AnnotationTrustedHostPaths lists the host paths werft mounts into the pod of a job itself, e.g. the workspace,
+// one per line. The docker and nomad executors let jobs mount those no matter which host paths they allow.
+const AnnotationTrustedHostPaths = "werft.sh/trustedHostPaths"
+
+// WithTrustedHostPaths marks host paths werft mounts into the pod of a job itself, e.g. the workspace
+func WithTrustedHostPaths(paths ...string) StartOpt {
+	return func(opts *startOptions) {
+		opts.TrustedHostPaths = append(opts.TrustedHostPaths, paths...)
+	}
+}
+
+// checkHostAccess makes sure a pod which runs directly on a host only mounts the host paths werft added itself or
+// the config allows, and has no privileged containers unless they're allowed. Jobs run arbitrary code, e.g. that of
+// pull requests - a job which mounts the docker socket or / takes over the host.
+func checkHostAccess(pod *corev1.Pod, allowedHostPaths []string, allowPrivileged bool, backend string) error {
+	var trusted []string
+	if pod.Annotations[AnnotationTrustedHostPaths] != "" {
+		trusted = strings.Split(pod.Annotations[AnnotationTrustedHostPaths], "\n")
+	}
+	for _, v := range pod.Spec.Volumes {
+		if v.HostPath == nil {
+			continue
+		}
+		if !hostPathAllowed(v.HostPath.Path, allowedHostPaths, trusted) {
+			return xerrors.Errorf("volume %s: host path %s is not allowed by the %s executor (allowed are %s)", v.Name, v.HostPath.Path, backend, strings.Join(allowedHostPaths, ", "))
+		}
+	}
+
+	for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		for _, m := range c.VolumeMounts {
+			if m.SubPath == "" {
+				continue
+			}
+			if filepath.IsAbs(m.SubPath) || strings.HasPrefix(filepath.Clean(m.SubPath), "..") {
+				return xerrors.Errorf("container %s: subPath %s must be relative to its volume", c.Name, m.SubPath)
+			}
+		}
+		if allowPrivileged {
+			continue
+		}
+		if sc := c.SecurityContext; sc != nil && sc.Privileged != nil && *sc.Privileged {
+			return xerrors.Errorf("container %s: privileged containers are not allowed by the %s executor", c.Name, backend)
+		}
+	}
+	return nil
+}
+
+// hostPathAllowed returns true if path is one of the trusted paths, or one of the allowed paths or below it
+func hostPathAllowed(path string, allowed, trusted []string) bool {
+	for _, t := range trusted {
+		if path == t {
+			return true
+		}
+	}
+
+	path = filepath.Clean(path)
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	for _, a := range allowed {
+		a = filepath.Clean(a)
+		if path == a || strings.HasPrefix(path, strings.TrimSuffix(a, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
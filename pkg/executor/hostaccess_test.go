@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func hostPathPod(trusted string, path string, mount corev1.VolumeMount, privileged bool) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job", Annotations: map[string]string{}},
+		Spec: corev1.PodSpec{
+			Volumes: []corev1.Volume{
+				{Name: "ws", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+			},
+			Containers: []corev1.Container{
+				{Name: "build", Image: "alpine", VolumeMounts: []corev1.VolumeMount{mount}},
+			},
+		},
+	}
+	if trusted != "" {
+		pod.Annotations[AnnotationTrustedHostPaths] = trusted
+	}
+	if path != "" {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{Name: "host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: path}}})
+	}
+	if privileged {
+		pod.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+	}
+	return pod
+}
+
+func TestCheckHostAccess(t *testing.T) {
+	ws := corev1.VolumeMount{Name: "ws", MountPath: "/workspace"}
+	tests := []struct {
+		Name            string
+		Pod             *corev1.Pod
+		AllowedPaths    []string
+		AllowPrivileged bool
+		Valid           bool
+	}{
+		{"no host paths", hostPathPod("", "", ws, false), nil, false, true},
+		{"host path not allowed", hostPathPod("", "/var/run/docker.sock", ws, false), nil, false, false},
+		{"root not allowed", hostPathPod("", "/", ws, false), []string{"/cache"}, false, false},
+		{"trusted host path", hostPathPod("/mnt/werft/job", "/mnt/werft/job", ws, false), nil, false, true},
+		{"below trusted host path", hostPathPod("/mnt/werft/job", "/mnt/werft/job/../..", ws, false), nil, false, false},
+		{"allowed host path", hostPathPod("", "/cache", ws, false), []string{"/cache"}, false, true},
+		{"below allowed host path", hostPathPod("", "/cache/go", ws, false), []string{"/cache/"}, false, true},
+		{"sibling of allowed host path", hostPathPod("", "/cache-other", ws, false), []string{"/cache"}, false, false},
+		{"escaping allowed host path", hostPathPod("", "/cache/../etc", ws, false), []string{"/cache"}, false, false},
+		{"relative host path", hostPathPod("", "cache", ws, false), []string{"/cache"}, false, false},
+		{"relative subPath", hostPathPod("", "", corev1.VolumeMount{Name: "ws", MountPath: "/w", SubPath: "src/app"}, false), nil, false, true},
+		{"escaping subPath", hostPathPod("", "", corev1.VolumeMount{Name: "ws", MountPath: "/w", SubPath: "../../etc"}, false), nil, false, false},
+		{"absolute subPath", hostPathPod("", "", corev1.VolumeMount{Name: "ws", MountPath: "/w", SubPath: "/etc"}, false), nil, false, false},
+		{"privileged not allowed", hostPathPod("", "", ws, true), nil, false, false},
+		{"privileged allowed", hostPathPod("", "", ws, true), nil, true, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := checkHostAccess(test.Pod, test.AllowedPaths, test.AllowPrivileged, "test")
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected the pod to be rejected")
+			}
+		})
+	}
+}
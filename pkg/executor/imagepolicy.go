@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// ImagePolicyConfig restricts the images the containers of jobs can use, e.g. to the registries of an organisation
+type ImagePolicyConfig struct {
+	// Allowed are patterns of the images job containers can use, e.g. gcr.io/my-project/* or docker.io/library/golang:*.
+	// Images are matched in their fully qualified form, i.e. golang:1.14 is docker.io/library/golang:1.14, and *
+	// matches any sequence of characters. If this is empty, all images are allowed.
+	Allowed []string `yaml:"allowed,omitempty"`
+
+	// RequireDigest names the triggers of jobs whose containers must pin their images by digest (e.g.
+	// golang@sha256:...), e.g. pull_request for jobs running untrusted code
+	RequireDigest []string `yaml:"requireDigest,omitempty"`
+
+	allowed []*regexp.Regexp
+}
+
+// Validate checks the image policy's patterns and triggers
+func (c *ImagePolicyConfig) Validate() error {
+	allowed := make([]*regexp.Regexp, 0, len(c.Allowed))
+	for _, p := range c.Allowed {
+		if p == "" {
+			return xerrors.Errorf("image policy: allowed images must not be empty")
+		}
+		expr, err := regexp.Compile("^" + strings.ReplaceAll(regexp.QuoteMeta(p), `\*`, ".*") + "$")
+		if err != nil {
+			return xerrors.Errorf("image policy: invalid pattern %s: %w", p, err)
+		}
+		allowed = append(allowed, expr)
+	}
+	for _, t := range c.RequireDigest {
+		if _, ok := v1.JobTrigger_value["TRIGGER_"+strings.ToUpper(t)]; !ok {
+			return xerrors.Errorf("image policy: unknown trigger %s", t)
+		}
+	}
+	c.allowed = allowed
+	return nil
+}
+
+// WithTrustedContainers exempts containers werft adds to the pod of a job itself, e.g. the checkout container,
+// from the image policy. Only containers which are exactly the ones werft added are exempt, so that jobs cannot
+// escape the policy by naming their containers like werft's.
+func WithTrustedContainers(containers ...corev1.Container) StartOpt {
+	return func(opts *startOptions) {
+		opts.TrustedContainers = append(opts.TrustedContainers, containers...)
+	}
+}
+
+// check returns an error listing all containers of a job whose images violate the policy
+func (c *ImagePolicyConfig) check(spec *corev1.PodSpec, trigger v1.JobTrigger, trusted []corev1.Container) error {
+	if c == nil {
+		return nil
+	}
+	if len(c.allowed) != len(c.Allowed) {
+		return xerrors.Errorf("image policy: policy was not validated")
+	}
+
+	triggerName := repoconfig.TriggerName(trigger)
+	var requireDigest bool
+	for _, t := range c.RequireDigest {
+		if t == triggerName {
+			requireDigest = true
+			break
+		}
+	}
+
+	var violations []string
+	for _, cs := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+	nextContainer:
+		for _, ctnr := range cs {
+			for _, tc := range trusted {
+				if equality.Semantic.DeepEqual(ctnr, tc) {
+					continue nextContainer
+				}
+			}
+
+			image := qualifiedImage(ctnr.Image)
+			if !c.allows(image) {
+				violations = append(violations, fmt.Sprintf("container %s uses %s, which is not an allowed image (allowed are %s)", ctnr.Name, image, strings.Join(c.Allowed, ", ")))
+			}
+			if requireDigest && !strings.Contains(ctnr.Image, "@") {
+				violations = append(violations, fmt.Sprintf("container %s must pin its image %s by digest (image@sha256:...) in %s jobs", ctnr.Name, ctnr.Image, triggerName))
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return xerrors.Errorf("image policy: %s", strings.Join(violations, "; "))
+	}
+	return nil
+}
+
+// allows returns true if an image matches one of the allowed patterns
+func (c *ImagePolicyConfig) allows(image string) bool {
+	if len(c.Allowed) == 0 {
+		return true
+	}
+	for _, expr := range c.allowed {
+		if expr.MatchString(image) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedImage returns the fully qualified form of an image reference, e.g. docker.io/library/golang:latest for golang
+func qualifiedImage(image string) string {
+	name, digest := image, ""
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, digest = name[:i], name[i:]
+	}
+	tag := ""
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, tag = name[:i], name[i:]
+	} else if digest == "" {
+		tag = ":latest"
+	}
+
+	segments := strings.SplitN(name, "/", 2)
+	if len(segments) == 1 {
+		name = "docker.io/library/" + name
+	} else if !strings.ContainsAny(segments[0], ".:") && segments[0] != "localhost" {
+		name = "docker.io/" + name
+	}
+	return name + tag + digest
+}
@@ -0,0 +1,191 @@
+package executor
+
+import (
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestQualifiedImage(t *testing.T) {
+	tests := []struct {
+		Image    string
+		Expected string
+	}{
+		{"golang", "docker.io/library/golang:latest"},
+		{"golang:1.14", "docker.io/library/golang:1.14"},
+		{"csweichel/werft", "docker.io/csweichel/werft:latest"},
+		{"docker.io/library/golang:1.14", "docker.io/library/golang:1.14"},
+		{"gcr.io/my-project/builder:v1", "gcr.io/my-project/builder:v1"},
+		{"golang@sha256:abc", "docker.io/library/golang@sha256:abc"},
+		{"golang:1.14@sha256:abc", "docker.io/library/golang:1.14@sha256:abc"},
+		{"registry:5000/builder", "registry:5000/builder:latest"},
+		{"registry:5000/builder:v1", "registry:5000/builder:v1"},
+		{"localhost/builder", "localhost/builder:latest"},
+		{"localhost:5000/builder@sha256:abc", "localhost:5000/builder@sha256:abc"},
+	}
+	for _, test := range tests {
+		t.Run(test.Image, func(t *testing.T) {
+			act := qualifiedImage(test.Image)
+			if act != test.Expected {
+				t.Errorf("unexpected qualified image: got %s, want %s", act, test.Expected)
+			}
+		})
+	}
+}
+
+func TestImagePolicyAllows(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Allowed  []string
+		Image    string
+		Expected bool
+	}{
+		{"no patterns", nil, "docker.io/library/golang:latest", true},
+		{"exact", []string{"docker.io/library/golang:1.14"}, "docker.io/library/golang:1.14", true},
+		{"wildcard tag", []string{"docker.io/library/golang:*"}, "docker.io/library/golang:1.14", true},
+		{"wildcard registry", []string{"gcr.io/my-project/*"}, "gcr.io/my-project/builder:v1", true},
+		{"other registry", []string{"gcr.io/my-project/*"}, "gcr.io/other-project/builder:v1", false},
+		{"no prefix match", []string{"golang:*"}, "docker.io/library/golang:1.14", false},
+		{"dot is literal", []string{"gcr.io/*"}, "gcrxio/builder:v1", false},
+		{"second pattern", []string{"gcr.io/*", "docker.io/library/*"}, "docker.io/library/alpine:latest", true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			c := &ImagePolicyConfig{Allowed: test.Allowed}
+			err := c.Validate()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if act := c.allows(test.Image); act != test.Expected {
+				t.Errorf("unexpected result for %s: got %v, want %v", test.Image, act, test.Expected)
+			}
+		})
+	}
+}
+
+func TestImagePolicyCheck(t *testing.T) {
+	var (
+		checkout = corev1.Container{Name: "werft-checkout", Image: "csweichel/werft-checkout:latest"}
+		golang   = corev1.Container{Name: "build", Image: "golang:1.14"}
+		pinned   = corev1.Container{Name: "build", Image: "golang@sha256:abc"}
+		evil     = corev1.Container{Name: "werft-checkout", Image: "evil/image"}
+	)
+	tests := []struct {
+		Name       string
+		Policy     *ImagePolicyConfig
+		Spec       corev1.PodSpec
+		Trigger    v1.JobTrigger
+		Trusted    []corev1.Container
+		Violations []string
+	}{
+		{
+			Name: "no policy",
+			Spec: corev1.PodSpec{Containers: []corev1.Container{evil}},
+		},
+		{
+			Name:   "allowed",
+			Policy: &ImagePolicyConfig{Allowed: []string{"docker.io/library/*"}},
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{golang}},
+		},
+		{
+			Name:       "not allowed",
+			Policy:     &ImagePolicyConfig{Allowed: []string{"gcr.io/*"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{golang}},
+			Violations: []string{"container build uses docker.io/library/golang:1.14"},
+		},
+		{
+			Name:       "init containers",
+			Policy:     &ImagePolicyConfig{Allowed: []string{"docker.io/library/*"}},
+			Spec:       corev1.PodSpec{InitContainers: []corev1.Container{evil}, Containers: []corev1.Container{golang}},
+			Violations: []string{"container werft-checkout uses docker.io/evil/image:latest"},
+		},
+		{
+			Name:    "trusted",
+			Policy:  &ImagePolicyConfig{Allowed: []string{"docker.io/library/*"}},
+			Spec:    corev1.PodSpec{InitContainers: []corev1.Container{checkout}, Containers: []corev1.Container{golang}},
+			Trusted: []corev1.Container{checkout},
+		},
+		{
+			Name:       "named like trusted",
+			Policy:     &ImagePolicyConfig{Allowed: []string{"docker.io/library/*"}},
+			Spec:       corev1.PodSpec{InitContainers: []corev1.Container{checkout, evil}},
+			Trusted:    []corev1.Container{checkout},
+			Violations: []string{"container werft-checkout uses docker.io/evil/image:latest"},
+		},
+		{
+			Name:       "digest required",
+			Policy:     &ImagePolicyConfig{RequireDigest: []string{"pull_request"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{golang}},
+			Trigger:    v1.JobTrigger_TRIGGER_PULL_REQUEST,
+			Violations: []string{"container build must pin its image golang:1.14 by digest"},
+		},
+		{
+			Name:    "digest pinned",
+			Policy:  &ImagePolicyConfig{RequireDigest: []string{"pull_request"}},
+			Spec:    corev1.PodSpec{Containers: []corev1.Container{pinned}},
+			Trigger: v1.JobTrigger_TRIGGER_PULL_REQUEST,
+		},
+		{
+			Name:    "digest not required for trigger",
+			Policy:  &ImagePolicyConfig{RequireDigest: []string{"pull_request"}},
+			Spec:    corev1.PodSpec{Containers: []corev1.Container{golang}},
+			Trigger: v1.JobTrigger_TRIGGER_PUSH,
+		},
+		{
+			Name:       "both violations",
+			Policy:     &ImagePolicyConfig{Allowed: []string{"gcr.io/*"}, RequireDigest: []string{"push"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{golang}},
+			Trigger:    v1.JobTrigger_TRIGGER_PUSH,
+			Violations: []string{"not an allowed image", "must pin its image"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if test.Policy != nil {
+				err := test.Policy.Validate()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			err := test.Policy.check(&test.Spec, test.Trigger, test.Trusted)
+			if len(test.Violations) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error")
+			}
+			for _, v := range test.Violations {
+				if !strings.Contains(err.Error(), v) {
+					t.Errorf("expected error to contain %q, got %v", v, err)
+				}
+			}
+		})
+	}
+}
+
+func TestImagePolicyValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Policy ImagePolicyConfig
+		Error  bool
+	}{
+		{"empty", ImagePolicyConfig{}, false},
+		{"empty pattern", ImagePolicyConfig{Allowed: []string{""}}, true},
+		{"known trigger", ImagePolicyConfig{RequireDigest: []string{"pull_request"}}, false},
+		{"unknown trigger", ImagePolicyConfig{RequireDigest: []string{"nightly"}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Policy.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("expected error %v, got %v", test.Error, err)
+			}
+		})
+	}
+}
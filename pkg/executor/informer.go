@@ -0,0 +1,186 @@
+package executor
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// defaultResyncInterval is the time after which all job pods are processed again, even if they haven't changed.
+// This makes sure we don't miss job state, e.g. timeouts, if an event got lost.
+const defaultResyncInterval = 5 * time.Minute
+
+// InformerStats describes the state of the pod cache and work queue of the executor
+type InformerStats struct {
+	// Synced is true once the pod cache was filled initially
+	Synced bool
+	// CachedPods is the number of job pods in the cache
+	CachedPods int
+	// QueueDepth is the number of job pods waiting to be processed
+	QueueDepth int
+	// Events is the number of pod events the executor has seen
+	Events uint64
+}
+
+// podInformer keeps a cache of all job pods and queues those which changed for processing.
+// Several changes to a pod which happen while it waits in the queue are processed only once.
+type podInformer struct {
+	factory  informers.SharedInformerFactory
+	informer cache.SharedIndexInformer
+	lister   corelisters.PodLister
+	queue    workqueue.RateLimitingInterface
+
+	// deleted holds pods which are gone from the cache, but still need processing
+	deleted   map[string]*corev1.Pod
+	deletedMu sync.Mutex
+
+	events uint64
+}
+
+func newPodInformer(client kubernetes.Interface, namespace string, resync time.Duration) *podInformer {
+	factory := informers.NewSharedInformerFactoryWithOptions(client, resync,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = fmt.Sprintf("%s=true", LabelWerftMarker)
+		}),
+	)
+	pods := factory.Core().V1().Pods()
+
+	pi := &podInformer{
+		factory:  factory,
+		informer: pods.Informer(),
+		lister:   pods.Lister(),
+		queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "werft-jobs"),
+		deleted:  make(map[string]*corev1.Pod),
+	}
+	pi.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    pi.enqueue,
+		UpdateFunc: func(old, obj interface{}) { pi.enqueue(obj) },
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				return
+			}
+			key, err := cache.MetaNamespaceKeyFunc(pod)
+			if err != nil {
+				return
+			}
+
+			pi.deletedMu.Lock()
+			pi.deleted[key] = pod
+			pi.deletedMu.Unlock()
+			pi.enqueue(pod)
+		},
+	})
+	return pi
+}
+
+func (pi *podInformer) enqueue(obj interface{}) {
+	atomic.AddUint64(&pi.events, 1)
+
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.WithError(err).Warn("cannot queue job pod")
+		return
+	}
+	pi.queue.Add(key)
+}
+
+// Synced returns true once the cache was filled initially. Until then the cache must not be used.
+func (pi *podInformer) Synced() bool {
+	return pi.informer.HasSynced()
+}
+
+// Stats returns the current state of the informer
+func (pi *podInformer) Stats() InformerStats {
+	return InformerStats{
+		Synced:     pi.Synced(),
+		CachedPods: len(pi.informer.GetStore().ListKeys()),
+		QueueDepth: pi.queue.Len(),
+		Events:     atomic.LoadUint64(&pi.events),
+	}
+}
+
+// run fills the cache and processes queued job pods until stop is closed
+func (pi *podInformer) run(stop <-chan struct{}, handler func(evt watch.EventType, pod *corev1.Pod)) {
+	defer pi.queue.ShutDown()
+
+	pi.factory.Start(stop)
+	if !cache.WaitForCacheSync(stop, pi.informer.HasSynced) {
+		log.Error("cannot sync job pod cache")
+		return
+	}
+	log.Info("connected to Kubernetes master")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for pi.processNext(handler) {
+		}
+	}()
+	<-stop
+
+	// let the handler finish the events which are queued already
+	pi.queue.ShutDown()
+	<-done
+}
+
+func (pi *podInformer) processNext(handler func(evt watch.EventType, pod *corev1.Pod)) bool {
+	item, quit := pi.queue.Get()
+	if quit {
+		return false
+	}
+	defer pi.queue.Done(item)
+	key := item.(string)
+
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		pi.queue.Forget(item)
+		return true
+	}
+	pod, err := pi.lister.Pods(ns).Get(name)
+	if err == nil {
+		handler(watch.Modified, pod.DeepCopy())
+		pi.queue.Forget(item)
+		return true
+	}
+
+	pi.deletedMu.Lock()
+	pod, ok := pi.deleted[key]
+	delete(pi.deleted, key)
+	pi.deletedMu.Unlock()
+	if ok {
+		handler(watch.Deleted, pod)
+	}
+	pi.queue.Forget(item)
+	return true
+}
+
+// listPods lists the job pods in the cache which match selector
+func (pi *podInformer) listPods(namespace string, selector labels.Selector) ([]corev1.Pod, error) {
+	pods, err := pi.lister.Pods(namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]corev1.Pod, len(pods))
+	for i, p := range pods {
+		res[i] = *p.DeepCopy()
+	}
+	return res, nil
+}
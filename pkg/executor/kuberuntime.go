@@ -0,0 +1,175 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+// kubeRuntime runs pods in a Kubernetes namespace. Unlike the Kubernetes executor it runs one pod at a time for
+// a runtime executor, i.e. for agents.
+type kubeRuntime struct {
+	Clientset kubernetes.Interface
+	Namespace string
+}
+
+// Name returns kubernetes
+func (k *kubeRuntime) Name() string {
+	return "kubernetes"
+}
+
+// CheckPod accepts all pods - Kubernetes runs them all
+func (k *kubeRuntime) CheckPod(pod *corev1.Pod) error {
+	return nil
+}
+
+// RemoveAll deletes the job pods in the namespace
+func (k *kubeRuntime) RemoveAll() error {
+	return k.Clientset.CoreV1().Pods(k.Namespace).DeleteCollection(&metav1.DeleteOptions{}, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=true", LabelWerftMarker),
+	})
+}
+
+// RunPod creates the pod and watches it until all its containers stopped or the pod is gone
+func (k *kubeRuntime) RunPod(pod *corev1.Pod, r podReporter) error {
+	if r.Stopped() {
+		return nil
+	}
+
+	p := pod.DeepCopy()
+	p.Namespace = k.Namespace
+	p.ResourceVersion = ""
+	p.UID = ""
+	p.CreationTimestamp = metav1.Time{}
+	p.Status = corev1.PodStatus{}
+	// werft reports what happened to the containers as they are - they must not restart
+	p.Spec.RestartPolicy = corev1.RestartPolicyNever
+	_, err := k.Clientset.CoreV1().Pods(k.Namespace).Create(p)
+	if err != nil {
+		return xerrors.Errorf("cannot create pod: %w", err)
+	}
+
+	var (
+		initDone bool
+		started  = make(map[string]bool)
+		stopped  = make(map[string]bool)
+	)
+	for {
+		w, err := k.Clientset.CoreV1().Pods(k.Namespace).Watch(metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("metadata.name", pod.Name).String(),
+		})
+		if err != nil {
+			return xerrors.Errorf("cannot watch pod: %w", err)
+		}
+
+		for e := range w.ResultChan() {
+			if e.Type == watch.Deleted {
+				w.Stop()
+				return nil
+			}
+			obj, ok := e.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+
+			for _, cs := range obj.Status.InitContainerStatuses {
+				reportKubeContainer(r, obj.Name, cs, true, started, stopped)
+			}
+			for _, cs := range obj.Status.ContainerStatuses {
+				if !initDone && (cs.State.Running != nil || cs.State.Terminated != nil) {
+					initDone = true
+					r.InitDone()
+				}
+				reportKubeContainer(r, obj.Name, cs, false, started, stopped)
+			}
+
+			if obj.Status.Phase == corev1.PodSucceeded || obj.Status.Phase == corev1.PodFailed {
+				w.Stop()
+				if obj.Status.Phase == corev1.PodFailed && len(stopped) == 0 {
+					// the pod failed before its containers ran, e.g. because it was evicted
+					return xerrors.Errorf("pod failed: %s %s", obj.Status.Reason, obj.Status.Message)
+				}
+				return nil
+			}
+		}
+		// the watch timed out - watch again
+	}
+}
+
+// reportKubeContainer tells r about a container which started or stopped since we last looked
+func reportKubeContainer(r podReporter, pod string, cs corev1.ContainerStatus, init bool, started, stopped map[string]bool) {
+	if !started[cs.Name] && (cs.State.Running != nil || cs.State.Terminated != nil) {
+		started[cs.Name] = true
+		r.ContainerStarted(cs.Name, pod+"/"+cs.Name, init)
+	}
+	if !stopped[cs.Name] && cs.State.Terminated != nil {
+		stopped[cs.Name] = true
+		r.ContainerStopped(cs.Name, *cs.State.Terminated)
+	}
+}
+
+// StopPod deletes the pod, giving its containers the grace period to stop
+func (k *kubeRuntime) StopPod(pod *corev1.Pod, containers map[string]string, gracePeriod time.Duration) {
+	gp := int64(gracePeriod.Seconds())
+	err := k.Clientset.CoreV1().Pods(k.Namespace).Delete(pod.Name, &metav1.DeleteOptions{GracePeriodSeconds: &gp})
+	if err != nil && !errors.IsNotFound(err) {
+		log.WithError(err).WithField("name", pod.Name).Warn("cannot stop pod")
+	}
+}
+
+// RemovePod deletes the pod unless it's gone already
+func (k *kubeRuntime) RemovePod(pod *corev1.Pod, containers map[string]string) error {
+	err := k.Clientset.CoreV1().Pods(k.Namespace).Delete(pod.Name, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return xerrors.Errorf("cannot delete pod: %w", err)
+	}
+	return nil
+}
+
+// splitKubeContainerID splits the IDs we give containers into pod and container name
+func splitKubeContainerID(id string) (pod, container string, err error) {
+	segs := strings.SplitN(id, "/", 2)
+	if len(segs) != 2 {
+		return "", "", xerrors.Errorf("invalid container ID %s", id)
+	}
+	return segs[0], segs[1], nil
+}
+
+// Logs streams the output of a container
+func (k *kubeRuntime) Logs(id string) (io.ReadCloser, error) {
+	pod, container, err := splitKubeContainerID(id)
+	if err != nil {
+		return nil, err
+	}
+	return k.Clientset.CoreV1().Pods(k.Namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container: container,
+		Follow:    true,
+	}).Stream()
+}
+
+// ContainerLogs returns the output of a container
+func (k *kubeRuntime) ContainerLogs(id string, limit int64) ([]byte, error) {
+	pod, container, err := splitKubeContainerID(id)
+	if err != nil {
+		return nil, err
+	}
+	return k.Clientset.CoreV1().Pods(k.Namespace).GetLogs(pod, &corev1.PodLogOptions{
+		Container:  container,
+		LimitBytes: &limit,
+	}).DoRaw()
+}
+
+// Exec is not supported: agents don't relay interactive sessions
+func (k *kubeRuntime) Exec(id string, opts AttachOptions) error {
+	return xerrors.Errorf("the kubernetes runtime cannot attach to containers")
+}
@@ -0,0 +1,39 @@
+package executor
+
+import (
+	"sync"
+)
+
+// lifecycle runs the background work of a backend, e.g. watching jobs, until the backend is closed
+type lifecycle struct {
+	once     sync.Once
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+func (l *lifecycle) stopChan() chan struct{} {
+	l.once.Do(func() {
+		l.stop = make(chan struct{})
+	})
+	return l.stop
+}
+
+// goBackground runs f in the background until the backend is closed. f must return once stop is closed.
+func (l *lifecycle) goBackground(f func(stop <-chan struct{})) {
+	stop := l.stopChan()
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		f(stop)
+	}()
+}
+
+// Close ends the background work of the backend and waits for it. Jobs keep running.
+func (l *lifecycle) Close() {
+	stop := l.stopChan()
+	l.stopOnce.Do(func() {
+		close(stop)
+	})
+	l.wg.Wait()
+}
@@ -0,0 +1,37 @@
+package executor
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLifecycleClose(t *testing.T) {
+	var (
+		l       lifecycle
+		stopped int32
+	)
+	for i := 0; i < 3; i++ {
+		l.goBackground(func(stop <-chan struct{}) {
+			<-stop
+			atomic.AddInt32(&stopped, 1)
+		})
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		l.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not return")
+	}
+	if n := atomic.LoadInt32(&stopped); n != 3 {
+		t.Errorf("unexpected number of stopped workers: %d, expected 3", n)
+	}
+
+	// closing twice must not panic
+	l.Close()
+}
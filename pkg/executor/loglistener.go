@@ -2,11 +2,13 @@ package executor
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"sync"
 	"time"
 
+	"github.com/32leaves/werft/pkg/logsanitize"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,6 +16,10 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
+// LogChunkSize is the length of the longest line we forward from a container's log. Longer lines are split
+// into several lines, so that a container which never ends a line can't make us buffer all of its output.
+const LogChunkSize = 16 * 1024
+
 type logListener struct {
 	Clientset kubernetes.Interface
 	Job       string
@@ -22,6 +28,7 @@ type logListener struct {
 	listener map[string]io.Closer
 	started  time.Time
 	closed   bool
+	done     chan struct{}
 	mu       sync.RWMutex
 
 	out  io.Reader
@@ -29,19 +36,33 @@ type logListener struct {
 	inmu sync.Mutex
 }
 
-// Listen establishes a log listener for a job
-func listenToLogs(client kubernetes.Interface, job, namespace string) io.Reader {
+// Listen establishes a log listener for a job. Closing the reader stops listening.
+func listenToLogs(client kubernetes.Interface, job, namespace string) io.ReadCloser {
 	ll := &logListener{
 		Clientset: client,
 		Job:       job,
 		Namespace: namespace,
 		started:   time.Now(),
 		listener:  make(map[string]io.Closer),
+		done:      make(chan struct{}),
 	}
 	ll.out, ll.in = io.Pipe()
 	go ll.Start()
 
-	return ll.out
+	return &logReader{ll}
+}
+
+// logReader reads the log of a job from a log listener
+type logReader struct {
+	ll *logListener
+}
+
+func (r *logReader) Read(p []byte) (int, error) {
+	return r.ll.out.Read(p)
+}
+
+func (r *logReader) Close() error {
+	return r.ll.Close()
 }
 
 func (ll *logListener) Close() error {
@@ -58,6 +79,7 @@ func (ll *logListener) Close() error {
 	}
 
 	ll.closed = true
+	close(ll.done)
 	ll.inmu.Lock()
 	defer ll.inmu.Unlock()
 
@@ -76,7 +98,12 @@ func (ll *logListener) Start() {
 	defer podwatch.Stop()
 
 	for {
-		e := <-podwatch.ResultChan()
+		var e watch.Event
+		select {
+		case e = <-podwatch.ResultChan():
+		case <-ll.done:
+			return
+		}
 		if e.Object == nil {
 			// Closed because of error
 			return
@@ -93,10 +120,18 @@ func (ll *logListener) Start() {
 			statuses = append(statuses, pod.Status.InitContainerStatuses...)
 			statuses = append(statuses, pod.Status.ContainerStatuses...)
 
+			sliced := applicationContainers(pod) > 1
 			for _, c := range statuses {
-				if c.State.Running != nil {
-					go ll.tail(pod.Name, c.Name)
+				if c.State.Running == nil {
+					continue
 				}
+
+				// the output of several containers running at the same time goes to slices of each container
+				var slice string
+				if sliced && !isInitContainer(pod, c.Name) {
+					slice = c.Name
+				}
+				go ll.tail(pod.Name, c.Name, slice)
 			}
 		case watch.Deleted:
 			var statuses []corev1.ContainerStatus
@@ -112,7 +147,53 @@ func (ll *logListener) Start() {
 	}
 }
 
-func (ll *logListener) tail(pod, container string) {
+// applicationContainers counts the containers of a pod which run at the same time as the job, i.e. all but the
+// init containers and the debug container
+func applicationContainers(pod *corev1.Pod) (n int) {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == DebugContainerName {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+func isInitContainer(pod *corev1.Pod, container string) bool {
+	for _, c := range pod.Spec.InitContainers {
+		if c.Name == container {
+			return true
+		}
+	}
+	return false
+}
+
+// appendContainerLine appends a line of a container's log to dst such that the line lands in the container's slices:
+// plain output goes to a slice named after the container, and the slices and phases the container logs are prefixed
+// with its name, e.g. [test] becomes [sidecar:test]. Results are left alone as their name is their type.
+func appendContainerLine(dst []byte, container string, line []byte) []byte {
+	sl := bytes.TrimLeft(line, " \t")
+	if len(sl) > 0 && sl[0] == '[' {
+		if end := bytes.IndexByte(sl, ']'); end > 1 {
+			if bytes.HasSuffix(sl[1:end], []byte("|RESULT")) {
+				return append(dst, line...)
+			}
+
+			dst = append(dst, '[')
+			dst = append(dst, container...)
+			dst = append(dst, ':')
+			return append(dst, sl[1:]...)
+		}
+	}
+
+	dst = append(dst, '[')
+	dst = append(dst, container...)
+	dst = append(dst, "] "...)
+	return append(dst, line...)
+}
+
+// tail forwards the log of a container. If slice is set, the container's lines are put into slices of that name.
+func (ll *logListener) tail(pod, container, slice string) {
 	var once sync.Once
 
 	ll.mu.Lock()
@@ -142,12 +223,56 @@ func (ll *logListener) tail(pod, container string) {
 	once.Do(ll.mu.Unlock)
 
 	// forward the logs line by line to ensure we don't mix the output of different conainer
-	scanner := bufio.NewScanner(logs)
-	for scanner.Scan() {
-		line := scanner.Text()
-		ll.inmu.Lock()
-		ll.in.Write([]byte(line + "\n"))
-		ll.inmu.Unlock()
+	rd := bufio.NewReaderSize(logs, LogChunkSize)
+	var (
+		// carry is the beginning of a UTF-8 character the previous piece of a long line ended with
+		carry []byte
+		// continuation starts the pieces of a long line after the first one, so that they land in the same slice
+		continuation []byte
+	)
+	for {
+		line, err := rd.ReadSlice('\n')
+		full := err == bufio.ErrBufferFull
+		if len(line) > 0 || len(carry) > 0 {
+			// line points into the reader's buffer which we must not modify
+			piece := append(carry, line...)
+			carry = nil
+			if full {
+				piece, carry = logsanitize.CutIncomplete(piece)
+				carry = append([]byte(nil), carry...)
+			} else {
+				piece = bytes.TrimSuffix(piece, []byte("\n"))
+			}
+			piece = logsanitize.Line(piece)
+
+			var chunk []byte
+			if continuation != nil {
+				chunk = make([]byte, 0, len(continuation)+len(piece)+1)
+				chunk = append(append(chunk, continuation...), piece...)
+			} else if slice == "" {
+				chunk = make([]byte, 0, len(piece)+1)
+				chunk = append(chunk, piece...)
+			} else {
+				chunk = appendContainerLine(make([]byte, 0, len(piece)+len(slice)+4), slice, piece)
+			}
+			if full && continuation == nil {
+				continuation = logsanitize.ContinuationPrefix(chunk)
+			} else if !full {
+				continuation = nil
+			}
+			chunk = append(chunk, '\n')
+
+			ll.inmu.Lock()
+			ll.in.Write(chunk)
+			ll.inmu.Unlock()
+		}
+		if full {
+			// the line is longer than a chunk - we'll forward the rest as continuation
+			continue
+		}
+		if err != nil {
+			return
+		}
 	}
 }
 
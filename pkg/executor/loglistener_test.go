@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestAppendContainerLine(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Line     string
+		Expected string
+	}{
+		{Name: "plain output", Line: "compiling", Expected: "[sidecar] compiling"},
+		{Name: "empty line", Line: "", Expected: "[sidecar] "},
+		{Name: "slice", Line: "[test] running tests", Expected: "[sidecar:test] running tests"},
+		{Name: "indented slice", Line: "  [test|PHASE] testing", Expected: "[sidecar:test|PHASE] testing"},
+		{Name: "result", Line: "[url|RESULT] https://example.com", Expected: "[url|RESULT] https://example.com"},
+		{Name: "unterminated bracket", Line: "[oops", Expected: "[sidecar] [oops"},
+		{Name: "empty brackets", Line: "[] foo", Expected: "[sidecar] [] foo"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := string(appendContainerLine([]byte("x"), "sidecar", []byte(test.Line)))
+			if act != "x"+test.Expected {
+				t.Errorf("expected %q, got %q", "x"+test.Expected, act)
+			}
+		})
+	}
+}
+
+func TestApplicationContainers(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "checkout"}},
+		Containers:     []corev1.Container{{Name: "build"}, {Name: DebugContainerName}},
+	}}
+	if act := applicationContainers(pod); act != 1 {
+		t.Errorf("expected 1 application container, got %d", act)
+	}
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "sidecar"})
+	if act := applicationContainers(pod); act != 2 {
+		t.Errorf("expected 2 application containers, got %d", act)
+	}
+
+	if !isInitContainer(pod, "checkout") {
+		t.Error("expected checkout to be an init container")
+	}
+	if isInitContainer(pod, "build") {
+		t.Error("expected build not to be an init container")
+	}
+}
@@ -0,0 +1,109 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maintenanceConfigMap is the name of the config map which keeps the executor paused across werft restarts
+const maintenanceConfigMap = "werft-maintenance"
+
+// Maintenance describes whether the executor is paused
+type Maintenance struct {
+	Enabled bool
+	Reason  string
+	Since   time.Time
+}
+
+// Pause puts the executor into maintenance mode: jobs started from now on wait until the executor resumes.
+// Jobs which already run are not affected.
+func (js *Executor) Pause(reason string) error {
+	m := Maintenance{Enabled: true, Reason: reason, Since: js.now()}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: maintenanceConfigMap},
+		Data: map[string]string{
+			"reason": m.Reason,
+			"since":  m.Since.Format(time.RFC3339),
+		},
+	}
+	cms := js.Client.CoreV1().ConfigMaps(js.Config.Namespace)
+	_, err := cms.Create(cm)
+	if errors.IsAlreadyExists(err) {
+		_, err = cms.Update(cm)
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot store maintenance mode: %w", err)
+	}
+
+	js.mu.Lock()
+	js.maintenance = m
+	js.mu.Unlock()
+	log.WithField("reason", reason).Info("entered maintenance mode - no new jobs will start")
+	return nil
+}
+
+// Resume ends the maintenance mode and starts all jobs which waited for it to end
+func (js *Executor) Resume() error {
+	err := js.Client.CoreV1().ConfigMaps(js.Config.Namespace).Delete(maintenanceConfigMap, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return xerrors.Errorf("cannot remove maintenance mode: %w", err)
+	}
+
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.maintenance = Maintenance{}
+
+	var started int
+	for name, wj := range js.waitingJobs {
+		if wj.Reason != v1.WaitReason_WAIT_MAINTENANCE {
+			continue
+		}
+		wj.Start()
+		delete(js.waitingJobs, name)
+		started++
+	}
+	log.WithField("jobs", started).Info("left maintenance mode - starting held jobs")
+	return nil
+}
+
+// Maintenance returns whether the executor is paused
+func (js *Executor) Maintenance() Maintenance {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+	return js.maintenance
+}
+
+// loadMaintenance restores the maintenance mode of a previous werft instance
+func (js *Executor) loadMaintenance() error {
+	cm, err := js.Client.CoreV1().ConfigMaps(js.Config.Namespace).Get(maintenanceConfigMap, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot load maintenance mode: %w", err)
+	}
+
+	since, err := time.Parse(time.RFC3339, cm.Data["since"])
+	if err != nil {
+		since = cm.CreationTimestamp.Time
+	}
+	js.maintenance = Maintenance{Enabled: true, Reason: cm.Data["reason"], Since: since}
+	log.WithField("reason", js.maintenance.Reason).Warn("werft is in maintenance mode - no new jobs will start until resumed")
+	return nil
+}
+
+// maintenanceDetails explains to users why their job does not start
+func maintenanceDetails(m Maintenance) string {
+	if m.Reason == "" {
+		return "werft is in maintenance mode"
+	}
+	return fmt.Sprintf("werft is in maintenance mode: %s", m.Reason)
+}
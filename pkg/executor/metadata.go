@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/gogo/protobuf/jsonpb"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// compressedMetadataPrefix marks metadata annotations which hold gzip compressed, base64 encoded JSON
+	compressedMetadataPrefix = "gzip:"
+
+	// metadataCompressionThreshold is the size above which we compress the metadata annotation. Smaller metadata
+	// stays plain JSON, so that it's readable using kubectl.
+	metadataCompressionThreshold = 4 * 1024
+
+	// MaxPodAnnotationsSize is the total size of all annotations of a pod Kubernetes accepts
+	MaxPodAnnotationsSize = 256 * 1024
+)
+
+// ErrAnnotationsTooLarge is returned by Start if a job's annotations don't fit in its pod
+var ErrAnnotationsTooLarge = xerrors.Errorf("job annotations are too large")
+
+// encodeMetadata produces the metadata annotation. Large metadata, e.g. that of jobs with large annotations,
+// is compressed to make the most of the space pod annotations have.
+func encodeMetadata(md *v1.JobMetadata) (string, error) {
+	mdjson, err := (&jsonpb.Marshaler{
+		EnumsAsInts: true,
+	}).MarshalToString(md)
+	if err != nil {
+		return "", err
+	}
+	if len(mdjson) <= metadataCompressionThreshold {
+		return mdjson, nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err = gz.Write([]byte(mdjson))
+	if err != nil {
+		return "", err
+	}
+	err = gz.Close()
+	if err != nil {
+		return "", err
+	}
+	return compressedMetadataPrefix + base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// decodeMetadata parses the metadata annotation, compressed or not
+func decodeMetadata(raw string) (*v1.JobMetadata, error) {
+	if strings.HasPrefix(raw, compressedMetadataPrefix) {
+		zipped, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(raw, compressedMetadataPrefix))
+		if err != nil {
+			return nil, err
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(zipped))
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		content, err := ioutil.ReadAll(gz)
+		if err != nil {
+			return nil, err
+		}
+		raw = string(content)
+	}
+
+	var md v1.JobMetadata
+	err := jsonpb.UnmarshalString(raw, &md)
+	if err != nil {
+		return nil, err
+	}
+	return &md, nil
+}
+
+// checkAnnotationsSize returns an ErrAnnotationsTooLarge if the annotations exceed what Kubernetes accepts for a pod
+func checkAnnotationsSize(annotations map[string]string) error {
+	var total int
+	for k, v := range annotations {
+		total += len(k) + len(v)
+	}
+	if total <= MaxPodAnnotationsSize {
+		return nil
+	}
+	return xerrors.Errorf("%w: they take up %d bytes in the job's pod (metadata %d bytes after compression), but Kubernetes accepts at most %d",
+		ErrAnnotationsTooLarge, total, len(annotations[AnnotationMetadata]), MaxPodAnnotationsSize)
+}
@@ -0,0 +1,128 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// resourceUsageInterval is the time between two samples of the resource usage of running jobs.
+	// The metrics server itself samples roughly every 15 seconds, hence polling more often does not help.
+	resourceUsageInterval = 15 * time.Second
+)
+
+// podMetricsList is the subset of metrics.k8s.io/v1beta1 PodMetricsList we care about
+type podMetricsList struct {
+	Items []struct {
+		Metadata   metav1.ObjectMeta `json:"metadata"`
+		Containers []struct {
+			Name  string              `json:"name"`
+			Usage corev1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// ResourceUsage returns the last known CPU and memory usage of a job. If the usage of the job is unknown
+// (e.g. because it does not run yet or the cluster has no metrics server), nil is returned.
+func (js *Executor) ResourceUsage(name string) *v1.ResourceUsage {
+	js.usageMu.RLock()
+	defer js.usageMu.RUnlock()
+
+	u, ok := js.usage[name]
+	if !ok {
+		return nil
+	}
+	return proto.Clone(u).(*v1.ResourceUsage)
+}
+
+// forgetResourceUsage drops the resource usage of a job, e.g. once its pod is gone
+func (js *Executor) forgetResourceUsage(name string) {
+	js.usageMu.Lock()
+	delete(js.usage, name)
+	js.usageMu.Unlock()
+}
+
+// monitorResourceUsage samples the resource usage of job pods until stop is closed
+func (js *Executor) monitorResourceUsage(stop <-chan struct{}) {
+	var warned bool
+	tick := time.NewTicker(resourceUsageInterval)
+	defer tick.Stop()
+	for {
+		err := js.sampleResourceUsage()
+		if err != nil && !warned {
+			log.WithError(err).Warn("cannot sample resource usage of jobs - is there a metrics server in the cluster?")
+			warned = true
+		} else if err != nil {
+			log.WithError(err).Debug("cannot sample resource usage of jobs")
+		}
+
+		select {
+		case <-tick.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// sampleResourceUsage fetches the current usage of all job pods from the metrics API and updates the usage peaks
+func (js *Executor) sampleResourceUsage() error {
+	fc, err := js.Client.CoreV1().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", js.Config.Namespace, "pods").
+		Param("labelSelector", fmt.Sprintf("%s=true", LabelWerftMarker)).
+		DoRaw()
+	if err != nil {
+		return xerrors.Errorf("cannot get pod metrics: %w", err)
+	}
+
+	var metrics podMetricsList
+	err = json.Unmarshal(fc, &metrics)
+	if err != nil {
+		return xerrors.Errorf("cannot unmarshal pod metrics: %w", err)
+	}
+
+	now, err := ptypes.TimestampProto(js.now())
+	if err != nil {
+		return err
+	}
+	js.usageMu.Lock()
+	defer js.usageMu.Unlock()
+	for _, pod := range metrics.Items {
+		name, ok := pod.Metadata.Labels[LabelJobName]
+		if !ok {
+			continue
+		}
+
+		var cpu, mem resource.Quantity
+		for _, c := range pod.Containers {
+			cpu.Add(c.Usage[corev1.ResourceCPU])
+			mem.Add(c.Usage[corev1.ResourceMemory])
+		}
+
+		u, ok := js.usage[name]
+		if !ok {
+			u = &v1.ResourceUsage{}
+			js.usage[name] = u
+		}
+		u.CpuMillis = cpu.MilliValue()
+		u.MemoryBytes = mem.Value()
+		u.Sampled = now
+		if u.CpuMillis > u.PeakCpuMillis {
+			u.PeakCpuMillis = u.CpuMillis
+		}
+		if u.MemoryBytes > u.PeakMemoryBytes {
+			u.PeakMemoryBytes = u.MemoryBytes
+		}
+	}
+
+	return nil
+}
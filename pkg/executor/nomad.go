@@ -0,0 +1,765 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/32leaves/werft/pkg/logsanitize"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// defaultNomadAddress is the address of the Nomad API if neither the config nor NOMAD_ADDR say otherwise
+	defaultNomadAddress = "http://127.0.0.1:4646"
+
+	// nomadJobPrefix prefixes the IDs of the Nomad jobs werft submits, so that we can find them again
+	nomadJobPrefix = "werft-"
+
+	// nomadGroupName is the name of the only task group of the Nomad jobs werft submits
+	nomadGroupName = "job"
+
+	// nomadWatchTimeout is how long we wait for the allocations of a job to change before we ask again
+	nomadWatchTimeout = 30 * time.Second
+
+	// nomadMaxErrors is the number of consecutive errors we tolerate while watching a job
+	nomadMaxErrors = 10
+)
+
+// NomadConfig configures the nomad executor
+type NomadConfig struct {
+	// Address is the URL of the Nomad API. Defaults to NOMAD_ADDR or http://127.0.0.1:4646.
+	Address string `yaml:"address,omitempty"`
+
+	// Token is the ACL token werft uses. Defaults to NOMAD_TOKEN. The token must be allowed to submit, read and
+	// stop jobs, and to read the logs of allocations.
+	Token string `yaml:"token,omitempty"`
+
+	// Region and Namespace the jobs run in. Default to the ones of the Nomad agent werft talks to.
+	Region    string `yaml:"region,omitempty"`
+	Namespace string `yaml:"namespace,omitempty"`
+
+	// Datacenters the jobs may run in. Defaults to dc1.
+	Datacenters []string `yaml:"datacenters,omitempty"`
+
+	// AllowedHostPaths are the host paths jobs may mount using hostPath volumes, including everything below them.
+	// By default jobs may mount none, except for the volumes werft adds itself, e.g. the workspace.
+	AllowedHostPaths []string `yaml:"allowedHostPaths,omitempty"`
+
+	// AllowPrivileged lets jobs run privileged containers, which have full access to the Nomad clients
+	AllowPrivileged bool `yaml:"allowPrivileged,omitempty"`
+}
+
+func (c *NomadConfig) address() string {
+	if c != nil && c.Address != "" {
+		return c.Address
+	}
+	if addr := os.Getenv("NOMAD_ADDR"); addr != "" {
+		return addr
+	}
+	return defaultNomadAddress
+}
+
+func (c *NomadConfig) token() string {
+	if c != nil && c.Token != "" {
+		return c.Token
+	}
+	return os.Getenv("NOMAD_TOKEN")
+}
+
+func (c *NomadConfig) datacenters() []string {
+	if c == nil || len(c.Datacenters) == 0 {
+		return []string{"dc1"}
+	}
+	return c.Datacenters
+}
+
+// NewNomadExecutor creates a new executor which runs jobs in a Nomad cluster
+func NewNomadExecutor(config Config) (*NomadExecutor, error) {
+	err := config.validate()
+	if err != nil {
+		return nil, err
+	}
+	addr, err := url.Parse(config.Nomad.address())
+	if err != nil {
+		return nil, xerrors.Errorf("invalid Nomad address: %w", err)
+	}
+	if addr.Scheme != "http" && addr.Scheme != "https" {
+		return nil, xerrors.Errorf("invalid Nomad address %s: must be an http or https URL", addr)
+	}
+
+	rt := &nomadRuntime{
+		Config:  config.Nomad,
+		Address: addr,
+		Client:  &http.Client{},
+	}
+	return &NomadExecutor{newRuntimeExecutor(config, rt)}, nil
+}
+
+// NomadExecutor runs every job as batch job in a Nomad cluster using the docker driver. The pod of a job becomes a
+// Nomad job with a single task group: init containers are prestart tasks, all other containers are the main tasks.
+// Nomad starts the prestart tasks at once rather than one after the other. Tasks which fail are neither restarted
+// nor rescheduled, and only emptyDir volumes (in the shared alloc directory) and hostPath volumes are supported.
+//
+// Like the docker executor, it keeps jobs in memory only: werft loses the jobs which run when it stops and purges
+// their Nomad jobs once it's back.
+type NomadExecutor struct {
+	*runtimeExecutor
+}
+
+// nomadRuntime runs pods as Nomad jobs using the Nomad HTTP API
+type nomadRuntime struct {
+	Config  *NomadConfig
+	Address *url.URL
+	Client  *http.Client
+}
+
+type nomadJob struct {
+	ID          string
+	Name        string
+	Type        string
+	Region      string `json:",omitempty"`
+	Namespace   string `json:",omitempty"`
+	Datacenters []string
+	Meta        map[string]string
+	TaskGroups  []nomadTaskGroup
+}
+
+type nomadTaskGroup struct {
+	Name             string
+	Count            int
+	RestartPolicy    nomadRestartPolicy
+	ReschedulePolicy nomadReschedulePolicy
+	Tasks            []nomadTask
+}
+
+type nomadRestartPolicy struct {
+	Attempts int
+	Mode     string
+}
+
+type nomadReschedulePolicy struct {
+	Attempts  int
+	Unlimited bool
+}
+
+type nomadTask struct {
+	Name        string
+	Driver      string
+	User        string `json:",omitempty"`
+	Config      map[string]interface{}
+	Env         map[string]string
+	Resources   *nomadResources `json:",omitempty"`
+	Lifecycle   *nomadLifecycle `json:",omitempty"`
+	KillTimeout time.Duration
+}
+
+type nomadResources struct {
+	MemoryMB int
+}
+
+type nomadLifecycle struct {
+	Hook    string
+	Sidecar bool
+}
+
+type nomadAllocation struct {
+	ID           string
+	ClientStatus string
+	CreateIndex  uint64
+	TaskStates   map[string]nomadTaskState
+}
+
+type nomadTaskState struct {
+	State      string
+	Failed     bool
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Events     []nomadTaskEvent
+}
+
+type nomadTaskEvent struct {
+	Type           string
+	DisplayMessage string
+	Details        map[string]string
+}
+
+// Name returns nomad
+func (n *nomadRuntime) Name() string {
+	return "nomad"
+}
+
+// nomadJobID is the ID of the Nomad job which runs a pod
+func nomadJobID(pod string) string {
+	return nomadJobPrefix + pod
+}
+
+// request builds a request to the Nomad API
+func (n *nomadRuntime) request(method, p string, query url.Values, body interface{}) (*http.Request, error) {
+	u := *n.Address
+	u.Path = path.Join(u.Path, p)
+	if query == nil {
+		query = make(url.Values)
+	}
+	if n.Config != nil && n.Config.Region != "" {
+		query.Set("region", n.Config.Region)
+	}
+	if n.Config != nil && n.Config.Namespace != "" {
+		query.Set("namespace", n.Config.Namespace)
+	}
+	u.RawQuery = query.Encode()
+
+	var in io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		in = bytes.NewReader(b)
+	}
+	req, err := http.NewRequest(method, u.String(), in)
+	if err != nil {
+		return nil, err
+	}
+	if token := n.Config.token(); token != "" {
+		req.Header.Set("X-Nomad-Token", token)
+	}
+	return req, nil
+}
+
+// stream sends a request to the Nomad API and returns the response body
+func (n *nomadRuntime) stream(method, p string, query url.Values, body interface{}) (io.ReadCloser, http.Header, error) {
+	req, err := n.request(method, p, query, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil, xerrors.Errorf("%w: %s", errNotFound, p)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, nil, xerrors.Errorf("nomad %s %s: %s: %s", method, p, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp.Body, resp.Header, nil
+}
+
+// do sends a request to the Nomad API and decodes the JSON response into out, unless out is nil. It returns the
+// index of the response, which blocking queries wait for to change.
+func (n *nomadRuntime) do(method, p string, query url.Values, body, out interface{}) (index uint64, err error) {
+	resp, hdr, err := n.stream(method, p, query, body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Close()
+
+	if out != nil {
+		err = json.NewDecoder(resp).Decode(out)
+		if err != nil {
+			return 0, xerrors.Errorf("cannot decode response of %s: %w", p, err)
+		}
+	}
+	index, _ = strconv.ParseUint(hdr.Get("X-Nomad-Index"), 10, 64)
+	return index, nil
+}
+
+// CheckPod makes sure the nomad executor can and may run a pod
+func (n *nomadRuntime) CheckPod(pod *corev1.Pod) error {
+	var (
+		allowedHostPaths []string
+		allowPrivileged  bool
+	)
+	if n.Config != nil {
+		allowedHostPaths, allowPrivileged = n.Config.AllowedHostPaths, n.Config.AllowPrivileged
+	}
+	err := checkHostAccess(pod, allowedHostPaths, allowPrivileged, "nomad")
+	if err != nil {
+		return err
+	}
+
+	spec := &pod.Spec
+	vols := make(map[string]struct{}, len(spec.Volumes))
+	for _, v := range spec.Volumes {
+		if v.EmptyDir == nil && v.HostPath == nil {
+			return xerrors.Errorf("volume %s: only emptyDir and hostPath volumes are supported by the nomad executor", v.Name)
+		}
+		vols[v.Name] = struct{}{}
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		if len(c.EnvFrom) > 0 {
+			return xerrors.Errorf("container %s: envFrom is not supported by the nomad executor", c.Name)
+		}
+		for _, e := range c.Env {
+			if e.ValueFrom != nil {
+				return xerrors.Errorf("container %s: env %s: valueFrom is not supported by the nomad executor", c.Name, e.Name)
+			}
+		}
+		for _, m := range c.VolumeMounts {
+			if _, ok := vols[m.Name]; !ok {
+				return xerrors.Errorf("container %s: unknown volume %s", c.Name, m.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// RemoveAll purges the Nomad jobs of a previous werft instance
+func (n *nomadRuntime) RemoveAll() error {
+	var jobs []struct{ ID string }
+	_, err := n.do(http.MethodGet, "/v1/jobs", url.Values{"prefix": {nomadJobPrefix}}, nil, &jobs)
+	if err != nil {
+		return xerrors.Errorf("cannot list jobs of previous werft instance: %w", err)
+	}
+	for _, job := range jobs {
+		_, err := n.do(http.MethodDelete, "/v1/job/"+job.ID, url.Values{"purge": {"true"}}, nil, nil)
+		if err != nil && !xerrors.Is(err, errNotFound) {
+			log.WithError(err).WithField("nomadJob", job.ID).Warn("cannot purge Nomad job of previous werft instance")
+		}
+	}
+	return nil
+}
+
+// RunPod submits a pod as Nomad job and watches its allocation until all tasks stopped
+func (n *nomadRuntime) RunPod(pod *corev1.Pod, r podReporter) error {
+	job, err := nomadJobSpec(pod, n.Config)
+	if err != nil {
+		return err
+	}
+	if r.Stopped() {
+		return nil
+	}
+	_, err = n.do(http.MethodPost, "/v1/jobs", nil, map[string]interface{}{"Job": job}, nil)
+	if err != nil {
+		return xerrors.Errorf("cannot submit Nomad job: %w", err)
+	}
+
+	init := make(map[string]bool, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for _, c := range pod.Spec.InitContainers {
+		init[c.Name] = true
+	}
+	for _, c := range pod.Spec.Containers {
+		init[c.Name] = false
+	}
+
+	var (
+		index    uint64
+		errs     int
+		initDone bool
+		started  = make(map[string]bool, len(init))
+		stopped  = make(map[string]bool, len(init))
+	)
+	for {
+		var allocs []nomadAllocation
+		q := url.Values{
+			"index": {strconv.FormatUint(index, 10)},
+			"wait":  {nomadWatchTimeout.String()},
+		}
+		idx, err := n.do(http.MethodGet, "/v1/job/"+job.ID+"/allocations", q, nil, &allocs)
+		if xerrors.Is(err, errNotFound) {
+			// the job is gone, e.g. because someone purged it
+			return xerrors.Errorf("Nomad job %s disappeared", job.ID)
+		}
+		if err != nil {
+			errs++
+			if errs >= nomadMaxErrors {
+				return xerrors.Errorf("cannot watch Nomad job: %w", err)
+			}
+			log.WithError(err).WithField("name", pod.Name).Debug("cannot watch Nomad job - retrying")
+			time.Sleep(time.Duration(errs) * time.Second)
+			continue
+		}
+		errs = 0
+		index = idx
+
+		var alloc *nomadAllocation
+		for i := range allocs {
+			if alloc == nil || allocs[i].CreateIndex > alloc.CreateIndex {
+				alloc = &allocs[i]
+			}
+		}
+		if alloc == nil {
+			// the job was not placed (yet). If it was stopped in the meantime, it never will be.
+			var state struct{ Stop bool }
+			_, err := n.do(http.MethodGet, "/v1/job/"+job.ID, nil, nil, &state)
+			if err == nil && state.Stop {
+				return nil
+			}
+			continue
+		}
+
+		// report the prestart tasks first, so that the pod only runs once its init containers are done
+		for _, name := range sortedTaskNames(alloc.TaskStates) {
+			if isInit, ok := init[name]; ok && isInit {
+				reportNomadTask(r, alloc, name, true, started, stopped)
+			}
+		}
+		for _, name := range sortedTaskNames(alloc.TaskStates) {
+			if isInit, ok := init[name]; !ok || isInit {
+				continue
+			}
+			if ts := alloc.TaskStates[name]; !initDone && (ts.State == "running" || !ts.StartedAt.IsZero()) {
+				initDone = true
+				r.InitDone()
+			}
+			reportNomadTask(r, alloc, name, false, started, stopped)
+		}
+
+		switch alloc.ClientStatus {
+		case "complete", "failed":
+			return nil
+		case "lost":
+			return xerrors.Errorf("Nomad lost the allocation of job %s", job.ID)
+		}
+	}
+}
+
+// reportNomadTask tells r about a task which started or stopped since we last looked
+func reportNomadTask(r podReporter, alloc *nomadAllocation, name string, init bool, started, stopped map[string]bool) {
+	ts := alloc.TaskStates[name]
+	if !started[name] && (ts.State == "running" || !ts.StartedAt.IsZero()) {
+		started[name] = true
+		r.ContainerStarted(name, alloc.ID+"/"+name, init)
+	}
+	if !stopped[name] && ts.State == "dead" && (started[name] || ts.Failed) {
+		stopped[name] = true
+		r.ContainerStopped(name, nomadTerminatedState(ts))
+	}
+}
+
+// sortedTaskNames returns the names of the tasks in alphabetical order, so that we report them in a stable order
+func sortedTaskNames(states map[string]nomadTaskState) []string {
+	res := make([]string, 0, len(states))
+	for name := range states {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// nomadTerminatedState translates the state of a Nomad task which is dead to the state of a container
+func nomadTerminatedState(ts nomadTaskState) corev1.ContainerStateTerminated {
+	var (
+		code    int
+		oom     bool
+		message string
+	)
+	for _, ev := range ts.Events {
+		if ev.Type == "Terminated" {
+			code, _ = strconv.Atoi(ev.Details["exit_code"])
+			oom = ev.Details["oom_killed"] == "true"
+		}
+	}
+
+	reason := "Completed"
+	switch {
+	case oom:
+		reason = "OOMKilled"
+	case code != 0:
+		reason = "Error"
+	case ts.Failed:
+		// the task failed without exit code, e.g. because its image could not be pulled
+		code, reason = 1, "Error"
+		if len(ts.Events) > 0 {
+			last := ts.Events[len(ts.Events)-1]
+			reason, message = strings.ReplaceAll(last.Type, " ", ""), last.DisplayMessage
+		}
+	}
+
+	res := corev1.ContainerStateTerminated{
+		ExitCode: int32(code),
+		Reason:   reason,
+		Message:  message,
+	}
+	if !ts.StartedAt.IsZero() {
+		res.StartedAt = metav1.NewTime(ts.StartedAt)
+	}
+	if !ts.FinishedAt.IsZero() {
+		res.FinishedAt = metav1.NewTime(ts.FinishedAt)
+	}
+	return res
+}
+
+// nomadJobSpec produces the Nomad job which runs a pod
+func nomadJobSpec(pod *corev1.Pod, cfg *NomadConfig) (*nomadJob, error) {
+	var killTimeout time.Duration
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		killTimeout = time.Duration(*pod.Spec.TerminationGracePeriodSeconds) * time.Second
+	}
+
+	tg := nomadTaskGroup{
+		Name:             nomadGroupName,
+		Count:            1,
+		RestartPolicy:    nomadRestartPolicy{Attempts: 0, Mode: "fail"},
+		ReschedulePolicy: nomadReschedulePolicy{Attempts: 0, Unlimited: false},
+	}
+	for _, c := range pod.Spec.InitContainers {
+		task, err := nomadTaskSpec(pod, &c)
+		if err != nil {
+			return nil, err
+		}
+		task.Lifecycle = &nomadLifecycle{Hook: "prestart"}
+		task.KillTimeout = killTimeout
+		tg.Tasks = append(tg.Tasks, *task)
+	}
+	for _, c := range pod.Spec.Containers {
+		task, err := nomadTaskSpec(pod, &c)
+		if err != nil {
+			return nil, err
+		}
+		task.KillTimeout = killTimeout
+		tg.Tasks = append(tg.Tasks, *task)
+	}
+
+	job := &nomadJob{
+		ID:          nomadJobID(pod.Name),
+		Name:        nomadJobID(pod.Name),
+		Type:        "batch",
+		Datacenters: cfg.datacenters(),
+		Meta: map[string]string{
+			LabelWerftMarker: "true",
+			LabelJobName:     pod.Name,
+		},
+		TaskGroups: []nomadTaskGroup{tg},
+	}
+	if cfg != nil {
+		job.Region, job.Namespace = cfg.Region, cfg.Namespace
+	}
+	return job, nil
+}
+
+// nomadTaskSpec produces the Nomad task which runs a container of a pod using the docker driver
+func nomadTaskSpec(pod *corev1.Pod, c *corev1.Container) (*nomadTask, error) {
+	env := make(map[string]string, len(c.Env))
+	for _, e := range c.Env {
+		if e.ValueFrom != nil {
+			return nil, xerrors.Errorf("container %s: env %s: valueFrom is not supported", c.Name, e.Name)
+		}
+		env[e.Name] = expandVars(e.Value, env)
+	}
+
+	vols := make(map[string]corev1.Volume, len(pod.Spec.Volumes))
+	for _, v := range pod.Spec.Volumes {
+		vols[v.Name] = v
+	}
+	var mounts []string
+	for _, m := range c.VolumeMounts {
+		v, ok := vols[m.Name]
+		if !ok {
+			return nil, xerrors.Errorf("container %s: unknown volume %s", c.Name, m.Name)
+		}
+		var src string
+		switch {
+		case v.EmptyDir != nil:
+			// relative paths are relative to the task directory, whose sibling "alloc" all tasks share
+			src = path.Join("..", "alloc", v.Name)
+		case v.HostPath != nil:
+			src = v.HostPath.Path
+		default:
+			return nil, xerrors.Errorf("volume %s: only emptyDir and hostPath volumes are supported", v.Name)
+		}
+		if m.SubPath != "" {
+			src = path.Join(src, m.SubPath)
+		}
+		spec := fmt.Sprintf("%s:%s", src, m.MountPath)
+		if m.ReadOnly {
+			spec += ":ro"
+		}
+		mounts = append(mounts, spec)
+	}
+
+	config := map[string]interface{}{
+		"image": c.Image,
+		"labels": map[string]string{
+			LabelWerftMarker: "true",
+			LabelJobName:     pod.Name,
+		},
+	}
+	if len(c.Command) > 0 {
+		command := make([]string, len(c.Command))
+		for i, a := range c.Command {
+			command[i] = expandVars(a, env)
+		}
+		config["entrypoint"] = command
+	}
+	if len(c.Args) > 0 {
+		args := make([]string, len(c.Args))
+		for i, a := range c.Args {
+			args[i] = expandVars(a, env)
+		}
+		config["args"] = args
+	}
+	if len(mounts) > 0 {
+		config["volumes"] = mounts
+	}
+	if c.WorkingDir != "" {
+		config["work_dir"] = c.WorkingDir
+	}
+
+	task := &nomadTask{
+		Name:   c.Name,
+		Driver: "docker",
+		Config: config,
+		Env:    env,
+	}
+	if sc := c.SecurityContext; sc != nil {
+		if sc.Privileged != nil && *sc.Privileged {
+			config["privileged"] = true
+		}
+		if sc.RunAsUser != nil {
+			task.User = strconv.FormatInt(*sc.RunAsUser, 10)
+		}
+	}
+	if mem := c.Resources.Limits.Memory(); !mem.IsZero() {
+		task.Resources = &nomadResources{MemoryMB: int((mem.Value() + 1<<20 - 1) >> 20)}
+	}
+	return task, nil
+}
+
+// StopPod stops the Nomad job of a pod. Nomad gives its tasks the kill timeout we set when we submitted the job.
+func (n *nomadRuntime) StopPod(pod *corev1.Pod, containers map[string]string, gracePeriod time.Duration) {
+	_, err := n.do(http.MethodDelete, "/v1/job/"+nomadJobID(pod.Name), nil, nil, nil)
+	if err != nil && !xerrors.Is(err, errNotFound) {
+		log.WithError(err).WithField("name", pod.Name).Warn("cannot stop Nomad job")
+	}
+}
+
+// RemovePod purges the Nomad job of a pod
+func (n *nomadRuntime) RemovePod(pod *corev1.Pod, containers map[string]string) error {
+	_, err := n.do(http.MethodDelete, "/v1/job/"+nomadJobID(pod.Name), url.Values{"purge": {"true"}}, nil, nil)
+	if err != nil && !xerrors.Is(err, errNotFound) {
+		return xerrors.Errorf("cannot purge Nomad job: %w", err)
+	}
+	return nil
+}
+
+// splitNomadTaskID splits the IDs we give containers into allocation ID and task name
+func splitNomadTaskID(id string) (alloc, task string, err error) {
+	segs := strings.SplitN(id, "/", 2)
+	if len(segs) != 2 {
+		return "", "", xerrors.Errorf("invalid task ID %s", id)
+	}
+	return segs[0], segs[1], nil
+}
+
+// logStream opens the stdout or stderr log of a task
+func (n *nomadRuntime) logStream(id, typ string, follow bool) (io.ReadCloser, error) {
+	alloc, task, err := splitNomadTaskID(id)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{
+		"task":   {task},
+		"type":   {typ},
+		"origin": {"start"},
+		"offset": {"0"},
+		"plain":  {"true"},
+		"follow": {strconv.FormatBool(follow)},
+	}
+	rc, _, err := n.stream(http.MethodGet, "/v1/client/fs/logs/"+alloc, q, nil)
+	return rc, err
+}
+
+// Logs streams stdout and stderr of a task line by line
+func (n *nomadRuntime) Logs(id string) (io.ReadCloser, error) {
+	var streams []io.ReadCloser
+	for _, typ := range []string{"stdout", "stderr"} {
+		rc, err := n.logStream(id, typ, true)
+		if err != nil {
+			for _, s := range streams {
+				s.Close()
+			}
+			return nil, err
+		}
+		streams = append(streams, rc)
+	}
+
+	pr, pw := io.Pipe()
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, s := range streams {
+		wg.Add(1)
+		go func(s io.Reader) {
+			defer wg.Done()
+
+			scanner := bufio.NewScanner(s)
+			scanner.Buffer(nil, LogChunkSize+1)
+			scanner.Split(logsanitize.ScanLines(LogChunkSize))
+			var line []byte
+			for scanner.Scan() {
+				line = append(append(line[:0], scanner.Bytes()...), '\n')
+				mu.Lock()
+				_, err := pw.Write(line)
+				mu.Unlock()
+				if err != nil {
+					return
+				}
+			}
+		}(s)
+	}
+	go func() {
+		wg.Wait()
+		pw.Close()
+	}()
+	return &nomadLogs{PipeReader: pr, streams: streams}, nil
+}
+
+// nomadLogs merges the stdout and stderr logs of a task. Closing it closes both streams.
+type nomadLogs struct {
+	*io.PipeReader
+	streams []io.ReadCloser
+}
+
+func (l *nomadLogs) Close() error {
+	for _, s := range l.streams {
+		s.Close()
+	}
+	return l.PipeReader.Close()
+}
+
+// ContainerLogs returns stdout followed by stderr of a task
+func (n *nomadRuntime) ContainerLogs(id string, limit int64) ([]byte, error) {
+	var out []byte
+	for _, typ := range []string{"stdout", "stderr"} {
+		if int64(len(out)) >= limit {
+			break
+		}
+		rc, err := n.logStream(id, typ, false)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot get logs of task %s: %w", id, err)
+		}
+		b, err := ioutil.ReadAll(io.LimitReader(rc, limit-int64(len(out))))
+		rc.Close()
+		if err != nil {
+			return nil, xerrors.Errorf("cannot get logs of task %s: %w", id, err)
+		}
+		out = append(out, b...)
+	}
+	return out, nil
+}
+
+// Exec is not supported: Nomad's exec API needs websockets
+func (n *nomadRuntime) Exec(id string, opts AttachOptions) error {
+	return xerrors.Errorf("the nomad executor cannot attach to jobs")
+}
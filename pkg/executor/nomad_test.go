@@ -0,0 +1,461 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNomadJobSpec(t *testing.T) {
+	var (
+		grace int64 = 30
+		user  int64 = 1000
+	)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-1"},
+		Spec: corev1.PodSpec{
+			TerminationGracePeriodSeconds: &grace,
+			Volumes: []corev1.Volume{
+				{Name: "ws", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: "cache", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/cache"}}},
+			},
+			InitContainers: []corev1.Container{
+				{Name: "checkout", Image: "alpine/git", VolumeMounts: []corev1.VolumeMount{{Name: "ws", MountPath: "/workspace"}}},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:       "build",
+					Image:      "golang",
+					Command:    []string{"sh", "-c"},
+					Args:       []string{"echo $(GREETING)"},
+					WorkingDir: "/workspace",
+					Env:        []corev1.EnvVar{{Name: "NAME", Value: "werft"}, {Name: "GREETING", Value: "hello $(NAME)"}},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "ws", MountPath: "/workspace"},
+						{Name: "cache", MountPath: "/go", SubPath: "go", ReadOnly: true},
+					},
+					Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1500k"),
+					}},
+					SecurityContext: &corev1.SecurityContext{RunAsUser: &user},
+				},
+			},
+		},
+	}
+
+	job, err := nomadJobSpec(pod, &NomadConfig{Region: "eu", Namespace: "ci", Datacenters: []string{"dc2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := map[string]string{LabelWerftMarker: "true", LabelJobName: "job-1"}
+	expected := &nomadJob{
+		ID:          "werft-job-1",
+		Name:        "werft-job-1",
+		Type:        "batch",
+		Region:      "eu",
+		Namespace:   "ci",
+		Datacenters: []string{"dc2"},
+		Meta:        labels,
+		TaskGroups: []nomadTaskGroup{{
+			Name:             nomadGroupName,
+			Count:            1,
+			RestartPolicy:    nomadRestartPolicy{Attempts: 0, Mode: "fail"},
+			ReschedulePolicy: nomadReschedulePolicy{},
+			Tasks: []nomadTask{
+				{
+					Name:   "checkout",
+					Driver: "docker",
+					Config: map[string]interface{}{
+						"image":   "alpine/git",
+						"labels":  labels,
+						"volumes": []string{"../alloc/ws:/workspace"},
+					},
+					Env:         map[string]string{},
+					Lifecycle:   &nomadLifecycle{Hook: "prestart"},
+					KillTimeout: 30 * time.Second,
+				},
+				{
+					Name:   "build",
+					Driver: "docker",
+					User:   "1000",
+					Config: map[string]interface{}{
+						"image":      "golang",
+						"labels":     labels,
+						"entrypoint": []string{"sh", "-c"},
+						"args":       []string{"echo hello werft"},
+						"volumes":    []string{"../alloc/ws:/workspace", "/cache/go:/go:ro"},
+						"work_dir":   "/workspace",
+					},
+					Env:         map[string]string{"NAME": "werft", "GREETING": "hello werft"},
+					Resources:   &nomadResources{MemoryMB: 2},
+					KillTimeout: 30 * time.Second,
+				},
+			},
+		}},
+	}
+	if !reflect.DeepEqual(job, expected) {
+		t.Errorf("unexpected job:\n\tgot  %+v\n\twant %+v", job, expected)
+	}
+
+	job, err = nomadJobSpec(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "job-2"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(job.Datacenters, []string{"dc1"}) || job.Region != "" || job.Namespace != "" {
+		t.Errorf("a job without config should default to dc1 and no region or namespace, got %+v", job)
+	}
+}
+
+func TestNomadTaskSpecErrors(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-1"},
+		Spec: corev1.PodSpec{Volumes: []corev1.Volume{
+			{Name: "cfg", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}}},
+		}},
+	}
+	tests := []struct {
+		Name      string
+		Container corev1.Container
+	}{
+		{"unknown volume", corev1.Container{Name: "build", VolumeMounts: []corev1.VolumeMount{{Name: "missing", MountPath: "/m"}}}},
+		{"unsupported volume", corev1.Container{Name: "build", VolumeMounts: []corev1.VolumeMount{{Name: "cfg", MountPath: "/m"}}}},
+		{"valueFrom", corev1.Container{Name: "build", Env: []corev1.EnvVar{{Name: "A", ValueFrom: &corev1.EnvVarSource{}}}}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			_, err := nomadTaskSpec(pod, &test.Container)
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestNomadCheckPod(t *testing.T) {
+	ws := corev1.VolumeMount{Name: "ws", MountPath: "/workspace"}
+	tests := []struct {
+		Name   string
+		Config *NomadConfig
+		Pod    *corev1.Pod
+		Valid  bool
+	}{
+		{"emptyDir", nil, hostPathPod("", "", ws, false), true},
+		{"host path", nil, hostPathPod("", "/etc", ws, false), false},
+		{"host path allowed", &NomadConfig{AllowedHostPaths: []string{"/etc"}}, hostPathPod("", "/etc", ws, false), true},
+		{"workspace", nil, hostPathPod("/mnt/job", "/mnt/job", ws, false), true},
+		{"privileged", nil, hostPathPod("", "", ws, true), false},
+		{"privileged allowed", &NomadConfig{AllowPrivileged: true}, hostPathPod("", "", ws, true), true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			rt := &nomadRuntime{Config: test.Config}
+			err := rt.CheckPod(test.Pod)
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected the pod to be rejected")
+			}
+		})
+	}
+}
+
+func TestSplitNomadTaskID(t *testing.T) {
+	alloc, task, err := splitNomadTaskID("1234-abcd/build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alloc != "1234-abcd" || task != "build" {
+		t.Errorf("unexpected split: %s, %s", alloc, task)
+	}
+	_, _, err = splitNomadTaskID("build")
+	if err == nil {
+		t.Error("expected an error for an ID without allocation")
+	}
+}
+
+func TestNomadTerminatedState(t *testing.T) {
+	started := time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+	terminated := func(code string, oom bool) nomadTaskEvent {
+		return nomadTaskEvent{Type: "Terminated", Details: map[string]string{"exit_code": code, "oom_killed": fmt.Sprint(oom)}}
+	}
+	tests := []struct {
+		Name     string
+		State    nomadTaskState
+		Expected corev1.ContainerStateTerminated
+	}{
+		{
+			Name:     "completed",
+			State:    nomadTaskState{State: "dead", StartedAt: started, FinishedAt: started.Add(time.Minute), Events: []nomadTaskEvent{terminated("0", false)}},
+			Expected: corev1.ContainerStateTerminated{Reason: "Completed", StartedAt: metav1.NewTime(started), FinishedAt: metav1.NewTime(started.Add(time.Minute))},
+		},
+		{
+			Name:     "exit code",
+			State:    nomadTaskState{State: "dead", Failed: true, Events: []nomadTaskEvent{terminated("2", false)}},
+			Expected: corev1.ContainerStateTerminated{ExitCode: 2, Reason: "Error"},
+		},
+		{
+			Name:     "out of memory",
+			State:    nomadTaskState{State: "dead", Failed: true, Events: []nomadTaskEvent{terminated("137", true)}},
+			Expected: corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"},
+		},
+		{
+			Name:     "failed without exit code",
+			State:    nomadTaskState{State: "dead", Failed: true, Events: []nomadTaskEvent{{Type: "Received"}, {Type: "Driver Failure", DisplayMessage: "image not found"}}},
+			Expected: corev1.ContainerStateTerminated{ExitCode: 1, Reason: "DriverFailure", Message: "image not found"},
+		},
+		{
+			Name:     "failed without events",
+			State:    nomadTaskState{State: "dead", Failed: true},
+			Expected: corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := nomadTerminatedState(test.State)
+			if !reflect.DeepEqual(act, test.Expected) {
+				t.Errorf("unexpected state: got %+v, want %+v", act, test.Expected)
+			}
+		})
+	}
+}
+
+// fakeNomad serves the parts of the Nomad API the nomad executor uses. Every query of a job's allocations returns
+// the next of allocs, so that tests can walk a job through its states.
+type fakeNomad struct {
+	mu      sync.Mutex
+	jobs    []string
+	allocs  [][]nomadAllocation
+	queries int
+	logs    map[string]string
+	logReqs []url.Values
+}
+
+func (f *fakeNomad) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/jobs":
+		var req struct{ Job nomadJob }
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.jobs = append(f.jobs, req.Job.ID)
+		w.Write([]byte("{}"))
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/allocations"):
+		if f.queries >= len(f.allocs) {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("X-Nomad-Index", fmt.Sprint(f.queries+1))
+		json.NewEncoder(w).Encode(f.allocs[f.queries])
+		f.queries++
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/client/fs/logs/"):
+		q := r.URL.Query()
+		f.logReqs = append(f.logReqs, q)
+		content, ok := f.logs[strings.TrimPrefix(r.URL.Path, "/v1/client/fs/logs/")+"/"+q.Get("task")+"/"+q.Get("type")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFakeNomadRuntime(t *testing.T, f *fakeNomad) (*nomadRuntime, func()) {
+	srv := httptest.NewServer(f)
+	addr, err := url.Parse(srv.URL)
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	return &nomadRuntime{Config: &NomadConfig{}, Address: addr, Client: srv.Client()}, srv.Close
+}
+
+func TestNomadRunPodStatus(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-1"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "checkout", Image: "alpine/git"}},
+			Containers:     []corev1.Container{{Name: "build", Image: "golang"}},
+		},
+	}
+	started := time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+	exit := func(code string) []nomadTaskEvent {
+		return []nomadTaskEvent{{Type: "Terminated", Details: map[string]string{"exit_code": code}}}
+	}
+	alloc := func(status string, states map[string]nomadTaskState) []nomadAllocation {
+		return []nomadAllocation{{ID: "alloc-1", ClientStatus: status, CreateIndex: 1, TaskStates: states}}
+	}
+
+	tests := []struct {
+		Name   string
+		Allocs [][]nomadAllocation
+		Events []string
+		Error  string
+	}{
+		{
+			Name: "failed job",
+			Allocs: [][]nomadAllocation{
+				alloc("pending", map[string]nomadTaskState{"checkout": {State: "pending"}, "build": {State: "pending"}}),
+				alloc("running", map[string]nomadTaskState{"checkout": {State: "running", StartedAt: started}, "build": {State: "pending"}}),
+				alloc("running", map[string]nomadTaskState{
+					"checkout": {State: "dead", StartedAt: started, Events: exit("0")},
+					"build":    {State: "running", StartedAt: started},
+				}),
+				alloc("failed", map[string]nomadTaskState{
+					"checkout": {State: "dead", StartedAt: started, Events: exit("0")},
+					"build":    {State: "dead", Failed: true, StartedAt: started, Events: exit("2")},
+				}),
+			},
+			Events: []string{
+				"started checkout init=true",
+				"stopped checkout 0 Completed",
+				"init done",
+				"started build init=false",
+				"stopped build 2 Error",
+			},
+		},
+		{
+			Name: "task never started",
+			Allocs: [][]nomadAllocation{
+				alloc("failed", map[string]nomadTaskState{
+					"checkout": {State: "dead", Failed: true, Events: []nomadTaskEvent{{Type: "Driver Failure", DisplayMessage: "image not found"}}},
+					"build":    {State: "pending"},
+				}),
+			},
+			Events: []string{"stopped checkout 1 DriverFailure"},
+		},
+		{
+			Name: "newest allocation",
+			Allocs: [][]nomadAllocation{
+				{
+					{ID: "alloc-2", ClientStatus: "complete", CreateIndex: 2, TaskStates: map[string]nomadTaskState{
+						"checkout": {State: "dead", StartedAt: started, Events: exit("0")},
+						"build":    {State: "dead", StartedAt: started, Events: exit("0")},
+					}},
+					{ID: "alloc-1", ClientStatus: "failed", CreateIndex: 1, TaskStates: map[string]nomadTaskState{
+						"checkout": {State: "dead", Failed: true},
+					}},
+				},
+			},
+			Events: []string{
+				"started checkout init=true",
+				"stopped checkout 0 Completed",
+				"init done",
+				"started build init=false",
+				"stopped build 0 Completed",
+			},
+		},
+		{
+			Name: "lost allocation",
+			Allocs: [][]nomadAllocation{
+				alloc("lost", map[string]nomadTaskState{"checkout": {State: "pending"}}),
+			},
+			Error: "Nomad lost the allocation of job werft-job-1",
+		},
+		{
+			Name:  "job disappears",
+			Error: "Nomad job werft-job-1 disappeared",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			f := &fakeNomad{allocs: test.Allocs}
+			rt, done := newFakeNomadRuntime(t, f)
+			defer done()
+
+			var r testPodReporter
+			err := rt.RunPod(pod, &r)
+			if test.Error == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.Error != "" && (err == nil || !strings.Contains(err.Error(), test.Error)) {
+				t.Fatalf("expected error containing %q, got %v", test.Error, err)
+			}
+			if !reflect.DeepEqual(f.jobs, []string{"werft-job-1"}) {
+				t.Errorf("expected the job to be submitted once, got %v", f.jobs)
+			}
+			if !reflect.DeepEqual(r.events, test.Events) {
+				t.Errorf("unexpected events:\n got %q\nwant %q", r.events, test.Events)
+			}
+		})
+	}
+}
+
+func TestNomadLogs(t *testing.T) {
+	f := &fakeNomad{logs: map[string]string{
+		"alloc-1/build/stdout": "compiling\ndone\n",
+		"alloc-1/build/stderr": "warning: unused variable\n",
+	}}
+	rt, done := newFakeNomadRuntime(t, f)
+	defer done()
+
+	rc, err := rt.Logs("alloc-1/build")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// stdout and stderr are merged line by line, but in no particular order
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	sort.Strings(lines)
+	if exp := []string{"compiling", "done", "warning: unused variable"}; !reflect.DeepEqual(lines, exp) {
+		t.Errorf("unexpected log lines: got %q, want %q", lines, exp)
+	}
+	for _, q := range f.logReqs {
+		if q.Get("follow") != "true" || q.Get("plain") != "true" || q.Get("origin") != "start" {
+			t.Errorf("logs should be followed from the start in plain text, got query %v", q)
+		}
+	}
+
+	f.logReqs = nil
+	b, err := rt.ContainerLogs("alloc-1/build", 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "compiling\ndo"; string(b) != exp {
+		t.Errorf("expected logs truncated to %q, got %q", exp, string(b))
+	}
+	if len(f.logReqs) != 1 || f.logReqs[0].Get("follow") != "false" {
+		t.Errorf("expected the complete stdout log to be read without following, got queries %v", f.logReqs)
+	}
+	b, err = rt.ContainerLogs("alloc-1/build", 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := "compiling\ndone\nwarning: unused variable\n"; string(b) != exp {
+		t.Errorf("expected stdout followed by stderr %q, got %q", exp, string(b))
+	}
+
+	_, err = rt.Logs("alloc-1/test")
+	if !xerrors.Is(err, errNotFound) {
+		t.Errorf("expected logs of an unknown task to be not found, got %v", err)
+	}
+	_, err = rt.Logs("build")
+	if err == nil {
+		t.Error("expected an error for an invalid task ID")
+	}
+}
@@ -0,0 +1,108 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Queue lists all jobs which wait to run, in the order we expect them to start. Jobs whose pods wait for
+// Kubernetes come first, followed by the jobs which wait for their time to start.
+func (js *Executor) Queue() ([]*v1.QueuedJob, error) {
+	pods, err := js.listPods(fmt.Sprintf("%s=true", LabelWerftMarker))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot list job pods: %w", err)
+	}
+
+	type entry struct {
+		Job   *v1.QueuedJob
+		Since time.Time
+		Until time.Time
+	}
+	var pending []entry
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		var cond *corev1.PodCondition
+		for i, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse {
+				cond = &pod.Status.Conditions[i]
+				break
+			}
+		}
+		if cond == nil {
+			// the pod is scheduled and just starting up - the job isn't waiting anymore
+			continue
+		}
+
+		status, err := getStatus(&pod, js.now())
+		if err != nil {
+			return nil, xerrors.Errorf("cannot get status of %s: %w", pod.Name, err)
+		}
+		details := cond.Message
+		if details == "" {
+			details = cond.Reason
+		}
+		pending = append(pending, entry{
+			Job:   &v1.QueuedJob{Job: status, Reason: v1.WaitReason_WAIT_POD_PENDING, Details: details},
+			Since: pod.CreationTimestamp.Time,
+		})
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Since.Before(pending[j].Since) })
+
+	var waiting []entry
+	js.mu.RLock()
+	for _, wj := range js.waitingJobs {
+		var details string
+		switch wj.Reason {
+		case v1.WaitReason_WAIT_RETRY_BACKOFF:
+			details = fmt.Sprintf("attempt %d waits until %s", wj.Status.Conditions.Attempt, wj.Until.Format(time.RFC3339))
+		case v1.WaitReason_WAIT_EXECUTION_WINDOW:
+			details = fmt.Sprintf("waits for its execution window to open at %s", wj.Until.Format(time.RFC3339))
+		case v1.WaitReason_WAIT_MAINTENANCE:
+			details = maintenanceDetails(js.maintenance)
+		case v1.WaitReason_WAIT_CAPACITY, v1.WaitReason_WAIT_CONCURRENCY_LIMIT, v1.WaitReason_WAIT_RATE_LIMIT, v1.WaitReason_WAIT_DEPENDENCY:
+			details = wj.Details
+		case v1.WaitReason_WAIT_APPROVAL:
+			details = approvalDetails
+		default:
+			details = fmt.Sprintf("scheduled to start at %s", wj.Until.Format(time.RFC3339))
+		}
+		status := *wj.Status
+		waiting = append(waiting, entry{
+			Job:   &v1.QueuedJob{Job: &status, Reason: wj.Reason, Details: details},
+			Since: wj.Since,
+			Until: wj.Until,
+		})
+	}
+	js.mu.RUnlock()
+	sort.Slice(waiting, func(i, j int) bool {
+		if !waiting[i].Until.Equal(waiting[j].Until) {
+			return waiting[i].Until.Before(waiting[j].Until)
+		}
+		return waiting[i].Since.Before(waiting[j].Since)
+	})
+
+	res := make([]*v1.QueuedJob, 0, len(pending)+len(waiting))
+	for i, e := range append(pending, waiting...) {
+		e.Job.Position = int32(i + 1)
+		e.Job.Since, err = ptypes.TimestampProto(e.Since)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot convert queue time of %s: %w", e.Job.Job.Name, err)
+		}
+		if !e.Until.IsZero() {
+			e.Job.Until, err = ptypes.TimestampProto(e.Until)
+			if err != nil {
+				return nil, xerrors.Errorf("cannot convert start time of %s: %w", e.Job.Job.Name, err)
+			}
+		}
+		res = append(res, e.Job)
+	}
+	return res, nil
+}
@@ -0,0 +1,225 @@
+package executor
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// testGate holds jobs with a fixed reason until it's opened
+type testGate struct {
+	Reason  v1.WaitReason
+	Details string
+
+	mu   sync.Mutex
+	open bool
+}
+
+func (g *testGate) check() (v1.WaitReason, string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.open {
+		return v1.WaitReason_WAIT_UNKNOWN, ""
+	}
+	return g.Reason, g.Details
+}
+
+func (g *testGate) Open() {
+	g.mu.Lock()
+	g.open = true
+	g.mu.Unlock()
+}
+
+type queuedJob struct {
+	Name    string
+	Reason  v1.WaitReason
+	Details string
+}
+
+func listQueue(t *testing.T, queue func() ([]*v1.QueuedJob, error)) []queuedJob {
+	jobs, err := queue()
+	if err != nil {
+		t.Fatalf("cannot list queue: %v", err)
+	}
+	res := make([]queuedJob, len(jobs))
+	for i, j := range jobs {
+		if int(j.Position) != i+1 {
+			t.Errorf("job %s: expected position %d, got %d", j.Job.Name, i+1, j.Position)
+		}
+		res[i] = queuedJob{Name: j.Job.Name, Reason: j.Reason, Details: j.Details}
+	}
+	return res
+}
+
+func assertQueue(t *testing.T, act, exp []queuedJob) {
+	if len(act) != len(exp) {
+		t.Fatalf("expected %d queued jobs, got %v", len(exp), act)
+	}
+	for i := range exp {
+		if act[i] != exp[i] {
+			t.Errorf("position %d: expected %+v, got %+v", i+1, exp[i], act[i])
+		}
+	}
+}
+
+// awaitQueue waits until the queue has the expected length, e.g. once a job left it
+func awaitQueue(t *testing.T, queue func() ([]*v1.QueuedJob, error), n int) []queuedJob {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		act := listQueue(t, queue)
+		if len(act) == n || time.Now().After(deadline) {
+			return act
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestExecutorQueue(t *testing.T) {
+	const ns = "werft"
+	var (
+		now  = time.Now()
+		spec = corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Image: "alpine"}}}
+	)
+	pending, _, err := newJobPod(&Config{Namespace: ns}, spec, v1.JobMetadata{Owner: "foo"}, now, monikerIDs{}.NewID, WithName("pending"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending.Namespace = ns
+	pending.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	pending.Status = corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{
+			{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: corev1.PodReasonUnschedulable, Message: "0/3 nodes are available"},
+		},
+	}
+	var (
+		client = fake.NewSimpleClientset(pending)
+		js     = &Executor{
+			OnUpdate:    func(pod *corev1.Pod, status *v1.JobStatus) {},
+			Config:      Config{Namespace: ns},
+			Client:      client,
+			pods:        newPodInformer(client, ns, 0),
+			waitingJobs: make(map[string]*waitingJob),
+			gating:      gating{gateInterval: 10 * time.Millisecond},
+		}
+		concurrency = &testGate{Reason: v1.WaitReason_WAIT_CONCURRENCY_LIMIT, Details: "3 of 3 jobs are running"}
+		dependency  = &testGate{Reason: v1.WaitReason_WAIT_DEPENDENCY, Details: "waiting for job build.1 to finish"}
+		rate        = &testGate{Reason: v1.WaitReason_WAIT_RATE_LIMIT, Details: "started 10 jobs within the last hour"}
+	)
+
+	starts := []struct {
+		Name string
+		Opts []StartOpt
+	}{
+		{"scheduled", []StartOpt{WithWaitUntil(now.Add(time.Hour))}},
+		{"concurrency", []StartOpt{WithGate(concurrency.check)}},
+		{"dependency", []StartOpt{WithGate(dependency.check)}},
+		{"approval", []StartOpt{WithApproval(), WithGate(rate.check)}},
+		{"open", []StartOpt{WithGate((&testGate{open: true}).check)}},
+	}
+	for _, s := range starts {
+		_, err := js.Start(spec, v1.JobMetadata{Owner: "foo"}, append(s.Opts, WithName(s.Name))...)
+		if err != nil {
+			t.Fatalf("cannot start %s: %v", s.Name, err)
+		}
+		// jobs which wait for the same time are ordered by the time they started waiting
+		time.Sleep(time.Millisecond)
+	}
+
+	// pods waiting for Kubernetes come first, jobs without start time in the order they arrived, scheduled jobs last
+	assertQueue(t, listQueue(t, js.Queue), []queuedJob{
+		{"pending", v1.WaitReason_WAIT_POD_PENDING, "0/3 nodes are available"},
+		{"concurrency", v1.WaitReason_WAIT_CONCURRENCY_LIMIT, "3 of 3 jobs are running"},
+		{"dependency", v1.WaitReason_WAIT_DEPENDENCY, "waiting for job build.1 to finish"},
+		{"approval", v1.WaitReason_WAIT_APPROVAL, approvalDetails},
+		{"scheduled", v1.WaitReason_WAIT_SCHEDULED, "scheduled to start at " + now.Add(time.Hour).Format(time.RFC3339)},
+	})
+	if _, err := client.CoreV1().Pods(ns).Get("open", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the job its gate let through to start right away: %v", err)
+	}
+
+	// once approved, the job waits for its gate
+	err = js.Approve("approval")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		q := listQueue(t, js.Queue)
+		if q[3].Reason == v1.WaitReason_WAIT_RATE_LIMIT || time.Now().After(deadline) {
+			assertQueue(t, q[3:4], []queuedJob{{"approval", v1.WaitReason_WAIT_RATE_LIMIT, "started 10 jobs within the last hour"}})
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// once its gate opens, the job starts
+	concurrency.Open()
+	assertQueue(t, awaitQueue(t, js.Queue, 4), []queuedJob{
+		{"pending", v1.WaitReason_WAIT_POD_PENDING, "0/3 nodes are available"},
+		{"dependency", v1.WaitReason_WAIT_DEPENDENCY, "waiting for job build.1 to finish"},
+		{"approval", v1.WaitReason_WAIT_RATE_LIMIT, "started 10 jobs within the last hour"},
+		{"scheduled", v1.WaitReason_WAIT_SCHEDULED, "scheduled to start at " + now.Add(time.Hour).Format(time.RFC3339)},
+	})
+	if _, err := client.CoreV1().Pods(ns).Get("concurrency", metav1.GetOptions{}); err != nil {
+		t.Errorf("expected the job to start once its gate opened: %v", err)
+	}
+}
+
+func TestRuntimeExecutorQueue(t *testing.T) {
+	e, err := NewFakeExecutor(Config{
+		JobPrepTimeout:  &Duration{Duration: time.Hour},
+		JobTotalTimeout: &Duration{Duration: time.Hour},
+		Fake:            &FakeConfig{Duration: &Duration{Duration: time.Hour}, LogLines: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	e.gateInterval = 10 * time.Millisecond
+
+	var (
+		now         = time.Now()
+		spec        = corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Image: "alpine"}}}
+		concurrency = &testGate{Reason: v1.WaitReason_WAIT_CONCURRENCY_LIMIT, Details: "3 of 3 jobs are running"}
+		rate        = &testGate{Reason: v1.WaitReason_WAIT_RATE_LIMIT, Details: "started 10 jobs within the last hour"}
+		dependency  = &testGate{Reason: v1.WaitReason_WAIT_DEPENDENCY, Details: "waiting for job build.1 to finish"}
+	)
+	starts := []struct {
+		Name string
+		Opts []StartOpt
+	}{
+		{"scheduled", []StartOpt{WithWaitUntil(now.Add(time.Hour)), WithGate(dependency.check)}},
+		{"rate", []StartOpt{WithGate(rate.check)}},
+		{"concurrency", []StartOpt{WithGate(concurrency.check)}},
+		{"open", []StartOpt{WithGate((&testGate{open: true}).check)}},
+	}
+	for _, s := range starts {
+		status, err := e.Start(spec, v1.JobMetadata{Owner: "foo"}, append(s.Opts, WithName(s.Name))...)
+		if err != nil {
+			t.Fatalf("cannot start %s: %v", s.Name, err)
+		}
+		if exp := s.Name == "open"; (status.Phase != v1.JobPhase_PHASE_WAITING) != exp {
+			t.Errorf("%s: unexpected phase %v", s.Name, status.Phase)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// the gate of a scheduled job doesn't matter before its time comes
+	assertQueue(t, listQueue(t, e.Queue), []queuedJob{
+		{"rate", v1.WaitReason_WAIT_RATE_LIMIT, "started 10 jobs within the last hour"},
+		{"concurrency", v1.WaitReason_WAIT_CONCURRENCY_LIMIT, "3 of 3 jobs are running"},
+		{"scheduled", v1.WaitReason_WAIT_SCHEDULED, "scheduled to start at " + now.Add(time.Hour).Format(time.RFC3339)},
+	})
+
+	rate.Open()
+	assertQueue(t, awaitQueue(t, e.Queue, 2), []queuedJob{
+		{"concurrency", v1.WaitReason_WAIT_CONCURRENCY_LIMIT, "3 of 3 jobs are running"},
+		{"scheduled", v1.WaitReason_WAIT_SCHEDULED, "scheduled to start at " + now.Add(time.Hour).Format(time.RFC3339)},
+	})
+}
@@ -0,0 +1,284 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// agentLogChunkSize is the size of the largest piece of logs agents send at once
+	agentLogChunkSize = 32 * 1024
+
+	// agentMaxBackoff is the longest an agent waits before it reconnects
+	agentMaxBackoff = time.Minute
+)
+
+// NewAgent creates an agent which runs the jobs werft sends it as pods in a Kubernetes namespace
+func NewAgent(name, token string, clientset kubernetes.Interface, namespace string) *Agent {
+	return &Agent{
+		Name:    name,
+		Token:   token,
+		runtime: &kubeRuntime{Clientset: clientset, Namespace: namespace},
+	}
+}
+
+// NewDockerAgent creates an agent which runs the jobs werft sends it on its host using docker
+func NewDockerAgent(name, token string, config *DockerConfig) *Agent {
+	return &Agent{
+		Name:    name,
+		Token:   token,
+		runtime: &dockerRuntime{Config: config},
+	}
+}
+
+// Agent runs jobs for a werft server it connects to, e.g. in a cluster the server cannot reach.
+// See AgentExecutor for the server's side.
+type Agent struct {
+	Name  string
+	Token string
+
+	runtime containerRuntime
+}
+
+// Serve connects to the werft server and runs the jobs it sends. If the connection breaks, Serve reconnects
+// until the context is cancelled.
+func (a *Agent) Serve(ctx context.Context, client v1.AgentServiceClient) error {
+	backoff := time.Second
+	for {
+		start := time.Now()
+		err := a.run(ctx, client)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if time.Since(start) > agentMaxBackoff {
+			backoff = time.Second
+		}
+		log.WithError(err).WithField("backoff", backoff).Warn("lost connection to werft - reconnecting")
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > agentMaxBackoff {
+			backoff = agentMaxBackoff
+		}
+	}
+}
+
+// agentSession is a connection of an agent to werft
+type agentSession struct {
+	Agent  *Agent
+	stream v1.AgentService_ConnectClient
+
+	sendMu sync.Mutex
+
+	mu sync.Mutex
+	// stopped lists the pods werft stopped
+	stopped map[string]bool
+	// logs are the log streams werft follows, by command ID
+	logs map[uint64]io.Closer
+}
+
+// run serves a single connection to werft
+func (a *Agent) run(ctx context.Context, client v1.AgentServiceClient) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	err = stream.Send(&v1.AgentMessage{Content: &v1.AgentMessage_Hello{Hello: &v1.AgentHello{Name: a.Name, Token: a.Token}}})
+	if err != nil {
+		return err
+	}
+
+	// werft forgets about the jobs we ran when we disconnect - so do we
+	err = a.runtime.RemoveAll()
+	if err != nil {
+		log.WithError(err).Warn("cannot remove leftovers of previous jobs")
+	}
+
+	s := &agentSession{
+		Agent:   a,
+		stream:  stream,
+		stopped: make(map[string]bool),
+		logs:    make(map[uint64]io.Closer),
+	}
+	var first = true
+	for {
+		cmd, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		if first {
+			log.Info("connected to werft")
+			first = false
+		}
+		go s.handle(cmd)
+	}
+}
+
+// send sends a message to werft
+func (s *agentSession) send(msg *v1.AgentMessage) error {
+	s.sendMu.Lock()
+	defer s.sendMu.Unlock()
+	return s.stream.Send(msg)
+}
+
+// done ends a command
+func (s *agentSession) done(id uint64, err error) {
+	res := &v1.AgentDone{}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	serr := s.send(&v1.AgentMessage{Id: id, Content: &v1.AgentMessage_Done{Done: res}})
+	if serr != nil {
+		log.WithError(serr).Debug("cannot tell werft a command is done")
+	}
+}
+
+// handle runs a command werft sent
+func (s *agentSession) handle(cmd *v1.AgentCommand) {
+	switch c := cmd.Content.(type) {
+	case *v1.AgentCommand_RunPod:
+		var pod corev1.Pod
+		err := json.Unmarshal(c.RunPod.Pod, &pod)
+		if err == nil {
+			log.WithField("name", pod.Name).Info("running job")
+			err = s.Agent.runtime.RunPod(&pod, &agentReporter{s: s, id: cmd.Id, pod: pod.Name})
+		}
+		s.done(cmd.Id, err)
+
+	case *v1.AgentCommand_StopPod:
+		var pod corev1.Pod
+		err := json.Unmarshal(c.StopPod.Pod, &pod)
+		if err == nil {
+			s.mu.Lock()
+			s.stopped[pod.Name] = true
+			s.mu.Unlock()
+			s.Agent.runtime.StopPod(&pod, c.StopPod.Containers, time.Duration(c.StopPod.GracePeriodSeconds)*time.Second)
+		}
+		s.done(cmd.Id, err)
+
+	case *v1.AgentCommand_RemovePod:
+		var pod corev1.Pod
+		err := json.Unmarshal(c.RemovePod.Pod, &pod)
+		if err == nil {
+			err = s.Agent.runtime.RemovePod(&pod, c.RemovePod.Containers)
+			s.mu.Lock()
+			delete(s.stopped, pod.Name)
+			s.mu.Unlock()
+		}
+		s.done(cmd.Id, err)
+
+	case *v1.AgentCommand_Logs:
+		s.done(cmd.Id, s.sendLogs(cmd.Id, c.Logs))
+
+	case *v1.AgentCommand_Cancel:
+		s.mu.Lock()
+		l, ok := s.logs[c.Cancel]
+		s.mu.Unlock()
+		if ok {
+			l.Close()
+		}
+	}
+}
+
+// sendLogs sends the output of a container to werft
+func (s *agentSession) sendLogs(id uint64, req *v1.AgentLogs) error {
+	var in io.Reader
+	if req.Follow {
+		rc, err := s.Agent.runtime.Logs(req.ContainerId)
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+
+		s.mu.Lock()
+		s.logs[id] = rc
+		s.mu.Unlock()
+		defer func() {
+			s.mu.Lock()
+			delete(s.logs, id)
+			s.mu.Unlock()
+		}()
+		in = rc
+	} else {
+		out, err := s.Agent.runtime.ContainerLogs(req.ContainerId, req.Limit)
+		if err != nil {
+			return err
+		}
+		in = bytes.NewReader(out)
+	}
+
+	buf := make([]byte, agentLogChunkSize)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			serr := s.send(&v1.AgentMessage{Id: id, Content: &v1.AgentMessage_Logs{Logs: append([]byte(nil), buf[:n]...)}})
+			if serr != nil {
+				return serr
+			}
+		}
+		if err == io.EOF || err == io.ErrClosedPipe {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// agentReporter relays the progress of a pod to werft
+type agentReporter struct {
+	s   *agentSession
+	id  uint64
+	pod string
+}
+
+func (r *agentReporter) send(msg *v1.AgentMessage) {
+	msg.Id = r.id
+	err := r.s.send(msg)
+	if err != nil {
+		log.WithError(err).WithField("name", r.pod).Debug("cannot report job progress to werft")
+	}
+}
+
+// ContainerStarted tells werft a container started
+func (r *agentReporter) ContainerStarted(name, id string, init bool) {
+	r.send(&v1.AgentMessage{Content: &v1.AgentMessage_ContainerStarted{ContainerStarted: &v1.AgentContainerStarted{Name: name, Id: id, Init: init}}})
+}
+
+// ContainerStopped tells werft a container stopped
+func (r *agentReporter) ContainerStopped(name string, state corev1.ContainerStateTerminated) {
+	s, err := json.Marshal(state)
+	if err != nil {
+		log.WithError(err).WithField("name", r.pod).Warn("cannot marshal container state")
+		return
+	}
+	r.send(&v1.AgentMessage{Content: &v1.AgentMessage_ContainerStopped{ContainerStopped: &v1.AgentContainerStopped{Name: name, State: s}}})
+}
+
+// InitDone tells werft the init containers are done
+func (r *agentReporter) InitDone() {
+	r.send(&v1.AgentMessage{Content: &v1.AgentMessage_InitDone{InitDone: &v1.AgentInitDone{}}})
+}
+
+// Stopped returns true once werft stopped the pod
+func (r *agentReporter) Stopped() bool {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.stopped[r.pod]
+}
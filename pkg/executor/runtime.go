@@ -0,0 +1,948 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logsanitize"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// logDrainTimeout is how long the logs of a job which finished may take to arrive before we stop reading them
+const logDrainTimeout = 10 * time.Second
+
+// containerRuntime runs the containers of job pods for the executors which don't use Kubernetes
+type containerRuntime interface {
+	// Name names the runtime in messages, e.g. docker
+	Name() string
+
+	// CheckPod makes sure the runtime can and may run a pod
+	CheckPod(pod *corev1.Pod) error
+
+	// RemoveAll removes what the jobs of a previous werft instance left behind
+	RemoveAll() error
+
+	// RunPod runs the containers of a pod and returns once they all stopped. It tells r about the containers
+	// starting and stopping.
+	RunPod(pod *corev1.Pod, r podReporter) error
+
+	// StopPod stops a pod. containers maps the names of the pod's running containers to their IDs.
+	StopPod(pod *corev1.Pod, containers map[string]string, gracePeriod time.Duration)
+
+	// RemovePod removes everything a pod which finished left behind
+	RemovePod(pod *corev1.Pod, containers map[string]string) error
+
+	// Logs streams the output of a container until it stops
+	Logs(id string) (io.ReadCloser, error)
+
+	// ContainerLogs returns the output of a container. Logs larger than limit bytes are truncated.
+	ContainerLogs(id string, limit int64) ([]byte, error)
+
+	// Exec runs a command in a running container and waits for it to finish
+	Exec(id string, opts AttachOptions) error
+}
+
+// podReporter is told about the containers of a pod starting and stopping
+type podReporter interface {
+	// ContainerStarted reports a container as running. id identifies the container for the runtime.
+	ContainerStarted(name, id string, init bool)
+
+	// ContainerStopped reports a container as terminated
+	ContainerStopped(name string, state corev1.ContainerStateTerminated)
+
+	// InitDone reports that all init containers succeeded and the other containers start
+	InitDone()
+
+	// Stopped returns true once the job was stopped, so that containers which did not start yet don't
+	Stopped() bool
+}
+
+// newRuntimeExecutor creates an executor which runs its jobs using a container runtime
+func newRuntimeExecutor(config Config, runtime containerRuntime) *runtimeExecutor {
+	return &runtimeExecutor{
+		OnUpdate: func(pod *corev1.Pod, status *v1.JobStatus) {},
+		Config:   config,
+		runtime:  runtime,
+		jobs:     make(map[string]*runtimeJob),
+	}
+}
+
+// runtimeExecutor runs jobs using a container runtime other than Kubernetes. Like the Kubernetes executor it
+// describes jobs as pods, but it keeps them in memory only: werft loses the jobs which run when it stops and
+// removes what they left behind once it's back.
+type runtimeExecutor struct {
+	// OnUpdate is called when the status of a job changes.
+	// Beware: this function can be called several times with the same status.
+	OnUpdate func(pod *corev1.Pod, status *v1.JobStatus)
+
+	Config Config
+
+	runtime     containerRuntime
+	jobs        map[string]*runtimeJob
+	maintenance Maintenance
+	mu          sync.RWMutex
+
+	// updateMu makes sure OnUpdate is called for one update at a time, like the Kubernetes executor does
+	updateMu sync.Mutex
+
+	podHooks
+	lifecycle
+	jobClock
+	jobIDs
+	gating
+}
+
+// runtimeJob is a job of a runtime executor. All fields are guarded by the executor's mutex.
+type runtimeJob struct {
+	Pod *corev1.Pod
+
+	// Containers maps the names of the job's containers to their runtime IDs once they started
+	Containers map[string]string
+
+	// Waiting is true while the job waits to start
+	Waiting bool
+	// Approval is true while the job waits for its approval
+	Approval bool
+	// Reason tells why the job waits
+	Reason v1.WaitReason
+	// ScheduleReason tells why the job waits for its start time
+	ScheduleReason v1.WaitReason
+	Since          time.Time
+	Until          time.Time
+
+	// Gate holds the job once nothing else does, GateDetails tells why it holds the job
+	Gate        Gate
+	GateDetails string
+
+	// wake makes a waiting job check whether it can start
+	wake chan struct{}
+
+	// started lists the containers which started so far, subs are told about those starting from now on
+	started []runtimeContainer
+	subs    []chan runtimeContainer
+
+	// tails are the log streams of the job's containers we read from
+	tails []io.Closer
+}
+
+// runtimeContainer is a container of a job which started
+type runtimeContainer struct {
+	Name string
+	ID   string
+	Init bool
+}
+
+// SetUpdateHandler sets the function called when the status of a job changes, i.e. OnUpdate
+func (e *runtimeExecutor) SetUpdateHandler(f func(pod *corev1.Pod, status *v1.JobStatus)) {
+	e.OnUpdate = f
+}
+
+// Run removes what the jobs of a previous werft instance left behind and starts the executor.
+// It returns once the leftovers are removed.
+func (e *runtimeExecutor) Run() {
+	err := e.runtime.RemoveAll()
+	if err != nil {
+		log.WithError(err).Warn("cannot remove leftovers of previous jobs")
+	}
+
+	e.goBackground(e.doHousekeeping)
+}
+
+// Start starts a new job
+func (e *runtimeExecutor) Start(podspec corev1.PodSpec, metadata v1.JobMetadata, options ...StartOpt) (*v1.JobStatus, error) {
+	pod, opts, err := newJobPod(&e.Config, podspec, metadata, e.now(), e.newID, options...)
+	if err != nil {
+		return nil, err
+	}
+	if opts.LimitEgress {
+		return nil, xerrors.Errorf("the %s executor cannot restrict the egress of jobs", e.runtime.Name())
+	}
+	err = e.runtime.CheckPod(pod)
+	if err != nil {
+		return nil, err
+	}
+	err = e.preCreatePod(pod)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Mutex != "" {
+		// enforce mutex by stopping all other jobs with the same mutex
+		msg := fmt.Sprintf("a newer job (%s) with the same mutex (%s) started", opts.JobName, opts.Mutex)
+		e.mu.RLock()
+		var others []string
+		for name, j := range e.jobs {
+			if j.Pod.Labels[LabelMutex] == opts.Mutex {
+				others = append(others, name)
+			}
+		}
+		e.mu.RUnlock()
+		for _, name := range others {
+			err := e.Stop(name, msg)
+			if err != nil && !xerrors.Is(err, errNotFound) {
+				return nil, xerrors.Errorf("cannot enforce mutex: %w", err)
+			}
+		}
+	}
+
+	scheduleReason := v1.WaitReason_WAIT_SCHEDULED
+	if opts.Attempt > 1 {
+		scheduleReason = v1.WaitReason_WAIT_RETRY_BACKOFF
+	}
+	if opts.WaitReason != v1.WaitReason_WAIT_UNKNOWN {
+		scheduleReason = opts.WaitReason
+	}
+	j := &runtimeJob{
+		Pod:            pod,
+		Containers:     make(map[string]string),
+		Approval:       opts.Approval,
+		ScheduleReason: scheduleReason,
+		Since:          e.now(),
+		Until:          opts.WaitUntil,
+		Gate:           opts.Gate,
+		wake:           make(chan struct{}, 1),
+	}
+
+	e.mu.Lock()
+	if _, exists := e.jobs[pod.Name]; exists {
+		e.mu.Unlock()
+		return nil, xerrors.Errorf("job %s exists already", pod.Name)
+	}
+	e.jobs[pod.Name] = j
+	j.Waiting = e.mustWait(j)
+	e.mu.Unlock()
+
+	waiting := j.Waiting || e.checkGate(j)
+	if !waiting {
+		e.mu.Lock()
+		j.Pod.Status.Phase = corev1.PodPending
+		e.mu.Unlock()
+	}
+
+	status, err := e.update(j)
+	if err != nil {
+		e.forget(j)
+		return nil, err
+	}
+	if waiting {
+		go e.wait(j)
+	} else {
+		go e.run(j)
+	}
+	return status, nil
+}
+
+// mustWait checks whether a job has to wait before it can start and updates the reason it waits for.
+// Callers must hold the executor's mutex.
+func (e *runtimeExecutor) mustWait(j *runtimeJob) bool {
+	switch {
+	case j.Pod.Annotations[AnnotationFailed] != "":
+		return false
+	case j.Approval:
+		j.Reason = v1.WaitReason_WAIT_APPROVAL
+	case e.now().Before(j.Until):
+		j.Reason = j.ScheduleReason
+	case e.maintenance.Enabled:
+		j.Reason = v1.WaitReason_WAIT_MAINTENANCE
+	default:
+		return false
+	}
+	return true
+}
+
+// checkGate asks the gate of a job which could start otherwise whether it can start, and holds the job if it can't.
+// The gate might take a while, hence callers must not hold the executor's mutex.
+func (e *runtimeExecutor) checkGate(j *runtimeJob) bool {
+	reason, details := j.Gate.check()
+	if reason == v1.WaitReason_WAIT_UNKNOWN {
+		return false
+	}
+
+	e.mu.Lock()
+	j.Waiting, j.Reason, j.GateDetails = true, reason, details
+	e.mu.Unlock()
+	return true
+}
+
+// waitDetails explains to users why a job does not start. Callers must hold the executor's mutex.
+func (e *runtimeExecutor) waitDetails(j *runtimeJob) string {
+	switch j.Reason {
+	case v1.WaitReason_WAIT_APPROVAL:
+		return approvalDetails
+	case v1.WaitReason_WAIT_MAINTENANCE:
+		return maintenanceDetails(e.maintenance)
+	case v1.WaitReason_WAIT_RETRY_BACKOFF:
+		return fmt.Sprintf("attempt %d waits until %s", getAttempt(j.Pod), j.Until.Format(time.RFC3339))
+	case v1.WaitReason_WAIT_EXECUTION_WINDOW:
+		return fmt.Sprintf("waits for its execution window to open at %s", j.Until.Format(time.RFC3339))
+	case v1.WaitReason_WAIT_CONCURRENCY_LIMIT, v1.WaitReason_WAIT_RATE_LIMIT, v1.WaitReason_WAIT_DEPENDENCY:
+		return j.GateDetails
+	default:
+		return fmt.Sprintf("scheduled to start at %s", j.Until.Format(time.RFC3339))
+	}
+}
+
+func getAttempt(pod *corev1.Pod) int {
+	n, err := strconv.Atoi(pod.Annotations[AnnotationAttempt])
+	if err != nil {
+		return 1
+	}
+	return n
+}
+
+// update tells OnUpdate about the current state of a job and returns the job's status
+func (e *runtimeExecutor) update(j *runtimeJob) (*v1.JobStatus, error) {
+	e.updateMu.Lock()
+	defer e.updateMu.Unlock()
+
+	e.mu.RLock()
+	pod := j.Pod.DeepCopy()
+	waiting, details := j.Waiting, e.waitDetails(j)
+	e.mu.RUnlock()
+
+	status, err := getStatus(pod, e.now())
+	if err != nil {
+		log.WithError(err).WithField("name", pod.Name).Error("cannot compute status")
+		return nil, err
+	}
+	if waiting {
+		status.Phase = v1.JobPhase_PHASE_WAITING
+		status.Details = details
+	}
+
+	e.OnUpdate(pod, status)
+	return status, nil
+}
+
+// wait holds a job until it can start
+func (e *runtimeExecutor) wait(j *runtimeJob) {
+	for {
+		e.mu.Lock()
+		j.Waiting = e.mustWait(j)
+		var (
+			waiting = j.Waiting
+			failed  = j.Pod.Annotations[AnnotationFailed] != ""
+			timeout <-chan time.Time
+		)
+		if waiting && j.Reason == j.ScheduleReason {
+			timeout = time.After(j.Until.Sub(e.now()))
+		}
+		e.mu.Unlock()
+
+		if failed {
+			// the job was stopped before it started
+			e.update(j)
+			e.forget(j)
+			return
+		}
+		if !waiting && e.checkGate(j) {
+			waiting = true
+			timeout = time.After(e.gateCheckInterval())
+		}
+		if !waiting {
+			e.run(j)
+			return
+		}
+
+		e.update(j)
+		select {
+		case <-timeout:
+		case <-j.wake:
+		}
+	}
+}
+
+// wakeUp makes a waiting job check whether it can start
+func (j *runtimeJob) wakeUp() {
+	select {
+	case j.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run runs the pod of a job and cleans up once it's done
+func (e *runtimeExecutor) run(j *runtimeJob) {
+	defer e.finish(j)
+
+	now := metav1.Now()
+	e.mu.Lock()
+	j.Pod.CreationTimestamp = now
+	j.Pod.Status = corev1.PodStatus{Phase: corev1.PodPending, StartTime: &now}
+	for _, c := range j.Pod.Spec.InitContainers {
+		j.Pod.Status.InitContainerStatuses = append(j.Pod.Status.InitContainerStatuses, corev1.ContainerStatus{
+			Name:  c.Name,
+			Image: c.Image,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "Pending"}},
+		})
+	}
+	for _, c := range j.Pod.Spec.Containers {
+		j.Pod.Status.ContainerStatuses = append(j.Pod.Status.ContainerStatuses, corev1.ContainerStatus{
+			Name:  c.Name,
+			Image: c.Image,
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}},
+		})
+	}
+	pod := j.Pod.DeepCopy()
+	e.mu.Unlock()
+	e.postCreatePod(pod)
+	e.update(j)
+
+	err := e.runtime.RunPod(pod, &jobReporter{e: e, j: j})
+	if err != nil {
+		e.fail(j, err.Error())
+	}
+}
+
+// jobReporter records the containers of a job starting and stopping
+type jobReporter struct {
+	e *runtimeExecutor
+	j *runtimeJob
+}
+
+// ContainerStarted reports a container as running
+func (r *jobReporter) ContainerStarted(name, id string, init bool) {
+	r.e.mu.Lock()
+	r.j.Containers[name] = id
+	setContainerState(r.j.Pod, name, corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.Now()}})
+	c := runtimeContainer{Name: name, ID: id, Init: init}
+	r.j.started = append(r.j.started, c)
+	for _, sub := range r.j.subs {
+		sub <- c
+	}
+	stopped := r.j.Pod.Annotations[AnnotationFailed] != ""
+	pod := r.j.Pod.DeepCopy()
+	r.e.mu.Unlock()
+	if stopped {
+		// the job was stopped while the container was starting
+		go r.e.runtime.StopPod(pod, map[string]string{name: id}, r.e.gracePeriod(pod))
+	}
+	r.e.update(r.j)
+}
+
+// ContainerStopped reports a container as terminated
+func (r *jobReporter) ContainerStopped(name string, state corev1.ContainerStateTerminated) {
+	r.e.mu.Lock()
+	if s := containerState(r.j.Pod, name); s != nil && s.Running != nil && state.StartedAt.IsZero() {
+		state.StartedAt = s.Running.StartedAt
+	}
+	if state.FinishedAt.IsZero() {
+		state.FinishedAt = metav1.Now()
+	}
+	setContainerState(r.j.Pod, name, corev1.ContainerState{Terminated: &state})
+	r.e.mu.Unlock()
+	r.e.update(r.j)
+}
+
+// InitDone reports that the pod runs its application containers
+func (r *jobReporter) InitDone() {
+	r.e.mu.Lock()
+	r.j.Pod.Status.Phase = corev1.PodRunning
+	r.e.mu.Unlock()
+}
+
+// Stopped returns true once the job was stopped
+func (r *jobReporter) Stopped() bool {
+	r.e.mu.RLock()
+	defer r.e.mu.RUnlock()
+	return r.j.Pod.Annotations[AnnotationFailed] != ""
+}
+
+// setContainerState sets the state of a container in the pod's status
+func setContainerState(pod *corev1.Pod, container string, state corev1.ContainerState) {
+	for _, statuses := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for i := range statuses {
+			if statuses[i].Name != container {
+				continue
+			}
+			statuses[i].State = state
+			statuses[i].Ready = state.Running != nil
+			return
+		}
+	}
+}
+
+// fail marks a job as failed unless it failed already
+func (e *runtimeExecutor) fail(j *runtimeJob, reason string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, failed := j.Pod.Annotations[AnnotationFailed]; !failed {
+		j.Pod.Annotations[AnnotationFailed] = reason
+	}
+}
+
+// finish reports a job as done, removes what its pod left behind, and forgets about the job
+func (e *runtimeExecutor) finish(j *runtimeJob) {
+	e.mu.Lock()
+	// containers which never ran count as terminated, so that the job is done
+	for _, statuses := range [][]corev1.ContainerStatus{j.Pod.Status.InitContainerStatuses, j.Pod.Status.ContainerStatuses} {
+		for i := range statuses {
+			if statuses[i].State.Terminated != nil {
+				continue
+			}
+			statuses[i].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "NotStarted", FinishedAt: metav1.Now()}}
+			statuses[i].Ready = false
+		}
+	}
+	j.Pod.Status.Phase = corev1.PodSucceeded
+	for _, cs := range append(j.Pod.Status.InitContainerStatuses, j.Pod.Status.ContainerStatuses...) {
+		if cs.State.Terminated.ExitCode != 0 {
+			j.Pod.Status.Phase = corev1.PodFailed
+		}
+	}
+	e.mu.Unlock()
+	e.update(j)
+
+	e.mu.Lock()
+	pod := j.Pod.DeepCopy()
+	containers := make(map[string]string, len(j.Containers))
+	for name, id := range j.Containers {
+		containers[name] = id
+	}
+	e.mu.Unlock()
+
+	e.preDeletePod(pod)
+	err := e.runtime.RemovePod(pod, containers)
+	if err != nil {
+		log.WithError(err).WithField("name", pod.Name).Warn("cannot remove job")
+	}
+
+	e.mu.Lock()
+	now := metav1.Now()
+	j.Pod.DeletionTimestamp = &now
+	j.Pod.DeletionGracePeriodSeconds = j.Pod.Spec.TerminationGracePeriodSeconds
+	e.mu.Unlock()
+	e.update(j)
+	e.forget(j)
+}
+
+// forget removes a job from the executor
+func (e *runtimeExecutor) forget(j *runtimeJob) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.jobs, j.Pod.Name)
+	for _, sub := range j.subs {
+		close(sub)
+	}
+	j.subs = nil
+
+	if len(j.tails) > 0 {
+		// give the logs some time to arrive, but don't wait for runtimes which keep log streams open forever
+		tails := j.tails
+		time.AfterFunc(logDrainTimeout, func() {
+			for _, t := range tails {
+				t.Close()
+			}
+		})
+		j.tails = nil
+	}
+}
+
+// gracePeriod is the time the containers of a pod get to stop
+func (e *runtimeExecutor) gracePeriod(pod *corev1.Pod) time.Duration {
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		return time.Duration(*pod.Spec.TerminationGracePeriodSeconds) * time.Second
+	}
+	return e.Config.terminationGracePeriod()
+}
+
+func (e *runtimeExecutor) doHousekeeping(stop <-chan struct{}) {
+	tick := time.NewTicker(e.Config.JobPrepTimeout.Duration / 2)
+	defer tick.Stop()
+	for {
+		type timeout struct{ Name, Msg string }
+		var timedOut []timeout
+
+		e.mu.RLock()
+		for name, j := range e.jobs {
+			if j.Waiting || j.Pod.Annotations[AnnotationFailed] != "" {
+				continue
+			}
+			status, err := getStatus(j.Pod, e.now())
+			if err != nil {
+				log.WithError(err).WithField("name", name).Warn("cannot perform housekeeping")
+				continue
+			}
+			created, err := ptypes.Timestamp(status.Metadata.Created)
+			if err != nil {
+				log.WithError(err).WithField("name", name).Warn("cannot perform housekeeping")
+				continue
+			}
+
+			var ttl time.Duration
+			if status.Phase == v1.JobPhase_PHASE_PREPARING {
+				ttl = e.Config.JobPrepTimeout.Duration
+			} else if t, err := time.ParseDuration(j.Pod.Annotations[AnnotationTimeout]); err == nil {
+				ttl = t
+			} else {
+				ttl = e.Config.JobTotalTimeout.Duration
+			}
+			if e.now().Sub(created) < ttl {
+				continue
+			}
+			timedOut = append(timedOut, timeout{name, fmt.Sprintf("job timed out during %s", strings.TrimPrefix(strings.ToLower(status.Phase.String()), "phase_"))})
+		}
+		e.mu.RUnlock()
+
+		for _, t := range timedOut {
+			log.WithField("job", t.Name).Info(t.Msg)
+			err := e.Stop(t.Name, t.Msg)
+			if err != nil {
+				log.WithError(err).WithField("name", t.Name).Warn("cannot stop job which timed out")
+			}
+		}
+
+		select {
+		case <-tick.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Stop stops a job
+func (e *runtimeExecutor) Stop(name, reason string) error {
+	e.mu.Lock()
+	j, ok := e.jobs[name]
+	if !ok {
+		e.mu.Unlock()
+		return xerrors.Errorf("%w: %s", errNotFound, name)
+	}
+	if _, failed := j.Pod.Annotations[AnnotationFailed]; !failed {
+		j.Pod.Annotations[AnnotationFailed] = reason
+	}
+	var (
+		waiting = j.Waiting
+		pod     = j.Pod.DeepCopy()
+		running = make(map[string]string)
+	)
+	for name, id := range j.Containers {
+		if s := containerState(j.Pod, name); s != nil && s.Running != nil {
+			running[name] = id
+		}
+	}
+	e.mu.Unlock()
+
+	if waiting {
+		j.wakeUp()
+		return nil
+	}
+	go e.runtime.StopPod(pod, running, e.gracePeriod(pod))
+	return nil
+}
+
+// containerState returns the state of a container of the pod, or nil if the container is unknown
+func containerState(pod *corev1.Pod, container string) *corev1.ContainerState {
+	for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if cs.Name == container {
+			return &cs.State
+		}
+	}
+	return nil
+}
+
+// Logs provides the log output of a running job. If the job is unknown, the log is empty.
+func (e *runtimeExecutor) Logs(name string) io.Reader {
+	e.mu.Lock()
+	j, ok := e.jobs[name]
+	if !ok {
+		e.mu.Unlock()
+		return bytes.NewReader(nil)
+	}
+	containers := make(chan runtimeContainer, len(j.Pod.Spec.InitContainers)+len(j.Pod.Spec.Containers))
+	for _, c := range j.started {
+		containers <- c
+	}
+	j.subs = append(j.subs, containers)
+	sliced := applicationContainers(j.Pod) > 1
+	e.mu.Unlock()
+
+	r, w := io.Pipe()
+	go func() {
+		var (
+			wg sync.WaitGroup
+			mu sync.Mutex
+		)
+		for c := range containers {
+			if c.Init {
+				// init containers run one after the other - so do their logs
+				e.tail(j, w, &mu, c.ID, "")
+				continue
+			}
+
+			var slice string
+			if sliced {
+				slice = c.Name
+			}
+			wg.Add(1)
+			go func(id, slice string) {
+				defer wg.Done()
+				e.tail(j, w, &mu, id, slice)
+			}(c.ID, slice)
+		}
+		wg.Wait()
+		w.Close()
+	}()
+	return r
+}
+
+// tail forwards the log of a container line by line. If slice is set, the container's lines are put into slices of
+// that name.
+func (e *runtimeExecutor) tail(j *runtimeJob, out io.Writer, mu *sync.Mutex, id, slice string) {
+	in, err := e.runtime.Logs(id)
+	if err != nil {
+		log.WithError(err).WithField("container", id).Debug("cannot connect to logs")
+		return
+	}
+	defer in.Close()
+
+	e.mu.Lock()
+	j.tails = append(j.tails, in)
+	e.mu.Unlock()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, LogChunkSize+1)
+	scanner.Split(logsanitize.ScanLines(LogChunkSize))
+	var line []byte
+	for scanner.Scan() {
+		line = line[:0]
+		if slice != "" {
+			line = appendContainerLine(line, slice, scanner.Bytes())
+		} else {
+			line = append(line, scanner.Bytes()...)
+		}
+		line = append(line, '\n')
+
+		mu.Lock()
+		_, err := out.Write(line)
+		mu.Unlock()
+		if err != nil {
+			break
+		}
+	}
+	//nolint:errcheck
+	io.Copy(ioutil.Discard, in)
+}
+
+// ContainerLogs returns the complete output of a container of a pod, e.g. of a job which finished.
+// Logs larger than limit bytes are truncated.
+func (e *runtimeExecutor) ContainerLogs(pod, container string, limit int64) ([]byte, error) {
+	e.mu.RLock()
+	var id string
+	if j, ok := e.jobs[pod]; ok {
+		id = j.Containers[container]
+	}
+	e.mu.RUnlock()
+	if id == "" {
+		return nil, xerrors.Errorf("%w: container %s of %s", errNotFound, container, pod)
+	}
+	return e.runtime.ContainerLogs(id, limit)
+}
+
+// GetKnownJobs returns a list of all jobs the executor knows about
+func (e *runtimeExecutor) GetKnownJobs() (jobs []v1.JobStatus, err error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, j := range e.jobs {
+		status, err := getStatus(j.Pod, e.now())
+		if err != nil {
+			return nil, err
+		}
+		if j.Waiting {
+			status.Phase = v1.JobPhase_PHASE_WAITING
+			status.Details = e.waitDetails(j)
+		}
+		jobs = append(jobs, *status)
+	}
+	return jobs, nil
+}
+
+// RegisterResult registers a result produced by a job
+func (e *runtimeExecutor) RegisterResult(jobname string, res *v1.JobResult) error {
+	e.mu.Lock()
+	j, ok := e.jobs[jobname]
+	if !ok {
+		e.mu.Unlock()
+		return xerrors.Errorf("%w: %s", errNotFound, jobname)
+	}
+	var results []v1.JobResult
+	if c, ok := j.Pod.Annotations[AnnotationResults]; ok {
+		err := json.Unmarshal([]byte(c), &results)
+		if err != nil {
+			e.mu.Unlock()
+			return xerrors.Errorf("cannot unmarshal previous results: %w", err)
+		}
+	}
+	results = append(results, *res)
+	ra, err := json.Marshal(results)
+	if err != nil {
+		e.mu.Unlock()
+		return xerrors.Errorf("cannot remarshal results: %w", err)
+	}
+	j.Pod.Annotations[AnnotationResults] = string(ra)
+	e.mu.Unlock()
+
+	_, err = e.update(j)
+	return err
+}
+
+// JobPod returns the pod which describes a job, or nil if the job is unknown
+func (e *runtimeExecutor) JobPod(name string) (*corev1.Pod, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	j, ok := e.jobs[name]
+	if !ok || j.Waiting {
+		return nil, nil
+	}
+	return j.Pod.DeepCopy(), nil
+}
+
+// Diagnostics explains why the pod of a job failed to start or run
+func (e *runtimeExecutor) Diagnostics(pod *corev1.Pod) ([]string, error) {
+	return podDiagnostics(pod), nil
+}
+
+// ResourceUsage returns nil as runtime executors don't track the resources jobs use
+func (e *runtimeExecutor) ResourceUsage(name string) *v1.ResourceUsage {
+	return nil
+}
+
+// Attach runs a command in a container of a running job and waits for it to finish
+func (e *runtimeExecutor) Attach(name string, opts AttachOptions) error {
+	e.mu.RLock()
+	var id string
+	if j, ok := e.jobs[name]; ok {
+		for _, c := range j.Pod.Spec.Containers {
+			if opts.Container != "" && c.Name != opts.Container {
+				continue
+			}
+			if s := containerState(j.Pod, c.Name); s != nil && s.Running != nil {
+				id = j.Containers[c.Name]
+				break
+			}
+		}
+	}
+	e.mu.RUnlock()
+	if id == "" {
+		return xerrors.Errorf("%w: %s", ErrNotRunning, name)
+	}
+
+	if len(opts.Command) == 0 {
+		opts.Command = []string{"sh"}
+	}
+	return e.runtime.Exec(id, opts)
+}
+
+// Approve lets a job which waits for approval start
+func (e *runtimeExecutor) Approve(name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	j, ok := e.jobs[name]
+	if !ok || !j.Waiting || !j.Approval {
+		return xerrors.Errorf("%s: %w", name, ErrNotWaitingForApproval)
+	}
+	j.Approval = false
+	j.wakeUp()
+	return nil
+}
+
+// WaitsForApproval returns true if the job waits for approval
+func (e *runtimeExecutor) WaitsForApproval(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	j, ok := e.jobs[name]
+	return ok && j.Waiting && j.Approval
+}
+
+// Queue lists all jobs which wait to run, in the order we expect them to start
+func (e *runtimeExecutor) Queue() ([]*v1.QueuedJob, error) {
+	e.mu.RLock()
+	var waiting []*runtimeJob
+	for _, j := range e.jobs {
+		if j.Waiting {
+			waiting = append(waiting, j)
+		}
+	}
+	sort.Slice(waiting, func(i, j int) bool {
+		if !waiting[i].Until.Equal(waiting[j].Until) {
+			return waiting[i].Until.Before(waiting[j].Until)
+		}
+		return waiting[i].Since.Before(waiting[j].Since)
+	})
+
+	res := make([]*v1.QueuedJob, 0, len(waiting))
+	for i, j := range waiting {
+		status, err := getStatus(j.Pod, e.now())
+		if err != nil {
+			e.mu.RUnlock()
+			return nil, xerrors.Errorf("cannot get status of %s: %w", j.Pod.Name, err)
+		}
+		status.Phase = v1.JobPhase_PHASE_WAITING
+		status.Details = e.waitDetails(j)
+
+		qj := &v1.QueuedJob{Job: status, Position: int32(i + 1), Reason: j.Reason, Details: status.Details}
+		qj.Since, err = ptypes.TimestampProto(j.Since)
+		if err == nil && !j.Until.IsZero() {
+			qj.Until, err = ptypes.TimestampProto(j.Until)
+		}
+		if err != nil {
+			e.mu.RUnlock()
+			return nil, xerrors.Errorf("cannot convert queue time of %s: %w", j.Pod.Name, err)
+		}
+		res = append(res, qj)
+	}
+	e.mu.RUnlock()
+	return res, nil
+}
+
+// Pause puts the executor into maintenance mode: jobs started from now on wait until the executor resumes.
+// Unlike the Kubernetes executor's, the maintenance mode ends when werft restarts.
+func (e *runtimeExecutor) Pause(reason string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.maintenance = Maintenance{Enabled: true, Reason: reason, Since: e.now()}
+	log.WithField("reason", reason).Info("entered maintenance mode - no new jobs will start")
+	return nil
+}
+
+// Resume ends the maintenance mode and starts all jobs which waited for it to end
+func (e *runtimeExecutor) Resume() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.maintenance = Maintenance{}
+	for _, j := range e.jobs {
+		if j.Waiting {
+			j.wakeUp()
+		}
+	}
+	log.Info("left maintenance mode - starting held jobs")
+	return nil
+}
+
+// Maintenance returns whether the executor is paused
+func (e *runtimeExecutor) Maintenance() Maintenance {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.maintenance
+}
@@ -0,0 +1,281 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LabelStandbyPool marks the idle pods of a standby pool with the name of the pool
+	LabelStandbyPool = "werft.sh/standbyPool"
+
+	// AnnotationStandbyPool names the standby pool whose pod a job claimed
+	AnnotationStandbyPool = "werft.sh/standbyPool"
+
+	// standbyPoolInterval is the time between two checks of the standby pools if no pod was claimed in the meantime
+	standbyPoolInterval = 30 * time.Second
+)
+
+// defaultStandbyCommand keeps a standby pod idle. Most images, including busybox based ones, have sleep.
+var defaultStandbyCommand = []string{"sleep", "2147483647"}
+
+// StandbyPoolConfig keeps idle pods of an image running, which pre-pull the image and reserve room for the jobs using
+// it. Kubernetes cannot hand a running pod over to another pod, hence the jobs don't run in the idle pods: a job claims
+// an idle pod by deleting it, which frees its room on a node that has pulled the image, and prefers that node.
+type StandbyPoolConfig struct {
+	// Name identifies the pool, e.g. golang
+	Name string `yaml:"name"`
+
+	// Image is the image of the jobs the pool serves. Jobs are served if all their containers use this image.
+	Image string `yaml:"image"`
+
+	// Size is the number of idle pods the pool keeps
+	Size int `yaml:"size"`
+
+	// CPU and Memory are the resources each idle pod requests, e.g. 500m and 1Gi. They should match the requests of
+	// the jobs the pool serves, so that a job fits where the pod it claimed ran.
+	CPU    string `yaml:"cpu,omitempty"`
+	Memory string `yaml:"memory,omitempty"`
+
+	// NodeSelector places the idle pods, e.g. on the node pool the jobs the pool serves run on
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+
+	// Command keeps the idle pods running. Defaults to sleep 2147483647.
+	Command []string `yaml:"command,omitempty"`
+}
+
+// validateStandbyPools checks that the standby pools are named uniquely and request valid resources
+func validateStandbyPools(pools []StandbyPoolConfig) error {
+	names := make(map[string]struct{}, len(pools))
+	for _, p := range pools {
+		if p.Name == "" || p.Image == "" {
+			return xerrors.Errorf("standby pools need a name and an image")
+		}
+		if _, exists := names[p.Name]; exists {
+			return xerrors.Errorf("standby pool %s is configured twice", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if p.Size < 0 {
+			return xerrors.Errorf("standby pool %s: size must not be negative", p.Name)
+		}
+		if _, err := p.requests(); err != nil {
+			return xerrors.Errorf("standby pool %s: %w", p.Name, err)
+		}
+	}
+	return nil
+}
+
+func (p *StandbyPoolConfig) requests() (corev1.ResourceList, error) {
+	res := make(corev1.ResourceList)
+	for name, val := range map[corev1.ResourceName]string{corev1.ResourceCPU: p.CPU, corev1.ResourceMemory: p.Memory} {
+		if val == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(val)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid %s request: %w", name, err)
+		}
+		res[name] = q
+	}
+	return res, nil
+}
+
+// serves returns true if all containers of a job's pod use the pool's image
+func (p *StandbyPoolConfig) serves(spec *corev1.PodSpec) bool {
+	if len(spec.Containers) == 0 {
+		return false
+	}
+	for _, c := range spec.Containers {
+		if c.Image != p.Image {
+			return false
+		}
+	}
+	return true
+}
+
+// standbyPod produces an idle pod of a pool
+func (js *Executor) standbyPod(p *StandbyPoolConfig) *corev1.Pod {
+	// validateStandbyPools made sure the requests are valid
+	requests, _ := p.requests()
+	command := p.Command
+	if len(command) == 0 {
+		command = defaultStandbyCommand
+	}
+	var gracePeriod int64
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("werft-standby-%s-", p.Name),
+			Labels: map[string]string{
+				LabelStandbyPool: p.Name,
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:      "standby",
+					Image:     p.Image,
+					Command:   command,
+					Resources: corev1.ResourceRequirements{Requests: requests},
+				},
+			},
+			NodeSelector:                  p.NodeSelector,
+			RestartPolicy:                 corev1.RestartPolicyAlways,
+			TerminationGracePeriodSeconds: &gracePeriod,
+			AutomountServiceAccountToken:  new(bool),
+		},
+	}
+	for _, name := range js.Config.ImagePullSecrets {
+		pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: name})
+	}
+	return pod
+}
+
+// maintainStandbyPools keeps the configured number of idle pods in every standby pool
+func (js *Executor) maintainStandbyPools(stop <-chan struct{}) {
+	if len(js.Config.StandbyPools) == 0 {
+		return
+	}
+
+	tick := time.NewTicker(standbyPoolInterval)
+	defer tick.Stop()
+	for {
+		for i := range js.Config.StandbyPools {
+			err := js.fillStandbyPool(&js.Config.StandbyPools[i])
+			if err != nil {
+				log.WithError(err).WithField("pool", js.Config.StandbyPools[i].Name).Warn("cannot maintain standby pool")
+			}
+		}
+
+		select {
+		case <-tick.C:
+		case <-js.standbyClaimed:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// fillStandbyPool creates idle pods until the pool has as many as configured, and replaces those which failed
+func (js *Executor) fillStandbyPool(p *StandbyPoolConfig) error {
+	pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", LabelStandbyPool, p.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	var alive int
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+			err := js.Client.CoreV1().Pods(js.Config.Namespace).Delete(pod.Name, &metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				log.WithError(err).WithField("name", pod.Name).Warn("cannot delete standby pod")
+			}
+			continue
+		}
+		alive++
+	}
+
+	for ; alive < p.Size; alive++ {
+		_, err := js.Client.CoreV1().Pods(js.Config.Namespace).Create(js.standbyPod(p))
+		if err != nil {
+			return xerrors.Errorf("cannot create standby pod: %w", err)
+		}
+	}
+	return nil
+}
+
+// claimStandbyPod deletes an idle pod of a pool which serves the job, to make room for the job's pod on the idle pod's
+// node, and makes the job's pod prefer that node because it has pulled the job's image already. The scheduler may
+// still place the job elsewhere, e.g. if another pod took the room in the meantime. If no pool serves the job or all
+// its pods are busy starting, the job is scheduled like any other.
+func (js *Executor) claimStandbyPod(pod *corev1.Pod) {
+	var pool *StandbyPoolConfig
+	for i, p := range js.Config.StandbyPools {
+		if p.serves(&pod.Spec) {
+			pool = &js.Config.StandbyPools[i]
+			break
+		}
+	}
+	if pool == nil || pod.Spec.NodeName != "" {
+		return
+	}
+
+	pods, err := js.Client.CoreV1().Pods(js.Config.Namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", LabelStandbyPool, pool.Name),
+	})
+	if err != nil {
+		log.WithError(err).WithField("pool", pool.Name).Warn("cannot claim standby pod")
+		return
+	}
+	// claim the oldest pods first, they're most likely to have their image pulled
+	sort.Slice(pods.Items, func(i, j int) bool {
+		return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+	})
+
+	for _, standby := range pods.Items {
+		if standby.DeletionTimestamp != nil || standby.Status.Phase != corev1.PodRunning || standby.Spec.NodeName == "" {
+			continue
+		}
+
+		// the UID precondition makes sure that concurrently started jobs don't claim the same pod
+		var gracePeriod int64
+		uid := standby.UID
+		err := js.Client.CoreV1().Pods(js.Config.Namespace).Delete(standby.Name, &metav1.DeleteOptions{
+			GracePeriodSeconds: &gracePeriod,
+			Preconditions:      &metav1.Preconditions{UID: &uid},
+		})
+		if errors.IsNotFound(err) || errors.IsConflict(err) {
+			continue
+		}
+		if err != nil {
+			log.WithError(err).WithField("name", standby.Name).Warn("cannot claim standby pod")
+			return
+		}
+
+		preferNode(&pod.Spec, standby.Spec.NodeName)
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[AnnotationStandbyPool] = pool.Name
+		log.WithField("name", pod.Name).WithField("pool", pool.Name).WithField("node", standby.Spec.NodeName).Debug("job claimed standby pod")
+
+		select {
+		case js.standbyClaimed <- struct{}{}:
+		default:
+		}
+		return
+	}
+	log.WithField("name", pod.Name).WithField("pool", pool.Name).Debug("no idle standby pod to claim")
+}
+
+// preferNode makes the scheduler prefer a node for a pod. The pod still runs elsewhere if the node is full.
+func preferNode(spec *corev1.PodSpec, node string) {
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+	if spec.Affinity.NodeAffinity == nil {
+		spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	na := spec.Affinity.NodeAffinity
+	na.PreferredDuringSchedulingIgnoredDuringExecution = append(na.PreferredDuringSchedulingIgnoredDuringExecution, corev1.PreferredSchedulingTerm{
+		Weight: 100,
+		Preference: corev1.NodeSelectorTerm{
+			MatchFields: []corev1.NodeSelectorRequirement{
+				{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{node}},
+			},
+		},
+	})
+}
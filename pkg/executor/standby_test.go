@@ -0,0 +1,259 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestValidateStandbyPools(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Pools []StandbyPoolConfig
+		Error string
+	}{
+		{Name: "none"},
+		{Name: "valid", Pools: []StandbyPoolConfig{{Name: "golang", Image: "golang:1.14", Size: 3, CPU: "500m", Memory: "1Gi"}, {Name: "node", Image: "node:12"}}},
+		{Name: "no name", Pools: []StandbyPoolConfig{{Image: "golang:1.14"}}, Error: "need a name and an image"},
+		{Name: "no image", Pools: []StandbyPoolConfig{{Name: "golang"}}, Error: "need a name and an image"},
+		{Name: "duplicate", Pools: []StandbyPoolConfig{{Name: "golang", Image: "golang:1.14"}, {Name: "golang", Image: "golang:1.13"}}, Error: "configured twice"},
+		{Name: "negative size", Pools: []StandbyPoolConfig{{Name: "golang", Image: "golang:1.14", Size: -1}}, Error: "size must not be negative"},
+		{Name: "invalid cpu", Pools: []StandbyPoolConfig{{Name: "golang", Image: "golang:1.14", CPU: "lots"}}, Error: "invalid cpu request"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := validateStandbyPools(test.Pools)
+			if test.Error == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.Error) {
+				t.Errorf("expected error containing %q, got %v", test.Error, err)
+			}
+		})
+	}
+}
+
+func TestStandbyPoolServes(t *testing.T) {
+	pool := StandbyPoolConfig{Name: "golang", Image: "golang:1.14"}
+	tests := []struct {
+		Name     string
+		Images   []string
+		Expected bool
+	}{
+		{Name: "no containers"},
+		{Name: "pool image", Images: []string{"golang:1.14"}, Expected: true},
+		{Name: "all containers", Images: []string{"golang:1.14", "golang:1.14"}, Expected: true},
+		{Name: "other image", Images: []string{"golang:1.13"}},
+		{Name: "some containers", Images: []string{"golang:1.14", "alpine"}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var spec corev1.PodSpec
+			for i, img := range test.Images {
+				spec.Containers = append(spec.Containers, corev1.Container{Name: fmt.Sprintf("c%d", i), Image: img})
+			}
+			if act := pool.serves(&spec); act != test.Expected {
+				t.Errorf("expected %v, got %v", test.Expected, act)
+			}
+		})
+	}
+}
+
+// newStandbyExecutor produces an executor whose client names the pods created using GenerateName, which the fake
+// clientset doesn't
+func newStandbyExecutor(pool StandbyPoolConfig, pods ...runtime.Object) (*Executor, *fake.Clientset) {
+	const ns = "werft"
+	client := fake.NewSimpleClientset(pods...)
+	var n int
+	client.PrependReactor("create", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		pod := action.(ktesting.CreateAction).GetObject().(*corev1.Pod)
+		if pod.Name == "" {
+			n++
+			pod.Name = fmt.Sprintf("%s%d", pod.GenerateName, n)
+		}
+		return false, nil, nil
+	})
+	return &Executor{
+		Config:         Config{Namespace: ns, StandbyPools: []StandbyPoolConfig{pool}},
+		Client:         client,
+		standbyClaimed: make(chan struct{}, 1),
+	}, client
+}
+
+func standbyPod(name, pool string, phase corev1.PodPhase, node string, age time.Duration) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "werft",
+			Labels:            map[string]string{LabelStandbyPool: pool},
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec:   corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func listStandbyPods(t *testing.T, client *fake.Clientset) []string {
+	pods, err := client.CoreV1().Pods("werft").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res []string
+	for _, p := range pods.Items {
+		res = append(res, p.Name)
+	}
+	sort.Strings(res)
+	return res
+}
+
+func TestFillStandbyPool(t *testing.T) {
+	pool := StandbyPoolConfig{Name: "golang", Image: "golang:1.14", Size: 3, CPU: "500m"}
+	tests := []struct {
+		Name     string
+		Pods     []runtime.Object
+		Expected []string
+	}{
+		{
+			Name:     "empty pool",
+			Expected: []string{"werft-standby-golang-1", "werft-standby-golang-2", "werft-standby-golang-3"},
+		},
+		{
+			Name: "full pool",
+			Pods: []runtime.Object{
+				standbyPod("a", "golang", corev1.PodRunning, "node-a", time.Hour),
+				standbyPod("b", "golang", corev1.PodPending, "", time.Minute),
+				standbyPod("c", "golang", corev1.PodRunning, "node-b", time.Minute),
+			},
+			Expected: []string{"a", "b", "c"},
+		},
+		{
+			Name: "failed pods are replaced",
+			Pods: []runtime.Object{
+				standbyPod("a", "golang", corev1.PodRunning, "node-a", time.Hour),
+				standbyPod("b", "golang", corev1.PodFailed, "node-a", time.Hour),
+				standbyPod("c", "golang", corev1.PodSucceeded, "node-b", time.Hour),
+			},
+			Expected: []string{"a", "werft-standby-golang-1", "werft-standby-golang-2"},
+		},
+		{
+			Name: "other pools don't count",
+			Pods: []runtime.Object{
+				standbyPod("a", "golang", corev1.PodRunning, "node-a", time.Hour),
+				standbyPod("b", "node", corev1.PodRunning, "node-a", time.Hour),
+			},
+			Expected: []string{"a", "b", "werft-standby-golang-1", "werft-standby-golang-2"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			js, client := newStandbyExecutor(pool, test.Pods...)
+			err := js.fillStandbyPool(&js.Config.StandbyPools[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act := listStandbyPods(t, client); strings.Join(act, ",") != strings.Join(test.Expected, ",") {
+				t.Errorf("expected pods %v, got %v", test.Expected, act)
+			}
+
+			created, err := client.CoreV1().Pods("werft").Get("werft-standby-golang-1", metav1.GetOptions{})
+			if err != nil {
+				return
+			}
+			if created.Labels[LabelStandbyPool] != "golang" {
+				t.Errorf("expected standby pod to be labelled with its pool, got %v", created.Labels)
+			}
+			c := created.Spec.Containers[0]
+			if c.Image != pool.Image || strings.Join(c.Command, " ") != strings.Join(defaultStandbyCommand, " ") {
+				t.Errorf("unexpected standby container %+v", c)
+			}
+			if cpu := c.Resources.Requests[corev1.ResourceCPU]; cpu.String() != "500m" {
+				t.Errorf("expected standby pod to request 500m CPU, got %s", cpu.String())
+			}
+		})
+	}
+}
+
+func TestClaimStandbyPod(t *testing.T) {
+	pool := StandbyPoolConfig{Name: "golang", Image: "golang:1.14", Size: 3}
+	js, client := newStandbyExecutor(pool,
+		standbyPod("newer", "golang", corev1.PodRunning, "node-b", time.Minute),
+		standbyPod("older", "golang", corev1.PodRunning, "node-a", time.Hour),
+		standbyPod("pending", "golang", corev1.PodPending, "", 2*time.Hour),
+	)
+
+	jobPod := func(image string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "job"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Image: image}}},
+		}
+	}
+	preferredNode := func(pod *corev1.Pod) string {
+		if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil {
+			return ""
+		}
+		terms := pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		if len(terms) != 1 {
+			t.Fatalf("expected one preferred node, got %v", terms)
+		}
+		return terms[0].Preference.MatchFields[0].Values[0]
+	}
+	claimed := func() bool {
+		select {
+		case <-js.standbyClaimed:
+			return true
+		default:
+			return false
+		}
+	}
+
+	tests := []struct {
+		Name  string
+		Image string
+		Node  string
+		Pods  []string
+	}{
+		{Name: "other image", Image: "alpine", Pods: []string{"newer", "older", "pending"}},
+		{Name: "oldest running pod first", Image: "golang:1.14", Node: "node-a", Pods: []string{"newer", "pending"}},
+		{Name: "next running pod", Image: "golang:1.14", Node: "node-b", Pods: []string{"pending"}},
+		{Name: "no running pod left", Image: "golang:1.14", Pods: []string{"pending"}},
+	}
+	for _, test := range tests {
+		// the steps build on one another, hence they don't run as subtests
+		pod := jobPod(test.Image)
+		js.claimStandbyPod(pod)
+
+		if act := preferredNode(pod); act != test.Node {
+			t.Errorf("%s: expected the job to prefer node %q, got %q", test.Name, test.Node, act)
+		}
+		if act := pod.Annotations[AnnotationStandbyPool]; (act == pool.Name) != (test.Node != "") {
+			t.Errorf("%s: unexpected standby pool annotation %q", test.Name, act)
+		}
+		if act := claimed(); act != (test.Node != "") {
+			t.Errorf("%s: expected the pool to be told about the claim: %v, got %v", test.Name, test.Node != "", act)
+		}
+		if act := listStandbyPods(t, client); strings.Join(act, ",") != strings.Join(test.Pods, ",") {
+			t.Errorf("%s: expected standby pods %v, got %v", test.Name, test.Pods, act)
+		}
+	}
+
+	// jobs which are bound to a node already aren't moved
+	pod := jobPod("golang:1.14")
+	pod.Spec.NodeName = "node-c"
+	js.claimStandbyPod(pod)
+	if pod.Spec.Affinity != nil {
+		t.Errorf("expected job bound to a node to stay unchanged, got %v", pod.Spec.Affinity)
+	}
+}
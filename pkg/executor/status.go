@@ -7,7 +7,6 @@ import (
 	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
-	"github.com/gogo/protobuf/jsonpb"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"golang.org/x/xerrors"
@@ -22,11 +21,11 @@ const (
 	LabelMutex = "werft.sh/mutex"
 )
 
-// extracts the phase from the job object
-func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
+// extracts the phase from the job object. Jobs which are done finished at now.
+func getStatus(obj *corev1.Pod, now time.Time) (status *v1.JobStatus, err error) {
 	defer func() {
 		if status != nil && status.Phase == v1.JobPhase_PHASE_DONE {
-			status.Metadata.Finished = ptypes.TimestampNow()
+			status.Metadata.Finished, _ = ptypes.TimestampProto(now)
 		}
 	}()
 
@@ -39,8 +38,7 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 	if !ok {
 		return nil, xerrors.Errorf("job has no metadata")
 	}
-	var md v1.JobMetadata
-	err = jsonpb.UnmarshalString(rawmd, &md)
+	md, err := decodeMetadata(rawmd)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot unmarshal metadata: %w", err)
 	}
@@ -66,28 +64,52 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 		}
 	}
 
+	attempt := 1
+	if a, ok := obj.Annotations[AnnotationAttempt]; ok {
+		attempt, err = strconv.Atoi(a)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot parse %s annotation: %w", AnnotationAttempt, err)
+		}
+	}
+
 	status = &v1.JobStatus{
 		Name:     name,
-		Metadata: &md,
+		Metadata: md,
 		Phase:    v1.JobPhase_PHASE_UNKNOWN,
 		Conditions: &v1.JobConditions{
 			Success:   true,
 			CanReplay: canReplay,
 			WaitUntil: waitUntil,
+			Attempt:   int32(attempt),
 		},
 		Results: results,
 	}
 
+	// evicted pods won't run again, no matter what their containers say
+	if obj.Status.Phase == corev1.PodFailed && obj.Status.Reason == "Evicted" {
+		status.Phase = v1.JobPhase_PHASE_DONE
+		status.Conditions.Success = false
+		status.Conditions.InfrastructureFailure = true
+		status.Details = obj.Status.Message
+		return
+	}
+
 	var (
 		statuses      = append(obj.Status.InitContainerStatuses, obj.Status.ContainerStatuses...)
 		anyFailed     bool
+		oomKilled     bool
 		maxRestart    int32
 		allTerminated = len(statuses) != 0
 	)
 	for _, cs := range statuses {
-		if w := cs.State.Waiting; w != nil && w.Reason == "ErrImagePull" {
+		if cs.Name == DebugContainerName {
+			// the debug container keeps running once the job has failed
+			continue
+		}
+		if w := cs.State.Waiting; w != nil && (w.Reason == "ErrImagePull" || w.Reason == "ImagePullBackOff") {
 			status.Phase = v1.JobPhase_PHASE_DONE
 			status.Conditions.Success = false
+			status.Conditions.InfrastructureFailure = true
 			status.Details = w.Message
 			return
 		}
@@ -99,6 +121,12 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 		} else {
 			allTerminated = false
 		}
+		if t := cs.State.Terminated; t != nil && t.Reason == "OOMKilled" {
+			oomKilled = true
+		}
+		if t := cs.LastTerminationState.Terminated; t != nil && t.Reason == "OOMKilled" {
+			oomKilled = true
+		}
 
 		if cs.RestartCount >= maxRestart {
 			maxRestart = cs.RestartCount
@@ -106,6 +134,7 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 	}
 	status.Conditions.FailureCount = maxRestart
 	status.Conditions.Success = !(anyFailed || maxRestart > getFailureLimit(obj))
+	status.Conditions.InfrastructureFailure = !status.Conditions.Success && oomKilled
 	status.Conditions.DidExecute = obj.Status.Phase != "" || len(statuses) > 0
 
 	if msg, failed := obj.Annotations[AnnotationFailed]; failed {
@@ -114,6 +143,8 @@ func getStatus(obj *corev1.Pod) (status *v1.JobStatus, err error) {
 			status.Phase = v1.JobPhase_PHASE_CLEANUP
 		}
 		status.Conditions.Success = false
+		// the job was failed explicitly (e.g. stopped or timed out), hence this is not an infrastructure failure
+		status.Conditions.InfrastructureFailure = false
 		status.Details = msg
 
 		return
@@ -0,0 +1,83 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// defaultTerminationGracePeriod is the time containers have between SIGTERM and SIGKILL if the config doesn't say otherwise
+	defaultTerminationGracePeriod = 5 * time.Second
+
+	// exitCodeSIGKILL is the exit code of containers which were killed
+	exitCodeSIGKILL = 128 + 9
+)
+
+func (c *Config) terminationGracePeriod() time.Duration {
+	if c.TerminationGracePeriod == nil {
+		return defaultTerminationGracePeriod
+	}
+	return c.TerminationGracePeriod.Duration
+}
+
+// Termination describes how the containers of a job's pod stopped after the pod was deleted
+type Termination struct {
+	// GracePeriod is the time the containers had between SIGTERM and SIGKILL
+	GracePeriod time.Duration
+
+	// Stopped lists the containers which stopped within the grace period
+	Stopped []string
+
+	// Killed lists the containers which were still running once the grace period was over
+	Killed []string
+}
+
+// String describes the termination, e.g. for the job's events
+func (t *Termination) String() string {
+	if len(t.Killed) == 0 {
+		return fmt.Sprintf("containers stopped within the %v grace period after SIGTERM: %s", t.GracePeriod, strings.Join(t.Stopped, ", "))
+	}
+	return fmt.Sprintf("containers did not stop within the %v grace period after SIGTERM and were killed: %s", t.GracePeriod, strings.Join(t.Killed, ", "))
+}
+
+// PodTermination returns how the containers of a deleted pod stopped once they all have. It returns nil for pods which
+// aren't being deleted, while containers still run, and if all containers had stopped before the pod was deleted,
+// e.g. because the job was done.
+func PodTermination(pod *corev1.Pod) *Termination {
+	if pod == nil || pod.DeletionTimestamp == nil {
+		return nil
+	}
+
+	// the deletion timestamp is when the grace period ends
+	var gracePeriod time.Duration
+	if pod.DeletionGracePeriodSeconds != nil {
+		gracePeriod = time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second
+	}
+	deleted := pod.DeletionTimestamp.Add(-gracePeriod)
+
+	res := &Termination{GracePeriod: gracePeriod}
+	for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		if cs.Name == DebugContainerName {
+			continue
+		}
+		if cs.State.Running != nil {
+			return nil
+		}
+		t := cs.State.Terminated
+		if t == nil || t.FinishedAt.Time.Before(deleted) {
+			continue
+		}
+		if t.ExitCode == exitCodeSIGKILL {
+			res.Killed = append(res.Killed, cs.Name)
+		} else {
+			res.Stopped = append(res.Stopped, cs.Name)
+		}
+	}
+	if len(res.Stopped) == 0 && len(res.Killed) == 0 {
+		return nil
+	}
+	return res
+}
@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPodTermination(t *testing.T) {
+	var (
+		deleted     = time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+		gracePeriod = int64(30)
+		deadline    = metav1.NewTime(deleted.Add(time.Duration(gracePeriod) * time.Second))
+	)
+	running := func(name string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: name, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	}
+	terminated := func(name string, after time.Duration, exitCode int32) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: name, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode:   exitCode,
+			FinishedAt: metav1.NewTime(deleted.Add(after)),
+		}}}
+	}
+
+	tests := []struct {
+		Name       string
+		NotDeleted bool
+		Init       []corev1.ContainerStatus
+		Containers []corev1.ContainerStatus
+		Expected   string
+	}{
+		{Name: "not deleted", NotDeleted: true, Containers: []corev1.ContainerStatus{terminated("build", time.Second, 0)}},
+		{Name: "still running", Containers: []corev1.ContainerStatus{terminated("build", time.Second, 0), running("sidecar")}},
+		{Name: "done before deletion", Containers: []corev1.ContainerStatus{terminated("build", -time.Minute, 0)}},
+		{
+			Name:       "stopped after SIGTERM",
+			Containers: []corev1.ContainerStatus{terminated("build", time.Second, 143), terminated("sidecar", 2*time.Second, 0)},
+			Expected:   "containers stopped within the 30s grace period after SIGTERM: build, sidecar",
+		},
+		{
+			Name:       "killed",
+			Init:       []corev1.ContainerStatus{terminated("checkout", -time.Minute, 0)},
+			Containers: []corev1.ContainerStatus{terminated("build", 30*time.Second, exitCodeSIGKILL), terminated("sidecar", time.Second, 0)},
+			Expected:   "containers did not stop within the 30s grace period after SIGTERM and were killed: build",
+		},
+		{
+			Name:       "debug container is ignored",
+			Containers: []corev1.ContainerStatus{terminated("build", time.Second, 0), running(DebugContainerName)},
+			Expected:   "containers stopped within the 30s grace period after SIGTERM: build",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{InitContainerStatuses: test.Init, ContainerStatuses: test.Containers}}
+			if !test.NotDeleted {
+				pod.DeletionTimestamp = &deadline
+				pod.DeletionGracePeriodSeconds = &gracePeriod
+			}
+
+			term := PodTermination(pod)
+			if test.Expected == "" {
+				if term != nil {
+					t.Errorf("expected no termination, got %q", term.String())
+				}
+				return
+			}
+			if term == nil {
+				t.Fatalf("expected %q, got no termination", test.Expected)
+			}
+			if act := term.String(); act != test.Expected {
+				t.Errorf("expected %q, got %q", test.Expected, act)
+			}
+		})
+	}
+
+	if PodTermination(nil) != nil {
+		t.Error("expected no termination without a pod")
+	}
+}
+
+func TestTerminationGracePeriod(t *testing.T) {
+	var cfg Config
+	if act := cfg.terminationGracePeriod(); act != defaultTerminationGracePeriod {
+		t.Errorf("expected default grace period, got %v", act)
+	}
+	cfg.TerminationGracePeriod = &Duration{Duration: time.Minute}
+	if act := cfg.terminationGracePeriod(); act != time.Minute {
+		t.Errorf("expected configured grace period, got %v", act)
+	}
+}
@@ -0,0 +1,440 @@
+package filterexpr
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// Expression is a boolean expression over string fields in a subset of the Common Expression Language (CEL), e.g.
+//
+//	type == "job" && job.repo.owner == "32leaves" && !(job.phase in ["waiting", "cleanup"])
+//
+// Fields are paths like job.repo.owner; segments which aren't identifiers can be indexed, e.g. job.label["app/tier"].
+// Operands are fields, strings in single or double quotes, numbers, true and false - all of which compare as strings.
+// Expressions support ==, !=, in [...], the methods startsWith, endsWith and contains, !, && and || and parentheses.
+// A field on its own is true if it is set, not empty and not false. Fields which aren't set are empty.
+type Expression struct {
+	src  string
+	root exprNode
+}
+
+// ParseExpression parses an expression. The empty expression matches everything.
+func ParseExpression(src string) (*Expression, error) {
+	if strings.TrimSpace(src) == "" {
+		return &Expression{src: src}, nil
+	}
+
+	toks, err := lexExpression(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{toks: toks}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, xerrors.Errorf("unexpected %s at position %d", t, t.pos)
+	}
+	return &Expression{src: src, root: root}, nil
+}
+
+// String returns the expression as it was parsed
+func (e *Expression) String() string {
+	return e.src
+}
+
+// Matches evaluates the expression against fields
+func (e *Expression) Matches(fields map[string]string) bool {
+	if e == nil || e.root == nil {
+		return true
+	}
+	return e.root.eval(fields)
+}
+
+// EventFields returns the fields of a server event expressions can refer to: type, message, job.name, job.phase,
+// job.owner, job.trigger, job.success, job.repo.*, job.annotation.*, job.label.*, event.type, event.phase and attr.*
+func EventFields(evt *v1.ServerEvent) map[string]string {
+	res := map[string]string{
+		"type":    evt.Type,
+		"message": evt.Message,
+	}
+	if evt.Job != nil {
+		for k, v := range JobFields(evt.Job) {
+			res["job."+k] = v
+		}
+		res["job.success"] = strconv.FormatBool(evt.Job.Conditions != nil && evt.Job.Conditions.Success)
+	}
+	if evt.JobName != "" {
+		res["job.name"] = evt.JobName
+	}
+	if evt.JobEvent != nil {
+		res["event.type"] = strings.ToLower(strings.TrimPrefix(evt.JobEvent.Type.String(), "EVENT_"))
+		res["event.phase"] = strings.ToLower(strings.TrimPrefix(evt.JobEvent.Phase.String(), "PHASE_"))
+		if evt.Message == "" {
+			res["message"] = evt.JobEvent.Message
+		}
+	}
+	for k, v := range evt.Attributes {
+		res["attr."+k] = v
+	}
+	return res
+}
+
+type exprNode interface {
+	eval(fields map[string]string) bool
+}
+
+// exprOperand is a field or a literal
+type exprOperand struct {
+	field   string
+	literal string
+}
+
+func (o exprOperand) value(fields map[string]string) string {
+	if o.field != "" {
+		return fields[o.field]
+	}
+	return o.literal
+}
+
+type (
+	exprOr    struct{ l, r exprNode }
+	exprAnd   struct{ l, r exprNode }
+	exprNot   struct{ n exprNode }
+	exprTruth struct{ o exprOperand }
+	exprCmp   struct {
+		l, r exprOperand
+		op   string
+	}
+	exprIn struct {
+		l    exprOperand
+		list []exprOperand
+	}
+)
+
+func (n exprOr) eval(f map[string]string) bool  { return n.l.eval(f) || n.r.eval(f) }
+func (n exprAnd) eval(f map[string]string) bool { return n.l.eval(f) && n.r.eval(f) }
+func (n exprNot) eval(f map[string]string) bool { return !n.n.eval(f) }
+
+func (n exprTruth) eval(f map[string]string) bool {
+	v := n.o.value(f)
+	return v != "" && v != "false"
+}
+
+func (n exprCmp) eval(f map[string]string) bool {
+	l, r := n.l.value(f), n.r.value(f)
+	switch n.op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "startsWith":
+		return strings.HasPrefix(l, r)
+	case "endsWith":
+		return strings.HasSuffix(l, r)
+	case "contains":
+		return strings.Contains(l, r)
+	}
+	return false
+}
+
+func (n exprIn) eval(f map[string]string) bool {
+	l := n.l.value(f)
+	for _, o := range n.list {
+		if o.value(f) == l {
+			return true
+		}
+	}
+	return false
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOp
+)
+
+type exprToken struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+func (t exprToken) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of expression"
+	case tokString:
+		return "string \"" + t.val + "\""
+	default:
+		return "\"" + t.val + "\""
+	}
+}
+
+// lexExpression splits an expression into tokens
+func lexExpression(src string) ([]exprToken, error) {
+	var (
+		res []exprToken
+		rs  = []rune(src)
+	)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			var (
+				val strings.Builder
+				j   = i + 1
+			)
+			for ; j < len(rs) && rs[j] != r; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+				}
+				val.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, xerrors.Errorf("unterminated string at position %d", i)
+			}
+			res = append(res, exprToken{kind: tokString, val: val.String(), pos: i})
+			i = j + 1
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_') {
+				j++
+			}
+			res = append(res, exprToken{kind: tokIdent, val: string(rs[i:j]), pos: i})
+			i = j
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			res = append(res, exprToken{kind: tokNumber, val: string(rs[i:j]), pos: i})
+			i = j
+		default:
+			var op string
+			for _, candidate := range []string{"==", "!=", "&&", "||", "!", "(", ")", "[", "]", ",", "."} {
+				if strings.HasPrefix(string(rs[i:]), candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, xerrors.Errorf("unexpected %q at position %d", r, i)
+			}
+			res = append(res, exprToken{kind: tokOp, val: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(res, exprToken{kind: tokEOF, pos: len(rs)}), nil
+}
+
+type exprParser struct {
+	toks []exprToken
+	pos  int
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.toks[p.pos]
+}
+
+func (p *exprParser) next() exprToken {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *exprParser) isOp(val string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.val == val
+}
+
+func (p *exprParser) expectOp(val string) error {
+	t := p.next()
+	if t.kind != tokOp || t.val != val {
+		return xerrors.Errorf("expected \"%s\" at position %d, found %s", val, t.pos, t)
+	}
+	return nil
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = exprOr{l, r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = exprAnd{l, r}
+	}
+	return l, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.isOp("!") {
+		p.next()
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return exprNot{n}, nil
+	}
+	if p.isOp("(") {
+		p.next()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return n, p.expectOp(")")
+	}
+	return p.parseComparison()
+}
+
+// exprMethods are the methods fields and literals have
+var exprMethods = map[string]struct{}{
+	"startsWith": {},
+	"endsWith":   {},
+	"contains":   {},
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	l, method, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if method != "" {
+		if err := p.expectOp("("); err != nil {
+			return nil, err
+		}
+		r, _, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return exprCmp{l: l, r: r, op: method}, p.expectOp(")")
+	}
+
+	switch t := p.peek(); {
+	case t.kind == tokOp && (t.val == "==" || t.val == "!="):
+		p.next()
+		r, method, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if method != "" {
+			return nil, xerrors.Errorf("unexpected method %s at position %d", method, t.pos)
+		}
+		return exprCmp{l: l, r: r, op: t.val}, nil
+	case t.kind == tokIdent && t.val == "in":
+		p.next()
+		if err := p.expectOp("["); err != nil {
+			return nil, err
+		}
+		var list []exprOperand
+		for !p.isOp("]") {
+			if len(list) > 0 {
+				if err := p.expectOp(","); err != nil {
+					return nil, err
+				}
+			}
+			o, _, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, o)
+		}
+		p.next()
+		return exprIn{l: l, list: list}, nil
+	default:
+		return exprTruth{l}, nil
+	}
+}
+
+// parseOperand parses a field or literal. If a method call follows the operand, it returns the method's name.
+func (p *exprParser) parseOperand() (o exprOperand, method string, err error) {
+	t := p.next()
+	switch t.kind {
+	case tokString, tokNumber:
+		o = exprOperand{literal: t.val}
+	case tokIdent:
+		if t.val == "true" || t.val == "false" {
+			o = exprOperand{literal: t.val}
+			break
+		}
+		segs := []string{t.val}
+		for {
+			if p.isOp("[") {
+				p.next()
+				idx := p.next()
+				if idx.kind != tokString {
+					return o, "", xerrors.Errorf("expected string index at position %d, found %s", idx.pos, idx)
+				}
+				segs = append(segs, idx.val)
+				if err := p.expectOp("]"); err != nil {
+					return o, "", err
+				}
+				continue
+			}
+			if !p.isOp(".") {
+				break
+			}
+			p.next()
+			seg := p.next()
+			if seg.kind != tokIdent {
+				return o, "", xerrors.Errorf("expected field at position %d, found %s", seg.pos, seg)
+			}
+			if p.isOp("(") {
+				if _, ok := exprMethods[seg.val]; !ok {
+					return o, "", xerrors.Errorf("unknown method %s at position %d", seg, seg.pos)
+				}
+				method = seg.val
+				break
+			}
+			segs = append(segs, seg.val)
+		}
+		o = exprOperand{field: strings.Join(segs, ".")}
+	default:
+		return o, "", xerrors.Errorf("expected field or value at position %d, found %s", t.pos, t)
+	}
+
+	if method == "" && p.isOp(".") {
+		// methods of literals, e.g. "foo".contains(field)
+		p.next()
+		seg := p.next()
+		if _, ok := exprMethods[seg.val]; !ok || seg.kind != tokIdent {
+			return o, "", xerrors.Errorf("unknown method %s at position %d", seg, seg.pos)
+		}
+		method = seg.val
+	}
+	return o, method, nil
+}
@@ -0,0 +1,97 @@
+package filterexpr_test
+
+import (
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+)
+
+func TestExpression(t *testing.T) {
+	fields := map[string]string{
+		"type":             "job",
+		"job.name":         "werft-build.12",
+		"job.phase":        "running",
+		"job.repo.owner":   "32leaves",
+		"job.success":      "false",
+		"job.label.app/ui": "yes",
+	}
+	tests := []struct {
+		Expr    string
+		Matches bool
+		Error   string
+	}{
+		{"", true, ""},
+		{"type == \"job\"", true, ""},
+		{"type != 'job'", false, ""},
+		{"type == \"job\" && job.repo.owner == \"someone\"", false, ""},
+		{"type == \"queue\" || job.repo.owner == \"32leaves\"", true, ""},
+		{"!(job.phase in [\"waiting\", \"cleanup\"])", true, ""},
+		{"job.phase in [\"running\"]", true, ""},
+		{"job.name.startsWith(\"werft-\") && job.name.endsWith(\".12\")", true, ""},
+		{"job.name.contains(\"build\")", true, ""},
+		{"\"werft-build.12 werft-build.13\".contains(job.name)", true, ""},
+		{"job.success", false, ""},
+		{"!job.success && job.label[\"app/ui\"]", true, ""},
+		{"job.annotation.missing", false, ""},
+		{"job.success == false", true, ""},
+		{"type ==", false, "expected field or value at position 7, found end of expression"},
+		{"(type == \"job\"", false, "expected \")\" at position 14, found end of expression"},
+		{"type == \"job", false, "unterminated string at position 8"},
+		{"type = \"job\"", false, "unexpected '=' at position 5"},
+		{"job.name.matches(\"x\")", false, "unknown method \"matches\" at position 9"},
+	}
+
+	for _, test := range tests {
+		expr, err := filterexpr.ParseExpression(test.Expr)
+		if err != nil {
+			if err.Error() != test.Error {
+				t.Errorf("%s: %v != %v", test.Expr, err, test.Error)
+			}
+			continue
+		}
+		if test.Error != "" {
+			t.Errorf("%s: expected error %s", test.Expr, test.Error)
+			continue
+		}
+		if act := expr.Matches(fields); act != test.Matches {
+			t.Errorf("%s: expected %v but got %v", test.Expr, test.Matches, act)
+		}
+	}
+}
+
+func TestEventFields(t *testing.T) {
+	evt := &v1.ServerEvent{
+		Type: "job_event",
+		Job: &v1.JobStatus{
+			Name:       "werft-build.12",
+			Phase:      v1.JobPhase_PHASE_RUNNING,
+			Metadata:   &v1.JobMetadata{Owner: "foo", Repository: &v1.Repository{Owner: "32leaves"}, Labels: map[string]string{"tier": "ui"}},
+			Conditions: &v1.JobConditions{Success: true},
+		},
+		JobEvent:   &v1.JobEvent{Type: v1.JobEventType_EVENT_QUEUED, Message: "waiting for a slot"},
+		Attributes: map[string]string{"revision": "abc"},
+	}
+	tests := []struct {
+		Expr    string
+		Matches bool
+	}{
+		{"type == \"job_event\" && job.name == \"werft-build.12\"", true},
+		{"job.phase == \"running\" && job.success", true},
+		{"job.repo.owner == \"32leaves\" && job.label.tier == \"ui\"", true},
+		{"event.type == \"queued\" && message.startsWith(\"waiting\")", true},
+		{"attr.revision == \"abc\"", true},
+		{"job.owner == \"bar\"", false},
+	}
+	fields := filterexpr.EventFields(evt)
+	for _, test := range tests {
+		expr, err := filterexpr.ParseExpression(test.Expr)
+		if err != nil {
+			t.Errorf("%s: %v", test.Expr, err)
+			continue
+		}
+		if act := expr.Matches(fields); act != test.Matches {
+			t.Errorf("%s: expected %v but got %v", test.Expr, test.Matches, act)
+		}
+	}
+}
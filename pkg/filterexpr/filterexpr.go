@@ -6,11 +6,55 @@ import (
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 )
 
 // ErrMissingOp indicates that the expression was not complete
 var ErrMissingOp = fmt.Errorf("missing operator")
 
+// LabelFieldPrefix is prepended to label keys to form filter fields, e.g. label.team
+const LabelFieldPrefix = "label."
+
+// ParseLabelSelector turns a Kubernetes-style label selector (e.g. "team=platform,stage in (build,test),!experimental")
+// into filter expressions over label fields.
+func ParseLabelSelector(selector string) ([]*v1.FilterExpression, error) {
+	reqs, err := labels.ParseToRequirements(selector)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid label selector: %w", err)
+	}
+
+	var res []*v1.FilterExpression
+	for _, req := range reqs {
+		field := LabelFieldPrefix + req.Key()
+		switch req.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+			var terms []*v1.FilterTerm
+			for _, val := range req.Values().List() {
+				terms = append(terms, &v1.FilterTerm{Field: field, Value: val, Operation: v1.FilterOp_OP_EQUALS})
+			}
+			res = append(res, &v1.FilterExpression{Terms: terms})
+		case selection.NotEquals, selection.NotIn:
+			for _, val := range req.Values().List() {
+				res = append(res, &v1.FilterExpression{Terms: []*v1.FilterTerm{
+					&v1.FilterTerm{Field: field, Value: val, Operation: v1.FilterOp_OP_EQUALS, Negate: true},
+				}})
+			}
+		case selection.Exists:
+			res = append(res, &v1.FilterExpression{Terms: []*v1.FilterTerm{
+				&v1.FilterTerm{Field: field, Operation: v1.FilterOp_OP_EXISTS},
+			}})
+		case selection.DoesNotExist:
+			res = append(res, &v1.FilterExpression{Terms: []*v1.FilterTerm{
+				&v1.FilterTerm{Field: field, Operation: v1.FilterOp_OP_EXISTS, Negate: true},
+			}})
+		default:
+			return nil, xerrors.Errorf("unsupported label selector operator %s", req.Operator())
+		}
+	}
+	return res, nil
+}
+
 // Parse parses a list of expressions
 func Parse(exprs []string) ([]*v1.FilterTerm, error) {
 	ops := map[string]v1.FilterOp{
@@ -80,30 +124,18 @@ func MatchesFilter(js *v1.JobStatus, filter []*v1.FilterExpression) (matches boo
 		return false
 	}
 
-	idx := map[string]string{
-		"name":  js.Name,
-		"phase": strings.ToLower(strings.TrimPrefix(js.Phase.String(), "PHASE_")),
-	}
-	if js.Metadata != nil {
-		idx["owner"] = js.Metadata.Owner
-		idx["trigger"] = strings.ToLower(strings.TrimPrefix("TRIGGER_", js.Metadata.Trigger.String()))
-		if js.Metadata.Repository != nil {
-			idx["repo.owner"] = js.Metadata.Repository.Owner
-			idx["repo.repo"] = js.Metadata.Repository.Repo
-			idx["repo.host"] = js.Metadata.Repository.Host
-			idx["repo.ref"] = js.Metadata.Repository.Ref
-			idx["repo.rev"] = js.Metadata.Repository.Revision
-		}
-	}
-	for _, at := range js.Metadata.Annotations {
-		idx["annotation."+at.Key] = at.Value
-	}
+	idx := JobFields(js)
 
 	matches = true
 	for _, req := range filter {
 		var tm bool
 		for _, alt := range req.Terms {
 			val, ok := idx[alt.Field]
+			if !ok && alt.Negate && strings.HasPrefix(alt.Field, LabelFieldPrefix) {
+				// as with Kubernetes label selectors, jobs without a label match negated label terms
+				tm = true
+				break
+			}
 			if !ok {
 				continue
 			}
@@ -137,3 +169,30 @@ func MatchesFilter(js *v1.JobStatus, filter []*v1.FilterExpression) (matches boo
 	}
 	return matches
 }
+
+// JobFields returns the fields of a job filters and expressions can refer to: name, phase, owner, trigger, repo.*,
+// annotation.* and label.*
+func JobFields(js *v1.JobStatus) map[string]string {
+	idx := map[string]string{
+		"name":  js.Name,
+		"phase": strings.ToLower(strings.TrimPrefix(js.Phase.String(), "PHASE_")),
+	}
+	if js.Metadata != nil {
+		idx["owner"] = js.Metadata.Owner
+		idx["trigger"] = strings.ToLower(strings.TrimPrefix(js.Metadata.Trigger.String(), "TRIGGER_"))
+		if js.Metadata.Repository != nil {
+			idx["repo.owner"] = js.Metadata.Repository.Owner
+			idx["repo.repo"] = js.Metadata.Repository.Repo
+			idx["repo.host"] = js.Metadata.Repository.Host
+			idx["repo.ref"] = js.Metadata.Repository.Ref
+			idx["repo.rev"] = js.Metadata.Repository.Revision
+		}
+		for _, at := range js.Metadata.Annotations {
+			idx["annotation."+at.Key] = at.Value
+		}
+		for k, v := range js.Metadata.Labels {
+			idx[LabelFieldPrefix+k] = v
+		}
+	}
+	return idx
+}
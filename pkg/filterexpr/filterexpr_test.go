@@ -80,3 +80,44 @@ func TestMatchesFilter(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		Selector string
+		Labels   map[string]string
+		Matches  bool
+		Error    bool
+	}{
+		{"team=platform", map[string]string{"team": "platform"}, true, false},
+		{"team=platform", map[string]string{"team": "product"}, false, false},
+		{"team!=platform", map[string]string{"team": "product"}, true, false},
+		{"team!=platform", nil, true, false},
+		{"stage in (build, test)", map[string]string{"stage": "test"}, true, false},
+		{"stage notin (build, test)", map[string]string{"stage": "test"}, false, false},
+		{"team,stage=build", map[string]string{"team": "platform", "stage": "build"}, true, false},
+		{"team", nil, false, false},
+		{"!experimental", map[string]string{"team": "platform"}, true, false},
+		{"!experimental", map[string]string{"experimental": "true"}, false, false},
+		{"a in (", nil, false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Selector, func(t *testing.T) {
+			filter, err := filterexpr.ParseLabelSelector(test.Selector)
+			if test.Error {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			job := &v1.JobStatus{Metadata: &v1.JobMetadata{Labels: test.Labels}}
+			if act := filterexpr.MatchesFilter(job, filter); act != test.Matches {
+				t.Errorf("expected match == %v but got %v for %s", test.Matches, act, repr.String(filter))
+			}
+		})
+	}
+}
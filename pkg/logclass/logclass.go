@@ -0,0 +1,183 @@
+// Package logclass classifies log lines as errors, warnings or info using regular expressions, and summarises the
+// log of a job: how many lines of each level its slices logged and where the first error is.
+package logclass
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// Level is the severity of a log line
+type Level int
+
+const (
+	// Info is the level of all lines which are neither errors nor warnings
+	Info Level = iota
+	// Warning is the level of lines which report warnings
+	Warning
+	// Error is the level of lines which report errors
+	Error
+)
+
+var (
+	// DefaultErrors match the lines most tools report errors with
+	DefaultErrors = []string{`(?i)\b(error|fatal|panic)\b`, `(?i)\bfailed\b`, `^--- FAIL`}
+
+	// DefaultWarnings match the lines most tools report warnings with
+	DefaultWarnings = []string{`(?i)\bwarn(ing)?\b`, `(?i)\bdeprecated\b`}
+)
+
+const (
+	// excerptLines is the number of lines following the first error the excerpt includes
+	excerptLines = 4
+
+	// maxExcerptLineLength is the number of bytes of each line the excerpt keeps
+	maxExcerptLineLength = 200
+)
+
+// Config configures which log lines are errors and warnings
+type Config struct {
+	// Errors are regular expressions matching lines which report errors. Defaults to DefaultErrors.
+	Errors []string `yaml:"errors,omitempty"`
+
+	// Warnings are regular expressions matching lines which report warnings. Defaults to DefaultWarnings.
+	Warnings []string `yaml:"warnings,omitempty"`
+}
+
+// Classifier classifies log lines
+type Classifier struct {
+	errors   []*regexp.Regexp
+	warnings []*regexp.Regexp
+}
+
+// New compiles the patterns of a config into a classifier
+func New(cfg Config) (*Classifier, error) {
+	if len(cfg.Errors) == 0 {
+		cfg.Errors = DefaultErrors
+	}
+	if len(cfg.Warnings) == 0 {
+		cfg.Warnings = DefaultWarnings
+	}
+
+	var (
+		res Classifier
+		err error
+	)
+	res.errors, err = compile(cfg.Errors)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid error pattern: %w", err)
+	}
+	res.warnings, err = compile(cfg.Warnings)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid warning pattern: %w", err)
+	}
+	return &res, nil
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		r, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = r
+	}
+	return res, nil
+}
+
+// Classify returns the level of a line. Lines which match both an error and a warning pattern are errors.
+func (c *Classifier) Classify(line string) Level {
+	for _, r := range c.errors {
+		if r.MatchString(line) {
+			return Error
+		}
+	}
+	for _, r := range c.warnings {
+		if r.MatchString(line) {
+			return Warning
+		}
+	}
+	return Info
+}
+
+// NewSummary starts summarising the log of a job
+func (c *Classifier) NewSummary() *Summary {
+	return &Summary{
+		classifier: c,
+		slices:     make(map[string]*v1.LogSliceSummary),
+	}
+}
+
+// Summary counts the lines of each level per slice and keeps an excerpt of the log starting at the first error.
+// It's safe for concurrent use.
+type Summary struct {
+	classifier *Classifier
+
+	mu     sync.Mutex
+	slices map[string]*v1.LogSliceSummary
+	order  []string
+
+	excerpt      []string
+	excerptSlice string
+}
+
+// Line classifies a line a slice logged
+func (s *Summary) Line(slice, line string) {
+	line = strings.TrimRight(line, "\r\n")
+	lvl := s.classifier.Classify(line)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sl, ok := s.slices[slice]
+	if !ok {
+		sl = &v1.LogSliceSummary{Name: slice}
+		s.slices[slice] = sl
+		s.order = append(s.order, slice)
+	}
+	switch lvl {
+	case Error:
+		sl.Errors++
+	case Warning:
+		sl.Warnings++
+	default:
+		sl.Info++
+	}
+
+	if len(line) > maxExcerptLineLength {
+		line = line[:maxExcerptLineLength] + "..."
+	}
+	if s.excerpt == nil && lvl == Error {
+		s.excerpt = []string{line}
+		s.excerptSlice = slice
+	} else if s.excerpt != nil && slice == s.excerptSlice && len(s.excerpt) <= excerptLines {
+		s.excerpt = append(s.excerpt, line)
+	}
+}
+
+// Proto returns the summary of what was logged so far
+func (s *Summary) Proto() *v1.JobLogSummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &v1.JobLogSummary{
+		Slices:          make([]*v1.LogSliceSummary, len(s.order)),
+		FirstError:      strings.Join(s.excerpt, "\n"),
+		FirstErrorSlice: s.excerptSlice,
+	}
+	for i, n := range s.order {
+		sl := *s.slices[n]
+		res.Slices[i] = &sl
+	}
+	return res
+}
+
+// FirstErrorLine returns the first line of the first error in a summary, or an empty string if there is none
+func FirstErrorLine(s *v1.JobLogSummary) string {
+	return strings.SplitN(s.GetFirstError(), "\n", 2)[0]
+}
@@ -0,0 +1,82 @@
+package logclass_test
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logclass"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      logclass.Config
+		Line        string
+		Expectation logclass.Level
+	}{
+		{"info", logclass.Config{}, "building the application", logclass.Info},
+		{"error", logclass.Config{}, "Error: cannot find module", logclass.Error},
+		{"go test failure", logclass.Config{}, "--- FAIL: TestFoo (0.00s)", logclass.Error},
+		{"warning", logclass.Config{}, "npm WARN deprecated request@2.88.2", logclass.Warning},
+		{"errors beat warnings", logclass.Config{}, "warning: treating warnings as errors: error", logclass.Error},
+		{"no partial words", logclass.Config{}, "terror alert", logclass.Info},
+		{"custom error", logclass.Config{Errors: []string{`^E\d+`}}, "E0412 cannot find type", logclass.Error},
+		{"custom patterns replace defaults", logclass.Config{Errors: []string{`^E\d+`}}, "error: foo", logclass.Info},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			c, err := logclass.New(test.Config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act := c.Classify(test.Line); act != test.Expectation {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestInvalidPattern(t *testing.T) {
+	_, err := logclass.New(logclass.Config{Warnings: []string{"("}})
+	if err == nil {
+		t.Errorf("expected an error for an invalid pattern")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	c, err := logclass.New(logclass.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := c.NewSummary()
+	lines := []struct{ Slice, Line string }{
+		{"build", "compiling"},
+		{"build", "warning: unused variable"},
+		{"test", "=== RUN TestFoo"},
+		{"test", "--- FAIL: TestFoo (0.00s)"},
+		{"build", "done"},
+		{"test", "    foo_test.go:12: expected 1, got 2"},
+		{"test", "FAIL"},
+		{"test", "error: second failure"},
+	}
+	for _, l := range lines {
+		s.Line(l.Slice, l.Line)
+	}
+
+	act := s.Proto()
+	exp := &v1.JobLogSummary{
+		Slices: []*v1.LogSliceSummary{
+			{Name: "build", Warnings: 1, Info: 2},
+			{Name: "test", Errors: 2, Info: 3},
+		},
+		FirstError:      "--- FAIL: TestFoo (0.00s)\n    foo_test.go:12: expected 1, got 2\nFAIL\nerror: second failure",
+		FirstErrorSlice: "test",
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected summary: %v", act)
+	}
+	if l := logclass.FirstErrorLine(act); l != "--- FAIL: TestFoo (0.00s)" {
+		t.Errorf("unexpected first error line: %q", l)
+	}
+}
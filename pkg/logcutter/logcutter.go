@@ -2,10 +2,13 @@ package logcutter
 
 import (
 	"bufio"
+	"bytes"
 	"io"
-	"strings"
+	"regexp"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logsanitize"
+	"golang.org/x/xerrors"
 )
 
 // Cutter splits a log stream into slices for more structured display
@@ -19,6 +22,10 @@ type Cutter interface {
 const (
 	// DefaultSlice is the parent slice of all unmarked content
 	DefaultSlice = "default"
+
+	// maxLineLength is the length of the longest line we slice, longer ones are sliced in pieces. Werft splits
+	// the lines of running jobs into much shorter ones, but logs may be imported from elsewhere.
+	maxLineLength = 1024 * 1024
 )
 
 // NoCutter does not slice the content up at all
@@ -33,13 +40,16 @@ func (noCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-
 	events, errchan = evts, errc
 
 	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, maxLineLength+1)
+	scanner.Split(logsanitize.ScanLines(maxLineLength))
 	go func() {
+		var buf []byte
 		for scanner.Scan() {
-			line := scanner.Text()
+			buf = append(append(buf[:0], scanner.Bytes()...), '\n')
 			evts <- &v1.LogSliceEvent{
 				Name:    DefaultSlice,
 				Type:    v1.LogSliceType_SLICE_CONTENT,
-				Payload: line + "\n",
+				Payload: string(buf),
 			}
 		}
 		if err := scanner.Err(); err != nil {
@@ -55,43 +65,187 @@ func (noCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-
 // DefaultCutter implements the default cutting behaviour
 var DefaultCutter Cutter = defaultCutter{}
 
-type defaultCutter struct{}
+// ExpressionType determines what a line matching an expression does to the slices of a log
+type ExpressionType string
+
+const (
+	// ExpressionPhase enters a new phase, like [name|PHASE] description
+	ExpressionPhase ExpressionType = "phase"
+	// ExpressionSlice logs the line to a slice, like [name] line
+	ExpressionSlice ExpressionType = "slice"
+	// ExpressionDone marks a slice as done, like [name|DONE]
+	ExpressionDone ExpressionType = "done"
+	// ExpressionFail marks a slice as failed, like [name|FAIL] line
+	ExpressionFail ExpressionType = "fail"
+)
+
+// Expression slices the output of tools which aren't werft-aware, e.g. Gradle task boundaries or Bazel target lines.
+// Lines matching the expression are treated as if they used the default syntax.
+type Expression struct {
+	// Regexp matches the lines the expression applies to. Its "name" group, or its first group if there's no such group,
+	// names the slice. Phases take their description from the "description" group, slices their content from the
+	// "payload" group. Both default to the whole line.
+	Regexp string `yaml:"regexp"`
+
+	// Type is one of phase, slice, done or fail. Defaults to phase.
+	Type ExpressionType `yaml:"type,omitempty"`
+}
+
+type expression struct {
+	re          *regexp.Regexp
+	verb        string
+	name        int
+	description int
+}
+
+// NewExpressionCutter produces a cutter which understands the default syntax and slices lines matching one of the
+// expressions as well. Expressions are tried in order, and are tried before the default syntax.
+func NewExpressionCutter(exprs []Expression) (Cutter, error) {
+	res := defaultCutter{exprs: make([]expression, len(exprs))}
+	for i, e := range exprs {
+		re, err := regexp.Compile(e.Regexp)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid log cutter expression %q: %w", e.Regexp, err)
+		}
+		if re.NumSubexp() == 0 {
+			return nil, xerrors.Errorf("invalid log cutter expression %q: needs a group which names the slice", e.Regexp)
+		}
+
+		expr := expression{re: re, name: 1, description: -1}
+		if idx := re.SubexpIndex("name"); idx > 0 {
+			expr.name = idx
+		}
+		switch e.Type {
+		case ExpressionPhase, "":
+			expr.verb = "PHASE"
+			expr.description = re.SubexpIndex("description")
+		case ExpressionSlice:
+			expr.description = re.SubexpIndex("payload")
+		case ExpressionDone:
+			expr.verb = "DONE"
+		case ExpressionFail:
+			expr.verb = "FAIL"
+		default:
+			return nil, xerrors.Errorf("invalid log cutter expression %q: unknown type %s, must be one of phase, slice, done or fail", e.Regexp, e.Type)
+		}
+		res.exprs[i] = expr
+	}
+	return res, nil
+}
+
+// match returns the slice name, verb and payload a line stands for if it matches one of the expressions.
+// The name and payload point into line.
+func (c defaultCutter) match(line []byte) (name []byte, verb string, payload []byte, ok bool) {
+	for _, e := range c.exprs {
+		// Match does not allocate, unlike finding the submatches. Most lines match none of the expressions.
+		if !e.re.Match(line) {
+			continue
+		}
+		m := e.re.FindSubmatchIndex(line)
+		if m == nil || m[2*e.name] < 0 {
+			continue
+		}
+
+		name = line[m[2*e.name]:m[2*e.name+1]]
+		payload = bytes.TrimSpace(line)
+		if e.description > 0 && m[2*e.description] >= 0 {
+			payload = line[m[2*e.description]:m[2*e.description+1]]
+		}
+		return name, e.verb, payload, true
+	}
+	return nil, "", nil, false
+}
+
+type defaultCutter struct {
+	exprs []expression
+}
+
+// parseLine determines the slice name, verb and payload of a line using the default syntax. The name, verb and
+// payload point into line. Lines which don't name a slice have no name and belong to the current phase.
+func parseLine(line []byte) (name, verb, payload []byte) {
+	sl := bytes.TrimSpace(line)
+	if len(sl) == 0 || sl[0] != '[' {
+		return nil, nil, line
+	}
+	end := bytes.IndexByte(sl, ']')
+	if end < 0 {
+		return nil, nil, line
+	}
+
+	name = sl[1:end]
+	payload = bytes.TrimPrefix(sl[end+1:], []byte(" "))
+
+	// only names with a single pipe have a verb
+	if sep := bytes.IndexByte(name, '|'); sep >= 0 && bytes.IndexByte(name[sep+1:], '|') < 0 {
+		name, verb = name[:sep], name[sep+1:]
+	}
+	return
+}
+
+// names interns slice names, so that we don't allocate a new name for every line of a slice
+type names map[string]string
+
+func (n names) get(name []byte) string {
+	// the compiler does not allocate for string(name) when it's used as map key
+	if res, ok := n[string(name)]; ok {
+		return res
+	}
+	res := string(name)
+	n[res] = res
+	return res
+}
 
 // Slice cuts a log stream into pieces based on a configurable delimiter
-func (defaultCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-chan error) {
+func (c defaultCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errchan <-chan error) {
 	evts := make(chan *v1.LogSliceEvent)
 	errc := make(chan error)
 	events, errchan = evts, errc
 
 	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, maxLineLength+1)
+	scanner.Split(logsanitize.ScanLines(maxLineLength))
 	phase := DefaultSlice
 	go func() {
-		idx := make(map[string]struct{})
+		var (
+			idx   = make(map[string]struct{})
+			known = make(names)
+		)
 		for scanner.Scan() {
-			line := scanner.Text()
-			sl := strings.TrimSpace(line)
-
+			// line is only valid until the next call to Scan, hence everything we keep must be copied out of it
+			line := scanner.Bytes()
 			var (
-				name    string
-				verb    string
-				payload string
+				rawName, rawPayload []byte
+				verb                string
+				matched             bool
 			)
-
-			if !(strings.HasPrefix(sl, "[") && strings.Contains(sl, "]")) {
-				name = phase
-				payload = line
-			} else {
-				start := strings.IndexRune(sl, '[')
-				end := strings.IndexRune(sl, ']')
-				name = sl[start+1 : end]
-				payload = strings.TrimPrefix(sl[end+1:], " ")
-
-				if segs := strings.Split(name, "|"); len(segs) == 2 {
-					name = segs[0]
-					verb = segs[1]
+			if len(c.exprs) > 0 {
+				rawName, verb, rawPayload, matched = c.match(line)
+			}
+			if !matched {
+				var rawVerb []byte
+				rawName, rawVerb, rawPayload = parseLine(line)
+				// converting the verb does not allocate as none of the cases below needs to keep it
+				switch string(rawVerb) {
+				case "DONE":
+					verb = "DONE"
+				case "FAIL":
+					verb = "FAIL"
+				case "RESULT":
+					verb = "RESULT"
+				case "PHASE":
+					verb = "PHASE"
 				}
 			}
 
+			name := phase
+			if rawName != nil {
+				name = known.get(rawName)
+			}
+			var payload string
+			if verb != "DONE" {
+				payload = string(rawPayload)
+			}
+
 			switch verb {
 			case "DONE":
 				delete(idx, name)
@@ -136,7 +290,7 @@ func (defaultCutter) Slice(in io.Reader) (events <-chan *v1.LogSliceEvent, errch
 			evts <- &v1.LogSliceEvent{
 				Name:    name,
 				Type:    v1.LogSliceType_SLICE_CONTENT,
-				Payload: string([]byte(payload)),
+				Payload: payload,
 			}
 		}
 		if err := scanner.Err(); err != nil {
@@ -50,44 +50,179 @@ func TestDefaultCutterSlice(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			"[long] " + strings.Repeat("x", 128*1024),
+			[]v1.LogSliceEvent{
+				v1.LogSliceEvent{Name: "long", Type: v1.LogSliceType_SLICE_START},
+				v1.LogSliceEvent{Name: "long", Type: v1.LogSliceType_SLICE_CONTENT, Payload: strings.Repeat("x", 128*1024)},
+				v1.LogSliceEvent{Name: "long", Type: v1.LogSliceType_SLICE_ABANDONED},
+			},
+			nil,
+		},
 	}
 
 	for _, test := range tests {
-		content := strings.TrimSpace(test.Input)
-		evtchan, errchan := logcutter.DefaultCutter.Slice(bytes.NewReader([]byte(content)))
-
-		var (
-			events []v1.LogSliceEvent
-			err    error
-		)
-	recv:
-		for {
-			select {
-			case evt := <-evtchan:
-				if evt == nil {
-					break recv
-				}
-
-				events = append(events, *evt)
-			case err = <-errchan:
-				break recv
-			}
-		}
-
+		events, err := slice(logcutter.DefaultCutter, strings.TrimSpace(test.Input))
 		if err != test.Error {
 			t.Errorf("unexpected error: \"%s\", expected \"%s\"", err, test.Error)
 		}
 		if !reflect.DeepEqual(test.Events, events) {
-			expevt := make([]string, len(test.Events))
-			for i, evt := range test.Events {
-				expevt[i] = fmt.Sprintf("\t[%s] %s: %s", evt.Name, evt.Type.String(), evt.Payload)
+			t.Errorf("unexpected events:\n%s\nexpected:\n%s", formatEvents(events), formatEvents(test.Events))
+		}
+	}
+}
+
+func TestExpressionCutter(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Expressions []logcutter.Expression
+		Input       string
+		Events      []v1.LogSliceEvent
+		Error       bool
+	}{
+		{
+			Name:        "gradle tasks",
+			Expressions: []logcutter.Expression{{Regexp: `^> Task (?P<name>\S+)`}},
+			Input: `
+> Task :app:compileJava
+compiling
+> Task :app:test
+[publish|PHASE] not a gradle line`,
+			Events: []v1.LogSliceEvent{
+				{Name: ":app:compileJava", Type: v1.LogSliceType_SLICE_PHASE, Payload: "> Task :app:compileJava"},
+				{Name: ":app:compileJava", Type: v1.LogSliceType_SLICE_START},
+				{Name: ":app:compileJava", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "compiling"},
+				{Name: ":app:test", Type: v1.LogSliceType_SLICE_PHASE, Payload: "> Task :app:test"},
+				{Name: "publish", Type: v1.LogSliceType_SLICE_PHASE, Payload: "not a gradle line"},
+				{Name: ":app:compileJava", Type: v1.LogSliceType_SLICE_ABANDONED},
+			},
+		},
+		{
+			Name: "bazel targets",
+			Expressions: []logcutter.Expression{
+				{Regexp: `^(?:INFO|ERROR): From \S+ (?P<name>//\S+):$`, Type: logcutter.ExpressionSlice},
+				{Regexp: `^ERROR: (//\S+) failed`, Type: logcutter.ExpressionFail},
+			},
+			Input: `
+INFO: From Compiling //foo:bar:
+ERROR: //foo:bar failed to build`,
+			Events: []v1.LogSliceEvent{
+				{Name: "//foo:bar", Type: v1.LogSliceType_SLICE_START},
+				{Name: "//foo:bar", Type: v1.LogSliceType_SLICE_CONTENT, Payload: "INFO: From Compiling //foo:bar:"},
+				{Name: "//foo:bar", Type: v1.LogSliceType_SLICE_FAIL, Payload: "ERROR: //foo:bar failed to build"},
+			},
+		},
+		{
+			Name:        "no group",
+			Expressions: []logcutter.Expression{{Regexp: `^> Task`}},
+			Error:       true,
+		},
+		{
+			Name:        "unknown type",
+			Expressions: []logcutter.Expression{{Regexp: `^> Task (\S+)`, Type: "start"}},
+			Error:       true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cutter, err := logcutter.NewExpressionCutter(test.Expressions)
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			events, err := slice(cutter, strings.TrimSpace(test.Input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(test.Events, events) {
+				t.Errorf("unexpected events:\n%s\nexpected:\n%s", formatEvents(events), formatEvents(test.Events))
 			}
-			actevt := make([]string, len(events))
-			for i, evt := range events {
-				actevt[i] = fmt.Sprintf("\t[%s] %s: %s", evt.Name, evt.Type.String(), evt.Payload)
+		})
+	}
+}
+
+// slice collects the events a cutter produces for some content
+func slice(cutter logcutter.Cutter, content string) (events []v1.LogSliceEvent, err error) {
+	evtchan, errchan := cutter.Slice(bytes.NewReader([]byte(content)))
+	for {
+		select {
+		case evt := <-evtchan:
+			if evt == nil {
+				return events, nil
 			}
+			events = append(events, *evt)
+		case err = <-errchan:
+			return events, err
+		}
+	}
+}
+
+func formatEvents(events []v1.LogSliceEvent) string {
+	res := make([]string, len(events))
+	for i, evt := range events {
+		res[i] = fmt.Sprintf("\t[%s] %s: %s", evt.Name, evt.Type.String(), evt.Payload)
+	}
+	return strings.Join(res, "\n")
+}
 
-			t.Errorf("unexpected events:\n%s\nexpected:\n%s", strings.Join(actevt, "\n"), strings.Join(expevt, "\n"))
+// benchmarkLog produces a build log of roughly size bytes, which mixes phases, slices and plain output
+func benchmarkLog(size int) []byte {
+	var (
+		buf bytes.Buffer
+		i   int
+	)
+	for buf.Len() < size {
+		switch {
+		case i%200 == 0:
+			fmt.Fprintf(&buf, "[phase%d|PHASE] building component %d\n", i/200, i/200)
+		case i%50 == 0:
+			fmt.Fprintf(&buf, "[slice%d|DONE]\n", i%7)
+		case i%3 == 0:
+			fmt.Fprintf(&buf, "[slice%d] compiling pkg/component%d/file%d.go with some more compiler output\n", i%7, i%13, i)
+		default:
+			fmt.Fprintf(&buf, "plain output line %d of a build step which isn't werft-aware at all, but long-ish\n", i)
 		}
+		i++
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkCutterSlice(b *testing.B) {
+	exprCutter, err := logcutter.NewExpressionCutter([]logcutter.Expression{
+		{Regexp: `^> Task (?P<name>\S+)`},
+		{Regexp: `^INFO: From \S+ (?P<name>//\S+):$`, Type: logcutter.ExpressionSlice},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	tests := []struct {
+		Name   string
+		Cutter logcutter.Cutter
+	}{
+		{"none", logcutter.NoCutter},
+		{"default", logcutter.DefaultCutter},
+		{"expressions", exprCutter},
+	}
+	content := benchmarkLog(1024 * 1024)
+	for _, test := range tests {
+		b.Run(test.Name, func(b *testing.B) {
+			b.SetBytes(int64(len(content)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				evts, errchan := test.Cutter.Slice(bytes.NewReader(content))
+				for range evts {
+				}
+				if err := <-errchan; err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
 	}
 }
@@ -0,0 +1,83 @@
+package logforward
+
+import (
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// Entry is a single line of job log output
+type Entry struct {
+	// Job is the name of the job which produced the line
+	Job string
+	// Slice is the log slice the line belongs to
+	Slice string
+	// Line is the (already masked) log content
+	Line string
+}
+
+// Forwarder mirrors job log output to an external sink.
+// Forward must not block: werft remains the primary store of logs, hence forwarders drop entries
+// rather than slowing down jobs.
+type Forwarder interface {
+	Forward(e Entry)
+	Close() error
+}
+
+// Config configures log forwarding. Sinks which aren't configured are disabled.
+type Config struct {
+	// Loki forwards logs to a Grafana Loki instance using its push API
+	Loki *LokiConfig `yaml:"loki,omitempty"`
+
+	// Syslog forwards logs to a syslog daemon
+	Syslog *SyslogConfig `yaml:"syslog,omitempty"`
+}
+
+// New creates a forwarder for all configured sinks. HTTP based sinks send their requests using transport, or
+// http.DefaultTransport if it's nil. If no sink is configured, New returns nil.
+func New(cfg Config, transport http.RoundTripper) (Forwarder, error) {
+	var fws multiForwarder
+	if cfg.Loki != nil {
+		fw, err := NewLoki(*cfg.Loki, transport)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot create Loki forwarder: %w", err)
+		}
+		fws = append(fws, fw)
+	}
+	if cfg.Syslog != nil {
+		fw, err := NewSyslog(*cfg.Syslog)
+		if err != nil {
+			fws.Close()
+			return nil, xerrors.Errorf("cannot create syslog forwarder: %w", err)
+		}
+		fws = append(fws, fw)
+	}
+
+	switch len(fws) {
+	case 0:
+		return nil, nil
+	case 1:
+		return fws[0], nil
+	default:
+		return fws, nil
+	}
+}
+
+// multiForwarder forwards to several sinks
+type multiForwarder []Forwarder
+
+func (m multiForwarder) Forward(e Entry) {
+	for _, fw := range m {
+		fw.Forward(e)
+	}
+}
+
+func (m multiForwarder) Close() error {
+	var err error
+	for _, fw := range m {
+		if cerr := fw.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package logforward
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	defaultLokiBatchSize = 500
+	lokiBatchWait        = 1 * time.Second
+	lokiBufferSize       = 10000
+)
+
+// LokiConfig configures log forwarding to Loki
+type LokiConfig struct {
+	// URL is the base URL of Loki, e.g. http://loki:3100
+	URL string `yaml:"url"`
+
+	// Labels are added to all log streams we push, in addition to the job and slice labels
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// Headers are sent with every push request, e.g. for authentication or the X-Scope-OrgID tenant
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// BatchSize is the maximum number of lines sent in a single push request. Defaults to 500.
+	BatchSize int `yaml:"batchSize,omitempty"`
+}
+
+// NewLoki creates a forwarder which pushes logs to Loki using transport, or http.DefaultTransport if it's nil. Lines
+// are sent in batches at least once a second.
+func NewLoki(cfg LokiConfig, transport http.RoundTripper) (*LokiForwarder, error) {
+	if cfg.URL == "" {
+		return nil, xerrors.Errorf("url is required")
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultLokiBatchSize
+	}
+
+	fw := &LokiForwarder{
+		Config: cfg,
+		Client: &http.Client{Transport: transport, Timeout: 10 * time.Second},
+
+		entries: make(chan lokiEntry, lokiBufferSize),
+		done:    make(chan struct{}),
+	}
+	fw.wg.Add(1)
+	go fw.run()
+	return fw, nil
+}
+
+// LokiForwarder pushes job logs to Loki
+type LokiForwarder struct {
+	Config LokiConfig
+	Client *http.Client
+
+	entries chan lokiEntry
+	done    chan struct{}
+	dropped int64
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+type lokiEntry struct {
+	Entry
+	Time time.Time
+}
+
+// Forward queues a line for pushing to Loki. If the queue is full the line is dropped.
+func (fw *LokiForwarder) Forward(e Entry) {
+	select {
+	case fw.entries <- lokiEntry{Entry: e, Time: time.Now()}:
+	default:
+		atomic.AddInt64(&fw.dropped, 1)
+	}
+}
+
+// Close pushes all queued lines and stops the forwarder
+func (fw *LokiForwarder) Close() error {
+	fw.once.Do(func() { close(fw.done) })
+	fw.wg.Wait()
+	return nil
+}
+
+func (fw *LokiForwarder) run() {
+	defer fw.wg.Done()
+
+	ticker := time.NewTicker(lokiBatchWait)
+	defer ticker.Stop()
+
+	var batch []lokiEntry
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		err := fw.push(batch)
+		if err != nil {
+			log.WithError(err).WithField("lines", len(batch)).Warn("cannot forward logs to Loki")
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case e := <-fw.entries:
+			batch = append(batch, e)
+			if len(batch) >= fw.Config.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+			if dropped := atomic.SwapInt64(&fw.dropped, 0); dropped > 0 {
+				log.WithField("lines", dropped).Warn("dropped log lines because Loki could not keep up")
+			}
+		case <-fw.done:
+			for {
+				select {
+				case e := <-fw.entries:
+					batch = append(batch, e)
+					if len(batch) >= fw.Config.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// push sends a batch of lines to Loki, grouping them into one stream per job and slice
+func (fw *LokiForwarder) push(batch []lokiEntry) error {
+	var (
+		req lokiPushRequest
+		idx = make(map[string]int)
+	)
+	for _, e := range batch {
+		key := e.Job + "/" + e.Slice
+		i, ok := idx[key]
+		if !ok {
+			labels := make(map[string]string, len(fw.Config.Labels)+2)
+			for k, v := range fw.Config.Labels {
+				labels[k] = v
+			}
+			labels["werft_job"] = e.Job
+			labels["slice"] = e.Slice
+
+			i = len(req.Streams)
+			idx[key] = i
+			req.Streams = append(req.Streams, lokiStream{Stream: labels})
+		}
+		req.Streams[i].Values = append(req.Streams[i].Values, [2]string{fmt.Sprintf("%d", e.Time.UnixNano()), e.Line})
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	hreq, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(fw.Config.URL, "/")+"/loki/api/v1/push", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	for k, v := range fw.Config.Headers {
+		hreq.Header.Set(k, v)
+	}
+
+	resp, err := fw.Client.Do(hreq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return xerrors.Errorf("Loki responded with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package logforward_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/logforward"
+)
+
+func TestLokiForwarder(t *testing.T) {
+	type pushRequest struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+
+	var (
+		mu       sync.Mutex
+		requests []pushRequest
+		tenants  []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/push" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		var req pushRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			t.Errorf("cannot decode push request: %v", err)
+		}
+
+		mu.Lock()
+		requests = append(requests, req)
+		tenants = append(tenants, r.Header.Get("X-Scope-OrgID"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	fw, err := logforward.NewLoki(logforward.LokiConfig{
+		URL:     srv.URL + "/",
+		Labels:  map[string]string{"app": "werft"},
+		Headers: map[string]string{"X-Scope-OrgID": "ci"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("cannot create forwarder: %v", err)
+	}
+	fw.Forward(logforward.Entry{Job: "foo.1", Slice: "build", Line: "hello"})
+	fw.Forward(logforward.Entry{Job: "foo.1", Slice: "build", Line: "world"})
+	fw.Forward(logforward.Entry{Job: "foo.1", Slice: "test", Line: "ok"})
+	err = fw.Close()
+	if err != nil {
+		t.Fatalf("cannot close forwarder: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requests) != 1 {
+		t.Fatalf("expected a single push request, got %d", len(requests))
+	}
+	if tenants[0] != "ci" {
+		t.Errorf("expected configured headers to be sent, got tenant %q", tenants[0])
+	}
+
+	lines := make(map[string][]string)
+	for _, s := range requests[0].Streams {
+		if s.Stream["app"] != "werft" || s.Stream["werft_job"] != "foo.1" {
+			t.Errorf("unexpected stream labels: %v", s.Stream)
+		}
+		for _, v := range s.Values {
+			lines[s.Stream["slice"]] = append(lines[s.Stream["slice"]], v[1])
+		}
+	}
+	if len(lines["build"]) != 2 || lines["build"][0] != "hello" || lines["build"][1] != "world" {
+		t.Errorf("unexpected build lines: %v", lines["build"])
+	}
+	if len(lines["test"]) != 1 || lines["test"][0] != "ok" {
+		t.Errorf("unexpected test lines: %v", lines["test"])
+	}
+}
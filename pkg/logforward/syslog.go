@@ -0,0 +1,94 @@
+package logforward
+
+import (
+	"fmt"
+	"log/syslog"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const syslogBufferSize = 10000
+
+// SyslogConfig configures log forwarding to syslog
+type SyslogConfig struct {
+	// Network is the network used to reach the syslog daemon, e.g. udp or tcp. If empty, we use the local syslog daemon.
+	Network string `yaml:"network,omitempty"`
+
+	// Address is the host:port of the syslog daemon
+	Address string `yaml:"address,omitempty"`
+
+	// Tag is the syslog tag of all messages. Defaults to werft.
+	Tag string `yaml:"tag,omitempty"`
+}
+
+// NewSyslog creates a forwarder which writes logs to syslog
+func NewSyslog(cfg SyslogConfig) (*SyslogForwarder, error) {
+	if cfg.Tag == "" {
+		cfg.Tag = "werft"
+	}
+	w, err := syslog.Dial(cfg.Network, cfg.Address, syslog.LOG_INFO|syslog.LOG_USER, cfg.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	fw := &SyslogForwarder{
+		writer:  w,
+		entries: make(chan Entry, syslogBufferSize),
+		done:    make(chan struct{}),
+	}
+	fw.wg.Add(1)
+	go fw.run()
+	return fw, nil
+}
+
+// SyslogForwarder writes job logs to syslog
+type SyslogForwarder struct {
+	writer  *syslog.Writer
+	entries chan Entry
+	done    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// Forward queues a line for writing to syslog. If the queue is full the line is dropped.
+func (fw *SyslogForwarder) Forward(e Entry) {
+	select {
+	case fw.entries <- e:
+	default:
+	}
+}
+
+// Close writes all queued lines and stops the forwarder
+func (fw *SyslogForwarder) Close() error {
+	fw.once.Do(func() { close(fw.done) })
+	fw.wg.Wait()
+	return fw.writer.Close()
+}
+
+func (fw *SyslogForwarder) run() {
+	defer fw.wg.Done()
+
+	for {
+		select {
+		case e := <-fw.entries:
+			fw.write(e)
+		case <-fw.done:
+			for {
+				select {
+				case e := <-fw.entries:
+					fw.write(e)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (fw *SyslogForwarder) write(e Entry) {
+	err := fw.writer.Info(fmt.Sprintf("%s [%s] %s", e.Job, e.Slice, e.Line))
+	if err != nil {
+		log.WithError(err).Debug("cannot forward log line to syslog")
+	}
+}
@@ -0,0 +1,116 @@
+package logparser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+const (
+	// ResultTestFailure is the type of results which name a failed test
+	ResultTestFailure = "test-failure"
+	// ResultBenchmark is the type of results which report the numbers of a benchmark
+	ResultBenchmark = "benchmark"
+	// ResultLint is the type of results which report a linter warning or error
+	ResultLint = "lint"
+)
+
+var (
+	goTestFailure = regexp.MustCompile(`^\s*--- FAIL: (\S+) \(([\d.]+s)\)`)
+	goBenchmark   = regexp.MustCompile(`^(Benchmark\S+)\s+(\d+)\s+(\d.* ns/op.*)$`)
+	goBenchMetric = regexp.MustCompile(`([\d.]+)\s+(\S+)`)
+)
+
+// GoTest reports the tests which failed in the output of go test, including subtests
+var GoTest Parser = LineParser(func(slice, line string) []*v1.JobResult {
+	m := goTestFailure.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	return []*v1.JobResult{{
+		Type:        ResultTestFailure,
+		Payload:     m[1],
+		Description: fmt.Sprintf("failed after %s", m[2]),
+	}}
+})
+
+// GoBench reports the results of go test -bench, e.g. "1234 ns/op, 64 B/op" for BenchmarkFoo-8
+var GoBench Parser = LineParser(func(slice, line string) []*v1.JobResult {
+	m := goBenchmark.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return nil
+	}
+	var metrics []string
+	for _, mm := range goBenchMetric.FindAllStringSubmatch(m[3], -1) {
+		metrics = append(metrics, mm[1]+" "+mm[2])
+	}
+	return []*v1.JobResult{{
+		Type:        ResultBenchmark,
+		Payload:     strings.Join(metrics, ", "),
+		Description: m[1],
+	}}
+})
+
+var (
+	eslintFile    = regexp.MustCompile(`^(/|[A-Za-z]:\\)\S.*\.\w+$`)
+	eslintProblem = regexp.MustCompile(`^\s+(\d+):(\d+)\s+(error|warning)\s+(.+?)(?:\s{2,}(\S+))?$`)
+)
+
+// ESLint reports the problems found by eslint using its default (stylish) output format,
+// which lists the problems of each file below the file's path.
+var ESLint Parser = eslintParser{}
+
+type eslintParser struct{}
+
+func (eslintParser) Parse(ctx context.Context, job string, emit func(*v1.JobResult)) (Session, error) {
+	return &eslintSession{Emit: emit, files: make(map[string]string)}, nil
+}
+
+type eslintSession struct {
+	Emit func(*v1.JobResult)
+
+	mu    sync.Mutex
+	files map[string]string
+}
+
+func (s *eslintSession) Line(slice, line string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line = strings.TrimRight(line, "\r\n")
+	if eslintFile.MatchString(line) {
+		s.files[slice] = line
+		return nil
+	}
+	if strings.TrimSpace(line) == "" {
+		delete(s.files, slice)
+		return nil
+	}
+
+	fn, ok := s.files[slice]
+	if !ok {
+		return nil
+	}
+	m := eslintProblem.FindStringSubmatch(line)
+	if m == nil {
+		return nil
+	}
+	desc := fmt.Sprintf("%s: %s", m[3], m[4])
+	if m[5] != "" {
+		desc += fmt.Sprintf(" (%s)", m[5])
+	}
+	s.Emit(&v1.JobResult{
+		Type:        ResultLint,
+		Payload:     fmt.Sprintf("%s:%s:%s", fn, m[1], m[2]),
+		Description: desc,
+	})
+	return nil
+}
+
+func (s *eslintSession) Close() error {
+	return nil
+}
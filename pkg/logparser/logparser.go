@@ -0,0 +1,136 @@
+package logparser
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+)
+
+// Parser extracts structured results, e.g. test failures or benchmark numbers, from the logs of jobs
+type Parser interface {
+	// Parse starts parsing the log of a job. Results found in the log are passed to emit, which is safe for concurrent use.
+	Parse(ctx context.Context, job string, emit func(*v1.JobResult)) (Session, error)
+}
+
+// Session parses the log of a single job
+type Session interface {
+	// Line parses a line of content of a log slice. Line must not block the log pipeline, e.g. on slow plugins.
+	Line(slice, line string) error
+
+	// Close is called once the log is complete. Parsers may emit results until Close returns.
+	Close() error
+}
+
+// Builtins are the parsers compiled into werft, by name
+var Builtins = map[string]Parser{
+	"gotest":  GoTest,
+	"gobench": GoBench,
+	"eslint":  ESLint,
+}
+
+// BuiltinNames returns the names of all compiled-in parsers
+func BuiltinNames() []string {
+	res := make([]string, 0, len(Builtins))
+	for n := range Builtins {
+		res = append(res, n)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// LineParser is a parser which looks at each line of a log on its own
+type LineParser func(slice, line string) []*v1.JobResult
+
+// Parse starts parsing the log of a job
+func (p LineParser) Parse(ctx context.Context, job string, emit func(*v1.JobResult)) (Session, error) {
+	return &lineSession{P: p, Emit: emit}, nil
+}
+
+type lineSession struct {
+	P    LineParser
+	Emit func(*v1.JobResult)
+}
+
+func (s *lineSession) Line(slice, line string) error {
+	for _, res := range s.P(slice, line) {
+		s.Emit(res)
+	}
+	return nil
+}
+
+func (s *lineSession) Close() error {
+	return nil
+}
+
+// MaxResults is the number of results parsers may emit per job. Results are stored alongside the job,
+// hence a lint run which finds thousands of problems must not produce thousands of results.
+const MaxResults = 100
+
+// Start starts parsing the log of a job with all parsers. Parsers which fail are logged and skipped,
+// so that one broken parser doesn't keep the others from working.
+func Start(ctx context.Context, job string, parsers []Parser, emit func(*v1.JobResult)) Session {
+	var (
+		mu    sync.Mutex
+		count int
+	)
+	limitedEmit := func(r *v1.JobResult) {
+		mu.Lock()
+		count++
+		n := count
+		mu.Unlock()
+
+		if n == MaxResults+1 {
+			log.WithField("name", job).WithField("max", MaxResults).Warn("log parsers found too many results - dropping the rest")
+		}
+		if n > MaxResults {
+			return
+		}
+		emit(r)
+	}
+
+	var res multiSession
+	for _, p := range parsers {
+		sess, err := p.Parse(ctx, job, limitedEmit)
+		if err != nil {
+			log.WithError(err).WithField("name", job).Warn("cannot start log parser")
+			continue
+		}
+		res.Sessions = append(res.Sessions, sess)
+	}
+	res.Job = job
+	return &res
+}
+
+type multiSession struct {
+	Job      string
+	Sessions []Session
+}
+
+func (m *multiSession) Line(slice, line string) error {
+	var active []Session
+	for _, s := range m.Sessions {
+		err := s.Line(slice, line)
+		if err != nil {
+			log.WithError(err).WithField("name", m.Job).Warn("log parser failed - no longer parsing this job's log")
+			s.Close()
+			continue
+		}
+		active = append(active, s)
+	}
+	m.Sessions = active
+	return nil
+}
+
+func (m *multiSession) Close() error {
+	for _, s := range m.Sessions {
+		err := s.Close()
+		if err != nil {
+			log.WithError(err).WithField("name", m.Job).Warn("log parser failed")
+		}
+	}
+	m.Sessions = nil
+	return nil
+}
@@ -0,0 +1,82 @@
+package logparser_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logparser"
+)
+
+func TestBuiltins(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Parser      logparser.Parser
+		Log         string
+		Expectation []v1.JobResult
+	}{
+		{"go test", logparser.GoTest, `=== RUN   TestFoo
+=== RUN   TestFoo/bar
+--- FAIL: TestFoo (0.01s)
+    --- FAIL: TestFoo/bar (0.00s)
+        foo_test.go:12: expected 1, got 2
+--- PASS: TestBar (0.00s)
+FAIL`, []v1.JobResult{
+			{Type: "test-failure", Payload: "TestFoo", Description: "failed after 0.01s"},
+			{Type: "test-failure", Payload: "TestFoo/bar", Description: "failed after 0.00s"},
+		}},
+		{"go bench", logparser.GoBench, `goos: linux
+BenchmarkFoo-8   	 1000000	      1234 ns/op	      64 B/op	       2 allocs/op
+BenchmarkBar-8   	   50000	     31.5 ns/op
+PASS`, []v1.JobResult{
+			{Type: "benchmark", Payload: "1234 ns/op, 64 B/op, 2 allocs/op", Description: "BenchmarkFoo-8"},
+			{Type: "benchmark", Payload: "31.5 ns/op", Description: "BenchmarkBar-8"},
+		}},
+		{"eslint", logparser.ESLint, `
+/workspace/src/index.js
+  1:10  error    'foo' is defined but never used  no-unused-vars
+  2:1   warning  Unexpected console statement     no-console
+
+   3:5  error  not a problem of any file
+
+✖ 2 problems (1 error, 1 warning)`, []v1.JobResult{
+			{Type: "lint", Payload: "/workspace/src/index.js:1:10", Description: "error: 'foo' is defined but never used (no-unused-vars)"},
+			{Type: "lint", Payload: "/workspace/src/index.js:2:1", Description: "warning: Unexpected console statement (no-console)"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			var act []v1.JobResult
+			sess := logparser.Start(context.Background(), "job", []logparser.Parser{test.Parser}, func(r *v1.JobResult) {
+				act = append(act, *r)
+			})
+			for _, l := range strings.Split(test.Log, "\n") {
+				sess.Line("build", l)
+			}
+			sess.Close()
+
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, actual %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestMaxResults(t *testing.T) {
+	var count int
+	sess := logparser.Start(context.Background(), "job", []logparser.Parser{logparser.GoTest}, func(r *v1.JobResult) {
+		count++
+	})
+	for i := 0; i < 2*logparser.MaxResults; i++ {
+		sess.Line("build", fmt.Sprintf("--- FAIL: Test%d (0.00s)", i))
+	}
+	sess.Close()
+
+	if count != logparser.MaxResults {
+		t.Errorf("expected %d results, actual %d", logparser.MaxResults, count)
+	}
+}
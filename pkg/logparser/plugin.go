@@ -0,0 +1,138 @@
+package logparser
+
+import (
+	"context"
+	"io"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+)
+
+const (
+	// pluginLineBuffer is the number of lines we buffer for a plugin before we start dropping them
+	pluginLineBuffer = 1000
+
+	// pluginCloseTimeout is the time plugins get to finish parsing once a log is complete
+	pluginCloseTimeout = 30 * time.Second
+)
+
+// NewPluginParser returns a parser which forwards logs to a log parser plugin
+func NewPluginParser(name string, client v1.LogParserPluginClient) Parser {
+	return &pluginParser{Name: name, Client: client}
+}
+
+type pluginParser struct {
+	Name   string
+	Client v1.LogParserPluginClient
+}
+
+// Parse starts parsing the log of a job. Connecting to the plugin happens in the background so that
+// a plugin which is (re-)starting does not hold up the log pipeline.
+func (p *pluginParser) Parse(ctx context.Context, job string, emit func(*v1.JobResult)) (Session, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	sess := &pluginSession{
+		Name:   p.Name,
+		Job:    job,
+		lines:  make(chan *v1.ParseLogRequest, pluginLineBuffer),
+		done:   make(chan error, 1),
+		cancel: cancel,
+	}
+	go func() {
+		sess.done <- sess.run(ctx, p.Client, emit)
+	}()
+	return sess, nil
+}
+
+type pluginSession struct {
+	Name string
+	Job  string
+
+	lines    chan *v1.ParseLogRequest
+	done     chan error
+	cancel   context.CancelFunc
+	dropped  int
+	finished bool
+	err      error
+}
+
+func (s *pluginSession) run(ctx context.Context, client v1.LogParserPluginClient, emit func(*v1.JobResult)) error {
+	stream, err := client.Parse(ctx, grpc.WaitForReady(true))
+	if err != nil {
+		return xerrors.Errorf("cannot connect to log parser plugin %s: %w", s.Name, err)
+	}
+
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				recvErr <- nil
+				return
+			}
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			if resp.Result != nil {
+				emit(resp.Result)
+			}
+		}
+	}()
+
+	for l := range s.lines {
+		err = stream.Send(l)
+		if err == io.EOF {
+			// the plugin ended the stream - Recv tells us why
+			break
+		}
+		if err != nil {
+			return xerrors.Errorf("cannot send log to log parser plugin %s: %w", s.Name, err)
+		}
+	}
+	err = stream.CloseSend()
+	if err != nil {
+		return xerrors.Errorf("cannot send log to log parser plugin %s: %w", s.Name, err)
+	}
+	return <-recvErr
+}
+
+func (s *pluginSession) Line(slice, line string) error {
+	if s.finished {
+		return s.err
+	}
+	select {
+	case s.err = <-s.done:
+		// the plugin stopped parsing before the log is complete
+		s.finished = true
+		return s.err
+	default:
+	}
+
+	select {
+	case s.lines <- &v1.ParseLogRequest{Job: s.Job, Slice: slice, Line: line}:
+	default:
+		if s.dropped == 0 {
+			log.WithField("name", s.Job).WithField("plugin", s.Name).Warn("log parser plugin is too slow - dropping log lines")
+		}
+		s.dropped++
+	}
+	return nil
+}
+
+func (s *pluginSession) Close() error {
+	defer s.cancel()
+
+	close(s.lines)
+	if s.finished {
+		return s.err
+	}
+	select {
+	case err := <-s.done:
+		return err
+	case <-time.After(pluginCloseTimeout):
+		return xerrors.Errorf("log parser plugin %s did not finish within %s", s.Name, pluginCloseTimeout)
+	}
+}
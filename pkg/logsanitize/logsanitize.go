@@ -0,0 +1,92 @@
+// Package logsanitize makes log lines safe to store, stream using gRPC and render in the UI: lines end with LF
+// instead of CRLF, contain valid UTF-8 only, and long lines are split into pieces which are marked as continuation.
+package logsanitize
+
+import (
+	"bufio"
+	"bytes"
+	"unicode/utf8"
+)
+
+// ContinuationMarker starts the pieces of a split line after the first one
+const ContinuationMarker = "↪ "
+
+// Line removes a trailing CR, i.e. the rest of a CRLF line ending, and invalid UTF-8 sequences from a line.
+// If the line needs no change it's returned as is, otherwise a copy is returned.
+func Line(line []byte) []byte {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if utf8.Valid(line) {
+		return line
+	}
+
+	res := make([]byte, 0, len(line))
+	for len(line) > 0 {
+		r, sz := utf8.DecodeRune(line)
+		if r != utf8.RuneError || sz > 1 {
+			res = append(res, line[:sz]...)
+		}
+		line = line[sz:]
+	}
+	return res
+}
+
+// CutIncomplete splits a piece of a line which is to be continued such that it does not end within a UTF-8 character.
+// The rest is the beginning of that character, which belongs to the next piece.
+func CutIncomplete(piece []byte) (complete, rest []byte) {
+	// UTF-8 characters are four bytes long at most, hence we need to look at the last three bytes only
+	for i := 1; i <= 3 && i <= len(piece); i++ {
+		b := piece[len(piece)-i]
+		if b < utf8.RuneSelf {
+			// ASCII never is part of a multi-byte character
+			break
+		}
+		if !utf8.RuneStart(b) {
+			continue
+		}
+		if !utf8.FullRune(piece[len(piece)-i:]) {
+			return piece[:len(piece)-i], piece[len(piece)-i:]
+		}
+		break
+	}
+	return piece, nil
+}
+
+// ContinuationPrefix returns what the pieces of a split line start with, given its first piece: lines which log to a
+// slice, like [name] content, continue in that slice. The prefix ends with the continuation marker.
+func ContinuationPrefix(first []byte) []byte {
+	sl := bytes.TrimLeft(first, " \t")
+	if len(sl) == 0 || sl[0] != '[' {
+		return []byte(ContinuationMarker)
+	}
+	end := bytes.IndexByte(sl, ']')
+	if end < 0 || bytes.IndexByte(sl[:end], '|') >= 0 {
+		// pieces of phases, results and the like are just content
+		return []byte(ContinuationMarker)
+	}
+
+	res := make([]byte, 0, end+2+len(ContinuationMarker))
+	res = append(res, sl[:end+1]...)
+	res = append(res, ' ')
+	return append(res, ContinuationMarker...)
+}
+
+// ScanLines is a split function for a bufio.Scanner which works like bufio.ScanLines, except that lines longer than
+// max bytes are split into several lines instead of failing the scan. The scanner's buffer must be larger than max.
+func ScanLines(max int) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (advance int, token []byte, err error) {
+		if i := bytes.IndexByte(data, '\n'); i >= 0 && i < max {
+			return i + 1, Line(data[:i]), nil
+		}
+		if len(data) >= max {
+			piece, _ := CutIncomplete(data[:max])
+			if len(piece) == 0 {
+				piece = data[:max]
+			}
+			return len(piece), Line(piece), nil
+		}
+		if atEOF && len(data) > 0 {
+			return len(data), Line(data), nil
+		}
+		return 0, nil, nil
+	}
+}
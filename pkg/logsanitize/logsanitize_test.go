@@ -0,0 +1,91 @@
+package logsanitize_test
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/logsanitize"
+)
+
+func TestLine(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Input       string
+		Expectation string
+	}{
+		{"plain", "hello world", "hello world"},
+		{"CRLF", "hello world\r", "hello world"},
+		{"carriage return within line", "50%\r100%", "50%\r100%"},
+		{"valid UTF-8", "grüße ✓", "grüße ✓"},
+		{"invalid UTF-8", "gr\xfc\xdfe \xe2\x9c", "gre "},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := string(logsanitize.Line([]byte(test.Input)))
+			if act != test.Expectation {
+				t.Errorf("expected %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestCutIncomplete(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    string
+		Complete string
+		Rest     string
+	}{
+		{"ASCII", "abc", "abc", ""},
+		{"complete character", "ab✓", "ab✓", ""},
+		{"incomplete character", "ab\xe2\x9c", "ab", "\xe2\x9c"},
+		{"start of character", "ab\xf0", "ab", "\xf0"},
+		{"empty", "", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			complete, rest := logsanitize.CutIncomplete([]byte(test.Input))
+			if string(complete) != test.Complete || string(rest) != test.Rest {
+				t.Errorf("expected %q/%q, got %q/%q", test.Complete, test.Rest, complete, rest)
+			}
+		})
+	}
+}
+
+func TestContinuationPrefix(t *testing.T) {
+	tests := []struct {
+		Input       string
+		Expectation string
+	}{
+		{"plain output", logsanitize.ContinuationMarker},
+		{"[build] compiling", "[build] " + logsanitize.ContinuationMarker},
+		{"  [sidecar:test] ok", "[sidecar:test] " + logsanitize.ContinuationMarker},
+		{"[build|PHASE] compiling", logsanitize.ContinuationMarker},
+	}
+	for _, test := range tests {
+		act := string(logsanitize.ContinuationPrefix([]byte(test.Input)))
+		if act != test.Expectation {
+			t.Errorf("%q: expected %q, got %q", test.Input, test.Expectation, act)
+		}
+	}
+}
+
+func TestScanLines(t *testing.T) {
+	input := "short\r\n" + strings.Repeat("x", 9) + "✓\nlast"
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	scanner.Buffer(nil, 11)
+	scanner.Split(logsanitize.ScanLines(10))
+
+	var act []string
+	for scanner.Scan() {
+		act = append(act, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	exp := []string{"short", strings.Repeat("x", 9), "✓", "last"}
+	if strings.Join(act, "|") != strings.Join(exp, "|") {
+		t.Errorf("expected %q, got %q", exp, act)
+	}
+}
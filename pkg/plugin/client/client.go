@@ -2,19 +2,24 @@ package client
 
 import (
 	"context"
+	"io"
 	"io/ioutil"
 	"net"
 	"os"
 	"os/signal"
 	"reflect"
+	"sync"
 	"syscall"
 	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logparser"
 	"github.com/32leaves/werft/pkg/plugin/common"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v3"
 )
 
@@ -48,6 +53,246 @@ func WithIntegrationPlugin(p IntegrationPlugin) ServeOpt {
 	}
 }
 
+// LogParserPlugin extracts structured results, e.g. test failures, from job logs
+type LogParserPlugin interface {
+	// Parser returns the parser for the logs of all jobs. It's called once when the plugin starts.
+	Parser(config interface{}) (logparser.Parser, error)
+}
+
+// WithLogParserPlugin registers log parser plugin capabilities
+func WithLogParserPlugin(p LogParserPlugin) ServeOpt {
+	return ServeOpt{
+		Type: common.TypeLogParser,
+		Run: func(ctx context.Context, config interface{}, socket string) error {
+			parser, err := p.Parser(config)
+			if err != nil {
+				return xerrors.Errorf("cannot create log parser: %w", err)
+			}
+
+			lis, err := net.Listen("unix", socket)
+			if err != nil {
+				return xerrors.Errorf("cannot listen on %s: %w", socket, err)
+			}
+			s := grpc.NewServer()
+			v1.RegisterLogParserPluginServer(s, &logParserServer{Parser: parser})
+			go func() {
+				<-ctx.Done()
+				s.GracefulStop()
+			}()
+
+			return s.Serve(lis)
+		},
+	}
+}
+
+type logParserServer struct {
+	Parser logparser.Parser
+}
+
+// Parse parses the log of a single job
+func (srv *logParserServer) Parse(s v1.LogParserPlugin_ParseServer) error {
+	var (
+		sess   logparser.Session
+		sendMu sync.Mutex
+	)
+	emit := func(res *v1.JobResult) {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+
+		err := s.Send(&v1.ParseLogResponse{Result: res})
+		if err != nil {
+			log.WithError(err).Warn("cannot send log parser result")
+		}
+	}
+
+	for {
+		req, err := s.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if sess != nil {
+				sess.Close()
+			}
+			return err
+		}
+
+		if sess == nil {
+			sess, err = srv.Parser.Parse(s.Context(), req.Job, emit)
+			if err != nil {
+				return err
+			}
+		}
+		err = sess.Line(req.Slice, req.Line)
+		if err != nil {
+			sess.Close()
+			return err
+		}
+	}
+
+	if sess == nil {
+		return nil
+	}
+	return sess.Close()
+}
+
+// HookPlugin takes part in handling webhooks and running jobs. Implementations also implement at least one of
+// WebhookFilter, JobMutator, JobDoneHook and the pod hooks - werft only calls the hooks a plugin implements.
+type HookPlugin interface {
+	// Init is called with the plugin config once the plugin starts, before any hook is called
+	Init(config interface{}) error
+}
+
+// WebhookFilter decides if werft handles webhook events, e.g. to ignore pushes by bots
+type WebhookFilter interface {
+	FilterWebhook(ctx context.Context, req *v1.FilterWebhookRequest) (*v1.FilterWebhookResponse, error)
+}
+
+// JobMutator can change jobs before they start, or reject them
+type JobMutator interface {
+	MutateJob(ctx context.Context, req *v1.MutateJobRequest) (*v1.MutateJobResponse, error)
+}
+
+// JobDoneHook is called once a job is done
+type JobDoneHook interface {
+	JobDone(ctx context.Context, req *v1.JobDoneRequest) error
+}
+
+// PodPreCreateHook can change the pods of jobs right before they're created, or reject them
+type PodPreCreateHook interface {
+	PreCreatePod(ctx context.Context, req *v1.PodHookRequest) (*v1.PreCreatePodResponse, error)
+}
+
+// PodPostCreateHook is called once the pod of a job was created
+type PodPostCreateHook interface {
+	PostCreatePod(ctx context.Context, req *v1.PodHookRequest) error
+}
+
+// PodPreDeleteHook is called before the pod of a job is deleted
+type PodPreDeleteHook interface {
+	PreDeletePod(ctx context.Context, req *v1.PodHookRequest) error
+}
+
+// WithHookPlugin registers hook plugin capabilities
+func WithHookPlugin(p HookPlugin) ServeOpt {
+	return ServeOpt{
+		Type: common.TypeHook,
+		Run: func(ctx context.Context, config interface{}, socket string) error {
+			err := p.Init(config)
+			if err != nil {
+				return xerrors.Errorf("cannot initialize hook plugin: %w", err)
+			}
+
+			lis, err := net.Listen("unix", socket)
+			if err != nil {
+				return xerrors.Errorf("cannot listen on %s: %w", socket, err)
+			}
+			s := grpc.NewServer()
+			v1.RegisterHookPluginServer(s, &hookServer{Plugin: p})
+			go func() {
+				<-ctx.Done()
+				s.GracefulStop()
+			}()
+
+			return s.Serve(lis)
+		},
+	}
+}
+
+type hookServer struct {
+	Plugin HookPlugin
+}
+
+// Info returns the version of the hook API we speak and the hooks the plugin implements
+func (srv *hookServer) Info(ctx context.Context, req *v1.HookInfoRequest) (*v1.HookInfoResponse, error) {
+	var hooks []v1.Hook
+	if _, ok := srv.Plugin.(WebhookFilter); ok {
+		hooks = append(hooks, v1.Hook_HOOK_FILTER_WEBHOOK)
+	}
+	if _, ok := srv.Plugin.(JobMutator); ok {
+		hooks = append(hooks, v1.Hook_HOOK_MUTATE_JOB)
+	}
+	if _, ok := srv.Plugin.(JobDoneHook); ok {
+		hooks = append(hooks, v1.Hook_HOOK_JOB_DONE)
+	}
+	if _, ok := srv.Plugin.(PodPreCreateHook); ok {
+		hooks = append(hooks, v1.Hook_HOOK_PRE_CREATE_POD)
+	}
+	if _, ok := srv.Plugin.(PodPostCreateHook); ok {
+		hooks = append(hooks, v1.Hook_HOOK_POST_CREATE_POD)
+	}
+	if _, ok := srv.Plugin.(PodPreDeleteHook); ok {
+		hooks = append(hooks, v1.Hook_HOOK_PRE_DELETE_POD)
+	}
+	return &v1.HookInfoResponse{ApiVersion: common.HookAPIVersion, Hooks: hooks}, nil
+}
+
+// FilterWebhook decides if werft handles a webhook event
+func (srv *hookServer) FilterWebhook(ctx context.Context, req *v1.FilterWebhookRequest) (*v1.FilterWebhookResponse, error) {
+	h, ok := srv.Plugin.(WebhookFilter)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not filter webhooks")
+	}
+	return h.FilterWebhook(ctx, req)
+}
+
+// MutateJob can change a job before it starts, or reject it
+func (srv *hookServer) MutateJob(ctx context.Context, req *v1.MutateJobRequest) (*v1.MutateJobResponse, error) {
+	h, ok := srv.Plugin.(JobMutator)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not mutate jobs")
+	}
+	return h.MutateJob(ctx, req)
+}
+
+// JobDone is called once a job is done
+func (srv *hookServer) JobDone(ctx context.Context, req *v1.JobDoneRequest) (*v1.JobDoneResponse, error) {
+	h, ok := srv.Plugin.(JobDoneHook)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not handle done jobs")
+	}
+	err := h.JobDone(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.JobDoneResponse{}, nil
+}
+
+// PreCreatePod can change the pod of a job before it's created, or reject it
+func (srv *hookServer) PreCreatePod(ctx context.Context, req *v1.PodHookRequest) (*v1.PreCreatePodResponse, error) {
+	h, ok := srv.Plugin.(PodPreCreateHook)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not change pods")
+	}
+	return h.PreCreatePod(ctx, req)
+}
+
+// PostCreatePod is called once the pod of a job was created
+func (srv *hookServer) PostCreatePod(ctx context.Context, req *v1.PodHookRequest) (*v1.PodHookResponse, error) {
+	h, ok := srv.Plugin.(PodPostCreateHook)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not handle created pods")
+	}
+	err := h.PostCreatePod(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.PodHookResponse{}, nil
+}
+
+// PreDeletePod is called before the pod of a job is deleted
+func (srv *hookServer) PreDeletePod(ctx context.Context, req *v1.PodHookRequest) (*v1.PodHookResponse, error) {
+	h, ok := srv.Plugin.(PodPreDeleteHook)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "plugin does not handle deleted pods")
+	}
+	err := h.PreDeletePod(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.PodHookResponse{}, nil
+}
+
 // Serve is the main entry point for plugins
 func Serve(configType interface{}, opts ...ServeOpt) {
 	if typ := reflect.TypeOf(configType); typ.Kind() != reflect.Ptr {
@@ -6,4 +6,14 @@ type Type string
 const (
 	// TypeIntegration means the plugin can act as integration plugin
 	TypeIntegration Type = "integration"
+
+	// TypeLogParser means the plugin can extract results from job logs
+	TypeLogParser Type = "logparser"
+
+	// TypeHook means the plugin takes part in handling webhooks and running jobs
+	TypeHook Type = "hook"
 )
+
+// HookAPIVersion is the version of the hook plugin API, i.e. the HookPlugin gRPC service.
+// It changes whenever the service changes in an incompatible way.
+const HookAPIVersion = 1
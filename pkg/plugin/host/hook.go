@@ -0,0 +1,136 @@
+package host
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/plugin/common"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// hookStartTimeout is the time hook plugins get to start serving
+const hookStartTimeout = 30 * time.Second
+
+// HookPlugin calls a hook plugin. Calls to hooks the plugin does not implement return an empty response.
+type HookPlugin struct {
+	name   string
+	client v1.HookPluginClient
+	hooks  map[v1.Hook]bool
+}
+
+// newHookPlugin waits for a hook plugin to start, and makes sure it speaks our version of the hook API
+func newHookPlugin(name string, client v1.HookPluginClient) (*HookPlugin, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), hookStartTimeout)
+	defer cancel()
+
+	info, err := client.Info(ctx, &v1.HookInfoRequest{ApiVersion: common.HookAPIVersion}, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get info of hook plugin %s: %w", name, err)
+	}
+	if info.ApiVersion != common.HookAPIVersion {
+		return nil, xerrors.Errorf("hook plugin %s speaks hook API v%d, but werft speaks v%d", name, info.ApiVersion, common.HookAPIVersion)
+	}
+
+	hooks := make(map[v1.Hook]bool, len(info.Hooks))
+	for _, h := range info.Hooks {
+		hooks[h] = true
+	}
+	return &HookPlugin{name: name, client: client, hooks: hooks}, nil
+}
+
+// Name returns the name the plugin was registered with
+func (p *HookPlugin) Name() string {
+	return p.name
+}
+
+// FilterWebhook decides if werft handles a webhook event
+func (p *HookPlugin) FilterWebhook(ctx context.Context, req *v1.FilterWebhookRequest) (*v1.FilterWebhookResponse, error) {
+	if !p.hooks[v1.Hook_HOOK_FILTER_WEBHOOK] {
+		return &v1.FilterWebhookResponse{}, nil
+	}
+	return p.client.FilterWebhook(ctx, req)
+}
+
+// MutateJob can change a job before it starts, or reject it
+func (p *HookPlugin) MutateJob(ctx context.Context, req *v1.MutateJobRequest) (*v1.MutateJobResponse, error) {
+	if !p.hooks[v1.Hook_HOOK_MUTATE_JOB] {
+		return &v1.MutateJobResponse{}, nil
+	}
+	return p.client.MutateJob(ctx, req)
+}
+
+// JobDone is called once a job is done
+func (p *HookPlugin) JobDone(ctx context.Context, req *v1.JobDoneRequest) error {
+	if !p.hooks[v1.Hook_HOOK_JOB_DONE] {
+		return nil
+	}
+	_, err := p.client.JobDone(ctx, req)
+	return err
+}
+
+// PreCreatePod can change the pod of a job before it's created, or reject it
+func (p *HookPlugin) PreCreatePod(ctx context.Context, pod *corev1.Pod) error {
+	if !p.hooks[v1.Hook_HOOK_PRE_CREATE_POD] {
+		return nil
+	}
+	req, err := podHookRequest(pod)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.PreCreatePod(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Reject != "" {
+		return xerrors.Errorf("rejected: %s", resp.Reject)
+	}
+	if len(resp.Pod) == 0 {
+		return nil
+	}
+
+	var mutated corev1.Pod
+	err = json.Unmarshal(resp.Pod, &mutated)
+	if err != nil {
+		return xerrors.Errorf("invalid pod: %w", err)
+	}
+	*pod = mutated
+	return nil
+}
+
+// PostCreatePod is called once the pod of a job was created
+func (p *HookPlugin) PostCreatePod(ctx context.Context, pod *corev1.Pod) error {
+	if !p.hooks[v1.Hook_HOOK_POST_CREATE_POD] {
+		return nil
+	}
+	req, err := podHookRequest(pod)
+	if err != nil {
+		return err
+	}
+	_, err = p.client.PostCreatePod(ctx, req)
+	return err
+}
+
+// PreDeletePod is called before the pod of a job is deleted
+func (p *HookPlugin) PreDeletePod(ctx context.Context, pod *corev1.Pod) error {
+	if !p.hooks[v1.Hook_HOOK_PRE_DELETE_POD] {
+		return nil
+	}
+	req, err := podHookRequest(pod)
+	if err != nil {
+		return err
+	}
+	_, err = p.client.PreDeletePod(ctx, req)
+	return err
+}
+
+func podHookRequest(pod *corev1.Pod) (*v1.PodHookRequest, error) {
+	fc, err := json.Marshal(pod)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot marshal pod: %w", err)
+	}
+	return &v1.PodHookRequest{Pod: fc}, nil
+}
@@ -0,0 +1,121 @@
+package host
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// testHookClient answers the pod hooks of a plugin. Calling any other method panics.
+type testHookClient struct {
+	v1.HookPluginClient
+
+	resp  *v1.PreCreatePodResponse
+	calls []string
+}
+
+func (c *testHookClient) record(call string, req *v1.PodHookRequest) {
+	var pod corev1.Pod
+	_ = json.Unmarshal(req.Pod, &pod)
+	c.calls = append(c.calls, call+":"+pod.Name)
+}
+
+func (c *testHookClient) PreCreatePod(ctx context.Context, req *v1.PodHookRequest, opts ...grpc.CallOption) (*v1.PreCreatePodResponse, error) {
+	c.record("pre-create", req)
+	return c.resp, nil
+}
+
+func (c *testHookClient) PostCreatePod(ctx context.Context, req *v1.PodHookRequest, opts ...grpc.CallOption) (*v1.PodHookResponse, error) {
+	c.record("post-create", req)
+	return &v1.PodHookResponse{}, nil
+}
+
+func (c *testHookClient) PreDeletePod(ctx context.Context, req *v1.PodHookRequest, opts ...grpc.CallOption) (*v1.PodHookResponse, error) {
+	c.record("pre-delete", req)
+	return &v1.PodHookResponse{}, nil
+}
+
+func TestHookPluginPodHooks(t *testing.T) {
+	mutatedPod := &corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-a"}}
+	mutatedPod.Name = "job"
+	mutatedPod.Labels = map[string]string{"mutated": "true"}
+	mutated, err := json.Marshal(mutatedPod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name    string
+		Hooks   []v1.Hook
+		Resp    *v1.PreCreatePodResponse
+		Error   string
+		Mutated bool
+		Calls   string
+	}{
+		{Name: "no pod hooks", Hooks: []v1.Hook{v1.Hook_HOOK_JOB_DONE}},
+		{
+			Name:  "unchanged pod",
+			Hooks: []v1.Hook{v1.Hook_HOOK_PRE_CREATE_POD, v1.Hook_HOOK_POST_CREATE_POD, v1.Hook_HOOK_PRE_DELETE_POD},
+			Resp:  &v1.PreCreatePodResponse{},
+			Calls: "pre-create:job,post-create:job,pre-delete:job",
+		},
+		{
+			Name:    "mutated pod",
+			Hooks:   []v1.Hook{v1.Hook_HOOK_PRE_CREATE_POD},
+			Resp:    &v1.PreCreatePodResponse{Pod: mutated},
+			Mutated: true,
+			Calls:   "pre-create:job",
+		},
+		{
+			Name:  "rejected pod",
+			Hooks: []v1.Hook{v1.Hook_HOOK_PRE_CREATE_POD, v1.Hook_HOOK_PRE_DELETE_POD},
+			Resp:  &v1.PreCreatePodResponse{Reject: "no GPUs here"},
+			Error: "rejected: no GPUs here",
+			Calls: "pre-create:job,pre-delete:job",
+		},
+		{
+			Name:  "invalid pod",
+			Hooks: []v1.Hook{v1.Hook_HOOK_PRE_CREATE_POD},
+			Resp:  &v1.PreCreatePodResponse{Pod: []byte("{")},
+			Error: "invalid pod",
+			Calls: "pre-create:job",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			client := &testHookClient{resp: test.Resp}
+			hooks := make(map[v1.Hook]bool)
+			for _, h := range test.Hooks {
+				hooks[h] = true
+			}
+			p := &HookPlugin{name: "test", client: client, hooks: hooks}
+
+			pod := &corev1.Pod{}
+			pod.Name = "job"
+			err := p.PreCreatePod(context.Background(), pod)
+			if test.Error == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			} else if test.Error != "" && (err == nil || !strings.Contains(err.Error(), test.Error)) {
+				t.Errorf("expected error %q, got %v", test.Error, err)
+			}
+			if act := pod.Labels["mutated"] == "true" && pod.Spec.NodeName == "node-a"; act != test.Mutated {
+				t.Errorf("expected the pod to be mutated: %v, got %+v", test.Mutated, pod)
+			}
+
+			if err := p.PostCreatePod(context.Background(), pod); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if err := p.PreDeletePod(context.Background(), pod); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if act := strings.Join(client.calls, ","); act != test.Calls {
+				t.Errorf("expected calls %q, got %q", test.Calls, act)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logparser"
 	"github.com/32leaves/werft/pkg/plugin/common"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
@@ -32,6 +33,12 @@ type Config []Registration
 type Plugins struct {
 	Errchan chan Error
 
+	// LogParsers forward job logs to the log parser plugins
+	LogParsers []logparser.Parser
+
+	// Hooks call the hook plugins
+	Hooks []*HookPlugin
+
 	stopchan     chan struct{}
 	sockets      map[string]string
 	werftService v1.WerftServiceServer
@@ -74,12 +81,17 @@ func Start(cfg Config, srv v1.WerftServiceServer) (*Plugins, error) {
 	return plugins, nil
 }
 
-func (p *Plugins) socketFor(t common.Type) (string, error) {
+// socketFor returns the socket a plugin of type t communicates on. For plugin types which werft calls,
+// rather than the plugin calling werft, it also returns the connection to the plugin.
+func (p *Plugins) socketFor(reg Registration, t common.Type) (string, *grpc.ClientConn, error) {
 	switch t {
 	case common.TypeIntegration:
-		return p.socketForIntegrationPlugin()
+		socket, err := p.socketForIntegrationPlugin()
+		return socket, nil, err
+	case common.TypeLogParser, common.TypeHook:
+		return p.socketForServingPlugin(reg, t)
 	default:
-		return "", xerrors.Errorf("unknown plugin type %s", t)
+		return "", nil, xerrors.Errorf("unknown plugin type %s", t)
 	}
 }
 
@@ -111,6 +123,29 @@ func (p *Plugins) socketForIntegrationPlugin() (string, error) {
 	return socketFN, nil
 }
 
+// socketForServingPlugin returns the socket a log parser or hook plugin serves on. Unlike integration plugins,
+// which all connect to werft's socket, each of these plugins gets a socket of its own which werft connects to.
+func (p *Plugins) socketForServingPlugin(reg Registration, t common.Type) (string, *grpc.ClientConn, error) {
+	name := fmt.Sprintf("%s-%s", reg.Name, t)
+	socketFN := filepath.Join(os.TempDir(), fmt.Sprintf("werft-plugin-%s-%d.sock", name, time.Now().UnixNano()))
+
+	conn, err := grpc.Dial(socketFN, grpc.WithInsecure(), grpc.WithDialer(unixConnect))
+	if err != nil {
+		return "", nil, xerrors.Errorf("cannot connect to %s plugin: %w", t, err)
+	}
+	go func() {
+		<-p.stopchan
+		conn.Close()
+	}()
+
+	p.sockets[name] = socketFN
+	return socketFN, conn, nil
+}
+
+func unixConnect(addr string, t time.Duration) (net.Conn, error) {
+	return net.DialTimeout("unix", addr, t)
+}
+
 func (p *Plugins) startPlugin(reg Registration) error {
 	cfgfile, err := ioutil.TempFile(os.TempDir(), "werft-plugin-cfg")
 	if err != nil {
@@ -126,7 +161,7 @@ func (p *Plugins) startPlugin(reg Registration) error {
 	}
 
 	for _, t := range reg.Type {
-		socket, err := p.socketFor(t)
+		socket, conn, err := p.socketFor(reg, t)
 		if err != nil {
 			return err
 		}
@@ -180,6 +215,17 @@ func (p *Plugins) startPlugin(reg Registration) error {
 				cmd.Process.Kill()
 			}
 		}()
+
+		switch t {
+		case common.TypeLogParser:
+			p.LogParsers = append(p.LogParsers, logparser.NewPluginParser(reg.Name, v1.NewLogParserPluginClient(conn)))
+		case common.TypeHook:
+			hook, err := newHookPlugin(reg.Name, v1.NewHookPluginClient(conn))
+			if err != nil {
+				return err
+			}
+			p.Hooks = append(p.Hooks, hook)
+		}
 	}
 
 	return nil
@@ -1,6 +1,8 @@
 package prettyprint
 
 import (
+	"fmt"
+	"strings"
 	"text/tabwriter"
 	"text/template"
 	"time"
@@ -23,6 +25,13 @@ func formatTemplate(pp *Content) error {
 				}
 				return ts.Format(time.RFC3339)
 			},
+			"toBytes": formatBytes,
+			"firstLine": func(s string) string {
+				return strings.SplitN(s, "\n", 2)[0]
+			},
+			"indent": func(s string) string {
+				return "  " + strings.ReplaceAll(s, "\n", "\n  ")
+			},
 		}).
 		Parse(pp.Template)
 	if err != nil {
@@ -38,3 +47,18 @@ func formatTemplate(pp *Content) error {
 	}
 	return nil
 }
+
+// formatBytes renders a number of bytes using binary units, e.g. 1.5Gi
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit && exp < 3; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%si", float64(n)/float64(div), []string{"K", "M", "G", "T"}[exp])
+}
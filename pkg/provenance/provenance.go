@@ -0,0 +1,255 @@
+package provenance
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// StatementType is the type of in-toto statements
+	StatementType = "https://in-toto.io/Statement/v0.1"
+
+	// PredicateType is the type of the SLSA provenance predicate
+	PredicateType = "https://slsa.dev/provenance/v0.2"
+
+	// BuildType describes how werft builds, i.e. by running a job spec on a revision of a repository
+	BuildType = "https://github.com/32leaves/werft/job@v1"
+
+	// PayloadType is the payload type of envelopes holding in-toto statements
+	PayloadType = "application/vnd.in-toto+json"
+)
+
+// Statement is an in-toto statement which attests the provenance of the subjects
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is something a job produced, e.g. a container image
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// Predicate describes how the subjects were built
+type Predicate struct {
+	Builder    Builder    `json:"builder"`
+	BuildType  string     `json:"buildType"`
+	Invocation Invocation `json:"invocation"`
+	Metadata   Metadata   `json:"metadata"`
+	Materials  []Material `json:"materials,omitempty"`
+}
+
+// Builder identifies the werft installation which ran the job
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Invocation describes what the job ran
+type Invocation struct {
+	ConfigSource ConfigSource      `json:"configSource"`
+	Parameters   map[string]string `json:"parameters,omitempty"`
+	Environment  map[string]string `json:"environment,omitempty"`
+}
+
+// ConfigSource points to the revision of the repository the job ran on
+type ConfigSource struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Metadata describes the job which produced the subjects
+type Metadata struct {
+	BuildInvocationID string     `json:"buildInvocationId"`
+	BuildStartedOn    *time.Time `json:"buildStartedOn,omitempty"`
+	BuildFinishedOn   *time.Time `json:"buildFinishedOn,omitempty"`
+}
+
+// Material is an input of the job, e.g. the source it was built from
+type Material struct {
+	URI    string            `json:"uri"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+// Envelope is a DSSE envelope holding a signed statement
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     []byte      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature signs the payload of an envelope
+type Signature struct {
+	KeyID string `json:"keyid,omitempty"`
+	Sig   []byte `json:"sig"`
+}
+
+// FromJob produces the provenance statement of a finished job. The results of the job are its subjects.
+// Results whose payload carries a digest (e.g. eu.gcr.io/foo/bar@sha256:...) are subjects with that digest.
+func FromJob(job *v1.JobStatus, builderID string) (*Statement, error) {
+	md := job.Metadata
+	if md == nil || md.Repository == nil {
+		return nil, xerrors.Errorf("job %s has no repository", job.Name)
+	}
+	repo := md.Repository
+
+	source := ConfigSource{
+		URI:    fmt.Sprintf("git+https://%s/%s/%s", repo.Host, repo.Owner, repo.Repo),
+		Digest: map[string]string{"sha1": repo.Revision},
+	}
+	if repo.Ref != "" {
+		source.URI += "@" + repo.Ref
+	}
+
+	res := &Statement{
+		Type:          StatementType,
+		Subject:       []Subject{},
+		PredicateType: PredicateType,
+		Predicate: Predicate{
+			Builder:   Builder{ID: builderID},
+			BuildType: BuildType,
+			Invocation: Invocation{
+				ConfigSource: source,
+				Environment: map[string]string{
+					"trigger": strings.ToLower(strings.TrimPrefix(md.Trigger.String(), "TRIGGER_")),
+				},
+			},
+			Metadata: Metadata{
+				BuildInvocationID: job.Name,
+			},
+			Materials: []Material{
+				{URI: source.URI, Digest: source.Digest},
+			},
+		},
+	}
+	if md.SpecHash != "" {
+		res.Predicate.Invocation.Environment["specHash"] = md.SpecHash
+	}
+	if len(md.Annotations) > 0 {
+		res.Predicate.Invocation.Parameters = make(map[string]string, len(md.Annotations))
+		for _, a := range md.Annotations {
+			res.Predicate.Invocation.Parameters[a.Key] = a.Value
+		}
+	}
+	if md.Created != nil {
+		t, err := ptypes.Timestamp(md.Created)
+		if err == nil {
+			res.Predicate.Metadata.BuildStartedOn = &t
+		}
+	}
+	if md.Finished != nil {
+		t, err := ptypes.Timestamp(md.Finished)
+		if err == nil {
+			res.Predicate.Metadata.BuildFinishedOn = &t
+		}
+	}
+
+	for _, r := range job.Results {
+		s := Subject{Name: r.Payload}
+		if i := strings.Index(r.Payload, "@sha256:"); i > -1 {
+			s.Name = r.Payload[:i]
+			s.Digest = map[string]string{"sha256": r.Payload[i+len("@sha256:"):]}
+		}
+		res.Subject = append(res.Subject, s)
+	}
+	sort.Slice(res.Subject, func(i, j int) bool { return res.Subject[i].Name < res.Subject[j].Name })
+
+	return res, nil
+}
+
+// pae computes the pre-authentication encoding of DSSE, which is what gets signed
+func pae(payloadType string, payload []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "DSSEv1 %d %s %d ", len(payloadType), payloadType, len(payload))
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+// Sign produces an envelope holding the statement signed by key
+func Sign(stmt *Statement, key ed25519.PrivateKey, keyID string) (*Envelope, error) {
+	payload, err := json.Marshal(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     payload,
+		Signatures: []Signature{
+			{KeyID: keyID, Sig: ed25519.Sign(key, pae(PayloadType, payload))},
+		},
+	}, nil
+}
+
+// Verify checks that the envelope was signed by key and returns the statement it holds
+func Verify(env *Envelope, key ed25519.PublicKey) (*Statement, error) {
+	if env.PayloadType != PayloadType {
+		return nil, xerrors.Errorf("unsupported payload type %s", env.PayloadType)
+	}
+
+	msg := pae(env.PayloadType, env.Payload)
+	var valid bool
+	for _, s := range env.Signatures {
+		if ed25519.Verify(key, msg, s.Sig) {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, xerrors.Errorf("provenance is not signed by this key")
+	}
+
+	var stmt Statement
+	err := json.Unmarshal(env.Payload, &stmt)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal statement: %w", err)
+	}
+	return &stmt, nil
+}
+
+// ParsePrivateKey reads a PEM encoded PKCS8 ed25519 private key, e.g. produced by openssl genpkey -algorithm ed25519
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, xerrors.Errorf("no PEM data found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	res, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, xerrors.Errorf("key is not an ed25519 private key")
+	}
+	return res, nil
+}
+
+// ParsePublicKey reads a PEM encoded PKIX ed25519 public key, e.g. produced by openssl pkey -pubout
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, xerrors.Errorf("no PEM data found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	res, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, xerrors.Errorf("key is not an ed25519 public key")
+	}
+	return res, nil
+}
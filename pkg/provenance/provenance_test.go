@@ -0,0 +1,131 @@
+package provenance_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/provenance"
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestFromJob(t *testing.T) {
+	created := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	createdTS, _ := ptypes.TimestampProto(created)
+	job := &v1.JobStatus{
+		Name: "werft-build-master.4",
+		Metadata: &v1.JobMetadata{
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master", Revision: "a3f5ee7"},
+			Trigger:    v1.JobTrigger_TRIGGER_PUSH,
+			Created:    createdTS,
+			SpecHash:   "sha256:1234",
+		},
+		Results: []*v1.JobResult{
+			{Type: "docker", Payload: "eu.gcr.io/werft/werft@sha256:abcd"},
+			{Type: "url", Payload: "https://example.com"},
+		},
+	}
+
+	stmt, err := provenance.FromJob(job, "https://werft.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stmt.Predicate.Invocation.ConfigSource.URI != "git+https://github.com/32leaves/werft@refs/heads/master" {
+		t.Errorf("unexpected config source %s", stmt.Predicate.Invocation.ConfigSource.URI)
+	}
+	if rev := stmt.Predicate.Invocation.ConfigSource.Digest["sha1"]; rev != "a3f5ee7" {
+		t.Errorf("expected revision a3f5ee7, got %s", rev)
+	}
+	if h := stmt.Predicate.Invocation.Environment["specHash"]; h != "sha256:1234" {
+		t.Errorf("expected spec hash sha256:1234, got %s", h)
+	}
+	if s := stmt.Predicate.Metadata.BuildStartedOn; s == nil || !s.Equal(created) {
+		t.Errorf("expected build to start on %v, got %v", created, s)
+	}
+	if len(stmt.Subject) != 2 {
+		t.Fatalf("expected 2 subjects, got %d", len(stmt.Subject))
+	}
+	if s := stmt.Subject[0]; s.Name != "eu.gcr.io/werft/werft" || s.Digest["sha256"] != "abcd" {
+		t.Errorf("unexpected subject %v", s)
+	}
+	if s := stmt.Subject[1]; s.Name != "https://example.com" || s.Digest != nil {
+		t.Errorf("unexpected subject %v", s)
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, err := provenance.FromJob(&v1.JobStatus{Name: "foo.1", Metadata: &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com"}}}, "werft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := provenance.Sign(stmt, priv, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, err := provenance.Verify(env, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act.Predicate.Metadata.BuildInvocationID != "foo.1" {
+		t.Errorf("expected statement of foo.1, got %s", act.Predicate.Metadata.BuildInvocationID)
+	}
+
+	if _, err := provenance.Verify(env, otherPub); err == nil {
+		t.Error("expected verification with another key to fail")
+	}
+
+	env.Payload = append(env.Payload, ' ')
+	if _, err := provenance.Verify(env, pub); err == nil {
+		t.Error("expected verification of a modified payload to fail")
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := provenance.ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Equal(priv) {
+		t.Error("private key does not match")
+	}
+
+	q, err := provenance.ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !q.Equal(pub) {
+		t.Error("public key does not match")
+	}
+
+	if _, err := provenance.ParsePrivateKey([]byte("not a key")); err == nil {
+		t.Error("expected an error for invalid PEM data")
+	}
+}
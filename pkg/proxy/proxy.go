@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/xerrors"
+)
+
+// Config configures the proxy outbound HTTP traffic goes through, e.g. to GitHub.
+// Settings which are empty fall back to the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+type Config struct {
+	// HTTPProxy is the proxy used for plain HTTP requests, e.g. http://proxy.example.com:3128
+	HTTPProxy string `yaml:"httpProxy,omitempty"`
+
+	// HTTPSProxy is the proxy used for HTTPS requests
+	HTTPSProxy string `yaml:"httpsProxy,omitempty"`
+
+	// NoProxy is a comma-separated list of hosts, domains and CIDRs which are reached directly, e.g. .svc,10.0.0.0/8
+	NoProxy string `yaml:"noProxy,omitempty"`
+}
+
+// Func returns a proxy function as used by http.Transport
+func (c Config) Func() func(*http.Request) (*url.URL, error) {
+	cfg := httpproxy.FromEnvironment()
+	if c.HTTPProxy != "" {
+		cfg.HTTPProxy = c.HTTPProxy
+	}
+	if c.HTTPSProxy != "" {
+		cfg.HTTPSProxy = c.HTTPSProxy
+	}
+	if c.NoProxy != "" {
+		cfg.NoProxy = c.NoProxy
+	}
+
+	f := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return f(req.URL)
+	}
+}
+
+// Transport returns a copy of http.DefaultTransport which uses the configured proxy
+func (c Config) Transport() (*http.Transport, error) {
+	for _, p := range []string{c.HTTPProxy, c.HTTPSProxy} {
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "://") {
+			// like the environment variables we accept proxies without scheme
+			p = "http://" + p
+		}
+		u, err := url.Parse(p)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid proxy %s: %w", p, err)
+		}
+		if u.Hostname() == "" {
+			return nil, xerrors.Errorf("invalid proxy %s: missing host", p)
+		}
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.Proxy = c.Func()
+	return tr, nil
+}
@@ -0,0 +1,54 @@
+package proxy_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/proxy"
+)
+
+func TestTransport(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Config      proxy.Config
+		URL         string
+		Expectation string
+		Error       bool
+	}{
+		{"https", proxy.Config{HTTPSProxy: "http://proxy.example.com:3128"}, "https://api.github.com/meta", "http://proxy.example.com:3128", false},
+		{"http", proxy.Config{HTTPProxy: "proxy.example.com:3128", HTTPSProxy: "http://other.example.com"}, "http://loki.example.com/push", "http://proxy.example.com:3128", false},
+		{"no proxy", proxy.Config{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: ".svc,github.com"}, "https://api.github.com/meta", "", false},
+		{"no proxy cidr", proxy.Config{HTTPProxy: "http://proxy.example.com:3128", NoProxy: "10.0.0.0/8"}, "http://10.1.2.3/push", "", false},
+		{"invalid proxy", proxy.Config{HTTPSProxy: "http://:3128"}, "", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			tr, err := test.Config.Transport()
+			if test.Error {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req, err := http.NewRequest("GET", test.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := tr.Proxy(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var act string
+			if u != nil {
+				act = u.String()
+			}
+			if act != test.Expectation {
+				t.Errorf("expected proxy %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
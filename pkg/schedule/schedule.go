@@ -0,0 +1,84 @@
+package schedule
+
+import (
+	"time"
+
+	cron "github.com/robfig/cron/v3"
+	"golang.org/x/xerrors"
+)
+
+// parser parses the cron specs werft supports: five fields (minute to day of week) or descriptors, e.g. @daily or @every 1h
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Schedule is a cron schedule evaluated in a time zone. It implements cron.Schedule.
+//
+// The schedule is evaluated in wall clock time of its time zone, hence it's safe across daylight saving time changes:
+// runs which fall into the hour skipped when clocks go forward don't happen that day, and runs which fall into the hour
+// repeated when clocks go back happen once only.
+type Schedule struct {
+	Spec     string
+	Location *time.Location
+
+	schedule cron.Schedule
+}
+
+// Parse parses a cron spec, e.g. "0 3 * * *", to be evaluated in a time zone, e.g. Europe/Berlin.
+// An empty time zone is UTC.
+func Parse(spec, timezone string) (*Schedule, error) {
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	s, err := parser.Parse(spec)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid schedule %s: %w", spec, err)
+	}
+	return &Schedule{Spec: spec, Location: loc, schedule: s}, nil
+}
+
+// LoadLocation returns the time zone of the name, e.g. Europe/Berlin. An empty name is UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid time zone %s: %w", name, err)
+	}
+	return loc, nil
+}
+
+// Next returns the first run after t in UTC, or the zero time if the schedule never runs
+func (s *Schedule) Next(t time.Time) time.Time {
+	n := s.schedule.Next(t.In(s.Location))
+	if _, ok := s.schedule.(*cron.SpecSchedule); ok {
+		// cron runs twice when clocks go back - we skip the second time the wall clock shows the same time
+		for !n.IsZero() && sameWallClock(n.Add(-time.Hour), n) {
+			n = s.schedule.Next(n)
+		}
+	}
+	if n.IsZero() {
+		return n
+	}
+	return n.UTC()
+}
+
+// sameWallClock returns true if the clock on the wall shows the same time at a and b
+func sameWallClock(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd && a.Hour() == b.Hour() && a.Minute() == b.Minute() && a.Second() == b.Second()
+}
+
+// NextRuns returns the next n runs after t in UTC
+func (s *Schedule) NextRuns(t time.Time, n int) []time.Time {
+	res := make([]time.Time, 0, n)
+	for i := 0; i < n; i++ {
+		t = s.Next(t)
+		if t.IsZero() {
+			break
+		}
+		res = append(res, t)
+	}
+	return res
+}
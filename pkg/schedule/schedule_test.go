@@ -0,0 +1,54 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/schedule"
+)
+
+func TestNextRuns(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Spec        string
+		Timezone    string
+		From        string
+		Expectation []string
+		Error       bool
+	}{
+		{"utc", "0 3 * * *", "", "2020-03-28T12:00:00Z", []string{"2020-03-29T03:00:00Z", "2020-03-30T03:00:00Z"}, false},
+		{"time zone", "0 3 * * *", "Europe/Berlin", "2020-03-26T12:00:00Z", []string{"2020-03-27T02:00:00Z", "2020-03-28T02:00:00Z"}, false},
+		{"clocks go forward", "0 3 * * *", "Europe/Berlin", "2020-03-28T12:00:00Z", []string{"2020-03-29T01:00:00Z", "2020-03-30T01:00:00Z"}, false},
+		{"skipped hour", "30 2 * * *", "Europe/Berlin", "2020-03-28T12:00:00Z", []string{"2020-03-30T00:30:00Z", "2020-03-31T00:30:00Z"}, false},
+		{"repeated hour", "30 2 * * *", "Europe/Berlin", "2020-10-24T12:00:00Z", []string{"2020-10-25T00:30:00Z", "2020-10-26T01:30:00Z"}, false},
+		{"descriptor", "@every 1h", "", "2020-03-28T12:00:00Z", []string{"2020-03-28T13:00:00Z", "2020-03-28T14:00:00Z"}, false},
+		{"invalid spec", "0 3 * *", "", "2020-03-28T12:00:00Z", nil, true},
+		{"invalid time zone", "0 3 * * *", "Mars/Olympus", "2020-03-28T12:00:00Z", nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			s, err := schedule.Parse(test.Spec, test.Timezone)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+
+			from, _ := time.Parse(time.RFC3339, test.From)
+			var act []string
+			for _, r := range s.NextRuns(from, 2) {
+				act = append(act, r.Format(time.RFC3339))
+			}
+			if len(act) != len(test.Expectation) {
+				t.Fatalf("expected %v, got %v", test.Expectation, act)
+			}
+			for i := range act {
+				if act[i] != test.Expectation[i] {
+					t.Errorf("expected %v, got %v", test.Expectation, act)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package store
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/gogo/protobuf/jsonpb"
+	"golang.org/x/xerrors"
+)
+
+// ObjectJobArchive archives jobs as gzipped JSON objects
+type ObjectJobArchive struct {
+	Objects ObjectStore
+	Prefix  string
+}
+
+// NewObjectJobArchive creates a new job archive which keeps the jobs in objects, prefixing their keys with prefix
+func NewObjectJobArchive(objects ObjectStore, prefix string) *ObjectJobArchive {
+	return &ObjectJobArchive{Objects: objects, Prefix: prefix}
+}
+
+// NewFileJobArchive creates a new file backed job archive
+func NewFileJobArchive(base string) (*ObjectJobArchive, error) {
+	objects, err := NewFileObjectStore(base)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create archive location: %w", err)
+	}
+
+	return NewObjectJobArchive(objects, ""), nil
+}
+
+func (a *ObjectJobArchive) key(name string) string {
+	return fmt.Sprintf("%s%s.json.gz", a.Prefix, name)
+}
+
+// Put places a job in the archive
+func (a *ObjectJobArchive) Put(ctx context.Context, job v1.JobStatus) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	err := (&jsonpb.Marshaler{EnumsAsInts: true}).Marshal(gz, &job)
+	if err != nil {
+		return xerrors.Errorf("cannot marshal job %s: %w", job.Name, err)
+	}
+	err = gz.Close()
+	if err != nil {
+		return err
+	}
+
+	return a.Objects.Put(ctx, a.key(job.Name), &buf)
+}
+
+// Get retrieves a job from the archive
+func (a *ObjectJobArchive) Get(ctx context.Context, name string) (*v1.JobStatus, error) {
+	r, err := a.Objects.Get(ctx, a.key(name))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read archived job %s: %w", name, err)
+	}
+	defer gz.Close()
+
+	var res v1.JobStatus
+	err = jsonpb.Unmarshal(gz, &res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal archived job %s: %w", name, err)
+	}
+
+	return &res, nil
+}
+
+// Delete removes a job from the archive
+func (a *ObjectJobArchive) Delete(ctx context.Context, name string) error {
+	return a.Objects.Delete(ctx, a.key(name))
+}
@@ -0,0 +1,67 @@
+package store_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/proto"
+)
+
+func TestFileJobArchive(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "tfja")
+	if err != nil {
+		t.Fatalf("cannot create test folder: %v", err)
+	}
+	defer os.RemoveAll(base)
+
+	archive, err := store.NewFileJobArchive(base)
+	if err != nil {
+		t.Fatalf("cannot create archive: %v", err)
+	}
+
+	ctx := context.Background()
+	_, err = archive.Get(ctx, "foo")
+	if err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for unknown job, got %v", err)
+	}
+
+	job := v1.JobStatus{
+		Name: "foo.1",
+		Metadata: &v1.JobMetadata{
+			Owner:      "someone",
+			Repository: &v1.Repository{Owner: "32leaves", Repo: "werft"},
+			Trigger:    v1.JobTrigger_TRIGGER_PUSH,
+		},
+		Phase:      v1.JobPhase_PHASE_DONE,
+		Conditions: &v1.JobConditions{Success: true},
+	}
+	err = archive.Put(ctx, job)
+	if err != nil {
+		t.Fatalf("cannot put job: %v", err)
+	}
+
+	act, err := archive.Get(ctx, job.Name)
+	if err != nil {
+		t.Fatalf("cannot get job: %v", err)
+	}
+	if !proto.Equal(act, &job) {
+		t.Errorf("archived job does not match: %v != %v", act, &job)
+	}
+
+	err = archive.Delete(ctx, job.Name)
+	if err != nil {
+		t.Fatalf("cannot delete job: %v", err)
+	}
+	_, err = archive.Get(ctx, job.Name)
+	if err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for deleted job, got %v", err)
+	}
+	err = archive.Delete(ctx, job.Name)
+	if err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound when deleting an unknown job, got %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// DefaultMaxBatchSize is the number of jobs a batching job store writes at once by default
+const DefaultMaxBatchSize = 500
+
+// batchRetryInterval is the least time after which a batching job store tries again to write jobs which failed
+const batchRetryInterval = time.Second
+
+// BatchingJobStore coalesces job updates and writes them to another job store in batches. Jobs which change
+// frequently, e.g. with every pod event, thus cause one write per batch window rather than one per update.
+// Jobs which are yet to be written are served from memory, so that readers always see the latest job status.
+//
+// Updates live in memory only until they're written: if the process crashes, the updates of the last batch window
+// are lost. Jobs the delegate fails to store are reported to OnError and written again with the next batch, unless
+// they've changed in the meantime, until the store is closed.
+type BatchingJobStore struct {
+	// MaxBatchSize is the number of jobs written at once. Once that many jobs are pending they're written
+	// without waiting for the batch window to end.
+	MaxBatchSize int
+
+	// OnError is called for jobs which could not be stored. Store cannot return those errors
+	// as the jobs are written later. The jobs are retried with the next batch.
+	OnError func(job v1.JobStatus, err error)
+
+	delegate Jobs
+	window   time.Duration
+
+	mu       sync.Mutex
+	pending  map[string]v1.JobStatus
+	inflight map[string]v1.JobStatus
+	timer    *time.Timer
+	flushing bool
+	closed   bool
+
+	// flushMu serializes writes so that an older batch never overwrites a newer one
+	flushMu sync.Mutex
+}
+
+// NewBatchingJobStore creates a job store which writes job updates to delegate at most once per window
+func NewBatchingJobStore(delegate Jobs, window time.Duration) *BatchingJobStore {
+	return &BatchingJobStore{
+		MaxBatchSize: DefaultMaxBatchSize,
+		delegate:     delegate,
+		window:       window,
+		pending:      make(map[string]v1.JobStatus),
+	}
+}
+
+// Store queues a job for the next batch. Once the store is closed, jobs are stored right away.
+func (b *BatchingJobStore) Store(ctx context.Context, job v1.JobStatus) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return b.delegate.Store(ctx, job)
+	}
+
+	b.pending[job.Name] = job
+	if b.MaxBatchSize > 0 && len(b.pending) >= b.MaxBatchSize {
+		if !b.flushing {
+			b.flushing = true
+			go b.Flush(context.Background())
+		}
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, func() { b.Flush(context.Background()) })
+	}
+	b.mu.Unlock()
+
+	return nil
+}
+
+// Pending returns the number of jobs which wait to be written, including those which are being written
+func (b *BatchingJobStore) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.pending) + len(b.inflight)
+}
+
+// Flush writes all pending jobs
+func (b *BatchingJobStore) Flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.flushing = false
+	batch := b.pending
+	b.pending = make(map[string]v1.JobStatus)
+	b.inflight = batch
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	failed, err := b.write(ctx, batch)
+
+	b.mu.Lock()
+	b.inflight = nil
+	if !b.closed {
+		b.retry(failed)
+	}
+	b.mu.Unlock()
+
+	return err
+}
+
+// retry queues jobs which could not be written for the next batch, unless a newer update of them is pending already.
+// Callers must hold mu.
+func (b *BatchingJobStore) retry(failed []v1.JobStatus) {
+	for _, job := range failed {
+		if _, newer := b.pending[job.Name]; newer {
+			continue
+		}
+		b.pending[job.Name] = job
+	}
+	if len(failed) == 0 || b.timer != nil {
+		return
+	}
+
+	delay := b.window
+	if delay < batchRetryInterval {
+		delay = batchRetryInterval
+	}
+	b.timer = time.AfterFunc(delay, func() { b.Flush(context.Background()) })
+}
+
+// write stores a batch in the delegate and returns the jobs which could not be stored
+func (b *BatchingJobStore) write(ctx context.Context, batch map[string]v1.JobStatus) ([]v1.JobStatus, error) {
+	names := make([]string, 0, len(batch))
+	for name := range batch {
+		names = append(names, name)
+	}
+	// a stable order keeps concurrent writers of the delegate from deadlocking each other
+	sort.Strings(names)
+	jobs := make([]v1.JobStatus, len(names))
+	for i, name := range names {
+		jobs[i] = batch[name]
+	}
+
+	size := b.MaxBatchSize
+	if size <= 0 {
+		size = len(jobs)
+	}
+	var (
+		failed   []v1.JobStatus
+		firstErr error
+	)
+	for len(jobs) > 0 {
+		n := size
+		if n > len(jobs) {
+			n = len(jobs)
+		}
+		chunk := jobs[:n]
+		jobs = jobs[n:]
+
+		if bs, ok := b.delegate.(BatchJobs); ok {
+			err := bs.StoreBatch(ctx, chunk)
+			if err == nil {
+				continue
+			}
+		}
+
+		// we store the jobs one by one so that a single job cannot hold up all others
+		for _, job := range chunk {
+			err := b.delegate.Store(ctx, job)
+			if err == nil {
+				continue
+			}
+
+			failed = append(failed, job)
+			if firstErr == nil {
+				firstErr = err
+			}
+			if b.OnError != nil {
+				b.OnError(job, err)
+			}
+		}
+	}
+	if firstErr != nil {
+		return failed, xerrors.Errorf("cannot store %d of %d jobs: %w", len(failed), len(batch), firstErr)
+	}
+
+	return nil, nil
+}
+
+// Close writes all pending jobs. Jobs which cannot be written then are reported to OnError and dropped. Jobs stored
+// afterwards are written right away.
+func (b *BatchingJobStore) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	return b.Flush(context.Background())
+}
+
+// StoreJobSpec stores job YAML data.
+func (b *BatchingJobStore) StoreJobSpec(name string, data []byte) error {
+	return b.delegate.StoreJobSpec(name, data)
+}
+
+// Get retrieves a particular job, including updates which are yet to be written.
+func (b *BatchingJobStore) Get(ctx context.Context, name string) (*v1.JobStatus, error) {
+	b.mu.Lock()
+	job, ok := b.pending[name]
+	if !ok {
+		job, ok = b.inflight[name]
+	}
+	b.mu.Unlock()
+	if ok {
+		return &job, nil
+	}
+
+	return b.delegate.Get(ctx, name)
+}
+
+// GetJobSpec retrieves previously stored job spec data
+func (b *BatchingJobStore) GetJobSpec(name string) (data []byte, err error) {
+	return b.delegate.GetJobSpec(name)
+}
+
+// StoreResolvedSpec stores the resolved job spec
+func (b *BatchingJobStore) StoreResolvedSpec(name string, data []byte) error {
+	return b.delegate.StoreResolvedSpec(name, data)
+}
+
+// GetResolvedSpec retrieves a previously stored resolved job spec
+func (b *BatchingJobStore) GetResolvedSpec(name string) (data []byte, err error) {
+	return b.delegate.GetResolvedSpec(name)
+}
+
+// StoreProvenance stores the signed provenance of a job
+func (b *BatchingJobStore) StoreProvenance(name string, data []byte) error {
+	return b.delegate.StoreProvenance(name, data)
+}
+
+// GetProvenance retrieves the signed provenance of a job
+func (b *BatchingJobStore) GetProvenance(name string) (data []byte, err error) {
+	return b.delegate.GetProvenance(name)
+}
+
+// StoreTriggerPayload stores what triggered a job
+func (b *BatchingJobStore) StoreTriggerPayload(name string, data []byte) error {
+	return b.delegate.StoreTriggerPayload(name, data)
+}
+
+// GetTriggerPayload retrieves what triggered a job
+func (b *BatchingJobStore) GetTriggerPayload(name string) (data []byte, err error) {
+	return b.delegate.GetTriggerPayload(name)
+}
+
+// StoreSBOM stores the software bill of materials of an image a job built
+func (b *BatchingJobStore) StoreSBOM(name, image string, data []byte) error {
+	return b.delegate.StoreSBOM(name, image, data)
+}
+
+// GetSBOMs retrieves the software bills of materials of the images a job built
+func (b *BatchingJobStore) GetSBOMs(name string) (sboms map[string][]byte, err error) {
+	return b.delegate.GetSBOMs(name)
+}
+
+// AddEvent records something that happened to a job
+func (b *BatchingJobStore) AddEvent(ctx context.Context, name string, evt v1.JobEvent) error {
+	return b.delegate.AddEvent(ctx, name, evt)
+}
+
+// GetEvents returns the events of a job, oldest first
+func (b *BatchingJobStore) GetEvents(ctx context.Context, name string) ([]v1.JobEvent, error) {
+	return b.delegate.GetEvents(ctx, name)
+}
+
+// Find writes all pending jobs and searches for jobs in the delegate store. If ctx allows stale reads,
+// pending jobs aren't written first.
+func (b *BatchingJobStore) Find(ctx context.Context, filter []*v1.FilterExpression, order []*v1.OrderExpression, start, limit int) (slice []v1.JobStatus, total int, err error) {
+	if !StaleReadsAllowed(ctx) {
+		// jobs which could not be written were reported to OnError already
+		_ = b.Flush(ctx)
+	}
+
+	return b.delegate.Find(ctx, filter, order, start, limit)
+}
+
+// Delete writes all pending jobs and removes a job from the delegate store
+func (b *BatchingJobStore) Delete(ctx context.Context, name string) error {
+	_ = b.Flush(ctx)
+
+	return b.delegate.Delete(ctx, name)
+}
+
+// Purge writes all pending jobs and purges a job from the delegate store
+func (b *BatchingJobStore) Purge(ctx context.Context, name string) error {
+	_ = b.Flush(ctx)
+
+	return b.delegate.Purge(ctx, name)
+}
@@ -0,0 +1,253 @@
+package store_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"golang.org/x/xerrors"
+)
+
+// countingJobs is a job store which counts its writes and takes latency for each of them,
+// roughly like a round trip to a database
+type countingJobs struct {
+	store.Jobs
+
+	Latency    time.Duration
+	FailBatch  bool
+	FailSingle string
+
+	writes int32
+}
+
+func (c *countingJobs) Store(ctx context.Context, job v1.JobStatus) error {
+	atomic.AddInt32(&c.writes, 1)
+	time.Sleep(c.Latency)
+	if job.Name == c.FailSingle {
+		return xerrors.Errorf("cannot store %s", job.Name)
+	}
+	return c.Jobs.Store(ctx, job)
+}
+
+func (c *countingJobs) StoreBatch(ctx context.Context, jobs []v1.JobStatus) error {
+	atomic.AddInt32(&c.writes, 1)
+	time.Sleep(c.Latency)
+	if c.FailBatch {
+		return xerrors.Errorf("cannot store batch")
+	}
+	for _, job := range jobs {
+		err := c.Jobs.Store(ctx, job)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *countingJobs) Writes() int {
+	return int(atomic.LoadInt32(&c.writes))
+}
+
+func TestBatchingJobStore(t *testing.T) {
+	ctx := context.Background()
+	delegate := &countingJobs{Jobs: store.NewInMemoryJobStore()}
+	jobs := store.NewBatchingJobStore(delegate, time.Hour)
+
+	for _, phase := range []v1.JobPhase{v1.JobPhase_PHASE_PREPARING, v1.JobPhase_PHASE_RUNNING, v1.JobPhase_PHASE_DONE} {
+		for _, name := range []string{"foo.1", "bar.1"} {
+			err := jobs.Store(ctx, v1.JobStatus{Name: name, Phase: phase, Metadata: &v1.JobMetadata{}})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if w := delegate.Writes(); w != 0 {
+		t.Errorf("expected no writes before the batch window ends, got %d", w)
+	}
+
+	job, err := jobs.Get(ctx, "foo.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Phase != v1.JobPhase_PHASE_DONE {
+		t.Errorf("expected Get to return the pending job update, got phase %v", job.Phase)
+	}
+
+	_, _, err = jobs.Find(store.WithStaleReads(ctx), nil, nil, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := delegate.Writes(); w != 0 {
+		t.Errorf("expected Find with stale reads not to write pending updates, got %d writes", w)
+	}
+
+	_, total, err := jobs.Find(ctx, nil, nil, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Errorf("expected Find to see 2 jobs, got %d", total)
+	}
+	if w := delegate.Writes(); w != 1 {
+		t.Errorf("expected all updates to be written at once, got %d writes", w)
+	}
+}
+
+func TestBatchingJobStoreWindow(t *testing.T) {
+	ctx := context.Background()
+	delegate := &countingJobs{Jobs: store.NewInMemoryJobStore()}
+	jobs := store.NewBatchingJobStore(delegate, 10*time.Millisecond)
+
+	err := jobs.Store(ctx, v1.JobStatus{Name: "foo.1", Metadata: &v1.JobMetadata{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := delegate.Jobs.Get(ctx, "foo.1")
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected job to be written once the batch window ended: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBatchingJobStoreErrors(t *testing.T) {
+	ctx := context.Background()
+	delegate := &countingJobs{Jobs: store.NewInMemoryJobStore(), FailBatch: true, FailSingle: "bar.1"}
+	jobs := store.NewBatchingJobStore(delegate, time.Hour)
+	var failed []string
+	jobs.OnError = func(job v1.JobStatus, err error) {
+		failed = append(failed, job.Name)
+	}
+
+	for _, name := range []string{"foo.1", "bar.1", "baz.1"} {
+		err := jobs.Store(ctx, v1.JobStatus{Name: name, Metadata: &v1.JobMetadata{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := jobs.Close()
+	if err == nil {
+		t.Error("expected an error")
+	}
+	if len(failed) != 1 || failed[0] != "bar.1" {
+		t.Errorf("expected only bar.1 to fail, got %v", failed)
+	}
+	for _, name := range []string{"foo.1", "baz.1"} {
+		if _, err := delegate.Jobs.Get(ctx, name); err != nil {
+			t.Errorf("expected %s to be stored one by one after the batch failed: %v", name, err)
+		}
+	}
+
+	// once closed, jobs are written right away
+	err = jobs.Store(ctx, v1.JobStatus{Name: "qux.1", Metadata: &v1.JobMetadata{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := delegate.Jobs.Get(ctx, "qux.1"); err != nil {
+		t.Errorf("expected job to be written after close: %v", err)
+	}
+}
+
+func TestBatchingJobStoreRetry(t *testing.T) {
+	ctx := context.Background()
+	delegate := &countingJobs{Jobs: store.NewInMemoryJobStore(), FailBatch: true, FailSingle: "bar.1"}
+	jobs := store.NewBatchingJobStore(delegate, time.Hour)
+	defer jobs.Close()
+	var failed []string
+	jobs.OnError = func(job v1.JobStatus, err error) {
+		failed = append(failed, job.Name)
+	}
+
+	for _, name := range []string{"foo.1", "bar.1"} {
+		err := jobs.Store(ctx, v1.JobStatus{Name: name, Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := jobs.Flush(ctx)
+	if err == nil {
+		t.Error("expected an error")
+	}
+	if len(failed) != 1 || failed[0] != "bar.1" {
+		t.Errorf("expected only bar.1 to fail, got %v", failed)
+	}
+	if n := jobs.Pending(); n != 1 {
+		t.Errorf("expected the failed job to wait for the next batch, got %d pending jobs", n)
+	}
+	if job, err := jobs.Get(ctx, "bar.1"); err != nil || job.Phase != v1.JobPhase_PHASE_RUNNING {
+		t.Errorf("expected the failed job to be served from memory, got %v (%v)", job, err)
+	}
+
+	// the delegate recovers and a newer update of the job arrives before the next batch
+	delegate.FailSingle = ""
+	err = jobs.Store(ctx, v1.JobStatus{Name: "bar.1", Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = jobs.Flush(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := jobs.Pending(); n != 0 {
+		t.Errorf("expected no pending jobs, got %d", n)
+	}
+	if job, err := delegate.Jobs.Get(ctx, "bar.1"); err != nil || job.Phase != v1.JobPhase_PHASE_DONE {
+		t.Errorf("expected the latest update of the failed job to be stored, got %v (%v)", job, err)
+	}
+}
+
+// BenchmarkJobStatusUpdates stores updates of 50 concurrently running jobs, once directly and once batched
+func BenchmarkJobStatusUpdates(b *testing.B) {
+	const runningJobs = 50
+
+	tests := []struct {
+		Name   string
+		Window time.Duration
+	}{
+		{"direct", 0},
+		{"batched-10ms", 10 * time.Millisecond},
+		{"batched-100ms", 100 * time.Millisecond},
+	}
+	for _, test := range tests {
+		b.Run(test.Name, func(b *testing.B) {
+			ctx := context.Background()
+			delegate := &countingJobs{Jobs: store.NewInMemoryJobStore(), Latency: 100 * time.Microsecond}
+			var (
+				jobs  store.Jobs = delegate
+				batch *store.BatchingJobStore
+			)
+			if test.Window > 0 {
+				batch = store.NewBatchingJobStore(delegate, test.Window)
+				jobs = batch
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				err := jobs.Store(ctx, v1.JobStatus{
+					Name:     fmt.Sprintf("job.%d", i%runningJobs),
+					Phase:    v1.JobPhase_PHASE_RUNNING,
+					Metadata: &v1.JobMetadata{},
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			if batch != nil {
+				batch.Close()
+			}
+			b.StopTimer()
+
+			b.ReportMetric(float64(delegate.Writes())/float64(b.N), "writes/op")
+		})
+	}
+}
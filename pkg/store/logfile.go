@@ -1,66 +1,135 @@
 package store
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
 )
 
-// FileLogStore is a file backed log store
+// FileLogStore is a file backed log store. Each log is locked on its own, so that reading or writing
+// one log never holds up another. Logs are appended to in place and never rotated. Should werft crash while it
+// writes a record of an encrypted log, the incomplete record is cut off once the log is opened again.
 type FileLogStore struct {
 	Base string
 
+	aead         cipher.AEAD
+	syncInterval *time.Duration
+
+	// mu guards the files map only
 	mu    sync.Mutex
 	files map[string]*file
 }
 
 type file struct {
+	fn           string
+	aead         cipher.AEAD
+	syncInterval *time.Duration
+
+	// cond.L guards everything below
+	cond   *sync.Cond
 	closed bool
-	fn     string
 	fp     *os.File
-	cond   *sync.Cond
+	// size is the number of bytes written completely. Readers never read beyond that while the log is written,
+	// so that all of them see the same, consistent content.
+	size int64
+	// dirty is true if there are writes which are not synced to disk yet
+	dirty   bool
+	syncErr error
+}
+
+// FileLogStoreOption configures a file backed log store
+type FileLogStoreOption func(*FileLogStore) error
+
+// WithEncryptionKey makes the log store encrypt all logs it writes using AES-GCM.
+// The key must be 16, 24 or 32 bytes long. Logs which were written without encryption remain readable.
+func WithEncryptionKey(key []byte) FileLogStoreOption {
+	return func(fs *FileLogStore) error {
+		blk, err := aes.NewCipher(key)
+		if err != nil {
+			return xerrors.Errorf("invalid log encryption key: %w", err)
+		}
+		aead, err := cipher.NewGCM(blk)
+		if err != nil {
+			return xerrors.Errorf("invalid log encryption key: %w", err)
+		}
+		fs.aead = aead
+		return nil
+	}
+}
+
+// WithSyncInterval makes the log store flush logs to disk (fsync) at most once per interval while they're written,
+// and once they're complete. An interval of 0 flushes logs after every write. Without this option the operating
+// system decides when logs reach the disk.
+func WithSyncInterval(interval time.Duration) FileLogStoreOption {
+	return func(fs *FileLogStore) error {
+		if interval < 0 {
+			return xerrors.Errorf("invalid log sync interval %s: must not be negative", interval)
+		}
+		fs.syncInterval = &interval
+		return nil
+	}
 }
 
 // NewFileLogStore creates a new file backed log store
-func NewFileLogStore(base string) (*FileLogStore, error) {
+func NewFileLogStore(base string, opts ...FileLogStoreOption) (*FileLogStore, error) {
 	f := &FileLogStore{
 		Base:  base,
 		files: make(map[string]*file),
 	}
+	for _, o := range opts {
+		err := o(f)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return f, nil
 }
 
-// Open places a logfile in this store and opens it for writing.
+// logFilename returns the name of the logfile of id, depending on whether it's encrypted or not
+func logFilename(id string, encrypted bool) string {
+	if encrypted {
+		return fmt.Sprintf("%s.log.enc", id)
+	}
+	return fmt.Sprintf("%s.log", id)
+}
+
+// Open places a logfile in this store and opens it for writing. Logs which exist already are appended to in the
+// format they were written in, i.e. turning encryption on or off doesn't split a log into two files.
 func (fs *FileLogStore) Open(id string) (io.WriteCloser, error) {
 	fs.mu.Lock()
-	defer fs.mu.Unlock()
-
-	if f, exists := fs.files[id]; exists {
-		if f.Closed() {
-			err := f.openForWriting(fs.Base)
-			if err != nil {
-				return nil, err
-			}
+	f, exists := fs.files[id]
+	if !exists {
+		fn, encrypted, err := fs.findLog(id)
+		if err == ErrNotFound {
+			fn, encrypted = logFilename(id, fs.aead != nil), fs.aead != nil
+		} else if err != nil {
+			fs.mu.Unlock()
+			return nil, err
 		}
-
-		return f, nil
+		var aead cipher.AEAD
+		if encrypted {
+			aead = fs.aead
+		}
+		f = fs.newFile(fn, aead)
+		fs.files[id] = f
 	}
+	fs.mu.Unlock()
 
-	fn := fmt.Sprintf("%s.log", id)
-	f := &file{
-		closed: true,
-		fn:     fn,
-		fp:     nil,
-		cond:   sync.NewCond(&sync.Mutex{}),
-	}
 	err := f.openForWriting(fs.Base)
 	if err != nil {
 		return nil, err
 	}
-	fs.files[id] = f
 	return f, nil
 }
 
@@ -77,20 +146,79 @@ func (fs *FileLogStore) Write(id string) (io.Writer, error) {
 	return f, nil
 }
 
+func (fs *FileLogStore) newFile(fn string, aead cipher.AEAD) *file {
+	return &file{
+		fn:           fn,
+		aead:         aead,
+		syncInterval: fs.syncInterval,
+		cond:         sync.NewCond(&sync.Mutex{}),
+		closed:       true,
+	}
+}
+
+// openForWriting opens the file for appending, unless it's open already
 func (f *file) openForWriting(base string) error {
 	f.cond.L.Lock()
 	defer f.cond.L.Unlock()
 
-	fp, err := os.OpenFile(filepath.Join(base, f.fn), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if !f.closed {
+		return nil
+	}
+
+	fp, err := os.OpenFile(filepath.Join(base, f.fn), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
 	if err != nil {
 		return err
 	}
+	stat, err := fp.Stat()
+	if err != nil {
+		fp.Close()
+		return err
+	}
+	size := stat.Size()
+	if f.aead != nil && size > 0 {
+		size, err = truncateIncompleteRecord(fp, size)
+		if err != nil {
+			fp.Close()
+			return xerrors.Errorf("cannot repair log %s: %w", f.fn, err)
+		}
+	}
 	f.fp = fp
+	f.size = size
 	f.closed = false
+	f.syncErr = nil
 
 	return nil
 }
 
+// truncateIncompleteRecord cuts off the last record of an encrypted log if it wasn't written completely, e.g. because
+// werft crashed, so that the records appended after it can be read. It returns the size of the log.
+func truncateIncompleteRecord(fp *os.File, size int64) (int64, error) {
+	var (
+		pos int64
+		hdr = make([]byte, 4)
+	)
+	for pos+4 <= size {
+		_, err := fp.ReadAt(hdr, pos)
+		if err != nil {
+			return 0, err
+		}
+		end := pos + 4 + int64(binary.BigEndian.Uint32(hdr))
+		if end > size {
+			break
+		}
+		pos = end
+	}
+	if pos == size {
+		return size, nil
+	}
+
+	err := fp.Truncate(pos)
+	if err != nil {
+		return 0, err
+	}
+	return pos, nil
+}
+
 func (f *file) Write(b []byte) (n int, err error) {
 	f.cond.L.Lock()
 	defer f.cond.L.Unlock()
@@ -98,14 +226,80 @@ func (f *file) Write(b []byte) (n int, err error) {
 	if f.closed {
 		return 0, io.ErrClosedPipe
 	}
+	if f.syncErr != nil {
+		return 0, f.syncErr
+	}
 
-	n, err = f.fp.Write(b)
+	if f.aead != nil {
+		n, err = f.writeEncrypted(b)
+	} else {
+		n, err = f.fp.Write(b)
+		f.size += int64(n)
+	}
 	if n > 0 {
 		f.cond.Broadcast()
+		if serr := f.scheduleSync(); err == nil {
+			err = serr
+		}
 	}
 	return n, err
 }
 
+// writeEncrypted writes b as a single record of the form <length uint32><nonce><ciphertext>.
+// Callers must hold the cond lock.
+func (f *file) writeEncrypted(b []byte) (n int, err error) {
+	nonce := make([]byte, f.aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return 0, err
+	}
+
+	rec := make([]byte, 4, 4+len(nonce)+len(b)+f.aead.Overhead())
+	rec = append(rec, nonce...)
+	rec = f.aead.Seal(rec, nonce, b, nil)
+	binary.BigEndian.PutUint32(rec[:4], uint32(len(rec)-4))
+
+	// a record is only useful if written completely, hence we don't report partial writes
+	wn, err := f.fp.Write(rec)
+	f.size += int64(wn)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+// scheduleSync flushes the file to disk right away or once the sync interval is up, depending on the sync policy.
+// Callers must hold the cond lock.
+func (f *file) scheduleSync() error {
+	if f.syncInterval == nil {
+		return nil
+	}
+	if *f.syncInterval == 0 {
+		return f.fp.Sync()
+	}
+	if f.dirty {
+		// there's a sync scheduled already
+		return nil
+	}
+
+	f.dirty = true
+	fp := f.fp
+	time.AfterFunc(*f.syncInterval, func() {
+		f.cond.L.Lock()
+		defer f.cond.L.Unlock()
+
+		if !f.dirty || f.fp != fp {
+			// the file was closed (and maybe opened again) in the meantime, which synced it
+			return
+		}
+		f.dirty = false
+		// we report sync errors on the next write
+		f.syncErr = fp.Sync()
+	})
+	return nil
+}
+
 func (f *file) Close() error {
 	f.cond.L.Lock()
 	defer f.cond.L.Unlock()
@@ -115,13 +309,22 @@ func (f *file) Close() error {
 	}
 
 	f.closed = true
+	f.dirty = false
+	f.cond.Broadcast()
+
+	var serr error
+	if f.syncInterval != nil {
+		serr = f.fp.Sync()
+	}
 	err := f.fp.Close()
+	f.fp = nil
 	if err != nil {
 		return err
 	}
-	f.cond.Broadcast()
-
-	return nil
+	if serr != nil {
+		return serr
+	}
+	return f.syncErr
 }
 
 func (f *file) Closed() bool {
@@ -131,65 +334,192 @@ func (f *file) Closed() bool {
 	return f.closed
 }
 
-// Read retrieves a log file from this store.
+// Read retrieves a log file from this store. Any number of readers can follow a log while it's being written.
 func (fs *FileLogStore) Read(id string) (io.ReadCloser, error) {
 	fs.mu.Lock()
-	defer fs.mu.Unlock()
-
 	f, ok := fs.files[id]
+	fs.mu.Unlock()
+
 	if !ok {
-		fn := fmt.Sprintf("%s.log", id)
-		if _, err := os.Stat(filepath.Join(fs.Base, fn)); err != nil {
-			return nil, ErrNotFound
+		fn, encrypted, err := fs.findLog(id)
+		if err != nil {
+			return nil, err
+		}
+		var aead cipher.AEAD
+		if encrypted {
+			aead = fs.aead
 		}
 
-		f = &file{
-			closed: true,
-			fn:     fn,
-			fp:     nil,
-			cond:   sync.NewCond(&sync.Mutex{}),
+		fs.mu.Lock()
+		// the log might have been opened while we were looking for it
+		if f, ok = fs.files[id]; !ok {
+			f = fs.newFile(fn, aead)
+			fs.files[id] = f
 		}
-		fs.files[id] = f
+		fs.mu.Unlock()
 	}
 
 	fp, err := os.OpenFile(filepath.Join(fs.Base, f.fn), os.O_RDONLY, 0644)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return &fileReader{f: f, fp: fp}, nil
+	fr := &fileReader{f: f, fp: fp}
+	if f.aead != nil {
+		return &encryptedFileReader{f: f, src: fr}, nil
+	}
+	return fr, nil
+}
+
+// Delete removes a log file from this store, encrypted or not
+func (fs *FileLogStore) Delete(id string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if f, ok := fs.files[id]; ok {
+		if !f.Closed() {
+			return xerrors.Errorf("cannot delete log %s while it's written", id)
+		}
+		delete(fs.files, id)
+	}
+
+	var found bool
+	for _, fn := range []string{logFilename(id, true), logFilename(id, false)} {
+		err := os.Remove(filepath.Join(fs.Base, fn))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+	}
+	if !found {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// findLog finds the file of a log which isn't placed in this store yet
+func (fs *FileLogStore) findLog(id string) (fn string, encrypted bool, err error) {
+	if _, err := os.Stat(filepath.Join(fs.Base, logFilename(id, true))); err == nil {
+		if fs.aead == nil {
+			return "", false, xerrors.Errorf("log %s is encrypted but no encryption key is configured", id)
+		}
+		return logFilename(id, true), true, nil
+	}
+	if _, err := os.Stat(filepath.Join(fs.Base, logFilename(id, false))); err == nil {
+		return logFilename(id, false), false, nil
+	}
+	return "", false, ErrNotFound
 }
 
+// fileReader reads a log file while it's being written. It only reads what was written completely and waits for
+// more content until the log is closed.
 type fileReader struct {
-	f  *file
-	fp io.ReadCloser
+	f   *file
+	fp  io.ReadCloser
+	pos int64
 }
 
 func (fr *fileReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	f := fr.f
+	f.cond.L.Lock()
+	for !f.closed && fr.pos >= f.size {
+		// we've read everything written so far - wait for more
+		f.cond.Wait()
+	}
+	closed, size := f.closed, f.size
+	f.cond.L.Unlock()
+
+	if !closed && int64(len(p)) > size-fr.pos {
+		p = p[:size-fr.pos]
+	}
+	n, err = fr.fp.Read(p)
+	fr.pos += int64(n)
+	if err == io.EOF && !closed {
+		// the file is shorter than what was written - it must have been truncated
+		return n, io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (fr *fileReader) Close() error {
+	return fr.fp.Close()
+}
+
+// encryptedFileReader decrypts a log file record by record while it's being written
+type encryptedFileReader struct {
+	f   *file
+	src io.ReadCloser
+
+	raw       bytes.Buffer
+	plaintext bytes.Buffer
+}
+
+func (fr *encryptedFileReader) Read(p []byte) (n int, err error) {
 	for {
-		n, err = fr.fp.Read(p)
-		if err != io.EOF {
-			return
+		if fr.plaintext.Len() > 0 {
+			return fr.plaintext.Read(p)
 		}
 
-		// we're done reading the file for now
-		// check if we're actually done
-		if err == io.EOF && fr.f.Closed() {
-			return n, io.EOF
+		ok, err := fr.decryptRecord()
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			continue
 		}
 
-		// if we did read something, return that
+		// we don't have a complete record yet - read more of the file, waiting for it to be written if need be
+		buf := make([]byte, 4096)
+		n, err := fr.src.Read(buf)
+		fr.raw.Write(buf[:n])
 		if n > 0 {
-			return n, nil
+			continue
 		}
+		if err == io.EOF && fr.raw.Len() > 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+}
 
-		// we didn't read anything, so let's wait for more data to be written
-		fr.f.cond.L.Lock()
-		fr.f.cond.Wait()
-		fr.f.cond.L.Unlock()
+// decryptRecord decrypts the next record if the raw buffer contains it completely
+func (fr *encryptedFileReader) decryptRecord() (ok bool, err error) {
+	raw := fr.raw.Bytes()
+	if len(raw) < 4 {
+		return false, nil
 	}
+	l := int(binary.BigEndian.Uint32(raw[:4]))
+	if len(raw) < 4+l {
+		return false, nil
+	}
+
+	ns := fr.f.aead.NonceSize()
+	if l < ns {
+		return false, xerrors.Errorf("corrupt log record")
+	}
+	rec := raw[4 : 4+l]
+	plain, err := fr.f.aead.Open(nil, rec[:ns], rec[ns:], nil)
+	if err != nil {
+		return false, xerrors.Errorf("cannot decrypt log: %w", err)
+	}
+	fr.plaintext.Write(plain)
+	fr.raw.Next(4 + l)
+
+	return true, nil
 }
 
-func (fr *fileReader) Close() error {
-	return fr.fp.Close()
+func (fr *encryptedFileReader) Close() error {
+	return fr.src.Close()
 }
@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -94,3 +95,295 @@ func TestContinuousWriteReading(t *testing.T) {
 		t.Errorf("did not read message back, but: %s", string(actual))
 	}
 }
+
+func TestEncryptedLogs(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "tel")
+	if err != nil {
+		t.Fatalf("cannot create test folder: %v", err)
+	}
+	defer os.RemoveAll(base)
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	s, err := store.NewFileLogStore(base, store.WithEncryptionKey(key))
+	if err != nil {
+		t.Fatalf("cannot create test store: %v", err)
+	}
+
+	msg := "secret build output\nmore output\n"
+	w, err := s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot place log: %v", err)
+	}
+	for _, l := range strings.SplitAfter(msg, "\n") {
+		w.Write([]byte(l))
+	}
+	w.Close()
+
+	raw, err := ioutil.ReadFile(filepath.Join(base, "foo.log.enc"))
+	if err != nil {
+		t.Fatalf("cannot read encrypted log file: %v", err)
+	}
+	if bytes.Contains(raw, []byte("secret")) {
+		t.Errorf("log file contains plaintext")
+	}
+
+	// a fresh store must be able to read the log written by another one
+	s, err = store.NewFileLogStore(base, store.WithEncryptionKey(key))
+	if err != nil {
+		t.Fatalf("cannot create test store: %v", err)
+	}
+	r, err := s.Read("foo")
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+	defer r.Close()
+	act, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+	if string(act) != msg {
+		t.Errorf("did not read message back, but: %s", string(act))
+	}
+
+	_, err = store.NewFileLogStore(base, store.WithEncryptionKey([]byte("too short")))
+	if err == nil {
+		t.Errorf("expected error for invalid key")
+	}
+}
+
+func TestReopenPlaintextLogWithEncryptionKey(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "tel")
+	if err != nil {
+		t.Fatalf("cannot create test folder: %v", err)
+	}
+	defer os.RemoveAll(base)
+
+	s, err := store.NewFileLogStore(base)
+	if err != nil {
+		t.Fatalf("cannot create test store: %v", err)
+	}
+	w, err := s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot place log: %v", err)
+	}
+	w.Write([]byte("before restart\n"))
+	w.Close()
+
+	// werft restarts with encryption turned on while the job still runs
+	key := []byte("0123456789abcdef0123456789abcdef")
+	s, err = store.NewFileLogStore(base, store.WithEncryptionKey(key))
+	if err != nil {
+		t.Fatalf("cannot create test store: %v", err)
+	}
+	w, err = s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot reopen log: %v", err)
+	}
+	w.Write([]byte("after restart\n"))
+	w.Close()
+
+	if _, err := os.Stat(filepath.Join(base, "foo.log.enc")); !os.IsNotExist(err) {
+		t.Errorf("expected the log to remain in plaintext, but an encrypted log exists: %v", err)
+	}
+	r, err := s.Read("foo")
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+	defer r.Close()
+	act, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("cannot read log: %v", err)
+	}
+	if exp := "before restart\nafter restart\n"; string(act) != exp {
+		t.Errorf("expected %q, got %q", exp, string(act))
+	}
+}
+
+func TestEncryptedLogIncompleteRecord(t *testing.T) {
+	tests := []struct {
+		Name string
+		Cut  int
+	}{
+		{"complete", 0},
+		{"partial length", 2},
+		{"partial record", 10},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			base, err := ioutil.TempDir(os.TempDir(), "telir")
+			if err != nil {
+				t.Fatalf("cannot create test folder: %v", err)
+			}
+			defer os.RemoveAll(base)
+
+			key := []byte("0123456789abcdef0123456789abcdef")
+			s, err := store.NewFileLogStore(base, store.WithEncryptionKey(key))
+			if err != nil {
+				t.Fatalf("cannot create test store: %v", err)
+			}
+			w, err := s.Open("foo")
+			if err != nil {
+				t.Fatalf("cannot place log: %v", err)
+			}
+			w.Write([]byte("first\n"))
+			w.Close()
+
+			// pretend werft crashed while writing the second record
+			fn := filepath.Join(base, "foo.log.enc")
+			if test.Cut > 0 {
+				fp, err := os.OpenFile(fn, os.O_WRONLY|os.O_APPEND, 0644)
+				if err != nil {
+					t.Fatalf("cannot open log file: %v", err)
+				}
+				fp.Write([]byte{0, 0, 0, 100, 1, 2, 3, 4, 5, 6}[:test.Cut])
+				fp.Close()
+			}
+
+			// a fresh store continues the log after the last complete record
+			s, err = store.NewFileLogStore(base, store.WithEncryptionKey(key))
+			if err != nil {
+				t.Fatalf("cannot create test store: %v", err)
+			}
+			w, err = s.Open("foo")
+			if err != nil {
+				t.Fatalf("cannot reopen log: %v", err)
+			}
+			w.Write([]byte("second\n"))
+			w.Close()
+
+			r, err := s.Read("foo")
+			if err != nil {
+				t.Fatalf("cannot read log: %v", err)
+			}
+			defer r.Close()
+			act, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("cannot read log: %v", err)
+			}
+			if exp := "first\nsecond\n"; string(act) != exp {
+				t.Errorf("unexpected log content: got %q, want %q", act, exp)
+			}
+		})
+	}
+}
+
+func TestDeleteLog(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "tdl")
+	if err != nil {
+		t.Fatalf("cannot create test folder: %v", err)
+	}
+	defer os.RemoveAll(base)
+
+	s, err := store.NewFileLogStore(base)
+	if err != nil {
+		t.Fatalf("cannot create test store: %v", err)
+	}
+
+	w, err := s.Open("foo")
+	if err != nil {
+		t.Fatalf("cannot place log: %v", err)
+	}
+	w.Write([]byte("hello world\n"))
+	err = s.Delete("foo")
+	if err == nil {
+		t.Errorf("expected an error when deleting a log which is written")
+	}
+	w.Close()
+
+	err = s.Delete("foo")
+	if err != nil {
+		t.Fatalf("cannot delete log: %v", err)
+	}
+	_, err = s.Read("foo")
+	if err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound for deleted log, got %v", err)
+	}
+	err = s.Delete("foo")
+	if err != store.ErrNotFound {
+		t.Errorf("expected ErrNotFound when deleting an unknown log, got %v", err)
+	}
+}
+
+func TestFollowers(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	tests := []struct {
+		Name    string
+		Options []store.FileLogStoreOption
+	}{
+		{"plain", nil},
+		{"sync every write", []store.FileLogStoreOption{store.WithSyncInterval(0)}},
+		{"sync batched", []store.FileLogStoreOption{store.WithSyncInterval(5 * time.Millisecond)}},
+		{"encrypted", []store.FileLogStoreOption{store.WithEncryptionKey(key), store.WithSyncInterval(5 * time.Millisecond)}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			base, err := ioutil.TempDir(os.TempDir(), "tf")
+			if err != nil {
+				t.Fatalf("cannot create test folder: %v", err)
+			}
+			defer os.RemoveAll(base)
+
+			s, err := store.NewFileLogStore(base, test.Options...)
+			if err != nil {
+				t.Fatalf("cannot create test store: %v", err)
+			}
+			w, err := s.Open("foo")
+			if err != nil {
+				t.Fatalf("cannot place log: %v", err)
+			}
+
+			var expected bytes.Buffer
+			for i := 0; i < 200; i++ {
+				fmt.Fprintf(&expected, "line %d\n", i)
+			}
+
+			const followers = 5
+			var (
+				wg  sync.WaitGroup
+				act = make([][]byte, followers)
+			)
+			for i := 0; i < followers; i++ {
+				r, err := s.Read("foo")
+				if err != nil {
+					t.Fatalf("cannot read log: %v", err)
+				}
+				wg.Add(1)
+				go func(i int, r io.ReadCloser) {
+					defer wg.Done()
+					defer r.Close()
+
+					b, err := ioutil.ReadAll(r)
+					if err != nil {
+						t.Errorf("cannot read log: %v", err)
+					}
+					act[i] = b
+				}(i, r)
+			}
+
+			for _, l := range strings.SplitAfter(expected.String(), "\n") {
+				_, err := w.Write([]byte(l))
+				if err != nil {
+					t.Fatalf("cannot write log: %v", err)
+				}
+			}
+			err = w.Close()
+			if err != nil {
+				t.Fatalf("cannot close log: %v", err)
+			}
+			wg.Wait()
+
+			for i, a := range act {
+				if !bytes.Equal(a, expected.Bytes()) {
+					t.Errorf("follower %d read %d bytes, expected %d", i, len(a), expected.Len())
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidSyncInterval(t *testing.T) {
+	_, err := store.NewFileLogStore(os.TempDir(), store.WithSyncInterval(-time.Second))
+	if err == nil {
+		t.Errorf("expected error for negative sync interval")
+	}
+}
@@ -1,10 +1,10 @@
 package store
 
 import (
-	"bytes"
 	"context"
 	"io"
-	"io/ioutil"
+	"sort"
+	"strings"
 	"sync"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
@@ -26,38 +26,57 @@ type inMemoryLogStore struct {
 }
 
 type logSession struct {
-	Data   *bytes.Buffer
-	Reader map[chan []byte]struct{}
-	Mu     sync.RWMutex
+	Data   []byte
+	Closed bool
+	Cond   *sync.Cond
 }
 
-func (l *logSession) Write(p []byte) (n int, err error) {
-	l.Mu.Lock()
-	defer l.Mu.Unlock()
+func newLogSession(data []byte, closed bool) *logSession {
+	return &logSession{
+		Data:   data,
+		Closed: closed,
+		Cond:   sync.NewCond(&sync.Mutex{}),
+	}
+}
 
-	n, err = l.Data.Write(p)
-	if n > 0 {
+func (l *logSession) Write(p []byte) (n int, err error) {
+	l.Cond.L.Lock()
+	defer l.Cond.L.Unlock()
 
-		for r := range l.Reader {
-			r <- p[:n]
-		}
-	}
-	if err != nil {
-		return n, err
+	if l.Closed {
+		return 0, io.ErrClosedPipe
 	}
-	return
+
+	l.Data = append(l.Data, p...)
+	l.Cond.Broadcast()
+	return len(p), nil
 }
 
 func (l *logSession) Close() error {
+	l.Cond.L.Lock()
+	defer l.Cond.L.Unlock()
+
+	if l.Closed {
+		return io.ErrClosedPipe
+	}
+	l.Closed = true
+	l.Cond.Broadcast()
+
 	return nil
 }
 
+// bytes returns a copy of the log content
+func (l *logSession) bytes() []byte {
+	l.Cond.L.Lock()
+	defer l.Cond.L.Unlock()
+
+	return append([]byte(nil), l.Data...)
+}
+
 type logSessionReader struct {
-	Log       *logSession
-	Pos       int
-	R         chan []byte
-	remainder []byte
-	closed    bool
+	Log    *logSession
+	Pos    int
+	closed bool
 }
 
 func (lr *logSessionReader) Read(p []byte) (n int, err error) {
@@ -65,62 +84,58 @@ func (lr *logSessionReader) Read(p []byte) (n int, err error) {
 		return 0, io.ErrClosedPipe
 	}
 
-	if len(lr.remainder) > 0 {
-		n = copy(p, lr.remainder)
-		lr.remainder = lr.remainder[:n]
-		lr.Pos += n
-		return
-	}
-
-	lr.Log.Mu.RLock()
-	if lr.Pos >= lr.Log.Data.Len() {
-		lr.Log.Mu.RUnlock()
-		inc := <-lr.R
+	lr.Log.Cond.L.Lock()
+	defer lr.Log.Cond.L.Unlock()
 
-		n = copy(p, inc)
-		lr.remainder = inc[:n]
-		lr.Pos += n
-		return
+	// wait until there's something to read or the log is done
+	for lr.Pos >= len(lr.Log.Data) && !lr.Log.Closed {
+		lr.Log.Cond.Wait()
+	}
+	if lr.Pos >= len(lr.Log.Data) {
+		return 0, io.EOF
 	}
 
-	n = copy(p, lr.Log.Data.Bytes()[lr.Pos:])
+	n = copy(p, lr.Log.Data[lr.Pos:])
 	lr.Pos += n
-
-	lr.Log.Mu.RUnlock()
-	return 0, nil
+	return n, nil
 }
 
 func (lr *logSessionReader) Close() error {
-	lr.Log.Mu.Lock()
-	defer lr.Log.Mu.Unlock()
-
-	delete(lr.Log.Reader, lr.R)
 	lr.closed = true
-
 	return nil
 }
 
-// Place writes to this store
+// Open places a logfile in this store and opens it for writing.
 func (s *inMemoryLogStore) Open(id string) (io.WriteCloser, error) {
 	s.mu.Lock()
-	if _, ok := s.logs[id]; ok {
-		s.mu.Unlock()
-		return nil, ErrAlreadyExists
-	}
+	defer s.mu.Unlock()
 
-	lg := &logSession{
-		Data:   bytes.NewBuffer(nil),
-		Reader: make(map[chan []byte]struct{}),
+	if lg, ok := s.logs[id]; ok {
+		lg.Cond.L.Lock()
+		defer lg.Cond.L.Unlock()
+
+		if !lg.Closed {
+			return nil, ErrAlreadyExists
+		}
+		lg.Closed = false
+		return lg, nil
 	}
 
+	lg := newLogSession(nil, false)
 	s.logs[id] = lg
-	s.mu.Unlock()
-
 	return lg, nil
 }
 
+// Write provides write access to a previously placed log
 func (s *inMemoryLogStore) Write(id string) (io.Writer, error) {
-	return nil, xerrors.Errorf("not supported")
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	l, ok := s.logs[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return l, nil
 }
 
 // Read reads from this store
@@ -133,28 +148,51 @@ func (s *inMemoryLogStore) Read(id string) (io.ReadCloser, error) {
 		return nil, ErrNotFound
 	}
 
-	ch := make(chan []byte)
-	l.Mu.Lock()
-	l.Reader[ch] = struct{}{}
-	l.Mu.Unlock()
-	return ioutil.NopCloser(&logSessionReader{
-		Log: l,
-		R:   ch,
-	}), nil
+	return &logSessionReader{Log: l}, nil
+}
+
+// Delete removes a log from this store
+func (s *inMemoryLogStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.logs[id]
+	if !ok {
+		return ErrNotFound
+	}
+	l.Cond.L.Lock()
+	closed := l.Closed
+	l.Cond.L.Unlock()
+	if !closed {
+		return xerrors.Errorf("cannot delete log %s while it's written", id)
+	}
+
+	delete(s.logs, id)
+	return nil
 }
 
 // NewInMemoryJobStore creates a new in-memory job store
 func NewInMemoryJobStore() Jobs {
 	return &inMemoryJobStore{
-		jobs:  make(map[string]v1.JobStatus),
-		specs: make(map[string][]byte),
+		jobs:       make(map[string]v1.JobStatus),
+		specs:      make(map[string][]byte),
+		resolved:   make(map[string][]byte),
+		provenance: make(map[string][]byte),
+		triggers:   make(map[string][]byte),
+		sboms:      make(map[string]map[string][]byte),
+		events:     make(map[string][]v1.JobEvent),
 	}
 }
 
 type inMemoryJobStore struct {
-	jobs  map[string]v1.JobStatus
-	specs map[string][]byte
-	mu    sync.RWMutex
+	jobs       map[string]v1.JobStatus
+	specs      map[string][]byte
+	resolved   map[string][]byte
+	provenance map[string][]byte
+	triggers   map[string][]byte
+	sboms      map[string]map[string][]byte
+	events     map[string][]v1.JobEvent
+	mu         sync.RWMutex
 }
 
 // Store stores job information in the store.
@@ -187,6 +225,12 @@ func (s *inMemoryJobStore) Find(ctx context.Context, filter []*v1.FilterExpressi
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	for _, o := range order {
+		if _, ok := orderFields[o.Field]; !ok {
+			return nil, 0, xerrors.Errorf("unknown field %s", o.Field)
+		}
+	}
+
 	var res []v1.JobStatus
 	for _, js := range s.jobs {
 		if !filterexpr.MatchesFilter(&js, filter) {
@@ -194,7 +238,101 @@ func (s *inMemoryJobStore) Find(ctx context.Context, filter []*v1.FilterExpressi
 		}
 		res = append(res, js)
 	}
-	return res, len(res), nil
+	sort.SliceStable(res, func(i, j int) bool {
+		for _, o := range order {
+			c := orderFields[o.Field](&res[i], &res[j])
+			if c == 0 {
+				continue
+			}
+			if o.Ascending {
+				return c < 0
+			}
+			return c > 0
+		}
+		return false
+	})
+
+	total = len(res)
+	if start >= len(res) {
+		return nil, total, nil
+	}
+	res = res[start:]
+	if limit > 0 && limit < len(res) {
+		res = res[:limit]
+	}
+	return res, total, nil
+}
+
+// orderFields compares two jobs by a particular field
+var orderFields = map[string]func(a, b *v1.JobStatus) int{
+	"name":  func(a, b *v1.JobStatus) int { return strings.Compare(a.Name, b.Name) },
+	"phase": func(a, b *v1.JobStatus) int { return int(a.Phase) - int(b.Phase) },
+	"owner": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.GetMetadata().GetOwner(), b.GetMetadata().GetOwner())
+	},
+	"repo.owner": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.GetMetadata().GetRepository().GetOwner(), b.GetMetadata().GetRepository().GetOwner())
+	},
+	"repo.repo": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.GetMetadata().GetRepository().GetRepo(), b.GetMetadata().GetRepository().GetRepo())
+	},
+	"repo.host": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.GetMetadata().GetRepository().GetHost(), b.GetMetadata().GetRepository().GetHost())
+	},
+	"repo.ref": func(a, b *v1.JobStatus) int {
+		return strings.Compare(a.GetMetadata().GetRepository().GetRef(), b.GetMetadata().GetRepository().GetRef())
+	},
+	"trigger": func(a, b *v1.JobStatus) int {
+		return int(a.GetMetadata().GetTrigger()) - int(b.GetMetadata().GetTrigger())
+	},
+	"success": func(a, b *v1.JobStatus) int {
+		var sa, sb int
+		if a.GetConditions().GetSuccess() {
+			sa = 1
+		}
+		if b.GetConditions().GetSuccess() {
+			sb = 1
+		}
+		return sa - sb
+	},
+	"created": func(a, b *v1.JobStatus) int {
+		ca, cb := a.GetMetadata().GetCreated().GetSeconds(), b.GetMetadata().GetCreated().GetSeconds()
+		switch {
+		case ca < cb:
+			return -1
+		case ca > cb:
+			return 1
+		default:
+			return 0
+		}
+	},
+}
+
+// Delete removes a job from the store
+func (s *inMemoryJobStore) Delete(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[name]; !ok {
+		return ErrNotFound
+	}
+	delete(s.jobs, name)
+	return nil
+}
+
+// Purge removes a job and everything stored about it
+func (s *inMemoryJobStore) Purge(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.jobs, name)
+	delete(s.specs, name)
+	delete(s.resolved, name)
+	delete(s.provenance, name)
+	delete(s.triggers, name)
+	delete(s.sboms, name)
+	delete(s.events, name)
+	return nil
 }
 
 func (s *inMemoryJobStore) StoreJobSpec(name string, data []byte) error {
@@ -207,7 +345,7 @@ func (s *inMemoryJobStore) StoreJobSpec(name string, data []byte) error {
 
 func (s *inMemoryJobStore) GetJobSpec(name string) (data []byte, err error) {
 	s.mu.RLock()
-	s.mu.RUnlock()
+	defer s.mu.RUnlock()
 
 	data, ok := s.specs[name]
 	if !ok {
@@ -215,3 +353,270 @@ func (s *inMemoryJobStore) GetJobSpec(name string) (data []byte, err error) {
 	}
 	return data, nil
 }
+
+func (s *inMemoryJobStore) StoreResolvedSpec(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resolved[name] = data
+	return nil
+}
+
+func (s *inMemoryJobStore) GetResolvedSpec(name string) (data []byte, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.resolved[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (s *inMemoryJobStore) StoreProvenance(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.provenance[name] = data
+	return nil
+}
+
+func (s *inMemoryJobStore) GetProvenance(name string) (data []byte, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.provenance[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (s *inMemoryJobStore) StoreTriggerPayload(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.triggers[name] = data
+	return nil
+}
+
+func (s *inMemoryJobStore) GetTriggerPayload(name string) (data []byte, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.triggers[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func (s *inMemoryJobStore) StoreSBOM(name, image string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sboms[name] == nil {
+		s.sboms[name] = make(map[string][]byte)
+	}
+	s.sboms[name][image] = data
+	return nil
+}
+
+func (s *inMemoryJobStore) GetSBOMs(name string) (sboms map[string][]byte, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make(map[string][]byte, len(s.sboms[name]))
+	for image, data := range s.sboms[name] {
+		res[image] = data
+	}
+	return res, nil
+}
+
+func (s *inMemoryJobStore) AddEvent(ctx context.Context, name string, evt v1.JobEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.events[name] {
+		if e.Type == evt.Type && e.Phase == evt.Phase {
+			return nil
+		}
+	}
+	s.events[name] = append(s.events[name], evt)
+	return nil
+}
+
+func (s *inMemoryJobStore) GetEvents(ctx context.Context, name string) ([]v1.JobEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	res := make([]v1.JobEvent, len(s.events[name]))
+	copy(res, s.events[name])
+	sort.SliceStable(res, func(i, j int) bool {
+		ti, tj := res[i].GetTime(), res[j].GetTime()
+		if ti.GetSeconds() != tj.GetSeconds() {
+			return ti.GetSeconds() < tj.GetSeconds()
+		}
+		return ti.GetNanos() < tj.GetNanos()
+	})
+	return res, nil
+}
+
+// NewInMemoryNumberGroup creates a new in-memory number group
+func NewInMemoryNumberGroup() NumberGroup {
+	return &inMemoryNumberGroup{
+		groups: make(map[string]int),
+	}
+}
+
+type inMemoryNumberGroup struct {
+	groups map[string]int
+	mu     sync.Mutex
+}
+
+// Latest returns the latest number of a particular number group.
+func (g *inMemoryNumberGroup) Latest(group string) (nr int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	nr, ok := g.groups[group]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return nr, nil
+}
+
+// Next returns the next number in the group.
+func (g *inMemoryNumberGroup) Next(group string) (nr int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	nr, ok := g.groups[group]
+	if ok {
+		nr++
+	}
+	g.groups[group] = nr
+	return nr, nil
+}
+
+// NewInMemoryDeadLetters creates a new in-memory dead letter queue
+func NewInMemoryDeadLetters() DeadLetters {
+	return &inMemoryDeadLetters{
+		letters: make(map[string]v1.DeadLetter),
+	}
+}
+
+type inMemoryDeadLetters struct {
+	letters map[string]v1.DeadLetter
+	mu      sync.RWMutex
+}
+
+// Put places an event in the queue
+func (q *inMemoryDeadLetters) Put(ctx context.Context, dl v1.DeadLetter) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.letters[dl.Id] = dl
+	return nil
+}
+
+// Get retrieves an event from the queue
+func (q *inMemoryDeadLetters) Get(ctx context.Context, id string) (*v1.DeadLetter, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	dl, ok := q.letters[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &dl, nil
+}
+
+// List returns all events in the queue, oldest first
+func (q *inMemoryDeadLetters) List(ctx context.Context) ([]v1.DeadLetter, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	res := make([]v1.DeadLetter, 0, len(q.letters))
+	for _, dl := range q.letters {
+		res = append(res, dl)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].GetReceived().GetSeconds() < res[j].GetReceived().GetSeconds()
+	})
+	return res, nil
+}
+
+// Delete removes an event from the queue
+func (q *inMemoryDeadLetters) Delete(ctx context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if _, ok := q.letters[id]; !ok {
+		return ErrNotFound
+	}
+	delete(q.letters, id)
+	return nil
+}
+
+// NewInMemoryImages creates a new in-memory image build store
+func NewInMemoryImages() Images {
+	return &inMemoryImages{
+		builds: make(map[string][]v1.ImageBuild),
+	}
+}
+
+type inMemoryImages struct {
+	builds map[string][]v1.ImageBuild
+	mu     sync.RWMutex
+}
+
+// Put records that a job built an image
+func (i *inMemoryImages) Put(ctx context.Context, img v1.ImageBuild) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	builds := i.builds[img.Digest]
+	for idx, b := range builds {
+		if b.Image == img.Image && b.Job == img.Job {
+			builds[idx] = img
+			return nil
+		}
+	}
+	i.builds[img.Digest] = append(builds, img)
+	return nil
+}
+
+// Find returns all builds of the image with the digest, oldest first
+func (i *inMemoryImages) Find(ctx context.Context, digest string) ([]v1.ImageBuild, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	res := make([]v1.ImageBuild, len(i.builds[digest]))
+	copy(res, i.builds[digest])
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].GetBuilt().GetSeconds() < res[j].GetBuilt().GetSeconds()
+	})
+	return res, nil
+}
+
+// DeleteJob removes the records of all images a job built
+func (i *inMemoryImages) DeleteJob(ctx context.Context, job string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for digest, builds := range i.builds {
+		res := builds[:0]
+		for _, b := range builds {
+			if b.Job != job {
+				res = append(res, b)
+			}
+		}
+		if len(res) == 0 {
+			delete(i.builds, digest)
+			continue
+		}
+		i.builds[digest] = res
+	}
+	return nil
+}
@@ -0,0 +1,369 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// FileObjectStore keeps objects as files in a directory. Slashes in keys become subdirectories.
+type FileObjectStore struct {
+	Base string
+}
+
+// NewFileObjectStore creates a new file backed object store
+func NewFileObjectStore(base string) (*FileObjectStore, error) {
+	err := os.MkdirAll(base, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create object store location: %w", err)
+	}
+	return &FileObjectStore{Base: base}, nil
+}
+
+func (fs *FileObjectStore) filename(key string) (string, error) {
+	fn := filepath.Join(fs.Base, filepath.FromSlash(key))
+	if !strings.HasPrefix(fn, filepath.Clean(fs.Base)+string(filepath.Separator)) {
+		return "", xerrors.Errorf("invalid object key %s", key)
+	}
+	return fn, nil
+}
+
+// Put stores an object
+func (fs *FileObjectStore) Put(ctx context.Context, key string, content io.Reader) error {
+	fn, err := fs.filename(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(fn), 0755)
+	if err != nil {
+		return err
+	}
+
+	// we write to a temporary file first and rename it later so that readers never see half-written files
+	f, err := ioutil.TempFile(filepath.Dir(fn), "object")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = io.Copy(f, content)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), fn)
+}
+
+// Get retrieves an object
+func (fs *FileObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	fn, err := fs.filename(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(fn)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete removes an object
+func (fs *FileObjectStore) Delete(ctx context.Context, key string) error {
+	fn, err := fs.filename(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(fn)
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// EncryptObjects encrypts all objects put into an object store using AES-GCM, and decrypts them when they're read.
+// The key must be 16, 24 or 32 bytes long. Objects which were put into the store without encryption cannot be read.
+func EncryptObjects(objects ObjectStore, key []byte) (ObjectStore, error) {
+	blk, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid encryption key: %w", err)
+	}
+	aead, err := cipher.NewGCM(blk)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid encryption key: %w", err)
+	}
+	return &encryptedObjectStore{ObjectStore: objects, aead: aead}, nil
+}
+
+type encryptedObjectStore struct {
+	ObjectStore
+	aead cipher.AEAD
+}
+
+// Put encrypts and stores an object. The nonce precedes the ciphertext.
+func (s *encryptedObjectStore) Put(ctx context.Context, key string, content io.Reader) error {
+	plaintext, err := ioutil.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return err
+	}
+	return s.ObjectStore.Put(ctx, key, bytes.NewReader(s.aead.Seal(nonce, nonce, plaintext, []byte(key))))
+}
+
+// Get retrieves and decrypts an object
+func (s *encryptedObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := s.ObjectStore.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	ciphertext, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	ns := s.aead.NonceSize()
+	if len(ciphertext) < ns {
+		return nil, xerrors.Errorf("cannot decrypt %s: object is too short", key)
+	}
+	plaintext, err := s.aead.Open(nil, ciphertext[:ns], ciphertext[ns:], []byte(key))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decrypt %s: %w", key, err)
+	}
+	return ioutil.NopCloser(bytes.NewReader(plaintext)), nil
+}
+
+// DefaultObjectLogPutAttempts is the number of times an object log store tries to put a complete log into
+// the object store
+const DefaultObjectLogPutAttempts = 3
+
+// ObjectLogStore keeps logs in an object store. Logs which are written are spooled to a local log store, so that
+// readers can follow them, and are put into the object store once they're complete. Logs which cannot be put into
+// the object store stay in the spool and are read from there.
+type ObjectLogStore struct {
+	Objects ObjectStore
+	Prefix  string
+	Spool   *FileLogStore
+
+	// PutAttempts is the number of times we try to put a complete log into the object store.
+	// Defaults to DefaultObjectLogPutAttempts.
+	PutAttempts int
+	// RetryInterval is the time we wait before putting a log again, multiplied by the attempt. Defaults to a second.
+	RetryInterval time.Duration
+
+	mu sync.Mutex
+	// writing are the logs which are written
+	writing map[string]bool
+}
+
+// NewObjectLogStore creates a new log store which keeps the logs in objects, prefixing their keys with prefix.
+// Logs are spooled in spool while they're written.
+func NewObjectLogStore(objects ObjectStore, prefix string, spool *FileLogStore) (*ObjectLogStore, error) {
+	err := os.MkdirAll(spool.Base, 0755)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create log spool: %w", err)
+	}
+	return &ObjectLogStore{
+		Objects: objects,
+		Prefix:  prefix,
+		Spool:   spool,
+		writing: make(map[string]bool),
+	}, nil
+}
+
+func (s *ObjectLogStore) key(id string) string {
+	return fmt.Sprintf("%s%s.log", s.Prefix, id)
+}
+
+// Open places a log in this store and opens it for writing. Opening a log which is in the object store already
+// continues that log.
+func (s *ObjectLogStore) Open(id string) (io.WriteCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writing[id] {
+		return nil, ErrAlreadyExists
+	}
+
+	// a log which is in the spool already failed to make it into the object store - we continue it from there
+	spooled := true
+	r, err := s.Spool.Read(id)
+	if err == ErrNotFound {
+		spooled = false
+	} else if err != nil {
+		return nil, xerrors.Errorf("cannot open log %s: %w", id, err)
+	} else {
+		r.Close()
+	}
+
+	w, err := s.Spool.Open(id)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot open log %s: %w", id, err)
+	}
+	if !spooled {
+		err = s.continueFromObjects(id, w)
+		if err != nil {
+			w.Close()
+			s.Spool.Delete(id)
+			return nil, err
+		}
+	}
+
+	s.writing[id] = true
+	return &objectLogWriter{WriteCloser: w, store: s, id: id}, nil
+}
+
+// continueFromObjects copies a log which is in the object store already to the spool
+func (s *ObjectLogStore) continueFromObjects(id string, w io.Writer) error {
+	r, err := s.Objects.Get(context.Background(), s.key(id))
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot open log %s: %w", id, err)
+	}
+	defer r.Close()
+
+	_, err = io.Copy(w, r)
+	if err != nil {
+		return xerrors.Errorf("cannot continue log %s: %w", id, err)
+	}
+	return nil
+}
+
+// Write provides write access to a log which is written
+func (s *ObjectLogStore) Write(id string) (io.Writer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.writing[id] {
+		return nil, ErrNotFound
+	}
+	return s.Spool.Write(id)
+}
+
+// Read reads a log from the spool while it's written or if it could not be put into the object store, and from
+// the object store otherwise
+func (s *ObjectLogStore) Read(id string) (io.ReadCloser, error) {
+	r, err := s.Spool.Read(id)
+	if err != ErrNotFound {
+		return r, err
+	}
+
+	return s.Objects.Get(context.Background(), s.key(id))
+}
+
+// Delete removes a log from this store
+func (s *ObjectLogStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writing[id] {
+		return xerrors.Errorf("cannot delete log %s while it's written", id)
+	}
+
+	serr := s.Spool.Delete(id)
+	if serr != nil && serr != ErrNotFound {
+		return serr
+	}
+	err := s.Objects.Delete(context.Background(), s.key(id))
+	if err == ErrNotFound && serr == nil {
+		// the log never made it into the object store
+		return nil
+	}
+	return err
+}
+
+// put puts a complete log from the spool into the object store, and removes it from the spool once it's there
+func (s *ObjectLogStore) put(id string) error {
+	attempts := s.PutAttempts
+	if attempts <= 0 {
+		attempts = DefaultObjectLogPutAttempts
+	}
+	interval := s.RetryInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	var err error
+	for i := 0; i < attempts; i++ {
+		if i > 0 {
+			time.Sleep(time.Duration(i) * interval)
+		}
+
+		var r io.ReadCloser
+		r, err = s.Spool.Read(id)
+		if err != nil {
+			return err
+		}
+		err = s.Objects.Put(context.Background(), s.key(id), r)
+		r.Close()
+		if err == nil {
+			break
+		}
+		log.WithError(err).WithField("log", id).WithField("attempt", i+1).Warn("cannot put log into object store")
+	}
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writing[id] {
+		// the log was opened again while we put it - it's put again once it's complete
+		return nil
+	}
+	err = s.Spool.Delete(id)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// objectLogWriter puts a log into the object store once it's complete
+type objectLogWriter struct {
+	io.WriteCloser
+	store *ObjectLogStore
+	id    string
+}
+
+// Close completes the log and puts it into the object store. Should that fail, the log stays in the spool.
+func (w *objectLogWriter) Close() error {
+	err := w.WriteCloser.Close()
+	w.store.mu.Lock()
+	delete(w.store.writing, w.id)
+	w.store.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	err = w.store.put(w.id)
+	if err != nil {
+		return xerrors.Errorf("cannot store log %s, keeping it in the spool: %w", w.id, err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/store"
+)
+
+func TestObjectLogStore(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "tols")
+	if err != nil {
+		t.Fatalf("cannot create test folder: %v", err)
+	}
+	defer os.RemoveAll(base)
+
+	objects, err := store.NewFileObjectStore(base)
+	if err != nil {
+		t.Fatalf("cannot create object store: %v", err)
+	}
+	tests := []struct {
+		Name    string
+		Objects func() (store.ObjectStore, error)
+	}{
+		{"plain", func() (store.ObjectStore, error) { return objects, nil }},
+		{"encrypted", func() (store.ObjectStore, error) {
+			return store.EncryptObjects(objects, []byte("0123456789abcdef"))
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			objs, err := test.Objects()
+			if err != nil {
+				t.Fatalf("cannot create object store: %v", err)
+			}
+			prefix := test.Name + "/"
+			logs := newObjectLogStore(t, objs, prefix, filepath.Join(base, "spool-"+test.Name))
+
+			w, err := logs.Open("foo")
+			if err != nil {
+				t.Fatalf("cannot open log: %v", err)
+			}
+			_, err = logs.Open("foo")
+			if err != store.ErrAlreadyExists {
+				t.Errorf("expected ErrAlreadyExists when opening a log twice, got %v", err)
+			}
+			w.Write([]byte("hello "))
+
+			// the log is not in the object store until it's complete, but can be read already
+			_, err = objs.Get(context.Background(), prefix+"foo.log")
+			if err != store.ErrNotFound {
+				t.Errorf("expected log to be in the spool only while it's written, got %v", err)
+			}
+			r, err := logs.Read("foo")
+			if err != nil {
+				t.Fatalf("cannot read log: %v", err)
+			}
+
+			w.Write([]byte("world"))
+			err = w.Close()
+			if err != nil {
+				t.Fatalf("cannot close log: %v", err)
+			}
+			content, err := ioutil.ReadAll(r)
+			if err != nil {
+				t.Fatalf("cannot read log: %v", err)
+			}
+			if string(content) != "hello world" {
+				t.Errorf("unexpected log content while written: %q", content)
+			}
+
+			r, err = logs.Read("foo")
+			if err != nil {
+				t.Fatalf("cannot read complete log: %v", err)
+			}
+			content, _ = ioutil.ReadAll(r)
+			r.Close()
+			if string(content) != "hello world" {
+				t.Errorf("unexpected log content: %q", content)
+			}
+
+			if _, err := os.Stat(filepath.Join(base, "spool-"+test.Name, "foo.log")); !os.IsNotExist(err) {
+				t.Errorf("expected the complete log to be removed from the spool, got %v", err)
+			}
+
+			// a new log store, e.g. after a restart, continues the log from the object store
+			logs = newObjectLogStore(t, objs, prefix, filepath.Join(base, "spool-"+test.Name))
+			w, err = logs.Open("foo")
+			if err != nil {
+				t.Fatalf("cannot reopen log: %v", err)
+			}
+			w.Write([]byte("!"))
+			w.Close()
+			r, err = logs.Read("foo")
+			if err != nil {
+				t.Fatalf("cannot read continued log: %v", err)
+			}
+			content, _ = ioutil.ReadAll(r)
+			r.Close()
+			if string(content) != "hello world!" {
+				t.Errorf("unexpected continued log content: %q", content)
+			}
+
+			err = logs.Delete("foo")
+			if err != nil {
+				t.Fatalf("cannot delete log: %v", err)
+			}
+			_, err = logs.Read("foo")
+			if err != store.ErrNotFound {
+				t.Errorf("expected ErrNotFound for deleted log, got %v", err)
+			}
+		})
+	}
+
+	// encrypted objects must not contain the plaintext
+	enc, _ := store.EncryptObjects(objects, []byte("0123456789abcdef"))
+	err = enc.Put(context.Background(), "secret", strings.NewReader("top secret"))
+	if err != nil {
+		t.Fatalf("cannot put encrypted object: %v", err)
+	}
+	fc, err := ioutil.ReadFile(filepath.Join(base, "secret"))
+	if err != nil {
+		t.Fatalf("cannot read encrypted object: %v", err)
+	}
+	if bytes.Contains(fc, []byte("top secret")) {
+		t.Errorf("encrypted object contains plaintext")
+	}
+
+	_, err = objects.Get(context.Background(), "../escape")
+	if err == nil {
+		t.Errorf("expected keys outside of the object store to be rejected")
+	}
+}
+
+func newObjectLogStore(t *testing.T, objects store.ObjectStore, prefix, spoolDir string) *store.ObjectLogStore {
+	spool, err := store.NewFileLogStore(spoolDir)
+	if err != nil {
+		t.Fatalf("cannot create log spool: %v", err)
+	}
+	logs, err := store.NewObjectLogStore(objects, prefix, spool)
+	if err != nil {
+		t.Fatalf("cannot create log store: %v", err)
+	}
+	logs.RetryInterval = time.Millisecond
+	return logs
+}
+
+// failingObjects fails to put the first Failures objects
+type failingObjects struct {
+	store.ObjectStore
+	Failures int
+
+	mu   sync.Mutex
+	puts int
+}
+
+func (f *failingObjects) Put(ctx context.Context, key string, content io.Reader) error {
+	f.mu.Lock()
+	f.puts++
+	fail := f.puts <= f.Failures
+	f.mu.Unlock()
+	if fail {
+		return fmt.Errorf("object store is unavailable")
+	}
+	return f.ObjectStore.Put(ctx, key, content)
+}
+
+func TestObjectLogStorePutFailure(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Failures int
+		Error    bool
+	}{
+		{"no failure", 0, false},
+		{"retried", store.DefaultObjectLogPutAttempts - 1, false},
+		{"kept in spool", store.DefaultObjectLogPutAttempts, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			base, err := ioutil.TempDir(os.TempDir(), "tolspf")
+			if err != nil {
+				t.Fatalf("cannot create test folder: %v", err)
+			}
+			defer os.RemoveAll(base)
+			files, err := store.NewFileObjectStore(filepath.Join(base, "objects"))
+			if err != nil {
+				t.Fatalf("cannot create object store: %v", err)
+			}
+			objects := &failingObjects{ObjectStore: files, Failures: test.Failures}
+			logs := newObjectLogStore(t, objects, "", filepath.Join(base, "spool"))
+
+			w, err := logs.Open("foo")
+			if err != nil {
+				t.Fatalf("cannot open log: %v", err)
+			}
+			w.Write([]byte("hello world"))
+			err = w.Close()
+			if (err != nil) != test.Error {
+				t.Errorf("expected error %v when closing the log, got %v", test.Error, err)
+			}
+
+			_, err = files.Get(context.Background(), "foo.log")
+			if inObjects := err == nil; inObjects == test.Error {
+				t.Errorf("expected the log to be in the object store: %v, got %v", !test.Error, err)
+			}
+			r, err := logs.Read("foo")
+			if err != nil {
+				t.Fatalf("cannot read log: %v", err)
+			}
+			content, _ := ioutil.ReadAll(r)
+			r.Close()
+			if string(content) != "hello world" {
+				t.Errorf("unexpected log content: %q", content)
+			}
+			if !test.Error {
+				return
+			}
+
+			// a log which is kept in the spool is continued from there and put into the object store once it's complete
+			w, err = logs.Open("foo")
+			if err != nil {
+				t.Fatalf("cannot reopen log: %v", err)
+			}
+			w.Write([]byte("!"))
+			err = w.Close()
+			if err != nil {
+				t.Fatalf("cannot close reopened log: %v", err)
+			}
+			r, err = files.Get(context.Background(), "foo.log")
+			if err != nil {
+				t.Fatalf("expected the log to be in the object store: %v", err)
+			}
+			content, _ = ioutil.ReadAll(r)
+			r.Close()
+			if string(content) != "hello world!" {
+				t.Errorf("unexpected log content: %q", content)
+			}
+		})
+	}
+}
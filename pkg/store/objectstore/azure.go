@@ -0,0 +1,308 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// azureVersion is the Blob Storage API version we speak
+	azureVersion = "2019-12-12"
+	// azureResource is what Azure AD tokens for Blob Storage are issued for
+	azureResource     = "https://storage.azure.com/"
+	azureAuthority    = "https://login.microsoftonline.com"
+	azureIMDSEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+)
+
+// AzureConfig configures access to Azure Blob Storage
+type AzureConfig struct {
+	// Account is the storage account
+	Account string `yaml:"account"`
+
+	// Container is the container to keep the objects in
+	Container string `yaml:"container"`
+
+	// AccountKey, or the file it's in, defaults to the AZURE_STORAGE_KEY environment variable. Without an account key
+	// requests are authorized using Azure AD: by AKS workload identity if it's set up for the pod, by the managed
+	// identity of the node otherwise.
+	AccountKey     string `yaml:"accountKey,omitempty"`
+	AccountKeyFile string `yaml:"accountKeyFile,omitempty"`
+
+	// ClientID selects the Azure AD application or user-assigned managed identity. Defaults to the AZURE_CLIENT_ID
+	// environment variable which workload identity sets.
+	ClientID string `yaml:"clientID,omitempty"`
+
+	// Endpoint is the URL of the blob service, e.g. of an emulator. Defaults to https://<account>.blob.core.windows.net.
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
+// Azure keeps objects as block blobs in an Azure Blob Storage container. Requests are authorized using Shared Key
+// or Azure AD tokens.
+type Azure struct {
+	Account   string
+	Container string
+
+	endpoint string
+	key      []byte
+	tokens   oauth2.TokenSource
+	client   *http.Client
+}
+
+// NewAzure creates a new Azure Blob Storage backed object store
+func NewAzure(cfg AzureConfig, client *http.Client) (*Azure, error) {
+	if cfg.Account == "" || cfg.Container == "" {
+		return nil, xerrors.Errorf("azure object store needs an account and a container")
+	}
+	encodedKey, err := secret(cfg.AccountKey, cfg.AccountKeyFile, "AZURE_STORAGE_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", cfg.Account)
+	}
+	res := &Azure{
+		Account:   cfg.Account,
+		Container: cfg.Container,
+		endpoint:  endpoint,
+		client:    client,
+	}
+	if encodedKey != "" {
+		res.key, err = base64.StdEncoding.DecodeString(encodedKey)
+		if err != nil {
+			return nil, xerrors.Errorf("invalid azure account key: %w", err)
+		}
+		return res, nil
+	}
+
+	clientID := cfg.ClientID
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	var tokens oauth2.TokenSource
+	if tokenFile := os.Getenv("AZURE_FEDERATED_TOKEN_FILE"); tokenFile != "" {
+		tenantID := os.Getenv("AZURE_TENANT_ID")
+		if tenantID == "" || clientID == "" {
+			return nil, xerrors.Errorf("azure workload identity needs AZURE_TENANT_ID and a client ID")
+		}
+		authority := os.Getenv("AZURE_AUTHORITY_HOST")
+		if authority == "" {
+			authority = azureAuthority
+		}
+		tokens = &azureFederatedTokenSource{
+			Client:    client,
+			URL:       fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authority, "/"), tenantID),
+			ClientID:  clientID,
+			TokenFile: tokenFile,
+		}
+	} else {
+		tokens = &azureManagedIdentityTokenSource{
+			Client:   client,
+			URL:      azureIMDSEndpoint,
+			ClientID: clientID,
+		}
+	}
+	res.tokens = oauth2.ReuseTokenSource(nil, tokens)
+	return res, nil
+}
+
+// Put stores an object
+func (a *Azure) Put(ctx context.Context, key string, content io.Reader) error {
+	body, err := ioutil.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	resp, err := a.do(ctx, http.MethodPut, key, body, map[string]string{"x-ms-blob-type": "BlockBlob"})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// Get retrieves an object
+func (a *Azure) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := a.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object
+func (a *Azure) Delete(ctx context.Context, key string) error {
+	resp, err := a.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (a *Azure) do(ctx context.Context, method, key string, body []byte, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("%s/%s/%s", a.endpoint, a.Container, escapePath(key)), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if a.key != nil {
+		a.sign(req, len(body), time.Now())
+	} else {
+		tkn, err := a.tokens.Token()
+		if err != nil {
+			return nil, xerrors.Errorf("cannot get azure AD token: %w", err)
+		}
+		req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+		req.Header.Set("x-ms-version", azureVersion)
+		tkn.SetAuthHeader(req)
+	}
+	return a.client.Do(req.WithContext(ctx))
+}
+
+// sign authorizes a request using Shared Key, see
+// https://docs.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func (a *Azure) sign(req *http.Request, contentLength int, now time.Time) {
+	req.Header.Set("x-ms-date", now.UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", azureVersion)
+
+	var msHeaders []string
+	for k, v := range req.Header {
+		k = strings.ToLower(k)
+		if strings.HasPrefix(k, "x-ms-") {
+			msHeaders = append(msHeaders, fmt.Sprintf("%s:%s\n", k, strings.TrimSpace(strings.Join(v, ","))))
+		}
+	}
+	sort.Strings(msHeaders)
+
+	length := ""
+	if contentLength > 0 {
+		length = strconv.Itoa(contentLength)
+	}
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		length,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, we send x-ms-date instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		strings.Join(msHeaders, "") + fmt.Sprintf("/%s%s", a.Account, req.URL.EscapedPath()),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", a.Account, base64.StdEncoding.EncodeToString(mac.Sum(nil))))
+}
+
+// azureFederatedTokenSource exchanges the service account token AKS workload identity projects into the pod
+// for an Azure AD token
+type azureFederatedTokenSource struct {
+	Client    *http.Client
+	URL       string
+	ClientID  string
+	TokenFile string
+}
+
+func (s *azureFederatedTokenSource) Token() (*oauth2.Token, error) {
+	// the kubelet rotates the token, hence we read it every time
+	assertion, err := ioutil.ReadFile(s.TokenFile)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot read federated token: %w", err)
+	}
+	resp, err := s.Client.PostForm(s.URL, url.Values{
+		"client_id":             {s.ClientID},
+		"scope":                 {azureResource + ".default"},
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return readAzureToken(resp)
+}
+
+// azureManagedIdentityTokenSource gets Azure AD tokens of a managed identity from the instance metadata service
+type azureManagedIdentityTokenSource struct {
+	Client   *http.Client
+	URL      string
+	ClientID string
+}
+
+func (s *azureManagedIdentityTokenSource) Token() (*oauth2.Token, error) {
+	q := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {azureResource},
+	}
+	if s.ClientID != "" {
+		q.Set("client_id", s.ClientID)
+	}
+	req, err := http.NewRequest(http.MethodGet, s.URL+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return readAzureToken(resp)
+}
+
+// readAzureToken reads a token response of Azure AD or the instance metadata service. The latter sends expires_in as string.
+func readAzureToken(resp *http.Response) (*oauth2.Token, error) {
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, xerrors.Errorf("azure AD responded with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var body struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	err := json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decode azure AD token: %w", err)
+	}
+	res := &oauth2.Token{AccessToken: body.AccessToken, TokenType: "Bearer"}
+	if secs, err := body.ExpiresIn.Int64(); err == nil && secs > 0 {
+		res.Expiry = time.Now().Add(time.Duration(secs) * time.Second)
+	}
+	return res, nil
+}
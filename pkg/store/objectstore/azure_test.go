@@ -0,0 +1,154 @@
+package objectstore_test
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/store/objectstore"
+)
+
+// fakeBlobService keeps blobs in memory and records the authorization of requests
+type fakeBlobService struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	auth  []string
+}
+
+func (f *fakeBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/tenant/oauth2/v2.0/token" {
+		r.ParseForm()
+		if r.Form.Get("client_assertion") != "k8s-token" || r.Form.Get("client_id") != "client" {
+			http.Error(w, "invalid assertion", http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"access_token":"aad-token","expires_in":3600,"token_type":"Bearer"}`))
+		return
+	}
+
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+			http.Error(w, "missing blob type", http.StatusBadRequest)
+			return
+		}
+		f.blobs[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		b, ok := f.blobs[r.URL.Path]
+		if !ok {
+			http.Error(w, "BlobNotFound", http.StatusNotFound)
+			return
+		}
+		w.Write(b)
+	case http.MethodDelete:
+		if _, ok := f.blobs[r.URL.Path]; !ok {
+			http.Error(w, "BlobNotFound", http.StatusNotFound)
+			return
+		}
+		delete(f.blobs, r.URL.Path)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func TestAzure(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "tazure")
+	if err != nil {
+		t.Fatalf("cannot create test folder: %v", err)
+	}
+	defer os.RemoveAll(base)
+	tokenFile := filepath.Join(base, "token")
+	err = ioutil.WriteFile(tokenFile, []byte("k8s-token\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name       string
+		Config     objectstore.AzureConfig
+		Env        map[string]string
+		AuthPrefix string
+	}{
+		{
+			Name:       "shared key",
+			Config:     objectstore.AzureConfig{AccountKey: base64.StdEncoding.EncodeToString([]byte("secret"))},
+			AuthPrefix: "SharedKey werft:",
+		},
+		{
+			Name: "workload identity",
+			Env: map[string]string{
+				"AZURE_FEDERATED_TOKEN_FILE": tokenFile,
+				"AZURE_TENANT_ID":            "tenant",
+				"AZURE_CLIENT_ID":            "client",
+			},
+			AuthPrefix: "Bearer aad-token",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			svc := &fakeBlobService{blobs: make(map[string][]byte)}
+			srv := httptest.NewServer(svc)
+			defer srv.Close()
+
+			os.Setenv("AZURE_AUTHORITY_HOST", srv.URL)
+			defer os.Unsetenv("AZURE_AUTHORITY_HOST")
+			for k, v := range test.Env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			cfg := test.Config
+			cfg.Account = "werft"
+			cfg.Container = "logs"
+			cfg.Endpoint = srv.URL
+			objs, err := objectstore.New(objectstore.Config{Type: objectstore.TypeAzure, Prefix: "werft/", Azure: &cfg}, srv.Client())
+			if err != nil {
+				t.Fatalf("cannot create object store: %v", err)
+			}
+
+			ctx := context.Background()
+			err = objs.Put(ctx, "foo/bar.log", strings.NewReader("hello world"))
+			if err != nil {
+				t.Fatalf("cannot put object: %v", err)
+			}
+			if _, ok := svc.blobs["/logs/werft/foo/bar.log"]; !ok {
+				t.Errorf("object was not stored as expected blob: %v", svc.blobs)
+			}
+			r, err := objs.Get(ctx, "foo/bar.log")
+			if err != nil {
+				t.Fatalf("cannot get object: %v", err)
+			}
+			content, _ := ioutil.ReadAll(r)
+			r.Close()
+			if string(content) != "hello world" {
+				t.Errorf("unexpected object content: %q", content)
+			}
+			err = objs.Delete(ctx, "foo/bar.log")
+			if err != nil {
+				t.Fatalf("cannot delete object: %v", err)
+			}
+			_, err = objs.Get(ctx, "foo/bar.log")
+			if err != store.ErrNotFound {
+				t.Errorf("expected ErrNotFound for deleted object, got %v", err)
+			}
+
+			for _, auth := range svc.auth {
+				if !strings.HasPrefix(auth, test.AuthPrefix) {
+					t.Errorf("unexpected authorization %q, expected %q", auth, test.AuthPrefix)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	"golang.org/x/xerrors"
+)
+
+const (
+	gcsScope    = "https://www.googleapis.com/auth/devstorage.read_write"
+	gcsEndpoint = "https://storage.googleapis.com"
+)
+
+// GCSConfig configures access to Google Cloud Storage
+type GCSConfig struct {
+	// CredentialsFile is a service account key file. Defaults to the application default credentials,
+	// e.g. of the GKE workload identity.
+	CredentialsFile string `yaml:"credentialsFile,omitempty"`
+
+	// Endpoint is the URL of the storage API, e.g. of an emulator. Defaults to Google Cloud Storage.
+	Endpoint string `yaml:"endpoint,omitempty"`
+}
+
+// GCS keeps objects in a Google Cloud Storage bucket using the JSON API
+type GCS struct {
+	Bucket string
+
+	endpoint string
+	client   *http.Client
+}
+
+// NewGCS creates a new Google Cloud Storage backed object store. Requests are authorized using OAuth2 tokens
+// of the configured credentials and sent using client.
+func NewGCS(bucket string, cfg GCSConfig, client *http.Client) (*GCS, error) {
+	if bucket == "" {
+		return nil, xerrors.Errorf("gcs object store needs a bucket")
+	}
+
+	// the OAuth2 library takes the client to fetch tokens with from the context
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+	var (
+		creds *google.Credentials
+		err   error
+	)
+	if cfg.CredentialsFile != "" {
+		var fc []byte
+		fc, err = ioutil.ReadFile(cfg.CredentialsFile)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot read GCS credentials: %w", err)
+		}
+		creds, err = google.CredentialsFromJSON(ctx, fc, gcsScope)
+	} else {
+		creds, err = google.FindDefaultCredentials(ctx, gcsScope)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get GCS credentials: %w", err)
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = gcsEndpoint
+	}
+	return &GCS{
+		Bucket:   bucket,
+		endpoint: endpoint,
+		client:   oauth2.NewClient(ctx, creds.TokenSource),
+	}, nil
+}
+
+func (g *GCS) objectURL(key string) string {
+	return fmt.Sprintf("%s/storage/v1/b/%s/o/%s", g.endpoint, url.PathEscape(g.Bucket), url.PathEscape(key))
+}
+
+// Put stores an object
+func (g *GCS) Put(ctx context.Context, key string, content io.Reader) error {
+	u := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s", g.endpoint, url.PathEscape(g.Bucket), url.QueryEscape(key))
+	body, err := ioutil.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := g.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// Get retrieves an object
+func (g *GCS) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, g.objectURL(key)+"?alt=media", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object
+func (g *GCS) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequest(http.MethodDelete, g.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := g.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/store"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// TypeFilesystem keeps objects in a directory, e.g. on a persistent volume
+	TypeFilesystem = "filesystem"
+	// TypeS3 keeps objects in an S3 bucket, or the bucket of any S3 compatible service, e.g. MinIO
+	TypeS3 = "s3"
+	// TypeGCS keeps objects in a Google Cloud Storage bucket
+	TypeGCS = "gcs"
+	// TypeAzure keeps objects in an Azure Blob Storage container
+	TypeAzure = "azure"
+)
+
+// Config configures an object store
+type Config struct {
+	// Type is one of filesystem, s3, gcs or azure
+	Type string `yaml:"type"`
+
+	// Path is the directory of a filesystem object store
+	Path string `yaml:"path,omitempty"`
+
+	// Bucket is the S3 or GCS bucket to keep the objects in
+	Bucket string `yaml:"bucket,omitempty"`
+
+	// Prefix is prepended to the keys of all objects, e.g. werft/ to share a bucket with others
+	Prefix string `yaml:"prefix,omitempty"`
+
+	S3    *S3Config    `yaml:"s3,omitempty"`
+	GCS   *GCSConfig   `yaml:"gcs,omitempty"`
+	Azure *AzureConfig `yaml:"azure,omitempty"`
+}
+
+// New produces the object store a config describes. HTTP based object stores send their requests using client.
+func New(cfg Config, client *http.Client) (store.ObjectStore, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var (
+		res store.ObjectStore
+		err error
+	)
+	switch cfg.Type {
+	case TypeFilesystem:
+		if cfg.Path == "" {
+			return nil, xerrors.Errorf("filesystem object store needs a path")
+		}
+		res, err = store.NewFileObjectStore(cfg.Path)
+	case TypeS3:
+		s3 := cfg.S3
+		if s3 == nil {
+			s3 = &S3Config{}
+		}
+		res, err = NewS3(cfg.Bucket, *s3, client)
+	case TypeGCS:
+		gcs := cfg.GCS
+		if gcs == nil {
+			gcs = &GCSConfig{}
+		}
+		res, err = NewGCS(cfg.Bucket, *gcs, client)
+	case TypeAzure:
+		if cfg.Azure == nil {
+			return nil, xerrors.Errorf("azure object store needs azure config")
+		}
+		res, err = NewAzure(*cfg.Azure, client)
+	default:
+		return nil, xerrors.Errorf("unknown object store type %q: must be one of %s", cfg.Type, strings.Join([]string{TypeFilesystem, TypeS3, TypeGCS, TypeAzure}, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Prefix != "" {
+		res = &prefixed{ObjectStore: res, Prefix: cfg.Prefix}
+	}
+	return res, nil
+}
+
+// secret returns value, or the content of file if value is empty, or the environment variable env if both are empty
+func secret(value, file, env string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if file != "" {
+		fc, err := ioutil.ReadFile(file)
+		if err != nil {
+			return "", xerrors.Errorf("cannot read %s: %w", file, err)
+		}
+		return strings.TrimSpace(string(fc)), nil
+	}
+	return os.Getenv(env), nil
+}
+
+// responseError turns an unexpected response into an error. Not found responses become store.ErrNotFound.
+func responseError(resp *http.Response) error {
+	if resp.StatusCode == http.StatusNotFound {
+		return store.ErrNotFound
+	}
+	msg, _ := ioutil.ReadAll(resp.Body)
+	if len(msg) > 512 {
+		msg = msg[:512]
+	}
+	return xerrors.Errorf("object store responded with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// prefixed prepends a prefix to the keys of all objects
+type prefixed struct {
+	store.ObjectStore
+	Prefix string
+}
+
+func (p *prefixed) Put(ctx context.Context, key string, content io.Reader) error {
+	return p.ObjectStore.Put(ctx, p.Prefix+key, content)
+}
+
+func (p *prefixed) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return p.ObjectStore.Get(ctx, p.Prefix+key)
+}
+
+func (p *prefixed) Delete(ctx context.Context, key string) error {
+	return p.ObjectStore.Delete(ctx, p.Prefix+key)
+}
@@ -0,0 +1,239 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// S3Config configures access to S3 or an S3 compatible service
+type S3Config struct {
+	// Endpoint is the URL of an S3 compatible service, e.g. https://minio.example.com. Buckets are addressed
+	// in the path then. Defaults to AWS.
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// Region is the region of the bucket. Defaults to us-east-1.
+	Region string `yaml:"region,omitempty"`
+
+	// AccessKeyID defaults to the AWS_ACCESS_KEY_ID environment variable
+	AccessKeyID string `yaml:"accessKeyID,omitempty"`
+
+	// SecretAccessKey, or the file it's in, defaults to the AWS_SECRET_ACCESS_KEY environment variable
+	SecretAccessKey     string `yaml:"secretAccessKey,omitempty"`
+	SecretAccessKeyFile string `yaml:"secretAccessKeyFile,omitempty"`
+}
+
+// S3 keeps objects in an S3 bucket. Requests are signed using AWS signature version 4.
+type S3 struct {
+	Bucket string
+
+	endpoint    string
+	pathStyle   bool
+	region      string
+	accessKeyID string
+	secretKey   string
+	token       string
+	client      *http.Client
+}
+
+// NewS3 creates a new S3 backed object store
+func NewS3(bucket string, cfg S3Config, client *http.Client) (*S3, error) {
+	if bucket == "" {
+		return nil, xerrors.Errorf("s3 object store needs a bucket")
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	accessKeyID, _ := secret(cfg.AccessKeyID, "", "AWS_ACCESS_KEY_ID")
+	secretKey, err := secret(cfg.SecretAccessKey, cfg.SecretAccessKeyFile, "AWS_SECRET_ACCESS_KEY")
+	if err != nil {
+		return nil, err
+	}
+	if accessKeyID == "" || secretKey == "" {
+		return nil, xerrors.Errorf("s3 object store needs an access key ID and a secret access key")
+	}
+
+	res := &S3{
+		Bucket:      bucket,
+		region:      region,
+		accessKeyID: accessKeyID,
+		secretKey:   secretKey,
+		client:      client,
+	}
+	if cfg.AccessKeyID == "" {
+		// session tokens belong to the key from the environment
+		res.token, _ = secret("", "", "AWS_SESSION_TOKEN")
+	}
+	if cfg.Endpoint == "" {
+		res.endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", bucket, region)
+	} else {
+		res.endpoint = strings.TrimSuffix(cfg.Endpoint, "/")
+		res.pathStyle = true
+	}
+	return res, nil
+}
+
+func (s *S3) url(key string) string {
+	if s.pathStyle {
+		return fmt.Sprintf("%s/%s/%s", s.endpoint, s.Bucket, escapePath(key))
+	}
+	return fmt.Sprintf("%s/%s", s.endpoint, escapePath(key))
+}
+
+// Put stores an object
+func (s *S3) Put(ctx context.Context, key string, content io.Reader) error {
+	body, err := ioutil.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+// Get retrieves an object
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp.Body, nil
+}
+
+// Delete removes an object. S3 doesn't tell if the object existed, hence we ask first.
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+
+	resp, err = s.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func (s *S3) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.url(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if s.token != "" {
+		req.Header.Set("X-Amz-Security-Token", s.token)
+	}
+	signV4(req, body, s.accessKeyID, s.secretKey, s.region, time.Now())
+	return s.client.Do(req)
+}
+
+// signV4 signs a request to S3 using AWS signature version 4. All headers of the request are signed.
+func signV4(req *http.Request, body []byte, accessKeyID, secretKey, region string, now time.Time) {
+	var (
+		amzDate     = now.UTC().Format("20060102T150405Z")
+		date        = amzDate[:8]
+		payloadHash = sha256Hex(body)
+		scope       = fmt.Sprintf("%s/%s/s3/aws4_request", date, region)
+	)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", k, headers[k])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapePath percent-encodes an object key for use in a URL path, keeping slashes
+func escapePath(key string) string {
+	var res strings.Builder
+	for _, b := range []byte(key) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			res.WriteByte(b)
+		default:
+			fmt.Fprintf(&res, "%%%02X", b)
+		}
+	}
+	return res.String()
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// DeadLetters provides a postgres backed dead letter queue
+type DeadLetters struct {
+	DB *sql.DB
+
+	// QueryTimeout is the time a query may take. Defaults to DefaultQueryTimeout.
+	QueryTimeout time.Duration
+}
+
+// NewDeadLetters creates a new SQL dead letter queue
+func NewDeadLetters(db *sql.DB) (*DeadLetters, error) {
+	return &DeadLetters{DB: db}, nil
+}
+
+// Put places an event in the queue
+func (q *DeadLetters) Put(ctx context.Context, dl v1.DeadLetter) error {
+	ctx, cancel := withTimeout(ctx, q.QueryTimeout)
+	defer cancel()
+
+	_, err := q.DB.ExecContext(ctx, `
+		INSERT
+		INTO   dead_letters (id, event_type, payload, error, received, attempts, failed_jobs)
+		VALUES              ($1, $2        , $3     , $4   , $5      , $6      , $7         )
+		ON CONFLICT (id) DO UPDATE
+			SET event_type = $2, payload = $3, error = $4, received = $5, attempts = $6, failed_jobs = $7
+		`,
+		dl.Id,
+		dl.EventType,
+		dl.Payload,
+		dl.Error,
+		dl.GetReceived().GetSeconds(),
+		dl.Attempts,
+		strings.Join(dl.FailedJobs, "\n"),
+	)
+	return err
+}
+
+// Get retrieves an event from the queue
+func (q *DeadLetters) Get(ctx context.Context, id string) (*v1.DeadLetter, error) {
+	ctx, cancel := withTimeout(ctx, q.QueryTimeout)
+	defer cancel()
+
+	row := q.DB.QueryRowContext(ctx, "SELECT id, event_type, payload, error, received, attempts, failed_jobs FROM dead_letters WHERE id = $1", id)
+	dl, err := scanDeadLetter(row)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dl, nil
+}
+
+// List returns all events in the queue, oldest first
+func (q *DeadLetters) List(ctx context.Context) ([]v1.DeadLetter, error) {
+	ctx, cancel := withTimeout(ctx, q.QueryTimeout)
+	defer cancel()
+
+	rows, err := q.DB.QueryContext(ctx, "SELECT id, event_type, payload, error, received, attempts, failed_jobs FROM dead_letters ORDER BY received ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []v1.DeadLetter
+	for rows.Next() {
+		dl, err := scanDeadLetter(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *dl)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Delete removes an event from the queue
+func (q *DeadLetters) Delete(ctx context.Context, id string) error {
+	ctx, cancel := withTimeout(ctx, q.QueryTimeout)
+	defer cancel()
+
+	res, err := q.DB.ExecContext(ctx, "DELETE FROM dead_letters WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return store.ErrNotFound
+	}
+	return nil
+}
+
+func scanDeadLetter(row interface{ Scan(...interface{}) error }) (*v1.DeadLetter, error) {
+	var (
+		dl         v1.DeadLetter
+		received   int64
+		failedJobs string
+	)
+	err := row.Scan(&dl.Id, &dl.EventType, &dl.Payload, &dl.Error, &received, &dl.Attempts, &failedJobs)
+	if err != nil {
+		return nil, err
+	}
+	dl.Received = &timestamp.Timestamp{Seconds: received}
+	if failedJobs != "" {
+		dl.FailedJobs = strings.Split(failedJobs, "\n")
+	}
+	return &dl, nil
+}
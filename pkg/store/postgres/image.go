@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// Images provides a postgres backed record of image builds
+type Images struct {
+	DB *sql.DB
+
+	// QueryTimeout is the time a query may take. Defaults to DefaultQueryTimeout.
+	QueryTimeout time.Duration
+}
+
+// NewImages creates a new SQL image build store
+func NewImages(db *sql.DB) (*Images, error) {
+	return &Images{DB: db}, nil
+}
+
+// Put records that a job built an image
+func (i *Images) Put(ctx context.Context, img v1.ImageBuild) error {
+	ctx, cancel := withTimeout(ctx, i.QueryTimeout)
+	defer cancel()
+
+	repo := img.GetRepository()
+	_, err := i.DB.ExecContext(ctx, `
+		INSERT
+		INTO   image_builds (digest, image, job, repo_host, repo_owner, repo_repo, repo_ref, repo_revision, built)
+		VALUES              ($1    , $2   , $3 , $4       , $5        , $6       , $7      , $8           , $9   )
+		ON CONFLICT (digest, image, job) DO UPDATE
+			SET repo_host = $4, repo_owner = $5, repo_repo = $6, repo_ref = $7, repo_revision = $8, built = $9
+		`,
+		img.Digest,
+		img.Image,
+		img.Job,
+		repo.GetHost(),
+		repo.GetOwner(),
+		repo.GetRepo(),
+		repo.GetRef(),
+		repo.GetRevision(),
+		img.GetBuilt().GetSeconds(),
+	)
+	return err
+}
+
+// Find returns all builds of the image with the digest, oldest first
+func (i *Images) Find(ctx context.Context, digest string) ([]v1.ImageBuild, error) {
+	ctx, cancel := withTimeout(ctx, i.QueryTimeout)
+	defer cancel()
+
+	rows, err := i.DB.QueryContext(ctx, `
+		SELECT digest, image, job, repo_host, repo_owner, repo_repo, repo_ref, repo_revision, built
+		FROM   image_builds
+		WHERE  digest = $1
+		ORDER BY built ASC`,
+		digest,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []v1.ImageBuild
+	for rows.Next() {
+		var (
+			img   v1.ImageBuild
+			repo  v1.Repository
+			built int64
+		)
+		err := rows.Scan(&img.Digest, &img.Image, &img.Job, &repo.Host, &repo.Owner, &repo.Repo, &repo.Ref, &repo.Revision, &built)
+		if err != nil {
+			return nil, err
+		}
+		img.Repository = &repo
+		img.Built = &timestamp.Timestamp{Seconds: built}
+		res = append(res, img)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DeleteJob removes the records of all images a job built
+func (i *Images) DeleteJob(ctx context.Context, job string) error {
+	ctx, cancel := withTimeout(ctx, i.QueryTimeout)
+	defer cancel()
+
+	_, err := i.DB.ExecContext(ctx, "DELETE FROM image_builds WHERE job = $1", job)
+	return err
+}
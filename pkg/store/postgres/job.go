@@ -5,17 +5,30 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
 	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/tracing"
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 )
 
 // JobStore stores jobs in a Postgres database
 type JobStore struct {
 	DB *sql.DB
+
+	// ReadDB answers Find queries whose context allows stale reads (see store.WithStaleReads), e.g. a read replica.
+	// If this is nil, all queries go to DB.
+	ReadDB *sql.DB
+
+	// QueryTimeout is the time a query may take. Defaults to DefaultQueryTimeout.
+	QueryTimeout time.Duration
 }
 
 // NewJobStore creates a new SQL job store
@@ -24,7 +37,49 @@ func NewJobStore(db *sql.DB) (*JobStore, error) {
 }
 
 // Store stores job information in the store.
-func (s *JobStore) Store(ctx context.Context, job v1.JobStatus) error {
+func (s *JobStore) Store(ctx context.Context, job v1.JobStatus) (err error) {
+	ctx, span := tracing.Start(ctx, "JobStore.Store", trace.WithAttributes(attribute.String("job", job.Name)))
+	defer tracing.FinishSpan(span, &err)
+	ctx, cancel := withTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	err = storeJob(ctx, tx, job)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// StoreBatch stores several jobs in a single transaction
+func (s *JobStore) StoreBatch(ctx context.Context, jobs []v1.JobStatus) (err error) {
+	ctx, span := tracing.Start(ctx, "JobStore.StoreBatch", trace.WithAttributes(attribute.Int("jobs", len(jobs))))
+	defer tracing.FinishSpan(span, &err)
+	ctx, cancel := withTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		err = storeJob(ctx, tx, job)
+		if err != nil {
+			tx.Rollback()
+			return xerrors.Errorf("cannot store job %s: %w", job.Name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// storeJob writes a job, its annotations and labels as part of tx
+func storeJob(ctx context.Context, tx *sql.Tx, job v1.JobStatus) error {
 	marshaler := &jsonpb.Marshaler{
 		EnumsAsInts: true,
 	}
@@ -38,12 +93,8 @@ func (s *JobStore) Store(ctx context.Context, job v1.JobStatus) error {
 		success = 1
 	}
 
-	tx, err := s.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return err
-	}
 	var jobID int
-	err = tx.QueryRow(`
+	err = tx.QueryRowContext(ctx, `
 		INSERT
 		INTO   job_status (name, data, owner, phase, repo_owner, repo_repo, repo_host, repo_ref, trigger_src, success, created)
 		VALUES            ($1  , $2  , $3   , $4   , $5        , $6       , $7       , $8      , $9         , $10,     $11    ) 
@@ -58,16 +109,15 @@ func (s *JobStore) Store(ctx context.Context, job v1.JobStatus) error {
 		job.Metadata.Repository.Repo,
 		job.Metadata.Repository.Host,
 		job.Metadata.Repository.Ref,
-		strings.ToLower(strings.TrimPrefix("TRIGGER_", job.Metadata.Trigger.String())),
+		strings.ToLower(strings.TrimPrefix(job.Metadata.Trigger.String(), "TRIGGER_")),
 		success,
 		job.Metadata.Created.Seconds,
 	).Scan(&jobID)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 	for _, annotation := range job.Metadata.Annotations {
-		_, err := tx.Exec(`
+		_, err := tx.ExecContext(ctx, `
 		INSERT
 		INTO   annotations (job_id, name, value)
 		VALUES             ($1    , $2  , $3   )
@@ -75,23 +125,36 @@ func (s *JobStore) Store(ctx context.Context, job v1.JobStatus) error {
 			SET value = $3
 		`, jobID, annotation.Key, annotation.Value)
 		if err != nil {
-			tx.Rollback()
 			return err
 		}
 	}
-
-	err = tx.Commit()
+	_, err = tx.ExecContext(ctx, "DELETE FROM job_labels WHERE job_id = $1", jobID)
 	if err != nil {
 		return err
 	}
+	for key, value := range job.Metadata.Labels {
+		_, err := tx.ExecContext(ctx, `
+		INSERT
+		INTO   job_labels (job_id, name, value)
+		VALUES            ($1    , $2  , $3   )
+		`, jobID, key, value)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
 // Get retrieves a particular job bassd on its name.
-func (s *JobStore) Get(ctx context.Context, name string) (*v1.JobStatus, error) {
+func (s *JobStore) Get(ctx context.Context, name string) (job *v1.JobStatus, err error) {
+	ctx, span := tracing.Start(ctx, "JobStore.Get", trace.WithAttributes(attribute.String("job", name)))
+	defer tracing.FinishSpan(span, &err)
+	ctx, cancel := withTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
 	var data string
-	err := s.DB.QueryRow("SELECT data FROM job_status WHERE name = $1", name).Scan(&data)
+	err = s.DB.QueryRowContext(ctx, "SELECT data FROM job_status WHERE name = $1", name).Scan(&data)
 	if err == sql.ErrNoRows {
 		return nil, store.ErrNotFound
 	}
@@ -110,6 +173,11 @@ func (s *JobStore) Get(ctx context.Context, name string) (*v1.JobStatus, error)
 
 // Find searches for jobs based on their annotations. If filter is empty no filter is applied.
 func (s *JobStore) Find(ctx context.Context, filter []*v1.FilterExpression, order []*v1.OrderExpression, start, limit int) (slice []v1.JobStatus, total int, err error) {
+	ctx, span := tracing.Start(ctx, "JobStore.Find")
+	defer tracing.FinishSpan(span, &err)
+	ctx, cancel := withTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
 	fieldMap := map[string]string{
 		"name":       "name",
 		"owner":      "owner",
@@ -118,7 +186,7 @@ func (s *JobStore) Find(ctx context.Context, filter []*v1.FilterExpression, orde
 		"repo.repo":  "repo_repo",
 		"repo.host":  "repo_host",
 		"repo.ref":   "repo_ref",
-		"trigger":    "trigger",
+		"trigger":    "trigger_src",
 		"success":    "success",
 		"created":    "created",
 	}
@@ -139,6 +207,30 @@ func (s *JobStore) Find(ctx context.Context, filter []*v1.FilterExpression, orde
 				not = "NOT"
 			}
 
+			if strings.HasPrefix(t.Field, filterexpr.LabelFieldPrefix) {
+				// labels live in their own table, hence we need a subquery
+				cond := "l.name = ?"
+				args = append(args, strings.TrimPrefix(t.Field, filterexpr.LabelFieldPrefix))
+				switch t.Operation {
+				case v1.FilterOp_OP_EQUALS:
+					cond += " AND l.value = ?"
+				case v1.FilterOp_OP_CONTAINS:
+					cond += " AND l.value LIKE '%' || ? || '%'"
+				case v1.FilterOp_OP_ENDS_WITH:
+					cond += " AND l.value LIKE '%' || ?"
+				case v1.FilterOp_OP_STARTS_WITH:
+					cond += " AND l.value LIKE ? || '%'"
+				case v1.FilterOp_OP_EXISTS:
+				default:
+					return nil, 0, xerrors.Errorf("unknown operation %v", t.Operation)
+				}
+				if t.Operation != v1.FilterOp_OP_EXISTS {
+					args = append(args, t.Value)
+				}
+				terms = append(terms, fmt.Sprintf("%s EXISTS (SELECT 1 FROM job_labels l WHERE l.job_id = job_status.id AND %s)", not, cond))
+				continue
+			}
+
 			field, ok := fieldMap[t.Field]
 			if !ok {
 				return nil, 0, xerrors.Errorf("unknown field %s", t.Field)
@@ -200,16 +292,21 @@ func (s *JobStore) Find(ctx context.Context, filter []*v1.FilterExpression, orde
 		limitExp = fmt.Sprintf("%d", limit)
 	}
 
+	db := s.DB
+	if s.ReadDB != nil && store.StaleReadsAllowed(ctx) {
+		db = s.ReadDB
+	}
+
 	countQuery := fmt.Sprintf("SELECT COUNT(1) FROM job_status %s", whereExp)
 	log.WithField("query", countQuery).Debug("running query")
-	err = s.DB.QueryRow(countQuery, args...).Scan(&total)
+	err = db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	query := fmt.Sprintf("SELECT data FROM job_status %s %s LIMIT %s OFFSET %d", whereExp, orderExp, limitExp, start)
 	log.WithField("query", query).Debug("running query")
-	rows, err := s.DB.Query(query, args...)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -238,9 +335,283 @@ func (s *JobStore) Find(ctx context.Context, filter []*v1.FilterExpression, orde
 	return result, total, nil
 }
 
+// StoreResolvedSpec stores the resolved job spec a job ran with.
+func (s *JobStore) StoreResolvedSpec(name string, data []byte) error {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT
+		INTO   job_resolved_spec (name, data)
+		VALUES                   ($1  , $2  )
+		ON CONFLICT (name) DO UPDATE
+			SET data = $2
+		`,
+		name,
+		data,
+	)
+	return err
+}
+
+// GetResolvedSpec retrieves the resolved job spec a job ran with.
+func (s *JobStore) GetResolvedSpec(name string) ([]byte, error) {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	var data []byte
+	err := s.DB.QueryRowContext(ctx, "SELECT data FROM job_resolved_spec WHERE name = $1", name).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// StoreProvenance stores the signed provenance of a job.
+func (s *JobStore) StoreProvenance(name string, data []byte) error {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT
+		INTO   job_provenance (name, data)
+		VALUES                ($1  , $2  )
+		ON CONFLICT (name) DO UPDATE
+			SET data = $2
+		`,
+		name,
+		data,
+	)
+	return err
+}
+
+// GetProvenance retrieves the signed provenance of a job.
+func (s *JobStore) GetProvenance(name string) ([]byte, error) {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	var data []byte
+	err := s.DB.QueryRowContext(ctx, "SELECT data FROM job_provenance WHERE name = $1", name).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// StoreTriggerPayload stores what triggered a job.
+func (s *JobStore) StoreTriggerPayload(name string, data []byte) error {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT
+		INTO   job_trigger_payload (name, data)
+		VALUES                     ($1  , $2  )
+		ON CONFLICT (name) DO UPDATE
+			SET data = $2
+		`,
+		name,
+		data,
+	)
+	return err
+}
+
+// GetTriggerPayload retrieves what triggered a job.
+func (s *JobStore) GetTriggerPayload(name string) ([]byte, error) {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	var data []byte
+	err := s.DB.QueryRowContext(ctx, "SELECT data FROM job_trigger_payload WHERE name = $1", name).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, store.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// StoreSBOM stores the software bill of materials of an image a job built.
+func (s *JobStore) StoreSBOM(name, image string, data []byte) error {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT
+		INTO   job_sbom (name, image, data)
+		VALUES          ($1  , $2   , $3  )
+		ON CONFLICT (name, image) DO UPDATE
+			SET data = $3
+		`,
+		name,
+		image,
+		data,
+	)
+	return err
+}
+
+// GetSBOMs retrieves the software bills of materials of the images a job built, by image.
+func (s *JobStore) GetSBOMs(name string) (sboms map[string][]byte, err error) {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT image, data FROM job_sbom WHERE name = $1", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sboms = make(map[string][]byte)
+	for rows.Next() {
+		var (
+			image string
+			data  []byte
+		)
+		err = rows.Scan(&image, &data)
+		if err != nil {
+			return nil, err
+		}
+		sboms[image] = data
+	}
+	return sboms, rows.Err()
+}
+
+// AddEvent records something that happened to a job. Jobs have at most one event per type and phase.
+func (s *JobStore) AddEvent(ctx context.Context, name string, evt v1.JobEvent) error {
+	ctx, cancel := withTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
+	t := evt.GetTime()
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT
+		INTO   job_events (name, type, phase, time, message)
+		VALUES            ($1  , $2  , $3   , $4  , $5     )
+		ON CONFLICT (name, type, phase) DO NOTHING
+		`,
+		name,
+		strings.ToLower(strings.TrimPrefix(evt.Type.String(), "EVENT_")),
+		strings.ToLower(strings.TrimPrefix(evt.Phase.String(), "PHASE_")),
+		t.GetSeconds()*int64(time.Second)+int64(t.GetNanos()),
+		evt.Message,
+	)
+	return err
+}
+
+// GetEvents returns the events of a job, oldest first.
+func (s *JobStore) GetEvents(ctx context.Context, name string) ([]v1.JobEvent, error) {
+	ctx, cancel := withTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT type, phase, time, message FROM job_events WHERE name = $1 ORDER BY time ASC, id ASC", name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []v1.JobEvent
+	for rows.Next() {
+		var (
+			evt        v1.JobEvent
+			tpe, phase string
+			nanos      int64
+		)
+		err := rows.Scan(&tpe, &phase, &nanos, &evt.Message)
+		if err != nil {
+			return nil, err
+		}
+		evt.Type = v1.JobEventType(v1.JobEventType_value["EVENT_"+strings.ToUpper(tpe)])
+		evt.Phase = v1.JobPhase(v1.JobPhase_value["PHASE_"+strings.ToUpper(phase)])
+		evt.Time = &timestamp.Timestamp{Seconds: nanos / int64(time.Second), Nanos: int32(nanos % int64(time.Second))}
+		res = append(res, evt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Delete removes a job, its annotations and labels from the store.
+func (s *JobStore) Delete(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "JobStore.Delete", trace.WithAttributes(attribute.String("job", name)))
+	defer tracing.FinishSpan(span, &err)
+	ctx, cancel := withTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	var jobID int
+	err = tx.QueryRowContext(ctx, "DELETE FROM job_status WHERE name = $1 RETURNING id", name).Scan(&jobID)
+	if err == sql.ErrNoRows {
+		tx.Rollback()
+		return store.ErrNotFound
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM annotations WHERE job_id = $1", jobID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM job_labels WHERE job_id = $1", jobID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Purge removes a job, its annotations, labels, job spec, resolved spec, provenance, trigger payload and events from the store.
+func (s *JobStore) Purge(ctx context.Context, name string) (err error) {
+	ctx, span := tracing.Start(ctx, "JobStore.Purge", trace.WithAttributes(attribute.String("job", name)))
+	defer tracing.FinishSpan(span, &err)
+	ctx, cancel := withTimeout(ctx, s.QueryTimeout)
+	defer cancel()
+
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, q := range []string{
+		"DELETE FROM annotations WHERE job_id IN (SELECT id FROM job_status WHERE name = $1)",
+		"DELETE FROM job_labels WHERE job_id IN (SELECT id FROM job_status WHERE name = $1)",
+		"DELETE FROM job_status WHERE name = $1",
+		"DELETE FROM job_spec WHERE name = $1",
+		"DELETE FROM job_resolved_spec WHERE name = $1",
+		"DELETE FROM job_provenance WHERE name = $1",
+		"DELETE FROM job_trigger_payload WHERE name = $1",
+		"DELETE FROM job_sbom WHERE name = $1",
+		"DELETE FROM job_events WHERE name = $1",
+	} {
+		_, err = tx.ExecContext(ctx, q, name)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // StoreJobSpec stores job information in the store.
 func (s *JobStore) StoreJobSpec(name string, data []byte) error {
-	rows, err := s.DB.Query(`
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
+	rows, err := s.DB.QueryContext(ctx, `
 		INSERT
 		INTO   job_spec (name, data)
 		VALUES          ($1  , $2  ) 
@@ -260,8 +631,11 @@ func (s *JobStore) StoreJobSpec(name string, data []byte) error {
 
 // GetJobSpec retrieves a particular job bassd on its name.
 func (s *JobStore) GetJobSpec(name string) ([]byte, error) {
+	ctx, cancel := withTimeout(context.Background(), s.QueryTimeout)
+	defer cancel()
+
 	var data []byte
-	err := s.DB.QueryRow("SELECT data FROM job_spec WHERE name = $1", name).Scan(&data)
+	err := s.DB.QueryRowContext(ctx, "SELECT data FROM job_spec WHERE name = $1", name).Scan(&data)
 	if err == sql.ErrNoRows {
 		return nil, store.ErrNotFound
 	}
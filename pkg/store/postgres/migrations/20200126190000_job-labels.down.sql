@@ -0,0 +1 @@
+DROP TABLE job_labels;
\ No newline at end of file
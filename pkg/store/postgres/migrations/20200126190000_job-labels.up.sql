@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS job_labels (
+	job_id INT NOT NULL,
+	name varchar(255) NOT NULL,
+	value varchar(255) NOT NULL,
+	CONSTRAINT job_label UNIQUE(job_id, name)
+);
+CREATE INDEX idx_job_labels_name_value ON job_labels(name, value);
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+	id varchar(255) NOT NULL PRIMARY KEY,
+	event_type varchar(255) NOT NULL,
+	payload bytea NOT NULL,
+	error text NOT NULL,
+	received int NOT NULL,
+	attempts int NOT NULL
+);
\ No newline at end of file
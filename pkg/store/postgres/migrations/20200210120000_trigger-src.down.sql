@@ -0,0 +1,13 @@
+-- restores the prefixed trigger names, taking the trigger from the job itself like the up migration
+UPDATE job_status SET trigger_src=CASE data::jsonb->'metadata'->>'trigger'
+	WHEN '1' THEN 'trigger_manual'
+	WHEN '2' THEN 'trigger_push'
+	WHEN '3' THEN 'trigger_deleted'
+	WHEN '4' THEN 'trigger_tag'
+	WHEN '5' THEN 'trigger_pull_request'
+	WHEN '6' THEN 'trigger_scheduled'
+	WHEN '7' THEN 'trigger_artifact'
+	WHEN '8' THEN 'trigger_upstream'
+	WHEN '9' THEN 'trigger_merge_group'
+	ELSE 'trigger_unknown'
+END;
@@ -0,0 +1,13 @@
+-- trigger_src used to be "trigger_" for all jobs, hence we take the trigger from the job itself. Triggers are stored as numbers (EnumsAsInts) and left out if they're unknown.
+UPDATE job_status SET trigger_src=CASE data::jsonb->'metadata'->>'trigger'
+	WHEN '1' THEN 'manual'
+	WHEN '2' THEN 'push'
+	WHEN '3' THEN 'deleted'
+	WHEN '4' THEN 'tag'
+	WHEN '5' THEN 'pull_request'
+	WHEN '6' THEN 'scheduled'
+	WHEN '7' THEN 'artifact'
+	WHEN '8' THEN 'upstream'
+	WHEN '9' THEN 'merge_group'
+	ELSE 'unknown'
+END;
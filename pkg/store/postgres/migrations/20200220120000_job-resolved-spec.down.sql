@@ -0,0 +1 @@
+DROP TABLE job_resolved_spec;
\ No newline at end of file
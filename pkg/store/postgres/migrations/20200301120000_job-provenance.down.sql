@@ -0,0 +1 @@
+DROP TABLE job_provenance;
\ No newline at end of file
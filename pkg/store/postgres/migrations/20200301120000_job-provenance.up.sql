@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS job_provenance (
+	name varchar(255) NOT NULL PRIMARY KEY,
+	data bytea NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE image_builds;
\ No newline at end of file
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS image_builds (
+	digest varchar(255) NOT NULL,
+	image varchar(255) NOT NULL,
+	job varchar(255) NOT NULL,
+	repo_host varchar(255) NOT NULL,
+	repo_owner varchar(255) NOT NULL,
+	repo_repo varchar(255) NOT NULL,
+	repo_ref varchar(255) NOT NULL,
+	repo_revision varchar(255) NOT NULL,
+	built int NOT NULL,
+	CONSTRAINT image_build UNIQUE(digest, image, job)
+);
+
+CREATE INDEX IF NOT EXISTS image_builds_digest ON image_builds (digest);
\ No newline at end of file
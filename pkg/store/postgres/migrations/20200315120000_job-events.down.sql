@@ -0,0 +1 @@
+DROP TABLE job_events;
\ No newline at end of file
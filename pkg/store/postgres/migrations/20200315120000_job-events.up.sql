@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS job_events (
+	id SERIAL PRIMARY KEY,
+	name varchar(255) NOT NULL,
+	type varchar(255) NOT NULL,
+	phase varchar(255) NOT NULL,
+	time bigint NOT NULL,
+	message text NOT NULL,
+	CONSTRAINT job_event UNIQUE(name, type, phase)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX idx_job_status_repo_ref_created;
\ No newline at end of file
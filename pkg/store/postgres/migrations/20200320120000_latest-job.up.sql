@@ -0,0 +1 @@
+CREATE INDEX idx_job_status_repo_ref_created ON job_status(repo_owner, repo_repo, repo_ref, created DESC);
\ No newline at end of file
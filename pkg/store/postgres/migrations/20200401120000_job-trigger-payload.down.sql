@@ -0,0 +1 @@
+DROP TABLE job_trigger_payload;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE job_sbom;
\ No newline at end of file
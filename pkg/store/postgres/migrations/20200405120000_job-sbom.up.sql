@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS job_sbom (
+	name varchar(255) NOT NULL,
+	image varchar(1024) NOT NULL,
+	data bytea NOT NULL,
+	PRIMARY KEY (name, image)
+);
\ No newline at end of file
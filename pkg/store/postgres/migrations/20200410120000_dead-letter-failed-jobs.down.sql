@@ -0,0 +1 @@
+ALTER TABLE dead_letters DROP COLUMN failed_jobs;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE dead_letters ADD COLUMN IF NOT EXISTS failed_jobs text NOT NULL DEFAULT '';
\ No newline at end of file
@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/32leaves/werft/pkg/store"
 )
@@ -9,6 +11,9 @@ import (
 // NumberGroup provides postgres backed number groups
 type NumberGroup struct {
 	DB *sql.DB
+
+	// QueryTimeout is the time a query may take. Defaults to DefaultQueryTimeout.
+	QueryTimeout time.Duration
 }
 
 // NewNumberGroup creates a new SQL number group store
@@ -18,7 +23,10 @@ func NewNumberGroup(db *sql.DB) (*NumberGroup, error) {
 
 // Latest returns the latest number of a particular number group.
 func (ngrp *NumberGroup) Latest(group string) (nr int, err error) {
-	err = ngrp.DB.QueryRow(`
+	ctx, cancel := withTimeout(context.Background(), ngrp.QueryTimeout)
+	defer cancel()
+
+	err = ngrp.DB.QueryRowContext(ctx, `
 		SELECT val
 		FROM   number_group
 		WHERE  name = $1`,
@@ -32,7 +40,10 @@ func (ngrp *NumberGroup) Latest(group string) (nr int, err error) {
 
 // Next returns the next number in the group.
 func (ngrp *NumberGroup) Next(group string) (nr int, err error) {
-	err = ngrp.DB.QueryRow(`
+	ctx, cancel := withTimeout(context.Background(), ngrp.QueryTimeout)
+	defer cancel()
+
+	err = ngrp.DB.QueryRowContext(ctx, `
 		INSERT
 		INTO   number_group (name, val)
 		VALUES              ($1  , 0  )
@@ -0,0 +1,18 @@
+package postgres
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultQueryTimeout is the time a query may take unless the store is configured otherwise
+const DefaultQueryTimeout = 30 * time.Second
+
+// withTimeout limits the time the queries using the returned context may take.
+// A timeout of zero uses DefaultQueryTimeout.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		timeout = DefaultQueryTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,173 @@
+package store
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// snapshot is the on-disk format of the in-memory stores
+type snapshot struct {
+	Jobs   []v1.JobStatus    `json:"jobs"`
+	Specs  map[string][]byte `json:"specs"`
+	Groups map[string]int    `json:"groups"`
+	Logs   map[string][]byte `json:"logs"`
+
+	ResolvedSpecs map[string][]byte        `json:"resolvedSpecs,omitempty"`
+	Provenance    map[string][]byte        `json:"provenance,omitempty"`
+	Events        map[string][]v1.JobEvent `json:"events,omitempty"`
+}
+
+// SaveSnapshot writes the content of in-memory stores to w.
+// All stores must have been created using the NewInMemory* functions.
+func SaveSnapshot(w io.Writer, jobs Jobs, groups NumberGroup, logs Logs) error {
+	js, ok := jobs.(*inMemoryJobStore)
+	if !ok {
+		return xerrors.Errorf("can only snapshot in-memory job stores")
+	}
+	ng, ok := groups.(*inMemoryNumberGroup)
+	if !ok {
+		return xerrors.Errorf("can only snapshot in-memory number groups")
+	}
+	ls, ok := logs.(*inMemoryLogStore)
+	if !ok {
+		return xerrors.Errorf("can only snapshot in-memory log stores")
+	}
+
+	snap := snapshot{
+		Specs:  make(map[string][]byte),
+		Groups: make(map[string]int),
+		Logs:   make(map[string][]byte),
+
+		ResolvedSpecs: make(map[string][]byte),
+		Provenance:    make(map[string][]byte),
+		Events:        make(map[string][]v1.JobEvent),
+	}
+
+	js.mu.RLock()
+	for _, j := range js.jobs {
+		snap.Jobs = append(snap.Jobs, j)
+	}
+	for k, v := range js.specs {
+		snap.Specs[k] = v
+	}
+	for k, v := range js.resolved {
+		snap.ResolvedSpecs[k] = v
+	}
+	for k, v := range js.provenance {
+		snap.Provenance[k] = v
+	}
+	for k, v := range js.events {
+		snap.Events[k] = v
+	}
+	js.mu.RUnlock()
+
+	ng.mu.Lock()
+	for k, v := range ng.groups {
+		snap.Groups[k] = v
+	}
+	ng.mu.Unlock()
+
+	ls.mu.RLock()
+	for k, v := range ls.logs {
+		snap.Logs[k] = v.bytes()
+	}
+	ls.mu.RUnlock()
+
+	return json.NewEncoder(w).Encode(snap)
+}
+
+// SaveSnapshotFile writes a snapshot of the in-memory stores to fn. The file is replaced atomically.
+func SaveSnapshotFile(fn string, jobs Jobs, groups NumberGroup, logs Logs) error {
+	f, err := ioutil.TempFile(filepath.Dir(fn), "snapshot")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	err = SaveSnapshot(f, jobs, groups, logs)
+	if err != nil {
+		f.Close()
+		return xerrors.Errorf("cannot write snapshot: %w", err)
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), fn)
+}
+
+// LoadSnapshot restores the content of in-memory stores from a snapshot previously written using SaveSnapshot.
+// Logs restored from a snapshot are closed.
+func LoadSnapshot(r io.Reader, jobs Jobs, groups NumberGroup, logs Logs) error {
+	js, ok := jobs.(*inMemoryJobStore)
+	if !ok {
+		return xerrors.Errorf("can only restore in-memory job stores")
+	}
+	ng, ok := groups.(*inMemoryNumberGroup)
+	if !ok {
+		return xerrors.Errorf("can only restore in-memory number groups")
+	}
+	ls, ok := logs.(*inMemoryLogStore)
+	if !ok {
+		return xerrors.Errorf("can only restore in-memory log stores")
+	}
+
+	var snap snapshot
+	err := json.NewDecoder(r).Decode(&snap)
+	if err != nil {
+		return xerrors.Errorf("cannot read snapshot: %w", err)
+	}
+
+	js.mu.Lock()
+	for _, j := range snap.Jobs {
+		js.jobs[j.Name] = j
+	}
+	for k, v := range snap.Specs {
+		js.specs[k] = v
+	}
+	for k, v := range snap.ResolvedSpecs {
+		js.resolved[k] = v
+	}
+	for k, v := range snap.Provenance {
+		js.provenance[k] = v
+	}
+	for k, v := range snap.Events {
+		js.events[k] = v
+	}
+	js.mu.Unlock()
+
+	ng.mu.Lock()
+	for k, v := range snap.Groups {
+		ng.groups[k] = v
+	}
+	ng.mu.Unlock()
+
+	ls.mu.Lock()
+	for k, v := range snap.Logs {
+		ls.logs[k] = newLogSession(v, true)
+	}
+	ls.mu.Unlock()
+
+	return nil
+}
+
+// LoadSnapshotFile restores the in-memory stores from a snapshot file. If the file does not exist, this function does nothing.
+func LoadSnapshotFile(fn string, jobs Jobs, groups NumberGroup, logs Logs) error {
+	f, err := os.Open(fn)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return LoadSnapshot(f, jobs, groups, logs)
+}
@@ -0,0 +1,97 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		jobs   = store.NewInMemoryJobStore()
+		groups = store.NewInMemoryNumberGroup()
+		logs   = store.NewInMemoryLogStore()
+	)
+
+	job := v1.JobStatus{
+		Name:     "foo.1",
+		Metadata: &v1.JobMetadata{Owner: "someone", Repository: &v1.Repository{Owner: "32leaves", Repo: "werft"}},
+		Phase:    v1.JobPhase_PHASE_DONE,
+	}
+	if err := jobs.Store(ctx, job); err != nil {
+		t.Fatalf("cannot store job: %v", err)
+	}
+	if err := jobs.StoreJobSpec(job.Name, []byte("spec")); err != nil {
+		t.Fatalf("cannot store job spec: %v", err)
+	}
+	evt := v1.JobEvent{Type: v1.JobEventType_EVENT_PHASE_CHANGED, Phase: v1.JobPhase_PHASE_DONE, Time: &timestamp.Timestamp{Seconds: 42}}
+	if err := jobs.AddEvent(ctx, job.Name, evt); err != nil {
+		t.Fatalf("cannot add job event: %v", err)
+	}
+	dup := evt
+	dup.Time = &timestamp.Timestamp{Seconds: 43}
+	if err := jobs.AddEvent(ctx, job.Name, dup); err != nil {
+		t.Fatalf("cannot add job event: %v", err)
+	}
+	if _, err := groups.Next("foo"); err != nil {
+		t.Fatalf("cannot advance number group: %v", err)
+	}
+	w, err := logs.Open(job.Name)
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+	w.Write([]byte("hello world"))
+	w.Close()
+
+	var buf bytes.Buffer
+	err = store.SaveSnapshot(&buf, jobs, groups, logs)
+	if err != nil {
+		t.Fatalf("cannot save snapshot: %v", err)
+	}
+
+	var (
+		rjobs   = store.NewInMemoryJobStore()
+		rgroups = store.NewInMemoryNumberGroup()
+		rlogs   = store.NewInMemoryLogStore()
+	)
+	err = store.LoadSnapshot(&buf, rjobs, rgroups, rlogs)
+	if err != nil {
+		t.Fatalf("cannot load snapshot: %v", err)
+	}
+
+	act, err := rjobs.Get(ctx, job.Name)
+	if err != nil {
+		t.Fatalf("cannot get restored job: %v", err)
+	}
+	if !proto.Equal(act, &job) {
+		t.Errorf("restored job does not match: %v != %v", act, &job)
+	}
+	if spec, _ := rjobs.GetJobSpec(job.Name); string(spec) != "spec" {
+		t.Errorf("restored job spec does not match: %s", spec)
+	}
+	if evts, _ := rjobs.GetEvents(ctx, job.Name); len(evts) != 1 || !proto.Equal(&evts[0], &evt) {
+		t.Errorf("restored job events do not match: %v", evts)
+	}
+	if nr, _ := rgroups.Latest("foo"); nr != 0 {
+		t.Errorf("restored number group does not match: %d", nr)
+	}
+	r, err := rlogs.Read(job.Name)
+	if err != nil {
+		t.Fatalf("cannot read restored log: %v", err)
+	}
+	defer r.Close()
+	lg, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("cannot read restored log: %v", err)
+	}
+	if string(lg) != "hello world" {
+		t.Errorf("restored log does not match: %s", lg)
+	}
+}
@@ -0,0 +1,342 @@
+package store
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// stateJobsDir and stateLogsDir are the directories of state archives which hold the job records and logs
+	stateJobsDir = "jobs/"
+	stateLogsDir = "logs/"
+
+	statePageSize = 100
+
+	// resultTypeImage marks results which report a container image a job built, see werft.recordImageBuild
+	resultTypeImage = "image"
+)
+
+// stateRecord is everything a state archive holds about a job, except for its logs
+type stateRecord struct {
+	Job          v1.JobStatus      `json:"job"`
+	Spec         []byte            `json:"spec,omitempty"`
+	ResolvedSpec []byte            `json:"resolvedSpec,omitempty"`
+	Provenance   []byte            `json:"provenance,omitempty"`
+	Trigger      []byte            `json:"trigger,omitempty"`
+	SBOMs        map[string][]byte `json:"sboms,omitempty"`
+	Events       []v1.JobEvent     `json:"events,omitempty"`
+	Images       []v1.ImageBuild   `json:"images,omitempty"`
+}
+
+// State is the set of stores whose content can be exported to and imported from a state archive, e.g. to migrate
+// werft to another database or cluster. Logs and Images are optional.
+type State struct {
+	Jobs   Jobs
+	Groups NumberGroup
+	Logs   Logs
+	Images Images
+}
+
+// ExportState writes all jobs of the job store, including their job specs, events, provenance, SBOMs and image builds,
+// to a state archive (tar.gz). If st.Logs is set, the archive contains the logs of the jobs as well.
+// Archived jobs are not exported. Returns the number of exported jobs.
+func ExportState(ctx context.Context, w io.Writer, st State) (n int, err error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	order := []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: true}}
+	for start := 0; ; start += statePageSize {
+		jobs, _, err := st.Jobs.Find(ctx, nil, order, start, statePageSize)
+		if err != nil {
+			return n, xerrors.Errorf("cannot find jobs: %w", err)
+		}
+		for _, j := range jobs {
+			err = exportJob(ctx, tw, st, j)
+			if err != nil {
+				return n, xerrors.Errorf("cannot export %s: %w", j.Name, err)
+			}
+			n++
+		}
+		if len(jobs) < statePageSize {
+			break
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return n, err
+	}
+	return n, gz.Close()
+}
+
+func exportJob(ctx context.Context, tw *tar.Writer, st State, job v1.JobStatus) error {
+	rec := stateRecord{Job: job}
+
+	var err error
+	rec.Spec, err = st.Jobs.GetJobSpec(job.Name)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	rec.ResolvedSpec, err = st.Jobs.GetResolvedSpec(job.Name)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	rec.Provenance, err = st.Jobs.GetProvenance(job.Name)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	rec.Trigger, err = st.Jobs.GetTriggerPayload(job.Name)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	rec.SBOMs, err = st.Jobs.GetSBOMs(job.Name)
+	if err != nil {
+		return err
+	}
+	rec.Events, err = st.Jobs.GetEvents(ctx, job.Name)
+	if err != nil {
+		return err
+	}
+	if st.Images != nil {
+		rec.Images, err = jobImageBuilds(ctx, st.Images, &job)
+		if err != nil {
+			return err
+		}
+	}
+
+	fc, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	err = writeStateFile(tw, stateJobsDir+job.Name+".json", fc)
+	if err != nil {
+		return err
+	}
+
+	if st.Logs == nil {
+		return nil
+	}
+	logs, err := st.Logs.Read(job.Name)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer logs.Close()
+	fc, err = ioutil.ReadAll(logs)
+	if err != nil {
+		return xerrors.Errorf("cannot read logs: %w", err)
+	}
+	return writeStateFile(tw, stateLogsDir+job.Name, fc)
+}
+
+// jobImageBuilds finds the image builds a job recorded using its image results
+func jobImageBuilds(ctx context.Context, images Images, job *v1.JobStatus) ([]v1.ImageBuild, error) {
+	var res []v1.ImageBuild
+	for _, r := range job.Results {
+		if r.Type != resultTypeImage {
+			continue
+		}
+		i := strings.LastIndex(r.Payload, "@")
+		if i < 0 {
+			continue
+		}
+		builds, err := images.Find(ctx, strings.TrimSpace(r.Payload[i+1:]))
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range builds {
+			if b.Job == job.Name {
+				res = append(res, b)
+			}
+		}
+	}
+	return res, nil
+}
+
+func writeStateFile(tw *tar.Writer, name string, content []byte) error {
+	err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(content)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tw.Write(content)
+	return err
+}
+
+// ImportState restores the jobs of a state archive written by ExportState. Jobs which exist already are replaced,
+// logs which exist already are kept. Number groups advance past the imported jobs, so that new jobs don't reuse
+// their names. Returns the number of imported jobs.
+func ImportState(ctx context.Context, r io.Reader, st State) (n int, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, xerrors.Errorf("cannot read state archive: %w", err)
+	}
+	defer gz.Close()
+
+	groups := make(map[string]int)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return n, xerrors.Errorf("cannot read state archive: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(hdr.Name, stateJobsDir):
+			var rec stateRecord
+			err = json.NewDecoder(tr).Decode(&rec)
+			if err != nil {
+				return n, xerrors.Errorf("cannot read %s: %w", hdr.Name, err)
+			}
+			err = importJob(ctx, st, &rec)
+			if err != nil {
+				return n, xerrors.Errorf("cannot import %s: %w", rec.Job.Name, err)
+			}
+			if group, nr, ok := jobNumber(rec.Job.Name); ok && nr > groups[group] {
+				groups[group] = nr
+			}
+			n++
+		case strings.HasPrefix(hdr.Name, stateLogsDir):
+			if st.Logs == nil {
+				continue
+			}
+			name := strings.TrimPrefix(hdr.Name, stateLogsDir)
+			err = importLogs(st.Logs, name, tr)
+			if err != nil {
+				return n, xerrors.Errorf("cannot import logs of %s: %w", name, err)
+			}
+		}
+	}
+
+	if st.Groups != nil {
+		for group, nr := range groups {
+			err = advanceNumberGroup(st.Groups, group, nr)
+			if err != nil {
+				return n, xerrors.Errorf("cannot advance number group %s: %w", group, err)
+			}
+		}
+	}
+	return n, nil
+}
+
+func importJob(ctx context.Context, st State, rec *stateRecord) error {
+	name := rec.Job.Name
+	err := st.Jobs.Store(ctx, rec.Job)
+	if err != nil {
+		return err
+	}
+	if rec.Spec != nil {
+		err = st.Jobs.StoreJobSpec(name, rec.Spec)
+		if err != nil {
+			return err
+		}
+	}
+	if rec.ResolvedSpec != nil {
+		err = st.Jobs.StoreResolvedSpec(name, rec.ResolvedSpec)
+		if err != nil {
+			return err
+		}
+	}
+	if rec.Provenance != nil {
+		err = st.Jobs.StoreProvenance(name, rec.Provenance)
+		if err != nil {
+			return err
+		}
+	}
+	if rec.Trigger != nil {
+		err = st.Jobs.StoreTriggerPayload(name, rec.Trigger)
+		if err != nil {
+			return err
+		}
+	}
+	for image, data := range rec.SBOMs {
+		err = st.Jobs.StoreSBOM(name, image, data)
+		if err != nil {
+			return err
+		}
+	}
+	for _, evt := range rec.Events {
+		err = st.Jobs.AddEvent(ctx, name, evt)
+		if err != nil {
+			return err
+		}
+	}
+	if st.Images != nil {
+		for _, img := range rec.Images {
+			err = st.Images.Put(ctx, img)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func importLogs(logs Logs, name string, content io.Reader) error {
+	existing, err := logs.Read(name)
+	if err == nil {
+		existing.Close()
+		return nil
+	}
+	if err != ErrNotFound {
+		return err
+	}
+
+	w, err := logs.Open(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, content)
+	if err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// jobNumber splits a job name into its number group and number, e.g. foo.3 into foo and 3
+func jobNumber(name string) (group string, nr int, ok bool) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return "", 0, false
+	}
+	nr, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return name[:i], nr, true
+}
+
+// advanceNumberGroup makes sure that the next number of a group is greater than nr
+func advanceNumberGroup(groups NumberGroup, group string, nr int) error {
+	latest, err := groups.Latest(group)
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	for err == ErrNotFound || latest < nr {
+		latest, err = groups.Next(group)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
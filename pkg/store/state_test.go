@@ -0,0 +1,135 @@
+package store_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	const digest = "sha256:4a1c4b21597c1b4415bdbecb28a3296c6b5e23ca4f9feeb599860a1dac6a0108"
+	var (
+		ctx = context.Background()
+		src = store.State{
+			Jobs:   store.NewInMemoryJobStore(),
+			Groups: store.NewInMemoryNumberGroup(),
+			Logs:   store.NewInMemoryLogStore(),
+			Images: store.NewInMemoryImages(),
+		}
+	)
+
+	job := v1.JobStatus{
+		Name:     "foo.3",
+		Metadata: &v1.JobMetadata{Owner: "someone", Created: &timestamp.Timestamp{Seconds: 42}},
+		Phase:    v1.JobPhase_PHASE_DONE,
+		Results:  []*v1.JobResult{&v1.JobResult{Type: "image", Payload: "eu.gcr.io/werft/werft@" + digest}},
+	}
+	evt := v1.JobEvent{Type: v1.JobEventType_EVENT_PHASE_CHANGED, Phase: v1.JobPhase_PHASE_DONE, Time: &timestamp.Timestamp{Seconds: 43}}
+	img := v1.ImageBuild{Digest: digest, Image: "eu.gcr.io/werft/werft", Job: job.Name}
+	for _, err := range []error{
+		src.Jobs.Store(ctx, job),
+		src.Jobs.StoreJobSpec(job.Name, []byte("spec")),
+		src.Jobs.StoreResolvedSpec(job.Name, []byte("resolved")),
+		src.Jobs.StoreTriggerPayload(job.Name, []byte("trigger")),
+		src.Jobs.StoreSBOM(job.Name, "eu.gcr.io/werft/werft@"+digest, []byte(`{"spdxVersion":"SPDX-2.2"}`)),
+		src.Jobs.AddEvent(ctx, job.Name, evt),
+		src.Images.Put(ctx, img),
+		src.Images.Put(ctx, v1.ImageBuild{Digest: digest, Image: "eu.gcr.io/werft/werft", Job: "bar.1"}),
+	} {
+		if err != nil {
+			t.Fatalf("cannot set up source: %v", err)
+		}
+	}
+	w, err := src.Logs.Open(job.Name)
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+	w.Write([]byte("hello world"))
+	w.Close()
+
+	tests := []struct {
+		Name     string
+		WithLogs bool
+	}{
+		{"with logs", true},
+		{"without logs", false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			exp := src
+			if !test.WithLogs {
+				exp.Logs = nil
+			}
+			var buf bytes.Buffer
+			n, err := store.ExportState(ctx, &buf, exp)
+			if err != nil {
+				t.Fatalf("cannot export state: %v", err)
+			}
+			if n != 1 {
+				t.Errorf("expected 1 exported job, got %d", n)
+			}
+
+			dst := store.State{
+				Jobs:   store.NewInMemoryJobStore(),
+				Groups: store.NewInMemoryNumberGroup(),
+				Logs:   store.NewInMemoryLogStore(),
+				Images: store.NewInMemoryImages(),
+			}
+			n, err = store.ImportState(ctx, &buf, dst)
+			if err != nil {
+				t.Fatalf("cannot import state: %v", err)
+			}
+			if n != 1 {
+				t.Errorf("expected 1 imported job, got %d", n)
+			}
+
+			act, err := dst.Jobs.Get(ctx, job.Name)
+			if err != nil {
+				t.Fatalf("cannot get imported job: %v", err)
+			}
+			if !proto.Equal(act, &job) {
+				t.Errorf("imported job does not match: %v != %v", act, &job)
+			}
+			if spec, _ := dst.Jobs.GetResolvedSpec(job.Name); string(spec) != "resolved" {
+				t.Errorf("imported resolved spec does not match: %s", spec)
+			}
+			if trigger, _ := dst.Jobs.GetTriggerPayload(job.Name); string(trigger) != "trigger" {
+				t.Errorf("imported trigger payload does not match: %s", trigger)
+			}
+			if sboms, _ := dst.Jobs.GetSBOMs(job.Name); len(sboms) != 1 || string(sboms["eu.gcr.io/werft/werft@"+digest]) != `{"spdxVersion":"SPDX-2.2"}` {
+				t.Errorf("imported SBOMs do not match: %v", sboms)
+			}
+			if evts, _ := dst.Jobs.GetEvents(ctx, job.Name); len(evts) != 1 || !proto.Equal(&evts[0], &evt) {
+				t.Errorf("imported job events do not match: %v", evts)
+			}
+			if builds, _ := dst.Images.Find(ctx, digest); len(builds) != 1 || !proto.Equal(&builds[0], &img) {
+				t.Errorf("imported image builds do not match: %v", builds)
+			}
+			if nr, _ := dst.Groups.Next("foo"); nr != 4 {
+				t.Errorf("expected number group to continue with 4, got %d", nr)
+			}
+
+			r, err := dst.Logs.Read(job.Name)
+			if !test.WithLogs {
+				if err != store.ErrNotFound {
+					t.Errorf("expected no logs, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("cannot read imported log: %v", err)
+			}
+			defer r.Close()
+			lg, _ := ioutil.ReadAll(r)
+			if string(lg) != "hello world" {
+				t.Errorf("imported log does not match: %s", lg)
+			}
+		})
+	}
+}
@@ -32,6 +32,25 @@ type Logs interface {
 	// Callers are supposed to close the reader once done.
 	// Reading from logs currently being written is supported.
 	Read(id string) (io.ReadCloser, error)
+
+	// Delete removes a log file from this store. Logs which are currently written cannot be deleted.
+	// Returns ErrNotFound if the log file isn't found.
+	Delete(id string) error
+}
+
+// ObjectStore keeps blobs by key, e.g. in a directory or a bucket. Stores which keep their data in objects, e.g. the
+// object log store and the job archive, build on it, so that every backend works for all of them.
+type ObjectStore interface {
+	// Put stores an object. Putting an object whose key we already have in store will override the previous object.
+	Put(ctx context.Context, key string, content io.Reader) error
+
+	// Get retrieves an object. Callers are supposed to close the reader once done.
+	// Returns ErrNotFound if the object isn't found.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes an object from this store.
+	// Returns ErrNotFound if the object isn't found.
+	Delete(ctx context.Context, key string) error
 }
 
 // Jobs provides access to past jobs
@@ -51,9 +70,120 @@ type Jobs interface {
 	// Get retrieves previously stored job spec data
 	GetJobSpec(name string) (data []byte, err error)
 
+	// StoreResolvedSpec stores the resolved job spec, i.e. the pod template a job ran with.
+	StoreResolvedSpec(name string, data []byte) error
+
+	// GetResolvedSpec retrieves a previously stored resolved job spec.
+	// If the job has no resolved spec we'll return ErrNotFound.
+	GetResolvedSpec(name string) (data []byte, err error)
+
+	// StoreProvenance stores the signed provenance of a job.
+	StoreProvenance(name string, data []byte) error
+
+	// GetProvenance retrieves the signed provenance of a job.
+	// If the job has no provenance we'll return ErrNotFound.
+	GetProvenance(name string) (data []byte, err error)
+
+	// StoreTriggerPayload stores what triggered a job, e.g. the webhook which started it.
+	StoreTriggerPayload(name string, data []byte) error
+
+	// GetTriggerPayload retrieves what triggered a job.
+	// If the job has no trigger payload we'll return ErrNotFound.
+	GetTriggerPayload(name string) (data []byte, err error)
+
+	// StoreSBOM stores the software bill of materials of an image a job built.
+	StoreSBOM(name, image string, data []byte) error
+
+	// GetSBOMs retrieves the software bills of materials of the images a job built, by image.
+	// If the job has none we'll return an empty map.
+	GetSBOMs(name string) (sboms map[string][]byte, err error)
+
+	// AddEvent records something that happened to a job, e.g. that its pod was scheduled.
+	// Jobs have at most one event per type and phase: adding another one is a no-op, i.e. the first event is kept.
+	AddEvent(ctx context.Context, name string, evt v1.JobEvent) error
+
+	// GetEvents returns the events of a job, oldest first.
+	GetEvents(ctx context.Context, name string) ([]v1.JobEvent, error)
+
 	// Searches for jobs based on their annotations. If filter is empty no filter is applied.
 	// If limit is 0, no limit is applied.
 	Find(ctx context.Context, filter []*v1.FilterExpression, order []*v1.OrderExpression, start, limit int) (slice []v1.JobStatus, total int, err error)
+
+	// Delete removes a job from the store. The job spec remains untouched.
+	// If the job is unknown we'll return ErrNotFound.
+	Delete(ctx context.Context, name string) error
+
+	// Purge removes a job and everything stored about it, i.e. its job spec, resolved spec, provenance, trigger payload, SBOMs and events.
+	// Unlike Delete, purging a job which is not in the store (e.g. because it was archived) still removes the rest.
+	Purge(ctx context.Context, name string) error
+}
+
+// BatchJobs is implemented by job stores which can store several jobs at once more efficiently than one at a time
+type BatchJobs interface {
+	Jobs
+
+	// StoreBatch stores several jobs at once. If it fails, none of the jobs may have been stored.
+	StoreBatch(ctx context.Context, jobs []v1.JobStatus) error
+}
+
+type staleReadsKey struct{}
+
+// WithStaleReads marks ctx such that job stores may answer Find from a read replica whose data can lag behind
+// the latest job updates. Use it for queries which tolerate that, e.g. listing jobs for a dashboard.
+func WithStaleReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, staleReadsKey{}, true)
+}
+
+// StaleReadsAllowed returns true if ctx was marked using WithStaleReads
+func StaleReadsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(staleReadsKey{}).(bool)
+	return allowed
+}
+
+// JobArchive stores jobs which have been moved out of the job store
+type JobArchive interface {
+	// Put places a job in the archive. Putting a job whose name we already have in the archive
+	// will override the previously archived job.
+	Put(ctx context.Context, job v1.JobStatus) error
+
+	// Get retrieves a job from the archive.
+	// If the job is unknown we'll return ErrNotFound.
+	Get(ctx context.Context, name string) (*v1.JobStatus, error)
+
+	// Delete removes a job from the archive.
+	// If the job is unknown we'll return ErrNotFound.
+	Delete(ctx context.Context, name string) error
+}
+
+// DeadLetters stores webhook events which failed processing, so that they can be inspected and replayed later
+type DeadLetters interface {
+	// Put places an event in the queue. Putting an event whose ID we already have in the queue
+	// will override the previously stored event.
+	Put(ctx context.Context, dl v1.DeadLetter) error
+
+	// Get retrieves an event from the queue.
+	// If the event is unknown we'll return ErrNotFound.
+	Get(ctx context.Context, id string) (*v1.DeadLetter, error)
+
+	// List returns all events in the queue, oldest first.
+	List(ctx context.Context) ([]v1.DeadLetter, error)
+
+	// Delete removes an event from the queue.
+	// If the event is unknown we'll return ErrNotFound.
+	Delete(ctx context.Context, id string) error
+}
+
+// Images records which jobs built which container images
+type Images interface {
+	// Put records that a job built an image. Recording the same digest, image and job again
+	// overrides the previous record.
+	Put(ctx context.Context, img v1.ImageBuild) error
+
+	// Find returns all builds of the image with the digest, oldest first.
+	Find(ctx context.Context, digest string) ([]v1.ImageBuild, error)
+
+	// DeleteJob removes the records of all images a job built.
+	DeleteJob(ctx context.Context, job string) error
 }
 
 // NumberGroup enables to atomic generation and storage of numbers.
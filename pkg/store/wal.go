@@ -0,0 +1,617 @@
+package store
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DefaultWALMaxSize is the size the write-ahead log of a WALJobStore may grow to if it isn't configured
+	DefaultWALMaxSize = 64 * 1024 * 1024
+
+	// DefaultWALRetryInterval is how often a WALJobStore tries to replay its write-ahead log if it isn't configured
+	DefaultWALRetryInterval = 5 * time.Second
+
+	// DefaultWALMaxAttempts is how often a WALJobStore replays an update which fails for an unknown reason if it isn't configured
+	DefaultWALMaxAttempts = 10
+)
+
+// WALJobStore keeps the job updates and events its delegate cannot store, e.g. while the database is unavailable,
+// in a write-ahead log on disk and replays them in order once the delegate is back. While there are updates in the
+// log, all updates go to the log so that they reach the delegate in the order they were made. Get returns buffered
+// updates, Find and everything other than job updates and events go to the delegate right away.
+//
+// Updates the delegate rejects for good, e.g. because they violate a constraint, would block the log forever. Replay
+// moves them to a dead letter file next to the log (its path with a .dead suffix) instead, and so it does with updates
+// which keep failing for an unknown reason. Updates which fail because the delegate is unavailable are retried until it's back.
+type WALJobStore struct {
+	// MaxSize is the size in bytes the log may grow to. Once it's full, updates fail like they would without the log.
+	MaxSize int64
+
+	// MaxAttempts is how often an update which fails for an unknown reason is replayed before it's dead-lettered
+	MaxAttempts int
+
+	delegate Jobs
+	path     string
+
+	// orderMu makes sure an update which goes to the delegate right away cannot overtake one which is about to go to the log
+	orderMu sync.Mutex
+
+	mu           sync.Mutex
+	f            *os.File
+	size         int64
+	entries      []walEntry
+	pending      map[string]v1.JobStatus
+	deadLettered int
+
+	// replayMu makes sure only one replay runs at a time. It guards attempts, the number of failed replays of the oldest update.
+	replayMu sync.Mutex
+	attempts int
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// walEntry is an update in the write-ahead log: either a job status or an event of the job
+type walEntry struct {
+	Job   *v1.JobStatus
+	Name  string
+	Event *v1.JobEvent
+}
+
+// walRecord is how entries are written to the log, one JSON object per line. Dead letters carry the error which made replaying them fail.
+type walRecord struct {
+	Job   json.RawMessage `json:"job,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Event json.RawMessage `json:"event,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+func (e walEntry) marshal() ([]byte, error) {
+	return e.marshalWithError(nil)
+}
+
+func (e walEntry) marshalWithError(cause error) ([]byte, error) {
+	var (
+		m   = &jsonpb.Marshaler{EnumsAsInts: true}
+		rec = walRecord{Name: e.Name}
+	)
+	if cause != nil {
+		rec.Error = cause.Error()
+	}
+	if e.Job != nil {
+		s, err := m.MarshalToString(e.Job)
+		if err != nil {
+			return nil, err
+		}
+		rec.Job = json.RawMessage(s)
+	}
+	if e.Event != nil {
+		s, err := m.MarshalToString(e.Event)
+		if err != nil {
+			return nil, err
+		}
+		rec.Event = json.RawMessage(s)
+	}
+	res, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	return append(res, '\n'), nil
+}
+
+func unmarshalWALEntry(line []byte) (walEntry, error) {
+	var rec walRecord
+	err := json.Unmarshal(line, &rec)
+	if err != nil {
+		return walEntry{}, err
+	}
+
+	res := walEntry{Name: rec.Name}
+	if len(rec.Job) > 0 {
+		res.Job = &v1.JobStatus{}
+		err = jsonpb.Unmarshal(bytes.NewReader(rec.Job), res.Job)
+		if err != nil {
+			return walEntry{}, err
+		}
+	}
+	if len(rec.Event) > 0 {
+		res.Event = &v1.JobEvent{}
+		err = jsonpb.Unmarshal(bytes.NewReader(rec.Event), res.Event)
+		if err != nil {
+			return walEntry{}, err
+		}
+	}
+	return res, nil
+}
+
+// NewWALJobStore creates a job store which keeps the updates delegate cannot store in a write-ahead log at path,
+// and tries to replay them every retryInterval. Updates left in the log by a previous run are replayed as well.
+func NewWALJobStore(delegate Jobs, path string, retryInterval time.Duration) (*WALJobStore, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot open write-ahead log: %w", err)
+	}
+
+	w := &WALJobStore{
+		MaxSize:     DefaultWALMaxSize,
+		MaxAttempts: DefaultWALMaxAttempts,
+		delegate:    delegate,
+		path:        path,
+		f:           f,
+		pending:     make(map[string]v1.JobStatus),
+		stop:        make(chan struct{}),
+	}
+	var invalid bool
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		e, err := unmarshalWALEntry(line)
+		if err != nil {
+			// the last line is incomplete if werft stopped while writing it
+			log.WithError(err).WithField("path", path).Warn("skipping invalid write-ahead log entry")
+			invalid = true
+			continue
+		}
+		w.entries = append(w.entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, xerrors.Errorf("cannot read write-ahead log: %w", err)
+	}
+	if invalid {
+		err = w.rewrite()
+	} else {
+		w.size, err = f.Seek(0, io.SeekEnd)
+		for _, e := range w.entries {
+			if e.Job != nil {
+				w.pending[e.Job.Name] = *e.Job
+			}
+		}
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if len(w.entries) > 0 {
+		log.WithField("entries", len(w.entries)).Info("replaying job updates from the write-ahead log")
+	}
+
+	if retryInterval <= 0 {
+		retryInterval = DefaultWALRetryInterval
+	}
+	go w.replayPeriodically(retryInterval)
+	return w, nil
+}
+
+// Buffered returns the number of updates in the log which wait to be replayed
+func (w *WALJobStore) Buffered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.entries)
+}
+
+// DeadLettered returns the number of updates which were moved to the dead letter file since the store was created
+func (w *WALJobStore) DeadLettered() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.deadLettered
+}
+
+// buffering returns true if there are updates in the log
+func (w *WALJobStore) buffering() bool {
+	return w.Buffered() > 0
+}
+
+// append adds an update to the log. cause is the error the delegate returned, if any.
+func (w *WALJobStore) append(e walEntry, cause error) error {
+	line, err := e.marshal()
+	if err != nil {
+		return xerrors.Errorf("cannot serialize job update: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.MaxSize > 0 && w.size+int64(len(line)) > w.MaxSize {
+		if cause == nil {
+			cause = xerrors.Errorf("job store unavailable")
+		}
+		return xerrors.Errorf("write-ahead log is full: %w", cause)
+	}
+	if len(w.entries) == 0 {
+		log.WithError(cause).Warn("job store unavailable - keeping job updates in the write-ahead log until it's back")
+	}
+
+	_, err = w.f.Seek(w.size, io.SeekStart)
+	if err == nil {
+		_, err = w.f.Write(line)
+	}
+	if err == nil {
+		err = w.f.Sync()
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot write to write-ahead log: %w", err)
+	}
+	w.size += int64(len(line))
+	w.entries = append(w.entries, e)
+	if e.Job != nil {
+		w.pending[e.Job.Name] = *e.Job
+	}
+	return nil
+}
+
+// replayPeriodically replays the log every interval until the store is closed
+func (w *WALJobStore) replayPeriodically(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-t.C:
+		}
+
+		if !w.buffering() {
+			continue
+		}
+		err := w.Replay(context.Background())
+		if err != nil {
+			log.WithError(err).WithField("entries", w.Buffered()).Debug("job store still unavailable")
+		}
+	}
+}
+
+// Replay writes the updates in the log to the delegate, oldest first, until the log is empty or the delegate fails.
+// Updates the delegate rejects for good, or which failed MaxAttempts times for an unknown reason, are dead-lettered.
+func (w *WALJobStore) Replay(ctx context.Context) error {
+	w.replayMu.Lock()
+	defer w.replayMu.Unlock()
+
+	maxAttempts := w.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultWALMaxAttempts
+	}
+	for {
+		w.mu.Lock()
+		entries := make([]walEntry, len(w.entries))
+		copy(entries, w.entries)
+		w.mu.Unlock()
+		if len(entries) == 0 {
+			return nil
+		}
+
+		// only the latest status of a job matters
+		latest := make(map[string]int)
+		for i, e := range entries {
+			if e.Job != nil {
+				latest[e.Job.Name] = i
+			}
+		}
+
+		var (
+			done int
+			err  error
+		)
+		for i, e := range entries {
+			if e.Job != nil && latest[e.Job.Name] != i {
+				done++
+				continue
+			}
+			if e.Job != nil {
+				err = w.delegate.Store(ctx, *e.Job)
+			} else {
+				err = w.delegate.AddEvent(ctx, e.Name, *e.Event)
+			}
+			if err != nil && !isTransientStoreError(err) {
+				w.attempts++
+				if isPermanentStoreError(err) || w.attempts >= maxAttempts {
+					err = w.deadLetter(e, err)
+				}
+			}
+			if err != nil {
+				break
+			}
+			w.attempts = 0
+			done++
+		}
+
+		terr := w.truncate(done)
+		if terr != nil {
+			return terr
+		}
+		if err != nil {
+			return xerrors.Errorf("cannot replay job updates: %w", err)
+		}
+		if !w.buffering() {
+			log.WithField("entries", done).Info("job store is back - replayed the write-ahead log")
+		}
+	}
+}
+
+// deadLetter appends an update which cannot be replayed to the dead letter file, so that it no longer blocks the log
+func (w *WALJobStore) deadLetter(e walEntry, cause error) error {
+	line, err := e.marshalWithError(cause)
+	if err != nil {
+		return xerrors.Errorf("cannot serialize dead letter: %w", err)
+	}
+
+	fn := w.path + ".dead"
+	f, err := os.OpenFile(fn, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return xerrors.Errorf("cannot dead-letter job update: %w", err)
+	}
+	_, err = f.Write(line)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return xerrors.Errorf("cannot dead-letter job update: %w", err)
+	}
+
+	name := e.Name
+	if e.Job != nil {
+		name = e.Job.Name
+	}
+	log.WithError(cause).WithField("name", name).WithField("path", fn).Error("cannot replay job update - moved it to the dead letter file")
+
+	w.mu.Lock()
+	w.deadLettered++
+	w.mu.Unlock()
+	return nil
+}
+
+// isTransientStoreError returns true if an error of the delegate is likely to go away on its own, e.g. because the
+// database is unreachable or shutting down
+func isTransientStoreError(err error) bool {
+	if xerrors.Is(err, context.Canceled) || xerrors.Is(err, context.DeadlineExceeded) || xerrors.Is(err, driver.ErrBadConn) ||
+		xerrors.Is(err, io.EOF) || xerrors.Is(err, io.ErrUnexpectedEOF) ||
+		xerrors.Is(err, syscall.ECONNREFUSED) || xerrors.Is(err, syscall.ECONNRESET) || xerrors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if xerrors.As(err, &netErr) {
+		return true
+	}
+	var pqErr *pq.Error
+	if xerrors.As(err, &pqErr) {
+		switch pqErr.Code.Class() {
+		case "08", "40", "53", "57", "58":
+			// connection exception, transaction rollback, insufficient resources, operator intervention, system error
+			return true
+		}
+	}
+	return false
+}
+
+// isPermanentStoreError returns true if the delegate rejected an update for good, i.e. replaying it would fail the same way
+func isPermanentStoreError(err error) bool {
+	if xerrors.Is(err, ErrNotFound) {
+		return true
+	}
+	var pqErr *pq.Error
+	return xerrors.As(err, &pqErr) && !isTransientStoreError(err)
+}
+
+// truncate removes the n oldest updates from the log
+func (w *WALJobStore) truncate(n int) error {
+	if n == 0 {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.entries = w.entries[n:]
+	return w.rewrite()
+}
+
+// rewrite writes the entries to the log, replacing its content. The entries go to a temporary file which then replaces
+// the log, so that a crash leaves either the old or the new log behind, but never a partial one. Callers must hold mu.
+func (w *WALJobStore) rewrite() error {
+	var buf bytes.Buffer
+	w.pending = make(map[string]v1.JobStatus)
+	for _, e := range w.entries {
+		line, err := e.marshal()
+		if err != nil {
+			return xerrors.Errorf("cannot serialize job update: %w", err)
+		}
+		buf.Write(line)
+		if e.Job != nil {
+			w.pending[e.Job.Name] = *e.Job
+		}
+	}
+
+	tmp := w.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return xerrors.Errorf("cannot rewrite write-ahead log: %w", err)
+	}
+	_, err = f.Write(buf.Bytes())
+	if err == nil {
+		err = f.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp, w.path)
+	}
+	if err == nil {
+		err = syncDir(filepath.Dir(w.path))
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return xerrors.Errorf("cannot rewrite write-ahead log: %w", err)
+	}
+
+	// f is the log now
+	w.f.Close()
+	w.f = f
+	w.size = int64(buf.Len())
+	return nil
+}
+
+// syncDir makes sure a rename in the directory survives a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Close stops replaying the log periodically, tries to replay it one last time and closes it.
+// Updates which could not be replayed stay in the log for the next run.
+func (w *WALJobStore) Close() error {
+	w.stopOnce.Do(func() { close(w.stop) })
+	if w.buffering() {
+		err := w.Replay(context.Background())
+		if err != nil {
+			log.WithError(err).WithField("entries", w.Buffered()).Warn("job updates remain in the write-ahead log")
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}
+
+// Store stores a job in the delegate, or in the log if the delegate cannot store it
+func (w *WALJobStore) Store(ctx context.Context, job v1.JobStatus) error {
+	w.orderMu.Lock()
+	defer w.orderMu.Unlock()
+
+	var cause error
+	if !w.buffering() {
+		cause = w.delegate.Store(ctx, job)
+		if cause == nil {
+			return nil
+		}
+	}
+	return w.append(walEntry{Job: &job}, cause)
+}
+
+// AddEvent records an event in the delegate, or in the log if the delegate cannot record it
+func (w *WALJobStore) AddEvent(ctx context.Context, name string, evt v1.JobEvent) error {
+	w.orderMu.Lock()
+	defer w.orderMu.Unlock()
+
+	var cause error
+	if !w.buffering() {
+		cause = w.delegate.AddEvent(ctx, name, evt)
+		if cause == nil {
+			return nil
+		}
+	}
+	return w.append(walEntry{Name: name, Event: &evt}, cause)
+}
+
+// Get retrieves a particular job, including updates which wait in the log
+func (w *WALJobStore) Get(ctx context.Context, name string) (*v1.JobStatus, error) {
+	w.mu.Lock()
+	job, ok := w.pending[name]
+	w.mu.Unlock()
+	if ok {
+		return &job, nil
+	}
+
+	return w.delegate.Get(ctx, name)
+}
+
+// StoreJobSpec stores job YAML data.
+func (w *WALJobStore) StoreJobSpec(name string, data []byte) error {
+	return w.delegate.StoreJobSpec(name, data)
+}
+
+// GetJobSpec retrieves previously stored job spec data
+func (w *WALJobStore) GetJobSpec(name string) (data []byte, err error) {
+	return w.delegate.GetJobSpec(name)
+}
+
+// StoreResolvedSpec stores the resolved job spec
+func (w *WALJobStore) StoreResolvedSpec(name string, data []byte) error {
+	return w.delegate.StoreResolvedSpec(name, data)
+}
+
+// GetResolvedSpec retrieves a previously stored resolved job spec
+func (w *WALJobStore) GetResolvedSpec(name string) (data []byte, err error) {
+	return w.delegate.GetResolvedSpec(name)
+}
+
+// StoreProvenance stores the signed provenance of a job
+func (w *WALJobStore) StoreProvenance(name string, data []byte) error {
+	return w.delegate.StoreProvenance(name, data)
+}
+
+// GetProvenance retrieves the signed provenance of a job
+func (w *WALJobStore) GetProvenance(name string) (data []byte, err error) {
+	return w.delegate.GetProvenance(name)
+}
+
+// StoreTriggerPayload stores what triggered a job
+func (w *WALJobStore) StoreTriggerPayload(name string, data []byte) error {
+	return w.delegate.StoreTriggerPayload(name, data)
+}
+
+// GetTriggerPayload retrieves what triggered a job
+func (w *WALJobStore) GetTriggerPayload(name string) (data []byte, err error) {
+	return w.delegate.GetTriggerPayload(name)
+}
+
+// StoreSBOM stores the software bill of materials of an image a job built
+func (w *WALJobStore) StoreSBOM(name, image string, data []byte) error {
+	return w.delegate.StoreSBOM(name, image, data)
+}
+
+// GetSBOMs retrieves the software bills of materials of the images a job built
+func (w *WALJobStore) GetSBOMs(name string) (sboms map[string][]byte, err error) {
+	return w.delegate.GetSBOMs(name)
+}
+
+// GetEvents returns the events of a job, oldest first
+func (w *WALJobStore) GetEvents(ctx context.Context, name string) ([]v1.JobEvent, error) {
+	return w.delegate.GetEvents(ctx, name)
+}
+
+// Find searches for jobs in the delegate store
+func (w *WALJobStore) Find(ctx context.Context, filter []*v1.FilterExpression, order []*v1.OrderExpression, start, limit int) (slice []v1.JobStatus, total int, err error) {
+	return w.delegate.Find(ctx, filter, order, start, limit)
+}
+
+// Delete replays the log and removes a job from the delegate store
+func (w *WALJobStore) Delete(ctx context.Context, name string) error {
+	err := w.Replay(ctx)
+	if err != nil {
+		return err
+	}
+	return w.delegate.Delete(ctx, name)
+}
+
+// Purge replays the log and purges a job from the delegate store
+func (w *WALJobStore) Purge(ctx context.Context, name string) error {
+	err := w.Replay(ctx)
+	if err != nil {
+		return err
+	}
+	return w.delegate.Purge(ctx, name)
+}
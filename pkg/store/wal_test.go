@@ -0,0 +1,308 @@
+package store_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"golang.org/x/xerrors"
+)
+
+// unavailableJobs is a job store which fails all writes while it's down, like a database during an outage
+type unavailableJobs struct {
+	store.Jobs
+
+	mu   sync.Mutex
+	down bool
+}
+
+func (u *unavailableJobs) SetDown(down bool) {
+	u.mu.Lock()
+	u.down = down
+	u.mu.Unlock()
+}
+
+func (u *unavailableJobs) err() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.down {
+		return xerrors.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (u *unavailableJobs) Store(ctx context.Context, job v1.JobStatus) error {
+	if err := u.err(); err != nil {
+		return err
+	}
+	return u.Jobs.Store(ctx, job)
+}
+
+func (u *unavailableJobs) AddEvent(ctx context.Context, name string, evt v1.JobEvent) error {
+	if err := u.err(); err != nil {
+		return err
+	}
+	return u.Jobs.AddEvent(ctx, name, evt)
+}
+
+func TestWALJobStore(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "twal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+	fn := filepath.Join(base, "jobs.wal")
+
+	ctx := context.Background()
+	delegate := &unavailableJobs{Jobs: store.NewInMemoryJobStore()}
+	jobs, err := store.NewWALJobStore(delegate, fn, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = jobs.Store(ctx, v1.JobStatus{Name: "foo.1", Phase: v1.JobPhase_PHASE_PREPARING, Metadata: &v1.JobMetadata{}, Conditions: &v1.JobConditions{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegate.SetDown(true)
+	for _, phase := range []v1.JobPhase{v1.JobPhase_PHASE_RUNNING, v1.JobPhase_PHASE_DONE} {
+		err = jobs.Store(ctx, v1.JobStatus{Name: "foo.1", Phase: phase, Metadata: &v1.JobMetadata{}, Conditions: &v1.JobConditions{}})
+		if err != nil {
+			t.Fatalf("expected store to buffer the update, got %v", err)
+		}
+		err = jobs.AddEvent(ctx, "foo.1", v1.JobEvent{Type: v1.JobEventType_EVENT_PHASE_CHANGED, Phase: phase})
+		if err != nil {
+			t.Fatalf("expected store to buffer the event, got %v", err)
+		}
+	}
+	if n := jobs.Buffered(); n != 4 {
+		t.Errorf("expected 4 buffered updates, got %d", n)
+	}
+	job, err := jobs.Get(ctx, "foo.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Phase != v1.JobPhase_PHASE_DONE {
+		t.Errorf("expected Get to return the buffered update, got phase %v", job.Phase)
+	}
+	if err := jobs.Replay(ctx); err == nil {
+		t.Errorf("expected replay to fail while the delegate is down")
+	}
+
+	// the log survives a restart of werft
+	err = jobs.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs, err = store.NewWALJobStore(delegate, fn, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jobs.Close()
+	// the failed replay dropped the running status, which the done status supersedes
+	if n := jobs.Buffered(); n != 3 {
+		t.Errorf("expected 3 buffered updates after reopening the log, got %d", n)
+	}
+
+	delegate.SetDown(false)
+	err = jobs.Replay(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := jobs.Buffered(); n != 0 {
+		t.Errorf("expected no buffered updates after replay, got %d", n)
+	}
+	job, err = delegate.Get(ctx, "foo.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Phase != v1.JobPhase_PHASE_DONE {
+		t.Errorf("expected the delegate to have the latest update, got phase %v", job.Phase)
+	}
+	evts, err := delegate.GetEvents(ctx, "foo.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(evts) != 2 {
+		t.Errorf("expected the delegate to have 2 events, got %v", evts)
+	}
+	if fi, err := os.Stat(fn); err != nil || fi.Size() != 0 {
+		t.Errorf("expected the log to be empty after replay, got %v (%v)", fi, err)
+	}
+}
+
+func TestWALJobStoreFull(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "twal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+
+	delegate := &unavailableJobs{Jobs: store.NewInMemoryJobStore(), down: true}
+	jobs, err := store.NewWALJobStore(delegate, filepath.Join(base, "jobs.wal"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jobs.Close()
+	jobs.MaxSize = 100
+
+	ctx := context.Background()
+	err = jobs.Store(ctx, v1.JobStatus{Name: "foo.1", Metadata: &v1.JobMetadata{Owner: "someone"}})
+	if err != nil {
+		t.Fatalf("expected first update to be buffered, got %v", err)
+	}
+	err = jobs.Store(ctx, v1.JobStatus{Name: "foo.1", Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{Owner: "someone"}})
+	if err == nil {
+		t.Errorf("expected an error once the log is full")
+	}
+}
+
+// rejectingJobs is a job store which fails to store particular jobs with an error of their own
+type rejectingJobs struct {
+	store.Jobs
+
+	mu     sync.Mutex
+	errors map[string]error
+}
+
+func (r *rejectingJobs) Store(ctx context.Context, job v1.JobStatus) error {
+	r.mu.Lock()
+	err := r.errors[job.Name]
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return r.Jobs.Store(ctx, job)
+}
+
+func TestWALJobStoreDeadLetters(t *testing.T) {
+	tests := []struct {
+		Name         string
+		Error        error
+		Replays      int
+		DeadLettered bool
+	}{
+		{"permanent", xerrors.Errorf("cannot store job: %w", store.ErrNotFound), 1, true},
+		{"unknown once", xerrors.Errorf("something went wrong"), 1, false},
+		{"unknown too often", xerrors.Errorf("something went wrong"), 2, true},
+		{"transient", xerrors.Errorf("cannot store job: %w", syscall.ECONNREFUSED), 5, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			base, err := ioutil.TempDir(os.TempDir(), "twal")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(base)
+			fn := filepath.Join(base, "jobs.wal")
+
+			ctx := context.Background()
+			delegate := &rejectingJobs{Jobs: store.NewInMemoryJobStore(), errors: map[string]error{"foo.1": test.Error}}
+			jobs, err := store.NewWALJobStore(delegate, fn, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer jobs.Close()
+			jobs.MaxAttempts = 2
+
+			// foo.1 goes to the log because the delegate rejects it, and bar.1 follows it there
+			for _, name := range []string{"foo.1", "bar.1"} {
+				err = jobs.Store(ctx, v1.JobStatus{Name: name, Metadata: &v1.JobMetadata{}, Conditions: &v1.JobConditions{}})
+				if err != nil {
+					t.Fatalf("expected store to buffer the update, got %v", err)
+				}
+			}
+			for i := 0; i < test.Replays; i++ {
+				jobs.Replay(ctx)
+			}
+
+			if act := jobs.DeadLettered() == 1; act != test.DeadLettered {
+				t.Fatalf("expected the update to be dead-lettered: %v, got %d dead letters", test.DeadLettered, jobs.DeadLettered())
+			}
+			if !test.DeadLettered {
+				if n := jobs.Buffered(); n != 2 {
+					t.Errorf("expected both updates to stay in the log, got %d", n)
+				}
+				return
+			}
+
+			if n := jobs.Buffered(); n != 0 {
+				t.Errorf("expected the log to be empty, got %d updates", n)
+			}
+			if _, err := delegate.Get(ctx, "bar.1"); err != nil {
+				t.Errorf("expected the update after the dead letter to be replayed: %v", err)
+			}
+			fc, err := ioutil.ReadFile(fn + ".dead")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(fc), `"foo.1"`) || !strings.Contains(string(fc), test.Error.Error()) {
+				t.Errorf("expected the dead letter file to contain the update and its error, got %s", fc)
+			}
+			if _, err := os.Stat(fn + ".tmp"); !os.IsNotExist(err) {
+				t.Errorf("expected no temporary log to be left behind, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWALJobStoreRewrite(t *testing.T) {
+	base, err := ioutil.TempDir(os.TempDir(), "twal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(base)
+	fn := filepath.Join(base, "jobs.wal")
+
+	ctx := context.Background()
+	delegate := &unavailableJobs{Jobs: store.NewInMemoryJobStore(), down: true}
+	jobs, err := store.NewWALJobStore(delegate, fn, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"foo.1", "foo.2"} {
+		err = jobs.Store(ctx, v1.JobStatus{Name: name, Metadata: &v1.JobMetadata{}, Conditions: &v1.JobConditions{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// an incomplete last line, as if werft crashed while appending
+	f, err := os.OpenFile(fn, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"job":{"name":"foo`)
+	f.Close()
+	jobs.Close()
+
+	// reopening the log rewrites it without the incomplete line, updates appended afterwards must survive as well
+	jobs, err = store.NewWALJobStore(delegate, fn, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = jobs.Store(ctx, v1.JobStatus{Name: "foo.3", Metadata: &v1.JobMetadata{}, Conditions: &v1.JobConditions{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs.Close()
+
+	jobs, err = store.NewWALJobStore(delegate, fn, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer jobs.Close()
+	if n := jobs.Buffered(); n != 3 {
+		t.Errorf("expected 3 buffered updates, got %d", n)
+	}
+	if _, err := os.Stat(fn + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("expected no temporary log to be left behind, got %v", err)
+	}
+}
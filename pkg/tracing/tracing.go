@@ -0,0 +1,91 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/xerrors"
+)
+
+const tracerName = "github.com/32leaves/werft"
+
+// Config configures the export of OpenTelemetry traces
+type Config struct {
+	// Endpoint is the host:port of the OTLP/HTTP collector spans are sent to.
+	// If empty, tracing is disabled.
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// Insecure disables TLS when talking to the collector
+	Insecure bool `yaml:"insecure,omitempty"`
+
+	// Headers are sent with every export request, e.g. for authentication
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// SampleRatio is the fraction of traces we record. Defaults to 1, i.e. all traces are recorded.
+	SampleRatio *float64 `yaml:"sampleRatio,omitempty"`
+}
+
+// Init sets up the global tracer provider. If no endpoint is configured, spans are discarded.
+// Call shutdown when the server stops to flush all pending spans.
+func Init(cfg Config) (shutdown func(context.Context) error, err error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(cfg.Endpoint),
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create OTLP exporter: %w", err)
+	}
+
+	ratio := 1.0
+	if cfg.SampleRatio != nil {
+		ratio = *cfg.SampleRatio
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("werft"))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer used throughout werft
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Start starts a new span as child of whatever span is present in ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// FinishSpan records the error (if any) and ends the span. Use with defer and a named error return value:
+//
+//	defer tracing.FinishSpan(span, &err)
+func FinishSpan(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
@@ -0,0 +1,352 @@
+package websecurity
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// TokenCookie holds the short-lived session token which authenticates API calls
+	TokenCookie = "werft_token"
+	// SessionCookie holds the session ID which refreshes the session token. It's only sent to /auth/.
+	SessionCookie = "werft_session"
+
+	// PermissionAdmin lets a user perform admin actions
+	PermissionAdmin = "admin"
+
+	defaultTokenTTL   = 15 * time.Minute
+	defaultSessionTTL = 12 * time.Hour
+)
+
+// SessionConfig configures session-based login to the web UI. Werft does not authenticate users itself: it trusts
+// the identity the authenticating proxy in front of it (e.g. Vouch or oauth2-proxy) passes in a header, and keeps
+// a session for it.
+type SessionConfig struct {
+	// IdentityHeader carries the user the proxy authenticated, e.g. X-Vouch-User or X-Forwarded-Email
+	IdentityHeader string `yaml:"identityHeader"`
+
+	// SigningKeyPath points to a file containing the key session tokens are signed with. Without it werft generates
+	// a key when it starts.
+	SigningKeyPath string `yaml:"signingKeyPath,omitempty"`
+
+	// TokenTTL is the time a session token is valid for before it must be refreshed. Defaults to 15 minutes.
+	TokenTTL *executor.Duration `yaml:"tokenTTL,omitempty"`
+
+	// SessionTTL is the time a session can be refreshed for before users must log in again. Defaults to 12 hours.
+	SessionTTL *executor.Duration `yaml:"sessionTTL,omitempty"`
+
+	// Admins are the users who have the admin permission
+	Admins []string `yaml:"admins,omitempty"`
+}
+
+// TokenGenerator produces secret tokens which cannot be guessed, e.g. session IDs. Tests replace the token
+// generator of sessions to know the tokens in advance.
+type TokenGenerator interface {
+	NewToken() (string, error)
+}
+
+// RandomTokens produces tokens of 32 random bytes, hex encoded
+type RandomTokens struct{}
+
+// NewToken returns a random token
+func (RandomTokens) NewToken() (string, error) {
+	tkn := make([]byte, 32)
+	_, err := rand.Read(tkn)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(tkn), nil
+}
+
+// Identity is the user a session belongs to
+type Identity struct {
+	User        string
+	Permissions []string
+	// Expires is the time the session token expires
+	Expires time.Time
+}
+
+// Sessions keeps the sessions of web UI users. Sessions live in memory, so that users log in again after a restart.
+type Sessions struct {
+	cfg        SessionConfig
+	key        []byte
+	tokenTTL   time.Duration
+	sessionTTL time.Duration
+
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+	// Tokens produces the session IDs. Defaults to RandomTokens.
+	Tokens TokenGenerator
+
+	mu       sync.Mutex
+	sessions map[string]session
+}
+
+type session struct {
+	User    string
+	Expires time.Time
+}
+
+// sessionToken is the signed content of the token cookie
+type sessionToken struct {
+	Session string `json:"sid"`
+	User    string `json:"sub"`
+	Expires int64  `json:"exp"`
+}
+
+// NewSessions creates a session manager
+func NewSessions(cfg SessionConfig) (*Sessions, error) {
+	if cfg.IdentityHeader == "" {
+		return nil, xerrors.Errorf("sessions: identityHeader is required")
+	}
+
+	var key []byte
+	if cfg.SigningKeyPath != "" {
+		fc, err := ioutil.ReadFile(cfg.SigningKeyPath)
+		if err != nil {
+			return nil, xerrors.Errorf("sessions: cannot read signing key: %w", err)
+		}
+		key = []byte(strings.TrimSpace(string(fc)))
+		if len(key) < 32 {
+			return nil, xerrors.Errorf("sessions: signing key must be at least 32 bytes long")
+		}
+	} else {
+		key = make([]byte, 32)
+		_, err := rand.Read(key)
+		if err != nil {
+			return nil, xerrors.Errorf("sessions: cannot generate signing key: %w", err)
+		}
+	}
+
+	s := &Sessions{
+		cfg:        cfg,
+		key:        key,
+		tokenTTL:   defaultTokenTTL,
+		sessionTTL: defaultSessionTTL,
+		Now:        time.Now,
+		Tokens:     RandomTokens{},
+		sessions:   make(map[string]session),
+	}
+	if cfg.TokenTTL != nil {
+		s.tokenTTL = cfg.TokenTTL.Duration
+	}
+	if cfg.SessionTTL != nil {
+		s.sessionTTL = cfg.SessionTTL.Duration
+	}
+	if s.tokenTTL <= 0 || s.sessionTTL < s.tokenTTL {
+		return nil, xerrors.Errorf("sessions: tokenTTL must be positive and no longer than sessionTTL")
+	}
+	return s, nil
+}
+
+// Handler serves the login, refresh and logout endpoints below /auth/
+func (s *Sessions) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/login", s.handleLogin)
+	mux.HandleFunc("/auth/refresh", s.handleRefresh)
+	mux.HandleFunc("/auth/logout", s.handleLogout)
+	return mux
+}
+
+// handleLogin starts a session for the user the proxy authenticated
+func (s *Sessions) handleLogin(w http.ResponseWriter, r *http.Request) {
+	user := strings.TrimSpace(r.Header.Get(s.cfg.IdentityHeader))
+	if user == "" {
+		http.Error(w, "not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	sid, err := s.Tokens.NewToken()
+	if err != nil {
+		http.Error(w, "cannot start session", http.StatusInternalServerError)
+		return
+	}
+	now := s.Now()
+
+	s.mu.Lock()
+	s.expireSessions(now)
+	s.sessions[sid] = session{User: user, Expires: now.Add(s.sessionTTL)}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    sid,
+		Path:     "/auth/",
+		Expires:  now.Add(s.sessionTTL),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	s.writeToken(w, sid, user, now)
+}
+
+// handleRefresh issues a new session token as long as the session is alive
+func (s *Sessions) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	c, err := r.Cookie(SessionCookie)
+	if err != nil {
+		http.Error(w, "no session", http.StatusUnauthorized)
+		return
+	}
+	now := s.Now()
+
+	s.mu.Lock()
+	s.expireSessions(now)
+	sess, ok := s.sessions[c.Value]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "session expired", http.StatusUnauthorized)
+		return
+	}
+	s.writeToken(w, c.Value, sess.User, now)
+}
+
+// handleLogout ends the session. Tokens issued for it are no longer accepted.
+func (s *Sessions) handleLogout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if c, err := r.Cookie(SessionCookie); err == nil {
+		s.mu.Lock()
+		delete(s.sessions, c.Value)
+		s.mu.Unlock()
+	}
+
+	http.SetCookie(w, &http.Cookie{Name: SessionCookie, Path: "/auth/", MaxAge: -1, HttpOnly: true, Secure: true, SameSite: http.SameSiteStrictMode})
+	http.SetCookie(w, &http.Cookie{Name: TokenCookie, Path: "/", MaxAge: -1, HttpOnly: true, Secure: true, SameSite: http.SameSiteStrictMode})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeToken sets the token cookie and answers with the identity the token carries
+func (s *Sessions) writeToken(w http.ResponseWriter, sid, user string, now time.Time) {
+	exp := now.Add(s.tokenTTL)
+	token, err := s.sign(sessionToken{Session: sid, User: user, Expires: exp.Unix()})
+	if err != nil {
+		http.Error(w, "cannot issue session token", http.StatusInternalServerError)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     TokenCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  exp,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		User        string   `json:"user"`
+		Permissions []string `json:"permissions"`
+		Expires     string   `json:"expires"`
+	}{user, s.permissions(user), exp.UTC().Format(time.RFC3339)})
+}
+
+// expireSessions forgets all sessions which expired. Callers must hold s.mu.
+func (s *Sessions) expireSessions(now time.Time) {
+	for sid, sess := range s.sessions {
+		if now.After(sess.Expires) {
+			delete(s.sessions, sid)
+		}
+	}
+}
+
+// Identity returns the identity a session token belongs to. It fails if the token is invalid or expired, or the
+// session has ended.
+func (s *Sessions) Identity(token string) (*Identity, error) {
+	t, err := s.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	now := s.Now()
+	exp := time.Unix(t.Expires, 0)
+	if now.After(exp) {
+		return nil, xerrors.Errorf("session token expired")
+	}
+
+	s.mu.Lock()
+	sess, ok := s.sessions[t.Session]
+	s.mu.Unlock()
+	if !ok || now.After(sess.Expires) || sess.User != t.User {
+		return nil, xerrors.Errorf("session ended")
+	}
+	return &Identity{User: t.User, Permissions: s.permissions(t.User), Expires: exp}, nil
+}
+
+// IdentityFromCookies returns the identity of the session token in a Cookie header
+func (s *Sessions) IdentityFromCookies(cookieHeader ...string) (*Identity, error) {
+	r := http.Request{Header: http.Header{"Cookie": cookieHeader}}
+	c, err := r.Cookie(TokenCookie)
+	if err != nil {
+		return nil, xerrors.Errorf("no session token")
+	}
+	return s.Identity(c.Value)
+}
+
+// permissions returns the permissions of a user
+func (s *Sessions) permissions(user string) []string {
+	for _, a := range s.cfg.Admins {
+		if a == user {
+			return []string{PermissionAdmin}
+		}
+	}
+	return []string{}
+}
+
+// sign produces a token of the form payload.signature, both base64 encoded
+func (s *Sessions) sign(t sessionToken) (string, error) {
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(mac.Sum(nil)), nil
+}
+
+// verify checks the signature of a token and returns its content
+func (s *Sessions) verify(token string) (*sessionToken, error) {
+	segs := strings.Split(token, ".")
+	if len(segs) != 2 {
+		return nil, xerrors.Errorf("invalid session token")
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(segs[0])
+	if err != nil {
+		return nil, xerrors.Errorf("invalid session token")
+	}
+	sig, err := enc.DecodeString(segs[1])
+	if err != nil {
+		return nil, xerrors.Errorf("invalid session token")
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return nil, xerrors.Errorf("invalid session token")
+	}
+
+	var t sessionToken
+	err = json.Unmarshal(payload, &t)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid session token")
+	}
+	return &t, nil
+}
@@ -0,0 +1,156 @@
+package websecurity_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/websecurity"
+)
+
+func TestSessions(t *testing.T) {
+	sessions, err := websecurity.NewSessions(websecurity.SessionConfig{
+		IdentityHeader: "X-Forwarded-Email",
+		Admins:         []string{"admin@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+	sessions.Now = func() time.Time { return now }
+	handler := sessions.Handler()
+
+	do := func(method, path, user string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if user != "" {
+			req.Header.Set("X-Forwarded-Email", user)
+		}
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	cookie := func(rec *httptest.ResponseRecorder, name string) *http.Cookie {
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == name {
+				return c
+			}
+		}
+		t.Fatalf("response has no %s cookie", name)
+		return nil
+	}
+
+	if rec := do(http.MethodGet, "/auth/login", "", nil); rec.Code != http.StatusUnauthorized {
+		t.Errorf("login without identity: expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	rec := do(http.MethodGet, "/auth/login", "admin@example.com", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	token, sid := cookie(rec, websecurity.TokenCookie), cookie(rec, websecurity.SessionCookie)
+	if !token.HttpOnly || !token.Secure || !sid.HttpOnly || sid.Path != "/auth/" {
+		t.Errorf("session cookies are not restricted: %v, %v", token, sid)
+	}
+
+	id, err := sessions.IdentityFromCookies(token.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.User != "admin@example.com" || len(id.Permissions) != 1 || id.Permissions[0] != websecurity.PermissionAdmin {
+		t.Errorf("unexpected identity: %+v", id)
+	}
+
+	if _, err := sessions.Identity(token.Value + "x"); err == nil {
+		t.Error("tampered token was accepted")
+	}
+	segs := strings.Split(token.Value, ".")
+	if _, err := sessions.Identity("eyJzaWQiOiJ4Iiwic3ViIjoiZXZlIiwiZXhwIjo5OTk5OTk5OTk5fQ." + segs[1]); err == nil {
+		t.Error("token with a foreign payload was accepted")
+	}
+
+	now = now.Add(20 * time.Minute)
+	if _, err := sessions.Identity(token.Value); err == nil {
+		t.Error("expired token was accepted")
+	}
+	if rec := do(http.MethodGet, "/auth/refresh", "", []*http.Cookie{sid}); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("refresh using GET: expected status %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+	rec = do(http.MethodPost, "/auth/refresh", "", []*http.Cookie{sid})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("refresh: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	token = cookie(rec, websecurity.TokenCookie)
+	if _, err := sessions.Identity(token.Value); err != nil {
+		t.Errorf("refreshed token was rejected: %v", err)
+	}
+
+	if rec := do(http.MethodPost, "/auth/logout", "", []*http.Cookie{sid}); rec.Code != http.StatusNoContent {
+		t.Fatalf("logout: expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	if _, err := sessions.Identity(token.Value); err == nil {
+		t.Error("token of an ended session was accepted")
+	}
+	if rec := do(http.MethodPost, "/auth/refresh", "", []*http.Cookie{sid}); rec.Code != http.StatusUnauthorized {
+		t.Errorf("refresh after logout: expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestSessionsExpire(t *testing.T) {
+	sessions, err := websecurity.NewSessions(websecurity.SessionConfig{IdentityHeader: "X-Forwarded-Email"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2020, time.January, 1, 12, 0, 0, 0, time.UTC)
+	sessions.Now = func() time.Time { return now }
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/login", nil)
+	req.Header.Set("X-Forwarded-Email", "user@example.com")
+	rec := httptest.NewRecorder()
+	sessions.Handler().ServeHTTP(rec, req)
+
+	now = now.Add(13 * time.Hour)
+	req = httptest.NewRequest(http.MethodPost, "/auth/refresh", nil)
+	for _, c := range rec.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	rec = httptest.NewRecorder()
+	sessions.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("refresh of an expired session: expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+type fixedTokens string
+
+func (t fixedTokens) NewToken() (string, error) { return string(t), nil }
+
+func TestSessionsUseTokenGenerator(t *testing.T) {
+	sessions, err := websecurity.NewSessions(websecurity.SessionConfig{IdentityHeader: "X-Forwarded-Email"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions.Tokens = fixedTokens("session-1")
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/login", nil)
+	req.Header.Set("X-Forwarded-Email", "user@example.com")
+	rec := httptest.NewRecorder()
+	sessions.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("login: expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var sid string
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == websecurity.SessionCookie {
+			sid = c.Value
+		}
+	}
+	if sid != "session-1" {
+		t.Errorf("expected session ID session-1, got %q", sid)
+	}
+}
@@ -0,0 +1,129 @@
+package websecurity
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// DefaultContentSecurityPolicy allows the web UI to load its own scripts and styles, the fonts it uses, and images from anywhere
+const DefaultContentSecurityPolicy = "default-src 'self'; script-src 'self'; " +
+	"style-src 'self' 'unsafe-inline' https://fonts.googleapis.com; font-src 'self' https://fonts.gstatic.com data:; " +
+	"img-src 'self' data: https:; connect-src 'self'; object-src 'none'; base-uri 'self'; frame-ancestors 'none'"
+
+// Config configures the headers the web server sends, and which other sites may call the API from browsers
+type Config struct {
+	// AllowedOrigins may call the API from browsers, e.g. https://dashboard.example.com. Origins can contain a wildcard
+	// for subdomains, e.g. https://*.example.com, and * allows all origins. By default only the web UI itself may call the API.
+	AllowedOrigins []string `yaml:"allowedOrigins,omitempty"`
+
+	// ContentSecurityPolicy is sent with all responses. Defaults to DefaultContentSecurityPolicy.
+	ContentSecurityPolicy string `yaml:"contentSecurityPolicy,omitempty"`
+
+	// Headers are sent with all responses in addition to the security headers, or replace them. Headers set to
+	// an empty value are not sent, e.g. to disable HSTS.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Sessions enables session-based login to the web UI
+	Sessions *SessionConfig `yaml:"sessions,omitempty"`
+}
+
+// Validate checks the allowed origins
+func (c Config) Validate() error {
+	for _, o := range c.AllowedOrigins {
+		if o == "*" {
+			continue
+		}
+		u, err := url.Parse(o)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			return xerrors.Errorf("invalid allowed origin %s: must be scheme://host[:port], e.g. https://dashboard.example.com", o)
+		}
+		if strings.Contains(strings.TrimPrefix(u.Host, "*."), "*") {
+			return xerrors.Errorf("invalid allowed origin %s: wildcards are only allowed for subdomains, e.g. https://*.example.com", o)
+		}
+	}
+	if c.Sessions != nil && c.Sessions.IdentityHeader == "" {
+		return xerrors.Errorf("sessions: identityHeader is required")
+	}
+	return nil
+}
+
+// OriginAllowed returns true if origin may call the API
+func (c Config) OriginAllowed(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	origin = strings.ToLower(origin)
+	for _, o := range c.AllowedOrigins {
+		o = strings.ToLower(strings.TrimSuffix(o, "/"))
+		if o == "*" || o == origin {
+			return true
+		}
+
+		// https://*.example.com matches https://foo.example.com and https://foo.bar.example.com, but not https://example.com
+		idx := strings.Index(o, "://*.")
+		if idx < 0 {
+			continue
+		}
+		scheme, domain := o[:idx+3], o[idx+4:]
+		if strings.HasPrefix(origin, scheme) && strings.HasSuffix(origin, domain) && len(origin) > len(scheme)+len(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handler returns a handler which sets the security headers on all responses of next
+func (c Config) Handler(next http.Handler) http.Handler {
+	csp := c.ContentSecurityPolicy
+	if csp == "" {
+		csp = DefaultContentSecurityPolicy
+	}
+	hdr := map[string]string{
+		"Content-Security-Policy":   csp,
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains; preload",
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+	}
+	for k, v := range c.Headers {
+		hdr[http.CanonicalHeaderKey(k)] = v
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range hdr {
+			if v == "" {
+				continue
+			}
+			w.Header().Set(k, v)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// CORS returns a handler which lets the allowed origins call next from browsers. It answers preflight requests itself.
+func (c Config) CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+		if !c.OriginAllowed(origin) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			if hdr := r.Header.Get("Access-Control-Request-Headers"); hdr != "" {
+				w.Header().Set("Access-Control-Allow-Headers", hdr)
+			}
+			w.Header().Set("Access-Control-Max-Age", "600")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
+		next.ServeHTTP(w, r)
+	})
+}
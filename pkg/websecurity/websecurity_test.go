@@ -0,0 +1,149 @@
+package websecurity_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/websecurity"
+)
+
+func TestOriginAllowed(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Allowed []string
+		Origin  string
+		Exp     bool
+	}{
+		{"no origins", nil, "https://dashboard.example.com", false},
+		{"no origin", []string{"*"}, "", false},
+		{"any origin", []string{"*"}, "https://dashboard.example.com", true},
+		{"exact match", []string{"https://dashboard.example.com"}, "https://dashboard.example.com", true},
+		{"case insensitive", []string{"https://Dashboard.example.com/"}, "https://dashboard.example.com", true},
+		{"other scheme", []string{"https://dashboard.example.com"}, "http://dashboard.example.com", false},
+		{"other port", []string{"https://dashboard.example.com"}, "https://dashboard.example.com:8443", false},
+		{"subdomain wildcard", []string{"https://*.example.com"}, "https://a.b.example.com", true},
+		{"wildcard excludes domain", []string{"https://*.example.com"}, "https://example.com", false},
+		{"wildcard suffix", []string{"https://*.example.com"}, "https://evilexample.com", false},
+		{"wildcard scheme", []string{"https://*.example.com"}, "http://a.example.com", false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := websecurity.Config{AllowedOrigins: test.Allowed}
+			if err := cfg.Validate(); err != nil {
+				t.Fatalf("unexpected validation error: %v", err)
+			}
+			act := cfg.OriginAllowed(test.Origin)
+			if act != test.Exp {
+				t.Errorf("OriginAllowed(%q) = %v, expected %v", test.Origin, act, test.Exp)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		Origin string
+		Valid  bool
+	}{
+		{"https://dashboard.example.com", true},
+		{"http://localhost:3000", true},
+		{"https://*.example.com", true},
+		{"dashboard.example.com", false},
+		{"https://dashboard.example.com/path", false},
+		{"https://dash*.example.com", false},
+	}
+	for _, test := range tests {
+		err := websecurity.Config{AllowedOrigins: []string{test.Origin}}.Validate()
+		if (err == nil) != test.Valid {
+			t.Errorf("Validate(%q) = %v, expected valid: %v", test.Origin, err, test.Valid)
+		}
+	}
+}
+
+func TestHandlers(t *testing.T) {
+	cfg := websecurity.Config{
+		AllowedOrigins: []string{"https://dashboard.example.com"},
+		Headers: map[string]string{
+			"strict-transport-security": "",
+			"X-Custom":                  "foo",
+		},
+	}
+	h := cfg.Handler(cfg.CORS(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})))
+
+	tests := []struct {
+		Name    string
+		Method  string
+		Origin  string
+		Status  int
+		Headers map[string]string
+	}{
+		{
+			Name:   "security headers",
+			Method: http.MethodGet,
+			Status: http.StatusOK,
+			Headers: map[string]string{
+				"Content-Security-Policy":      websecurity.DefaultContentSecurityPolicy,
+				"X-Frame-Options":              "DENY",
+				"X-Custom":                     "foo",
+				"Strict-Transport-Security":    "",
+				"Access-Control-Allow-Origin":  "",
+				"Access-Control-Allow-Methods": "",
+			},
+		},
+		{
+			Name:   "allowed origin",
+			Method: http.MethodGet,
+			Origin: "https://dashboard.example.com",
+			Status: http.StatusOK,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin": "https://dashboard.example.com",
+			},
+		},
+		{
+			Name:   "preflight",
+			Method: http.MethodOptions,
+			Origin: "https://dashboard.example.com",
+			Status: http.StatusNoContent,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin":  "https://dashboard.example.com",
+				"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
+				"Access-Control-Allow-Headers": "authorization",
+			},
+		},
+		{
+			Name:   "other origin",
+			Method: http.MethodOptions,
+			Origin: "https://evil.example.com",
+			Status: http.StatusOK,
+			Headers: map[string]string{
+				"Access-Control-Allow-Origin": "",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(test.Method, "/api/version", nil)
+			if test.Origin != "" {
+				req.Header.Set("Origin", test.Origin)
+			}
+			if test.Method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+				req.Header.Set("Access-Control-Request-Headers", "authorization")
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Errorf("unexpected status %d, expected %d", rec.Code, test.Status)
+			}
+			for k, v := range test.Headers {
+				if act := rec.Header().Get(k); act != v {
+					t.Errorf("unexpected %s header %q, expected %q", k, act, v)
+				}
+			}
+		})
+	}
+}
@@ -769,6 +769,9 @@ export interface JobTriggerMap {
   TRIGGER_MANUAL: 1;
   TRIGGER_PUSH: 2;
   TRIGGER_DELETED: 3;
+  TRIGGER_TAG: 4;
+  TRIGGER_PULL_REQUEST: 5;
+  TRIGGER_SCHEDULED: 6;
 }
 
 export const JobTrigger: JobTriggerMap;
@@ -5567,7 +5567,10 @@ proto.v1.JobTrigger = {
   TRIGGER_UNKNOWN: 0,
   TRIGGER_MANUAL: 1,
   TRIGGER_PUSH: 2,
-  TRIGGER_DELETED: 3
+  TRIGGER_DELETED: 3,
+  TRIGGER_TAG: 4,
+  TRIGGER_PULL_REQUEST: 5,
+  TRIGGER_SCHEDULED: 6
 };
 
 /**
@@ -0,0 +1,37 @@
+package werft
+
+import (
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// MaxAnnotationKeySize is the length an annotation key can have at most
+	MaxAnnotationKeySize = 255
+
+	// MaxAnnotationValueSize is the size a single annotation value can have at most
+	MaxAnnotationValueSize = 64 * 1024
+
+	// MaxAnnotationsSize is the size all annotations of a job can have together at most. Job metadata is compressed
+	// in the job's pod, which lets jobs have more annotations than Kubernetes accepts for a pod.
+	MaxAnnotationsSize = 1024 * 1024
+)
+
+// ValidateAnnotations checks that the annotations of a job stay within the size limits werft supports, so that
+// jobs with too large annotations are rejected with an explicit error before werft tries to run them.
+func ValidateAnnotations(md *v1.JobMetadata) error {
+	var total int
+	for _, a := range md.GetAnnotations() {
+		if len(a.Key) > MaxAnnotationKeySize {
+			return xerrors.Errorf("annotation key %.32s... is %d characters long, at most %d are allowed", a.Key, len(a.Key), MaxAnnotationKeySize)
+		}
+		if len(a.Value) > MaxAnnotationValueSize {
+			return xerrors.Errorf("annotation %s is %d bytes large, at most %d bytes are allowed", a.Key, len(a.Value), MaxAnnotationValueSize)
+		}
+		total += len(a.Key) + len(a.Value)
+	}
+	if total > MaxAnnotationsSize {
+		return xerrors.Errorf("annotations are %d bytes large together, at most %d bytes are allowed", total, MaxAnnotationsSize)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package werft_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateAnnotations(t *testing.T) {
+	many := make([]*v1.Annotation, 20)
+	for i := range many {
+		many[i] = &v1.Annotation{Key: strings.Repeat("k", i+1), Value: strings.Repeat("v", werft.MaxAnnotationValueSize)}
+	}
+	tests := []struct {
+		Name        string
+		Annotations []*v1.Annotation
+		Error       bool
+	}{
+		{"none", nil, false},
+		{"larger than a pod annotation", []*v1.Annotation{{Key: "payload", Value: strings.Repeat("v", werft.MaxAnnotationValueSize)}}, false},
+		{"key too long", []*v1.Annotation{{Key: strings.Repeat("k", werft.MaxAnnotationKeySize+1), Value: "v"}}, true},
+		{"value too large", []*v1.Annotation{{Key: "payload", Value: strings.Repeat("v", werft.MaxAnnotationValueSize+1)}}, true},
+		{"too large together", many, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := werft.ValidateAnnotations(&v1.JobMetadata{Annotations: test.Annotations})
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetTriggerPayloadNotFound(t *testing.T) {
+	srv := &werft.Service{Jobs: store.NewInMemoryJobStore()}
+	_, err := srv.GetTriggerPayload(context.Background(), &v1.GetTriggerPayloadRequest{Name: "foo.1"})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("expected %v, got %v (%v)", codes.NotFound, code, err)
+	}
+}
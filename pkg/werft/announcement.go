@@ -0,0 +1,82 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// announcementSlice is the log slice jobs show the announcement in
+	announcementSlice = "announcement"
+
+	// maxAnnouncementLength is the number of characters an announcement can have at most
+	maxAnnouncementLength = 2000
+)
+
+// SetAnnouncement sets or clears the announcement werft shows its users
+func (srv *Service) SetAnnouncement(ctx context.Context, req *v1.SetAnnouncementRequest) (*v1.SetAnnouncementResponse, error) {
+	if err := srv.requireAdmin(req.Token, "setting announcements"); err != nil {
+		return nil, err
+	}
+
+	msg := strings.TrimSpace(req.Message)
+	if utf8.RuneCountInString(msg) > maxAnnouncementLength {
+		return nil, status.Errorf(codes.InvalidArgument, "announcement is longer than %d characters", maxAnnouncementLength)
+	}
+
+	var announcement *v1.Announcement
+	if msg != "" {
+		announcement = &v1.Announcement{Message: msg, Since: srv.timestampNow()}
+	}
+
+	srv.announcementMu.Lock()
+	srv.announcement = announcement
+	srv.announcementMu.Unlock()
+
+	if announcement == nil {
+		log.Info("announcement cleared")
+	} else {
+		log.WithField("message", msg).Info("announcement set")
+	}
+	srv.publishEvent(&v1.ServerEvent{Type: serverEventAnnouncement, Message: msg})
+	return &v1.SetAnnouncementResponse{Announcement: announcement}, nil
+}
+
+// GetAnnouncement returns the current announcement, if there is one
+func (srv *Service) GetAnnouncement(ctx context.Context, req *v1.GetAnnouncementRequest) (*v1.GetAnnouncementResponse, error) {
+	return &v1.GetAnnouncementResponse{Announcement: srv.getAnnouncement()}, nil
+}
+
+// getAnnouncement returns a copy of the current announcement or nil if there is none
+func (srv *Service) getAnnouncement() *v1.Announcement {
+	srv.announcementMu.RLock()
+	defer srv.announcementMu.RUnlock()
+
+	if srv.announcement == nil {
+		return nil
+	}
+	return proto.Clone(srv.announcement).(*v1.Announcement)
+}
+
+// writeAnnouncement shows the current announcement in its own slice of a job's log, so that users see it
+// where they look anyways
+func (srv *Service) writeAnnouncement(out io.Writer) {
+	announcement := srv.getAnnouncement()
+	if announcement == nil {
+		return
+	}
+
+	for _, line := range strings.Split(announcement.Message, "\n") {
+		fmt.Fprintf(out, "[%s] %s\n", announcementSlice, strings.TrimRight(line, "\r"))
+	}
+	fmt.Fprintf(out, "[%s|DONE]\n", announcementSlice)
+}
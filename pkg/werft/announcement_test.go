@@ -0,0 +1,50 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetAnnouncement(t *testing.T) {
+	tests := []struct {
+		Name        string
+		AdminTokens []string
+		Token       string
+		Messages    []string
+		Expectation codes.Code
+		Announced   string
+	}{
+		{"not enabled", nil, "secret", []string{"maintenance at 17:00"}, codes.Unavailable, ""},
+		{"invalid token", []string{"secret"}, "guess", []string{"maintenance at 17:00"}, codes.PermissionDenied, ""},
+		{"set", []string{"secret"}, "secret", []string{"  maintenance at 17:00\n"}, codes.OK, "maintenance at 17:00"},
+		{"replaced", []string{"secret"}, "secret", []string{"maintenance at 17:00", "maintenance at 18:00"}, codes.OK, "maintenance at 18:00"},
+		{"cleared", []string{"secret"}, "secret", []string{"maintenance at 17:00", ""}, codes.OK, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := context.Background()
+			srv := &werft.Service{Config: werft.Config{AdminTokens: test.AdminTokens}}
+
+			var err error
+			for _, msg := range test.Messages {
+				_, err = srv.SetAnnouncement(ctx, &v1.SetAnnouncementRequest{Message: msg, Token: test.Token})
+			}
+			if code := status.Code(err); code != test.Expectation {
+				t.Fatalf("expected %v, got %v", test.Expectation, err)
+			}
+
+			resp, err := srv.GetAnnouncement(ctx, &v1.GetAnnouncementRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act := resp.Announcement.GetMessage(); act != test.Announced {
+				t.Errorf("expected announcement %q, got %q", test.Announced, act)
+			}
+		})
+	}
+}
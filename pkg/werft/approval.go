@@ -0,0 +1,245 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// approveJobPermission is the GitHub permission users need on the repository of a job to approve it
+	approveJobPermission = "write"
+
+	// approveCommand approves the jobs waiting for approval on a pull request when commented on the pull request.
+	// Everything following the command is recorded as comment of the approval.
+	approveCommand = "/werft approve"
+)
+
+// ApprovalConfig holds jobs of a repository until someone approves them, e.g. to deploy to production
+type ApprovalConfig struct {
+	// Jobs limits approval to jobs whose name without number matches one of these patterns, e.g. werft-deploy-*.
+	// The jobs of a matrix job match by the name of the matrix job. Defaults to all jobs of the repository.
+	Jobs []string `yaml:"jobs,omitempty"`
+
+	// Approvers are the GitHub users who can approve jobs. Defaults to everyone with write permission
+	// on the repository.
+	Approvers []string `yaml:"approvers,omitempty"`
+
+	// Triggers limits approval to jobs started by these triggers, e.g. tag. Defaults to all triggers.
+	Triggers []string `yaml:"triggers,omitempty"`
+}
+
+// Validate checks the settings
+func (c *ApprovalConfig) Validate() error {
+	for _, p := range c.Jobs {
+		if _, err := path.Match(p, ""); err != nil {
+			return xerrors.Errorf("invalid approval job pattern %s: %w", p, err)
+		}
+	}
+	for _, t := range c.Triggers {
+		trigger, ok := v1.JobTrigger_value["TRIGGER_"+strings.ToUpper(t)]
+		if !ok || trigger == int32(v1.JobTrigger_TRIGGER_UNKNOWN) {
+			return xerrors.Errorf("invalid approval trigger %s", t)
+		}
+	}
+	return nil
+}
+
+// policy returns the approval policy of a job, or nil if the job needs no approval
+func (c *ApprovalConfig) policy(name string, md *v1.JobMetadata) *repoconfig.ApprovalPolicy {
+	if c == nil {
+		return nil
+	}
+	if len(c.Jobs) > 0 {
+		group := jobGroup(name)
+		if md.Parent != "" {
+			group = jobGroup(md.Parent)
+		}
+		var matches bool
+		for _, p := range c.Jobs {
+			if ok, _ := path.Match(p, group); ok && group != "" {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			return nil
+		}
+	}
+
+	policy := &repoconfig.ApprovalPolicy{Approvers: c.Approvers, Triggers: c.Triggers}
+	if !policy.Required(md) {
+		return nil
+	}
+	return policy
+}
+
+// pendingApproval is a job which waits for approval
+type pendingApproval struct {
+	Policy   *repoconfig.ApprovalPolicy
+	Metadata *v1.JobMetadata
+}
+
+type approvedKey struct{}
+
+// withApproved marks jobs started in this context as approved, e.g. retries of a job which was approved before
+func withApproved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, approvedKey{}, true)
+}
+
+// isApproved returns true if jobs started in this context don't need approval
+func isApproved(ctx context.Context) bool {
+	approved, _ := ctx.Value(approvedKey{}).(bool)
+	return approved
+}
+
+// awaitApproval remembers who can approve a job until it's approved or done
+func (srv *Service) awaitApproval(name string, md *v1.JobMetadata, policy *repoconfig.ApprovalPolicy) {
+	srv.approvalMu.Lock()
+	defer srv.approvalMu.Unlock()
+
+	if srv.approvals == nil {
+		srv.approvals = make(map[string]pendingApproval)
+	}
+	srv.approvals[name] = pendingApproval{Policy: policy, Metadata: md}
+}
+
+// forgetApproval drops the approval policy of a job, e.g. because it was approved or is done
+func (srv *Service) forgetApproval(name string) {
+	srv.approvalMu.Lock()
+	defer srv.approvalMu.Unlock()
+
+	delete(srv.approvals, name)
+}
+
+// ApproveJob lets a job which waits for approval, or all jobs of a matrix job which do, start
+func (srv *Service) ApproveJob(ctx context.Context, req *v1.ApproveJobRequest) (*v1.ApproveJobResponse, error) {
+	name := srv.resolveJobName(ctx, req.Name)
+	job, err := srv.Jobs.Get(ctx, name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if job.Metadata.GetRepository() == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s has no repository and cannot be approved", name)
+	}
+
+	user, err := srv.authorizeGitHubUser(ctx, req.GithubToken, job.Metadata.Repository, approveJobPermission, "approve jobs")
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{name}
+	if len(job.Metadata.Children) > 0 {
+		names = job.Metadata.Children
+	}
+	var res []string
+	for _, n := range names {
+		approved, err := srv.approveJob(n, user, req.Comment)
+		if err != nil {
+			return nil, err
+		}
+		if approved {
+			res = append(res, n)
+		}
+	}
+	if len(res) == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s does not wait for approval", name)
+	}
+	return &v1.ApproveJobResponse{Jobs: res}, nil
+}
+
+// approveJob lets a job start if it waits for approval and the user can approve it. Approvals are recorded
+// as job events.
+func (srv *Service) approveJob(name, user, comment string) (approved bool, err error) {
+	srv.approvalMu.Lock()
+	pending, ok := srv.approvals[name]
+	srv.approvalMu.Unlock()
+	if !ok || !srv.Executor.WaitsForApproval(name) {
+		return false, nil
+	}
+	if !pending.Policy.MayApprove(user) {
+		return false, status.Errorf(codes.PermissionDenied, "%s cannot approve %s: approvers are %s", user, name, strings.Join(pending.Policy.Approvers, ", "))
+	}
+
+	err = srv.Executor.Approve(name)
+	if xerrors.Is(err, executor.ErrNotWaitingForApproval) {
+		// someone else was quicker
+		return false, nil
+	}
+	if err != nil {
+		return false, status.Error(codes.Internal, err.Error())
+	}
+	srv.forgetApproval(name)
+
+	msg := fmt.Sprintf("approved by %s", user)
+	if comment != "" {
+		msg += ": " + comment
+	}
+	srv.addJobEvent(name, v1.JobEvent{Type: v1.JobEventType_EVENT_APPROVED, Message: msg})
+	log.WithFields(jobLogFields(name, pending.Metadata)).WithField("user", user).Info("job approved")
+	return true, nil
+}
+
+// processIssueCommentEvent approves the jobs which wait for approval on the head of a pull request
+// if someone comments the approve command on it
+func (srv *Service) processIssueCommentEvent(ctx context.Context, logger *log.Entry, event *github.IssueCommentEvent) error {
+	if event.GetAction() != "created" || !event.GetIssue().IsPullRequest() {
+		return nil
+	}
+	line := strings.TrimSpace(strings.SplitN(event.GetComment().GetBody(), "\n", 2)[0])
+	if line != approveCommand && !strings.HasPrefix(line, approveCommand+" ") {
+		return nil
+	}
+	comment := strings.TrimSpace(strings.TrimPrefix(line, approveCommand))
+
+	var (
+		owner = event.GetRepo().GetOwner().GetLogin()
+		repo  = event.GetRepo().GetName()
+		user  = event.GetComment().GetUser().GetLogin()
+	)
+	perm, _, err := srv.GitHub.Client.Repositories.GetPermissionLevel(ctx, owner, repo, user)
+	if err != nil {
+		return xerrors.Errorf("cannot check permission of %s: %w", user, err)
+	}
+	if githubPermissionLevels[perm.GetPermission()] < githubPermissionLevels[approveJobPermission] {
+		logger.WithField("user", user).Info("ignoring approval by user without write permission")
+		return nil
+	}
+	pr, _, err := srv.GitHub.Client.PullRequests.Get(ctx, owner, repo, event.GetIssue().GetNumber())
+	if err != nil {
+		return xerrors.Errorf("cannot get pull request: %w", err)
+	}
+	rev := pr.GetHead().GetSHA()
+
+	var names []string
+	srv.approvalMu.Lock()
+	for name, pending := range srv.approvals {
+		r := pending.Metadata.GetRepository()
+		if r.GetOwner() == owner && r.GetRepo() == repo && r.GetRevision() == rev {
+			names = append(names, name)
+		}
+	}
+	srv.approvalMu.Unlock()
+
+	for _, name := range names {
+		_, err := srv.approveJob(name, user, comment)
+		if err != nil {
+			logger.WithError(err).WithField("name", name).WithField("user", user).Warn("cannot approve job")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package werft
+
+import (
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+func TestApprovalConfigPolicy(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Config   *ApprovalConfig
+		Job      string
+		Parent   string
+		Trigger  v1.JobTrigger
+		Required bool
+	}{
+		{"no config", nil, "werft-deploy-master.1", "", v1.JobTrigger_TRIGGER_PUSH, false},
+		{"all jobs", &ApprovalConfig{}, "werft-build-master.1", "", v1.JobTrigger_TRIGGER_PUSH, true},
+		{"matching job", &ApprovalConfig{Jobs: []string{"werft-deploy-*"}}, "werft-deploy-master.1", "", v1.JobTrigger_TRIGGER_PUSH, true},
+		{"other job", &ApprovalConfig{Jobs: []string{"werft-deploy-*"}}, "werft-build-master.1", "", v1.JobTrigger_TRIGGER_PUSH, false},
+		{"unnumbered job", &ApprovalConfig{Jobs: []string{"*"}}, "local-alice-1234", "", v1.JobTrigger_TRIGGER_MANUAL, false},
+		{"matrix job", &ApprovalConfig{Jobs: []string{"werft-deploy-*"}}, "werft-deploy-master.1-2", "werft-deploy-master.1", v1.JobTrigger_TRIGGER_PUSH, true},
+		{"matching trigger", &ApprovalConfig{Triggers: []string{"tag"}}, "werft-deploy-v1.1", "", v1.JobTrigger_TRIGGER_TAG, true},
+		{"other trigger", &ApprovalConfig{Triggers: []string{"tag"}}, "werft-deploy-master.1", "", v1.JobTrigger_TRIGGER_PUSH, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			policy := test.Config.policy(test.Job, &v1.JobMetadata{Parent: test.Parent, Trigger: test.Trigger})
+			if act := policy != nil; act != test.Required {
+				t.Errorf("expected approval to be required: %v, actual %v", test.Required, act)
+			}
+		})
+	}
+}
+
+func TestApprovalConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Config ApprovalConfig
+		Valid  bool
+	}{
+		{"empty", ApprovalConfig{}, true},
+		{"valid", ApprovalConfig{Jobs: []string{"werft-deploy-*"}, Triggers: []string{"tag", "manual"}}, true},
+		{"invalid pattern", ApprovalConfig{Jobs: []string{"werft-[deploy"}}, false},
+		{"unknown trigger", ApprovalConfig{Triggers: []string{"deploy"}}, false},
+		{"unknown trigger name", ApprovalConfig{Triggers: []string{"unknown"}}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
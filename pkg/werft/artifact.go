@@ -0,0 +1,385 @@
+package werft
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// artifactWebhookPath is where registry webhooks are received, followed by the name of the artifact trigger
+	artifactWebhookPath = "/artifacts/"
+
+	// annotationArtifact et al. tell jobs started by an artifact trigger which artifact version they were started for
+	annotationArtifact        = "artifact"
+	annotationArtifactVersion = "artifactVersion"
+	annotationArtifactDigest  = "artifactDigest"
+
+	// defaultChartPollInterval is the time between two polls of a chart repository if the config doesn't say otherwise
+	defaultChartPollInterval = 5 * time.Minute
+
+	chartIndexTimeout = 30 * time.Second
+
+	// defaultJobRef is the ref jobs werft starts on its own are started on if the config doesn't say otherwise
+	defaultJobRef = "refs/heads/master"
+)
+
+// ArtifactTriggerConfig starts jobs whenever a new version of an artifact is published, e.g. to rebuild downstream
+// images when their base image updates. Either Image or Chart must be set.
+type ArtifactTriggerConfig struct {
+	// Name identifies the trigger. Container registries send their webhooks to /artifacts/<name>.
+	Name string `yaml:"name"`
+
+	// Image starts the jobs when a container registry reports a push of a matching image, e.g. docker.io/library/golang.
+	// Supports globs, e.g. eu.gcr.io/werft/*
+	Image string `yaml:"image,omitempty"`
+
+	// Tag limits the image pushes which start jobs to matching tags, e.g. 1.*. Defaults to all tags.
+	Tag string `yaml:"tag,omitempty"`
+
+	// Token authorizes registry webhooks. Registries send it as bearer token or as token query parameter.
+	Token string `yaml:"token,omitempty"`
+
+	// Chart starts the jobs when a new version of a chart appears in a chart repository
+	Chart *ChartSourceConfig `yaml:"chart,omitempty"`
+
+	// Jobs are started for every new version of the artifact
+	Jobs []ArtifactJobConfig `yaml:"jobs"`
+}
+
+// ChartSourceConfig points to a chart in a Helm chart repository which werft polls for new versions
+type ChartSourceConfig struct {
+	// Repo is the URL of the chart repository, e.g. https://charts.helm.sh/stable
+	Repo string `yaml:"repo"`
+
+	// Name is the name of the chart
+	Name string `yaml:"name"`
+
+	// PollInterval is the time between two polls of the repository's index. Defaults to five minutes.
+	PollInterval *executor.Duration `yaml:"pollInterval,omitempty"`
+}
+
+// ArtifactJobConfig is a job an artifact trigger starts
+type ArtifactJobConfig struct {
+	// Repo is the repository of the job as host/owner/repo or owner/repo (on github.com)
+	Repo string `yaml:"repo"`
+
+	// Ref is the ref the job is started on. Defaults to refs/heads/master.
+	Ref string `yaml:"ref,omitempty"`
+
+	// Path is the job file. Defaults to the job the repository's werft config chooses for the artifact trigger.
+	Path string `yaml:"path,omitempty"`
+}
+
+// ArtifactVersion is a version of an artifact which was published
+type ArtifactVersion struct {
+	// Artifact is the image (without tag) or the chart
+	Artifact string
+	Version  string
+	Digest   string
+}
+
+// Validate checks that the trigger has a name, exactly one artifact source and jobs to start
+func (c ArtifactTriggerConfig) Validate() error {
+	if c.Name == "" || strings.Contains(c.Name, "/") {
+		return xerrors.Errorf("artifact triggers must have a name without slashes")
+	}
+	if (c.Image == "") == (c.Chart == nil) {
+		return xerrors.Errorf("artifact trigger %s must set either image or chart", c.Name)
+	}
+	for _, p := range []string{c.Image, c.Tag} {
+		if _, err := path.Match(p, ""); err != nil {
+			return xerrors.Errorf("invalid pattern %s of artifact trigger %s: %w", p, c.Name, err)
+		}
+	}
+	if c.Image != "" && c.Token == "" {
+		return xerrors.Errorf("artifact trigger %s: token is required to receive registry webhooks", c.Name)
+	}
+	if c.Chart != nil && (c.Chart.Repo == "" || c.Chart.Name == "") {
+		return xerrors.Errorf("artifact trigger %s: chart repo and name are required", c.Name)
+	}
+	if len(c.Jobs) == 0 {
+		return xerrors.Errorf("artifact trigger %s starts no job", c.Name)
+	}
+	for _, j := range c.Jobs {
+		if _, err := parseRepoName(j.Repo); err != nil {
+			return xerrors.Errorf("artifact trigger %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// matches returns true if the trigger starts jobs for that image version
+func (c ArtifactTriggerConfig) matches(v ArtifactVersion) bool {
+	if c.Image == "" {
+		return false
+	}
+	if ok, _ := path.Match(c.Image, v.Artifact); !ok {
+		return false
+	}
+	if c.Tag == "" {
+		return true
+	}
+	ok, _ := path.Match(c.Tag, v.Version)
+	return ok
+}
+
+// parseRepoName parses a repository given as host/owner/repo or owner/repo (on github.com)
+func parseRepoName(repo string) (*v1.Repository, error) {
+	segs := strings.Split(repo, "/")
+	switch len(segs) {
+	case 2:
+		segs = append([]string{"github.com"}, segs...)
+	case 3:
+	default:
+		return nil, xerrors.Errorf("invalid repository %s: expected host/owner/repo or owner/repo", repo)
+	}
+	for _, s := range segs {
+		if s == "" || strings.ContainsAny(s, "*?[") {
+			return nil, xerrors.Errorf("invalid repository %s: expected host/owner/repo or owner/repo", repo)
+		}
+	}
+	return &v1.Repository{Host: segs[0], Owner: segs[1], Repo: segs[2]}, nil
+}
+
+// ParseRegistryWebhook parses the image pushes of a registry webhook. It supports the notifications of
+// Docker Distribution based registries (e.g. Harbor or the Docker registry) and Docker Hub webhooks.
+func ParseRegistryWebhook(payload []byte) ([]ArtifactVersion, error) {
+	var evt struct {
+		// Docker Distribution
+		Events []struct {
+			Action string `json:"action"`
+			Target struct {
+				Repository string `json:"repository"`
+				Tag        string `json:"tag"`
+				Digest     string `json:"digest"`
+			} `json:"target"`
+			Request struct {
+				Host string `json:"host"`
+			} `json:"request"`
+		} `json:"events"`
+
+		// Docker Hub
+		PushData *struct {
+			Tag string `json:"tag"`
+		} `json:"push_data"`
+		Repository struct {
+			RepoName string `json:"repo_name"`
+		} `json:"repository"`
+	}
+	err := json.Unmarshal(payload, &evt)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse registry webhook: %w", err)
+	}
+
+	if evt.PushData != nil {
+		if evt.Repository.RepoName == "" || evt.PushData.Tag == "" {
+			return nil, xerrors.Errorf("Docker Hub webhook has no repository or tag")
+		}
+		return []ArtifactVersion{{Artifact: "docker.io/" + evt.Repository.RepoName, Version: evt.PushData.Tag}}, nil
+	}
+
+	var res []ArtifactVersion
+	for _, e := range evt.Events {
+		// blobs are pushed without tag - only the manifest push tells us about the new version
+		if e.Action != "push" || e.Target.Tag == "" {
+			continue
+		}
+		image := e.Target.Repository
+		if e.Request.Host != "" {
+			image = e.Request.Host + "/" + image
+		}
+		res = append(res, ArtifactVersion{Artifact: image, Version: e.Target.Tag, Digest: e.Target.Digest})
+	}
+	return res, nil
+}
+
+// ParseChartIndex returns the latest version of a chart from the index.yaml of a chart repository.
+// Chart repositories list the versions of a chart newest first.
+func ParseChartIndex(index []byte, chart string) (*ArtifactVersion, error) {
+	var idx struct {
+		Entries map[string][]struct {
+			Version string `yaml:"version"`
+			Digest  string `yaml:"digest"`
+		} `yaml:"entries"`
+	}
+	err := yaml.Unmarshal(index, &idx)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse chart repository index: %w", err)
+	}
+	versions := idx.Entries[chart]
+	if len(versions) == 0 {
+		return nil, xerrors.Errorf("chart repository has no chart %s", chart)
+	}
+	return &ArtifactVersion{Artifact: chart, Version: versions[0].Version, Digest: versions[0].Digest}, nil
+}
+
+// startArtifactPollers polls the chart repositories of all chart triggers in the background
+func (srv *Service) startArtifactPollers() {
+	for i := range srv.Config.ArtifactTriggers {
+		c := &srv.Config.ArtifactTriggers[i]
+		if c.Chart == nil {
+			continue
+		}
+		srv.goBackground(func(ctx context.Context) { srv.pollChart(ctx, c) })
+	}
+}
+
+// pollChart starts the jobs of a chart trigger whenever the latest version of the chart changes.
+// The first poll only takes note of the current version.
+func (srv *Service) pollChart(ctx context.Context, c *ArtifactTriggerConfig) {
+	interval := defaultChartPollInterval
+	if c.Chart.PollInterval != nil {
+		interval = c.Chart.PollInterval.Duration
+	}
+	logger := log.WithField("trigger", c.Name).WithField("chart", c.Chart.Name)
+
+	var last string
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		v, err := fetchLatestChart(srv.httpClient(chartIndexTimeout), c.Chart)
+		if err != nil {
+			logger.WithError(err).Warn("cannot poll chart repository")
+		} else if last == "" {
+			last = v.Version
+		} else if v.Version != last {
+			last = v.Version
+			srv.startArtifactJobs(ctx, c, *v)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// fetchLatestChart downloads the index of a chart repository and returns the latest version of the chart
+func fetchLatestChart(client *http.Client, c *ChartSourceConfig) (*ArtifactVersion, error) {
+	resp, err := client.Get(strings.TrimSuffix(c.Repo, "/") + "/index.yaml")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, xerrors.Errorf("chart repository responded with %s", resp.Status)
+	}
+	index, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseChartIndex(index, c.Name)
+}
+
+// startArtifactJobs starts the jobs of an artifact trigger for a new version of the artifact
+func (srv *Service) startArtifactJobs(ctx context.Context, c *ArtifactTriggerConfig, v ArtifactVersion) {
+	logger := log.WithField("trigger", c.Name).WithField("artifact", v.Artifact).WithField("version", v.Version)
+	logger.Info("new artifact version - starting jobs")
+
+	annotations := []*v1.Annotation{
+		{Key: annotationArtifact, Value: v.Artifact},
+		{Key: annotationArtifactVersion, Value: v.Version},
+	}
+	if v.Digest != "" {
+		annotations = append(annotations, &v1.Annotation{Key: annotationArtifactDigest, Value: v.Digest})
+	}
+	for _, j := range c.Jobs {
+		// Validate made sure the repo is valid
+		repo, _ := parseRepoName(j.Repo)
+		repo.Ref = j.Ref
+		if repo.Ref == "" {
+			repo.Ref = defaultJobRef
+		}
+
+		_, err := srv.StartGitHubJob(ctx, &v1.StartGitHubJobRequest{
+			Metadata: &v1.JobMetadata{
+				Owner:       fmt.Sprintf("artifact-trigger/%s", c.Name),
+				Repository:  repo,
+				Trigger:     v1.JobTrigger_TRIGGER_ARTIFACT,
+				Annotations: annotations,
+			},
+			JobPath: j.Path,
+		})
+		if err != nil {
+			logger.WithError(err).WithField("repo", j.Repo).Warn("cannot start artifact job")
+		}
+	}
+}
+
+// HandleArtifactWebhook receives the webhooks of container registries at /artifacts/<trigger name> and starts the
+// trigger's jobs for every matching image push. Requests must carry the trigger's token.
+func (srv *Service) HandleArtifactWebhook(w http.ResponseWriter, r *http.Request) {
+	received := srv.now()
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var trigger *ArtifactTriggerConfig
+	name := strings.TrimPrefix(r.URL.Path, artifactWebhookPath)
+	for i, c := range srv.Config.ArtifactTriggers {
+		if c.Name == name && c.Image != "" {
+			trigger = &srv.Config.ArtifactTriggers[i]
+			break
+		}
+	}
+	if trigger == nil {
+		http.NotFound(w, r)
+		return
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(trigger.Token)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	maxPayloadSize := srv.Config.MaxWebhookPayloadSize
+	if maxPayloadSize <= 0 {
+		maxPayloadSize = DefaultMaxWebhookPayloadSize
+	}
+	payload, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	versions, err := ParseRegistryWebhook(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var started int
+	for _, v := range versions {
+		if !trigger.matches(v) {
+			continue
+		}
+		// registries retry webhooks until they get a response - we must not make them wait for GitHub
+		v := v
+		srv.goBackground(func(ctx context.Context) {
+			ctx = withWebhookReceived(ctx, received)
+			ctx = withTriggerPayload(ctx, triggerSourceArtifact, trigger.Name, "", received, payload)
+			srv.startArtifactJobs(ctx, trigger, v)
+		})
+		started++
+	}
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "%d matching image pushes\n", started)
+}
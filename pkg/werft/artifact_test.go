@@ -0,0 +1,65 @@
+package werft_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestParseRegistryWebhook(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Payload     string
+		Expectation []werft.ArtifactVersion
+		Error       bool
+	}{
+		{"distribution", `{"events":[
+			{"action":"push","target":{"repository":"werft/base","digest":"sha256:1234"},"request":{"host":"registry.example.com"}},
+			{"action":"push","target":{"repository":"werft/base","tag":"1.2","digest":"sha256:abcd"},"request":{"host":"registry.example.com"}},
+			{"action":"pull","target":{"repository":"werft/base","tag":"1.2"}}
+		]}`, []werft.ArtifactVersion{
+			{Artifact: "registry.example.com/werft/base", Version: "1.2", Digest: "sha256:abcd"},
+		}, false},
+		{"docker hub", `{"push_data":{"tag":"1.14"},"repository":{"repo_name":"library/golang"}}`, []werft.ArtifactVersion{
+			{Artifact: "docker.io/library/golang", Version: "1.14"},
+		}, false},
+		{"docker hub without tag", `{"push_data":{},"repository":{"repo_name":"library/golang"}}`, nil, true},
+		{"no pushes", `{"events":[]}`, nil, false},
+		{"invalid", `not json`, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := werft.ParseRegistryWebhook([]byte(test.Payload))
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestParseChartIndex(t *testing.T) {
+	index := []byte(`apiVersion: v1
+entries:
+  nginx:
+  - version: 1.1.0
+    digest: abcd
+  - version: 1.0.0
+    digest: 1234
+`)
+
+	act, err := werft.ParseChartIndex(index, "nginx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := (werft.ArtifactVersion{Artifact: "nginx", Version: "1.1.0", Digest: "abcd"}); *act != exp {
+		t.Errorf("expected %v, got %v", exp, *act)
+	}
+
+	if _, err := werft.ParseChartIndex(index, "redis"); err == nil {
+		t.Error("expected an error for an unknown chart")
+	}
+}
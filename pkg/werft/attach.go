@@ -0,0 +1,197 @@
+package werft
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/util/exec"
+)
+
+// AttachConfig opts a repository into interactive debug sessions
+type AttachConfig struct {
+	// Permission is the minimum permission users need on the GitHub repository to attach, i.e. read, write or admin.
+	// Defaults to admin.
+	Permission string `yaml:"permission,omitempty"`
+}
+
+// defaultAttachPermission is the GitHub permission users need to attach if the repository config doesn't say otherwise
+const defaultAttachPermission = "admin"
+
+// defaultPodPermission is the GitHub permission users need to see the pod of a job if the repository config doesn't say otherwise
+const defaultPodPermission = "write"
+
+// githubPermissionLevels orders the permissions GitHub reports for collaborators
+var githubPermissionLevels = map[string]int{
+	"none":  0,
+	"read":  1,
+	"write": 2,
+	"admin": 3,
+}
+
+// AttachJob runs an interactive command in the pod of a running job
+func (srv *Service) AttachJob(s v1.WerftService_AttachJobServer) error {
+	req, err := s.Recv()
+	if err != nil {
+		return err
+	}
+	start := req.GetStart()
+	if start == nil {
+		return status.Error(codes.InvalidArgument, "first request must start the session")
+	}
+
+	ctx := s.Context()
+	name := srv.resolveJobName(ctx, start.Name)
+	job, err := srv.Jobs.Get(ctx, name)
+	if err == store.ErrNotFound {
+		return status.Errorf(codes.NotFound, "%s not found", start.Name)
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if job.Phase != v1.JobPhase_PHASE_RUNNING && job.Phase != v1.JobPhase_PHASE_DONE {
+		// done jobs qualify as their pod might be kept for debugging - the executor tells us if it's not
+		return status.Error(codes.FailedPrecondition, "can only attach to running jobs or failed jobs kept for debugging")
+	}
+
+	cfg := srv.repositoryConfig(job.Metadata.Repository).Attach
+	if cfg == nil || job.Metadata.Repository == nil {
+		return status.Error(codes.PermissionDenied, "attaching to jobs of this repository is not enabled")
+	}
+	required := cfg.Permission
+	if required == "" {
+		required = defaultAttachPermission
+	}
+	user, err := srv.authorizeGitHubUser(ctx, start.GithubToken, job.Metadata.Repository, required, "attach")
+	if err != nil {
+		return err
+	}
+	log.WithFields(jobLogFields(name, job.Metadata)).WithField("user", user).WithField("command", start.Command).Info("user attached to job")
+
+	var (
+		stdin, stdinW = io.Pipe()
+		sizes         = make(terminalSizeQueue, 1)
+		sendMu        sync.Mutex
+	)
+	if start.Size != nil {
+		sizes <- remotecommand.TerminalSize{Width: uint16(start.Size.Width), Height: uint16(start.Size.Height)}
+	}
+	go func() {
+		defer close(sizes)
+		for {
+			req, err := s.Recv()
+			if err == io.EOF {
+				stdinW.Close()
+				return
+			}
+			if err != nil {
+				stdinW.CloseWithError(err)
+				return
+			}
+
+			if in := req.GetStdin(); in != nil {
+				_, err = stdinW.Write(in)
+				if err != nil {
+					return
+				}
+			}
+			if sz := req.GetResize(); sz != nil {
+				select {
+				case sizes <- remotecommand.TerminalSize{Width: uint16(sz.Width), Height: uint16(sz.Height)}:
+				default:
+					// the previous size change hasn't been applied yet - drop this one rather than block input
+				}
+			}
+		}
+	}()
+
+	err = srv.Executor.Attach(name, executor.AttachOptions{
+		Container: start.Container,
+		Command:   start.Command,
+		Stdin:     stdin,
+		Stdout:    &attachOutput{S: s, Mu: &sendMu},
+		Stderr:    &attachOutput{S: s, Mu: &sendMu, Stderr: true},
+		TTY:       start.Tty,
+		Resize:    sizes,
+	})
+	stdin.Close()
+
+	var (
+		exitErr exec.ExitError
+		code    int32
+	)
+	if xerrors.As(err, &exitErr) {
+		code = int32(exitErr.ExitStatus())
+	} else if xerrors.Is(err, executor.ErrNotRunning) {
+		return status.Error(codes.FailedPrecondition, "can only attach to running jobs or failed jobs kept for debugging")
+	} else if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	sendMu.Lock()
+	defer sendMu.Unlock()
+	return s.Send(&v1.AttachJobResponse{Content: &v1.AttachJobResponse_ExitCode{ExitCode: code}})
+}
+
+// authorizeGitHubUser checks that the GitHub user identified by token has at least the required permission on repo,
+// so that they may perform the action. It returns the user's login.
+func (srv *Service) authorizeGitHubUser(ctx context.Context, token string, repo *v1.Repository, required, action string) (string, error) {
+	if token == "" {
+		return "", status.Errorf(codes.Unauthenticated, "you need a GitHub token to %s", action)
+	}
+
+	user, _, err := srv.userGitHubClient(ctx, token).Users.Get(ctx, "")
+	if err != nil {
+		return "", status.Error(codes.Unauthenticated, "invalid GitHub token")
+	}
+	perm, _, err := srv.GitHub.Client.Repositories.GetPermissionLevel(ctx, repo.Owner, repo.Repo, user.GetLogin())
+	if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	if githubPermissionLevels[perm.GetPermission()] < githubPermissionLevels[required] {
+		return "", status.Errorf(codes.PermissionDenied, "%s needs %s permission on %s/%s to %s", user.GetLogin(), required, repo.Owner, repo.Repo, action)
+	}
+	return user.GetLogin(), nil
+}
+
+// terminalSizeQueue passes terminal size changes on to an attached command
+type terminalSizeQueue chan remotecommand.TerminalSize
+
+// Next returns the next terminal size, or nil once the queue is closed
+func (q terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	sz, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &sz
+}
+
+// attachOutput sends the output of an attached command to the client
+type attachOutput struct {
+	S      v1.WerftService_AttachJobServer
+	Mu     *sync.Mutex
+	Stderr bool
+}
+
+func (o *attachOutput) Write(p []byte) (n int, err error) {
+	resp := &v1.AttachJobResponse{Content: &v1.AttachJobResponse_Stdout{Stdout: p}}
+	if o.Stderr {
+		resp.Content = &v1.AttachJobResponse_Stderr{Stderr: p}
+	}
+
+	o.Mu.Lock()
+	defer o.Mu.Unlock()
+	err = o.S.Send(resp)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
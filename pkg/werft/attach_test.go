@@ -0,0 +1,99 @@
+package werft_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeAttachServer struct {
+	grpc.ServerStream
+	Requests []*v1.AttachJobRequest
+}
+
+func (s *fakeAttachServer) Context() context.Context { return context.Background() }
+
+func (s *fakeAttachServer) Recv() (*v1.AttachJobRequest, error) {
+	if len(s.Requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.Requests[0]
+	s.Requests = s.Requests[1:]
+	return req, nil
+}
+
+func (s *fakeAttachServer) Send(*v1.AttachJobResponse) error { return nil }
+
+func TestAttachJob(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	for _, job := range []v1.JobStatus{
+		{Name: "enabled.1", Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}}},
+		{Name: "enabled.2", Phase: v1.JobPhase_PHASE_PREPARING, Metadata: &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}}},
+		{Name: "disabled.1", Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "other"}}},
+	} {
+		err := jobs.Store(context.Background(), job)
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+	srv := &werft.Service{
+		Jobs: jobs,
+		Config: werft.Config{
+			Repositories: []werft.RepositoryConfig{
+				{Repo: "32leaves/werft", Attach: &werft.AttachConfig{Permission: "write"}},
+			},
+		},
+	}
+
+	start := func(name, token string) *v1.AttachJobRequest {
+		return &v1.AttachJobRequest{Content: &v1.AttachJobRequest_Start{Start: &v1.AttachJobStart{Name: name, GithubToken: token}}}
+	}
+	tests := []struct {
+		Name     string
+		Requests []*v1.AttachJobRequest
+		Code     codes.Code
+	}{
+		{"no start", []*v1.AttachJobRequest{&v1.AttachJobRequest{Content: &v1.AttachJobRequest_Stdin{Stdin: []byte("ls")}}}, codes.InvalidArgument},
+		{"unknown job", []*v1.AttachJobRequest{start("unknown.1", "token")}, codes.NotFound},
+		{"job not running", []*v1.AttachJobRequest{start("enabled.2", "token")}, codes.FailedPrecondition},
+		{"repo not enabled", []*v1.AttachJobRequest{start("disabled.1", "token")}, codes.PermissionDenied},
+		{"no token", []*v1.AttachJobRequest{start("enabled.1", "")}, codes.Unauthenticated},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := srv.AttachJob(&fakeAttachServer{Requests: test.Requests})
+			if code := status.Code(err); code != test.Code {
+				t.Errorf("expected %v, got %v (%v)", test.Code, code, err)
+			}
+		})
+	}
+}
+
+func TestGetJobPod(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	err := jobs.Store(context.Background(), v1.JobStatus{Name: "foo.1", Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}}})
+	if err != nil {
+		t.Fatalf("cannot store job: %v", err)
+	}
+	srv := &werft.Service{Jobs: jobs}
+
+	resp, err := srv.GetJob(context.Background(), &v1.GetJobRequest{Name: "foo.1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Pod != nil {
+		t.Errorf("expected no pod unless requested, got %v", resp.Pod)
+	}
+
+	_, err = srv.GetJob(context.Background(), &v1.GetJobRequest{Name: "foo.1", IncludePod: true})
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Errorf("expected %v without a token, got %v (%v)", codes.Unauthenticated, code, err)
+	}
+}
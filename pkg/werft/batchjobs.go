@@ -0,0 +1,156 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultBatchLimit is the number of jobs a batch operation applies to at most if the request doesn't say otherwise
+	defaultBatchLimit = 100
+
+	// maxBatchLimit is the number of jobs a batch operation applies to at most
+	maxBatchLimit = 1000
+
+	// batchJobPermission is the GitHub permission users need on the repository of a job to cancel or retry it in a batch
+	batchJobPermission = "write"
+)
+
+// batchOp applies a batch operation to a single job. It returns the name of the job it started, if any.
+type batchOp func(ctx context.Context, job *v1.JobStatus) (newJob string, err error)
+
+// CancelJobs stops all waiting or running jobs matching a filter
+func (srv *Service) CancelJobs(ctx context.Context, req *v1.BatchJobsRequest) (*v1.BatchJobsResponse, error) {
+	phases := []v1.JobPhase{v1.JobPhase_PHASE_WAITING, v1.JobPhase_PHASE_PREPARING, v1.JobPhase_PHASE_STARTING, v1.JobPhase_PHASE_RUNNING}
+	return srv.batchJobs(ctx, req, "cancel jobs", phases, func(ctx context.Context, job *v1.JobStatus) (string, error) {
+		_, err := srv.StopJob(ctx, &v1.StopJobRequest{Name: job.Name})
+		return "", err
+	})
+}
+
+// RetryJobs starts all finished jobs matching a filter again
+func (srv *Service) RetryJobs(ctx context.Context, req *v1.BatchJobsRequest) (*v1.BatchJobsResponse, error) {
+	return srv.batchJobs(ctx, req, "retry jobs", []v1.JobPhase{v1.JobPhase_PHASE_DONE}, func(ctx context.Context, job *v1.JobStatus) (string, error) {
+		resp, err := srv.StartFromPreviousJob(ctx, &v1.StartFromPreviousJobRequest{PreviousJob: job.Name})
+		if err != nil {
+			return "", err
+		}
+		return resp.Status.Name, nil
+	})
+}
+
+// batchJobs applies op to the newest jobs in one of phases which match the filter of the request. Jobs of a matrix
+// are left to their matrix job if op is applied to that, too.
+func (srv *Service) batchJobs(ctx context.Context, req *v1.BatchJobsRequest, action string, phases []v1.JobPhase, op batchOp) (*v1.BatchJobsResponse, error) {
+	if req.GithubToken == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "you need a GitHub token to %s", action)
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultBatchLimit
+	}
+	if limit > maxBatchLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must not exceed %d", maxBatchLimit)
+	}
+
+	filter := append([]*v1.FilterExpression{notDeletedFilter()}, req.Filter...)
+	if req.LabelSelector != "" {
+		lf, err := filterexpr.ParseLabelSelector(req.LabelSelector)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter = append(filter, lf...)
+	}
+	// the limit must only cut jobs op applies to
+	phaseFilter := &v1.FilterExpression{}
+	for _, p := range phases {
+		phaseFilter.Terms = append(phaseFilter.Terms, &v1.FilterTerm{Field: "phase", Value: strings.ToLower(strings.TrimPrefix(p.String(), "PHASE_")), Operation: v1.FilterOp_OP_EQUALS})
+	}
+	filter = append(filter, phaseFilter)
+
+	jobs, _, err := srv.Jobs.Find(ctx, filter, []*v1.OrderExpression{{Field: "created", Ascending: false}}, 0, limit+1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := &v1.BatchJobsResponse{}
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+		res.Truncated = true
+	}
+
+	var (
+		selected = make(map[string]struct{}, len(jobs))
+		acted    = make(map[string]bool, len(jobs))
+		// we authorize the user once per repository, not once per job
+		authorized = make(map[string]error)
+	)
+	for _, job := range jobs {
+		selected[job.Name] = struct{}{}
+	}
+	apply := func(job *v1.JobStatus, r *v1.BatchJobResult) {
+		repo := job.Metadata.GetRepository()
+		if repo == nil {
+			r.Outcome, r.Message = v1.BatchJobOutcome_BATCH_FAILED, "job has no repository"
+			res.Failed++
+			return
+		}
+		key := repo.Owner + "/" + repo.Repo
+		authErr, ok := authorized[key]
+		if !ok {
+			_, authErr = srv.authorizeGitHubUser(ctx, req.GithubToken, repo, batchJobPermission, action)
+			authorized[key] = authErr
+		}
+		if authErr != nil {
+			r.Outcome, r.Message = v1.BatchJobOutcome_BATCH_FAILED, status.Convert(authErr).Message()
+			res.Failed++
+			return
+		}
+
+		if req.DryRun {
+			r.Outcome = v1.BatchJobOutcome_BATCH_DRY_RUN
+			res.Succeeded++
+			acted[job.Name] = true
+			return
+		}
+		newJob, err := op(ctx, job)
+		if err != nil {
+			r.Outcome, r.Message = v1.BatchJobOutcome_BATCH_FAILED, status.Convert(err).Message()
+			res.Failed++
+			return
+		}
+		r.NewJob = newJob
+		res.Succeeded++
+		acted[job.Name] = true
+	}
+
+	// matrix jobs go first, so that we know which jobs of a matrix are handled by their matrix job already
+	res.Results = make([]*v1.BatchJobResult, len(jobs))
+	var children []int
+	for i := range jobs {
+		res.Results[i] = &v1.BatchJobResult{Name: jobs[i].Name}
+		if _, ok := selected[jobs[i].Metadata.GetParent()]; ok {
+			children = append(children, i)
+			continue
+		}
+		apply(&jobs[i], res.Results[i])
+	}
+	for _, i := range children {
+		job, r := &jobs[i], res.Results[i]
+		if acted[job.Metadata.Parent] {
+			r.Outcome, r.Message = v1.BatchJobOutcome_BATCH_SKIPPED, fmt.Sprintf("handled by its matrix job %s", job.Metadata.Parent)
+			res.Skipped++
+			continue
+		}
+		apply(job, r)
+	}
+
+	log.WithField("action", action).WithField("dryRun", req.DryRun).WithField("succeeded", res.Succeeded).WithField("failed", res.Failed).WithField("skipped", res.Skipped).Info("applied batch operation")
+	return res, nil
+}
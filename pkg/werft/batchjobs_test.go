@@ -0,0 +1,168 @@
+package werft_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/google/go-github/github"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBatchJobsRequestValidation(t *testing.T) {
+	srv := &werft.Service{Jobs: store.NewInMemoryJobStore()}
+	tests := []struct {
+		Name        string
+		Req         *v1.BatchJobsRequest
+		Expectation codes.Code
+	}{
+		{"no token", &v1.BatchJobsRequest{}, codes.Unauthenticated},
+		{"limit too high", &v1.BatchJobsRequest{GithubToken: "foo", Limit: 5000}, codes.InvalidArgument},
+		{"invalid selector", &v1.BatchJobsRequest{GithubToken: "foo", LabelSelector: "team in (a"}, codes.InvalidArgument},
+		{"no matching jobs", &v1.BatchJobsRequest{GithubToken: "foo"}, codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			for _, op := range []func(context.Context, *v1.BatchJobsRequest) (*v1.BatchJobsResponse, error){srv.CancelJobs, srv.RetryJobs} {
+				_, err := op(context.Background(), test.Req)
+				if code := status.Code(err); code != test.Expectation {
+					t.Errorf("expected %v, got %v: %v", test.Expectation, code, err)
+				}
+			}
+		})
+	}
+}
+
+// redirectTransport sends all requests to a test server
+type redirectTransport struct {
+	URL *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req.URL.Scheme, req.URL.Host = t.URL.Scheme, t.URL.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBatchJobsSelection(t *testing.T) {
+	mux := http.NewServeMux()
+	ghsrv := httptest.NewServer(mux)
+	defer ghsrv.Close()
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"login":"alice"}`)
+	})
+	mux.HandleFunc("/repos/32leaves/werft/collaborators/alice/permission", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"permission":"write"}`)
+	})
+	mux.HandleFunc("/repos/32leaves/other/collaborators/alice/permission", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"permission":"read"}`)
+	})
+	ghURL, _ := url.Parse(ghsrv.URL + "/")
+	client := github.NewClient(nil)
+	client.BaseURL = ghURL
+
+	var (
+		werftRepo = &v1.Repository{Owner: "32leaves", Repo: "werft"}
+		otherRepo = &v1.Repository{Owner: "32leaves", Repo: "other"}
+		created   int64
+	)
+	job := func(name string, phase v1.JobPhase, repo *v1.Repository, parent string) v1.JobStatus {
+		created++
+		return v1.JobStatus{Name: name, Phase: phase, Metadata: &v1.JobMetadata{Repository: repo, Parent: parent, Created: &timestamp.Timestamp{Seconds: created}}}
+	}
+
+	tests := []struct {
+		Name     string
+		Jobs     []v1.JobStatus
+		Retry    bool
+		Limit    int32
+		Expected []string
+	}{
+		{
+			Name: "cancel selects running jobs only",
+			Jobs: []v1.JobStatus{
+				job("done.1", v1.JobPhase_PHASE_DONE, werftRepo, ""),
+				job("running.1", v1.JobPhase_PHASE_RUNNING, werftRepo, ""),
+				job("waiting.1", v1.JobPhase_PHASE_WAITING, werftRepo, ""),
+				job("done.2", v1.JobPhase_PHASE_DONE, werftRepo, ""),
+			},
+			Expected: []string{"waiting.1 BATCH_DRY_RUN", "running.1 BATCH_DRY_RUN"},
+		},
+		{
+			Name: "retry selects finished jobs only",
+			Jobs: []v1.JobStatus{
+				job("done.1", v1.JobPhase_PHASE_DONE, werftRepo, ""),
+				job("running.1", v1.JobPhase_PHASE_RUNNING, werftRepo, ""),
+			},
+			Retry:    true,
+			Expected: []string{"done.1 BATCH_DRY_RUN"},
+		},
+		{
+			Name: "limit keeps the newest jobs",
+			Jobs: []v1.JobStatus{
+				job("running.1", v1.JobPhase_PHASE_RUNNING, werftRepo, ""),
+				job("running.2", v1.JobPhase_PHASE_RUNNING, werftRepo, ""),
+				job("running.3", v1.JobPhase_PHASE_RUNNING, werftRepo, ""),
+			},
+			Limit:    2,
+			Expected: []string{"running.3 BATCH_DRY_RUN", "running.2 BATCH_DRY_RUN"},
+		},
+		{
+			Name: "matrix jobs handle their jobs",
+			Jobs: []v1.JobStatus{
+				job("matrix.1", v1.JobPhase_PHASE_RUNNING, werftRepo, ""),
+				job("matrix.1-0", v1.JobPhase_PHASE_RUNNING, werftRepo, "matrix.1"),
+				job("matrix.1-1", v1.JobPhase_PHASE_RUNNING, werftRepo, "matrix.1"),
+			},
+			Expected: []string{"matrix.1-1 BATCH_SKIPPED", "matrix.1-0 BATCH_SKIPPED", "matrix.1 BATCH_DRY_RUN"},
+		},
+		{
+			Name: "matrix jobs which fail don't handle their jobs",
+			Jobs: []v1.JobStatus{
+				job("matrix.1", v1.JobPhase_PHASE_RUNNING, otherRepo, ""),
+				job("matrix.1-0", v1.JobPhase_PHASE_RUNNING, werftRepo, "matrix.1"),
+			},
+			Expected: []string{"matrix.1-0 BATCH_DRY_RUN", "matrix.1 BATCH_FAILED"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			jobs := store.NewInMemoryJobStore()
+			for _, j := range test.Jobs {
+				err := jobs.Store(context.Background(), j)
+				if err != nil {
+					t.Fatalf("cannot store job: %v", err)
+				}
+			}
+			srv := &werft.Service{Jobs: jobs, Transport: &redirectTransport{ghURL}}
+			srv.GitHub.Client = client
+
+			op := srv.CancelJobs
+			if test.Retry {
+				op = srv.RetryJobs
+			}
+			resp, err := op(context.Background(), &v1.BatchJobsRequest{GithubToken: "foo", DryRun: true, Limit: test.Limit})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var act []string
+			for _, r := range resp.Results {
+				act = append(act, r.Name+" "+r.Outcome.String())
+			}
+			if strings.Join(act, ",") != strings.Join(test.Expected, ",") {
+				t.Errorf("expected %v, got %v", test.Expected, act)
+			}
+			if resp.Truncated != (test.Limit > 0) {
+				t.Errorf("expected truncated to be %v", test.Limit > 0)
+			}
+		})
+	}
+}
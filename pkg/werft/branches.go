@@ -0,0 +1,79 @@
+package werft
+
+import (
+	"context"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultBranchesJobs is the number of most recent jobs of a repository GetBranches looks at unless told otherwise
+const defaultBranchesJobs = 1000
+
+// GetBranches returns the branches of a repository which had jobs recently, with their latest jobs. All branches
+// are served by a single query for the most recent jobs of the repository.
+func (srv *Service) GetBranches(ctx context.Context, req *v1.GetBranchesRequest) (*v1.GetBranchesResponse, error) {
+	repo := req.Repository
+	if repo == nil || repo.Owner == "" || repo.Repo == "" {
+		return nil, status.Error(codes.InvalidArgument, "repository owner and repo are required")
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultBranchesJobs
+	}
+
+	term := func(field, value string, op v1.FilterOp) *v1.FilterExpression {
+		return &v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: field, Value: value, Operation: op}}}
+	}
+	filter := []*v1.FilterExpression{
+		term("repo.owner", repo.Owner, v1.FilterOp_OP_EQUALS),
+		term("repo.repo", repo.Repo, v1.FilterOp_OP_EQUALS),
+		term("repo.ref", "refs/heads/", v1.FilterOp_OP_STARTS_WITH),
+	}
+	if repo.Host != "" {
+		filter = append(filter, term("repo.host", repo.Host, v1.FilterOp_OP_EQUALS))
+	}
+	order := []*v1.OrderExpression{{Field: "created", Ascending: false}}
+	jobs, _, err := srv.Jobs.Find(store.WithStaleReads(ctx), filter, order, 0, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var (
+		idx = make(map[string]*v1.Branch)
+		res []*v1.Branch
+	)
+	for i := range jobs {
+		job := &jobs[i]
+		md := job.GetMetadata()
+		ref := md.GetRepository().GetRef()
+		if md.GetParent() != "" || md.GetMergeGroup() != nil || !strings.HasPrefix(ref, "refs/heads/") {
+			// the children of matrix jobs are represented by their parent, and merge groups are no branches of their own
+			continue
+		}
+
+		b, ok := idx[ref]
+		if !ok {
+			b = &v1.Branch{Ref: ref, Latest: summarizeJob(job)}
+			idx[ref] = b
+			res = append(res, b)
+		}
+		b.Jobs++
+		if job.Phase != v1.JobPhase_PHASE_DONE || job.GetConditions().GetSkipped() {
+			continue
+		}
+		if b.LatestFinished == nil {
+			b.LatestFinished = summarizeJob(job)
+		}
+		if b.LastSuccess == nil && job.GetConditions().GetSuccess() {
+			b.LastSuccess = md.Finished
+			if b.LastSuccess == nil {
+				b.LastSuccess = md.Created
+			}
+		}
+	}
+	return &v1.GetBranchesResponse{Branches: res}, nil
+}
@@ -0,0 +1,72 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetBranches(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	job := func(name, ref string, phase v1.JobPhase, success bool, created int64) v1.JobStatus {
+		return v1.JobStatus{
+			Name:  name,
+			Phase: phase,
+			Metadata: &v1.JobMetadata{
+				Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: ref},
+				Created:    &timestamp.Timestamp{Seconds: created},
+				Finished:   &timestamp.Timestamp{Seconds: created + 10},
+			},
+			Conditions: &v1.JobConditions{Success: success},
+		}
+	}
+	for _, j := range []v1.JobStatus{
+		job("werft-build-master.1", "refs/heads/master", v1.JobPhase_PHASE_DONE, true, 100),
+		job("werft-build-master.2", "refs/heads/master", v1.JobPhase_PHASE_DONE, false, 200),
+		job("werft-build-master.3", "refs/heads/master", v1.JobPhase_PHASE_RUNNING, false, 500),
+		job("werft-build-foo.1", "refs/heads/foo", v1.JobPhase_PHASE_DONE, true, 300),
+		job("werft-build-v1.1", "refs/tags/v1", v1.JobPhase_PHASE_DONE, true, 400),
+	} {
+		err := jobs.Store(context.Background(), j)
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+	srv := &werft.Service{Jobs: jobs}
+
+	_, err := srv.GetBranches(context.Background(), &v1.GetBranchesRequest{Repository: &v1.Repository{Owner: "32leaves"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without repo, got %v", err)
+	}
+
+	resp, err := srv.GetBranches(context.Background(), &v1.GetBranchesRequest{Repository: &v1.Repository{Owner: "32leaves", Repo: "werft"}})
+	if err != nil {
+		t.Fatalf("cannot get branches: %v", err)
+	}
+	type branch struct {
+		Ref            string
+		Latest         string
+		LatestFinished string
+		LastSuccess    int64
+		Jobs           int32
+	}
+	exp := []branch{
+		{"refs/heads/master", "werft-build-master.3", "werft-build-master.2", 110, 3},
+		{"refs/heads/foo", "werft-build-foo.1", "werft-build-foo.1", 310, 1},
+	}
+	if len(resp.Branches) != len(exp) {
+		t.Fatalf("expected %d branches, got %v", len(exp), resp.Branches)
+	}
+	for i, b := range resp.Branches {
+		act := branch{b.Ref, b.Latest.GetName(), b.LatestFinished.GetName(), b.LastSuccess.GetSeconds(), b.Jobs}
+		if act != exp[i] {
+			t.Errorf("unexpected branch %d: %+v, expected %+v", i, act, exp[i])
+		}
+	}
+}
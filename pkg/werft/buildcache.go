@@ -0,0 +1,128 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// resultTypeBuildCache marks results which report how many build steps were served from the build cache
+	resultTypeBuildCache = "buildcache"
+
+	// envBuildCacheRef et al. tell jobs where BuildKit imports its cache from and exports it to
+	envBuildCacheRef    = "BUILDKIT_CACHE_REF"
+	envBuildCacheImport = "BUILDKIT_CACHE_IMPORT"
+	envBuildCacheExport = "BUILDKIT_CACHE_EXPORT"
+)
+
+// BuildCacheConfig configures a BuildKit registry cache for the jobs of a repository
+type BuildCacheConfig struct {
+	// Ref is the image the cache is stored in. It's a template of the job's repository,
+	// e.g. registry.example.com/cache/{{ .Owner }}/{{ .Repo }}
+	Ref string `yaml:"ref"`
+
+	// Mode is the BuildKit cache export mode: min exports the layers of the resulting image only, max those of
+	// all build stages. Defaults to max.
+	Mode string `yaml:"mode,omitempty"`
+}
+
+// Validate checks that the cache ref is a valid template and the mode is known
+func (c *BuildCacheConfig) Validate() error {
+	if c.Ref == "" {
+		return xerrors.Errorf("buildCache: ref is required")
+	}
+	if _, err := template.New("ref").Parse(c.Ref); err != nil {
+		return xerrors.Errorf("buildCache: invalid ref %s: %w", c.Ref, err)
+	}
+	switch c.Mode {
+	case "", "min", "max":
+	default:
+		return xerrors.Errorf("buildCache: invalid mode %s: must be min or max", c.Mode)
+	}
+	return nil
+}
+
+// Env produces the environment which tells a job's BuildKit where to import its cache from and export it to.
+// Jobs started for pull requests only import the cache, so that untrusted changes cannot poison it.
+func (c *BuildCacheConfig) Env(md *v1.JobMetadata) (map[string]string, error) {
+	tpl, err := template.New("ref").Parse(c.Ref)
+	if err != nil {
+		return nil, err
+	}
+	var ref bytes.Buffer
+	err = tpl.Execute(&ref, md.Repository)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot produce build cache ref: %w", err)
+	}
+
+	mode := c.Mode
+	if mode == "" {
+		mode = "max"
+	}
+	res := map[string]string{
+		envBuildCacheRef:    ref.String(),
+		envBuildCacheImport: fmt.Sprintf("type=registry,ref=%s", ref.String()),
+	}
+	if md.Trigger != v1.JobTrigger_TRIGGER_PULL_REQUEST {
+		res[envBuildCacheExport] = fmt.Sprintf("type=registry,ref=%s,mode=%s", ref.String(), mode)
+	}
+	return res, nil
+}
+
+// ParseBuildCacheResult parses the payload of a buildcache result, e.g. 42/50 for 42 of 50 build steps which
+// were served from the cache.
+func ParseBuildCacheResult(payload string) (cached, total int, err error) {
+	segs := strings.Split(strings.TrimSpace(payload), "/")
+	if len(segs) != 2 {
+		return 0, 0, xerrors.Errorf("invalid build cache result %s: expected <cached steps>/<total steps>", payload)
+	}
+	cached, err = strconv.Atoi(strings.TrimSpace(segs[0]))
+	if err != nil {
+		return 0, 0, xerrors.Errorf("invalid build cache result %s: %w", payload, err)
+	}
+	total, err = strconv.Atoi(strings.TrimSpace(segs[1]))
+	if err != nil {
+		return 0, 0, xerrors.Errorf("invalid build cache result %s: %w", payload, err)
+	}
+	if cached < 0 || cached > total {
+		return 0, 0, xerrors.Errorf("invalid build cache result %s: cached steps must be between 0 and the total", payload)
+	}
+	return cached, total, nil
+}
+
+// newBuildCacheMetrics produces the counter of build steps jobs reported in buildcache results
+func newBuildCacheMetrics() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_buildcache_steps_total",
+		Help: "Build steps jobs reported in buildcache results, by repository and whether they were served from the build cache.",
+	}, []string{"repo", "cached"})
+}
+
+// recordBuildCacheResult counts the build steps a job reported, so that one can tell the cache hit rate of repositories
+func (srv *Service) recordBuildCacheResult(ctx context.Context, name string, res *v1.JobResult) {
+	if srv.buildCacheSteps == nil {
+		return
+	}
+	cached, total, err := ParseBuildCacheResult(res.Payload)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("job reported an invalid build cache result")
+		return
+	}
+
+	var repo string
+	if job, err := srv.Jobs.Get(ctx, name); err == nil {
+		r := job.GetMetadata().GetRepository()
+		repo = fmt.Sprintf("%s/%s", r.GetOwner(), r.GetRepo())
+	}
+	srv.buildCacheSteps.WithLabelValues(repo, "true").Add(float64(cached))
+	srv.buildCacheSteps.WithLabelValues(repo, "false").Add(float64(total - cached))
+}
@@ -0,0 +1,75 @@
+package werft_test
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestParseBuildCacheResult(t *testing.T) {
+	tests := []struct {
+		Payload string
+		Cached  int
+		Total   int
+		Error   bool
+	}{
+		{"42/50", 42, 50, false},
+		{" 0 / 3 ", 0, 3, false},
+		{"51/50", 0, 0, true},
+		{"-1/50", 0, 0, true},
+		{"42", 0, 0, true},
+		{"a/b", 0, 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Payload, func(t *testing.T) {
+			cached, total, err := werft.ParseBuildCacheResult(test.Payload)
+			if (err != nil) != test.Error {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if cached != test.Cached || total != test.Total {
+				t.Errorf("expected %d/%d, got %d/%d", test.Cached, test.Total, cached, total)
+			}
+		})
+	}
+}
+
+func TestBuildCacheEnv(t *testing.T) {
+	repo := &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"}
+	tests := []struct {
+		Name        string
+		Config      werft.BuildCacheConfig
+		Trigger     v1.JobTrigger
+		Expectation map[string]string
+	}{
+		{"push", werft.BuildCacheConfig{Ref: "registry.example.com/cache/{{ .Owner }}/{{ .Repo }}"}, v1.JobTrigger_TRIGGER_PUSH, map[string]string{
+			"BUILDKIT_CACHE_REF":    "registry.example.com/cache/32leaves/werft",
+			"BUILDKIT_CACHE_IMPORT": "type=registry,ref=registry.example.com/cache/32leaves/werft",
+			"BUILDKIT_CACHE_EXPORT": "type=registry,ref=registry.example.com/cache/32leaves/werft,mode=max",
+		}},
+		{"min mode", werft.BuildCacheConfig{Ref: "registry.example.com/cache", Mode: "min"}, v1.JobTrigger_TRIGGER_MANUAL, map[string]string{
+			"BUILDKIT_CACHE_REF":    "registry.example.com/cache",
+			"BUILDKIT_CACHE_IMPORT": "type=registry,ref=registry.example.com/cache",
+			"BUILDKIT_CACHE_EXPORT": "type=registry,ref=registry.example.com/cache,mode=min",
+		}},
+		{"pull request imports only", werft.BuildCacheConfig{Ref: "registry.example.com/cache"}, v1.JobTrigger_TRIGGER_PULL_REQUEST, map[string]string{
+			"BUILDKIT_CACHE_REF":    "registry.example.com/cache",
+			"BUILDKIT_CACHE_IMPORT": "type=registry,ref=registry.example.com/cache",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if err := test.Config.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			act, err := test.Config.Env(&v1.JobMetadata{Repository: repo, Trigger: test.Trigger})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// defaultCentralConfigPath is the file in the central config repository which holds the settings
+	defaultCentralConfigPath = "werft.yaml"
+
+	// defaultCentralConfigPollInterval is the time between two polls of the central config repository
+	defaultCentralConfigPollInterval = 5 * time.Minute
+)
+
+// CentralConfigRepoConfig points to a GitHub repository which holds organisation-wide settings. Werft polls the
+// repository and refreshes the settings whenever the file changes.
+type CentralConfigRepoConfig struct {
+	// Repo is the repository as owner/repo or github.com/owner/repo
+	Repo string `yaml:"repo"`
+
+	// Ref is the branch the settings are read from. Defaults to the default branch of the repository.
+	Ref string `yaml:"ref,omitempty"`
+
+	// Path is the file holding the settings. Defaults to werft.yaml.
+	Path string `yaml:"path,omitempty"`
+
+	// PollInterval is the time between two polls of the repository. Pushes to the repository refresh the
+	// settings right away if werft receives its webhook events. Defaults to 5 minutes.
+	PollInterval *executor.Duration `yaml:"pollInterval,omitempty"`
+}
+
+// repository returns the central config repository
+func (c *CentralConfigRepoConfig) repository() (*v1.Repository, error) {
+	segs := strings.Split(c.Repo, "/")
+	switch {
+	case len(segs) == 2:
+		return &v1.Repository{Host: "github.com", Owner: segs[0], Repo: segs[1]}, nil
+	case len(segs) == 3 && segs[0] == "github.com":
+		return &v1.Repository{Host: segs[0], Owner: segs[1], Repo: segs[2]}, nil
+	default:
+		return nil, xerrors.Errorf("invalid central config repository %s: must be owner/repo or github.com/owner/repo", c.Repo)
+	}
+}
+
+// OrgConfig are the organisation-wide settings a central config repository can hold. Settings present in the
+// central config replace those of the server config, e.g. a central config with a repositories section replaces
+// the repositories section of the server config.
+type OrgConfig struct {
+	AllowedRepositories  []string                `yaml:"allowedRepositories,omitempty"`
+	DeniedRepositories   []string                `yaml:"deniedRepositories,omitempty"`
+	JobSpecFragmentRepos []string                `yaml:"jobSpecFragmentRepos,omitempty"`
+	Repositories         []RepositoryConfig      `yaml:"repositories,omitempty"`
+	FallbackJobs         []FallbackJobConfig     `yaml:"fallbackJobs,omitempty"`
+	Projects             []ProjectConfig         `yaml:"projects,omitempty"`
+	Quotas               []QuotaConfig           `yaml:"quotas,omitempty"`
+	ExecutionWindows     []ExecutionWindowConfig `yaml:"executionWindows,omitempty"`
+}
+
+// Validate checks the settings. Execution windows must have a next occurrence after now.
+func (c *OrgConfig) Validate(now time.Time) error {
+	for _, p := range append(c.AllowedRepositories, c.DeniedRepositories...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return xerrors.Errorf("invalid repository pattern %s: %w", p, err)
+		}
+	}
+	for _, fc := range c.FallbackJobs {
+		if err := fc.Validate(); err != nil {
+			return err
+		}
+	}
+	err := validateProjects(c.Projects)
+	if err != nil {
+		return err
+	}
+	err = validateQuotas(c.Quotas)
+	if err != nil {
+		return err
+	}
+	for _, w := range c.ExecutionWindows {
+		if _, err := w.Next(now); err != nil {
+			return err
+		}
+	}
+	for _, rc := range c.Repositories {
+		if rc.BuildCache != nil {
+			if err := rc.BuildCache.Validate(); err != nil {
+				return xerrors.Errorf("%s: %w", rc.Repo, err)
+			}
+		}
+		if rc.Egress != nil {
+			if err := rc.Egress.Validate(); err != nil {
+				return xerrors.Errorf("%s: %w", rc.Repo, err)
+			}
+		}
+		if _, err := logcutter.NewExpressionCutter(rc.LogCutter); err != nil {
+			return xerrors.Errorf("%s: %w", rc.Repo, err)
+		}
+		for _, p := range rc.FlakyJobs {
+			if _, err := path.Match(p, ""); err != nil {
+				return xerrors.Errorf("%s: invalid flaky job %s: %w", rc.Repo, p, err)
+			}
+		}
+		if rc.Approval != nil {
+			if err := rc.Approval.Validate(); err != nil {
+				return xerrors.Errorf("%s: %w", rc.Repo, err)
+			}
+		}
+		if rc.Attach != nil && rc.Attach.Permission != "" && githubPermissionLevels[rc.Attach.Permission] == 0 {
+			return xerrors.Errorf("invalid attach permission %s for %s: must be read, write or admin", rc.Attach.Permission, rc.Repo)
+		}
+		if rc.PodPermission != "" && githubPermissionLevels[rc.PodPermission] == 0 {
+			return xerrors.Errorf("invalid pod permission %s for %s: must be read, write or admin", rc.PodPermission, rc.Repo)
+		}
+	}
+	return nil
+}
+
+// orgConfig returns the organisation-wide settings of the server config
+func (c Config) orgConfig() OrgConfig {
+	return OrgConfig{
+		AllowedRepositories:  c.AllowedRepositories,
+		DeniedRepositories:   c.DeniedRepositories,
+		JobSpecFragmentRepos: c.JobSpecFragmentRepos,
+		Repositories:         c.Repositories,
+		FallbackJobs:         c.FallbackJobs,
+		Projects:             c.Projects,
+		Quotas:               c.Quotas,
+		ExecutionWindows:     c.ExecutionWindows,
+	}
+}
+
+// withOrgConfig returns the config with the settings present in org replacing its own
+func (c Config) withOrgConfig(org *OrgConfig) Config {
+	if org.AllowedRepositories != nil {
+		c.AllowedRepositories = org.AllowedRepositories
+	}
+	if org.DeniedRepositories != nil {
+		c.DeniedRepositories = org.DeniedRepositories
+	}
+	if org.JobSpecFragmentRepos != nil {
+		c.JobSpecFragmentRepos = org.JobSpecFragmentRepos
+	}
+	if org.Repositories != nil {
+		c.Repositories = org.Repositories
+	}
+	if org.FallbackJobs != nil {
+		c.FallbackJobs = org.FallbackJobs
+	}
+	if org.Projects != nil {
+		c.Projects = org.Projects
+	}
+	if org.Quotas != nil {
+		c.Quotas = org.Quotas
+	}
+	if org.ExecutionWindows != nil {
+		c.ExecutionWindows = org.ExecutionWindows
+	}
+	return c
+}
+
+// config returns the server config with the settings of the central config repository applied
+func (srv *Service) config() Config {
+	srv.orgMu.RLock()
+	org := srv.org
+	srv.orgMu.RUnlock()
+
+	if org == nil {
+		return srv.Config
+	}
+	return srv.Config.withOrgConfig(org)
+}
+
+// startCentralConfig loads the settings of the central config repository and keeps them up to date.
+// If werft cannot load them within ctx it starts with the server config alone.
+func (srv *Service) startCentralConfig(ctx context.Context) error {
+	cc := srv.Config.CentralConfig
+	if cc == nil {
+		return nil
+	}
+	_, err := cc.repository()
+	if err != nil {
+		return err
+	}
+
+	srv.orgRefresh = make(chan struct{}, 1)
+	err = srv.RefreshCentralConfig(ctx)
+	if err != nil {
+		log.WithError(err).WithField("repo", cc.Repo).Warn("cannot load central config - starting with the server config")
+	}
+
+	interval := defaultCentralConfigPollInterval
+	if cc.PollInterval != nil {
+		interval = cc.PollInterval.Duration
+	}
+	srv.goBackground(func(ctx context.Context) {
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tick.C:
+			case <-srv.orgRefresh:
+			}
+
+			err := srv.RefreshCentralConfig(ctx)
+			if err != nil {
+				log.WithError(err).WithField("repo", cc.Repo).Warn("cannot refresh central config - keeping the current settings")
+			}
+		}
+	})
+	return nil
+}
+
+// RefreshCentralConfig downloads the settings of the central config repository and applies them if they are valid.
+// Invalid settings are rejected and the current ones stay in place.
+func (srv *Service) RefreshCentralConfig(ctx context.Context) error {
+	cc := srv.Config.CentralConfig
+	if cc == nil {
+		return xerrors.Errorf("no central config repository configured")
+	}
+	repo, err := cc.repository()
+	if err != nil {
+		return err
+	}
+	fn := cc.Path
+	if fn == "" {
+		fn = defaultCentralConfigPath
+	}
+
+	fp := &GitHubContentProvider{
+		Owner:    repo.Owner,
+		Repo:     repo.Repo,
+		Revision: cc.Ref,
+		Client:   srv.GitHub.Client,
+	}
+	in, err := fp.Download(ctx, fn)
+	if err != nil {
+		return xerrors.Errorf("cannot download %s: %w", fn, err)
+	}
+	defer in.Close()
+	content, err := ioutil.ReadAll(in)
+	if err != nil {
+		return xerrors.Errorf("cannot download %s: %w", fn, err)
+	}
+
+	srv.orgMu.RLock()
+	unchanged := srv.org != nil && bytes.Equal(content, srv.orgContent)
+	srv.orgMu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	var org OrgConfig
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	dec.KnownFields(true)
+	err = dec.Decode(&org)
+	if err != nil && err != io.EOF {
+		return xerrors.Errorf("invalid central config: %w", err)
+	}
+	err = org.Validate(srv.now())
+	if err != nil {
+		return xerrors.Errorf("invalid central config: %w", err)
+	}
+
+	srv.orgMu.Lock()
+	srv.org = &org
+	srv.orgContent = content
+	srv.orgMu.Unlock()
+	log.WithField("repo", cc.Repo).WithField("path", fn).Info("applied central config")
+	srv.publishEvent(&v1.ServerEvent{
+		Type:       serverEventConfig,
+		Message:    "applied central config",
+		Attributes: map[string]string{"repo": cc.Repo, "path": fn, "ref": cc.Ref},
+	})
+	return nil
+}
+
+// refreshCentralConfigOnPush refreshes the settings of the central config repository if a push changed its ref
+func (srv *Service) refreshCentralConfigOnPush(event *github.PushEvent) {
+	cc := srv.Config.CentralConfig
+	if cc == nil || srv.orgRefresh == nil {
+		return
+	}
+	repo, err := cc.repository()
+	if err != nil {
+		return
+	}
+	if event.GetRepo().GetOwner().GetName() != repo.Owner || event.GetRepo().GetName() != repo.Repo {
+		return
+	}
+	ref := cc.Ref
+	if ref == "" {
+		ref = event.GetRepo().GetDefaultBranch()
+	}
+	if event.GetRef() != ref && event.GetRef() != "refs/heads/"+ref {
+		return
+	}
+
+	select {
+	case srv.orgRefresh <- struct{}{}:
+	default:
+		// a refresh is pending already
+	}
+}
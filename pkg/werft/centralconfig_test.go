@@ -0,0 +1,77 @@
+package werft_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/google/go-github/github"
+)
+
+func TestRefreshCentralConfig(t *testing.T) {
+	var content string
+	mux := http.NewServeMux()
+	ghsrv := httptest.NewServer(mux)
+	defer ghsrv.Close()
+	mux.HandleFunc("/repos/acme/ci-config/contents/", func(w http.ResponseWriter, r *http.Request) {
+		if ref := r.URL.Query().Get("ref"); ref != "main" {
+			t.Errorf("expected the config to be read from main, not %q", ref)
+		}
+		fmt.Fprintf(w, `[{"name":"werft.yaml","download_url":%q}]`, ghsrv.URL+"/raw/werft.yaml")
+	})
+	mux.HandleFunc("/raw/werft.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, content)
+	})
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(ghsrv.URL + "/")
+	srv := &werft.Service{
+		GitHub: werft.GitHubSetup{Client: client},
+		Config: werft.Config{
+			Projects:      []werft.ProjectConfig{{Name: "server", Repos: []string{"acme/*"}}},
+			CentralConfig: &werft.CentralConfigRepoConfig{Repo: "acme/ci-config", Ref: "main"},
+		},
+	}
+	projects := func() []string {
+		resp, err := srv.ListProjects(context.Background(), &v1.ListProjectsRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res []string
+		for _, p := range resp.Projects {
+			res = append(res, p.Name)
+		}
+		return res
+	}
+
+	tests := []struct {
+		Name        string
+		Content     string
+		Error       bool
+		Expectation string
+	}{
+		{"empty", "", false, "[server]"},
+		{"projects", "projects:\n- name: shop\n  repos: [acme/shop-*]\n", false, "[shop]"},
+		{"invalid", "projects:\n- name: a\n  repos: [acme/a]\n- name: a\n  repos: [acme/b]\n", true, "[shop]"},
+		{"unknown field", "project:\n- name: typo\n", true, "[shop]"},
+		{"server settings", "adminTokens: [foo]\n", true, "[shop]"},
+		{"other settings", "allowedRepositories: [acme/*]\n", false, "[server]"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			content = test.Content
+			err := srv.RefreshCentralConfig(context.Background())
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if act := fmt.Sprint(projects()); act != test.Expectation {
+				t.Errorf("expected projects %s, got %s", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package werft
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/technosophos/moniker"
+)
+
+// Clock tells the time. Tests replace the clock of a service to control time, e.g. using pkg/werft/testing.
+type Clock interface {
+	Now() time.Time
+}
+
+// IDGenerator produces unique identifiers, e.g. for the names of local jobs. IDs must be valid in Kubernetes
+// label values, i.e. consist of alphanumeric characters and dashes.
+type IDGenerator interface {
+	NewID() string
+}
+
+// SystemClock is the clock of the system werft runs on
+type SystemClock struct{}
+
+// Now returns the current time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// MonikerIDs produces random, readable IDs like "gilded-turtle"
+type MonikerIDs struct{}
+
+// NewID returns a random ID
+func (MonikerIDs) NewID() string {
+	return moniker.New().NameSep("-")
+}
+
+// now returns the current time according to the service's clock
+func (srv *Service) now() time.Time {
+	if srv.Clock == nil {
+		return time.Now()
+	}
+	return srv.Clock.Now()
+}
+
+// since returns the time which elapsed since t according to the service's clock
+func (srv *Service) since(t time.Time) time.Duration {
+	return srv.now().Sub(t)
+}
+
+// timestampNow returns the current time according to the service's clock as protobuf timestamp
+func (srv *Service) timestampNow() *timestamp.Timestamp {
+	ts, err := ptypes.TimestampProto(srv.now())
+	if err != nil {
+		// only times before year 1 or after year 10000 cannot be represented
+		return ptypes.TimestampNow()
+	}
+	return ts
+}
+
+// ids returns the ID generator of the service
+func (srv *Service) ids() IDGenerator {
+	if srv.IDs == nil {
+		return MonikerIDs{}
+	}
+	return srv.IDs
+}
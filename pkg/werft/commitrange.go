@@ -0,0 +1,64 @@
+package werft
+
+import (
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-github/github"
+)
+
+const (
+	// maxCommitRangeCommits is the number of commits of a push we record. Job metadata ends up in a pod annotation,
+	// hence we cannot record arbitrarily many.
+	maxCommitRangeCommits = 50
+
+	// maxCommitRangeFiles is the number of changed files of a push we record across all of its commits
+	maxCommitRangeFiles = 1000
+)
+
+// PushCommitRange describes the commits a push brought to a ref. If the push brought more commits or changed more
+// files than we record, the most recent commits are kept and the range is marked as truncated.
+func PushCommitRange(event *github.PushEvent) *v1.CommitRange {
+	res := &v1.CommitRange{
+		Before:     event.GetBefore(),
+		After:      event.GetAfter(),
+		CompareUrl: event.GetCompare(),
+		Forced:     event.GetForced(),
+	}
+
+	commits := event.Commits
+	if len(commits) > maxCommitRangeCommits {
+		commits = commits[len(commits)-maxCommitRangeCommits:]
+		res.Truncated = true
+	}
+
+	res.Commits = make([]*v1.Commit, len(commits))
+	files := 0
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		commit := &v1.Commit{
+			Sha:         c.GetID(),
+			Message:     c.GetMessage(),
+			AuthorName:  c.GetAuthor().GetName(),
+			AuthorEmail: c.GetAuthor().GetEmail(),
+			Url:         c.GetURL(),
+		}
+		if commit.Sha == "" {
+			commit.Sha = c.GetSHA()
+		}
+		if c.Timestamp != nil {
+			commit.Timestamp, _ = ptypes.TimestampProto(c.Timestamp.Time)
+		}
+
+		// files count from the most recent commit, so that those are the ones we record in full
+		if n := len(c.Added) + len(c.Removed) + len(c.Modified); files+n <= maxCommitRangeFiles {
+			commit.Added = c.Added
+			commit.Removed = c.Removed
+			commit.Modified = c.Modified
+			files += n
+		} else {
+			res.Truncated = true
+		}
+		res.Commits[i] = commit
+	}
+	return res
+}
@@ -0,0 +1,70 @@
+package werft_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/google/go-github/github"
+)
+
+func TestPushCommitRange(t *testing.T) {
+	commit := func(id string, files int) github.PushEventCommit {
+		c := github.PushEventCommit{
+			ID:      github.String(id),
+			Message: github.String("fix " + id + "\n\ndetails"),
+			Author:  &github.CommitAuthor{Name: github.String("dev"), Email: github.String("dev@example.com")},
+		}
+		for i := 0; i < files; i++ {
+			c.Modified = append(c.Modified, fmt.Sprintf("file%d.go", i))
+		}
+		return c
+	}
+	commits := func(n, files int) []github.PushEventCommit {
+		var res []github.PushEventCommit
+		for i := 0; i < n; i++ {
+			res = append(res, commit(fmt.Sprintf("c%d", i), files))
+		}
+		return res
+	}
+
+	tests := []struct {
+		Name      string
+		Commits   []github.PushEventCommit
+		Commits0  string
+		Count     int
+		Files     []int
+		Truncated bool
+	}{
+		{"single commit", commits(1, 2), "c0", 1, []int{2}, false},
+		{"too many commits", commits(60, 0), "c10", 50, nil, true},
+		{"too many files", commits(3, 600), "c0", 3, []int{0, 0, 600}, true},
+		{"no commits", nil, "", 0, nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			rng := werft.PushCommitRange(&github.PushEvent{
+				Before:  github.String("before"),
+				After:   github.String("after"),
+				Commits: test.Commits,
+			})
+			if rng.Before != "before" || rng.After != "after" {
+				t.Errorf("unexpected range %s..%s", rng.Before, rng.After)
+			}
+			if len(rng.Commits) != test.Count {
+				t.Fatalf("expected %d commits, got %d", test.Count, len(rng.Commits))
+			}
+			if test.Count > 0 && rng.Commits[0].Sha != test.Commits0 {
+				t.Errorf("expected the oldest commit to be %s, got %s", test.Commits0, rng.Commits[0].Sha)
+			}
+			for i, n := range test.Files {
+				if len(rng.Commits[i].Modified) != n {
+					t.Errorf("expected commit %d to have %d files, got %d", i, n, len(rng.Commits[i].Modified))
+				}
+			}
+			if rng.Truncated != test.Truncated {
+				t.Errorf("expected truncated to be %v", test.Truncated)
+			}
+		})
+	}
+}
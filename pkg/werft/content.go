@@ -7,6 +7,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/go-github/github"
@@ -36,12 +38,35 @@ type ContentProvider interface {
 	Serve(jobName string) error
 }
 
+// CacheableContentProvider is a content provider whose content can be restored from a checkout cache
+type CacheableContentProvider interface {
+	ContentProvider
+
+	// CacheInitContainer builds the container that will initialize the job content like InitContainer does,
+	// but restores the content from the cache mounted at path if possible, and populates the cache otherwise.
+	// Cache entries which haven't been used for maxAge are removed. If the content cannot be cached,
+	// ok is false and InitContainer must be used instead.
+	CacheInitContainer(path string, maxAge time.Duration) (c *corev1.Container, ok bool, err error)
+}
+
 // FileProvider provides access to a single file
 type FileProvider interface {
 	// Download provides access to a single file
 	Download(ctx context.Context, path string) (io.ReadCloser, error)
 }
 
+// errNoRepositoryFiles is returned when reading a file of a repository werft cannot read files of
+var errNoRepositoryFiles = xerrors.New("werft cannot read the files of this repository")
+
+// deployKeyFiles stands in for the files of repositories which are cloned using a deploy key. Werft cannot read
+// their files using the GitHub API, hence their jobs come from fallback jobs or job files passed in when starting them.
+type deployKeyFiles struct{}
+
+// Download always fails with errNoRepositoryFiles
+func (deployKeyFiles) Download(ctx context.Context, path string) (io.ReadCloser, error) {
+	return nil, xerrors.Errorf("cannot download %s: %w", path, errNoRepositoryFiles)
+}
+
 // LocalContentProvider provides access to local files
 type LocalContentProvider struct {
 	TarStream io.Reader
@@ -161,6 +186,10 @@ type GitHubContentProvider struct {
 	Client   *github.Client
 	Auth     GitCredentialHelper
 	Sideload *GitHubContentProviderSideload
+
+	// DeployKey makes the init container clone the repository over SSH. The caller must add the
+	// volume produced by deployKeyVolume to the pod.
+	DeployKey *DeployKeyConfig
 }
 
 // GitHubContentProviderSideload enables side-loading of files after a Git clone
@@ -181,6 +210,58 @@ func (gcp *GitHubContentProvider) Download(ctx context.Context, path string) (io
 
 // InitContainer builds the container that will initialize the job content.
 func (gcp *GitHubContentProvider) InitContainer() (*corev1.Container, error) {
+	return gcp.initContainer(func(cloneCmd string) string { return cloneCmd })
+}
+
+// CacheInitContainer builds the container that will initialize the job content using a checkout cache.
+// Cache entries are keyed by the commit, hence we can only cache revisions which are full commit SHAs.
+func (gcp *GitHubContentProvider) CacheInitContainer(path string, maxAge time.Duration) (c *corev1.Container, ok bool, err error) {
+	if !isCommitSHA(gcp.Revision) {
+		return nil, false, nil
+	}
+
+	cacheFile := filepath.Join(path, gcp.Owner, gcp.Repo, fmt.Sprintf("%s.tar.gz", gcp.Revision))
+	c, err = gcp.initContainer(func(cloneCmd string) string {
+		return fmt.Sprintf(`set -e
+if [ -f %[1]s ] && tar xzf %[1]s; then
+  touch %[1]s
+  echo restored workspace from checkout cache
+else
+  rm -rf ./* ./.[!.]* 2>/dev/null || true
+  %[2]s
+  (mkdir -p %[3]s && tar czf %[1]s.$HOSTNAME . && mv %[1]s.$HOSTNAME %[1]s) || echo cannot populate checkout cache
+fi
+find %[4]s -name '*.tar.gz' -mmin +%[5]d -delete 2>/dev/null || true`, cacheFile, cloneCmd, filepath.Dir(cacheFile), path, int(maxAge.Minutes()))
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return c, true, nil
+}
+
+// sideloadWaitCmd makes the checkout container wait for side-loaded content after cloning
+const sideloadWaitCmd = "; touch /workspace/.cloned; echo waiting for sideload; while [ ! -f /workspace/.ready ]; do [ -f /workspace/.failed ] && exit 1; sleep 1; done"
+
+// isCommitSHA returns true if rev is a full Git commit SHA
+func isCommitSHA(rev string) bool {
+	if len(rev) != 40 {
+		return false
+	}
+	for _, c := range rev {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// initContainer builds the init container. The checkout function receives the command which clones the
+// repository and returns the command which initializes the workspace.
+func (gcp *GitHubContentProvider) initContainer(checkout func(cloneCmd string) string) (*corev1.Container, error) {
+	if gcp.DeployKey != nil {
+		return gcp.sshInitContainer(checkout), nil
+	}
+
 	var (
 		user string
 		pass string
@@ -198,8 +279,9 @@ func (gcp *GitHubContentProvider) InitContainer() (*corev1.Container, error) {
 		cloneCmd = fmt.Sprintf("git clone -c \"credential.helper=/bin/sh -c 'echo username=$GHUSER_SECRET; echo password=$GHPASS_SECRET'\"")
 	}
 	cloneCmd = fmt.Sprintf("%s https://github.com/%s/%s.git .; git checkout %s", cloneCmd, gcp.Owner, gcp.Repo, gcp.Revision)
+	cloneCmd = checkout(cloneCmd)
 	if gcp.Sideload != nil {
-		cloneCmd += "; touch /workspace/.cloned; echo waiting for sideload; while [ ! -f /workspace/.ready ]; do [ -f /workspace/.failed ] && exit 1; sleep 1; done"
+		cloneCmd += sideloadWaitCmd
 	}
 
 	return &corev1.Container{
@@ -222,6 +304,120 @@ func (gcp *GitHubContentProvider) InitContainer() (*corev1.Container, error) {
 	}, nil
 }
 
+const (
+	// deployKeyVolumeName is the name of the volume holding the deploy key in job pods
+	deployKeyVolumeName = "werft-deploy-key"
+
+	// deployKeyPath is where the deploy key volume is mounted in the checkout container
+	deployKeyPath = "/mnt/werft-deploy-key"
+
+	// defaultDeployKeyPrivateKey is the key kubernetes.io/ssh-auth secrets store the private key under
+	defaultDeployKeyPrivateKey = "ssh-privatekey"
+)
+
+// deployKeyVolume produces the volume which makes the deploy key available to the checkout container
+func deployKeyVolume(cfg *DeployKeyConfig) corev1.Volume {
+	privateKey := cfg.PrivateKey
+	if privateKey == "" {
+		privateKey = defaultDeployKeyPrivateKey
+	}
+	items := []corev1.KeyToPath{{Key: privateKey, Path: "id"}}
+	if cfg.KnownHosts != "" {
+		items = append(items, corev1.KeyToPath{Key: cfg.KnownHosts, Path: "known_hosts"})
+	}
+
+	// ssh refuses to use private keys which other users can read
+	mode := int32(0400)
+	return corev1.Volume{
+		Name: deployKeyVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  cfg.SecretName,
+				Items:       items,
+				DefaultMode: &mode,
+			},
+		},
+	}
+}
+
+// checkDeployKeyUnused returns an error if a pod spec refers to the deploy key, i.e. mounts the deploy key volume,
+// brings its own volume of the deploy key secret or reads the secret into environment variables
+func checkDeployKeyUnused(podspec *corev1.PodSpec, cfg *DeployKeyConfig) error {
+	for _, v := range podspec.Volumes {
+		if v.Name == deployKeyVolumeName {
+			return xerrors.Errorf("volume %s is reserved for the deploy key", v.Name)
+		}
+		if v.Secret != nil && v.Secret.SecretName == cfg.SecretName {
+			return xerrors.Errorf("volume %s must not refer to the deploy key secret", v.Name)
+		}
+		if v.Projected == nil {
+			continue
+		}
+		for _, src := range v.Projected.Sources {
+			if src.Secret != nil && src.Secret.Name == cfg.SecretName {
+				return xerrors.Errorf("volume %s must not refer to the deploy key secret", v.Name)
+			}
+		}
+	}
+
+	for _, cs := range [][]corev1.Container{podspec.InitContainers, podspec.Containers} {
+		for _, c := range cs {
+			for _, m := range c.VolumeMounts {
+				if m.Name == deployKeyVolumeName {
+					return xerrors.Errorf("container %s must not mount the deploy key volume %s", c.Name, deployKeyVolumeName)
+				}
+			}
+			for _, e := range c.Env {
+				if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil && e.ValueFrom.SecretKeyRef.Name == cfg.SecretName {
+					return xerrors.Errorf("container %s must not read the deploy key secret into %s", c.Name, e.Name)
+				}
+			}
+			for _, e := range c.EnvFrom {
+				if e.SecretRef != nil && e.SecretRef.Name == cfg.SecretName {
+					return xerrors.Errorf("container %s must not read the deploy key secret into its environment", c.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sshInitContainer builds an init container which clones the repository over SSH using a deploy key
+func (gcp *GitHubContentProvider) sshInitContainer(checkout func(cloneCmd string) string) *corev1.Container {
+	knownHosts := "-o StrictHostKeyChecking=accept-new -o UserKnownHostsFile=/tmp/known_hosts"
+	if gcp.DeployKey.KnownHosts != "" {
+		knownHosts = fmt.Sprintf("-o StrictHostKeyChecking=yes -o UserKnownHostsFile=%s/known_hosts", deployKeyPath)
+	}
+
+	cloneCmd := fmt.Sprintf("git clone git@github.com:%s/%s.git .; git checkout %s", gcp.Owner, gcp.Repo, gcp.Revision)
+	cloneCmd = checkout(cloneCmd)
+	if gcp.Sideload != nil {
+		cloneCmd += sideloadWaitCmd
+	}
+
+	return &corev1.Container{
+		Image: "alpine/git:latest",
+		Command: []string{
+			"sh", "-c",
+			cloneCmd,
+		},
+		Env: []corev1.EnvVar{
+			corev1.EnvVar{
+				Name:  "GIT_SSH_COMMAND",
+				Value: fmt.Sprintf("ssh -i %s/id -o IdentitiesOnly=yes %s", deployKeyPath, knownHosts),
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			corev1.VolumeMount{
+				Name:      deployKeyVolumeName,
+				MountPath: deployKeyPath,
+				ReadOnly:  true,
+			},
+		},
+		WorkingDir: "/workspace",
+	}
+}
+
 // Serve provides additional services required during initialization.
 func (gcp *GitHubContentProvider) Serve(jobName string) error {
 	if gcp.Sideload == nil {
@@ -0,0 +1,52 @@
+package werft
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/executor"
+)
+
+func TestCheckoutInitContainer(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	cache := &CheckoutCacheConfig{ClaimName: "checkouts"}
+	tests := []struct {
+		Name     string
+		Cache    *CheckoutCacheConfig
+		Provider ContentProvider
+		Cached   bool
+		MaxAge   string
+	}{
+		{Name: "no cache", Provider: &GitHubContentProvider{Owner: "32leaves", Repo: "werft", Revision: sha}},
+		{Name: "no claim", Cache: &CheckoutCacheConfig{}, Provider: &GitHubContentProvider{Owner: "32leaves", Repo: "werft", Revision: sha}},
+		{Name: "commit", Cache: cache, Provider: &GitHubContentProvider{Owner: "32leaves", Repo: "werft", Revision: sha}, Cached: true, MaxAge: "-mmin +10080"},
+		{Name: "max age", Cache: &CheckoutCacheConfig{ClaimName: "checkouts", MaxAge: &executor.Duration{Duration: 2 * time.Hour}}, Provider: &GitHubContentProvider{Owner: "32leaves", Repo: "werft", Revision: sha}, Cached: true, MaxAge: "-mmin +120"},
+		{Name: "branch", Cache: cache, Provider: &GitHubContentProvider{Owner: "32leaves", Repo: "werft", Revision: "master"}},
+		{Name: "local content", Cache: cache, Provider: &LocalContentProvider{}},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := &Service{Config: Config{CheckoutCache: test.Cache}}
+			c, cached, err := srv.checkoutInitContainer(test.Provider)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c == nil {
+				t.Fatal("expected an init container")
+			}
+			if cached != test.Cached {
+				t.Errorf("expected cached %v, got %v", test.Cached, cached)
+			}
+
+			cmd := c.Command[len(c.Command)-1]
+			usesCache := strings.Contains(cmd, checkoutCachePath+"/")
+			if usesCache != test.Cached {
+				t.Errorf("expected init container to use the cache: %v, got %s", test.Cached, cmd)
+			}
+			if test.MaxAge != "" && !strings.Contains(cmd, test.MaxAge) {
+				t.Errorf("expected init container to remove checkouts older than %q: %s", test.MaxAge, cmd)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package werft_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestGitHubContentProviderDeployKey(t *testing.T) {
+	tests := []struct {
+		Name       string
+		DeployKey  *werft.DeployKeyConfig
+		CloneURL   string
+		SSHCommand string
+	}{
+		{"https", nil, "https://github.com/32leaves/werft.git", ""},
+		{"deploy key", &werft.DeployKeyConfig{SecretName: "key"}, "git@github.com:32leaves/werft.git", "StrictHostKeyChecking=accept-new"},
+		{"known hosts", &werft.DeployKeyConfig{SecretName: "key", KnownHosts: "known_hosts"}, "git@github.com:32leaves/werft.git", "StrictHostKeyChecking=yes"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cp := &werft.GitHubContentProvider{Owner: "32leaves", Repo: "werft", Revision: "master", DeployKey: test.DeployKey}
+			c, err := cp.InitContainer()
+			if err != nil {
+				t.Fatalf("cannot produce init container: %v", err)
+			}
+
+			cmd := strings.Join(c.Command, " ")
+			if !strings.Contains(cmd, test.CloneURL) {
+				t.Errorf("expected to clone %s, but command was: %s", test.CloneURL, cmd)
+			}
+
+			var sshCommand string
+			for _, e := range c.Env {
+				if e.Name == "GIT_SSH_COMMAND" {
+					sshCommand = e.Value
+				}
+			}
+			if test.SSHCommand == "" && sshCommand != "" {
+				t.Errorf("expected no SSH command, got %s", sshCommand)
+			}
+			if !strings.Contains(sshCommand, test.SSHCommand) {
+				t.Errorf("expected SSH command to contain %s, got %s", test.SSHCommand, sshCommand)
+			}
+			if test.DeployKey != nil && len(c.VolumeMounts) != 1 {
+				t.Errorf("expected the deploy key to be mounted, got %v", c.VolumeMounts)
+			}
+		})
+	}
+}
+
+func TestGitHubContentProviderCacheInitContainer(t *testing.T) {
+	const sha = "0123456789abcdef0123456789abcdef01234567"
+	tests := []struct {
+		Name      string
+		Revision  string
+		DeployKey *werft.DeployKeyConfig
+		Cached    bool
+		CloneURL  string
+	}{
+		{Name: "commit", Revision: sha, Cached: true, CloneURL: "https://github.com/32leaves/werft.git"},
+		{Name: "commit with deploy key", Revision: sha, DeployKey: &werft.DeployKeyConfig{SecretName: "key"}, Cached: true, CloneURL: "git@github.com:32leaves/werft.git"},
+		{Name: "branch", Revision: "master"},
+		{Name: "short commit", Revision: sha[:7]},
+		{Name: "upper case commit", Revision: strings.ToUpper(sha)},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cp := &werft.GitHubContentProvider{Owner: "32leaves", Repo: "werft", Revision: test.Revision, DeployKey: test.DeployKey}
+			c, ok, err := cp.CacheInitContainer("/cache", time.Hour)
+			if err != nil {
+				t.Fatalf("cannot produce init container: %v", err)
+			}
+			if ok != test.Cached {
+				t.Fatalf("expected cached %v, got %v", test.Cached, ok)
+			}
+			if !ok {
+				return
+			}
+
+			cmd := strings.Join(c.Command, " ")
+			for _, exp := range []string{
+				// restores the checkout of the commit
+				"tar xzf /cache/32leaves/werft/" + sha + ".tar.gz",
+				// clones the repository otherwise
+				test.CloneURL,
+				// populates the cache atomically
+				"mv /cache/32leaves/werft/" + sha + ".tar.gz.$HOSTNAME /cache/32leaves/werft/" + sha + ".tar.gz",
+				// removes unused checkouts
+				"find /cache -name '*.tar.gz' -mmin +60 -delete",
+			} {
+				if !strings.Contains(cmd, exp) {
+					t.Errorf("expected init container command to contain %q: %s", exp, cmd)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// defaultCostTeamLabel is the label which assigns jobs to a team if the cost config doesn't say otherwise
+	defaultCostTeamLabel = "team"
+
+	// gpuResource is the resource GPUs are requested as
+	gpuResource corev1.ResourceName = "nvidia.com/gpu"
+
+	// maxCostJobs is the number of most recent jobs a cost summary considers at most
+	maxCostJobs = 10000
+
+	// costPageSize is the number of jobs we read from the store at once when summing up costs
+	costPageSize = 500
+)
+
+// CostConfig prices the resources jobs request. A job costs what its pod requests multiplied by the time the pod ran for.
+type CostConfig struct {
+	// Currency is what the prices are in, e.g. USD
+	Currency string `yaml:"currency,omitempty"`
+
+	// CPUHour is the price of one CPU core for an hour
+	CPUHour float64 `yaml:"cpuHour,omitempty"`
+
+	// MemoryGiBHour is the price of one GiB of memory for an hour
+	MemoryGiBHour float64 `yaml:"memoryGiBHour,omitempty"`
+
+	// GPUHour is the price of one GPU (nvidia.com/gpu) for an hour
+	GPUHour float64 `yaml:"gpuHour,omitempty"`
+
+	// TeamLabel is the job label costs are attributed to teams by. Defaults to team.
+	TeamLabel string `yaml:"teamLabel,omitempty"`
+}
+
+// Validate checks that the prices make sense
+func (c *CostConfig) Validate() error {
+	if c.CPUHour < 0 || c.MemoryGiBHour < 0 || c.GPUHour < 0 {
+		return xerrors.Errorf("costs: prices must not be negative")
+	}
+	if c.CPUHour == 0 && c.MemoryGiBHour == 0 && c.GPUHour == 0 {
+		return xerrors.Errorf("costs: at least one of cpuHour, memoryGiBHour or gpuHour must be set")
+	}
+	return nil
+}
+
+func (c *CostConfig) teamLabel() string {
+	if c.TeamLabel == "" {
+		return defaultCostTeamLabel
+	}
+	return c.TeamLabel
+}
+
+// Estimate computes the cost of a job's pod from the resources it requests and the time it ran for until end.
+// Pods which haven't started yet cost nothing.
+func (c *CostConfig) Estimate(pod *corev1.Pod, end time.Time) *v1.JobCost {
+	res := &v1.JobCost{Currency: c.Currency}
+	res.CpuMillis, res.MemoryBytes, res.Gpus = podRequests(pod.Spec)
+	if start := pod.Status.StartTime; start != nil && end.After(start.Time) {
+		res.Seconds = end.Sub(start.Time).Seconds()
+	}
+
+	hours := res.Seconds / 3600
+	res.Amount = hours * (float64(res.CpuMillis)/1000*c.CPUHour +
+		float64(res.MemoryBytes)/(1<<30)*c.MemoryGiBHour +
+		float64(res.Gpus)*c.GPUHour)
+	return res
+}
+
+// podRequests returns the resources a pod requests like the Kubernetes scheduler sees them: the sum of its containers,
+// or the largest init container if that's more. Containers which set only a limit request that limit.
+func podRequests(spec corev1.PodSpec) (cpuMillis, memoryBytes, gpus int64) {
+	request := func(c corev1.Container, name corev1.ResourceName) (q int64) {
+		v, ok := c.Resources.Requests[name]
+		if !ok {
+			v, ok = c.Resources.Limits[name]
+		}
+		if !ok {
+			return 0
+		}
+		if name == corev1.ResourceCPU {
+			return v.MilliValue()
+		}
+		return v.Value()
+	}
+	max := func(a, b int64) int64 {
+		if a > b {
+			return a
+		}
+		return b
+	}
+
+	for _, c := range spec.Containers {
+		cpuMillis += request(c, corev1.ResourceCPU)
+		memoryBytes += request(c, corev1.ResourceMemory)
+		gpus += request(c, gpuResource)
+	}
+	for _, c := range spec.InitContainers {
+		cpuMillis = max(cpuMillis, request(c, corev1.ResourceCPU))
+		memoryBytes = max(memoryBytes, request(c, corev1.ResourceMemory))
+		gpus = max(gpus, request(c, gpuResource))
+	}
+	return
+}
+
+// attributeCost estimates the cost of a job whose pod changed. Once a job is done its cost doesn't change anymore,
+// and jobs whose pod is gone keep the cost they had.
+func (srv *Service) attributeCost(pod *corev1.Pod, s, prev *v1.JobStatus) {
+	c := srv.Config.Costs
+	if c == nil {
+		return
+	}
+	if pod == nil || (prev != nil && prev.Phase == v1.JobPhase_PHASE_DONE && prev.Cost != nil) {
+		if prev != nil && s.Cost == nil {
+			s.Cost = prev.Cost
+		}
+		return
+	}
+
+	end := srv.now()
+	if s.Phase == v1.JobPhase_PHASE_DONE || s.Phase == v1.JobPhase_PHASE_CLEANUP {
+		if t, err := ptypes.Timestamp(s.Metadata.GetFinished()); err == nil {
+			end = t
+		}
+	}
+	s.Cost = c.Estimate(pod, end)
+}
+
+// Summarise sums up the cost of jobs by the groupings. Matrix jobs count by their children, which run the pods.
+// Jobs without a cost, e.g. because they never ran, don't count.
+func (c *CostConfig) Summarise(jobs []v1.JobStatus, groupBy []v1.CostGrouping) []*v1.JobCostGroup {
+	var (
+		idx = make(map[string]*v1.JobCostGroup)
+		res []*v1.JobCostGroup
+	)
+	for _, job := range jobs {
+		md := job.GetMetadata()
+		if job.Cost == nil || md == nil || len(md.Children) > 0 {
+			continue
+		}
+
+		var grp v1.JobCostGroup
+		for _, g := range groupBy {
+			switch g {
+			case v1.CostGrouping_COST_BY_REPOSITORY:
+				grp.Repository = fmt.Sprintf("%s/%s/%s", md.GetRepository().GetHost(), md.GetRepository().GetOwner(), md.GetRepository().GetRepo())
+			case v1.CostGrouping_COST_BY_TEAM:
+				grp.Team = md.Labels[c.teamLabel()]
+			case v1.CostGrouping_COST_BY_MONTH:
+				if t, err := ptypes.Timestamp(md.Created); err == nil {
+					grp.Month = t.UTC().Format("2006-01")
+				}
+			}
+		}
+		key := grp.Repository + "\x00" + grp.Team + "\x00" + grp.Month
+		sum, ok := idx[key]
+		if !ok {
+			sum = &grp
+			idx[key] = sum
+			res = append(res, sum)
+		}
+		sum.Amount += job.Cost.Amount
+		sum.Seconds += job.Cost.Seconds
+		sum.Jobs++
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Repository != b.Repository {
+			return a.Repository < b.Repository
+		}
+		if a.Team != b.Team {
+			return a.Team < b.Team
+		}
+		return a.Month < b.Month
+	})
+	return res
+}
+
+// GetJobCosts sums up the estimated cost of jobs per repository, team and/or month
+func (srv *Service) GetJobCosts(ctx context.Context, req *v1.GetJobCostsRequest) (*v1.GetJobCostsResponse, error) {
+	c := srv.Config.Costs
+	if c == nil {
+		return nil, status.Error(codes.FailedPrecondition, "costs are not configured")
+	}
+	var from, to time.Time
+	if req.From != nil {
+		t, err := ptypes.Timestamp(req.From)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+		}
+		from = t
+	}
+	if req.To != nil {
+		t, err := ptypes.Timestamp(req.To)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+		}
+		to = t
+	}
+
+	var filter []*v1.FilterExpression
+	if repo := req.Repository; repo != nil {
+		for _, t := range []struct{ Field, Value string }{{"repo.host", repo.Host}, {"repo.owner", repo.Owner}, {"repo.repo", repo.Repo}} {
+			if t.Value == "" {
+				continue
+			}
+			filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{{Field: t.Field, Value: t.Value, Operation: v1.FilterOp_OP_EQUALS}}})
+		}
+	}
+
+	// the store can't filter by time, hence we go through the jobs from the most recent one until we're past from
+	ctx = store.WithStaleReads(ctx)
+	var (
+		order     = []*v1.OrderExpression{{Field: "created", Ascending: false}}
+		jobs      []v1.JobStatus
+		truncated bool
+	)
+pages:
+	for start := 0; ; start += costPageSize {
+		if start >= maxCostJobs {
+			truncated = true
+			break
+		}
+		page, total, err := srv.Jobs.Find(ctx, filter, order, start, costPageSize)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		for _, job := range page {
+			created, err := ptypes.Timestamp(job.GetMetadata().GetCreated())
+			if err != nil {
+				continue
+			}
+			if !from.IsZero() && created.Before(from) {
+				break pages
+			}
+			if !to.IsZero() && !created.Before(to) {
+				continue
+			}
+			jobs = append(jobs, job)
+		}
+		if len(page) < costPageSize || start+len(page) >= total {
+			break
+		}
+	}
+
+	res := &v1.GetJobCostsResponse{
+		Currency:  c.Currency,
+		Groups:    c.Summarise(jobs, req.GroupBy),
+		Truncated: truncated,
+	}
+	for _, g := range res.Groups {
+		res.Total += g.Amount
+	}
+	return res, nil
+}
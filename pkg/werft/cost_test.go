@@ -0,0 +1,183 @@
+package werft_test
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCostEstimate(t *testing.T) {
+	resources := func(req, lim corev1.ResourceList) corev1.ResourceRequirements {
+		return corev1.ResourceRequirements{Requests: req, Limits: lim}
+	}
+	start := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	cfg := werft.CostConfig{Currency: "USD", CPUHour: 0.04, MemoryGiBHour: 0.005, GPUHour: 1}
+
+	tests := []struct {
+		Name        string
+		Spec        corev1.PodSpec
+		Started     bool
+		Expectation v1.JobCost
+	}{
+		{
+			Name: "containers",
+			Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Resources: resources(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m"), corev1.ResourceMemory: resource.MustParse("2Gi")}, nil)},
+				{Resources: resources(nil, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), "nvidia.com/gpu": resource.MustParse("1")})},
+			}},
+			Started:     true,
+			Expectation: v1.JobCost{Amount: 2*0.04 + 2*0.005 + 1, Currency: "USD", CpuMillis: 2000, MemoryBytes: 2 << 30, Gpus: 1, Seconds: 3600},
+		},
+		{
+			Name: "larger init container",
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Resources: resources(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}, nil)}},
+				Containers:     []corev1.Container{{Resources: resources(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}, nil)}},
+			},
+			Started:     true,
+			Expectation: v1.JobCost{Amount: 4 * 0.04, Currency: "USD", CpuMillis: 4000, Seconds: 3600},
+		},
+		{
+			Name:        "not started",
+			Spec:        corev1.PodSpec{Containers: []corev1.Container{{Resources: resources(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}, nil)}}},
+			Expectation: v1.JobCost{Currency: "USD", CpuMillis: 1000},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: test.Spec}
+			if test.Started {
+				pod.Status.StartTime = &metav1.Time{Time: start}
+			}
+			act := cfg.Estimate(pod, start.Add(time.Hour))
+			if math.Abs(act.Amount-test.Expectation.Amount) > 1e-9 {
+				t.Errorf("expected amount %f, got %f", test.Expectation.Amount, act.Amount)
+			}
+			act.Amount = test.Expectation.Amount
+			if !proto.Equal(act, &test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, *act)
+			}
+		})
+	}
+}
+
+func TestGetJobCosts(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	job := func(name, repo, team string, created time.Time, amount float64, children ...string) v1.JobStatus {
+		ts, _ := ptypes.TimestampProto(created)
+		md := &v1.JobMetadata{
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: repo},
+			Created:    ts,
+			Children:   children,
+		}
+		if team != "" {
+			md.Labels = map[string]string{"squad": team}
+		}
+		res := v1.JobStatus{Name: name, Phase: v1.JobPhase_PHASE_DONE, Metadata: md, Conditions: &v1.JobConditions{}}
+		if amount > 0 {
+			res.Cost = &v1.JobCost{Amount: amount, Currency: "EUR", Seconds: 60}
+		}
+		return res
+	}
+	for _, j := range []v1.JobStatus{
+		job("werft.1", "werft", "infra", time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC), 1),
+		job("werft.2", "werft", "infra", time.Date(2020, 2, 10, 0, 0, 0, 0, time.UTC), 2),
+		job("shop.1", "shop", "web", time.Date(2020, 2, 11, 0, 0, 0, 0, time.UTC), 4),
+		job("shop.2", "shop", "", time.Date(2020, 2, 12, 0, 0, 0, 0, time.UTC), 8),
+		job("shop.3", "shop", "web", time.Date(2020, 2, 13, 0, 0, 0, 0, time.UTC), 16, "shop.3.a"),
+		job("shop.4", "shop", "web", time.Date(2020, 2, 14, 0, 0, 0, 0, time.UTC), 0),
+		job("shop.5", "shop", "web", time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), 32),
+	} {
+		err := jobs.Store(context.Background(), j)
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+
+	_, err := (&werft.Service{Jobs: jobs}).GetJobCosts(context.Background(), &v1.GetJobCostsRequest{})
+	if code := status.Code(err); code != codes.FailedPrecondition {
+		t.Errorf("expected %v without a cost config, got %v", codes.FailedPrecondition, code)
+	}
+
+	srv := &werft.Service{
+		Jobs:   jobs,
+		Config: werft.Config{Costs: &werft.CostConfig{Currency: "EUR", CPUHour: 1, TeamLabel: "squad"}},
+	}
+	from, _ := ptypes.TimestampProto(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC))
+	to, _ := ptypes.TimestampProto(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		Name        string
+		Req         *v1.GetJobCostsRequest
+		Expectation []v1.JobCostGroup
+		Total       float64
+	}{
+		{
+			Name:        "all jobs",
+			Req:         &v1.GetJobCostsRequest{},
+			Expectation: []v1.JobCostGroup{{Amount: 47, Jobs: 5, Seconds: 300}},
+			Total:       47,
+		},
+		{
+			Name: "by team and month",
+			Req:  &v1.GetJobCostsRequest{GroupBy: []v1.CostGrouping{v1.CostGrouping_COST_BY_TEAM, v1.CostGrouping_COST_BY_MONTH}},
+			Expectation: []v1.JobCostGroup{
+				{Month: "2020-02", Amount: 8, Jobs: 1, Seconds: 60},
+				{Team: "infra", Month: "2020-01", Amount: 1, Jobs: 1, Seconds: 60},
+				{Team: "infra", Month: "2020-02", Amount: 2, Jobs: 1, Seconds: 60},
+				{Team: "web", Month: "2020-02", Amount: 4, Jobs: 1, Seconds: 60},
+				{Team: "web", Month: "2020-03", Amount: 32, Jobs: 1, Seconds: 60},
+			},
+			Total: 47,
+		},
+		{
+			Name: "by repository in February",
+			Req:  &v1.GetJobCostsRequest{GroupBy: []v1.CostGrouping{v1.CostGrouping_COST_BY_REPOSITORY}, From: from, To: to},
+			Expectation: []v1.JobCostGroup{
+				{Repository: "github.com/32leaves/shop", Amount: 12, Jobs: 2, Seconds: 120},
+				{Repository: "github.com/32leaves/werft", Amount: 2, Jobs: 1, Seconds: 60},
+			},
+			Total: 14,
+		},
+		{
+			Name:        "of a repository",
+			Req:         &v1.GetJobCostsRequest{Repository: &v1.Repository{Owner: "32leaves", Repo: "werft"}},
+			Expectation: []v1.JobCostGroup{{Amount: 3, Jobs: 2, Seconds: 120}},
+			Total:       3,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.GetJobCosts(context.Background(), test.Req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Currency != "EUR" {
+				t.Errorf("expected currency EUR, got %s", resp.Currency)
+			}
+			if resp.Total != test.Total {
+				t.Errorf("expected total %f, got %f", test.Total, resp.Total)
+			}
+			if len(resp.Groups) != len(test.Expectation) {
+				t.Fatalf("expected %d groups, got %d: %v", len(test.Expectation), len(resp.Groups), resp.Groups)
+			}
+			for i, g := range resp.Groups {
+				if !proto.Equal(g, &test.Expectation[i]) {
+					t.Errorf("group %d: expected %v, got %v", i, test.Expectation[i], *g)
+				}
+			}
+		})
+	}
+}
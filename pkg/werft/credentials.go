@@ -0,0 +1,99 @@
+package werft
+
+import (
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/credentials"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// setupCredentials creates the credential providers configured for the service
+func (srv *Service) setupCredentials() error {
+	srv.credentials = make(map[string]credentials.Provider, len(srv.Config.Credentials))
+	for _, cfg := range srv.Config.Credentials {
+		if _, exists := srv.credentials[cfg.Name]; exists {
+			return xerrors.Errorf("credentials %s are configured more than once", cfg.Name)
+		}
+
+		p, err := credentials.New(cfg)
+		if err != nil {
+			return err
+		}
+		srv.credentials[cfg.Name] = p
+	}
+	return nil
+}
+
+// checkCredentialAccess makes sure the pod spec of a job cannot obtain credentials behind werft's back, i.e. neither
+// runs with the service account of credentials nor projects service account tokens itself.
+func (srv *Service) checkCredentialAccess(podspec *corev1.PodSpec) error {
+	if len(srv.credentials) == 0 {
+		return nil
+	}
+
+	for _, vol := range podspec.Volumes {
+		if credentials.IsTokenProjection(vol) {
+			return xerrors.Errorf("volume %s: jobs must request credentials rather than project service account tokens", vol.Name)
+		}
+	}
+	for name, p := range srv.credentials {
+		if podspec.ServiceAccountName == p.ServiceAccount() || podspec.DeprecatedServiceAccount == p.ServiceAccount() {
+			return xerrors.Errorf("service account %s is reserved for credentials %s", p.ServiceAccount(), name)
+		}
+	}
+	return nil
+}
+
+// jobCredentials returns the credential providers for all credentials a job requests, provided their policy allows the job to use them,
+// and makes the job's pod run with their service account.
+func (srv *Service) jobCredentials(md *v1.JobMetadata, jobspec *repoconfig.JobSpec, podspec *corev1.PodSpec) ([]credentials.Provider, error) {
+	if len(jobspec.Credentials) == 0 {
+		return nil, nil
+	}
+
+	var (
+		res            = make([]credentials.Provider, 0, len(jobspec.Credentials))
+		serviceAccount string
+	)
+	for _, name := range jobspec.Credentials {
+		var cfg *credentials.Config
+		for i, c := range srv.Config.Credentials {
+			if c.Name == name {
+				cfg = &srv.Config.Credentials[i]
+				break
+			}
+		}
+		p, ok := srv.credentials[name]
+		if cfg == nil || !ok {
+			return nil, xerrors.Errorf("unknown credentials %s", name)
+		}
+		if !policyAllowsJob(cfg.Repositories, cfg.Refs, md) {
+			return nil, xerrors.Errorf("credentials %s are not available to jobs of this repository or ref", name)
+		}
+		if serviceAccount != "" && serviceAccount != p.ServiceAccount() {
+			return nil, xerrors.Errorf("credentials %s use a different service account than %s, jobs can only request credentials of one service account", name, jobspec.Credentials[0])
+		}
+
+		serviceAccount = p.ServiceAccount()
+		res = append(res, p)
+	}
+
+	// The service account of a class the job requested explicitly must match, while the default class (or no class at all) gives way.
+	// The pod spec itself cannot set the service account: checkCredentialAccess or applyServiceAccount would have rejected it.
+	if jobspec.ServiceAccount != "" && podspec.ServiceAccountName != serviceAccount {
+		return nil, xerrors.Errorf("job requests service account %s, but its credentials need service account %s", jobspec.ServiceAccount, serviceAccount)
+	}
+	if len(srv.Config.ServiceAccounts) == 0 && (podspec.ServiceAccountName != "" || podspec.DeprecatedServiceAccount != "") {
+		return nil, xerrors.Errorf("jobs which request credentials cannot set a service account on their pod")
+	}
+	podspec.ServiceAccountName = serviceAccount
+	podspec.DeprecatedServiceAccount = ""
+	if jobspec.ServiceAccount == "" {
+		// the job needs the projected token only, not access to the Kubernetes API
+		automount := false
+		podspec.AutomountServiceAccountToken = &automount
+	}
+
+	return res, nil
+}
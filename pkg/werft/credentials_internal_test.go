@@ -0,0 +1,156 @@
+package werft
+
+import (
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/credentials"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestJobCredentials(t *testing.T) {
+	var (
+		master = &v1.Repository{Host: "github.com", Owner: "acme", Repo: "shop", Ref: "refs/heads/master"}
+		branch = &v1.Repository{Host: "github.com", Owner: "acme", Repo: "shop", Ref: "refs/heads/feature"}
+		aws    = func(name, sa string, refs ...string) credentials.Config {
+			return credentials.Config{Name: name, ServiceAccount: sa, Refs: refs, AWS: &credentials.AWSConfig{RoleARN: "arn:aws:iam::123456789012:role/" + name}}
+		}
+	)
+
+	tests := []struct {
+		Name            string
+		Credentials     []credentials.Config
+		ServiceAccounts []ServiceAccountConfig
+		Metadata        *v1.JobMetadata
+		JobSpec         repoconfig.JobSpec
+		PodSpec         corev1.PodSpec
+		Error           bool
+		ServiceAccount  string
+	}{
+		{
+			Name:           "verified",
+			Credentials:    []credentials.Config{aws("deploy", "werft-deploy", "refs/heads/master")},
+			Metadata:       &v1.JobMetadata{Repository: master, SourceVerified: true},
+			JobSpec:        repoconfig.JobSpec{Credentials: []string{"deploy"}},
+			ServiceAccount: "werft-deploy",
+		},
+		{
+			Name:        "unverified",
+			Credentials: []credentials.Config{aws("deploy", "werft-deploy", "refs/heads/master")},
+			Metadata:    &v1.JobMetadata{Repository: master},
+			JobSpec:     repoconfig.JobSpec{Credentials: []string{"deploy"}},
+			Error:       true,
+		},
+		{
+			Name:           "unverified without policy",
+			Credentials:    []credentials.Config{aws("deploy", "werft-deploy")},
+			Metadata:       &v1.JobMetadata{Repository: branch},
+			JobSpec:        repoconfig.JobSpec{Credentials: []string{"deploy"}},
+			ServiceAccount: "werft-deploy",
+		},
+		{
+			Name:        "other ref",
+			Credentials: []credentials.Config{aws("deploy", "werft-deploy", "refs/heads/master")},
+			Metadata:    &v1.JobMetadata{Repository: branch, SourceVerified: true},
+			JobSpec:     repoconfig.JobSpec{Credentials: []string{"deploy"}},
+			Error:       true,
+		},
+		{
+			Name:        "unknown credentials",
+			Credentials: []credentials.Config{aws("deploy", "werft-deploy")},
+			Metadata:    &v1.JobMetadata{Repository: master},
+			JobSpec:     repoconfig.JobSpec{Credentials: []string{"other"}},
+			Error:       true,
+		},
+		{
+			Name:        "different service accounts",
+			Credentials: []credentials.Config{aws("deploy", "werft-deploy"), aws("audit", "werft-audit")},
+			Metadata:    &v1.JobMetadata{Repository: master},
+			JobSpec:     repoconfig.JobSpec{Credentials: []string{"deploy", "audit"}},
+			Error:       true,
+		},
+		{
+			Name:            "default class gives way",
+			Credentials:     []credentials.Config{aws("deploy", "werft-deploy")},
+			ServiceAccounts: []ServiceAccountConfig{{Name: "default", ServiceAccount: "werft-job"}},
+			Metadata:        &v1.JobMetadata{Repository: master},
+			JobSpec:         repoconfig.JobSpec{Credentials: []string{"deploy"}},
+			ServiceAccount:  "werft-deploy",
+		},
+		{
+			Name:            "requested class conflicts",
+			Credentials:     []credentials.Config{aws("deploy", "werft-deploy")},
+			ServiceAccounts: []ServiceAccountConfig{{Name: "builder", ServiceAccount: "werft-builder"}},
+			Metadata:        &v1.JobMetadata{Repository: master},
+			JobSpec:         repoconfig.JobSpec{Credentials: []string{"deploy"}, ServiceAccount: "builder"},
+			Error:           true,
+		},
+		{
+			Name:        "service account on pod",
+			Credentials: []credentials.Config{aws("deploy", "werft-deploy")},
+			Metadata:    &v1.JobMetadata{Repository: master},
+			JobSpec:     repoconfig.JobSpec{Credentials: []string{"deploy"}},
+			PodSpec:     corev1.PodSpec{ServiceAccountName: "admin"},
+			Error:       true,
+		},
+		{
+			Name:        "credentials service account on pod",
+			Credentials: []credentials.Config{aws("deploy", "werft-deploy")},
+			Metadata:    &v1.JobMetadata{Repository: master},
+			PodSpec:     corev1.PodSpec{ServiceAccountName: "werft-deploy"},
+			Error:       true,
+		},
+		{
+			Name:        "token projection on pod",
+			Credentials: []credentials.Config{aws("deploy", "werft-deploy")},
+			Metadata:    &v1.JobMetadata{Repository: master},
+			PodSpec: corev1.PodSpec{Volumes: []corev1.Volume{{
+				Name: "token",
+				VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{Sources: []corev1.VolumeProjection{
+					{ServiceAccountToken: &corev1.ServiceAccountTokenProjection{Audience: "sts.amazonaws.com", Path: "token"}},
+				}}},
+			}}},
+			Error: true,
+		},
+		{
+			Name:     "no credentials configured",
+			Metadata: &v1.JobMetadata{Repository: master},
+			PodSpec:  corev1.PodSpec{ServiceAccountName: "admin"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := &Service{Config: Config{Credentials: test.Credentials, ServiceAccounts: test.ServiceAccounts}}
+			err := srv.setupCredentials()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			podspec := test.PodSpec
+			err = srv.checkCredentialAccess(&podspec)
+			if err == nil {
+				err = srv.applyServiceAccount(test.Metadata, &test.JobSpec, &podspec)
+			}
+			var creds []credentials.Provider
+			if err == nil {
+				creds, err = srv.jobCredentials(test.Metadata, &test.JobSpec, &podspec)
+			}
+			if test.Error {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(creds) != len(test.JobSpec.Credentials) {
+				t.Errorf("expected %d credential providers, got %d", len(test.JobSpec.Credentials), len(creds))
+			}
+			if test.ServiceAccount != "" && podspec.ServiceAccountName != test.ServiceAccount {
+				t.Errorf("expected the pod to run with service account %s, got %s", test.ServiceAccount, podspec.ServiceAccountName)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package werft
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+func TestReplayedJobs(t *testing.T) {
+	paths := []string{".werft/build.yaml", ".werft/lint.yaml", ".werft/deploy.yaml"}
+	tests := []struct {
+		Name        string
+		Replay      []string
+		Expectation []string
+	}{
+		{"no replay", nil, paths},
+		{"failed jobs", []string{".werft/deploy.yaml", ".werft/build.yaml"}, []string{".werft/build.yaml", ".werft/deploy.yaml"}},
+		{"no longer routed", []string{".werft/gone.yaml"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := context.Background()
+			if test.Replay != nil {
+				ctx = withReplayJobs(ctx, test.Replay)
+			}
+			act := replayedJobs(ctx, log.WithField("test", t.Name()), paths)
+			if !reflect.DeepEqual(act, test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestPutDeadLetterFailedJobs(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		logger = log.WithField("test", t.Name())
+		dls    = store.NewInMemoryDeadLetters()
+		srv    = &Service{DeadLetters: dls}
+	)
+
+	steps := []struct {
+		Error      error
+		FailedJobs []string
+	}{
+		{xerrors.Errorf("cannot handle push: %w", &jobsNotStartedError{Paths: []string{"a.yaml", "b.yaml"}}), []string{"a.yaml", "b.yaml"}},
+		// a replay which fails before starting jobs must not replay the jobs which started before
+		{xerrors.Errorf("cannot get repo config"), []string{"a.yaml", "b.yaml"}},
+		{&jobsNotStartedError{Paths: []string{"b.yaml"}}, []string{"b.yaml"}},
+	}
+	for i, step := range steps {
+		srv.putDeadLetter(ctx, logger, "delivery", "push", []byte("{}"), step.Error)
+
+		dl, err := dls.Get(ctx, "delivery")
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(dl.FailedJobs, step.FailedJobs) {
+			t.Errorf("step %d: expected failed jobs %v, got %v", i, step.FailedJobs, dl.FailedJobs)
+		}
+		if dl.Attempts != int32(i+1) {
+			t.Errorf("step %d: expected %d attempts, got %d", i, i+1, dl.Attempts)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeadLetterAccess(t *testing.T) {
+	tests := []struct {
+		Name        string
+		AdminTokens []string
+		Token       string
+		List        codes.Code
+		Replay      codes.Code
+	}{
+		{"not enabled", nil, "secret", codes.Unavailable, codes.Unavailable},
+		{"no token", []string{"secret"}, "", codes.PermissionDenied, codes.PermissionDenied},
+		{"invalid token", []string{"secret"}, "guess", codes.PermissionDenied, codes.PermissionDenied},
+		{"admin", []string{"secret"}, "secret", codes.OK, codes.NotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := context.Background()
+			dls := store.NewInMemoryDeadLetters()
+			err := dls.Put(ctx, v1.DeadLetter{Id: "delivery", EventType: "push", Payload: []byte("{}")})
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := &werft.Service{DeadLetters: dls, Config: werft.Config{AdminTokens: test.AdminTokens}}
+
+			resp, err := srv.ListDeadLetters(ctx, &v1.ListDeadLettersRequest{Token: test.Token})
+			if code := status.Code(err); code != test.List {
+				t.Errorf("list: expected %v, got %v", test.List, err)
+			}
+			if err == nil && len(resp.Result) != 1 {
+				t.Errorf("list: expected one dead letter, got %v", resp.Result)
+			}
+
+			// the event isn't replayed for an unknown ID even with a valid token
+			_, err = srv.ReplayDeadLetter(ctx, &v1.ReplayDeadLetterRequest{Id: "unknown", Token: test.Token})
+			if code := status.Code(err); code != test.Replay {
+				t.Errorf("replay: expected %v, got %v", test.Replay, err)
+			}
+		})
+	}
+}
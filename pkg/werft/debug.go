@@ -0,0 +1,54 @@
+package werft
+
+import (
+	"strconv"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// annotationDebug keeps the pod of a failed job around for debugging. Its value is either true, in which case
+	// the pod is kept for the configured debug keep-alive time, or the time to keep the pod, e.g. 1h.
+	annotationDebug = "debug"
+
+	// defaultDebugKeepAlive is the time the pod of a failed job is kept for debugging if the config doesn't say otherwise
+	defaultDebugKeepAlive = 30 * time.Minute
+
+	// maxDebugKeepAlive limits the time users can keep the pod of a failed job around
+	maxDebugKeepAlive = 4 * time.Hour
+)
+
+// debugKeepAlive returns the time the pod of a job should be kept around for debugging if the job fails.
+// Zero means the pod is not kept.
+func (srv *Service) debugKeepAlive(md *v1.JobMetadata) (time.Duration, error) {
+	var val string
+	for _, a := range md.Annotations {
+		if a.Key == annotationDebug {
+			val = a.Value
+		}
+	}
+	if val == "" {
+		return 0, nil
+	}
+
+	if b, err := strconv.ParseBool(val); err == nil {
+		if !b {
+			return 0, nil
+		}
+		if srv.Config.DebugKeepAlive != nil {
+			return srv.Config.DebugKeepAlive.Duration, nil
+		}
+		return defaultDebugKeepAlive, nil
+	}
+
+	keepAlive, err := time.ParseDuration(val)
+	if err != nil || keepAlive <= 0 {
+		return 0, xerrors.Errorf("invalid %s annotation %q: must be true, false or a duration", annotationDebug, val)
+	}
+	if keepAlive > maxDebugKeepAlive {
+		keepAlive = maxDebugKeepAlive
+	}
+	return keepAlive, nil
+}
@@ -0,0 +1,149 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// labelDeleted marks jobs which were deleted, i.e. are hidden from job listings
+	labelDeleted = "deleted"
+
+	// deleteJobPermission is the GitHub permission users need on the repository of a job to delete it
+	deleteJobPermission = "write"
+)
+
+// notDeletedFilter excludes deleted jobs from a job listing
+func notDeletedFilter() *v1.FilterExpression {
+	return &v1.FilterExpression{Terms: []*v1.FilterTerm{
+		{Field: filterexpr.LabelFieldPrefix + labelDeleted, Operation: v1.FilterOp_OP_EXISTS, Negate: true},
+	}}
+}
+
+// DeleteJob hides a finished job, including the jobs of its matrix, from job listings
+func (srv *Service) DeleteJob(ctx context.Context, req *v1.DeleteJobRequest) (*v1.DeleteJobResponse, error) {
+	name := srv.resolveJobName(ctx, req.Name)
+	job, err := srv.Jobs.Get(ctx, name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if job.Phase != v1.JobPhase_PHASE_DONE {
+		return nil, status.Error(codes.FailedPrecondition, "only finished jobs can be deleted")
+	}
+	if job.Metadata.GetRepository() == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s has no repository and cannot be deleted", name)
+	}
+
+	user, err := srv.authorizeGitHubUser(ctx, req.GithubToken, job.Metadata.Repository, deleteJobPermission, "delete jobs")
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range append([]string{name}, job.Metadata.Children...) {
+		err = srv.markJobDeleted(ctx, n, user)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	log.WithFields(jobLogFields(name, job.Metadata)).WithField("user", user).Info("job deleted")
+
+	return &v1.DeleteJobResponse{}, nil
+}
+
+// markJobDeleted labels a job as deleted and records who deleted it. Jobs which aren't in the store are skipped.
+func (srv *Service) markJobDeleted(ctx context.Context, name, user string) error {
+	job, err := srv.Jobs.Get(ctx, name)
+	if err == store.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, deleted := job.Metadata.Labels[labelDeleted]; deleted {
+		return nil
+	}
+
+	if job.Metadata.Labels == nil {
+		job.Metadata.Labels = make(map[string]string)
+	}
+	job.Metadata.Labels[labelDeleted] = "true"
+	err = srv.Jobs.Store(ctx, *job)
+	if err != nil {
+		return xerrors.Errorf("cannot delete %s: %w", name, err)
+	}
+	srv.addJobEvent(name, v1.JobEvent{Type: v1.JobEventType_EVENT_DELETED, Message: fmt.Sprintf("deleted by %s", user)})
+	return nil
+}
+
+// PurgeJob removes a finished job, including the jobs of its matrix, and all of their data
+func (srv *Service) PurgeJob(ctx context.Context, req *v1.PurgeJobRequest) (*v1.PurgeJobResponse, error) {
+	if err := srv.requireAdmin(req.Token, "purging jobs"); err != nil {
+		return nil, err
+	}
+
+	name := srv.resolveJobName(ctx, req.Name)
+	job, err := srv.Jobs.Get(ctx, name)
+	if err == store.ErrNotFound && srv.Archive != nil {
+		job, err = srv.Archive.Get(ctx, name)
+	}
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if job.Phase != v1.JobPhase_PHASE_DONE {
+		return nil, status.Error(codes.FailedPrecondition, "only finished jobs can be purged")
+	}
+
+	res := append([]string{name}, job.Metadata.GetChildren()...)
+	for _, n := range res {
+		err = srv.purgeJob(ctx, n)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+	log.WithField("jobs", res).Info("jobs purged")
+
+	return &v1.PurgeJobResponse{Jobs: res}, nil
+}
+
+// purgeJob removes a job from all stores, i.e. its logs, image builds, archived record, custom resource and record.
+// The record goes last, so that a purge which fails half-way can be tried again.
+func (srv *Service) purgeJob(ctx context.Context, name string) error {
+	err := srv.Logs.Delete(name)
+	if err != nil && err != store.ErrNotFound {
+		return xerrors.Errorf("cannot purge logs of %s: %w", name, err)
+	}
+	if srv.Images != nil {
+		err = srv.Images.DeleteJob(ctx, name)
+		if err != nil {
+			return xerrors.Errorf("cannot purge image builds of %s: %w", name, err)
+		}
+	}
+	if srv.Archive != nil {
+		err = srv.Archive.Delete(ctx, name)
+		if err != nil && err != store.ErrNotFound {
+			return xerrors.Errorf("cannot purge archived %s: %w", name, err)
+		}
+	}
+	err = srv.deleteJobResource(name)
+	if err != nil {
+		return err
+	}
+	err = srv.Jobs.Purge(ctx, name)
+	if err != nil {
+		return xerrors.Errorf("cannot purge %s: %w", name, err)
+	}
+	return nil
+}
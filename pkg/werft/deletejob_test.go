@@ -0,0 +1,104 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListJobsHidesDeletedJobs(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	for _, j := range []v1.JobStatus{
+		{Name: "foo.1", Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{Labels: map[string]string{"deleted": "true"}}},
+		{Name: "foo.2", Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{}},
+	} {
+		err := jobs.Store(context.Background(), j)
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+	srv := &werft.Service{Jobs: jobs}
+
+	tests := []struct {
+		Name           string
+		IncludeDeleted bool
+		Expectation    int
+	}{
+		{"default", false, 1},
+		{"include deleted", true, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.ListJobs(context.Background(), &v1.ListJobsRequest{IncludeDeleted: test.IncludeDeleted})
+			if err != nil {
+				t.Fatalf("cannot list jobs: %v", err)
+			}
+			if len(resp.Result) != test.Expectation {
+				t.Errorf("expected %d jobs, got %d", test.Expectation, len(resp.Result))
+			}
+		})
+	}
+}
+
+func TestPurgeJob(t *testing.T) {
+	tests := []struct {
+		Name        string
+		AdminTokens []string
+		Token       string
+		Phase       v1.JobPhase
+		Expectation codes.Code
+	}{
+		{"not enabled", nil, "secret", v1.JobPhase_PHASE_DONE, codes.Unavailable},
+		{"invalid token", []string{"secret"}, "guess", v1.JobPhase_PHASE_DONE, codes.PermissionDenied},
+		{"running job", []string{"secret"}, "secret", v1.JobPhase_PHASE_RUNNING, codes.FailedPrecondition},
+		{"purged", []string{"secret"}, "secret", v1.JobPhase_PHASE_DONE, codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx := context.Background()
+			srv := &werft.Service{
+				Jobs:   store.NewInMemoryJobStore(),
+				Logs:   store.NewInMemoryLogStore(),
+				Images: store.NewInMemoryImages(),
+				Config: werft.Config{AdminTokens: test.AdminTokens},
+			}
+			srv.Jobs.Store(ctx, v1.JobStatus{Name: "foo.1", Phase: test.Phase, Metadata: &v1.JobMetadata{Children: []string{"foo.1.a"}}})
+			srv.Jobs.Store(ctx, v1.JobStatus{Name: "foo.1.a", Phase: test.Phase, Metadata: &v1.JobMetadata{Parent: "foo.1"}})
+			srv.Jobs.StoreJobSpec("foo.1", []byte("pod: {}"))
+			srv.Images.Put(ctx, v1.ImageBuild{Digest: "sha256:abc", Image: "foo", Job: "foo.1.a"})
+			for _, n := range []string{"foo.1", "foo.1.a"} {
+				w, _ := srv.Logs.Open(n)
+				w.Write([]byte("hello world"))
+				w.Close()
+			}
+
+			_, err := srv.PurgeJob(ctx, &v1.PurgeJobRequest{Name: "foo.1", Token: test.Token})
+			if code := status.Code(err); code != test.Expectation {
+				t.Fatalf("expected %v, got %v", test.Expectation, err)
+			}
+			if test.Expectation != codes.OK {
+				return
+			}
+
+			for _, n := range []string{"foo.1", "foo.1.a"} {
+				if _, err := srv.Jobs.Get(ctx, n); err != store.ErrNotFound {
+					t.Errorf("expected %s to be purged, got %v", n, err)
+				}
+				if _, err := srv.Logs.Read(n); err != store.ErrNotFound {
+					t.Errorf("expected logs of %s to be purged, got %v", n, err)
+				}
+			}
+			if _, err := srv.Jobs.GetJobSpec("foo.1"); err != store.ErrNotFound {
+				t.Errorf("expected job spec to be purged, got %v", err)
+			}
+			if builds, _ := srv.Images.Find(ctx, "sha256:abc"); len(builds) != 0 {
+				t.Errorf("expected image builds to be purged, got %v", builds)
+			}
+		})
+	}
+}
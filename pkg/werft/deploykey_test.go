@@ -0,0 +1,150 @@
+package werft_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	werfttesting "github.com/32leaves/werft/pkg/werft/testing"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStartGitHubJobDeployKey(t *testing.T) {
+	h, err := werfttesting.NewHarness(werft.Config{
+		Repositories: []werft.RepositoryConfig{
+			{Repo: "github.com/32leaves/private", DeployKey: &werft.DeployKeyConfig{SecretName: "private-deploy-key"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	const mountsKey = `
+pod:
+  containers:
+  - name: build
+    image: alpine:latest
+    volumeMounts:
+    - name: werft-deploy-key
+      mountPath: /key
+`
+	const secretVolume = `
+pod:
+  volumes:
+  - name: key
+    secret:
+      secretName: private-deploy-key
+  containers:
+  - name: build
+    image: alpine:latest
+`
+	const projectedVolume = `
+pod:
+  volumes:
+  - name: key
+    projected:
+      sources:
+      - secret:
+          name: private-deploy-key
+  containers:
+  - name: build
+    image: alpine:latest
+`
+	const secretEnv = `
+pod:
+  containers:
+  - name: build
+    image: alpine:latest
+    env:
+    - name: KEY
+      valueFrom:
+        secretKeyRef:
+          name: private-deploy-key
+          key: ssh-privatekey
+`
+	tests := []struct {
+		Name       string
+		Repository *v1.Repository
+		JobYAML    string
+		JobSpecRef string
+		Code       codes.Code
+		Error      string
+	}{
+		{
+			Name:       "no revision",
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "private", Ref: "refs/heads/master"},
+			JobYAML:    mountsKey,
+			Code:       codes.InvalidArgument,
+			Error:      "please name the revision",
+		},
+		{
+			Name:       "job spec ref",
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "private", Revision: "abc"},
+			JobSpecRef: "main",
+			Code:       codes.InvalidArgument,
+			Error:      "cannot read job specs from another ref",
+		},
+		{
+			Name:       "no job file",
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "private", Revision: "abc"},
+			Code:       codes.Internal,
+			Error:      "werft cannot read the files of this repository",
+		},
+		{
+			Name:       "job mounts deploy key",
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "private", Revision: "abc"},
+			JobYAML:    mountsKey,
+			Code:       codes.Internal,
+			Error:      "container build must not mount the deploy key volume",
+		},
+		{
+			Name:       "job mounts deploy key secret",
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "private", Revision: "abc"},
+			JobYAML:    secretVolume,
+			Code:       codes.Internal,
+			Error:      "volume key must not refer to the deploy key secret",
+		},
+		{
+			Name:       "job projects deploy key secret",
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "private", Revision: "abc"},
+			JobYAML:    projectedVolume,
+			Code:       codes.Internal,
+			Error:      "volume key must not refer to the deploy key secret",
+		},
+		{
+			Name:       "job reads deploy key secret into env",
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "private", Revision: "abc"},
+			JobYAML:    secretEnv,
+			Code:       codes.Internal,
+			Error:      "container build must not read the deploy key secret into KEY",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			req := &v1.StartGitHubJobRequest{
+				Metadata: &v1.JobMetadata{
+					Owner:      "someone",
+					Repository: test.Repository,
+					Trigger:    v1.JobTrigger_TRIGGER_MANUAL,
+				},
+				JobPath:    "build.yaml",
+				JobSpecRef: test.JobSpecRef,
+			}
+			if test.JobYAML != "" {
+				req.JobYaml = []byte(test.JobYAML)
+			}
+			_, err := h.Service.StartGitHubJob(ctx, req)
+			if status.Code(err) != test.Code || !strings.Contains(err.Error(), test.Error) {
+				t.Errorf("expected %v error containing %q, got %v", test.Code, test.Error, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// annotationUpstream names the job which started a downstream job
+	annotationUpstream = "upstream"
+
+	// annotationUpstreamChain lists the repositories of all upstream jobs of a downstream job, separated by commas
+	annotationUpstreamChain = "upstreamChain"
+
+	// defaultMaxDownstreamDepth is the number of jobs a chain of downstream jobs can have if the config doesn't say otherwise
+	defaultMaxDownstreamDepth = 5
+)
+
+// PlanDownstreamJobs produces the requests which start the downstream jobs of a successful job. Downstream jobs which
+// don't apply to the ref of the job are left out, defaultRef being the default branch of the job's repository. Those
+// which would run in a repository which is part of the chain of upstream jobs already, or would make the chain longer
+// than maxDepth, are left out and reported as errors.
+// Pull request jobs and jobs whose source werft did not verify never start downstream jobs: their code (or their claim
+// to run on a ref) is not to be trusted with starting jobs in other repositories.
+func PlanDownstreamJobs(upstream *v1.JobStatus, jobs []repoconfig.DownstreamJob, maxDepth int, defaultRef string) ([]*v1.StartGitHubJobRequest, []error) {
+	md := upstream.Metadata
+	if md.Trigger == v1.JobTrigger_TRIGGER_PULL_REQUEST || !md.SourceVerified {
+		return nil, nil
+	}
+
+	annotations := make(map[string]string, len(md.Annotations))
+	for _, a := range md.Annotations {
+		annotations[a.Key] = a.Value
+	}
+
+	var chain []string
+	if c := annotations[annotationUpstreamChain]; c != "" {
+		chain = strings.Split(c, ",")
+	}
+	chain = append(chain, repoKey(md.Repository))
+
+	// annotations which only make sense for the upstream job itself are not passed on
+	delete(annotations, annotationDebug)
+	delete(annotations, filterexpr.LabelFieldPrefix+labelLegacyName)
+	delete(annotations, filterexpr.LabelFieldPrefix+labelJobSpec)
+	annotations[annotationUpstream] = upstream.Name
+	annotations[annotationUpstreamChain] = strings.Join(chain, ",")
+
+	var (
+		res  []*v1.StartGitHubJobRequest
+		errs []error
+	)
+	for _, j := range jobs {
+		if !j.Applies(md.Repository.GetRef(), defaultRef) {
+			continue
+		}
+
+		repo, err := parseRepoName(j.Repo)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if len(chain) >= maxDepth {
+			errs = append(errs, xerrors.Errorf("not starting downstream job in %s: chain of %d jobs reached the maximum depth", j.Repo, len(chain)))
+			continue
+		}
+		if key := repoKey(repo); isInChain(chain, key) {
+			errs = append(errs, xerrors.Errorf("not starting downstream job in %s: cycle %s,%s", j.Repo, strings.Join(chain, ","), key))
+			continue
+		}
+		repo.Ref = j.Ref
+		if repo.Ref == "" {
+			repo.Ref = defaultJobRef
+		}
+
+		jobAnnotations := make(map[string]string, len(annotations)+len(j.Annotations))
+		for k, v := range annotations {
+			jobAnnotations[k] = v
+		}
+		for k, v := range j.Annotations {
+			jobAnnotations[k] = v
+		}
+		res = append(res, &v1.StartGitHubJobRequest{
+			Metadata: &v1.JobMetadata{
+				Owner:       md.Owner,
+				Repository:  repo,
+				Trigger:     v1.JobTrigger_TRIGGER_UPSTREAM,
+				Annotations: sortedAnnotations(jobAnnotations),
+			},
+			JobPath: j.Path,
+		})
+	}
+	return res, errs
+}
+
+// acceptsUpstream returns true if jobs of the upstream repository may start jobs in the repository this config belongs to
+func (rc RepositoryConfig) acceptsUpstream(upstream *v1.Repository) bool {
+	for _, u := range rc.Upstreams {
+		if repoMatches(u, upstream) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoKey identifies a repository in an upstream chain
+func repoKey(repo *v1.Repository) string {
+	return fmt.Sprintf("%s/%s/%s", repo.GetHost(), repo.GetOwner(), repo.GetRepo())
+}
+
+func isInChain(chain []string, key string) bool {
+	for _, c := range chain {
+		if c == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedAnnotations(annotations map[string]string) []*v1.Annotation {
+	res := make([]*v1.Annotation, 0, len(annotations))
+	for k, v := range annotations {
+		res = append(res, &v1.Annotation{Key: k, Value: v})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Key < res[j].Key })
+	return res
+}
+
+// startDownstreamJobs starts the downstream jobs of a job which just succeeded. spec names the job whose job spec
+// declares the downstream jobs, which is a child for matrix jobs. Only replayable jobs have a stored job spec,
+// hence e.g. local jobs never start downstream jobs.
+func (srv *Service) startDownstreamJobs(ctx context.Context, s, spec v1.JobStatus) {
+	if !s.Conditions.GetSuccess() || s.Conditions.GetSkipped() {
+		return
+	}
+	logger := log.WithFields(jobLogFields(s.Name, s.Metadata))
+
+	jobYAML, err := srv.Jobs.GetJobSpec(spec.Name)
+	if err == store.ErrNotFound {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Warn("cannot start downstream jobs")
+		return
+	}
+	jobspec, err := renderJobSpec(spec.Name, spec.Metadata, jobYAML)
+	if err != nil {
+		logger.WithError(err).Warn("cannot start downstream jobs")
+		return
+	}
+	if len(jobspec.Downstream) == 0 {
+		return
+	}
+
+	repo := s.Metadata.Repository
+	gh, _, err := srv.GitHub.Client.Repositories.Get(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		logger.WithError(err).Warn("cannot start downstream jobs: cannot get default branch")
+		return
+	}
+
+	maxDepth := srv.Config.MaxDownstreamDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxDownstreamDepth
+	}
+	reqs, errs := PlanDownstreamJobs(&s, jobspec.Downstream, maxDepth, "refs/heads/"+gh.GetDefaultBranch())
+	for _, err := range errs {
+		logger.WithError(err).Warn("cannot start downstream job")
+	}
+	for _, req := range reqs {
+		if !srv.repositoryConfig(req.Metadata.Repository).acceptsUpstream(repo) {
+			logger.WithField("downstream", repoKey(req.Metadata.Repository)).Warn("not starting downstream job: repository does not list this one in its upstreams")
+			continue
+		}
+
+		resp, err := srv.StartGitHubJob(ctx, req)
+		if err != nil {
+			logger.WithError(err).WithField("downstream", repoKey(req.Metadata.Repository)).Warn("cannot start downstream job")
+			continue
+		}
+		logger.WithField("downstream", resp.Status.Name).Info("started downstream job")
+	}
+}
@@ -0,0 +1,28 @@
+package werft
+
+import (
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+func TestAcceptsUpstream(t *testing.T) {
+	lib := &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "lib"}
+	tests := []struct {
+		Name      string
+		Upstreams []string
+		Accepts   bool
+	}{
+		{"no upstreams", nil, false},
+		{"listed", []string{"32leaves/lib"}, true},
+		{"glob", []string{"github.com/32leaves/*"}, true},
+		{"other", []string{"32leaves/other"}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if act := (RepositoryConfig{Upstreams: test.Upstreams}).acceptsUpstream(lib); act != test.Accepts {
+				t.Errorf("expected %v, got %v", test.Accepts, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package werft_test
+
+import (
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestPlanDownstreamJobs(t *testing.T) {
+	upstream := func(repo, ref string, annotations ...*v1.Annotation) *v1.JobStatus {
+		return &v1.JobStatus{
+			Name: repo + "-build.1",
+			Metadata: &v1.JobMetadata{
+				Owner:          "csweichel",
+				Repository:     &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: repo, Ref: ref},
+				Trigger:        v1.JobTrigger_TRIGGER_PUSH,
+				Annotations:    annotations,
+				SourceVerified: true,
+			},
+		}
+	}
+	pullRequest := upstream("lib", "refs/heads/master")
+	pullRequest.Metadata.Trigger = v1.JobTrigger_TRIGGER_PULL_REQUEST
+	unverified := upstream("lib", "refs/heads/master")
+	unverified.Metadata.SourceVerified = false
+
+	type expectation struct {
+		Repos       []string
+		Annotations map[string]string
+		Errors      int
+	}
+	tests := []struct {
+		Name        string
+		Upstream    *v1.JobStatus
+		Jobs        []repoconfig.DownstreamJob
+		Expectation expectation
+	}{
+		{
+			"starts on ref",
+			upstream("lib", "refs/heads/master", &v1.Annotation{Key: "version", Value: "1.2"}, &v1.Annotation{Key: "debug", Value: "true"}),
+			[]repoconfig.DownstreamJob{
+				{Repo: "32leaves/app", OnRefs: []string{"refs/heads/master"}, Annotations: map[string]string{"lib": "true"}},
+				{Repo: "32leaves/release", OnRefs: []string{"refs/tags/*"}},
+			},
+			expectation{
+				Repos: []string{"github.com/32leaves/app@refs/heads/master"},
+				Annotations: map[string]string{
+					"version":       "1.2",
+					"lib":           "true",
+					"upstream":      "lib-build.1",
+					"upstreamChain": "github.com/32leaves/lib",
+				},
+			},
+		},
+		{
+			"cycle",
+			upstream("app", "refs/heads/master", &v1.Annotation{Key: "upstreamChain", Value: "github.com/32leaves/lib"}),
+			[]repoconfig.DownstreamJob{{Repo: "github.com/32leaves/lib"}, {Repo: "32leaves/app"}, {Repo: "32leaves/deploy", Ref: "refs/heads/prod"}},
+			expectation{Repos: []string{"github.com/32leaves/deploy@refs/heads/prod"}, Errors: 2},
+		},
+		{
+			"max depth",
+			upstream("c", "refs/heads/master", &v1.Annotation{Key: "upstreamChain", Value: "github.com/32leaves/a,github.com/32leaves/b"}),
+			[]repoconfig.DownstreamJob{{Repo: "32leaves/d"}},
+			expectation{Errors: 1},
+		},
+		{
+			"invalid repo",
+			upstream("lib", "refs/heads/master"),
+			[]repoconfig.DownstreamJob{{Repo: "app"}},
+			expectation{Errors: 1},
+		},
+		{
+			"default branch by default",
+			upstream("lib", "refs/heads/master"),
+			[]repoconfig.DownstreamJob{{Repo: "32leaves/app"}},
+			expectation{Repos: []string{"github.com/32leaves/app@refs/heads/master"}},
+		},
+		{
+			"other branch by default",
+			upstream("lib", "refs/heads/feature"),
+			[]repoconfig.DownstreamJob{{Repo: "32leaves/app"}},
+			expectation{},
+		},
+		{
+			"pull request",
+			pullRequest,
+			[]repoconfig.DownstreamJob{{Repo: "32leaves/app", OnRefs: []string{"refs/heads/*"}}},
+			expectation{},
+		},
+		{
+			"unverified source",
+			unverified,
+			[]repoconfig.DownstreamJob{{Repo: "32leaves/app", OnRefs: []string{"refs/heads/*"}}},
+			expectation{},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			reqs, errs := werft.PlanDownstreamJobs(test.Upstream, test.Jobs, 3, "refs/heads/master")
+			if len(errs) != test.Expectation.Errors {
+				t.Errorf("expected %d errors, got %v", test.Expectation.Errors, errs)
+			}
+
+			var repos []string
+			for _, r := range reqs {
+				repo := r.Metadata.Repository
+				repos = append(repos, repo.Host+"/"+repo.Owner+"/"+repo.Repo+"@"+repo.Ref)
+				if r.Metadata.Trigger != v1.JobTrigger_TRIGGER_UPSTREAM {
+					t.Errorf("expected upstream trigger, got %v", r.Metadata.Trigger)
+				}
+				if r.Metadata.Owner != test.Upstream.Metadata.Owner {
+					t.Errorf("expected owner %s, got %s", test.Upstream.Metadata.Owner, r.Metadata.Owner)
+				}
+			}
+			if len(repos) != len(test.Expectation.Repos) {
+				t.Fatalf("expected %v, got %v", test.Expectation.Repos, repos)
+			}
+			for i := range repos {
+				if repos[i] != test.Expectation.Repos[i] {
+					t.Errorf("expected %v, got %v", test.Expectation.Repos, repos)
+				}
+			}
+
+			if test.Expectation.Annotations == nil {
+				return
+			}
+			act := make(map[string]string)
+			for _, a := range reqs[0].Metadata.Annotations {
+				act[a.Key] = a.Value
+			}
+			if len(act) != len(test.Expectation.Annotations) {
+				t.Errorf("expected annotations %v, got %v", test.Expectation.Annotations, act)
+			}
+			for k, v := range test.Expectation.Annotations {
+				if act[k] != v {
+					t.Errorf("expected annotation %s=%s, got %s", k, v, act[k])
+				}
+			}
+		})
+	}
+}
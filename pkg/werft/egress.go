@@ -0,0 +1,101 @@
+package werft
+
+import (
+	"net"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// EgressConfig limits the network destinations the jobs of a repository can reach, e.g. so that untrusted pull request
+// code cannot reach internal services. Each job gets a NetworkPolicy which allows DNS and the destinations listed here.
+type EgressConfig struct {
+	// Allow lists the destinations jobs can reach, e.g. a proxy, a registry or GitHub
+	Allow []EgressRule `yaml:"allow"`
+}
+
+// EgressRule allows jobs to reach a destination. Destinations are given as IP range or select pods in the cluster.
+type EgressRule struct {
+	// CIDR is an IP range jobs can reach, e.g. 140.82.112.0/20
+	CIDR string `yaml:"cidr,omitempty"`
+
+	// Except are IP ranges within CIDR jobs cannot reach
+	Except []string `yaml:"except,omitempty"`
+
+	// Namespaces selects the namespaces whose pods jobs can reach by their labels. Together with Pods it selects the
+	// matching pods in those namespaces.
+	Namespaces map[string]string `yaml:"namespaces,omitempty"`
+
+	// Pods selects the pods jobs can reach by their labels, e.g. app: proxy. Without Namespaces it selects pods in
+	// the namespace jobs run in.
+	Pods map[string]string `yaml:"pods,omitempty"`
+
+	// Ports limits the rule to these TCP ports. Defaults to all ports.
+	Ports []int `yaml:"ports,omitempty"`
+}
+
+// Validate checks that all rules name a destination and their IP ranges are valid
+func (c *EgressConfig) Validate() error {
+	for _, r := range c.Allow {
+		if r.CIDR == "" && r.Namespaces == nil && r.Pods == nil {
+			return xerrors.Errorf("egress rules must set cidr, namespaces or pods")
+		}
+		if r.CIDR != "" && (r.Namespaces != nil || r.Pods != nil) {
+			return xerrors.Errorf("egress rule for %s cannot select namespaces or pods as well", r.CIDR)
+		}
+		if r.CIDR == "" && len(r.Except) > 0 {
+			return xerrors.Errorf("egress rules need a cidr to make exceptions from")
+		}
+		for _, cidr := range append([]string{r.CIDR}, r.Except...) {
+			if cidr == "" {
+				continue
+			}
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return xerrors.Errorf("invalid egress rule: %w", err)
+			}
+		}
+		for _, p := range r.Ports {
+			if p < 1 || p > 65535 {
+				return xerrors.Errorf("invalid egress rule: port %d out of range", p)
+			}
+		}
+	}
+	return nil
+}
+
+// JobRules produces the egress rules of the network policy of a job. The destinations a repository allows are only granted to
+// jobs whose source werft verified - any other job merely claims to belong to the repository and can reach DNS only.
+func (c *EgressConfig) JobRules(md *v1.JobMetadata) []networkingv1.NetworkPolicyEgressRule {
+	if !md.GetSourceVerified() {
+		return nil
+	}
+	return c.Rules()
+}
+
+// Rules produces the egress rules of the network policy of a job
+func (c *EgressConfig) Rules() []networkingv1.NetworkPolicyEgressRule {
+	res := make([]networkingv1.NetworkPolicyEgressRule, 0, len(c.Allow))
+	for _, r := range c.Allow {
+		var peer networkingv1.NetworkPolicyPeer
+		if r.CIDR != "" {
+			peer.IPBlock = &networkingv1.IPBlock{CIDR: r.CIDR, Except: r.Except}
+		}
+		if r.Namespaces != nil {
+			peer.NamespaceSelector = &metav1.LabelSelector{MatchLabels: r.Namespaces}
+		}
+		if r.Pods != nil {
+			peer.PodSelector = &metav1.LabelSelector{MatchLabels: r.Pods}
+		}
+
+		rule := networkingv1.NetworkPolicyEgressRule{To: []networkingv1.NetworkPolicyPeer{peer}}
+		for _, p := range r.Ports {
+			port := intstr.FromInt(p)
+			rule.Ports = append(rule.Ports, networkingv1.NetworkPolicyPort{Port: &port})
+		}
+		res = append(res, rule)
+	}
+	return res
+}
@@ -0,0 +1,90 @@
+package werft_test
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestEgressConfigValidate(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Rule  werft.EgressRule
+		Error bool
+	}{
+		{"cidr", werft.EgressRule{CIDR: "140.82.112.0/20", Except: []string{"140.82.113.0/24"}, Ports: []int{443}}, false},
+		{"pods", werft.EgressRule{Namespaces: map[string]string{"name": "proxy"}, Pods: map[string]string{"app": "proxy"}}, false},
+		{"no destination", werft.EgressRule{Ports: []int{443}}, true},
+		{"cidr and pods", werft.EgressRule{CIDR: "10.0.0.0/8", Pods: map[string]string{"app": "proxy"}}, true},
+		{"invalid cidr", werft.EgressRule{CIDR: "10.0.0.0"}, true},
+		{"invalid except", werft.EgressRule{CIDR: "10.0.0.0/8", Except: []string{"foo"}}, true},
+		{"except without cidr", werft.EgressRule{Pods: map[string]string{"app": "proxy"}, Except: []string{"10.0.0.0/8"}}, true},
+		{"invalid port", werft.EgressRule{CIDR: "10.0.0.0/8", Ports: []int{0}}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := werft.EgressConfig{Allow: []werft.EgressRule{test.Rule}}
+			err := cfg.Validate()
+			if (err != nil) != test.Error {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEgressConfigRules(t *testing.T) {
+	https := intstr.FromInt(443)
+	tests := []struct {
+		Name        string
+		Rule        werft.EgressRule
+		Expectation networkingv1.NetworkPolicyEgressRule
+	}{
+		{
+			"cidr",
+			werft.EgressRule{CIDR: "140.82.112.0/20", Except: []string{"140.82.113.0/24"}, Ports: []int{443}},
+			networkingv1.NetworkPolicyEgressRule{
+				To:    []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "140.82.112.0/20", Except: []string{"140.82.113.0/24"}}}},
+				Ports: []networkingv1.NetworkPolicyPort{{Port: &https}},
+			},
+		},
+		{
+			"pods in namespace",
+			werft.EgressRule{Namespaces: map[string]string{"name": "proxy"}, Pods: map[string]string{"app": "proxy"}},
+			networkingv1.NetworkPolicyEgressRule{
+				To: []networkingv1.NetworkPolicyPeer{{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "proxy"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "proxy"}},
+				}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			cfg := werft.EgressConfig{Allow: []werft.EgressRule{test.Rule}}
+			rules := cfg.Rules()
+			if len(rules) != 1 {
+				t.Fatalf("expected one rule, got %d", len(rules))
+			}
+			if !reflect.DeepEqual(rules[0], test.Expectation) {
+				t.Errorf("unexpected rule: %+v", rules[0])
+			}
+		})
+	}
+}
+
+func TestEgressConfigJobRules(t *testing.T) {
+	cfg := werft.EgressConfig{Allow: []werft.EgressRule{{CIDR: "10.0.0.0/8"}, {Pods: map[string]string{"app": "proxy"}}}}
+	repo := &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"}
+
+	if rules := cfg.JobRules(&v1.JobMetadata{Repository: repo, SourceVerified: true}); len(rules) != 2 {
+		t.Errorf("expected jobs with a verified source to get all rules, got %d", len(rules))
+	}
+	if rules := cfg.JobRules(&v1.JobMetadata{Repository: repo, Trigger: v1.JobTrigger_TRIGGER_MANUAL}); len(rules) != 0 {
+		t.Errorf("expected jobs without a verified source to get no rules, got %d", len(rules))
+	}
+}
@@ -0,0 +1,239 @@
+package werft
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/logmask"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nodeInfoTTL is the time we remember what a node runs, e.g. its kernel version. Nodes are upgraded rarely.
+const nodeInfoTTL = 10 * time.Minute
+
+type cachedNodeInfo struct {
+	Info    corev1.NodeSystemInfo
+	Fetched time.Time
+}
+
+// nodeInfoCache remembers the system info of the nodes jobs ran on
+type nodeInfoCache struct {
+	mu    sync.Mutex
+	nodes map[string]cachedNodeInfo
+}
+
+// recordEnvironment records the environment a job runs in, i.e. the digests its images resolved to, its node and
+// the environment variables of its containers. Jobs whose pod is gone keep the environment they had.
+func (srv *Service) recordEnvironment(pod *corev1.Pod, s, prev *v1.JobStatus, masker *logmask.Masker) {
+	if prev != nil && s.Environment == nil {
+		s.Environment = prev.Environment
+	}
+	if pod == nil {
+		return
+	}
+
+	env := podEnvironment(pod, masker, srv.secretEnvNames())
+	if info, ok := srv.nodeInfo(pod.Spec.NodeName); ok {
+		env.KernelVersion = info.KernelVersion
+		env.OsImage = info.OSImage
+		env.ContainerRuntime = info.ContainerRuntimeVersion
+		env.KubeletVersion = info.KubeletVersion
+	} else if s.Environment != nil && s.Environment.Node == env.Node {
+		env.KernelVersion = s.Environment.KernelVersion
+		env.OsImage = s.Environment.OsImage
+		env.ContainerRuntime = s.Environment.ContainerRuntime
+		env.KubeletVersion = s.Environment.KubeletVersion
+	}
+	if s.Environment != nil {
+		// containers lose their status once the pod is deleted, but not the image they ran
+		prevIDs := make(map[string]string, len(s.Environment.Containers))
+		for _, c := range s.Environment.Containers {
+			prevIDs[c.Name] = c.ImageId
+		}
+		for _, c := range env.Containers {
+			if c.ImageId == "" {
+				c.ImageId = prevIDs[c.Name]
+			}
+		}
+	}
+	s.Environment = env
+}
+
+// podEnvironment describes the environment of a job's pod, except for what its node runs. The values of environment
+// variables werft set from secrets and of those whose name says they're secret are redacted, whether or not the
+// masker knows them.
+func podEnvironment(pod *corev1.Pod, masker *logmask.Masker, secretNames secretEnvNames) *v1.JobEnvironment {
+	isSecret := make(map[string]bool)
+	for _, name := range executor.SecretEnv(pod) {
+		isSecret[name] = true
+	}
+
+	imageIDs := make(map[string]string)
+	for _, cs := range [][]corev1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, c := range cs {
+			imageIDs[c.Name] = c.ImageID
+		}
+	}
+
+	res := &v1.JobEnvironment{Node: pod.Spec.NodeName}
+	for _, cs := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range cs {
+			ce := &v1.ContainerEnvironment{
+				Name:    c.Name,
+				Image:   c.Image,
+				ImageId: imageIDs[c.Name],
+			}
+			for _, e := range c.Env {
+				if e.ValueFrom != nil {
+					continue
+				}
+				if ce.Env == nil {
+					ce.Env = make(map[string]string)
+				}
+				val := e.Value
+				if isSecret[e.Name] || secretNames.matches(e.Name) {
+					val = "[redacted]"
+				} else if masker != nil {
+					val = masker.Mask(val)
+				}
+				ce.Env[e.Name] = val
+			}
+			res.Containers = append(res.Containers, ce)
+		}
+	}
+	return res
+}
+
+// nodeInfo returns what a node runs, e.g. its kernel version. Only the Kubernetes executor knows about nodes.
+func (srv *Service) nodeInfo(name string) (info corev1.NodeSystemInfo, ok bool) {
+	kexec, isKube := srv.Executor.(*executor.Executor)
+	if name == "" || !isKube {
+		return
+	}
+
+	srv.nodeInfos.mu.Lock()
+	defer srv.nodeInfos.mu.Unlock()
+	if c, exists := srv.nodeInfos.nodes[name]; exists && srv.since(c.Fetched) < nodeInfoTTL {
+		return c.Info, true
+	}
+
+	node, err := kexec.Client.CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithField("node", name).Debug("cannot get node info")
+		return
+	}
+	if srv.nodeInfos.nodes == nil {
+		srv.nodeInfos.nodes = make(map[string]cachedNodeInfo)
+	}
+	srv.nodeInfos.nodes[name] = cachedNodeInfo{Info: node.Status.NodeInfo, Fetched: srv.now()}
+	return node.Status.NodeInfo, true
+}
+
+// DiffJobEnvironments compares the environments two jobs ran in
+func (srv *Service) DiffJobEnvironments(ctx context.Context, req *v1.DiffJobEnvironmentsRequest) (*v1.DiffJobEnvironmentsResponse, error) {
+	if req.From == "" || req.To == "" {
+		return nil, status.Error(codes.InvalidArgument, "from and to are required")
+	}
+
+	from, err := srv.jobEnvironment(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := srv.jobEnvironment(ctx, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.DiffJobEnvironmentsResponse{
+		From:    from,
+		To:      to,
+		Changes: diffEnvironments(from, to),
+	}, nil
+}
+
+func (srv *Service) jobEnvironment(ctx context.Context, name string) (*v1.JobEnvironment, error) {
+	name = srv.resolveJobName(ctx, name)
+	job, err := srv.Jobs.Get(ctx, name)
+	if err == store.ErrNotFound && srv.Archive != nil {
+		job, err = srv.Archive.Get(ctx, name)
+	}
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s not found", name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if job.Environment == nil {
+		return nil, status.Errorf(codes.NotFound, "job %s has no recorded environment", name)
+	}
+	return job.Environment, nil
+}
+
+// diffEnvironments lists the differences between two environments, node first, then containers by name
+func diffEnvironments(from, to *v1.JobEnvironment) []*v1.JobEnvironmentChange {
+	var res []*v1.JobEnvironmentChange
+	diff := func(field, a, b string) {
+		if a != b {
+			res = append(res, &v1.JobEnvironmentChange{Field: field, From: a, To: b})
+		}
+	}
+
+	diff("node", from.Node, to.Node)
+	diff("kernel_version", from.KernelVersion, to.KernelVersion)
+	diff("os_image", from.OsImage, to.OsImage)
+	diff("container_runtime", from.ContainerRuntime, to.ContainerRuntime)
+	diff("kubelet_version", from.KubeletVersion, to.KubeletVersion)
+
+	var (
+		fromCtnrs = make(map[string]*v1.ContainerEnvironment)
+		toCtnrs   = make(map[string]*v1.ContainerEnvironment)
+		names     []string
+	)
+	for _, c := range from.Containers {
+		fromCtnrs[c.Name] = c
+		names = append(names, c.Name)
+	}
+	for _, c := range to.Containers {
+		toCtnrs[c.Name] = c
+		if _, exists := fromCtnrs[c.Name]; !exists {
+			names = append(names, c.Name)
+		}
+	}
+	for _, n := range names {
+		a, b := fromCtnrs[n], toCtnrs[n]
+		if a == nil {
+			a = &v1.ContainerEnvironment{}
+		}
+		if b == nil {
+			b = &v1.ContainerEnvironment{}
+		}
+
+		prefix := "containers." + n + "."
+		diff(prefix+"image", a.Image, b.Image)
+		diff(prefix+"image_id", a.ImageId, b.ImageId)
+
+		keys := make([]string, 0, len(a.Env)+len(b.Env))
+		for k := range a.Env {
+			keys = append(keys, k)
+		}
+		for k := range b.Env {
+			if _, exists := a.Env[k]; !exists {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diff(prefix+"env."+k, a.Env[k], b.Env[k])
+		}
+	}
+	return res
+}
@@ -0,0 +1,68 @@
+package werft_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDiffJobEnvironments(t *testing.T) {
+	ctx := context.Background()
+	srv := &werft.Service{Jobs: store.NewInMemoryJobStore()}
+	srv.Jobs.Store(ctx, v1.JobStatus{Name: "foo.1", Metadata: &v1.JobMetadata{}, Environment: &v1.JobEnvironment{
+		Node:          "node-a",
+		KernelVersion: "5.4.0",
+		Containers: []*v1.ContainerEnvironment{
+			{Name: "werft-checkout", Image: "alpine/git:latest", ImageId: "alpine/git@sha256:aaa"},
+			{Name: "build", Image: "golang:1.14", ImageId: "golang@sha256:bbb", Env: map[string]string{"GOPROXY": "https://proxy.golang.org", "CI": "true"}},
+		},
+	}})
+	srv.Jobs.Store(ctx, v1.JobStatus{Name: "foo.2", Metadata: &v1.JobMetadata{}, Environment: &v1.JobEnvironment{
+		Node:          "node-b",
+		KernelVersion: "5.4.0",
+		Containers: []*v1.ContainerEnvironment{
+			{Name: "werft-checkout", Image: "alpine/git:latest", ImageId: "alpine/git@sha256:aaa"},
+			{Name: "build", Image: "golang:1.14", ImageId: "golang@sha256:ccc", Env: map[string]string{"GOPROXY": "direct", "CI": "true"}},
+			{Name: "db", Image: "postgres:12"},
+		},
+	}})
+	srv.Jobs.Store(ctx, v1.JobStatus{Name: "foo.3", Metadata: &v1.JobMetadata{}})
+
+	tests := []struct {
+		Name        string
+		From, To    string
+		Expectation []*v1.JobEnvironmentChange
+		Code        codes.Code
+	}{
+		{"same", "foo.1", "foo.1", nil, codes.OK},
+		{"changed", "foo.1", "foo.2", []*v1.JobEnvironmentChange{
+			{Field: "node", From: "node-a", To: "node-b"},
+			{Field: "containers.build.image_id", From: "golang@sha256:bbb", To: "golang@sha256:ccc"},
+			{Field: "containers.build.env.GOPROXY", From: "https://proxy.golang.org", To: "direct"},
+			{Field: "containers.db.image", To: "postgres:12"},
+		}, codes.OK},
+		{"no environment", "foo.1", "foo.3", nil, codes.NotFound},
+		{"unknown job", "foo.1", "bar.1", nil, codes.NotFound},
+		{"missing job", "foo.1", "", nil, codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.DiffJobEnvironments(ctx, &v1.DiffJobEnvironmentsRequest{From: test.From, To: test.To})
+			if code := status.Code(err); code != test.Code {
+				t.Fatalf("expected %v, got %v", test.Code, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(resp.Changes, test.Expectation) {
+				t.Errorf("unexpected changes: %v", resp.Changes)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package werft
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// addJobEvent records an event in the timeline of a job. If the event has no time, it happened now.
+func (srv *Service) addJobEvent(name string, evt v1.JobEvent) {
+	if evt.Time == nil {
+		evt.Time = srv.timestampNow()
+	}
+	err := srv.Jobs.AddEvent(context.Background(), name, evt)
+	if err != nil {
+		log.WithError(err).WithField("name", name).WithField("event", evt.Type.String()).Warn("cannot record job event")
+	}
+	srv.publishEvent(&v1.ServerEvent{Type: serverEventJobEvent, Time: evt.Time, JobName: name, JobEvent: &evt})
+}
+
+// recordPhaseEvent records that a job entered its current phase
+func (srv *Service) recordPhaseEvent(s *v1.JobStatus) {
+	evt := v1.JobEvent{
+		Type:  v1.JobEventType_EVENT_PHASE_CHANGED,
+		Phase: s.Phase,
+	}
+	switch s.Phase {
+	case v1.JobPhase_PHASE_WAITING:
+		evt.Type = v1.JobEventType_EVENT_QUEUED
+		evt.Message = s.Details
+		srv.publishEvent(&v1.ServerEvent{Type: serverEventQueue, Job: s, JobName: s.Name, Message: s.Details})
+	case v1.JobPhase_PHASE_CLEANUP:
+		evt.Type = v1.JobEventType_EVENT_POD_DELETED
+	case v1.JobPhase_PHASE_DONE:
+		evt.Message = s.Details
+	}
+	srv.addJobEvent(s.Name, evt)
+}
+
+// recordTermination records how the containers of a job stopped once its pod was deleted, i.e. whether they stopped
+// after SIGTERM or had to be killed. We record this only once, even though the pod is updated several times.
+func (srv *Service) recordTermination(name string, pod *corev1.Pod) {
+	t := executor.PodTermination(pod)
+	if t == nil {
+		return
+	}
+	events, err := srv.Jobs.GetEvents(context.Background(), name)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("cannot record container termination")
+		return
+	}
+	for _, evt := range events {
+		if evt.Type == v1.JobEventType_EVENT_CONTAINERS_STOPPED {
+			return
+		}
+	}
+	srv.addJobEvent(name, v1.JobEvent{Type: v1.JobEventType_EVENT_CONTAINERS_STOPPED, Message: t.String()})
+}
+
+// podScheduledTime returns when Kubernetes assigned the pod to a node, or nil if it hasn't been scheduled yet
+func podScheduledTime(pod *corev1.Pod) *timestamp.Timestamp {
+	for _, c := range pod.Status.Conditions {
+		if c.Type != corev1.PodScheduled || c.Status != corev1.ConditionTrue {
+			continue
+		}
+		t, err := ptypes.TimestampProto(c.LastTransitionTime.Time)
+		if err != nil || c.LastTransitionTime.IsZero() {
+			return ptypes.TimestampNow()
+		}
+		return t
+	}
+	return nil
+}
+
+// GetJobEvents returns the timeline of a job
+func (srv *Service) GetJobEvents(ctx context.Context, req *v1.GetJobEventsRequest) (*v1.GetJobEventsResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	name := srv.resolveJobName(ctx, req.Name)
+	events, err := srv.Jobs.GetEvents(ctx, name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if len(events) == 0 {
+		// jobs which ran before we recorded events have none, but unknown jobs should not look like them
+		_, err = srv.Jobs.Get(ctx, name)
+		if err == store.ErrNotFound && srv.Archive != nil {
+			_, err = srv.Archive.Get(ctx, name)
+		}
+		if err == store.ErrNotFound {
+			return nil, status.Error(codes.NotFound, "not found")
+		}
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	res := make([]*v1.JobEvent, len(events))
+	for i := range events {
+		res[i] = &events[i]
+	}
+	return &v1.GetJobEventsResponse{Events: res}, nil
+}
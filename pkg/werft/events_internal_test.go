@@ -0,0 +1,58 @@
+package werft
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecordTermination(t *testing.T) {
+	srv := &Service{Jobs: store.NewInMemoryJobStore()}
+	countStopped := func() (n int, msg string) {
+		evts, err := srv.Jobs.GetEvents(context.Background(), "build.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range evts {
+			if e.Type == v1.JobEventType_EVENT_CONTAINERS_STOPPED {
+				n++
+				msg = e.Message
+			}
+		}
+		return
+	}
+
+	deleted := time.Now()
+	deadline := metav1.NewTime(deleted.Add(5 * time.Second))
+	gracePeriod := int64(5)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "build.1", DeletionTimestamp: &deadline, DeletionGracePeriodSeconds: &gracePeriod},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "build", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		}},
+	}
+
+	srv.recordTermination("build.1", pod)
+	if n, _ := countStopped(); n != 0 {
+		t.Errorf("expected no event while containers run, got %d", n)
+	}
+
+	pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		ExitCode:   137,
+		FinishedAt: metav1.NewTime(deleted.Add(5 * time.Second)),
+	}}
+	srv.recordTermination("build.1", pod)
+	srv.recordTermination("build.1", pod)
+	n, msg := countStopped()
+	if n != 1 {
+		t.Errorf("expected the termination to be recorded once, got %d", n)
+	}
+	if exp := "containers did not stop within the 5s grace period after SIGTERM and were killed: build"; msg != exp {
+		t.Errorf("expected %q, got %q", exp, msg)
+	}
+}
@@ -0,0 +1,310 @@
+package werft
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// exportPageSize is the number of jobs we fetch from the store at a time when exporting
+	exportPageSize = 500
+
+	// exportChunkSize is the size of the chunks an export is streamed in
+	exportChunkSize = 32 * 1024
+)
+
+// exportCSVHeader names the columns of CSV exports
+var exportCSVHeader = []string{"name", "repository", "ref", "revision", "trigger", "owner", "phase", "success", "created", "finished", "duration_seconds", "attempt"}
+
+// setupTokens adds the tokens of the token files to the configured ones
+func (srv *Service) setupTokens() error {
+	for _, f := range []struct {
+		Name   string
+		Tokens *[]string
+	}{
+		{srv.Config.ExportTokensFile, &srv.Config.ExportTokens},
+		{srv.Config.AdminTokensFile, &srv.Config.AdminTokens},
+	} {
+		if f.Name == "" {
+			continue
+		}
+		fc, err := ioutil.ReadFile(f.Name)
+		if err != nil {
+			return xerrors.Errorf("cannot read tokens: %w", err)
+		}
+		// we don't append to the configured slice in place, it might be shared with the caller's config
+		*f.Tokens = append(append([]string(nil), *f.Tokens...), strings.Fields(string(fc))...)
+	}
+	return nil
+}
+
+// authorizeExport returns true if token is one of the configured export tokens
+func (srv *Service) authorizeExport(token string) bool {
+	return tokenMatches(srv.Config.ExportTokens, token)
+}
+
+// requireAdmin returns a gRPC error unless token is one of the admin tokens. Without admin tokens, what (e.g. "purging
+// jobs") is unavailable.
+func (srv *Service) requireAdmin(token, what string) error {
+	if len(srv.Config.AdminTokens) == 0 {
+		return status.Errorf(codes.Unavailable, "%s is not enabled", what)
+	}
+	if !tokenMatches(srv.Config.AdminTokens, token) {
+		return status.Error(codes.PermissionDenied, "invalid admin token")
+	}
+	return nil
+}
+
+// tokenMatches returns true if token is one of tokens
+func tokenMatches(tokens []string, token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// exportJobs writes all jobs matching the filter which were created within from and to (if set), newest first
+func (srv *Service) exportJobs(ctx context.Context, filter []*v1.FilterExpression, from, to time.Time, format v1.ExportFormat, out io.Writer) error {
+	var write func(j *v1.JobStatus) error
+	switch format {
+	case v1.ExportFormat_EXPORT_JSONL:
+		marshaler := &jsonpb.Marshaler{}
+		write = func(j *v1.JobStatus) error {
+			err := marshaler.Marshal(out, j)
+			if err != nil {
+				return err
+			}
+			_, err = out.Write([]byte("\n"))
+			return err
+		}
+	case v1.ExportFormat_EXPORT_CSV:
+		w := csv.NewWriter(out)
+		defer w.Flush()
+		err := w.Write(exportCSVHeader)
+		if err != nil {
+			return err
+		}
+		write = func(j *v1.JobStatus) error { return w.Write(exportCSVRecord(j)) }
+	default:
+		return xerrors.Errorf("unknown export format %v", format)
+	}
+
+	order := []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: false}}
+	for start := 0; ; start += exportPageSize {
+		jobs, _, err := srv.Jobs.Find(store.WithStaleReads(ctx), filter, order, start, exportPageSize)
+		if err != nil {
+			return xerrors.Errorf("cannot find jobs: %w", err)
+		}
+		for i := range jobs {
+			created := exportTime(jobs[i].Metadata.GetCreated())
+			if !to.IsZero() && created.After(to) {
+				continue
+			}
+			if !from.IsZero() && created.Before(from) {
+				// jobs come newest first - all further jobs are older still
+				return nil
+			}
+
+			err = write(&jobs[i])
+			if err != nil {
+				return err
+			}
+		}
+		if len(jobs) < exportPageSize {
+			return nil
+		}
+	}
+}
+
+// exportCSVRecord produces the CSV columns of a job in the order of exportCSVHeader
+func exportCSVRecord(j *v1.JobStatus) []string {
+	var (
+		md       = j.GetMetadata()
+		repo     = md.GetRepository()
+		created  = exportTime(md.GetCreated())
+		finished = exportTime(md.GetFinished())
+		duration string
+	)
+	if !created.IsZero() && !finished.IsZero() {
+		duration = strconv.FormatInt(int64(finished.Sub(created).Seconds()), 10)
+	}
+	attempt := j.GetConditions().GetAttempt()
+	if attempt == 0 {
+		attempt = 1
+	}
+
+	return []string{
+		j.Name,
+		fmt.Sprintf("%s/%s/%s", repo.GetHost(), repo.GetOwner(), repo.GetRepo()),
+		repo.GetRef(),
+		repo.GetRevision(),
+		strings.ToLower(strings.TrimPrefix(md.GetTrigger().String(), "TRIGGER_")),
+		md.GetOwner(),
+		strings.ToLower(strings.TrimPrefix(j.Phase.String(), "PHASE_")),
+		strconv.FormatBool(j.GetConditions().GetSuccess()),
+		formatExportTime(created),
+		formatExportTime(finished),
+		duration,
+		strconv.Itoa(int(attempt)),
+	}
+}
+
+// exportTime converts a timestamp, returning the zero time if the timestamp is not set
+func exportTime(ts *timestamp.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ExportJobs dumps the records of all jobs matching a filter and time range
+func (srv *Service) ExportJobs(req *v1.ExportJobsRequest, resp v1.WerftService_ExportJobsServer) error {
+	if len(srv.Config.ExportTokens) == 0 {
+		return status.Error(codes.Unavailable, "exporting jobs is not enabled")
+	}
+	if !srv.authorizeExport(req.Token) {
+		return status.Error(codes.PermissionDenied, "invalid export token")
+	}
+
+	var from, to time.Time
+	if req.From != nil {
+		t, err := ptypes.Timestamp(req.From)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		from = t
+	}
+	if req.To != nil {
+		t, err := ptypes.Timestamp(req.To)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		to = t
+	}
+
+	out := bufio.NewWriterSize(exportStream{resp}, exportChunkSize)
+	err := srv.exportJobs(resp.Context(), req.Filter, from, to, req.Format, out)
+	if err == nil {
+		err = out.Flush()
+	}
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// exportStream sends everything written to it as export chunks
+type exportStream struct {
+	S v1.WerftService_ExportJobsServer
+}
+
+func (s exportStream) Write(p []byte) (n int, err error) {
+	// the gRPC stream marshals the message before Send returns, hence we need not copy p
+	err = s.S.Send(&v1.ExportJobsResponse{Data: p})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// HandleJobExport serves the records of all jobs at /export/jobs, e.g. to build reports. Requests must carry one of
+// the export tokens as bearer token. Query parameters select the format (jsonl or csv), the time range jobs were
+// created in (from and to as RFC3339) and filter expressions like those of werft job list (filter, repeatable).
+func (srv *Service) HandleJobExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if len(srv.Config.ExportTokens) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	if !srv.authorizeExport(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")) {
+		http.Error(w, "invalid export token", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	var (
+		format      v1.ExportFormat
+		contentType string
+	)
+	switch q.Get("format") {
+	case "", "jsonl":
+		format, contentType = v1.ExportFormat_EXPORT_JSONL, "application/x-ndjson"
+	case "csv":
+		format, contentType = v1.ExportFormat_EXPORT_CSV, "text/csv; charset=utf-8"
+	default:
+		http.Error(w, "format must be jsonl or csv", http.StatusBadRequest)
+		return
+	}
+
+	var times [2]time.Time
+	for i, p := range []string{"from", "to"} {
+		v := q.Get(p)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%s must be an RFC3339 time", p), http.StatusBadRequest)
+			return
+		}
+		times[i] = t
+	}
+
+	var filter []*v1.FilterExpression
+	if exprs := q["filter"]; len(exprs) > 0 {
+		terms, err := filterexpr.Parse(exprs)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid filter: %v", err), http.StatusBadRequest)
+			return
+		}
+		for _, t := range terms {
+			filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{t}})
+		}
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	err := srv.exportJobs(r.Context(), filter, times[0], times[1], format, w)
+	if err != nil {
+		// we've likely started writing the response already, hence can't change the status code anymore
+		log.WithError(err).Warn("cannot export jobs")
+	}
+}
@@ -0,0 +1,72 @@
+package werft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetupTokens(t *testing.T) {
+	dir, err := ioutil.TempDir("", "werft-tokens")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	adminFile := filepath.Join(dir, "admin-tokens")
+	err = ioutil.WriteFile(adminFile, []byte("admin-1\nadmin-2\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configured := []string{"admin-0"}
+	srv := &Service{Config: Config{AdminTokens: configured, AdminTokensFile: adminFile}}
+	err = srv.setupTokens()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := strings.Join(srv.Config.AdminTokens, ","); act != "admin-0,admin-1,admin-2" {
+		t.Errorf("unexpected admin tokens: %s", act)
+	}
+	if configured[0] != "admin-0" || len(configured) != 1 {
+		t.Errorf("expected the configured tokens to stay unchanged, got %v", configured)
+	}
+	if len(srv.Config.ExportTokens) != 0 {
+		t.Errorf("expected no export tokens, got %v", srv.Config.ExportTokens)
+	}
+	if err := srv.requireAdmin("admin-2", "purging jobs"); err != nil {
+		t.Errorf("expected token from file to be accepted: %v", err)
+	}
+
+	srv = &Service{Config: Config{ExportTokensFile: filepath.Join(dir, "missing")}}
+	if err := srv.setupTokens(); err == nil {
+		t.Error("expected an error for a missing token file")
+	}
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Tokens []string
+		Token  string
+		Code   codes.Code
+	}{
+		{Name: "no admin tokens", Token: "secret", Code: codes.Unavailable},
+		{Name: "no token", Tokens: []string{"secret"}, Code: codes.PermissionDenied},
+		{Name: "wrong token", Tokens: []string{"secret"}, Token: "guess", Code: codes.PermissionDenied},
+		{Name: "valid token", Tokens: []string{"other", "secret"}, Token: "secret", Code: codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := &Service{Config: Config{AdminTokens: test.Tokens}}
+			err := srv.requireAdmin(test.Token, "purging jobs")
+			if act := status.Code(err); act != test.Code {
+				t.Errorf("expected code %v, got %v", test.Code, err)
+			}
+		})
+	}
+}
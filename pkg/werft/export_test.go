@@ -0,0 +1,75 @@
+package werft_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestHandleJobExport(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	for i, name := range []string{"foo.1", "foo.2", "bar.1"} {
+		created := int64(1580000000 + i*3600)
+		err := jobs.Store(context.Background(), v1.JobStatus{
+			Name:  name,
+			Phase: v1.JobPhase_PHASE_DONE,
+			Metadata: &v1.JobMetadata{
+				Owner:      "someone",
+				Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master", Revision: "abc"},
+				Trigger:    v1.JobTrigger_TRIGGER_PUSH,
+				Created:    &timestamp.Timestamp{Seconds: created},
+				Finished:   &timestamp.Timestamp{Seconds: created + 90},
+			},
+			Conditions: &v1.JobConditions{Success: name != "foo.2", Attempt: 1},
+		})
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+	srv := &werft.Service{Jobs: jobs, Config: werft.Config{ExportTokens: []string{"secret"}}}
+
+	tests := []struct {
+		Name        string
+		Token       string
+		Query       string
+		Status      int
+		Expectation string
+	}{
+		{"no token", "", "format=csv", http.StatusUnauthorized, ""},
+		{"wrong token", "guess", "format=csv", http.StatusUnauthorized, ""},
+		{"invalid format", "secret", "format=xml", http.StatusBadRequest, ""},
+		{"csv", "secret", "format=csv&filter=name|=foo", http.StatusOK, `name,repository,ref,revision,trigger,owner,phase,success,created,finished,duration_seconds,attempt
+foo.2,github.com/32leaves/werft,refs/heads/master,abc,push,someone,done,false,2020-01-26T01:53:20Z,2020-01-26T01:54:50Z,90,1
+foo.1,github.com/32leaves/werft,refs/heads/master,abc,push,someone,done,true,2020-01-26T00:53:20Z,2020-01-26T00:54:50Z,90,1
+`},
+		{"time range", "secret", "format=csv&from=2020-01-26T01:00:00Z&to=2020-01-26T02:00:00Z", http.StatusOK, `name,repository,ref,revision,trigger,owner,phase,success,created,finished,duration_seconds,attempt
+foo.2,github.com/32leaves/werft,refs/heads/master,abc,push,someone,done,false,2020-01-26T01:53:20Z,2020-01-26T01:54:50Z,90,1
+`},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/export/jobs?"+test.Query, nil)
+			if test.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.Token)
+			}
+			rec := httptest.NewRecorder()
+			srv.HandleJobExport(rec, req)
+
+			if rec.Code != test.Status {
+				t.Fatalf("expected status %d, got %d: %s", test.Status, rec.Code, rec.Body.String())
+			}
+			if test.Status != http.StatusOK {
+				return
+			}
+			if act := rec.Body.String(); act != test.Expectation {
+				t.Errorf("expected\n%s\ngot\n%s", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package werft
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// FallbackJobConfig configures the jobs of repositories which have no .werft/config.yaml, e.g. a standard Go build
+// for all repositories of an organisation. This way repositories get built without adding job files to each of them.
+type FallbackJobConfig struct {
+	// Repo identifies the repositories like in the repositories section, e.g. github.com/32leaves/*
+	Repo string `yaml:"repo"`
+
+	// Config is used in place of the repository's .werft/config.yaml. Its job file paths refer to Jobs.
+	Config repoconfig.C `yaml:"config"`
+
+	// Jobs maps job file paths to job specs, e.g. build.yaml to a pod running go test
+	Jobs map[string]string `yaml:"jobs"`
+}
+
+// Validate checks that the fallback config starts jobs and all job files it refers to exist
+func (c FallbackJobConfig) Validate() error {
+	if _, err := path.Match(c.Repo, ""); err != nil {
+		return xerrors.Errorf("invalid repository pattern %s: %w", c.Repo, err)
+	}
+
+	paths := c.Config.JobPaths()
+	if len(paths) == 0 {
+		return xerrors.Errorf("fallback jobs for %s start no job: config has no defaultJob, rules or jobs", c.Repo)
+	}
+	for _, p := range paths {
+		if _, ok := c.Jobs[p]; !ok {
+			return xerrors.Errorf("fallback jobs for %s refer to unknown job file %s", c.Repo, p)
+		}
+	}
+	return nil
+}
+
+// fallbackFileProvider serves the job files of fallback jobs and all other files from the repository
+type fallbackFileProvider struct {
+	Fallback *FallbackJobConfig
+	Repo     FileProvider
+}
+
+// Download provides access to a single file
+func (fp fallbackFileProvider) Download(ctx context.Context, path string) (io.ReadCloser, error) {
+	if spec, ok := fp.Fallback.Jobs[path]; ok {
+		return ioutil.NopCloser(strings.NewReader(spec)), nil
+	}
+	return fp.Repo.Download(ctx, path)
+}
+
+// fallbackJobs returns the first fallback config which matches the repository, or nil if there is none
+func (srv *Service) fallbackJobs(repo *v1.Repository) *FallbackJobConfig {
+	fallbackJobs := srv.config().FallbackJobs
+	for i, c := range fallbackJobs {
+		if repoMatches(c.Repo, repo) {
+			return &fallbackJobs[i]
+		}
+	}
+	return nil
+}
+
+// getRepoCfg fetches the repo config of a repository and returns where to download its job files from.
+// Repositories without a repo config use their fallback jobs if there are any.
+func (srv *Service) getRepoCfg(ctx context.Context, repo *v1.Repository, fp FileProvider) (*repoconfig.C, FileProvider, error) {
+	cfg, err := getRepoCfg(ctx, fp)
+	if err == nil || !isNotFound(err) {
+		return cfg, fp, err
+	}
+
+	fallback := srv.fallbackJobs(repo)
+	if fallback == nil {
+		return nil, fp, err
+	}
+	log.WithField("repo", fallback.Repo).WithField("ref", repo.GetRef()).Debug("repository has no werft config - using fallback jobs")
+	return &fallback.Config, fallbackFileProvider{Fallback: fallback, Repo: fp}, nil
+}
+
+// isNotFound returns true if err says that a file does not exist in a repository
+func isNotFound(err error) bool {
+	if xerrors.Is(err, errNoRepositoryFiles) {
+		return true
+	}
+
+	var gherr *github.ErrorResponse
+	if xerrors.As(err, &gherr) {
+		return gherr.Response != nil && gherr.Response.StatusCode == http.StatusNotFound
+	}
+
+	// DownloadContents does not return a typed error if the directory exists, but the file doesn't
+	return strings.Contains(err.Error(), "No file named")
+}
@@ -0,0 +1,51 @@
+package werft
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+)
+
+func TestGetRepoCfgDeployKey(t *testing.T) {
+	fallback := FallbackJobConfig{
+		Repo:   "github.com/32leaves/private",
+		Config: repoconfig.C{DefaultJob: "build.yaml"},
+		Jobs:   map[string]string{"build.yaml": "pod:\n  containers: []\n"},
+	}
+	tests := []struct {
+		Name     string
+		Fallback []FallbackJobConfig
+		Error    bool
+	}{
+		{"fallback jobs", []FallbackJobConfig{fallback}, false},
+		{"no fallback jobs", nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := &Service{Config: Config{FallbackJobs: test.Fallback}}
+			repo := &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "private", Revision: "abc"}
+
+			cfg, files, err := srv.getRepoCfg(context.Background(), repo, deployKeyFiles{})
+			if (err != nil) != test.Error {
+				t.Fatalf("expected error %v, got %v", test.Error, err)
+			}
+			if test.Error {
+				return
+			}
+			if cfg.DefaultJob != "build.yaml" {
+				t.Errorf("expected the fallback config, got %v", cfg)
+			}
+			in, err := files.Download(context.Background(), "build.yaml")
+			if err != nil {
+				t.Fatalf("cannot download fallback job: %v", err)
+			}
+			defer in.Close()
+			if spec, _ := ioutil.ReadAll(in); string(spec) != fallback.Jobs["build.yaml"] {
+				t.Errorf("unexpected job spec: %q", spec)
+			}
+		})
+	}
+}
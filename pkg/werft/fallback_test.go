@@ -0,0 +1,32 @@
+package werft_test
+
+import (
+	"testing"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestFallbackJobConfigValidate(t *testing.T) {
+	build := map[string]string{"build.yaml": "pod:\n  containers: []\n"}
+
+	tests := []struct {
+		Name   string
+		Config werft.FallbackJobConfig
+		Valid  bool
+	}{
+		{"default job", werft.FallbackJobConfig{Repo: "github.com/32leaves/*", Config: repoconfig.C{DefaultJob: "build.yaml"}, Jobs: build}, true},
+		{"jobs", werft.FallbackJobConfig{Repo: "github.com/32leaves/*", Config: repoconfig.C{Jobs: []*repoconfig.JobStartRule{{Path: "build.yaml"}}}, Jobs: build}, true},
+		{"no job", werft.FallbackJobConfig{Repo: "github.com/32leaves/*", Jobs: build}, false},
+		{"unknown job file", werft.FallbackJobConfig{Repo: "github.com/32leaves/*", Config: repoconfig.C{DefaultJob: "test.yaml"}, Jobs: build}, false},
+		{"invalid pattern", werft.FallbackJobConfig{Repo: "github.com/32leaves/[", Config: repoconfig.C{DefaultJob: "build.yaml"}, Jobs: build}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := test.Config.Validate()
+			if valid := err == nil; valid != test.Valid {
+				t.Errorf("expected valid to be %v, got %v", test.Valid, err)
+			}
+		})
+	}
+}
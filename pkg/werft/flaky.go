@@ -0,0 +1,176 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultFlakyJobsLimit is the number of recent jobs GetFlakyJobs analyses unless told otherwise
+const defaultFlakyJobsLimit = 500
+
+// GetFlakyJobs finds jobs of a repository which alternate between success and failure, most flaky first
+func (srv *Service) GetFlakyJobs(ctx context.Context, req *v1.GetFlakyJobsRequest) (*v1.GetFlakyJobsResponse, error) {
+	repo := req.Repository
+	if repo == nil || repo.Owner == "" || repo.Repo == "" {
+		return nil, status.Error(codes.InvalidArgument, "repository owner and repo are required")
+	}
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultFlakyJobsLimit
+	}
+
+	term := func(field, value string) *v1.FilterExpression {
+		return &v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: field, Value: value, Operation: v1.FilterOp_OP_EQUALS}}}
+	}
+	filter := []*v1.FilterExpression{
+		term("repo.owner", repo.Owner),
+		term("repo.repo", repo.Repo),
+		term("phase", "done"),
+	}
+	if repo.Host != "" {
+		filter = append(filter, term("repo.host", repo.Host))
+	}
+	if repo.Ref != "" {
+		filter = append(filter, term("repo.ref", repo.Ref))
+	}
+
+	jobs, _, err := srv.Jobs.Find(store.WithStaleReads(ctx), filter, []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: false}}, 0, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.GetFlakyJobsResponse{Result: flakyJobs(jobs)}, nil
+}
+
+// flakyJobs scores how often jobs flip between success and failure from one run to the next. Runs are grouped by
+// their name without number. Only runs which actually executed count, i.e. skipped jobs and jobs which failed to
+// start are ignored.
+func flakyJobs(jobs []v1.JobStatus) []*v1.FlakyJob {
+	groups := make(map[string][]v1.JobStatus)
+	for _, j := range jobs {
+		group := jobGroup(j.Name)
+		if group == "" || !j.Conditions.GetDidExecute() || j.Conditions.GetSkipped() {
+			continue
+		}
+		groups[group] = append(groups[group], j)
+	}
+
+	var res []*v1.FlakyJob
+	for group, runs := range groups {
+		if len(runs) < 2 {
+			continue
+		}
+		sort.SliceStable(runs, func(i, j int) bool {
+			return runs[i].Metadata.GetCreated().GetSeconds() < runs[j].Metadata.GetCreated().GetSeconds()
+		})
+
+		var (
+			flips       int
+			muted       int
+			lastFailure string
+			outcomes    = make(map[string]map[bool]bool)
+			revisions   []string
+		)
+		for i, r := range runs {
+			success := r.Conditions.GetSuccess()
+			if i > 0 && success != runs[i-1].Conditions.GetSuccess() {
+				flips++
+			}
+			if !success {
+				lastFailure = r.Name
+			}
+			if r.Conditions.GetMuted() {
+				muted++
+			}
+
+			rev := r.Metadata.GetRepository().GetRevision()
+			if rev == "" {
+				continue
+			}
+			if _, ok := outcomes[rev]; !ok {
+				outcomes[rev] = make(map[bool]bool)
+				revisions = append(revisions, rev)
+			}
+			outcomes[rev][success] = true
+		}
+		if flips == 0 {
+			continue
+		}
+
+		var flakyRevisions []string
+		for _, rev := range revisions {
+			if outcomes[rev][true] && outcomes[rev][false] {
+				flakyRevisions = append(flakyRevisions, rev)
+			}
+		}
+		res = append(res, &v1.FlakyJob{
+			Name:           group,
+			Score:          float64(flips) / float64(len(runs)-1),
+			Runs:           int32(len(runs)),
+			Flips:          int32(flips),
+			FlakyRevisions: flakyRevisions,
+			LastFailure:    lastFailure,
+			Muted:          int32(muted),
+		})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+func newFlakyFailureMetrics() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "job_flaky_failures_total",
+		Help: "Failures of known-flaky jobs by repository and outcome: muted failures which were rerun, and reruns which failed, too.",
+	}, []string{"repo", "outcome"})
+}
+
+// isKnownFlaky returns true if the job is listed among the flaky jobs of its repository
+func (srv *Service) isKnownFlaky(s *v1.JobStatus) bool {
+	group := jobGroup(s.Name)
+	if group == "" {
+		return false
+	}
+	for _, p := range srv.repositoryConfig(s.Metadata.Repository).FlakyJobs {
+		if ok, _ := path.Match(p, group); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// muteFlakyFailure mutes the failure of a known-flaky job, which makes retryJob run the job again. Only the first
+// attempt of a job is muted, hence the job counts as failed if the rerun fails, too.
+// Jobs which cannot be replayed and matrix jobs are never muted.
+func (srv *Service) muteFlakyFailure(s *v1.JobStatus) {
+	if s.Conditions.Success || !s.Conditions.CanReplay || s.Metadata.Parent != "" || len(s.Metadata.Children) > 0 || !srv.isKnownFlaky(s) {
+		return
+	}
+
+	repo := fmt.Sprintf("%s/%s", s.Metadata.Repository.GetOwner(), s.Metadata.Repository.GetRepo())
+	if s.Conditions.Attempt > 1 {
+		if srv.flakyFailures != nil {
+			srv.flakyFailures.WithLabelValues(repo, "failed").Inc()
+		}
+		return
+	}
+
+	s.Conditions.Muted = true
+	if srv.flakyFailures != nil {
+		srv.flakyFailures.WithLabelValues(repo, "muted").Inc()
+	}
+	log.WithFields(jobLogFields(s.Name, s.Metadata)).Info("known-flaky job failed - muting the failure and running the job again")
+}
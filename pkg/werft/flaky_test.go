@@ -0,0 +1,78 @@
+package werft_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestGetFlakyJobs(t *testing.T) {
+	var created int64
+	run := func(name, ref, rev string, success bool) v1.JobStatus {
+		created++
+		return v1.JobStatus{
+			Name:  name,
+			Phase: v1.JobPhase_PHASE_DONE,
+			Metadata: &v1.JobMetadata{
+				Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: ref, Revision: rev},
+				Created:    &timestamp.Timestamp{Seconds: created},
+			},
+			Conditions: &v1.JobConditions{Success: success, DidExecute: true},
+		}
+	}
+	muted := func(j v1.JobStatus) v1.JobStatus {
+		j.Conditions.Muted = true
+		return j
+	}
+
+	jobs := store.NewInMemoryJobStore()
+	for _, j := range []v1.JobStatus{
+		run("werft-build-master.1", "refs/heads/master", "a", true),
+		muted(run("werft-build-master.2", "refs/heads/master", "b", false)),
+		run("werft-build-master.3", "refs/heads/master", "b", true),
+		run("werft-build-master.4", "refs/heads/master", "c", true),
+		run("werft-build-master.5", "refs/heads/master", "d", true),
+		run("werft-build-foo.1", "refs/heads/foo", "e", false),
+		run("werft-build-foo.2", "refs/heads/foo", "f", true),
+		run("werft-build-foo.3", "refs/heads/foo", "g", false),
+		run("werft-build-stable.1", "refs/heads/stable", "h", true),
+		run("werft-build-stable.2", "refs/heads/stable", "i", true),
+	} {
+		err := jobs.Store(context.Background(), j)
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+	srv := &werft.Service{Jobs: jobs}
+
+	tests := []struct {
+		Name        string
+		Repo        *v1.Repository
+		Expectation []*v1.FlakyJob
+	}{
+		{"repo", &v1.Repository{Owner: "32leaves", Repo: "werft"}, []*v1.FlakyJob{
+			{Name: "werft-build-foo", Score: 1, Runs: 3, Flips: 2, LastFailure: "werft-build-foo.3"},
+			{Name: "werft-build-master", Score: 0.5, Runs: 5, Flips: 2, FlakyRevisions: []string{"b"}, LastFailure: "werft-build-master.2", Muted: 1},
+		}},
+		{"ref", &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"}, []*v1.FlakyJob{
+			{Name: "werft-build-master", Score: 0.5, Runs: 5, Flips: 2, FlakyRevisions: []string{"b"}, LastFailure: "werft-build-master.2", Muted: 1},
+		}},
+		{"other repo", &v1.Repository{Owner: "32leaves", Repo: "other"}, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.GetFlakyJobs(context.Background(), &v1.GetFlakyJobsRequest{Repository: test.Repo})
+			if err != nil {
+				t.Fatalf("cannot get flaky jobs: %v", err)
+			}
+			if !reflect.DeepEqual(resp.Result, test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, resp.Result)
+			}
+		})
+	}
+}
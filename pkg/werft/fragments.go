@@ -0,0 +1,98 @@
+package werft
+
+import (
+	"context"
+	"io/ioutil"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// fragmentCacheTTL is the time fragments of other repositories are cached for
+const fragmentCacheTTL = 5 * time.Minute
+
+type cachedFragment struct {
+	Content []byte
+	Fetched time.Time
+}
+
+// resolveJobSpecFragments pulls the fragments a job spec extends or includes into the job spec.
+// Fragments of the job's own repository are downloaded from files, fragments of other repositories
+// from GitHub using the app's credentials.
+func (srv *Service) resolveJobSpecFragments(ctx context.Context, repo *v1.Repository, files FileProvider, path string, jobYAML []byte) ([]byte, error) {
+	return repoconfig.ResolveFragments(ctx, path, jobYAML, func(ctx context.Context, ref repoconfig.FragmentRef) ([]byte, error) {
+		if ref.Repo == "" {
+			in, err := files.Download(ctx, ref.Path)
+			if err != nil {
+				return nil, err
+			}
+			defer in.Close()
+			return ioutil.ReadAll(in)
+		}
+		return srv.fetchRemoteFragment(ctx, repo, ref)
+	})
+}
+
+// fetchRemoteFragment downloads a fragment of another repository, or of the job's own repository if it's referred
+// to by name. Fragments are cached for a while as many jobs tend to use the same ones.
+func (srv *Service) fetchRemoteFragment(ctx context.Context, repo *v1.Repository, ref repoconfig.FragmentRef) ([]byte, error) {
+	host := ref.Host
+	if host == "" {
+		host = "github.com"
+	}
+	if host != "github.com" {
+		return nil, xerrors.Errorf("fragments can only be included from GitHub repositories, not %s", host)
+	}
+	fragRepo := &v1.Repository{Host: host, Owner: ref.Owner, Repo: ref.Repo}
+	if fragRepo.Owner != repo.Owner || fragRepo.Repo != repo.Repo {
+		var allowed bool
+		for _, p := range srv.config().JobSpecFragmentRepos {
+			if repoMatches(p, fragRepo) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, xerrors.Errorf("fragments of %s/%s/%s are not allowed: add the repository to jobSpecFragmentRepos", host, ref.Owner, ref.Repo)
+		}
+	}
+
+	key := ref.String()
+	srv.fragmentMu.Lock()
+	if srv.fragments == nil {
+		srv.fragments = make(map[string]cachedFragment)
+	}
+	cached, ok := srv.fragments[key]
+	srv.fragmentMu.Unlock()
+	if ok && srv.since(cached.Fetched) < fragmentCacheTTL {
+		return cached.Content, nil
+	}
+
+	fp := &GitHubContentProvider{
+		Owner:    ref.Owner,
+		Repo:     ref.Repo,
+		Revision: ref.Rev,
+		Client:   srv.GitHub.Client,
+	}
+	in, err := fp.Download(ctx, ref.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	content, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	srv.fragmentMu.Lock()
+	for k, f := range srv.fragments {
+		if srv.since(f.Fetched) >= fragmentCacheTTL {
+			delete(srv.fragments, k)
+		}
+	}
+	srv.fragments[key] = cachedFragment{Content: content, Fetched: srv.now()}
+	srv.fragmentMu.Unlock()
+	return content, nil
+}
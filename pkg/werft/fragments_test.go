@@ -0,0 +1,57 @@
+package werft_test
+
+import (
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestValidateJobSpecWithFragments(t *testing.T) {
+	md := &v1.JobMetadata{Owner: "foo", Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}}
+	tests := []struct {
+		Name  string
+		Spec  string
+		Valid bool
+	}{
+		{
+			Name: "fragment provides the pod",
+			Spec: `pod:
+  containers:
+  - name: build
+    image: golang:1.13
+    command: ["make"]
+---
+extends: base.yaml
+labels:
+  repo: {{ .Repository.Repo }}
+pod:
+  containers:
+  - name: build
+    command: ["make", "test"]
+`,
+			Valid: true,
+		},
+		{
+			Name: "invalid merged field",
+			Spec: `pod:
+  containers: []
+---
+extends: base.yaml
+pod:
+  restartPolicy: [Never]
+`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			err := werft.ValidateJobSpec("job", md, []byte(test.Spec))
+			if test.Valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !test.Valid && err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// annotationWaitFor names the jobs, separated by spaces, which must be done before a job starts
+	annotationWaitFor = "waitFor"
+
+	// rateLimitPeriod is the period in which MaxJobsPerHour limits the number of jobs a repository starts
+	rateLimitPeriod = time.Hour
+
+	// dependencyCheckTimeout limits the time it takes to look up the jobs a job waits for or the running jobs
+	dependencyCheckTimeout = 10 * time.Second
+)
+
+// jobStart records a job which passed its gate, i.e. which werft started
+type jobStart struct {
+	Name string
+	Time time.Time
+}
+
+// dependencies returns the jobs a job waits for, as named in its waitFor annotation. All of them must exist.
+func (srv *Service) dependencies(ctx context.Context, name string, md *v1.JobMetadata) ([]string, error) {
+	var val string
+	for _, a := range md.Annotations {
+		if a.Key == annotationWaitFor {
+			val = a.Value
+		}
+	}
+
+	var res []string
+	for _, dep := range strings.Fields(val) {
+		if dep == name {
+			return nil, xerrors.Errorf("invalid %s annotation: job %s cannot wait for itself", annotationWaitFor, dep)
+		}
+		_, err := srv.Jobs.Get(ctx, dep)
+		if err == store.ErrNotFound {
+			return nil, xerrors.Errorf("invalid %s annotation: job %s does not exist", annotationWaitFor, dep)
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("cannot get job %s to wait for: %w", dep, err)
+		}
+		res = append(res, dep)
+	}
+	return res, nil
+}
+
+// jobGate holds a job in the queue until the jobs it waits for are done and its repository may start and run another
+// job. A job which passes the gate takes one of the repository's slots until it's done, see releaseSlot.
+// It returns nil if nothing can hold the job.
+func (srv *Service) jobGate(name string, md *v1.JobMetadata, repoCfg RepositoryConfig, waitFor []string) executor.Gate {
+	if len(waitFor) == 0 && repoCfg.MaxJobsPerHour <= 0 && repoCfg.MaxConcurrentJobs <= 0 {
+		return nil
+	}
+
+	repo := repoKey(md.Repository)
+	return func() (v1.WaitReason, string) {
+		for _, dep := range waitFor {
+			if details := srv.waitsFor(dep); details != "" {
+				return v1.WaitReason_WAIT_DEPENDENCY, details
+			}
+		}
+
+		// counting and reserving happen under one lock, so that jobs passing the gate at the same time don't
+		// exceed the limits together
+		srv.gateMu.Lock()
+		defer srv.gateMu.Unlock()
+
+		if limit := repoCfg.MaxJobsPerHour; limit > 0 {
+			if details := srv.exceedsRateLimit(repo, name, limit); details != "" {
+				return v1.WaitReason_WAIT_RATE_LIMIT, details
+			}
+		}
+		if limit := repoCfg.MaxConcurrentJobs; limit > 0 {
+			if details := srv.exceedsConcurrency(md.Repository, name, limit); details != "" {
+				return v1.WaitReason_WAIT_CONCURRENCY_LIMIT, details
+			}
+			srv.reserveSlot(repo, name)
+		}
+		if repoCfg.MaxJobsPerHour > 0 {
+			srv.recordJobStart(repo, name)
+		}
+		return v1.WaitReason_WAIT_UNKNOWN, ""
+	}
+}
+
+// waitsFor returns why a job still waits for one it depends on, or an empty string if that job is done. Jobs which
+// were deleted in the meantime don't hold anyone.
+func (srv *Service) waitsFor(dep string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), dependencyCheckTimeout)
+	defer cancel()
+
+	job, err := srv.Jobs.Get(ctx, dep)
+	if err == store.ErrNotFound {
+		return ""
+	}
+	if err != nil {
+		log.WithError(err).WithField("name", dep).Warn("cannot check if job is done")
+		return fmt.Sprintf("waiting for job %s, which cannot be checked right now", dep)
+	}
+	if job.Phase == v1.JobPhase_PHASE_DONE || job.Phase == v1.JobPhase_PHASE_CLEANUP {
+		return ""
+	}
+	return fmt.Sprintf("waiting for job %s to finish", dep)
+}
+
+// exceedsRateLimit returns why a repository cannot start another job yet, or an empty string if it can. Jobs which
+// passed the limit before pass it again. Callers must hold gateMu.
+func (srv *Service) exceedsRateLimit(repo, name string, limit int) string {
+	now := srv.now()
+	var recent []jobStart
+	for _, s := range srv.jobStarts[repo] {
+		if s.Name == name {
+			return ""
+		}
+		if now.Sub(s.Time) < rateLimitPeriod {
+			recent = append(recent, s)
+		}
+	}
+	if len(recent) == 0 {
+		delete(srv.jobStarts, repo)
+	} else {
+		srv.jobStarts[repo] = recent
+	}
+
+	if len(recent) < limit {
+		return ""
+	}
+	next := recent[len(recent)-limit].Time.Add(rateLimitPeriod)
+	return fmt.Sprintf("%s started %d jobs within the last hour (limit is %d), the next one can start at %s", repo, len(recent), limit, next.Format(time.RFC3339))
+}
+
+// exceedsConcurrency returns why a repository cannot run another job yet, or an empty string if it can. Jobs run
+// from the time they passed the gate until they're done. Jobs which passed before keep their slot. Callers must
+// hold gateMu.
+func (srv *Service) exceedsConcurrency(repo *v1.Repository, name string, limit int) string {
+	key := repoKey(repo)
+	if srv.slots[name] == key {
+		return ""
+	}
+
+	running, err := srv.runningJobs(repo)
+	if err != nil {
+		log.WithError(err).WithField("repo", key).Warn("cannot count running jobs")
+		return fmt.Sprintf("waiting for the running jobs of %s, which cannot be counted right now", key)
+	}
+	for job, r := range srv.slots {
+		if r == key {
+			running[job] = struct{}{}
+		}
+	}
+	if len(running) < limit {
+		return ""
+	}
+	return fmt.Sprintf("%s runs %d jobs (limit is %d)", key, len(running), limit)
+}
+
+// runningJobs returns the names of the jobs of a repository which are neither waiting nor done
+func (srv *Service) runningJobs(repo *v1.Repository) (map[string]struct{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dependencyCheckTimeout)
+	defer cancel()
+
+	term := func(field, value string, negate bool) *v1.FilterExpression {
+		return &v1.FilterExpression{Terms: []*v1.FilterTerm{{Field: field, Value: value, Operation: v1.FilterOp_OP_EQUALS, Negate: negate}}}
+	}
+	filter := []*v1.FilterExpression{
+		term("repo.host", repo.GetHost(), false),
+		term("repo.owner", repo.GetOwner(), false),
+		term("repo.repo", repo.GetRepo(), false),
+		term("phase", "done", true),
+		term("phase", "cleanup", true),
+		term("phase", "waiting", true),
+	}
+	jobs, _, err := srv.Jobs.Find(ctx, filter, nil, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]struct{}, len(jobs))
+	for _, j := range jobs {
+		res[j.Name] = struct{}{}
+	}
+	return res, nil
+}
+
+// reserveSlot counts a job which passed its gate as running until it's done. Callers must hold gateMu.
+func (srv *Service) reserveSlot(repo, name string) {
+	if srv.slots == nil {
+		srv.slots = make(map[string]string)
+	}
+	srv.slots[name] = repo
+}
+
+// releaseSlot frees the slot of a job which is done or didn't start
+func (srv *Service) releaseSlot(name string) {
+	srv.gateMu.Lock()
+	defer srv.gateMu.Unlock()
+
+	delete(srv.slots, name)
+}
+
+// recordJobStart remembers that a job of a repository started for the rate limit. Callers must hold gateMu.
+func (srv *Service) recordJobStart(repo, name string) {
+	if srv.jobStarts == nil {
+		srv.jobStarts = make(map[string][]jobStart)
+	}
+	for _, s := range srv.jobStarts[repo] {
+		if s.Name == name {
+			return
+		}
+	}
+	srv.jobStarts[repo] = append(srv.jobStarts[repo], jobStart{Name: name, Time: srv.now()})
+}
@@ -0,0 +1,227 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+)
+
+type testClock struct {
+	t time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.t
+}
+
+func TestJobGate(t *testing.T) {
+	var (
+		start = time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+		clock = &testClock{start}
+		srv   = &Service{Jobs: store.NewInMemoryJobStore(), Clock: clock}
+		md    = &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}}
+		other = &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "other"}}
+		cfg   = RepositoryConfig{MaxJobsPerHour: 2}
+	)
+	storeJob := func(name string, phase v1.JobPhase) {
+		err := srv.Jobs.Store(context.Background(), v1.JobStatus{Name: name, Phase: phase, Metadata: md})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	storeJob("build.1", v1.JobPhase_PHASE_RUNNING)
+
+	if gate := srv.jobGate("plain.1", md, RepositoryConfig{}, nil); gate != nil {
+		t.Error("expected no gate for jobs nothing can hold")
+	}
+
+	tests := []struct {
+		Name    string
+		Job     string
+		Md      *v1.JobMetadata
+		WaitFor []string
+		Now     time.Duration
+		Reason  v1.WaitReason
+		Details string
+	}{
+		{Name: "dependency running", Job: "deploy.1", WaitFor: []string{"build.1"}, Reason: v1.WaitReason_WAIT_DEPENDENCY, Details: "waiting for job build.1 to finish"},
+		{Name: "first job", Job: "test.1"},
+		{Name: "same job again", Job: "test.1", Now: time.Minute},
+		{Name: "second job", Job: "test.2", Now: 10 * time.Minute},
+		{Name: "limit reached", Job: "test.3", Now: 20 * time.Minute, Reason: v1.WaitReason_WAIT_RATE_LIMIT, Details: "github.com/32leaves/werft started 2 jobs within the last hour (limit is 2), the next one can start at 2020-04-01T13:00:00Z"},
+		{Name: "started job passes", Job: "test.2", Now: 30 * time.Minute},
+		{Name: "other repository", Job: "other.1", Md: other, Now: 30 * time.Minute},
+		{Name: "first job expired", Job: "test.3", Now: time.Hour},
+		{Name: "dependency done", Job: "deploy.1", WaitFor: []string{"build.1"}, Now: 61 * time.Minute, Reason: v1.WaitReason_WAIT_RATE_LIMIT},
+		{Name: "dependency deleted", Job: "deploy.1", WaitFor: []string{"gone.1"}, Now: 70 * time.Minute},
+	}
+	for _, test := range tests {
+		// the steps build on one another, hence they don't run as subtests
+		clock.t = start.Add(test.Now)
+		if test.Name == "dependency done" {
+			storeJob("build.1", v1.JobPhase_PHASE_DONE)
+		}
+		md := md
+		if test.Md != nil {
+			md = test.Md
+		}
+
+		reason, details := srv.jobGate(test.Job, md, cfg, test.WaitFor)()
+		if reason != test.Reason {
+			t.Errorf("%s: expected reason %v, got %v (%s)", test.Name, test.Reason, reason, details)
+		}
+		if !strings.Contains(details, test.Details) {
+			t.Errorf("%s: expected details %q, got %q", test.Name, test.Details, details)
+		}
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	srv := &Service{Jobs: store.NewInMemoryJobStore()}
+	for _, name := range []string{"build.1", "test.1"} {
+		err := srv.Jobs.Store(context.Background(), v1.JobStatus{Name: name, Metadata: &v1.JobMetadata{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		Name     string
+		WaitFor  string
+		Expected []string
+		Error    string
+	}{
+		{Name: "none"},
+		{Name: "one", WaitFor: "build.1", Expected: []string{"build.1"}},
+		{Name: "several", WaitFor: " build.1  test.1\n", Expected: []string{"build.1", "test.1"}},
+		{Name: "unknown job", WaitFor: "build.1 gone.1", Error: "job gone.1 does not exist"},
+		{Name: "itself", WaitFor: "deploy.1", Error: "cannot wait for itself"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			md := &v1.JobMetadata{}
+			if test.WaitFor != "" {
+				md.Annotations = []*v1.Annotation{{Key: annotationWaitFor, Value: test.WaitFor}}
+			}
+			act, err := srv.dependencies(context.Background(), "deploy.1", md)
+			if test.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), test.Error) {
+					t.Errorf("expected error containing %q, got %v", test.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(act, ",") != strings.Join(test.Expected, ",") {
+				t.Errorf("expected dependencies %v, got %v", test.Expected, act)
+			}
+		})
+	}
+}
+
+func TestJobGateConcurrency(t *testing.T) {
+	var (
+		srv   = &Service{Jobs: store.NewInMemoryJobStore()}
+		md    = &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}}
+		other = &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "other"}}
+		cfg   = RepositoryConfig{MaxConcurrentJobs: 2}
+	)
+	storeJob := func(name string, md *v1.JobMetadata, phase v1.JobPhase) {
+		err := srv.Jobs.Store(context.Background(), v1.JobStatus{Name: name, Phase: phase, Metadata: md})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	storeJob("running.1", md, v1.JobPhase_PHASE_RUNNING)
+	storeJob("waiting.1", md, v1.JobPhase_PHASE_WAITING)
+	storeJob("done.1", md, v1.JobPhase_PHASE_DONE)
+	storeJob("other.1", other, v1.JobPhase_PHASE_RUNNING)
+	storeJob("other.2", other, v1.JobPhase_PHASE_RUNNING)
+
+	tests := []struct {
+		Name    string
+		Job     string
+		Step    func()
+		Reason  v1.WaitReason
+		Details string
+	}{
+		{Name: "slot free", Job: "first.1"},
+		{Name: "same job again", Job: "first.1"},
+		{Name: "limit reached", Job: "second.1", Reason: v1.WaitReason_WAIT_CONCURRENCY_LIMIT, Details: "github.com/32leaves/werft runs 2 jobs (limit is 2)"},
+		{
+			Name: "started job counted once",
+			Job:  "second.1",
+			Step: func() {
+				storeJob("first.1", md, v1.JobPhase_PHASE_RUNNING)
+			},
+			Reason:  v1.WaitReason_WAIT_CONCURRENCY_LIMIT,
+			Details: "github.com/32leaves/werft runs 2 jobs (limit is 2)",
+		},
+		{
+			Name: "job done",
+			Job:  "second.1",
+			Step: func() {
+				storeJob("running.1", md, v1.JobPhase_PHASE_DONE)
+			},
+		},
+		{
+			Name: "slot released",
+			Job:  "third.1",
+			Step: func() {
+				storeJob("first.1", md, v1.JobPhase_PHASE_DONE)
+				srv.releaseSlot("first.1")
+			},
+		},
+		{Name: "limit reached again", Job: "fourth.1", Reason: v1.WaitReason_WAIT_CONCURRENCY_LIMIT},
+	}
+	for _, test := range tests {
+		// the steps build on one another, hence they don't run as subtests
+		if test.Step != nil {
+			test.Step()
+		}
+
+		reason, details := srv.jobGate(test.Job, md, cfg, nil)()
+		if reason != test.Reason {
+			t.Errorf("%s: expected reason %v, got %v (%s)", test.Name, test.Reason, reason, details)
+		}
+		if !strings.Contains(details, test.Details) {
+			t.Errorf("%s: expected details %q, got %q", test.Name, test.Details, details)
+		}
+	}
+}
+
+func TestJobGateConcurrentStarts(t *testing.T) {
+	var (
+		srv = &Service{Jobs: store.NewInMemoryJobStore()}
+		md  = &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft"}}
+		cfg = RepositoryConfig{MaxConcurrentJobs: 2}
+
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		passed int
+	)
+	for i := 0; i < 10; i++ {
+		gate := srv.jobGate(fmt.Sprintf("build.%d", i), md, cfg, nil)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if reason, _ := gate(); reason == v1.WaitReason_WAIT_UNKNOWN {
+				mu.Lock()
+				passed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if passed != cfg.MaxConcurrentJobs {
+		t.Errorf("expected %d jobs to pass the gate, got %d", cfg.MaxConcurrentJobs, passed)
+	}
+}
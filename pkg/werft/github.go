@@ -1,15 +1,23 @@
 package werft
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logclass"
+	"github.com/32leaves/werft/pkg/tracing"
+	"github.com/golang/protobuf/proto"
 	"github.com/google/go-github/github"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v3"
 )
@@ -21,8 +29,65 @@ var (
 	// annotationStatusUpdate is set on jobs whoose status needs to be updated on GitHub.
 	// This is set only on jobs created through GitHub events.
 	annotationStatusUpdate = "updateGitHubStatus"
+
+	// annotationResultChannels lists the channels results of a job are published to by default, separated by comma.
+	// This is set on jobs whose repo config names result channels.
+	annotationResultChannels = "resultChannels"
+
+	// annotationPullRequestLabel is the pull request label which started a job.
+	// This is set on jobs started by adding a label the repo config names in labelJobs.
+	annotationPullRequestLabel = "pullRequestLabel"
 )
 
+// githubMaxDescriptionLength is the maximum length of a commit status description GitHub accepts
+const githubMaxDescriptionLength = 140
+
+// logPhase returns the description of the log phase a job is currently in, or an empty string if the job
+// hasn't entered a phase yet.
+func (srv *Service) logPhase(name string) string {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+
+	jl, ok := srv.logListener[name]
+	if !ok {
+		return ""
+	}
+	return jl.Phase
+}
+
+// enterLogPhase records that a job entered a new log phase and reports the phase to GitHub,
+// so that the progress of a job is visible on the commit.
+func (srv *Service) enterLogPhase(ctx context.Context, name string, evt *v1.LogSliceEvent) {
+	phase := strings.TrimSpace(evt.Payload)
+	if phase == "" {
+		phase = evt.Name
+	}
+
+	srv.mu.Lock()
+	jl, ok := srv.logListener[name]
+	changed := ok && jl.Phase != phase
+	if ok {
+		jl.Phase = phase
+	}
+	srv.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	job, err := srv.Jobs.Get(ctx, name)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Debug("cannot get job to report its phase")
+		return
+	}
+	if job.Phase != v1.JobPhase_PHASE_RUNNING {
+		return
+	}
+	err = srv.updateGitHubStatus(job)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(job.Name, job.Metadata)).Warn("cannot update GitHub status")
+	}
+}
+
 func (srv *Service) updateGitHubStatus(job *v1.JobStatus) error {
 	var wantsUpdate bool
 	for _, a := range job.Metadata.Annotations {
@@ -34,6 +99,10 @@ func (srv *Service) updateGitHubStatus(job *v1.JobStatus) error {
 	if !wantsUpdate {
 		return nil
 	}
+	if srv.Config.PullRequestSummary && job.Metadata.Repository != nil {
+		repo := *job.Metadata.Repository
+		srv.goBackground(func(ctx context.Context) { srv.updatePullRequestSummaries(ctx, repo) })
+	}
 
 	var (
 		state string
@@ -43,23 +112,56 @@ func (srv *Service) updateGitHubStatus(job *v1.JobStatus) error {
 	case v1.JobPhase_PHASE_PREPARING, v1.JobPhase_PHASE_STARTING, v1.JobPhase_PHASE_RUNNING:
 		state = "pending"
 		desc = "build is " + strings.TrimPrefix(strings.ToLower(job.Phase.String()), "phase_")
+		if phase := srv.logPhase(job.Name); phase != "" && job.Phase == v1.JobPhase_PHASE_RUNNING {
+			desc += ": " + phase
+		}
+		if est := srv.estimateDuration(context.Background(), job); est != nil {
+			desc = remainingTime(est) + " - " + desc
+		}
+		if len(desc) > githubMaxDescriptionLength {
+			desc = desc[:githubMaxDescriptionLength-3] + "..."
+		}
 	default:
-		if job.Conditions.Success {
+		if job.Conditions.Skipped {
+			state = "success"
+			desc = "The build was skipped: " + job.Details
+			if len(desc) > githubMaxDescriptionLength {
+				desc = desc[:githubMaxDescriptionLength-3] + "..."
+			}
+		} else if job.Conditions.Success {
 			state = "success"
 			desc = "The build succeeded!"
+		} else if job.Conditions.Muted {
+			// the rerun decides whether the build failed
+			state = "pending"
+			desc = "The build is known to be flaky and failed - running it again"
+		} else if !job.Conditions.DidExecute && job.Details != "" {
+			// the job did not even start, e.g. because its job spec is invalid - tell the user why
+			state = "failure"
+			desc = "The build could not start: " + job.Details
+			if len(desc) > githubMaxDescriptionLength {
+				desc = desc[:githubMaxDescriptionLength-3] + "..."
+			}
 		} else {
 			state = "failure"
 			desc = "The build failed!"
+			if l := logclass.FirstErrorLine(job.LogSummary); l != "" {
+				desc = "The build failed: " + l
+				if len(desc) > githubMaxDescriptionLength {
+					desc = desc[:githubMaxDescriptionLength-3] + "..."
+				}
+			}
 		}
 	}
 	url := fmt.Sprintf("%s/job/%s", srv.Config.BaseURL, job.Name)
+	jobContext := statusContext(job.Metadata)
 	ghstatus := &github.RepoStatus{
 		State:       &state,
 		Description: &desc,
-		Context:     &werftGithubContext,
+		Context:     &jobContext,
 		TargetURL:   &url,
 	}
-	log.WithField("status", ghstatus).Debugf("updating GitHub status for %s", job.Name)
+	log.WithFields(jobLogFields(job.Name, job.Metadata)).WithField("status", ghstatus).Debug("updating GitHub status")
 	ctx := context.Background()
 	_, _, err := srv.GitHub.Client.Repositories.CreateStatus(ctx, job.Metadata.Repository.Owner, job.Metadata.Repository.Repo, job.Metadata.Repository.Revision, ghstatus)
 	if err != nil {
@@ -98,7 +200,7 @@ func (srv *Service) updateGitHubStatus(job *v1.JobStatus) error {
 			},
 		)
 		if err != nil {
-			log.WithError(err).WithField("job", job.Name).Warn("cannot update result status")
+			log.WithError(err).WithFields(jobLogFields(job.Name, job.Metadata)).Warn("cannot update result status")
 		}
 	}
 
@@ -108,12 +210,23 @@ func (srv *Service) updateGitHubStatus(job *v1.JobStatus) error {
 // HandleGithubWebhook handles incoming Github events
 func (srv *Service) HandleGithubWebhook(w http.ResponseWriter, r *http.Request) {
 	var err error
+	logger := log.WithField("delivery", github.DeliveryID(r))
+	ctx, span := tracing.Start(context.Background(), "HandleGithubWebhook",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("github.delivery", github.DeliveryID(r)),
+			attribute.String("github.event", github.WebHookType(r)),
+		),
+	)
+	defer tracing.FinishSpan(span, &err)
+	received := srv.now()
+	ctx = withWebhookReceived(ctx, received)
 	defer func(err *error) {
 		if *err == nil {
 			return
 		}
 
-		log.WithError(*err).Warn("GitHub webhook error")
+		logger.WithError(*err).Warn("GitHub webhook error")
 		http.Error(w, (*err).Error(), http.StatusInternalServerError)
 	}(&err)
 
@@ -122,7 +235,7 @@ func (srv *Service) HandleGithubWebhook(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	payload, err := github.ValidatePayload(r, srv.GitHub.WebhookSecret)
+	payload, secret, err := srv.validateWebhook(r)
 	if err != nil && strings.Contains(err.Error(), "unknown X-Github-Event") {
 		err = nil
 		return
@@ -130,23 +243,149 @@ func (srv *Service) HandleGithubWebhook(w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		return
 	}
-	event, err := github.ParseWebHook(github.WebHookType(r), payload)
-	if err != nil {
+	logger = logger.WithField("secret", secret)
+	if secret != "webhookSecret" {
+		// once no delivery is signed using an additional secret, a rotation of the webhook secret is complete
+		logger.Info("GitHub webhook is signed using an additional webhook secret")
+	}
+
+	eventType := github.WebHookType(r)
+	ctx = withTriggerPayload(ctx, triggerSourceGitHub, eventType, github.DeliveryID(r), received, payload)
+	handled, perr := srv.processGitHubEvent(ctx, logger, eventType, payload)
+	if !handled && perr == nil {
+		http.Error(w, "unhandled event", http.StatusInternalServerError)
+		return
+	}
+	if !handled {
+		err = perr
 		return
 	}
+	if perr != nil {
+		logger.WithError(perr).Warn("GitHub webhook error")
+		srv.putDeadLetter(ctx, logger, github.DeliveryID(r), eventType, payload, perr)
+	}
+}
+
+// validateWebhook checks the signature of a webhook request against all webhook secrets and returns its payload,
+// as well as the secret which matched, i.e. webhookSecret or webhookSecrets[i].
+func (srv *Service) validateWebhook(r *http.Request) (payload []byte, secret string, err error) {
+	type namedSecret struct {
+		Name   string
+		Secret []byte
+	}
+	var secrets []namedSecret
+	if len(srv.GitHub.WebhookSecret) > 0 {
+		secrets = append(secrets, namedSecret{"webhookSecret", srv.GitHub.WebhookSecret})
+	}
+	for i, s := range srv.GitHub.WebhookSecrets {
+		secrets = append(secrets, namedSecret{fmt.Sprintf("webhookSecrets[%d]", i), s})
+	}
+	if len(secrets) == 0 {
+		return nil, "", xerrors.Errorf("no webhook secret configured")
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, s := range secrets {
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		payload, err = github.ValidatePayload(r, s.Secret)
+		if err == nil {
+			return payload, s.Name, nil
+		}
+	}
+	return nil, "", xerrors.Errorf("webhook matches none of the %d webhook secrets: %w", len(secrets), err)
+}
+
+// processGitHubEvent handles a single GitHub webhook event. If the event cannot be parsed or is of
+// a type we don't handle, handled is false.
+func (srv *Service) processGitHubEvent(ctx context.Context, logger *log.Entry, eventType string, payload []byte) (handled bool, err error) {
+	var event interface{}
+	if eventType == mergeGroupEventType {
+		event, err = ParseMergeGroupEvent(payload)
+	} else {
+		event, err = github.ParseWebHook(eventType, payload)
+	}
+	if err != nil {
+		return false, err
+	}
+	if pe, ok := event.(*github.PushEvent); ok {
+		// the central config repository needn't be a repository werft builds
+		srv.refreshCentralConfigOnPush(pe)
+	}
+	drop, err := srv.filterWebhook(ctx, logger, eventType, payload)
+	if err != nil {
+		return true, err
+	}
+	if drop {
+		return true, nil
+	}
+
+	// validateWebhook made sure GitHub sent the event, hence the revisions it names are those of its refs
+	ctx = withSourceVerified(ctx, true)
 	switch event := event.(type) {
 	case *github.PushEvent:
-		srv.processPushEvent(event)
+		return true, srv.processPushEvent(ctx, logger, event)
+	case *github.PullRequestEvent:
+		return true, srv.processPullRequestEvent(ctx, logger, event)
+	case *MergeGroupEvent:
+		return true, srv.processMergeGroupEvent(ctx, logger, event)
+	case *github.IssueCommentEvent:
+		return true, srv.processIssueCommentEvent(ctx, logger, event)
 	case *github.InstallationEvent:
-		srv.processInstallationEvent(event)
+		srv.processInstallationEvent(logger, event)
+		return true, nil
 	default:
-		log.WithField("event", event).Debug("unhandled GitHub event")
-		http.Error(w, "unhandled event", http.StatusInternalServerError)
+		logger.WithField("event", event).Debug("unhandled GitHub event")
+		return false, nil
 	}
 }
 
-func (srv *Service) processPushEvent(event *github.PushEvent) {
-	ctx := context.Background()
+// putDeadLetter places a webhook event which failed processing in the dead letter queue so that it can be replayed later
+func (srv *Service) putDeadLetter(ctx context.Context, logger *log.Entry, id, eventType string, payload []byte, perr error) {
+	if srv.DeadLetters == nil {
+		return
+	}
+
+	if id == "" {
+		id = fmt.Sprintf("unknown-%d", srv.now().UnixNano())
+	}
+	dl := v1.DeadLetter{
+		Id:        id,
+		EventType: eventType,
+		Payload:   payload,
+		Error:     perr.Error(),
+		Received:  srv.timestampNow(),
+		Attempts:  1,
+	}
+	var notStarted *jobsNotStartedError
+	if xerrors.As(perr, &notStarted) {
+		dl.FailedJobs = notStarted.Paths
+	}
+	if prev, err := srv.DeadLetters.Get(ctx, id); err == nil {
+		dl.Received = prev.Received
+		dl.Attempts = prev.Attempts + 1
+		if notStarted == nil {
+			// the jobs which started before must not start again, no matter why the replay failed
+			dl.FailedJobs = prev.FailedJobs
+		}
+	}
+
+	err := srv.DeadLetters.Put(ctx, dl)
+	if err != nil {
+		logger.WithError(err).Error("cannot store failed webhook event in dead letter queue - event is lost")
+		return
+	}
+	logger.WithField("deadLetter", id).Info("stored failed webhook event in dead letter queue")
+	srv.publishEvent(&v1.ServerEvent{
+		Type:       serverEventDeadLetter,
+		Message:    dl.Error,
+		Attributes: map[string]string{"id": id, "event": eventType, "attempts": strconv.Itoa(int(dl.Attempts))},
+	})
+}
+
+func (srv *Service) processPushEvent(ctx context.Context, logger *log.Entry, event *github.PushEvent) error {
 	rev := *event.After
 
 	// the ref is something like refs/heads/ or refs/tags/ ... we want to strip those prefixes
@@ -163,6 +402,8 @@ func (srv *Service) processPushEvent(event *github.PushEvent) {
 	trigger := v1.JobTrigger_TRIGGER_PUSH
 	if event.Deleted != nil && *event.Deleted {
 		trigger = v1.JobTrigger_TRIGGER_DELETED
+	} else if strings.HasPrefix(*event.Ref, "refs/tags/") {
+		trigger = v1.JobTrigger_TRIGGER_TAG
 	}
 
 	metadata := v1.JobMetadata{
@@ -182,33 +423,272 @@ func (srv *Service) processPushEvent(event *github.PushEvent) {
 			},
 		},
 	}
+	if trigger != v1.JobTrigger_TRIGGER_DELETED {
+		metadata.CommitRange = PushCommitRange(event)
+	}
 
-	cp := &GitHubContentProvider{
+	if !srv.webhookAllowed(metadata.Repository) {
+		logger.WithField("repo", fmt.Sprintf("%s/%s", metadata.Repository.Owner, metadata.Repository.Repo)).Info("ignoring webhook event of a repository which is not allowed")
+		return nil
+	}
+
+	var files FileProvider = &GitHubContentProvider{
 		Client:   srv.GitHub.Client,
 		Owner:    metadata.Repository.Owner,
 		Repo:     metadata.Repository.Repo,
 		Revision: rev,
 	}
-	repoCfg, err := getRepoCfg(ctx, cp)
+	if srv.repositoryConfig(metadata.Repository).DeployKey != nil {
+		// Repositories with a deploy key send plain webhooks, i.e. the GitHub app has no access to them. Hence we can
+		// neither read their files nor report the status of their jobs.
+		files = deployKeyFiles{}
+		metadata.Annotations = nil
+	}
+	logger = logger.WithFields(jobLogFields(flatname, &metadata))
+	repoCfg, _, err := srv.getRepoCfg(ctx, metadata.Repository, files)
 	if err != nil {
-		log.WithError(err).WithField("name", flatname).Error("cannot start job")
-		return
+		return xerrors.Errorf("cannot start job: %w", err)
 	}
 
-	// check if we need to build/do anything
-	if !repoCfg.ShouldRun(&metadata) {
-		return
+	srv.syncRequiredChecksOnPush(logger, repoCfg, &metadata)
+
+	if repoCfg.MergeGroups && strings.HasPrefix(metadata.Repository.Ref, mergeQueueRefPrefix) {
+		// the merge_group event of this push starts the jobs
+		logger.Debug("ignoring push to merge queue branch")
+		return nil
 	}
 
-	_, err = srv.StartGitHubJob(ctx, &v1.StartGitHubJobRequest{
-		Metadata: &metadata,
-	})
+	var changed []string
+	if repoCfg.NeedsChangedPaths() {
+		changed = pushChangedPaths(event)
+	}
+	return srv.startGitHubJobs(ctx, logger, repoCfg, &metadata, changed)
+}
+
+// pushChangedPaths lists the files changed by the commits of a push event. If we cannot tell which files
+// changed, e.g. because a new branch was pushed, this returns nil.
+func pushChangedPaths(event *github.PushEvent) []string {
+	if event.GetCreated() || event.GetDeleted() || len(event.Commits) == 0 {
+		return nil
+	}
+
+	res := []string{}
+	for _, c := range event.Commits {
+		res = append(res, c.Added...)
+		res = append(res, c.Removed...)
+		res = append(res, c.Modified...)
+	}
+	return res
+}
+
+func (srv *Service) processPullRequestEvent(ctx context.Context, logger *log.Entry, event *github.PullRequestEvent) error {
+	switch event.GetAction() {
+	case "opened", "reopened", "synchronize", "labeled":
+	default:
+		return nil
+	}
+
+	var (
+		pr   = event.GetPullRequest()
+		head = pr.GetHead()
+		base = pr.GetBase()
+	)
+	if head.GetRepo().GetFullName() != base.GetRepo().GetFullName() {
+		// we'd run code from a fork with the secrets of this repo
+		logger.WithField("pr", pr.GetNumber()).WithField("head", head.GetRepo().GetFullName()).Info("not starting jobs for pull requests from forks")
+		return nil
+	}
+
+	rev := head.GetSHA()
+	metadata := v1.JobMetadata{
+		Owner: pr.GetUser().GetLogin(),
+		Repository: &v1.Repository{
+			Host:     "github.com",
+			Owner:    base.GetRepo().GetOwner().GetLogin(),
+			Repo:     base.GetRepo().GetName(),
+			Ref:      "refs/heads/" + head.GetRef(),
+			Revision: rev,
+		},
+		Trigger: v1.JobTrigger_TRIGGER_PULL_REQUEST,
+		Annotations: []*v1.Annotation{
+			&v1.Annotation{
+				Key:   annotationStatusUpdate,
+				Value: "true",
+			},
+		},
+	}
+
+	if !srv.webhookAllowed(metadata.Repository) {
+		logger.WithField("repo", fmt.Sprintf("%s/%s", metadata.Repository.Owner, metadata.Repository.Repo)).Info("ignoring webhook event of a repository which is not allowed")
+		return nil
+	}
+
+	cp := &GitHubContentProvider{
+		Client:   srv.GitHub.Client,
+		Owner:    metadata.Repository.Owner,
+		Repo:     metadata.Repository.Repo,
+		Revision: rev,
+	}
+	logger = logger.WithFields(jobLogFields(strings.ToLower(strings.ReplaceAll(head.GetRef(), "/", "-")), &metadata))
+	repoCfg, _, err := srv.getRepoCfg(ctx, metadata.Repository, cp)
 	if err != nil {
-		log.WithError(err).Warn("GitHub webhook error")
+		return xerrors.Errorf("cannot start job: %w", err)
+	}
+
+	if event.GetAction() == "labeled" {
+		return srv.startLabelJobs(ctx, logger, repoCfg, &metadata, event.GetLabel().GetName())
+	}
+
+	// the branch of a pull request gets push jobs already - repositories have to ask for pull request jobs explicitly
+	if !repoCfg.PullRequests {
+		return nil
+	}
+
+	var changed []string
+	if repoCfg.NeedsChangedPaths() {
+		changed, err = srv.pullRequestChangedPaths(ctx, metadata.Repository, pr.GetNumber())
+		if err != nil {
+			logger.WithError(err).Warn("cannot list files changed by pull request - starting jobs regardless")
+			changed = nil
+		}
+	}
+	return srv.startGitHubJobs(ctx, logger, repoCfg, &metadata, changed)
+}
+
+// startLabelJobs starts the jobs the repo config maps a pull request label to
+func (srv *Service) startLabelJobs(ctx context.Context, logger *log.Entry, repoCfg *repoconfig.C, metadata *v1.JobMetadata, label string) error {
+	paths := repoCfg.LabelJobPaths(label)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	logger = logger.WithField("label", label)
+	metadata.Annotations = append(metadata.Annotations, &v1.Annotation{Key: annotationPullRequestLabel, Value: label})
+
+	var failed []string
+	for _, path := range replayedJobs(ctx, logger, paths) {
+		_, err := srv.StartGitHubJob(ctx, &v1.StartGitHubJobRequest{
+			Metadata: proto.Clone(metadata).(*v1.JobMetadata),
+			JobPath:  path,
+		})
+		if err != nil {
+			logger.WithError(err).WithField("path", path).Warn("cannot start job")
+			failed = append(failed, path)
+			continue
+		}
+		logger.WithField("path", path).Info("started job for pull request label")
+	}
+	if len(failed) > 0 {
+		return &jobsNotStartedError{Paths: failed}
 	}
+	return nil
+}
+
+// pullRequestChangedPaths lists the files changed by a pull request
+func (srv *Service) pullRequestChangedPaths(ctx context.Context, repo *v1.Repository, number int) ([]string, error) {
+	res := []string{}
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		files, resp, err := srv.GitHub.Client.PullRequests.ListFiles(ctx, repo.Owner, repo.Repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range files {
+			res = append(res, f.GetFilename())
+		}
+		if resp.NextPage == 0 {
+			return res, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// jobsNotStartedError is returned if some of the jobs an event routes to didn't start. Replaying the
+// event starts only those.
+type jobsNotStartedError struct {
+	Paths []string
+}
+
+func (e *jobsNotStartedError) Error() string {
+	return fmt.Sprintf("cannot start jobs %s", strings.Join(e.Paths, ", "))
+}
+
+type replayJobsKey struct{}
+
+// withReplayJobs marks a context as replaying an event, so that only the jobs with the given paths start
+func withReplayJobs(ctx context.Context, paths []string) context.Context {
+	return context.WithValue(ctx, replayJobsKey{}, paths)
+}
+
+// replayedJobs returns those of the paths whose jobs start when ctx replays an event. Unless ctx replays an
+// event, that's all of them.
+func replayedJobs(ctx context.Context, logger *log.Entry, paths []string) []string {
+	only, _ := ctx.Value(replayJobsKey{}).([]string)
+	if len(only) == 0 {
+		return paths
+	}
+
+	var res []string
+	for _, path := range paths {
+		var replay bool
+		for _, o := range only {
+			if o == path {
+				replay = true
+				break
+			}
+		}
+		if !replay {
+			logger.WithField("path", path).Debug("not replaying job which started before")
+			continue
+		}
+		res = append(res, path)
+	}
+	return res
 }
 
-func getRepoCfg(ctx context.Context, fp FileProvider) (*repoconfig.C, error) {
+// startGitHubJobs starts all jobs the repo config routes an event to. If changed is nil, we don't know
+// which files changed and start jobs regardless. If some of the jobs don't start, the error is a jobsNotStartedError.
+func (srv *Service) startGitHubJobs(ctx context.Context, logger *log.Entry, repoCfg *repoconfig.C, metadata *v1.JobMetadata, changed []string) error {
+	var failed []string
+	for _, path := range replayedJobs(ctx, logger, repoCfg.TemplatePaths(metadata, changed)) {
+		_, err := srv.StartGitHubJob(ctx, &v1.StartGitHubJobRequest{
+			Metadata: proto.Clone(metadata).(*v1.JobMetadata),
+			JobPath:  path,
+		})
+		if err != nil {
+			logger.WithError(err).WithField("path", path).Warn("cannot start job")
+			failed = append(failed, path)
+		}
+	}
+	if len(failed) > 0 {
+		return &jobsNotStartedError{Paths: failed}
+	}
+	return nil
+}
+
+// webhookAllowed returns true if werft handles webhook events of a repository
+func (srv *Service) webhookAllowed(repo *v1.Repository) bool {
+	cfg := srv.config()
+	for _, p := range cfg.DeniedRepositories {
+		if repoMatches(p, repo) {
+			return false
+		}
+	}
+	if len(cfg.AllowedRepositories) == 0 {
+		return true
+	}
+	for _, p := range cfg.AllowedRepositories {
+		if repoMatches(p, repo) {
+			return true
+		}
+	}
+	return false
+}
+
+func getRepoCfg(ctx context.Context, fp FileProvider) (cfg *repoconfig.C, err error) {
+	ctx, span := tracing.Start(ctx, "getRepoCfg")
+	defer tracing.FinishSpan(span, &err)
+
 	// download werft config from branch
 	werftYAML, err := fp.Download(ctx, PathWerftConfig)
 	if err != nil {
@@ -224,12 +704,30 @@ func getRepoCfg(ctx context.Context, fp FileProvider) (*repoconfig.C, error) {
 	return &repoCfg, nil
 }
 
-func (srv *Service) processInstallationEvent(event *github.InstallationEvent) {
+// applyRepoCfg sets up a job with the defaults of its repo config
+func applyRepoCfg(md *v1.JobMetadata, cfg *repoconfig.C) {
+	cfg.ApplyAnnotations(md)
+	if cfg.StatusChecks != nil && cfg.StatusChecks.PerJob {
+		setAnnotation(md, annotationPerJobStatus, "true")
+	}
+
+	if len(cfg.ResultChannels) == 0 {
+		return
+	}
+	for _, a := range md.Annotations {
+		if a.Key == annotationResultChannels {
+			return
+		}
+	}
+	md.Annotations = append(md.Annotations, &v1.Annotation{Key: annotationResultChannels, Value: strings.Join(cfg.ResultChannels, ",")})
+}
+
+func (srv *Service) processInstallationEvent(logger *log.Entry, event *github.InstallationEvent) {
 	if *event.Action != "created" {
 		return
 	}
 
-	log.WithFields(log.Fields{
+	logger.WithFields(log.Fields{
 		"action":         *event.Action,
 		"sender":         event.Sender.Name,
 		"installationID": *event.Installation.ID,
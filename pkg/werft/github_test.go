@@ -0,0 +1,128 @@
+package werft_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+const webhookSecret = "secret"
+
+// newPushWebhook produces a signed GitHub webhook request of a push to master
+func newPushWebhook(owner, repo string) *http.Request {
+	payload := []byte(fmt.Sprintf(`{"ref":"refs/heads/master","after":"abc","repository":{"name":%q,"owner":{"name":%q}},"pusher":{"name":"bot"}}`, repo, owner))
+	mac := hmac.New(sha1.New, []byte(webhookSecret))
+	mac.Write(payload)
+
+	req := httptest.NewRequest("POST", "/github/app", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-GitHub-Delivery", "delivery")
+	req.Header.Set("X-Hub-Signature", "sha1="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestWebhookRepositoryFilter(t *testing.T) {
+	// werft only gets to ignore the events of these tests - handling them would require GitHub
+	tests := []struct {
+		Name    string
+		Allowed []string
+		Denied  []string
+		Owner   string
+		Repo    string
+	}{
+		{"not allowed", []string{"32leaves/*"}, nil, "someone", "werft"},
+		{"not allowed by host", []string{"github.example.com/32leaves/*"}, nil, "32leaves", "werft"},
+		{"denied", nil, []string{"32leaves/secret-*"}, "32leaves", "secret-stuff"},
+		{"denied despite allowed", []string{"32leaves/*"}, []string{"github.com/32leaves/secret-stuff"}, "32leaves", "secret-stuff"},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			dls := store.NewInMemoryDeadLetters()
+			srv := &werft.Service{
+				DeadLetters: dls,
+				GitHub:      werft.GitHubSetup{WebhookSecret: []byte(webhookSecret)},
+				Config: werft.Config{
+					AllowedRepositories: test.Allowed,
+					DeniedRepositories:  test.Denied,
+				},
+			}
+
+			rec := httptest.NewRecorder()
+			srv.HandleGithubWebhook(rec, newPushWebhook(test.Owner, test.Repo))
+
+			if rec.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+			}
+			dl, err := dls.List(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(dl) != 0 {
+				t.Errorf("expected the event to be ignored, but it ended up in the dead letter queue: %v", dl)
+			}
+		})
+	}
+}
+
+func TestWebhookSecrets(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Secret  string
+		Secrets []string
+		Status  int
+		// Logged is the secret which is logged as signing the webhook, if any
+		Logged string
+	}{
+		{"single secret", webhookSecret, nil, http.StatusOK, ""},
+		{"rotated secret", "old", []string{"other", webhookSecret}, http.StatusOK, "webhookSecrets[1]"},
+		{"only secrets", "", []string{webhookSecret}, http.StatusOK, "webhookSecrets[0]"},
+		{"wrong secret", "old", []string{"other"}, http.StatusInternalServerError, ""},
+		{"no secret", "", nil, http.StatusInternalServerError, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			hook := logtest.NewGlobal()
+			defer log.StandardLogger().ReplaceHooks(make(log.LevelHooks))
+
+			gh := werft.GitHubSetup{WebhookSecret: []byte(test.Secret)}
+			for _, s := range test.Secrets {
+				gh.WebhookSecrets = append(gh.WebhookSecrets, []byte(s))
+			}
+			srv := &werft.Service{
+				DeadLetters: store.NewInMemoryDeadLetters(),
+				GitHub:      gh,
+				// werft gets to ignore the event once its signature is valid
+				Config: werft.Config{AllowedRepositories: []string{"32leaves/*"}},
+			}
+
+			rec := httptest.NewRecorder()
+			srv.HandleGithubWebhook(rec, newPushWebhook("someone", "werft"))
+
+			if rec.Code != test.Status {
+				t.Errorf("expected status %d, got %d: %s", test.Status, rec.Code, rec.Body.String())
+			}
+			var logged string
+			for _, e := range hook.AllEntries() {
+				if e.Level <= log.InfoLevel && strings.Contains(e.Message, "additional webhook secret") {
+					logged = e.Data["secret"].(string)
+				}
+			}
+			if logged != test.Logged {
+				t.Errorf("expected webhook signed using %q to be logged, got %q", test.Logged, logged)
+			}
+		})
+	}
+}
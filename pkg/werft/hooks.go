@@ -0,0 +1,123 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// hookTimeout is the time a hook gets to respond
+const hookTimeout = 10 * time.Second
+
+// Hook takes part in handling webhooks and running jobs, e.g. a hook plugin.
+// Hooks which don't implement one of the calls return an empty response.
+type Hook interface {
+	// Name identifies the hook in logs and errors
+	Name() string
+
+	// FilterWebhook decides if werft handles a webhook event
+	FilterWebhook(ctx context.Context, req *v1.FilterWebhookRequest) (*v1.FilterWebhookResponse, error)
+
+	// MutateJob can change a job before it starts, or reject it
+	MutateJob(ctx context.Context, req *v1.MutateJobRequest) (*v1.MutateJobResponse, error)
+
+	// JobDone is called once a job is done
+	JobDone(ctx context.Context, req *v1.JobDoneRequest) error
+}
+
+// AddHook adds a hook which is called for all webhooks and jobs from now on
+func (srv *Service) AddHook(h Hook) {
+	srv.hookMu.Lock()
+	defer srv.hookMu.Unlock()
+
+	srv.hooks = append(srv.hooks, h)
+}
+
+func (srv *Service) getHooks() []Hook {
+	srv.hookMu.RLock()
+	defer srv.hookMu.RUnlock()
+
+	return srv.hooks
+}
+
+// filterWebhook returns true if a hook wants werft to ignore a webhook event
+func (srv *Service) filterWebhook(ctx context.Context, logger *log.Entry, eventType string, payload []byte) (drop bool, err error) {
+	for _, h := range srv.getHooks() {
+		hctx, cancel := context.WithTimeout(ctx, hookTimeout)
+		resp, err := h.FilterWebhook(hctx, &v1.FilterWebhookRequest{EventType: eventType, Payload: payload})
+		cancel()
+		if err != nil {
+			return false, xerrors.Errorf("hook %s cannot filter webhook: %w", h.Name(), err)
+		}
+		if resp.GetDrop() {
+			logger.WithField("hook", h.Name()).WithField("reason", resp.Reason).Info("hook dropped webhook event")
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mutateJob passes a job through all hooks, which may change its pod spec and annotations, before it starts
+func (srv *Service) mutateJob(ctx context.Context, name string, md *v1.JobMetadata, podspec *corev1.PodSpec) (*corev1.PodSpec, error) {
+	for _, h := range srv.getHooks() {
+		spec, err := json.Marshal(podspec)
+		if err != nil {
+			return nil, err
+		}
+
+		hctx, cancel := context.WithTimeout(ctx, hookTimeout)
+		resp, err := h.MutateJob(hctx, &v1.MutateJobRequest{Name: name, Metadata: md, PodSpec: spec})
+		cancel()
+		if err != nil {
+			return nil, xerrors.Errorf("hook %s cannot mutate job: %w", h.Name(), err)
+		}
+		if resp.GetReject() != "" {
+			return nil, xerrors.Errorf("rejected by hook %s: %s", h.Name(), resp.Reject)
+		}
+
+		if len(resp.GetPodSpec()) > 0 {
+			var mutated corev1.PodSpec
+			err = json.Unmarshal(resp.PodSpec, &mutated)
+			if err != nil {
+				return nil, xerrors.Errorf("hook %s produced an invalid pod spec: %w", h.Name(), err)
+			}
+			podspec = &mutated
+		}
+		for _, a := range resp.GetAnnotations() {
+			setAnnotation(md, a.Key, a.Value)
+		}
+	}
+	return podspec, nil
+}
+
+// jobDone tells all hooks that a job is done. The hooks are called in the background.
+func (srv *Service) jobDone(s *v1.JobStatus) {
+	for _, h := range srv.getHooks() {
+		h := h
+		srv.goBackground(func(ctx context.Context) {
+			ctx, cancel := context.WithTimeout(ctx, hookTimeout)
+			defer cancel()
+
+			err := h.JobDone(ctx, &v1.JobDoneRequest{Job: s})
+			if err != nil {
+				log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).WithField("hook", h.Name()).Warn("hook failed handling done job")
+			}
+		})
+	}
+}
+
+// setAnnotation sets an annotation on a job, replacing an existing annotation with the same key
+func setAnnotation(md *v1.JobMetadata, key, value string) {
+	for _, a := range md.Annotations {
+		if a.Key == key {
+			a.Value = value
+			return
+		}
+	}
+	md.Annotations = append(md.Annotations, &v1.Annotation{Key: key, Value: value})
+}
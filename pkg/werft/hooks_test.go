@@ -0,0 +1,71 @@
+package werft_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+type fakeHook struct {
+	Drop bool
+	Err  error
+}
+
+func (h *fakeHook) Name() string { return "fake" }
+
+func (h *fakeHook) FilterWebhook(ctx context.Context, req *v1.FilterWebhookRequest) (*v1.FilterWebhookResponse, error) {
+	if h.Err != nil {
+		return nil, h.Err
+	}
+	return &v1.FilterWebhookResponse{Drop: h.Drop, Reason: "testing"}, nil
+}
+
+func (h *fakeHook) MutateJob(ctx context.Context, req *v1.MutateJobRequest) (*v1.MutateJobResponse, error) {
+	return &v1.MutateJobResponse{}, nil
+}
+
+func (h *fakeHook) JobDone(ctx context.Context, req *v1.JobDoneRequest) error {
+	return nil
+}
+
+func TestFilterWebhook(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Hook        *fakeHook
+		Status      int
+		DeadLetters int
+	}{
+		{"dropped", &fakeHook{Drop: true}, http.StatusOK, 0},
+		{"hook fails", &fakeHook{Err: fmt.Errorf("plugin is down")}, http.StatusOK, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			dls := store.NewInMemoryDeadLetters()
+			srv := &werft.Service{
+				DeadLetters: dls,
+				GitHub:      werft.GitHubSetup{WebhookSecret: []byte(webhookSecret)},
+			}
+			srv.AddHook(test.Hook)
+
+			rec := httptest.NewRecorder()
+			srv.HandleGithubWebhook(rec, newPushWebhook("32leaves", "werft"))
+
+			if rec.Code != test.Status {
+				t.Errorf("expected status %d, got %d: %s", test.Status, rec.Code, rec.Body.String())
+			}
+			dl, err := dls.List(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(dl) != test.DeadLetters {
+				t.Errorf("expected %d dead letters, got %d", test.DeadLetters, len(dl))
+			}
+		})
+	}
+}
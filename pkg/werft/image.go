@@ -0,0 +1,144 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/gogo/protobuf/jsonpb"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// resultTypeImage marks results which report a container image a job built
+	resultTypeImage = "image"
+
+	imageWebhookTimeout = 10 * time.Second
+)
+
+var imageDigest = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// ImageWebhookConfig configures where werft pushes the images jobs built, e.g. to an artifact metadata service
+type ImageWebhookConfig struct {
+	// URL receives a POST request with the image build (JSON) whenever a job reports an image
+	URL string `yaml:"url"`
+
+	// Headers are sent with every request, e.g. for authentication
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// ParseImageResult parses the payload of an image result, e.g. eu.gcr.io/werft/werft:master@sha256:4a1c...,
+// into the image name and its digest.
+func ParseImageResult(payload string) (image, digest string, err error) {
+	payload = strings.TrimSpace(payload)
+	i := strings.LastIndex(payload, "@")
+	if i < 0 {
+		return "", "", xerrors.Errorf("image %s has no digest: expected name@sha256:<digest>", payload)
+	}
+	image, digest = payload[:i], payload[i+1:]
+	if image == "" {
+		return "", "", xerrors.Errorf("image %s has no name", payload)
+	}
+	if !imageDigest.MatchString(digest) {
+		return "", "", xerrors.Errorf("invalid image digest %s: expected sha256:<64 hex characters>", digest)
+	}
+	return image, digest, nil
+}
+
+// recordImageBuild records the image a job reported as result, so that one can find the job which built an image
+func (srv *Service) recordImageBuild(ctx context.Context, name string, res *v1.JobResult) {
+	if srv.Images == nil {
+		return
+	}
+
+	image, digest, err := ParseImageResult(res.Payload)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("job reported an invalid image result")
+		return
+	}
+	build := v1.ImageBuild{
+		Digest: digest,
+		Image:  image,
+		Job:    name,
+		Built:  srv.timestampNow(),
+	}
+	job, err := srv.Jobs.Get(ctx, name)
+	if err == nil {
+		build.Repository = job.GetMetadata().GetRepository()
+	} else {
+		log.WithError(err).WithField("name", name).Debug("cannot get job to determine the repository of an image")
+	}
+
+	err = srv.Images.Put(ctx, build)
+	if err != nil {
+		log.WithError(err).WithField("name", name).WithField("image", res.Payload).Warn("cannot record image build")
+		return
+	}
+
+	if srv.Config.ImageWebhook != nil {
+		srv.goBackground(func(ctx context.Context) {
+			err := pushImageBuild(ctx, srv.httpClient(imageWebhookTimeout), srv.Config.ImageWebhook, &build)
+			if err != nil {
+				log.WithError(err).WithField("name", name).WithField("image", res.Payload).Warn("cannot push image build to webhook")
+			}
+		})
+	}
+}
+
+// pushImageBuild sends an image build to the image webhook
+func pushImageBuild(ctx context.Context, client *http.Client, cfg *ImageWebhookConfig, build *v1.ImageBuild) error {
+	var body bytes.Buffer
+	err := (&jsonpb.Marshaler{}).Marshal(&body, build)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, cfg.URL, &body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return xerrors.Errorf("image webhook responded with %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// FindImageBuilds returns the jobs which built a container image
+func (srv *Service) FindImageBuilds(ctx context.Context, req *v1.FindImageBuildsRequest) (*v1.FindImageBuildsResponse, error) {
+	if srv.Images == nil {
+		return nil, status.Error(codes.Unavailable, "image builds are not recorded")
+	}
+	if !imageDigest.MatchString(req.Digest) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid digest %s: expected sha256:<64 hex characters>", req.Digest)
+	}
+
+	builds, err := srv.Images.Find(ctx, req.Digest)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := make([]*v1.ImageBuild, len(builds))
+	for i := range builds {
+		res[i] = &builds[i]
+	}
+	return &v1.FindImageBuildsResponse{Builds: res}, nil
+}
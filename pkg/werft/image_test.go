@@ -0,0 +1,41 @@
+package werft_test
+
+import (
+	"testing"
+
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestParseImageResult(t *testing.T) {
+	const digest = "sha256:4a1c4b21597c1b4415bdbecb28a3296c6b5e23ca4f9feeb599860a1dac6b0108"
+
+	tests := []struct {
+		Name    string
+		Payload string
+		Image   string
+		Digest  string
+		Valid   bool
+	}{
+		{"name and digest", "eu.gcr.io/werft/werft@" + digest, "eu.gcr.io/werft/werft", digest, true},
+		{"tag and digest", "eu.gcr.io/werft/werft:master@" + digest, "eu.gcr.io/werft/werft:master", digest, true},
+		{"registry port", "localhost:5000/werft@" + digest, "localhost:5000/werft", digest, true},
+		{"no digest", "eu.gcr.io/werft/werft:master", "", "", false},
+		{"no name", "@" + digest, "", "", false},
+		{"short digest", "eu.gcr.io/werft/werft@sha256:4a1c", "", "", false},
+		{"unsupported algorithm", "eu.gcr.io/werft/werft@md5:4a1c", "", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			image, digest, err := werft.ParseImageResult(test.Payload)
+			if valid := err == nil; valid != test.Valid {
+				t.Fatalf("expected valid to be %v, got %v", test.Valid, err)
+			}
+			if image != test.Image {
+				t.Errorf("expected image %s, got %s", test.Image, image)
+			}
+			if digest != test.Digest {
+				t.Errorf("expected digest %s, got %s", test.Digest, digest)
+			}
+		})
+	}
+}
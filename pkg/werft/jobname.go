@@ -0,0 +1,177 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/store"
+	sprig "github.com/Masterminds/sprig/v3"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DefaultJobNameTemplate produces the job names werft has always used, e.g. werft-build-master
+	DefaultJobNameTemplate = "{{ .Repo }}-{{ .JobSpec }}-{{ .Ref }}"
+
+	// labelLegacyName is the label under which we record the name a job would have had under the default naming scheme
+	labelLegacyName = "legacy-name"
+
+	// maxJobNameLength leaves space for the job number, as Kubernetes label values must not be longer than 63 characters according to
+	// https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set
+	// Assuming that we won't have more than 9999 builds on a single long named branch, leaving four chars should be enough.
+	maxJobNameLength = 58
+)
+
+// JobNameData is available to job name templates
+type JobNameData struct {
+	// Owner is the owner of the repository, e.g. 32leaves
+	Owner string
+	// Repo is the name of the repository, e.g. werft
+	Repo string
+	// Ref is the branch or tag name the job runs on, e.g. master
+	Ref string
+	// JobSpec is the name of the job spec file without extension, e.g. build
+	JobSpec string
+	// Trigger is what caused the job to start, e.g. push or manual
+	Trigger string
+}
+
+// RenderJobName produces the name of a job (without its number) from a job name template.
+// The result is turned into a slug that's safe to use as job name.
+func RenderJobName(tpl string, md *v1.JobMetadata, jobSpec string) (string, error) {
+	return renderJobName(tpl, md, jobSpec, MonikerIDs{})
+}
+
+// renderJobName renders a job name, using ids if the job's ref doesn't make for a sensible name
+func renderJobName(tpl string, md *v1.JobMetadata, jobSpec string, ids IDGenerator) (string, error) {
+	if tpl == "" {
+		tpl = DefaultJobNameTemplate
+	}
+	t, err := template.New("name").Funcs(sprig.TxtFuncMap()).Option("missingkey=error").Parse(tpl)
+	if err != nil {
+		return "", xerrors.Errorf("invalid job name template: %w", err)
+	}
+
+	ref := refSlug(md.Repository.Ref)
+	if ref == "" {
+		// we did not compute a sensible refname - use a random ID
+		ref = ids.NewID()
+	}
+	data := JobNameData{
+		Owner:   md.Repository.Owner,
+		Repo:    md.Repository.Repo,
+		Ref:     ref,
+		JobSpec: jobSpec,
+		Trigger: repoconfig.TriggerName(md.Trigger),
+	}
+
+	var buf bytes.Buffer
+	err = t.Execute(&buf, data)
+	if err != nil {
+		return "", xerrors.Errorf("cannot render job name: %w", err)
+	}
+
+	name := slugify(buf.String())
+	if name == "" {
+		return "", xerrors.Errorf("job name template produced an empty name")
+	}
+	if len(name) > maxJobNameLength {
+		name = strings.TrimRight(name[:maxJobNameLength], "-.")
+	}
+	return name, nil
+}
+
+// refSlug turns a Git ref into something we can use in a job name, e.g. refs/heads/feature/foo becomes feature-foo
+func refSlug(ref string) string {
+	ref = strings.TrimPrefix(ref, "refs/heads/")
+	ref = strings.TrimPrefix(ref, "refs/tags/")
+	ref = strings.ReplaceAll(ref, "/", "-")
+	ref = strings.ReplaceAll(ref, "_", "-")
+	ref = strings.ReplaceAll(ref, "@", "-")
+	return strings.ToLower(ref)
+}
+
+var (
+	invalidJobNameChars = regexp.MustCompile(`[^a-z0-9\-.]+`)
+	repeatedDashes      = regexp.MustCompile(`-{2,}`)
+)
+
+// slugify lowercases a name and replaces everything which isn't valid in a job name with dashes
+func slugify(name string) string {
+	name = strings.ToLower(name)
+	name = invalidJobNameChars.ReplaceAllString(name, "-")
+	name = repeatedDashes.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-.")
+}
+
+// numberJob appends the next number of a job name group to name. The resulting name is guaranteed not to be taken
+// by an existing job, even if the number group was reset.
+func (srv *Service) numberJob(ctx context.Context, name string) (string, error) {
+	for i := 0; i < 100; i++ {
+		t, err := srv.Groups.Next(name)
+		if err != nil {
+			return "", err
+		}
+
+		res := fmt.Sprintf("%s.%d", name, t)
+		_, err = srv.Jobs.Get(ctx, res)
+		if err == store.ErrNotFound {
+			return res, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", xerrors.Errorf("cannot find a free job name for %s", name)
+}
+
+// newGitHubJobName produces a unique name for a job started from GitHub. If the naming scheme differs from the
+// default one, it also returns the name the job would have had using the default scheme.
+func (srv *Service) newGitHubJobName(ctx context.Context, md *v1.JobMetadata, jobSpec string) (name, legacyName string, err error) {
+	base, err := renderJobName(srv.Config.JobNameTemplate, md, jobSpec, srv.ids())
+	if err != nil {
+		return "", "", err
+	}
+	name, err = srv.numberJob(ctx, base)
+	if err != nil {
+		return "", "", err
+	}
+
+	if srv.Config.JobNameTemplate == "" || srv.Config.JobNameTemplate == DefaultJobNameTemplate {
+		return name, "", nil
+	}
+
+	legacyBase, err := renderJobName(DefaultJobNameTemplate, md, jobSpec, srv.ids())
+	if err != nil {
+		return "", "", err
+	}
+	legacyName, err = srv.numberJob(ctx, legacyBase)
+	if err != nil {
+		return "", "", err
+	}
+	return name, legacyName, nil
+}
+
+// resolveJobName returns the name of a job, which may also be known by its default-scheme name.
+// If no job is known by name, name is returned as is.
+func (srv *Service) resolveJobName(ctx context.Context, name string) string {
+	_, err := srv.Jobs.Get(ctx, name)
+	if err != store.ErrNotFound {
+		return name
+	}
+
+	jobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{
+		&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: filterexpr.LabelFieldPrefix + labelLegacyName, Value: name, Operation: v1.FilterOp_OP_EQUALS}}},
+	}, []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: true}}, 0, 1)
+	if err != nil || len(jobs) == 0 {
+		return name
+	}
+	return jobs[0].Name
+}
@@ -0,0 +1,68 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestRenderJobName(t *testing.T) {
+	md := &v1.JobMetadata{
+		Repository: &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "refs/heads/feature/Some_Thing"},
+		Trigger:    v1.JobTrigger_TRIGGER_PUSH,
+	}
+
+	tests := []struct {
+		Name        string
+		Template    string
+		Expectation string
+		Error       bool
+	}{
+		{"default", "", "werft-build-feature-some-thing", false},
+		{"repo and branch", "{{ .Repo }}-{{ .Ref }}", "werft-feature-some-thing", false},
+		{"owner and trigger", "{{ .Owner }}/{{ .Repo }} {{ .Trigger }}", "32leaves-werft-push", false},
+		{"too long", "{{ .Repo }}-{{ repeat 20 \"abc\" }}", "werft-abcabcabcabcabcabcabcabcabcabcabcabcabcabcabcabcabca", false},
+		{"empty", "---", "", true},
+		{"unknown field", "{{ .Foo }}", "", true},
+		{"invalid", "{{ .Repo ", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := werft.RenderJobName(test.Template, md, "build")
+			if (err != nil) != test.Error {
+				t.Fatalf("expected error: %v, got %v", test.Error, err)
+			}
+			if act != test.Expectation {
+				t.Errorf("expected %q, got %q", test.Expectation, act)
+			}
+		})
+	}
+}
+
+func TestGetJobByLegacyName(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	err := jobs.Store(context.Background(), v1.JobStatus{
+		Name: "werft-master.3",
+		Metadata: &v1.JobMetadata{
+			Repository: &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"},
+			Labels:     map[string]string{"legacy-name": "werft-build-master.7"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("cannot store job: %v", err)
+	}
+	srv := &werft.Service{Jobs: jobs}
+
+	for _, name := range []string{"werft-master.3", "werft-build-master.7"} {
+		resp, err := srv.GetJob(context.Background(), &v1.GetJobRequest{Name: name})
+		if err != nil {
+			t.Fatalf("cannot get job %s: %v", name, err)
+		}
+		if resp.Result.Name != "werft-master.3" {
+			t.Errorf("expected %s to resolve to werft-master.3, got %s", name, resp.Result.Name)
+		}
+	}
+}
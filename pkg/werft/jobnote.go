@@ -0,0 +1,68 @@
+package werft
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// addJobNotePermission is the GitHub permission users need on the repository of a job to add notes to it
+	addJobNotePermission = "write"
+
+	// maxJobNoteLength is the number of characters a note can have at most
+	maxJobNoteLength = 2000
+)
+
+// AddJobNote attaches a note to a job
+func (srv *Service) AddJobNote(ctx context.Context, req *v1.AddJobNoteRequest) (*v1.AddJobNoteResponse, error) {
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return nil, status.Error(codes.InvalidArgument, "note is empty")
+	}
+	if utf8.RuneCountInString(text) > maxJobNoteLength {
+		return nil, status.Errorf(codes.InvalidArgument, "note is longer than %d characters", maxJobNoteLength)
+	}
+
+	name := srv.resolveJobName(ctx, req.Name)
+	job, err := srv.Jobs.Get(ctx, name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s not found", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if job.Metadata.GetRepository() == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s has no repository and cannot have notes", name)
+	}
+
+	user, err := srv.authorizeGitHubUser(ctx, req.GithubToken, job.Metadata.Repository, addJobNotePermission, "add notes to jobs")
+	if err != nil {
+		return nil, err
+	}
+
+	note := &v1.JobNote{Author: user, Created: srv.timestampNow(), Text: text}
+	job.Notes = append(job.Notes, note)
+	err = srv.Jobs.Store(ctx, *job)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	log.WithFields(jobLogFields(name, job.Metadata)).WithField("user", user).Info("note added to job")
+	<-srv.events.Emit("job", job)
+
+	return &v1.AddJobNoteResponse{Note: note}, nil
+}
+
+// keepNotes carries the notes of a job over to a new status of the job, which is computed without them
+func keepNotes(s, prev *v1.JobStatus) {
+	if prev == nil || len(s.Notes) > 0 {
+		return
+	}
+	s.Notes = prev.Notes
+}
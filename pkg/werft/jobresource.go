@@ -0,0 +1,201 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/retry"
+)
+
+// JobResourcesConfig mirrors jobs as WerftJob custom resources
+type JobResourcesConfig struct {
+	// Namespace is where the WerftJob resources live. Defaults to the namespace of the executor.
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// JobResourceGVR identifies the WerftJob custom resource jobs are mirrored as
+var JobResourceGVR = schema.GroupVersionResource{Group: "werft.sh", Version: "v1", Resource: "werftjobs"}
+
+const (
+	// JobResourceKind is the kind of the custom resource jobs are mirrored as
+	JobResourceKind = "WerftJob"
+
+	// labelJobResourcePhase is the label of WerftJob resources holding the job's phase, e.g. running
+	labelJobResourcePhase = "werft.sh/phase"
+
+	// labelJobResourceRepo is the label of WerftJob resources holding the job's repository as owner.repo
+	labelJobResourceRepo = "werft.sh/repo"
+
+	// labelJobResourceSuccess is the label of WerftJob resources which says whether the job succeeded
+	labelJobResourceSuccess = "werft.sh/success"
+)
+
+// mirrorJobResources keeps the WerftJob resources up to date with all job updates until ctx is done
+func (srv *Service) mirrorJobResources(ctx context.Context) {
+	for {
+		sub := srv.subscribeJobs(ctx)
+		for {
+			job, err := sub.Next(ctx)
+			if err == errSlowSubscriber {
+				// we've missed updates, but each update carries the full job - we'll catch up with the next ones
+				log.WithError(err).Warn("job resources fell behind")
+				break
+			}
+			if err != nil {
+				return
+			}
+
+			err = srv.syncJobResource(job)
+			if err != nil {
+				log.WithError(err).WithFields(jobLogFields(job.Name, job.Metadata)).Warn("cannot mirror job as custom resource")
+			}
+		}
+	}
+}
+
+// syncJobResource creates or updates the WerftJob resource of a job
+func (srv *Service) syncJobResource(job *v1.JobStatus) error {
+	desired := jobResource(job)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := srv.JobResources.Get(desired.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			obj, err = srv.JobResources.Create(desired, metav1.CreateOptions{})
+		} else if err == nil && (!reflect.DeepEqual(obj.GetLabels(), desired.GetLabels()) || !reflect.DeepEqual(obj.Object["spec"], desired.Object["spec"])) {
+			obj.SetLabels(desired.GetLabels())
+			obj.Object["spec"] = desired.Object["spec"]
+			obj, err = srv.JobResources.Update(obj, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return err
+		}
+
+		// the status is a subresource, hence creating or updating the resource leaves it alone
+		if reflect.DeepEqual(obj.Object["status"], desired.Object["status"]) {
+			return nil
+		}
+		obj.Object["status"] = desired.Object["status"]
+		_, err = srv.JobResources.UpdateStatus(obj, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// deleteJobResource removes the WerftJob resource of a job, e.g. once it's archived or purged
+func (srv *Service) deleteJobResource(name string) error {
+	if srv.JobResources == nil {
+		return nil
+	}
+	err := srv.JobResources.Delete(jobResourceName(name), &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return xerrors.Errorf("cannot delete job resource of %s: %w", name, err)
+	}
+	return nil
+}
+
+// jobResource produces the WerftJob resource of a job
+func jobResource(job *v1.JobStatus) *unstructured.Unstructured {
+	md := job.Metadata
+	if md == nil {
+		md = &v1.JobMetadata{}
+	}
+
+	labels := map[string]string{
+		labelJobResourcePhase:   strings.ToLower(strings.TrimPrefix(job.Phase.String(), "PHASE_")),
+		labelJobResourceSuccess: fmt.Sprint(job.Conditions.GetSuccess()),
+	}
+	spec := map[string]interface{}{
+		"name":    job.Name,
+		"owner":   md.Owner,
+		"trigger": strings.ToLower(strings.TrimPrefix(md.Trigger.String(), "TRIGGER_")),
+	}
+	if repo := md.Repository; repo != nil {
+		labels[labelJobResourceRepo] = jobResourceLabelValue(repo.Owner + "." + repo.Repo)
+		spec["repository"] = map[string]interface{}{
+			"host":     repo.Host,
+			"owner":    repo.Owner,
+			"repo":     repo.Repo,
+			"ref":      repo.Ref,
+			"revision": repo.Revision,
+		}
+	}
+	if len(md.Annotations) > 0 {
+		annotations := make(map[string]interface{}, len(md.Annotations))
+		for _, a := range md.Annotations {
+			annotations[a.Key] = a.Value
+		}
+		spec["annotations"] = annotations
+	}
+
+	status := map[string]interface{}{
+		"phase":   labels[labelJobResourcePhase],
+		"success": job.Conditions.GetSuccess(),
+	}
+	if job.Details != "" {
+		status["details"] = job.Details
+	}
+	if c := job.Conditions; c != nil {
+		status["failureCount"] = int64(c.FailureCount)
+		status["didExecute"] = c.DidExecute
+	}
+	if t := jobResourceTime(md.Created); t != "" {
+		status["created"] = t
+	}
+	if t := jobResourceTime(md.Finished); t != "" {
+		status["finished"] = t
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec":   spec,
+		"status": status,
+	}}
+	obj.SetAPIVersion(JobResourceGVR.GroupVersion().String())
+	obj.SetKind(JobResourceKind)
+	obj.SetName(jobResourceName(job.Name))
+	obj.SetLabels(labels)
+	return obj
+}
+
+// jobResourceTime formats a job's timestamp like Kubernetes does, or returns an empty string if there is none
+func jobResourceTime(ts *timestamp.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+var jobResourceInvalidChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// jobResourceName turns a job name into a valid resource name, e.g. werft-build-master.12
+func jobResourceName(name string) string {
+	res := jobResourceInvalidChars.ReplaceAllString(strings.ToLower(name), "-")
+	if len(res) > 253 {
+		res = res[:253]
+	}
+	return strings.Trim(res, "-.")
+}
+
+var labelValueInvalidChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// jobResourceLabelValue turns a string into a valid label value
+func jobResourceLabelValue(val string) string {
+	res := labelValueInvalidChars.ReplaceAllString(val, "-")
+	if len(res) > 63 {
+		res = res[:63]
+	}
+	return strings.Trim(res, "-._")
+}
@@ -0,0 +1,44 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+func TestPurgeJobDeletesJobResource(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(werft.JobResourceGVR.GroupVersion().String())
+	obj.SetKind(werft.JobResourceKind)
+	obj.SetNamespace("werft")
+	obj.SetName("foo.1")
+	resources := fake.NewSimpleDynamicClient(runtime.NewScheme(), obj).Resource(werft.JobResourceGVR).Namespace("werft")
+
+	ctx := context.Background()
+	srv := &werft.Service{
+		Jobs:         store.NewInMemoryJobStore(),
+		Logs:         store.NewInMemoryLogStore(),
+		JobResources: resources,
+		Config:       werft.Config{AdminTokens: []string{"secret"}},
+	}
+	// foo.1.a has no resource, e.g. because it finished before jobs were mirrored
+	srv.Jobs.Store(ctx, v1.JobStatus{Name: "foo.1", Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{Children: []string{"foo.1.a"}}})
+	srv.Jobs.Store(ctx, v1.JobStatus{Name: "foo.1.a", Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{Parent: "foo.1"}})
+
+	_, err := srv.PurgeJob(ctx, &v1.PurgeJobRequest{Name: "foo.1", Token: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = resources.Get("foo.1", metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected the job resource to be deleted, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package werft_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	werfttesting "github.com/32leaves/werft/pkg/werft/testing"
+	"github.com/google/go-github/github"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStartGitHubJobJobSpecRef(t *testing.T) {
+	const (
+		headRev = "1111111111111111111111111111111111111111"
+		mainRev = "2222222222222222222222222222222222222222"
+	)
+	var (
+		// the job spec of the pull request has no pod, which makes the job fail before it runs
+		headSpec = "description: pull request spec\n"
+		// the job spec of main skips its only container, which makes the job finish without running
+		mainSpec = "pod:\n  containers:\n  - name: build\n    image: alpine:latest\nwhen:\n  build: annotation.never == \"true\"\n"
+		specs    = map[string]string{headRev: headSpec, mainRev: mainSpec}
+		refs     = map[string]string{"main": mainRev, "refs/pull/42/head": headRev}
+	)
+
+	mux := http.NewServeMux()
+	ghsrv := httptest.NewServer(mux)
+	defer ghsrv.Close()
+	mux.HandleFunc("/repos/32leaves/werft/commits/", func(w http.ResponseWriter, r *http.Request) {
+		ref := strings.TrimPrefix(r.URL.Path, "/repos/32leaves/werft/commits/")
+		if strings.Contains(r.Header.Get("Accept"), "sha") {
+			rev, ok := refs[ref]
+			if !ok {
+				http.Error(w, `{"message":"No commit found"}`, http.StatusUnprocessableEntity)
+				return
+			}
+			fmt.Fprint(w, rev)
+			return
+		}
+		fmt.Fprintf(w, `{"sha":%q}`, ref)
+	})
+	mux.HandleFunc("/repos/32leaves/werft/contents/.werft", func(w http.ResponseWriter, r *http.Request) {
+		ref := r.URL.Query().Get("ref")
+		if _, ok := specs[ref]; !ok {
+			t.Errorf("unexpected ref %q", ref)
+		}
+		fmt.Fprintf(w, `[{"name":"build.yaml","download_url":%q}]`, ghsrv.URL+"/raw/"+ref+"/build.yaml")
+	})
+	mux.HandleFunc("/raw/", func(w http.ResponseWriter, r *http.Request) {
+		rev := strings.Split(strings.TrimPrefix(r.URL.Path, "/raw/"), "/")[0]
+		fmt.Fprint(w, specs[rev])
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+
+	h, err := werfttesting.NewHarness(werft.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(ghsrv.URL + "/")
+	h.Service.GitHub = werft.GitHubSetup{Client: client}
+
+	tests := []struct {
+		Name       string
+		JobSpecRef string
+		JobYAML    string
+		Code       codes.Code
+		Error      string
+		Source     *v1.Repository
+	}{
+		{
+			Name:  "spec of the job's revision",
+			Code:  codes.Internal,
+			Error: "pod: is required",
+		},
+		{
+			Name:       "spec of another ref",
+			JobSpecRef: "main",
+			Source:     &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "main", Revision: mainRev},
+		},
+		{
+			Name:       "unknown ref",
+			JobSpecRef: "nope",
+			Code:       codes.NotFound,
+		},
+		{
+			Name:       "with job YAML",
+			JobSpecRef: "main",
+			JobYAML:    mainSpec,
+			Code:       codes.InvalidArgument,
+			Error:      "cannot use a job spec ref with a job YAML",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			req := &v1.StartGitHubJobRequest{
+				Metadata: &v1.JobMetadata{
+					Owner:      "someone",
+					Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/pull/42/head"},
+					Trigger:    v1.JobTrigger_TRIGGER_MANUAL,
+				},
+				JobPath:    ".werft/build.yaml",
+				JobSpecRef: test.JobSpecRef,
+			}
+			if test.JobYAML != "" {
+				req.JobYaml = []byte(test.JobYAML)
+			}
+			resp, err := h.Service.StartGitHubJob(ctx, req)
+			if status.Code(err) != test.Code {
+				t.Fatalf("expected code %v, got %v", test.Code, err)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), test.Error) {
+					t.Errorf("expected error containing %q, got %v", test.Error, err)
+				}
+				return
+			}
+
+			md := resp.Status.Metadata
+			if md.Repository.Revision != headRev {
+				t.Errorf("expected the job to check out %s, got %s", headRev, md.Repository.Revision)
+			}
+			if act := md.JobSpecSource; act == nil || act.String() != test.Source.String() {
+				t.Errorf("expected job spec source %v, got %v", test.Source, act)
+			}
+			if !resp.Status.Conditions.Skipped {
+				t.Errorf("expected the job to use the spec of %s and be skipped, got %v", test.JobSpecRef, resp.Status)
+			}
+		})
+	}
+}
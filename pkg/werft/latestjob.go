@@ -0,0 +1,55 @@
+package werft
+
+import (
+	"context"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// labelJobSpec records the job file a job runs, e.g. build for .werft/build.yaml
+const labelJobSpec = "job-spec"
+
+// GetLatestJob returns the most recent job of a ref, optionally of a particular job spec or only amongst finished jobs
+func (srv *Service) GetLatestJob(ctx context.Context, req *v1.GetLatestJobRequest) (*v1.GetLatestJobResponse, error) {
+	repo := req.Repository
+	if repo == nil || repo.Owner == "" || repo.Repo == "" || repo.Ref == "" {
+		return nil, status.Error(codes.InvalidArgument, "repository owner, repo and ref are required")
+	}
+
+	term := func(field, value string) *v1.FilterExpression {
+		return &v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: field, Value: value, Operation: v1.FilterOp_OP_EQUALS}}}
+	}
+	ref := term("repo.ref", repo.Ref)
+	if !strings.HasPrefix(repo.Ref, "refs/") {
+		// branches can be given by their name, e.g. master for refs/heads/master
+		ref.Terms = append(ref.Terms, &v1.FilterTerm{Field: "repo.ref", Value: "refs/heads/" + repo.Ref, Operation: v1.FilterOp_OP_EQUALS})
+	}
+	filter := []*v1.FilterExpression{
+		term("repo.owner", repo.Owner),
+		term("repo.repo", repo.Repo),
+		ref,
+	}
+	if repo.Host != "" {
+		filter = append(filter, term("repo.host", repo.Host))
+	}
+	if req.JobSpec != "" {
+		filter = append(filter, term(filterexpr.LabelFieldPrefix+labelJobSpec, req.JobSpec))
+	}
+	if req.Done {
+		filter = append(filter, term("phase", "done"))
+	}
+
+	jobs, _, err := srv.Jobs.Find(ctx, filter, []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: false}}, 0, 1)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if len(jobs) == 0 {
+		return nil, status.Errorf(codes.NotFound, "no job found for %s/%s:%s", repo.Owner, repo.Repo, repo.Ref)
+	}
+	job := jobs[0]
+	return &v1.GetLatestJobResponse{Result: &job}, nil
+}
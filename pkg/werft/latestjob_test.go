@@ -0,0 +1,71 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetLatestJob(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	job := func(name, ref, spec string, phase v1.JobPhase, created int64) v1.JobStatus {
+		return v1.JobStatus{
+			Name:  name,
+			Phase: phase,
+			Metadata: &v1.JobMetadata{
+				Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/" + ref},
+				Labels:     map[string]string{"job-spec": spec},
+				Created:    &timestamp.Timestamp{Seconds: created},
+			},
+			Conditions: &v1.JobConditions{},
+		}
+	}
+	for _, j := range []v1.JobStatus{
+		job("werft-build-master.1", "master", "build", v1.JobPhase_PHASE_DONE, 100),
+		job("werft-deploy-master.1", "master", "deploy", v1.JobPhase_PHASE_DONE, 200),
+		job("werft-build-master.2", "master", "build", v1.JobPhase_PHASE_RUNNING, 300),
+		job("werft-build-foo.1", "foo", "build", v1.JobPhase_PHASE_DONE, 400),
+	} {
+		err := jobs.Store(context.Background(), j)
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+	srv := &werft.Service{Jobs: jobs}
+
+	repo := &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "master"}
+	tests := []struct {
+		Name        string
+		Request     *v1.GetLatestJobRequest
+		Expectation string
+		Code        codes.Code
+	}{
+		{"missing ref", &v1.GetLatestJobRequest{Repository: &v1.Repository{Owner: "32leaves", Repo: "werft"}}, "", codes.InvalidArgument},
+		{"latest", &v1.GetLatestJobRequest{Repository: repo}, "werft-build-master.2", codes.OK},
+		{"done", &v1.GetLatestJobRequest{Repository: repo, Done: true}, "werft-deploy-master.1", codes.OK},
+		{"full ref", &v1.GetLatestJobRequest{Repository: &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "refs/heads/foo"}}, "werft-build-foo.1", codes.OK},
+		{"job spec", &v1.GetLatestJobRequest{Repository: repo, JobSpec: "build", Done: true}, "werft-build-master.1", codes.OK},
+		{"unknown job spec", &v1.GetLatestJobRequest{Repository: repo, JobSpec: "test"}, "", codes.NotFound},
+		{"other host", &v1.GetLatestJobRequest{Repository: &v1.Repository{Host: "gitlab.com", Owner: "32leaves", Repo: "werft", Ref: "master"}}, "", codes.NotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.GetLatestJob(context.Background(), test.Request)
+			if status.Code(err) != test.Code {
+				t.Fatalf("unexpected error: %v, expected %v", err, test.Code)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Result.Name != test.Expectation {
+				t.Errorf("unexpected latest job %s, expected %s", resp.Result.Name, test.Expectation)
+			}
+		})
+	}
+}
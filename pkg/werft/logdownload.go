@@ -0,0 +1,112 @@
+package werft
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+)
+
+// HandleLogDownload serves the stored log of a job at /logs/<job>.txt, or gzip compressed at /logs/<job>.txt.gz.
+// The slice query parameter restricts the log to the content of a single slice.
+// If the job is still running, the download completes once the job is done.
+func (srv *Service) HandleLogDownload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	fn := strings.TrimPrefix(r.URL.Path, "/logs/")
+	var compress bool
+	if strings.HasSuffix(fn, ".gz") {
+		compress = true
+		fn = strings.TrimSuffix(fn, ".gz")
+	}
+	if !strings.HasSuffix(fn, ".txt") {
+		http.NotFound(w, r)
+		return
+	}
+	name := strings.TrimSuffix(fn, ".txt")
+	if name == "" || strings.Contains(name, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	rd, err := srv.Logs.Read(name)
+	if err == store.ErrNotFound {
+		// the job might be known by its name under the default naming scheme
+		if resolved := srv.resolveJobName(r.Context(), name); resolved != name {
+			name = resolved
+			rd, err = srv.Logs.Read(name)
+		}
+	}
+	if err == store.ErrNotFound {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("cannot read log for download")
+		http.Error(w, "cannot read log", http.StatusInternalServerError)
+		return
+	}
+	defer rd.Close()
+
+	var out io.Writer = w
+	if compress {
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".txt.gz"))
+		gw := gzip.NewWriter(w)
+		defer gw.Close()
+		out = gw
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".txt"))
+	}
+
+	slice := r.URL.Query().Get("slice")
+	if slice == "" {
+		_, err = io.Copy(out, rd)
+	} else {
+		var repo *v1.Repository
+		if job, err := srv.Jobs.Get(r.Context(), name); err == nil {
+			repo = job.Metadata.GetRepository()
+		}
+		err = writeLogSlice(out, srv.logCutter(repo), rd, slice)
+	}
+	if err != nil {
+		// we've likely started writing the response already, hence can't change the status code anymore
+		log.WithError(err).WithField("name", name).Warn("cannot download log")
+	}
+}
+
+// writeLogSlice writes the content of a single log slice to out
+func writeLogSlice(out io.Writer, cutter logcutter.Cutter, in io.Reader, slice string) error {
+	if cutter == nil {
+		cutter = logcutter.DefaultCutter
+	}
+
+	evts, errchan := cutter.Slice(in)
+	for {
+		select {
+		case evt := <-evts:
+			if evt == nil {
+				return nil
+			}
+			if evt.Name != slice || evt.Type != v1.LogSliceType_SLICE_CONTENT {
+				continue
+			}
+			_, err := fmt.Fprintln(out, evt.Payload)
+			if err != nil {
+				return err
+			}
+		case err := <-errchan:
+			return err
+		}
+	}
+}
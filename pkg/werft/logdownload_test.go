@@ -0,0 +1,66 @@
+package werft_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestHandleLogDownload(t *testing.T) {
+	logs := store.NewInMemoryLogStore()
+	w, err := logs.Open("foo.1")
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+	w.Write([]byte("[build|PHASE] building\n[build] hello\n[test] world\n"))
+	w.Close()
+
+	srv := &werft.Service{Logs: logs, Jobs: store.NewInMemoryJobStore()}
+
+	tests := []struct {
+		Path        string
+		Status      int
+		Gzip        bool
+		Expectation string
+	}{
+		{"/logs/foo.1.txt", http.StatusOK, false, "[build|PHASE] building\n[build] hello\n[test] world\n"},
+		{"/logs/foo.1.txt.gz", http.StatusOK, true, "[build|PHASE] building\n[build] hello\n[test] world\n"},
+		{"/logs/foo.1.txt?slice=test", http.StatusOK, false, "world\n"},
+		{"/logs/foo.1.txt.gz?slice=build", http.StatusOK, true, "hello\n"},
+		{"/logs/foo.2.txt", http.StatusNotFound, false, ""},
+		{"/logs/foo.1.log", http.StatusNotFound, false, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.Path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.HandleLogDownload(rec, httptest.NewRequest(http.MethodGet, test.Path, nil))
+
+			if rec.Code != test.Status {
+				t.Fatalf("expected status %d, got %d", test.Status, rec.Code)
+			}
+			if test.Status != http.StatusOK {
+				return
+			}
+
+			body := rec.Body.Bytes()
+			if test.Gzip {
+				gr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("response is not gzip compressed: %v", err)
+				}
+				body, err = ioutil.ReadAll(gr)
+				if err != nil {
+					t.Fatalf("cannot decompress response: %v", err)
+				}
+			}
+			if string(body) != test.Expectation {
+				t.Errorf("expected %q, got %q", test.Expectation, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package werft
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logclass"
+	log "github.com/sirupsen/logrus"
+)
+
+// logSummary returns the summary of a job's log, starting one if we haven't yet. Returns nil if we don't
+// classify logs, e.g. because the service wasn't started.
+func (srv *Service) logSummary(name string) *logclass.Summary {
+	if srv.logClassifier == nil {
+		return nil
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	jl, ok := srv.logListener[name]
+	if !ok {
+		return srv.logClassifier.NewSummary()
+	}
+	if jl.Summary == nil {
+		jl.Summary = srv.logClassifier.NewSummary()
+	}
+	return jl.Summary
+}
+
+// recordLogSummary adds the summary of a job's log to its status. Jobs we no longer listen to keep the summary
+// they had.
+func (srv *Service) recordLogSummary(s, prev *v1.JobStatus) {
+	srv.mu.RLock()
+	jl, ok := srv.logListener[s.Name]
+	srv.mu.RUnlock()
+	if ok && jl.Summary != nil {
+		s.LogSummary = jl.Summary.Proto()
+		return
+	}
+	if prev != nil && s.LogSummary == nil {
+		s.LogSummary = prev.LogSummary
+	}
+}
+
+// storeLogSummary stores the final summary of a job's log once the log is complete
+func (srv *Service) storeLogSummary(ctx context.Context, name string, summary *logclass.Summary) {
+	if summary == nil {
+		return
+	}
+
+	job, err := srv.Jobs.Get(ctx, name)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Debug("cannot get job to store its log summary")
+		return
+	}
+	job.LogSummary = summary.Proto()
+	err = srv.Jobs.Store(ctx, *job)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(name, job.Metadata)).Warn("cannot store log summary")
+		return
+	}
+	<-srv.events.Emit("job", job)
+}
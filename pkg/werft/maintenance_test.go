@@ -0,0 +1,50 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetMaintenanceMode(t *testing.T) {
+	tests := []struct {
+		Name        string
+		AdminTokens []string
+		Token       string
+		Expectation codes.Code
+		Enabled     bool
+	}{
+		{"not enabled", nil, "secret", codes.Unavailable, false},
+		{"no token", []string{"secret"}, "", codes.PermissionDenied, false},
+		{"invalid token", []string{"secret"}, "guess", codes.PermissionDenied, false},
+		{"admin", []string{"secret"}, "secret", codes.OK, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			exec, err := executor.NewFakeExecutor(executor.Config{
+				Namespace:       "werft",
+				JobPrepTimeout:  &executor.Duration{Duration: time.Minute},
+				JobTotalTimeout: &executor.Duration{Duration: time.Hour},
+				Fake:            &executor.FakeConfig{Duration: &executor.Duration{}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := &werft.Service{Executor: exec, Config: werft.Config{AdminTokens: test.AdminTokens}}
+
+			_, err = srv.SetMaintenanceMode(context.Background(), &v1.SetMaintenanceModeRequest{Enabled: true, Reason: "upgrade", Token: test.Token})
+			if code := status.Code(err); code != test.Expectation {
+				t.Fatalf("expected %v, got %v", test.Expectation, err)
+			}
+			if act := exec.Maintenance().Enabled; act != test.Enabled {
+				t.Errorf("expected maintenance mode enabled %v, got %v", test.Enabled, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+// runMatrixJob expands a job into one child job per matrix combination. The matrix job itself does not run,
+// but aggregates the status of its children.
+func (srv *Service) runMatrixJob(ctx context.Context, name string, metadata v1.JobMetadata, cp ContentProvider, jobYAML []byte, jobspec *repoconfig.JobSpec, canReplay bool, waitUntil time.Time, opts ...executor.StartOpt) (*v1.JobStatus, error) {
+	// all children share the content provider, hence it must be able to serve content more than once
+	if gcp, ok := cp.(*GitHubContentProvider); !ok || gcp.Sideload != nil {
+		return nil, xerrors.Errorf("matrix jobs can only run on repository content from GitHub")
+	}
+
+	combinations := jobspec.MatrixCombinations()
+	if len(combinations) == 0 {
+		return nil, xerrors.Errorf("matrix has no combinations")
+	}
+
+	metadata.Created = srv.timestampNow()
+	metadata.Children = make([]string, len(combinations))
+	for i := range combinations {
+		metadata.Children[i] = fmt.Sprintf("%s-%d", name, i+1)
+	}
+
+	logs, err := srv.Logs.Open(name)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot start logging for %s: %w", name, err)
+	}
+	fmt.Fprintf(logs, "[matrix|PHASE] starting %d matrix jobs\n", len(combinations))
+
+	for i, combination := range combinations {
+		child := metadata.Children[i]
+
+		md := metadata
+		md.Parent = name
+		md.Children = nil
+		md.Annotations = matrixAnnotations(metadata.Annotations, combination)
+
+		_, err := srv.RunJob(ctx, child, md, cp, jobYAML, canReplay, waitUntil, opts...)
+		if err != nil {
+			// RunJob has already marked the child as failed, which will fail the matrix job as a whole
+			log.WithError(err).WithFields(jobLogFields(child, &md)).Warn("cannot start matrix job")
+			fmt.Fprintf(logs, "[matrix] cannot start %s: %v\n", child, err)
+			continue
+		}
+		fmt.Fprintf(logs, "[matrix] started %s with %s\n", child, formatCombination(combination))
+	}
+	logs.Close()
+
+	return srv.updateMatrixJob(ctx, name, &metadata)
+}
+
+// matrixAnnotations produces the annotations of a matrix child job. Matrix values take precedence
+// over annotations of the same name. Children never report their status to GitHub themselves,
+// their matrix job does.
+func matrixAnnotations(annotations []*v1.Annotation, combination map[string]string) []*v1.Annotation {
+	res := make([]*v1.Annotation, 0, len(annotations)+len(combination))
+	for _, a := range annotations {
+		if _, overridden := combination[a.Key]; overridden || a.Key == annotationStatusUpdate {
+			continue
+		}
+		res = append(res, a)
+	}
+
+	keys := make([]string, 0, len(combination))
+	for k := range combination {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		res = append(res, &v1.Annotation{Key: k, Value: combination[k]})
+	}
+	return res
+}
+
+func formatCombination(combination map[string]string) string {
+	keys := make([]string, 0, len(combination))
+	for k := range combination {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	segs := make([]string, len(keys))
+	for i, k := range keys {
+		segs[i] = fmt.Sprintf("%s=%s", k, combination[k])
+	}
+	return strings.Join(segs, ", ")
+}
+
+// updateMatrixJob computes the status of a matrix job from its children and stores it. If metadata is nil,
+// the matrix job must already be stored.
+func (srv *Service) updateMatrixJob(ctx context.Context, name string, metadata *v1.JobMetadata) (*v1.JobStatus, error) {
+	srv.matrixMu.Lock()
+	defer srv.matrixMu.Unlock()
+
+	var prev *v1.JobStatus
+	if metadata == nil {
+		var err error
+		prev, err = srv.Jobs.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		md := *prev.Metadata
+		metadata = &md
+	}
+
+	var (
+		s = &v1.JobStatus{
+			Name:       name,
+			Metadata:   metadata,
+			Phase:      v1.JobPhase_PHASE_DONE,
+			Conditions: &v1.JobConditions{Success: true, DidExecute: true},
+		}
+		failed int
+	)
+	for _, child := range metadata.Children {
+		cs, err := srv.Jobs.Get(ctx, child)
+		if err == store.ErrNotFound {
+			s.Phase = v1.JobPhase_PHASE_PREPARING
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch cs.Phase {
+		case v1.JobPhase_PHASE_DONE, v1.JobPhase_PHASE_CLEANUP:
+			if !cs.Conditions.Success {
+				failed++
+			}
+		case v1.JobPhase_PHASE_RUNNING:
+			s.Phase = v1.JobPhase_PHASE_RUNNING
+		default:
+			if s.Phase == v1.JobPhase_PHASE_DONE {
+				s.Phase = v1.JobPhase_PHASE_PREPARING
+			}
+		}
+	}
+	keepNotes(s, prev)
+	if failed > 0 {
+		s.Conditions.Success = false
+		s.Details = fmt.Sprintf("%d of %d matrix jobs failed", failed, len(metadata.Children))
+	}
+	if s.Phase == v1.JobPhase_PHASE_DONE {
+		s.Metadata.Finished = srv.timestampNow()
+	}
+
+	if prev != nil && prev.Phase == s.Phase && prev.Conditions.GetSuccess() == s.Conditions.Success {
+		return prev, nil
+	}
+
+	err := srv.Jobs.Store(ctx, *s)
+	if err != nil {
+		return nil, err
+	}
+	err = srv.updateGitHubStatus(s)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).Warn("cannot update GitHub status")
+	}
+	<-srv.events.Emit("job", s)
+
+	if prev != nil && prev.Phase != v1.JobPhase_PHASE_DONE && s.Phase == v1.JobPhase_PHASE_DONE && s.Conditions.Success && len(metadata.Children) > 0 {
+		// all children share the job spec which declares the downstream jobs
+		child, err := srv.Jobs.Get(ctx, metadata.Children[0])
+		if err == nil {
+			done := *s
+			srv.goBackground(func(ctx context.Context) { srv.startDownstreamJobs(ctx, done, *child) })
+		} else {
+			log.WithError(err).WithFields(jobLogFields(name, metadata)).Warn("cannot start downstream jobs")
+		}
+	}
+
+	return s, nil
+}
+
+// replaceMatrixChild replaces a child of a matrix job, e.g. when that child is retried
+func (srv *Service) replaceMatrixChild(ctx context.Context, name, oldChild, newChild string) error {
+	srv.matrixMu.Lock()
+	s, err := srv.Jobs.Get(ctx, name)
+	if err != nil {
+		srv.matrixMu.Unlock()
+		return err
+	}
+	for i, c := range s.Metadata.Children {
+		if c == oldChild {
+			s.Metadata.Children[i] = newChild
+		}
+	}
+	err = srv.Jobs.Store(ctx, *s)
+	srv.matrixMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	_, err = srv.updateMatrixJob(ctx, name, nil)
+	return err
+}
+
+// matrixRetryName produces the name of the retry of a matrix child job, e.g. foo.3-1-attempt2 for foo.3-1
+func matrixRetryName(name string, attempt int) string {
+	if idx := strings.LastIndex(name, "-attempt"); idx > 0 {
+		name = name[:idx]
+	}
+	return fmt.Sprintf("%s-attempt%d", name, attempt)
+}
@@ -0,0 +1,130 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// mergeGroupEventType is the type of the webhook events GitHub merge queues send. Our GitHub client library
+	// predates merge queues, hence we parse these events ourselves.
+	mergeGroupEventType = "merge_group"
+
+	// mergeQueueRefPrefix is the prefix of the temporary branches GitHub merge queues push merge groups to
+	mergeQueueRefPrefix = "refs/heads/gh-readonly-queue/"
+)
+
+// MergeGroupEvent is sent by GitHub merge queues, e.g. when a merge group needs checks
+type MergeGroupEvent struct {
+	Action     string `json:"action"`
+	MergeGroup struct {
+		HeadSHA string `json:"head_sha"`
+		HeadRef string `json:"head_ref"`
+		BaseSHA string `json:"base_sha"`
+		BaseRef string `json:"base_ref"`
+	} `json:"merge_group"`
+	Repository *github.Repository `json:"repository"`
+	Sender     *github.User       `json:"sender"`
+}
+
+// ParseMergeGroupEvent parses the payload of a merge_group webhook event
+func ParseMergeGroupEvent(payload []byte) (*MergeGroupEvent, error) {
+	var res MergeGroupEvent
+	err := json.Unmarshal(payload, &res)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot parse merge group event: %w", err)
+	}
+	if res.MergeGroup.HeadSHA == "" || res.Repository == nil {
+		return nil, xerrors.Errorf("cannot parse merge group event: merge group or repository is missing")
+	}
+	return &res, nil
+}
+
+// MergeGroupJobMetadata produces the metadata of jobs which check a merge group. The jobs run on the head of the
+// merge group and report their status for it, which is what the merge queue waits for.
+func MergeGroupJobMetadata(event *MergeGroupEvent) *v1.JobMetadata {
+	mg := event.MergeGroup
+	return &v1.JobMetadata{
+		Owner: event.Sender.GetLogin(),
+		Repository: &v1.Repository{
+			Host:     "github.com",
+			Owner:    event.Repository.GetOwner().GetLogin(),
+			Repo:     event.Repository.GetName(),
+			Ref:      mg.HeadRef,
+			Revision: mg.HeadSHA,
+		},
+		Trigger: v1.JobTrigger_TRIGGER_MERGE_GROUP,
+		Annotations: []*v1.Annotation{
+			&v1.Annotation{
+				Key:   annotationStatusUpdate,
+				Value: "true",
+			},
+		},
+		MergeGroup: &v1.MergeGroup{
+			HeadSha: mg.HeadSHA,
+			HeadRef: mg.HeadRef,
+			BaseSha: mg.BaseSHA,
+			BaseRef: mg.BaseRef,
+		},
+	}
+}
+
+func (srv *Service) processMergeGroupEvent(ctx context.Context, logger *log.Entry, event *MergeGroupEvent) error {
+	if event.Action != "checks_requested" {
+		// e.g. destroyed once the group was merged or a check failed
+		return nil
+	}
+
+	metadata := MergeGroupJobMetadata(event)
+	if !srv.webhookAllowed(metadata.Repository) {
+		logger.WithField("repo", fmt.Sprintf("%s/%s", metadata.Repository.Owner, metadata.Repository.Repo)).Info("ignoring webhook event of a repository which is not allowed")
+		return nil
+	}
+
+	cp := &GitHubContentProvider{
+		Client:   srv.GitHub.Client,
+		Owner:    metadata.Repository.Owner,
+		Repo:     metadata.Repository.Repo,
+		Revision: metadata.Repository.Revision,
+	}
+	logger = logger.WithFields(jobLogFields(strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(metadata.Repository.Ref, "refs/heads/"), "/", "-")), metadata))
+	repoCfg, _, err := srv.getRepoCfg(ctx, metadata.Repository, cp)
+	if err != nil {
+		return xerrors.Errorf("cannot start job: %w", err)
+	}
+
+	// repositories have to ask for merge group jobs explicitly, just like for pull request jobs
+	if !repoCfg.MergeGroups {
+		return nil
+	}
+
+	var changed []string
+	if repoCfg.NeedsChangedPaths() {
+		changed, err = srv.mergeGroupChangedPaths(ctx, metadata)
+		if err != nil {
+			logger.WithError(err).Warn("cannot list files changed by merge group - starting jobs regardless")
+			changed = nil
+		}
+	}
+	return srv.startGitHubJobs(ctx, logger, repoCfg, metadata, changed)
+}
+
+// mergeGroupChangedPaths lists the files a merge group changes compared to its base
+func (srv *Service) mergeGroupChangedPaths(ctx context.Context, md *v1.JobMetadata) ([]string, error) {
+	cmp, _, err := srv.GitHub.Client.Repositories.CompareCommits(ctx, md.Repository.Owner, md.Repository.Repo, md.MergeGroup.BaseSha, md.MergeGroup.HeadSha)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]string, 0, len(cmp.Files))
+	for _, f := range cmp.Files {
+		res = append(res, f.GetFilename())
+	}
+	return res, nil
+}
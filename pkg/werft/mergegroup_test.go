@@ -0,0 +1,80 @@
+package werft_test
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestMergeGroupJobMetadata(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Payload     string
+		Expectation *v1.JobMetadata
+		Error       bool
+	}{
+		{
+			Name: "checks requested",
+			Payload: `{
+				"action": "checks_requested",
+				"merge_group": {
+					"head_sha": "abc",
+					"head_ref": "refs/heads/gh-readonly-queue/main/pr-42-def",
+					"base_sha": "def",
+					"base_ref": "refs/heads/main"
+				},
+				"repository": {"name": "werft", "owner": {"login": "32leaves"}},
+				"sender": {"login": "dev"}
+			}`,
+			Expectation: &v1.JobMetadata{
+				Owner: "dev",
+				Repository: &v1.Repository{
+					Host:     "github.com",
+					Owner:    "32leaves",
+					Repo:     "werft",
+					Ref:      "refs/heads/gh-readonly-queue/main/pr-42-def",
+					Revision: "abc",
+				},
+				Trigger:     v1.JobTrigger_TRIGGER_MERGE_GROUP,
+				Annotations: []*v1.Annotation{{Key: "updateGitHubStatus", Value: "true"}},
+				MergeGroup: &v1.MergeGroup{
+					HeadSha: "abc",
+					HeadRef: "refs/heads/gh-readonly-queue/main/pr-42-def",
+					BaseSha: "def",
+					BaseRef: "refs/heads/main",
+				},
+			},
+		},
+		{
+			Name:    "no merge group",
+			Payload: `{"action": "checks_requested", "repository": {"name": "werft", "owner": {"login": "32leaves"}}}`,
+			Error:   true,
+		},
+		{
+			Name:    "invalid JSON",
+			Payload: `{`,
+			Error:   true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			evt, err := werft.ParseMergeGroupEvent([]byte(test.Payload))
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			md := werft.MergeGroupJobMetadata(evt)
+			if !reflect.DeepEqual(md, test.Expectation) {
+				t.Errorf("unexpected metadata: %v", md)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package werft
+
+import (
+	"net/http"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/32leaves/werft/pkg/logforward"
+	"github.com/32leaves/werft/pkg/store"
+	"golang.org/x/xerrors"
+	"k8s.io/client-go/dynamic"
+)
+
+// Option configures a service created using New
+type Option func(*Service) error
+
+// WithConfig sets the config of the service
+func WithConfig(cfg Config) Option {
+	return func(srv *Service) error {
+		srv.Config = cfg
+		return nil
+	}
+}
+
+// WithExecutor sets the backend which runs the jobs. The service starts the backend when it starts.
+func WithExecutor(exec executor.Backend) Option {
+	return func(srv *Service) error {
+		if exec == nil {
+			return xerrors.Errorf("executor must not be nil")
+		}
+		srv.Executor = exec
+		return nil
+	}
+}
+
+// WithJobStore sets the store for jobs. Without this option jobs are kept in memory.
+func WithJobStore(jobs store.Jobs) Option {
+	return func(srv *Service) error {
+		srv.Jobs = jobs
+		return nil
+	}
+}
+
+// WithLogStore sets the store for job logs. Without this option logs are kept in memory.
+func WithLogStore(logs store.Logs) Option {
+	return func(srv *Service) error {
+		srv.Logs = logs
+		return nil
+	}
+}
+
+// WithNumberGroup sets the store for job numbers. Without this option numbers are kept in memory.
+func WithNumberGroup(groups store.NumberGroup) Option {
+	return func(srv *Service) error {
+		srv.Groups = groups
+		return nil
+	}
+}
+
+// WithArchive makes the service move old jobs to an archive
+func WithArchive(archive store.JobArchive) Option {
+	return func(srv *Service) error {
+		srv.Archive = archive
+		return nil
+	}
+}
+
+// WithDeadLetters makes the service keep webhook events it could not handle
+func WithDeadLetters(dl store.DeadLetters) Option {
+	return func(srv *Service) error {
+		srv.DeadLetters = dl
+		return nil
+	}
+}
+
+// WithImages makes the service record the images jobs build
+func WithImages(images store.Images) Option {
+	return func(srv *Service) error {
+		srv.Images = images
+		return nil
+	}
+}
+
+// WithGitHub sets up the access to GitHub
+func WithGitHub(gh GitHubSetup) Option {
+	return func(srv *Service) error {
+		srv.GitHub = gh
+		return nil
+	}
+}
+
+// WithCutter sets the cutter which splits job logs into slices. Without this option the service uses the default cutter.
+func WithCutter(cutter logcutter.Cutter) Option {
+	return func(srv *Service) error {
+		srv.Cutter = cutter
+		return nil
+	}
+}
+
+// WithLogForwarder makes the service forward job logs
+func WithLogForwarder(f logforward.Forwarder) Option {
+	return func(srv *Service) error {
+		srv.LogForwarder = f
+		return nil
+	}
+}
+
+// WithJobResources makes the service mirror jobs as WerftJob custom resources
+func WithJobResources(res dynamic.ResourceInterface) Option {
+	return func(srv *Service) error {
+		srv.JobResources = res
+		return nil
+	}
+}
+
+// WithVersion sets the version the service reports
+func WithVersion(version *v1.GetVersionResponse) Option {
+	return func(srv *Service) error {
+		srv.Version = version
+		return nil
+	}
+}
+
+// WithClock sets the clock of the service. Without this option the service uses the system clock.
+func WithClock(clock Clock) Option {
+	return func(srv *Service) error {
+		srv.Clock = clock
+		return nil
+	}
+}
+
+// WithTransport sets the transport the service sends outbound HTTP requests with, e.g. one which uses a proxy
+func WithTransport(transport http.RoundTripper) Option {
+	return func(srv *Service) error {
+		srv.Transport = transport
+		return nil
+	}
+}
+
+// WithIDs sets the generator of the IDs which name jobs. Without this option the service uses monikers.
+func WithIDs(ids IDGenerator) Option {
+	return func(srv *Service) error {
+		srv.IDs = ids
+		return nil
+	}
+}
+
+// New creates a service which embeds werft in a program. The service needs an executor, everything else is optional.
+// Call Start to run the service and Stop once you're done with it.
+func New(opts ...Option) (*Service, error) {
+	srv := &Service{}
+	for _, o := range opts {
+		err := o(srv)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if srv.Executor == nil {
+		return nil, xerrors.Errorf("executor is required")
+	}
+	if srv.Jobs == nil {
+		srv.Jobs = store.NewInMemoryJobStore()
+	}
+	if srv.Logs == nil {
+		srv.Logs = store.NewInMemoryLogStore()
+	}
+	if srv.Groups == nil {
+		srv.Groups = store.NewInMemoryNumberGroup()
+	}
+	if srv.Cutter == nil {
+		srv.Cutter = logcutter.DefaultCutter
+	}
+	return srv, nil
+}
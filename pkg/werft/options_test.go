@@ -0,0 +1,108 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"golang.org/x/xerrors"
+)
+
+func TestServiceLifecycle(t *testing.T) {
+	_, err := werft.New()
+	if err == nil {
+		t.Fatal("New without an executor should fail")
+	}
+
+	exec, err := executor.NewFakeExecutor(executor.Config{
+		Namespace:       "werft",
+		JobPrepTimeout:  &executor.Duration{Duration: time.Minute},
+		JobTotalTimeout: &executor.Duration{Duration: time.Hour},
+		Fake:            &executor.FakeConfig{Duration: &executor.Duration{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := werft.New(werft.WithExecutor(exec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.Jobs == nil || srv.Logs == nil || srv.Groups == nil || srv.Cutter == nil {
+		t.Fatal("New should default to in-memory stores and the default cutter")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = srv.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = srv.Start(ctx)
+	if err == nil {
+		t.Error("starting a service twice should fail")
+	}
+	err = srv.Stop(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = srv.Stop(ctx)
+	if err == nil {
+		t.Error("stopping a stopped service should fail")
+	}
+	err = srv.Start(ctx)
+	if err == nil {
+		t.Error("starting a stopped service should fail")
+	}
+}
+
+type closeCountingExecutor struct {
+	executor.Backend
+	closed int
+}
+
+func (e *closeCountingExecutor) Close() {
+	e.closed++
+	e.Backend.Close()
+}
+
+type failingFindJobs struct {
+	store.Jobs
+}
+
+func (failingFindJobs) Find(ctx context.Context, filter []*v1.FilterExpression, order []*v1.OrderExpression, start, limit int) ([]v1.JobStatus, int, error) {
+	return nil, 0, xerrors.Errorf("store is down")
+}
+
+func TestServiceStartFailureClosesExecutor(t *testing.T) {
+	fake, err := executor.NewFakeExecutor(executor.Config{
+		Namespace:       "werft",
+		JobPrepTimeout:  &executor.Duration{Duration: time.Minute},
+		JobTotalTimeout: &executor.Duration{Duration: time.Hour},
+		Fake:            &executor.FakeConfig{Duration: &executor.Duration{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exec := &closeCountingExecutor{Backend: fake}
+	srv, err := werft.New(
+		werft.WithExecutor(exec),
+		werft.WithJobStore(failingFindJobs{store.NewInMemoryJobStore()}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = srv.Start(ctx)
+	if err == nil {
+		t.Fatal("Start should fail if waiting jobs cannot be restored")
+	}
+	if exec.closed != 1 {
+		t.Errorf("expected the executor to be closed once, was closed %d times", exec.closed)
+	}
+}
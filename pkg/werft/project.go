@@ -0,0 +1,167 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/store"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// labelProject assigns jobs to a project
+	labelProject = "project"
+
+	// projectHealthJobs is the number of most recent jobs of a project we look at to determine its health
+	projectHealthJobs = 1000
+)
+
+// ProjectConfig groups related repositories, e.g. the microservices a team owns. Jobs of these repositories
+// are labelled project=<name>, unless their job file or annotations name a project already.
+type ProjectConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+
+	// Repos identifies the repositories of the project like the repositories section, e.g. github.com/32leaves/*
+	Repos []string `yaml:"repos"`
+}
+
+// validateProjects checks that projects have unique names and valid repository patterns
+func validateProjects(projects []ProjectConfig) error {
+	names := make(map[string]struct{}, len(projects))
+	for _, p := range projects {
+		if p.Name == "" {
+			return xerrors.Errorf("projects must have a name")
+		}
+		if _, exists := names[p.Name]; exists {
+			return xerrors.Errorf("project %s is configured more than once", p.Name)
+		}
+		names[p.Name] = struct{}{}
+
+		for _, r := range p.Repos {
+			if _, err := path.Match(r, ""); err != nil {
+				return xerrors.Errorf("invalid repository pattern %s of project %s: %w", r, p.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// projectOf returns the name of the first project the repository belongs to, or an empty string if there is none
+func (srv *Service) projectOf(repo *v1.Repository) string {
+	for _, p := range srv.config().Projects {
+		for _, r := range p.Repos {
+			if repoMatches(r, repo) {
+				return p.Name
+			}
+		}
+	}
+	return ""
+}
+
+// applyProjectLabel assigns a job to the project of its repository, unless the job names a project itself
+func (srv *Service) applyProjectLabel(md *v1.JobMetadata) {
+	if _, ok := md.Labels[labelProject]; ok {
+		return
+	}
+	project := srv.projectOf(md.Repository)
+	if project == "" {
+		return
+	}
+	if md.Labels == nil {
+		md.Labels = make(map[string]string)
+	}
+	md.Labels[labelProject] = project
+}
+
+// projectFilter selects the jobs of a project
+func projectFilter(name string) *v1.FilterExpression {
+	return &v1.FilterExpression{Terms: []*v1.FilterTerm{
+		{Field: filterexpr.LabelFieldPrefix + labelProject, Value: name, Operation: v1.FilterOp_OP_EQUALS},
+	}}
+}
+
+// ListProjects lists the configured projects
+func (srv *Service) ListProjects(ctx context.Context, req *v1.ListProjectsRequest) (*v1.ListProjectsResponse, error) {
+	projects := srv.config().Projects
+	res := make([]*v1.Project, len(projects))
+	for i, p := range projects {
+		res[i] = &v1.Project{
+			Name:        p.Name,
+			Description: p.Description,
+			Repos:       p.Repos,
+		}
+	}
+	return &v1.ListProjectsResponse{Projects: res}, nil
+}
+
+// GetProjectHealth returns the latest job of each repository of a project
+func (srv *Service) GetProjectHealth(ctx context.Context, req *v1.GetProjectHealthRequest) (*v1.GetProjectHealthResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	filter := []*v1.FilterExpression{projectFilter(req.Name)}
+	if req.Ref != "" {
+		filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{
+			{Field: "repo.ref", Value: req.Ref, Operation: v1.FilterOp_OP_EQUALS},
+		}})
+	}
+	order := []*v1.OrderExpression{{Field: "created", Ascending: false}}
+	jobs, _, err := srv.Jobs.Find(store.WithStaleReads(ctx), filter, order, 0, projectHealthJobs)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var (
+		idx = make(map[string]*v1.RepositoryHealth)
+		res []*v1.RepositoryHealth
+	)
+	for i := range jobs {
+		job := &jobs[i]
+		repo := job.GetMetadata().GetRepository()
+		if repo == nil || job.Metadata.Parent != "" {
+			// the children of matrix jobs are represented by their parent
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%s/%s", repo.Host, repo.Owner, repo.Repo)
+		h, ok := idx[key]
+		if !ok {
+			h = &v1.RepositoryHealth{
+				Repository: &v1.Repository{Host: repo.Host, Owner: repo.Owner, Repo: repo.Repo},
+				Latest:     summarizeJob(job),
+			}
+			idx[key] = h
+			res = append(res, h)
+		}
+		if h.LatestFinished == nil && job.Phase == v1.JobPhase_PHASE_DONE && !job.GetConditions().GetSkipped() {
+			h.LatestFinished = summarizeJob(job)
+		}
+	}
+	if len(res) == 0 && !srv.isProject(req.Name) {
+		return nil, status.Errorf(codes.NotFound, "project %s is not configured and has no jobs", req.Name)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i].Repository, res[j].Repository
+		return fmt.Sprintf("%s/%s/%s", a.Host, a.Owner, a.Repo) < fmt.Sprintf("%s/%s/%s", b.Host, b.Owner, b.Repo)
+	})
+	return &v1.GetProjectHealthResponse{Repositories: res}, nil
+}
+
+// isProject returns true if a project of that name is configured
+func (srv *Service) isProject(name string) bool {
+	for _, p := range srv.config().Projects {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetProjectHealth(t *testing.T) {
+	var created int64
+	run := func(name, repo, ref, project string, phase v1.JobPhase, success bool) v1.JobStatus {
+		created++
+		return v1.JobStatus{
+			Name:  name,
+			Phase: phase,
+			Metadata: &v1.JobMetadata{
+				Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: repo, Ref: ref},
+				Created:    &timestamp.Timestamp{Seconds: created},
+				Labels:     map[string]string{"project": project},
+			},
+			Conditions: &v1.JobConditions{Success: success, DidExecute: true},
+		}
+	}
+
+	jobs := store.NewInMemoryJobStore()
+	for _, j := range []v1.JobStatus{
+		run("api-build-master.1", "api", "refs/heads/master", "shop", v1.JobPhase_PHASE_DONE, false),
+		run("api-build-master.2", "api", "refs/heads/master", "shop", v1.JobPhase_PHASE_DONE, true),
+		run("api-build-foo.1", "api", "refs/heads/foo", "shop", v1.JobPhase_PHASE_DONE, false),
+		run("web-build-master.1", "web", "refs/heads/master", "shop", v1.JobPhase_PHASE_DONE, false),
+		run("web-build-master.2", "web", "refs/heads/master", "shop", v1.JobPhase_PHASE_RUNNING, false),
+		run("werft-build-master.1", "werft", "refs/heads/master", "ci", v1.JobPhase_PHASE_DONE, true),
+	} {
+		err := jobs.Store(context.Background(), j)
+		if err != nil {
+			t.Fatalf("cannot store job: %v", err)
+		}
+	}
+	srv := &werft.Service{Jobs: jobs, Config: werft.Config{Projects: []werft.ProjectConfig{{Name: "empty", Repos: []string{"32leaves/nothing-*"}}}}}
+
+	type health struct {
+		Repo, Latest, LatestFinished string
+	}
+	tests := []struct {
+		Name        string
+		Req         *v1.GetProjectHealthRequest
+		Expectation []health
+		Code        codes.Code
+	}{
+		{"all refs", &v1.GetProjectHealthRequest{Name: "shop"}, []health{
+			{"api", "api-build-foo.1", "api-build-foo.1"},
+			{"web", "web-build-master.2", "web-build-master.1"},
+		}, codes.OK},
+		{"ref", &v1.GetProjectHealthRequest{Name: "shop", Ref: "refs/heads/master"}, []health{
+			{"api", "api-build-master.2", "api-build-master.2"},
+			{"web", "web-build-master.2", "web-build-master.1"},
+		}, codes.OK},
+		{"configured project without jobs", &v1.GetProjectHealthRequest{Name: "empty"}, nil, codes.OK},
+		{"unknown project", &v1.GetProjectHealthRequest{Name: "unknown"}, nil, codes.NotFound},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.GetProjectHealth(context.Background(), test.Req)
+			if status.Code(err) != test.Code {
+				t.Fatalf("expected %v, got %v", test.Code, err)
+			}
+			if err != nil {
+				return
+			}
+
+			var act []health
+			for _, h := range resp.Repositories {
+				var finished string
+				if h.LatestFinished != nil {
+					finished = h.LatestFinished.Name
+				}
+				act = append(act, health{h.Repository.Repo, h.Latest.Name, finished})
+			}
+			if len(act) != len(test.Expectation) {
+				t.Fatalf("expected %v, got %v", test.Expectation, act)
+			}
+			for i := range act {
+				if act[i] != test.Expectation[i] {
+					t.Errorf("expected %v, got %v", test.Expectation, act)
+				}
+			}
+		})
+	}
+}
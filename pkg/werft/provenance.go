@@ -0,0 +1,98 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/provenance"
+	"github.com/32leaves/werft/pkg/store"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ProvenanceConfig configures how werft signs the provenance of finished jobs
+type ProvenanceConfig struct {
+	// SigningKey is the path to a PEM encoded ed25519 private key (PKCS8), e.g. mounted from a secret
+	SigningKey string `yaml:"signingKey"`
+
+	// KeyID is added to signatures, so that verifiers can tell which public key to use
+	KeyID string `yaml:"keyID,omitempty"`
+
+	// BuilderID identifies this werft installation in provenance. Defaults to the base URL.
+	BuilderID string `yaml:"builderID,omitempty"`
+}
+
+// setupProvenance loads the key which signs provenance
+func (srv *Service) setupProvenance() error {
+	cfg := srv.Config.Provenance
+	if cfg == nil || cfg.SigningKey == "" {
+		return nil
+	}
+
+	fc, err := ioutil.ReadFile(cfg.SigningKey)
+	if err != nil {
+		return xerrors.Errorf("cannot read provenance signing key: %w", err)
+	}
+	srv.provenanceKey, err = provenance.ParsePrivateKey(fc)
+	if err != nil {
+		return xerrors.Errorf("invalid provenance signing key: %w", err)
+	}
+	return nil
+}
+
+// recordProvenance signs and stores the provenance of a job which just finished. Only jobs which succeeded and whose
+// source werft verified get one: werft cannot vouch for what local jobs, custom job specs or sideloaded content built.
+func (srv *Service) recordProvenance(s *v1.JobStatus) {
+	if srv.provenanceKey == nil {
+		return
+	}
+	if !s.Conditions.GetSuccess() || !s.Metadata.GetSourceVerified() {
+		return
+	}
+
+	err := srv.storeProvenance(s)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).Warn("cannot record job provenance")
+	}
+}
+
+func (srv *Service) storeProvenance(s *v1.JobStatus) error {
+	builderID := srv.Config.Provenance.BuilderID
+	if builderID == "" {
+		builderID = srv.Config.BaseURL
+	}
+
+	stmt, err := provenance.FromJob(s, builderID)
+	if err != nil {
+		return err
+	}
+	env, err := provenance.Sign(stmt, srv.provenanceKey, srv.Config.Provenance.KeyID)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return srv.Jobs.StoreProvenance(s.Name, data)
+}
+
+// GetJobProvenance returns the signed provenance of a finished job
+func (srv *Service) GetJobProvenance(ctx context.Context, req *v1.GetJobProvenanceRequest) (*v1.GetJobProvenanceResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	data, err := srv.Jobs.GetProvenance(req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "job %s has no provenance", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.GetJobProvenanceResponse{Envelope: data}, nil
+}
@@ -0,0 +1,104 @@
+package werft
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/google/go-github/github"
+)
+
+func TestRecordProvenance(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		Name     string
+		Verified bool
+		Success  bool
+		Signed   bool
+	}{
+		{"verified and successful", true, true, true},
+		{"verified but failed", true, false, false},
+		{"unverified, e.g. local", false, true, false},
+		{"unverified and failed", false, false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := &Service{
+				Jobs:          store.NewInMemoryJobStore(),
+				Config:        Config{Provenance: &ProvenanceConfig{BuilderID: "https://werft.example.com"}},
+				provenanceKey: key,
+			}
+			srv.recordProvenance(&v1.JobStatus{
+				Name: "shop-build-main.1",
+				Metadata: &v1.JobMetadata{
+					Repository:     &v1.Repository{Host: "github.com", Owner: "acme", Repo: "shop", Ref: "refs/heads/main", Revision: "abc"},
+					Trigger:        v1.JobTrigger_TRIGGER_PUSH,
+					SourceVerified: test.Verified,
+				},
+				Conditions: &v1.JobConditions{Success: test.Success},
+			})
+
+			_, err := srv.Jobs.GetProvenance("shop-build-main.1")
+			if test.Signed && err != nil {
+				t.Errorf("expected the job to have provenance: %v", err)
+			}
+			if !test.Signed && err != store.ErrNotFound {
+				t.Errorf("expected the job to have no provenance, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRefPointsTo(t *testing.T) {
+	mux := http.NewServeMux()
+	ghsrv := httptest.NewServer(mux)
+	defer ghsrv.Close()
+	mux.HandleFunc("/repos/acme/shop/commits/refs/heads/main", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "abc")
+	})
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(ghsrv.URL + "/")
+
+	tests := []struct {
+		Name     string
+		Repo     *v1.Repository
+		Verified bool
+	}{
+		{"ref points to revision", &v1.Repository{Owner: "acme", Repo: "shop", Ref: "refs/heads/main", Revision: "abc"}, true},
+		{"ref moved on", &v1.Repository{Owner: "acme", Repo: "shop", Ref: "refs/heads/main", Revision: "def"}, false},
+		{"unknown ref", &v1.Repository{Owner: "acme", Repo: "shop", Ref: "refs/heads/other", Revision: "abc"}, false},
+		{"no ref", &v1.Repository{Owner: "acme", Repo: "shop", Revision: "abc"}, false},
+		{"no repository", nil, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			if act := refPointsTo(context.Background(), client, test.Repo); act != test.Verified {
+				t.Errorf("expected %v, got %v", test.Verified, act)
+			}
+		})
+	}
+}
+
+func TestSourceVerified(t *testing.T) {
+	ctx := context.Background()
+	if sourceVerified(ctx) {
+		t.Error("contexts should not be verified by default")
+	}
+	if !sourceVerified(withSourceVerified(ctx, true)) {
+		t.Error("expected the context to be verified")
+	}
+	if sourceVerified(withSourceVerified(withSourceVerified(ctx, true), false)) {
+		t.Error("a custom job spec should revoke the verification of a webhook")
+	}
+}
@@ -0,0 +1,204 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/logclass"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// prSummaryMarker identifies the summary comment werft maintains on pull requests
+	prSummaryMarker = "<!-- werft:summary -->"
+
+	// prSummaryMaxJobs is the maximum number of jobs we list in a pull request summary
+	prSummaryMaxJobs = 50
+)
+
+// prSummary is the last summary comment we've posted to a pull request
+type prSummary struct {
+	CommentID int64
+	Body      string
+}
+
+// updatePullRequestSummaries posts or updates a comment listing all jobs of the commit on all
+// open pull requests whose head is that commit.
+func (srv *Service) updatePullRequestSummaries(ctx context.Context, repo v1.Repository) {
+	// we serialize all summary updates so that we never post two summary comments on the same pull request
+	srv.summaryMu.Lock()
+	defer srv.summaryMu.Unlock()
+
+	err := srv.doUpdatePullRequestSummaries(ctx, repo)
+	if err != nil {
+		log.WithError(err).WithField("repo", fmt.Sprintf("%s/%s", repo.Owner, repo.Repo)).WithField("revision", repo.Revision).Warn("cannot update pull request summary")
+	}
+}
+
+func (srv *Service) doUpdatePullRequestSummaries(ctx context.Context, repo v1.Repository) error {
+	if !strings.HasPrefix(repo.Ref, "refs/heads/") {
+		return nil
+	}
+
+	prs, _, err := srv.GitHub.Client.PullRequests.List(ctx, repo.Owner, repo.Repo, &github.PullRequestListOptions{
+		State: "open",
+		Head:  fmt.Sprintf("%s:%s", repo.Owner, strings.TrimPrefix(repo.Ref, "refs/heads/")),
+	})
+	if err != nil {
+		return xerrors.Errorf("cannot list pull requests: %w", err)
+	}
+
+	var relevant []*github.PullRequest
+	for _, pr := range prs {
+		if pr.GetHead().GetSHA() == repo.Revision {
+			relevant = append(relevant, pr)
+		}
+	}
+	if len(relevant) == 0 {
+		return nil
+	}
+
+	term := func(field, value string) *v1.FilterExpression {
+		return &v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: field, Value: value, Operation: v1.FilterOp_OP_EQUALS}}}
+	}
+	jobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{
+		term("repo.owner", repo.Owner),
+		term("repo.repo", repo.Repo),
+		term("repo.ref", repo.Ref),
+	}, []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: true}}, 0, 0)
+	if err != nil {
+		return xerrors.Errorf("cannot find jobs: %w", err)
+	}
+	var (
+		commitJobs []v1.JobStatus
+		done       = true
+	)
+	for _, j := range jobs {
+		// matrix children are summarized by their matrix job
+		if j.Metadata.Repository.Revision != repo.Revision || j.Metadata.Parent != "" {
+			continue
+		}
+		commitJobs = append(commitJobs, j)
+		done = done && j.Phase == v1.JobPhase_PHASE_DONE
+	}
+	if len(commitJobs) > prSummaryMaxJobs {
+		commitJobs = commitJobs[len(commitJobs)-prSummaryMaxJobs:]
+	}
+	body := renderPullRequestSummary(srv.Config.BaseURL, repo.Revision, commitJobs, srv.now())
+
+	for _, pr := range relevant {
+		err = srv.postPullRequestSummary(ctx, repo, pr.GetNumber(), body, done)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postPullRequestSummary creates the summary comment on a pull request or updates the existing one. We remember the
+// comment until all jobs it lists are done, after which we forget it so that summaries don't pile up in memory.
+// Should another job start for the pull request, we find the comment again.
+func (srv *Service) postPullRequestSummary(ctx context.Context, repo v1.Repository, number int, body string, done bool) error {
+	if srv.summaries == nil {
+		srv.summaries = make(map[string]prSummary)
+	}
+	key := fmt.Sprintf("%s/%s#%d", repo.Owner, repo.Repo, number)
+	last, known := srv.summaries[key]
+	if known && last.Body == body {
+		if done {
+			delete(srv.summaries, key)
+		}
+		return nil
+	}
+
+	if !known {
+		// we might have posted a summary before werft restarted
+		opts := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+		for {
+			comments, resp, err := srv.GitHub.Client.Issues.ListComments(ctx, repo.Owner, repo.Repo, number, opts)
+			if err != nil {
+				return xerrors.Errorf("cannot list comments of %s: %w", key, err)
+			}
+			for _, c := range comments {
+				if strings.HasPrefix(c.GetBody(), prSummaryMarker) {
+					last.CommentID = c.GetID()
+				}
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+	}
+
+	comment := &github.IssueComment{Body: &body}
+	if last.CommentID != 0 {
+		_, _, err := srv.GitHub.Client.Issues.EditComment(ctx, repo.Owner, repo.Repo, last.CommentID, comment)
+		if err != nil {
+			return xerrors.Errorf("cannot update summary of %s: %w", key, err)
+		}
+	} else {
+		c, _, err := srv.GitHub.Client.Issues.CreateComment(ctx, repo.Owner, repo.Repo, number, comment)
+		if err != nil {
+			return xerrors.Errorf("cannot post summary on %s: %w", key, err)
+		}
+		last.CommentID = c.GetID()
+	}
+
+	if done {
+		delete(srv.summaries, key)
+		return nil
+	}
+	last.Body = body
+	srv.summaries[key] = last
+	return nil
+}
+
+// renderPullRequestSummary produces the markdown of a pull request summary comment
+func renderPullRequestSummary(baseURL, revision string, jobs []v1.JobStatus, now time.Time) string {
+	short := revision
+	if len(short) > 7 {
+		short = short[:7]
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, prSummaryMarker)
+	fmt.Fprintf(&b, "**Werft jobs for %s**\n\n", short)
+	fmt.Fprintln(&b, "| Job | Phase | Result | Duration |")
+	fmt.Fprintln(&b, "| --- | ----- | ------ | -------- |")
+	for _, j := range jobs {
+		phase := strings.TrimPrefix(strings.ToLower(j.Phase.String()), "phase_")
+
+		var result string
+		if j.Phase == v1.JobPhase_PHASE_DONE || j.Phase == v1.JobPhase_PHASE_CLEANUP {
+			result = ":white_check_mark: success"
+			if !j.Conditions.GetSuccess() {
+				result = ":x: failed"
+				if j.Details != "" {
+					result += " - " + strings.ReplaceAll(j.Details, "|", "\\|")
+				}
+				if l := logclass.FirstErrorLine(j.LogSummary); l != "" {
+					result += "<br>`" + strings.ReplaceAll(strings.ReplaceAll(l, "`", "'"), "|", "\\|") + "`"
+				}
+			}
+		}
+
+		var duration string
+		if created, err := ptypes.Timestamp(j.Metadata.Created); err == nil {
+			end := now
+			if finished, err := ptypes.Timestamp(j.Metadata.Finished); err == nil && j.Metadata.Finished != nil {
+				end = finished
+			}
+			duration = end.Sub(created).Round(time.Second).String()
+		}
+
+		fmt.Fprintf(&b, "| [%s](%s/job/%s) | %s | %s | %s |\n", j.Name, baseURL, j.Name, phase, result, duration)
+	}
+	return b.String()
+}
@@ -0,0 +1,105 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/google/go-github/github"
+)
+
+func TestUpdatePullRequestSummariesForgetsDoneCommits(t *testing.T) {
+	const rev = "1111111111111111111111111111111111111111"
+	var (
+		mu       sync.Mutex
+		comment  string
+		listed   int
+		created  int
+		edited   int
+		mux      = http.NewServeMux()
+		ghsrv    = httptest.NewServer(mux)
+		repo     = v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/feature", Revision: rev}
+		jobStore = store.NewInMemoryJobStore()
+	)
+	defer ghsrv.Close()
+	mux.HandleFunc("/repos/32leaves/werft/pulls", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"number":1,"head":{"sha":%q}}]`, rev)
+	})
+	mux.HandleFunc("/repos/32leaves/werft/issues/1/comments", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == http.MethodPost {
+			var c github.IssueComment
+			json.NewDecoder(r.Body).Decode(&c)
+			comment = c.GetBody()
+			created++
+			fmt.Fprint(w, `{"id":10}`)
+			return
+		}
+		listed++
+		if comment == "" {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		json.NewEncoder(w).Encode([]*github.IssueComment{{ID: github.Int64(9), Body: github.String("lgtm")}, {ID: github.Int64(10), Body: &comment}})
+	})
+	mux.HandleFunc("/repos/32leaves/werft/issues/comments/10", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var c github.IssueComment
+		json.NewDecoder(r.Body).Decode(&c)
+		comment = c.GetBody()
+		edited++
+		fmt.Fprint(w, `{"id":10}`)
+	})
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(ghsrv.URL + "/")
+	srv := &Service{Jobs: jobStore, GitHub: GitHubSetup{Client: client}}
+	storeJob := func(name string, phase v1.JobPhase) {
+		r := repo
+		err := jobStore.Store(context.Background(), v1.JobStatus{Name: name, Phase: phase, Metadata: &v1.JobMetadata{Repository: &r}, Conditions: &v1.JobConditions{Success: true}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		Name    string
+		Step    func()
+		Listed  int
+		Created int
+		Edited  int
+		Known   bool
+	}{
+		{Name: "first job", Step: func() { storeJob("build.1", v1.JobPhase_PHASE_RUNNING) }, Listed: 1, Created: 1, Known: true},
+		{Name: "unchanged", Listed: 1, Created: 1, Known: true},
+		{Name: "second job", Step: func() { storeJob("lint.1", v1.JobPhase_PHASE_RUNNING) }, Listed: 1, Created: 1, Edited: 1, Known: true},
+		{Name: "one job done", Step: func() { storeJob("lint.1", v1.JobPhase_PHASE_DONE) }, Listed: 1, Created: 1, Edited: 2, Known: true},
+		{Name: "all jobs done", Step: func() { storeJob("build.1", v1.JobPhase_PHASE_DONE) }, Listed: 1, Created: 1, Edited: 3},
+		{Name: "restarted job finds comment", Step: func() { storeJob("build.1", v1.JobPhase_PHASE_RUNNING) }, Listed: 2, Created: 1, Edited: 4, Known: true},
+	}
+	for _, test := range tests {
+		// the steps build on one another, hence they don't run as subtests
+		if test.Step != nil {
+			test.Step()
+		}
+		srv.updatePullRequestSummaries(context.Background(), repo)
+
+		mu.Lock()
+		if listed != test.Listed || created != test.Created || edited != test.Edited {
+			t.Errorf("%s: expected %d/%d/%d listed/created/edited comments, got %d/%d/%d", test.Name, test.Listed, test.Created, test.Edited, listed, created, edited)
+		}
+		mu.Unlock()
+		if _, known := srv.summaries["32leaves/werft#1"]; known != test.Known {
+			t.Errorf("%s: expected the summary to be remembered: %v, got %v", test.Name, test.Known, known)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultQuotaPeriod is the period job minutes are counted over if the quota doesn't say otherwise
+	defaultQuotaPeriod = 24 * time.Hour
+
+	// quotaUsagePageSize is the number of jobs we read at a time to determine the usage of a quota
+	quotaUsagePageSize = 500
+)
+
+// QuotaConfig limits the resources the jobs of a team or repository consume, so that they cannot starve the
+// jobs of everyone else. Jobs which would exceed a quota don't run and are marked as such.
+type QuotaConfig struct {
+	Name string `yaml:"name"`
+
+	// Repositories limits the quota to jobs of these repositories, given as host/owner/repo or owner/repo.
+	// Supports globs. If empty, jobs of all repositories count towards the quota.
+	Repositories []string `yaml:"repositories,omitempty"`
+
+	// Labels limits the quota to jobs with these labels, e.g. team: platform or project: shop
+	Labels map[string]string `yaml:"labels,omitempty"`
+
+	// MaxConcurrentJobs limits the number of jobs whose pods exist at the same time
+	MaxConcurrentJobs int `yaml:"maxConcurrentJobs,omitempty"`
+
+	// MaxJobMinutes limits the time jobs run for within the period
+	MaxJobMinutes int64 `yaml:"maxJobMinutes,omitempty"`
+
+	// Period is the sliding window job minutes are counted over. Defaults to 24 hours.
+	Period *executor.Duration `yaml:"period,omitempty"`
+}
+
+// validateQuotas checks that quotas have unique names, valid repository patterns and limit something
+func validateQuotas(quotas []QuotaConfig) error {
+	names := make(map[string]struct{}, len(quotas))
+	for _, q := range quotas {
+		if q.Name == "" {
+			return xerrors.Errorf("quotas must have a name")
+		}
+		if _, exists := names[q.Name]; exists {
+			return xerrors.Errorf("quota %s is configured more than once", q.Name)
+		}
+		names[q.Name] = struct{}{}
+
+		if q.MaxConcurrentJobs <= 0 && q.MaxJobMinutes <= 0 {
+			return xerrors.Errorf("quota %s must set maxConcurrentJobs or maxJobMinutes", q.Name)
+		}
+		if q.Period != nil && q.Period.Duration <= 0 {
+			return xerrors.Errorf("quota %s must have a positive period", q.Name)
+		}
+		for _, r := range q.Repositories {
+			if _, err := path.Match(r, ""); err != nil {
+				return xerrors.Errorf("invalid repository pattern %s of quota %s: %w", r, q.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Applies returns true if a job counts towards this quota
+func (q QuotaConfig) Applies(md *v1.JobMetadata) bool {
+	if len(q.Repositories) > 0 {
+		var match bool
+		for _, r := range q.Repositories {
+			if repoMatches(r, md.GetRepository()) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	for k, v := range q.Labels {
+		if md.GetLabels()[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (q QuotaConfig) period() time.Duration {
+	if q.Period == nil {
+		return defaultQuotaPeriod
+	}
+	return q.Period.Duration
+}
+
+// Usage determines how much of the quota the jobs consume at the time now. Matrix jobs count by their children,
+// which run the pods. Jobs which never ran, e.g. because they were skipped, don't count at all.
+func (q QuotaConfig) Usage(jobs []v1.JobStatus, now time.Time) *v1.QuotaUsage {
+	res := &v1.QuotaUsage{
+		Name:              q.Name,
+		MaxConcurrentJobs: int32(q.MaxConcurrentJobs),
+		MaxJobMinutes:     q.MaxJobMinutes,
+		PeriodSeconds:     int64(q.period().Seconds()),
+	}
+	periodStart := now.Add(-q.period())
+	for _, job := range jobs {
+		md := job.GetMetadata()
+		if md == nil || len(md.Children) > 0 || !q.Applies(md) {
+			continue
+		}
+
+		switch job.Phase {
+		case v1.JobPhase_PHASE_WAITING:
+			continue
+		case v1.JobPhase_PHASE_DONE, v1.JobPhase_PHASE_CLEANUP:
+			if !job.GetConditions().GetDidExecute() {
+				continue
+			}
+		default:
+			res.ConcurrentJobs++
+		}
+
+		start, err := ptypes.Timestamp(md.Created)
+		if err != nil {
+			continue
+		}
+		if wait, err := ptypes.Timestamp(job.GetConditions().GetWaitUntil()); err == nil && wait.After(start) {
+			start = wait
+		}
+		if start.Before(periodStart) {
+			start = periodStart
+		}
+		end := now
+		if job.Phase == v1.JobPhase_PHASE_DONE || job.Phase == v1.JobPhase_PHASE_CLEANUP {
+			end, err = ptypes.Timestamp(md.Finished)
+			if err != nil {
+				continue
+			}
+		}
+		if end.After(start) {
+			res.JobMinutes += end.Sub(start).Minutes()
+		}
+	}
+	return res
+}
+
+// Exceeded returns why a job would exceed the quota given its current usage, or an empty string if it would not
+func (q QuotaConfig) Exceeded(usage *v1.QuotaUsage) string {
+	if q.MaxConcurrentJobs > 0 && int(usage.ConcurrentJobs) >= q.MaxConcurrentJobs {
+		return fmt.Sprintf("quota %s exceeded: %d of %d concurrent jobs are running", q.Name, usage.ConcurrentJobs, q.MaxConcurrentJobs)
+	}
+	if q.MaxJobMinutes > 0 && usage.JobMinutes >= float64(q.MaxJobMinutes) {
+		return fmt.Sprintf("quota %s exceeded: %.0f of %d job minutes used in the last %s", q.Name, usage.JobMinutes, q.MaxJobMinutes, q.period())
+	}
+	return ""
+}
+
+// quotaUsage determines the current usage of a quota from the jobs which run now and those created within its
+// period. Jobs which were created before the period but finished within it don't count.
+func (srv *Service) quotaUsage(ctx context.Context, q QuotaConfig) (*v1.QuotaUsage, error) {
+	var filter []*v1.FilterExpression
+	for k, v := range q.Labels {
+		filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{
+			{Field: filterexpr.LabelFieldPrefix + k, Value: v, Operation: v1.FilterOp_OP_EQUALS},
+		}})
+	}
+	now := srv.now()
+	periodStart := now.Add(-q.period())
+
+	// jobs which run count however long ago they were created
+	phase := func(p string) *v1.FilterTerm {
+		return &v1.FilterTerm{Field: "phase", Value: p, Operation: v1.FilterOp_OP_EQUALS}
+	}
+	active := &v1.FilterExpression{Terms: []*v1.FilterTerm{phase("preparing"), phase("starting"), phase("running")}}
+	jobs, _, err := srv.Jobs.Find(ctx, append(filter, active), nil, 0, 0)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot determine usage of quota %s: %w", q.Name, err)
+	}
+	seen := make(map[string]struct{}, len(jobs))
+	for _, j := range jobs {
+		seen[j.Name] = struct{}{}
+	}
+
+	// the repositories of a quota are globs which the store cannot filter by, hence we read all jobs of the period
+	order := []*v1.OrderExpression{{Field: "created", Ascending: false}}
+	for offset := 0; ; offset += quotaUsagePageSize {
+		page, _, err := srv.Jobs.Find(ctx, filter, order, offset, quotaUsagePageSize)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot determine usage of quota %s: %w", q.Name, err)
+		}
+		for _, j := range page {
+			created, err := ptypes.Timestamp(j.GetMetadata().GetCreated())
+			if err == nil && created.Before(periodStart) {
+				return q.Usage(jobs, now), nil
+			}
+			if _, ok := seen[j.Name]; ok {
+				continue
+			}
+			jobs = append(jobs, j)
+		}
+		if len(page) < quotaUsagePageSize {
+			return q.Usage(jobs, now), nil
+		}
+	}
+}
+
+// checkQuotas returns why a job cannot run because it would exceed a quota, or an empty string if it can run.
+// If it can run, the quotas of the job stay locked until release is called, which the caller must do once the
+// job is stored. This way concurrent jobs cannot all pass a quota which has room for one of them only.
+func (srv *Service) checkQuotas(ctx context.Context, md *v1.JobMetadata) (reason string, release func(), err error) {
+	var quotas []QuotaConfig
+	for _, q := range srv.config().Quotas {
+		if q.Applies(md) {
+			quotas = append(quotas, q)
+		}
+	}
+	// we lock the quotas in a stable order, so that jobs locking the same quotas cannot deadlock
+	sort.Slice(quotas, func(i, j int) bool { return quotas[i].Name < quotas[j].Name })
+
+	var locked []*sync.Mutex
+	release = func() {
+		for _, mu := range locked {
+			mu.Unlock()
+		}
+	}
+	for _, q := range quotas {
+		mu, _ := srv.quotaLocks.LoadOrStore(q.Name, &sync.Mutex{})
+		mu.(*sync.Mutex).Lock()
+		locked = append(locked, mu.(*sync.Mutex))
+
+		usage, err := srv.quotaUsage(ctx, q)
+		if err != nil {
+			release()
+			return "", func() {}, err
+		}
+		if reason := q.Exceeded(usage); reason != "" {
+			release()
+			return reason, func() {}, nil
+		}
+	}
+	return "", release, nil
+}
+
+// GetQuotaUsage returns the current resource consumption of the configured quotas
+func (srv *Service) GetQuotaUsage(ctx context.Context, req *v1.GetQuotaUsageRequest) (*v1.GetQuotaUsageResponse, error) {
+	var res []*v1.QuotaUsage
+	for _, q := range srv.config().Quotas {
+		if req.Name != "" && q.Name != req.Name {
+			continue
+		}
+		usage, err := srv.quotaUsage(store.WithStaleReads(ctx), q)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		res = append(res, usage)
+	}
+	if req.Name != "" && len(res) == 0 {
+		return nil, status.Errorf(codes.NotFound, "quota %s is not configured", req.Name)
+	}
+	return &v1.GetQuotaUsageResponse{Quotas: res}, nil
+}
@@ -0,0 +1,116 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestQuotaUsageReadsAllJobsOfThePeriod(t *testing.T) {
+	var (
+		now   = time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+		jobs  = store.NewInMemoryJobStore()
+		quota = QuotaConfig{Name: "api", Repositories: []string{"32leaves/api"}, MaxJobMinutes: 1000}
+		srv   = &Service{Jobs: jobs, Clock: &testClock{now}}
+	)
+	storeJob := func(name, repo string, phase v1.JobPhase, created, finished time.Duration) {
+		md := &v1.JobMetadata{
+			Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: repo},
+			Created:    &timestamp.Timestamp{Seconds: now.Add(-created).Unix()},
+		}
+		if phase == v1.JobPhase_PHASE_DONE {
+			md.Finished = &timestamp.Timestamp{Seconds: now.Add(-finished).Unix()}
+		}
+		err := jobs.Store(context.Background(), v1.JobStatus{Name: name, Phase: phase, Metadata: md, Conditions: &v1.JobConditions{DidExecute: true}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the jobs of the quota are older than many jobs of other repositories
+	storeJob("api.1", "api", v1.JobPhase_PHASE_DONE, 3*time.Hour, 2*time.Hour)
+	storeJob("api.2", "api", v1.JobPhase_PHASE_DONE, 23*time.Hour, 22*time.Hour)
+	for i := 0; i < 2*quotaUsagePageSize; i++ {
+		storeJob(fmt.Sprintf("web.%d", i), "web", v1.JobPhase_PHASE_DONE, time.Hour, time.Hour)
+	}
+	// jobs which run count even if they were created before the period
+	storeJob("api.0", "api", v1.JobPhase_PHASE_RUNNING, 48*time.Hour, 0)
+	// jobs created before the period don't count
+	storeJob("api.old", "api", v1.JobPhase_PHASE_DONE, 30*time.Hour, 29*time.Hour)
+
+	usage, err := srv.quotaUsage(context.Background(), quota)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.ConcurrentJobs != 1 {
+		t.Errorf("expected 1 concurrent job, got %d", usage.ConcurrentJobs)
+	}
+	// api.0 counts for the whole period of 24 hours, api.1 and api.2 for an hour each
+	if exp := float64(26 * 60); usage.JobMinutes != exp {
+		t.Errorf("expected %v job minutes, got %v", exp, usage.JobMinutes)
+	}
+}
+
+func TestCheckQuotasSerializesJobStarts(t *testing.T) {
+	var (
+		now  = time.Date(2020, 4, 1, 12, 0, 0, 0, time.UTC)
+		jobs = store.NewInMemoryJobStore()
+		srv  = &Service{Jobs: jobs, Clock: &testClock{now}}
+		md   = &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "api"}}
+	)
+	srv.Config.Quotas = []QuotaConfig{
+		{Name: "other", Repositories: []string{"32leaves/web"}, MaxConcurrentJobs: 1},
+		{Name: "api", Repositories: []string{"32leaves/api"}, MaxConcurrentJobs: 1},
+	}
+
+	reason, release, err := srv.checkQuotas(context.Background(), md)
+	if err != nil || reason != "" {
+		t.Fatalf("expected the first job to pass: %q, %v", reason, err)
+	}
+
+	type result struct {
+		Reason string
+		Err    error
+	}
+	second := make(chan result, 1)
+	go func() {
+		reason, release, err := srv.checkQuotas(context.Background(), md)
+		release()
+		second <- result{reason, err}
+	}()
+	select {
+	case r := <-second:
+		t.Fatalf("expected the second job to wait for the first one to be stored, got %+v", r)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	err = jobs.Store(context.Background(), v1.JobStatus{Name: "api.1", Phase: v1.JobPhase_PHASE_RUNNING, Metadata: &v1.JobMetadata{
+		Repository: md.Repository,
+		Created:    &timestamp.Timestamp{Seconds: now.Unix()},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	select {
+	case r := <-second:
+		if r.Err != nil || r.Reason == "" {
+			t.Errorf("expected the second job to exceed the quota, got %+v", r)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second job did not pass the quota check")
+	}
+
+	// jobs of other quotas don't wait
+	reason, release, err = srv.checkQuotas(context.Background(), &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "web"}})
+	if err != nil || reason != "" {
+		t.Errorf("expected job of another quota to pass: %q, %v", reason, err)
+	}
+	release()
+}
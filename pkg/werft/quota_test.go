@@ -0,0 +1,70 @@
+package werft_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestQuotaUsage(t *testing.T) {
+	now := time.Unix(100000, 0)
+	ago := func(d time.Duration) *timestamp.Timestamp {
+		return &timestamp.Timestamp{Seconds: now.Add(-d).Unix()}
+	}
+	job := func(repo, team string, phase v1.JobPhase, created, finished *timestamp.Timestamp) v1.JobStatus {
+		return v1.JobStatus{
+			Phase: phase,
+			Metadata: &v1.JobMetadata{
+				Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: repo},
+				Labels:     map[string]string{"team": team},
+				Created:    created,
+				Finished:   finished,
+			},
+			Conditions: &v1.JobConditions{DidExecute: phase != v1.JobPhase_PHASE_WAITING},
+		}
+	}
+
+	jobs := []v1.JobStatus{
+		job("api", "shop", v1.JobPhase_PHASE_RUNNING, ago(10*time.Minute), nil),
+		job("api", "shop", v1.JobPhase_PHASE_DONE, ago(30*time.Minute), ago(20*time.Minute)),
+		job("web", "shop", v1.JobPhase_PHASE_DONE, ago(70*time.Minute), ago(50*time.Minute)),
+		job("web", "shop", v1.JobPhase_PHASE_WAITING, ago(5*time.Minute), nil),
+		job("werft", "ci", v1.JobPhase_PHASE_STARTING, ago(1*time.Minute), nil),
+	}
+	skipped := job("web", "shop", v1.JobPhase_PHASE_DONE, ago(5*time.Minute), ago(5*time.Minute))
+	skipped.Conditions = &v1.JobConditions{Success: true, Skipped: true}
+	matrix := job("api", "shop", v1.JobPhase_PHASE_RUNNING, ago(10*time.Minute), nil)
+	matrix.Metadata.Children = []string{"api-build-master.1-0"}
+	jobs = append(jobs, skipped, matrix)
+
+	tests := []struct {
+		Name       string
+		Quota      werft.QuotaConfig
+		Concurrent int32
+		Minutes    float64
+		Exceeded   bool
+	}{
+		{"label", werft.QuotaConfig{Name: "shop", Labels: map[string]string{"team": "shop"}, MaxConcurrentJobs: 2}, 1, 40, false},
+		{"repository", werft.QuotaConfig{Name: "api", Repositories: []string{"32leaves/api"}, MaxConcurrentJobs: 1}, 1, 20, true},
+		{"all jobs", werft.QuotaConfig{Name: "all", MaxJobMinutes: 100}, 2, 41, false},
+		{"period", werft.QuotaConfig{Name: "shop", Labels: map[string]string{"team": "shop"}, MaxJobMinutes: 30, Period: &executor.Duration{Duration: time.Hour}}, 1, 30, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			usage := test.Quota.Usage(jobs, now)
+			if usage.ConcurrentJobs != test.Concurrent {
+				t.Errorf("expected %d concurrent jobs, got %d", test.Concurrent, usage.ConcurrentJobs)
+			}
+			if usage.JobMinutes != test.Minutes {
+				t.Errorf("expected %v job minutes, got %v", test.Minutes, usage.JobMinutes)
+			}
+			if exceeded := test.Quota.Exceeded(usage); (exceeded != "") != test.Exceeded {
+				t.Errorf("unexpected quota state: %q", exceeded)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package werft
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// annotationPerJobStatus is set on jobs which report their GitHub status under a context of their own, i.e. jobs of
+// repositories whose repo config sets statusChecks.perJob
+const annotationPerJobStatus = "perJobStatus"
+
+// statusContext returns the GitHub status context a job reports its status under
+func statusContext(md *v1.JobMetadata) string {
+	var (
+		perJob  bool
+		jobSpec string
+	)
+	for _, a := range md.Annotations {
+		switch a.Key {
+		case annotationPerJobStatus:
+			perJob = a.Value == "true"
+		case filterexpr.LabelFieldPrefix + labelJobSpec:
+			jobSpec = a.Value
+		}
+	}
+	if !perJob || jobSpec == "" {
+		return werftGithubContext
+	}
+	return werftGithubContext + "/" + jobSpec
+}
+
+// isWerftStatusContext returns true if werft reports the status of jobs under a status context
+func isWerftStatusContext(ctx string) bool {
+	if ctx == werftGithubContext {
+		return true
+	}
+	return strings.HasPrefix(ctx, werftGithubContext+"/") && !strings.HasPrefix(ctx, werftResultGithubContext)
+}
+
+// requiredStatusContexts returns the status contexts of the jobs a repo config starts which are to be required
+func requiredStatusContexts(cfg *repoconfig.C) []string {
+	paths := cfg.RequiredJobPaths()
+	if len(paths) == 0 {
+		return nil
+	}
+	if cfg.StatusChecks == nil || !cfg.StatusChecks.PerJob {
+		return []string{werftGithubContext}
+	}
+
+	var (
+		res  []string
+		seen = make(map[string]struct{})
+	)
+	for _, p := range paths {
+		// this must produce the same name as StartGitHubJob does for the job-spec label
+		ctx := werftGithubContext + "/" + strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		if _, ok := seen[ctx]; ok {
+			continue
+		}
+		seen[ctx] = struct{}{}
+		res = append(res, ctx)
+	}
+	return res
+}
+
+// syncRequiredChecks makes the werft status contexts among the required status checks of a protected branch match
+// the jobs the repo config starts
+func (srv *Service) syncRequiredChecks(ctx context.Context, repo *v1.Repository, branch string, cfg *repoconfig.C, dryRun bool) (*v1.SyncRequiredChecksResponse, error) {
+	current, _, err := srv.GitHub.Client.Repositories.GetRequiredStatusChecks(ctx, repo.Owner, repo.Repo, branch)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot get required status checks of %s: %w", branch, err)
+	}
+
+	var (
+		res     v1.SyncRequiredChecksResponse
+		present = make(map[string]struct{}, len(current.Contexts))
+		desired = make(map[string]struct{})
+	)
+	for _, c := range requiredStatusContexts(cfg) {
+		desired[c] = struct{}{}
+	}
+	for _, c := range current.Contexts {
+		present[c] = struct{}{}
+		if _, ok := desired[c]; !ok && isWerftStatusContext(c) {
+			res.Removed = append(res.Removed, c)
+			continue
+		}
+		res.Contexts = append(res.Contexts, c)
+	}
+	for c := range desired {
+		if _, ok := present[c]; ok {
+			continue
+		}
+		res.Added = append(res.Added, c)
+		res.Contexts = append(res.Contexts, c)
+	}
+	sort.Strings(res.Added)
+	sort.Strings(res.Contexts)
+
+	if dryRun || (len(res.Added) == 0 && len(res.Removed) == 0) {
+		return &res, nil
+	}
+	if len(res.Contexts) == 0 {
+		return nil, xerrors.Errorf("cannot remove all required status checks of %s", branch)
+	}
+	_, _, err = srv.GitHub.Client.Repositories.UpdateRequiredStatusChecks(ctx, repo.Owner, repo.Repo, branch, &github.RequiredStatusChecksRequest{
+		Strict:   &current.Strict,
+		Contexts: res.Contexts,
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot update required status checks of %s: %w", branch, err)
+	}
+	log.WithFields(log.Fields{
+		"owner":   repo.Owner,
+		"repo":    repo.Repo,
+		"branch":  branch,
+		"added":   res.Added,
+		"removed": res.Removed,
+	}).Info("synced required status checks")
+	return &res, nil
+}
+
+// syncRequiredChecksOnPush syncs the required status checks of a branch if the repo config asks for it
+func (srv *Service) syncRequiredChecksOnPush(logger *log.Entry, cfg *repoconfig.C, md *v1.JobMetadata) {
+	if cfg.StatusChecks == nil || md.Trigger != v1.JobTrigger_TRIGGER_PUSH || !strings.HasPrefix(md.Repository.Ref, "refs/heads/") {
+		return
+	}
+	branch := strings.TrimPrefix(md.Repository.Ref, "refs/heads/")
+	var sync bool
+	for _, b := range cfg.StatusChecks.SyncRequired {
+		if b == branch {
+			sync = true
+			break
+		}
+	}
+	if !sync {
+		return
+	}
+
+	repo := *md.Repository
+	srv.goBackground(func(ctx context.Context) {
+		_, err := srv.syncRequiredChecks(ctx, &repo, branch, cfg, false)
+		if err != nil {
+			logger.WithError(err).Warn("cannot sync required status checks")
+		}
+	})
+}
+
+// SyncRequiredChecks makes the werft status checks among the required status checks of a protected branch match
+// the jobs the repository's werft config starts
+func (srv *Service) SyncRequiredChecks(ctx context.Context, req *v1.SyncRequiredChecksRequest) (*v1.SyncRequiredChecksResponse, error) {
+	if err := srv.requireAdmin(req.Token, "syncing required checks"); err != nil {
+		return nil, err
+	}
+
+	if req.Repository == nil || req.Repository.Owner == "" || req.Repository.Repo == "" {
+		return nil, status.Error(codes.InvalidArgument, "repository is required")
+	}
+
+	repo := &v1.Repository{Host: "github.com", Owner: req.Repository.Owner, Repo: req.Repository.Repo}
+	branch := req.Branch
+	if branch == "" {
+		r, _, err := srv.GitHub.Client.Repositories.Get(ctx, repo.Owner, repo.Repo)
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "cannot get repository: %v", err)
+		}
+		branch = r.GetDefaultBranch()
+	}
+	repo.Ref = "refs/heads/" + branch
+
+	cfg, _, err := srv.getRepoCfg(ctx, repo, &GitHubContentProvider{
+		Owner:    repo.Owner,
+		Repo:     repo.Repo,
+		Revision: branch,
+		Client:   srv.GitHub.Client,
+	})
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	res, err := srv.syncRequiredChecks(ctx, repo, branch, cfg, req.DryRun)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return res, nil
+}
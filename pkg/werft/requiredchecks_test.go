@@ -0,0 +1,99 @@
+package werft_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/google/go-github/github"
+)
+
+func TestSyncRequiredChecks(t *testing.T) {
+	var (
+		config  string
+		patched []string
+	)
+	mux := http.NewServeMux()
+	ghsrv := httptest.NewServer(mux)
+	defer ghsrv.Close()
+	mux.HandleFunc("/repos/acme/shop/contents/.werft", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[{"name":"config.yaml","download_url":%q}]`, ghsrv.URL+"/raw/config.yaml")
+	})
+	mux.HandleFunc("/raw/config.yaml", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, config)
+	})
+	mux.HandleFunc("/repos/acme/shop/branches/master/protection/required_status_checks", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			var req github.RequiredStatusChecksRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			patched = req.Contexts
+		}
+		fmt.Fprint(w, `{"strict":true,"contexts":["continuous-integration/werft","continuous-integration/werft/lint","other-ci"]}`)
+	})
+
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(ghsrv.URL + "/")
+	srv := &werft.Service{
+		GitHub: werft.GitHubSetup{Client: client},
+		Config: werft.Config{AdminTokens: []string{"admin"}},
+	}
+
+	tests := []struct {
+		Name     string
+		Config   string
+		DryRun   bool
+		Contexts []string
+		Patched  []string
+	}{
+		{
+			Name:     "single context",
+			Config:   "defaultJob: .werft/build.yaml\n",
+			Contexts: []string{"continuous-integration/werft", "other-ci"},
+			Patched:  []string{"continuous-integration/werft", "other-ci"},
+		},
+		{
+			Name:     "per job",
+			Config:   "defaultJob: .werft/build.yaml\njobs:\n- path: .werft/lint.yaml\n- path: .werft/nightly.yaml\nstatusChecks:\n  perJob: true\n  notRequired: [.werft/nightly.yaml]\n",
+			Contexts: []string{"continuous-integration/werft/build", "continuous-integration/werft/lint", "other-ci"},
+			Patched:  []string{"continuous-integration/werft/build", "continuous-integration/werft/lint", "other-ci"},
+		},
+		{
+			Name:     "dry run",
+			Config:   "defaultJob: .werft/build.yaml\nstatusChecks:\n  perJob: true\n",
+			DryRun:   true,
+			Contexts: []string{"continuous-integration/werft/build", "other-ci"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			config, patched = test.Config, nil
+			resp, err := srv.SyncRequiredChecks(context.Background(), &v1.SyncRequiredChecksRequest{
+				Repository: &v1.Repository{Owner: "acme", Repo: "shop"},
+				Branch:     "master",
+				DryRun:     test.DryRun,
+				Token:      "admin",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(resp.Contexts, test.Contexts) {
+				t.Errorf("expected contexts %v, got %v", test.Contexts, resp.Contexts)
+			}
+			if !reflect.DeepEqual(patched, test.Patched) {
+				t.Errorf("expected update %v, got %v", test.Patched, patched)
+			}
+		})
+	}
+
+	_, err := srv.SyncRequiredChecks(context.Background(), &v1.SyncRequiredChecksRequest{Repository: &v1.Repository{Owner: "acme", Repo: "shop"}, Token: "nope"})
+	if err == nil {
+		t.Error("expected invalid admin tokens to be rejected")
+	}
+}
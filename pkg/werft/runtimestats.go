@@ -0,0 +1,103 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetRuntimeStats returns the internal state of the werft server to diagnose hangs and memory growth
+func (srv *Service) GetRuntimeStats(ctx context.Context, req *v1.GetRuntimeStatsRequest) (*v1.GetRuntimeStatsResponse, error) {
+	if err := srv.requireAdmin(req.Token, "reading runtime stats"); err != nil {
+		return nil, err
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	res := &v1.GetRuntimeStatsResponse{
+		GoVersion:      runtime.Version(),
+		Goroutines:     int32(runtime.NumGoroutine()),
+		HeapAllocBytes: mem.HeapAlloc,
+		HeapObjects:    mem.HeapObjects,
+		SysBytes:       mem.Sys,
+		GcCycles:       mem.NumGC,
+		GcPauseTotalNs: mem.PauseTotalNs,
+		Buffers:        srv.bufferStats(),
+	}
+	if !srv.started.IsZero() {
+		res.Started, _ = ptypes.TimestampProto(srv.started)
+	}
+	if req.GoroutineDump {
+		var buf bytes.Buffer
+		err := rpprof.Lookup("goroutine").WriteTo(&buf, 2)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		res.GoroutineDump = buf.String()
+	}
+	return res, nil
+}
+
+// bufferStats returns the sizes of the service's queues and buffers
+func (srv *Service) bufferStats() map[string]int64 {
+	res := make(map[string]int64)
+
+	if srv.Executor != nil {
+		if queue, err := srv.Executor.Queue(); err == nil {
+			res["queued_jobs"] = int64(len(queue))
+		}
+	}
+	if srv.jobBatch != nil {
+		res["job_batch_pending"] = int64(srv.jobBatch.Pending())
+	}
+
+	srv.mu.RLock()
+	res["job_logs"] = int64(len(srv.logListener))
+	srv.mu.RUnlock()
+
+	srv.approvalMu.Lock()
+	res["pending_approvals"] = int64(len(srv.approvals))
+	srv.approvalMu.Unlock()
+
+	for _, topic := range srv.events.Topics() {
+		res["listeners_"+topic] = int64(len(srv.events.Listeners(topic)))
+	}
+	return res
+}
+
+// HandleDebug serves pprof profiles and goroutine dumps at /debug/pprof/ to callers which present one of the admin
+// tokens as bearer token. Without admin tokens the endpoint does not exist.
+func (srv *Service) HandleDebug(w http.ResponseWriter, r *http.Request) {
+	err := srv.requireAdmin(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), "debugging")
+	if status.Code(err) == codes.Unavailable {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, "invalid admin token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/debug/pprof/cmdline":
+		pprof.Cmdline(w, r)
+	case "/debug/pprof/profile":
+		pprof.Profile(w, r)
+	case "/debug/pprof/symbol":
+		pprof.Symbol(w, r)
+	case "/debug/pprof/trace":
+		pprof.Trace(w, r)
+	default:
+		// the index serves the named profiles, e.g. /debug/pprof/goroutine?debug=2 or /debug/pprof/heap
+		pprof.Index(w, r)
+	}
+}
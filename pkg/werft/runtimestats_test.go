@@ -0,0 +1,81 @@
+package werft_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetRuntimeStats(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Tokens []string
+		Req    *v1.GetRuntimeStatsRequest
+		Code   codes.Code
+	}{
+		{"disabled", nil, &v1.GetRuntimeStatsRequest{Token: "secret"}, codes.Unavailable},
+		{"wrong token", []string{"secret"}, &v1.GetRuntimeStatsRequest{Token: "guess"}, codes.PermissionDenied},
+		{"stats", []string{"secret"}, &v1.GetRuntimeStatsRequest{Token: "secret"}, codes.OK},
+		{"goroutine dump", []string{"secret"}, &v1.GetRuntimeStatsRequest{Token: "secret", GoroutineDump: true}, codes.OK},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := &werft.Service{Config: werft.Config{AdminTokens: test.Tokens}}
+			resp, err := srv.GetRuntimeStats(context.Background(), test.Req)
+			if code := status.Code(err); code != test.Code {
+				t.Fatalf("expected %v, got %v", test.Code, err)
+			}
+			if err != nil {
+				return
+			}
+			if resp.Goroutines == 0 || resp.HeapAllocBytes == 0 {
+				t.Errorf("expected goroutines and heap to be reported, got %v", resp)
+			}
+			if _, ok := resp.Buffers["job_logs"]; !ok {
+				t.Errorf("expected job_logs buffer, got %v", resp.Buffers)
+			}
+			if hasDump := strings.Contains(resp.GoroutineDump, "TestGetRuntimeStats"); hasDump != test.Req.GoroutineDump {
+				t.Errorf("expected goroutine dump: %v, got %q", test.Req.GoroutineDump, resp.GoroutineDump)
+			}
+		})
+	}
+}
+
+func TestHandleDebug(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Tokens []string
+		Token  string
+		Status int
+	}{
+		{"disabled", nil, "secret", http.StatusNotFound},
+		{"no token", []string{"secret"}, "", http.StatusUnauthorized},
+		{"wrong token", []string{"secret"}, "guess", http.StatusUnauthorized},
+		{"goroutines", []string{"secret"}, "secret", http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			srv := &werft.Service{Config: werft.Config{AdminTokens: test.Tokens}}
+			req := httptest.NewRequest(http.MethodGet, "/debug/pprof/goroutine?debug=2", nil)
+			if test.Token != "" {
+				req.Header.Set("Authorization", "Bearer "+test.Token)
+			}
+			rec := httptest.NewRecorder()
+			srv.HandleDebug(rec, req)
+
+			if rec.Code != test.Status {
+				t.Fatalf("expected status %d, got %d: %s", test.Status, rec.Code, rec.Body.String())
+			}
+			if test.Status == http.StatusOK && !strings.Contains(rec.Body.String(), "goroutine") {
+				t.Errorf("expected goroutine dump, got %s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package werft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// annotationSBOMJob is set on jobs which generate the SBOM of an image another job built. Its value is the name
+	// of that job. Like cleanup jobs, these jobs are not stored in the database and do not propagate through the system.
+	annotationSBOMJob = "sbomJob"
+
+	// annotationSBOMImage names the image (name@digest) an SBOM job generates the SBOM of
+	annotationSBOMImage = "sbomImage"
+
+	// sbomContainerName is the name of the container whose output is the SBOM
+	sbomContainerName = "sbom"
+
+	// defaultSBOMImage is the SBOM generator werft runs if the config doesn't say otherwise
+	defaultSBOMImage = "anchore/syft:latest"
+
+	// defaultSBOMFormat is the format of the SBOMs the default generator produces
+	defaultSBOMFormat = "spdx-json"
+
+	// maxSBOMSize is the size in bytes of the largest SBOM werft stores
+	maxSBOMSize = 16 << 20
+)
+
+// defaultSBOMArgs make syft print the SBOM of the image to stdout
+var defaultSBOMArgs = []string{"$(SBOM_IMAGE)", "-o", defaultSBOMFormat, "-q"}
+
+// SBOMConfig configures the generation of software bills of materials (SBOM) for the images jobs built
+type SBOMConfig struct {
+	// Image is the SBOM generator. Defaults to defaultSBOMImage.
+	Image string `yaml:"image,omitempty"`
+
+	// Args are passed to the generator, which must print the SBOM (JSON) to stdout. $(SBOM_IMAGE) is replaced by the
+	// image (name@digest). Defaults to defaultSBOMArgs.
+	Args []string `yaml:"args,omitempty"`
+
+	// Format names the format of the SBOMs the generator produces. Defaults to defaultSBOMFormat.
+	Format string `yaml:"format,omitempty"`
+
+	// PodSpec forms the basis of the pods which generate SBOMs, e.g. to provide registry credentials.
+	PodSpec *configPodSpec `yaml:"podSpec,omitempty"`
+}
+
+func (c *SBOMConfig) format() string {
+	if c == nil || c.Format == "" {
+		return defaultSBOMFormat
+	}
+	return c.Format
+}
+
+// generateSBOMs starts a job which generates the SBOM of every image a job which just succeeded reported as result
+func (srv *Service) generateSBOMs(s *v1.JobStatus) {
+	cfg := srv.Config.SBOM
+	if cfg == nil || !s.Conditions.GetSuccess() {
+		return
+	}
+
+	var idx int
+	for _, res := range s.Results {
+		if res.Type != resultTypeImage {
+			continue
+		}
+		image, digest, err := ParseImageResult(res.Payload)
+		if err != nil {
+			continue
+		}
+
+		ref := fmt.Sprintf("%s@%s", image, digest)
+		err = srv.startSBOMJob(cfg, s, ref, fmt.Sprintf("sbom-%s-%d", s.Name, idx))
+		if err != nil {
+			log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).WithField("image", ref).Warn("cannot start SBOM job")
+		}
+		idx++
+	}
+}
+
+func (srv *Service) startSBOMJob(cfg *SBOMConfig, s *v1.JobStatus, image, name string) error {
+	md := v1.JobMetadata{
+		Owner:      s.Metadata.Owner,
+		Repository: s.Metadata.Repository,
+		Trigger:    v1.JobTrigger_TRIGGER_UNKNOWN,
+		Created:    srv.timestampNow(),
+		Annotations: []*v1.Annotation{
+			{Key: annotationSBOMJob, Value: s.Name},
+			{Key: annotationSBOMImage, Value: image},
+		},
+	}
+
+	var podspec corev1.PodSpec
+	if cfg.PodSpec != nil {
+		podspec = corev1.PodSpec(*cfg.PodSpec)
+	}
+	gen := corev1.Container{
+		Name:  sbomContainerName,
+		Image: cfg.Image,
+		Args:  cfg.Args,
+		Env:   []corev1.EnvVar{{Name: "SBOM_IMAGE", Value: image}},
+	}
+	if gen.Image == "" {
+		gen.Image = defaultSBOMImage
+	}
+	if len(gen.Args) == 0 {
+		gen.Args = defaultSBOMArgs
+	}
+	podspec.Containers = append(podspec.Containers, gen)
+	podspec.RestartPolicy = corev1.RestartPolicyNever
+
+	_, err := srv.Executor.Start(podspec, md, executor.WithCanReplay(false), executor.WithBackoff(3), executor.WithName(name), executor.WithTrustedContainers(gen))
+	return err
+}
+
+// handleSBOMJobUpdate stores the SBOM a job produced once it finished
+func (srv *Service) handleSBOMJobUpdate(pod *corev1.Pod, s *v1.JobStatus) {
+	if s.Phase != v1.JobPhase_PHASE_DONE || pod == nil {
+		return
+	}
+
+	var job, image string
+	for _, annotation := range s.Metadata.Annotations {
+		switch annotation.Key {
+		case annotationSBOMJob:
+			job = annotation.Value
+		case annotationSBOMImage:
+			image = annotation.Value
+		}
+	}
+	logger := log.WithField("name", job).WithField("image", image)
+	if !s.Conditions.GetSuccess() {
+		logger.WithField("sbomJob", s.Name).Warn("cannot generate SBOM: generator failed")
+		return
+	}
+
+	err := srv.storeSBOM(pod.Name, job, image)
+	if err != nil {
+		logger.WithError(err).Warn("cannot store SBOM")
+	}
+}
+
+func (srv *Service) storeSBOM(pod, job, image string) error {
+	data, err := srv.Executor.ContainerLogs(pod, sbomContainerName, maxSBOMSize+1)
+	if err != nil {
+		return xerrors.Errorf("cannot get generator output: %w", err)
+	}
+	if len(data) > maxSBOMSize {
+		return xerrors.Errorf("SBOM exceeds %d bytes", maxSBOMSize)
+	}
+	if !json.Valid(data) {
+		return xerrors.Errorf("generator did not produce JSON")
+	}
+	return srv.Jobs.StoreSBOM(job, image, data)
+}
+
+// GetJobSBOM returns the software bills of materials of the images a job built
+func (srv *Service) GetJobSBOM(ctx context.Context, req *v1.GetJobSBOMRequest) (*v1.GetJobSBOMResponse, error) {
+	if req.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	sboms, err := srv.Jobs.GetSBOMs(req.Name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	format := srv.Config.SBOM.format()
+	res := &v1.GetJobSBOMResponse{}
+	for image, data := range sboms {
+		if req.Image != "" && image != req.Image && !strings.HasPrefix(image, req.Image+"@") {
+			continue
+		}
+		res.Sboms = append(res.Sboms, &v1.JobSBOM{Image: image, Format: format, Content: data})
+	}
+	if len(res.Sboms) == 0 {
+		if req.Image != "" {
+			return nil, status.Errorf(codes.NotFound, "job %s has no SBOM of %s", req.Name, req.Image)
+		}
+		return nil, status.Errorf(codes.NotFound, "job %s has no SBOMs", req.Name)
+	}
+	sort.Slice(res.Sboms, func(i, j int) bool { return res.Sboms[i].Image < res.Sboms[j].Image })
+	return res, nil
+}
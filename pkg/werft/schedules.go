@@ -0,0 +1,108 @@
+package werft
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/schedule"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultScheduleRuns is the number of next runs ListSchedules lists per schedule unless asked for another number
+	defaultScheduleRuns = 5
+
+	// maxScheduleRuns is the largest number of next runs ListSchedules lists per schedule
+	maxScheduleRuns = 100
+)
+
+// scheduleTimezone returns the time zone a schedule is evaluated in
+func (srv *Service) scheduleTimezone(s *v1.Schedule) string {
+	if s.Timezone != "" {
+		return s.Timezone
+	}
+	if tz := srv.config().Timezone; tz != "" {
+		return tz
+	}
+	return time.Local.String()
+}
+
+// RegisterSchedules replaces the schedules a scheduler registered before
+func (srv *Service) RegisterSchedules(ctx context.Context, req *v1.RegisterSchedulesRequest) (*v1.RegisterSchedulesResponse, error) {
+	if req.Scheduler == "" {
+		return nil, status.Error(codes.InvalidArgument, "scheduler is required")
+	}
+
+	res := make([]*v1.Schedule, 0, len(req.Schedules))
+	for _, s := range req.Schedules {
+		s = proto.Clone(s).(*v1.Schedule)
+		s.Scheduler = req.Scheduler
+		s.NextRuns = nil
+		_, err := schedule.Parse(s.Spec, srv.scheduleTimezone(s))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "schedule %s: %v", s.Name, err)
+		}
+		res = append(res, s)
+	}
+
+	srv.scheduleMu.Lock()
+	if srv.schedules == nil {
+		srv.schedules = make(map[string][]*v1.Schedule)
+	}
+	srv.schedules[req.Scheduler] = res
+	srv.scheduleMu.Unlock()
+
+	log.WithField("scheduler", req.Scheduler).WithField("schedules", len(res)).Info("registered schedules")
+	return &v1.RegisterSchedulesResponse{}, nil
+}
+
+// ListSchedules returns the registered schedules and their next runs
+func (srv *Service) ListSchedules(ctx context.Context, req *v1.ListSchedulesRequest) (*v1.ListSchedulesResponse, error) {
+	runs := int(req.Runs)
+	if runs <= 0 {
+		runs = defaultScheduleRuns
+	}
+	if runs > maxScheduleRuns {
+		runs = maxScheduleRuns
+	}
+
+	srv.scheduleMu.RLock()
+	var res []*v1.Schedule
+	for _, ss := range srv.schedules {
+		for _, s := range ss {
+			res = append(res, proto.Clone(s).(*v1.Schedule))
+		}
+	}
+	srv.scheduleMu.RUnlock()
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Scheduler != res[j].Scheduler {
+			return res[i].Scheduler < res[j].Scheduler
+		}
+		return res[i].Name < res[j].Name
+	})
+
+	now := srv.now()
+	for _, s := range res {
+		s.Timezone = srv.scheduleTimezone(s)
+		sched, err := schedule.Parse(s.Spec, s.Timezone)
+		if err != nil {
+			// the time zone werft is configured with changed since the schedule was registered
+			log.WithError(err).WithField("scheduler", s.Scheduler).WithField("name", s.Name).Warn("cannot evaluate schedule")
+			continue
+		}
+		for _, t := range sched.NextRuns(now, runs) {
+			ts, err := ptypes.TimestampProto(t)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			s.NextRuns = append(s.NextRuns, ts)
+		}
+	}
+	return &v1.ListSchedulesResponse{Schedules: res}, nil
+}
@@ -0,0 +1,60 @@
+package werft_test
+
+import (
+	"context"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes"
+)
+
+func TestSchedules(t *testing.T) {
+	srv := &werft.Service{Config: werft.Config{Timezone: "Europe/Berlin"}}
+	ctx := context.Background()
+
+	_, err := srv.RegisterSchedules(ctx, &v1.RegisterSchedulesRequest{
+		Scheduler: "cron",
+		Schedules: []*v1.Schedule{{Name: "broken", Spec: "0 3 * *"}},
+	})
+	if err == nil {
+		t.Error("expected invalid specs to be rejected")
+	}
+
+	_, err = srv.RegisterSchedules(ctx, &v1.RegisterSchedulesRequest{
+		Scheduler: "cron",
+		Schedules: []*v1.Schedule{
+			{Name: "nightly", Spec: "0 3 * * *"},
+			{Name: "hourly", Spec: "0 * * * *", Timezone: "UTC"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := srv.ListSchedules(ctx, &v1.ListSchedulesRequest{Runs: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Schedules) != 2 {
+		t.Fatalf("expected 2 schedules, got %d", len(resp.Schedules))
+	}
+	for _, s := range resp.Schedules {
+		if len(s.NextRuns) != 3 {
+			t.Errorf("%s: expected 3 runs, got %d", s.Name, len(s.NextRuns))
+		}
+		if s.Scheduler != "cron" {
+			t.Errorf("%s: expected scheduler cron, got %s", s.Name, s.Scheduler)
+		}
+	}
+	nightly := resp.Schedules[1]
+	if nightly.Name != "nightly" || nightly.Timezone != "Europe/Berlin" {
+		t.Fatalf("expected nightly schedule in Europe/Berlin, got %s in %s", nightly.Name, nightly.Timezone)
+	}
+	for _, r := range nightly.NextRuns {
+		ts, _ := ptypes.Timestamp(r)
+		if h := ts.Hour(); h != 1 && h != 2 {
+			t.Errorf("expected nightly runs at 03:00 Europe/Berlin, got %v", ts)
+		}
+	}
+}
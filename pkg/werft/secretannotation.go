@@ -0,0 +1,65 @@
+package werft
+
+import (
+	"context"
+	"regexp"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// redactedAnnotationValue replaces the value of secret annotations in the metadata werft stores and returns
+const redactedAnnotationValue = "[redacted]"
+
+// validEnvName matches the names secret annotations can have, as they become the name of an environment variable
+var validEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RedactSecretAnnotations takes the values of the secret annotations out of a job's metadata and replaces them with
+// a placeholder. It returns the values by annotation key, i.e. the environment the job's pod needs.
+func RedactSecretAnnotations(md *v1.JobMetadata) (map[string]string, error) {
+	var env map[string]string
+	for _, a := range md.Annotations {
+		if !a.Secret {
+			continue
+		}
+		if !validEnvName.MatchString(a.Key) {
+			return nil, xerrors.Errorf("secret annotation %q is not a valid environment variable name", a.Key)
+		}
+		if a.Value == redactedAnnotationValue {
+			// e.g. metadata copied from an earlier job - werft never stored the actual value
+			return nil, xerrors.Errorf("secret annotation %s has no value: secret annotations must be set again for every job", a.Key)
+		}
+		if _, exists := env[a.Key]; exists {
+			return nil, xerrors.Errorf("secret annotation %s is set more than once", a.Key)
+		}
+
+		if env == nil {
+			env = make(map[string]string)
+		}
+		env[a.Key] = a.Value
+		a.Value = redactedAnnotationValue
+	}
+	return env, nil
+}
+
+type secretAnnotationsKey struct{}
+
+// withSecretAnnotations redacts the secret annotations of a job and keeps their values in the context,
+// so that RunJob can hand them to the job's pod. The values never leave the context, hence jobs which
+// are started again (e.g. retries) don't have them.
+func withSecretAnnotations(ctx context.Context, md *v1.JobMetadata) (context.Context, error) {
+	env, err := RedactSecretAnnotations(md)
+	if err != nil {
+		return ctx, err
+	}
+	if len(env) == 0 {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, secretAnnotationsKey{}, env), nil
+}
+
+// secretAnnotations returns the values of the secret annotations of the job started in this context
+func secretAnnotations(ctx context.Context) map[string]string {
+	env, _ := ctx.Value(secretAnnotationsKey{}).(map[string]string)
+	return env
+}
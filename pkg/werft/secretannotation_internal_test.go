@@ -0,0 +1,146 @@
+package werft
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/executor"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestPodSecrets(t *testing.T) {
+	spec := corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "checkout", Env: []corev1.EnvVar{{Name: "GIT_SECRET", Value: "init"}}}},
+		Containers: []corev1.Container{{
+			Name: "build",
+			Env: []corev1.EnvVar{
+				{Name: "NPM_AUTH", Value: "from-annotation"},
+				{Name: "MY_SECRET", Value: "by-name"},
+				{Name: "GITHUB_TOKEN", Value: "token"},
+				{Name: "DB_PASSWORD_FILE", Value: "password"},
+				{Name: "deploy_key", Value: "key"},
+				{Name: "PLAIN", Value: "visible"},
+				{Name: "KEYBOARD", Value: "visible-too"},
+				{Name: "EMPTY_SECRET"},
+				{Name: "FROM_SECRET", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+					Key:                  "api",
+				}}},
+				{Name: "FROM_MISSING_SECRET", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "missing"},
+					Key:                  "api",
+				}}},
+			},
+			EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "env"}}}},
+		}},
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "creds", Namespace: "werft"}, Data: map[string][]byte{"api": []byte("from-secret-key-ref")}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "env", Namespace: "werft"}, Data: map[string][]byte{"A": []byte("from-env-from")}},
+	)
+	kexec := &executor.Executor{Client: client, Config: executor.Config{Namespace: "werft"}}
+
+	tests := []struct {
+		Name      string
+		Executor  executor.Backend
+		Patterns  []string
+		SecretEnv []string
+		Secrets   []string
+	}{
+		{
+			Name:    "default patterns",
+			Secrets: []string{"by-name", "init", "key", "password", "token"},
+		},
+		{
+			Name:      "after restart",
+			SecretEnv: []string{"NPM_AUTH"},
+			Secrets:   []string{"by-name", "from-annotation", "init", "key", "password", "token"},
+		},
+		{
+			Name:     "configured patterns",
+			Patterns: []string{"*_AUTH", "plain"},
+			Secrets:  []string{"from-annotation", "visible"},
+		},
+		{
+			Name:     "kubernetes secrets",
+			Executor: kexec,
+			Patterns: []string{"NONE"},
+			Secrets:  []string{"from-env-from", "from-secret-key-ref"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			names, err := newSecretEnvNames(test.Patterns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := &Service{Executor: test.Executor, secretEnv: names}
+
+			act := srv.podSecrets(spec, test.SecretEnv)
+			sort.Strings(act)
+			if !reflect.DeepEqual(act, test.Secrets) {
+				t.Errorf("unexpected secrets: got %v, want %v", act, test.Secrets)
+			}
+		})
+	}
+}
+
+func TestNewSecretEnvNames(t *testing.T) {
+	tests := []struct {
+		Patterns []string
+		Name     string
+		Matches  bool
+		Error    bool
+	}{
+		{nil, "MY_SECRET", true, false},
+		{nil, "secret_thing", true, false},
+		{nil, "NPM_TOKEN", true, false},
+		{nil, "TOKENS", false, false},
+		{nil, "PASSWORD", true, false},
+		{nil, "AWS_SECRET_ACCESS_KEY", true, false},
+		{nil, "SSH_KEY", true, false},
+		{nil, "KEYS", false, false},
+		{[]string{"API_*"}, "api_url", true, false},
+		{[]string{"API_*"}, "MY_SECRET", false, false},
+		{[]string{"A.B"}, "AXB", false, false},
+		{[]string{""}, "", false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			names, err := newSecretEnvNames(test.Patterns)
+			if (err != nil) != test.Error {
+				t.Fatalf("expected error %v, got %v", test.Error, err)
+			}
+			if act := names.matches(test.Name); act != test.Matches {
+				t.Errorf("%v matching %s: got %v, want %v", test.Patterns, test.Name, act, test.Matches)
+			}
+		})
+	}
+}
+
+func TestPodEnvironmentRedactsSecretEnv(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{executor.AnnotationSecretEnv: "NPM_AUTH\nRELEASE"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Env: []corev1.EnvVar{
+			{Name: "NPM_AUTH", Value: "from-annotation"},
+			{Name: "RELEASE", Value: "v1"},
+			{Name: "MY_SECRET", Value: "by-name"},
+			{Name: "PLAIN", Value: "visible"},
+		}}}},
+	}
+
+	// without a masker, e.g. because werft restarted and lost it
+	env := podEnvironment(pod, nil, defaultSecretEnvNames)
+	expected := map[string]string{
+		"NPM_AUTH":  "[redacted]",
+		"RELEASE":   "[redacted]",
+		"MY_SECRET": "[redacted]",
+		"PLAIN":     "visible",
+	}
+	if act := env.Containers[0].Env; !reflect.DeepEqual(act, expected) {
+		t.Errorf("unexpected env: got %v, want %v", act, expected)
+	}
+}
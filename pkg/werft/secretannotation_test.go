@@ -0,0 +1,67 @@
+package werft_test
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestRedactSecretAnnotations(t *testing.T) {
+	tests := []struct {
+		Name        string
+		Annotations []*v1.Annotation
+		Env         map[string]string
+		Redacted    []*v1.Annotation
+		Error       bool
+	}{
+		{
+			Name:        "no secrets",
+			Annotations: []*v1.Annotation{{Key: "foo", Value: "bar"}},
+			Redacted:    []*v1.Annotation{{Key: "foo", Value: "bar"}},
+		},
+		{
+			Name:        "secret",
+			Annotations: []*v1.Annotation{{Key: "foo", Value: "bar"}, {Key: "NPM_TOKEN", Value: "s3cr3t", Secret: true}},
+			Env:         map[string]string{"NPM_TOKEN": "s3cr3t"},
+			Redacted:    []*v1.Annotation{{Key: "foo", Value: "bar"}, {Key: "NPM_TOKEN", Value: "[redacted]", Secret: true}},
+		},
+		{
+			Name:        "invalid name",
+			Annotations: []*v1.Annotation{{Key: "npm-token", Value: "s3cr3t", Secret: true}},
+			Error:       true,
+		},
+		{
+			Name:        "already redacted",
+			Annotations: []*v1.Annotation{{Key: "NPM_TOKEN", Value: "[redacted]", Secret: true}},
+			Error:       true,
+		},
+		{
+			Name:        "duplicate",
+			Annotations: []*v1.Annotation{{Key: "NPM_TOKEN", Value: "a", Secret: true}, {Key: "NPM_TOKEN", Value: "b", Secret: true}},
+			Error:       true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			md := &v1.JobMetadata{Annotations: test.Annotations}
+			env, err := werft.RedactSecretAnnotations(md)
+			if test.Error {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(env, test.Env) {
+				t.Errorf("unexpected env: %v", env)
+			}
+			if !reflect.DeepEqual(md.Annotations, test.Redacted) {
+				t.Errorf("unexpected annotations: %v", md.Annotations)
+			}
+		})
+	}
+}
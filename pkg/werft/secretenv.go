@@ -0,0 +1,129 @@
+package werft
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/32leaves/werft/pkg/executor"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultSecretEnvPatterns are the names of environment variables whose values werft treats as secrets unless the
+// config names others
+var DefaultSecretEnvPatterns = []string{"*SECRET*", "*_TOKEN", "*PASSWORD*", "*_KEY"}
+
+var defaultSecretEnvNames, _ = newSecretEnvNames(DefaultSecretEnvPatterns)
+
+// secretEnvNames matches the names of environment variables whose values are secrets
+type secretEnvNames []*regexp.Regexp
+
+// newSecretEnvNames compiles name patterns, in which * matches any sequence of characters. Names are matched
+// regardless of their case. Without patterns DefaultSecretEnvPatterns apply.
+func newSecretEnvNames(patterns []string) (secretEnvNames, error) {
+	if len(patterns) == 0 {
+		patterns = DefaultSecretEnvPatterns
+	}
+
+	res := make(secretEnvNames, 0, len(patterns))
+	for _, p := range patterns {
+		if p == "" {
+			return nil, xerrors.Errorf("secret env patterns must not be empty")
+		}
+		expr, err := regexp.Compile("(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(p), `\*`, ".*") + "$")
+		if err != nil {
+			return nil, xerrors.Errorf("invalid secret env pattern %s: %w", p, err)
+		}
+		res = append(res, expr)
+	}
+	return res, nil
+}
+
+// matches returns true if name is the name of a secret environment variable
+func (n secretEnvNames) matches(name string) bool {
+	for _, expr := range n {
+		if expr.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretEnvNames returns the configured secret env names, or the default ones if the service wasn't started
+func (srv *Service) secretEnvNames() secretEnvNames {
+	if srv.secretEnv == nil {
+		return defaultSecretEnvNames
+	}
+	return srv.secretEnv
+}
+
+// podSecrets returns the values of all environment variables of a pod spec which are secrets: those whose name
+// matches the secret env patterns, those werft set from secret annotations (secretEnv) and those which come from
+// Kubernetes secrets.
+func (srv *Service) podSecrets(spec corev1.PodSpec, secretEnv []string) []string {
+	isSecret := make(map[string]bool, len(secretEnv))
+	for _, name := range secretEnv {
+		isSecret[name] = true
+	}
+	names := srv.secretEnvNames()
+	secrets := make(map[string]*corev1.Secret)
+
+	var res []string
+	for _, cs := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for _, c := range cs {
+			for _, e := range c.EnvFrom {
+				if e.SecretRef == nil {
+					continue
+				}
+				secret := srv.jobSecret(secrets, e.SecretRef.Name)
+				if secret == nil {
+					continue
+				}
+				for _, val := range secret.Data {
+					if len(val) > 0 {
+						res = append(res, string(val))
+					}
+				}
+			}
+
+			for _, e := range c.Env {
+				if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+					ref := e.ValueFrom.SecretKeyRef
+					if secret := srv.jobSecret(secrets, ref.Name); secret != nil && len(secret.Data[ref.Key]) > 0 {
+						res = append(res, string(secret.Data[ref.Key]))
+					}
+					continue
+				}
+				if e.Value == "" || !(isSecret[e.Name] || names.matches(e.Name)) {
+					continue
+				}
+				res = append(res, e.Value)
+			}
+		}
+	}
+	return res
+}
+
+// jobSecret gets a secret jobs refer to, e.g. in valueFrom.secretKeyRef, so that its values can be masked. Only the
+// Kubernetes executor can read secrets. cache holds the secrets got already, including those which we couldn't get.
+func (srv *Service) jobSecret(cache map[string]*corev1.Secret, name string) *corev1.Secret {
+	if s, ok := cache[name]; ok {
+		return s
+	}
+	cache[name] = nil
+
+	kexec, isKube := srv.Executor.(*executor.Executor)
+	if !isKube {
+		log.WithField("secret", name).Warn("cannot mask the values of secrets jobs refer to: only the Kubernetes executor can read secrets")
+		return nil
+	}
+	secret, err := kexec.Client.CoreV1().Secrets(kexec.Config.Namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		log.WithError(err).WithField("secret", name).Warn("cannot get secret of job to mask its values")
+		return nil
+	}
+	cache[name] = secret
+	return secret
+}
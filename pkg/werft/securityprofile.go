@@ -0,0 +1,208 @@
+package werft
+
+import (
+	"strings"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// defaultSecurityProfile is the security profile of jobs which don't request one
+	defaultSecurityProfile = "default"
+
+	// annotationSeccompPod and annotationAppArmorPrefix configure the seccomp and AppArmor profiles of pods
+	annotationSeccompPod     = "seccomp.security.alpha.kubernetes.io/pod"
+	annotationAppArmorPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+	// annotationSecurityProfile records the security profile a job ran with if it's not the default profile,
+	// so that one can audit which jobs made use of exceptions
+	annotationSecurityProfile = "securityProfile"
+
+	// readOnlyTmpVolume is mounted at /tmp in the containers of jobs with a read-only root filesystem
+	readOnlyTmpVolume = "werft-tmp"
+)
+
+// SecurityProfileConfig hardens the pods of jobs, e.g. those running untrusted pull request code. Jobs run with the
+// "default" profile unless they request another one in their spec, which they get only if the policy of that profile
+// allows it - e.g. to let the jobs of repositories which build images run as root.
+type SecurityProfileConfig struct {
+	// Name is the profile jobs request in their spec, e.g. image-builder. The "default" profile is used by all jobs
+	// which don't request a profile.
+	Name string `yaml:"name"`
+
+	// Seccomp is the seccomp profile of job pods, e.g. runtime/default or localhost/<profile>
+	Seccomp string `yaml:"seccomp,omitempty"`
+
+	// AppArmor is the AppArmor profile of job containers, e.g. runtime/default or localhost/<profile>
+	AppArmor string `yaml:"appArmor,omitempty"`
+
+	// RunAsNonRoot makes Kubernetes refuse to start job containers which would run as root
+	RunAsNonRoot bool `yaml:"runAsNonRoot,omitempty"`
+
+	// RunAsUser is the user job containers run as. Defaults to the user of their image.
+	RunAsUser *int64 `yaml:"runAsUser,omitempty"`
+
+	// ReadOnlyRootFilesystem mounts the root filesystem of job containers read-only. Jobs can write to
+	// /workspace and an empty /tmp.
+	ReadOnlyRootFilesystem bool `yaml:"readOnlyRootFilesystem,omitempty"`
+
+	// DropCapabilities are removed from job containers, e.g. ALL. Containers cannot add them back.
+	DropCapabilities []string `yaml:"dropCapabilities,omitempty"`
+
+	// AllowPrivilegeEscalation permits privileged containers and processes gaining more privileges than their parent.
+	// Both are forbidden by default.
+	AllowPrivilegeEscalation bool `yaml:"allowPrivilegeEscalation,omitempty"`
+
+	// Repositories limits the profile to jobs of these repositories, given as host/owner/repo or owner/repo.
+	// Supports globs. If empty, jobs of all repositories can use this profile. The default profile applies to all jobs.
+	Repositories []string `yaml:"repositories,omitempty"`
+
+	// Refs limits the profile to jobs running on these refs, e.g. refs/heads/master. Supports globs.
+	// If empty, jobs on all refs can use this profile.
+	Refs []string `yaml:"refs,omitempty"`
+}
+
+// Validate checks that the profile has a name and the default profile applies to all jobs
+func (c SecurityProfileConfig) Validate() error {
+	if c.Name == "" {
+		return xerrors.Errorf("security profiles must have a name")
+	}
+	if c.Name == defaultSecurityProfile && (len(c.Repositories) > 0 || len(c.Refs) > 0) {
+		return xerrors.Errorf("the default security profile applies to all jobs and cannot be limited to repositories or refs")
+	}
+	return nil
+}
+
+// Allows returns true if a job may use this security profile. Profiles limited to repositories or refs are
+// exceptions which only jobs with a verified source get.
+func (c SecurityProfileConfig) Allows(md *v1.JobMetadata) bool {
+	return policyAllowsJob(c.Repositories, c.Refs, md)
+}
+
+// Apply hardens the containers of a job's pod spec and returns the annotations its pod needs, e.g. for seccomp.
+// Werft's own containers are added later and are not affected.
+func (c SecurityProfileConfig) Apply(podspec *corev1.PodSpec) map[string]string {
+	annotations := make(map[string]string)
+	if c.Seccomp != "" {
+		annotations[annotationSeccompPod] = c.Seccomp
+	}
+
+	var (
+		dropAll  bool
+		mountTmp bool
+	)
+	drop := make(map[string]struct{}, len(c.DropCapabilities))
+	for _, cp := range c.DropCapabilities {
+		if strings.EqualFold(cp, "ALL") {
+			dropAll = true
+		}
+		drop[strings.ToUpper(cp)] = struct{}{}
+	}
+
+	harden := func(ctr *corev1.Container) {
+		if c.AppArmor != "" {
+			annotations[annotationAppArmorPrefix+ctr.Name] = c.AppArmor
+		}
+
+		sc := ctr.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+			ctr.SecurityContext = sc
+		}
+		if c.RunAsNonRoot {
+			nonRoot := true
+			sc.RunAsNonRoot = &nonRoot
+		}
+		if c.RunAsUser != nil {
+			uid := *c.RunAsUser
+			sc.RunAsUser = &uid
+		}
+		if c.ReadOnlyRootFilesystem {
+			readOnly := true
+			sc.ReadOnlyRootFilesystem = &readOnly
+			if !mountsPath(ctr, "/tmp") {
+				ctr.VolumeMounts = append(ctr.VolumeMounts, corev1.VolumeMount{Name: readOnlyTmpVolume, MountPath: "/tmp"})
+				mountTmp = true
+			}
+		}
+		if !c.AllowPrivilegeEscalation {
+			privileged, escalate := false, false
+			sc.Privileged = &privileged
+			sc.AllowPrivilegeEscalation = &escalate
+		}
+		if len(drop) > 0 {
+			if sc.Capabilities == nil {
+				sc.Capabilities = &corev1.Capabilities{}
+			}
+			var add []corev1.Capability
+			for _, cp := range sc.Capabilities.Add {
+				if _, dropped := drop[strings.ToUpper(string(cp))]; !dropAll && !dropped {
+					add = append(add, cp)
+				}
+			}
+			sc.Capabilities.Add = add
+			for _, cp := range c.DropCapabilities {
+				sc.Capabilities.Drop = append(sc.Capabilities.Drop, corev1.Capability(cp))
+			}
+		}
+	}
+	for i := range podspec.InitContainers {
+		harden(&podspec.InitContainers[i])
+	}
+	for i := range podspec.Containers {
+		harden(&podspec.Containers[i])
+	}
+
+	if mountTmp {
+		podspec.Volumes = append(podspec.Volumes, corev1.Volume{
+			Name:         readOnlyTmpVolume,
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+	}
+	return annotations
+}
+
+func mountsPath(ctr *corev1.Container, path string) bool {
+	for _, m := range ctr.VolumeMounts {
+		if m.MountPath == path {
+			return true
+		}
+	}
+	return false
+}
+
+// applySecurityProfile hardens a job's pod spec using the security profile requested in its spec, or the default
+// profile. It returns the annotations the job's pod needs. If no security profiles are configured, the pod spec is
+// left as is.
+func (srv *Service) applySecurityProfile(md *v1.JobMetadata, jobspec *repoconfig.JobSpec, podspec *corev1.PodSpec) (map[string]string, error) {
+	name := jobspec.SecurityProfile
+	if name == "" {
+		name = defaultSecurityProfile
+	}
+
+	var cfg *SecurityProfileConfig
+	for i, c := range srv.Config.SecurityProfiles {
+		if c.Name == name {
+			cfg = &srv.Config.SecurityProfiles[i]
+			break
+		}
+	}
+	if cfg == nil && jobspec.SecurityProfile != "" {
+		return nil, xerrors.Errorf("unknown security profile %s", jobspec.SecurityProfile)
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+	if !cfg.Allows(md) {
+		return nil, xerrors.Errorf("security profile %s is not available to jobs of this repository or ref", name)
+	}
+
+	annotations := cfg.Apply(podspec)
+	if name != defaultSecurityProfile {
+		setAnnotation(md, annotationSecurityProfile, name)
+	}
+	return annotations, nil
+}
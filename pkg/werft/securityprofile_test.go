@@ -0,0 +1,137 @@
+package werft_test
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSecurityProfileApply(t *testing.T) {
+	var (
+		uid   int64 = 1000
+		yes         = true
+		no          = false
+		build       = func() corev1.Container {
+			return corev1.Container{
+				Name: "build",
+				SecurityContext: &corev1.SecurityContext{
+					Privileged:   &yes,
+					Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN", "SYS_TIME"}},
+				},
+			}
+		}
+	)
+
+	tests := []struct {
+		Name            string
+		Profile         werft.SecurityProfileConfig
+		Container       corev1.Container
+		SecurityContext *corev1.SecurityContext
+		Annotations     map[string]string
+		TmpMounts       int
+		Volumes         int
+	}{
+		{
+			Name: "hardened",
+			Profile: werft.SecurityProfileConfig{
+				Seccomp:                "runtime/default",
+				AppArmor:               "runtime/default",
+				RunAsNonRoot:           true,
+				RunAsUser:              &uid,
+				ReadOnlyRootFilesystem: true,
+				DropCapabilities:       []string{"ALL"},
+			},
+			Container: build(),
+			SecurityContext: &corev1.SecurityContext{
+				Privileged:               &no,
+				AllowPrivilegeEscalation: &no,
+				RunAsNonRoot:             &yes,
+				RunAsUser:                &uid,
+				ReadOnlyRootFilesystem:   &yes,
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			},
+			Annotations: map[string]string{
+				"seccomp.security.alpha.kubernetes.io/pod":             "runtime/default",
+				"container.apparmor.security.beta.kubernetes.io/build": "runtime/default",
+			},
+			TmpMounts: 1,
+			Volumes:   1,
+		},
+		{
+			Name:      "drop single capability",
+			Profile:   werft.SecurityProfileConfig{DropCapabilities: []string{"net_admin"}, AllowPrivilegeEscalation: true},
+			Container: build(),
+			SecurityContext: &corev1.SecurityContext{
+				Privileged:   &yes,
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"SYS_TIME"}, Drop: []corev1.Capability{"net_admin"}},
+			},
+			Annotations: map[string]string{},
+		},
+		{
+			Name:    "existing tmp mount",
+			Profile: werft.SecurityProfileConfig{ReadOnlyRootFilesystem: true, AllowPrivilegeEscalation: true},
+			Container: corev1.Container{
+				Name:         "build",
+				VolumeMounts: []corev1.VolumeMount{{Name: "scratch", MountPath: "/tmp"}},
+			},
+			SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: &yes},
+			Annotations:     map[string]string{},
+			TmpMounts:       1,
+			Volumes:         0,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			podspec := corev1.PodSpec{Containers: []corev1.Container{test.Container}}
+			annotations := test.Profile.Apply(&podspec)
+
+			if !reflect.DeepEqual(annotations, test.Annotations) {
+				t.Errorf("expected annotations %v, got %v", test.Annotations, annotations)
+			}
+			ctr := podspec.Containers[0]
+			if !reflect.DeepEqual(ctr.SecurityContext, test.SecurityContext) {
+				t.Errorf("expected security context %v, got %v", test.SecurityContext, ctr.SecurityContext)
+			}
+			var tmpMounts int
+			for _, m := range ctr.VolumeMounts {
+				if m.MountPath == "/tmp" {
+					tmpMounts++
+				}
+			}
+			if tmpMounts != test.TmpMounts {
+				t.Errorf("expected %d mounts at /tmp, got %d", test.TmpMounts, tmpMounts)
+			}
+			if len(podspec.Volumes) != test.Volumes {
+				t.Errorf("expected %d volumes, got %d", test.Volumes, len(podspec.Volumes))
+			}
+		})
+	}
+}
+
+func TestSecurityProfileAllows(t *testing.T) {
+	repo := &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"}
+	exception := werft.SecurityProfileConfig{Name: "image-builder", Repositories: []string{"32leaves/*"}, Refs: []string{"refs/heads/master"}}
+
+	tests := []struct {
+		Name        string
+		Profile     werft.SecurityProfileConfig
+		Metadata    *v1.JobMetadata
+		Expectation bool
+	}{
+		{"default", werft.SecurityProfileConfig{Name: "default"}, &v1.JobMetadata{Repository: repo}, true},
+		{"verified", exception, &v1.JobMetadata{Repository: repo, SourceVerified: true}, true},
+		{"unverified", exception, &v1.JobMetadata{Repository: repo}, false},
+		{"other ref", exception, &v1.JobMetadata{Repository: &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/fix"}, SourceVerified: true}, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := test.Profile.Allows(test.Metadata)
+			if act != test.Expectation {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package werft
+
+import (
+	"context"
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// serverEventTopic is the emitter topic server events other than job updates are published on
+	serverEventTopic = "server"
+
+	// maxPendingServerEvents is the number of events a subscriber to the event stream may fall behind on before we drop it
+	maxPendingServerEvents = 1000
+)
+
+// Server event types. Job updates are server events of type job.
+const (
+	serverEventJob          = "job"
+	serverEventJobEvent     = "job_event"
+	serverEventQueue        = "queue"
+	serverEventConfig       = "config"
+	serverEventMaintenance  = "maintenance"
+	serverEventAnnouncement = "announcement"
+	serverEventDeadLetter   = "dead_letter"
+)
+
+// publishEvent publishes a server event to the event stream subscribers
+func (srv *Service) publishEvent(evt *v1.ServerEvent) {
+	if evt.Time == nil {
+		evt.Time = srv.timestampNow()
+	}
+	<-srv.events.Emit(serverEventTopic, evt)
+}
+
+// StreamEvents streams all server events which match the filter expression
+func (srv *Service) StreamEvents(req *v1.StreamEventsRequest, resp v1.WerftService_StreamEventsServer) error {
+	filter, err := filterexpr.ParseExpression(req.Filter)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid filter: %v", err)
+	}
+
+	ctx := resp.Context()
+	sub := srv.subscribeEvents(ctx, filter)
+	for {
+		evt, err := sub.Next(ctx)
+		if err == errSlowSubscriber {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if err != nil {
+			return status.Error(codes.Aborted, err.Error())
+		}
+
+		err = resp.Send(evt)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// eventSubscription delivers the server events which match a filter to a single subscriber. Like jobSubscription it
+// takes events from the emitter as soon as they're emitted. Unlike job updates, events are never replaced by newer ones.
+type eventSubscription struct {
+	mu      sync.Mutex
+	pending []*v1.ServerEvent
+	err     error
+	notify  chan struct{}
+}
+
+// subscribeEvents subscribes to job updates and server events which match the filter until ctx is done or the
+// service stops
+func (srv *Service) subscribeEvents(ctx context.Context, filter *filterexpr.Expression) *eventSubscription {
+	sub := &eventSubscription{
+		notify: make(chan struct{}, 1),
+	}
+
+	jobs := srv.events.On("job")
+	evts := srv.events.On(serverEventTopic)
+	deliver := func(runCtx context.Context) {
+		done, stopped := ctx.Done(), runCtx.Done()
+		var draining bool
+		for jobs != nil || evts != nil {
+			var evt *v1.ServerEvent
+			select {
+			case e, ok := <-jobs:
+				if !ok {
+					jobs = nil
+					continue
+				}
+				if len(e.Args) == 0 {
+					continue
+				}
+				if job, ok := e.Args[0].(*v1.JobStatus); ok {
+					evt = &v1.ServerEvent{Type: serverEventJob, Time: srv.timestampNow(), Job: job, JobName: job.Name}
+				}
+			case e, ok := <-evts:
+				if !ok {
+					evts = nil
+					continue
+				}
+				if len(e.Args) == 0 {
+					continue
+				}
+				evt, _ = e.Args[0].(*v1.ServerEvent)
+			case <-done:
+			case <-stopped:
+			}
+			if !draining && (ctx.Err() != nil || runCtx.Err() != nil) {
+				// Off waits for pending events to be delivered, hence we must keep draining until it closes the channels
+				draining, done, stopped = true, nil, nil
+				go srv.events.Off("job", jobs)
+				go srv.events.Off(serverEventTopic, evts)
+			}
+			if evt != nil && filter.Matches(filterexpr.EventFields(evt)) {
+				sub.push(evt)
+			}
+		}
+	}
+	if !srv.goBackground(deliver) {
+		// the service stopped already - we still have to unsubscribe
+		go deliver(srv.backgroundContext())
+	}
+
+	return sub
+}
+
+func (sub *eventSubscription) push(evt *v1.ServerEvent) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.err != nil {
+		return
+	}
+	if len(sub.pending) >= maxPendingServerEvents {
+		sub.err = errSlowSubscriber
+		sub.pending = nil
+	} else {
+		sub.pending = append(sub.pending, evt)
+	}
+
+	select {
+	case sub.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Next returns the next event. It blocks until there is one or ctx is done.
+func (sub *eventSubscription) Next(ctx context.Context) (*v1.ServerEvent, error) {
+	for {
+		sub.mu.Lock()
+		if sub.err != nil {
+			sub.mu.Unlock()
+			return nil, sub.err
+		}
+		if len(sub.pending) > 0 {
+			evt := sub.pending[0]
+			sub.pending = sub.pending[1:]
+			sub.mu.Unlock()
+			return evt, nil
+		}
+		sub.mu.Unlock()
+
+		select {
+		case <-sub.notify:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
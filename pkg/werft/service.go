@@ -8,26 +8,37 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
 	"github.com/32leaves/werft/pkg/filterexpr"
 	"github.com/32leaves/werft/pkg/logcutter"
 	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/tracing"
 	termtohtml "github.com/buildkite/terminal-to-html"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-github/github"
 	log "github.com/sirupsen/logrus"
-	"github.com/technosophos/moniker"
-	"golang.org/x/oauth2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/xerrors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 // StartLocalJob starts a job whoose content is uploaded
 func (srv *Service) StartLocalJob(inc v1.WerftService_StartLocalJobServer) error {
+	// the workspace of local jobs is uploaded into their pod, which only works in Kubernetes
+	kexec, ok := srv.Executor.(*executor.Executor)
+	if !ok {
+		return status.Error(codes.Unimplemented, "local jobs require the kubernetes executor")
+	}
+
 	req, err := inc.Recv()
 	if err != nil {
 		return err
@@ -36,6 +47,14 @@ func (srv *Service) StartLocalJob(inc v1.WerftService_StartLocalJobServer) error
 		return status.Error(codes.InvalidArgument, "first request must contain metadata")
 	}
 	md := *req.GetMetadata()
+	err = ValidateAnnotations(&md)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx, err := withSecretAnnotations(inc.Context(), &md)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
 	log.WithField("name", md).Debug("StartLocalJob - received metadata")
 
 	dfs, err := ioutil.TempFile(os.TempDir(), "werft-lcp")
@@ -113,16 +132,16 @@ func (srv *Service) StartLocalJob(inc v1.WerftService_StartLocalJobServer) error
 
 	cp := &LocalContentProvider{
 		TarStream:  dfs,
-		Namespace:  srv.Executor.Config.Namespace,
-		Kubeconfig: srv.Executor.KubeConfig,
-		Clientset:  srv.Executor.Client,
+		Namespace:  kexec.Config.Namespace,
+		Kubeconfig: kexec.KubeConfig,
+		Clientset:  kexec.Client,
 	}
 
 	// Note: for local jobs we DO NOT store the job yaml as we cannot replay those jobs anyways.
 	//       The context upload is a one time thing and hence prevent job replay.
 
 	flatOwner := strings.ReplaceAll(strings.ToLower(md.Owner), " ", "")
-	name := fmt.Sprintf("local-%s-%s", flatOwner, moniker.New().NameSep("-"))
+	name := fmt.Sprintf("local-%s-%s", flatOwner, srv.ids().NewID())
 	if len(name) > 58 {
 		// Kubernetes label values must not be longer than 63 characters according to
 		// https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set
@@ -131,7 +150,7 @@ func (srv *Service) StartLocalJob(inc v1.WerftService_StartLocalJobServer) error
 		name = name[:58]
 	}
 
-	jobStatus, err := srv.RunJob(inc.Context(), name, md, cp, jobYAML, false, time.Time{})
+	jobStatus, err := srv.RunJob(ctx, name, md, cp, jobYAML, false, time.Time{})
 
 	if err != nil {
 		return status.Error(codes.Internal, err.Error())
@@ -145,30 +164,62 @@ func (srv *Service) StartLocalJob(inc v1.WerftService_StartLocalJobServer) error
 
 // StartGitHubJob starts a job on a Git context, possibly with a custom job.
 func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRequest) (resp *v1.StartJobResponse, err error) {
+	ctx, span := tracing.Start(ctx, "StartGitHubJob", trace.WithAttributes(
+		attribute.String("repo.owner", req.GetMetadata().GetRepository().GetOwner()),
+		attribute.String("repo.repo", req.GetMetadata().GetRepository().GetRepo()),
+		attribute.String("repo.ref", req.GetMetadata().GetRepository().GetRef()),
+	))
+	defer tracing.FinishSpan(span, &err)
+
 	var (
 		ghclient = srv.GitHub.Client
 		gitauth  = srv.GitHub.Auth
 	)
 	if req.GithubToken != "" {
-		ts := oauth2.StaticTokenSource(
-			&oauth2.Token{AccessToken: req.GithubToken},
-		)
-		tc := oauth2.NewClient(ctx, ts)
-		ghclient = github.NewClient(tc)
+		ghclient = srv.userGitHubClient(ctx, req.GithubToken)
 		gitauth = fixedOAuthTokenGitCreds(req.GithubToken)
 	}
 
 	md := req.Metadata
+	err = ValidateAnnotations(md)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ctx, err = withSecretAnnotations(ctx, md)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// werft cannot access repositories with a deploy key using the GitHub API, but only clone them
+	deployKey := srv.repositoryConfig(md.Repository).DeployKey != nil
+	if deployKey && md.Repository.Revision == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "%s/%s is cloned using a deploy key, hence werft cannot resolve refs: please name the revision", md.Repository.Owner, md.Repository.Repo)
+	}
+	if deployKey && req.JobSpecRef != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "%s/%s is cloned using a deploy key, hence werft cannot read job specs from another ref", md.Repository.Owner, md.Repository.Repo)
+	}
+
+	// jobs started by webhooks are verified already, others are if we resolve their ref to their revision
+	verified := sourceVerified(ctx)
 	if md.Repository.Revision == "" && md.Repository.Ref != "" {
 		md.Repository.Revision, _, err = ghclient.Repositories.GetCommitSHA1(ctx, md.Repository.Owner, md.Repository.Repo, md.Repository.Ref, "")
 		if err != nil {
 			return nil, translateGitHubToGRPCError(err, md.Repository.Revision, md.Repository.Ref)
 		}
+		verified = true
+	} else if !verified && !deployKey {
+		verified = refPointsTo(ctx, ghclient, md.Repository)
 	}
+	if req.JobYaml != nil || len(req.Sideload) > 0 {
+		// the job runs a spec or content which doesn't come from the repository
+		verified = false
+	}
+	ctx = withSourceVerified(ctx, verified)
 
-	_, _, err = ghclient.Repositories.GetCommit(ctx, md.Repository.Owner, md.Repository.Repo, md.Repository.Revision)
-	if err != nil {
-		return nil, translateGitHubToGRPCError(err, md.Repository.Revision, md.Repository.Ref)
+	if !deployKey {
+		_, _, err = ghclient.Repositories.GetCommit(ctx, md.Repository.Owner, md.Repository.Repo, md.Repository.Revision)
+		if err != nil {
+			return nil, translateGitHubToGRPCError(err, md.Repository.Revision, md.Repository.Ref)
+		}
 	}
 
 	var cp = &GitHubContentProvider{
@@ -180,11 +231,16 @@ func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRe
 	}
 
 	if len(req.Sideload) > 0 {
+		// like the workspace of local jobs, sideloaded content is uploaded into the job's pod
+		kexec, ok := srv.Executor.(*executor.Executor)
+		if !ok {
+			return nil, status.Error(codes.Unimplemented, "sideloading requires the kubernetes executor")
+		}
 		cp.Sideload = &GitHubContentProviderSideload{
 			TarStream:  bytes.NewReader(req.Sideload),
-			Namespace:  srv.Executor.Config.Namespace,
-			Kubeconfig: srv.Executor.KubeConfig,
-			Clientset:  srv.Executor.Client,
+			Namespace:  kexec.Config.Namespace,
+			Kubeconfig: kexec.KubeConfig,
+			Clientset:  kexec.Client,
 		}
 	}
 
@@ -193,16 +249,53 @@ func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRe
 		tplpath     = req.JobPath
 		jobSpecName = "custom"
 	)
+	// files are where the job spec and fragments of the job's repository come from
+	var files FileProvider = cp
+	if deployKey {
+		files = deployKeyFiles{}
+	}
+	if req.JobSpecRef != "" {
+		if jobYAML != nil {
+			return nil, status.Error(codes.InvalidArgument, "cannot use a job spec ref with a job YAML")
+		}
+
+		specRev, _, err := ghclient.Repositories.GetCommitSHA1(ctx, md.Repository.Owner, md.Repository.Repo, req.JobSpecRef, "")
+		if err != nil {
+			return nil, translateGitHubToGRPCError(err, "", req.JobSpecRef)
+		}
+		files = &GitHubContentProvider{
+			Owner:    md.Repository.Owner,
+			Repo:     md.Repository.Repo,
+			Revision: specRev,
+			Client:   ghclient,
+			Auth:     gitauth,
+		}
+		md.JobSpecSource = &v1.Repository{
+			Host:     md.Repository.Host,
+			Owner:    md.Repository.Owner,
+			Repo:     md.Repository.Repo,
+			Ref:      req.JobSpecRef,
+			Revision: specRev,
+		}
+	}
 	if jobYAML == nil {
-		if tplpath == "" {
-			repoCfg, err := getRepoCfg(ctx, cp)
-			if err != nil {
-				return nil, status.Error(codes.Internal, err.Error())
+		// jobs started from a given job file don't need a repo config, but get its defaults if there is one
+		var (
+			repoCfg *repoconfig.C
+			cfgErr  error
+		)
+		repoCfg, files, cfgErr = srv.getRepoCfg(ctx, md.Repository, files)
+		if cfgErr != nil && tplpath == "" {
+			return nil, status.Error(codes.Internal, cfgErr.Error())
+		}
+		if cfgErr == nil {
+			if tplpath == "" {
+				tplpath = repoCfg.TemplatePath(req.Metadata)
 			}
-			tplpath = repoCfg.TemplatePath(req.Metadata)
+			applyRepoCfg(md, repoCfg)
 		}
 
-		in, err := cp.Download(ctx, tplpath)
+		in, err := files.Download(ctx, tplpath)
 		if err != nil {
 			return nil, status.Errorf(codes.Internal, "cannot download jobspec from %s: %s", tplpath, err.Error())
 		}
@@ -212,42 +305,26 @@ func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRe
 			return nil, status.Errorf(codes.Internal, "cannot download jobspec from %s: %s", tplpath, err.Error())
 		}
 	}
+	jobYAML, err = srv.resolveJobSpecFragments(ctx, md.Repository, files, tplpath, jobYAML)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
 	if tplpath != "" {
 		jobSpecName = strings.TrimSuffix(filepath.Base(tplpath), filepath.Ext(tplpath))
 	}
 
-	// build job name
-	refname := md.Repository.Ref
-	refname = strings.TrimPrefix(refname, "refs/heads/")
-	refname = strings.TrimPrefix(refname, "refs/tags/")
-	refname = strings.ReplaceAll(refname, "/", "-")
-	refname = strings.ReplaceAll(refname, "_", "-")
-	refname = strings.ReplaceAll(refname, "@", "-")
-	refname = strings.ToLower(refname)
-	if refname == "" {
-		// we did not compute a sensible refname - use moniker
-		refname = moniker.New().NameSep("-")
-	}
-	name := fmt.Sprintf("%s-%s-%s", md.Repository.Repo, jobSpecName, refname)
-	if len(name) > 58 {
-		// Kubernetes label values must not be longer than 63 characters according to
-		// https://kubernetes.io/docs/concepts/overview/working-with-objects/labels/#syntax-and-character-set
-		// We need to leave some space for the build number. Assuming that we won't have more than 9999 builds on
-		// a single long named branch, leaving four chars should be enough.
-		name = name[:58]
+	name, legacyName, err := srv.newGitHubJobName(ctx, md, jobSpecName)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
 	}
-	if refname != "" {
-		// we have a valid refname, hence need to acquire job number
-		t, err := srv.Groups.Next(name)
-		if err != nil {
-			return nil, status.Error(codes.Internal, err.Error())
-		}
-
-		name = fmt.Sprintf("%s.%d", name, t)
+	if legacyName != "" {
+		// we keep the name the job would have had before the naming scheme changed, so that links using that name keep working
+		md.Annotations = append(md.Annotations, &v1.Annotation{Key: filterexpr.LabelFieldPrefix + labelLegacyName, Value: legacyName})
 	}
+	setAnnotation(md, filterexpr.LabelFieldPrefix+labelJobSpec, jobSpecName)
 
-	// We do not store the GitHub token of the request and hence can only restart those with default auth
-	canReplay := req.GithubToken == "" && len(req.Sideload) == 0
+	// We do not store the GitHub token of the request or values of secret annotations and hence can only restart those with default auth
+	canReplay := req.GithubToken == "" && len(req.Sideload) == 0 && len(secretAnnotations(ctx)) == 0
 
 	var waitUntil time.Time
 	if req.WaitUntil != nil {
@@ -257,7 +334,7 @@ func (srv *Service) StartGitHubJob(ctx context.Context, req *v1.StartGitHubJobRe
 		}
 
 		if !canReplay {
-			return nil, status.Error(codes.InvalidArgument, "cannot delay the execution of non-replayable jobs (i.e. jobs with custom GitHub token or sideload)")
+			return nil, status.Error(codes.InvalidArgument, "cannot delay the execution of non-replayable jobs (i.e. jobs with custom GitHub token, sideload or secret annotations)")
 		}
 	}
 
@@ -293,6 +370,11 @@ func (srv *Service) StartFromPreviousJob(ctx context.Context, req *v1.StartFromP
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
+	for _, a := range oldJobStatus.Metadata.GetAnnotations() {
+		if a.Secret {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s has secret annotations whose values werft does not keep - please start a new job instead", req.PreviousJob)
+		}
+	}
 	jobYAML, err := srv.Jobs.GetJobSpec(req.PreviousJob)
 	if err == store.ErrNotFound {
 		return nil, status.Error(codes.NotFound, "job spec not found")
@@ -301,16 +383,10 @@ func (srv *Service) StartFromPreviousJob(ctx context.Context, req *v1.StartFromP
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	name := req.PreviousJob
-	if strings.Contains(name, ".") {
-		segs := strings.Split(name, ".")
-		name = strings.Join(segs[0:len(segs)-1], ".")
-	}
-	nr, err := srv.Groups.Next(name)
+	name, err := srv.nextJobName(req.PreviousJob)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
-	name = fmt.Sprintf("%s.%d", name, nr)
 
 	gitauth := srv.GitHub.Auth
 	if req.GithubToken != "" {
@@ -318,6 +394,8 @@ func (srv *Service) StartFromPreviousJob(ctx context.Context, req *v1.StartFromP
 	}
 
 	md := oldJobStatus.Metadata
+	// the old job ran what's still at the ref only if the ref hasn't moved since
+	ctx = withSourceVerified(ctx, md.SourceVerified && refPointsTo(ctx, srv.GitHub.Client, md.Repository))
 	cp := &GitHubContentProvider{
 		Owner:    md.Repository.Owner,
 		Repo:     md.Repository.Repo,
@@ -393,7 +471,23 @@ func (tsa *tarStreamAdapter) Read(p []byte) (n int, err error) {
 
 // ListJobs lists jobs
 func (srv *Service) ListJobs(ctx context.Context, req *v1.ListJobsRequest) (resp *v1.ListJobsResponse, err error) {
-	result, total, err := srv.Jobs.Find(ctx, req.Filter, req.Order, int(req.Start), int(req.Limit))
+	filter := req.Filter
+	if req.LabelSelector != "" {
+		lf, err := filterexpr.ParseLabelSelector(req.LabelSelector)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		filter = append(filter, lf...)
+	}
+	if req.Project != "" {
+		filter = append(filter, projectFilter(req.Project))
+	}
+	if !req.IncludeDeleted {
+		filter = append(filter, notDeletedFilter())
+	}
+
+	// listing jobs doesn't drive any job state, hence it's fine to read from a replica
+	result, total, err := srv.Jobs.Find(store.WithStaleReads(ctx), filter, req.Order, int(req.Start), int(req.Limit))
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -401,6 +495,9 @@ func (srv *Service) ListJobs(ctx context.Context, req *v1.ListJobsRequest) (resp
 	res := make([]*v1.JobStatus, len(result))
 	for i := range result {
 		res[i] = &result[i]
+		if req.View == v1.JobView_JOB_VIEW_SUMMARY {
+			res[i] = summarizeJob(res[i])
+		}
 	}
 
 	return &v1.ListJobsResponse{
@@ -409,54 +506,158 @@ func (srv *Service) ListJobs(ctx context.Context, req *v1.ListJobsRequest) (resp
 	}, nil
 }
 
+// summarizeJob reduces a job to what's needed to list it, i.e. its name, phase, ref, time and notes
+func summarizeJob(job *v1.JobStatus) *v1.JobStatus {
+	res := &v1.JobStatus{
+		Name:  job.Name,
+		Phase: job.Phase,
+		Notes: job.Notes,
+	}
+	if job.Conditions != nil {
+		res.Conditions = &v1.JobConditions{
+			Success:    job.Conditions.Success,
+			DidExecute: job.Conditions.DidExecute,
+		}
+	}
+	if md := job.Metadata; md != nil {
+		res.Metadata = &v1.JobMetadata{
+			Owner:      md.Owner,
+			Repository: md.Repository,
+			Trigger:    md.Trigger,
+			Created:    md.Created,
+			Finished:   md.Finished,
+			Parent:     md.Parent,
+		}
+	}
+	return res
+}
+
 // Subscribe listens to job updates
 func (srv *Service) Subscribe(req *v1.SubscribeRequest, resp v1.WerftService_SubscribeServer) (err error) {
-	evts := srv.events.On("job")
-	for evt := range evts {
-		job := evt.Args[0].(*v1.JobStatus)
+	ctx := resp.Context()
+	sub := srv.subscribeJobs(ctx)
+	for {
+		job, err := sub.Next(ctx)
+		if err == errSlowSubscriber {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if err != nil {
+			return status.Error(codes.Aborted, err.Error())
+		}
 		if !filterexpr.MatchesFilter(job, req.Filter) {
 			continue
 		}
 
-		resp.Send(&v1.SubscribeResponse{
+		err = resp.Send(&v1.SubscribeResponse{
 			Result: job,
 		})
+		if err != nil {
+			return err
+		}
 	}
-	return nil
 }
 
 // GetJob returns the information about a particular job
 func (srv *Service) GetJob(ctx context.Context, req *v1.GetJobRequest) (resp *v1.GetJobResponse, err error) {
-	job, err := srv.Jobs.Get(ctx, req.Name)
+	job, err := srv.Jobs.Get(ctx, srv.resolveJobName(ctx, req.Name))
+	if err == store.ErrNotFound && srv.Archive != nil {
+		job, err = srv.Archive.Get(ctx, req.Name)
+	}
+	if err == store.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 	if job == nil {
 		return nil, status.Error(codes.NotFound, "not found")
 	}
+	if job.Phase == v1.JobPhase_PHASE_RUNNING && srv.Executor != nil {
+		// the stored usage is only updated when the job's pod changes - we want the latest sample
+		if usage := srv.Executor.ResourceUsage(job.Name); usage != nil {
+			job.ResourceUsage = usage
+		}
+	}
+	job.Estimate = srv.estimateDuration(ctx, job)
 
-	return &v1.GetJobResponse{
+	resp = &v1.GetJobResponse{
 		Result: job,
-	}, nil
+	}
+	if req.IncludePod {
+		resp.Pod, err = srv.jobPod(ctx, req.GithubToken, job)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// jobPod returns the pod a job runs in if the user identified by token may see it
+func (srv *Service) jobPod(ctx context.Context, token string, job *v1.JobStatus) (*v1.JobPod, error) {
+	required := srv.repositoryConfig(job.Metadata.Repository).PodPermission
+	if required == "" {
+		required = defaultPodPermission
+	}
+	_, err := srv.authorizeGitHubUser(ctx, token, job.Metadata.Repository, required, "see the pods of its jobs")
+	if err != nil {
+		return nil, err
+	}
+	if srv.Executor == nil {
+		return nil, nil
+	}
+
+	pod, err := srv.Executor.JobPod(job.Name)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if pod == nil {
+		return nil, nil
+	}
+	res := &v1.JobPod{
+		Name:      pod.Name,
+		Namespace: pod.Namespace,
+		Node:      pod.Spec.NodeName,
+	}
+	for _, c := range pod.Spec.Containers {
+		res.Containers = append(res.Containers, c.Name)
+	}
+	return res, nil
+}
+
+// logRange limits a log to limit bytes starting at offset. A limit of zero reads the remainder of the log.
+// Log readers can't seek, hence we have to read what we skip.
+func logRange(rd io.Reader, offset, limit int64) (io.Reader, error) {
+	if offset > 0 {
+		_, err := io.CopyN(ioutil.Discard, rd, offset)
+		if err != nil && err != io.EOF {
+			return nil, xerrors.Errorf("cannot skip to offset %d: %w", offset, err)
+		}
+	}
+	if limit > 0 {
+		return io.LimitReader(rd, limit), nil
+	}
+	return rd, nil
 }
 
 // Listen listens to logs
 func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServer) error {
 	// TOOD: if one of the listeners fails, all have to fail
+	req.Name = srv.resolveJobName(ls.Context(), req.Name)
 	job, err := srv.Jobs.Get(ls.Context(), req.Name)
 	if err == store.ErrNotFound {
 		return status.Errorf(codes.NotFound, "%s not found", req.Name)
 	}
 
+	if req.ByteOffset < 0 || req.ByteLimit < 0 {
+		return status.Error(codes.InvalidArgument, "byte offset and limit must not be negative")
+	}
+
 	var (
 		wg      sync.WaitGroup
 		logwg   sync.WaitGroup
 		errchan = make(chan error)
 	)
 	if req.Logs != v1.ListenRequestLogs_LOGS_DISABLED {
-		wg.Add(1)
-		logwg.Add(1)
-
 		rd, err := srv.Logs.Read(req.Name)
 		if err != nil {
 			if err == store.ErrNotFound {
@@ -466,23 +667,42 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 			return status.Error(codes.Internal, err.Error())
 		}
 
+		wg.Add(1)
+		logwg.Add(1)
+
 		go func() {
 			defer rd.Close()
 			defer wg.Done()
 			defer logwg.Done()
 
-			cutter := logcutter.DefaultCutter
+			cutter := srv.logCutter(job.GetMetadata().GetRepository())
 			if req.Logs == v1.ListenRequestLogs_LOGS_UNSLICED {
 				cutter = logcutter.NoCutter
 			}
 
-			evts, echan := cutter.Slice(rd)
+			// skipping to the offset of a running job's log waits for the job to get there
+			in, err := logRange(rd, req.ByteOffset, req.ByteLimit)
+			if err != nil {
+				errchan <- status.Error(codes.Internal, err.Error())
+				return
+			}
+
+			evts, echan := cutter.Slice(in)
+			var skipped int64
 			for {
 				select {
 				case evt := <-evts:
 					if evt == nil {
 						return
 					}
+					if req.Slice != "" && evt.Name != req.Slice {
+						continue
+					}
+					if skipped < req.Offset {
+						// the client has seen this slice already
+						skipped++
+						continue
+					}
 					if req.Logs == v1.ListenRequestLogs_LOGS_HTML {
 						evt.Payload = string(termtohtml.Render([]byte(evt.Payload)))
 					}
@@ -526,14 +746,18 @@ func (srv *Service) Listen(req *v1.ListenRequest, ls v1.WerftService_ListenServe
 				return
 			}
 
-			evts := srv.events.On("job")
-			for evt := range evts {
-				if len(evt.Args) == 0 {
+			sub := srv.subscribeJobs(ls.Context())
+			for {
+				job, err := sub.Next(ls.Context())
+				if err == errSlowSubscriber {
+					select {
+					case errchan <- status.Error(codes.ResourceExhausted, err.Error()):
+					case <-ls.Context().Done():
+					}
 					return
 				}
-				job, ok := evt.Args[0].(*v1.JobStatus)
-				if !ok {
-					continue
+				if err != nil {
+					return
 				}
 				if job.Name != req.Name {
 					continue
@@ -570,6 +794,18 @@ func (srv *Service) StopJob(ctx context.Context, req *v1.StopJobRequest) (*v1.St
 	if job.Phase != v1.JobPhase_PHASE_WAITING && job.Phase != v1.JobPhase_PHASE_PREPARING && job.Phase != v1.JobPhase_PHASE_STARTING && job.Phase != v1.JobPhase_PHASE_RUNNING {
 		return nil, status.Error(codes.FailedPrecondition, "job is in unstoppable phase")
 	}
+	srv.addJobEvent(job.Name, v1.JobEvent{Type: v1.JobEventType_EVENT_CANCEL_REQUESTED, Message: "job was stopped manually"})
+
+	if len(job.Metadata.Children) > 0 {
+		// matrix jobs don't run themselves - stopping them means stopping their children
+		for _, child := range job.Metadata.Children {
+			_, err = srv.StopJob(ctx, &v1.StopJobRequest{Name: child})
+			if err != nil && status.Code(err) != codes.FailedPrecondition {
+				return nil, err
+			}
+		}
+		return &v1.StopJobResponse{}, nil
+	}
 
 	err = srv.Executor.Stop(req.Name, "job was stopped manually")
 	if err != nil {
@@ -579,6 +815,130 @@ func (srv *Service) StopJob(ctx context.Context, req *v1.StopJobRequest) (*v1.St
 	return &v1.StopJobResponse{}, nil
 }
 
+// GetQueueStatus lists the jobs which wait to run, and why they are waiting
+func (srv *Service) GetQueueStatus(ctx context.Context, req *v1.GetQueueStatusRequest) (*v1.GetQueueStatusResponse, error) {
+	jobs, err := srv.Executor.Queue()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.GetQueueStatusResponse{Jobs: jobs}, nil
+}
+
+// SetMaintenanceMode pauses or resumes job processing. This requires an admin token.
+func (srv *Service) SetMaintenanceMode(ctx context.Context, req *v1.SetMaintenanceModeRequest) (*v1.SetMaintenanceModeResponse, error) {
+	if err := srv.requireAdmin(req.Token, "maintenance mode"); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if req.Enabled {
+		err = srv.Executor.Pause(req.Reason)
+	} else {
+		err = srv.Executor.Resume()
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	mode, err := maintenanceMode(srv.Executor.Maintenance())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	srv.publishEvent(&v1.ServerEvent{
+		Type:       serverEventMaintenance,
+		Message:    mode.Reason,
+		Attributes: map[string]string{"enabled": strconv.FormatBool(mode.Enabled)},
+	})
+	return &v1.SetMaintenanceModeResponse{Mode: mode}, nil
+}
+
+// GetMaintenanceMode returns whether job processing is paused
+func (srv *Service) GetMaintenanceMode(ctx context.Context, req *v1.GetMaintenanceModeRequest) (*v1.GetMaintenanceModeResponse, error) {
+	mode, err := maintenanceMode(srv.Executor.Maintenance())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.GetMaintenanceModeResponse{Mode: mode}, nil
+}
+
+func maintenanceMode(m executor.Maintenance) (*v1.MaintenanceMode, error) {
+	res := &v1.MaintenanceMode{Enabled: m.Enabled, Reason: m.Reason}
+	if m.Enabled {
+		since, err := ptypes.TimestampProto(m.Since)
+		if err != nil {
+			return nil, err
+		}
+		res.Since = since
+	}
+	return res, nil
+}
+
+// ListDeadLetters lists webhook events which failed processing. The events may contain private data, hence
+// listing them requires an admin token.
+func (srv *Service) ListDeadLetters(ctx context.Context, req *v1.ListDeadLettersRequest) (*v1.ListDeadLettersResponse, error) {
+	if srv.DeadLetters == nil {
+		return nil, status.Error(codes.Unavailable, "dead letter queue is not configured")
+	}
+	if err := srv.requireAdmin(req.Token, "dead letter queue access"); err != nil {
+		return nil, err
+	}
+
+	letters, err := srv.DeadLetters.List(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	res := make([]*v1.DeadLetter, len(letters))
+	for i := range letters {
+		res[i] = &letters[i]
+	}
+	return &v1.ListDeadLettersResponse{Result: res}, nil
+}
+
+// ReplayDeadLetter processes a failed webhook event again. Replaying requires an admin token.
+func (srv *Service) ReplayDeadLetter(ctx context.Context, req *v1.ReplayDeadLetterRequest) (*v1.ReplayDeadLetterResponse, error) {
+	if srv.DeadLetters == nil {
+		return nil, status.Error(codes.Unavailable, "dead letter queue is not configured")
+	}
+	if err := srv.requireAdmin(req.Token, "dead letter queue access"); err != nil {
+		return nil, err
+	}
+
+	dl, err := srv.DeadLetters.Get(ctx, req.Id)
+	if err == store.ErrNotFound {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	logger := log.WithField("delivery", dl.Id).WithField("replay", true)
+	received, _ := ptypes.Timestamp(dl.Received)
+	replayCtx := withTriggerPayload(ctx, triggerSourceGitHub, dl.EventType, dl.Id, received, dl.Payload)
+	if len(dl.FailedJobs) > 0 {
+		replayCtx = withReplayJobs(replayCtx, dl.FailedJobs)
+	}
+	_, err = srv.processGitHubEvent(replayCtx, logger, dl.EventType, dl.Payload)
+	if err != nil {
+		srv.putDeadLetter(ctx, logger, dl.Id, dl.EventType, dl.Payload, err)
+		return nil, status.Errorf(codes.Aborted, "replay failed: %v", err)
+	}
+
+	err = srv.DeadLetters.Delete(ctx, dl.Id)
+	if err != nil && err != store.ErrNotFound {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.ReplayDeadLetterResponse{}, nil
+}
+
+// GetVersion returns the version of this werft server
+func (srv *Service) GetVersion(ctx context.Context, req *v1.GetVersionRequest) (*v1.GetVersionResponse, error) {
+	if srv.Version == nil {
+		return &v1.GetVersionResponse{Version: "unknown", ApiVersion: v1.APIVersion}, nil
+	}
+	return srv.Version, nil
+}
+
 func fixedOAuthTokenGitCreds(tkn string) GitCredentialHelper {
 	return func(ctx context.Context) (user string, pass string, err error) {
 		return tkn, "x-oauth-basic", nil
@@ -0,0 +1,259 @@
+package werft_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListJobsView(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	err := jobs.Store(context.Background(), v1.JobStatus{
+		Name:  "foo.1",
+		Phase: v1.JobPhase_PHASE_DONE,
+		Metadata: &v1.JobMetadata{
+			Owner:       "someone",
+			Repository:  &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"},
+			Created:     ptypes.TimestampNow(),
+			Annotations: []*v1.Annotation{&v1.Annotation{Key: "foo", Value: "bar"}},
+		},
+		Conditions: &v1.JobConditions{Success: true, FailureCount: 1},
+		Details:    "some details",
+		Results:    []*v1.JobResult{&v1.JobResult{Type: "url", Payload: "https://werft.dev"}},
+		Notes:      []*v1.JobNote{&v1.JobNote{Author: "dev", Text: "failed because of a flaky registry"}},
+	})
+	if err != nil {
+		t.Fatalf("cannot store job: %v", err)
+	}
+	srv := &werft.Service{Jobs: jobs}
+
+	tests := []struct {
+		Name       string
+		View       v1.JobView
+		ExpectFull bool
+	}{
+		{"full view", v1.JobView_JOB_VIEW_FULL, true},
+		{"summary view", v1.JobView_JOB_VIEW_SUMMARY, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.ListJobs(context.Background(), &v1.ListJobsRequest{View: test.View})
+			if err != nil {
+				t.Fatalf("cannot list jobs: %v", err)
+			}
+			if len(resp.Result) != 1 {
+				t.Fatalf("expected one job, got %d", len(resp.Result))
+			}
+
+			job := resp.Result[0]
+			if job.Name != "foo.1" || job.Phase != v1.JobPhase_PHASE_DONE || !job.Conditions.Success {
+				t.Errorf("unexpected job: %v", job)
+			}
+			if job.Metadata.Repository.Ref != "refs/heads/master" || job.Metadata.Created == nil {
+				t.Errorf("job is missing its ref or time: %v", job.Metadata)
+			}
+			if len(job.Notes) != 1 {
+				t.Errorf("job is missing its notes: %v", job.Notes)
+			}
+
+			full := len(job.Results) > 0 && len(job.Metadata.Annotations) > 0 && job.Details != ""
+			if full != test.ExpectFull {
+				t.Errorf("expected full job: %v, got %v", test.ExpectFull, job)
+			}
+		})
+	}
+}
+
+func TestGetJobEstimate(t *testing.T) {
+	ts := func(t time.Time) *timestamp.Timestamp {
+		res, _ := ptypes.TimestampProto(t)
+		return res
+	}
+	job := func(name string, phase v1.JobPhase, success bool, created time.Time, duration time.Duration) v1.JobStatus {
+		md := &v1.JobMetadata{Repository: &v1.Repository{}, Created: ts(created)}
+		if phase == v1.JobPhase_PHASE_DONE {
+			md.Finished = ts(created.Add(duration))
+		}
+		return v1.JobStatus{Name: name, Phase: phase, Metadata: md, Conditions: &v1.JobConditions{Success: success, DidExecute: true}}
+	}
+	now := time.Now()
+
+	tests := []struct {
+		Name        string
+		History     []v1.JobStatus
+		Job         v1.JobStatus
+		Expectation *v1.DurationEstimate
+	}{
+		{
+			Name: "running",
+			History: []v1.JobStatus{
+				job("foo.1", v1.JobPhase_PHASE_DONE, true, now.Add(-4*time.Hour), 2*time.Minute),
+				job("foo.2", v1.JobPhase_PHASE_DONE, true, now.Add(-3*time.Hour), 4*time.Minute),
+				job("foo.3", v1.JobPhase_PHASE_DONE, false, now.Add(-2*time.Hour), 10*time.Second),
+				job("foo.4", v1.JobPhase_PHASE_DONE, true, now.Add(-1*time.Hour), 10*time.Minute),
+				job("foo-other.1", v1.JobPhase_PHASE_DONE, true, now.Add(-1*time.Hour), time.Hour),
+			},
+			Job:         job("foo.5", v1.JobPhase_PHASE_RUNNING, true, now.Add(-1*time.Minute), 0),
+			Expectation: &v1.DurationEstimate{P50Seconds: 240, P95Seconds: 600, Samples: 3},
+		},
+		{
+			Name: "slower than usual",
+			History: []v1.JobStatus{
+				job("foo.1", v1.JobPhase_PHASE_DONE, true, now.Add(-4*time.Hour), 2*time.Minute),
+				job("foo.2", v1.JobPhase_PHASE_DONE, true, now.Add(-3*time.Hour), 4*time.Minute),
+				job("foo.3", v1.JobPhase_PHASE_DONE, true, now.Add(-1*time.Hour), 10*time.Minute),
+			},
+			Job:         job("foo.4", v1.JobPhase_PHASE_RUNNING, true, now.Add(-5*time.Minute), 0),
+			Expectation: &v1.DurationEstimate{P50Seconds: 240, P95Seconds: 600, Samples: 3},
+		},
+		{
+			Name: "too few runs",
+			History: []v1.JobStatus{
+				job("foo.1", v1.JobPhase_PHASE_DONE, true, now.Add(-4*time.Hour), 2*time.Minute),
+			},
+			Job: job("foo.2", v1.JobPhase_PHASE_RUNNING, true, now.Add(-1*time.Minute), 0),
+		},
+		{
+			Name: "done",
+			History: []v1.JobStatus{
+				job("foo.1", v1.JobPhase_PHASE_DONE, true, now.Add(-4*time.Hour), 2*time.Minute),
+				job("foo.2", v1.JobPhase_PHASE_DONE, true, now.Add(-3*time.Hour), 4*time.Minute),
+				job("foo.3", v1.JobPhase_PHASE_DONE, true, now.Add(-1*time.Hour), 10*time.Minute),
+			},
+			Job: job("foo.4", v1.JobPhase_PHASE_DONE, true, now.Add(-5*time.Minute), time.Minute),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			jobs := store.NewInMemoryJobStore()
+			for _, j := range append(test.History, test.Job) {
+				err := jobs.Store(context.Background(), j)
+				if err != nil {
+					t.Fatalf("cannot store job: %v", err)
+				}
+			}
+			srv := &werft.Service{Jobs: jobs}
+
+			resp, err := srv.GetJob(context.Background(), &v1.GetJobRequest{Name: test.Job.Name})
+			if err != nil {
+				t.Fatalf("cannot get job: %v", err)
+			}
+			act := resp.Result.Estimate
+			if test.Expectation == nil {
+				if act != nil {
+					t.Errorf("expected no estimate, got %v", act)
+				}
+				return
+			}
+			if act == nil {
+				t.Fatalf("expected an estimate, got none")
+			}
+			if act.P50Seconds != test.Expectation.P50Seconds || act.P95Seconds != test.Expectation.P95Seconds || act.Samples != test.Expectation.Samples {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+			completion, _ := ptypes.Timestamp(act.Completion)
+			if !completion.After(now) {
+				t.Errorf("expected completion in the future, got %v", completion)
+			}
+		})
+	}
+}
+
+type fakeListenServer struct {
+	grpc.ServerStream
+	Slices []string
+}
+
+func (s *fakeListenServer) Context() context.Context { return context.Background() }
+
+func (s *fakeListenServer) Send(resp *v1.ListenResponse) error {
+	if slice := resp.GetSlice(); slice != nil {
+		s.Slices = append(s.Slices, fmt.Sprintf("%s %s %q", slice.Name, slice.Type, slice.Payload))
+	}
+	return nil
+}
+
+func TestListenPartialLogs(t *testing.T) {
+	const content = "[build|PHASE] building\n[build] hello\n[test] world\n[build] bye\n"
+	logs := store.NewInMemoryLogStore()
+	w, err := logs.Open("foo.1")
+	if err != nil {
+		t.Fatalf("cannot open log: %v", err)
+	}
+	w.Write([]byte(content))
+	w.Close()
+	jobs := store.NewInMemoryJobStore()
+	err = jobs.Store(context.Background(), v1.JobStatus{Name: "foo.1", Phase: v1.JobPhase_PHASE_DONE, Metadata: &v1.JobMetadata{}})
+	if err != nil {
+		t.Fatalf("cannot store job: %v", err)
+	}
+	srv := &werft.Service{Logs: logs, Jobs: jobs}
+
+	tests := []struct {
+		Name        string
+		Request     *v1.ListenRequest
+		Expectation []string
+		Code        codes.Code
+	}{
+		{
+			Name:    "slice",
+			Request: &v1.ListenRequest{Slice: "test"},
+			Expectation: []string{
+				`test SLICE_START ""`,
+				`test SLICE_CONTENT "world"`,
+				`test SLICE_ABANDONED ""`,
+			},
+		},
+		{
+			Name:    "byte range",
+			Request: &v1.ListenRequest{ByteOffset: 23, ByteLimit: 27},
+			Expectation: []string{
+				`build SLICE_START ""`,
+				`build SLICE_CONTENT "hello"`,
+				`build SLICE_ABANDONED ""`,
+				`test SLICE_START ""`,
+				`test SLICE_CONTENT "world"`,
+				`test SLICE_ABANDONED ""`,
+			},
+		},
+		{
+			Name:    "offset beyond log",
+			Request: &v1.ListenRequest{ByteOffset: int64(len(content) + 10)},
+		},
+		{
+			Name:    "negative offset",
+			Request: &v1.ListenRequest{ByteOffset: -1},
+			Code:    codes.InvalidArgument,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			test.Request.Name = "foo.1"
+			test.Request.Logs = v1.ListenRequestLogs_LOGS_RAW
+			ls := &fakeListenServer{}
+			err := srv.Listen(test.Request, ls)
+			if status.Code(err) != test.Code {
+				t.Fatalf("expected %v, got %v", test.Code, err)
+			}
+
+			// slices which aren't done are abandoned in no particular order
+			sort.Strings(ls.Slices)
+			sort.Strings(test.Expectation)
+			if !reflect.DeepEqual(ls.Slices, test.Expectation) {
+				t.Errorf("unexpected slices: %q, expected %q", ls.Slices, test.Expectation)
+			}
+		})
+	}
+}
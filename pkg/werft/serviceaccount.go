@@ -0,0 +1,124 @@
+package werft
+
+import (
+	"path"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultServiceAccountClass is the service account class used by jobs which don't request one
+const defaultServiceAccountClass = "default"
+
+// ServiceAccountConfig makes a Kubernetes service account available to jobs. Jobs request a service account
+// by the name of its class (e.g. deployer) and get it only if the policy of the class allows it.
+type ServiceAccountConfig struct {
+	// Name is the class jobs request in their spec, e.g. deployer. The "default" class is used by all jobs which don't request a class.
+	Name string `yaml:"name"`
+
+	// ServiceAccount is the Kubernetes service account in the executor's namespace jobs of this class run with
+	ServiceAccount string `yaml:"serviceAccount"`
+
+	// Repositories limits the class to jobs of these repositories, given as host/owner/repo or owner/repo. Supports globs.
+	// If empty, jobs of all repositories can use this class.
+	Repositories []string `yaml:"repositories,omitempty"`
+
+	// Refs limits the class to jobs running on these refs, e.g. refs/heads/master. Supports globs.
+	// If empty, jobs on all refs can use this class.
+	Refs []string `yaml:"refs,omitempty"`
+}
+
+// Allows returns true if a job may use this service account class. Classes limited to repositories or refs
+// are only available to jobs whose source werft verified.
+func (c ServiceAccountConfig) Allows(md *v1.JobMetadata) bool {
+	return policyAllowsJob(c.Repositories, c.Refs, md)
+}
+
+// policyAllows returns true if a job running on the repo is matched by the repository and ref globs.
+// Empty lists match all repositories or refs.
+func policyAllows(repositories, refs []string, repo *v1.Repository) bool {
+	if repo == nil {
+		return len(repositories) == 0 && len(refs) == 0
+	}
+
+	if len(repositories) > 0 {
+		var ok bool
+		for _, r := range repositories {
+			if (RepositoryConfig{Repo: r}).matches(repo) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if len(refs) > 0 {
+		var ok bool
+		for _, r := range refs {
+			if m, _ := path.Match(r, repo.Ref); m {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// policyAllowsJob is policyAllows for a job. Policies which limit the repositories or refs allow only jobs whose source
+// werft verified, because the repository and ref of any other job (e.g. a local one) are whatever its creator claimed.
+func policyAllowsJob(repositories, refs []string, md *v1.JobMetadata) bool {
+	if (len(repositories) > 0 || len(refs) > 0) && !md.GetSourceVerified() {
+		return false
+	}
+	return policyAllows(repositories, refs, md.GetRepository())
+}
+
+// applyServiceAccount sets the service account of a job's pod based on the class requested in its spec.
+// If no service account classes are configured, the pod spec is left as is.
+func (srv *Service) applyServiceAccount(md *v1.JobMetadata, jobspec *repoconfig.JobSpec, podspec *corev1.PodSpec) error {
+	if len(srv.Config.ServiceAccounts) == 0 {
+		if jobspec.ServiceAccount != "" {
+			return xerrors.Errorf("job requests service account %s, but no service accounts are configured", jobspec.ServiceAccount)
+		}
+		return nil
+	}
+
+	if podspec.ServiceAccountName != "" || podspec.DeprecatedServiceAccount != "" {
+		return xerrors.Errorf("jobs must request a service account using serviceAccount rather than setting it on the pod")
+	}
+
+	class := jobspec.ServiceAccount
+	if class == "" {
+		class = defaultServiceAccountClass
+	}
+	var cfg *ServiceAccountConfig
+	for i, c := range srv.Config.ServiceAccounts {
+		if c.Name == class {
+			cfg = &srv.Config.ServiceAccounts[i]
+			break
+		}
+	}
+	if cfg == nil && jobspec.ServiceAccount != "" {
+		return xerrors.Errorf("unknown service account %s", class)
+	}
+	if cfg == nil {
+		// without a default class jobs don't get to talk to Kubernetes at all
+		automount := false
+		podspec.AutomountServiceAccountToken = &automount
+		return nil
+	}
+
+	if !cfg.Allows(md) {
+		return xerrors.Errorf("service account %s is not available to jobs of this repository or ref", class)
+	}
+	podspec.ServiceAccountName = cfg.ServiceAccount
+	return nil
+}
@@ -0,0 +1,45 @@
+package werft_test
+
+import (
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestServiceAccountConfigAllows(t *testing.T) {
+	var (
+		repo       = &v1.Repository{Host: "github.com", Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"}
+		verified   = &v1.JobMetadata{Repository: repo, SourceVerified: true}
+		unverified = &v1.JobMetadata{Repository: repo}
+		local      = &v1.JobMetadata{Repository: repo, Trigger: v1.JobTrigger_TRIGGER_MANUAL}
+	)
+
+	tests := []struct {
+		Name        string
+		Config      werft.ServiceAccountConfig
+		Metadata    *v1.JobMetadata
+		Expectation bool
+	}{
+		{"no policy", werft.ServiceAccountConfig{}, verified, true},
+		{"no policy without repo", werft.ServiceAccountConfig{}, &v1.JobMetadata{}, true},
+		{"no policy unverified", werft.ServiceAccountConfig{}, unverified, true},
+		{"repo match", werft.ServiceAccountConfig{Repositories: []string{"32leaves/werft"}}, verified, true},
+		{"repo glob", werft.ServiceAccountConfig{Repositories: []string{"github.com/32leaves/*"}}, verified, true},
+		{"repo mismatch", werft.ServiceAccountConfig{Repositories: []string{"32leaves/other"}}, verified, false},
+		{"ref match", werft.ServiceAccountConfig{Refs: []string{"refs/heads/master"}}, verified, true},
+		{"ref glob", werft.ServiceAccountConfig{Refs: []string{"refs/tags/*"}}, verified, false},
+		{"repo and ref", werft.ServiceAccountConfig{Repositories: []string{"32leaves/werft"}, Refs: []string{"refs/tags/*"}}, verified, false},
+		{"policy without repo", werft.ServiceAccountConfig{Refs: []string{"refs/heads/master"}}, &v1.JobMetadata{SourceVerified: true}, false},
+		{"repo match unverified", werft.ServiceAccountConfig{Repositories: []string{"32leaves/werft"}}, unverified, false},
+		{"ref match local job", werft.ServiceAccountConfig{Refs: []string{"refs/heads/master"}}, local, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act := test.Config.Allows(test.Metadata)
+			if act != test.Expectation {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package werft
+
+import (
+	"context"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/google/go-github/github"
+)
+
+type sourceVerifiedKey struct{}
+
+// withSourceVerified tells RunJob whether werft made sure the job's ref pointed to its revision and its job spec came
+// from the repository. Only werft itself can put this into a context - whatever the metadata of a request claims,
+// RunJob only believes the context.
+func withSourceVerified(ctx context.Context, verified bool) context.Context {
+	return context.WithValue(ctx, sourceVerifiedKey{}, verified)
+}
+
+// sourceVerified returns true if the source of jobs started in this context is verified
+func sourceVerified(ctx context.Context) bool {
+	verified, _ := ctx.Value(sourceVerifiedKey{}).(bool)
+	return verified
+}
+
+// refPointsTo asks GitHub whether the ref of a repository currently points to its revision
+func refPointsTo(ctx context.Context, ghclient *github.Client, repo *v1.Repository) bool {
+	if repo == nil || repo.Ref == "" || repo.Revision == "" {
+		return false
+	}
+	sha, _, err := ghclient.Repositories.GetCommitSHA1(ctx, repo.Owner, repo.Repo, repo.Ref, "")
+	if err != nil {
+		return false
+	}
+	return sha == repo.Revision
+}
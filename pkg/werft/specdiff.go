@@ -0,0 +1,79 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/pmezard/go-difflib/difflib"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	k8sjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+)
+
+// resolveSpec serializes the pod template of a job. The result is the same for the same pod template,
+// hence its hash identifies the spec a job ran with.
+func resolveSpec(podspec *corev1.PodSpec) ([]byte, error) {
+	var buf bytes.Buffer
+	err := k8sjson.NewYAMLSerializer(k8sjson.DefaultMetaFactory, nil, nil).Encode(&corev1.Pod{Spec: *podspec}, &buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// specHash computes the hash of a resolved job spec
+func specHash(spec []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(spec))
+}
+
+// DiffJobSpecs compares the resolved job specs two jobs ran with
+func (srv *Service) DiffJobSpecs(ctx context.Context, req *v1.DiffJobSpecsRequest) (*v1.DiffJobSpecsResponse, error) {
+	if req.From == "" || req.To == "" {
+		return nil, status.Error(codes.InvalidArgument, "from and to are required")
+	}
+
+	from, err := srv.resolvedSpec(req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := srv.resolvedSpec(req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &v1.DiffJobSpecsResponse{
+		FromHash: specHash(from),
+		ToHash:   specHash(to),
+	}
+	if res.FromHash == res.ToHash {
+		return res, nil
+	}
+
+	res.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: req.From,
+		ToFile:   req.To,
+		Context:  3,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return res, nil
+}
+
+func (srv *Service) resolvedSpec(name string) ([]byte, error) {
+	spec, err := srv.Jobs.GetResolvedSpec(name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "job %s has no recorded spec", name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return spec, nil
+}
@@ -0,0 +1,57 @@
+package werft_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDiffJobSpecs(t *testing.T) {
+	jobs := store.NewInMemoryJobStore()
+	jobs.StoreResolvedSpec("green.1", []byte("spec:\n  containers:\n  - image: golang:1.13\n    name: build\n"))
+	jobs.StoreResolvedSpec("green.2", []byte("spec:\n  containers:\n  - image: golang:1.13\n    name: build\n"))
+	jobs.StoreResolvedSpec("red.1", []byte("spec:\n  containers:\n  - image: golang:1.14\n    name: build\n"))
+	srv := &werft.Service{Jobs: jobs}
+
+	tests := []struct {
+		Name      string
+		From, To  string
+		Same      bool
+		Contains  []string
+		ErrorCode codes.Code
+	}{
+		{"same spec", "green.1", "green.2", true, nil, codes.OK},
+		{"changed spec", "green.1", "red.1", false, []string{"--- green.1", "+++ red.1", "-  - image: golang:1.13", "+  - image: golang:1.14"}, codes.OK},
+		{"unknown job", "green.1", "blue.1", false, nil, codes.NotFound},
+		{"missing job", "green.1", "", false, nil, codes.InvalidArgument},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			resp, err := srv.DiffJobSpecs(context.Background(), &v1.DiffJobSpecsRequest{From: test.From, To: test.To})
+			if code := status.Code(err); code != test.ErrorCode {
+				t.Fatalf("expected error code %v, got %v", test.ErrorCode, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if same := resp.FromHash == resp.ToHash; same != test.Same {
+				t.Errorf("expected same hash to be %v, got %s and %s", test.Same, resp.FromHash, resp.ToHash)
+			}
+			if test.Same && resp.Diff != "" {
+				t.Errorf("expected no diff, got %s", resp.Diff)
+			}
+			for _, c := range test.Contains {
+				if !strings.Contains(resp.Diff, c) {
+					t.Errorf("expected diff to contain %q, got\n%s", c, resp.Diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultStartLatencyJobs is the number of recent jobs start latency summaries consider if the request doesn't say otherwise
+	defaultStartLatencyJobs = 100
+
+	// maxStartLatencyJobs is the largest number of recent jobs start latency summaries consider
+	maxStartLatencyJobs = 1000
+)
+
+type webhookReceivedKey struct{}
+
+// withWebhookReceived marks a context as handling a webhook which was received at t, so that the jobs it starts
+// can tell how long it took them to run
+func withWebhookReceived(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, webhookReceivedKey{}, t)
+}
+
+// webhookReceived returns the time the webhook handled in this context was received at
+func webhookReceived(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(webhookReceivedKey{}).(time.Time)
+	return t, ok
+}
+
+// StartLatency computes the time a job took from the webhook which started it until its pod ran, based on the events
+// of the job. Jobs which were not started by a webhook count from their creation. Jobs which wait for their start time
+// (e.g. scheduled jobs or retries) count from that time. Returns false if the job's pod didn't run (yet).
+func StartLatency(events []v1.JobEvent, waitUntil *timestamp.Timestamp) (time.Duration, bool) {
+	var received, created, running time.Time
+	for _, evt := range events {
+		t, err := ptypes.Timestamp(evt.Time)
+		if err != nil {
+			continue
+		}
+		switch {
+		case evt.Type == v1.JobEventType_EVENT_WEBHOOK_RECEIVED && received.IsZero():
+			received = t
+		case evt.Type == v1.JobEventType_EVENT_CREATED && created.IsZero():
+			created = t
+		case evt.Type == v1.JobEventType_EVENT_PHASE_CHANGED && evt.Phase == v1.JobPhase_PHASE_RUNNING && running.IsZero():
+			running = t
+		}
+	}
+
+	start := received
+	if start.IsZero() {
+		start = created
+	}
+	if wait, err := ptypes.Timestamp(waitUntil); err == nil && wait.After(start) {
+		start = wait
+	}
+	if start.IsZero() || running.IsZero() || running.Before(start) {
+		return 0, false
+	}
+	return running.Sub(start), true
+}
+
+// newStartLatencyMetrics produces the histogram of the start latency of jobs
+func newStartLatencyMetrics() *prometheus.HistogramVec {
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "job_start_latency_seconds",
+		Help:    "Time jobs took from the webhook which started them (or their creation) until their pod ran, by repository.",
+		Buckets: []float64{1, 2, 5, 10, 20, 30, 60, 120, 300, 600, 1800},
+	}, []string{"repo"})
+}
+
+// recordStartLatency observes the start latency of a job whose pod just started running
+func (srv *Service) recordStartLatency(s *v1.JobStatus) {
+	if srv.startLatency == nil {
+		return
+	}
+	events, err := srv.Jobs.GetEvents(context.Background(), s.Name)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).Warn("cannot determine start latency")
+		return
+	}
+	latency, ok := StartLatency(events, s.Conditions.GetWaitUntil())
+	if !ok {
+		return
+	}
+	repo := s.Metadata.GetRepository()
+	srv.startLatency.WithLabelValues(fmt.Sprintf("%s/%s", repo.GetOwner(), repo.GetRepo())).Observe(latency.Seconds())
+}
+
+// GetStartLatency returns the time recent jobs took from the webhook which started them until their pod ran
+func (srv *Service) GetStartLatency(ctx context.Context, req *v1.GetStartLatencyRequest) (*v1.GetStartLatencyResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = defaultStartLatencyJobs
+	}
+	if limit > maxStartLatencyJobs {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must not exceed %d", maxStartLatencyJobs)
+	}
+
+	var filter []*v1.FilterExpression
+	if repo := req.Repository; repo != nil {
+		for _, t := range []struct{ Field, Value string }{{"repo.host", repo.Host}, {"repo.owner", repo.Owner}, {"repo.repo", repo.Repo}} {
+			if t.Value == "" {
+				continue
+			}
+			filter = append(filter, &v1.FilterExpression{Terms: []*v1.FilterTerm{{Field: t.Field, Value: t.Value, Operation: v1.FilterOp_OP_EQUALS}}})
+		}
+	}
+	order := []*v1.OrderExpression{{Field: "created", Ascending: false}}
+	ctx = store.WithStaleReads(ctx)
+	jobs, _, err := srv.Jobs.Find(ctx, filter, order, 0, limit)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var (
+		res       v1.GetStartLatencyResponse
+		latencies = make(map[string][]time.Duration)
+		repos     = make(map[string]*v1.Repository)
+	)
+	for _, job := range jobs {
+		if job.Phase == v1.JobPhase_PHASE_WAITING || job.Phase == v1.JobPhase_PHASE_PREPARING || len(job.GetMetadata().GetChildren()) > 0 {
+			continue
+		}
+		events, err := srv.Jobs.GetEvents(ctx, job.Name)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		latency, ok := StartLatency(events, job.Conditions.GetWaitUntil())
+		if !ok {
+			continue
+		}
+
+		r := job.Metadata.GetRepository()
+		repo := &v1.Repository{Host: r.GetHost(), Owner: r.GetOwner(), Repo: r.GetRepo()}
+		key := repoKey(repo)
+		latencies[key] = append(latencies[key], latency)
+		repos[key] = repo
+		res.Jobs = append(res.Jobs, &v1.JobStartLatency{
+			Name:           job.Name,
+			Repository:     repo,
+			LatencySeconds: latency.Seconds(),
+		})
+	}
+
+	for key, ls := range latencies {
+		sort.Slice(ls, func(i, j int) bool { return ls[i] < ls[j] })
+		res.Repositories = append(res.Repositories, &v1.RepositoryStartLatency{
+			Repository: repos[key],
+			Samples:    int32(len(ls)),
+			P50Seconds: percentile(ls, 50).Seconds(),
+			P90Seconds: percentile(ls, 90).Seconds(),
+			P99Seconds: percentile(ls, 99).Seconds(),
+			MaxSeconds: ls[len(ls)-1].Seconds(),
+		})
+	}
+	sort.Slice(res.Repositories, func(i, j int) bool {
+		return repoKey(res.Repositories[i].Repository) < repoKey(res.Repositories[j].Repository)
+	})
+	return &res, nil
+}
@@ -0,0 +1,46 @@
+package werft_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+func TestStartLatency(t *testing.T) {
+	at := func(sec int64) *timestamp.Timestamp { return &timestamp.Timestamp{Seconds: sec} }
+	var (
+		received = v1.JobEvent{Type: v1.JobEventType_EVENT_WEBHOOK_RECEIVED, Time: at(100)}
+		created  = v1.JobEvent{Type: v1.JobEventType_EVENT_CREATED, Time: at(103)}
+		starting = v1.JobEvent{Type: v1.JobEventType_EVENT_PHASE_CHANGED, Phase: v1.JobPhase_PHASE_STARTING, Time: at(104)}
+		running  = v1.JobEvent{Type: v1.JobEventType_EVENT_PHASE_CHANGED, Phase: v1.JobPhase_PHASE_RUNNING, Time: at(130)}
+		done     = v1.JobEvent{Type: v1.JobEventType_EVENT_PHASE_CHANGED, Phase: v1.JobPhase_PHASE_DONE, Time: at(200)}
+	)
+
+	tests := []struct {
+		Name        string
+		Events      []v1.JobEvent
+		WaitUntil   *timestamp.Timestamp
+		Expectation time.Duration
+		OK          bool
+	}{
+		{"webhook", []v1.JobEvent{received, created, starting, running, done}, nil, 30 * time.Second, true},
+		{"no webhook", []v1.JobEvent{created, starting, running, done}, nil, 27 * time.Second, true},
+		{"scheduled", []v1.JobEvent{received, created, starting, running}, at(120), 10 * time.Second, true},
+		{"not running yet", []v1.JobEvent{received, created, starting}, nil, 0, false},
+		{"no events", nil, nil, 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			latency, ok := werft.StartLatency(test.Events, test.WaitUntil)
+			if ok != test.OK {
+				t.Fatalf("expected ok to be %v, got %v", test.OK, ok)
+			}
+			if latency != test.Expectation {
+				t.Errorf("expected %v, got %v", test.Expectation, latency)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package werft
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// durationStatsSamples is the number of recent successful runs duration statistics are computed from
+	durationStatsSamples = 50
+
+	// durationStatsMinSamples is the number of successful runs we need to have seen before we estimate durations
+	durationStatsMinSamples = 3
+
+	// durationStatsTTL is the time we keep duration statistics before computing them again
+	durationStatsTTL = 5 * time.Minute
+)
+
+// numberedJobName matches job names of the form <group>.<number>, e.g. werft-build-master.12
+var numberedJobName = regexp.MustCompile(`^(.+)\.\d+$`)
+
+// jobGroup returns the name of a job without its number, e.g. werft-build-master for werft-build-master.12.
+// Jobs which aren't numbered, e.g. matrix children, don't belong to a group.
+func jobGroup(name string) string {
+	m := numberedJobName.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// durationStats describes how long recent successful runs of a job group took
+type durationStats struct {
+	P50      time.Duration
+	P95      time.Duration
+	Samples  int
+	Computed time.Time
+}
+
+// durationStats computes the statistics of a job group from the job store, or returns them from the cache
+func (srv *Service) durationStats(ctx context.Context, group string) (*durationStats, error) {
+	srv.statsMu.Lock()
+	if s, ok := srv.stats[group]; ok && srv.since(s.Computed) < durationStatsTTL {
+		srv.statsMu.Unlock()
+		return s, nil
+	}
+	srv.statsMu.Unlock()
+
+	jobs, _, err := srv.Jobs.Find(store.WithStaleReads(ctx), []*v1.FilterExpression{
+		&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "name", Value: group + ".", Operation: v1.FilterOp_OP_STARTS_WITH}}},
+		&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "phase", Value: "done", Operation: v1.FilterOp_OP_EQUALS}}},
+	}, []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: false}}, 0, 2*durationStatsSamples)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot compute duration statistics of %s: %w", group, err)
+	}
+
+	var durations []time.Duration
+	for _, j := range jobs {
+		if len(durations) == durationStatsSamples {
+			break
+		}
+		if jobGroup(j.Name) != group || !j.Conditions.GetSuccess() || !j.Conditions.GetDidExecute() || j.Conditions.GetSkipped() {
+			continue
+		}
+		created, err := ptypes.Timestamp(j.Metadata.GetCreated())
+		if err != nil {
+			continue
+		}
+		finished, err := ptypes.Timestamp(j.Metadata.GetFinished())
+		if err != nil {
+			continue
+		}
+		durations = append(durations, finished.Sub(created))
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	s := &durationStats{Samples: len(durations), Computed: srv.now()}
+	if len(durations) > 0 {
+		s.P50 = percentile(durations, 50)
+		s.P95 = percentile(durations, 95)
+	}
+
+	srv.statsMu.Lock()
+	if srv.stats == nil {
+		srv.stats = make(map[string]*durationStats)
+	}
+	srv.stats[group] = s
+	srv.statsMu.Unlock()
+	return s, nil
+}
+
+// percentile returns the p-th percentile of sorted durations using the nearest-rank method
+func percentile(sorted []time.Duration, p int) time.Duration {
+	idx := (p*len(sorted)+99)/100 - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+// remainingTime describes the time until a job is expected to be done, e.g. "~4 min remaining"
+func remainingTime(est *v1.DurationEstimate) string {
+	completion, err := ptypes.Timestamp(est.Completion)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("~%d min remaining", int(math.Ceil(time.Until(completion).Minutes())))
+}
+
+// estimateDuration estimates when a running job is done. Jobs which already take longer than the median are expected
+// to finish by the 95th percentile. Returns nil if there's no sensible estimate.
+func (srv *Service) estimateDuration(ctx context.Context, job *v1.JobStatus) *v1.DurationEstimate {
+	if job.Phase != v1.JobPhase_PHASE_RUNNING || srv.Jobs == nil {
+		return nil
+	}
+	group := jobGroup(job.Name)
+	if group == "" {
+		return nil
+	}
+	created, err := ptypes.Timestamp(job.Metadata.GetCreated())
+	if err != nil {
+		return nil
+	}
+
+	stats, err := srv.durationStats(ctx, group)
+	if err != nil {
+		log.WithError(err).WithField("name", job.Name).Warn("cannot estimate job duration")
+		return nil
+	}
+	if stats.Samples < durationStatsMinSamples {
+		return nil
+	}
+
+	now := srv.now()
+	for _, d := range []time.Duration{stats.P50, stats.P95} {
+		completion := created.Add(d)
+		if !completion.After(now) {
+			continue
+		}
+		ts, err := ptypes.TimestampProto(completion)
+		if err != nil {
+			return nil
+		}
+		return &v1.DurationEstimate{
+			Completion: ts,
+			P50Seconds: int64(stats.P50.Seconds()),
+			P95Seconds: int64(stats.P95.Seconds()),
+			Samples:    int32(stats.Samples),
+		}
+	}
+	return nil
+}
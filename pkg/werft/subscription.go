@@ -0,0 +1,110 @@
+package werft
+
+import (
+	"context"
+	"sync"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"golang.org/x/xerrors"
+)
+
+// maxPendingUpdates is the number of jobs with updates a subscriber may fall behind on before we drop it
+const maxPendingUpdates = 1000
+
+// errSlowSubscriber is returned to subscribers which cannot keep up with job updates
+var errSlowSubscriber = xerrors.Errorf("subscriber cannot keep up with job updates")
+
+// jobSubscription delivers job updates to a single subscriber, e.g. a gRPC client. Job updates are taken from the
+// event emitter as soon as they're emitted, so that a slow subscriber never holds up everyone else. Updates of a job
+// the subscriber hasn't received yet are replaced by newer ones.
+type jobSubscription struct {
+	mu      sync.Mutex
+	pending map[string]*v1.JobStatus
+	order   []string
+	err     error
+	notify  chan struct{}
+}
+
+// subscribeJobs subscribes to job updates until ctx is done
+func (srv *Service) subscribeJobs(ctx context.Context) *jobSubscription {
+	sub := &jobSubscription{
+		pending: make(map[string]*v1.JobStatus),
+		notify:  make(chan struct{}, 1),
+	}
+
+	evts := srv.events.On("job")
+	go func() {
+		done := ctx.Done()
+		for {
+			select {
+			case evt, ok := <-evts:
+				if !ok {
+					return
+				}
+				if len(evt.Args) == 0 {
+					continue
+				}
+				if job, ok := evt.Args[0].(*v1.JobStatus); ok {
+					sub.push(job)
+				}
+			case <-done:
+				// Off waits for pending events to be delivered, hence we must keep draining until it closes evts
+				done = nil
+				go srv.events.Off("job", evts)
+			}
+		}
+	}()
+
+	return sub
+}
+
+func (sub *jobSubscription) push(job *v1.JobStatus) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.err != nil {
+		return
+	}
+	if _, exists := sub.pending[job.Name]; !exists {
+		if len(sub.order) >= maxPendingUpdates {
+			sub.err = errSlowSubscriber
+			sub.pending, sub.order = nil, nil
+		} else {
+			sub.order = append(sub.order, job.Name)
+			sub.pending[job.Name] = job
+		}
+	} else {
+		sub.pending[job.Name] = job
+	}
+
+	select {
+	case sub.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Next returns the next job update. It blocks until there is one or ctx is done.
+func (sub *jobSubscription) Next(ctx context.Context) (*v1.JobStatus, error) {
+	for {
+		sub.mu.Lock()
+		if sub.err != nil {
+			sub.mu.Unlock()
+			return nil, sub.err
+		}
+		if len(sub.order) > 0 {
+			name := sub.order[0]
+			sub.order = sub.order[1:]
+			job := sub.pending[name]
+			delete(sub.pending, name)
+			sub.mu.Unlock()
+			return job, nil
+		}
+		sub.mu.Unlock()
+
+		select {
+		case <-sub.notify:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
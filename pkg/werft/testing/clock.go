@@ -0,0 +1,85 @@
+// Package testing helps to test code which embeds the werft service. It provides a clock, IDs and tokens under the control
+// of tests, and a harness which runs the service against in-memory stores and the fake executor.
+package testing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FakeClock is a clock which only moves when it's told to
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock creates a clock which stands at t
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now returns the time the clock stands at
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set sets the clock to t
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// Advance moves the clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// SequentialIDs produces the IDs prefix-1, prefix-2 and so on
+type SequentialIDs struct {
+	// Prefix is the part all IDs start with. Defaults to id.
+	Prefix string
+
+	mu sync.Mutex
+	n  int
+}
+
+// NewID returns the next ID
+func (s *SequentialIDs) NewID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.n++
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = "id"
+	}
+	return fmt.Sprintf("%s-%d", prefix, s.n)
+}
+
+// SequentialTokens produces the tokens prefix-1, prefix-2 and so on, e.g. as session IDs of the web UI
+type SequentialTokens struct {
+	// Prefix is the part all tokens start with. Defaults to token.
+	Prefix string
+
+	mu sync.Mutex
+	n  int
+}
+
+// NewToken returns the next token
+func (s *SequentialTokens) NewToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.n++
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = "token"
+	}
+	return fmt.Sprintf("%s-%d", prefix, s.n), nil
+}
@@ -0,0 +1,114 @@
+package testing
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/werft"
+	"golang.org/x/xerrors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultStart is the time the clock of a harness stands at when the harness starts
+var DefaultStart = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// pollInterval is the time between two checks whether a job is done
+const pollInterval = 10 * time.Millisecond
+
+// Harness runs a werft service against in-memory stores and the fake executor, whose jobs finish right away.
+// The service's clock stands still and its IDs are sequential, so that job names and times are the same in
+// every run of a test.
+type Harness struct {
+	Service *werft.Service
+	Clock   *FakeClock
+	IDs     *SequentialIDs
+}
+
+// NewHarness starts a service with config. Close the harness once the test is done.
+func NewHarness(config werft.Config) (*Harness, error) {
+	exec, err := executor.NewFakeExecutor(executor.Config{
+		Namespace:       "werft",
+		JobPrepTimeout:  &executor.Duration{Duration: time.Minute},
+		JobTotalTimeout: &executor.Duration{Duration: time.Hour},
+		Fake: &executor.FakeConfig{
+			Duration: &executor.Duration{},
+			LogLines: 1,
+		},
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("cannot create fake executor: %w", err)
+	}
+	if config.JobStatusBatchWindow == nil {
+		// tests want to see job updates right away
+		config.JobStatusBatchWindow = &executor.Duration{}
+	}
+
+	h := &Harness{
+		Clock: NewFakeClock(DefaultStart),
+		IDs:   &SequentialIDs{},
+	}
+	h.Service, err = werft.New(
+		werft.WithConfig(config),
+		werft.WithExecutor(exec),
+		werft.WithClock(h.Clock),
+		werft.WithIDs(h.IDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = h.Service.Start(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Close stops the service
+func (h *Harness) Close() error {
+	return h.Service.Stop(context.Background())
+}
+
+// RunJob starts a job from a job spec. Its workspace is empty.
+func (h *Harness) RunJob(ctx context.Context, name string, md v1.JobMetadata, jobYAML string) (*v1.JobStatus, error) {
+	return h.Service.RunJob(ctx, name, md, EmptyContent{}, []byte(jobYAML), false, time.Time{})
+}
+
+// WaitForJob waits until a job is done and returns its final status
+func (h *Harness) WaitForJob(ctx context.Context, name string) (*v1.JobStatus, error) {
+	tick := time.NewTicker(pollInterval)
+	defer tick.Stop()
+	for {
+		job, err := h.Service.Jobs.Get(ctx, name)
+		if err != nil && err != store.ErrNotFound {
+			return nil, err
+		}
+		if job != nil && job.Phase >= v1.JobPhase_PHASE_DONE {
+			return job, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-tick.C:
+		}
+	}
+}
+
+// EmptyContent provides jobs with an empty workspace
+type EmptyContent struct{}
+
+// InitContainer returns a container which does nothing
+func (EmptyContent) InitContainer() (*corev1.Container, error) {
+	return &corev1.Container{
+		Image:   "alpine:latest",
+		Command: []string{"true"},
+	}, nil
+}
+
+// Serve does nothing, as there is no content to serve
+func (EmptyContent) Serve(jobName string) error {
+	return nil
+}
@@ -0,0 +1,135 @@
+package testing_test
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/werft"
+	werfttesting "github.com/32leaves/werft/pkg/werft/testing"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	corev1 "k8s.io/api/core/v1"
+	k8sjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+)
+
+func TestHarnessSkipsJob(t *testing.T) {
+	h, err := werfttesting.NewHarness(werft.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	md := v1.JobMetadata{
+		Owner:      "someone",
+		Repository: &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"},
+		Trigger:    v1.JobTrigger_TRIGGER_MANUAL,
+	}
+	_, err = h.RunJob(ctx, "werft-build-master.1", md, `
+pod:
+  containers:
+  - name: build
+    image: alpine:latest
+    command: ["true"]
+when:
+  build: trigger == "push"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := h.WaitForJob(ctx, "werft-build-master.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !job.Conditions.Skipped {
+		t.Errorf("expected the job to be skipped: %v", job)
+	}
+	created, err := ptypes.Timestamp(job.Metadata.Created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Equal(werfttesting.DefaultStart) {
+		t.Errorf("expected the job to be created at %v, got %v", werfttesting.DefaultStart, created)
+	}
+}
+
+func TestHarnessRunsJob(t *testing.T) {
+	if !canEncodePods() {
+		t.Skip("the JSON encoder of the Kubernetes client crashes with this Go version")
+	}
+
+	h, err := werfttesting.NewHarness(werft.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	md := v1.JobMetadata{
+		Owner:      "someone",
+		Repository: &v1.Repository{Owner: "32leaves", Repo: "werft", Ref: "refs/heads/master"},
+		Trigger:    v1.JobTrigger_TRIGGER_MANUAL,
+	}
+	_, err = h.RunJob(ctx, "werft-build-master.1", md, `
+pod:
+  containers:
+  - name: build
+    image: alpine:latest
+    command: ["true"]
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := h.WaitForJob(ctx, "werft-build-master.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !job.Conditions.Success || job.Conditions.Skipped {
+		t.Errorf("expected the job to succeed: %v", job)
+	}
+	for _, ts := range []struct {
+		Name string
+		T    *timestamp.Timestamp
+	}{
+		{"created", job.Metadata.Created},
+		{"finished", job.Metadata.Finished},
+	} {
+		act, err := ptypes.Timestamp(ts.T)
+		if err != nil {
+			t.Errorf("%s: %v", ts.Name, err)
+			continue
+		}
+		if !act.Equal(werfttesting.DefaultStart) {
+			t.Errorf("expected the job to be %s at %v, got %v", ts.Name, werfttesting.DefaultStart, act)
+		}
+	}
+}
+
+// canEncodePods returns false if encoding pods panics, as it does with the json-iterator version the Kubernetes
+// client uses and Go 1.18 or later
+func canEncodePods() (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "build", Image: "alpine:latest"}}}}
+	err := k8sjson.NewYAMLSerializer(k8sjson.DefaultMetaFactory, nil, nil).Encode(pod, ioutil.Discard)
+	return err == nil
+}
+
+func TestSequentialIDs(t *testing.T) {
+	ids := &werfttesting.SequentialIDs{Prefix: "job"}
+	for _, exp := range []string{"job-1", "job-2", "job-3"} {
+		if act := ids.NewID(); act != exp {
+			t.Errorf("expected %s, got %s", exp, act)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package werft
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/github"
+	"golang.org/x/oauth2"
+)
+
+// httpClient returns a client which sends outbound requests using the service's transport
+func (srv *Service) httpClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: srv.Transport, Timeout: timeout}
+}
+
+// userGitHubClient returns a GitHub client which acts on behalf of the user token belongs to
+func (srv *Service) userGitHubClient(ctx context.Context, token string) *github.Client {
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, srv.httpClient(0))
+	return github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})))
+}
@@ -0,0 +1,112 @@
+package werft
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"time"
+
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/store"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// triggerSourceGitHub marks payloads of GitHub webhooks
+	triggerSourceGitHub = "github"
+
+	// triggerSourceArtifact marks payloads of container registry webhooks
+	triggerSourceArtifact = "artifact"
+)
+
+type triggerPayloadKey struct{}
+
+// withTriggerPayload marks a context as handling a webhook, so that the jobs it starts keep the webhook's payload
+func withTriggerPayload(ctx context.Context, source, eventType, delivery string, received time.Time, payload []byte) context.Context {
+	ts, _ := ptypes.TimestampProto(received)
+	return context.WithValue(ctx, triggerPayloadKey{}, &v1.TriggerPayload{
+		Source:    source,
+		EventType: eventType,
+		Delivery:  delivery,
+		Received:  ts,
+		Payload:   payload,
+	})
+}
+
+// triggerPayload returns the payload of the webhook handled in this context
+func triggerPayload(ctx context.Context) *v1.TriggerPayload {
+	p, _ := ctx.Value(triggerPayloadKey{}).(*v1.TriggerPayload)
+	return p
+}
+
+// storeTriggerPayload keeps the payload of the webhook which started a job, compressed as webhooks can be large.
+// Jobs which weren't started by a webhook have no payload.
+func (srv *Service) storeTriggerPayload(ctx context.Context, name string) {
+	p := triggerPayload(ctx)
+	if p == nil {
+		return
+	}
+
+	err := func() error {
+		data, err := proto.Marshal(p)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		_, err = gz.Write(data)
+		if err != nil {
+			return err
+		}
+		err = gz.Close()
+		if err != nil {
+			return err
+		}
+		return srv.Jobs.StoreTriggerPayload(name, buf.Bytes())
+	}()
+	if err != nil {
+		log.WithError(err).WithField("name", name).Warn("cannot store trigger payload")
+	}
+}
+
+// GetTriggerPayload returns what triggered a job, e.g. the body of the GitHub webhook which started it
+func (srv *Service) GetTriggerPayload(ctx context.Context, req *v1.GetTriggerPayloadRequest) (*v1.GetTriggerPayloadResponse, error) {
+	data, err := srv.Jobs.GetTriggerPayload(req.Name)
+	if err == store.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "%s has no trigger payload: it was not started by a webhook", req.Name)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	p, err := decodeTriggerPayload(data)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.GetTriggerPayloadResponse{Trigger: p}, nil
+}
+
+func decodeTriggerPayload(data []byte) (*v1.TriggerPayload, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decompress trigger payload: %w", err)
+	}
+	defer gz.Close()
+	raw, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot decompress trigger payload: %w", err)
+	}
+
+	var p v1.TriggerPayload
+	err = proto.Unmarshal(raw, &p)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot unmarshal trigger payload: %w", err)
+	}
+	return &p, nil
+}
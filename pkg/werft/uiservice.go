@@ -10,8 +10,14 @@ import (
 	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
 	"github.com/32leaves/werft/pkg/reporef"
+	"github.com/32leaves/werft/pkg/websecurity"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-github/github"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,6 +26,9 @@ type UIService struct {
 	Github *github.Client
 	Repos  []string
 
+	// Sessions keeps the sessions of logged in users. It's nil if session-based login is disabled.
+	Sessions *websecurity.Sessions
+
 	cache []*v1.ListJobSpecsResponse
 	mu    sync.RWMutex
 }
@@ -49,87 +58,148 @@ func (uis *UIService) updateJobSpecs() error {
 			log.WithError(err).WithField("repo", r).Warn("unable to download job spec while updating UI")
 			continue
 		}
-		if repo.Ref != "" && repo.Revision == "" {
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
-			repo.Revision, _, err = uis.Github.Repositories.GetCommitSHA1(ctx, repo.Owner, repo.Repo, repo.Ref, "")
-			cancel()
 
-			if err != nil {
-				log.WithError(err).WithField("repo", r).Warn("cannot resolve ref to revision")
+		specs, err := uis.repoJobSpecs(repo)
+		if err != nil {
+			log.WithError(err).WithField("repo", r).Warn("unable to download job spec while updating UI")
+			continue
+		}
+		uis.cache = append(uis.cache, specs...)
+	}
+
+	return nil
+}
+
+// repoJobSpecs lists the job specs of a repository at its ref or revision. Job specs which cannot be
+// downloaded or parsed are skipped.
+func (uis *UIService) repoJobSpecs(repo *v1.Repository) ([]*v1.ListJobSpecsResponse, error) {
+	if repo.Ref != "" && repo.Revision == "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		rev, _, err := uis.Github.Repositories.GetCommitSHA1(ctx, repo.Owner, repo.Repo, repo.Ref, "")
+		cancel()
+		if err != nil {
+			return nil, xerrors.Errorf("cannot resolve ref to revision: %w", err)
+		}
+		repo.Revision = rev
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	_, dc, _, err := uis.Github.Repositories.GetContents(ctx, repo.Owner, repo.Repo, ".werft", &github.RepositoryContentGetOptions{
+		Ref: repo.Revision,
+	})
+	cancel()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		paths []string
+		seen  = make(map[string]struct{})
+	)
+	for _, f := range dc {
+		if f.GetType() != "file" {
+			continue
+		}
+
+		fn := f.GetName()
+		if !strings.HasSuffix(fn, "yaml") || f.GetPath() == PathWerftConfig {
+			continue
+		}
+		paths = append(paths, f.GetPath())
+		seen[f.GetPath()] = struct{}{}
+	}
+
+	// job files can live outside of .werft/ if the repo config points to them
+	cp := &GitHubContentProvider{Client: uis.Github, Owner: repo.Owner, Repo: repo.Repo, Revision: repo.Revision}
+	ctx, cancel = context.WithTimeout(context.Background(), 1*time.Minute)
+	repoCfg, err := getRepoCfg(ctx, cp)
+	cancel()
+	if err == nil {
+		for _, p := range repoCfg.JobPaths() {
+			if _, ok := seen[p]; ok {
 				continue
 			}
+			paths = append(paths, p)
+			seen[p] = struct{}{}
 		}
+	}
+
+	var res []*v1.ListJobSpecsResponse
+	for _, fp := range paths {
+		jobName := strings.TrimSuffix(filepath.Base(fp), filepath.Ext(fp))
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
-		_, dc, _, err := uis.Github.Repositories.GetContents(ctx, repo.Owner, repo.Repo, ".werft", &github.RepositoryContentGetOptions{
+		fc, err := uis.Github.Repositories.DownloadContents(ctx, repo.Owner, repo.Repo, fp, &github.RepositoryContentGetOptions{
 			Ref: repo.Revision,
 		})
 		cancel()
 		if err != nil {
-			log.WithError(err).WithField("repo", repo).Warn("unable to download job spec while updating UI")
+			log.WithError(err).WithField("repo", repo).WithField("path", fp).Warn("unable to download job spec")
 			continue
 		}
 
-		for _, f := range dc {
-			if f.GetType() != "file" {
-				continue
-			}
-
-			fn := f.GetName()
-			if !strings.HasSuffix(fn, "yaml") || f.GetPath() == PathWerftConfig {
-				continue
-			}
-			jobName := strings.TrimSuffix(fn, filepath.Ext(fn))
+		var jobspec repoconfig.JobSpec
+		err = yaml.NewDecoder(fc).Decode(&jobspec)
+		fc.Close()
+		if err != nil {
+			log.WithError(err).WithField("repo", repo).WithField("path", fp).Warn("unable to unmarshal job spec")
+			continue
+		}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
-			fc, err := uis.Github.Repositories.DownloadContents(ctx, repo.Owner, repo.Repo, f.GetPath(), &github.RepositoryContentGetOptions{
-				Ref: repo.Revision,
+		var args []*v1.DesiredAnnotation
+		for _, arg := range jobspec.Args {
+			args = append(args, &v1.DesiredAnnotation{
+				Name:        arg.Name,
+				Required:    arg.Req,
+				Description: arg.Desc,
 			})
-			cancel()
-			if err != nil {
-				log.WithError(err).WithField("repo", repo).WithField("path", f.GetPath()).Warn("unable to download job spec while updating UI")
-				continue
-			}
+		}
 
-			var jobspec repoconfig.JobSpec
-			err = yaml.NewDecoder(fc).Decode(&jobspec)
-			fc.Close()
-			if err != nil {
-				log.WithError(err).WithField("repo", repo).WithField("path", f.GetPath()).Warn("unable to unmarshal job spec while updating UI")
-				continue
-			}
+		res = append(res, &v1.ListJobSpecsResponse{
+			Repo: &v1.Repository{
+				Host:     "github.com",
+				Owner:    repo.Owner,
+				Repo:     repo.Repo,
+				Ref:      repo.Ref,
+				Revision: repo.Revision,
+			},
+			Name:        jobName,
+			Path:        fp,
+			Description: jobspec.Desc,
+			Arguments:   args,
+		})
+	}
+	return res, nil
+}
 
-			var args []*v1.DesiredAnnotation
-			for _, arg := range jobspec.Args {
-				args = append(args, &v1.DesiredAnnotation{
-					Name:        arg.Name,
-					Required:    arg.Req,
-					Description: arg.Desc,
-				})
-			}
+// ListJobSpecs returns a list of jobs that can be started through the UI or CLI.
+// If the request names a repository, its job specs are fetched right away. Otherwise
+// the job specs of the configured repositories are listed.
+func (uis *UIService) ListJobSpecs(req *v1.ListJobSpecsRequest, srv v1.WerftUI_ListJobSpecsServer) error {
+	if repo := req.Repository; repo != nil {
+		if repo.Owner == "" || repo.Repo == "" {
+			return status.Error(codes.InvalidArgument, "repository owner and repo are required")
+		}
+		if repo.Host != "" && repo.Host != "github.com" {
+			return status.Errorf(codes.InvalidArgument, "unsupported repository host %s", repo.Host)
+		}
+		if uis.Github == nil {
+			return status.Error(codes.FailedPrecondition, "werft has no access to GitHub")
+		}
 
-			res := &v1.ListJobSpecsResponse{
-				Repo: &v1.Repository{
-					Host:     "github.com",
-					Owner:    repo.Owner,
-					Repo:     repo.Repo,
-					Ref:      repo.Ref,
-					Revision: repo.Revision,
-				},
-				Name:        jobName,
-				Path:        f.GetPath(),
-				Description: jobspec.Desc,
-				Arguments:   args,
+		specs, err := uis.repoJobSpecs(&v1.Repository{Owner: repo.Owner, Repo: repo.Repo, Ref: repo.Ref, Revision: repo.Revision})
+		if err != nil {
+			return status.Errorf(codes.NotFound, "cannot list job specs of %s/%s: %v", repo.Owner, repo.Repo, err)
+		}
+		for _, r := range specs {
+			err := srv.Send(r)
+			if err != nil {
+				return err
 			}
-			uis.cache = append(uis.cache, res)
 		}
+		return nil
 	}
 
-	return nil
-}
-
-// ListJobSpecs returns a list of jobs that can be started through the UI.
-func (uis *UIService) ListJobSpecs(req *v1.ListJobSpecsRequest, srv v1.WerftUI_ListJobSpecsServer) error {
 	uis.mu.RLock()
 	defer uis.mu.RUnlock()
 
@@ -142,3 +212,24 @@ func (uis *UIService) ListJobSpecs(req *v1.ListJobSpecsRequest, srv v1.WerftUI_L
 
 	return nil
 }
+
+// GetIdentity returns the user of the current session and their permissions
+func (uis *UIService) GetIdentity(ctx context.Context, req *v1.GetIdentityRequest) (*v1.GetIdentityResponse, error) {
+	if uis.Sessions == nil {
+		return nil, status.Error(codes.Unimplemented, "session-based login is not configured")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	id, err := uis.Sessions.IdentityFromCookies(md.Get("cookie")...)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	exp, err := ptypes.TimestampProto(id.Expires)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &v1.GetIdentityResponse{
+		User:        id.User,
+		Permissions: id.Permissions,
+		Expires:     exp,
+	}, nil
+}
@@ -0,0 +1,213 @@
+package werft
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/github"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+)
+
+const (
+	// DefaultMaxWebhookPayloadSize is the size of the largest webhook event werft accepts by default.
+	// GitHub caps webhook payloads at 25 MB.
+	DefaultMaxWebhookPayloadSize = 25 << 20
+
+	// githubMetaRefreshInterval is the time between refreshing the addresses GitHub sends webhook events from
+	githubMetaRefreshInterval = 1 * time.Hour
+)
+
+// WebhookSourcesConfig configures the addresses werft accepts webhook events from
+type WebhookSourcesConfig struct {
+	// GitHubMeta accepts webhook events from the addresses GitHub publishes through its meta API.
+	// The addresses are refreshed every hour.
+	GitHubMeta bool `yaml:"githubMeta,omitempty"`
+
+	// CIDRs are accepted in addition to the GitHub addresses, e.g. those of a GitHub Enterprise installation
+	CIDRs []string `yaml:"cidrs,omitempty"`
+
+	// TrustForwardedFor uses the last address of the X-Forwarded-For header instead of the address of the
+	// connection. Enable this only if werft is behind a load balancer or ingress which sets that header.
+	TrustForwardedFor bool `yaml:"trustForwardedFor,omitempty"`
+}
+
+// WebhookGuard rejects webhook requests which come from unknown addresses or are too large
+type WebhookGuard struct {
+	cfg            *WebhookSourcesConfig
+	maxPayloadSize int64
+
+	static []*net.IPNet
+
+	mu     sync.RWMutex
+	github []*net.IPNet
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWebhookGuard produces a new webhook guard. If sources is nil, requests from all addresses are accepted.
+// If the GitHub addresses are accepted, they're refreshed in the background using client until the guard is closed.
+func NewWebhookGuard(sources *WebhookSourcesConfig, maxPayloadSize int64, client *github.Client) (*WebhookGuard, error) {
+	if maxPayloadSize <= 0 {
+		maxPayloadSize = DefaultMaxWebhookPayloadSize
+	}
+	g := &WebhookGuard{
+		cfg:            sources,
+		maxPayloadSize: maxPayloadSize,
+		stop:           make(chan struct{}),
+	}
+	if sources == nil {
+		return g, nil
+	}
+
+	var err error
+	g.static, err = parseCIDRs(sources.CIDRs)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid webhook source: %w", err)
+	}
+	if sources.GitHubMeta {
+		if client == nil {
+			return nil, xerrors.Errorf("cannot accept webhook events from GitHub addresses without GitHub client")
+		}
+		err = g.refreshGitHub(client)
+		if err != nil {
+			// we'll try again in the background - until then only the static CIDRs are accepted
+			log.WithError(err).Warn("cannot get GitHub webhook addresses")
+		}
+		go g.keepRefreshingGitHub(client, g.stop)
+	}
+	return g, nil
+}
+
+// Close stops refreshing the GitHub addresses
+func (g *WebhookGuard) Close() {
+	g.stopOnce.Do(func() { close(g.stop) })
+}
+
+// Handler wraps a webhook handler so that it only sees requests the guard accepts
+func (g *WebhookGuard) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > g.maxPayloadSize {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if g.cfg != nil {
+			addr := g.sourceAddr(r)
+			if !g.accepts(addr) {
+				log.WithField("addr", r.RemoteAddr).WithField("source", addr.String()).Warn("rejected webhook request from unknown address")
+				http.Error(w, "forbidden", http.StatusForbidden)
+				return
+			}
+		}
+
+		// requests without content length, e.g. chunked ones, are read before the handler sees them so that we can
+		// reject those which are too large, rather than failing in the handler
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, g.maxPayloadSize+1))
+		if err != nil {
+			http.Error(w, "cannot read payload", http.StatusBadRequest)
+			return
+		}
+		if int64(len(body)) > g.maxPayloadSize {
+			http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// sourceAddr returns the address a request comes from, or nil if it cannot be determined
+func (g *WebhookGuard) sourceAddr(r *http.Request) net.IP {
+	if g.cfg.TrustForwardedFor {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			segs := strings.Split(fwd, ",")
+			return net.ParseIP(strings.TrimSpace(segs[len(segs)-1]))
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+func (g *WebhookGuard) accepts(addr net.IP) bool {
+	if addr == nil {
+		return false
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	for _, nets := range [][]*net.IPNet{g.static, g.github} {
+		for _, n := range nets {
+			if n.Contains(addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (g *WebhookGuard) keepRefreshingGitHub(client *github.Client, stop <-chan struct{}) {
+	tick := time.NewTicker(githubMetaRefreshInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+		case <-stop:
+			return
+		}
+
+		err := g.refreshGitHub(client)
+		if err != nil {
+			// we keep the addresses we had before
+			log.WithError(err).Warn("cannot refresh GitHub webhook addresses")
+		}
+	}
+}
+
+func (g *WebhookGuard) refreshGitHub(client *github.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	meta, _, err := client.APIMeta(ctx)
+	if err != nil {
+		return err
+	}
+	nets, err := parseCIDRs(meta.Hooks)
+	if err != nil {
+		return xerrors.Errorf("GitHub published an invalid webhook address: %w", err)
+	}
+	if len(nets) == 0 {
+		return xerrors.Errorf("GitHub published no webhook addresses")
+	}
+
+	g.mu.Lock()
+	g.github = nets
+	g.mu.Unlock()
+	log.WithField("cidrs", meta.Hooks).Debug("refreshed GitHub webhook addresses")
+	return nil
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	res := make([]*net.IPNet, len(cidrs))
+	for i, c := range cidrs {
+		_, n, err := net.ParseCIDR(c)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = n
+	}
+	return res, nil
+}
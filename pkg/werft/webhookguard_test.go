@@ -0,0 +1,113 @@
+package werft_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/32leaves/werft/pkg/werft"
+	"github.com/google/go-github/github"
+)
+
+func TestWebhookGuard(t *testing.T) {
+	meta := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hooks":["192.30.252.0/22"]}`))
+	}))
+	defer meta.Close()
+	client := github.NewClient(nil)
+	client.BaseURL, _ = url.Parse(meta.URL + "/")
+
+	tests := []struct {
+		Name         string
+		Sources      *werft.WebhookSourcesConfig
+		MaxSize      int64
+		RemoteAddr   string
+		ForwardedFor string
+		Payload      string
+		Status       int
+	}{
+		{"no sources", nil, 0, "1.2.3.4:1234", "", "{}", http.StatusOK},
+		{"static CIDR", &werft.WebhookSourcesConfig{CIDRs: []string{"10.0.0.0/8"}}, 0, "10.1.2.3:1234", "", "{}", http.StatusOK},
+		{"unknown address", &werft.WebhookSourcesConfig{CIDRs: []string{"10.0.0.0/8"}}, 0, "1.2.3.4:1234", "", "{}", http.StatusForbidden},
+		{"GitHub address", &werft.WebhookSourcesConfig{GitHubMeta: true}, 0, "192.30.252.10:1234", "", "{}", http.StatusOK},
+		{"not a GitHub address", &werft.WebhookSourcesConfig{GitHubMeta: true}, 0, "1.2.3.4:1234", "", "{}", http.StatusForbidden},
+		{"forwarded for ignored", &werft.WebhookSourcesConfig{GitHubMeta: true}, 0, "1.2.3.4:1234", "192.30.252.10", "{}", http.StatusForbidden},
+		{"forwarded for", &werft.WebhookSourcesConfig{GitHubMeta: true, TrustForwardedFor: true}, 0, "10.1.2.3:1234", "1.2.3.4, 192.30.252.10", "{}", http.StatusOK},
+		{"spoofed forwarded for", &werft.WebhookSourcesConfig{GitHubMeta: true, TrustForwardedFor: true}, 0, "10.1.2.3:1234", "192.30.252.10, 1.2.3.4", "{}", http.StatusForbidden},
+		{"too large", nil, 8, "1.2.3.4:1234", "", `{"foo":"bar"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			guard, err := werft.NewWebhookGuard(test.Sources, test.MaxSize, client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer guard.Close()
+			h := guard.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			req := httptest.NewRequest("POST", "/github/app", strings.NewReader(test.Payload))
+			req.RemoteAddr = test.RemoteAddr
+			if test.ForwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", test.ForwardedFor)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Errorf("expected status %d, got %d: %s", test.Status, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWebhookGuardPayloadWithoutLength(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Payload string
+		Status  int
+	}{
+		{"small enough", `{"foo":1}`, http.StatusOK},
+		{"too large", `{"foo":"bar"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			guard, err := werft.NewWebhookGuard(nil, 10, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer guard.Close()
+			var received string
+			h := guard.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("cannot read payload: %v", err)
+				}
+				received = string(body)
+			}))
+
+			// chunked requests don't tell their size up front
+			req := httptest.NewRequest("POST", "/github/app", strings.NewReader(test.Payload))
+			req.ContentLength = -1
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != test.Status {
+				t.Errorf("expected status %d, got %d: %s", test.Status, rec.Code, rec.Body.String())
+			}
+			if test.Status == http.StatusOK && received != test.Payload {
+				t.Errorf("expected the handler to receive %q, got %q", test.Payload, received)
+			}
+		})
+	}
+}
+
+func TestWebhookGuardInvalidCIDR(t *testing.T) {
+	_, err := werft.NewWebhookGuard(&werft.WebhookSourcesConfig{CIDRs: []string{"10.0.0.0"}}, 0, nil)
+	if err == nil {
+		t.Error("expected an error for an invalid CIDR")
+	}
+}
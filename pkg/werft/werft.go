@@ -3,9 +3,13 @@ package werft
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"path"
 	"path/filepath"
 	"reflect"
 	"strings"
@@ -15,20 +19,33 @@ import (
 
 	"github.com/32leaves/werft/pkg/api/repoconfig"
 	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/configschema"
+	"github.com/32leaves/werft/pkg/credentials"
 	"github.com/32leaves/werft/pkg/executor"
+	"github.com/32leaves/werft/pkg/filterexpr"
+	"github.com/32leaves/werft/pkg/logclass"
 	"github.com/32leaves/werft/pkg/logcutter"
+	"github.com/32leaves/werft/pkg/logforward"
+	"github.com/32leaves/werft/pkg/logmask"
+	"github.com/32leaves/werft/pkg/logparser"
+	"github.com/32leaves/werft/pkg/schedule"
 	"github.com/32leaves/werft/pkg/store"
+	"github.com/32leaves/werft/pkg/tracing"
 	sprig "github.com/Masterminds/sprig/v3"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/go-github/github"
 	"github.com/olebedev/emitter"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/segmentio/textio"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/xerrors"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	k8sjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
@@ -46,16 +63,351 @@ type Config struct {
 	// WorkspaceNodePathPrefix is the location on the node where we place the builds
 	WorkspaceNodePathPrefix string `yaml:"workspaceNodePathPrefix,omitempty"`
 
+	// PullRequestSummary makes werft post and maintain a comment on pull requests which lists all jobs
+	// of the pull request's head commit. This requires read & write access to pull requests for the GitHub app.
+	PullRequestSummary bool `yaml:"pullRequestSummary,omitempty"`
+
+	// CheckoutCache configures a cache of repository checkouts keyed by commit, so that jobs which run on the same
+	// commit repeatedly (e.g. retries) can restore their workspace instead of cloning the repository again.
+	CheckoutCache *CheckoutCacheConfig `yaml:"checkoutCache,omitempty"`
+
 	// CleanupJobSpec is a podspec YAML which forms the basis for cleanup jobs.
 	// Can be empty, in which clean up jobs will use a default.
 	CleanupJobSpec *configPodSpec `yaml:"cleanupJobSpec,omitempty"`
 
+	// ArchiveJobsAfter is the age after which finished jobs are moved from the job store to the archive.
+	// If this is not set jobs are never archived.
+	ArchiveJobsAfter *executor.Duration `yaml:"archiveJobsAfter,omitempty"`
+
+	// JobNameTemplate is a Go template producing the names of jobs started from GitHub, e.g. "{{ .Repo }}-{{ .Ref }}".
+	// Job names are numbered, e.g. werft-master.12. Defaults to DefaultJobNameTemplate.
+	JobNameTemplate string `yaml:"jobNameTemplate,omitempty"`
+
+	// DebugKeepAlive is the time the pods of failed jobs started with the debug annotation are kept around.
+	// Defaults to 30 minutes.
+	DebugKeepAlive *executor.Duration `yaml:"debugKeepAlive,omitempty"`
+
+	// LogLevels configures which log lines are errors and warnings. Werft counts them per slice and shows the first
+	// error of failed jobs, e.g. on GitHub. Defaults to patterns most tools' output matches.
+	LogLevels logclass.Config `yaml:"logLevels,omitempty"`
+
+	// LogParsers names the compiled-in log parsers which extract results from the logs of all jobs, e.g. gotest.
+	// Log parser plugins are added to those.
+	LogParsers []string `yaml:"logParsers,omitempty"`
+
+	// AllowedRepositories restricts the repositories werft handles webhook events of, e.g. to the organisations
+	// of an instance which is reachable from the internet. Repositories are given like in the repositories section,
+	// e.g. github.com/32leaves/*. If this is empty, werft handles webhook events of all repositories.
+	AllowedRepositories []string `yaml:"allowedRepositories,omitempty"`
+
+	// DeniedRepositories are repositories werft ignores webhook events of, even if they are allowed
+	DeniedRepositories []string `yaml:"deniedRepositories,omitempty"`
+
+	// WebhookSources restricts the addresses werft accepts webhook events from. If this is nil, werft accepts
+	// webhook events from everywhere.
+	WebhookSources *WebhookSourcesConfig `yaml:"webhookSources,omitempty"`
+
+	// MaxWebhookPayloadSize is the size in bytes of the largest webhook event werft accepts.
+	// Defaults to DefaultMaxWebhookPayloadSize.
+	MaxWebhookPayloadSize int64 `yaml:"maxWebhookPayloadSize,omitempty"`
+
+	// JobStatusBatchWindow is the time job status updates are collected for before they're written to the job
+	// store in one go. Defaults to defaultJobStatusBatchWindow; 0 writes every update right away. Updates of the last
+	// window are lost if werft crashes.
+	JobStatusBatchWindow *executor.Duration `yaml:"jobStatusBatchWindow,omitempty"`
+
+	// JobSpecFragmentRepos are the repositories job specs can extend or include fragments of, in addition to their own
+	// repository, e.g. github.com/32leaves/ci-config. Repositories are given like in the repositories section.
+	JobSpecFragmentRepos []string `yaml:"jobSpecFragmentRepos,omitempty"`
+
+	// Repositories overrides the global defaults for jobs of particular repositories
+	Repositories []RepositoryConfig `yaml:"repositories,omitempty"`
+
+	// CentralConfig points to a repository which holds organisation-wide settings, e.g. the repositories section.
+	// Werft applies changes to those settings without a restart.
+	CentralConfig *CentralConfigRepoConfig `yaml:"centralConfig,omitempty"`
+
+	// FallbackJobs configure the jobs of repositories which have no .werft/config.yaml. The first entry
+	// matching a repository is used.
+	FallbackJobs []FallbackJobConfig `yaml:"fallbackJobs,omitempty"`
+
+	// Credentials are short-lived cloud credentials jobs can request, e.g. to deploy without long-lived static keys
+	Credentials []credentials.Config `yaml:"credentials,omitempty"`
+
+	// ServiceAccounts are the service accounts jobs can request. If this is empty, jobs run with the service account
+	// their pod spec names.
+	ServiceAccounts []ServiceAccountConfig `yaml:"serviceAccounts,omitempty"`
+
+	// SecurityProfiles harden the pods of jobs. Jobs run with the "default" profile unless they request another one.
+	// If this is empty, job pods are left as they are.
+	SecurityProfiles []SecurityProfileConfig `yaml:"securityProfiles,omitempty"`
+
+	// ExecutionWindows limit the time of day jobs of particular repositories, or jobs requesting them, start at
+	ExecutionWindows []ExecutionWindowConfig `yaml:"executionWindows,omitempty"`
+
+	// Timezone is the time zone of execution windows and schedules which don't name one, e.g. Europe/Berlin.
+	// Defaults to the time zone of the werft server. Werft stores and reports all times in UTC regardless.
+	Timezone string `yaml:"timezone,omitempty"`
+
+	// Provenance makes werft sign and record the provenance of finished jobs
+	Provenance *ProvenanceConfig `yaml:"provenance,omitempty"`
+
+	// SBOM makes werft generate the software bill of materials of the images successful jobs report as results
+	SBOM *SBOMConfig `yaml:"sbom,omitempty"`
+
+	// Projects group related repositories, e.g. the microservices of a team, so that their jobs and health
+	// can be viewed together
+	Projects []ProjectConfig `yaml:"projects,omitempty"`
+
+	// ArtifactTriggers start jobs whenever a new version of an artifact is published, e.g. a base image or a chart
+	ArtifactTriggers []ArtifactTriggerConfig `yaml:"artifactTriggers,omitempty"`
+
+	// Quotas limit the resources the jobs of teams or repositories consume. Jobs which would exceed a quota don't run.
+	Quotas []QuotaConfig `yaml:"quotas,omitempty"`
+
+	// Costs prices the resources jobs request, so that werft can estimate what each job cost and attribute it to
+	// repositories and teams. Jobs have no cost unless this is configured.
+	Costs *CostConfig `yaml:"costs,omitempty"`
+
+	// ImageWebhook receives the container images jobs report as results, e.g. to keep an artifact metadata service
+	// up to date
+	ImageWebhook *ImageWebhookConfig `yaml:"imageWebhook,omitempty"`
+
+	// MaxDownstreamDepth limits the number of jobs in a chain of downstream jobs. Defaults to defaultMaxDownstreamDepth.
+	MaxDownstreamDepth int `yaml:"maxDownstreamDepth,omitempty"`
+
+	// ExportTokens authorize exporting job records using the ExportJobs API or /export/jobs. Exporting is disabled
+	// unless there are tokens.
+	ExportTokens []string `yaml:"exportTokens,omitempty"`
+
+	// ExportTokensFile names a file, e.g. mounted from a Kubernetes secret, with more export tokens, one per line
+	ExportTokensFile string `yaml:"exportTokensFile,omitempty"`
+
+	// JobResources mirrors each job as a WerftJob custom resource, so that kubectl users and controllers can observe
+	// jobs in the cluster. The service's JobResources must be set, too.
+	JobResources *JobResourcesConfig `yaml:"jobResources,omitempty"`
+
+	// SecretEnvPatterns are the names of environment variables whose values werft treats as secrets, e.g. *_TOKEN.
+	// Their values are masked in job logs and redacted from recorded job specs and environments. * matches any
+	// sequence of characters, and names are matched regardless of their case. Defaults to DefaultSecretEnvPatterns.
+	SecretEnvPatterns []string `yaml:"secretEnvPatterns,omitempty"`
+
+	// AdminTokens authorize administrative APIs, e.g. purging jobs using the PurgeJob API. Those APIs are disabled
+	// unless there are tokens.
+	AdminTokens []string `yaml:"adminTokens,omitempty"`
+
+	// AdminTokensFile names a file, e.g. mounted from a Kubernetes secret, with more admin tokens, one per line
+	AdminTokensFile string `yaml:"adminTokensFile,omitempty"`
+
 	// Enables the webui debug proxy pointing to this address
 	DebugProxy string
 }
 
+// CheckoutCacheConfig configures the checkout cache
+type CheckoutCacheConfig struct {
+	// ClaimName names the persistent volume claim which holds the cache. As jobs run on different nodes
+	// this volume should support ReadWriteMany.
+	ClaimName string `yaml:"claimName"`
+
+	// MaxAge is the time after which checkouts which weren't used are removed from the cache. Defaults to a week.
+	MaxAge *executor.Duration `yaml:"maxAge,omitempty"`
+}
+
+const (
+	// checkoutContainerName is the name of the init container which checks out the content of jobs
+	checkoutContainerName = "werft-checkout"
+
+	// checkoutCacheVolume is the name of the volume of the checkout cache in job pods
+	checkoutCacheVolume = "werft-checkout-cache"
+
+	// checkoutCachePath is where the checkout cache is mounted in the checkout container
+	checkoutCachePath = "/cache"
+
+	// defaultCheckoutCacheMaxAge is the default time after which unused checkouts are removed from the cache
+	defaultCheckoutCacheMaxAge = 7 * 24 * time.Hour
+)
+
+// defaultJobStatusBatchWindow is the time job status updates are collected for if the config doesn't say otherwise
+const defaultJobStatusBatchWindow = 100 * time.Millisecond
+
+// RepositoryConfig overrides the global defaults for jobs of a particular repository
+type RepositoryConfig struct {
+	// Repo identifies the repository as host/owner/repo or owner/repo. Supports globs, e.g. github.com/32leaves/*
+	Repo string `yaml:"repo"`
+
+	// Timeout overrides the total timeout of the executor for jobs of this repository
+	Timeout *executor.Duration `yaml:"timeout,omitempty"`
+
+	// MaxConcurrentJobs limits the number of jobs of this repository which can run at the same time. Jobs beyond the
+	// limit wait in the queue. Zero means no limit.
+	MaxConcurrentJobs int `yaml:"maxConcurrentJobs,omitempty"`
+
+	// MaxJobsPerHour limits the number of jobs of this repository which can start within an hour. Jobs beyond the
+	// limit wait in the queue. Zero means no limit.
+	MaxJobsPerHour int `yaml:"maxJobsPerHour,omitempty"`
+
+	// ResultChannels are the channels results are published to if a job does not name any
+	ResultChannels []string `yaml:"resultChannels,omitempty"`
+
+	// ImagePullSecrets are used to pull the images of this repository's jobs, in addition to those of the executor
+	ImagePullSecrets []string `yaml:"imagePullSecrets,omitempty"`
+
+	// Env is set in all containers of this repository's jobs, in addition to the environment of the executor.
+	// Jobs override these variables by setting them in their containers.
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// DeployKey makes jobs clone this repository over SSH using a deploy key rather than using the GitHub app's credentials
+	DeployKey *DeployKeyConfig `yaml:"deployKey,omitempty"`
+
+	// Attach allows users to run interactive commands in the running jobs of this repository, e.g. to debug failing builds
+	Attach *AttachConfig `yaml:"attach,omitempty"`
+
+	// BuildCache tells the BuildKit of this repository's jobs where to import its cache from and export it to
+	BuildCache *BuildCacheConfig `yaml:"buildCache,omitempty"`
+
+	// Egress limits the network destinations this repository's jobs can reach
+	Egress *EgressConfig `yaml:"egress,omitempty"`
+
+	// LogCutter slices the output of tools which aren't werft-aware, in addition to the default log syntax
+	LogCutter []logcutter.Expression `yaml:"logCutter,omitempty"`
+
+	// FlakyJobs are known to be flaky: their failures are muted and they are rerun once. Entries match the job name
+	// without its number, e.g. werft-e2e-master, and support globs, e.g. werft-e2e-*.
+	FlakyJobs []string `yaml:"flakyJobs,omitempty"`
+
+	// PodPermission is the GitHub permission users need to see the pods of this repository's jobs (read, write or admin).
+	// Defaults to write.
+	PodPermission string `yaml:"podPermission,omitempty"`
+
+	// Approval holds the jobs of this repository until someone approves them, e.g. to deploy to production.
+	// Without approval, jobs start right away.
+	Approval *ApprovalConfig `yaml:"approval,omitempty"`
+
+	// Upstreams are the repositories whose jobs may start downstream jobs in this repository, given as host/owner/repo
+	// or owner/repo. Supports globs. Without upstreams, no other repository can start jobs in this one.
+	Upstreams []string `yaml:"upstreams,omitempty"`
+}
+
+// DeployKeyConfig points to an SSH deploy key stored in a secret in the executor's namespace
+type DeployKeyConfig struct {
+	// SecretName names the secret which holds the private key
+	SecretName string `yaml:"secretName"`
+
+	// PrivateKey is the key within the secret holding the private key. Defaults to ssh-privatekey, the key kubernetes.io/ssh-auth secrets use.
+	PrivateKey string `yaml:"privateKey,omitempty"`
+
+	// KnownHosts is the key within the secret holding a known_hosts file. If empty, the host key of GitHub is accepted on first use.
+	KnownHosts string `yaml:"knownHosts,omitempty"`
+}
+
+// matches returns true if this config applies to the repo
+func (rc RepositoryConfig) matches(repo *v1.Repository) bool {
+	return repoMatches(rc.Repo, repo)
+}
+
+// repoMatches returns true if a repository matches a pattern of the form host/owner/repo or owner/repo.
+// Patterns support globs, e.g. github.com/32leaves/*
+func repoMatches(pattern string, repo *v1.Repository) bool {
+	if repo == nil {
+		return false
+	}
+
+	name := fmt.Sprintf("%s/%s/%s", repo.Host, repo.Owner, repo.Repo)
+	if strings.Count(pattern, "/") < 2 {
+		name = fmt.Sprintf("%s/%s", repo.Owner, repo.Repo)
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// repositoryConfig returns the configuration for a repository. All matching entries
+// of the repositories section are merged, with later entries overriding earlier ones.
+func (srv *Service) repositoryConfig(repo *v1.Repository) (res RepositoryConfig) {
+	for _, rc := range srv.config().Repositories {
+		if !rc.matches(repo) {
+			continue
+		}
+
+		res.Repo = rc.Repo
+		if rc.Timeout != nil {
+			res.Timeout = rc.Timeout
+		}
+		if rc.MaxConcurrentJobs > 0 {
+			res.MaxConcurrentJobs = rc.MaxConcurrentJobs
+		}
+		if rc.MaxJobsPerHour > 0 {
+			res.MaxJobsPerHour = rc.MaxJobsPerHour
+		}
+		if len(rc.ResultChannels) > 0 {
+			res.ResultChannels = rc.ResultChannels
+		}
+		if len(rc.ImagePullSecrets) > 0 {
+			res.ImagePullSecrets = rc.ImagePullSecrets
+		}
+		for k, v := range rc.Env {
+			if res.Env == nil {
+				res.Env = make(map[string]string)
+			}
+			res.Env[k] = v
+		}
+		if rc.DeployKey != nil {
+			res.DeployKey = rc.DeployKey
+		}
+		if rc.Attach != nil {
+			res.Attach = rc.Attach
+		}
+		if rc.BuildCache != nil {
+			res.BuildCache = rc.BuildCache
+		}
+		if rc.Egress != nil {
+			res.Egress = rc.Egress
+		}
+		if len(rc.LogCutter) > 0 {
+			res.LogCutter = rc.LogCutter
+		}
+		if len(rc.FlakyJobs) > 0 {
+			res.FlakyJobs = rc.FlakyJobs
+		}
+		if rc.PodPermission != "" {
+			res.PodPermission = rc.PodPermission
+		}
+		if len(rc.Upstreams) > 0 {
+			res.Upstreams = rc.Upstreams
+		}
+		if rc.Approval != nil {
+			res.Approval = rc.Approval
+		}
+	}
+	return
+}
+
+// logCutter returns the cutter which slices the logs of jobs of repo
+func (srv *Service) logCutter(repo *v1.Repository) logcutter.Cutter {
+	cutter := srv.Cutter
+	if cutter == nil {
+		cutter = logcutter.DefaultCutter
+	}
+
+	exprs := srv.repositoryConfig(repo).LogCutter
+	if len(exprs) == 0 {
+		return cutter
+	}
+	res, err := logcutter.NewExpressionCutter(exprs)
+	if err != nil {
+		// Start validates the expressions, hence this should never happen
+		log.WithError(err).WithField("repo", repoKey(repo)).Warn("invalid log cutter expressions - using the default log cutter")
+		return cutter
+	}
+	return res
+}
+
 type configPodSpec corev1.PodSpec
 
+// ConfigSchema describes pod specs in config files, which follow the Kubernetes API rather than our YAML conventions
+func (configPodSpec) ConfigSchema() *configschema.Schema {
+	return &configschema.Schema{Type: "object"}
+}
+
 func (spec *configPodSpec) UnmarshalYAML(value *yaml.Node) error {
 	raw, err := yaml.Marshal(value)
 	if err != nil {
@@ -73,23 +425,137 @@ func (spec *configPodSpec) UnmarshalYAML(value *yaml.Node) error {
 type jobLog struct {
 	CancelExecutorListener context.CancelFunc
 	LogStore               io.Closer
+
+	// SchedulingSpan traces the time between handing a job to the executor and the job actually running
+	SchedulingSpan trace.Span
+
+	// Masker redacts secrets from the job's logs
+	Masker *logmask.Masker
+
+	// Phase describes the log phase the job is currently in, e.g. "building the application"
+	Phase string
+
+	// Summary counts the error, warning and info lines of each slice. It's nil until we listen to the job's log.
+	Summary *logclass.Summary
 }
 
 // Service ties everything together
 type Service struct {
-	Logs     store.Logs
-	Jobs     store.Jobs
-	Groups   store.NumberGroup
-	Executor *executor.Executor
-	Cutter   logcutter.Cutter
-	GitHub   GitHubSetup
+	Logs        store.Logs
+	Jobs        store.Jobs
+	Archive     store.JobArchive
+	DeadLetters store.DeadLetters
+	Images      store.Images
+	Groups      store.NumberGroup
+	Executor    executor.Backend
+	Cutter      logcutter.Cutter
+	GitHub      GitHubSetup
+
+	// LogForwarder mirrors job log output to external sinks. If nil, logs are not forwarded.
+	LogForwarder logforward.Forwarder
+
+	// JobResources holds the WerftJob custom resources jobs are mirrored as. If nil, jobs are not mirrored.
+	JobResources dynamic.ResourceInterface
+
+	// Clock tells the service the time, e.g. when jobs are created or finish. Defaults to the system clock.
+	Clock Clock
+
+	// Transport sends the service's outbound HTTP requests, e.g. to the image webhook or chart repositories.
+	// Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// IDs produces the random parts of names, e.g. of local jobs. Defaults to MonikerIDs.
+	IDs IDGenerator
+
+	// Version describes the build of werft which runs this service, as reported by GetVersion
+	Version *v1.GetVersionResponse
 
 	Config Config
 
 	mu          sync.RWMutex
 	logListener map[string]*jobLog
 
+	matrixMu sync.Mutex
+
+	summaryMu sync.Mutex
+	summaries map[string]prSummary
+
+	credentials map[string]credentials.Provider
+
+	logParserMu sync.RWMutex
+	logParsers  []logparser.Parser
+
+	hookMu sync.RWMutex
+	hooks  []Hook
+
+	approvalMu sync.Mutex
+	approvals  map[string]pendingApproval
+
+	// jobStarts are the jobs which passed their gate recently, by repository. slots are the repositories of the jobs
+	// which passed their gate and aren't done yet, by job name.
+	gateMu    sync.Mutex
+	jobStarts map[string][]jobStart
+	slots     map[string]string
+
+	// quotaLocks serialize checking a quota and starting the job which passed the check, by quota name
+	quotaLocks sync.Map
+
+	announcementMu sync.RWMutex
+	announcement   *v1.Announcement
+
+	// schedules are the schedules registered by schedulers, e.g. the cron plugin, by scheduler
+	scheduleMu sync.RWMutex
+	schedules  map[string][]*v1.Schedule
+
+	statsMu sync.Mutex
+	stats   map[string]*durationStats
+
+	fragmentMu sync.Mutex
+	fragments  map[string]cachedFragment
+
+	// org are the settings of the central config repository. It's nil if there is none or it wasn't loaded yet.
+	orgMu      sync.RWMutex
+	org        *OrgConfig
+	orgContent []byte
+	orgRefresh chan struct{}
+
+	// jobBatch writes job status updates in batches. It's nil if updates are written right away.
+	jobBatch *store.BatchingJobStore
+
+	// provenanceKey signs the provenance of finished jobs. It's nil if provenance is not recorded.
+	provenanceKey ed25519.PrivateKey
+
+	// buildCacheSteps counts the build steps jobs reported in buildcache results
+	buildCacheSteps *prometheus.CounterVec
+
+	// startLatency observes the time jobs took from the webhook which started them until their pod ran
+	startLatency *prometheus.HistogramVec
+
+	// flakyFailures counts the failures of known-flaky jobs, i.e. muted failures and failed reruns
+	flakyFailures *prometheus.CounterVec
+
 	events emitter.Emitter
+
+	// started is when the service started
+	started time.Time
+
+	// logClassifier classifies the log lines of jobs as errors, warnings or info
+	logClassifier *logclass.Classifier
+
+	// secretEnv matches the names of environment variables whose values are secrets
+	secretEnv secretEnvNames
+
+	// nodeInfos remembers what the nodes jobs ran on run, e.g. their kernel version
+	nodeInfos nodeInfoCache
+
+	// lifecycleMu guards runCtx and stop
+	lifecycleMu sync.Mutex
+	// runCtx is done once the service stops. Background work of the service runs until then. It's nil until the service starts.
+	runCtx context.Context
+	// stop cancels runCtx. It's nil if the service isn't running.
+	stop context.CancelFunc
+	// background waits for the background work of the service to end
+	background sync.WaitGroup
 }
 
 // GitCredentialHelper can authenticate provide authentication credentials for a repository
@@ -98,19 +564,156 @@ type GitCredentialHelper func(ctx context.Context) (user string, pass string, er
 // GitHubSetup sets up the access to GitHub
 type GitHubSetup struct {
 	WebhookSecret []byte
-	Client        *github.Client
-	Auth          GitCredentialHelper
+	// WebhookSecrets are accepted in addition to WebhookSecret, e.g. while the secret is rotated
+	WebhookSecrets [][]byte
+	Client         *github.Client
+	Auth           GitCredentialHelper
 }
 
-// Start sets up everything to run this werft instance, including executor config
-func (srv *Service) Start() error {
+// Metrics returns the Prometheus collectors of this service
+func (srv *Service) Metrics() []prometheus.Collector {
+	var res []prometheus.Collector
+	if srv.buildCacheSteps != nil {
+		res = append(res, srv.buildCacheSteps)
+	}
+	if srv.startLatency != nil {
+		res = append(res, srv.startLatency)
+	}
+	if srv.flakyFailures != nil {
+		res = append(res, srv.flakyFailures)
+	}
+	return res
+}
+
+// Start sets up everything to run this werft instance and starts the executor. ctx bounds the work done while
+// starting, e.g. loading the central config and restoring waiting jobs - the service keeps running once Start
+// returns, until Stop is called. A service can be started only once.
+func (srv *Service) Start(ctx context.Context) (err error) {
+	srv.lifecycleMu.Lock()
+	if srv.runCtx != nil {
+		srv.lifecycleMu.Unlock()
+		return xerrors.Errorf("service was started already")
+	}
+	srv.runCtx, srv.stop = context.WithCancel(context.Background())
+	srv.lifecycleMu.Unlock()
+	var executorRunning bool
+	defer func() {
+		if err == nil {
+			return
+		}
+		srv.lifecycleMu.Lock()
+		srv.stop()
+		srv.stop = nil
+		srv.lifecycleMu.Unlock()
+		if executorRunning {
+			// like Stop, we close the executor before waiting, because its job updates may start background work
+			srv.Executor.Close()
+		}
+		srv.background.Wait()
+	}()
+
+	srv.started = srv.now()
 	if srv.logListener == nil {
 		srv.logListener = make(map[string]*jobLog)
 	}
-	srv.Executor.OnUpdate = srv.handleJobUpdate
+	srv.Executor.SetUpdateHandler(srv.handleJobUpdate)
+	if srv.Clock != nil {
+		srv.Executor.SetClock(srv.Clock)
+	}
+	if srv.IDs != nil {
+		srv.Executor.SetIDs(srv.IDs)
+	}
+
+	err = srv.setupCredentials()
+	if err != nil {
+		return err
+	}
+	err = srv.setupProvenance()
+	if err != nil {
+		return err
+	}
+	err = srv.setupTokens()
+	if err != nil {
+		return err
+	}
+
+	batchWindow := defaultJobStatusBatchWindow
+	if srv.Config.JobStatusBatchWindow != nil {
+		batchWindow = srv.Config.JobStatusBatchWindow.Duration
+	}
+	if batchWindow > 0 {
+		// pods cause an update with every event - batching them saves the job store from lots of tiny writes
+		srv.jobBatch = store.NewBatchingJobStore(srv.Jobs, batchWindow)
+		srv.jobBatch.OnError = func(job v1.JobStatus, err error) {
+			log.WithError(err).WithFields(jobLogFields(job.Name, job.Metadata)).Warn("cannot store job")
+		}
+		srv.Jobs = srv.jobBatch
+	}
+
+	if srv.Config.JobNameTemplate != "" {
+		_, err := template.New("name").Funcs(sprig.TxtFuncMap()).Parse(srv.Config.JobNameTemplate)
+		if err != nil {
+			return xerrors.Errorf("invalid job name template: %w", err)
+		}
+	}
+	if srv.Config.Timezone != "" {
+		if _, err := schedule.LoadLocation(srv.Config.Timezone); err != nil {
+			return err
+		}
+	}
+	org := srv.Config.orgConfig()
+	err = org.Validate(srv.now())
+	if err != nil {
+		return err
+	}
+	for _, c := range srv.Config.ArtifactTriggers {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	if c := srv.Config.Costs; c != nil {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	if wh := srv.Config.ImageWebhook; wh != nil && wh.URL == "" {
+		return xerrors.Errorf("imageWebhook: url is required")
+	}
+	srv.logClassifier, err = logclass.New(srv.Config.LogLevels)
+	if err != nil {
+		return xerrors.Errorf("logLevels: %w", err)
+	}
+	srv.secretEnv, err = newSecretEnvNames(srv.Config.SecretEnvPatterns)
+	if err != nil {
+		return xerrors.Errorf("secretEnvPatterns: %w", err)
+	}
+	for _, n := range srv.Config.LogParsers {
+		p, ok := logparser.Builtins[n]
+		if !ok {
+			return xerrors.Errorf("unknown log parser %s: must be one of %s", n, strings.Join(logparser.BuiltinNames(), ", "))
+		}
+		srv.AddLogParser(p)
+	}
+	for _, p := range srv.Config.SecurityProfiles {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	srv.buildCacheSteps = newBuildCacheMetrics()
+	srv.startLatency = newStartLatencyMetrics()
+	srv.flakyFailures = newFlakyFailureMetrics()
+	err = srv.startCentralConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if srv.JobResources != nil {
+		srv.goBackground(srv.mirrorJobResources)
+	}
+	srv.Executor.Run()
+	executorRunning = true
 
 	// we might still have waiting jobs which we must load back into the executor
-	waitingJobs, _, err := srv.Jobs.Find(context.Background(), []*v1.FilterExpression{
+	waitingJobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{
 		&v1.FilterExpression{Terms: []*v1.FilterTerm{
 			&v1.FilterTerm{
 				Field:     "phase",
@@ -136,10 +739,14 @@ func (srv *Service) Start() error {
 			cancelJob(err)
 			continue
 		}
-		waitUntil, err := ptypes.Timestamp(j.Conditions.WaitUntil)
-		if err != nil {
-			cancelJob(err)
-			continue
+		// jobs held back by the maintenance mode don't have a start time
+		var waitUntil time.Time
+		if j.Conditions.WaitUntil != nil {
+			waitUntil, err = ptypes.Timestamp(j.Conditions.WaitUntil)
+			if err != nil {
+				cancelJob(err)
+				continue
+			}
 		}
 
 		md := j.Metadata
@@ -150,86 +757,348 @@ func (srv *Service) Start() error {
 			Client:   srv.GitHub.Client,
 			Auth:     srv.GitHub.Auth,
 		}
-		_, err = srv.RunJob(context.Background(), j.Name, *md, cp, jobYAML, true, waitUntil)
+		// the job runs the same spec on the same revision it was started with, so it's as verified as it was
+		ctx := withSourceVerified(context.Background(), md.SourceVerified)
+		_, err = srv.RunJob(ctx, j.Name, *md, cp, jobYAML, true, waitUntil, executor.WithAttempt(int(j.Conditions.Attempt)))
 		if err != nil {
 			cancelJob(err)
 			continue
 		}
 	}
 
-	go srv.doHousekeeping()
+	srv.goBackground(srv.doHousekeeping)
+	srv.startArtifactPollers()
 
 	return nil
 }
 
-func (srv *Service) doHousekeeping() {
+// goBackground runs f in the background until the service stops. f must return once ctx is done.
+// Once the service stopped, f doesn't run at all and goBackground returns false.
+func (srv *Service) goBackground(f func(ctx context.Context)) bool {
+	srv.lifecycleMu.Lock()
+	defer srv.lifecycleMu.Unlock()
+
+	ctx := srv.runCtx
+	if ctx == nil {
+		// the service isn't started, e.g. in tests - there's nothing to wait for then
+		ctx = context.Background()
+	} else if ctx.Err() != nil {
+		return false
+	}
+	srv.background.Add(1)
+	go func() {
+		defer srv.background.Done()
+		f(ctx)
+	}()
+	return true
+}
+
+// backgroundContext returns a context which is done once the service stops
+func (srv *Service) backgroundContext() context.Context {
+	srv.lifecycleMu.Lock()
+	defer srv.lifecycleMu.Unlock()
+
+	if srv.runCtx == nil {
+		return context.Background()
+	}
+	return srv.runCtx
+}
+
+// AddLogParser adds a parser which extracts results from the logs of all jobs started from now on
+func (srv *Service) AddLogParser(p logparser.Parser) {
+	srv.logParserMu.Lock()
+	defer srv.logParserMu.Unlock()
+
+	srv.logParsers = append(srv.logParsers, p)
+}
+
+func (srv *Service) doHousekeeping(ctx context.Context) {
 	tick := time.NewTicker(5 * time.Minute)
+	defer tick.Stop()
 	for {
 		log.Debug("performing werft service housekeeping")
 
-		ctx := context.Background()
-		expectedJobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "phase", Value: "done", Operation: v1.FilterOp_OP_EQUALS, Negate: true}}}}, []*v1.OrderExpression{}, 0, 0)
+		err := srv.archiveJobs(ctx)
 		if err != nil {
-			log.WithError(err).Warn("cannot perform housekeeping")
-			continue
+			log.WithError(err).Warn("cannot archive jobs")
 		}
 
-		knownJobs, err := srv.Executor.GetKnownJobs()
+		err = srv.reconcileJobs(ctx)
 		if err != nil {
 			log.WithError(err).Warn("cannot perform housekeeping")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+// staleJobGracePeriod is the time a job has to appear in the executor before we consider it stale.
+// This way we don't fail jobs which are just being started.
+const staleJobGracePeriod = 2 * time.Minute
+
+// reconcileJobs compares the jobs we expect to be active with those the executor knows about.
+// Jobs which are stuck, because their pod no longer exists or never existed, are marked as failed.
+func (srv *Service) reconcileJobs(ctx context.Context) error {
+	phase := func(p string) *v1.FilterTerm {
+		return &v1.FilterTerm{Field: "phase", Value: p, Operation: v1.FilterOp_OP_EQUALS}
+	}
+	expectedJobs, _, err := srv.Jobs.Find(ctx, []*v1.FilterExpression{&v1.FilterExpression{Terms: []*v1.FilterTerm{
+		phase("preparing"),
+		phase("starting"),
+		phase("running"),
+		phase("waiting"),
+	}}}, []*v1.OrderExpression{}, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	knownJobs, err := srv.Executor.GetKnownJobs()
+	if err != nil {
+		return err
+	}
+
+	knownJobsIdx := make(map[string]v1.JobStatus)
+	for _, s := range knownJobs {
+		knownJobsIdx[s.Name] = s
+	}
+
+	for _, job := range expectedJobs {
+		if len(job.Metadata.Children) > 0 {
+			// matrix jobs don't run themselves, hence the executor does not know them
+			_, err := srv.updateMatrixJob(ctx, job.Name, nil)
+			if err != nil {
+				log.WithError(err).WithFields(jobLogFields(job.Name, job.Metadata)).Warn("cannot update matrix job")
+			}
+			continue
+		}
+
+		knownStatus, exists := knownJobsIdx[job.Name]
+		if !exists {
+			srv.reapStaleJob(ctx, job)
 			continue
 		}
 
-		knownJobsIdx := make(map[string]v1.JobStatus)
-		for _, s := range knownJobs {
-			knownJobsIdx[s.Name] = s
+		if !reflect.DeepEqual(knownStatus, job) {
+			log.WithFields(jobLogFields(job.Name, job.Metadata)).Warn("executor had a different status than what we had last seen - we have missed an event. Updating job.")
+			srv.handleJobUpdate(nil, &job)
+		}
+	}
+
+	return nil
+}
+
+// reapStaleJob marks a job the executor does not know about as failed
+func (srv *Service) reapStaleJob(ctx context.Context, job v1.JobStatus) {
+	if created, err := ptypes.Timestamp(job.Metadata.Created); err == nil && srv.since(created) < staleJobGracePeriod {
+		return
+	}
+
+	// the job might have finished since we listed the jobs - in that case the executor rightfully forgot about it
+	current, err := srv.Jobs.Get(ctx, job.Name)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(job.Name, job.Metadata)).Warn("cannot get stale job")
+		return
+	}
+	if current.Phase == v1.JobPhase_PHASE_DONE || current.Phase == v1.JobPhase_PHASE_CLEANUP {
+		return
+	}
+	job = *current
+
+	var details string
+	if job.Phase == v1.JobPhase_PHASE_RUNNING || job.Conditions.GetDidExecute() {
+		details = "The job's pod no longer exists, e.g. because it was deleted or its node went away. Werft missed the job finishing."
+	} else {
+		details = "The job's pod was never created or disappeared before the job started."
+	}
+	log.WithFields(jobLogFields(job.Name, job.Metadata)).WithField("phase", job.Phase.String()).Warn("executor does not know about this job - marking it as failed")
+
+	if out, err := srv.Logs.Write(job.Name); err == nil {
+		fmt.Fprintf(out, "\n[werft] FAILURE %s\n", details)
+	}
+
+	if job.Conditions == nil {
+		job.Conditions = &v1.JobConditions{}
+	}
+	job.Phase = v1.JobPhase_PHASE_DONE
+	job.Conditions.Success = false
+	job.Conditions.InfrastructureFailure = true
+	job.Details = details
+	if job.Metadata.Finished == nil {
+		job.Metadata.Finished = srv.timestampNow()
+	}
+	srv.handleJobUpdate(nil, &job)
+}
+
+// archiveBatchSize is the number of jobs we try to archive at once
+const archiveBatchSize = 100
+
+// archiveJobs moves all finished jobs older than Config.ArchiveJobsAfter from the job store to the archive
+func (srv *Service) archiveJobs(ctx context.Context) error {
+	if srv.Archive == nil || srv.Config.ArchiveJobsAfter == nil {
+		return nil
+	}
+
+	var (
+		cutoff = srv.now().Add(-srv.Config.ArchiveJobsAfter.Duration)
+		filter = []*v1.FilterExpression{&v1.FilterExpression{Terms: []*v1.FilterTerm{&v1.FilterTerm{Field: "phase", Value: "done", Operation: v1.FilterOp_OP_EQUALS}}}}
+		order  = []*v1.OrderExpression{&v1.OrderExpression{Field: "created", Ascending: true}}
+	)
+	for {
+		jobs, _, err := srv.Jobs.Find(ctx, filter, order, 0, archiveBatchSize)
+		if err != nil {
+			return err
 		}
 
-		for _, job := range expectedJobs {
-			knownStatus, exists := knownJobsIdx[job.Name]
-			if !exists {
-				log.WithField("name", job.Name).Warn("executor does not know about this job - we have missed an event. Marking as failed.")
-				job.Phase = v1.JobPhase_PHASE_DONE
-				job.Conditions.Success = false
-				job.Details = "Werft missed updates for this job and the job is no longer running."
-				srv.handleJobUpdate(nil, &job)
+		var archived int
+		for _, job := range jobs {
+			created, err := ptypes.Timestamp(job.Metadata.Created)
+			if err != nil || created.After(cutoff) {
 				continue
 			}
 
-			if !reflect.DeepEqual(knownStatus, job) {
-				log.WithField("name", job.Name).Warn("executor had a different status than what we had last seen - we have missed an event. Updating job.")
-				srv.handleJobUpdate(nil, &job)
+			err = srv.Archive.Put(ctx, job)
+			if err != nil {
+				return xerrors.Errorf("cannot archive %s: %w", job.Name, err)
 			}
+			err = srv.Jobs.Delete(ctx, job.Name)
+			if err != nil {
+				return xerrors.Errorf("cannot remove archived job %s from job store: %w", job.Name, err)
+			}
+			err = srv.deleteJobResource(job.Name)
+			if err != nil {
+				log.WithError(err).WithFields(jobLogFields(job.Name, job.Metadata)).Warn("cannot delete job resource")
+			}
+			archived++
 		}
+		log.WithField("count", archived).Debug("archived jobs")
 
-		<-tick.C
+		if archived == 0 || len(jobs) < archiveBatchSize {
+			return nil
+		}
+	}
+}
+
+// Stop ends the background work of the service and the executor, e.g. housekeeping, polling and watching jobs, and
+// writes job status updates which are yet to be stored. It waits for all that until ctx is done. Call it before
+// shutting down the stores. Jobs keep running in the executor - a service started later picks them up again.
+func (srv *Service) Stop(ctx context.Context) error {
+	srv.lifecycleMu.Lock()
+	stop := srv.stop
+	srv.stop = nil
+	if stop != nil {
+		// we cancel while holding the lock so that goBackground starts no work once we wait for it
+		stop()
+	}
+	srv.lifecycleMu.Unlock()
+	if stop == nil {
+		return xerrors.Errorf("service is not running")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		// the executor reports job updates until it's closed, which may start more background work
+		srv.Executor.Close()
+		srv.background.Wait()
+		if srv.jobBatch == nil {
+			done <- nil
+			return
+		}
+		done <- srv.jobBatch.Close()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return xerrors.Errorf("cannot store pending job updates: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
 func (srv *Service) handleJobUpdate(pod *corev1.Pod, s *v1.JobStatus) {
-	var isCleanupJob bool
+	var isCleanupJob, isSBOMJob bool
 	for _, annotation := range s.Metadata.Annotations {
-		if annotation.Key == annotationCleanupJob {
+		switch annotation.Key {
+		case annotationCleanupJob:
 			isCleanupJob = true
-			break
+		case annotationSBOMJob:
+			isSBOMJob = true
 		}
 	}
 	// We ignore all status updates from cleanup jobs - they are not user triggered and we do not want them polluting the system.
 	if isCleanupJob {
 		return
 	}
+	// SBOM jobs are not user triggered either - all we care about is the SBOM they produced.
+	if isSBOMJob {
+		srv.handleSBOMJobUpdate(pod, s)
+		return
+	}
 
-	// ensure we have logging, e.g. reestablish joblog for unknown jobs (i.e. after restart)
-	srv.ensureLogging(s)
+	// ensure we have logging, e.g. reestablish joblog for unknown jobs (i.e. after restart). The secrets of a job
+	// are resolved once it starts, hence we only resolve them again for jobs we don't know.
+	var secrets []string
+	if pod != nil && !srv.hasJobLog(s.Name) {
+		secrets = srv.podSecrets(pod.Spec, executor.SecretEnv(pod))
+	}
+	masker := srv.ensureLogging(s, secrets)
+
+	if s.Phase == v1.JobPhase_PHASE_RUNNING || s.Phase == v1.JobPhase_PHASE_DONE || s.Phase == v1.JobPhase_PHASE_CLEANUP {
+		srv.endSchedulingSpan(s)
+	}
+
+	// We only want to act on a job finishing (e.g. retry it) once, hence we check the job actually changed to done with this update.
+	prev, err := srv.Jobs.Get(context.Background(), s.Name)
+	srv.attributeCost(pod, s, prev)
+	srv.recordEnvironment(pod, s, prev, masker)
+	srv.recordLogSummary(s, prev)
+	if err == nil {
+		keepNotes(s, prev)
+		if prev.Conditions.GetMuted() && s.Conditions != nil {
+			// the executor doesn't know that we muted the failure of this job
+			s.Conditions.Muted = true
+		}
+	}
+	justDone := s.Phase == v1.JobPhase_PHASE_DONE && err == nil && prev.Phase != v1.JobPhase_PHASE_DONE
+	justFailed := justDone && !s.Conditions.Success
+
+	phaseChanged := err != nil || prev.Phase != s.Phase
+	if phaseChanged {
+		srv.recordPhaseEvent(s)
+	}
+	if phaseChanged && s.Phase == v1.JobPhase_PHASE_RUNNING {
+		srv.recordStartLatency(s)
+	}
+	if s.Phase == v1.JobPhase_PHASE_CLEANUP {
+		srv.recordTermination(s.Name, pod)
+	}
+	// pods can be scheduled and start running between two updates, hence we check on phase changes, too
+	if pod != nil && (s.Phase == v1.JobPhase_PHASE_PREPARING || phaseChanged) {
+		if t := podScheduledTime(pod); t != nil {
+			srv.addJobEvent(s.Name, v1.JobEvent{Type: v1.JobEventType_EVENT_POD_SCHEDULED, Time: t, Message: pod.Spec.NodeName})
+		}
+	}
 
 	out, err := srv.Logs.Write(s.Name)
 	if err == nil && pod != nil {
-		pw := textio.NewPrefixWriter(out, "[werft:kubernetes] ")
+		// the pod contains secrets (e.g. in its environment), hence we must mask it before it ends up in the logs
+		var podlog bytes.Buffer
+		pw := textio.NewPrefixWriter(&podlog, "[werft:kubernetes] ")
 		k8sjson.NewSerializer(k8sjson.DefaultMetaFactory, scheme.Scheme, nil, false).Encode(pod, pw)
 		pw.Flush()
+		io.WriteString(out, masker.Mask(podlog.String()))
 
 		jsonStatus, _ := json.Marshal(s)
-		fmt.Fprintf(out, "[werft:status] %s\n", jsonStatus)
+		fmt.Fprintf(out, "[werft:status] %s\n", masker.Mask(string(jsonStatus)))
+
+		if justFailed && s.Conditions.DidExecute {
+			srv.writeDiagnostics(out, pod, masker)
+		}
 	}
 
 	// TODO make sure this runs only once, e.g. by improving the status computation s.t. we pass through starting
@@ -251,54 +1120,232 @@ func (srv *Service) handleJobUpdate(pod *corev1.Pod, s *v1.JobStatus) {
 			}
 			srv.cleanupJobWorkspace(s)
 
-			delete(srv.logListener, s.Name)
+			delete(srv.logListener, s.Name)
+		}
+		srv.mu.Unlock()
+
+		return
+	}
+	// a job which just failed might have to be retried
+	if justFailed {
+		srv.muteFlakyFailure(s)
+		retry := *s
+		srv.goBackground(func(ctx context.Context) { srv.retryJob(ctx, retry) })
+	}
+
+	err = srv.Jobs.Store(context.Background(), *s)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).Warn("cannot store job")
+	}
+
+	err = srv.updateGitHubStatus(s)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).Warn("cannot update GitHub status")
+	}
+	if justDone {
+		srv.forgetApproval(s.Name)
+		srv.releaseSlot(s.Name)
+		srv.recordProvenance(s)
+		srv.generateSBOMs(s)
+		srv.jobDone(s)
+
+		// matrix jobs start their downstream jobs once all children succeeded
+		if s.Metadata.Parent == "" {
+			done := *s
+			srv.goBackground(func(ctx context.Context) { srv.startDownstreamJobs(ctx, done, done) })
+		}
+	}
+
+	if parent := s.Metadata.Parent; parent != "" {
+		_, err = srv.updateMatrixJob(context.Background(), parent, nil)
+		if err != nil && err != store.ErrNotFound {
+			log.WithError(err).WithFields(jobLogFields(parent, s.Metadata)).Warn("cannot update matrix job")
+		}
+	}
+
+	// tell our Listen subscribers about this change
+	<-srv.events.Emit("job", s)
+}
+
+// writeDiagnostics appends the diagnostics of a failed job's pod to its log as a separate phase,
+// so that users can see why the pod failed instead of just that it failed.
+func (srv *Service) writeDiagnostics(out io.Writer, pod *corev1.Pod, masker *logmask.Masker) {
+	diag, err := srv.Executor.Diagnostics(pod)
+	if err != nil {
+		log.WithError(err).WithField("name", pod.Name).Warn("cannot produce complete pod diagnostics")
+	}
+	if len(diag) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out, "[diagnostics|PHASE] pod diagnostics")
+	for _, l := range diag {
+		fmt.Fprintf(out, "[diagnostics] %s\n", masker.Mask(l))
+	}
+}
+
+// retryJob starts a failed job again if its retry policy asks for it.
+// Only replayable jobs can be retried as we need their job spec to do so.
+func (srv *Service) retryJob(ctx context.Context, s v1.JobStatus) {
+	logger := log.WithFields(jobLogFields(s.Name, s.Metadata))
+
+	jobYAML, err := srv.Jobs.GetJobSpec(s.Name)
+	if err == store.ErrNotFound {
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Warn("cannot retry job")
+		return
+	}
+	jobspec, err := renderJobSpec(s.Name, s.Metadata, jobYAML)
+	if err != nil {
+		logger.WithError(err).Warn("cannot retry job")
+		return
+	}
+
+	attempt := int(s.Conditions.Attempt)
+	if attempt < 1 {
+		attempt = 1
+	}
+	retry := jobspec.Retry.ShouldRetry(attempt, s.Conditions.InfrastructureFailure)
+	if !retry && !s.Conditions.Muted {
+		return
+	}
+	// muted known-flaky jobs are rerun right away unless their retry policy asks for a backoff
+	var delay time.Duration
+	if retry {
+		delay, err = jobspec.Retry.Delay(attempt)
+		if err != nil {
+			logger.WithError(err).Warn("cannot retry job: invalid backoff")
+			return
 		}
-		srv.mu.Unlock()
+	}
 
-		return
+	// matrix children are retried within their matrix job
+	var name string
+	if s.Metadata.Parent != "" {
+		name = matrixRetryName(s.Name, attempt+1)
+	} else {
+		name, err = srv.nextJobName(s.Name)
+		if err != nil {
+			logger.WithError(err).Warn("cannot retry job")
+			return
+		}
 	}
-	err = srv.Jobs.Store(context.Background(), *s)
+
+	md := *s.Metadata
+	md.Finished = nil
+	cp := &GitHubContentProvider{
+		Owner:    md.Repository.Owner,
+		Repo:     md.Repository.Repo,
+		Revision: md.Repository.Revision,
+		Client:   srv.GitHub.Client,
+		Auth:     srv.GitHub.Auth,
+	}
+	// the approval and source verification of the first attempt cover its retries
+	runCtx := withSourceVerified(withApproved(ctx), md.SourceVerified)
+	_, err = srv.RunJob(runCtx, name, md, cp, jobYAML, true, srv.now().Add(delay), executor.WithAttempt(attempt+1))
 	if err != nil {
-		log.WithError(err).WithField("name", s.Name).Warn("cannot store job")
+		logger.WithError(err).Warn("cannot retry job")
+		return
 	}
+	logger.WithField("retry", name).WithField("attempt", attempt+1).WithField("delay", delay.String()).Info("retrying failed job")
 
-	err = srv.updateGitHubStatus(s)
+	if md.Parent != "" {
+		err = srv.replaceMatrixChild(ctx, md.Parent, s.Name, name)
+		if err != nil {
+			logger.WithError(err).Warn("cannot add retry to matrix job")
+		}
+	}
+}
+
+// nextJobName produces the name of a new job in the same group as a previous job, e.g. foo.3 for foo.2
+func (srv *Service) nextJobName(previous string) (string, error) {
+	name := previous
+	if strings.Contains(name, ".") {
+		segs := strings.Split(name, ".")
+		name = strings.Join(segs[0:len(segs)-1], ".")
+	}
+	nr, err := srv.Groups.Next(name)
 	if err != nil {
-		log.WithError(err).WithField("name", s.Name).Warn("cannot update GitHub status")
+		return "", err
 	}
+	return fmt.Sprintf("%s.%d", name, nr), nil
+}
 
-	// tell our Listen subscribers about this change
-	<-srv.events.Emit("job", s)
+// jobLogFields produces the log fields identifying a job. Use these on all log lines concerning a job
+// so that they can be correlated in log aggregation systems.
+func jobLogFields(name string, md *v1.JobMetadata) log.Fields {
+	fields := log.Fields{"name": name}
+	if md == nil {
+		return fields
+	}
+	if md.Owner != "" {
+		fields["owner"] = md.Owner
+	}
+	if repo := md.Repository; repo != nil {
+		fields["repo"] = fmt.Sprintf("%s/%s/%s", repo.Host, repo.Owner, repo.Repo)
+		if repo.Ref != "" {
+			fields["ref"] = repo.Ref
+		}
+	}
+	return fields
 }
 
-func (srv *Service) ensureLogging(s *v1.JobStatus) {
-	if s.Phase > v1.JobPhase_PHASE_DONE {
+// endSchedulingSpan ends the scheduling span of a job if there is one
+func (srv *Service) endSchedulingSpan(s *v1.JobStatus) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	jl, ok := srv.logListener[s.Name]
+	if !ok || jl.SchedulingSpan == nil {
 		return
 	}
 
-	allOk := func() bool {
+	jl.SchedulingSpan.SetAttributes(attribute.String("phase", s.Phase.String()))
+	jl.SchedulingSpan.End()
+	jl.SchedulingSpan = nil
+}
+
+// ensureLogging makes sure we listen to the executor logs of a job and returns the masker used for its logs.
+// Secrets are added to that masker before we start listening.
+func (srv *Service) ensureLogging(s *v1.JobStatus, secrets []string) *logmask.Masker {
+	if s.Phase > v1.JobPhase_PHASE_DONE {
+		srv.mu.RLock()
+		jl, ok := srv.logListener[s.Name]
+		srv.mu.RUnlock()
+		if ok {
+			jl.Masker.Add(secrets...)
+			return jl.Masker
+		}
+		return logmask.NewMasker(secrets...)
+	}
+
+	allOk := func() (*logmask.Masker, bool) {
 		jl, ok := srv.logListener[s.Name]
 		if !ok {
-			return false
+			return nil, false
 		}
 		if jl.CancelExecutorListener == nil {
-			return false
+			return nil, false
 		}
 
-		return true
+		return jl.Masker, true
 	}
 
 	srv.mu.RLock()
-	if allOk() {
+	if masker, ok := allOk(); ok {
 		srv.mu.RUnlock()
-		return
+		masker.Add(secrets...)
+		return masker
 	}
 	srv.mu.RUnlock()
 
 	srv.mu.Lock()
 	defer srv.mu.Unlock()
-	if allOk() {
-		return
+	if masker, ok := allOk(); ok {
+		masker.Add(secrets...)
+		return masker
 	}
 
 	jl, ok := srv.logListener[s.Name]
@@ -307,29 +1354,115 @@ func (srv *Service) ensureLogging(s *v1.JobStatus) {
 	if !ok {
 		logs, err := srv.Logs.Open(s.Name)
 		if err != nil {
-			log.WithError(err).WithField("name", s.Name).Error("cannot (re-)establish logs for this job")
-			return
+			log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).Error("cannot (re-)establish logs for this job")
+			return logmask.NewMasker(secrets...)
 		}
 
-		jl = &jobLog{LogStore: logs}
+		jl = &jobLog{LogStore: logs, Masker: logmask.NewMasker()}
 		srv.logListener[s.Name] = jl
 	}
+	jl.Masker.Add(secrets...)
 
 	// if we should be listening to the executor log, make sure we are
 	if jl.CancelExecutorListener == nil {
-		ctx, cancel := context.WithCancel(context.Background())
+		ctx, cancel := context.WithCancel(srv.backgroundContext())
 		jl.CancelExecutorListener = cancel
-		go func() {
-			err := srv.listenToLogs(ctx, s.Name, srv.Executor.Logs(s.Name))
+		srv.goBackground(func(context.Context) {
+			err := srv.listenToLogs(ctx, s.Name, srv.logCutter(s.Metadata.GetRepository()), jl.Masker.Reader(srv.Executor.Logs(s.Name)))
 			if err != nil && err != context.Canceled {
-				log.WithError(err).WithField("name", s.Name).Error("cannot listen to job logs")
+				log.WithError(err).WithFields(jobLogFields(s.Name, s.Metadata)).Error("cannot listen to job logs")
 				jl.CancelExecutorListener = nil
 			}
-		}()
+		})
+	}
+
+	return jl.Masker
+}
+
+// hasJobLog returns true if we know the logs of a job, i.e. if we started it or re-established its logs already
+func (srv *Service) hasJobLog(name string) bool {
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+
+	_, ok := srv.logListener[name]
+	return ok
+}
+
+// defaultResultChannels returns the channels results of a job are published to if the job does not name any.
+// The result channels of the repo config take precedence over those of the werft config.
+func (srv *Service) defaultResultChannels(ctx context.Context, name string) []string {
+	job, err := srv.Jobs.Get(ctx, name)
+	if err != nil {
+		log.WithError(err).WithField("name", name).Debug("cannot get job to determine default result channels")
+		return nil
+	}
+	for _, a := range job.GetMetadata().GetAnnotations() {
+		if a.Key == annotationResultChannels && a.Value != "" {
+			return strings.Split(a.Value, ",")
+		}
 	}
+	return srv.repositoryConfig(job.GetMetadata().GetRepository()).ResultChannels
 }
 
-func (srv *Service) listenToLogs(ctx context.Context, name string, inc io.Reader) error {
+// skipJob records a job which does not run, e.g. because its sampling policy left it out
+func (srv *Service) skipJob(ctx context.Context, name string, metadata v1.JobMetadata, jobspec *repoconfig.JobSpec, reason string) (*v1.JobStatus, error) {
+	return srv.finishWithoutRunning(ctx, name, metadata, jobspec, &v1.JobConditions{Success: true, Skipped: true}, reason)
+}
+
+// finishWithoutRunning records a job which is done before it ran, e.g. because it was skipped or exceeded a quota.
+// The reason becomes the job's details.
+func (srv *Service) finishWithoutRunning(ctx context.Context, name string, metadata v1.JobMetadata, jobspec *repoconfig.JobSpec, conditions *v1.JobConditions, reason string) (*v1.JobStatus, error) {
+	metadata.Labels = jobLabels(&metadata, jobspec.Labels)
+	srv.applyProjectLabel(&metadata)
+	if metadata.Created == nil {
+		metadata.Created = srv.timestampNow()
+	}
+	metadata.Finished = metadata.Created
+
+	s := &v1.JobStatus{
+		Name:       name,
+		Metadata:   &metadata,
+		Phase:      v1.JobPhase_PHASE_DONE,
+		Conditions: conditions,
+		Details:    reason,
+	}
+	err := srv.Jobs.Store(ctx, *s)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot store job %s: %w", name, err)
+	}
+	srv.recordPhaseEvent(s)
+	<-srv.events.Emit("job", s)
+	log.WithFields(jobLogFields(name, &metadata)).WithField("reason", reason).Info("job did not run")
+
+	err = srv.updateGitHubStatus(s)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(name, &metadata)).Warn("cannot update GitHub status")
+	}
+	srv.jobDone(s)
+
+	return s, nil
+}
+
+// jobLabels combines the labels of a job spec with annotations prefixed with "label." (e.g. label.team=platform).
+// Annotations take precedence over labels from the spec.
+func jobLabels(md *v1.JobMetadata, specLabels map[string]string) map[string]string {
+	res := make(map[string]string, len(specLabels))
+	for k, v := range specLabels {
+		res[k] = v
+	}
+	for _, a := range md.Annotations {
+		if !strings.HasPrefix(a.Key, filterexpr.LabelFieldPrefix) {
+			continue
+		}
+		res[strings.TrimPrefix(a.Key, filterexpr.LabelFieldPrefix)] = a.Value
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	return res
+}
+
+func (srv *Service) listenToLogs(ctx context.Context, name string, cutter logcutter.Cutter, inc io.Reader) error {
 	out, err := srv.Logs.Write(name)
 	if err != nil {
 		return err
@@ -338,24 +1471,74 @@ func (srv *Service) listenToLogs(ctx context.Context, name string, inc io.Reader
 	// we pipe the content to the log cutter to find results
 	pr, pw := io.Pipe()
 	tr := io.TeeReader(inc, pw)
-	evtchan, cerrchan := srv.Cutter.Slice(pr)
-
-	// then forward the logs we read from the executor to the log store
+	evtchan, cerrchan := cutter.Slice(pr)
+	// then forward the logs we read from the executor to the log store chunk by chunk as they arrive
 	errchan := make(chan error, 1)
+	copied := make(chan struct{})
 	go func() {
-		_, err := io.Copy(out, tr)
+		defer close(copied)
+		_, err := io.CopyBuffer(out, tr, make([]byte, executor.LogChunkSize))
 		if err != nil && err != io.EOF {
 			errchan <- err
 		}
+		// let the cutter know that the log is complete
+		pw.Close()
 		close(errchan)
 	}()
+	defer func() {
+		// Should we stop cutting before the log is complete, the log must still reach the log store.
+		// Without anyone reading from the pipe writing to the store would block forever.
+		drained := make(chan struct{})
+		go func() {
+			io.Copy(ioutil.Discard, pr)
+			close(drained)
+		}()
+
+		// once we stop listening, e.g. because the service stops, we stop reading the log
+		if c, ok := inc.(io.Closer); ok {
+			c.Close()
+		}
+		<-copied
+		<-drained
+	}()
+
+	srv.logParserMu.RLock()
+	parsers := srv.logParsers
+	srv.logParserMu.RUnlock()
+	parser := logparser.Start(ctx, name, parsers, func(res *v1.JobResult) {
+		srv.registerResult(ctx, name, res)
+	})
+	defer parser.Close()
+	summary := srv.logSummary(name)
 
 	for {
 		select {
-		case err := <-cerrchan:
+		case err, ok := <-cerrchan:
+			if !ok {
+				cerrchan = nil
+				continue
+			}
 			log.WithError(err).WithField("name", name).Warn("listening for build results failed")
 			continue
-		case evt := <-evtchan:
+		case evt, ok := <-evtchan:
+			if !ok {
+				// the log is complete
+				srv.storeLogSummary(ctx, name, summary)
+				return nil
+			}
+			if evt.Type == v1.LogSliceType_SLICE_CONTENT {
+				if srv.LogForwarder != nil {
+					srv.LogForwarder.Forward(logforward.Entry{Job: name, Slice: evt.Name, Line: evt.Payload})
+				}
+				parser.Line(evt.Name, evt.Payload)
+				if summary != nil {
+					summary.Line(evt.Name, evt.Payload)
+				}
+			}
+			if evt.Type == v1.LogSliceType_SLICE_PHASE {
+				srv.enterLogPhase(ctx, name, evt)
+				continue
+			}
 			if evt.Type != v1.LogSliceType_SLICE_RESULT {
 				continue
 			}
@@ -382,24 +1565,114 @@ func (srv *Service) listenToLogs(ctx context.Context, name string, inc io.Reader
 					Description: desc,
 				}
 			}
-
-			err := srv.Executor.RegisterResult(name, res)
-			if err != nil {
-				log.WithError(err).WithField("name", name).WithField("res", res).Warn("cannot record job result")
-			}
-		case err := <-errchan:
-			if err != nil {
-				srv.Executor.Stop(name, fmt.Sprintf("log infrastructure failure: %s", err.Error()))
-				return xerrors.Errorf("writing logs for %s: %v", name, err)
+			srv.registerResult(ctx, name, res)
+		case err, ok := <-errchan:
+			if !ok {
+				errchan = nil
+				continue
 			}
+			srv.Executor.Stop(name, fmt.Sprintf("log infrastructure failure: %s", err.Error()))
+			return xerrors.Errorf("writing logs for %s: %v", name, err)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
-// RunJob starts a build job from some context
-func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMetadata, cp ContentProvider, jobYAML []byte, canReplay bool, waitUntil time.Time) (status *v1.JobStatus, err error) {
+// registerResult records a result of a job, e.g. one published in its log or found by a log parser
+func (srv *Service) registerResult(ctx context.Context, name string, res *v1.JobResult) {
+	if len(res.Channels) == 0 {
+		res.Channels = srv.defaultResultChannels(ctx, name)
+	}
+
+	_, span := tracing.Start(ctx, "RegisterResult", trace.WithAttributes(attribute.String("job", name), attribute.String("type", res.Type)))
+	err := srv.Executor.RegisterResult(name, res)
+	tracing.FinishSpan(span, &err)
+	if err != nil {
+		log.WithError(err).WithField("name", name).WithField("res", res).Warn("cannot record job result")
+		return
+	}
+
+	switch res.Type {
+	case resultTypeImage:
+		srv.recordImageBuild(ctx, name, res)
+	case resultTypeBuildCache:
+		srv.recordBuildCacheResult(ctx, name, res)
+	}
+}
+
+// ValidateJobSpec renders a job spec template for a job with the given name and metadata, and validates the result.
+// Line numbers in validation errors refer to the rendered job spec.
+func ValidateJobSpec(name string, md *v1.JobMetadata, jobYAML []byte) error {
+	_, err := renderJobSpec(name, md, jobYAML)
+	return err
+}
+
+// renderJobSpec executes the job YAML template and parses the resulting job spec. Job specs with fragments
+// (see repoconfig.ResolveFragments) consist of several documents which are rendered one by one and then merged.
+func renderJobSpec(name string, md *v1.JobMetadata, jobYAML []byte) (*repoconfig.JobSpec, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if docs := repoconfig.SplitDocuments(jobYAML); len(docs) > 1 {
+		rendered := make([][]byte, len(docs))
+		for i, doc := range docs {
+			rendered[i], err = renderTemplate(name, md, doc)
+			if err != nil {
+				return nil, err
+			}
+		}
+		content, err = repoconfig.MergeDocuments(rendered)
+	} else {
+		content, err = renderTemplate(name, md, jobYAML)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = repoconfig.ValidateJobSpec(content)
+	if err != nil {
+		return nil, err
+	}
+
+	// we have to use the Kubernetes YAML decoder to decode the podspec
+	var jobspec repoconfig.JobSpec
+	err = k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096).Decode(&jobspec)
+	if err != nil {
+		return nil, err
+	}
+	return &jobspec, nil
+}
+
+// renderTemplate executes a job YAML template
+func renderTemplate(name string, md *v1.JobMetadata, jobYAML []byte) ([]byte, error) {
+	jobTpl, err := template.New("job").Funcs(sprig.TxtFuncMap()).Parse(string(jobYAML))
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bytes.NewBuffer(nil)
+	err = jobTpl.Execute(buf, newTemplateObj(name, md))
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RunJob starts a build job from some context. Additional executor options (e.g. the attempt of a retried job)
+// are passed on to the executor.
+func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMetadata, cp ContentProvider, jobYAML []byte, canReplay bool, waitUntil time.Time, opts ...executor.StartOpt) (status *v1.JobStatus, err error) {
+	ctx, span := tracing.Start(ctx, "RunJob", trace.WithAttributes(attribute.String("job", name)))
+	defer tracing.FinishSpan(span, &err)
+
+	if t, ok := webhookReceived(ctx); ok {
+		received, _ := ptypes.TimestampProto(t)
+		srv.addJobEvent(name, v1.JobEvent{Type: v1.JobEventType_EVENT_WEBHOOK_RECEIVED, Time: received})
+	}
+	srv.addJobEvent(name, v1.JobEvent{Type: v1.JobEventType_EVENT_CREATED})
+	srv.storeTriggerPayload(ctx, name)
+	metadata.SourceVerified = sourceVerified(ctx)
+
 	var logs io.WriteCloser
 	defer func(perr *error) {
 		if *perr == nil {
@@ -416,7 +1689,7 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 		s.Conditions = &v1.JobConditions{Success: false, FailureCount: 1}
 		s.Metadata = &metadata
 		if s.Metadata.Created == nil {
-			s.Metadata.Created = ptypes.TimestampNow()
+			s.Metadata.Created = srv.timestampNow()
 		}
 		s.Details = (*perr).Error()
 		if logs != nil {
@@ -424,38 +1697,124 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 		}
 
 		srv.Jobs.Store(context.Background(), s)
+		srv.recordPhaseEvent(&s)
 		<-srv.events.Emit("job", &s)
+
+		err := srv.updateGitHubStatus(&s)
+		if err != nil {
+			log.WithError(err).WithFields(jobLogFields(name, &metadata)).Warn("cannot update GitHub status")
+		}
+		srv.jobDone(&s)
 	}(&err)
 
+	secretEnv := secretAnnotations(ctx)
+	if len(secretEnv) > 0 {
+		// we don't keep the values of secret annotations, hence cannot run the job again
+		canReplay = false
+	}
 	if canReplay {
 		// save job yaml
 		err = srv.Jobs.StoreJobSpec(name, jobYAML)
 		if err != nil {
-			log.WithError(err).Warn("cannot store job YAML - job will not be replayable")
+			log.WithError(err).WithFields(jobLogFields(name, &metadata)).Warn("cannot store job YAML - job will not be replayable")
 		}
 	}
 
-	jobTpl, err := template.New("job").Funcs(sprig.TxtFuncMap()).Parse(string(jobYAML))
+	err = ValidateAnnotations(&metadata)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
 	}
-
-	buf := bytes.NewBuffer(nil)
-	err = jobTpl.Execute(buf, newTemplateObj(name, &metadata))
+	jobspec, err := renderJobSpec(name, &metadata, jobYAML)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
 	}
 
-	// we have to use the Kubernetes YAML decoder to decode the podspec
-	var jobspec repoconfig.JobSpec
-	err = k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(buf.Bytes()), 4096).Decode(&jobspec)
+	podspec := jobspec.PodFor(metadata.Trigger)
+	if podspec == nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: no podspec present", name)
+	}
+	sampled, reason, err := jobspec.Sampling.Sample(&metadata)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	if !sampled {
+		return srv.skipJob(ctx, name, metadata, jobspec, reason)
+	}
+	var skippedContainers []string
+	if len(jobspec.Matrix) == 0 || metadata.Parent != "" {
+		// the jobs of a matrix evaluate the conditions against their own annotations
+		skippedContainers, err = jobspec.ApplyConditions(podspec, &metadata)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+		}
+		if len(podspec.Containers) == 0 {
+			return srv.skipJob(ctx, name, metadata, jobspec, fmt.Sprintf("the conditions of all containers (%s) do not hold", strings.Join(skippedContainers, ", ")))
+		}
+	}
+	podspec, err = srv.mutateJob(ctx, name, &metadata, podspec)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	err = srv.checkCredentialAccess(podspec)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	err = srv.applyServiceAccount(&metadata, jobspec, podspec)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	podAnnotations, err := srv.applySecurityProfile(&metadata, jobspec, podspec)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	creds, err := srv.jobCredentials(&metadata, jobspec, podspec)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	keepAlive, err := srv.debugKeepAlive(&metadata)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	waitFor, err := srv.dependencies(ctx, name, &metadata)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
 	}
 
-	podspec := jobspec.Pod
-	if podspec == nil {
-		return nil, xerrors.Errorf("cannot handle job for %s: no podspec present", name)
+	metadata.Labels = jobLabels(&metadata, jobspec.Labels)
+	srv.applyProjectLabel(&metadata)
+
+	if len(jobspec.Matrix) > 0 && metadata.Parent == "" {
+		return srv.runMatrixJob(ctx, name, metadata, cp, jobYAML, jobspec, canReplay, waitUntil, opts...)
+	}
+
+	repoCfg := srv.repositoryConfig(metadata.Repository)
+	window, err := srv.executionWindow(&metadata, jobspec)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	if window != nil {
+		start := srv.now()
+		if waitUntil.After(start) {
+			start = waitUntil
+		}
+		open, err := window.Next(start)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+		}
+		if open.After(start) {
+			// the job would start outside its window - it waits for the window to open instead
+			waitUntil = open
+			opts = append(opts, executor.WithWaitReason(v1.WaitReason_WAIT_EXECUTION_WINDOW))
+		}
+	}
+	quotaExceeded, releaseQuotas, err := srv.checkQuotas(ctx, &metadata)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+	}
+	// other jobs of the quotas must wait until this one is stored, so that they count it
+	defer releaseQuotas()
+	if quotaExceeded != "" {
+		return srv.finishWithoutRunning(ctx, name, metadata, jobspec, &v1.JobConditions{QuotaExceeded: true}, quotaExceeded)
 	}
 
 	nodePath := filepath.Join(srv.Config.WorkspaceNodePathPrefix, name)
@@ -480,18 +1839,41 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 		})
 	}
 
-	initcontainer, err := cp.InitContainer()
+	if gcp, ok := cp.(*GitHubContentProvider); ok && repoCfg.DeployKey != nil {
+		// only the checkout container gets the deploy key, which it adds itself
+		err = checkDeployKeyUnused(podspec, repoCfg.DeployKey)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot start job %s: %w", name, err)
+		}
+		gcp.DeployKey = repoCfg.DeployKey
+		podspec.Volumes = append(podspec.Volumes, deployKeyVolume(repoCfg.DeployKey))
+	}
+	initcontainer, cached, err := srv.checkoutInitContainer(cp)
 	if err != nil {
 		return nil, xerrors.Errorf("cannot produce init container: %w", err)
 	}
 	cpinit := *initcontainer
-	cpinit.Name = "werft-checkout"
+	cpinit.Name = checkoutContainerName
 	cpinit.ImagePullPolicy = corev1.PullIfNotPresent
 	cpinit.VolumeMounts = append(cpinit.VolumeMounts, corev1.VolumeMount{
 		Name:      wsVolume,
 		ReadOnly:  false,
 		MountPath: "/workspace",
 	})
+	if cached {
+		podspec.Volumes = append(podspec.Volumes, corev1.Volume{
+			Name: checkoutCacheVolume,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: srv.Config.CheckoutCache.ClaimName,
+				},
+			},
+		})
+		cpinit.VolumeMounts = append(cpinit.VolumeMounts, corev1.VolumeMount{
+			Name:      checkoutCacheVolume,
+			MountPath: checkoutCachePath,
+		})
+	}
 	podspec.InitContainers = append(podspec.InitContainers, cpinit)
 	for i, c := range podspec.Containers {
 		podspec.Containers[i].VolumeMounts = append(c.VolumeMounts, corev1.VolumeMount{
@@ -505,18 +1887,27 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 	if err != nil {
 		return nil, xerrors.Errorf("cannot start logging for %s: %w", name, err)
 	}
+	// we resolve the secrets of the job once here, rather than with every update of its pod
+	secrets := srv.podSecrets(*podspec, nil)
+	for _, v := range secretEnv {
+		secrets = append(secrets, v)
+	}
 	srv.mu.Lock()
-	srv.logListener[name] = &jobLog{LogStore: logs}
+	srv.logListener[name] = &jobLog{LogStore: logs, Masker: logmask.NewMasker(secrets...)}
 	srv.mu.Unlock()
 	fmt.Fprintln(logs, "[preparing|PHASE] job preparation")
+	srv.writeAnnouncement(logs)
+	for _, c := range skippedContainers {
+		fmt.Fprintf(logs, "[werft:conditions] skipping %s: %s does not hold\n", c, jobspec.When[c])
+	}
 
 	// dump podspec into logs
 	pw := textio.NewPrefixWriter(logs, "[werft:template] ")
 	redactedSpec := podspec.DeepCopy()
+	secretNames := srv.secretEnvNames()
 	for ci, c := range redactedSpec.InitContainers {
 		for ei, e := range c.Env {
-			log.WithField("conts", strings.Contains(strings.ToLower(e.Name), "secret")).WithField("name", e.Name).Debug("redacting")
-			if !strings.Contains(strings.ToLower(e.Name), "secret") {
+			if e.Value == "" || !secretNames.matches(e.Name) {
 				continue
 			}
 
@@ -525,21 +1916,96 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 			redactedSpec.InitContainers[ci] = c
 		}
 	}
-	k8sjson.NewYAMLSerializer(k8sjson.DefaultMetaFactory, nil, nil).Encode(&corev1.Pod{Spec: *redactedSpec}, pw)
+	resolved, err := resolveSpec(redactedSpec)
+	if err != nil {
+		return nil, xerrors.Errorf("cannot resolve job spec of %s: %w", name, err)
+	}
+	pw.Write(resolved)
 	pw.Flush()
 
+	// the hash and resolved spec let users find out what changed between two runs
+	metadata.SpecHash = specHash(resolved)
+	err = srv.Jobs.StoreResolvedSpec(name, resolved)
+	if err != nil {
+		log.WithError(err).WithFields(jobLogFields(name, &metadata)).Warn("cannot store resolved job spec")
+	}
+
 	// schedule/start job
-	status, err = srv.Executor.Start(*podspec, metadata,
+	_, execSpan := tracing.Start(ctx, "executor.Start")
+	execOpts := []executor.StartOpt{
 		executor.WithName(name),
 		executor.WithCanReplay(canReplay),
 		executor.WithWaitUntil(waitUntil),
 		executor.WithMutex(jobspec.Mutex),
-	)
+		executor.WithTrustedContainers(cpinit),
+	}
+	if nodePath != "" {
+		execOpts = append(execOpts, executor.WithTrustedHostPaths(nodePath))
+	}
+	if repoCfg.Timeout != nil {
+		execOpts = append(execOpts, executor.WithTimeout(repoCfg.Timeout.Duration))
+	}
+	if len(repoCfg.ImagePullSecrets) > 0 {
+		execOpts = append(execOpts, executor.WithImagePullSecrets(repoCfg.ImagePullSecrets...))
+	}
+	if repoCfg.BuildCache != nil {
+		env, err := repoCfg.BuildCache.Env(&metadata)
+		if err != nil {
+			return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
+		}
+		execOpts = append(execOpts, executor.WithEnv(env))
+	}
+	if len(repoCfg.Env) > 0 {
+		execOpts = append(execOpts, executor.WithEnv(repoCfg.Env))
+	}
+	if len(secretEnv) > 0 {
+		execOpts = append(execOpts, executor.WithSecretEnv(secretEnv))
+	}
+	if len(creds) > 0 {
+		execOpts = append(execOpts, executor.WithCredentials(creds...))
+	}
+	if repoCfg.Egress != nil {
+		execOpts = append(execOpts, executor.WithEgress(repoCfg.Egress.JobRules(&metadata)))
+	}
+	if len(podAnnotations) > 0 {
+		execOpts = append(execOpts, executor.WithPodAnnotations(podAnnotations))
+	}
+	if keepAlive > 0 {
+		execOpts = append(execOpts, executor.WithDebugKeepAlive(keepAlive))
+	}
+	if gate := srv.jobGate(name, &metadata, repoCfg, waitFor); gate != nil {
+		execOpts = append(execOpts, executor.WithGate(gate))
+	}
+	approval := repoCfg.Approval.policy(name, &metadata)
+	needsApproval := approval != nil && !isApproved(ctx)
+	if needsApproval {
+		execOpts = append(execOpts, executor.WithApproval())
+		srv.awaitApproval(name, &metadata, approval)
+	}
+	execOpts = append(execOpts, opts...)
+	status, err = srv.Executor.Start(*podspec, metadata, execOpts...)
+	tracing.FinishSpan(execSpan, &err)
 	if err != nil {
+		if needsApproval {
+			srv.forgetApproval(name)
+		}
+		srv.releaseSlot(name)
 		return nil, xerrors.Errorf("cannot handle job for %s: %w", name, err)
 	}
 	name = status.Name
 
+	if status.Phase != v1.JobPhase_PHASE_WAITING {
+		// the scheduling span ends once the job runs, i.e. covers pod scheduling, image pulls and the checkout
+		_, schedSpan := tracing.Start(ctx, "scheduling", trace.WithAttributes(attribute.String("job", name)))
+		srv.mu.Lock()
+		if jl, ok := srv.logListener[name]; ok {
+			jl.SchedulingSpan = schedSpan
+		} else {
+			schedSpan.End()
+		}
+		srv.mu.Unlock()
+	}
+
 	err = cp.Serve(name)
 	if err != nil {
 		return nil, err
@@ -547,12 +2013,32 @@ func (srv *Service) RunJob(ctx context.Context, name string, metadata v1.JobMeta
 
 	err = srv.Jobs.Store(ctx, *status)
 	if err != nil {
-		log.WithError(err).WithField("name", name).Warn("cannot store job status")
+		log.WithError(err).WithFields(jobLogFields(name, &metadata)).Warn("cannot store job status")
 	}
 
 	return status, nil
 }
 
+// checkoutInitContainer produces the init container of a job, using the checkout cache if it's configured
+// and the content provider supports it.
+func (srv *Service) checkoutInitContainer(cp ContentProvider) (c *corev1.Container, cached bool, err error) {
+	ccp, ok := cp.(CacheableContentProvider)
+	if cc := srv.Config.CheckoutCache; ok && cc != nil && cc.ClaimName != "" {
+		maxAge := defaultCheckoutCacheMaxAge
+		if cc.MaxAge != nil {
+			maxAge = cc.MaxAge.Duration
+		}
+
+		c, cached, err = ccp.CacheInitContainer(checkoutCachePath, maxAge)
+		if err != nil || cached {
+			return
+		}
+	}
+
+	c, err = cp.InitContainer()
+	return c, false, err
+}
+
 // cleanupWorkspace starts a cleanup job for a previously run job
 func (srv *Service) cleanupJobWorkspace(s *v1.JobStatus) {
 	if srv.Config.WorkspaceNodePathPrefix == "" {
@@ -566,7 +2052,7 @@ func (srv *Service) cleanupJobWorkspace(s *v1.JobStatus) {
 		Owner:      s.Metadata.Owner,
 		Repository: s.Metadata.Repository,
 		Trigger:    v1.JobTrigger_TRIGGER_UNKNOWN,
-		Created:    ptypes.TimestampNow(),
+		Created:    srv.timestampNow(),
 		Annotations: []*v1.Annotation{
 			&v1.Annotation{
 				Key:   annotationCleanupJob,
@@ -592,7 +2078,7 @@ func (srv *Service) cleanupJobWorkspace(s *v1.JobStatus) {
 			},
 		},
 	})
-	podspec.Containers = append(podspec.Containers, corev1.Container{
+	cleanup := corev1.Container{
 		Name:       "cleanup",
 		Image:      "alpine:latest",
 		Command:    []string{"sh", "-c", "rm -rf *"},
@@ -603,11 +2089,12 @@ func (srv *Service) cleanupJobWorkspace(s *v1.JobStatus) {
 				MountPath: "/workspace",
 			},
 		},
-	})
+	}
+	podspec.Containers = append(podspec.Containers, cleanup)
 	podspec.RestartPolicy = corev1.RestartPolicyOnFailure
-	_, err := srv.Executor.Start(podspec, md, executor.WithCanReplay(false), executor.WithBackoff(3), executor.WithName(fmt.Sprintf("cleanup-%s", name)))
+	_, err := srv.Executor.Start(podspec, md, executor.WithCanReplay(false), executor.WithBackoff(3), executor.WithName(fmt.Sprintf("cleanup-%s", name)), executor.WithTrustedContainers(cleanup), executor.WithTrustedHostPaths(nodePath))
 	if err != nil {
-		log.WithError(err).WithField("name", name).Error("cannot start cleanup job")
+		log.WithError(err).WithFields(jobLogFields(name, s.Metadata)).Error("cannot start cleanup job")
 	}
 }
 
@@ -629,7 +2116,7 @@ func newTemplateObj(name string, md *v1.JobMetadata) templateObj {
 		Name:        name,
 		Owner:       md.Owner,
 		Repository:  *md.Repository,
-		Trigger:     strings.ToLower(strings.TrimPrefix(md.Trigger.String(), "TRIGGER_")),
+		Trigger:     repoconfig.TriggerName(md.Trigger),
 		Annotations: annotations,
 	}
 }
@@ -0,0 +1,101 @@
+package werft
+
+import (
+	"time"
+
+	"github.com/32leaves/werft/pkg/api/repoconfig"
+	v1 "github.com/32leaves/werft/pkg/api/v1"
+	"github.com/32leaves/werft/pkg/schedule"
+	"golang.org/x/xerrors"
+)
+
+// ExecutionWindowConfig limits the time of day jobs start at, e.g. to run heavy nightly jobs between 00:00 and 06:00.
+// Jobs request a window by its name, or are subject to it because of their repository. Jobs which are started
+// outside their window wait until it opens.
+type ExecutionWindowConfig struct {
+	// Name is the window jobs request in their spec, e.g. nightly
+	Name string `yaml:"name"`
+
+	// Start is the time of day the window opens at, e.g. 00:00
+	Start string `yaml:"start"`
+
+	// End is the time of day the window closes at, e.g. 06:00. Windows which end before they start span midnight.
+	End string `yaml:"end"`
+
+	// Repositories subjects all jobs of these repositories to this window, given as host/owner/repo or owner/repo.
+	// Supports globs. If empty, only jobs which request the window are subject to it.
+	Repositories []string `yaml:"repositories,omitempty"`
+
+	// Timezone is the time zone of Start and End, e.g. Europe/Berlin. Defaults to the time zone werft is configured with.
+	Timezone string `yaml:"timezone,omitempty"`
+}
+
+// Next returns the earliest time at or after t which lies within the window. Windows without time zone use
+// the time zone of t. Start and End are wall clock times, hence windows open at the same time of day across
+// daylight saving time changes.
+func (w ExecutionWindowConfig) Next(t time.Time) (time.Time, error) {
+	if w.Timezone != "" {
+		loc, err := schedule.LoadLocation(w.Timezone)
+		if err != nil {
+			return t, xerrors.Errorf("invalid execution window %s: %w", w.Name, err)
+		}
+		t = t.In(loc)
+	}
+
+	start, err := time.Parse("15:04", w.Start)
+	if err != nil {
+		return t, xerrors.Errorf("invalid start of execution window %s: %w", w.Name, err)
+	}
+	end, err := time.Parse("15:04", w.End)
+	if err != nil {
+		return t, xerrors.Errorf("invalid end of execution window %s: %w", w.Name, err)
+	}
+
+	var (
+		from = start.Hour()*60 + start.Minute()
+		to   = end.Hour()*60 + end.Minute()
+		now  = t.Hour()*60 + t.Minute()
+	)
+	if from == to {
+		// the window is always open
+		return t, nil
+	}
+	if from < to && from <= now && now < to {
+		return t, nil
+	}
+	if from > to && (now >= from || now < to) {
+		return t, nil
+	}
+
+	open := time.Date(t.Year(), t.Month(), t.Day(), start.Hour(), start.Minute(), 0, 0, t.Location())
+	if !open.After(t) {
+		open = time.Date(t.Year(), t.Month(), t.Day()+1, start.Hour(), start.Minute(), 0, 0, t.Location())
+	}
+	return open.UTC(), nil
+}
+
+// executionWindow returns the execution window a job is subject to, or nil if it can start at any time
+func (srv *Service) executionWindow(md *v1.JobMetadata, jobspec *repoconfig.JobSpec) (*ExecutionWindowConfig, error) {
+	cfg := srv.config()
+	withTimezone := func(w ExecutionWindowConfig) *ExecutionWindowConfig {
+		if w.Timezone == "" {
+			w.Timezone = cfg.Timezone
+		}
+		return &w
+	}
+	for _, w := range cfg.ExecutionWindows {
+		if jobspec.ExecutionWindow != "" && w.Name == jobspec.ExecutionWindow {
+			return withTimezone(w), nil
+		}
+	}
+	if jobspec.ExecutionWindow != "" {
+		return nil, xerrors.Errorf("unknown execution window %s", jobspec.ExecutionWindow)
+	}
+
+	for _, w := range cfg.ExecutionWindows {
+		if len(w.Repositories) > 0 && policyAllows(w.Repositories, nil, md.Repository) {
+			return withTimezone(w), nil
+		}
+	}
+	return nil, nil
+}
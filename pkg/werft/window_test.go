@@ -0,0 +1,44 @@
+package werft_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/32leaves/werft/pkg/werft"
+)
+
+func TestExecutionWindowNext(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 2, day, hour, min, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		Name        string
+		Window      werft.ExecutionWindowConfig
+		Time        time.Time
+		Expectation time.Time
+	}{
+		{"inside", werft.ExecutionWindowConfig{Start: "00:00", End: "06:00"}, at(12, 3, 30), at(12, 3, 30)},
+		{"at start", werft.ExecutionWindowConfig{Start: "00:00", End: "06:00"}, at(12, 0, 0), at(12, 0, 0)},
+		{"at end", werft.ExecutionWindowConfig{Start: "00:00", End: "06:00"}, at(12, 6, 0), at(13, 0, 0)},
+		{"before", werft.ExecutionWindowConfig{Start: "20:00", End: "23:00"}, at(12, 10, 15), at(12, 20, 0)},
+		{"after", werft.ExecutionWindowConfig{Start: "20:00", End: "23:00"}, at(12, 23, 30), at(13, 20, 0)},
+		{"spans midnight inside", werft.ExecutionWindowConfig{Start: "22:00", End: "04:00"}, at(12, 1, 0), at(12, 1, 0)},
+		{"spans midnight outside", werft.ExecutionWindowConfig{Start: "22:00", End: "04:00"}, at(12, 12, 0), at(12, 22, 0)},
+		{"always open", werft.ExecutionWindowConfig{Start: "00:00", End: "00:00"}, at(12, 12, 0), at(12, 12, 0)},
+		{"time zone", werft.ExecutionWindowConfig{Start: "20:00", End: "23:00", Timezone: "Europe/Berlin"}, at(12, 10, 15), at(12, 19, 0)},
+		{"time zone inside", werft.ExecutionWindowConfig{Start: "20:00", End: "23:00", Timezone: "Europe/Berlin"}, at(12, 20, 30), at(12, 20, 30)},
+		{"clocks go forward", werft.ExecutionWindowConfig{Start: "20:00", End: "23:00", Timezone: "Europe/Berlin"}, time.Date(2020, 3, 28, 23, 0, 0, 0, time.UTC), time.Date(2020, 3, 29, 18, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			act, err := test.Window.Next(test.Time)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !act.Equal(test.Expectation) {
+				t.Errorf("expected %v, got %v", test.Expectation, act)
+			}
+		})
+	}
+}